@@ -20,6 +20,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/cockroachdb/cockroach/pkg/cli/cliflags"
@@ -228,6 +229,11 @@ type StoreSpec struct {
 	EncryptionOptions []byte
 	// ProvisionedRateSpec is optional.
 	ProvisionedRateSpec ProvisionedRateSpec
+	// WALFailoverThreshold, if non-zero, is the latency of a WAL write above
+	// which the store fails over to its secondary WAL directory, overriding the
+	// storage.wal_failover.unhealthy_op_threshold cluster setting. It has no
+	// effect unless WAL failover is configured.
+	WALFailoverThreshold time.Duration
 }
 
 // String returns a fully parsable version of the store spec.
@@ -274,6 +280,9 @@ func (ss StoreSpec) String() string {
 		fmt.Fprintf(&buffer, "provisioned-rate=bandwidth=%s/s,",
 			humanizeutil.IBytes(ss.ProvisionedRateSpec.ProvisionedBandwidth))
 	}
+	if ss.WALFailoverThreshold > 0 {
+		fmt.Fprintf(&buffer, "wal-failover-threshold=%s,", ss.WALFailoverThreshold)
+	}
 	// Trim the extra comma from the end if it exists.
 	if l := buffer.Len(); l > 0 {
 		buffer.Truncate(l - 1)
@@ -319,6 +328,9 @@ var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-
 //   - provisioned-rate=bandwidth=<bandwidth-bytes/s> The provisioned-rate can be
 //     used for admission control for operations on the store and if unspecified,
 //     a cluster setting (kvadmission.store.provisioned_bandwidth) will be used.
+//   - wal-failover-threshold=<duration> The latency of a WAL write above which
+//     the store fails over to its secondary WAL directory. If unspecified, a
+//     cluster setting (storage.wal_failover.unhealthy_op_threshold) will be used.
 //
 // Note that commas are forbidden within any field name or value.
 func NewStoreSpec(value string) (StoreSpec, error) {
@@ -448,6 +460,15 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 				return StoreSpec{}, err
 			}
 			ss.ProvisionedRateSpec = rateSpec
+		case "wal-failover-threshold":
+			threshold, err := time.ParseDuration(value)
+			if err != nil {
+				return StoreSpec{}, errors.Wrapf(err, "could not parse %s", field)
+			}
+			if threshold <= 0 {
+				return StoreSpec{}, fmt.Errorf("%s must be positive: %s", field, value)
+			}
+			ss.WALFailoverThreshold = threshold
 
 		default:
 			return StoreSpec{}, fmt.Errorf("%s is not a valid store field", field)
@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -157,6 +158,12 @@ target_file_size=2097152`
 		{"path=/mnt/hda1,provisioned-rate=200MiB/s", "provisioned-rate field has invalid value 200MiB/s", StoreSpec{}},
 		{"path=/mnt/hda1,provisioned-rate=bandwidth=0B/s", "provisioned-rate field is trying to set bandwidth to 0", StoreSpec{}},
 
+		// WAL failover threshold
+		{"path=/mnt/hda1,wal-failover-threshold=250ms", "",
+			StoreSpec{Path: "/mnt/hda1", WALFailoverThreshold: 250 * time.Millisecond}},
+		{"path=/mnt/hda1,wal-failover-threshold=abc", "could not parse wal-failover-threshold: time: invalid duration \"abc\"", StoreSpec{}},
+		{"path=/mnt/hda1,wal-failover-threshold=0s", "wal-failover-threshold must be positive: 0s", StoreSpec{}},
+
 		// RocksDB
 		{"path=/,rocksdb=key1=val1;key2=val2", "", StoreSpec{Path: "/", RocksDBOptions: "key1=val1;key2=val2"}},
 
@@ -17,6 +17,8 @@ import (
 
 type BackupMetrics struct {
 	LastKMSInaccessibleErrorTime *metric.Gauge
+	OnlineRestoreLinkedBytes     *metric.Counter
+	OnlineRestoreDownloadedBytes *metric.Counter
 }
 
 // MetricStruct implements the metric.Struct interface.
@@ -31,6 +33,18 @@ func MakeBackupMetrics(time.Duration) metric.Struct {
 			Measurement: "Jobs",
 			Unit:        metric.Unit_TIMESTAMP_SEC,
 		}),
+		OnlineRestoreLinkedBytes: metric.NewCounter(metric.Metadata{
+			Name:        "restore.online.linked_bytes",
+			Help:        "Number of bytes of backup files linked by online restores, which are queryable before being downloaded",
+			Measurement: "Bytes",
+			Unit:        metric.Unit_BYTES,
+		}),
+		OnlineRestoreDownloadedBytes: metric.NewCounter(metric.Metadata{
+			Name:        "restore.online.downloaded_bytes",
+			Help:        "Number of bytes of linked backup files downloaded in the background by online restores",
+			Measurement: "Bytes",
+			Unit:        metric.Unit_BYTES,
+		}),
 	}
 	return m
 }
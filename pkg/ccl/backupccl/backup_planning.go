@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -270,6 +271,7 @@ func checkPrivilegesForBackup(
 	{
 		// Cluster and tenant backups require the `BACKUP` system privilege.
 		requiresBackupSystemPrivilege := backupStmt.Coverage() == tree.AllDescriptors ||
+			backupStmt.Coverage() == tree.SystemMetadata ||
 			(backupStmt.Targets != nil && backupStmt.Targets.TenantID.IsSet())
 
 		if requiresBackupSystemPrivilege {
@@ -438,6 +440,12 @@ func backupPlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	if backupStmt.Coverage() == tree.SystemMetadata &&
+		!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_2_BackupSystemMetadata) {
+		return nil, nil, nil, false, pgerror.New(pgcode.FeatureNotSupported,
+			"BACKUP SYSTEM METADATA not supported before V24.2")
+	}
+
 	detached := backupStmt.Options.Detached == tree.DBoolTrue
 
 	// Deprecation notice for `BACKUP TO` syntax. Remove this once the syntax is
@@ -632,6 +640,12 @@ func backupPlanHook(
 			if err != nil {
 				return err
 			}
+		case tree.SystemMetadata:
+			var err error
+			targetDescs, err = systemMetadataTargetsBackup(ctx, p.ExecCfg(), endTime)
+			if err != nil {
+				return err
+			}
 		default:
 			return errors.AssertionFailedf("unexpected descriptor coverage %v", backupStmt.Coverage())
 		}
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -220,6 +221,9 @@ func (bp *backupDataProcessor) Start(ctx context.Context) {
 	}
 	ctx = bp.StartInternal(ctx, backupProcessorName, bp.agg)
 	ctx, cancel := context.WithCancel(ctx)
+	// In virtual clusters, the KV and external I/O requests of the backup are
+	// paced by the dedicated backup token bucket, if one is configured.
+	ctx = multitenant.WithBackupRUPacing(ctx)
 
 	bp.cancelAndWaitForWorker = func() {
 		cancel()
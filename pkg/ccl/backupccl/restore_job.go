@@ -2004,6 +2004,17 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 		}
 		details = r.job.Details().(jobspb.RestoreDetails)
 
+		if err := r.cleanupTempSystemTables(ctx); err != nil {
+			return err
+		}
+	} else if details.DescriptorCoverage == tree.SystemMetadata {
+		systemTables := append(
+			append([]catalog.TableDescriptor(nil), preData.systemTables...), mainData.systemTables...)
+		if err := r.restoreSystemMetadata(ctx, p.ExecCfg().InternalDB, systemTables); err != nil {
+			return err
+		}
+		details = r.job.Details().(jobspb.RestoreDetails)
+
 		if err := r.cleanupTempSystemTables(ctx); err != nil {
 			return err
 		}
@@ -2164,7 +2175,8 @@ func (r *restoreResumer) notifyStatsRefresherOfNewTables() {
 func tempSystemDatabaseID(
 	details jobspb.RestoreDetails, tables []catalog.TableDescriptor,
 ) descpb.ID {
-	if details.DescriptorCoverage != tree.AllDescriptors && !isSystemUserRestore(details) {
+	if details.DescriptorCoverage != tree.AllDescriptors &&
+		details.DescriptorCoverage != tree.SystemMetadata && !isSystemUserRestore(details) {
 		return descpb.InvalidID
 	}
 
@@ -3183,6 +3195,87 @@ VALUES ($1, $2, $3, (SELECT user_id FROM system.users WHERE username = $1), (SEL
 	})
 }
 
+// restoreSystemMetadata restores the cluster metadata in a backup of it into
+// the restoring cluster. Unlike a cluster restore, which replaces the contents
+// of the system tables, the metadata is merged into that of the restoring
+// cluster:
+//   - users and roles are restored as in RESTORE SYSTEM USERS, i.e. only those
+//     which do not currently exist are created;
+//   - system privileges are granted, unless the grantee already has system
+//     privileges on the same object;
+//   - cluster settings are overwritten, except for the cluster version;
+//   - zone configurations are overwritten, but only those of the cluster's
+//     ranges and system tables, since the descriptor IDs of other objects are
+//     meaningless in the restoring cluster;
+//   - schedules are created, unless a schedule with the same ID or name
+//     already exists. Row-level TTL schedules are skipped, as they reference
+//     descriptor IDs of the backing up cluster.
+func (r *restoreResumer) restoreSystemMetadata(
+	ctx context.Context, db isql.DB, systemTables []catalog.TableDescriptor,
+) error {
+	if err := r.restoreSystemUsers(ctx, db, systemTables); err != nil {
+		return err
+	}
+	return db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		if hasSystemTableByName(systemschema.SystemPrivilegeTable.GetName(), systemTables) {
+			// Backups taken before system.privileges had a user_id column don't
+			// have it, so the user IDs are looked up in the restoring cluster.
+			privileges, err := txn.QueryBuffered(ctx, "get-system-privileges", txn.KV(),
+				"SELECT username, path, privileges, grant_options FROM crdb_temp_system.privileges")
+			if err != nil {
+				return err
+			}
+			insertPrivilege := fmt.Sprintf(`
+INSERT INTO system.privileges (username, path, privileges, grant_options, user_id)
+VALUES ($1, $2, $3, $4, (
+    SELECT CASE $1
+		WHEN '%s' THEN %d
+		ELSE (SELECT user_id FROM system.users WHERE username = $1)
+	END
+))
+ON CONFLICT DO NOTHING`, username.PublicRole, username.PublicRoleID)
+			for _, row := range privileges {
+				if _, err := txn.Exec(ctx, "insert-system-privileges", txn.KV(), insertPrivilege,
+					row[0], row[1], row[2], row[3],
+				); err != nil {
+					return errors.Wrap(err, "inserting data to system.privileges")
+				}
+			}
+		}
+
+		if hasSystemTableByName(systemschema.SettingsTable.GetName(), systemTables) {
+			if _, err := txn.Exec(ctx, "upsert-settings", txn.KV(),
+				"UPSERT INTO system.settings (SELECT * FROM crdb_temp_system.settings WHERE name <> 'version')",
+			); err != nil {
+				return errors.Wrap(err, "inserting data to system.settings")
+			}
+		}
+
+		if hasSystemTableByName(systemschema.ZonesTable.GetName(), systemTables) {
+			if _, err := txn.Exec(ctx, "upsert-zones", txn.KV(),
+				"UPSERT INTO system.zones (SELECT * FROM crdb_temp_system.zones WHERE id <= $1)",
+				keys.MaxReservedDescID,
+			); err != nil {
+				return errors.Wrap(err, "inserting data to system.zones")
+			}
+		}
+
+		if hasSystemTableByName(systemschema.ScheduledJobsTable.GetName(), systemTables) {
+			if _, err := txn.Exec(ctx, "insert-scheduled-jobs", txn.KV(), `
+INSERT INTO system.scheduled_jobs
+(SELECT * FROM crdb_temp_system.scheduled_jobs temp WHERE executor_type <> $1 AND NOT EXISTS (
+	SELECT * FROM system.scheduled_jobs s
+	WHERE s.schedule_id = temp.schedule_id OR s.schedule_name = temp.schedule_name
+))`,
+				tree.ScheduledRowLevelTTLExecutor.InternalName(),
+			); err != nil {
+				return errors.Wrap(err, "inserting data to system.scheduled_jobs")
+			}
+		}
+		return nil
+	})
+}
+
 func hasSystemRoleMembersTable(systemTables []catalog.TableDescriptor) bool {
 	return hasSystemTableByName(systemschema.RoleMembersTable.GetName(), systemTables)
 }
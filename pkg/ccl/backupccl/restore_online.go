@@ -286,6 +286,7 @@ func sendAddRemoteSSTWorker(
 	approxRows *int64,
 	approxDataSize *int64,
 ) func(context.Context) error {
+	metrics := execCtx.ExecCfg().JobRegistry.MetricsStruct().Backup.(*BackupMetrics)
 	return func(ctx context.Context) error {
 		for entry := range restoreSpanEntriesCh {
 			log.VInfof(ctx, 1, "starting restore of backed up span %s containing %d files", entry.Span, len(entry.Files))
@@ -332,6 +333,7 @@ func sendAddRemoteSSTWorker(
 			}
 			atomic.AddInt64(approxRows, rows)
 			atomic.AddInt64(approxDataSize, dataSize)
+			metrics.OnlineRestoreLinkedBytes.Inc(dataSize)
 			requestFinishedCh <- struct{}{}
 		}
 		return nil
@@ -648,6 +650,10 @@ func (r *restoreResumer) waitForDownloadToComplete(
 	ctx, tsp := tracing.ChildSpan(ctx, "backupccl.waitForDownloadToComplete")
 	defer tsp.Finish()
 
+	// If the total was calculated by a previous resumption of the job, some of
+	// it may already have been downloaded and counted in the metrics, so the
+	// downloaded bytes are only counted from the first poll onwards.
+	resumed := r.job.Progress().Details.(*jobspb.Progress_Restore).Restore.TotalDownloadRequired != 0
 	total, err := r.maybeCalculateTotalDownloadSpans(ctx, execCtx, details)
 	if err != nil {
 		return errors.Wrap(err, "failed to calculate total number of spans to download")
@@ -661,6 +667,8 @@ func (r *restoreResumer) waitForDownloadToComplete(
 		})
 	}
 
+	metrics := execCtx.ExecCfg().JobRegistry.MetricsStruct().Backup.(*BackupMetrics)
+	lastRemaining, haveLastRemaining := total, !resumed
 	var lastProgressUpdate time.Time
 	for rt := retry.StartWithCtx(
 		ctx, retry.Options{InitialBackoff: time.Second, MaxBackoff: time.Second * 10},
@@ -683,6 +691,11 @@ func (r *restoreResumer) waitForDownloadToComplete(
 			total = remaining
 		}
 
+		if haveLastRemaining && remaining < lastRemaining {
+			metrics.OnlineRestoreDownloadedBytes.Inc(int64(lastRemaining - remaining))
+		}
+		lastRemaining, haveLastRemaining = remaining, true
+
 		fractionComplete := float32(total-remaining) / float32(total)
 		log.VInfof(ctx, 1, "restore download phase, %s downloaded, %s remaining of %s total (%.2f complete)",
 			sz(total-remaining), sz(remaining), sz(total), fractionComplete,
@@ -699,6 +712,11 @@ func (r *restoreResumer) waitForDownloadToComplete(
 			}); err != nil {
 				return err
 			}
+			if err := r.job.NoTxn().RunningStatus(ctx, jobs.RunningStatus(fmt.Sprintf(
+				"Downloading restored data: %s of %s remaining", sz(remaining), sz(total),
+			))); err != nil {
+				return err
+			}
 			lastProgressUpdate = timeutil.Now()
 		}
 		// Signal the download job if it is waiting that we've polled and found work
@@ -80,6 +80,10 @@ func TestOnlineRestoreBasic(t *testing.T) {
 
 	rSQLDB.CheckQueryResults(t, createStmt, createStmtRes)
 	sqlDB.CheckQueryResults(t, jobutils.GetExternalBytesForConnectedTenant, [][]string{{"0"}})
+
+	metrics := rtc.Servers[0].JobRegistry().(*jobs.Registry).MetricsStruct().Backup.(*BackupMetrics)
+	require.Greater(t, metrics.OnlineRestoreLinkedBytes.Count(), int64(0))
+	require.Greater(t, metrics.OnlineRestoreDownloadedBytes.Count(), int64(0))
 }
 
 func TestOnlineRestorePartitioned(t *testing.T) {
@@ -821,7 +821,8 @@ func allocateDescriptorRewrites(
 		}
 	}
 
-	if descriptorCoverage == tree.AllDescriptors || descriptorCoverage == tree.SystemUsers {
+	if descriptorCoverage == tree.AllDescriptors || descriptorCoverage == tree.SystemUsers ||
+		descriptorCoverage == tree.SystemMetadata {
 		// Increment the DescIDSequenceKey so that it is higher than both the max desc ID
 		// in the backup and current max desc ID in the restoring cluster. This generator
 		// keeps produced the next descriptor ID.
@@ -1229,6 +1230,12 @@ func restorePlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	if restoreStmt.DescriptorCoverage == tree.SystemMetadata &&
+		!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_2_BackupSystemMetadata) {
+		return nil, nil, nil, false, pgerror.New(pgcode.FeatureNotSupported,
+			"RESTORE SYSTEM METADATA not supported before V24.2")
+	}
+
 	if !restoreStmt.Options.SchemaOnly && restoreStmt.Options.VerifyData {
 		return nil, nil, nil, false,
 			errors.New("to set the verify_backup_table_data option, the schema_only option must be set")
@@ -1273,6 +1280,9 @@ func restorePlanHook(
 		if restoreStmt.DescriptorCoverage == tree.SystemUsers {
 			return nil, nil, nil, false, errors.New("cannot set into_db option when only restoring system users")
 		}
+		if restoreStmt.DescriptorCoverage == tree.SystemMetadata {
+			return nil, nil, nil, false, errors.New("cannot set into_db option when only restoring system metadata")
+		}
 		var err error
 		intoDB, err = exprEval.String(ctx, restoreStmt.Options.IntoDB)
 		if err != nil {
@@ -1507,9 +1517,10 @@ func checkPrivilegesForRestore(
 	}
 
 	{
-		// Cluster and tenant restores require the `RESTORE` system privilege for
-		// non-admin users.
+		// Cluster, system metadata and tenant restores require the `RESTORE`
+		// system privilege for non-admin users.
 		requiresRestoreSystemPrivilege := restoreStmt.DescriptorCoverage == tree.AllDescriptors ||
+			restoreStmt.DescriptorCoverage == tree.SystemMetadata ||
 			restoreStmt.Targets.TenantID.IsSet()
 
 		if requiresRestoreSystemPrivilege {
@@ -351,6 +351,54 @@ func fullClusterTargetsBackup(
 	return fullClusterDescs, fullClusterDBIDs, nil
 }
 
+// systemMetadataTables is the set of system tables which hold the cluster
+// metadata captured by BACKUP SYSTEM METADATA.
+var systemMetadataTables = map[string]struct{}{
+	systemschema.UsersTable.GetName():           {},
+	systemschema.RoleMembersTable.GetName():     {},
+	systemschema.RoleOptionsTable.GetName():     {},
+	systemschema.SystemPrivilegeTable.GetName(): {},
+	systemschema.SettingsTable.GetName():        {},
+	systemschema.ZonesTable.GetName():           {},
+	systemschema.ScheduledJobsTable.GetName():   {},
+}
+
+// systemMetadataTargetsBackup returns the descriptors included in a BACKUP
+// SYSTEM METADATA: the system database and those of its tables which hold the
+// cluster's metadata.
+func systemMetadataTargetsBackup(
+	ctx context.Context, execCfg *sql.ExecutorConfig, endTime hlc.Timestamp,
+) ([]catalog.Descriptor, error) {
+	allDescs, err := backupresolver.LoadAllDescs(ctx, execCfg, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var descs []catalog.Descriptor
+	for _, desc := range allDescs {
+		if desc.Dropped() {
+			continue
+		}
+		switch desc := desc.(type) {
+		case catalog.DatabaseDescriptor:
+			if desc.GetID() == keys.SystemDatabaseID {
+				descs = append(descs, desc)
+			}
+		case catalog.TableDescriptor:
+			if desc.GetParentID() != keys.SystemDatabaseID {
+				continue
+			}
+			if _, ok := systemMetadataTables[desc.GetName()]; ok {
+				descs = append(descs, desc)
+			}
+		}
+	}
+	if len(descs) == 0 {
+		return nil, errors.New("no descriptors available to backup at selected time")
+	}
+	return descs, nil
+}
+
 // selectTargets loads all descriptors from the selected backup manifest(s),
 // filters the descriptors based on the targets specified in the restore, and
 // calculates the max descriptor ID in the backup.
@@ -412,6 +460,29 @@ func selectTargets(
 		return systemTables, nil, nil, nil, nil
 	}
 
+	if descriptorCoverage == tree.SystemMetadata {
+		// The metadata can be restored from both a BACKUP SYSTEM METADATA and a
+		// full cluster backup, as both include the metadata system tables.
+		systemTables := make([]catalog.Descriptor, 0, len(systemMetadataTables))
+		var users catalog.Descriptor
+		for _, desc := range allDescs {
+			if desc.GetParentID() != systemschema.SystemDB.GetID() {
+				continue
+			}
+			if _, ok := systemMetadataTables[desc.GetName()]; !ok {
+				continue
+			}
+			if desc.GetName() == systemschema.UsersTable.GetName() {
+				users = desc
+			}
+			systemTables = append(systemTables, desc)
+		}
+		if users == nil {
+			return nil, nil, nil, nil, errors.Errorf("cannot restore system metadata as no system.users table in the backup")
+		}
+		return systemTables, nil, nil, nil, nil
+	}
+
 	if targets.TenantID.IsSet() {
 		for _, tenant := range lastBackupManifest.Tenants {
 			// TODO(dt): for now it is zero-or-one but when that changes, we should
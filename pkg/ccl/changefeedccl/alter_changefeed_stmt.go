@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedvalidators"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsauth"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -32,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -221,6 +223,21 @@ func alterChangefeedPlanHook(
 			return err
 		}
 		newPayload.MaximumPTSAge = newExpiration
+
+		// Removing or adding targets changes the set of tables the changefeed
+		// needs protected; rewrite the protected timestamp record accordingly so
+		// that dropped targets are released.
+		if err := replaceProtectedTimestampTargets(
+			ctx,
+			p.ExecCfg().ProtectedTimestampProvider.WithTxn(p.InternalSQLTxn()),
+			p.ExecCfg().Codec,
+			jobID,
+			AllTargets(newDetails),
+			newProgress.GetChangefeed(),
+		); err != nil {
+			return err
+		}
+
 		j, err := p.ExecCfg().JobRegistry.LoadJobWithTxn(ctx, jobID, p.InternalSQLTxn())
 		if err != nil {
 			return err
@@ -513,8 +530,21 @@ func generateAndValidateNewTargets(
 				} else {
 					withInitialScan = true
 				}
-			} else {
-				withInitialScan = false
+			} else if !noInitialScanSet && !initialScanOnlySet {
+				// Nodes running older versions don't track the backfill of added
+				// targets separately, so the initial scan is only the default once
+				// the cluster has been upgraded.
+				withInitialScan = changefeedbase.AlterAddTargetsInitialScanByDefault.Get(&p.ExecCfg().Settings.SV) &&
+					p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_2)
+				// An initial scan cannot be performed while the changefeed is in the
+				// middle of a non-initial backfill. Rather than failing a statement
+				// that did not ask for one, add the targets without it.
+				if withInitialScan && initialScanBlockedByCheckpoint(newJobProgress) {
+					withInitialScan = false
+					p.BufferClientNotice(ctx, pgnotice.Newf(
+						"not performing an initial scan on the added targets because the changefeed is "+
+							"in the middle of a backfill; only subsequent changes to them will be emitted"))
+				}
 			}
 
 			if initialScanType != `` && initialScanType != `yes` && initialScanType != `no` && initialScanType != `only` {
@@ -575,9 +605,9 @@ func generateAndValidateNewTargets(
 
 			addedTargetSpans := fetchSpansForDescs(p, newTargetIDs)
 
-			// By default, we will not perform an initial scan on newly added
-			// targets. Hence, the user must explicitly state that they want an
-			// initial scan performed on the new targets.
+			// Unless changefeed.alter.add_targets_initial_scan.enabled is
+			// disabled, an initial scan is performed on newly added targets
+			// when the user does not specify whether they want one.
 			newJobProgress, newJobStatementTime, err = generateNewProgress(
 				newJobProgress,
 				newJobStatementTime,
@@ -755,6 +785,52 @@ func generateNewProgress(
 	haveCheckpoint := changefeedProgress != nil && changefeedProgress.Checkpoint != nil &&
 		len(changefeedProgress.Checkpoint.Spans) != 0
 
+	// Targets adding a column family of a table the changefeed already watches
+	// share the span of the table, which is not scanned again.
+	var scanSpanGroup roachpb.SpanGroup
+	scanSpanGroup.Add(newSpans...)
+	scanSpanGroup.Sub(existingTargetSpans...)
+	scanSpans := scanSpanGroup.Slice()
+	if len(scanSpans) == 0 {
+		withInitialScan = false
+	}
+
+	// If the targets added by a previous ALTER CHANGEFEED are still being
+	// backfilled, the new targets either join that backfill, which is performed
+	// at the statement time, or are added to the checkpoint along with the
+	// existing targets. The high watermark of the existing targets is kept.
+	if changefeedProgress != nil && changefeedProgress.AddedTargetsBackfill != nil {
+		var checkpointSpans, backfillSpans roachpb.SpanGroup
+		var checkpointTS hlc.Timestamp
+		if haveCheckpoint {
+			checkpointSpans.Add(changefeedProgress.Checkpoint.Spans...)
+			checkpointTS = changefeedProgress.Checkpoint.Timestamp
+		}
+		backfillSpans.Add(changefeedProgress.AddedTargetsBackfill.Spans...)
+		if withInitialScan {
+			backfillSpans.Add(scanSpans...)
+		} else {
+			checkpointSpans.Add(scanSpans...)
+		}
+
+		newProgress := jobspb.Progress{
+			Progress: &jobspb.Progress_HighWater{HighWater: prevHighWater},
+			Details: &jobspb.Progress_Changefeed{
+				Changefeed: &jobspb.ChangefeedProgress{
+					Checkpoint: &jobspb.ChangefeedProgress_Checkpoint{
+						Spans:     checkpointSpans.Slice(),
+						Timestamp: checkpointTS,
+					},
+					ProtectedTimestampRecord: ptsRecord,
+					AddedTargetsBackfill: &jobspb.ChangefeedProgress_AddedTargetsBackfill{
+						Spans: backfillSpans.Slice(),
+					},
+				},
+			},
+		}
+		return newProgress, prevStatementTime, nil
+	}
+
 	// Check if the progress does not need to be updated. The progress does not
 	// need to be updated if:
 	// * the high watermark is empty, and we would like to perform an initial scan.
@@ -766,7 +842,7 @@ func generateNewProgress(
 
 	// Check if the user is trying to perform an initial scan during a
 	// non-initial backfill.
-	if haveHighwater && haveCheckpoint && withInitialScan {
+	if initialScanBlockedByCheckpoint(prevProgress) && withInitialScan {
 		return prevProgress, prevStatementTime, errors.Errorf(
 			`cannot perform initial scan on newly added targets while the checkpoint is non-empty, `+
 				`please unpause the changefeed and wait until the high watermark progresses past the current value %s to add these targets.`,
@@ -777,22 +853,25 @@ func generateNewProgress(
 	// Check if the user is trying to perform an initial scan while the high
 	// watermark is non-empty but the checkpoint is empty.
 	if haveHighwater && !haveCheckpoint && withInitialScan {
-		// If we would like to perform an initial scan on the new targets,
-		// we need to reset the high watermark. However, by resetting the high
-		// watermark, the initial scan will be performed on existing targets as well.
-		// To avoid this, we update the statement time of the job to the previous high
-		// watermark, and add all the existing targets to the checkpoint to skip the
-		// initial scan on these targets.
+		// The initial scan of the new targets is performed at the statement
+		// time, so we update the statement time of the job to the previous high
+		// watermark, and add all the existing targets to the checkpoint to skip
+		// the initial scan on these targets. The spans of the new targets are
+		// tracked separately until their scan completes, which lets the high
+		// watermark of the existing targets be kept rather than reset.
 		newStatementTime := *prevHighWater
 
 		newProgress := jobspb.Progress{
-			Progress: &jobspb.Progress_HighWater{},
+			Progress: &jobspb.Progress_HighWater{HighWater: &newStatementTime},
 			Details: &jobspb.Progress_Changefeed{
 				Changefeed: &jobspb.ChangefeedProgress{
 					Checkpoint: &jobspb.ChangefeedProgress_Checkpoint{
 						Spans: existingTargetSpans,
 					},
 					ProtectedTimestampRecord: ptsRecord,
+					AddedTargetsBackfill: &jobspb.ChangefeedProgress_AddedTargetsBackfill{
+						Spans: scanSpans,
+					},
 				},
 			},
 		}
@@ -829,11 +908,31 @@ func generateNewProgress(
 	return newProgress, prevStatementTime, nil
 }
 
+// initialScanBlockedByCheckpoint returns whether the changefeed is in the
+// middle of a non-initial backfill, in which case an initial scan cannot be
+// performed on newly added targets.
+func initialScanBlockedByCheckpoint(progress jobspb.Progress) bool {
+	highWater := progress.GetHighWater()
+	changefeedProgress := progress.GetChangefeed()
+	return highWater != nil && !highWater.IsEmpty() &&
+		changefeedProgress != nil && changefeedProgress.AddedTargetsBackfill == nil &&
+		changefeedProgress.Checkpoint != nil && len(changefeedProgress.Checkpoint.Spans) != 0
+}
+
 func removeSpansFromProgress(prevProgress jobspb.Progress, spansToRemove []roachpb.Span) {
 	changefeedProgress := prevProgress.GetChangefeed()
 	if changefeedProgress == nil {
 		return
 	}
+	if backfill := changefeedProgress.AddedTargetsBackfill; backfill != nil {
+		var spanGroup roachpb.SpanGroup
+		spanGroup.Add(backfill.Spans...)
+		spanGroup.Sub(spansToRemove...)
+		backfill.Spans = spanGroup.Slice()
+		if len(backfill.Spans) == 0 {
+			changefeedProgress.AddedTargetsBackfill = nil
+		}
+	}
 	changefeedCheckpoint := changefeedProgress.Checkpoint
 	if changefeedCheckpoint == nil {
 		return
@@ -1513,6 +1513,97 @@ func TestAlterChangefeedInitialScan(t *testing.T) {
 	}
 }
 
+func TestAlterChangefeedAddTargetInitialScanDefault(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1), (2)`)
+		sqlDB.Exec(t, `CREATE TABLE baz (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO baz VALUES (1)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved = '1s'`)
+		defer closeFeed(t, testFeed)
+
+		assertPayloads(t, testFeed, []string{
+			`foo: [1]->{"after": {"a": 1}}`,
+		})
+		expectResolvedTimestamp(t, testFeed)
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		job, err := registry.LoadJob(context.Background(), feed.JobID())
+		require.NoError(t, err)
+		prog := job.Progress()
+		prevHighWater := prog.GetHighWater()
+		require.NotNil(t, prevHighWater)
+		require.False(t, prevHighWater.IsEmpty())
+
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d ADD bar`, feed.JobID()))
+
+		// The backfill of the added target is tracked separately, and the high
+		// water of foo is not reset.
+		job, err = registry.LoadJob(context.Background(), feed.JobID())
+		require.NoError(t, err)
+		prog = job.Progress()
+		require.Equal(t, prevHighWater, prog.GetHighWater())
+		require.NotNil(t, prog.GetChangefeed().AddedTargetsBackfill)
+
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		// Only the added target is backfilled.
+		assertPayloads(t, testFeed, []string{
+			`bar: [1]->{"after": {"a": 1}}`,
+			`bar: [2]->{"after": {"a": 2}}`,
+		})
+
+		// Once the backfill completes, the high water advances past the time
+		// the target was added.
+		testutils.SucceedsSoon(t, func() error {
+			job, err := registry.LoadJob(context.Background(), feed.JobID())
+			require.NoError(t, err)
+			prog := job.Progress()
+			if prog.GetChangefeed().AddedTargetsBackfill != nil {
+				return errors.New("waiting for the added target backfill")
+			}
+			if h := prog.GetHighWater(); h == nil || !prevHighWater.Less(*h) {
+				return errors.New("waiting for highwater")
+			}
+			return nil
+		})
+
+		// With the setting disabled, added targets are not backfilled unless
+		// initial_scan is specified.
+		changefeedbase.AlterAddTargetsInitialScanByDefault.Override(
+			context.Background(), &s.Server.ClusterSettings().SV, false)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d ADD baz`, feed.JobID()))
+
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		sqlDB.Exec(t, `INSERT INTO baz VALUES (2)`)
+		assertPayloads(t, testFeed, []string{
+			`baz: [2]->{"after": {"a": 2}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoExternalConnection)
+}
+
 // This test checks that the time used to get table descriptors in alter
 // changefeed is the time from which changefeed will resume (check
 // validateNewTargets for more info on how this time is calculated).
@@ -57,6 +57,14 @@ type BatchBuffer interface {
 // batch of messages that is ready to be emitted by its Flush method.
 type SinkPayload interface{}
 
+// sinkPayloadSizer may be implemented by a SinkClient which compresses its
+// payloads, in order to report the number of bytes that are emitted to the
+// sink for a payload. It returns sinkDoesNotCompress if the payload is not
+// compressed.
+type sinkPayloadSizer interface {
+	compressedSize(SinkPayload) int
+}
+
 // batchingSink wraps a SinkClient to provide a Sink implementation that calls
 // the SinkClient methods to form batches and flushes those batches across
 // multiple parallel IO workers.
@@ -242,11 +250,12 @@ type sinkBatch struct {
 	buffer  BatchBuffer
 	payload SinkPayload // payload is nil until FinalizePayload has been called
 
-	numMessages int
-	numKVBytes  int          // the total amount of uncompressed kv data in the batch
-	keys        intsets.Fast // the set of keys within the batch to provide to parallelIO
-	bufferTime  time.Time    // the earliest time a message was inserted into the batch
-	mvcc        hlc.Timestamp
+	numMessages     int
+	numKVBytes      int          // the total amount of uncompressed kv data in the batch
+	compressedBytes int          // the size of the compressed payload, if any
+	keys            intsets.Fast // the set of keys within the batch to provide to parallelIO
+	bufferTime      time.Time    // the earliest time a message was inserted into the batch
+	mvcc            hlc.Timestamp
 
 	alloc  kvevent.Alloc
 	hasher hash.Hash32
@@ -351,7 +360,7 @@ func (s *batchingSink) runBatchingWorker(ctx context.Context) {
 			s.handleError(err)
 		} else {
 			s.metrics.recordEmittedBatch(
-				batch.bufferTime, batch.numMessages, batch.mvcc, batch.numKVBytes, sinkDoesNotCompress,
+				batch.bufferTime, batch.numMessages, batch.mvcc, batch.numKVBytes, batch.compressedBytes,
 			)
 		}
 
@@ -376,6 +385,10 @@ func (s *batchingSink) runBatchingWorker(ctx context.Context) {
 		if err := batchBuffer.FinalizePayload(); err != nil {
 			return err
 		}
+		batchBuffer.compressedBytes = sinkDoesNotCompress
+		if sizer, ok := s.client.(sinkPayloadSizer); ok {
+			batchBuffer.compressedBytes = sizer.compressedSize(batchBuffer.payload)
+		}
 
 		req, send, err := ioEmitter.AdmitRequest(ctx, batchBuffer)
 		if errors.Is(err, ErrNotEnoughQuota) {
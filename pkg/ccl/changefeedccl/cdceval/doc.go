@@ -52,6 +52,17 @@ section below for more info).
 Certain stable functions (s.a. now(), current_timestamp(), etc) are allowed -- they will always
 return the MVCC timestamp of the event.
 
+The target table may be joined against (small, rarely changing) reference tables:
+   SELECT o.*, c.name FROM orders AS o LEFT JOIN customers AS c ON c.id = o.customer_id
+Reference tables must be LEFT JOINed on their primary key so that each event
+produces exactly one row.  The joins are executed as lookup joins, which look
+up each event in the reference tables as soon as it is pushed into the
+pipeline.  Reference tables are read as of the time the expression was planned;
+the Evaluator periodically replans the expression (see
+changefeed.expressions.reference_table_refresh_interval) so that changes to the
+reference tables are eventually observed.  Changes to the reference tables do
+not, by themselves, cause any events to be emitted.
+
 Access to the previous state of the row is accomplished via (typed) cdc_prev tuple.
 This tuple can be used to build complex expressions around the previous state of the row:
    SELECT * FROM foo WHERE status='active' AND cdc_prev.status='inactive'
@@ -11,6 +11,7 @@ package cdceval
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -60,6 +61,11 @@ type familyEvaluator struct {
 	prevDesc     *cdcevent.EventDescriptor
 	prevRowTuple *tree.DTuple
 	alloc        tree.DatumAlloc
+	// readsReferenceTables is set if the plan joins against reference tables,
+	// in which case the plan is restarted periodically (see plannedAt) to
+	// observe changes to those tables.
+	readsReferenceTables bool
+	plannedAt            time.Time
 
 	// Execution context.
 	execCfg     *sql.ExecutorConfig
@@ -179,8 +185,10 @@ func (e *familyEvaluator) eval(
 
 	havePrev := prevRow.IsInitialized()
 	if !(sameVersion(e.currDesc, updatedRow.EventDescriptor) &&
-		(!havePrev || sameVersion(e.prevDesc, prevRow.EventDescriptor))) {
-		// Descriptor versions changed; re-initialize.
+		(!havePrev || sameVersion(e.prevDesc, prevRow.EventDescriptor))) ||
+		e.referenceTablesStale() {
+		// Descriptor versions changed, or reference tables need to be re-read;
+		// re-initialize.
 		if err := e.closeErr(); err != nil {
 			return cdcevent.Row{}, err
 		}
@@ -247,6 +255,17 @@ func (e *familyEvaluator) eval(
 	}
 }
 
+// referenceTablesStale returns true if the plan reads reference tables, and it
+// was started long enough ago that it must be restarted to observe changes to
+// those tables.
+func (e *familyEvaluator) referenceTablesStale() bool {
+	if !e.readsReferenceTables {
+		return false
+	}
+	refreshInterval := changefeedbase.ReferenceTableRefreshInterval.Get(&e.execCfg.Settings.SV)
+	return timeutil.Since(e.plannedAt) >= refreshInterval
+}
+
 // sameVersion returns true if row descriptor versions match.
 func sameVersion(currentVersion, newVersion *cdcevent.EventDescriptor) bool {
 	if currentVersion == nil {
@@ -274,6 +293,8 @@ func (e *familyEvaluator) planAndRun(ctx context.Context) (err error) {
 	}
 
 	e.setupProjection(plan.Presentation)
+	e.readsReferenceTables = plan.ReadsReferenceTables
+	e.plannedAt = timeutil.Now()
 	e.input, err = e.executePlan(ctx, plan, prevCol)
 	return err
 }
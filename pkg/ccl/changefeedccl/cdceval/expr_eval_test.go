@@ -15,6 +15,7 @@ import (
 	"sort"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
@@ -635,6 +636,100 @@ $$`)
 	}
 }
 
+// TestEvaluatorReferenceTables verifies that changefeed expressions may join
+// the target table against reference tables.
+func TestEvaluatorReferenceTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+
+	for _, l := range []serverutils.ApplicationLayerInterface{s, srv.SystemLayer()} {
+		kvserver.RangefeedEnabled.Override(ctx, &l.ClusterSettings().SV, true)
+	}
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE countries (code STRING PRIMARY KEY, name STRING, INDEX (name))`)
+	sqlDB.Exec(t, `INSERT INTO countries VALUES ('us', 'United States'), ('ca', 'Canada')`)
+	sqlDB.Exec(t, `CREATE TABLE users (id INT PRIMARY KEY, country STRING)`)
+
+	desc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "users")
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+	target := changefeedbase.Target{
+		TableID:    desc.GetID(),
+		FamilyName: desc.GetFamilies()[0].Name,
+	}
+
+	for _, tc := range []struct {
+		stmt      string
+		expectErr string
+	}{
+		{
+			stmt:      "SELECT * FROM users JOIN countries ON countries.code = users.country",
+			expectErr: "reference tables must be joined using LEFT JOIN",
+		},
+		{
+			stmt:      "SELECT * FROM users LEFT HASH JOIN countries ON countries.code = users.country",
+			expectErr: "join hint HASH not supported by CDC",
+		},
+		{
+			stmt:      "SELECT * FROM users LEFT JOIN countries ON countries.name = users.country",
+			expectErr: "reference table countries must be joined using LEFT JOIN on its primary key",
+		},
+	} {
+		_, err := newEvaluatorWithNormCheck(&execCfg, desc, s.Clock().Now(), target, tc.stmt)
+		require.Regexp(t, tc.expectErr, err, tc.stmt)
+	}
+
+	// Replan the expression on each event, so that changes to the reference
+	// table are observed right away.
+	changefeedbase.ReferenceTableRefreshInterval.Override(
+		ctx, &s.ClusterSettings().SV, time.Nanosecond)
+
+	e, err := newEvaluatorWithNormCheck(&execCfg, desc, s.Clock().Now(), target,
+		"SELECT id, c.name AS country_name FROM users "+
+			"LEFT JOIN countries AS c ON c.code = users.country")
+	require.NoError(t, err)
+	defer e.Close()
+
+	targets := changefeedbase.Targets{}
+	targets.Add(target)
+	decoder, err := cdcevent.NewEventDecoder(ctx, &execCfg, targets, false, false)
+	require.NoError(t, err)
+
+	popRow, cleanup := cdctest.MakeRangeFeedValueReader(t, s.ExecutorConfig(), desc)
+	defer cleanup()
+
+	evalNext := func(n int) (res []map[string]string) {
+		t.Helper()
+		for _, v := range readSortedRangeFeedValues(t, n, popRow) {
+			updatedRow := decodeRow(t, decoder, &v, cdcevent.CurrentRow)
+			prevRow := decodeRow(t, decoder, &v, cdcevent.PrevRow)
+			projection, err := e.Eval(ctx, updatedRow, prevRow)
+			require.NoError(t, err)
+			res = append(res, slurpValues(t, projection))
+		}
+		return res
+	}
+
+	sqlDB.Exec(t, `INSERT INTO users VALUES (1, 'us'), (2, 'fr'), (3, 'ca')`)
+	require.Equal(t, []map[string]string{
+		{"id": "1", "country_name": "United States"},
+		{"id": "2", "country_name": "NULL"},
+		{"id": "3", "country_name": "Canada"},
+	}, evalNext(3))
+
+	sqlDB.Exec(t, `INSERT INTO countries VALUES ('fr', 'France')`)
+	sqlDB.Exec(t, `UPDATE users SET country = 'fr' WHERE id = 1`)
+	require.Equal(t, []map[string]string{
+		{"id": "1", "country_name": "France"},
+	}, evalNext(1))
+}
+
 // Tests that use of volatile functions, without CDC specific override,
 // results in an error.
 func TestUnsupportedCDCFunctions(t *testing.T) {
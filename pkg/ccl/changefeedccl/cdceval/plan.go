@@ -172,6 +172,10 @@ func withPlanner(
 		// Current implementation relies on row-by-row evaluation;
 		// so, ensure vectorized engine is off.
 		sd.VectorizeMode = sessiondatapb.VectorizeOff
+		// Similarly, lookups into reference tables must be performed as soon as
+		// each row is received, instead of buffering input rows into batches.
+		sd.JoinReaderOrderingStrategyBatchSize = 1
+		sd.JoinReaderNoOrderingStrategyBatchSize = 1
 		planner, plannerCleanup := sql.NewInternalPlanner(
 			"cdc-expr", txn.KV(),
 			user,
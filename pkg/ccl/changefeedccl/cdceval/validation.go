@@ -47,10 +47,13 @@ func (n *NormalizedSelectClause) SelectStatementForFamily() *tree.Select {
 	// expression evaluation for different table column families.
 	sc := *n.SelectClause
 	sc.From.Tables = append(tree.TableExprs(nil), n.SelectClause.From.Tables...)
-	sc.From.Tables[0] = &tree.AliasedTableExpr{
-		Expr:       n.SelectClause.From.Tables[0],
-		IndexFlags: &tree.IndexFlags{FamilyID: &n.desc.FamilyID},
-	}
+	sc.From.Tables[0] = tree.ReplaceChangefeedTargetTableExpr(
+		n.SelectClause.From.Tables[0],
+		&tree.AliasedTableExpr{
+			Expr:       tree.ChangefeedTargetTableExpr(n.SelectClause.From.Tables[0]),
+			IndexFlags: &tree.IndexFlags{FamilyID: &n.desc.FamilyID},
+		},
+	)
 
 	return &tree.Select{Select: &sc}
 }
@@ -91,11 +94,17 @@ func normalizeAndValidateSelectForTarget(
 			target.TableID, desc.GetID())
 	}
 
+	refNames, err := validateReferenceTableJoins(sc.From.Tables[0])
+	if err != nil {
+		return nil, err
+	}
+
 	columnVisitor := checkColumnsVisitor{
 		desc:         desc,
+		refNames:     refNames,
 		splitColFams: splitColFams,
 	}
-	err := columnVisitor.FindColumnFamilies(sc)
+	err = columnVisitor.FindColumnFamilies(sc)
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +129,52 @@ func normalizeAndValidateSelectForTarget(
 	return normalized, nil
 }
 
+// validateReferenceTableJoins verifies that the target table is only joined
+// against reference tables using LEFT JOIN, and forces those joins to be
+// executed as lookup joins so that each event is evaluated as soon as it is
+// received. Returns the names (or aliases) of the reference tables.
+func validateReferenceTableJoins(from tree.TableExpr) (refNames []tree.Name, _ error) {
+	for {
+		j, ok := from.(*tree.JoinTableExpr)
+		if !ok {
+			return refNames, nil
+		}
+		if j.JoinType != tree.AstLeft {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"reference tables must be joined using LEFT JOIN")
+		}
+		if _, ok := j.Cond.(*tree.OnJoinCond); !ok {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"reference tables must be joined using an ON condition")
+		}
+		if j.Hint != "" && j.Hint != tree.AstLookup {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"join hint %s not supported by CDC", j.Hint)
+		}
+		j.Hint = tree.AstLookup
+
+		ref, ok := j.Right.(*tree.AliasedTableExpr)
+		if !ok {
+			return nil, errors.AssertionFailedf("unexpected reference table expression %T", j.Right)
+		}
+		switch t := ref.Expr.(type) {
+		case *tree.TableName:
+			if ref.As.Alias != "" {
+				refNames = append(refNames, ref.As.Alias)
+			} else {
+				refNames = append(refNames, t.ObjectName)
+			}
+		default:
+			if ref.As.Alias == "" {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"reference table %s must be aliased", tree.AsString(ref))
+			}
+			refNames = append(refNames, ref.As.Alias)
+		}
+		from = j.Left
+	}
+}
+
 func getExpressionTargetSpecification(
 	desc catalog.TableDescriptor,
 	target jobspb.ChangefeedTargetSpecification,
@@ -341,13 +396,30 @@ func normalizeSelectClause(
 }
 
 type checkColumnsVisitor struct {
-	err          error
-	desc         catalog.TableDescriptor
+	err  error
+	desc catalog.TableDescriptor
+	// refNames are the names (or aliases) of the reference tables joined
+	// against the target table. Columns of reference tables are ignored.
+	refNames     []tree.Name
 	columns      []descpb.ColumnID
 	seenStar     bool
 	splitColFams bool
 }
 
+// isReferenceTable returns true if the specified table prefix refers to
+// a reference table.
+func (c *checkColumnsVisitor) isReferenceTable(tn *tree.UnresolvedObjectName) bool {
+	if tn == nil {
+		return false
+	}
+	for _, name := range c.refNames {
+		if name == tree.Name(tn.Object()) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *checkColumnsVisitor) VisitCols(expr tree.Expr) (bool, tree.Expr) {
 	switch e := expr.(type) {
 	case *tree.UnresolvedName:
@@ -359,14 +431,27 @@ func (c *checkColumnsVisitor) VisitCols(expr tree.Expr) (bool, tree.Expr) {
 		return c.VisitCols(vn)
 
 	case *tree.ColumnItem:
+		if c.isReferenceTable(e.TableName) {
+			return false, expr
+		}
 		col, err := catalog.MustFindColumnByTreeName(c.desc, e.ColumnName)
 		if err != nil {
+			if len(c.refNames) > 0 && e.TableName == nil {
+				// Unqualified column may refer to one of the reference tables;
+				// let the optimizer resolve it.
+				return false, expr
+			}
 			c.err = err
 			return false, expr
 		}
 
 		c.columns = append(c.columns, col.GetID())
-	case tree.UnqualifiedStar, *tree.AllColumnsSelector:
+	case *tree.AllColumnsSelector:
+		if c.isReferenceTable(e.TableName) {
+			return false, expr
+		}
+		c.seenStar = true
+	case tree.UnqualifiedStar:
 		c.seenStar = true
 	}
 	return true, expr
@@ -9,6 +9,7 @@
 package cdctest
 
 import (
+	"compress/gzip"
 	"crypto/tls"
 	"io"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/errors"
+	"github.com/klauspost/compress/zstd"
 )
 
 // MockWebhookSink is the Webhook sink used in tests.
@@ -182,9 +184,28 @@ func (s *MockWebhookSink) requestHandler(hw http.ResponseWriter, hr *http.Reques
 	}
 }
 
+// decodeBody returns a reader over the decompressed body of the request,
+// according to its Content-Encoding header.
+func decodeBody(hr *http.Request) (io.Reader, error) {
+	switch encoding := hr.Header.Get("Content-Encoding"); encoding {
+	case "":
+		return hr.Body, nil
+	case "gzip":
+		return gzip.NewReader(hr.Body)
+	case "zstd":
+		return zstd.NewReader(hr.Body)
+	default:
+		return nil, errors.Newf("unsupported content encoding %q", encoding)
+	}
+}
+
 func (s *MockWebhookSink) publish(hw http.ResponseWriter, hr *http.Request) error {
 	defer hr.Body.Close()
-	row, err := io.ReadAll(hr.Body)
+	body, err := decodeBody(hr)
+	if err != nil {
+		return err
+	}
+	row, err := io.ReadAll(body)
 	if err != nil {
 		return err
 	}
@@ -107,7 +107,12 @@ func distChangefeedFlow(
 
 	var initialHighWater hlc.Timestamp
 	schemaTS := details.StatementTime
-	{
+	// While the initial scan of targets added by ALTER CHANGEFEED is pending,
+	// the high-water only applies to the other targets, which resume from the
+	// checkpoint. Plan the changefeed as if there were no high-water so that
+	// the added targets are scanned at the statement time.
+	if changefeedProgress := progress.GetChangefeed(); changefeedProgress == nil ||
+		changefeedProgress.AddedTargetsBackfill == nil {
 		if h := progress.GetHighWater(); h != nil && !h.IsEmpty() {
 			initialHighWater = *h
 			// If we have a high-water set, use it to compute the spans, since the
@@ -1265,7 +1265,7 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 		if ts := p.GetHighWater(); ts != nil {
 			cf.highWaterAtStart.Forward(*ts)
 			cf.frontier.initialHighWater = *ts
-			for _, span := range cf.spec.TrackedSpans {
+			for _, span := range spansResolvedAtHighWater(p, cf.spec.TrackedSpans) {
 				if _, err := cf.frontier.Forward(span, *ts); err != nil {
 					cf.MoveToDraining(err)
 					return
@@ -1580,9 +1580,7 @@ func (cf *changeFrontier) checkpointJobProgress(
 
 			// Advance resolved timestamp.
 			progress := md.Progress
-			progress.Progress = &jobspb.Progress_HighWater{
-				HighWater: &frontier,
-			}
+			highWater := advanceHighWater(progress, cf.spec.Feed.StatementTime, frontier)
 
 			changefeedProgress := progress.Details.(*jobspb.Progress_Changefeed).Changefeed
 			changefeedProgress.Checkpoint = &checkpoint
@@ -1593,7 +1591,12 @@ func (cf *changeFrontier) checkpointJobProgress(
 			}
 
 			if updateRunStatus {
-				md.Progress.RunningStatus = fmt.Sprintf("running: resolved=%s", frontier)
+				if changefeedProgress.AddedTargetsBackfill != nil {
+					md.Progress.RunningStatus = fmt.Sprintf(
+						"running: backfilling added targets as of %s", highWater)
+				} else {
+					md.Progress.RunningStatus = fmt.Sprintf("running: resolved=%s", frontier)
+				}
 			}
 
 			ju.UpdateProgress(progress)
@@ -1612,12 +1615,52 @@ func (cf *changeFrontier) checkpointJobProgress(
 		}
 	}
 
-	cf.localState.SetHighwater(frontier)
+	advanceHighWater(&cf.localState.progress, cf.spec.Feed.StatementTime, frontier)
 	cf.localState.SetCheckpoint(checkpoint.Spans, checkpoint.Timestamp)
 
 	return true, nil
 }
 
+// advanceHighWater records the frontier as the high-water of the changefeed
+// progress, and returns the high-water recorded. While the initial scan of
+// targets added by ALTER CHANGEFEED is pending, the frontier is below the
+// statement time the scan is performed at, and the high-water is held at the
+// statement time for the other targets instead; once the frontier reaches it,
+// the scan is complete.
+func advanceHighWater(
+	progress *jobspb.Progress, statementTime hlc.Timestamp, frontier hlc.Timestamp,
+) hlc.Timestamp {
+	highWater := frontier
+	if changefeedProgress := progress.GetChangefeed(); changefeedProgress != nil &&
+		changefeedProgress.AddedTargetsBackfill != nil {
+		if frontier.Less(statementTime) {
+			highWater = statementTime
+		} else {
+			changefeedProgress.AddedTargetsBackfill = nil
+		}
+	}
+	progress.Progress = &jobspb.Progress_HighWater{
+		HighWater: &highWater,
+	}
+	return highWater
+}
+
+// spansResolvedAtHighWater returns the tracked spans that are resolved at the
+// high-water of the changefeed progress. This excludes the spans of targets
+// added by ALTER CHANGEFEED whose initial scan is still pending.
+func spansResolvedAtHighWater(
+	progress jobspb.Progress, trackedSpans []roachpb.Span,
+) []roachpb.Span {
+	changefeedProgress := progress.GetChangefeed()
+	if changefeedProgress == nil || changefeedProgress.AddedTargetsBackfill == nil {
+		return trackedSpans
+	}
+	var resolved roachpb.SpanGroup
+	resolved.Add(trackedSpans...)
+	resolved.Sub(changefeedProgress.AddedTargetsBackfill.Spans...)
+	return resolved.Slice()
+}
+
 // manageProtectedTimestamps periodically advances the protected timestamp for
 // the changefeed's targets to the current highwater mark.  The record is
 // cleared during changefeedResumer.OnFailOrCancel
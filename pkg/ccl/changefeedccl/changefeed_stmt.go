@@ -1364,10 +1364,15 @@ func reconcileJobStateWithLocalState(
 	}
 
 	// Build frontier based on tracked spans.
-	sf, err := span.MakeFrontierAt(highWater, localState.trackedSpans...)
+	sf, err := span.MakeFrontier(localState.trackedSpans...)
 	if err != nil {
 		return err
 	}
+	for _, s := range spansResolvedAtHighWater(localState.progress, localState.trackedSpans) {
+		if _, err := sf.Forward(s, highWater); err != nil {
+			return err
+		}
+	}
 	// Advance frontier based on the information received from the aggregators.
 	for _, s := range localState.aggregatorFrontier {
 		_, err := sf.Forward(s.Span, s.Timestamp)
@@ -1389,7 +1394,8 @@ func reconcileJobStateWithLocalState(
 
 	if updateHW || updateSpanCheckpoint {
 		if updateHW {
-			localState.SetHighwater(sf.Frontier())
+			details := reloadedJob.Details().(jobspb.ChangefeedDetails)
+			advanceHighWater(&localState.progress, details.StatementTime, sf.Frontier())
 		}
 		localState.SetCheckpoint(checkpointSpans, checkpointTS)
 		if log.V(1) {
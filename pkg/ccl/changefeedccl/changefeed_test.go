@@ -10,6 +10,7 @@ package changefeedccl
 
 import (
 	"context"
+	"crypto/sha256"
 	gosql "database/sql"
 	"encoding/base64"
 	"encoding/json"
@@ -460,6 +461,51 @@ func TestChangefeedProgressMetrics(t *testing.T) {
 	})
 }
 
+// TestChangefeedPerTableMetrics verifies that changefeeds maintain metrics
+// labeled by target table without requiring any configuration.
+func TestChangefeedPerTableMetrics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1), (2)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1)`)
+
+		foobar := feed(t, f, `CREATE CHANGEFEED FOR foo, bar WITH metrics_label='label_a'`)
+		defer closeFeed(t, foobar)
+		assertPayloads(t, foobar, []string{
+			`bar: [1]->{"after": {"a": 1}}`,
+			`foo: [1]->{"after": {"a": 1}}`,
+			`foo: [2]->{"after": {"a": 2}}`,
+		})
+
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		sli, err := registry.MetricsStruct().Changefeed.(*Metrics).getSLIMetrics("label_a")
+		require.NoError(t, err)
+
+		for table, expectedRows := range map[string]int64{"foo": 2, "bar": 1} {
+			tm := sli.getTableMetrics(table)
+			testutils.SucceedsSoon(t, func() error {
+				if rows := tm.EmittedRows.Value(); rows != expectedRows {
+					return errors.Newf("expected %d rows emitted for %s, found %d",
+						expectedRows, table, rows)
+				}
+				return nil
+			})
+			require.Less(t, int64(0), tm.EmittedBytes.Value())
+			require.Less(t, int64(0), tm.EmitLag.Value())
+		}
+
+		// Tables not watched by any changefeed in the scope have no metrics.
+		require.Zero(t, sli.getTableMetrics("baz").EmittedRows.Value())
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
 func TestChangefeedIdleness(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -9260,6 +9306,72 @@ func TestPubsubAttributes(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink("pubsub"))
 }
 
+func TestPubsubOrderingKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		PubsubV2Enabled.Override(ctx, &s.Server.ClusterSettings().SV, true)
+		db := sqlutils.MakeSQLRunner(s.DB)
+		db.Exec(t, "CREATE TABLE foo (a INT PRIMARY KEY, b STRING)")
+
+		nextRaw := func(feed cdctest.TestFeed) *mockPubsubMessage {
+			msg, err := feed.(*pubsubFeed).Next()
+			require.NoError(t, err)
+			return msg.RawMessage.(*mockPubsubMessage)
+		}
+
+		t.Run("ordered", func(t *testing.T) {
+			foo, err := f.Feed(`CREATE CHANGEFEED FOR TABLE foo ` +
+				`INTO 'gcpubsub://testfeed?with_ordering_key=true' WITH initial_scan = 'no'`)
+			require.NoError(t, err)
+			defer closeFeed(t, foo)
+
+			db.Exec(t, "INSERT INTO foo VALUES (1, 'a')")
+			require.Equal(t, `[1]`, nextRaw(foo).orderingKey)
+		})
+
+		t.Run("default", func(t *testing.T) {
+			foo, err := f.Feed(`CREATE CHANGEFEED FOR TABLE foo WITH initial_scan = 'no'`)
+			require.NoError(t, err)
+			defer closeFeed(t, foo)
+
+			db.Exec(t, "UPSERT INTO foo VALUES (1, 'b')")
+			require.Empty(t, nextRaw(foo).orderingKey)
+		})
+
+		t.Run("unordered", func(t *testing.T) {
+			_, err := f.Feed(`CREATE CHANGEFEED FOR TABLE foo ` +
+				`INTO 'gcpubsub://testfeed?with_ordering_key=true' WITH initial_scan = 'no', unordered`)
+			require.ErrorContains(t, err, "with_ordering_key=true is incompatible with the unordered option")
+		})
+
+		t.Run("avro", func(t *testing.T) {
+			schemaReg := cdctest.StartTestSchemaRegistry()
+			defer schemaReg.Close()
+
+			foo, err := f.Feed(fmt.Sprintf(`CREATE CHANGEFEED FOR TABLE foo `+
+				`INTO 'gcpubsub://testfeed?with_ordering_key=true' `+
+				`WITH initial_scan = 'no', format = 'avro', confluent_schema_registry = '%s'`,
+				schemaReg.URL()))
+			require.NoError(t, err)
+			defer closeFeed(t, foo)
+
+			db.Exec(t, "UPSERT INTO foo VALUES (1, 'c')")
+			raw := nextRaw(foo)
+			// Avro-encoded keys are hashed into ordering keys.
+			require.Len(t, raw.orderingKey, 2*sha256.Size)
+			value, err := schemaReg.AvroToJSON([]byte(raw.data))
+			require.NoError(t, err)
+			require.Equal(t, `{"after":{"foo":{"a":{"long":1},"b":{"string":"c"}}}}`, string(value))
+			require.Contains(t, schemaReg.Subjects(), `foo-value`)
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("pubsub"))
+}
+
 // TestChangefeedAvroDecimalColumnWithDiff is a regression test for
 // https://github.com/cockroachdb/cockroach/issues/118647.
 func TestChangefeedAvroDecimalColumnWithDiff(t *testing.T) {
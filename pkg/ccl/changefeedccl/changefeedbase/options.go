@@ -220,6 +220,7 @@ const (
 	SinkParamSASLScopes             = `sasl_scopes`
 	SinkParamSASLGrantType          = `sasl_grant_type`
 	SinkParamTableNameAttribute     = `with_table_name_attribute`
+	SinkParamOrderingKey            = `with_ordering_key`
 
 	SinkSchemeConfluentKafka    = `confluent-cloud`
 	SinkParamConfluentAPIKey    = `api_key`
@@ -395,10 +396,11 @@ var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaReg
 var CloudStorageValidOptions = makeStringSet(OptCompression)
 
 // WebhookValidOptions is options exclusive to webhook sink
-var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig)
+var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig,
+	OptCompression)
 
 // PubsubValidOptions is options exclusive to pubsub sink
-var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig)
+var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig, OptAvroSchemaPrefix, OptConfluentSchemaRegistry)
 
 // ExternalConnectionValidOptions is options exclusive to the external
 // connection sink.
@@ -256,6 +256,20 @@ var EventConsumerPacerRequestSize = settings.RegisterDurationSetting(
 	settings.PositiveDuration,
 )
 
+// AlterAddTargetsInitialScanByDefault controls whether ALTER CHANGEFEED ...
+// ADD performs an initial scan of the added targets when neither initial_scan
+// nor no_initial_scan is specified. It can be disabled to restore the previous
+// behavior of only emitting subsequent changes to the added targets. It has no
+// effect until the cluster version is at least V24_2.
+var AlterAddTargetsInitialScanByDefault = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"changefeed.alter.add_targets_initial_scan.enabled",
+	"if enabled, targets added to a changefeed with ALTER CHANGEFEED are backfilled "+
+		"with an initial scan unless no_initial_scan is specified; the initial scan only "+
+		"covers the added targets; has no effect until the cluster is upgraded to 24.2",
+	true,
+)
+
 // PerEventElasticCPUControlEnabled determines whether changefeed event
 // processing integrates with elastic CPU control.
 var PerEventElasticCPUControlEnabled = settings.RegisterBoolSetting(
@@ -301,6 +315,30 @@ var SinkPacerRequestSize = settings.RegisterDurationSetting(
 	settings.PositiveDuration,
 )
 
+// ReferenceTableRefreshInterval controls how often changefeed expressions
+// joining reference tables are replanned. Reference tables are read as of the
+// time the expression was planned; replanning allows changes to the reference
+// tables to be observed.
+var ReferenceTableRefreshInterval = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"changefeed.expressions.reference_table_refresh_interval",
+	"the interval after which changefeed expressions joining reference tables "+
+		"are replanned in order to observe changes to the reference tables",
+	time.Minute,
+	settings.PositiveDuration,
+	settings.WithPublic)
+
+// PerTableMetricsEnabled controls whether changefeeds maintain metrics labeled
+// by target table.
+var PerTableMetricsEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"changefeed.table_metrics.enabled",
+	"if enabled, changefeeds maintain metrics (emitted rows, bytes and lag) for each target "+
+		"table, labeled by the metrics scope and the table name; these are exported "+
+		"when server.child_metrics.enabled is set",
+	true,
+	settings.WithPublic)
+
 // DefaultLaggingRangesThreshold is the default duration by which a range must be
 // lagging behind the present to be considered as 'lagging' behind in metrics.
 var DefaultLaggingRangesThreshold = 3 * time.Minute
@@ -69,6 +69,10 @@ type kvEventToRowConsumer struct {
 	metrics *sliMetrics
 	sv      *settings.Values
 
+	// tableMetrics caches per-table metrics, keyed by the statement time name
+	// of the target table.
+	tableMetrics map[changefeedbase.StatementTimeName]*tableMetrics
+
 	// This pacer is used to incorporate event consumption to elastic CPU
 	// control. This helps ensure that event encoding/decoding does not throttle
 	// foreground SQL traffic.
@@ -256,6 +260,7 @@ func newKVEventToRowConsumer(
 		metrics:              metrics,
 		pacer:                pacer,
 		sv:                   cfg.SV(),
+		tableMetrics:         make(map[changefeedbase.StatementTimeName]*tableMetrics),
 	}, nil
 }
 
@@ -422,10 +427,16 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	}
 
 	if c.encodingOpts.Format == changefeedbase.OptFormatParquet {
-		return c.encodeForParquet(
+		if err := c.encodeForParquet(
 			ctx, updatedRow, prevRow, topic, schemaTS, updatedRow.MvccTimestamp,
 			c.encodingOpts, alloc,
-		)
+		); err != nil {
+			return err
+		}
+		// The size of the encoded row is not known until the parquet file is
+		// written.
+		c.tableMetricsForTopic(topic).recordEmittedRow(schemaTS, 0 /* bytes */)
+		return nil
 	}
 	var keyCopy, valueCopy []byte
 	encodedKey, err := c.encoder.EncodeKey(ctx, updatedRow)
@@ -450,12 +461,28 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	); err != nil {
 		return err
 	}
+	c.tableMetricsForTopic(topic).recordEmittedRow(schemaTS, len(keyCopy)+len(valueCopy))
 	if log.V(3) {
 		log.Infof(ctx, `r %s: %s -> %s`, updatedRow.TableName, keyCopy, valueCopy)
 	}
 	return nil
 }
 
+// tableMetricsForTopic returns per-table metrics for the target table of the
+// topic, or nil if per-table metrics are disabled.
+func (c *kvEventToRowConsumer) tableMetricsForTopic(topic TopicDescriptor) *tableMetrics {
+	if !changefeedbase.PerTableMetricsEnabled.Get(c.sv) {
+		return nil
+	}
+	table := topic.GetTargetSpecification().StatementTimeName
+	tm, ok := c.tableMetrics[table]
+	if !ok {
+		tm = c.metrics.getTableMetrics(string(table))
+		c.tableMetrics[table] = tm
+	}
+	return tm
+}
+
 // Close closes this consumer.
 func (c *kvEventToRowConsumer) Close() error {
 	c.pacer.Close()
@@ -48,6 +48,10 @@ const maxSLIScopeNameLen = 128
 // keeping track of all changefeeds which did not have explicit sli scope specified.
 const defaultSLIScope = "default"
 
+// maxTablesPerSLIScope is the maximum number of target tables for which
+// per-table metrics are maintained in a single SLI scope.
+const maxTablesPerSLIScope = 1024
+
 // AggMetrics are aggregated metrics keeping track of aggregated changefeed performance
 // indicators, combined with a limited number of per-changefeed indicators.
 type AggMetrics struct {
@@ -82,6 +86,11 @@ type AggMetrics struct {
 	CloudstorageBufferedBytes   *aggmetric.AggGauge
 	KafkaThrottlingNanos        *aggmetric.AggHistogram
 
+	// Per-table metrics, labeled with the scope and the target table.
+	TableEmittedRows  *aggmetric.AggCounter
+	TableEmittedBytes *aggmetric.AggCounter
+	TableEmitLag      *aggmetric.AggGauge
+
 	// There is always at least 1 sliMetrics created for defaultSLI scope.
 	mu struct {
 		syncutil.Mutex
@@ -154,12 +163,57 @@ type sliMetrics struct {
 	CloudstorageBufferedBytes   *aggmetric.Gauge
 	KafkaThrottlingNanos        *aggmetric.Histogram
 
+	scope string
+	agg   *AggMetrics
+
 	mu struct {
 		syncutil.Mutex
 		id         int64
 		resolved   map[int64]hlc.Timestamp
 		checkpoint map[int64]hlc.Timestamp
+		tables     map[string]*tableMetrics
+	}
+}
+
+// tableMetrics holds metrics for a single target table.
+type tableMetrics struct {
+	EmittedRows  *aggmetric.Counter
+	EmittedBytes *aggmetric.Counter
+	EmitLag      *aggmetric.Gauge
+}
+
+// getTableMetrics returns metrics for the specified target table, creating
+// them if needed. Returns nil if too many tables are tracked in this scope.
+func (m *sliMetrics) getTableMetrics(table string) *tableMetrics {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tm, ok := m.mu.tables[table]; ok {
+		return tm
+	}
+	if len(m.mu.tables) == maxTablesPerSLIScope {
+		return nil
+	}
+	tm := &tableMetrics{
+		EmittedRows:  m.agg.TableEmittedRows.AddChild(m.scope, table),
+		EmittedBytes: m.agg.TableEmittedBytes.AddChild(m.scope, table),
+		EmitLag:      m.agg.TableEmitLag.AddChild(m.scope, table),
 	}
+	m.mu.tables[table] = tm
+	return tm
+}
+
+// recordEmittedRow records a row emitted to the sink. The lag is measured
+// relative to the updated timestamp of the row.
+func (m *tableMetrics) recordEmittedRow(updated hlc.Timestamp, bytes int) {
+	if m == nil {
+		return
+	}
+	m.EmittedRows.Inc(1)
+	m.EmittedBytes.Inc(int64(bytes))
+	m.EmitLag.Update(timeutil.Since(updated.GoTime()).Nanoseconds())
 }
 
 // closeId unregisters an id. The id can still be used after its closed, but
@@ -825,6 +879,25 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 		Measurement: "Nanoseconds",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaChangefeedTableEmittedRows := metric.Metadata{
+		Name:        "changefeed.table_metrics.emitted_rows",
+		Help:        "Rows emitted to the sink, labeled by target table",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaChangefeedTableEmittedBytes := metric.Metadata{
+		Name:        "changefeed.table_metrics.emitted_bytes",
+		Help:        "Bytes of encoded rows emitted to the sink, labeled by target table",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaChangefeedTableEmitLag := metric.Metadata{
+		Name: "changefeed.table_metrics.emit_lag",
+		Help: "Difference between the updated timestamp of the most recently emitted row " +
+			"and the time it was emitted to the sink, labeled by target table",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 
 	functionalGaugeMinFn := func(childValues []int64) int64 {
 		var min int64
@@ -840,6 +913,7 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 	// retain significant figures of 2.
 	b := aggmetric.MakeBuilder("scope")
 	emittedMessagesBuilder := aggmetric.MakeBuilder("scope", "message_type")
+	tableBuilder := aggmetric.MakeBuilder("scope", "table")
 	a := &AggMetrics{
 		ErrorRetries:    b.Counter(metaChangefeedErrorRetries),
 		EmittedMessages: emittedMessagesBuilder.Counter(metaChangefeedEmittedMessages),
@@ -925,6 +999,9 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 			SigFigs:      2,
 			BucketConfig: metric.BatchProcessLatencyBuckets,
 		}),
+		TableEmittedRows:  tableBuilder.Counter(metaChangefeedTableEmittedRows),
+		TableEmittedBytes: tableBuilder.Counter(metaChangefeedTableEmittedBytes),
+		TableEmitLag:      tableBuilder.Gauge(metaChangefeedTableEmitLag),
 	}
 	a.mu.sliMetrics = make(map[string]*sliMetrics)
 	_, err := a.getOrCreateScope(defaultSLIScope)
@@ -992,9 +1069,12 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 		LaggingRanges:               a.LaggingRanges.AddChild(scope),
 		CloudstorageBufferedBytes:   a.CloudstorageBufferedBytes.AddChild(scope),
 		KafkaThrottlingNanos:        a.KafkaThrottlingNanos.AddChild(scope),
+		scope:                       scope,
+		agg:                         a,
 	}
 	sm.mu.resolved = make(map[int64]hlc.Timestamp)
 	sm.mu.checkpoint = make(map[int64]hlc.Timestamp)
+	sm.mu.tables = make(map[string]*tableMetrics)
 	sm.mu.id = 1 // start the first id at 1 so we can detect intiialization
 
 	minTimestampGetter := func(m map[int64]hlc.Timestamp) func() int64 {
@@ -15,12 +15,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
 )
 
 // createProtectedTimestampRecord will create a record to protect the spans for
@@ -42,6 +44,73 @@ func createProtectedTimestampRecord(
 		jobsprotectedts.Jobs, targetToProtect)
 }
 
+// replaceProtectedTimestampTargets ensures that the protected timestamp record
+// referenced by progress protects exactly the given targets. ALTER CHANGEFEED
+// may add or remove targets; if the record's targets no longer match, a new
+// record is written at the old record's timestamp and the old one is released
+// so that removed tables are no longer held back from garbage collection.
+func replaceProtectedTimestampTargets(
+	ctx context.Context,
+	pts protectedts.Storage,
+	codec keys.SQLCodec,
+	jobID jobspb.JobID,
+	targets changefeedbase.Targets,
+	progress *jobspb.ChangefeedProgress,
+) error {
+	if progress == nil || progress.ProtectedTimestampRecord == uuid.Nil {
+		return nil
+	}
+
+	rec, err := pts.GetRecord(ctx, progress.ProtectedTimestampRecord)
+	if err != nil {
+		if errors.Is(err, protectedts.ErrNotExists) {
+			// The frontier will create a new record once the changefeed resumes.
+			progress.ProtectedTimestampRecord = uuid.Nil
+			return nil
+		}
+		return err
+	}
+	// Deprecated records without a target are migrated by the frontier.
+	if rec.Target == nil || sameSchemaObjects(rec.Target, makeTargetToProtect(targets)) {
+		return nil
+	}
+
+	prevRecordID := progress.ProtectedTimestampRecord
+	ptr := createProtectedTimestampRecord(ctx, codec, jobID, targets, rec.Timestamp)
+	if err := pts.Protect(ctx, ptr); err != nil {
+		return err
+	}
+	progress.ProtectedTimestampRecord = ptr.ID.GetUUID()
+	if err := pts.Release(ctx, prevRecordID); err != nil {
+		return err
+	}
+	log.VEventf(ctx, 2, "replaced pts record %v with %v at %v after altering targets",
+		prevRecordID, progress.ProtectedTimestampRecord, rec.Timestamp)
+	return nil
+}
+
+// sameSchemaObjects returns whether both targets protect the same set of
+// schema objects, irrespective of order.
+func sameSchemaObjects(a, b *ptpb.Target) bool {
+	aObjs, bObjs := a.GetSchemaObjects(), b.GetSchemaObjects()
+	if aObjs == nil || bObjs == nil {
+		return aObjs == bObjs
+	}
+	ids := make(map[descpb.ID]struct{}, len(aObjs.IDs))
+	for _, id := range aObjs.IDs {
+		ids[id] = struct{}{}
+	}
+	if len(ids) != len(bObjs.IDs) {
+		return false
+	}
+	for _, id := range bObjs.IDs {
+		if _, ok := ids[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 func makeTargetToProtect(targets changefeedbase.Targets) *ptpb.Target {
 	// NB: We add 1 because we're also going to protect system.descriptors.
 	// We protect system.descriptors because a changefeed needs all of the history
@@ -400,6 +400,69 @@ func TestChangefeedAlterPTS(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks)
 }
 
+// TestChangefeedAlterDropTargetReleasesPTS verifies that dropping a target
+// with ALTER CHANGEFEED replaces the protected timestamp record with one that
+// no longer protects the dropped table.
+func TestChangefeedAlterDropTargetReleasesPTS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServerWithSystem, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo, bar WITH resolved = '20ms'`)
+		defer closeFeed(t, testFeed)
+
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		execCfg := s.Server.ExecutorConfig().(sql.ExecutorConfig)
+		ptp := s.Server.DistSQLServer().(*distsql.ServerImpl).ServerConfig.ProtectedTimestampProvider
+		fooID := desctestutils.TestingGetPublicTableDescriptor(s.SystemServer.DB(), s.Codec, "d", "foo").GetID()
+		barID := desctestutils.TestingGetPublicTableDescriptor(s.SystemServer.DB(), s.Codec, "d", "bar").GetID()
+
+		jobFeed := testFeed.(cdctest.EnterpriseTestFeed)
+		getPTSRecordID := func() uuid.UUID {
+			var recordID uuid.UUID
+			testutils.SucceedsSoon(t, func() error {
+				uid := loadProgress(t, jobFeed, registry).GetChangefeed().ProtectedTimestampRecord
+				if uid == uuid.Nil {
+					return errors.Newf("no pts record")
+				}
+				recordID = uid
+				return nil
+			})
+			return recordID
+		}
+		readPTSRecord := func(recID uuid.UUID) (rec *ptpb.Record, err error) {
+			err = execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+				rec, err = ptp.WithTxn(txn).GetRecord(ctx, recID)
+				return err
+			})
+			return
+		}
+
+		oldRecordID := getPTSRecordID()
+		require.NoError(t, jobFeed.Pause())
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d DROP bar`, jobFeed.JobID()))
+
+		newRecordID := loadProgress(t, jobFeed, registry).GetChangefeed().ProtectedTimestampRecord
+		require.NotEqual(t, oldRecordID, newRecordID)
+		newRec, err := readPTSRecord(newRecordID)
+		require.NoError(t, err)
+		targetIDs := newRec.Target.GetSchemaObjects().IDs
+		require.Contains(t, targetIDs, fooID)
+		require.Contains(t, targetIDs, descpb.ID(keys.DescriptorTableID))
+		require.NotContains(t, targetIDs, barID)
+
+		_, err = readPTSRecord(oldRecordID)
+		require.ErrorContains(t, err, "does not exist")
+	}
+
+	cdcTestWithSystem(t, testFn, feedTestEnterpriseSinks)
+}
+
 // TestChangefeedCanceledWhenPTSIsOld is a test for the setting
 // `kv.closed_timestamp.target_duration` which ensures that a paused changefeed
 // job holding a PTS record gets canceled if paused for too long.
@@ -288,15 +288,26 @@ func makeScheduledChangefeedSpec(
 	// when we prepare the changefeed expression to be serialized.
 	if schedule.Select != nil {
 		tableExprs := make(tree.TableExprs, 1)
-		// For cdc transformations, we expect only 1 table. Therefore, we can always
-		// expect the size of qualifiedTablePatterns to be 1 - which is why we can
-		// directly index with 0.
+		// For cdc transformations, we expect only 1 target table. Therefore, we
+		// can always expect the size of qualifiedTablePatterns to be 1 - which is
+		// why we can directly index with 0.
 		// We can directly typecast tree.TablePatter to tree.TableExpr without
 		// checking for error because when parsing the statement we typecast
 		// tree.TableExpr (from Select clause) into tree.ChangefeedTarget. If that
 		// typecasting was successful, it is guaranteed that the reverse should work
 		// without any errors.
-		tableExprs[0] = qualifiedTablePatterns[0].(tree.TableExpr)
+		// The target table may be joined against reference tables, in which case
+		// only the target table expression is replaced, preserving its alias since
+		// the join conditions may refer to it.
+		target := qualifiedTablePatterns[0].(tree.TableExpr)
+		from := schedule.Select.From.Tables[0]
+		if t, ok := tree.ChangefeedTargetTableExpr(from).(*tree.AliasedTableExpr); ok &&
+			t.As.Alias != "" {
+			aliased := *t
+			aliased.Expr = target
+			target = &aliased
+		}
+		tableExprs[0] = tree.ReplaceChangefeedTargetTableExpr(from, target)
 		schedule.Select.From.Tables = tableExprs
 	}
 
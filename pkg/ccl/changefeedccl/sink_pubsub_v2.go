@@ -11,9 +11,12 @@ package changefeedccl
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/url"
 	"time"
+	"unicode/utf8"
 
 	pubsub "cloud.google.com/go/pubsub/apiv1"
 	pb "cloud.google.com/go/pubsub/apiv1/pubsubpb"
@@ -45,6 +48,10 @@ const gcpScope = "https://www.googleapis.com/auth/pubsub"
 const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
 const globalGCPEndpoint = "pubsub.googleapis.com:443"
 
+// pubsubMaxOrderingKeyLength is the maximum length of an ordering key accepted
+// by Pub/Sub.
+const pubsubMaxOrderingKeyLength = 1024
+
 // isPubsubSink returns true if url contains scheme with valid pubsub sink
 func isPubsubSink(u *url.URL) bool {
 	return u.Scheme == GcpScheme
@@ -57,7 +64,11 @@ type pubsubSinkClient struct {
 	format                 changefeedbase.FormatType
 	batchCfg               sinkBatchConfig
 	withTableNameAttribute bool
-	mu                     struct {
+	// withOrderingKeys is set if the sink URI has with_ordering_key=true, in
+	// which case messages are published with ordering keys derived from their
+	// keys.
+	withOrderingKeys bool
+	mu               struct {
 		syncutil.RWMutex
 
 		// Topic creation errors may not be an actual issue unless the Publish call
@@ -81,6 +92,7 @@ func makePubsubSinkClient(
 	batchCfg sinkBatchConfig,
 	unordered bool,
 	withTableNameAttribute bool,
+	withOrderingKeys bool,
 	knobs *TestingKnobs,
 ) (SinkClient, error) {
 	if u.Scheme != GcpScheme {
@@ -93,6 +105,10 @@ func makePubsubSinkClient(
 		formatType = changefeedbase.OptFormatJSON
 	case changefeedbase.OptFormatCSV:
 		formatType = changefeedbase.OptFormatCSV
+	case changefeedbase.OptFormatAvro:
+		// Avro messages are published in the Confluent wire format, referencing
+		// schemas that the encoder registers in the schema registry.
+		formatType = changefeedbase.OptFormatAvro
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, encodingOpts.Format)
@@ -131,6 +147,7 @@ func makePubsubSinkClient(
 		batchCfg:               batchCfg,
 		projectID:              projectID,
 		withTableNameAttribute: withTableNameAttribute,
+		withOrderingKeys:       withOrderingKeys,
 	}
 	sinkClient.mu.topicCache = make(map[string]struct{})
 
@@ -241,11 +258,14 @@ func (psb *pubsubBuffer) Append(key []byte, value []byte, attributes attributes)
 		buffer.Write(psb.topicEncoded)
 		buffer.WriteString("}")
 		content = buffer.Bytes()
-	case changefeedbase.OptFormatCSV:
+	case changefeedbase.OptFormatCSV, changefeedbase.OptFormatAvro:
 		content = value
 	}
 
 	msg := &pb.PubsubMessage{Data: content}
+	if psb.sc.withOrderingKeys {
+		msg.OrderingKey = pubsubOrderingKey(key)
+	}
 	if psb.sc.withTableNameAttribute {
 		if _, ok := psb.attributesCache[attributes]; !ok {
 			psb.attributesCache[attributes] = map[string]string{"TABLE_NAME": attributes.tableName}
@@ -257,6 +277,18 @@ func (psb *pubsubBuffer) Append(key []byte, value []byte, attributes attributes)
 	psb.numBytes += len(content)
 }
 
+// pubsubOrderingKey returns the ordering key under which a message with the
+// given key is published, so that Pub/Sub delivers the messages for a row in
+// order. Keys that cannot be used as ordering keys as-is, such as Avro-encoded
+// keys or keys exceeding the maximum length, are hashed instead.
+func pubsubOrderingKey(key []byte) string {
+	if len(key) <= pubsubMaxOrderingKeyLength && utf8.Valid(key) {
+		return string(key)
+	}
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])
+}
+
 // Close implements the BatchBuffer interface
 func (psb *pubsubBuffer) Close() (SinkPayload, error) {
 	return &pb.PublishRequest{
@@ -442,8 +474,19 @@ func makePubsubSink(
 	if err != nil {
 		return nil, err
 	}
+	// Ordering keys are opt-in until they have been validated with
+	// subscriptions which have message ordering enabled.
+	var withOrderingKeys bool
+	_, err = pubsubURL.consumeBool(changefeedbase.SinkParamOrderingKey, &withOrderingKeys)
+	if err != nil {
+		return nil, err
+	}
+	if withOrderingKeys && unordered {
+		return nil, errors.Errorf("%s=true is incompatible with the %s option",
+			changefeedbase.SinkParamOrderingKey, changefeedbase.OptUnordered)
+	}
 	sinkClient, err := makePubsubSinkClient(ctx, u, encodingOpts, targets, batchCfg, unordered,
-		includeTableNameAttribute, knobs)
+		includeTableNameAttribute, withOrderingKeys, knobs)
 	if err != nil {
 		return nil, err
 	}
@@ -252,6 +252,11 @@ func makeDeprecatedWebhookSink(
 			changefeedbase.OptEnvelope, encodingOpts.Envelope)
 	}
 
+	if encodingOpts.Compression != "" {
+		return nil, errors.Errorf(`%s requires %s to be enabled`,
+			changefeedbase.OptCompression, WebhookV2Enabled.Name())
+	}
+
 	encodingOpts.TopicInValue = true
 
 	if encodingOpts.Envelope != changefeedbase.OptEnvelopeBare {
@@ -333,6 +333,73 @@ func TestWebhookSinkWithAuthOptions(t *testing.T) {
 	}
 }
 
+func TestWebhookSinkCompression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, compression := range []string{"gzip", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			cert, certEncoded, err := cdctest.NewCACertBase64Encoded()
+			require.NoError(t, err)
+			sinkDest, err := cdctest.StartMockWebhookSink(cert)
+			require.NoError(t, err)
+			defer sinkDest.Close()
+
+			sinkDestHost, err := url.Parse(sinkDest.URL())
+			require.NoError(t, err)
+			params := sinkDestHost.Query()
+			params.Set(changefeedbase.SinkParamCACert, certEncoded)
+			sinkDestHost.RawQuery = params.Encode()
+
+			opts := getGenericWebhookSinkOptions(struct {
+				key   string
+				value string
+			}{
+				key:   changefeedbase.OptCompression,
+				value: compression,
+			})
+			details := jobspb.ChangefeedDetails{
+				SinkURI: fmt.Sprintf("webhook-%s", sinkDestHost.String()),
+				Opts:    opts.AsMap(),
+			}
+
+			// The mock sink decompresses request bodies according to their
+			// Content-Encoding, so the rows should be received unchanged.
+			sinkSrc, err := setupWebhookSinkWithDetails(
+				context.Background(), details, 2 /* parallelism */, timeutil.DefaultTimeSource{},
+			)
+			require.NoError(t, err)
+			testSendAndReceiveRows(t, sinkSrc, sinkDest)
+			require.NoError(t, sinkSrc.Close())
+
+			// The payload of a batch is compressed, and its compressed size is
+			// reported.
+			encodingOpts, err := opts.GetEncodingOptions()
+			require.NoError(t, err)
+			sinkOpts, err := opts.GetWebhookSinkOptions()
+			require.NoError(t, err)
+			u, err := url.Parse(details.SinkURI)
+			require.NoError(t, err)
+			client, err := makeWebhookSinkClient(
+				context.Background(), sinkURL{URL: u}, encodingOpts, sinkOpts, sinkBatchConfig{},
+				1 /* parallelism */, cluster.MakeTestingClusterSettings(),
+			)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, client.Close()) }()
+
+			buf := client.MakeBatchBuffer("")
+			value := []byte(strings.Repeat(`{"after":{"col1":"val1"}}`, 100))
+			buf.Append([]byte("[1001]"), value, attributes{})
+			payload, err := buf.Close()
+			require.NoError(t, err)
+			req := payload.(*http.Request)
+			require.Equal(t, compression, req.Header.Get("Content-Encoding"))
+			size := client.(sinkPayloadSizer).compressedSize(payload)
+			require.Equal(t, int(req.ContentLength), size)
+			require.Less(t, size, len(value))
+		})
+	}
+}
+
 func TestWebhookSinkConfig(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
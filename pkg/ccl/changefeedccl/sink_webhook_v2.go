@@ -31,9 +31,10 @@ import (
 )
 
 const (
-	applicationTypeJSON = `application/json`
-	applicationTypeCSV  = `text/csv`
-	authorizationHeader = `Authorization`
+	applicationTypeJSON   = `application/json`
+	applicationTypeCSV    = `text/csv`
+	authorizationHeader   = `Authorization`
+	contentEncodingHeader = `Content-Encoding`
 )
 
 func isWebhookSink(u *url.URL) bool {
@@ -53,10 +54,15 @@ type webhookSinkClient struct {
 	authHeader string
 	batchCfg   sinkBatchConfig
 	client     *httputil.Client
+	settings   *cluster.Settings
+
+	// compression is the algorithm used to compress request bodies, if any.
+	compression compressionAlgo
 }
 
 var _ SinkClient = (*webhookSinkClient)(nil)
 var _ SinkPayload = (*http.Request)(nil)
+var _ sinkPayloadSizer = (*webhookSinkClient)(nil)
 
 func makeWebhookSinkClient(
 	ctx context.Context,
@@ -65,6 +71,7 @@ func makeWebhookSinkClient(
 	opts changefeedbase.WebhookSinkOptions,
 	batchCfg sinkBatchConfig,
 	parallelism int,
+	settings *cluster.Settings,
 ) (SinkClient, error) {
 	err := validateWebhookOpts(u, encodingOpts, opts)
 	if err != nil {
@@ -78,6 +85,14 @@ func makeWebhookSinkClient(
 		authHeader: opts.AuthHeader,
 		format:     encodingOpts.Format,
 		batchCfg:   batchCfg,
+		settings:   settings,
+	}
+
+	if codec := encodingOpts.Compression; codec != "" {
+		sinkClient.compression, _, err = compressionFromString(codec)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var connTimeout time.Duration
@@ -179,10 +194,20 @@ func makeWebhookClient(
 }
 
 func (sc *webhookSinkClient) makePayloadForBytes(body []byte) (SinkPayload, error) {
+	if sc.compression.enabled() {
+		var err error
+		if body, err = sc.compress(body); err != nil {
+			return nil, err
+		}
+	}
+
 	req, err := http.NewRequestWithContext(sc.ctx, http.MethodPost, sc.url.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
+	if sc.compression.enabled() {
+		req.Header.Set(contentEncodingHeader, string(sc.compression))
+	}
 	switch sc.format {
 	case changefeedbase.OptFormatJSON:
 		req.Header.Set("Content-Type", applicationTypeJSON)
@@ -197,6 +222,30 @@ func (sc *webhookSinkClient) makePayloadForBytes(body []byte) (SinkPayload, erro
 	return req, nil
 }
 
+// compress returns the body compressed with the configured algorithm.
+func (sc *webhookSinkClient) compress(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	codec, err := newCompressionCodec(sc.compression, &sc.settings.SV, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := codec.Write(body); err != nil {
+		return nil, err
+	}
+	if err := codec.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// compressedSize implements the sinkPayloadSizer interface.
+func (sc *webhookSinkClient) compressedSize(payload SinkPayload) int {
+	if !sc.compression.enabled() {
+		return sinkDoesNotCompress
+	}
+	return int(payload.(*http.Request).ContentLength)
+}
+
 // FlushResolvedPayload implements the SinkClient interface
 func (sc *webhookSinkClient) FlushResolvedPayload(
 	ctx context.Context, body []byte, _ func(func(topic string) error) error, retryOpts retry.Options,
@@ -362,7 +411,9 @@ func makeWebhookSink(
 		return nil, err
 	}
 
-	sinkClient, err := makeWebhookSinkClient(ctx, u, encodingOpts, opts, batchCfg, parallelism)
+	sinkClient, err := makeWebhookSinkClient(
+		ctx, u, encodingOpts, opts, batchCfg, parallelism, settings,
+	)
 	if err != nil {
 		return nil, err
 	}
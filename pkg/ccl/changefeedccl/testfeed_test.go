@@ -2214,6 +2214,8 @@ type mockPubsubMessage struct {
 	attributes map[string]string
 	// topic is only populated for the non-deprecated pubsub sink.
 	topic string
+	// orderingKey is only populated for the non-deprecated pubsub sink.
+	orderingKey string
 }
 
 type deprecatedMockPubsubMessageBuffer struct {
@@ -2321,7 +2323,12 @@ func (ps *fakePubsubServer) React(req interface{}) (handled bool, ret interface{
 
 		for _, msg := range publishReq.Messages {
 			ps.mu.buffer = append(ps.mu.buffer,
-				mockPubsubMessage{data: string(msg.Data), topic: publishReq.Topic, attributes: msg.Attributes})
+				mockPubsubMessage{
+					data:        string(msg.Data),
+					topic:       publishReq.Topic,
+					attributes:  msg.Attributes,
+					orderingKey: msg.OrderingKey,
+				})
 		}
 		if ps.mu.notify != nil {
 			notifyCh := ps.mu.notify
@@ -2535,7 +2542,7 @@ func (p *pubsubFeed) Next() (*cdctest.TestFeedMessage, error) {
 						continue
 					}
 				}
-			case changefeedbase.OptFormatCSV:
+			case changefeedbase.OptFormatCSV, changefeedbase.OptFormatAvro:
 				m.Value = []byte(msg.data)
 			default:
 				return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptFormat, v)
@@ -0,0 +1,123 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package tenantcostclient
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// IndexReadSampleRate is the fraction of KV read batches whose cost is
+// attributed to the index they read from. It is exported for testing purposes.
+var IndexReadSampleRate = settings.RegisterFloatSetting(
+	settings.ApplicationLevel,
+	"tenant_cost_control.index_reads.sample_rate",
+	"fraction of KV read batches whose request units are attributed to the index "+
+		"they read from, for crdb_internal.node_index_read_usage; 0 disables the "+
+		"tracking of index reads",
+	0.01,
+	settings.FloatInRange(0, 1),
+)
+
+// maxTrackedIndexes is the maximum number of indexes whose reads are tracked.
+// Once the limit is reached, the least recently read index is evicted to make
+// room for a new one.
+const maxTrackedIndexes = 10000
+
+// indexReadTracker attributes the cost of a sample of the KV reads of the SQL
+// instance to the indexes they read from. The usage of each index is
+// extrapolated from the sample by weighting each sampled batch by the inverse
+// of the sample rate.
+type indexReadTracker struct {
+	sv    *settings.Values
+	codec keys.SQLCodec
+
+	mu struct {
+		syncutil.Mutex
+		// indexes maps indexReadKeys to *multitenant.IndexReadUsage.
+		indexes *cache.UnorderedCache
+	}
+}
+
+type indexReadKey struct {
+	tableID, indexID uint32
+}
+
+func (t *indexReadTracker) init(sv *settings.Values, codec keys.SQLCodec) {
+	t.sv = sv
+	t.codec = codec
+	t.mu.indexes = cache.NewUnorderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(size int, _, _ interface{}) bool {
+			return size > maxTrackedIndexes
+		},
+	})
+}
+
+// maybeRecord attributes the given read batch, which cost the given number of
+// RUs, to the index it read from, if the batch is sampled.
+func (t *indexReadTracker) maybeRecord(resp tenantcostmodel.ResponseInfo, ru tenantcostmodel.RU) {
+	rate := IndexReadSampleRate.Get(t.sv)
+	if rate == 0 || !resp.IsRead() || (rate < 1 && rand.Float64() >= rate) {
+		return
+	}
+	_, tableID, indexID, err := t.codec.DecodeIndexPrefix(resp.ReadKey())
+	if err != nil {
+		// The batch did not read from an index (e.g. it read a range of keys
+		// which is not part of the SQL keyspace).
+		return
+	}
+	key := indexReadKey{tableID: tableID, indexID: indexID}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var usage *multitenant.IndexReadUsage
+	if v, ok := t.mu.indexes.Get(key); ok {
+		usage = v.(*multitenant.IndexReadUsage)
+	} else {
+		usage = &multitenant.IndexReadUsage{TableID: tableID, IndexID: indexID}
+		t.mu.indexes.Add(key, usage)
+	}
+	usage.RU += float64(ru) / rate
+	usage.ReadBytes += int64(float64(resp.ReadBytes()) / rate)
+	usage.ReadRequests += int64(float64(resp.ReadCount()) / rate)
+	usage.SampledBatches++
+}
+
+// topN returns the usage of the limit indexes with the highest read cost, in
+// decreasing order of cost.
+func (t *indexReadTracker) topN(limit int) []multitenant.IndexReadUsage {
+	t.mu.Lock()
+	res := make([]multitenant.IndexReadUsage, 0, t.mu.indexes.Len())
+	t.mu.indexes.Do(func(e *cache.Entry) {
+		res = append(res, *e.Value.(*multitenant.IndexReadUsage))
+	})
+	t.mu.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].RU != res[j].RU {
+			return res[i].RU > res[j].RU
+		}
+		if res[i].TableID != res[j].TableID {
+			return res[i].TableID < res[j].TableID
+		}
+		return res[i].IndexID < res[j].IndexID
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
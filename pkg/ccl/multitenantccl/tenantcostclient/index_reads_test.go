@@ -0,0 +1,63 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package tenantcostclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIndexReadTrackerEviction verifies that the least recently read index is
+// evicted once maxTrackedIndexes indexes are tracked.
+func TestIndexReadTrackerEviction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	IndexReadSampleRate.Override(context.Background(), &st.SV, 1)
+	codec := keys.MakeSQLCodec(roachpb.MustMakeTenantID(10))
+
+	var tracker indexReadTracker
+	tracker.init(&st.SV, codec)
+	read := func(tableID uint32) {
+		resp := tenantcostmodel.TestingResponseInfo(
+			true /* isRead */, 1 /* readCount */, 10 /* readBytes */, 0, /* networkCost */
+		).WithReadKey(codec.IndexPrefix(tableID, 1 /* indexID */))
+		tracker.maybeRecord(resp, 1)
+	}
+	tracked := func() map[uint32]bool {
+		res := make(map[uint32]bool)
+		for _, usage := range tracker.topN(maxTrackedIndexes + 1) {
+			res[usage.TableID] = true
+		}
+		return res
+	}
+
+	const firstID = 100
+	for id := uint32(firstID); id < firstID+maxTrackedIndexes; id++ {
+		read(id)
+	}
+	require.Len(t, tracked(), maxTrackedIndexes)
+
+	// Read the first index again, so that the second one is the least recently
+	// read and gets evicted by a new index.
+	read(firstID)
+	read(firstID + maxTrackedIndexes)
+	res := tracked()
+	require.Len(t, res, maxTrackedIndexes)
+	require.True(t, res[firstID])
+	require.False(t, res[firstID+1])
+	require.True(t, res[firstID+maxTrackedIndexes])
+}
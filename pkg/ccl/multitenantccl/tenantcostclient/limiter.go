@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
@@ -43,6 +44,9 @@ type limiter struct {
 	// notifyThreshold.
 	notifyCh chan struct{}
 
+	// waitEventEvery rate limits the structured events reporting long waits.
+	waitEventEvery log.EveryN
+
 	// Access to these fields is protected by the quota pool lock. Only access
 	// them in the scope of the abstract pool's Update method or within the
 	// wait request's Acquire method.
@@ -63,7 +67,7 @@ type limiter struct {
 }
 
 func (l *limiter) Init(metrics *metrics, timeSource timeutil.TimeSource, notifyCh chan struct{}) {
-	*l = limiter{metrics: metrics, notifyCh: notifyCh}
+	*l = limiter{metrics: metrics, notifyCh: notifyCh, waitEventEvery: log.Every(time.Second)}
 
 	onWaitStartFn := func(ctx context.Context, poolName string, r quotapool.Request) {
 		// Add to the waiting RU total if this request has not already been added
@@ -84,9 +88,19 @@ func (l *limiter) Init(metrics *metrics, timeSource timeutil.TimeSource, notifyC
 		})
 		l.metrics.CurrentBlocked.Dec(1)
 
-		// Log a trace event for requests that waited for a long time.
-		if waitDuration := timeSource.Since(start); waitDuration > time.Second {
+		waitDuration := timeSource.Since(start)
+		l.metrics.WaitDuration.RecordValue(waitDuration.Nanoseconds())
+
+		// Log a trace event for requests that waited for a long time, and
+		// report them on the QUOTA channel (rate limited).
+		if waitDuration > time.Second {
 			log.VEventf(ctx, 1, "request waited for RUs for %s", waitDuration.String())
+			if l.waitEventEvery.ShouldLog() {
+				log.StructuredEvent(ctx, &eventpb.TenantRequestUnitsWait{
+					RequestedRU: float64(r.(*waitRequest).needed),
+					WaitNanos:   waitDuration.Nanoseconds(),
+				})
+			}
 		}
 	}
 
@@ -9,7 +9,9 @@
 package tenantcostclient
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 )
 
@@ -20,6 +22,24 @@ var (
 		Measurement: "Requests",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaThrottled = metric.Metadata{
+		Name:        "tenant.cost_client.throttled",
+		Help:        "Whether the tenant is currently throttled (1) or not (0) because it has exhausted its request units; KV requests are deprioritized by KV admission control while throttled",
+		Measurement: "Throttled",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaIdleSuppressedIntervals = metric.Metadata{
+		Name:        "tenant.cost_client.idle_suppressed_intervals",
+		Help:        "Number of consumption reporting intervals in which no report was sent to the host cluster because the SQL pod was idle",
+		Measurement: "Intervals",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaWaitDuration = metric.Metadata{
+		Name:        "tenant.cost_client.wait_duration",
+		Help:        "Latency of requests blocked by the rate limiter",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 
 	// SQL usage related metrics.
 	metaTotalRU = metric.Metadata{
@@ -70,12 +90,6 @@ var (
 		Measurement: "Bytes",
 		Unit:        metric.Unit_COUNT,
 	}
-	metaTotalSQLPodsCPUSeconds = metric.Metadata{
-		Name:        "tenant.sql_usage.sql_pods_cpu_seconds",
-		Help:        "Total amount of CPU used by SQL pods",
-		Measurement: "CPU Seconds",
-		Unit:        metric.Unit_SECONDS,
-	}
 	metaTotalPGWireEgressBytes = metric.Metadata{
 		Name:        "tenant.sql_usage.pgwire_egress_bytes",
 		Help:        "Total number of bytes transferred from a SQL pod to the client",
@@ -100,11 +114,38 @@ var (
 		Measurement: "Request Units",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaTotalEstimatedCPUSeconds = metric.Metadata{
+		Name:        "tenant.sql_usage.estimated_cpu_seconds",
+		Help:        "Total estimated vCPU-seconds consumed by SQL pods and KV operations, when billed by estimated CPU",
+		Measurement: "CPU Seconds",
+		Unit:        metric.Unit_SECONDS,
+	}
+	metaTotalEstimatedKVCPUSeconds = metric.Metadata{
+		Name:        "tenant.sql_usage.estimated_kv_cpu_seconds",
+		Help:        "Total estimated vCPU-seconds consumed by KV operations, when billed by estimated CPU",
+		Measurement: "CPU Seconds",
+		Unit:        metric.Unit_SECONDS,
+	}
+	metaTotalBackupRU = metric.Metadata{
+		Name:        "tenant.sql_usage.backup_ru",
+		Help:        "Total number of RUs consumed by backups paced by the dedicated backup token bucket",
+		Measurement: "Request Units",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaTotalSystemOverheadRU = metric.Metadata{
+		Name:        "tenant.sql_usage.system_overhead_ru",
+		Help:        "Total number of RUs consumed by mandatory system operations, such as SQL liveness heartbeats, which are not charged to the tenant",
+		Measurement: "Request Units",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // metrics manage the metrics used by the tenant cost client.
 type metrics struct {
 	CurrentBlocked              *metric.Gauge
+	Throttled                   *metric.Gauge
+	IdleSuppressedIntervals     *metric.Counter
+	WaitDuration                metric.IHistogram
 	TotalRU                     *metric.CounterFloat64
 	TotalKVRU                   *metric.CounterFloat64
 	TotalReadBatches            *metric.Counter
@@ -118,6 +159,10 @@ type metrics struct {
 	TotalExternalIOEgressBytes  *metric.Counter
 	TotalExternalIOIngressBytes *metric.Counter
 	TotalCrossRegionNetworkRU   *metric.CounterFloat64
+	TotalEstimatedCPUSeconds    *metric.CounterFloat64
+	TotalEstimatedKVCPUSeconds  *metric.CounterFloat64
+	TotalBackupRU               *metric.CounterFloat64
+	TotalSystemOverheadRU       *metric.CounterFloat64
 }
 
 var _ metric.Struct = (*metrics)(nil)
@@ -128,6 +173,14 @@ func (m *metrics) MetricStruct() {}
 // Init initializes the tenant cost client metrics.
 func (m *metrics) Init() {
 	m.CurrentBlocked = metric.NewGauge(metaCurrentBlocked)
+	m.Throttled = metric.NewGauge(metaThrottled)
+	m.IdleSuppressedIntervals = metric.NewCounter(metaIdleSuppressedIntervals)
+	m.WaitDuration = metric.NewHistogram(metric.HistogramOptions{
+		Mode:         metric.HistogramModePreferHdrLatency,
+		Metadata:     metaWaitDuration,
+		Duration:     base.DefaultHistogramWindowInterval(),
+		BucketConfig: metric.IOLatencyBuckets,
+	})
 	m.TotalRU = metric.NewCounterFloat64(metaTotalRU)
 	m.TotalKVRU = metric.NewCounterFloat64(metaTotalKVRU)
 	m.TotalReadBatches = metric.NewCounter(metaTotalReadBatches)
@@ -136,11 +189,15 @@ func (m *metrics) Init() {
 	m.TotalWriteBatches = metric.NewCounter(metaTotalWriteBatches)
 	m.TotalWriteRequests = metric.NewCounter(metaTotalWriteRequests)
 	m.TotalWriteBytes = metric.NewCounter(metaTotalWriteBytes)
-	m.TotalSQLPodsCPUSeconds = metric.NewCounterFloat64(metaTotalSQLPodsCPUSeconds)
+	m.TotalSQLPodsCPUSeconds = metric.NewCounterFloat64(multitenant.MetaTotalSQLPodsCPUSeconds)
 	m.TotalPGWireEgressBytes = metric.NewCounter(metaTotalPGWireEgressBytes)
 	m.TotalExternalIOEgressBytes = metric.NewCounter(metaTotalExternalIOEgressBytes)
 	m.TotalExternalIOIngressBytes = metric.NewCounter(metaTotalExternalIOIngressBytes)
 	m.TotalCrossRegionNetworkRU = metric.NewCounterFloat64(metaTotalCrossRegionNetworkRU)
+	m.TotalEstimatedCPUSeconds = metric.NewCounterFloat64(metaTotalEstimatedCPUSeconds)
+	m.TotalEstimatedKVCPUSeconds = metric.NewCounterFloat64(metaTotalEstimatedKVCPUSeconds)
+	m.TotalBackupRU = metric.NewCounterFloat64(metaTotalBackupRU)
+	m.TotalSystemOverheadRU = metric.NewCounterFloat64(metaTotalSystemOverheadRU)
 }
 
 // incrementConsumption updates consumption-related metrics with the delta
@@ -159,4 +216,8 @@ func (m *metrics) incrementConsumption(delta kvpb.TenantConsumption) {
 	m.TotalExternalIOEgressBytes.Inc(int64(delta.ExternalIOEgressBytes))
 	m.TotalExternalIOIngressBytes.Inc(int64(delta.ExternalIOIngressBytes))
 	m.TotalCrossRegionNetworkRU.Inc(delta.CrossRegionNetworkRU)
+	m.TotalEstimatedCPUSeconds.Inc(delta.EstimatedCPUSeconds)
+	m.TotalEstimatedKVCPUSeconds.Inc(delta.EstimatedKVCPUSeconds)
+	m.TotalBackupRU.Inc(delta.BackupRU)
+	m.TotalSystemOverheadRU.Inc(delta.SystemOverheadRU)
 }
@@ -0,0 +1,153 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package tenantcostclient
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// RangeCostSampleRate is the fraction of KV batches whose cost is attributed to
+// the range they were sent to. It is exported for testing purposes.
+var RangeCostSampleRate = settings.RegisterFloatSetting(
+	settings.ApplicationLevel,
+	"tenant_cost_control.range_costs.sample_rate",
+	"fraction of KV batches whose request units are attributed to the range "+
+		"they were sent to, for SHOW RANGES WITH COSTS; 0 disables the tracking "+
+		"of range costs",
+	0.01,
+	settings.FloatInRange(0, 1),
+)
+
+// rangeCostWindow is the length of the windows over which the cost of ranges
+// is recorded. The reported costs cover the current window and the previous
+// one, so that they always reflect between one and two windows of activity.
+const rangeCostWindow = 10 * time.Minute
+
+// maxTrackedRanges is the maximum number of ranges whose costs are tracked in
+// a window. Requests to other ranges are ignored once the limit is reached.
+const maxTrackedRanges = 10000
+
+// rangeCostTracker attributes the cost of a sample of the KV batches of the SQL
+// instance to the ranges they were sent to. Like indexReadTracker, the cost of
+// each range is extrapolated from the sample by weighting each sampled batch by
+// the inverse of the sample rate. Unlike it, only the recent costs are kept.
+type rangeCostTracker struct {
+	sv         *settings.Values
+	timeSource timeutil.TimeSource
+
+	mu struct {
+		syncutil.Mutex
+		// cur and prev hold the costs recorded in the current and previous
+		// windows.
+		cur, prev rangeCostWindowData
+	}
+}
+
+type rangeCostWindowData struct {
+	start  time.Time
+	ranges map[roachpb.RangeID]*multitenant.RangeCost
+}
+
+func (t *rangeCostTracker) init(sv *settings.Values, timeSource timeutil.TimeSource) {
+	t.sv = sv
+	t.timeSource = timeSource
+	t.mu.cur = rangeCostWindowData{
+		start:  timeSource.Now(),
+		ranges: make(map[roachpb.RangeID]*multitenant.RangeCost),
+	}
+}
+
+// maybeRecord attributes the given batch, which cost the given number of RUs
+// and estimated KV CPU seconds, to the range it was sent to, if the batch is
+// sampled.
+func (t *rangeCostTracker) maybeRecord(
+	req tenantcostmodel.RequestInfo,
+	resp tenantcostmodel.ResponseInfo,
+	ru tenantcostmodel.RU,
+	kvCPUSeconds float64,
+) {
+	rate := RangeCostSampleRate.Get(t.sv)
+	if rate == 0 || req.RangeID() == 0 || (rate < 1 && rand.Float64() >= rate) {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.maybeRotateLocked(t.timeSource.Now())
+	cost, ok := t.mu.cur.ranges[req.RangeID()]
+	if !ok {
+		if len(t.mu.cur.ranges) >= maxTrackedRanges {
+			return
+		}
+		cost = &multitenant.RangeCost{RangeID: req.RangeID()}
+		t.mu.cur.ranges[req.RangeID()] = cost
+	}
+	cost.RU += float64(ru) / rate
+	cost.KVCPUSeconds += kvCPUSeconds / rate
+	cost.ReadBytes += int64(float64(resp.ReadBytes()) / rate)
+	cost.WriteBytes += int64(float64(req.WriteBytes()) / rate)
+	cost.SampledBatches++
+}
+
+// maybeRotateLocked starts a new window if the current one has ended.
+func (t *rangeCostTracker) maybeRotateLocked(now time.Time) {
+	elapsed := now.Sub(t.mu.cur.start)
+	if elapsed < rangeCostWindow {
+		return
+	}
+	if elapsed < 2*rangeCostWindow {
+		t.mu.prev = t.mu.cur
+	} else {
+		// There was no activity in the last window.
+		t.mu.prev = rangeCostWindowData{}
+	}
+	t.mu.cur = rangeCostWindowData{
+		start:  now,
+		ranges: make(map[roachpb.RangeID]*multitenant.RangeCost),
+	}
+}
+
+// costs returns the costs of the ranges recorded in the current and previous
+// windows.
+func (t *rangeCostTracker) costs() []multitenant.RangeCost {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.maybeRotateLocked(t.timeSource.Now())
+
+	since := t.mu.cur.start
+	if t.mu.prev.ranges != nil {
+		since = t.mu.prev.start
+	}
+	res := make([]multitenant.RangeCost, 0, len(t.mu.cur.ranges)+len(t.mu.prev.ranges))
+	index := make(map[roachpb.RangeID]int, cap(res))
+	for _, w := range []rangeCostWindowData{t.mu.prev, t.mu.cur} {
+		for rangeID, cost := range w.ranges {
+			i, ok := index[rangeID]
+			if !ok {
+				i = len(res)
+				index[rangeID] = i
+				res = append(res, multitenant.RangeCost{RangeID: rangeID, Since: since})
+			}
+			res[i].RU += cost.RU
+			res[i].KVCPUSeconds += cost.KVCPUSeconds
+			res[i].ReadBytes += cost.ReadBytes
+			res[i].WriteBytes += cost.WriteBytes
+			res[i].SampledBatches += cost.SampledBatches
+		}
+	}
+	return res
+}
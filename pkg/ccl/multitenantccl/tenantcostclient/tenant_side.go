@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvtenant"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
@@ -23,9 +24,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/ring"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -87,6 +92,68 @@ var ExternalIORUAccountingMode = *settings.RegisterStringSetting(
 	}),
 )
 
+// BackupRURate is the fill rate of the token bucket that paces backups. It is
+// exported for testing purposes.
+var BackupRURate = settings.RegisterFloatSetting(
+	settings.SystemVisible,
+	"tenant_cost_control.backup_ru_rate",
+	"if positive, backups are paced by a dedicated token bucket that is refilled "+
+		"at this many request units per second, instead of consuming from the "+
+		"tenant's token bucket; the consumption of such backups is reported "+
+		"separately as backup request units",
+	0,
+	settings.NonNegativeFloat,
+)
+
+// SystemOverheadExemption controls whether the RUs consumed by operations
+// exempt from cost control are reported as system overhead instead of being
+// charged to the tenant. It is exported for testing purposes.
+var SystemOverheadExemption = settings.RegisterBoolSetting(
+	settings.SystemVisible,
+	"tenant_cost_control.system_overhead_exemption.enabled",
+	"if enabled, the request units consumed by mandatory system operations of "+
+		"the tenant, such as SQL liveness heartbeats, are reported separately as "+
+		"system overhead request units instead of being charged to the tenant; "+
+		"such operations are never throttled either way",
+	true,
+)
+
+// BackgroundWorkMinAvailableRU is the number of available RUs in the local
+// token bucket below which background work is delayed. It is exported for
+// testing purposes.
+var BackgroundWorkMinAvailableRU = settings.RegisterFloatSetting(
+	settings.SystemVisible,
+	"tenant_cost_control.background_work.min_available_ru",
+	"background work of the tenant, such as row-level TTL deletions, waits while "+
+		"fewer than this many request units are available in the local token bucket, "+
+		"so that it slows down before foreground traffic is throttled; 0 disables "+
+		"such waits",
+	bufferRUs/5,
+	settings.NonNegativeFloat,
+)
+
+// ThrottledBatchBytes is the preferred size of the KV batches sent by the SQL
+// layer while the tenant is throttled or its token bucket is in debt. It is
+// exported for testing purposes.
+var ThrottledBatchBytes = settings.RegisterByteSizeSetting(
+	settings.SystemVisible,
+	"tenant_cost_control.throttled_batch_bytes",
+	"preferred size of the KV batches sent by SQL statements while the tenant is "+
+		"throttled or has run out of request units, so that the per-batch cost of "+
+		"KV requests is amortized over more data; 0 disables such batching hints",
+	16<<20,
+	// Larger batches risk exceeding the maximum size of a raft command
+	// (kv.raft.command.max_size, 64 MiB by default).
+	settings.NonNegativeIntWithMaximum(maxThrottledBatchBytes),
+)
+
+// maxThrottledBatchBytes is the maximum value of ThrottledBatchBytes.
+const maxThrottledBatchBytes = 64 << 20
+
+// backgroundWorkPollInterval is the interval at which OnBackgroundWorkWait
+// checks whether enough RUs are available again.
+const backgroundWorkPollInterval = 100 * time.Millisecond
+
 type externalIORUAccountingMode int64
 
 const (
@@ -119,6 +186,10 @@ func externalIORUAccountingModeFromString(s string) externalIORUAccountingMode {
 // evaluate whether we need to send a new token request.
 const defaultTickInterval = time.Second
 
+// costHistoryInterval is the period at which samples of the consumption and
+// throttling of the tenant are recorded (see GetCostHistory).
+const costHistoryInterval = 10 * time.Second
+
 // movingAvgRUPerSecFactor is the weight applied to a new "sample" of RU usage
 // (with one sample per tickInterval).
 //
@@ -146,6 +217,25 @@ const consumptionReportingThreshold = 100
 // The extended reporting period is this factor times the normal period.
 const extendedReportingPeriodFactor = 4
 
+// If the SQL pod has been idle since the last token bucket request, there is no
+// consumption to report and the extended reporting periods are skipped. A
+// report is still sent once this factor times the normal period has elapsed,
+// so that the host cluster keeps hearing from the instance. Note that the host
+// only cleans up instances that are no longer live, so idle instances are not
+// cleaned up in between reports.
+const idleReportingPeriodFactor = 30
+
+// supportedCapabilities are the optional features of the token bucket protocol
+// that the SQL pod supports. The host enables the subset it also supports.
+const supportedCapabilities = kvpb.TokenBucketCapabilityUnacknowledgedConsumption |
+	kvpb.TokenBucketCapabilityReportInterval
+
+// maxUnacknowledgedConsumption is the maximum number of failed token bucket
+// requests whose consumption is resent to the host. Once the limit is reached,
+// new consumption is held back until a request succeeds (see
+// sendTokenBucketRequest).
+const maxUnacknowledgedConsumption = 16
+
 // We try to maintain this many RUs in our local bucket, regardless of estimated
 // usage. This is intended to support usage spikes without blocking.
 const bufferRUs = 5000
@@ -172,6 +262,8 @@ func newTenantSideCostController(
 
 	// Initialize metrics.
 	c.metrics.Init()
+	c.indexReads.init(&st.SV, keys.MakeSQLCodec(tenantID))
+	c.rangeCosts.init(&st.SV, timeSource)
 
 	// Start with filled burst buffer.
 	c.limiter.Init(&c.metrics, timeSource, c.lowRUNotifyChan)
@@ -187,6 +279,13 @@ func newTenantSideCostController(
 	initialConfig := tenantcostmodel.ConfigFromSettings(&st.SV)
 	c.costCfg.CompareAndSwap(nil, &initialConfig)
 
+	// The backup limiter is only used while BackupRURate is positive.
+	c.backupLimiter.Init(&c.metrics, timeSource, nil /* notifyCh */)
+	c.reconfigureBackupLimiter()
+	BackupRURate.SetOnChange(&st.SV, func(context.Context) {
+		c.reconfigureBackupLimiter()
+	})
+
 	c.modeMu.externalIORUAccountingMode = externalIORUAccountingModeFromString(ExternalIORUAccountingMode.Get(&st.SV))
 	ExternalIORUAccountingMode.SetOnChange(&st.SV, func(context.Context) {
 		c.modeMu.Lock()
@@ -234,6 +333,22 @@ func TestingAvailableRU(ctrl multitenant.TenantSideCostController) tenantcostmod
 	return c.limiter.AvailableRU(c.timeSource.Now())
 }
 
+// TestingConsumption returns the consumption accounted for by the controller
+// so far, for testing purposes.
+func TestingConsumption(ctrl multitenant.TenantSideCostController) kvpb.TenantConsumption {
+	c := ctrl.(*tenantSideCostController)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.consumption
+}
+
+// TestingBlockedRequests returns the number of requests currently waiting for
+// RUs in the tenant's token bucket, for testing purposes.
+func TestingBlockedRequests(ctrl multitenant.TenantSideCostController) int64 {
+	c := ctrl.(*tenantSideCostController)
+	return c.metrics.CurrentBlocked.Value()
+}
+
 // TestingSetRate sets the fill rate of the tenant's token bucket, for testing
 // purposes.
 func TestingSetRate(ctrl multitenant.TenantSideCostController, rate tenantcostmodel.RU) {
@@ -254,12 +369,37 @@ type tenantSideCostController struct {
 	tenantID             roachpb.TenantID
 	provider             kvtenant.TokenBucketProvider
 	limiter              limiter
+	backupLimiter        limiter
 	stopper              *stop.Stopper
 	instanceID           base.SQLInstanceID
 	sessionID            sqlliveness.SessionID
 	externalUsageFn      multitenant.ExternalUsageFn
 	nextLiveInstanceIDFn multitenant.NextLiveInstanceIDFn
 
+	// throttled is set if the local token bucket was in debt as of the last
+	// tick of the main loop. See IsThrottled.
+	throttled atomic.Bool
+
+	// liveBytesLimitExceeded is set if the last token bucket response indicated
+	// that the tenant exceeded its max_live_bytes capability. See
+	// CheckLiveBytesLimit.
+	liveBytesLimitExceeded atomic.Bool
+
+	// costModel is the tenantcostmodel.ModelVersion under which the tenant is
+	// billed, as indicated by the last token bucket response. Under the
+	// EstimatedCPUModel, consumption is accounted for in estimated vCPU-seconds
+	// instead of RUs. In both cases, the local token bucket is charged in RUs,
+	// with CPU usage converted at the SQL CPU-second cost.
+	costModel atomic.Int64
+
+	// indexReads attributes the cost of a sample of KV reads to the indexes
+	// they read from. See GetIndexReadUsage.
+	indexReads indexReadTracker
+
+	// rangeCosts attributes the cost of a sample of KV batches to the ranges
+	// they were sent to. See GetRangeCosts.
+	rangeCosts rangeCostTracker
+
 	modeMu struct {
 		syncutil.RWMutex
 
@@ -278,6 +418,16 @@ type tenantSideCostController struct {
 		// per second; used to estimate the CPU usage of a query. It is only written
 		// in the main loop, but can be read by multiple goroutines so is protected.
 		avgCPUPerSec float64
+
+		// hostConsumptionHistory contains snapshots of the total consumption of
+		// the tenant, as recorded by the host cluster in the last
+		// multitenant.HostConsumptionHistorySize token bucket responses, oldest
+		// first. See GetHostConsumptionHistory.
+		hostConsumptionHistory ring.Buffer[multitenant.HostConsumptionSnapshot]
+
+		// history contains the samples recorded over the last
+		// multitenant.CostHistoryRetention, oldest first. See GetCostHistory.
+		history []multitenant.CostSample
 	}
 
 	// lowRUNotifyChan is used when the number of available RUs is running low and
@@ -298,9 +448,13 @@ type tenantSideCostController struct {
 		externalUsage multitenant.ExternalUsage
 		// consumption stores the last value of mu.consumption.
 		consumption kvpb.TenantConsumption
-		// targetPeriod stores the value of the TargetPeriodSetting setting at the
-		// last update.
+		// targetPeriod is the period at which consumption is reported to the
+		// host. It is pushed by the host if it supports it, and is otherwise the
+		// configured period.
 		targetPeriod time.Duration
+		// configuredTargetPeriod stores the value of the TargetPeriodSetting
+		// setting at the last update.
+		configuredTargetPeriod time.Duration
 
 		// requestSeqNum is an increasing sequence number that is included in token
 		// bucket requests to prevent duplicate consumption reporting.
@@ -309,6 +463,12 @@ type tenantSideCostController struct {
 		// nil if there is no call in progress. It gets set to nil when we process
 		// the response (in the main loop), even in error cases.
 		requestInProgress *kvpb.TokenBucketRequest
+		// unacknowledgedConsumption is the consumption reported by the token
+		// bucket requests that failed since the last successful one, in order of
+		// their sequence numbers. It is resent with each request, and the host
+		// accounts for the requests it has not seen. Hosts that do not support
+		// TokenBucketCapabilityUnacknowledgedConsumption ignore it.
+		unacknowledgedConsumption []kvpb.TenantConsumptionDelta
 		// shouldSendRequest is set if the last token bucket request encountered an
 		// error. This triggers a retry attempt on the next tick.
 		//
@@ -319,6 +479,10 @@ type tenantSideCostController struct {
 		// lastRequestTime is the time that the last token bucket request was
 		// sent to the server.
 		lastRequestTime time.Time
+		// idleSuppressedIntervals is the number of extended reporting periods
+		// that elapsed since the last token bucket request without a report
+		// being sent, because the SQL pod was idle.
+		idleSuppressedIntervals int64
 		// lastReportedConsumption is the set of tenant resource consumption
 		// metrics last sent to the token bucket server.
 		lastReportedConsumption kvpb.TenantConsumption
@@ -352,6 +516,11 @@ type tenantSideCostController struct {
 		// consumption per second; used to estimate the RU requirements for the next
 		// request.
 		avgRUPerSec float64
+
+		// lastSampleTime is the time at which the last cost history sample was
+		// recorded, and lastSampleConsumption the consumption at that time.
+		lastSampleTime        time.Time
+		lastSampleConsumption kvpb.TenantConsumption
 	}
 }
 
@@ -384,12 +553,14 @@ func (c *tenantSideCostController) Start(
 }
 
 func (c *tenantSideCostController) initRunState(ctx context.Context) {
-	c.run.targetPeriod = TargetPeriodSetting.Get(&c.settings.SV)
+	c.run.configuredTargetPeriod = TargetPeriodSetting.Get(&c.settings.SV)
+	c.run.targetPeriod = c.run.configuredTargetPeriod
 
 	now := c.timeSource.Now()
 	c.run.lastTick = now
 	c.run.externalUsage = c.externalUsageFn(ctx)
 	c.run.lastRequestTime = now
+	c.run.lastSampleTime = now
 	c.run.avgRUPerSec = InitialRequestSetting.Get(&c.settings.SV) / c.run.targetPeriod.Seconds()
 	c.run.requestSeqNum = 1
 }
@@ -433,12 +604,16 @@ func (c *tenantSideCostController) onTick(ctx context.Context, newTime time.Time
 	}
 
 	costCfg := c.costCfg.Load()
+	estimatedCPU := c.useEstimatedCPUModel()
 	ru := costCfg.PodCPUCost(deltaCPU)
 
 	var deltaPGWireEgressBytes uint64
 	if newExternalUsage.PGWireEgressBytes > c.run.externalUsage.PGWireEgressBytes {
 		deltaPGWireEgressBytes = newExternalUsage.PGWireEgressBytes - c.run.externalUsage.PGWireEgressBytes
-		ru += costCfg.PGWireEgressCost(int64(deltaPGWireEgressBytes))
+		// Egress is not billed under the estimated CPU model.
+		if !estimatedCPU {
+			ru += costCfg.PGWireEgressCost(int64(deltaPGWireEgressBytes))
+		}
 	}
 
 	// KV RUs are not included here, these metrics correspond only to the SQL pod.
@@ -448,12 +623,16 @@ func (c *tenantSideCostController) onTick(ctx context.Context, newTime time.Time
 		defer c.mu.Unlock()
 		c.mu.consumption.SQLPodsCPUSeconds += deltaCPU
 		c.mu.consumption.PGWireEgressBytes += deltaPGWireEgressBytes
-		c.mu.consumption.RU += float64(ru)
+		if estimatedCPU {
+			c.mu.consumption.EstimatedCPUSeconds += deltaCPU
+		} else {
+			c.mu.consumption.RU += float64(ru)
+		}
 		newConsumption = c.mu.consumption
 	}()
 
 	// Update the average RUs consumed per second, based on the latest stats.
-	delta := newConsumption.RU - c.run.consumption.RU
+	delta := consumedRU(costCfg, &newConsumption) - consumedRU(costCfg, &c.run.consumption)
 	avg := delta * float64(time.Second) / float64(deltaTime)
 	c.run.avgRUPerSec = movingAvgRUPerSecFactor*avg + (1-movingAvgRUPerSecFactor)*c.run.avgRUPerSec
 
@@ -464,6 +643,23 @@ func (c *tenantSideCostController) onTick(ctx context.Context, newTime time.Time
 	// Remove the tick RU from the bucket.
 	c.limiter.RemoveRU(newTime, ru)
 
+	// Determine whether the tenant is throttled, which is the case if it has
+	// fallen into debt.
+	available := c.limiter.AvailableRU(newTime)
+	throttled := available < 0
+	if c.throttled.Swap(throttled) != throttled {
+		log.StructuredEvent(ctx, &eventpb.TenantThrottlingChange{
+			Throttled:   throttled,
+			AvailableRU: float64(available),
+			FillRate:    c.run.lastRate,
+		})
+	}
+	if throttled {
+		c.metrics.Throttled.Update(1)
+	} else {
+		c.metrics.Throttled.Update(0)
+	}
+
 	// Switch to the fallback rate if needed.
 	if !c.run.fallbackRateStart.IsZero() && !newTime.Before(c.run.fallbackRateStart) &&
 		c.run.fallbackRate != 0 {
@@ -482,6 +678,10 @@ func (c *tenantSideCostController) onTick(ctx context.Context, newTime time.Time
 	c.run.lastExportedConsumption = c.run.consumption
 	c.metrics.incrementConsumption(deltaConsumption)
 
+	if newTime.Sub(c.run.lastSampleTime) >= costHistoryInterval {
+		c.recordCostSample(newTime)
+	}
+
 	// Should a token bucket request be sent? It might be for a retry or for
 	// periodic consumption reporting.
 	if c.run.shouldSendRequest || c.shouldReportConsumption() {
@@ -490,22 +690,85 @@ func (c *tenantSideCostController) onTick(ctx context.Context, newTime time.Time
 }
 
 // shouldReportConsumption decides if it's time to send a token bucket request
-// to report consumption.
+// to report consumption. Reports that are suppressed because the SQL pod is
+// idle are counted in the IdleSuppressedIntervals metric.
 func (c *tenantSideCostController) shouldReportConsumption() bool {
 	timeSinceLastRequest := c.run.lastTick.Sub(c.run.lastRequestTime)
 	if timeSinceLastRequest >= c.run.targetPeriod {
-		consumptionToReport := c.run.consumption.RU - c.run.lastReportedConsumption.RU
+		costCfg := c.costCfg.Load()
+		consumptionToReport := consumedRU(costCfg, &c.run.consumption) -
+			consumedRU(costCfg, &c.run.lastReportedConsumption)
 		if consumptionToReport >= consumptionReportingThreshold {
 			return true
 		}
-		if timeSinceLastRequest >= extendedReportingPeriodFactor*c.run.targetPeriod {
-			return true
+		extendedPeriod := extendedReportingPeriodFactor * c.run.targetPeriod
+		if timeSinceLastRequest >= extendedPeriod {
+			if !c.isIdle() || timeSinceLastRequest >= idleReportingPeriodFactor*c.run.targetPeriod {
+				return true
+			}
+			if intervals := int64(timeSinceLastRequest / extendedPeriod); intervals > c.run.idleSuppressedIntervals {
+				c.metrics.IdleSuppressedIntervals.Inc(intervals - c.run.idleSuppressedIntervals)
+				c.run.idleSuppressedIntervals = intervals
+			}
 		}
 	}
 
 	return false
 }
 
+// isIdle returns true if the SQL pod has been fully idle since the last token
+// bucket request, i.e. it has no consumption at all to report (background CPU
+// usage under the allowance is not consumption), and no requests are waiting
+// for RUs. The pod is never considered idle before its first request, which
+// makes the host aware of the instance.
+func (c *tenantSideCostController) isIdle() bool {
+	return c.run.requestSeqNum > 1 &&
+		c.run.consumption == c.run.lastReportedConsumption &&
+		c.metrics.CurrentBlocked.Value() == 0 &&
+		!c.throttled.Load()
+}
+
+// recordCostSample appends a sample of the consumption since the previous
+// sample and of the current throttling to the cost history, and discards the
+// samples older than multitenant.CostHistoryRetention.
+func (c *tenantSideCostController) recordCostSample(now time.Time) {
+	costCfg := c.costCfg.Load()
+	waitDurations := c.metrics.WaitDuration.WindowedSnapshot()
+	sample := multitenant.CostSample{
+		Time: now,
+		RU: consumedRU(costCfg, &c.run.consumption) -
+			consumedRU(costCfg, &c.run.lastSampleConsumption),
+		EstimatedCPUSeconds: c.run.consumption.EstimatedCPUSeconds -
+			c.run.lastSampleConsumption.EstimatedCPUSeconds,
+		BlockedRequests: c.metrics.CurrentBlocked.Value(),
+		WaitDurationP50: time.Duration(waitDurations.ValueAtQuantile(50)),
+		WaitDurationP90: time.Duration(waitDurations.ValueAtQuantile(90)),
+		WaitDurationP99: time.Duration(waitDurations.ValueAtQuantile(99)),
+	}
+	c.run.lastSampleTime = now
+	c.run.lastSampleConsumption = c.run.consumption
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var i int
+	for i < len(c.mu.history) && now.Sub(c.mu.history[i].Time) > multitenant.CostHistoryRetention {
+		i++
+	}
+	c.mu.history = append(c.mu.history[i:], sample)
+}
+
+// consumedRU returns the RUs charged to the local token bucket for the given
+// cumulative consumption, under either cost model.
+func consumedRU(costCfg *tenantcostmodel.Config, consumption *kvpb.TenantConsumption) float64 {
+	return consumption.RU + float64(costCfg.PodCPUCost(consumption.EstimatedCPUSeconds))
+}
+
+// useEstimatedCPUModel returns true if the tenant is billed under the
+// estimated CPU cost model.
+func (c *tenantSideCostController) useEstimatedCPUModel() bool {
+	return tenantcostmodel.ModelVersion(c.costModel.Load()) == tenantcostmodel.EstimatedCPUModel
+}
+
 func (c *tenantSideCostController) sendTokenBucketRequest(ctx context.Context) {
 	if c.run.requestInProgress != nil {
 		// Don't allow multiple concurrent token bucket requests. But do send
@@ -515,8 +778,16 @@ func (c *tenantSideCostController) sendTokenBucketRequest(ctx context.Context) {
 	}
 	c.run.shouldSendRequest = false
 
-	deltaConsumption := c.run.consumption
-	deltaConsumption.Sub(&c.run.lastReportedConsumption)
+	// Hold back new consumption if we can't keep track of another failed
+	// request. It is reported by the first request after one succeeds. Merging
+	// the deltas of failed requests instead would lose or double count
+	// consumption, depending on which of them the host processed.
+	var deltaConsumption kvpb.TenantConsumption
+	holdBack := len(c.run.unacknowledgedConsumption) >= maxUnacknowledgedConsumption
+	if !holdBack {
+		deltaConsumption = c.run.consumption
+		deltaConsumption.Sub(&c.run.lastReportedConsumption)
+	}
 	var requested tenantcostmodel.RU
 	now := c.timeSource.Now()
 
@@ -552,12 +823,18 @@ func (c *tenantSideCostController) sendTokenBucketRequest(ctx context.Context) {
 		ConsumptionSinceLastRequest: deltaConsumption,
 		RequestedRU:                 float64(requested),
 		TargetRequestPeriod:         c.run.targetPeriod,
+		ProtocolVersion:             kvpb.TokenBucketProtocolLatest,
+		Capabilities:                supportedCapabilities,
+		UnacknowledgedConsumption:   c.run.unacknowledgedConsumption,
 	}
 	c.run.requestInProgress = req
 	c.run.requestSeqNum++
 
 	c.run.lastRequestTime = now
-	c.run.lastReportedConsumption = c.run.consumption
+	c.run.idleSuppressedIntervals = 0
+	if !holdBack {
+		c.run.lastReportedConsumption = c.run.consumption
+	}
 
 	ctx, _ = c.stopper.WithCancelOnQuiesce(ctx)
 	err := c.stopper.RunAsyncTask(ctx, "token-bucket-request", func(ctx context.Context) {
@@ -586,6 +863,24 @@ func (c *tenantSideCostController) sendTokenBucketRequest(ctx context.Context) {
 	}
 }
 
+// addUnacknowledgedConsumption records the consumption reported by a token
+// bucket request that failed, so that it is resent with the next request. The
+// host might have processed the request anyway, in which case it ignores the
+// resent consumption based on its sequence number.
+func (c *tenantSideCostController) addUnacknowledgedConsumption(req *kvpb.TokenBucketRequest) {
+	if req.ConsumptionSinceLastRequest == (kvpb.TenantConsumption{}) {
+		return
+	}
+	// Don't append to the slice of the failed request, which still references
+	// it.
+	unacked := append([]kvpb.TenantConsumptionDelta(nil), c.run.unacknowledgedConsumption...)
+	unacked = append(unacked, kvpb.TenantConsumptionDelta{
+		SeqNum:      req.SeqNum,
+		Consumption: req.ConsumptionSinceLastRequest,
+	})
+	c.run.unacknowledgedConsumption = unacked
+}
+
 func (c *tenantSideCostController) handleTokenBucketResponse(
 	ctx context.Context, req *kvpb.TokenBucketRequest, resp *kvpb.TokenBucketResponse,
 ) {
@@ -596,12 +891,48 @@ func (c *tenantSideCostController) handleTokenBucketResponse(
 		)
 	}
 
+	if exceeded := resp.LiveBytesLimitExceeded; exceeded != c.liveBytesLimitExceeded.Load() {
+		if exceeded {
+			log.Warningf(ctx, "live bytes limit exceeded; rejecting writes")
+		} else {
+			log.Infof(ctx, "live bytes back under limit; allowing writes")
+		}
+		c.liveBytesLimitExceeded.Store(exceeded)
+	}
+
+	if model := resp.CostModel; model != c.costModel.Load() {
+		log.Infof(ctx, "switching to the %s cost model", tenantcostmodel.ModelVersion(model))
+		c.costModel.Store(model)
+	}
+
+	// Use the report interval pushed by the host, if any. Hosts running older
+	// versions don't set the capability, in which case the configured period is
+	// used.
+	targetPeriod := c.run.configuredTargetPeriod
+	if resp.Capabilities.Has(kvpb.TokenBucketCapabilityReportInterval) && resp.ReportInterval > 0 {
+		targetPeriod = resp.ReportInterval
+	}
+	if targetPeriod != c.run.targetPeriod {
+		log.Infof(ctx, "reporting consumption every %s", targetPeriod)
+		c.run.targetPeriod = targetPeriod
+	}
+
 	// Reset fallback rate now that we've gotten a response.
 	c.run.fallbackRate = resp.FallbackRate
 	c.run.fallbackRateStart = time.Time{}
 
-	// Process granted RUs.
 	now := c.timeSource.Now()
+	c.mu.Lock()
+	if c.mu.hostConsumptionHistory.Len() >= multitenant.HostConsumptionHistorySize {
+		c.mu.hostConsumptionHistory.RemoveFirst()
+	}
+	c.mu.hostConsumptionHistory.AddLast(multitenant.HostConsumptionSnapshot{
+		Time:        now,
+		Consumption: resp.Consumption,
+	})
+	c.mu.Unlock()
+
+	// Process granted RUs.
 	granted := tenantcostmodel.RU(resp.GrantedRU)
 
 	// Shut down any trickle previously in-progress trickle.
@@ -716,7 +1047,10 @@ func (c *tenantSideCostController) mainLoop(ctx context.Context) {
 			req := c.run.requestInProgress
 			c.run.requestInProgress = nil
 			if resp != nil {
-				// Token bucket request was successful.
+				// Token bucket request was successful. The host accounted for the
+				// consumption of any previous requests that failed, unless it
+				// predates TokenBucketCapabilityUnacknowledgedConsumption.
+				c.run.unacknowledgedConsumption = nil
 				c.handleTokenBucketResponse(ctx, req, resp)
 
 				// Immediately send another token bucket request if one was requested
@@ -733,6 +1067,7 @@ func (c *tenantSideCostController) mainLoop(ctx context.Context) {
 				// Retry the request on the next tick so there's at least some
 				// delay between retries.
 				c.run.shouldSendRequest = true
+				c.addUnacknowledgedConsumption(req)
 
 				if c.testInstr != nil {
 					c.testInstr.Event(c.timeSource.Now(), TokenBucketResponseError)
@@ -758,6 +1093,7 @@ func (c *tenantSideCostController) mainLoop(ctx context.Context) {
 
 		case <-c.stopper.ShouldQuiesce():
 			c.limiter.Close()
+			c.backupLimiter.Close()
 			// TODO(radu): send one last request to update consumption.
 			return
 		}
@@ -770,10 +1106,37 @@ func (c *tenantSideCostController) OnRequestWait(ctx context.Context) error {
 		return nil
 	}
 
+	// While the tenant is throttled, operations of resource groups that exceed
+	// their share of the tenant's resources yield to the other groups.
+	if start, ok := multitenant.ResourceGroupStartFromContext(ctx); ok && c.throttled.Load() {
+		if err := c.waitUntil(ctx, start); err != nil {
+			return err
+		}
+	}
+
 	// Note that the tenantSideController might not be started yet; that is ok
 	// because we initialize the limiter with some initial RUs and a reasonable
 	// initial rate.
-	return c.limiter.Wait(ctx, 0)
+	lim, _ := c.limiterFor(ctx)
+	return lim.Wait(ctx, 0)
+}
+
+// waitUntil blocks until the given time, or until the context is canceled.
+func (c *tenantSideCostController) waitUntil(ctx context.Context, t time.Time) error {
+	wait := t.Sub(c.timeSource.Now())
+	if wait <= 0 {
+		return nil
+	}
+	timer := c.timeSource.NewTimer()
+	defer timer.Stop()
+	timer.Reset(wait)
+	select {
+	case <-timer.Ch():
+		timer.MarkRead()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // OnResponseWait is part of the multitenant.TenantSideBatchInterceptor
@@ -781,9 +1144,7 @@ func (c *tenantSideCostController) OnRequestWait(ctx context.Context) error {
 func (c *tenantSideCostController) OnResponseWait(
 	ctx context.Context, req tenantcostmodel.RequestInfo, resp tenantcostmodel.ResponseInfo,
 ) error {
-	if multitenant.HasTenantCostControlExemption(ctx) {
-		return nil
-	}
+	exempt, overhead := c.systemOverhead(ctx)
 
 	// Account for the cost of write requests and read responses.
 	costCfg := c.costCfg.Load()
@@ -791,45 +1152,126 @@ func (c *tenantSideCostController) OnResponseWait(
 	readKVRU, readNetworkRU := costCfg.ResponseCost(resp)
 	totalRU := writeKVRU + readKVRU + writeNetworkRU + readNetworkRU
 
+	// Under the estimated CPU model, only the estimated CPU usage of the KV
+	// operations is charged; network traffic is not.
+	estimatedCPU := c.useEstimatedCPUModel()
+	var kvCPUSeconds float64
+	if estimatedCPU {
+		kvCPUSeconds = costCfg.EstimatedKVCPUSeconds(writeKVRU + readKVRU)
+		totalRU = costCfg.PodCPUCost(kvCPUSeconds)
+	}
+
+	if overhead {
+		// RUs of mandatory system operations are only reported as system
+		// overhead, so that the consumption of the tenant reflects its own
+		// workload.
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.mu.consumption.SystemOverheadRU += float64(totalRU)
+		return nil
+	}
+
 	// TODO(andyk): Consider breaking up huge acquisition requests into chunks
 	// that can be fulfilled separately and reported separately. This would make
 	// it easier to stick within a constrained RU/s budget.
-	if err := c.limiter.Wait(ctx, totalRU); err != nil {
+	lim, backup := c.limiterFor(ctx)
+	if exempt {
+		// Exempt operations are charged without waiting, so that they are never
+		// throttled.
+		lim.RemoveRU(c.timeSource.Now(), totalRU)
+	} else if err := lim.Wait(ctx, totalRU); err != nil {
 		return err
 	}
+	if observer := multitenant.RUObserverFromContext(ctx); observer != nil {
+		observer(totalRU, kvCPUSeconds)
+	}
+	if resp.IsRead() {
+		c.indexReads.maybeRecord(resp, totalRU)
+	}
+	c.rangeCosts.maybeRecord(req, resp, totalRU, costCfg.EstimatedKVCPUSeconds(writeKVRU+readKVRU))
 
 	// Record the number of RUs consumed by the IO request.
 	if execinfra.IncludeRUEstimateInExplainAnalyze.Get(&c.settings.SV) {
 		if sp := tracing.SpanFromContext(ctx); sp != nil &&
 			sp.RecordingType() != tracingpb.RecordingOff {
-			sp.RecordStructured(&kvpb.TenantConsumption{
-				RU: float64(totalRU),
-			})
+			if estimatedCPU {
+				sp.RecordStructured(&kvpb.TenantConsumption{
+					EstimatedCPUSeconds:   kvCPUSeconds,
+					EstimatedKVCPUSeconds: kvCPUSeconds,
+				})
+			} else {
+				sp.RecordStructured(&kvpb.TenantConsumption{
+					RU: float64(totalRU),
+				})
+			}
 		}
 	}
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if backup {
+		// RUs of paced backups are only reported as backup RUs, so that they are
+		// not taken into account when requesting RUs for the main token bucket.
+		c.mu.consumption.BackupRU += float64(totalRU)
+	}
+
 	if req.IsWrite() {
 		c.mu.consumption.WriteBatches += uint64(req.WriteReplicas())
 		c.mu.consumption.WriteRequests += uint64(req.WriteReplicas() * req.WriteCount())
 		c.mu.consumption.WriteBytes += uint64(req.WriteReplicas() * req.WriteBytes())
-		c.mu.consumption.KVRU += float64(writeKVRU)
-		c.mu.consumption.RU += float64(writeKVRU + writeNetworkRU)
-		c.mu.consumption.CrossRegionNetworkRU += float64(writeNetworkRU)
+		if !estimatedCPU && !backup {
+			c.mu.consumption.KVRU += float64(writeKVRU)
+			c.mu.consumption.RU += float64(writeKVRU + writeNetworkRU)
+			c.mu.consumption.CrossRegionNetworkRU += float64(writeNetworkRU)
+		}
 	} else if resp.IsRead() {
 		c.mu.consumption.ReadBatches++
 		c.mu.consumption.ReadRequests += uint64(resp.ReadCount())
 		c.mu.consumption.ReadBytes += uint64(resp.ReadBytes())
-		c.mu.consumption.KVRU += float64(readKVRU)
-		c.mu.consumption.RU += float64(readKVRU + readNetworkRU)
-		c.mu.consumption.CrossRegionNetworkRU += float64(readNetworkRU)
+		if !estimatedCPU && !backup {
+			c.mu.consumption.KVRU += float64(readKVRU)
+			c.mu.consumption.RU += float64(readKVRU + readNetworkRU)
+			c.mu.consumption.CrossRegionNetworkRU += float64(readNetworkRU)
+		}
+	}
+	if estimatedCPU && !backup {
+		c.mu.consumption.EstimatedCPUSeconds += kvCPUSeconds
+		c.mu.consumption.EstimatedKVCPUSeconds += kvCPUSeconds
 	}
 
 	return nil
 }
 
+// IsThrottled is part of the multitenant.TenantSideKVInterceptor interface.
+func (c *tenantSideCostController) IsThrottled(ctx context.Context) bool {
+	if multitenant.HasTenantCostControlExemption(ctx) {
+		return false
+	}
+	return c.throttled.Load()
+}
+
+// CheckLiveBytesLimit is part of the multitenant.TenantSideKVInterceptor
+// interface.
+func (c *tenantSideCostController) CheckLiveBytesLimit(ctx context.Context) error {
+	if multitenant.HasTenantCostControlExemption(ctx) || !c.liveBytesLimitExceeded.Load() {
+		return nil
+	}
+	return errors.WithHint(
+		pgerror.New(pgcode.DiskFull, "virtual cluster exceeded its storage limit"),
+		"Delete data, or ask the system operator to raise the max_live_bytes capability.",
+	)
+}
+
+// PreferredBatchBytes is part of the multitenant.TenantSideKVInterceptor
+// interface.
+func (c *tenantSideCostController) PreferredBatchBytes() int64 {
+	if !c.throttled.Load() && c.limiter.AvailableRU(c.timeSource.Now()) >= 0 {
+		return 0
+	}
+	return ThrottledBatchBytes.Get(&c.settings.SV)
+}
+
 func (c *tenantSideCostController) shouldWaitForExternalIORUs() bool {
 	c.modeMu.RLock()
 	defer c.modeMu.RUnlock()
@@ -865,7 +1307,17 @@ func (c *tenantSideCostController) OnExternalIO(
 func (c *tenantSideCostController) onExternalIO(
 	ctx context.Context, usage multitenant.ExternalIOUsage, wait bool,
 ) error {
-	if multitenant.HasTenantCostControlExemption(ctx) {
+	exempt, overhead := c.systemOverhead(ctx)
+
+	if c.useEstimatedCPUModel() {
+		// External I/O is not billed under the estimated CPU model.
+		if overhead {
+			return nil
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.mu.consumption.ExternalIOIngressBytes += uint64(usage.IngressBytes)
+		c.mu.consumption.ExternalIOEgressBytes += uint64(usage.EgressBytes)
 		return nil
 	}
 
@@ -873,12 +1325,26 @@ func (c *tenantSideCostController) onExternalIO(
 	totalRU := costCfg.ExternalIOIngressCost(usage.IngressBytes) +
 		costCfg.ExternalIOEgressCost(usage.EgressBytes)
 
-	if wait {
-		if err := c.limiter.Wait(ctx, totalRU); err != nil {
+	if overhead {
+		if c.shouldAccountForExternalIORUs() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.mu.consumption.SystemOverheadRU += float64(totalRU)
+		}
+		return nil
+	}
+
+	lim, backup := c.limiterFor(ctx)
+	if wait && !exempt {
+		if err := lim.Wait(ctx, totalRU); err != nil {
 			return err
 		}
 	} else {
-		c.limiter.RemoveRU(c.timeSource.Now(), totalRU)
+		lim.RemoveRU(c.timeSource.Now(), totalRU)
+	}
+
+	if observer := multitenant.RUObserverFromContext(ctx); observer != nil {
+		observer(totalRU, 0 /* estimatedCPUSeconds */)
 	}
 
 	c.mu.Lock()
@@ -886,12 +1352,74 @@ func (c *tenantSideCostController) onExternalIO(
 	c.mu.consumption.ExternalIOIngressBytes += uint64(usage.IngressBytes)
 	c.mu.consumption.ExternalIOEgressBytes += uint64(usage.EgressBytes)
 	if c.shouldAccountForExternalIORUs() {
-		c.mu.consumption.RU += float64(totalRU)
+		if backup {
+			c.mu.consumption.BackupRU += float64(totalRU)
+		} else {
+			c.mu.consumption.RU += float64(totalRU)
+		}
 	}
 
 	return nil
 }
 
+// OnBackgroundWorkWait is part of the multitenant.TenantSideCostController
+// interface.
+func (c *tenantSideCostController) OnBackgroundWorkWait(ctx context.Context) error {
+	if multitenant.HasTenantCostControlExemption(ctx) {
+		return nil
+	}
+
+	var timer timeutil.TimerI
+	for {
+		minAvailable := tenantcostmodel.RU(BackgroundWorkMinAvailableRU.Get(&c.settings.SV))
+		if c.limiter.AvailableRU(c.timeSource.Now()) >= minAvailable {
+			return nil
+		}
+		if timer == nil {
+			timer = c.timeSource.NewTimer()
+			defer timer.Stop()
+		}
+		timer.Reset(backgroundWorkPollInterval)
+		select {
+		case <-timer.Ch():
+			timer.MarkRead()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// systemOverhead returns whether the operations of the given context are exempt
+// from cost control, in which case they are never throttled, and whether their
+// RUs are reported as system overhead instead of being charged to the tenant.
+func (c *tenantSideCostController) systemOverhead(ctx context.Context) (exempt, overhead bool) {
+	if !multitenant.HasTenantCostControlExemption(ctx) {
+		return false, false
+	}
+	return true, SystemOverheadExemption.Get(&c.settings.SV)
+}
+
+// limiterFor returns the limiter that should pace the operations of the given
+// context. Backups are paced by the backup limiter if BackupRURate is positive,
+// in which case backup is true.
+func (c *tenantSideCostController) limiterFor(ctx context.Context) (_ *limiter, backup bool) {
+	if multitenant.HasBackupRUPacing(ctx) && BackupRURate.Get(&c.settings.SV) > 0 {
+		return &c.backupLimiter, true
+	}
+	return &c.limiter, false
+}
+
+// reconfigureBackupLimiter updates the backup limiter to refill at the rate set
+// by BackupRURate, allowing a burst of at most one second worth of RUs.
+func (c *tenantSideCostController) reconfigureBackupLimiter() {
+	rate := tenantcostmodel.RU(BackupRURate.Get(&c.settings.SV))
+	c.backupLimiter.Reconfigure(c.timeSource.Now(), limiterReconfigureArgs{
+		NewTokens: rate,
+		NewRate:   rate,
+		MaxTokens: rate,
+	})
+}
+
 // GetCPUMovingAvg is used to obtain an exponential moving average estimate
 // for the CPU usage in seconds per each second of wall-clock time.
 func (c *tenantSideCostController) GetCPUMovingAvg() float64 {
@@ -905,6 +1433,37 @@ func (c *tenantSideCostController) GetCostConfig() *tenantcostmodel.Config {
 	return c.costCfg.Load()
 }
 
+// GetHostConsumptionHistory is part of the
+// multitenant.TenantSideCostController interface.
+func (c *tenantSideCostController) GetHostConsumptionHistory() []multitenant.HostConsumptionSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := make([]multitenant.HostConsumptionSnapshot, c.mu.hostConsumptionHistory.Len())
+	for i := range res {
+		res[i] = c.mu.hostConsumptionHistory.Get(i)
+	}
+	return res
+}
+
+// GetCostHistory is part of the multitenant.TenantSideCostController
+// interface.
+func (c *tenantSideCostController) GetCostHistory() []multitenant.CostSample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]multitenant.CostSample(nil), c.mu.history...)
+}
+
+// GetIndexReadUsage is part of the multitenant.TenantSideCostController
+// interface.
+func (c *tenantSideCostController) GetIndexReadUsage(limit int) []multitenant.IndexReadUsage {
+	return c.indexReads.topN(limit)
+}
+
+// GetRangeCosts is part of the multitenant.TenantSideCostController interface.
+func (c *tenantSideCostController) GetRangeCosts() []multitenant.RangeCost {
+	return c.rangeCosts.costs()
+}
+
 // Metrics returns a metric.Struct which holds metrics for the controller.
 func (c *tenantSideCostController) Metrics() metric.Struct {
 	return &c.metrics
@@ -16,9 +16,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"reflect"
 	"strconv"
-	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -27,6 +25,7 @@ import (
 	_ "github.com/cockroachdb/cockroach/pkg/ccl" // ccl init hooks
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/multitenantccl/tenantcostclient"
+	"github.com/cockroachdb/cockroach/pkg/ccl/multitenantccl/tenantcostclient/tenantcostclienttest"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
 	"github.com/cockroachdb/cockroach/pkg/cloud/nullsink"
@@ -34,12 +33,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobstest"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvtenant"
-	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/multitenantio"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
-	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -47,7 +44,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
-	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness/slbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -58,14 +54,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
-	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
-	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/datadriven"
 	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/require"
-	yaml "gopkg.in/yaml.v2"
 )
 
 // TestDataDriven tests the tenant-side cost controller in an isolated setting.
@@ -76,746 +70,14 @@ func TestDataDriven(t *testing.T) {
 		defer leaktest.AfterTest(t)()
 		defer log.Scope(t).Close(t)
 
-		var ts testState
-		ts.start(t)
-		defer ts.stop()
-
-		datadriven.RunTest(t, path, func(t *testing.T, d *datadriven.TestData) string {
-			args := parseArgs(t, d)
-			fn, ok := testStateCommands[d.Cmd]
-			if !ok {
-				d.Fatalf(t, "unknown command %s", d.Cmd)
-			}
-			return fn(&ts, t, d, args)
-		})
+		tenantcostclienttest.RunDataDriven(t, path)
 	})
 }
 
-type testState struct {
-	timeSrc    *timeutil.ManualTime
-	settings   *cluster.Settings
-	stopper    *stop.Stopper
-	provider   *testProvider
-	controller multitenant.TenantSideCostController
-	eventWait  *eventWaiter
-
-	// external usage values, accessed using atomic.
-	cpuUsage          time.Duration
-	pgwireEgressBytes int64
-
-	requestDoneCh map[string]chan struct{}
-}
-
-var eventTypeStr = map[tenantcostclient.TestEventType]string{
-	tenantcostclient.TickProcessed:                "tick",
-	tenantcostclient.LowRUNotification:            "low-ru",
-	tenantcostclient.TokenBucketResponseProcessed: "token-bucket-response",
-	tenantcostclient.TokenBucketResponseError:     "token-bucket-response-error",
-}
-
-const timeFormat = "15:04:05.000"
-
-var t0 = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+var t0 = tenantcostclienttest.StartTime
 
 const timeout = 10 * time.Second
 
-func (ts *testState) start(t *testing.T) {
-	ctx := context.Background()
-
-	ts.requestDoneCh = make(map[string]chan struct{})
-
-	ts.timeSrc = timeutil.NewManualTime(t0)
-	ts.eventWait = newEventWaiter(ts.timeSrc)
-
-	ts.settings = cluster.MakeTestingClusterSettings()
-	// Fix settings so that the defaults can be changed without updating the test.
-	tenantcostclient.TargetPeriodSetting.Override(ctx, &ts.settings.SV, 10*time.Second)
-	tenantcostclient.CPUUsageAllowance.Override(ctx, &ts.settings.SV, 10*time.Millisecond)
-	tenantcostclient.InitialRequestSetting.Override(ctx, &ts.settings.SV, 10000)
-
-	ts.stopper = stop.NewStopper()
-	var err error
-	ts.provider = newTestProvider()
-	ts.controller, err = tenantcostclient.TestingTenantSideCostController(
-		ts.settings,
-		roachpb.MustMakeTenantID(5),
-		ts.provider,
-		ts.timeSrc,
-		ts.eventWait,
-	)
-	if err != nil {
-		t.Fatal(err)
-	}
-	externalUsageFn := func(context.Context) multitenant.ExternalUsage {
-		return multitenant.ExternalUsage{
-			CPUSecs:           time.Duration(atomic.LoadInt64((*int64)(&ts.cpuUsage))).Seconds(),
-			PGWireEgressBytes: uint64(atomic.LoadInt64(&ts.pgwireEgressBytes)),
-		}
-	}
-	nextLiveInstanceIDFn := func(ctx context.Context) base.SQLInstanceID {
-		return 0
-	}
-	instanceID := base.SQLInstanceID(1)
-	sessionID := sqlliveness.SessionID("foo")
-	if err := ts.controller.Start(
-		ctx, ts.stopper, instanceID, sessionID, externalUsageFn, nextLiveInstanceIDFn,
-	); err != nil {
-		t.Fatal(err)
-	}
-
-	// Wait for main loop to start in order to avoid race conditions where a test
-	// starts before the main loop has been initialized.
-	if !ts.eventWait.WaitForEvent(tenantcostclient.MainLoopStarted) {
-		t.Fatal("did not receive event MainLoopStarted")
-	}
-}
-
-func (ts *testState) stop() {
-	ts.stopper.Stop(context.Background())
-}
-
-type cmdArgs struct {
-	count       int64
-	bytes       int64
-	repeat      int64
-	label       string
-	wait        bool
-	networkCost float64
-}
-
-func parseBytesVal(arg datadriven.CmdArg) (int64, error) {
-	if len(arg.Vals) != 1 {
-		return 0, errors.Newf("expected one value for bytes")
-	}
-	val, err := strconv.ParseInt(arg.Vals[0], 10, 64)
-	if err != nil {
-		return 0, errors.Wrap(err, "could not convert value to integer")
-	}
-	return val, nil
-}
-
-func parseArgs(t *testing.T, d *datadriven.TestData) cmdArgs {
-	var res cmdArgs
-	res.count = 1
-	for _, args := range d.CmdArgs {
-		switch args.Key {
-		case "count":
-			if len(args.Vals) != 1 {
-				d.Fatalf(t, "expected one value for count")
-			}
-			val, err := strconv.Atoi(args.Vals[0])
-			if err != nil {
-				d.Fatalf(t, "invalid count value")
-			}
-			res.count = int64(val)
-
-		case "bytes":
-			v, err := parseBytesVal(args)
-			if err != nil {
-				d.Fatalf(t, err.Error())
-			}
-			res.bytes = v
-
-		case "repeat":
-			if len(args.Vals) != 1 {
-				d.Fatalf(t, "expected one value for repeat")
-			}
-			val, err := strconv.Atoi(args.Vals[0])
-			if err != nil {
-				d.Fatalf(t, "invalid repeat value")
-			}
-			res.repeat = int64(val)
-
-		case "label":
-			if len(args.Vals) != 1 || args.Vals[0] == "" {
-				d.Fatalf(t, "label requires a value")
-			}
-			res.label = args.Vals[0]
-
-		case "wait":
-			if len(args.Vals) != 1 {
-				d.Fatalf(t, "expected one value for wait")
-			}
-			switch args.Vals[0] {
-			case "true":
-				res.wait = true
-			case "false":
-			default:
-				d.Fatalf(t, "invalid wait value")
-			}
-
-		case "networkCost":
-			if len(args.Vals) != 1 {
-				d.Fatalf(t, "expected one value for networkCost")
-			}
-			val, err := strconv.ParseFloat(args.Vals[0], 64)
-			if err != nil {
-				d.Fatalf(t, "invalid networkCost value")
-			}
-			res.networkCost = val
-		default:
-			d.Fatalf(t, "uknown command: '%s'", args.Key)
-		}
-	}
-	return res
-}
-
-var testStateCommands = map[string]func(
-	*testState, *testing.T, *datadriven.TestData, cmdArgs,
-) string{
-	"read":                           (*testState).read,
-	"write":                          (*testState).write,
-	"await":                          (*testState).await,
-	"not-completed":                  (*testState).notCompleted,
-	"advance":                        (*testState).advance,
-	"wait-for-event":                 (*testState).waitForEvent,
-	"timers":                         (*testState).timers,
-	"cpu":                            (*testState).cpu,
-	"pgwire-egress":                  (*testState).pgwireEgress,
-	"external-egress":                (*testState).externalEgress,
-	"external-ingress":               (*testState).externalIngress,
-	"enable-external-ru-accounting":  (*testState).enableRUAccounting,
-	"disable-external-ru-accounting": (*testState).disableRUAccounting,
-	"usage":                          (*testState).usage,
-	"metrics":                        (*testState).metrics,
-	"configure":                      (*testState).configure,
-	"token-bucket":                   (*testState).tokenBucket,
-	"unblock-request":                (*testState).unblockRequest,
-}
-
-// runOperation invokes the given operation function on a background goroutine.
-// If label is empty, runOperation will synchronously wait for the operation to
-// complete. Otherwise, it will enter the label in the requestDoneCh map so that
-// the caller can wait for it to complete.
-func (ts *testState) runOperation(t *testing.T, d *datadriven.TestData, label string, op func()) {
-	runInBackground := func(op func()) chan struct{} {
-		ch := make(chan struct{})
-		go func() {
-			op()
-			close(ch)
-		}()
-		return ch
-	}
-
-	if label != "" {
-		// Async case.
-		if _, ok := ts.requestDoneCh[label]; ok {
-			d.Fatalf(t, "label %v already in use", label)
-		}
-
-		ts.requestDoneCh[label] = runInBackground(op)
-	} else {
-		// Sync case.
-		select {
-		case <-runInBackground(op):
-		case <-time.After(timeout):
-			d.Fatalf(t, "request timed out")
-		}
-	}
-}
-
-// request simulates processing a read or write. If a label is provided, the
-// request is started in the background.
-func (ts *testState) request(
-	t *testing.T, d *datadriven.TestData, isWrite bool, args cmdArgs,
-) string {
-	ctx := context.Background()
-	repeat := args.repeat
-	if repeat == 0 {
-		repeat = 1
-	}
-
-	var writeCount, readCount, writeBytes, readBytes int64
-	var writeNetworkCost, readNetworkCost tenantcostmodel.NetworkCost
-	if isWrite {
-		writeCount = args.count
-		writeBytes = args.bytes
-		writeNetworkCost = tenantcostmodel.NetworkCost(args.networkCost)
-	} else {
-		readCount = args.count
-		readBytes = args.bytes
-		readNetworkCost = tenantcostmodel.NetworkCost(args.networkCost)
-	}
-	reqInfo := tenantcostmodel.TestingRequestInfo(1, writeCount, writeBytes, writeNetworkCost)
-	respInfo := tenantcostmodel.TestingResponseInfo(!isWrite, readCount, readBytes, readNetworkCost)
-
-	for ; repeat > 0; repeat-- {
-		ts.runOperation(t, d, args.label, func() {
-			if err := ts.controller.OnRequestWait(ctx); err != nil {
-				t.Errorf("OnRequestWait error: %v", err)
-			}
-			if err := ts.controller.OnResponseWait(ctx, reqInfo, respInfo); err != nil {
-				t.Errorf("OnResponseWait error: %v", err)
-			}
-		})
-	}
-	return ""
-}
-
-func (ts *testState) externalIngress(t *testing.T, _ *datadriven.TestData, args cmdArgs) string {
-	usage := multitenant.ExternalIOUsage{IngressBytes: args.bytes}
-	if err := ts.controller.OnExternalIOWait(context.Background(), usage); err != nil {
-		t.Errorf("OnExternalIOWait error: %s", err)
-	}
-	return ""
-}
-
-func (ts *testState) externalEgress(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
-	ctx := context.Background()
-	usage := multitenant.ExternalIOUsage{EgressBytes: args.bytes}
-	ts.runOperation(t, d, args.label, func() {
-		if err := ts.controller.OnExternalIOWait(ctx, usage); err != nil {
-			t.Errorf("OnExternalIOWait error: %s", err)
-		}
-	})
-	return ""
-}
-
-func (ts *testState) enableRUAccounting(_ *testing.T, _ *datadriven.TestData, _ cmdArgs) string {
-	tenantcostclient.ExternalIORUAccountingMode.Override(context.Background(), &ts.settings.SV, "on")
-	return ""
-}
-
-func (ts *testState) disableRUAccounting(_ *testing.T, _ *datadriven.TestData, _ cmdArgs) string {
-	tenantcostclient.ExternalIORUAccountingMode.Override(context.Background(), &ts.settings.SV, "off")
-	return ""
-}
-
-// read simulates processing a read. If a label is provided, the request is
-// started in the background.
-func (ts *testState) read(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
-	return ts.request(t, d, false /* isWrite */, args)
-}
-
-// write simulates processing a write. If a label is provided, the request is
-// started in the background.
-func (ts *testState) write(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
-	return ts.request(t, d, true /* isWrite */, args)
-}
-
-// await waits until the given request completes.
-func (ts *testState) await(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
-	ch, ok := ts.requestDoneCh[args.label]
-	if !ok {
-		d.Fatalf(t, "unknown label %q", args.label)
-	}
-	select {
-	case <-ch:
-	case <-time.After(timeout):
-		d.Fatalf(t, "await(%q) timed out", args.label)
-	}
-	delete(ts.requestDoneCh, args.label)
-	return ""
-}
-
-// notCompleted verifies that the request with the given label has not completed
-// yet.
-func (ts *testState) notCompleted(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
-	ch, ok := ts.requestDoneCh[args.label]
-	if !ok {
-		d.Fatalf(t, "unknown label %v", args.label)
-	}
-	// Sleep a bit to give a chance for a bug to manifest.
-	time.Sleep(1 * time.Millisecond)
-	select {
-	case <-ch:
-		d.Fatalf(t, "request %v completed unexpectedly", args.label)
-	default:
-	}
-	return ""
-}
-
-// advance advances the clock by the provided duration and returns the new
-// current time.
-//
-//	advance
-//	2s
-//	----
-//	00:00:02.000
-//
-// An optional "wait" argument will cause advance to block until it receives a
-// tick event, indicating the clock change has been processed.
-func (ts *testState) advance(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
-	ctx := context.Background()
-	dur, err := time.ParseDuration(d.Input)
-	if err != nil {
-		d.Fatalf(t, "failed to parse input as duration: %v", err)
-	}
-	if log.ExpensiveLogEnabled(ctx, 1) {
-		log.Infof(ctx, "Advance %v", dur)
-	}
-	ts.timeSrc.Advance(dur)
-	if args.wait {
-		// Wait for tick event.
-		ts.waitForEvent(t, &datadriven.TestData{Input: "tick"}, cmdArgs{})
-	}
-	return ts.timeSrc.Now().Format(timeFormat)
-}
-
-// waitForEvent waits until the tenant controller reports the given event
-// type(s), at the current time.
-func (ts *testState) waitForEvent(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
-	typs := make(map[string]tenantcostclient.TestEventType)
-	for ev, evStr := range eventTypeStr {
-		typs[evStr] = ev
-	}
-
-	typ, ok := typs[d.Input]
-	if !ok {
-		d.Fatalf(t, "unknown event type %s (supported types: %v)", d.Input, typs)
-	}
-
-	if !ts.eventWait.WaitForEvent(typ) {
-		d.Fatalf(t, "did not receive event %s", d.Input)
-	}
-
-	return ""
-}
-
-// unblockRequest resumes a token bucket request that was blocked by the
-// "blockRequest" configuration option.
-func (ts *testState) unblockRequest(t *testing.T, _ *datadriven.TestData, _ cmdArgs) string {
-	ts.provider.unblockRequest(t)
-	return ""
-}
-
-// timers waits for the set of open timers to match the expected output.
-// timers is critical to avoid synchronization problems in testing. The command
-// outputs the set of timers in increasing order with each timer's deadline on
-// its own line.
-//
-// The following example would wait for there to be two outstanding timers at
-// 00:00:01.000 and 00:00:02.000.
-//
-//	timers
-//	----
-//	00:00:01.000
-//	00:00:02.000
-func (ts *testState) timers(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
-	// If we are rewriting the test, just sleep a bit before returning the
-	// timers.
-	if d.Rewrite {
-		time.Sleep(time.Second)
-		return timesToString(ts.timeSrc.Timers())
-	}
-
-	exp := strings.TrimSpace(d.Expected)
-	if err := testutils.SucceedsWithinError(func() error {
-		got := timesToString(ts.timeSrc.Timers())
-		if got != exp {
-			return errors.Errorf("got: %q, exp: %q", got, exp)
-		}
-		return nil
-	}, timeout); err != nil {
-		d.Fatalf(t, "failed to find expected timers: %v", err)
-	}
-	return d.Expected
-}
-
-func timesToString(times []time.Time) string {
-	strs := make([]string, len(times))
-	for i, t := range times {
-		strs[i] = t.Format(timeFormat)
-	}
-	return strings.Join(strs, "\n")
-}
-
-// configure the test provider.
-func (ts *testState) configure(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
-	var cfg testProviderConfig
-	if err := yaml.UnmarshalStrict([]byte(d.Input), &cfg); err != nil {
-		d.Fatalf(t, "failed to parse request yaml: %v", err)
-	}
-	ts.provider.configure(cfg)
-	return ""
-}
-
-// tokenBucket dumps the current state of the tenant's token bucket.
-func (ts *testState) tokenBucket(*testing.T, *datadriven.TestData, cmdArgs) string {
-	return tenantcostclient.TestingTokenBucketString(ts.controller)
-}
-
-// cpu adds CPU usage which will be observed by the controller on the next main
-// loop tick.
-func (ts *testState) cpu(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
-	duration, err := time.ParseDuration(d.Input)
-	if err != nil {
-		d.Fatalf(t, "error parsing cpu duration: %v", err)
-	}
-	atomic.AddInt64((*int64)(&ts.cpuUsage), int64(duration))
-	return ""
-}
-
-// pgwire adds PGWire egress usage which will be observed by the controller on the next
-// main loop tick.
-func (ts *testState) pgwireEgress(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
-	bytes, err := strconv.Atoi(d.Input)
-	if err != nil {
-		d.Fatalf(t, "error parsing pgwire bytes value: %v", err)
-	}
-	atomic.AddInt64(&ts.pgwireEgressBytes, int64(bytes))
-	return ""
-}
-
-// usage prints out the latest consumption. Callers are responsible for
-// triggering calls to the token bucket provider and waiting for responses.
-func (ts *testState) usage(*testing.T, *datadriven.TestData, cmdArgs) string {
-	c := ts.provider.consumption()
-	return fmt.Sprintf(""+
-		"RU:  %.2f\n"+
-		"KVRU:  %.2f\n"+
-		"CrossRegionNetworkRU:  %.2f\n"+
-		"Reads:  %d requests in %d batches (%d bytes)\n"+
-		"Writes:  %d requests in %d batches (%d bytes)\n"+
-		"SQL Pods CPU seconds:  %.2f\n"+
-		"PGWire egress:  %d bytes\n"+
-		"ExternalIO egress: %d bytes\n"+
-		"ExternalIO ingress: %d bytes\n",
-		c.RU,
-		c.KVRU,
-		c.CrossRegionNetworkRU,
-		c.ReadRequests,
-		c.ReadBatches,
-		c.ReadBytes,
-		c.WriteRequests,
-		c.WriteBatches,
-		c.WriteBytes,
-		c.SQLPodsCPUSeconds,
-		c.PGWireEgressBytes,
-		c.ExternalIOEgressBytes,
-		c.ExternalIOIngressBytes,
-	)
-}
-
-// metrics prints out cost client related consumption metrics. Callers are
-// responsible for waiting on tick events since that is when metrics will be
-// updated.
-func (ts *testState) metrics(*testing.T, *datadriven.TestData, cmdArgs) string {
-	metricNames := []string{
-		"tenant.sql_usage.request_units",
-		"tenant.sql_usage.kv_request_units",
-		"tenant.sql_usage.read_batches",
-		"tenant.sql_usage.read_requests",
-		"tenant.sql_usage.read_bytes",
-		"tenant.sql_usage.write_batches",
-		"tenant.sql_usage.write_requests",
-		"tenant.sql_usage.write_bytes",
-		"tenant.sql_usage.sql_pods_cpu_seconds",
-		"tenant.sql_usage.pgwire_egress_bytes",
-		"tenant.sql_usage.external_io_ingress_bytes",
-		"tenant.sql_usage.external_io_egress_bytes",
-		"tenant.sql_usage.cross_region_network_ru",
-	}
-	state := make(map[string]interface{})
-	v := reflect.ValueOf(ts.controller.Metrics()).Elem()
-	for i := 0; i < v.NumField(); i++ {
-		switch typ := v.Field(i).Interface().(type) {
-		case metric.Iterable:
-			typ.Inspect(func(v interface{}) {
-				switch it := v.(type) {
-				case *metric.Counter:
-					state[typ.GetName()] = it.Count()
-				case *metric.CounterFloat64:
-					state[typ.GetName()] = fmt.Sprintf("%.2f", it.Count())
-				}
-			})
-		}
-	}
-	var output string
-	for _, name := range metricNames {
-		v, ok := state[name]
-		if !ok {
-			panic(fmt.Sprintf("missing data for metric %q", name))
-		}
-		output += fmt.Sprintf("%s: %v\n", name, v)
-	}
-	return output
-}
-
-type event struct {
-	time time.Time
-	typ  tenantcostclient.TestEventType
-}
-
-type eventWaiter struct {
-	timeSrc *timeutil.ManualTime
-	ch      chan event
-}
-
-var _ tenantcostclient.TestInstrumentation = (*eventWaiter)(nil)
-
-func newEventWaiter(timeSrc *timeutil.ManualTime) *eventWaiter {
-	return &eventWaiter{timeSrc: timeSrc, ch: make(chan event, 10000)}
-}
-
-// Event implements the TestInstrumentation interface.
-func (ew *eventWaiter) Event(now time.Time, typ tenantcostclient.TestEventType) {
-	ev := event{
-		time: now,
-		typ:  typ,
-	}
-	select {
-	case ew.ch <- ev:
-		if testing.Verbose() {
-			log.Infof(context.Background(), "event %s at %s\n",
-				eventTypeStr[typ], now.Format(timeFormat))
-		}
-	default:
-		panic("events channel full")
-	}
-}
-
-// WaitForEvent returns true if it receives the given event type at the current
-// time. If it fails to do this before timeout, it returns false.
-func (ew *eventWaiter) WaitForEvent(typ tenantcostclient.TestEventType) bool {
-	now := ew.timeSrc.Now()
-	for {
-		select {
-		case ev := <-ew.ch:
-			if ev.time == now && ev.typ == typ {
-				return true
-			}
-			// Else drop the event.
-
-		case <-time.After(timeout):
-			return false
-		}
-	}
-}
-
-// testProvider is a testing implementation of kvtenant.TokenBucketProvider,
-type testProvider struct {
-	mu struct {
-		syncutil.Mutex
-		consumption kvpb.TenantConsumption
-
-		lastSeqNum int64
-
-		cfg testProviderConfig
-	}
-	recvOnRequest chan struct{}
-	sendOnRequest chan struct{}
-}
-
-type testProviderConfig struct {
-	// If zero, the provider always grants RUs immediately. If positive, the
-	// provider grants RUs at this rate. If negative, the provider never grants
-	// RUs.
-	Throttle float64 `yaml:"throttle"`
-
-	// If set, the provider always errors out.
-	ProviderError bool `yaml:"error"`
-
-	// If set, the provider blocks after receiving each TokenBucket request and
-	// waits until unblockRequest is called.
-	ProviderBlock bool `yaml:"block"`
-
-	FallbackRate float64 `yaml:"fallback_rate"`
-}
-
-var _ kvtenant.TokenBucketProvider = (*testProvider)(nil)
-
-func newTestProvider() *testProvider {
-	return &testProvider{
-		recvOnRequest: make(chan struct{}),
-		sendOnRequest: make(chan struct{}),
-	}
-}
-
-func (tp *testProvider) configure(cfg testProviderConfig) {
-	tp.mu.Lock()
-	defer tp.mu.Unlock()
-	tp.mu.cfg = cfg
-}
-
-// waitForRequest waits until the next TokenBucket request.
-func (tp *testProvider) waitForRequest(t *testing.T) {
-	t.Helper()
-	// Try to send through the unbuffered channel, which blocks until TokenBucket
-	// is called.
-	select {
-	case tp.recvOnRequest <- struct{}{}:
-	case <-time.After(timeout):
-		t.Fatal("did not receive request")
-	}
-}
-
-func (tp *testProvider) consumption() kvpb.TenantConsumption {
-	tp.mu.Lock()
-	defer tp.mu.Unlock()
-	return tp.mu.consumption
-}
-
-// waitForConsumption waits for the next TokenBucket request and returns the
-// total consumption.
-func (tp *testProvider) waitForConsumption(t *testing.T) kvpb.TenantConsumption {
-	tp.waitForRequest(t)
-	// it is possible that the TokenBucket request was in the process of being
-	// prepared; we have to wait for another one to make sure the latest
-	// consumption is incorporated.
-	tp.waitForRequest(t)
-	return tp.consumption()
-}
-
-// unblockRequest unblocks a TokenBucket request that was blocked by the "block"
-// configuration option. This is used to test race conditions.
-func (tp *testProvider) unblockRequest(t *testing.T) {
-	t.Helper()
-	// Try to receive through the unbuffered channel, which blocks until
-	// TokenBucket sends.
-	select {
-	case <-tp.sendOnRequest:
-	case <-time.After(timeout):
-		t.Fatal("did not receive request")
-	}
-}
-
-// TokenBucket implements the kvtenant.TokenBucketProvider interface.
-func (tp *testProvider) TokenBucket(
-	_ context.Context, in *kvpb.TokenBucketRequest,
-) (*kvpb.TokenBucketResponse, error) {
-	tp.mu.Lock()
-	defer tp.mu.Unlock()
-	select {
-	case <-tp.recvOnRequest:
-	default:
-	}
-
-	if in.SeqNum <= tp.mu.lastSeqNum {
-		panic("non-increasing sequence number")
-	}
-	tp.mu.lastSeqNum = in.SeqNum
-
-	if tp.mu.cfg.ProviderError {
-		return nil, errors.New("injected error")
-	}
-	if tp.mu.cfg.ProviderBlock {
-		// Block until unblockRequest is called.
-		select {
-		case tp.sendOnRequest <- struct{}{}:
-		case <-time.After(timeout):
-			return nil, errors.New("TokenBucket was never unblocked")
-		}
-	}
-
-	tp.mu.consumption.Add(&in.ConsumptionSinceLastRequest)
-	res := &kvpb.TokenBucketResponse{}
-
-	rate := tp.mu.cfg.Throttle
-	if rate >= 0 {
-		res.GrantedRU = in.RequestedRU
-		if rate > 0 {
-			res.TrickleDuration = time.Duration(in.RequestedRU / rate * float64(time.Second))
-			if res.TrickleDuration > in.TargetRequestPeriod {
-				res.GrantedRU *= in.TargetRequestPeriod.Seconds() / res.TrickleDuration.Seconds()
-				res.TrickleDuration = in.TargetRequestPeriod
-			}
-		}
-	}
-	res.FallbackRate = tp.mu.cfg.FallbackRate
-	return res, nil
-}
-
 // TestWaitingRU verifies that multiple concurrent requests that stack up in the
 // quota pool are reflected in AvailableRU.
 func TestWaitingRU(t *testing.T) {
@@ -828,12 +90,12 @@ func TestWaitingRU(t *testing.T) {
 	st := cluster.MakeTestingClusterSettings()
 	tenantcostclient.CPUUsageAllowance.Override(ctx, &st.SV, time.Second)
 
-	testProvider := newTestProvider()
-	testProvider.configure(testProviderConfig{ProviderError: true})
+	testProvider := tenantcostclienttest.NewProvider()
+	testProvider.Configure(tenantcostclienttest.ProviderConfig{ProviderError: true})
 
 	tenantID := serverutils.TestTenantID()
 	timeSource := timeutil.NewManualTime(t0)
-	eventWait := newEventWaiter(timeSource)
+	eventWait := tenantcostclienttest.NewEventWaiter(timeSource)
 	ctrl, err := tenantcostclient.TestingTenantSideCostController(
 		st, tenantID, testProvider, timeSource, eventWait)
 	require.NoError(t, err)
@@ -923,6 +185,275 @@ func TestWaitingRU(t *testing.T) {
 	}, 2*time.Minute)
 }
 
+// TestBackupRUPacing verifies that backups are paced by the dedicated backup
+// token bucket when tenant_cost_control.backup_ru_rate is set, without consuming
+// RUs from the tenant's token bucket.
+func TestBackupRUPacing(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	timeSource := timeutil.NewManualTime(t0)
+	ctrl, err := tenantcostclient.TestingTenantSideCostController(
+		st, serverutils.TestTenantID(), tenantcostclienttest.NewProvider(), timeSource, nil /* testInstr */)
+	require.NoError(t, err)
+
+	// Each request costs 1K RUs.
+	req := tenantcostmodel.TestingRequestInfo(1, 1, 1021952, 0)
+	resp := tenantcostmodel.TestingResponseInfo(false, 0, 0, 0)
+	backupCtx := multitenant.WithBackupRUPacing(ctx)
+	const allowedDelta = 0.01
+
+	// Without a backup RU rate, backups consume RUs from the tenant's initial 5K
+	// RUs like any other operation.
+	require.NoError(t, ctrl.OnResponseWait(backupCtx, req, resp))
+	require.InDelta(t, 4000, float64(tenantcostclient.TestingAvailableRU(ctrl)), allowedDelta)
+
+	// With a backup RU rate, backups are charged to the backup token bucket.
+	tenantcostclient.BackupRURate.Override(ctx, &st.SV, 100)
+	require.NoError(t, ctrl.OnResponseWait(backupCtx, req, resp))
+	require.InDelta(t, 4000, float64(tenantcostclient.TestingAvailableRU(ctrl)), allowedDelta)
+
+	// The backup token bucket is now in debt, so backups must wait, whereas
+	// other operations are not affected.
+	require.NoError(t, ctrl.OnRequestWait(ctx))
+	require.NoError(t, ctrl.OnResponseWait(ctx, req, resp))
+	require.InDelta(t, 3000, float64(tenantcostclient.TestingAvailableRU(ctrl)), allowedDelta)
+	func() {
+		waitCtx, cancel := context.WithTimeout(backupCtx, 10*time.Millisecond)
+		defer cancel()
+		require.Error(t, ctrl.OnRequestWait(waitCtx))
+	}()
+
+	// Backups can proceed once the debt has been repaid at 100 RU/s.
+	timeSource.Advance(10 * time.Second)
+	require.NoError(t, ctrl.OnRequestWait(backupCtx))
+}
+
+// TestSystemOverheadExemption verifies that operations exempt from cost control
+// are never throttled, and that their RUs are reported as system overhead unless
+// tenant_cost_control.system_overhead_exemption.enabled is false.
+func TestSystemOverheadExemption(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	timeSource := timeutil.NewManualTime(t0)
+	ctrl, err := tenantcostclient.TestingTenantSideCostController(
+		st, serverutils.TestTenantID(), tenantcostclienttest.NewProvider(), timeSource, nil /* testInstr */)
+	require.NoError(t, err)
+
+	// Each request costs 1K RUs.
+	req := tenantcostmodel.TestingRequestInfo(1, 1, 1021952, 0)
+	resp := tenantcostmodel.TestingResponseInfo(false, 0, 0, 0)
+	exemptCtx := multitenant.WithTenantCostControlExemption(ctx)
+	const allowedDelta = 0.01
+
+	// Exempt operations don't consume RUs from the tenant's initial 5K RUs.
+	require.NoError(t, ctrl.OnRequestWait(exemptCtx))
+	require.NoError(t, ctrl.OnResponseWait(exemptCtx, req, resp))
+	require.InDelta(t, 5000, float64(tenantcostclient.TestingAvailableRU(ctrl)), allowedDelta)
+	consumption := tenantcostclient.TestingConsumption(ctrl)
+	require.InDelta(t, 1000, consumption.SystemOverheadRU, allowedDelta)
+	require.Zero(t, consumption.RU)
+	require.Zero(t, consumption.WriteBatches)
+
+	// With the exemption disabled, exempt operations are charged to the tenant,
+	// but are still never throttled, even once the token bucket is in debt.
+	tenantcostclient.SystemOverheadExemption.Override(ctx, &st.SV, false)
+	for i := 0; i < 6; i++ {
+		require.NoError(t, ctrl.OnRequestWait(exemptCtx))
+		require.NoError(t, ctrl.OnResponseWait(exemptCtx, req, resp))
+	}
+	require.InDelta(t, -1000, float64(tenantcostclient.TestingAvailableRU(ctrl)), allowedDelta)
+	consumption = tenantcostclient.TestingConsumption(ctrl)
+	require.InDelta(t, 1000, consumption.SystemOverheadRU, allowedDelta)
+	require.InDelta(t, 6000, consumption.RU, allowedDelta)
+	require.Equal(t, uint64(6), consumption.WriteBatches)
+
+	// Other operations are throttled.
+	func() {
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		require.Error(t, ctrl.OnRequestWait(waitCtx))
+	}()
+}
+
+// TestPreferredBatchBytes verifies that larger KV batches are suggested once
+// the token bucket is in debt.
+func TestPreferredBatchBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	tenantcostclient.SystemOverheadExemption.Override(ctx, &st.SV, false)
+	timeSource := timeutil.NewManualTime(t0)
+	ctrl, err := tenantcostclient.TestingTenantSideCostController(
+		st, serverutils.TestTenantID(), tenantcostclienttest.NewProvider(), timeSource, nil /* testInstr */)
+	require.NoError(t, err)
+
+	// There is no preference while RUs are available.
+	require.Zero(t, ctrl.PreferredBatchBytes())
+
+	// Put the token bucket in debt by consuming 6K of its initial 5K RUs.
+	// Exempt operations are charged without waiting.
+	req := tenantcostmodel.TestingRequestInfo(1, 1, 1021952, 0)
+	resp := tenantcostmodel.TestingResponseInfo(false, 0, 0, 0)
+	exemptCtx := multitenant.WithTenantCostControlExemption(ctx)
+	for i := 0; i < 6; i++ {
+		require.NoError(t, ctrl.OnResponseWait(exemptCtx, req, resp))
+	}
+	require.Equal(t, int64(16<<20), ctrl.PreferredBatchBytes())
+
+	tenantcostclient.ThrottledBatchBytes.Override(ctx, &st.SV, 0)
+	require.Zero(t, ctrl.PreferredBatchBytes())
+	tenantcostclient.ThrottledBatchBytes.Override(ctx, &st.SV, 1<<20)
+	require.Equal(t, int64(1<<20), ctrl.PreferredBatchBytes())
+
+	// There is no preference once the debt has been repaid.
+	tenantcostclient.TestingSetRate(ctrl, 1000)
+	timeSource.Advance(2 * time.Second)
+	require.Zero(t, ctrl.PreferredBatchBytes())
+}
+
+// TestThrottledBatchBytesSetting verifies that the batch size suggested while
+// throttled is bounded, and can be overridden for virtual clusters.
+func TestThrottledBatchBytesSetting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, mainDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestControlsTenantsExplicitly,
+	})
+	defer s.Stopper().Stop(ctx)
+	sysDB := sqlutils.MakeSQLRunner(mainDB)
+
+	tenant, tenantDB := serverutils.StartTenant(t, s, base.TestTenantArgs{
+		TenantID: serverutils.TestTenantID(),
+	})
+	defer tenantDB.Close()
+	tenantSQL := sqlutils.MakeSQLRunner(tenantDB)
+
+	sysDB.ExpectErr(t, `expected value in range \[0, 67108864\], got: 134217728`,
+		"SET CLUSTER SETTING tenant_cost_control.throttled_batch_bytes = '128 MiB'")
+
+	sysDB.Exec(t, "ALTER TENANT ALL SET CLUSTER SETTING tenant_cost_control.throttled_batch_bytes = '32 MiB'")
+	tenantSQL.CheckQueryResultsRetry(t,
+		"SHOW CLUSTER SETTING tenant_cost_control.throttled_batch_bytes", [][]string{{"32 MiB"}})
+	require.Equal(t, int64(32<<20), tenantcostclient.ThrottledBatchBytes.Get(&tenant.ClusterSettings().SV))
+}
+
+// TestBackgroundWorkWait verifies that background work waits while the tenant
+// is running low on RUs, and that RU observers are notified of consumption.
+func TestBackgroundWorkWait(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	tenantcostclient.BackgroundWorkMinAvailableRU.Override(ctx, &st.SV, 1000)
+	timeSource := timeutil.NewManualTime(t0)
+	ctrl, err := tenantcostclient.TestingTenantSideCostController(
+		st, serverutils.TestTenantID(), tenantcostclienttest.NewProvider(), timeSource, nil /* testInstr */)
+	require.NoError(t, err)
+
+	// With the initial 5K RUs available, background work can proceed.
+	require.NoError(t, ctrl.OnBackgroundWorkWait(ctx))
+
+	// Consume 4.5K RUs, observing the consumption.
+	var observed tenantcostmodel.RU
+	observerCtx := multitenant.WithRUObserver(ctx, func(ru tenantcostmodel.RU, _ float64) {
+		observed += ru
+	})
+	resp := tenantcostmodel.TestingResponseInfo(false, 0, 0, 0)
+	require.NoError(t, ctrl.OnResponseWait(observerCtx,
+		tenantcostmodel.TestingRequestInfo(1, 1, 1021952, 0), resp))
+	require.NoError(t, ctrl.OnResponseWait(observerCtx,
+		tenantcostmodel.TestingRequestInfo(1, 1, 2557952, 0), resp))
+	require.InDelta(t, 3500, float64(observed), 0.01)
+	require.NoError(t, ctrl.OnResponseWait(ctx,
+		tenantcostmodel.TestingRequestInfo(1, 1, 1021952, 0), resp))
+	require.InDelta(t, 3500, float64(observed), 0.01)
+
+	// Background work now waits, unless it is exempt from cost control.
+	func() {
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		require.Error(t, ctrl.OnBackgroundWorkWait(waitCtx))
+	}()
+	require.NoError(t, ctrl.OnBackgroundWorkWait(multitenant.WithTenantCostControlExemption(ctx)))
+
+	// Background work can proceed once the bucket is refilled.
+	tenantcostclient.TestingSetRate(ctrl, 1000)
+	timeSource.Advance(time.Second)
+	require.NoError(t, ctrl.OnBackgroundWorkWait(ctx))
+}
+
+// TestCostHistory verifies that the controller periodically records samples of
+// the consumption of the tenant, and discards them after the retention period.
+func TestCostHistory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	// Disable CPU consumption so that it doesn't interfere with test.
+	st := cluster.MakeTestingClusterSettings()
+	tenantcostclient.CPUUsageAllowance.Override(ctx, &st.SV, time.Second)
+
+	testProvider := tenantcostclienttest.NewProvider()
+	testProvider.Configure(tenantcostclienttest.ProviderConfig{ProviderError: true})
+
+	timeSource := timeutil.NewManualTime(t0)
+	eventWait := tenantcostclienttest.NewEventWaiter(timeSource)
+	ctrl, err := tenantcostclient.TestingTenantSideCostController(
+		st, serverutils.TestTenantID(), testProvider, timeSource, eventWait)
+	require.NoError(t, err)
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	externalUsage := func(ctx context.Context) multitenant.ExternalUsage {
+		return multitenant.ExternalUsage{}
+	}
+	nextLiveInstanceID := func(ctx context.Context) base.SQLInstanceID { return 2 }
+	require.NoError(t, ctrl.Start(
+		ctx, stopper, 1, "test", externalUsage, nextLiveInstanceID))
+	require.True(t, eventWait.WaitForEvent(tenantcostclient.TokenBucketResponseError))
+	require.Empty(t, ctrl.GetCostHistory())
+
+	// Consume 1K RUs.
+	require.NoError(t, ctrl.OnResponseWait(ctx,
+		tenantcostmodel.TestingRequestInfo(1, 1, 1021952, 0),
+		tenantcostmodel.TestingResponseInfo(false, 0, 0, 0)))
+
+	timeSource.Advance(10 * time.Second)
+	require.True(t, eventWait.WaitForEvent(tenantcostclient.TickProcessed))
+	history := ctrl.GetCostHistory()
+	require.Len(t, history, 1)
+	require.Equal(t, t0.Add(10*time.Second), history[0].Time)
+	require.InDelta(t, 1000, history[0].RU, 0.01)
+	require.Zero(t, history[0].BlockedRequests)
+
+	// No consumption since the last sample.
+	timeSource.Advance(10 * time.Second)
+	require.True(t, eventWait.WaitForEvent(tenantcostclient.TickProcessed))
+	history = ctrl.GetCostHistory()
+	require.Len(t, history, 2)
+	require.Zero(t, history[1].RU)
+
+	// Samples older than the retention period are discarded.
+	timeSource.Advance(multitenant.CostHistoryRetention)
+	require.True(t, eventWait.WaitForEvent(tenantcostclient.TickProcessed))
+	history = ctrl.GetCostHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, t0.Add(20*time.Second+multitenant.CostHistoryRetention), history[1].Time)
+}
+
 // TestConsumption verifies consumption reporting from a tenant server process.
 func TestConsumption(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -936,7 +467,7 @@ func TestConsumption(t *testing.T) {
 	tenantcostclient.TargetPeriodSetting.Override(context.Background(), &st.SV, targetPeriod)
 	tenantcostclient.CPUUsageAllowance.Override(context.Background(), &st.SV, 0)
 
-	testProvider := newTestProvider()
+	testProvider := tenantcostclienttest.NewProvider()
 
 	_, tenantDB := serverutils.StartTenant(t, hostServer, base.TestTenantArgs{
 		TenantID: serverutils.TestTenantID(),
@@ -957,14 +488,14 @@ func TestConsumption(t *testing.T) {
 	// test a few times, since background requests can trick the test into
 	// passing.
 	for repeat := 0; repeat < 5; repeat++ {
-		beforeWrite := testProvider.waitForConsumption(t)
+		beforeWrite := testProvider.WaitForConsumption(t)
 		r.Exec(t, "INSERT INTO t (v) SELECT repeat('1234567890', 1024) FROM generate_series(1, 10) AS g(i)")
 		const expectedBytes = 10 * 10 * 1024
 
 		// Try a few times because background activity can trigger bucket
 		// requests before the test query does.
 		testutils.SucceedsSoon(t, func() error {
-			afterWrite := testProvider.waitForConsumption(t)
+			afterWrite := testProvider.WaitForConsumption(t)
 			delta := afterWrite
 			delta.Sub(&beforeWrite)
 			if delta.WriteBatches < 1 || delta.WriteRequests < 2 || delta.WriteBytes < expectedBytes*2 {
@@ -973,13 +504,13 @@ func TestConsumption(t *testing.T) {
 			return nil
 		})
 
-		beforeRead := testProvider.waitForConsumption(t)
+		beforeRead := testProvider.WaitForConsumption(t)
 		r.QueryStr(t, "SELECT min(v) FROM t")
 
 		// Try a few times because background activity can trigger bucket
 		// requests before the test query does.
 		testutils.SucceedsSoon(t, func() error {
-			afterRead := testProvider.waitForConsumption(t)
+			afterRead := testProvider.WaitForConsumption(t)
 			delta := afterRead
 			delta.Sub(&beforeRead)
 			if delta.ReadBatches < 1 || delta.ReadRequests < 1 || delta.ReadBytes < expectedBytes {
@@ -991,7 +522,7 @@ func TestConsumption(t *testing.T) {
 	}
 	// Make sure some CPU usage is reported.
 	testutils.SucceedsSoon(t, func() error {
-		c := testProvider.waitForConsumption(t)
+		c := testProvider.WaitForConsumption(t)
 		if c.SQLPodsCPUSeconds == 0 {
 			return errors.New("no CPU usage reported")
 		}
@@ -1002,6 +533,214 @@ func TestConsumption(t *testing.T) {
 	})
 }
 
+// TestStatementStatisticsRU verifies that the RUs consumed by a statement are
+// recorded in its statement statistics.
+func TestStatementStatisticsRU(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	hostServer := serverutils.StartServerOnly(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestControlsTenantsExplicitly,
+	})
+	defer hostServer.Stopper().Stop(context.Background())
+
+	_, tenantDB := serverutils.StartTenant(t, hostServer, base.TestTenantArgs{
+		TenantID: serverutils.TestTenantID(),
+	})
+	r := sqlutils.MakeSQLRunner(tenantDB)
+	r.Exec(t, "SET application_name = 'ru_test'")
+	r.Exec(t, "CREATE TABLE t (v STRING)")
+	for i := 0; i < 3; i++ {
+		r.Exec(t, "INSERT INTO t SELECT repeat('1234567890', 1024) FROM generate_series(1, 10)")
+	}
+
+	var count int
+	var meanRU float64
+	r.QueryRow(t, `
+SELECT (statistics->'statistics'->>'cnt')::INT,
+       (statistics->'statistics'->'requestUnits'->>'mean')::FLOAT
+  FROM crdb_internal.statement_statistics
+ WHERE app_name = 'ru_test' AND metadata->>'query' LIKE 'INSERT INTO t%'`,
+	).Scan(&count, &meanRU)
+	require.Equal(t, 3, count)
+	// Each statement writes 100KiB, which costs well over 1 RU.
+	require.Greater(t, meanRU, 1.0)
+}
+
+// TestTenantUsageDetails verifies that a tenant can read its own consumption,
+// as recorded by the host cluster, from crdb_internal.tenant_usage_details.
+func TestTenantUsageDetails(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	hostServer, hostDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestControlsTenantsExplicitly,
+	})
+	defer hostServer.Stopper().Stop(context.Background())
+
+	st := cluster.MakeTestingClusterSettings()
+	tenantcostclient.TargetPeriodSetting.Override(context.Background(), &st.SV, time.Millisecond*20)
+	_, tenantDB := serverutils.StartTenant(t, hostServer, base.TestTenantArgs{
+		TenantID: serverutils.TestTenantID(),
+		Settings: st,
+	})
+	r := sqlutils.MakeSQLRunner(tenantDB)
+	r.Exec(t, "CREATE TABLE t (v STRING)")
+	r.Exec(t, "INSERT INTO t SELECT repeat('1234567890', 1024) FROM generate_series(1, 10)")
+	const expectedBytes = 10 * 10 * 1024
+
+	const query = `
+SELECT tenant_id, total_ru, total_write_bytes
+  FROM crdb_internal.tenant_usage_details
+ WHERE tenant_id = $1
+ ORDER BY as_of DESC
+ LIMIT 1`
+	tenantID := serverutils.TestTenantID().ToUint64()
+	testutils.SucceedsSoon(t, func() error {
+		var id uint64
+		var ru float64
+		var writeBytes int64
+		if err := tenantDB.QueryRow(query, tenantID).Scan(&id, &ru, &writeBytes); err != nil {
+			return err
+		}
+		if ru <= 0 || writeBytes < expectedBytes {
+			return errors.Newf("ru: %g, write bytes: %d", ru, writeBytes)
+		}
+		return nil
+	})
+
+	// The host cluster sees at least the consumption last reported to the
+	// tenant.
+	const ruQuery = `
+SELECT total_ru
+  FROM crdb_internal.tenant_usage_details
+ WHERE tenant_id = $1
+ ORDER BY as_of DESC
+ LIMIT 1`
+	var tenantRU, hostRU float64
+	r.QueryRow(t, ruQuery, tenantID).Scan(&tenantRU)
+	sqlutils.MakeSQLRunner(hostDB).QueryRow(t, ruQuery, tenantID).Scan(&hostRU)
+	require.GreaterOrEqual(t, hostRU, tenantRU)
+
+	// The tenant keeps a history of the consumption reported by the host, which
+	// never decreases.
+	rows := r.QueryStr(t, `
+SELECT total_ru
+  FROM crdb_internal.tenant_usage_details
+ WHERE tenant_id = $1
+ ORDER BY as_of`, tenantID)
+	require.Greater(t, len(rows), 1)
+	require.LessOrEqual(t, len(rows), multitenant.HostConsumptionHistorySize)
+	var prevRU float64
+	for _, row := range rows {
+		ru, err := strconv.ParseFloat(row[0], 64)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, ru, prevRU)
+		prevRU = ru
+	}
+}
+
+// TestClusterRangeCosts verifies that crdb_internal.cluster_range_costs, and so
+// SHOW RANGES WITH COSTS, include the costs recorded by every SQL instance of
+// the tenant.
+func TestClusterRangeCosts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	hostServer := serverutils.StartServerOnly(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestControlsTenantsExplicitly,
+	})
+	defer hostServer.Stopper().Stop(ctx)
+
+	startInstance := func(disableCreateTenant bool) (serverutils.ApplicationLayerInterface, *sqlutils.SQLRunner) {
+		st := cluster.MakeTestingClusterSettings()
+		tenantcostclient.RangeCostSampleRate.Override(ctx, &st.SV, 1)
+		tenant, tenantDB := serverutils.StartTenant(t, hostServer, base.TestTenantArgs{
+			TenantID:            serverutils.TestTenantID(),
+			Settings:            st,
+			DisableCreateTenant: disableCreateTenant,
+		})
+		return tenant, sqlutils.MakeSQLRunner(tenantDB)
+	}
+	tenant1, r1 := startInstance(false /* disableCreateTenant */)
+	tenant2, r2 := startInstance(true /* disableCreateTenant */)
+
+	// Only the first instance writes, and only the second one reads.
+	r1.Exec(t, "CREATE TABLE t (k INT PRIMARY KEY, v STRING)")
+	r1.Exec(t, "INSERT INTO t SELECT i, repeat('x', 1024) FROM generate_series(1, 10) AS g(i)")
+	r2.Exec(t, "SELECT count(*) FROM t")
+
+	// Either instance sees the costs of both.
+	const costsQuery = `
+SELECT DISTINCT c.node_id
+  FROM crdb_internal.cluster_range_costs c
+  JOIN [SHOW RANGES FROM TABLE t] r ON r.range_id = c.range_id
+ ORDER BY 1`
+	expected := [][]string{
+		{fmt.Sprint(tenant1.SQLInstanceID())},
+		{fmt.Sprint(tenant2.SQLInstanceID())},
+	}
+	r1.CheckQueryResultsRetry(t, costsQuery, expected)
+	r2.CheckQueryResultsRetry(t, costsQuery, expected)
+
+	// The costs of a range are summed over the instances.
+	var ru float64
+	var readBytes, writeBytes int64
+	r2.QueryRow(t, `
+SELECT sum(ru), sum(read_bytes), sum(write_bytes)
+  FROM [SHOW RANGES FROM TABLE t WITH COSTS]`).Scan(&ru, &readBytes, &writeBytes)
+	require.Greater(t, ru, 0.0)
+	require.Greater(t, readBytes, int64(0))
+	require.Greater(t, writeBytes, int64(0))
+
+	// The node-level table only includes the costs of the local instance.
+	r1.CheckQueryResults(t,
+		"SELECT DISTINCT node_id FROM crdb_internal.node_range_costs",
+		[][]string{{fmt.Sprint(tenant1.SQLInstanceID())}})
+}
+
+// TestCopyPacing verifies that COPY FROM waits before inserting its rows while
+// the tenant is running low on RUs, unless sql.copy.ru_pacing.enabled is false.
+func TestCopyPacing(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	hostServer := serverutils.StartServerOnly(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestControlsTenantsExplicitly,
+	})
+	defer hostServer.Stopper().Stop(ctx)
+
+	// Make background work wait forever.
+	st := cluster.MakeTestingClusterSettings()
+	tenantcostclient.BackgroundWorkMinAvailableRU.Override(ctx, &st.SV, 1e12)
+	tenant, tenantDB := serverutils.StartTenant(t, hostServer, base.TestTenantArgs{
+		TenantID: serverutils.TestTenantID(),
+		Settings: st,
+	})
+	r := sqlutils.MakeSQLRunner(tenantDB)
+	r.Exec(t, "CREATE TABLE t (k INT PRIMARY KEY)")
+
+	pgURL, cleanup := tenant.PGUrl(t)
+	defer cleanup()
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, conn.Close(ctx)) }()
+	_, err = conn.Exec(ctx, "SET statement_timeout = '500ms'")
+	require.NoError(t, err)
+
+	rows := [][]interface{}{{1}, {2}, {3}}
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"t"}, []string{"k"}, pgx.CopyFromRows(rows))
+	require.ErrorContains(t, err, "query execution canceled due to statement timeout")
+	r.CheckQueryResults(t, "SELECT count(*) FROM t", [][]string{{"0"}})
+
+	r.Exec(t, "SET CLUSTER SETTING sql.copy.ru_pacing.enabled = false")
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"t"}, []string{"k"}, pgx.CopyFromRows(rows))
+	require.NoError(t, err)
+	r.CheckQueryResults(t, "SELECT count(*) FROM t", [][]string{{"3"}})
+}
+
 // TestSQLLivenessExemption verifies that the operations done by the sqlliveness
 // subsystem are exempt from cost control.
 func TestSQLLivenessExemption(t *testing.T) {
@@ -1088,7 +827,7 @@ func TestScheduledJobsConsumption(t *testing.T) {
 	})
 	defer hostServer.Stopper().Stop(ctx)
 
-	testProvider := newTestProvider()
+	testProvider := tenantcostclienttest.NewProvider()
 
 	env := jobstest.NewJobSchedulerTestEnv(jobstest.UseSystemTables, timeutil.Now())
 	var zeroDuration time.Duration
@@ -1135,13 +874,13 @@ func TestScheduledJobsConsumption(t *testing.T) {
 	r.Exec(t, "CREATE TABLE t (v INT PRIMARY KEY) WITH ("+
 		"ttl_expire_after = '1 microsecond', ttl_job_cron = '* * * * ?', ttl_delete_batch_size = 1)")
 	r.Exec(t, "INSERT INTO t SELECT x FROM generate_series(1,100) g(x)")
-	before := testProvider.waitForConsumption(t)
+	before := testProvider.WaitForConsumption(t)
 
 	// Ensure the job system is not consuming RUs when scanning/claiming jobs.
 	tenantServer.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
 	env.AdvanceTime(24 * time.Hour)
 	time.Sleep(100 * time.Millisecond)
-	after := testProvider.waitForConsumption(t)
+	after := testProvider.WaitForConsumption(t)
 	after.Sub(&before)
 	require.Zero(t, after.WriteBatches)
 	require.Zero(t, after.WriteBytes)
@@ -1157,7 +896,7 @@ func TestScheduledJobsConsumption(t *testing.T) {
 		require.NoError(t, execSchedules())
 
 		// Check consumption.
-		c := testProvider.waitForConsumption(t)
+		c := testProvider.WaitForConsumption(t)
 		c.Sub(&before)
 		if c.WriteRequests < 100 {
 			return errors.New("no write requests reported")
@@ -1185,7 +924,7 @@ func TestConsumptionChangefeeds(t *testing.T) {
 	tenantcostclient.CPUUsageAllowance.Override(ctx, &st.SV, 0)
 	kvserver.RangefeedEnabled.Override(ctx, &st.SV, true)
 
-	testProvider := newTestProvider()
+	testProvider := tenantcostclienttest.NewProvider()
 
 	_, tenantDB := serverutils.StartTenant(t, hostServer, base.TestTenantArgs{
 		TenantID: serverutils.TestTenantID(),
@@ -1206,12 +945,12 @@ func TestConsumptionChangefeeds(t *testing.T) {
 	r := sqlutils.MakeSQLRunner(tenantDB)
 	r.Exec(t, "CREATE TABLE t (v STRING)")
 	r.Exec(t, "INSERT INTO t SELECT repeat('1234567890', 1024) FROM generate_series(1, 10) AS g(i)")
-	beforeChangefeed := testProvider.waitForConsumption(t)
+	beforeChangefeed := testProvider.WaitForConsumption(t)
 	r.Exec(t, "CREATE CHANGEFEED FOR t INTO 'null://'")
 
 	// Make sure some external io usage is reported.
 	testutils.SucceedsSoon(t, func() error {
-		c := testProvider.waitForConsumption(t)
+		c := testProvider.WaitForConsumption(t)
 		c.Sub(&beforeChangefeed)
 		if c.ExternalIOEgressBytes == 0 {
 			return errors.New("no external io usage reported")
@@ -1254,7 +993,7 @@ func TestConsumptionExternalStorage(t *testing.T) {
 	tenantcostclient.TargetPeriodSetting.Override(context.Background(), &st.SV, time.Millisecond*20)
 	tenantcostclient.CPUUsageAllowance.Override(context.Background(), &st.SV, 0)
 
-	testProvider := newTestProvider()
+	testProvider := tenantcostclienttest.NewProvider()
 	_, tenantDB := serverutils.StartTenant(t, hostServer, base.TestTenantArgs{
 		TenantID:      serverutils.TestTenantID(),
 		Settings:      st,
@@ -1282,18 +1021,18 @@ func TestConsumptionExternalStorage(t *testing.T) {
 	t.Run("export in a tenant increments egress bytes", func(t *testing.T) {
 		createTables()
 		defer dropTables()
-		before := testProvider.waitForConsumption(t)
+		before := testProvider.WaitForConsumption(t)
 		r.Exec(t, fmt.Sprintf("EXPORT INTO CSV '%s' FROM TABLE t", testSink.URL))
-		c := testProvider.waitForConsumption(t)
+		c := testProvider.WaitForConsumption(t)
 		c.Sub(&before)
 		require.NotEqual(t, uint64(0), c.ExternalIOEgressBytes)
 	})
 	t.Run("export from a host does not increment egress bytes", func(t *testing.T) {
 		createTables()
 		defer dropTables()
-		before := testProvider.waitForConsumption(t)
+		before := testProvider.WaitForConsumption(t)
 		hostSQL.Exec(t, fmt.Sprintf("EXPORT INTO CSV '%s' FROM TABLE t", testSink.URL))
-		c := testProvider.waitForConsumption(t)
+		c := testProvider.WaitForConsumption(t)
 		c.Sub(&before)
 		require.Equal(t, uint64(0), c.ExternalIOEgressBytes)
 		require.Equal(t, uint64(0), c.ExternalIOIngressBytes)
@@ -1301,9 +1040,9 @@ func TestConsumptionExternalStorage(t *testing.T) {
 	t.Run("import from a tenant increments ingress bytes", func(t *testing.T) {
 		createTables()
 		defer dropTables()
-		before := testProvider.waitForConsumption(t)
+		before := testProvider.WaitForConsumption(t)
 		r.Exec(t, fmt.Sprintf("IMPORT INTO t CSV DATA('%s')", testSink.URL))
-		c := testProvider.waitForConsumption(t)
+		c := testProvider.WaitForConsumption(t)
 		c.Sub(&before)
 		t.Logf("%v", c)
 		require.NotEqual(t, uint64(0), c.ExternalIOIngressBytes)
@@ -1311,9 +1050,9 @@ func TestConsumptionExternalStorage(t *testing.T) {
 	t.Run("export from a host does not increment ingress bytes", func(t *testing.T) {
 		createTables()
 		defer dropTables()
-		before := testProvider.waitForConsumption(t)
+		before := testProvider.WaitForConsumption(t)
 		hostSQL.Exec(t, fmt.Sprintf("IMPORT INTO t CSV DATA('%s')", testSink.URL))
-		c := testProvider.waitForConsumption(t)
+		c := testProvider.WaitForConsumption(t)
 		c.Sub(&before)
 		require.Equal(t, uint64(0), c.ExternalIOIngressBytes)
 		require.Equal(t, uint64(0), c.ExternalIOEgressBytes)
@@ -1324,9 +1063,9 @@ func TestConsumptionExternalStorage(t *testing.T) {
 		defer dropTables()
 		nodelocal.LocalRequiresExternalIOAccounting = true
 		defer func() { nodelocal.LocalRequiresExternalIOAccounting = false }()
-		before := testProvider.waitForConsumption(t)
+		before := testProvider.WaitForConsumption(t)
 		r.Exec(t, "BACKUP t INTO 'nodelocal://1/backups/tenant'")
-		c := testProvider.waitForConsumption(t)
+		c := testProvider.WaitForConsumption(t)
 		c.Sub(&before)
 		require.NotEqual(t, uint64(0), c.ExternalIOEgressBytes)
 	})
@@ -1335,9 +1074,9 @@ func TestConsumptionExternalStorage(t *testing.T) {
 		defer dropTables()
 		nodelocal.LocalRequiresExternalIOAccounting = true
 		defer func() { nodelocal.LocalRequiresExternalIOAccounting = false }()
-		before := testProvider.waitForConsumption(t)
+		before := testProvider.WaitForConsumption(t)
 		hostSQL.Exec(t, "BACKUP t INTO 'nodelocal://1/backups/host'")
-		c := testProvider.waitForConsumption(t)
+		c := testProvider.WaitForConsumption(t)
 		c.Sub(&before)
 		require.Equal(t, uint64(0), c.ExternalIOEgressBytes)
 		require.Equal(t, uint64(0), c.ExternalIOIngressBytes)
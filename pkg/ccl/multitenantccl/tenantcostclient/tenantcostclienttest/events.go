@@ -0,0 +1,80 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package tenantcostclienttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/multitenantccl/tenantcostclient"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+var eventTypeStr = map[tenantcostclient.TestEventType]string{
+	tenantcostclient.TickProcessed:                "tick",
+	tenantcostclient.LowRUNotification:            "low-ru",
+	tenantcostclient.TokenBucketResponseProcessed: "token-bucket-response",
+	tenantcostclient.TokenBucketResponseError:     "token-bucket-response-error",
+}
+
+type event struct {
+	time time.Time
+	typ  tenantcostclient.TestEventType
+}
+
+// EventWaiter is a tenantcostclient.TestInstrumentation which allows waiting
+// for the events reported by the tenant controller.
+type EventWaiter struct {
+	timeSrc *timeutil.ManualTime
+	ch      chan event
+}
+
+var _ tenantcostclient.TestInstrumentation = (*EventWaiter)(nil)
+
+// NewEventWaiter creates an EventWaiter for a controller using the given clock.
+func NewEventWaiter(timeSrc *timeutil.ManualTime) *EventWaiter {
+	return &EventWaiter{timeSrc: timeSrc, ch: make(chan event, 10000)}
+}
+
+// Event implements the TestInstrumentation interface.
+func (ew *EventWaiter) Event(now time.Time, typ tenantcostclient.TestEventType) {
+	ev := event{
+		time: now,
+		typ:  typ,
+	}
+	select {
+	case ew.ch <- ev:
+		if testing.Verbose() {
+			log.Infof(context.Background(), "event %s at %s\n",
+				eventTypeStr[typ], now.Format(timeFormat))
+		}
+	default:
+		panic("events channel full")
+	}
+}
+
+// WaitForEvent returns true if it receives the given event type at the current
+// time. If it fails to do this before timeout, it returns false.
+func (ew *EventWaiter) WaitForEvent(typ tenantcostclient.TestEventType) bool {
+	now := ew.timeSrc.Now()
+	for {
+		select {
+		case ev := <-ew.ch:
+			if ev.time == now && ev.typ == typ {
+				return true
+			}
+			// Else drop the event.
+
+		case <-time.After(timeout):
+			return false
+		}
+	}
+}
@@ -0,0 +1,822 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+// Package tenantcostclienttest contains a deterministic harness for the
+// tenant-side cost controller. The harness drives the controller with a manual
+// clock and a scriptable token bucket provider, and can be used through
+// datadriven files to regression-test throttling behavior; see RunDataDriven.
+package tenantcostclienttest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/multitenantccl/tenantcostclient"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// StartTime is the initial time of the manual clock of a Harness.
+var StartTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+const timeFormat = "15:04:05.000"
+
+const timeout = 10 * time.Second
+
+// RunDataDriven runs the datadriven test file at the given path against a new
+// Harness. See Harness.RunCommand for the supported commands.
+func RunDataDriven(t *testing.T, path string) {
+	h := NewHarness(t)
+	defer h.Stop()
+	h.dataDir = filepath.Dir(path)
+
+	datadriven.RunTest(t, path, h.RunCommand)
+}
+
+// Harness runs a tenant-side cost controller in an isolated setting. The
+// controller uses a manual clock, which only advances when instructed to, and
+// requests RUs from a Provider.
+type Harness struct {
+	TimeSrc    *timeutil.ManualTime
+	Settings   *cluster.Settings
+	Provider   *Provider
+	Controller multitenant.TenantSideCostController
+
+	codec     keys.SQLCodec
+	stopper   *stop.Stopper
+	eventWait *EventWaiter
+
+	// external usage values, accessed using atomic.
+	cpuUsage          time.Duration
+	pgwireEgressBytes int64
+
+	requestDoneCh map[string]chan struct{}
+
+	// dataDir is the directory against which the paths of workload traces are
+	// resolved.
+	dataDir string
+	// workloadOps is the number of workload operations started so far, used to
+	// label them.
+	workloadOps int
+}
+
+// NewHarness creates and starts a Harness. The settings of the controller that
+// affect its behavior are fixed, so that their defaults can be changed
+// without affecting the tests using the harness.
+func NewHarness(t *testing.T) *Harness {
+	ctx := context.Background()
+	h := &Harness{}
+
+	h.requestDoneCh = make(map[string]chan struct{})
+
+	h.TimeSrc = timeutil.NewManualTime(StartTime)
+	h.eventWait = NewEventWaiter(h.TimeSrc)
+
+	h.Settings = cluster.MakeTestingClusterSettings()
+	tenantcostclient.TargetPeriodSetting.Override(ctx, &h.Settings.SV, 10*time.Second)
+	tenantcostclient.CPUUsageAllowance.Override(ctx, &h.Settings.SV, 10*time.Millisecond)
+	tenantcostclient.InitialRequestSetting.Override(ctx, &h.Settings.SV, 10000)
+	tenantcostclient.IndexReadSampleRate.Override(ctx, &h.Settings.SV, 1)
+	tenantcostclient.RangeCostSampleRate.Override(ctx, &h.Settings.SV, 1)
+
+	h.stopper = stop.NewStopper()
+	var err error
+	h.Provider = NewProvider()
+	tenantID := roachpb.MustMakeTenantID(5)
+	h.codec = keys.MakeSQLCodec(tenantID)
+	h.Controller, err = tenantcostclient.TestingTenantSideCostController(
+		h.Settings,
+		tenantID,
+		h.Provider,
+		h.TimeSrc,
+		h.eventWait,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	externalUsageFn := func(context.Context) multitenant.ExternalUsage {
+		return multitenant.ExternalUsage{
+			CPUSecs:           time.Duration(atomic.LoadInt64((*int64)(&h.cpuUsage))).Seconds(),
+			PGWireEgressBytes: uint64(atomic.LoadInt64(&h.pgwireEgressBytes)),
+		}
+	}
+	nextLiveInstanceIDFn := func(ctx context.Context) base.SQLInstanceID {
+		return 0
+	}
+	instanceID := base.SQLInstanceID(1)
+	sessionID := sqlliveness.SessionID("foo")
+	if err := h.Controller.Start(
+		ctx, h.stopper, instanceID, sessionID, externalUsageFn, nextLiveInstanceIDFn,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for main loop to start in order to avoid race conditions where a test
+	// starts before the main loop has been initialized.
+	if !h.eventWait.WaitForEvent(tenantcostclient.MainLoopStarted) {
+		t.Fatal("did not receive event MainLoopStarted")
+	}
+	return h
+}
+
+// Stop stops the controller.
+func (h *Harness) Stop() {
+	h.stopper.Stop(context.Background())
+}
+
+// WaitForEvent returns true if the controller reports the given event type at
+// the current time, before a timeout.
+func (h *Harness) WaitForEvent(typ tenantcostclient.TestEventType) bool {
+	return h.eventWait.WaitForEvent(typ)
+}
+
+// RunCommand runs a datadriven command against the harness. The supported
+// commands are:
+//
+//   - read, write: simulates a KV request; arguments count, bytes, repeat and
+//     networkCost describe the request, and label starts it in the background.
+//     For reads, index=(<table-id>,<index-id>) sets the index that is read;
+//     range=<range-id> sets the range the request is sent to.
+//   - external-ingress, external-egress: simulates external I/O of the given
+//     bytes.
+//   - enable-external-ru-accounting, disable-external-ru-accounting.
+//   - cpu, pgwire-egress: adds the given usage, observed on the next tick.
+//   - await, not-completed: checks on a request started with a label.
+//   - advance: advances the clock; with wait=true, waits for the next tick.
+//   - wait-for-event: waits for a controller event (tick, low-ru,
+//     token-bucket-response, token-bucket-response-error).
+//   - timers: waits for the timers of the clock to match the expected output.
+//   - configure: configures the provider, as a YAML ProviderConfig.
+//   - script: appends responses to the script of the provider, as a YAML list
+//     of ScriptedResponses.
+//   - unblock-request: unblocks a request to a provider configured with block.
+//   - workload: replays a workload trace; see (*Harness).workload.
+//   - token-bucket, usage, metrics, estimated-cpu-usage,
+//     estimated-cpu-metrics, idle-metrics, index-reads, range-costs: print out
+//     the state of the controller.
+func (h *Harness) RunCommand(t *testing.T, d *datadriven.TestData) string {
+	args := parseArgs(t, d)
+	fn, ok := harnessCommands[d.Cmd]
+	if !ok {
+		d.Fatalf(t, "unknown command %s", d.Cmd)
+	}
+	return fn(h, t, d, args)
+}
+
+type cmdArgs struct {
+	count       int64
+	bytes       int64
+	repeat      int64
+	label       string
+	wait        bool
+	networkCost float64
+	file        string
+	// index is the table and index IDs of the index read by a read, if set.
+	index []uint32
+	// rangeID is the range to which a read or write is sent, if set.
+	rangeID roachpb.RangeID
+}
+
+func parseBytesVal(arg datadriven.CmdArg) (int64, error) {
+	if len(arg.Vals) != 1 {
+		return 0, errors.Newf("expected one value for bytes")
+	}
+	val, err := strconv.ParseInt(arg.Vals[0], 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not convert value to integer")
+	}
+	return val, nil
+}
+
+func parseArgs(t *testing.T, d *datadriven.TestData) cmdArgs {
+	res, err := parseCmdArgs(d.CmdArgs)
+	if err != nil {
+		d.Fatalf(t, "%v", err)
+	}
+	return res
+}
+
+func parseCmdArgs(args []datadriven.CmdArg) (cmdArgs, error) {
+	var res cmdArgs
+	res.count = 1
+	for _, arg := range args {
+		switch arg.Key {
+		case "count":
+			if len(arg.Vals) != 1 {
+				return res, errors.New("expected one value for count")
+			}
+			val, err := strconv.Atoi(arg.Vals[0])
+			if err != nil {
+				return res, errors.New("invalid count value")
+			}
+			res.count = int64(val)
+
+		case "bytes":
+			v, err := parseBytesVal(arg)
+			if err != nil {
+				return res, err
+			}
+			res.bytes = v
+
+		case "repeat":
+			if len(arg.Vals) != 1 {
+				return res, errors.New("expected one value for repeat")
+			}
+			val, err := strconv.Atoi(arg.Vals[0])
+			if err != nil {
+				return res, errors.New("invalid repeat value")
+			}
+			res.repeat = int64(val)
+
+		case "label":
+			if len(arg.Vals) != 1 || arg.Vals[0] == "" {
+				return res, errors.New("label requires a value")
+			}
+			res.label = arg.Vals[0]
+
+		case "wait":
+			if len(arg.Vals) != 1 {
+				return res, errors.New("expected one value for wait")
+			}
+			switch arg.Vals[0] {
+			case "true":
+				res.wait = true
+			case "false":
+			default:
+				return res, errors.New("invalid wait value")
+			}
+
+		case "networkCost":
+			if len(arg.Vals) != 1 {
+				return res, errors.New("expected one value for networkCost")
+			}
+			val, err := strconv.ParseFloat(arg.Vals[0], 64)
+			if err != nil {
+				return res, errors.New("invalid networkCost value")
+			}
+			res.networkCost = val
+
+		case "file":
+			if len(arg.Vals) != 1 || arg.Vals[0] == "" {
+				return res, errors.New("file requires a value")
+			}
+			res.file = arg.Vals[0]
+
+		case "index":
+			if len(arg.Vals) != 2 {
+				return res, errors.New("expected table and index IDs for index")
+			}
+			for _, v := range arg.Vals {
+				id, err := strconv.ParseUint(v, 10, 32)
+				if err != nil {
+					return res, errors.New("invalid index value")
+				}
+				res.index = append(res.index, uint32(id))
+			}
+
+		case "range":
+			if len(arg.Vals) != 1 {
+				return res, errors.New("expected one value for range")
+			}
+			id, err := strconv.ParseInt(arg.Vals[0], 10, 64)
+			if err != nil {
+				return res, errors.New("invalid range value")
+			}
+			res.rangeID = roachpb.RangeID(id)
+
+		default:
+			return res, errors.Newf("unknown argument: '%s'", arg.Key)
+		}
+	}
+	return res, nil
+}
+
+var harnessCommands = map[string]func(
+	*Harness, *testing.T, *datadriven.TestData, cmdArgs,
+) string{
+	"read":                           (*Harness).read,
+	"write":                          (*Harness).write,
+	"await":                          (*Harness).await,
+	"not-completed":                  (*Harness).notCompleted,
+	"advance":                        (*Harness).advance,
+	"wait-for-event":                 (*Harness).waitForEvent,
+	"timers":                         (*Harness).timers,
+	"cpu":                            (*Harness).cpu,
+	"pgwire-egress":                  (*Harness).pgwireEgress,
+	"external-egress":                (*Harness).externalEgress,
+	"external-ingress":               (*Harness).externalIngress,
+	"enable-external-ru-accounting":  (*Harness).enableRUAccounting,
+	"disable-external-ru-accounting": (*Harness).disableRUAccounting,
+	"usage":                          (*Harness).usage,
+	"metrics":                        (*Harness).metrics,
+	"estimated-cpu-usage":            (*Harness).estimatedCPUUsage,
+	"estimated-cpu-metrics":          (*Harness).estimatedCPUMetrics,
+	"idle-metrics":                   (*Harness).idleMetrics,
+	"index-reads":                    (*Harness).indexReads,
+	"range-costs":                    (*Harness).rangeCosts,
+	"configure":                      (*Harness).configure,
+	"script":                         (*Harness).script,
+	"token-bucket":                   (*Harness).tokenBucket,
+	"unblock-request":                (*Harness).unblockRequest,
+	"workload":                       (*Harness).workload,
+}
+
+// runInBackground invokes the given operation function on a background
+// goroutine, and returns a channel which is closed once it completes.
+func runInBackground(op func()) chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		op()
+		close(ch)
+	}()
+	return ch
+}
+
+// runOperation invokes the given operation function on a background goroutine.
+// If label is empty, runOperation will synchronously wait for the operation to
+// complete. Otherwise, it will enter the label in the requestDoneCh map so that
+// the caller can wait for it to complete.
+func (h *Harness) runOperation(t *testing.T, d *datadriven.TestData, label string, op func()) {
+	if label != "" {
+		// Async case.
+		if _, ok := h.requestDoneCh[label]; ok {
+			d.Fatalf(t, "label %v already in use", label)
+		}
+
+		h.requestDoneCh[label] = runInBackground(op)
+	} else {
+		// Sync case.
+		select {
+		case <-runInBackground(op):
+		case <-time.After(timeout):
+			d.Fatalf(t, "request timed out")
+		}
+	}
+}
+
+// requestOp returns a function which simulates processing a read or write.
+func (h *Harness) requestOp(t *testing.T, isWrite bool, args cmdArgs) func() {
+	ctx := context.Background()
+	var writeCount, readCount, writeBytes, readBytes int64
+	var writeNetworkCost, readNetworkCost tenantcostmodel.NetworkCost
+	if isWrite {
+		writeCount = args.count
+		writeBytes = args.bytes
+		writeNetworkCost = tenantcostmodel.NetworkCost(args.networkCost)
+	} else {
+		readCount = args.count
+		readBytes = args.bytes
+		readNetworkCost = tenantcostmodel.NetworkCost(args.networkCost)
+	}
+	reqInfo := tenantcostmodel.TestingRequestInfo(1, writeCount, writeBytes, writeNetworkCost)
+	respInfo := tenantcostmodel.TestingResponseInfo(!isWrite, readCount, readBytes, readNetworkCost)
+	if args.index != nil {
+		respInfo = respInfo.WithReadKey(h.codec.IndexPrefix(args.index[0], args.index[1]))
+	}
+	reqInfo = reqInfo.WithRangeID(args.rangeID)
+
+	return func() {
+		if err := h.Controller.OnRequestWait(ctx); err != nil {
+			t.Errorf("OnRequestWait error: %v", err)
+		}
+		if err := h.Controller.OnResponseWait(ctx, reqInfo, respInfo); err != nil {
+			t.Errorf("OnResponseWait error: %v", err)
+		}
+	}
+}
+
+// externalIOOp returns a function which simulates external I/O.
+func (h *Harness) externalIOOp(t *testing.T, usage multitenant.ExternalIOUsage) func() {
+	return func() {
+		if err := h.Controller.OnExternalIOWait(context.Background(), usage); err != nil {
+			t.Errorf("OnExternalIOWait error: %s", err)
+		}
+	}
+}
+
+// request simulates processing a read or write. If a label is provided, the
+// request is started in the background.
+func (h *Harness) request(
+	t *testing.T, d *datadriven.TestData, isWrite bool, args cmdArgs,
+) string {
+	repeat := args.repeat
+	if repeat == 0 {
+		repeat = 1
+	}
+	op := h.requestOp(t, isWrite, args)
+	for ; repeat > 0; repeat-- {
+		h.runOperation(t, d, args.label, op)
+	}
+	return ""
+}
+
+func (h *Harness) externalIngress(t *testing.T, _ *datadriven.TestData, args cmdArgs) string {
+	h.externalIOOp(t, multitenant.ExternalIOUsage{IngressBytes: args.bytes})()
+	return ""
+}
+
+func (h *Harness) externalEgress(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
+	h.runOperation(t, d, args.label, h.externalIOOp(t, multitenant.ExternalIOUsage{EgressBytes: args.bytes}))
+	return ""
+}
+
+func (h *Harness) enableRUAccounting(_ *testing.T, _ *datadriven.TestData, _ cmdArgs) string {
+	tenantcostclient.ExternalIORUAccountingMode.Override(context.Background(), &h.Settings.SV, "on")
+	return ""
+}
+
+func (h *Harness) disableRUAccounting(_ *testing.T, _ *datadriven.TestData, _ cmdArgs) string {
+	tenantcostclient.ExternalIORUAccountingMode.Override(context.Background(), &h.Settings.SV, "off")
+	return ""
+}
+
+// read simulates processing a read. If a label is provided, the request is
+// started in the background.
+func (h *Harness) read(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
+	return h.request(t, d, false /* isWrite */, args)
+}
+
+// write simulates processing a write. If a label is provided, the request is
+// started in the background.
+func (h *Harness) write(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
+	return h.request(t, d, true /* isWrite */, args)
+}
+
+// await waits until the given request completes.
+func (h *Harness) await(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
+	ch, ok := h.requestDoneCh[args.label]
+	if !ok {
+		d.Fatalf(t, "unknown label %q", args.label)
+	}
+	select {
+	case <-ch:
+	case <-time.After(timeout):
+		d.Fatalf(t, "await(%q) timed out", args.label)
+	}
+	delete(h.requestDoneCh, args.label)
+	return ""
+}
+
+// notCompleted verifies that the request with the given label has not completed
+// yet.
+func (h *Harness) notCompleted(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
+	ch, ok := h.requestDoneCh[args.label]
+	if !ok {
+		d.Fatalf(t, "unknown label %v", args.label)
+	}
+	// Sleep a bit to give a chance for a bug to manifest.
+	time.Sleep(1 * time.Millisecond)
+	select {
+	case <-ch:
+		d.Fatalf(t, "request %v completed unexpectedly", args.label)
+	default:
+	}
+	return ""
+}
+
+// advance advances the clock by the provided duration and returns the new
+// current time.
+//
+//	advance
+//	2s
+//	----
+//	00:00:02.000
+//
+// An optional "wait" argument will cause advance to block until it receives a
+// tick event, indicating the clock change has been processed.
+func (h *Harness) advance(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
+	dur, err := time.ParseDuration(d.Input)
+	if err != nil {
+		d.Fatalf(t, "failed to parse input as duration: %v", err)
+	}
+	if err := h.advanceBy(dur, args.wait); err != nil {
+		d.Fatalf(t, "%v", err)
+	}
+	return h.TimeSrc.Now().Format(timeFormat)
+}
+
+// advanceBy advances the clock by the provided duration. If wait is set, it
+// waits for the controller to process a tick at the new time.
+func (h *Harness) advanceBy(dur time.Duration, wait bool) error {
+	ctx := context.Background()
+	if log.ExpensiveLogEnabled(ctx, 1) {
+		log.Infof(ctx, "Advance %v", dur)
+	}
+	h.TimeSrc.Advance(dur)
+	if wait && !h.eventWait.WaitForEvent(tenantcostclient.TickProcessed) {
+		return errors.Newf("did not receive event %s", eventTypeStr[tenantcostclient.TickProcessed])
+	}
+	return nil
+}
+
+// waitForEvent waits until the tenant controller reports the given event
+// type(s), at the current time.
+func (h *Harness) waitForEvent(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
+	typs := make(map[string]tenantcostclient.TestEventType)
+	for ev, evStr := range eventTypeStr {
+		typs[evStr] = ev
+	}
+
+	typ, ok := typs[d.Input]
+	if !ok {
+		d.Fatalf(t, "unknown event type %s (supported types: %v)", d.Input, typs)
+	}
+
+	if !h.eventWait.WaitForEvent(typ) {
+		d.Fatalf(t, "did not receive event %s", d.Input)
+	}
+
+	return ""
+}
+
+// unblockRequest resumes a token bucket request that was blocked by the
+// "block" configuration option.
+func (h *Harness) unblockRequest(t *testing.T, _ *datadriven.TestData, _ cmdArgs) string {
+	h.Provider.UnblockRequest(t)
+	return ""
+}
+
+// timers waits for the set of open timers to match the expected output.
+// timers is critical to avoid synchronization problems in testing. The command
+// outputs the set of timers in increasing order with each timer's deadline on
+// its own line.
+//
+// The following example would wait for there to be two outstanding timers at
+// 00:00:01.000 and 00:00:02.000.
+//
+//	timers
+//	----
+//	00:00:01.000
+//	00:00:02.000
+func (h *Harness) timers(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
+	// If we are rewriting the test, just sleep a bit before returning the
+	// timers.
+	if d.Rewrite {
+		time.Sleep(time.Second)
+		return timesToString(h.TimeSrc.Timers())
+	}
+
+	exp := strings.TrimSpace(d.Expected)
+	if err := testutils.SucceedsWithinError(func() error {
+		got := timesToString(h.TimeSrc.Timers())
+		if got != exp {
+			return errors.Errorf("got: %q, exp: %q", got, exp)
+		}
+		return nil
+	}, timeout); err != nil {
+		d.Fatalf(t, "failed to find expected timers: %v", err)
+	}
+	return d.Expected
+}
+
+func timesToString(times []time.Time) string {
+	strs := make([]string, len(times))
+	for i, t := range times {
+		strs[i] = t.Format(timeFormat)
+	}
+	return strings.Join(strs, "\n")
+}
+
+// configure the test provider.
+func (h *Harness) configure(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
+	var cfg ProviderConfig
+	if err := yaml.UnmarshalStrict([]byte(d.Input), &cfg); err != nil {
+		d.Fatalf(t, "failed to parse request yaml: %v", err)
+	}
+	h.Provider.Configure(cfg)
+	return ""
+}
+
+// script appends responses to the script of the test provider, which are
+// returned in order to the next token bucket requests.
+//
+//	script
+//	- granted: 1000
+//	  trickle: 10s
+//	- error: true
+//	----
+func (h *Harness) script(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
+	var responses []ScriptedResponse
+	if err := yaml.UnmarshalStrict([]byte(d.Input), &responses); err != nil {
+		d.Fatalf(t, "failed to parse script yaml: %v", err)
+	}
+	h.Provider.Script(responses...)
+	return ""
+}
+
+// tokenBucket dumps the current state of the tenant's token bucket.
+func (h *Harness) tokenBucket(*testing.T, *datadriven.TestData, cmdArgs) string {
+	return tenantcostclient.TestingTokenBucketString(h.Controller)
+}
+
+// cpu adds CPU usage which will be observed by the controller on the next main
+// loop tick.
+func (h *Harness) cpu(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
+	duration, err := time.ParseDuration(d.Input)
+	if err != nil {
+		d.Fatalf(t, "error parsing cpu duration: %v", err)
+	}
+	atomic.AddInt64((*int64)(&h.cpuUsage), int64(duration))
+	return ""
+}
+
+// pgwire adds PGWire egress usage which will be observed by the controller on the next
+// main loop tick.
+func (h *Harness) pgwireEgress(t *testing.T, d *datadriven.TestData, _ cmdArgs) string {
+	bytes, err := strconv.Atoi(d.Input)
+	if err != nil {
+		d.Fatalf(t, "error parsing pgwire bytes value: %v", err)
+	}
+	atomic.AddInt64(&h.pgwireEgressBytes, int64(bytes))
+	return ""
+}
+
+// usage prints out the latest consumption. Callers are responsible for
+// triggering calls to the token bucket provider and waiting for responses.
+func (h *Harness) usage(*testing.T, *datadriven.TestData, cmdArgs) string {
+	c := h.Provider.Consumption()
+	return fmt.Sprintf(""+
+		"RU:  %.2f\n"+
+		"KVRU:  %.2f\n"+
+		"CrossRegionNetworkRU:  %.2f\n"+
+		"Reads:  %d requests in %d batches (%d bytes)\n"+
+		"Writes:  %d requests in %d batches (%d bytes)\n"+
+		"SQL Pods CPU seconds:  %.2f\n"+
+		"PGWire egress:  %d bytes\n"+
+		"ExternalIO egress: %d bytes\n"+
+		"ExternalIO ingress: %d bytes\n",
+		c.RU,
+		c.KVRU,
+		c.CrossRegionNetworkRU,
+		c.ReadRequests,
+		c.ReadBatches,
+		c.ReadBytes,
+		c.WriteRequests,
+		c.WriteBatches,
+		c.WriteBytes,
+		c.SQLPodsCPUSeconds,
+		c.PGWireEgressBytes,
+		c.ExternalIOEgressBytes,
+		c.ExternalIOIngressBytes,
+	)
+}
+
+// estimatedCPUUsage prints out the latest consumption that is relevant to the
+// estimated CPU cost model. Callers are responsible for triggering calls to the
+// token bucket provider and waiting for responses.
+func (h *Harness) estimatedCPUUsage(*testing.T, *datadriven.TestData, cmdArgs) string {
+	c := h.Provider.Consumption()
+	return fmt.Sprintf(""+
+		"RU:  %.2f\n"+
+		"KVRU:  %.2f\n"+
+		"Estimated CPU seconds:  %.4f\n"+
+		"Estimated KV CPU seconds:  %.4f\n"+
+		"SQL Pods CPU seconds:  %.2f\n",
+		c.RU,
+		c.KVRU,
+		c.EstimatedCPUSeconds,
+		c.EstimatedKVCPUSeconds,
+		c.SQLPodsCPUSeconds,
+	)
+}
+
+// estimatedCPUMetrics prints out the consumption metrics that are relevant to
+// the estimated CPU cost model. Callers are responsible for waiting on tick
+// events since that is when metrics will be updated.
+func (h *Harness) estimatedCPUMetrics(*testing.T, *datadriven.TestData, cmdArgs) string {
+	return h.formatMetrics([]string{
+		"tenant.sql_usage.request_units",
+		"tenant.sql_usage.kv_request_units",
+		"tenant.sql_usage.sql_pods_cpu_seconds",
+		"tenant.sql_usage.estimated_cpu_seconds",
+		"tenant.sql_usage.estimated_kv_cpu_seconds",
+	})
+}
+
+// metrics prints out cost client related consumption metrics. Callers are
+// responsible for waiting on tick events since that is when metrics will be
+// updated.
+func (h *Harness) metrics(*testing.T, *datadriven.TestData, cmdArgs) string {
+	return h.formatMetrics([]string{
+		"tenant.sql_usage.request_units",
+		"tenant.sql_usage.kv_request_units",
+		"tenant.sql_usage.read_batches",
+		"tenant.sql_usage.read_requests",
+		"tenant.sql_usage.read_bytes",
+		"tenant.sql_usage.write_batches",
+		"tenant.sql_usage.write_requests",
+		"tenant.sql_usage.write_bytes",
+		"tenant.sql_usage.sql_pods_cpu_seconds",
+		"tenant.sql_usage.pgwire_egress_bytes",
+		"tenant.sql_usage.external_io_ingress_bytes",
+		"tenant.sql_usage.external_io_egress_bytes",
+		"tenant.sql_usage.cross_region_network_ru",
+	})
+}
+
+// idleMetrics prints out the metrics related to idle SQL pods. Callers are
+// responsible for waiting on tick events since that is when metrics will be
+// updated.
+func (h *Harness) idleMetrics(*testing.T, *datadriven.TestData, cmdArgs) string {
+	return h.formatMetrics([]string{
+		"tenant.cost_client.idle_suppressed_intervals",
+	})
+}
+
+// indexReads prints out the read usage of the indexes with the highest read
+// cost. The number of indexes is given by the count argument (1 by default).
+//
+//	index-reads count=2
+//	----
+//	table=104 index=2: 33.25 RU, 2097152 bytes in 2 requests (2 sampled batches)
+//	table=104 index=1: 16.62 RU, 1048576 bytes in 1 requests (1 sampled batches)
+func (h *Harness) indexReads(_ *testing.T, _ *datadriven.TestData, args cmdArgs) string {
+	var buf strings.Builder
+	for _, u := range h.Controller.GetIndexReadUsage(int(args.count)) {
+		fmt.Fprintf(&buf, "table=%d index=%d: %.2f RU, %d bytes in %d requests (%d sampled batches)\n",
+			u.TableID, u.IndexID, u.RU, u.ReadBytes, u.ReadRequests, u.SampledBatches)
+	}
+	return buf.String()
+}
+
+// rangeCosts prints out the recent costs of ranges, in decreasing order of
+// cost.
+//
+//	range-costs
+//	----
+//	since 00:00:00.000
+//	r2: 33.25 RU, 0.02 KV CPU seconds, read 2097152 bytes, wrote 0 bytes (2 sampled batches)
+//	r1: 16.62 RU, 0.01 KV CPU seconds, read 1048576 bytes, wrote 0 bytes (1 sampled batches)
+func (h *Harness) rangeCosts(*testing.T, *datadriven.TestData, cmdArgs) string {
+	costs := h.Controller.GetRangeCosts()
+	if len(costs) == 0 {
+		return ""
+	}
+	sort.Slice(costs, func(i, j int) bool {
+		if costs[i].RU != costs[j].RU {
+			return costs[i].RU > costs[j].RU
+		}
+		return costs[i].RangeID < costs[j].RangeID
+	})
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "since %s\n", costs[0].Since.Format(timeFormat))
+	for _, c := range costs {
+		fmt.Fprintf(&buf, "r%d: %.2f RU, %.2f KV CPU seconds, read %d bytes, wrote %d bytes (%d sampled batches)\n",
+			c.RangeID, c.RU, c.KVCPUSeconds, c.ReadBytes, c.WriteBytes, c.SampledBatches)
+	}
+	return buf.String()
+}
+
+// formatMetrics prints out the value of the given cost client metrics.
+func (h *Harness) formatMetrics(metricNames []string) string {
+	state := make(map[string]interface{})
+	v := reflect.ValueOf(h.Controller.Metrics()).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		switch typ := v.Field(i).Interface().(type) {
+		case metric.Iterable:
+			typ.Inspect(func(v interface{}) {
+				switch it := v.(type) {
+				case *metric.Counter:
+					state[typ.GetName()] = it.Count()
+				case *metric.CounterFloat64:
+					state[typ.GetName()] = fmt.Sprintf("%.2f", it.Count())
+				}
+			})
+		}
+	}
+	var output string
+	for _, name := range metricNames {
+		v, ok := state[name]
+		if !ok {
+			panic(fmt.Sprintf("missing data for metric %q", name))
+		}
+		output += fmt.Sprintf("%s: %v\n", name, v)
+	}
+	return output
+}
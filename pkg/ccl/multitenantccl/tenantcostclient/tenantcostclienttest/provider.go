@@ -0,0 +1,240 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package tenantcostclienttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvtenant"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Provider is a testing implementation of kvtenant.TokenBucketProvider. By
+// default, it grants all requested RUs immediately; it can be configured to
+// throttle, fail or block requests, or to return a script of responses.
+type Provider struct {
+	mu struct {
+		syncutil.Mutex
+		consumption kvpb.TenantConsumption
+
+		lastSeqNum int64
+		// lastAckedSeqNum is the sequence number of the last request whose
+		// consumption was accounted for.
+		lastAckedSeqNum int64
+
+		cfg ProviderConfig
+
+		// script contains the responses to the next requests, in order. Once
+		// it's exhausted, responses are derived from cfg.
+		script []ScriptedResponse
+	}
+	recvOnRequest chan struct{}
+	sendOnRequest chan struct{}
+}
+
+// ProviderConfig configures the responses of a Provider.
+type ProviderConfig struct {
+	// If zero, the provider always grants RUs immediately. If positive, the
+	// provider grants RUs at this rate. If negative, the provider never grants
+	// RUs.
+	Throttle float64 `yaml:"throttle"`
+
+	// If set, the provider always errors out.
+	ProviderError bool `yaml:"error"`
+
+	// If set, the provider blocks after receiving each TokenBucket request and
+	// waits until UnblockRequest is called.
+	ProviderBlock bool `yaml:"block"`
+
+	FallbackRate float64 `yaml:"fallback_rate"`
+
+	// CostModel is the tenantcostmodel.ModelVersion returned in each response.
+	CostModel int64 `yaml:"cost_model"`
+
+	// ReportInterval is the report interval pushed to the controller. If zero,
+	// the controller uses the interval of its own settings.
+	ReportInterval time.Duration `yaml:"report_interval"`
+}
+
+// ScriptedResponse is the response of a Provider to a single TokenBucket
+// request, regardless of the RUs requested.
+type ScriptedResponse struct {
+	// GrantedRU is the number of RUs granted.
+	GrantedRU float64 `yaml:"granted"`
+
+	// TrickleDuration is the duration over which the granted RUs are trickled
+	// in; if zero, they are granted immediately.
+	TrickleDuration time.Duration `yaml:"trickle"`
+
+	FallbackRate float64 `yaml:"fallback_rate"`
+
+	// If set, the request errors out.
+	Error bool `yaml:"error"`
+}
+
+var _ kvtenant.TokenBucketProvider = (*Provider)(nil)
+
+// NewProvider creates a Provider which grants all requested RUs immediately.
+func NewProvider() *Provider {
+	return &Provider{
+		recvOnRequest: make(chan struct{}),
+		sendOnRequest: make(chan struct{}),
+	}
+}
+
+// Configure replaces the configuration of the provider.
+func (tp *Provider) Configure(cfg ProviderConfig) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.mu.cfg = cfg
+}
+
+// Script appends responses to be returned, in order, to the next TokenBucket
+// requests. They take precedence over the configuration of the provider,
+// except for ProviderBlock.
+func (tp *Provider) Script(responses ...ScriptedResponse) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.mu.script = append(tp.mu.script, responses...)
+}
+
+// ScriptedResponses returns the number of scripted responses that haven't been
+// returned yet.
+func (tp *Provider) ScriptedResponses() int {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return len(tp.mu.script)
+}
+
+// WaitForRequest waits until the next TokenBucket request.
+func (tp *Provider) WaitForRequest(t testing.TB) {
+	t.Helper()
+	// Try to send through the unbuffered channel, which blocks until TokenBucket
+	// is called.
+	select {
+	case tp.recvOnRequest <- struct{}{}:
+	case <-time.After(timeout):
+		t.Fatal("did not receive request")
+	}
+}
+
+// Consumption returns the total consumption reported to the provider.
+func (tp *Provider) Consumption() kvpb.TenantConsumption {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return tp.mu.consumption
+}
+
+// WaitForConsumption waits for the next TokenBucket request and returns the
+// total consumption.
+func (tp *Provider) WaitForConsumption(t testing.TB) kvpb.TenantConsumption {
+	tp.WaitForRequest(t)
+	// it is possible that the TokenBucket request was in the process of being
+	// prepared; we have to wait for another one to make sure the latest
+	// consumption is incorporated.
+	tp.WaitForRequest(t)
+	return tp.Consumption()
+}
+
+// UnblockRequest unblocks a TokenBucket request that was blocked by the "block"
+// configuration option. This is used to test race conditions.
+func (tp *Provider) UnblockRequest(t testing.TB) {
+	t.Helper()
+	// Try to receive through the unbuffered channel, which blocks until
+	// TokenBucket sends.
+	select {
+	case <-tp.sendOnRequest:
+	case <-time.After(timeout):
+		t.Fatal("did not receive request")
+	}
+}
+
+// TokenBucket implements the kvtenant.TokenBucketProvider interface.
+func (tp *Provider) TokenBucket(
+	_ context.Context, in *kvpb.TokenBucketRequest,
+) (*kvpb.TokenBucketResponse, error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	select {
+	case <-tp.recvOnRequest:
+	default:
+	}
+
+	if in.SeqNum <= tp.mu.lastSeqNum {
+		panic("non-increasing sequence number")
+	}
+	tp.mu.lastSeqNum = in.SeqNum
+
+	var scripted *ScriptedResponse
+	injectError := tp.mu.cfg.ProviderError
+	if len(tp.mu.script) > 0 {
+		scripted = &tp.mu.script[0]
+		tp.mu.script = tp.mu.script[1:]
+		injectError = scripted.Error
+	}
+
+	if injectError {
+		return nil, errors.New("injected error")
+	}
+	if tp.mu.cfg.ProviderBlock {
+		// Block until UnblockRequest is called.
+		select {
+		case tp.sendOnRequest <- struct{}{}:
+		case <-time.After(timeout):
+			return nil, errors.New("TokenBucket was never unblocked")
+		}
+	}
+
+	// Like the host, account for the consumption of the requests that errored
+	// out, if the controller resends it.
+	res := &kvpb.TokenBucketResponse{}
+	if in.ProtocolVersion >= kvpb.TokenBucketProtocolV2 {
+		res.ProtocolVersion = kvpb.TokenBucketProtocolV2
+		res.Capabilities = in.Capabilities & kvpb.TokenBucketCapabilityUnacknowledgedConsumption
+		if tp.mu.cfg.ReportInterval != 0 {
+			res.Capabilities |= in.Capabilities & kvpb.TokenBucketCapabilityReportInterval
+			res.ReportInterval = tp.mu.cfg.ReportInterval
+		}
+	}
+	if res.Capabilities.Has(kvpb.TokenBucketCapabilityUnacknowledgedConsumption) {
+		for i := range in.UnacknowledgedConsumption {
+			if delta := &in.UnacknowledgedConsumption[i]; delta.SeqNum > tp.mu.lastAckedSeqNum {
+				tp.mu.consumption.Add(&delta.Consumption)
+			}
+		}
+	}
+	tp.mu.consumption.Add(&in.ConsumptionSinceLastRequest)
+	tp.mu.lastAckedSeqNum = in.SeqNum
+	res.CostModel = tp.mu.cfg.CostModel
+
+	if scripted != nil {
+		res.GrantedRU = scripted.GrantedRU
+		res.TrickleDuration = scripted.TrickleDuration
+		res.FallbackRate = scripted.FallbackRate
+		return res, nil
+	}
+
+	rate := tp.mu.cfg.Throttle
+	if rate >= 0 {
+		res.GrantedRU = in.RequestedRU
+		if rate > 0 {
+			res.TrickleDuration = time.Duration(in.RequestedRU / rate * float64(time.Second))
+			if res.TrickleDuration > in.TargetRequestPeriod {
+				res.GrantedRU *= in.TargetRequestPeriod.Seconds() / res.TrickleDuration.Seconds()
+				res.TrickleDuration = in.TargetRequestPeriod
+			}
+		}
+	}
+	res.FallbackRate = tp.mu.cfg.FallbackRate
+	return res, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package tenantcostclienttest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/multitenantccl/tenantcostclient"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/errors"
+)
+
+// workload replays a workload trace, given either inline or in the file of the
+// "file" argument (relative to the directory of the datadriven file). Each line
+// of the trace is an operation:
+//
+//   - read, write: a KV request, with the arguments of the read and write
+//     commands (except label and repeat).
+//   - external-ingress, external-egress: external I/O of the given bytes.
+//   - advance <duration>: advances the clock, and waits for the controller to
+//     process a tick at the new time.
+//
+// Empty lines and lines starting with # are ignored. Requests are started in
+// order, at the current time of the clock, and are reported as either admitted,
+// if they complete without waiting for RUs, or blocked. Blocked requests keep
+// running in the background, and can be waited on with the await command using
+// the reported label.
+//
+//	workload
+//	write bytes=1024
+//	advance 1s
+//	write bytes=4093952
+//	----
+//	00:00:00.000 write bytes=1024: admitted
+//	00:00:01.000 advance 1s
+//	00:00:01.000 write bytes=4093952: blocked (label=workload-2)
+func (h *Harness) workload(t *testing.T, d *datadriven.TestData, args cmdArgs) string {
+	trace := d.Input
+	if args.file != "" {
+		path := args.file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(h.dataDir, path)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			d.Fatalf(t, "failed to read workload trace: %v", err)
+		}
+		trace = string(b)
+	}
+
+	var buf strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(trace))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		res, err := h.runWorkloadOp(t, line)
+		if err != nil {
+			d.Fatalf(t, "%s: %v", line, err)
+		}
+		fmt.Fprintf(&buf, "%s %s", h.TimeSrc.Now().Format(timeFormat), line)
+		if res != "" {
+			fmt.Fprintf(&buf, ": %s", res)
+		}
+		buf.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		d.Fatalf(t, "failed to read workload trace: %v", err)
+	}
+	return buf.String()
+}
+
+// runWorkloadOp runs a single operation of a workload trace, and returns its
+// outcome.
+func (h *Harness) runWorkloadOp(t *testing.T, line string) (string, error) {
+	cmd, cmdArgs, err := datadriven.ParseLine(line)
+	if err != nil {
+		return "", err
+	}
+
+	if cmd == "advance" {
+		if len(cmdArgs) != 1 || len(cmdArgs[0].Vals) != 0 {
+			return "", errors.New("expected a duration")
+		}
+		dur, err := time.ParseDuration(cmdArgs[0].Key)
+		if err != nil {
+			return "", err
+		}
+		return "", h.advanceBy(dur, true /* wait */)
+	}
+
+	args, err := parseCmdArgs(cmdArgs)
+	if err != nil {
+		return "", err
+	}
+	if args.label != "" || args.repeat != 0 || args.file != "" || args.wait {
+		return "", errors.New("unsupported argument in workload trace")
+	}
+	var op func()
+	switch cmd {
+	case "read":
+		op = h.requestOp(t, false /* isWrite */, args)
+	case "write":
+		op = h.requestOp(t, true /* isWrite */, args)
+	case "external-ingress":
+		op = h.externalIOOp(t, multitenant.ExternalIOUsage{IngressBytes: args.bytes})
+	case "external-egress":
+		op = h.externalIOOp(t, multitenant.ExternalIOUsage{EgressBytes: args.bytes})
+	default:
+		return "", errors.Newf("unknown workload operation %s", cmd)
+	}
+
+	h.workloadOps++
+	label := fmt.Sprintf("workload-%d", h.workloadOps)
+	if _, ok := h.requestDoneCh[label]; ok {
+		return "", errors.Newf("label %v already in use", label)
+	}
+
+	// The operation either completes, or waits for RUs in the token bucket. Note
+	// that traces are only deterministic if no token bucket request is in flight
+	// while an operation blocks, since its response could unblock it.
+	blocked := tenantcostclient.TestingBlockedRequests(h.Controller)
+	doneCh := runInBackground(op)
+	var admitted bool
+	if err := testutils.SucceedsWithinError(func() error {
+		select {
+		case <-doneCh:
+			admitted = true
+			return nil
+		default:
+		}
+		if tenantcostclient.TestingBlockedRequests(h.Controller) > blocked {
+			return nil
+		}
+		return errors.New("operation neither completed nor blocked")
+	}, timeout); err != nil {
+		return "", err
+	}
+	if admitted {
+		return "admitted", nil
+	}
+	h.requestDoneCh[label] = doneCh
+	return fmt.Sprintf("blocked (label=%s)", label), nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package tenantcostserver
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+var anomalyThreshold = settings.RegisterFloatSetting(
+	settings.SystemOnly,
+	"tenant_cost_control.anomaly_detection.threshold",
+	"number of standard deviations by which the consumption rate of a virtual cluster must exceed "+
+		"its trailing baseline to be flagged as anomalous; 0 disables the detection",
+	4,
+	settings.NonNegativeFloat,
+)
+
+var anomalySampleInterval = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"tenant_cost_control.anomaly_detection.sample_interval",
+	"minimum interval over which the consumption rate of a virtual cluster is measured for the "+
+		"purpose of anomaly detection",
+	1*time.Minute,
+	settings.PositiveDuration,
+)
+
+const (
+	// anomalyBaselineFactor is the weight given to the most recent sample when
+	// computing the exponentially weighted mean and variance of the consumption
+	// rate. The baseline thus reflects roughly the last 1/anomalyBaselineFactor
+	// samples.
+	anomalyBaselineFactor = 0.1
+
+	// anomalyMinBaselineSamples is the number of samples that are needed to
+	// establish a baseline before any anomaly is flagged.
+	anomalyMinBaselineSamples = 10
+
+	// anomalyMinStdDev and anomalyMinRelativeStdDev bound the standard
+	// deviation used to compute the deviation from below, so that tenants with
+	// a very steady consumption are not flagged for small fluctuations. The
+	// bound is the largest of anomalyMinStdDev (in units per second) and
+	// anomalyMinRelativeStdDev times the baseline rate.
+	anomalyMinStdDev         = 1
+	anomalyMinRelativeStdDev = 0.1
+)
+
+// anomalyDetector tracks the trailing baseline of the consumption rate of a
+// tenant, as observed by this node through token bucket requests. It is
+// protected by the tenantMetrics mutex.
+//
+// The consumption is measured in RUs, or in estimated vCPU-seconds for tenants
+// billed under the estimated CPU cost model. The baseline is reset when the
+// cost model changes.
+type anomalyDetector struct {
+	costModel tenantcostmodel.ModelVersion
+	// lastSample is the time of the last sample, or zero if there was none.
+	lastSample time.Time
+	// lastTotal is the total consumption as of lastSample.
+	lastTotal float64
+	// samples is the number of rate samples incorporated into the baseline.
+	samples int
+	// mean and variance are the exponentially weighted mean and variance of
+	// the consumption rate.
+	mean, variance float64
+	// anomalous is set if the last sample was flagged as anomalous.
+	anomalous bool
+}
+
+// anomalySample is a sample of the consumption rate of a tenant, compared to
+// the baseline that preceded it.
+type anomalySample struct {
+	rate           float64
+	baselineRate   float64
+	baselineStdDev float64
+	// deviation is the number of standard deviations by which the rate exceeds
+	// the baseline.
+	deviation float64
+}
+
+// observe records the total consumption of the tenant. If at least the given
+// interval elapsed since the last sample, it compares the consumption rate
+// over that interval to the baseline, then incorporates the rate into the
+// baseline. It returns ok=false if no sample was taken, or if there is no
+// baseline yet.
+func (a *anomalyDetector) observe(
+	now time.Time, model tenantcostmodel.ModelVersion, total float64, interval time.Duration,
+) (sample anomalySample, ok bool) {
+	if a.lastSample.IsZero() || model != a.costModel || total < a.lastTotal {
+		// Start a new baseline. An ongoing anomaly is resolved once the new
+		// baseline is established.
+		*a = anomalyDetector{
+			costModel: model, lastSample: now, lastTotal: total, anomalous: a.anomalous,
+		}
+		return anomalySample{}, false
+	}
+	elapsed := now.Sub(a.lastSample)
+	if elapsed < interval {
+		return anomalySample{}, false
+	}
+	rate := (total - a.lastTotal) / elapsed.Seconds()
+	a.lastSample = now
+	a.lastTotal = total
+
+	if a.samples >= anomalyMinBaselineSamples {
+		stdDev := math.Sqrt(a.variance)
+		minStdDev := math.Max(anomalyMinStdDev, anomalyMinRelativeStdDev*a.mean)
+		sample = anomalySample{
+			rate:           rate,
+			baselineRate:   a.mean,
+			baselineStdDev: stdDev,
+			deviation:      (rate - a.mean) / math.Max(stdDev, minStdDev),
+		}
+		ok = true
+	}
+
+	if a.samples == 0 {
+		a.mean = rate
+	} else {
+		diff := rate - a.mean
+		incr := anomalyBaselineFactor * diff
+		a.mean += incr
+		a.variance = (1 - anomalyBaselineFactor) * (a.variance + diff*incr)
+	}
+	a.samples++
+	return sample, ok
+}
+
+// consumptionTotal returns the total consumption of a tenant in the unit of
+// its cost model.
+func consumptionTotal(
+	model tenantcostmodel.ModelVersion, consumption *kvpb.TenantConsumption,
+) float64 {
+	if model == tenantcostmodel.EstimatedCPUModel {
+		return consumption.EstimatedCPUSeconds
+	}
+	return consumption.RU
+}
+
+// maybeDetectAnomaly updates the baseline of the consumption rate of the
+// tenant and flags the tenant if its rate exceeds the baseline by more than
+// the configured number of standard deviations. Only increases are flagged:
+// drops in consumption (e.g. a workload that stops) are expected. It must be
+// called with the tenantMetrics mutex held.
+func (s *instance) maybeDetectAnomaly(
+	ctx context.Context,
+	tenantID roachpb.TenantID,
+	metrics tenantMetrics,
+	now time.Time,
+	model tenantcostmodel.ModelVersion,
+	consumption *kvpb.TenantConsumption,
+) {
+	a := metrics.anomaly
+	sample, ok := a.observe(
+		now, model, consumptionTotal(model, consumption), anomalySampleInterval.Get(&s.settings.SV),
+	)
+	threshold := anomalyThreshold.Get(&s.settings.SV)
+	if threshold == 0 {
+		if a.anomalous {
+			a.anomalous = false
+			metrics.consumptionAnomaly.Update(0)
+		}
+		return
+	}
+	if !ok {
+		return
+	}
+	anomalous := sample.deviation > threshold
+	if anomalous == a.anomalous {
+		return
+	}
+	a.anomalous = anomalous
+	if anomalous {
+		metrics.consumptionAnomaly.Update(1)
+		log.StructuredEvent(ctx, &eventpb.TenantConsumptionAnomalyDetected{
+			TenantID:       tenantID.ToUint64(),
+			Rate:           sample.rate,
+			BaselineRate:   sample.baselineRate,
+			BaselineStdDev: sample.baselineStdDev,
+			Deviation:      sample.deviation,
+		})
+	} else {
+		metrics.consumptionAnomaly.Update(0)
+		log.StructuredEvent(ctx, &eventpb.TenantConsumptionAnomalyResolved{
+			TenantID:       tenantID.ToUint64(),
+			Rate:           sample.rate,
+			BaselineRate:   sample.baselineRate,
+			BaselineStdDev: sample.baselineStdDev,
+			Deviation:      sample.deviation,
+		})
+	}
+}
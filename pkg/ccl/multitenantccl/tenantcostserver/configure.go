@@ -18,6 +18,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
 )
 
@@ -42,7 +44,9 @@ func (s *instance) ReconfigureTokenBucket(
 		return err
 	}
 	now := s.timeSource.Now()
-	state.update(now)
+	if rate := state.update(now); rate != 0 {
+		logBurstExpired(ctx, tenantID, rate)
+	}
 	state.Bucket.Reconfigure(
 		ctx, tenantID, availableRU, refillRate, maxBurstRU, asOf, asOfConsumedRequestUnits,
 		now, state.Consumption.RU,
@@ -53,6 +57,40 @@ func (s *instance) ReconfigureTokenBucket(
 	return nil
 }
 
+// GrantBurst temporarily increases the refill rate of a tenant's token bucket.
+// It is part of the TenantUsageServer interface; see that for more details.
+func (s *instance) GrantBurst(
+	ctx context.Context,
+	txn isql.Txn,
+	tenantID roachpb.TenantID,
+	burstRate float64,
+	duration time.Duration,
+) error {
+	if err := s.checkTenantID(ctx, txn, tenantID); err != nil {
+		return err
+	}
+	h := makeSysTableHelper(ctx, tenantID)
+	state, err := h.readTenantState(txn)
+	if err != nil {
+		return err
+	}
+	now := s.timeSource.Now()
+	if rate := state.update(now); rate != 0 {
+		logBurstExpired(ctx, tenantID, rate)
+	}
+	state.Bucket.Boost(ctx, tenantID, burstRate, duration)
+	return h.updateTenantState(txn, state)
+}
+
+// logBurstExpired records the expiration of a burst granted to a tenant. Note
+// that the expiration is only noticed when the token bucket is next updated.
+func logBurstExpired(ctx context.Context, tenantID roachpb.TenantID, burstRate float64) {
+	log.StructuredEvent(ctx, &eventpb.TenantBurstExpired{
+		TenantID:  tenantID.ToUint64(),
+		BurstRate: burstRate,
+	})
+}
+
 // checkTenantID verifies that the tenant exists and is active.
 func (s *instance) checkTenantID(
 	ctx context.Context, txn isql.Txn, tenantID roachpb.TenantID,
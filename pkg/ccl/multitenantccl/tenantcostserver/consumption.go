@@ -0,0 +1,201 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package tenantcostserver
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+)
+
+// ruRateMovingAvgFactor is the weight given to the most recent sample when
+// computing the moving average of the RU consumption rate.
+const ruRateMovingAvgFactor = 0.25
+
+// maxRecentThrottleEvents limits the number of throttling events that are
+// retained for a tenant.
+const maxRecentThrottleEvents = 1000
+
+// usageStats tracks the recent consumption rate and throttling events of a
+// tenant, as observed by this node through token bucket requests. It is
+// protected by the tenantMetrics mutex.
+type usageStats struct {
+	// lastUpdate is the time of the last token bucket request, or zero if there
+	// was none.
+	lastUpdate time.Time
+	// lastRU is the total RU consumption reported as of lastUpdate.
+	lastRU float64
+	// ruRate is the moving average of the RU consumption rate.
+	ruRate float64
+	// throttleEvents contains the times of the requests that could not be fully
+	// granted, in increasing order.
+	throttleEvents []time.Time
+	// rollups contains periodic snapshots of the cumulative consumption of the
+	// tenant, in increasing time order. See
+	// multitenant.ConsumptionRollupInterval.
+	rollups []multitenant.TenantConsumptionRollup
+}
+
+// record updates the stats after a token bucket request.
+func (u *usageStats) record(now time.Time, consumption *kvpb.TenantConsumption, throttled bool) {
+	totalRU := consumption.RU
+	if !u.lastUpdate.IsZero() {
+		if elapsed := now.Sub(u.lastUpdate).Seconds(); elapsed > 0 {
+			rate := (totalRU - u.lastRU) / elapsed
+			u.ruRate = ruRateMovingAvgFactor*rate + (1-ruRateMovingAvgFactor)*u.ruRate
+		}
+	}
+	u.lastUpdate = now
+	u.lastRU = totalRU
+
+	if throttled {
+		u.throttleEvents = append(u.throttleEvents, now)
+	}
+	u.trimThrottleEvents(now)
+
+	if n := len(u.rollups); n == 0 ||
+		now.Sub(u.rollups[n-1].Time) >= multitenant.ConsumptionRollupInterval {
+		u.rollups = append(u.rollups, multitenant.TenantConsumptionRollup{
+			Time:              now,
+			RU:                totalRU,
+			SQLPodsCPUSeconds: consumption.SQLPodsCPUSeconds,
+			LiveBytes:         consumption.LiveBytes,
+		})
+	}
+	u.trimRollups(now)
+}
+
+// trimRollups discards the rollups that are older than
+// multitenant.ConsumptionRollupRetention.
+func (u *usageStats) trimRollups(now time.Time) {
+	cutoff := now.Add(-multitenant.ConsumptionRollupRetention)
+	i := sort.Search(len(u.rollups), func(i int) bool {
+		return !u.rollups[i].Time.Before(cutoff)
+	})
+	if i > 0 {
+		u.rollups = append(u.rollups[:0], u.rollups[i:]...)
+	}
+}
+
+// trimThrottleEvents discards the events that are older than
+// multitenant.RecentThrottleWindow.
+func (u *usageStats) trimThrottleEvents(now time.Time) {
+	cutoff := now.Add(-multitenant.RecentThrottleWindow)
+	i := 0
+	for i < len(u.throttleEvents) && (u.throttleEvents[i].Before(cutoff) ||
+		len(u.throttleEvents)-i > maxRecentThrottleEvents) {
+		i++
+	}
+	if i > 0 {
+		u.throttleEvents = append(u.throttleEvents[:0], u.throttleEvents[i:]...)
+	}
+}
+
+// GetTenantConsumption is part of the multitenant.TenantUsageServer interface.
+func (s *instance) GetTenantConsumption(
+	ctx context.Context, txn isql.Txn, tenantID roachpb.TenantID,
+) (multitenant.TenantConsumptionInfo, error) {
+	h := makeSysTableHelper(ctx, tenantID)
+	state, err := h.readTenantState(txn)
+	if err != nil {
+		return multitenant.TenantConsumptionInfo{}, err
+	}
+	var info multitenant.TenantConsumptionInfo
+	now := s.timeSource.Now()
+	if state.Present {
+		info.Present = true
+		info.LastUpdate = state.LastUpdate.Time
+		// Account for the refill since the last update, without persisting it.
+		state.update(now)
+		info.RUAvailable = state.Bucket.RUCurrent
+		info.RURefillRate = state.Bucket.RURefillRate
+		info.RUBurstLimit = state.Bucket.RUBurstLimit
+		info.Consumption = state.Consumption
+	}
+
+	metrics, ok := s.metrics.lookupTenantMetrics(tenantID)
+	if !ok {
+		return info, nil
+	}
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	stats := metrics.usage
+	if stats.lastUpdate.IsZero() {
+		return info, nil
+	}
+	stats.trimThrottleEvents(now)
+	info.HasRecentStats = true
+	info.RURate = stats.ruRate
+	info.RecentThrottleEvents = len(stats.throttleEvents)
+	if n := len(stats.throttleEvents); n > 0 {
+		info.LastThrottled = stats.throttleEvents[n-1]
+	}
+	return info, nil
+}
+
+// GetThrottledTenants is part of the multitenant.TenantUsageServer interface.
+func (s *instance) GetThrottledTenants(ctx context.Context) ([]roachpb.TenantID, error) {
+	var throttled []roachpb.TenantID
+	if err := s.ief.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		throttled = throttled[:0]
+		tenantIDs, tenants, err := readAllTenantStates(ctx, txn)
+		if err != nil {
+			return err
+		}
+		now := s.timeSource.Now()
+		for i := range tenants {
+			// Account for the refill since the last update, without persisting it.
+			tenants[i].update(now)
+			if tenants[i].Bucket.RUCurrent < 0 {
+				throttled = append(throttled, tenantIDs[i])
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return throttled, nil
+}
+
+// GetConsumptionSummaries is part of the multitenant.TenantUsageServer
+// interface.
+func (s *instance) GetConsumptionSummaries(
+	window time.Duration,
+) []multitenant.TenantConsumptionSummary {
+	now := s.timeSource.Now()
+	cutoff := now.Add(-window)
+	var res []multitenant.TenantConsumptionSummary
+	for tenantID, metrics := range s.metrics.allTenantMetrics() {
+		func() {
+			metrics.mutex.Lock()
+			defer metrics.mutex.Unlock()
+			rollups := metrics.usage.rollups
+			i := sort.Search(len(rollups), func(i int) bool {
+				return !rollups[i].Time.Before(cutoff)
+			})
+			if i == len(rollups) {
+				return
+			}
+			res = append(res, multitenant.TenantConsumptionSummary{
+				TenantID: tenantID,
+				Start:    rollups[i],
+				End:      rollups[len(rollups)-1],
+			})
+		}()
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].TenantID.ToUint64() < res[j].TenantID.ToUint64()
+	})
+	return res
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package tenantcostserver
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+// dataSizeState tracks the storage used by a tenant, as last measured by the
+// node. It is protected by the tenantMetrics mutex.
+type dataSizeState struct {
+	// measured is set once a measurement was available.
+	measured   bool
+	liveBytes  int64
+	totalBytes int64
+	// limitExceeded is set if liveBytes exceeded the max_live_bytes capability
+	// of the tenant as of the last measurement.
+	limitExceeded bool
+}
+
+// updateDataSize picks up the last live and total bytes of the tenant measured
+// in the background, and checks them against the max_live_bytes capability. It
+// must be called with the tenantMetrics mutex held.
+func (s *instance) updateDataSize(
+	ctx context.Context, tenantID roachpb.TenantID, metrics tenantMetrics,
+) {
+	if s.dataSizeReader == nil {
+		return
+	}
+	liveBytes, totalBytes, found := s.dataSizeReader.GetDataSize(tenantID)
+	if !found {
+		return
+	}
+	state := metrics.dataSize
+	state.measured = true
+	state.liveBytes = liveBytes
+	state.totalBytes = totalBytes
+	metrics.liveBytes.Update(liveBytes)
+	metrics.totalBytes.Update(totalBytes)
+
+	var limit int64
+	if s.capabilities != nil {
+		if caps, found := s.capabilities.GetCapabilities(tenantID); found {
+			limit = tenantcapabilities.MustGetInt64ByID(caps, tenantcapabilities.MaxLiveBytes)
+		}
+	}
+	exceeded := limit > 0 && liveBytes > limit
+	if exceeded == state.limitExceeded {
+		return
+	}
+	state.limitExceeded = exceeded
+	if exceeded {
+		metrics.liveBytesLimitExceeded.Update(1)
+		log.StructuredEvent(ctx, &eventpb.TenantLiveBytesLimitExceeded{
+			TenantID:       tenantID.ToUint64(),
+			LiveBytes:      liveBytes,
+			LiveBytesLimit: limit,
+		})
+	} else {
+		metrics.liveBytesLimitExceeded.Update(0)
+		log.StructuredEvent(ctx, &eventpb.TenantLiveBytesLimitRestored{
+			TenantID:       tenantID.ToUint64(),
+			LiveBytes:      liveBytes,
+			LiveBytesLimit: limit,
+		})
+	}
+}
@@ -25,6 +25,9 @@ import (
 // NewInstance is exported for testing purposes.
 var NewInstance = newInstance
 
+// ReportInterval is exported for testing purposes.
+var ReportInterval = reportInterval
+
 func TestMain(m *testing.M) {
 	securityassets.SetLoader(securitytest.EmbeddedAssets)
 	randutil.SeedForTests()
@@ -25,19 +25,34 @@ import (
 // aggregated value for a metric is not useful (it sums up the consumption for
 // each tenant, as last reported to this node).
 type Metrics struct {
-	TotalRU                     *aggmetric.AggCounterFloat64
-	TotalKVRU                   *aggmetric.AggCounterFloat64
-	TotalReadBatches            *aggmetric.AggGauge
-	TotalReadRequests           *aggmetric.AggGauge
-	TotalReadBytes              *aggmetric.AggGauge
-	TotalWriteBatches           *aggmetric.AggGauge
-	TotalWriteRequests          *aggmetric.AggGauge
-	TotalWriteBytes             *aggmetric.AggGauge
-	TotalSQLPodsCPUSeconds      *aggmetric.AggGaugeFloat64
-	TotalPGWireEgressBytes      *aggmetric.AggGauge
-	TotalExternalIOEgressBytes  *aggmetric.AggGauge
-	TotalExternalIOIngressBytes *aggmetric.AggGauge
-	TotalCrossRegionNetworkRU   *aggmetric.AggCounterFloat64
+	TotalRU                                *aggmetric.AggCounterFloat64
+	TotalKVRU                              *aggmetric.AggCounterFloat64
+	TotalReadBatches                       *aggmetric.AggGauge
+	TotalReadRequests                      *aggmetric.AggGauge
+	TotalReadBytes                         *aggmetric.AggGauge
+	TotalWriteBatches                      *aggmetric.AggGauge
+	TotalWriteRequests                     *aggmetric.AggGauge
+	TotalWriteBytes                        *aggmetric.AggGauge
+	TotalSQLPodsCPUSeconds                 *aggmetric.AggGaugeFloat64
+	TotalPGWireEgressBytes                 *aggmetric.AggGauge
+	TotalExternalIOEgressBytes             *aggmetric.AggGauge
+	TotalExternalIOIngressBytes            *aggmetric.AggGauge
+	TotalCrossRegionNetworkRU              *aggmetric.AggCounterFloat64
+	TotalEstimatedCPUSeconds               *aggmetric.AggGaugeFloat64
+	TotalEstimatedKVCPUSeconds             *aggmetric.AggGaugeFloat64
+	TotalBackupRU                          *aggmetric.AggCounterFloat64
+	TotalSystemOverheadRU                  *aggmetric.AggCounterFloat64
+	MaxRequestsPerSecondPerNode            *aggmetric.AggGauge
+	MaxReadBytesPerSecondPerNode           *aggmetric.AggGauge
+	MaxWriteBytesPerSecondPerNode          *aggmetric.AggGauge
+	MaxBlockCacheFillBytesPerSecondPerNode *aggmetric.AggGauge
+	MaxSQLConnectionsPerInstance           *aggmetric.AggGauge
+	MaxLiveBytes                           *aggmetric.AggGauge
+	LiveBytes                              *aggmetric.AggGauge
+	TotalBytes                             *aggmetric.AggGauge
+	LiveBytesLimitExceeded                 *aggmetric.AggGauge
+	CostModel                              *aggmetric.AggGauge
+	ConsumptionAnomaly                     *aggmetric.AggGauge
 
 	mu struct {
 		syncutil.Mutex
@@ -132,49 +147,201 @@ var (
 		Measurement: "Request Units",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaTotalEstimatedCPUSeconds = metric.Metadata{
+		Name:        "tenant.consumption.estimated_cpu_seconds",
+		Help:        "Total estimated vCPU-seconds consumed by SQL pods and KV operations, for tenants billed by estimated CPU",
+		Measurement: "CPU Seconds",
+		Unit:        metric.Unit_SECONDS,
+	}
+	metaTotalEstimatedKVCPUSeconds = metric.Metadata{
+		Name:        "tenant.consumption.estimated_kv_cpu_seconds",
+		Help:        "Total estimated vCPU-seconds consumed by KV operations, for tenants billed by estimated CPU",
+		Measurement: "CPU Seconds",
+		Unit:        metric.Unit_SECONDS,
+	}
+	metaTotalBackupRU = metric.Metadata{
+		Name:        "tenant.consumption.backup_ru",
+		Help:        "Total number of RUs consumed by backups paced by the dedicated backup token bucket of the tenant",
+		Measurement: "Request Units",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaTotalSystemOverheadRU = metric.Metadata{
+		Name:        "tenant.consumption.system_overhead_ru",
+		Help:        "Total number of RUs consumed by mandatory system operations of the tenant, such as SQL liveness heartbeats, which are not charged to the tenant",
+		Measurement: "Request Units",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaMaxRequestsPerSecondPerNode = metric.Metadata{
+		Name:        "tenant.capabilities.max_requests_per_second_per_node",
+		Help:        "Limit on the rate of KV batch requests per node set by the max_requests_per_second_per_node capability (0 if unlimited)",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaMaxReadBytesPerSecondPerNode = metric.Metadata{
+		Name:        "tenant.capabilities.max_read_bytes_per_second_per_node",
+		Help:        "Limit on the rate of bytes read per node set by the max_read_bytes_per_second_per_node capability (0 if unlimited)",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaMaxWriteBytesPerSecondPerNode = metric.Metadata{
+		Name:        "tenant.capabilities.max_write_bytes_per_second_per_node",
+		Help:        "Limit on the rate of bytes written per node set by the max_write_bytes_per_second_per_node capability (0 if unlimited)",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaMaxBlockCacheFillBytesPerSecondPerNode = metric.Metadata{
+		Name:        "tenant.capabilities.max_block_cache_fill_bytes_per_second_per_node",
+		Help:        "Limit on the rate of bytes loaded into the block cache per node set by the max_block_cache_fill_bytes_per_second_per_node capability (0 if unlimited)",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaMaxSQLConnectionsPerInstance = metric.Metadata{
+		Name:        "tenant.capabilities.max_sql_connections_per_instance",
+		Help:        "Limit on the number of SQL connections per SQL instance set by the max_sql_connections_per_instance capability (0 if unlimited)",
+		Measurement: "Connections",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaMaxLiveBytes = metric.Metadata{
+		Name:        "tenant.capabilities.max_live_bytes",
+		Help:        "Limit on the live bytes set by the max_live_bytes capability (0 if unlimited)",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaLiveBytes = metric.Metadata{
+		Name:        "tenant.consumption.live_bytes",
+		Help:        "Logical live bytes of the tenant's data, as last measured",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaTotalBytes = metric.Metadata{
+		Name:        "tenant.consumption.total_bytes",
+		Help:        "Logical bytes of the tenant's data including non-live data, as last measured",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaLiveBytesLimitExceeded = metric.Metadata{
+		Name:        "tenant.live_bytes_limit_exceeded",
+		Help:        "Set to 1 if the live bytes exceed the max_live_bytes capability and writes are rejected",
+		Measurement: "Flag",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConsumptionAnomaly = metric.Metadata{
+		Name:        "tenant.consumption_anomaly_detected",
+		Help:        "1 if the consumption rate of the tenant exceeds its trailing baseline by more than tenant_cost_control.anomaly_detection.threshold standard deviations",
+		Measurement: "Anomaly",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaCostModel = metric.Metadata{
+		Name:        "tenant.capabilities.cost_model",
+		Help:        "Cost model under which the tenant is billed, set by the cost_model capability (0 for Request Units, 1 for estimated CPU)",
+		Measurement: "Cost Model",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 func (m *Metrics) init() {
 	b := aggmetric.MakeBuilder(multitenant.TenantIDLabel)
 	*m = Metrics{
-		TotalRU:                     b.CounterFloat64(metaTotalRU),
-		TotalKVRU:                   b.CounterFloat64(metaTotalKVRU),
-		TotalReadBatches:            b.Gauge(metaTotalReadBatches),
-		TotalReadRequests:           b.Gauge(metaTotalReadRequests),
-		TotalReadBytes:              b.Gauge(metaTotalReadBytes),
-		TotalWriteBatches:           b.Gauge(metaTotalWriteBatches),
-		TotalWriteRequests:          b.Gauge(metaTotalWriteRequests),
-		TotalWriteBytes:             b.Gauge(metaTotalWriteBytes),
-		TotalSQLPodsCPUSeconds:      b.GaugeFloat64(metaTotalSQLPodsCPUSeconds),
-		TotalPGWireEgressBytes:      b.Gauge(metaTotalPGWireEgressBytes),
-		TotalExternalIOEgressBytes:  b.Gauge(metaTotalExternalIOEgressBytes),
-		TotalExternalIOIngressBytes: b.Gauge(metaTotalExternalIOIngressBytes),
-		TotalCrossRegionNetworkRU:   b.CounterFloat64(metaTotalCrossRegionNetworkRU),
+		TotalRU:                                b.CounterFloat64(metaTotalRU),
+		TotalKVRU:                              b.CounterFloat64(metaTotalKVRU),
+		TotalReadBatches:                       b.Gauge(metaTotalReadBatches),
+		TotalReadRequests:                      b.Gauge(metaTotalReadRequests),
+		TotalReadBytes:                         b.Gauge(metaTotalReadBytes),
+		TotalWriteBatches:                      b.Gauge(metaTotalWriteBatches),
+		TotalWriteRequests:                     b.Gauge(metaTotalWriteRequests),
+		TotalWriteBytes:                        b.Gauge(metaTotalWriteBytes),
+		TotalSQLPodsCPUSeconds:                 b.GaugeFloat64(metaTotalSQLPodsCPUSeconds),
+		TotalPGWireEgressBytes:                 b.Gauge(metaTotalPGWireEgressBytes),
+		TotalExternalIOEgressBytes:             b.Gauge(metaTotalExternalIOEgressBytes),
+		TotalExternalIOIngressBytes:            b.Gauge(metaTotalExternalIOIngressBytes),
+		TotalCrossRegionNetworkRU:              b.CounterFloat64(metaTotalCrossRegionNetworkRU),
+		TotalEstimatedCPUSeconds:               b.GaugeFloat64(metaTotalEstimatedCPUSeconds),
+		TotalEstimatedKVCPUSeconds:             b.GaugeFloat64(metaTotalEstimatedKVCPUSeconds),
+		TotalBackupRU:                          b.CounterFloat64(metaTotalBackupRU),
+		TotalSystemOverheadRU:                  b.CounterFloat64(metaTotalSystemOverheadRU),
+		MaxRequestsPerSecondPerNode:            b.Gauge(metaMaxRequestsPerSecondPerNode),
+		MaxReadBytesPerSecondPerNode:           b.Gauge(metaMaxReadBytesPerSecondPerNode),
+		MaxWriteBytesPerSecondPerNode:          b.Gauge(metaMaxWriteBytesPerSecondPerNode),
+		MaxBlockCacheFillBytesPerSecondPerNode: b.Gauge(metaMaxBlockCacheFillBytesPerSecondPerNode),
+		MaxSQLConnectionsPerInstance:           b.Gauge(metaMaxSQLConnectionsPerInstance),
+		MaxLiveBytes:                           b.Gauge(metaMaxLiveBytes),
+		LiveBytes:                              b.Gauge(metaLiveBytes),
+		TotalBytes:                             b.Gauge(metaTotalBytes),
+		LiveBytesLimitExceeded:                 b.Gauge(metaLiveBytesLimitExceeded),
+		CostModel:                              b.Gauge(metaCostModel),
+		ConsumptionAnomaly:                     b.Gauge(metaConsumptionAnomaly),
 	}
 	m.mu.tenantMetrics = make(map[roachpb.TenantID]tenantMetrics)
 }
 
 // tenantMetrics represent metrics for an individual tenant.
 type tenantMetrics struct {
-	totalRU                     *aggmetric.CounterFloat64
-	totalKVRU                   *aggmetric.CounterFloat64
-	totalReadBatches            *aggmetric.Gauge
-	totalReadRequests           *aggmetric.Gauge
-	totalReadBytes              *aggmetric.Gauge
-	totalWriteBatches           *aggmetric.Gauge
-	totalWriteRequests          *aggmetric.Gauge
-	totalWriteBytes             *aggmetric.Gauge
-	totalSQLPodsCPUSeconds      *aggmetric.GaugeFloat64
-	totalPGWireEgressBytes      *aggmetric.Gauge
-	totalExternalIOEgressBytes  *aggmetric.Gauge
-	totalExternalIOIngressBytes *aggmetric.Gauge
-	totalCrossRegionNetworkRU   *aggmetric.CounterFloat64
+	totalRU                                *aggmetric.CounterFloat64
+	totalKVRU                              *aggmetric.CounterFloat64
+	totalReadBatches                       *aggmetric.Gauge
+	totalReadRequests                      *aggmetric.Gauge
+	totalReadBytes                         *aggmetric.Gauge
+	totalWriteBatches                      *aggmetric.Gauge
+	totalWriteRequests                     *aggmetric.Gauge
+	totalWriteBytes                        *aggmetric.Gauge
+	totalSQLPodsCPUSeconds                 *aggmetric.GaugeFloat64
+	totalPGWireEgressBytes                 *aggmetric.Gauge
+	totalExternalIOEgressBytes             *aggmetric.Gauge
+	totalExternalIOIngressBytes            *aggmetric.Gauge
+	totalCrossRegionNetworkRU              *aggmetric.CounterFloat64
+	totalEstimatedCPUSeconds               *aggmetric.GaugeFloat64
+	totalEstimatedKVCPUSeconds             *aggmetric.GaugeFloat64
+	totalBackupRU                          *aggmetric.CounterFloat64
+	totalSystemOverheadRU                  *aggmetric.CounterFloat64
+	maxRequestsPerSecondPerNode            *aggmetric.Gauge
+	maxReadBytesPerSecondPerNode           *aggmetric.Gauge
+	maxWriteBytesPerSecondPerNode          *aggmetric.Gauge
+	maxBlockCacheFillBytesPerSecondPerNode *aggmetric.Gauge
+	maxSQLConnectionsPerInstance           *aggmetric.Gauge
+	maxLiveBytes                           *aggmetric.Gauge
+	liveBytes                              *aggmetric.Gauge
+	totalBytes                             *aggmetric.Gauge
+	liveBytesLimitExceeded                 *aggmetric.Gauge
+	costModel                              *aggmetric.Gauge
+	consumptionAnomaly                     *aggmetric.Gauge
+
+	// usage tracks the recent consumption rate and throttling events of the
+	// tenant. It is protected by mutex.
+	usage *usageStats
+
+	// dataSize tracks the storage used by the tenant. It is protected by mutex.
+	dataSize *dataSizeState
+
+	// anomaly tracks the baseline of the consumption rate of the tenant. It is
+	// protected by mutex.
+	anomaly *anomalyDetector
 
 	// Mutex is used to atomically update metrics together with a corresponding
 	// change to the system table.
 	mutex *syncutil.Mutex
 }
 
+// lookupTenantMetrics returns the metrics for a tenant, if the tenant has sent
+// TokenBucketRequests to this node.
+func (m *Metrics) lookupTenantMetrics(tenantID roachpb.TenantID) (tenantMetrics, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tm, ok := m.mu.tenantMetrics[tenantID]
+	return tm, ok
+}
+
+// allTenantMetrics returns the metrics for all tenants that have sent
+// TokenBucketRequests to this node.
+func (m *Metrics) allTenantMetrics() map[roachpb.TenantID]tenantMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := make(map[roachpb.TenantID]tenantMetrics, len(m.mu.tenantMetrics))
+	for tenantID, tm := range m.mu.tenantMetrics {
+		res[tenantID] = tm
+	}
+	return res
+}
+
 // getTenantMetrics returns the metrics for a tenant.
 func (m *Metrics) getTenantMetrics(tenantID roachpb.TenantID) tenantMetrics {
 	m.mu.Lock()
@@ -183,20 +350,38 @@ func (m *Metrics) getTenantMetrics(tenantID roachpb.TenantID) tenantMetrics {
 	if !ok {
 		tid := tenantID.String()
 		tm = tenantMetrics{
-			totalRU:                     m.TotalRU.AddChild(tid),
-			totalKVRU:                   m.TotalKVRU.AddChild(tid),
-			totalReadBatches:            m.TotalReadBatches.AddChild(tid),
-			totalReadRequests:           m.TotalReadRequests.AddChild(tid),
-			totalReadBytes:              m.TotalReadBytes.AddChild(tid),
-			totalWriteBatches:           m.TotalWriteBatches.AddChild(tid),
-			totalWriteRequests:          m.TotalWriteRequests.AddChild(tid),
-			totalWriteBytes:             m.TotalWriteBytes.AddChild(tid),
-			totalSQLPodsCPUSeconds:      m.TotalSQLPodsCPUSeconds.AddChild(tid),
-			totalPGWireEgressBytes:      m.TotalPGWireEgressBytes.AddChild(tid),
-			totalExternalIOEgressBytes:  m.TotalExternalIOEgressBytes.AddChild(tid),
-			totalExternalIOIngressBytes: m.TotalExternalIOIngressBytes.AddChild(tid),
-			totalCrossRegionNetworkRU:   m.TotalCrossRegionNetworkRU.AddChild(tid),
-			mutex:                       &syncutil.Mutex{},
+			totalRU:                                m.TotalRU.AddChild(tid),
+			totalKVRU:                              m.TotalKVRU.AddChild(tid),
+			totalReadBatches:                       m.TotalReadBatches.AddChild(tid),
+			totalReadRequests:                      m.TotalReadRequests.AddChild(tid),
+			totalReadBytes:                         m.TotalReadBytes.AddChild(tid),
+			totalWriteBatches:                      m.TotalWriteBatches.AddChild(tid),
+			totalWriteRequests:                     m.TotalWriteRequests.AddChild(tid),
+			totalWriteBytes:                        m.TotalWriteBytes.AddChild(tid),
+			totalSQLPodsCPUSeconds:                 m.TotalSQLPodsCPUSeconds.AddChild(tid),
+			totalPGWireEgressBytes:                 m.TotalPGWireEgressBytes.AddChild(tid),
+			totalExternalIOEgressBytes:             m.TotalExternalIOEgressBytes.AddChild(tid),
+			totalExternalIOIngressBytes:            m.TotalExternalIOIngressBytes.AddChild(tid),
+			totalCrossRegionNetworkRU:              m.TotalCrossRegionNetworkRU.AddChild(tid),
+			totalEstimatedCPUSeconds:               m.TotalEstimatedCPUSeconds.AddChild(tid),
+			totalEstimatedKVCPUSeconds:             m.TotalEstimatedKVCPUSeconds.AddChild(tid),
+			totalBackupRU:                          m.TotalBackupRU.AddChild(tid),
+			totalSystemOverheadRU:                  m.TotalSystemOverheadRU.AddChild(tid),
+			maxRequestsPerSecondPerNode:            m.MaxRequestsPerSecondPerNode.AddChild(tid),
+			maxReadBytesPerSecondPerNode:           m.MaxReadBytesPerSecondPerNode.AddChild(tid),
+			maxWriteBytesPerSecondPerNode:          m.MaxWriteBytesPerSecondPerNode.AddChild(tid),
+			maxBlockCacheFillBytesPerSecondPerNode: m.MaxBlockCacheFillBytesPerSecondPerNode.AddChild(tid),
+			maxSQLConnectionsPerInstance:           m.MaxSQLConnectionsPerInstance.AddChild(tid),
+			maxLiveBytes:                           m.MaxLiveBytes.AddChild(tid),
+			liveBytes:                              m.LiveBytes.AddChild(tid),
+			totalBytes:                             m.TotalBytes.AddChild(tid),
+			liveBytesLimitExceeded:                 m.LiveBytesLimitExceeded.AddChild(tid),
+			costModel:                              m.CostModel.AddChild(tid),
+			consumptionAnomaly:                     m.ConsumptionAnomaly.AddChild(tid),
+			usage:                                  &usageStats{},
+			dataSize:                               &dataSizeState{},
+			anomaly:                                &anomalyDetector{},
+			mutex:                                  &syncutil.Mutex{},
 		}
 		m.mu.tenantMetrics[tenantID] = tm
 	}
@@ -12,7 +12,9 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -27,6 +29,14 @@ type instance struct {
 	metrics    Metrics
 	timeSource timeutil.TimeSource
 	settings   *cluster.Settings
+
+	// capabilities is used to surface the limits configured for each tenant in
+	// the metrics and to enforce the max_live_bytes capability. It may be nil.
+	capabilities tenantcapabilities.Reader
+
+	// dataSizeReader provides the storage used by each tenant, as measured in
+	// the background. It may be nil, in which case the storage is not metered.
+	dataSizeReader multitenant.TenantDataSizeReader
 }
 
 // Note: the "four" in the description comes from
@@ -42,14 +52,38 @@ var instanceInactivity = settings.RegisterDurationSetting(
 	settings.WithName("tenant_cost_control.instance_inactivity.timeout"),
 )
 
+// reportInterval is pushed to the SQL instances of tenants that support
+// kvpb.TokenBucketCapabilityReportInterval.
+var reportInterval = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"tenant_cost_control.report_interval",
+	"if positive, the interval at which the SQL instances of virtual clusters send token bucket "+
+		"requests, overriding their tenant_cost_control_period setting; only applies to "+
+		"instances that support it",
+	0,
+	settings.DurationWithMinimumOrZeroDisable(5*time.Second),
+)
+
+// supportedCapabilities are the optional features of the TokenBucket protocol
+// supported by the host.
+const supportedCapabilities = kvpb.TokenBucketCapabilityUnacknowledgedConsumption |
+	kvpb.TokenBucketCapabilityReportInterval
+
 func newInstance(
-	settings *cluster.Settings, db *kv.DB, ief isql.DB, timeSource timeutil.TimeSource,
+	settings *cluster.Settings,
+	db *kv.DB,
+	ief isql.DB,
+	capabilities tenantcapabilities.Reader,
+	dataSizeReader multitenant.TenantDataSizeReader,
+	timeSource timeutil.TimeSource,
 ) *instance {
 	res := &instance{
-		db:         db,
-		ief:        ief,
-		timeSource: timeSource,
-		settings:   settings,
+		db:             db,
+		ief:            ief,
+		timeSource:     timeSource,
+		settings:       settings,
+		capabilities:   capabilities,
+		dataSizeReader: dataSizeReader,
 	}
 	res.metrics.init()
 	return res
@@ -67,7 +101,11 @@ func init() {
 		settings *cluster.Settings,
 		db *kv.DB,
 		ief isql.DB,
+		capabilities tenantcapabilities.Reader,
+		dataSizeReader multitenant.TenantDataSizeReader,
 	) multitenant.TenantUsageServer {
-		return newInstance(settings, db, ief, timeutil.DefaultTimeSource{})
+		return newInstance(
+			settings, db, ief, capabilities, dataSizeReader, timeutil.DefaultTimeSource{},
+		)
 	}
 }
@@ -15,6 +15,7 @@ import (
 	"math/rand"
 	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +24,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities/tenantcapabilitiespb"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -71,6 +75,55 @@ type testState struct {
 	tenantUsage multitenant.TenantUsageServer
 	metricsReg  *metric.Registry
 	autoSeqNum  int64
+
+	// dataSize and maxLiveBytes are used to mock the storage used by each
+	// tenant and its max_live_bytes capability.
+	dataSize     map[roachpb.TenantID][2]int64
+	maxLiveBytes map[roachpb.TenantID]int64
+	// costModel is used to mock the cost_model capability of each tenant.
+	costModel map[roachpb.TenantID]int64
+}
+
+// testCapabilitiesReader is a tenantcapabilities.Reader that only returns the
+// max_live_bytes and cost_model capabilities.
+type testCapabilitiesReader struct {
+	ts *testState
+}
+
+var _ tenantcapabilities.Reader = testCapabilitiesReader{}
+
+func (r testCapabilitiesReader) GetInfo(
+	id roachpb.TenantID,
+) (_ tenantcapabilities.Entry, _ <-chan struct{}, found bool) {
+	return tenantcapabilities.Entry{}, nil, false
+}
+
+func (r testCapabilitiesReader) GetCapabilities(
+	id roachpb.TenantID,
+) (_ *tenantcapabilitiespb.TenantCapabilities, found bool) {
+	return &tenantcapabilitiespb.TenantCapabilities{
+		MaxLiveBytes: r.ts.maxLiveBytes[id],
+		CostModel:    r.ts.costModel[id],
+	}, true
+}
+
+func (r testCapabilitiesReader) GetGlobalCapabilityState() map[roachpb.TenantID]*tenantcapabilitiespb.TenantCapabilities {
+	return nil
+}
+
+// testDataSizeReader is a multitenant.TenantDataSizeReader that returns the
+// data size set with the data-size command.
+type testDataSizeReader struct {
+	ts *testState
+}
+
+var _ multitenant.TenantDataSizeReader = testDataSizeReader{}
+
+func (r testDataSizeReader) GetDataSize(
+	id roachpb.TenantID,
+) (liveBytes, totalBytes int64, found bool) {
+	size, found := r.ts.dataSize[id]
+	return size[0], size[1], found
 }
 
 const timeFormat = "15:04:05.000"
@@ -86,10 +139,15 @@ func (ts *testState) start(t *testing.T) {
 	ts.r = sqlutils.MakeSQLRunner(ts.db)
 
 	ts.clock = timeutil.NewManualTime(t0)
+	ts.dataSize = make(map[roachpb.TenantID][2]int64)
+	ts.maxLiveBytes = make(map[roachpb.TenantID]int64)
+	ts.costModel = make(map[roachpb.TenantID]int64)
 	ts.tenantUsage = tenantcostserver.NewInstance(
 		ts.s.ClusterSettings(),
 		ts.kvDB,
 		ts.s.InternalDB().(isql.DB),
+		testCapabilitiesReader{ts: ts},
+		testDataSizeReader{ts: ts},
 		ts.clock,
 	)
 	ts.metricsReg = metric.NewRegistry()
@@ -105,13 +163,19 @@ func (ts *testState) formatTime(tm time.Time) string {
 }
 
 var testStateCommands = map[string]func(*testState, *testing.T, *datadriven.TestData) string{
-	"create-tenant":        (*testState).createTenant,
-	"token-bucket-request": (*testState).tokenBucketRequest,
-	"metrics":              (*testState).metrics,
-	"configure":            (*testState).configure,
-	"inspect":              (*testState).inspect,
-	"wait-inspect":         (*testState).waitInspect,
-	"advance":              (*testState).advance,
+	"create-tenant":         (*testState).createTenant,
+	"token-bucket-request":  (*testState).tokenBucketRequest,
+	"metrics":               (*testState).metrics,
+	"configure":             (*testState).configure,
+	"grant-burst":           (*testState).grantBurst,
+	"inspect":               (*testState).inspect,
+	"wait-inspect":          (*testState).waitInspect,
+	"advance":               (*testState).advance,
+	"data-size":             (*testState).setDataSize,
+	"max-live-bytes":        (*testState).setMaxLiveBytes,
+	"cost-model":            (*testState).setCostModel,
+	"report-interval":       (*testState).setReportInterval,
+	"consumption-summaries": (*testState).consumptionSummaries,
 }
 
 func (ts *testState) tenantID(t *testing.T, d *datadriven.TestData) uint64 {
@@ -145,23 +209,17 @@ func (ts *testState) tokenBucketRequest(t *testing.T, d *datadriven.TestData) st
 		InstanceLease      string `yaml:"instance_lease"`
 		NextLiveInstanceID uint32 `yaml:"next_live_instance_id"`
 		SeqNum             int64  `yaml:"seq_num"`
-		Consumption        struct {
-			RU                     float64 `yaml:"ru"`
-			KVRU                   float64 `yaml:"kvru"`
-			ReadBatches            uint64  `yaml:"read_batches"`
-			ReadReq                uint64  `yaml:"read_req"`
-			ReadBytes              uint64  `yaml:"read_bytes"`
-			WriteBatches           uint64  `yaml:"write_batches"`
-			WriteReq               uint64  `yaml:"write_req"`
-			WriteBytes             uint64  `yaml:"write_bytes"`
-			SQLPodsCPUUsage        float64 `yaml:"sql_pods_cpu_usage"`
-			PGWireEgressBytes      uint64  `yaml:"pgwire_egress_bytes"`
-			ExternalIOIngressBytes uint64  `yaml:"external_io_ingress_bytes"`
-			ExternalIOEgressBytes  uint64  `yaml:"external_io_egress_bytes"`
-			CrossRegionNetworkRU   float64 `yaml:"cross_region_network_ru"`
+		Consumption        consumptionArgs
+		RU                 float64 `yaml:"ru"`
+		Period             string  `yaml:"period"`
+		// ProtocolVersion, Capabilities and Unacknowledged are only used with
+		// TokenBucketProtocolV2.
+		ProtocolVersion string   `yaml:"protocol_version"`
+		Capabilities    []string `yaml:"capabilities"`
+		Unacknowledged  []struct {
+			SeqNum      int64 `yaml:"seq_num"`
+			Consumption consumptionArgs
 		}
-		RU     float64 `yaml:"ru"`
-		Period string  `yaml:"period"`
 	}
 	args.SeqNum = -1
 	args.Period = "10s"
@@ -179,28 +237,36 @@ func (ts *testState) tokenBucketRequest(t *testing.T, d *datadriven.TestData) st
 		d.Fatalf(t, "failed to parse duration: %v", args.Period)
 	}
 	req := kvpb.TokenBucketRequest{
-		TenantID:           tenantID,
-		InstanceID:         args.InstanceID,
-		InstanceLease:      []byte(args.InstanceLease),
-		NextLiveInstanceID: args.NextLiveInstanceID,
-		SeqNum:             args.SeqNum,
-		ConsumptionSinceLastRequest: kvpb.TenantConsumption{
-			RU:                     args.Consumption.RU,
-			KVRU:                   args.Consumption.KVRU,
-			ReadBatches:            args.Consumption.ReadBatches,
-			ReadRequests:           args.Consumption.ReadReq,
-			ReadBytes:              args.Consumption.ReadBytes,
-			WriteBatches:           args.Consumption.WriteBatches,
-			WriteRequests:          args.Consumption.WriteReq,
-			WriteBytes:             args.Consumption.WriteBytes,
-			SQLPodsCPUSeconds:      args.Consumption.SQLPodsCPUUsage,
-			PGWireEgressBytes:      args.Consumption.PGWireEgressBytes,
-			ExternalIOIngressBytes: args.Consumption.ExternalIOIngressBytes,
-			ExternalIOEgressBytes:  args.Consumption.ExternalIOEgressBytes,
-			CrossRegionNetworkRU:   args.Consumption.CrossRegionNetworkRU,
-		},
-		RequestedRU:         args.RU,
-		TargetRequestPeriod: period,
+		TenantID:                    tenantID,
+		InstanceID:                  args.InstanceID,
+		InstanceLease:               []byte(args.InstanceLease),
+		NextLiveInstanceID:          args.NextLiveInstanceID,
+		SeqNum:                      args.SeqNum,
+		ConsumptionSinceLastRequest: args.Consumption.toProto(),
+		RequestedRU:                 args.RU,
+		TargetRequestPeriod:         period,
+	}
+	if args.ProtocolVersion != "" {
+		for req.ProtocolVersion.String() != args.ProtocolVersion {
+			if req.ProtocolVersion == kvpb.TokenBucketProtocolLatest {
+				d.Fatalf(t, "unknown protocol version %q", args.ProtocolVersion)
+			}
+			req.ProtocolVersion++
+		}
+	}
+	for _, c := range args.Capabilities {
+		switch c {
+		case "unacknowledged_consumption":
+			req.Capabilities |= kvpb.TokenBucketCapabilityUnacknowledgedConsumption
+		case "report_interval":
+			req.Capabilities |= kvpb.TokenBucketCapabilityReportInterval
+		default:
+			d.Fatalf(t, "unknown capability %q", c)
+		}
+	}
+	for _, u := range args.Unacknowledged {
+		req.UnacknowledgedConsumption = append(req.UnacknowledgedConsumption,
+			kvpb.TenantConsumptionDelta{SeqNum: u.SeqNum, Consumption: u.Consumption.toProto()})
 	}
 	res := ts.tenantUsage.TokenBucketRequest(
 		context.Background(), roachpb.MustMakeTenantID(tenantID), &req,
@@ -208,20 +274,93 @@ func (ts *testState) tokenBucketRequest(t *testing.T, d *datadriven.TestData) st
 	if res.Error != (errors.EncodedError{}) {
 		return fmt.Sprintf("error: %v", errors.DecodeError(context.Background(), res.Error))
 	}
+	var buf strings.Builder
 	if res.GrantedRU == 0 {
 		if res.TrickleDuration != 0 {
 			d.Fatalf(t, "trickle duration set with 0 granted RUs")
 		}
-		return ""
+	} else {
+		trickleStr := "immediately"
+		if res.TrickleDuration != 0 {
+			trickleStr = fmt.Sprintf("over %s", res.TrickleDuration)
+		}
+		fmt.Fprintf(&buf,
+			"%.10g RUs granted %s. Fallback rate: %.10g RU/s\n",
+			res.GrantedRU, trickleStr, res.FallbackRate,
+		)
+	}
+	if res.LiveBytesLimitExceeded {
+		buf.WriteString("Live bytes limit exceeded\n")
+	}
+	if model := tenantcostmodel.ModelVersion(res.CostModel); model != tenantcostmodel.RequestUnitModel {
+		fmt.Fprintf(&buf, "Cost model: %s\n", model)
+	}
+	if res.ProtocolVersion != kvpb.TokenBucketProtocolV1 {
+		fmt.Fprintf(&buf, "Protocol version: %s\n", res.ProtocolVersion)
+	}
+	if res.Capabilities.Has(kvpb.TokenBucketCapabilityUnacknowledgedConsumption) {
+		buf.WriteString("Accounts for unacknowledged consumption\n")
+	}
+	if res.Capabilities.Has(kvpb.TokenBucketCapabilityReportInterval) {
+		fmt.Fprintf(&buf, "Report interval: %s\n", res.ReportInterval)
+	}
+	return buf.String()
+}
+
+// consumptionArgs is the YAML representation of the consumption reported by a
+// token bucket request.
+type consumptionArgs struct {
+	RU                     float64 `yaml:"ru"`
+	KVRU                   float64 `yaml:"kvru"`
+	ReadBatches            uint64  `yaml:"read_batches"`
+	ReadReq                uint64  `yaml:"read_req"`
+	ReadBytes              uint64  `yaml:"read_bytes"`
+	WriteBatches           uint64  `yaml:"write_batches"`
+	WriteReq               uint64  `yaml:"write_req"`
+	WriteBytes             uint64  `yaml:"write_bytes"`
+	SQLPodsCPUUsage        float64 `yaml:"sql_pods_cpu_usage"`
+	PGWireEgressBytes      uint64  `yaml:"pgwire_egress_bytes"`
+	ExternalIOIngressBytes uint64  `yaml:"external_io_ingress_bytes"`
+	ExternalIOEgressBytes  uint64  `yaml:"external_io_egress_bytes"`
+	CrossRegionNetworkRU   float64 `yaml:"cross_region_network_ru"`
+	EstimatedCPUSeconds    float64 `yaml:"estimated_cpu_seconds"`
+	EstimatedKVCPUSeconds  float64 `yaml:"estimated_kv_cpu_seconds"`
+	BackupRU               float64 `yaml:"backup_ru"`
+	SystemOverheadRU       float64 `yaml:"system_overhead_ru"`
+}
+
+func (c *consumptionArgs) toProto() kvpb.TenantConsumption {
+	return kvpb.TenantConsumption{
+		RU:                     c.RU,
+		KVRU:                   c.KVRU,
+		ReadBatches:            c.ReadBatches,
+		ReadRequests:           c.ReadReq,
+		ReadBytes:              c.ReadBytes,
+		WriteBatches:           c.WriteBatches,
+		WriteRequests:          c.WriteReq,
+		WriteBytes:             c.WriteBytes,
+		SQLPodsCPUSeconds:      c.SQLPodsCPUUsage,
+		PGWireEgressBytes:      c.PGWireEgressBytes,
+		ExternalIOIngressBytes: c.ExternalIOIngressBytes,
+		ExternalIOEgressBytes:  c.ExternalIOEgressBytes,
+		CrossRegionNetworkRU:   c.CrossRegionNetworkRU,
+		EstimatedCPUSeconds:    c.EstimatedCPUSeconds,
+		EstimatedKVCPUSeconds:  c.EstimatedKVCPUSeconds,
+		BackupRU:               c.BackupRU,
+		SystemOverheadRU:       c.SystemOverheadRU,
 	}
-	trickleStr := "immediately"
-	if res.TrickleDuration != 0 {
-		trickleStr = fmt.Sprintf("over %s", res.TrickleDuration)
+}
+
+// setReportInterval overrides the report interval pushed to the instances.
+func (ts *testState) setReportInterval(t *testing.T, d *datadriven.TestData) string {
+	interval, err := time.ParseDuration(d.Input)
+	if err != nil {
+		d.Fatalf(t, "failed to parse duration: %v", d.Input)
 	}
-	return fmt.Sprintf(
-		"%.10g RUs granted %s. Fallback rate: %.10g RU/s\n",
-		res.GrantedRU, trickleStr, res.FallbackRate,
+	tenantcostserver.ReportInterval.Override(
+		context.Background(), &ts.s.ClusterSettings().SV, interval,
 	)
+	return ""
 }
 
 // metrics outputs all metrics that match the regex in the input.
@@ -270,6 +409,35 @@ func (ts *testState) configure(t *testing.T, d *datadriven.TestData) string {
 	return ""
 }
 
+// grantBurst grants a burst to a tenant (specified in a tenant=X argument). A
+// zero rate revokes the burst.
+func (ts *testState) grantBurst(t *testing.T, d *datadriven.TestData) string {
+	tenantID := ts.tenantID(t, d)
+	var args struct {
+		Rate     float64 `yaml:"rate"`
+		Duration string  `yaml:"duration"`
+	}
+	args.Duration = "0s"
+	if err := yaml.UnmarshalStrict([]byte(d.Input), &args); err != nil {
+		d.Fatalf(t, "failed to parse request yaml: %v", err)
+	}
+	duration, err := time.ParseDuration(args.Duration)
+	if err != nil {
+		d.Fatalf(t, "failed to parse duration: %v", args.Duration)
+	}
+	db := ts.s.InternalDB().(isql.DB)
+	if err := db.Txn(context.Background(), func(
+		ctx context.Context, txn isql.Txn,
+	) error {
+		return ts.tenantUsage.GrantBurst(
+			ctx, txn, roachpb.MustMakeTenantID(tenantID), args.Rate, duration,
+		)
+	}); err != nil {
+		d.Fatalf(t, "grant burst error: %v", err)
+	}
+	return ""
+}
+
 // inspect shows all the metadata for a tenant (specified in a tenant=X
 // argument), in a user-friendly format.
 func (ts *testState) inspect(t *testing.T, d *datadriven.TestData) (res string) {
@@ -323,6 +491,63 @@ func (ts *testState) advance(t *testing.T, d *datadriven.TestData) string {
 	return ts.formatTime(ts.clock.Now())
 }
 
+// setDataSize sets the live and total bytes last measured for a tenant
+// (specified in a tenant=X argument). The input is a yaml for the struct below.
+func (ts *testState) setDataSize(t *testing.T, d *datadriven.TestData) string {
+	tenantID := roachpb.MustMakeTenantID(ts.tenantID(t, d))
+	var args struct {
+		LiveBytes  int64 `yaml:"live_bytes"`
+		TotalBytes int64 `yaml:"total_bytes"`
+	}
+	if err := yaml.UnmarshalStrict([]byte(d.Input), &args); err != nil {
+		d.Fatalf(t, "failed to parse request yaml: %v", err)
+	}
+	ts.dataSize[tenantID] = [2]int64{args.LiveBytes, args.TotalBytes}
+	return ""
+}
+
+// setMaxLiveBytes sets the max_live_bytes capability of a tenant (specified in
+// a tenant=X argument). The input is the limit.
+func (ts *testState) setMaxLiveBytes(t *testing.T, d *datadriven.TestData) string {
+	tenantID := roachpb.MustMakeTenantID(ts.tenantID(t, d))
+	limit, err := strconv.ParseInt(strings.TrimSpace(d.Input), 10, 64)
+	if err != nil {
+		d.Fatalf(t, "failed to parse limit: %v", err)
+	}
+	ts.maxLiveBytes[tenantID] = limit
+	return ""
+}
+
+// setCostModel sets the cost_model capability of a tenant (specified in a
+// tenant=X argument). The input is the cost model version.
+func (ts *testState) setCostModel(t *testing.T, d *datadriven.TestData) string {
+	tenantID := roachpb.MustMakeTenantID(ts.tenantID(t, d))
+	model, err := strconv.ParseInt(strings.TrimSpace(d.Input), 10, 64)
+	if err != nil {
+		d.Fatalf(t, "failed to parse cost model: %v", err)
+	}
+	ts.costModel[tenantID] = model
+	return ""
+}
+
+// consumptionSummaries outputs the consumption summaries of all tenants over
+// the window specified in the input.
+func (ts *testState) consumptionSummaries(t *testing.T, d *datadriven.TestData) string {
+	window, err := time.ParseDuration(strings.TrimSpace(d.Input))
+	if err != nil {
+		d.Fatalf(t, "failed to parse input as duration: %v", err)
+	}
+	var buf strings.Builder
+	for _, s := range ts.tenantUsage.GetConsumptionSummaries(window) {
+		fmt.Fprintf(&buf, "tenant %s: %s - %s  ru=%g-%g  cpu=%g-%g  live-bytes=%d\n",
+			s.TenantID, ts.formatTime(s.Start.Time), ts.formatTime(s.End.Time),
+			s.Start.RU, s.End.RU, s.Start.SQLPodsCPUSeconds, s.End.SQLPodsCPUSeconds,
+			s.End.LiveBytes,
+		)
+	}
+	return buf.String()
+}
+
 // TestInstanceCleanup is a randomized test that verifies that the server keeps
 // up with a changing live set.
 func TestInstanceCleanup(t *testing.T) {
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -63,7 +64,8 @@ const maxInstancesCleanup = 10
 
 // update accounts for the passing of time since LastUpdate.
 // If the tenantState is not initialized (Present=false), it is initialized now.
-func (ts *tenantState) update(now time.Time) {
+// Returns the rate of the boost that expired in the meantime, or 0 if none did.
+func (ts *tenantState) update(now time.Time) (expiredBoostRate float64) {
 	if !ts.Present {
 		*ts = tenantState{
 			Present:       true,
@@ -74,15 +76,30 @@ func (ts *tenantState) update(now time.Time) {
 				RUCurrent:    defaultInitialRUs,
 			},
 		}
-		return
+		return 0
 	}
 	delta := now.Sub(ts.LastUpdate.Time)
 	if delta > 0 {
 		// Make sure we never push back LastUpdate, or we'd refill tokens for the
 		// same period multiple times.
-		ts.Bucket.Update(delta)
+		boostRate := ts.Bucket.RUBoostRate
+		if ts.Bucket.Update(delta) {
+			expiredBoostRate = boostRate
+		}
 		ts.LastUpdate.Time = now
 	}
+	return expiredBoostRate
+}
+
+// boostColumns returns the values of the instance_seq and instance_shares
+// columns of the per-tenant row, to which the boost of the bucket is mapped.
+// They are NULL if there is no boost.
+func (ts *tenantState) boostColumns() (expiration, rate tree.Datum) {
+	if ts.Bucket.RUBoostRate == 0 {
+		return tree.DNull, tree.DNull
+	}
+	expiration = tree.NewDInt(tree.DInt(ts.LastUpdate.Add(ts.Bucket.BoostRemaining).UnixNano()))
+	return expiration, tree.NewDFloat(tree.DFloat(ts.Bucket.RUBoostRate))
 }
 
 type instanceState struct {
@@ -172,25 +189,8 @@ func (h *sysTableHelper) readTenantAndInstanceState(
 		instanceID := base.SQLInstanceID(tree.MustBeDInt(r[0]))
 		if instanceID == 0 {
 			// Tenant state.
-			// NOTE: The current_share_sum column is mapped to the RUCurrentAvg
-			// field.
-			tenant.Present = true
-			tenant.LastUpdate = tree.MustBeDTimestamp(r[2])
-			tenant.FirstInstance = base.SQLInstanceID(tree.MustBeDInt(r[1]))
-			tenant.Bucket = tenanttokenbucket.State{
-				RUBurstLimit: float64(tree.MustBeDFloat(r[3])),
-				RURefillRate: float64(tree.MustBeDFloat(r[4])),
-				RUCurrent:    float64(tree.MustBeDFloat(r[5])),
-				RUCurrentAvg: float64(tree.MustBeDFloat(r[6])),
-			}
-			if consumption := r[7]; consumption != tree.DNull {
-				// total_consumption can be NULL because of an upgrade of the
-				// tenant_usage table.
-				if err := protoutil.Unmarshal(
-					[]byte(tree.MustBeDBytes(consumption)), &tenant.Consumption,
-				); err != nil {
-					return tenantState{}, instanceState{}, err
-				}
+			if tenant, err = tenantStateFromRow(r); err != nil {
+				return tenantState{}, instanceState{}, err
 			}
 		} else {
 			// Instance state.
@@ -206,15 +206,97 @@ func (h *sysTableHelper) readTenantAndInstanceState(
 	return tenant, instance, nil
 }
 
+// tenantStateFromRow decodes the per-tenant row (instance_id = 0) of the
+// tenant_usage table, whose first 11 columns are those selected by
+// readTenantAndInstanceState.
+func tenantStateFromRow(r tree.Datums) (tenantState, error) {
+	// NOTE: The current_share_sum column is mapped to the RUCurrentAvg field.
+	tenant := tenantState{
+		Present:       true,
+		LastUpdate:    tree.MustBeDTimestamp(r[2]),
+		FirstInstance: base.SQLInstanceID(tree.MustBeDInt(r[1])),
+		Bucket: tenanttokenbucket.State{
+			RUBurstLimit: float64(tree.MustBeDFloat(r[3])),
+			RURefillRate: float64(tree.MustBeDFloat(r[4])),
+			RUCurrent:    float64(tree.MustBeDFloat(r[5])),
+			RUCurrentAvg: float64(tree.MustBeDFloat(r[6])),
+		},
+	}
+	// NOTE: The instance_shares and instance_seq columns are mapped to the
+	// boost rate and expiration time (in nanoseconds since the epoch); they are
+	// NULL if there is no boost.
+	if r[10] != tree.DNull {
+		expiration := timeutil.Unix(0, int64(tree.MustBeDInt(r[9])))
+		tenant.Bucket.RUBoostRate = float64(tree.MustBeDFloat(r[10]))
+		tenant.Bucket.BoostRemaining = expiration.Sub(tenant.LastUpdate.Time)
+	}
+	if consumption := r[7]; consumption != tree.DNull {
+		// total_consumption can be NULL because of an upgrade of the
+		// tenant_usage table.
+		if err := protoutil.Unmarshal(
+			[]byte(tree.MustBeDBytes(consumption)), &tenant.Consumption,
+		); err != nil {
+			return tenantState{}, err
+		}
+	}
+	return tenant, nil
+}
+
+// readAllTenantStates reads the per-tenant state of all the tenants from the
+// system table, with a single query.
+func readAllTenantStates(
+	ctx context.Context, txn isql.Txn,
+) (tenantIDs []roachpb.TenantID, tenants []tenantState, _ error) {
+	rows, err := txn.QueryBufferedEx(
+		ctx, "tenant-usage-select-tenants", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT
+		  instance_id,               /* 0 */
+			next_instance_id,          /* 1 */
+			last_update,               /* 2 */
+			ru_burst_limit,            /* 3 */
+			ru_refill_rate,            /* 4 */
+			ru_current,                /* 5 */
+			current_share_sum,         /* 6 */
+			total_consumption,         /* 7 */
+			instance_lease,            /* 8 */
+			instance_seq,              /* 9 */
+			instance_shares,           /* 10 */
+			tenant_id                  /* 11 */
+		 FROM system.tenant_usage
+		 WHERE instance_id = 0`,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	tenantIDs = make([]roachpb.TenantID, 0, len(rows))
+	tenants = make([]tenantState, 0, len(rows))
+	for _, r := range rows {
+		tenantID, err := roachpb.MakeTenantID(uint64(tree.MustBeDInt(r[11])))
+		if err != nil {
+			return nil, nil, err
+		}
+		tenant, err := tenantStateFromRow(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+		tenants = append(tenants, tenant)
+	}
+	return tenantIDs, tenants, nil
+}
+
 // updateTenantState writes out an updated tenant state.
 func (h *sysTableHelper) updateTenantState(txn isql.Txn, tenant tenantState) error {
 	consumption, err := protoutil.Marshal(&tenant.Consumption)
 	if err != nil {
 		return err
 	}
+	boostExpiration, boostRate := tenant.boostColumns()
 	// Note: it is important that this UPSERT specifies all columns of the
 	// table, to allow it to perform "blind" writes.
-	// Note: The RUCurrentAvg field is mapped to the current_share_sum column.
+	// Note: The RUCurrentAvg field is mapped to the current_share_sum column,
+	// and the boost to the instance_seq and instance_shares columns.
 	_, err = txn.ExecEx(
 		h.ctx, "tenant-usage-upsert", txn.KV(),
 		sessiondata.NodeUserSessionDataOverride,
@@ -231,7 +313,7 @@ func (h *sysTableHelper) updateTenantState(txn isql.Txn, tenant tenantState) err
 			instance_lease,
 			instance_seq,
 			instance_shares
-		 ) VALUES ($1, 0, $2, $3, $4, $5, $6, $7, $8, NULL, NULL, NULL)
+		 ) VALUES ($1, 0, $2, $3, $4, $5, $6, $7, $8, NULL, $9, $10)
 		 `,
 		h.tenantID.ToUint64(),                    // $1
 		int64(tenant.FirstInstance),              // $2
@@ -241,6 +323,8 @@ func (h *sysTableHelper) updateTenantState(txn isql.Txn, tenant tenantState) err
 		tenant.Bucket.RUCurrent,                  // $6
 		tenant.Bucket.RUCurrentAvg,               // $7
 		tree.NewDBytes(tree.DBytes(consumption)), // $8
+		boostExpiration,                          // $9
+		boostRate,                                // $10
 	)
 	return err
 }
@@ -253,9 +337,11 @@ func (h *sysTableHelper) updateTenantAndInstanceState(
 	if err != nil {
 		return err
 	}
+	boostExpiration, boostRate := tenant.boostColumns()
 	// Note: it is important that this UPSERT specifies all columns of the
 	// table, to allow it to perform "blind" writes.
-	// Note: The RUCurrentAvg field is mapped to the current_share_sum column.
+	// Note: The RUCurrentAvg field is mapped to the current_share_sum column,
+	// and the boost to the instance_seq and instance_shares columns.
 	_, err = txn.ExecEx(
 		h.ctx, "tenant-usage-insert", txn.KV(),
 		sessiondata.NodeUserSessionDataOverride,
@@ -273,7 +359,7 @@ func (h *sysTableHelper) updateTenantAndInstanceState(
 			instance_seq,
 			instance_shares
 		 ) VALUES
-		   ($1, 0,  $2,  $3,  $4,   $5,   $6,   $7,   $8,   NULL, NULL, NULL),
+		   ($1, 0,  $2,  $3,  $4,   $5,   $6,   $7,   $8,   NULL, $15,  $16),
 			 ($1, $9, $10, $11, NULL, NULL, NULL, NULL, NULL, $12,  $13,  $14)
 		 `,
 		h.tenantID.ToUint64(),                    // $1
@@ -290,6 +376,8 @@ func (h *sysTableHelper) updateTenantAndInstanceState(
 		&instance.Lease,                          // $12
 		instance.Seq,                             // $13
 		instance.Shares,                          // $14
+		boostExpiration,                          // $15
+		boostRate,                                // $16
 	)
 	return err
 }
@@ -499,8 +587,16 @@ func (h *sysTableHelper) checkInvariants(txn isql.Txn) error {
 	for i := range rows {
 		var nullFirst, nullLast int
 		if i == 0 {
-			// Row 0 should have NULL per-instance values.
+			// Row 0 should have NULL per-instance values, except for the
+			// instance_seq and instance_shares columns which are used for the
+			// boost, if there is one.
 			nullFirst, nullLast = 8, 10
+			if (rows[i][9] == tree.DNull) != (rows[i][10] == tree.DNull) {
+				return errors.New("expected both or neither boost columns to be NULL")
+			}
+			if rows[i][10] != tree.DNull {
+				nullLast = 8
+			}
 		} else {
 			// Other rows should have NULL per-tenant values.
 			nullFirst, nullLast = 3, 7
@@ -558,6 +654,12 @@ func InspectTenantMetadata(
 		tenant.Bucket.RUCurrent,
 		tenant.Bucket.RUCurrentAvg,
 	)
+	if tenant.Bucket.RUBoostRate != 0 {
+		fmt.Fprintf(&buf, "Burst: ru-rate=%g  expiration=%s\n",
+			tenant.Bucket.RUBoostRate,
+			tenant.LastUpdate.Add(tenant.Bucket.BoostRemaining).Format(timeFormat),
+		)
+	}
 	fmt.Fprintf(&buf, "Consumption: ru=%.12g kvru=%.12g  reads=%d in %d batches (%d bytes)  writes=%d in %d batches (%d bytes)  pod-cpu-usage: %g secs  pgwire-egress=%d bytes  external-egress=%d bytes  external-ingress=%d bytes\n",
 		tenant.Consumption.RU,
 		tenant.Consumption.KVRU,
@@ -50,6 +50,16 @@ type State struct {
 	// system.tenant_usage table. That column is unused and reusing it avoids
 	// a system table schema change.
 	RUCurrentAvg float64
+
+	// RUBoostRate is an additional refill rate in RUs/second, granted
+	// temporarily (e.g. during a migration) until BoostRemaining elapses.
+	RUBoostRate float64
+
+	// BoostRemaining is the time remaining before the boost expires.
+	// NOTE: The boost fields are serialized as the instance_shares and
+	// instance_seq columns of the per-tenant row in the system.tenant_usage
+	// table. These columns are otherwise only used by the per-instance rows.
+	BoostRemaining time.Duration
 }
 
 // fallbackRateTimeFrame is a time frame used to calculate a fallback rate.
@@ -61,12 +71,30 @@ type State struct {
 const fallbackRateTimeFrame = time.Hour
 
 // Update accounts for passing of time, replenishing tokens according to the
-// rate.
-func (s *State) Update(since time.Duration) {
+// rate. Returns true if the boost expired during that time.
+func (s *State) Update(since time.Duration) (boostExpired bool) {
 	if since > 0 {
 		s.RUCurrent += s.RURefillRate * since.Seconds()
+		if s.RUBoostRate > 0 {
+			boosted := since
+			if boosted > s.BoostRemaining {
+				boosted = s.BoostRemaining
+			}
+			s.RUCurrent += s.RUBoostRate * boosted.Seconds()
+			s.BoostRemaining -= boosted
+			if s.BoostRemaining <= 0 {
+				s.RUBoostRate, s.BoostRemaining = 0, 0
+				boostExpired = true
+			}
+		}
 	}
 	s.clampToLimit()
+	return boostExpired
+}
+
+// refillRate returns the current refill rate, including the boost.
+func (s *State) refillRate() float64 {
+	return s.RURefillRate + s.RUBoostRate
 }
 
 // Request processes a request for more tokens and updates the State
@@ -81,7 +109,7 @@ func (s *State) Request(
 	s.RUCurrentAvg = movingAvgFactor*s.RUCurrent + (1-movingAvgFactor)*s.RUCurrentAvg
 
 	// Calculate the fallback rate.
-	res.FallbackRate = s.RURefillRate
+	res.FallbackRate = s.refillRate()
 	if s.RUCurrent > 0 {
 		res.FallbackRate += s.RUCurrent / fallbackRateTimeFrame.Seconds()
 	}
@@ -91,7 +119,7 @@ func (s *State) Request(
 	}
 
 	needed := req.RequestedRU
-	if needed > s.RUCurrent && s.RURefillRate == 0 {
+	if needed > s.RUCurrent && s.refillRate() == 0 {
 		// No way to refill tokens, so don't allow taking on debt.
 		needed = s.RUCurrent
 	}
@@ -142,7 +170,7 @@ func (s *State) Request(
 	// EMA of -6000 * 0.2 = -1200. Instance #2 is therefore granted 880 RU/s.
 	// This temporarily exceeds the token bucket refill rate. However, over time
 	// the EMA will converge towards -5000 and each instance will get 500 RU/s.
-	refill := req.TargetRequestPeriod.Seconds() * s.RURefillRate
+	refill := req.TargetRequestPeriod.Seconds() * s.refillRate()
 	available := refill
 
 	if debtAvg := -s.RUCurrentAvg; debtAvg > 0 {
@@ -220,6 +248,23 @@ func (s *State) Reconfigure(
 	)
 }
 
+// Boost temporarily increases the refill rate of the token bucket by the given
+// rate (in RUs/second), for the given duration. It replaces any previous boost;
+// a zero rate or duration removes the boost.
+func (s *State) Boost(
+	ctx context.Context, tenantID roachpb.TenantID, rate float64, duration time.Duration,
+) {
+	if rate <= 0 || duration <= 0 {
+		rate, duration = 0, 0
+	}
+	s.RUBoostRate = rate
+	s.BoostRemaining = duration
+	log.Infof(
+		ctx, "token bucket for tenant %s boosted: boost-rate=%g remaining=%s",
+		tenantID.String(), s.RUBoostRate, s.BoostRemaining,
+	)
+}
+
 // clampToLimit limits current RUs in the bucket to the burst limit.
 func (s *State) clampToLimit() {
 	if s.RUBurstLimit > 0 && s.RUCurrent > s.RUBurstLimit {
@@ -46,7 +46,7 @@ type testState struct {
 }
 
 func (ts *testState) String() string {
-	return fmt.Sprintf(
+	s := fmt.Sprintf(
 		strings.Join(
 			[]string{
 				"Burst Limit: %.10g",
@@ -56,10 +56,15 @@ func (ts *testState) String() string {
 			}, "\n"),
 		ts.RUBurstLimit, ts.RURefillRate, ts.RUCurrent, ts.RUCurrentAvg,
 	)
+	if ts.RUBoostRate != 0 {
+		s += fmt.Sprintf("\nBoost Rate: %.10g (remaining: %s)", ts.RUBoostRate, ts.BoostRemaining)
+	}
+	return s
 }
 
 var testStateCommands = map[string]func(*testState, *testing.T, *datadriven.TestData) string{
 	"reconfigure": (*testState).reconfigure,
+	"boost":       (*testState).boost,
 	"update":      (*testState).update,
 	"request":     (*testState).request,
 }
@@ -88,8 +93,25 @@ func parseDuration(t *testing.T, d *datadriven.TestData, str string) time.Durati
 	return duration
 }
 
+func (ts *testState) boost(t *testing.T, d *datadriven.TestData) string {
+	var vals struct {
+		Rate     float64
+		Duration string
+	}
+	vals.Duration = "0s"
+	if err := yaml.UnmarshalStrict([]byte(d.Input), &vals); err != nil {
+		d.Fatalf(t, "failed to unmarshal boost values: %v", err)
+	}
+	ts.State.Boost(
+		context.Background(), roachpb.TenantID{}, vals.Rate, parseDuration(t, d, vals.Duration),
+	)
+	return ts.String()
+}
+
 func (ts *testState) update(t *testing.T, d *datadriven.TestData) string {
-	ts.State.Update(parseDuration(t, d, d.Input))
+	if ts.State.Update(parseDuration(t, d, d.Input)) {
+		return "Boost expired\n" + ts.String()
+	}
 	return ts.String()
 }
 
@@ -10,9 +10,13 @@ package tenantcostserver
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities/tenantcapabilitiespb"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -51,8 +55,36 @@ func (s *instance) TokenBucketRequest(
 	metrics.mutex.Lock()
 	defer metrics.mutex.Unlock()
 
+	s.updateDataSize(ctx, tenantID, metrics)
+
+	var caps *tenantcapabilitiespb.TenantCapabilities
+	if s.capabilities != nil {
+		caps, _ = s.capabilities.GetCapabilities(tenantID)
+	}
+	var costModel int64
+	if caps != nil {
+		costModel = tenantcapabilities.MustGetInt64ByID(caps, tenantcapabilities.CostModel)
+	}
+
+	// Negotiate the version and the optional features of the protocol. Requests
+	// from instances that predate TokenBucketProtocolV2 are served as before.
+	protocolVersion := in.ProtocolVersion
+	if protocolVersion > kvpb.TokenBucketProtocolLatest {
+		protocolVersion = kvpb.TokenBucketProtocolLatest
+	}
+	var capabilities kvpb.TokenBucketCapabilities
+	interval := reportInterval.Get(&s.settings.SV)
+	if protocolVersion >= kvpb.TokenBucketProtocolV2 {
+		capabilities = in.Capabilities & supportedCapabilities
+		if interval == 0 {
+			capabilities &^= kvpb.TokenBucketCapabilityReportInterval
+		}
+	}
+
 	result := &kvpb.TokenBucketResponse{}
 	var consumption kvpb.TenantConsumption
+	var now time.Time
+	var expiredBoostRate float64
 	if err := s.ief.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
 		*result = kvpb.TokenBucketResponse{}
 
@@ -70,8 +102,8 @@ func (s *instance) TokenBucketRequest(
 				return err
 			}
 		}
-		now := s.timeSource.Now()
-		tenant.update(now)
+		now = s.timeSource.Now()
+		expiredBoostRate = tenant.update(now)
 
 		if !instance.Present {
 			if err := h.accomodateNewInstance(txn, &tenant, &instance); err != nil {
@@ -93,6 +125,17 @@ func (s *instance) TokenBucketRequest(
 			}
 		}
 
+		// Account for the consumption of the previous requests of the instance
+		// that it did not get a response for, unless we already did.
+		if capabilities.Has(kvpb.TokenBucketCapabilityUnacknowledgedConsumption) {
+			for i := range in.UnacknowledgedConsumption {
+				delta := &in.UnacknowledgedConsumption[i]
+				if instance.Seq == 0 || instance.Seq < delta.SeqNum {
+					tenant.Consumption.Add(&delta.Consumption)
+				}
+			}
+		}
+
 		// Only update consumption if we are sure this is not a duplicate request
 		// that we already counted. Note that if this is a duplicate request, it
 		// will still use RUs from the bucket (RUCurrent); we rely on a higher level
@@ -103,7 +146,19 @@ func (s *instance) TokenBucketRequest(
 			instance.Seq = in.SeqNum
 		}
 
+		if metrics.dataSize.measured {
+			tenant.Consumption.LiveBytes = uint64(metrics.dataSize.liveBytes)
+			tenant.Consumption.TotalBytes = uint64(metrics.dataSize.totalBytes)
+		}
+
 		*result = tenant.Bucket.Request(ctx, in)
+		result.LiveBytesLimitExceeded = metrics.dataSize.limitExceeded
+		result.CostModel = costModel
+		result.ProtocolVersion = protocolVersion
+		result.Capabilities = capabilities
+		if capabilities.Has(kvpb.TokenBucketCapabilityReportInterval) {
+			result.ReportInterval = interval
+		}
 
 		instance.LastUpdate.Time = now
 		if err := h.updateTenantAndInstanceState(txn, tenant, instance); err != nil {
@@ -114,6 +169,7 @@ func (s *instance) TokenBucketRequest(
 			panic(err)
 		}
 		consumption = tenant.Consumption
+		result.Consumption = consumption
 		return nil
 	}); err != nil {
 		return &kvpb.TokenBucketResponse{
@@ -121,6 +177,10 @@ func (s *instance) TokenBucketRequest(
 		}
 	}
 
+	if expiredBoostRate != 0 {
+		logBurstExpired(ctx, tenantID, expiredBoostRate)
+	}
+
 	// Report current consumption.
 	metrics.totalRU.UpdateIfHigher(consumption.RU)
 	metrics.totalKVRU.UpdateIfHigher(consumption.KVRU)
@@ -135,6 +195,35 @@ func (s *instance) TokenBucketRequest(
 	metrics.totalExternalIOEgressBytes.Update(int64(consumption.ExternalIOEgressBytes))
 	metrics.totalExternalIOIngressBytes.Update(int64(consumption.ExternalIOIngressBytes))
 	metrics.totalCrossRegionNetworkRU.UpdateIfHigher(consumption.CrossRegionNetworkRU)
+	metrics.totalEstimatedCPUSeconds.Update(consumption.EstimatedCPUSeconds)
+	metrics.totalEstimatedKVCPUSeconds.Update(consumption.EstimatedKVCPUSeconds)
+	metrics.totalBackupRU.UpdateIfHigher(consumption.BackupRU)
+	metrics.totalSystemOverheadRU.UpdateIfHigher(consumption.SystemOverheadRU)
+
+	// A request that could not be fully granted, or was granted over time, means
+	// that the tenant is being throttled.
+	throttled := result.GrantedRU < in.RequestedRU || result.TrickleDuration > 0
+	metrics.usage.record(now, &consumption, throttled)
+	s.maybeDetectAnomaly(
+		ctx, tenantID, metrics, now, tenantcostmodel.ModelVersion(costModel), &consumption,
+	)
+
+	// Report the limits and cost model configured for the tenant.
+	if caps != nil {
+		metrics.maxRequestsPerSecondPerNode.Update(
+			tenantcapabilities.MustGetInt64ByID(caps, tenantcapabilities.MaxRequestsPerSecondPerNode))
+		metrics.maxReadBytesPerSecondPerNode.Update(
+			tenantcapabilities.MustGetInt64ByID(caps, tenantcapabilities.MaxReadBytesPerSecondPerNode))
+		metrics.maxWriteBytesPerSecondPerNode.Update(
+			tenantcapabilities.MustGetInt64ByID(caps, tenantcapabilities.MaxWriteBytesPerSecondPerNode))
+		metrics.maxBlockCacheFillBytesPerSecondPerNode.Update(
+			tenantcapabilities.MustGetInt64ByID(caps, tenantcapabilities.MaxBlockCacheFillBytesPerSecondPerNode))
+		metrics.maxSQLConnectionsPerInstance.Update(
+			tenantcapabilities.MustGetInt64ByID(caps, tenantcapabilities.MaxSQLConnectionsPerInstance))
+		metrics.maxLiveBytes.Update(
+			tenantcapabilities.MustGetInt64ByID(caps, tenantcapabilities.MaxLiveBytes))
+		metrics.costModel.Update(costModel)
+	}
 	return result
 }
 
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlinstance/instancestorage"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
@@ -509,3 +510,43 @@ func TestStartTenantWithStaleInstance(t *testing.T) {
 	_, err := db.Exec("SELECT count(*) FROM system.sqlliveness")
 	require.NoError(t, err)
 }
+
+// TestTenantMaxSQLConnectionsCapabilityAppliesToRoot verifies that the
+// connection limit set by the host cluster through the
+// max_sql_connections_per_instance capability cannot be bypassed by connecting
+// to the virtual cluster as root.
+func TestTenantMaxSQLConnectionsCapabilityAppliesToRoot(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestTenantAlwaysEnabled,
+	})
+	defer s.Stopper().Stop(ctx)
+
+	// Hold on to one root connection so that the limit below is reached.
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	require.NoError(t, conn.PingContext(ctx))
+
+	setLimit := func(limit string) {
+		systemDB := sqlutils.MakeSQLRunner(s.SystemLayer().SQLConn(t))
+		systemDB.Exec(t, `ALTER TENANT [$1] GRANT CAPABILITY max_sql_connections_per_instance = `+limit,
+			serverutils.TestTenantID().ToUint64())
+		serverutils.WaitForTenantCapabilities(t, s, serverutils.TestTenantID(), map[tenantcapabilities.ID]string{
+			tenantcapabilities.MaxSQLConnectionsPerInstance: limit,
+		}, "")
+	}
+
+	setLimit("1")
+	rootDB := s.ApplicationLayer().SQLConn(t)
+	err = rootDB.PingContext(ctx)
+	var pqErr *pq.Error
+	require.True(t, errors.As(err, &pqErr), "expected a pq error, got %v", err)
+	require.Equal(t, pgcode.TooManyConnections.String(), string(pqErr.Code))
+
+	setLimit("0")
+	require.NoError(t, rootDB.PingContext(ctx))
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package spanconfiglimiterccl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHostEnforcesMaxSpanConfigs ensures that the host rejects span config
+// updates that would take a tenant past its max_span_configs capability, and
+// that the tenant's updates go through once the limit is lifted.
+func TestHostEnforcesMaxSpanConfigs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 1, base.TestClusterArgs{ServerArgs: base.TestServerArgs{
+		DefaultTestTenant: base.TestControlsTenantsExplicitly,
+	}})
+	defer tc.Stopper().Stop(ctx)
+	ts := tc.Server(0)
+	hostDB := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+
+	tenantID := roachpb.MustMakeTenantID(10)
+	tenant, err := ts.TenantController().StartTenant(ctx, base.TestTenantArgs{
+		TenantID: tenantID,
+	})
+	require.NoError(t, err)
+	tenantSQLDB := tenant.SQLConn(t)
+	tenantDB := sqlutils.MakeSQLRunner(tenantSQLDB)
+
+	// Limit the tenant to a single span config; it already has more than that
+	// from its system tables, so any update adding new ones is rejected.
+	hostDB.Exec(t, `ALTER TENANT [10] GRANT CAPABILITY max_span_configs = 1`)
+	serverutils.WaitForTenantCapabilities(t, ts, tenantID, map[tenantcapabilities.ID]string{
+		tenantcapabilities.MaxSpanConfigs: "1",
+	}, "")
+
+	tenantDB.Exec(t, `CREATE TABLE t(k INT PRIMARY KEY)`)
+	id := sqlutils.QueryTableID(t, tenantSQLDB, "defaultdb", "public", "t")
+	tablePrefix := keys.MakeSQLCodec(tenantID).TablePrefix(uint32(id))
+
+	rejectedUpdates := func() int64 {
+		var count int64
+		hostDB.QueryRow(t, `SELECT value FROM crdb_internal.node_metrics WHERE name = 'spanconfig.tenant_limit.rejected_updates'`).Scan(&count)
+		return count
+	}
+	tableSpanConfigs := func() int {
+		var count int
+		hostDB.QueryRow(t, `SELECT count(*) FROM system.span_configurations WHERE start_key = $1`, []byte(tablePrefix)).Scan(&count)
+		return count
+	}
+
+	testutils.SucceedsSoon(t, func() error {
+		if rejected := rejectedUpdates(); rejected == 0 {
+			return errors.New("expected span config updates to be rejected")
+		}
+		return nil
+	})
+	require.Zero(t, tableSpanConfigs())
+
+	// Once the limit is lifted, the table's span config is reconciled.
+	hostDB.Exec(t, `ALTER TENANT [10] REVOKE CAPABILITY max_span_configs`)
+	serverutils.WaitForTenantCapabilities(t, ts, tenantID, map[tenantcapabilities.ID]string{
+		tenantcapabilities.MaxSpanConfigs: "0",
+	}, "")
+	testutils.SucceedsSoon(t, func() error {
+		if count := tableSpanConfigs(); count != 1 {
+			return errors.Newf("expected a span config for the table, found %d", count)
+		}
+		return nil
+	})
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/errors/oserror"
@@ -432,6 +433,93 @@ func TestPebbleEncryption2(t *testing.T) {
 	addKeyAndValidate("d", "d", "plain", "16v2.key")
 }
 
+func TestPebbleEncryptionRewriteInactiveKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const stickyVFSID = `foo`
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	stickyRegistry := fs.NewStickyRegistry()
+	memFS := stickyRegistry.Get(stickyVFSID)
+	writeToFile(t, memFS, "16v1.key", []byte("111111111111111111111111111111111234567890123456"))
+	writeToFile(t, memFS, "16v2.key", []byte("111111111111111111111111111111198765432198765432"))
+
+	open := func(encKeyFile, oldEncKeyFile string) storage.Engine {
+		encOptionsBytes, err := protoutil.Marshal(&baseccl.EncryptionOptions{
+			KeySource: baseccl.EncryptionKeySource_KeyFiles,
+			KeyFiles: &baseccl.EncryptionKeyFiles{
+				CurrentKey: encKeyFile,
+				OldKey:     oldEncKeyFile,
+			},
+			DataKeyRotationPeriod: 1000,
+		})
+		require.NoError(t, err)
+		env, err := fs.InitEnvFromStoreSpec(
+			ctx,
+			base.StoreSpec{
+				InMemory:          true,
+				Attributes:        roachpb.Attributes{},
+				Size:              base.SizeSpec{InBytes: 512 << 20},
+				EncryptionOptions: encOptionsBytes,
+				StickyVFSID:       stickyVFSID,
+			},
+			fs.ReadWrite,
+			stickyRegistry, /* sticky registry */
+			nil,            /* statsCollector */
+		)
+		require.NoError(t, err)
+		// Keep the flushed sstables in L0, since manual compactions don't rewrite
+		// sstables in the bottommost level which don't overlap the level above.
+		db, err := storage.Open(ctx, env, cluster.MakeTestingClusterSettings(),
+			storage.DisableAutomaticCompactions)
+		require.NoError(t, err)
+		return db
+	}
+	inactiveKeyBytes := func(db storage.Engine) uint64 {
+		stats, err := db.GetEnvStats()
+		require.NoError(t, err)
+		t.Logf("EnvStats:\n%+v\n\n", *stats)
+		var bytes uint64
+		for _, ks := range stats.KeyStats {
+			if ks.KeyID != stats.ActiveKeyID {
+				bytes += ks.Bytes
+			}
+		}
+		return bytes
+	}
+
+	db := open("16v1.key", "plain")
+	batch := db.NewWriteBatch()
+	require.NoError(t, batch.PutUnversioned(roachpb.Key("a"), []byte("a")))
+	require.NoError(t, batch.Commit(true))
+	batch.Close()
+	require.NoError(t, db.Flush())
+	require.Zero(t, inactiveKeyBytes(db))
+	db.Close()
+
+	// Rotating the store key rotates the data key, leaving the sstable
+	// encrypted with the previous data key until it is rewritten.
+	db = open("16v2.key", "16v1.key")
+	require.NotZero(t, inactiveKeyBytes(db))
+	rewritten, err := db.RewriteInactiveKeySSTables(ctx, stopper)
+	require.NoError(t, err)
+	require.NotZero(t, rewritten)
+	require.Zero(t, inactiveKeyBytes(db))
+	require.Equal(t, []byte("a"), storageutils.MVCCGetRaw(t, db, storageutils.PointKey("a", 0)))
+
+	// Sstables in the bottommost level which don't overlap the level above
+	// can't be rewritten, and are reported.
+	require.NoError(t, db.Compact())
+	db.Close()
+	db = open("16v1.key", "16v2.key")
+	defer db.Close()
+	require.NotZero(t, inactiveKeyBytes(db))
+	_, err = db.RewriteInactiveKeySSTables(ctx, stopper)
+	require.ErrorContains(t, err, "in the bottommost level still use inactive keys")
+	require.NotZero(t, inactiveKeyBytes(db))
+}
+
 func TestCanRegistryElide(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -1011,6 +1011,16 @@ For example:
   --store=provisioned-rate=disk-name=nvme1n1
   --store=provisioned-rate=disk-name=sdb:bandwidth=250MiB/s
 
+</PRE>
+When WAL failover is configured (see --wal-failover), the latency of a WAL
+write above which a store fails over to its secondary WAL directory can be
+set per store with the "wal-failover-threshold" field. It overrides the value
+of the cluster setting storage.wal_failover.unhealthy_op_threshold.
+For example:
+<PRE>
+
+  --store=path=/mnt/ssd01,wal-failover-threshold=50ms
+
 </PRE>
 Commas are forbidden in all values, since they are used to separate fields.
 Also, if you use equal signs in the file path to a store, you must use the
@@ -1349,6 +1359,14 @@ Takes any of the following values:
 status, without actually decommissioning the node.`,
 	}
 
+	NodeDecommissionAnalyze = FlagInfo{
+		Name: "analyze",
+		Description: `Only simulate the decommission and report the replica moves
+it requires, the volume of data to move, the ranges whose replicas cannot be
+moved without violating their constraints, and an estimate of the time it would
+take, without actually decommissioning the node.`,
+	}
+
 	NodeDrainSelf = FlagInfo{
 		Name: "self",
 		Description: `Use the node ID of the node connected to via --host
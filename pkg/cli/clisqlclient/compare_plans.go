@@ -0,0 +1,57 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package clisqlclient
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ComparePlans retrieves the plans recorded for the statement fingerprint with
+// the given hex-encoded ID, rendered as a side-by-side comparison along with
+// execution stats for each plan. See crdb_internal.compare_plans.
+func ComparePlans(ctx context.Context, conn Conn, fingerprintID string) ([]string, error) {
+	fingerprintID = strings.TrimPrefix(strings.TrimPrefix(fingerprintID, `\x`), "0x")
+	if _, err := hex.DecodeString(fingerprintID); err != nil {
+		return nil, errors.Wrapf(err, "%q is not a valid hex-encoded statement fingerprint ID", fingerprintID)
+	}
+	rows, err := conn.Query(ctx,
+		`SELECT * FROM crdb_internal.compare_plans(decode($1, 'hex'))`, fingerprintID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compare plans")
+	}
+	defer func() { _ = rows.Close() }()
+
+	var lines []string
+	vals := make([]driver.Value, 1)
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to compare plans")
+		}
+		switch v := vals[0].(type) {
+		case string:
+			lines = append(lines, v)
+		case []byte:
+			lines = append(lines, string(v))
+		default:
+			return nil, errors.AssertionFailedf("unexpected value type %T", v)
+		}
+	}
+	return lines, nil
+}
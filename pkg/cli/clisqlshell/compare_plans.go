@@ -0,0 +1,39 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package clisqlshell
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlclient"
+)
+
+// handleComparePlans handles the `\compareplans` command.
+func (c *cliState) handleComparePlans(
+	args []string, loopState, errState cliStateEnum,
+) (resState cliStateEnum) {
+	if len(args) != 1 {
+		return c.invalidSyntax(errState)
+	}
+
+	lines, err := clisqlclient.ComparePlans(context.Background(), c.conn, args[0])
+	if err != nil {
+		fmt.Fprintln(c.iCtx.stderr, err)
+		c.exitErr = err
+		return errState
+	}
+	for _, l := range lines {
+		fmt.Fprintln(c.iCtx.stdout, l)
+	}
+	fmt.Fprintln(c.iCtx.stdout)
+	return loopState
+}
@@ -116,6 +116,9 @@ Statement diagnostics
   \statement-diag list                               list available bundles.
   \statement-diag download <bundle-id> [<filename>]  download bundle.
 
+Query plans
+  \compareplans <fingerprint-id>  compare the plans recorded for a statement fingerprint (hex-encoded ID).
+
 %s
 More documentation about our SQL dialect and the CLI shell is available online:
 %s
@@ -1564,6 +1567,9 @@ ORDER BY 1`
 	case `\statement-diag`:
 		return c.handleStatementDiag(cmd[1:], loopState, errState)
 
+	case `\compareplans`:
+		return c.handleComparePlans(cmd[1:], loopState, errState)
+
 	default:
 		return c.invalidSyntax(errState)
 	}
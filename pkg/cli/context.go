@@ -591,14 +591,15 @@ func setDrainContextDefaults() {
 // nodeCtx captures the command-line parameters of the `node` command.
 // See below for defaults.
 var nodeCtx struct {
-	nodeDecommissionWait   nodeDecommissionWaitType
-	nodeDecommissionSelf   bool
-	nodeDecommissionChecks nodeDecommissionCheckMode
-	nodeDecommissionDryRun bool
-	statusShowRanges       bool
-	statusShowStats        bool
-	statusShowDecommission bool
-	statusShowAll          bool
+	nodeDecommissionWait    nodeDecommissionWaitType
+	nodeDecommissionSelf    bool
+	nodeDecommissionChecks  nodeDecommissionCheckMode
+	nodeDecommissionDryRun  bool
+	nodeDecommissionAnalyze bool
+	statusShowRanges        bool
+	statusShowStats         bool
+	statusShowDecommission  bool
+	statusShowAll           bool
 }
 
 // setNodeContextDefaults set the default values in nodeCtx.  This
@@ -609,6 +610,7 @@ func setNodeContextDefaults() {
 	nodeCtx.nodeDecommissionSelf = false
 	nodeCtx.nodeDecommissionChecks = nodeDecommissionChecksEnabled
 	nodeCtx.nodeDecommissionDryRun = false
+	nodeCtx.nodeDecommissionAnalyze = false
 	nodeCtx.statusShowRanges = false
 	nodeCtx.statusShowStats = false
 	nodeCtx.statusShowAll = false
@@ -37,10 +37,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/gc"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/raftlog"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
+	"github.com/cockroachdb/cockroach/pkg/raft/raftpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server"
@@ -768,11 +771,24 @@ $ curl -X POST  'http://localhost:8080/ts/query' \
 	RunE: runDebugDecodeProto,
 }
 
+var debugRaftLogOpts struct {
+	fromIndex, toIndex uint64
+	term               uint64
+}
+
 var debugRaftLogCmd = &cobra.Command{
 	Use:   "raft-log <directory> <range id>",
 	Short: "print the raft log for a range",
 	Long: `
-Prints all log entries in a store for the given range.
+Prints all log entries in a store for the given range, decoding the command
+each of them carries: its kind (e.g. conf change, lease request, split, or
+write batch), its replicated evaluation result and the contents of its write
+batch.
+
+The entries can be restricted to a range of indexes with --from-index and
+--to-index, and to those of a single term with --term. For example:
+
+	$ cockroach debug raft-log /path/to/store 42 --from-index=100 --to-index=120
 `,
 	Args: cobra.ExactArgs(2),
 	RunE: clierrorplus.MaybeDecorateError(runDebugRaftLog),
@@ -792,20 +808,41 @@ func runDebugRaftLog(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	start := keys.RaftLogPrefix(rangeID)
+	lo := kvpb.RaftIndex(debugRaftLogOpts.fromIndex)
+	// A zero upper bound leaves the iteration unbounded.
+	var hi kvpb.RaftIndex
+	if debugRaftLogOpts.toIndex != 0 {
+		if debugRaftLogOpts.toIndex < debugRaftLogOpts.fromIndex {
+			return errors.Errorf("--to-index %d is lower than --from-index %d",
+				debugRaftLogOpts.toIndex, debugRaftLogOpts.fromIndex)
+		}
+		hi = kvpb.RaftIndex(debugRaftLogOpts.toIndex + 1)
+	}
+
+	start := keys.RaftLogKey(rangeID, lo)
 	end := keys.RaftLogPrefix(rangeID).PrefixEnd()
+	if hi != 0 {
+		end = keys.RaftLogKey(rangeID, hi)
+	}
 	fmt.Printf("Printing keys %s -> %s (RocksDB keys: %#x - %#x )\n",
 		start, end,
 		string(storage.EncodeMVCCKey(storage.MakeMVCCMetadataKey(start))),
 		string(storage.EncodeMVCCKey(storage.MakeMVCCMetadataKey(end))))
 
-	// NB: raft log does not have intents.
-	return db.MVCCIterate(cmd.Context(), start, end, storage.MVCCKeyIterKind,
-		storage.IterKeyTypePointsOnly, fs.UnknownReadCategory,
-		func(kv storage.MVCCKeyValue, _ storage.MVCCRangeKeyStack) error {
-			kvserver.PrintMVCCKeyValue(kv)
+	return raftlog.Visit(cmd.Context(), db, rangeID, lo, hi, func(ent raftpb.Entry) error {
+		if debugRaftLogOpts.term != 0 && ent.Term != debugRaftLogOpts.term {
 			return nil
-		})
+		}
+		s, err := kvserver.SprintRaftLogEntry(ent)
+		if err != nil {
+			// Keep going: a single undecodable entry shouldn't hide the rest
+			// of the log, which is likely what is being investigated.
+			s = fmt.Sprintf("Term:%d Index:%d Type:%s: failed to decode: %v\n",
+				ent.Term, ent.Index, ent.Type, err)
+		}
+		fmt.Print(s)
+		return nil
+	})
 }
 
 var debugGCCmd = &cobra.Command{
@@ -1363,6 +1400,7 @@ var DebugCommandsRequiringEncryption = []*cobra.Command{
 	debugRangeDescriptorsCmd,
 	debugRecoverCollectInfoCmd,
 	debugRecoverExecuteCmd,
+	debugTenantConsumptionCmd,
 }
 
 // Debug commands. All commands in this list to be added to root debug command.
@@ -1375,6 +1413,7 @@ var debugCmds = []*cobra.Command{
 	debugRaftLogCmd,
 	debugRangeDataCmd,
 	debugRangeDescriptorsCmd,
+	debugTenantConsumptionCmd,
 	debugBallastCmd,
 	debugCheckLogConfigCmd,
 	debugDecodeKeyCmd,
@@ -1576,6 +1615,14 @@ func init() {
 	f.StringSliceVar(&debugMergeLogsOpts.tenantIDsFilter, "tenant-ids", nil,
 		"tenant IDs to filter logs by")
 
+	f = debugRaftLogCmd.Flags()
+	f.Uint64Var(&debugRaftLogOpts.fromIndex, "from-index", 0,
+		"lowest index of the entries to print")
+	f.Uint64Var(&debugRaftLogOpts.toIndex, "to-index", 0,
+		"highest index of the entries to print; 0 for no limit")
+	f.Uint64Var(&debugRaftLogOpts.term, "term", 0,
+		"only print the entries of this term; 0 for all terms")
+
 	f = debugDecodeKeyCmd.Flags()
 	f.Var(&decodeKeyOptions.encoding, "encoding", "key argument encoding")
 	f.BoolVar(&decodeKeyOptions.userKey, "user-key", false, "key type")
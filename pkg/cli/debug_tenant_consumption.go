@@ -0,0 +1,215 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clierrorplus"
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlexec"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+)
+
+var debugTenantConsumptionCmd = &cobra.Command{
+	Use:   "tenant-consumption <directory>",
+	Short: "print the resource consumption of virtual clusters",
+	Long: `
+Prints the state of the token bucket and the cumulative resource consumption of
+each virtual cluster, as recorded in the system.tenant_usage table of the host
+cluster, by reading the table directly from a store directory. This works
+without a running node, e.g. when SQL is unavailable.
+
+The store must hold a replica of the system.tenant_usage range. Since the
+table is not included in backups, the store of a stopped node is the only
+offline source of this data.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: clierrorplus.MaybeDecorateError(runDebugTenantConsumption),
+}
+
+func runDebugTenantConsumption(cmd *cobra.Command, args []string) error {
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.Background())
+
+	db, err := OpenEngine(args[0], stopper, fs.ReadOnly, storage.MustExist)
+	if err != nil {
+		return err
+	}
+
+	tenants, err := readTenantConsumption(cmd.Context(), db)
+	if err != nil {
+		return err
+	}
+	if len(tenants) == 0 {
+		fmt.Fprintln(stderr, "no rows found; the store may not hold a replica of system.tenant_usage")
+		return nil
+	}
+	cols := []string{
+		"tenant_id", "last_update", "instances",
+		"ru_current", "ru_refill_rate", "ru_burst_limit", "current_share_sum",
+		"total_ru", "kv_ru", "read_batches", "read_bytes", "write_batches", "write_bytes",
+		"sql_pods_cpu_seconds", "estimated_kv_cpu_seconds", "pgwire_egress_bytes",
+	}
+	return sqlExecCtx.PrintQueryOutput(os.Stdout, stderr, cols,
+		clisqlexec.NewRowSliceIter(formatTenantConsumption(tenants), "rlrrrrrrrrrrrrrr"))
+}
+
+// tenantConsumption is the state of a virtual cluster recorded in
+// system.tenant_usage.
+type tenantConsumption struct {
+	tenantID uint64
+	// lastUpdate is the time of the last update from any instance.
+	lastUpdate time.Time
+	// instances is the number of instances which have a row in the table.
+	instances int
+
+	// The state of the token bucket, as of lastUpdate.
+	ruCurrent, ruRefillRate, ruBurstLimit, currentShareSum float64
+
+	consumption kvpb.TenantConsumption
+}
+
+// readTenantConsumption reads the latest state of each virtual cluster from
+// the rows of system.tenant_usage found in the given reader, in tenant ID
+// order.
+func readTenantConsumption(ctx context.Context, r storage.Reader) ([]tenantConsumption, error) {
+	table := systemschema.TenantUsageTable.TableDescriptor
+	span := table.TableSpan(keys.SystemSQLCodec)
+	// The table may have intents if the node was stopped in the middle of a
+	// transaction; ignore them, and read the last committed values instead.
+	res, err := storage.MVCCScan(ctx, r, span.Key, span.EndKey, hlc.MaxTimestamp,
+		storage.MVCCScanOptions{Inconsistent: true})
+	if err != nil {
+		return nil, err
+	}
+
+	colIDs := make([]descpb.ColumnID, len(table.PublicColumns()))
+	for i, col := range table.PublicColumns() {
+		colIDs[i] = col.GetID()
+	}
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(
+		&spec, keys.SystemSQLCodec, table, table.GetPrimaryIndex(), colIDs,
+	); err != nil {
+		return nil, err
+	}
+	var rf row.Fetcher
+	if err := rf.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &tree.DatumAlloc{},
+		Spec:              &spec,
+	}); err != nil {
+		return nil, err
+	}
+	defer rf.Close(ctx)
+	if err := rf.ConsumeKVProvider(ctx, &row.KVProvider{KVs: res.KVs}); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint64]*tenantConsumption)
+	for {
+		datums, err := rf.NextRowDecoded(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if datums == nil {
+			break
+		}
+		// The columns are those of the CREATE TABLE statement, in order.
+		tenantID := uint64(tree.MustBeDInt(datums[0]))
+		t, ok := byID[tenantID]
+		if !ok {
+			t = &tenantConsumption{tenantID: tenantID}
+			byID[tenantID] = t
+		}
+		if instanceID := tree.MustBeDInt(datums[1]); instanceID != 0 {
+			t.instances++
+			continue
+		}
+		// The row with instance ID 0 holds the per-tenant state.
+		t.lastUpdate = tree.MustBeDTimestamp(datums[3]).Time
+		t.ruBurstLimit = floatOrZero(datums[4])
+		t.ruRefillRate = floatOrZero(datums[5])
+		t.ruCurrent = floatOrZero(datums[6])
+		t.currentShareSum = floatOrZero(datums[7])
+		if datums[8] != tree.DNull {
+			if err := protoutil.Unmarshal(
+				[]byte(tree.MustBeDBytes(datums[8])), &t.consumption,
+			); err != nil {
+				return nil, errors.Wrapf(err, "decoding consumption of tenant %d", tenantID)
+			}
+		}
+	}
+
+	tenants := make([]tenantConsumption, 0, len(byID))
+	for _, t := range byID {
+		tenants = append(tenants, *t)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].tenantID < tenants[j].tenantID })
+	return tenants, nil
+}
+
+func floatOrZero(d tree.Datum) float64 {
+	if d == tree.DNull {
+		return 0
+	}
+	return float64(tree.MustBeDFloat(d))
+}
+
+// formatTenantConsumption formats the given states as rows of the output of
+// debug tenant-consumption.
+func formatTenantConsumption(tenants []tenantConsumption) [][]string {
+	rows := make([][]string, len(tenants))
+	for i, t := range tenants {
+		c := &t.consumption
+		lastUpdate := "NULL"
+		if !t.lastUpdate.IsZero() {
+			lastUpdate = t.lastUpdate.UTC().Format(time.RFC3339)
+		}
+		rows[i] = []string{
+			fmt.Sprint(t.tenantID),
+			lastUpdate,
+			fmt.Sprint(t.instances),
+			fmt.Sprintf("%.2f", t.ruCurrent),
+			fmt.Sprintf("%.2f", t.ruRefillRate),
+			fmt.Sprintf("%.2f", t.ruBurstLimit),
+			fmt.Sprintf("%.2f", t.currentShareSum),
+			fmt.Sprintf("%.2f", c.RU),
+			fmt.Sprintf("%.2f", c.KVRU),
+			fmt.Sprint(c.ReadBatches),
+			fmt.Sprint(c.ReadBytes),
+			fmt.Sprint(c.WriteBatches),
+			fmt.Sprint(c.WriteBytes),
+			fmt.Sprintf("%.2f", c.SQLPodsCPUSeconds),
+			fmt.Sprintf("%.2f", c.EstimatedKVCPUSeconds),
+			fmt.Sprint(c.PGWireEgressBytes),
+		}
+	}
+	return rows
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugTenantConsumption(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	storePath, dirCleanupFn := testutils.TempDir(t)
+	defer dirCleanupFn()
+
+	consumption, err := protoutil.Marshal(&kvpb.TenantConsumption{
+		RU:          1234.5,
+		ReadBatches: 10,
+		WriteBytes:  2048,
+	})
+	require.NoError(t, err)
+
+	// Write the state of a tenant with two instances, then stop the server.
+	func() {
+		srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+			DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+			StoreSpecs:        []base.StoreSpec{{Path: storePath}},
+		})
+		defer srv.Stopper().Stop(ctx)
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `
+INSERT INTO system.tenant_usage (
+  tenant_id, instance_id, next_instance_id, last_update,
+  ru_burst_limit, ru_refill_rate, ru_current, current_share_sum, total_consumption
+) VALUES (5, 0, 1, '2024-01-01 00:00:00', 1000, 100, 500, 2, $1)`, consumption)
+		sqlDB.Exec(t, `
+INSERT INTO system.tenant_usage (
+  tenant_id, instance_id, next_instance_id, last_update, instance_seq, instance_shares
+) VALUES (5, 1, 2, now(), 1, 1), (5, 2, 0, now(), 1, 1)`)
+	}()
+
+	eng, err := storage.Open(ctx,
+		fs.MustInitPhysicalTestingEnv(storePath),
+		cluster.MakeClusterSettings(),
+		storage.CacheSize(10<<20 /* 10 MiB */),
+		storage.MustExist)
+	require.NoError(t, err)
+	defer eng.Close()
+
+	tenants, err := readTenantConsumption(ctx, eng)
+	require.NoError(t, err)
+	require.Len(t, tenants, 1)
+	tc := tenants[0]
+	require.Equal(t, uint64(5), tc.tenantID)
+	require.Equal(t, 2, tc.instances)
+	require.Equal(t, 500.0, tc.ruCurrent)
+	require.Equal(t, 100.0, tc.ruRefillRate)
+	require.Equal(t, 1000.0, tc.ruBurstLimit)
+	require.Equal(t, 1234.5, tc.consumption.RU)
+	require.Equal(t, uint64(10), tc.consumption.ReadBatches)
+	require.Equal(t, uint64(2048), tc.consumption.WriteBytes)
+
+	rows := formatTenantConsumption(tenants)
+	require.Equal(t, []string{
+		"5", "2024-01-01T00:00:00Z", "2",
+		"500.00", "100.00", "1000.00", "2.00",
+		"1234.50", "0.00", "10", "0", "0", "2048",
+		"0.00", "0.00", "0",
+	}, rows[0])
+}
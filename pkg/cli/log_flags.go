@@ -459,6 +459,7 @@ sinks:
       WARNING: all except [DEV, OPS]
   health:                 { channels: HEALTH  }
   pebble:                 { channels: STORAGE }
+  quota:                  { channels: QUOTA }
   security:               { channels: [PRIVILEGES, USER_ADMIN], auditable: true  }
   sql-auth:               { channels: SESSIONS, auditable: true }
   sql-audit:              { channels: SENSITIVE_ACCESS, auditable: true }
@@ -13,9 +13,11 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"time"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/errors"
@@ -374,6 +377,9 @@ func runDecommissionNode(cmd *cobra.Command, args []string) error {
 	}
 
 	c := serverpb.NewAdminClient(conn)
+	if nodeCtx.nodeDecommissionAnalyze {
+		return runDecommissionAnalyze(ctx, c, nodeIDs)
+	}
 	if err := runDecommissionNodeImpl(ctx, c, nodeCtx.nodeDecommissionWait,
 		nodeCtx.nodeDecommissionChecks, nodeCtx.nodeDecommissionDryRun,
 		nodeIDs, localNodeID,
@@ -704,6 +710,89 @@ func decommissionStatusAndReadinessValueToRows(
 	return rows
 }
 
+var decommissionAnalyzeColumnHeaders = []string{
+	"id",
+	"readiness",
+	"replicas",
+	"logical_bytes",
+}
+
+// maxDecommissionViolationsToReport is the maximum number of ranges whose
+// constraint violations are reported by node decommission --analyze.
+const maxDecommissionViolationsToReport = 20
+
+// runDecommissionAnalyze reports the work involved in decommissioning the
+// given nodes, without decommissioning them. It returns an error if some
+// replicas can't be moved off the nodes.
+func runDecommissionAnalyze(
+	ctx context.Context, c serverpb.AdminClient, nodeIDs []roachpb.NodeID,
+) error {
+	resp, err := c.DecommissionAnalyze(ctx, &serverpb.DecommissionAnalyzeRequest{
+		NodeIDs:             nodeIDs,
+		NumViolationsReport: maxDecommissionViolationsToReport,
+	})
+	if err != nil {
+		return errors.Wrap(err, "while trying to analyze decommission")
+	}
+	if err := sqlExecCtx.PrintQueryOutput(os.Stdout, stderr, decommissionAnalyzeColumnHeaders,
+		clisqlexec.NewRowSliceIter(decommissionAnalysisToRows(resp.Nodes), "rlrr"),
+	); err != nil {
+		return err
+	}
+	printDecommissionAnalysisSummary(os.Stdout, resp)
+	if resp.NumViolations > 0 {
+		return errors.New("Cannot decommission nodes.")
+	}
+	return nil
+}
+
+// decommissionAnalysisToRows converts the analysis of each node to SQL-like
+// result rows, so that we can pretty-print them.
+func decommissionAnalysisToRows(
+	nodes []serverpb.DecommissionAnalyzeResponse_NodeAnalysis,
+) [][]string {
+	rows := make([][]string, 0, len(nodes))
+	for _, node := range nodes {
+		rows = append(rows, []string{
+			strconv.FormatInt(int64(node.NodeID), 10),
+			node.DecommissionReadiness.String(),
+			strconv.FormatInt(node.ReplicaCount, 10),
+			strconv.FormatInt(node.LogicalBytes, 10),
+		})
+	}
+	return rows
+}
+
+// printDecommissionAnalysisSummary prints the replica moves, the volume of
+// data and the estimated duration of the decommission, followed by the
+// reported constraint violations.
+func printDecommissionAnalysisSummary(w io.Writer, resp *serverpb.DecommissionAnalyzeResponse) {
+	fmt.Fprintf(w, "\nranges with replicas on the nodes: %d\n", resp.RangesChecked)
+	actions := make([]string, 0, len(resp.ActionCounts))
+	for action := range resp.ActionCounts {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		fmt.Fprintf(w, "  %s: %d\n", action, resp.ActionCounts[action])
+	}
+	fmt.Fprintf(w, "data to move: %s\n", humanizeutil.IBytes(resp.TotalBytes))
+	fmt.Fprintf(w, "receiving stores: %d, snapshot rate limit: %s/s\n",
+		resp.ReceivingStores, humanizeutil.IBytes(resp.SnapshotRate))
+	fmt.Fprintf(w, "estimated duration: at least %s\n", resp.EstimatedDuration.Round(time.Second))
+
+	if resp.NumViolations == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nranges which cannot be moved off the nodes: %d\n", resp.NumViolations)
+	for _, v := range resp.Violations {
+		fmt.Fprintf(w, "r%d (%s): %s\n", v.RangeID, v.Action, v.Error)
+	}
+	if int64(len(resp.Violations)) < resp.NumViolations {
+		fmt.Fprintf(w, "...%d more\n", resp.NumViolations-int64(len(resp.Violations)))
+	}
+}
+
 var recommissionNodeCmd = &cobra.Command{
 	Use:   "recommission { --self | <node id 1> [<node id 2> ...] }",
 	Short: "recommissions the node(s)",
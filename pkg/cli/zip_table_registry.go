@@ -458,6 +458,8 @@ var zipInternalTablesPerCluster = DebugZipTableRegistry{
 			"last_run",
 			"next_run",
 			"num_runs",
+			"request_units",
+			"estimated_cpu_seconds",
 		},
 	},
 	"crdb_internal.system_jobs": {
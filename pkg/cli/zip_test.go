@@ -80,6 +80,9 @@ table_name NOT IN (
 	'cluster_contended_indexes',
 	'cluster_contended_tables',
 	'cluster_inflight_traces',
+	'cluster_range_costs',
+	'cluster_setting_history',
+	'cluster_txn_wait_for_graph',
 	'cross_db_references',
 	'databases',
 	'forward_dependencies',
@@ -97,9 +100,18 @@ table_name NOT IN (
 	'kv_flow_control_handles',
 	'kv_flow_controller',
 	'kv_flow_token_deductions',
+	'kv_store_encryption_keys',
+	'load_based_split_decisions',
 	'lost_descriptors_with_data',
+	'node_index_read_usage',
+	'node_range_costs',
+	'node_statement_diagnostics_auto_capture',
+	'node_statement_iterator_stats',
+	'raft_proposal_quota',
+	'raft_status',
 	'table_columns',
 	'table_row_statistics',
+	'table_mvcc_garbage',
 	'ranges',
 	'ranges_no_leases',
 	'predefined_comments',
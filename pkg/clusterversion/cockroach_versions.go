@@ -251,6 +251,22 @@ const (
 	// system.statement_diagnostics_requests table.
 	V24_2_StmtDiagRedacted
 
+	// V24_2_SnapshotCompression is the version at which the receivers of
+	// snapshots can decompress the KV batches of snapshots compressed according
+	// to kv.snapshot.compression.codec.
+	V24_2_SnapshotCompression
+
+	// V24_2_TenantBurst is the version at which the token bucket of a tenant
+	// can be granted a burst with ALTER VIRTUAL CLUSTER ... GRANT BURST. The
+	// burst is stored in columns of the system.tenant_usage table that older
+	// versions leave NULL in the per-tenant row.
+	V24_2_TenantBurst
+
+	// V24_2_BackupSystemMetadata is the version at which BACKUP and RESTORE
+	// SYSTEM METADATA can be used. Older nodes don't know the descriptor
+	// coverage of their jobs.
+	V24_2_BackupSystemMetadata
+
 	// *************************************************
 	// Step (1) Add new versions above this comment.
 	// Do not add new versions to a patch release.
@@ -310,7 +326,10 @@ var versionTable = [numKeys]roachpb.Version{
 	// v24.2 versions. Internal versions must be even.
 	V24_2Start: {Major: 24, Minor: 1, Internal: 2},
 
-	V24_2_StmtDiagRedacted: {Major: 24, Minor: 1, Internal: 4},
+	V24_2_StmtDiagRedacted:     {Major: 24, Minor: 1, Internal: 4},
+	V24_2_SnapshotCompression:  {Major: 24, Minor: 1, Internal: 6},
+	V24_2_TenantBurst:          {Major: 24, Minor: 1, Internal: 8},
+	V24_2_BackupSystemMetadata: {Major: 24, Minor: 1, Internal: 10},
 
 	// *************************************************
 	// Step (2): Add new versions above this comment.
@@ -36,6 +36,12 @@ const (
 	VoterConstraints       // voter_constraints
 	LeasePreferences       // lease_preferences
 
-	// NumFields is the number of fields in the config.
+	// NumFields is the number of fields in the config which are shared by
+	// SpanConfig and ZoneConfig.
 	NumFields int = iota - 1
 )
+
+// Fields which only exist in ZoneConfig.
+const (
+	ProtectedTimestampMaxAge Field = Field(NumFields) + 1 + iota // protected_timestamp_max_age_seconds
+)
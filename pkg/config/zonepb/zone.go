@@ -414,6 +414,11 @@ func (z *ZoneConfig) Validate() error {
 		return fmt.Errorf("GC.TTLSeconds %d less than minimum allowed 1", z.GC.TTLSeconds)
 	}
 
+	if z.ProtectedTimestampMaxAgeSeconds != nil && *z.ProtectedTimestampMaxAgeSeconds < 0 {
+		return fmt.Errorf("ProtectedTimestampMaxAgeSeconds %d less than minimum allowed 0",
+			*z.ProtectedTimestampMaxAgeSeconds)
+	}
+
 	for _, constraints := range z.Constraints {
 		for _, constraint := range constraints.Constraints {
 			if constraint.Type == Constraint_DEPRECATED_POSITIVE {
@@ -581,6 +586,11 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 		tempGC := *parent.GC
 		z.GC = &tempGC
 	}
+	if z.ProtectedTimestampMaxAgeSeconds == nil {
+		if parent.ProtectedTimestampMaxAgeSeconds != nil {
+			z.ProtectedTimestampMaxAgeSeconds = proto.Int32(*parent.ProtectedTimestampMaxAgeSeconds)
+		}
+	}
 	if z.ShouldInheritConstraints(parent) {
 		z.Constraints = parent.Constraints
 		z.InheritedConstraints = false
@@ -631,6 +641,11 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 				tempGC := *other.GC
 				z.GC = &tempGC
 			}
+		case "protected_timestamp_max_age_seconds":
+			z.ProtectedTimestampMaxAgeSeconds = nil
+			if other.ProtectedTimestampMaxAgeSeconds != nil {
+				z.ProtectedTimestampMaxAgeSeconds = proto.Int32(*other.ProtectedTimestampMaxAgeSeconds)
+			}
 		case "constraints":
 			z.Constraints = other.Constraints
 			z.InheritedConstraints = other.InheritedConstraints
@@ -77,6 +77,14 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"GC.TTLSeconds 0 less than minimum allowed",
 		},
+		{
+			ZoneConfig{
+				NumReplicas:                     proto.Int32(1),
+				RangeMaxBytes:                   DefaultZoneConfig().RangeMaxBytes,
+				ProtectedTimestampMaxAgeSeconds: proto.Int32(-1),
+			},
+			"ProtectedTimestampMaxAgeSeconds -1 less than minimum allowed 0",
+		},
 		{
 			ZoneConfig{
 				NumReplicas:   proto.Int32(1),
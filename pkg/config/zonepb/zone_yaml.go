@@ -202,6 +202,7 @@ type marshalableZoneConfig struct {
 	RangeMinBytes                *int64            `json:"range_min_bytes" yaml:"range_min_bytes"`
 	RangeMaxBytes                *int64            `json:"range_max_bytes" yaml:"range_max_bytes"`
 	GC                           *GCPolicy         `json:"gc"`
+	ProtectedTimestampMaxAge     *int32            `json:"protected_timestamp_max_age_seconds" yaml:"protected_timestamp_max_age_seconds,omitempty"`
 	GlobalReads                  *bool             `json:"global_reads" yaml:"global_reads"`
 	NumReplicas                  *int32            `json:"num_replicas" yaml:"num_replicas"`
 	NumVoters                    *int32            `json:"num_voters" yaml:"num_voters"`
@@ -225,6 +226,9 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 		tempGC := *c.GC
 		m.GC = &tempGC
 	}
+	if c.ProtectedTimestampMaxAgeSeconds != nil {
+		m.ProtectedTimestampMaxAge = proto.Int32(*c.ProtectedTimestampMaxAgeSeconds)
+	}
 	if c.GlobalReads != nil {
 		m.GlobalReads = proto.Bool(*c.GlobalReads)
 	}
@@ -264,6 +268,9 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 		tempGC := *m.GC
 		c.GC = &tempGC
 	}
+	if m.ProtectedTimestampMaxAge != nil {
+		c.ProtectedTimestampMaxAgeSeconds = proto.Int32(*m.ProtectedTimestampMaxAge)
+	}
 	if m.GlobalReads != nil {
 		c.GlobalReads = proto.Bool(*m.GlobalReads)
 	}
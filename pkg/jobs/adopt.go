@@ -428,6 +428,10 @@ func (r *Registry) runJob(
 	// Make sure that we remove the job from the running set when this returns.
 	defer r.unregister(job.ID())
 
+	// In virtual clusters, attribute the resources consumed by the job's KV and
+	// external I/O operations to the job.
+	ctx = multitenant.WithRUObserver(ctx, job.trackResourceUsage())
+
 	// Bookkeeping.
 	execCtx, cleanup := r.execCtx(ctx, "resume-"+taskName, username)
 	defer cleanup()
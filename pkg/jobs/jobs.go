@@ -61,6 +61,10 @@ type Job struct {
 		progress jobspb.Progress
 		status   Status
 		runStats *RunStats
+		// resourceUsage, if set, accumulates the resources consumed by the
+		// job while it's run by this registry. It is recorded in the progress
+		// of the job whenever the job is updated.
+		resourceUsage *resourceUsage
 	}
 }
 
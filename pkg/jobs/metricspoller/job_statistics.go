@@ -17,13 +17,18 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -97,8 +102,9 @@ const cancelJobTimeout = 60 * time.Second
 // manageProtectedTimestamps manages protected timestamp records owned by
 // various jobs or schedules.. This function mostly concerns itself with
 // collecting statistics related to job PTS records. It also detects PTS records
-// that are too old (as configured by the owner job) and requests job
-// cancellation for those jobs.
+// that are too old (as configured by the owner job, or by the
+// protected_timestamp_max_age_seconds zone configuration of the protected
+// schema objects) and requests job cancellation for those jobs.
 func manageProtectedTimestamps(ctx context.Context, execCtx sql.JobExecContext) error {
 	var ptsStats map[jobspb.Type]*ptsStat
 	var schedulePtsStats map[string]*schedulePTSStat
@@ -123,7 +129,7 @@ func manageProtectedTimestamps(ctx context.Context, execCtx sql.JobExecContext)
 			return err
 		}
 		if err := timeutil.RunWithTimeout(ctx, "cancel-job-old-pts", cancelJobTimeout, func(ctx context.Context) error {
-			return execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+			return execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
 				// Grab the pts within the transaction to ensure we have an up to date view of it.
 				rec, err := execCfg.ProtectedTimestampProvider.WithTxn(txn).GetRecord(ctx, scannedRec.ID.GetUUID())
 				if err != nil {
@@ -161,7 +167,7 @@ func processJobPTSRecord(
 	jobID int64,
 	rec *ptpb.Record,
 	ptsStats map[jobspb.Type]*ptsStat,
-	txn isql.Txn,
+	txn descs.Txn,
 ) error {
 	var stats *ptsStat
 	defer func() {
@@ -197,6 +203,22 @@ func processJobPTSRecord(
 				log.Warningf(ctx, "job %d canceled due to %s", jobID, ptsExpired)
 				return ju.CancelRequestedWithReason(ctx, md, ptsExpired)
 			}
+			// Similarly, verify that the PTS record is not older than the limit
+			// configured on the zones of the protected schema objects. This
+			// requires reading the zone configurations, so it is done last.
+			zoneMaxAge, err := zoneConfigPTSMaxAge(ctx, txn, rec)
+			if err != nil {
+				return err
+			}
+			if zoneMaxAge > 0 &&
+				rec.Timestamp.GoTime().Add(zoneMaxAge).Before(timeutil.Now()) {
+				stats.expired++
+				ptsExpired := errors.Newf(
+					"protected timestamp records %s as of %s (age %s) exceeds zone configured limit of %s",
+					rec.ID, rec.Timestamp, timeutil.Since(rec.Timestamp.GoTime()), zoneMaxAge)
+				log.Warningf(ctx, "job %d canceled due to %s", jobID, ptsExpired)
+				return ju.CancelRequestedWithReason(ctx, md, ptsExpired)
+			}
 			return nil
 		})
 	if err != nil {
@@ -209,6 +231,54 @@ func processJobPTSRecord(
 
 }
 
+// zoneConfigPTSMaxAge returns the smallest protected_timestamp_max_age_seconds
+// zone configuration value which applies to the schema objects protected by
+// the given record, or zero if there is no such limit. Records protecting the
+// whole cluster or tenants are subject to the limit of the default zone.
+// Schema objects which are dropped or no longer exist, e.g. because the record
+// is stale, are not subject to any limit.
+func zoneConfigPTSMaxAge(
+	ctx context.Context, txn descs.Txn, rec *ptpb.Record,
+) (time.Duration, error) {
+	var ids []descpb.ID
+	switch t := rec.Target.GetUnion().(type) {
+	case *ptpb.Target_SchemaObjects:
+		ids = t.SchemaObjects.IDs
+	case *ptpb.Target_Cluster, *ptpb.Target_Tenants:
+		ids = []descpb.ID{keys.RootNamespaceID}
+	default:
+		return 0, nil
+	}
+	var maxAge time.Duration
+	for _, id := range ids {
+		// GetHydratedZoneConfigForTable falls back to the database and default
+		// zone configurations, so it works for database IDs as well.
+		zone, err := sql.GetHydratedZoneConfigForTable(ctx, txn.KV(), txn.Descriptors(), id)
+		if err != nil {
+			if isMissingOrDroppedDescriptorError(err) {
+				continue
+			}
+			return 0, err
+		}
+		if zone.ProtectedTimestampMaxAgeSeconds == nil || *zone.ProtectedTimestampMaxAgeSeconds == 0 {
+			continue
+		}
+		age := time.Duration(*zone.ProtectedTimestampMaxAgeSeconds) * time.Second
+		if maxAge == 0 || age < maxAge {
+			maxAge = age
+		}
+	}
+	return maxAge, nil
+}
+
+// isMissingOrDroppedDescriptorError returns whether the error indicates that a
+// descriptor does not exist or is being dropped.
+func isMissingOrDroppedDescriptorError(err error) bool {
+	return errors.Is(err, catalog.ErrDescriptorNotFound) ||
+		errors.Is(err, catalog.ErrDescriptorDropped) ||
+		sqlerrors.IsMissingDescriptorError(err)
+}
+
 func updateJobPTSMetrics(
 	jobMetrics *jobs.Metrics, clock *hlc.Clock, ptsStats map[jobspb.Type]*ptsStat,
 ) {
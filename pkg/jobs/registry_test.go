@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/keyvisualizer"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/spanconfig"
@@ -1751,3 +1752,54 @@ func TestGetClaimedResumerFromRegistry(t *testing.T) {
 	require.NoError(t, resumer.OnFailOrCancel(ctx, nil, nil))
 	require.Equal(t, 1, counter)
 }
+
+// TestJobResourceUsage verifies that the resources consumed by a job are
+// recorded in its progress and shown by SHOW JOBS.
+func TestJobResourceUsage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	defer ResetConstructors()
+	cleanup := TestingRegisterConstructor(jobspb.TypeImport, func(job *Job, cs *cluster.Settings) Resumer {
+		return jobstest.FakeResumer{
+			OnResume: func(ctx context.Context) error {
+				// Simulate the tenant cost controller reporting the consumption
+				// of the job's KV operations.
+				observer := multitenant.RUObserverFromContext(ctx)
+				if observer == nil {
+					return errors.New("expected an RU observer in the resumer's context")
+				}
+				observer(10, 0.5)
+				observer(5, 0.25)
+				return nil
+			},
+		}
+	}, UsesTenantCostControl)
+	defer cleanup()
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	r := s.JobRegistry().(*Registry)
+	idb := s.InternalDB().(isql.DB)
+	record := Record{
+		JobID:       r.MakeJobID(),
+		Description: "testing",
+		Username:    username.RootUserName(),
+		Details:     jobspb.ImportDetails{},
+		Progress:    jobspb.ImportProgress{},
+	}
+	require.NoError(t, idb.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		_, err := r.CreateJobWithTxn(ctx, record, record.JobID, txn)
+		return err
+	}))
+	require.NoError(t, r.Run(ctx, []jobspb.JobID{record.JobID}))
+
+	var requestUnits, estimatedCPUSeconds float64
+	runner.QueryRow(t, `SELECT request_units, estimated_cpu_seconds FROM [SHOW JOB $1]`,
+		record.JobID).Scan(&requestUnits, &estimatedCPUSeconds)
+	require.Equal(t, 15.0, requestUnits)
+	require.Equal(t, 0.75, estimatedCPUSeconds)
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jobs
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// resourceUsage accumulates the consumption reported to a
+// multitenant.RUObserver for a job, which may be called concurrently.
+type resourceUsage struct {
+	requestUnits        syncutil.AtomicFloat64
+	estimatedCPUSeconds syncutil.AtomicFloat64
+}
+
+func (u *resourceUsage) observe(ru tenantcostmodel.RU, estimatedCPUSeconds float64) {
+	syncutil.AddFloat64(&u.requestUnits, float64(ru))
+	syncutil.AddFloat64(&u.estimatedCPUSeconds, estimatedCPUSeconds)
+}
+
+func (u *resourceUsage) get() jobspb.ResourceUsage {
+	return jobspb.ResourceUsage{
+		RequestUnits:        syncutil.LoadFloat64(&u.requestUnits),
+		EstimatedCPUSeconds: syncutil.LoadFloat64(&u.estimatedCPUSeconds),
+	}
+}
+
+// trackResourceUsage starts accumulating the resources consumed by the job,
+// on top of those already recorded in its progress, and returns the observer
+// to report them to.
+func (j *Job) trackResourceUsage() multitenant.RUObserver {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.mu.resourceUsage == nil {
+		u := &resourceUsage{}
+		syncutil.StoreFloat64(&u.requestUnits, j.mu.progress.ResourceUsage.RequestUnits)
+		syncutil.StoreFloat64(&u.estimatedCPUSeconds, j.mu.progress.ResourceUsage.EstimatedCPUSeconds)
+		j.mu.resourceUsage = u
+	}
+	return j.mu.resourceUsage.observe
+}
+
+// getResourceUsage returns the resources consumed by the job so far, if they
+// are being tracked by this registry.
+func (j *Job) getResourceUsage() (jobspb.ResourceUsage, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.mu.resourceUsage == nil {
+		return jobspb.ResourceUsage{}, false
+	}
+	return j.mu.resourceUsage.get(), true
+}
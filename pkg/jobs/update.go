@@ -171,6 +171,18 @@ WHERE id = $1
 	if err := updateFn(u.txn, md, &ju); err != nil {
 		return err
 	}
+	if usage, ok := j.getResourceUsage(); ok && ju.hasUpdates() {
+		// Record the resources consumed by the job so far along with the update,
+		// if they changed.
+		progress := ju.md.Progress
+		if progress == nil {
+			progress = md.Progress
+		}
+		if progress.ResourceUsage != usage {
+			progress.ResourceUsage = usage
+			ju.UpdateProgress(progress)
+		}
+	}
 	if j.registry.knobs.BeforeUpdate != nil {
 		if err := j.registry.knobs.BeforeUpdate(md, ju.md); err != nil {
 			return err
@@ -219,6 +219,10 @@ var (
 	// is to allow a restarting node to discover approximately how long it has
 	// been down without needing to retrieve liveness records from the cluster.
 	localStoreLastUpSuffix = []byte("uptm")
+	// localStoreLoadSnapshotSuffix stores a periodically refreshed snapshot of
+	// the load statistics of the store's replicas, which is used to warm start
+	// them when the store restarts.
+	localStoreLoadSnapshotSuffix = []byte("load")
 	// localRemovedLeakedRaftEntriesSuffix is DEPRECATED and remains to prevent
 	// reuse.
 	localRemovedLeakedRaftEntriesSuffix = []byte("dlre")
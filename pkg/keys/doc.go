@@ -216,6 +216,7 @@ var _ = [...]interface{}{
 	StoreGossipKey,                   // "goss"
 	StoreHLCUpperBoundKey,            // "hlcu"
 	StoreIdentKey,                    // "iden"
+	StoreLoadSnapshotKey,             // "load"
 	StoreUnsafeReplicaRecoveryKey,    // "loqr"
 	StoreNodeTombstoneKey,            // "ntmb"
 	StoreCachedSettingsKey,           // "stng"
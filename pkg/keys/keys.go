@@ -75,6 +75,12 @@ func StoreLastUpKey() roachpb.Key {
 	return MakeStoreKey(localStoreLastUpSuffix, nil)
 }
 
+// StoreLoadSnapshotKey returns the store-local key for the snapshot of the
+// store's replica load statistics.
+func StoreLoadSnapshotKey() roachpb.Key {
+	return MakeStoreKey(localStoreLoadSnapshotSuffix, nil)
+}
+
 // StoreHLCUpperBoundKey returns the store-local key for storing an upper bound
 // to the wall time used by HLC.
 func StoreHLCUpperBoundKey() roachpb.Key {
@@ -909,6 +909,40 @@ func (db *DB) QueryResolvedTimestamp(
 	return r.ResolvedTS, nil
 }
 
+// QueryResolvedTimestampForKeys returns the minimum resolved timestamp of the
+// given keys, i.e. the maximum timestamp at which all of them can be read
+// without blocking on in-flight writes. See QueryResolvedTimestamp.
+//
+// If nearest is true, the requests are routed to the nearest replicas of the
+// ranges containing the keys, such that the result reflects the timestamp up
+// to which reads can be served by these replicas, e.g. by follower reads. An
+// empty timestamp is returned if no keys are given.
+func (db *DB) QueryResolvedTimestampForKeys(
+	ctx context.Context, keys []roachpb.Key, nearest bool,
+) (hlc.Timestamp, error) {
+	if len(keys) == 0 {
+		return hlc.Timestamp{}, nil
+	}
+	b := &Batch{}
+	for _, key := range keys {
+		b.queryResolvedTimestamp(key, key.Next())
+	}
+	if nearest {
+		b.Header.RoutingPolicy = kvpb.RoutingPolicy_NEAREST
+	}
+	if err := db.Run(ctx, b); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	var resolvedTS hlc.Timestamp
+	for i, ru := range b.RawResponse().Responses {
+		ts := ru.GetQueryResolvedTimestamp().ResolvedTS
+		if i == 0 || ts.Less(resolvedTS) {
+			resolvedTS = ts
+		}
+	}
+	return resolvedTS, nil
+}
+
 // Barrier is a command that waits for conflicting operations such as earlier
 // writes on the specified key range to finish.
 func (db *DB) Barrier(ctx context.Context, begin, end interface{}) (hlc.Timestamp, error) {
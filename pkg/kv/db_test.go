@@ -591,6 +591,52 @@ func TestDB_TxnIterate(t *testing.T) {
 	}
 }
 
+func TestDB_TxnReverseIterate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	s, db := setup(t)
+	defer s.Stopper().Stop(context.Background())
+
+	b := &kv.Batch{}
+	b.Put("aa", "1")
+	b.Put("ab", "2")
+	b.Put("ac", "3")
+	b.Put("bb", "4")
+	if err := db.Run(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+
+	tc := []struct{ pageSize, numPages int }{
+		{1, 3},
+		{2, 2},
+		{3, 1},
+	}
+	for _, c := range tc {
+		var rows []kv.KeyValue
+		var p int
+		if err := db.Txn(context.Background(), func(ctx context.Context, txn *kv.Txn) error {
+			p = 0
+			rows = make([]kv.KeyValue, 0)
+			return txn.ReverseIterate(context.Background(), "a", "b", c.pageSize,
+				func(rs []kv.KeyValue) error {
+					p++
+					rows = append(rows, rs...)
+					return nil
+				})
+		}); err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for _, r := range rows {
+			keys = append(keys, string(r.Key))
+		}
+		require.Equal(t, []string{"ac", "ab", "aa"}, keys)
+		if p != c.numPages {
+			t.Errorf("expected %d pages, got %d", c.numPages, p)
+		}
+	}
+}
+
 func TestDB_Del(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -311,6 +311,18 @@ This counts the number of ranges with an active rangefeed that are performing ca
 		Measurement: "Requests",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaDistSenderCircuitBreakerRangesTripped = metric.Metadata{
+		Name:        "distsender.circuit_breaker.ranges.tripped",
+		Help:        `Number of ranges for which the DistSender circuit breakers of all tracked replicas are currently tripped`,
+		Measurement: "Ranges",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaDistSenderCircuitBreakerRangesRequestsRejected = metric.Metadata{
+		Name:        "distsender.circuit_breaker.ranges.requests.rejected",
+		Help:        `Cumulative number of requests rejected because the DistSender circuit breakers of all replicas of the range were tripped`,
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // metamorphicRouteToLeaseholderFirst is used to control the behavior of the
@@ -439,6 +451,8 @@ type DistSenderCircuitBreakerMetrics struct {
 	ReplicasProbesFailure     *metric.Counter
 	ReplicasRequestsCancelled *metric.Counter
 	ReplicasRequestsRejected  *metric.Counter
+	RangesTripped             *metric.Gauge
+	RangesRequestsRejected    *metric.Counter
 }
 
 func (DistSenderCircuitBreakerMetrics) MetricStruct() {}
@@ -505,6 +519,8 @@ func makeDistSenderCircuitBreakerMetrics() DistSenderCircuitBreakerMetrics {
 		ReplicasProbesFailure:     metric.NewCounter(metaDistSenderCircuitBreakerReplicasProbesFailure),
 		ReplicasRequestsCancelled: metric.NewCounter(metaDistSenderCircuitBreakerReplicasRequestsCancelled),
 		ReplicasRequestsRejected:  metric.NewCounter(metaDistSenderCircuitBreakerReplicasRequestsRejected),
+		RangesTripped:             metric.NewGauge(metaDistSenderCircuitBreakerRangesTripped),
+		RangesRequestsRejected:    metric.NewCounter(metaDistSenderCircuitBreakerRangesRequestsRejected),
 	}
 }
 
@@ -2028,6 +2044,9 @@ func (ds *DistSender) sendPartialBatch(
 	var reply *kvpb.BatchResponse
 	var pErr *kvpb.Error
 	var err error
+	// evictedTrippedRange is set once the descriptor was evicted because the
+	// circuit breakers of all its replicas were tripped.
+	var evictedTrippedRange bool
 
 	// Start a retry loop for sending the batch to the range. Each iteration of
 	// this loop uses a new descriptor. Attempts to send to multiple replicas in
@@ -2191,6 +2210,19 @@ func (ds *DistSender) sendPartialBatch(
 				log.VEventf(ctx, 1, "evicting range desc %s after %s", routingTok, err)
 				routingTok.Evict(ctx)
 				continue
+
+			case errors.Is(err, errRangeCircuitBreakersTripped):
+				// The circuit breakers of all the replicas of the descriptor are
+				// tripped. The descriptor may be stale though, e.g. if the range was
+				// moved to other replicas, so evict it and retry once with a fresh
+				// descriptor before failing fast.
+				if !evictedTrippedRange {
+					log.VEventf(ctx, 1, "evicting range desc %s after %s", routingTok, err)
+					evictedTrippedRange = true
+					routingTok.Evict(ctx)
+					continue
+				}
+				ds.metrics.CircuitBreaker.RangesRequestsRejected.Inc(1)
 			}
 			break
 		}
@@ -2625,12 +2657,26 @@ func (ds *DistSender) sendToReplicas(
 		if err := ds.kvInterceptor.OnRequestWait(ctx); err != nil {
 			return nil, err
 		}
+		// Reject writes that add data if the tenant exceeded its storage limit,
+		// rather than sending them to the host cluster, which rejects them too.
+		// Deletions are still allowed so that the tenant can free up space.
+		if ba.AddsData() {
+			if err := ds.kvInterceptor.CheckLiveBytesLimit(ctx); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// This loop will retry operations that fail with errors that reflect
 	// per-replica state and may succeed on other replicas.
 	var ambiguousError, replicaUnavailableError error
 	var leaseholderUnavailable bool
+	// rangeUnavailableError is set when the request has been rejected by the
+	// tripped circuit breakers of all the replicas tried so far, and cleared once
+	// the request is sent to a replica. If the transport is exhausted while it is
+	// set, the circuit breakers of all replicas of the range are tripped and we
+	// error out instead of retrying the range until a probe succeeds.
+	var rangeUnavailableError error
 	var br *kvpb.BatchResponse
 	attempts := int64(0)
 	for first := true; ; first, attempts = false, attempts+1 {
@@ -2646,8 +2692,17 @@ func (ds *DistSender) sendToReplicas(
 		if lastErr == nil && br != nil {
 			lastErr = br.Error.GoError()
 		}
-		err = skipStaleReplicas(transport, routing, ambiguousError, replicaUnavailableError, lastErr)
+		unavailableError := replicaUnavailableError
+		if unavailableError == nil {
+			unavailableError = rangeUnavailableError
+		}
+		err = skipStaleReplicas(transport, routing, ambiguousError, unavailableError, lastErr)
 		if err != nil {
+			if rangeUnavailableError != nil && err == rangeUnavailableError {
+				// Let sendPartialBatch know that the request wasn't sent, so that
+				// it can refresh the descriptor before failing fast.
+				err = errors.Mark(err, errRangeCircuitBreakersTripped)
+			}
 			return nil, err
 		}
 		curReplica := transport.NextReplica()
@@ -2765,7 +2820,11 @@ func (ds *DistSender) sendToReplicas(
 			// Circuit breaker is tripped. err will be handled below.
 			err = cbErr
 			transport.SkipReplica()
+			if first || rangeUnavailableError != nil {
+				rangeUnavailableError = kvpb.NewReplicaUnavailableError(cbErr, desc, curReplica)
+			}
 		} else {
+			rangeUnavailableError = nil
 			br, err = transport.SendNext(sendCtx, requestToSend)
 			tEnd := timeutil.Now()
 			if cancelErr := cbToken.Done(br, err, tEnd.UnixNano()); cancelErr != nil {
@@ -2911,8 +2970,10 @@ func (ds *DistSender) sendToReplicas(
 					}
 					if !reqInfo.IsWrite() {
 						networkCost := ds.computeNetworkCost(ctx, desc, &curReplica, false /* isWrite */)
-						respInfo = tenantcostmodel.MakeResponseInfo(br, true, networkCost)
+						respInfo = tenantcostmodel.MakeResponseInfo(br, true, networkCost).
+							WithReadKey(ba.Requests[0].GetInner().Header().Key)
 					}
+					reqInfo = reqInfo.WithRangeID(desc.RangeID)
 					if err := ds.kvInterceptor.OnResponseWait(ctx, reqInfo, respInfo); err != nil {
 						return nil, err
 					}
@@ -3179,6 +3240,17 @@ func (ds *DistSender) getLocalityComparison(
 	return comparisonResult
 }
 
+// PreferredBatchBytes returns the size in bytes that the batches sent through
+// the DistSender should preferably reach, as suggested by the KV interceptor
+// based on the state of the tenant's token bucket, or 0 if there is no
+// preference.
+func (ds *DistSender) PreferredBatchBytes() int64 {
+	if ds.kvInterceptor == nil {
+		return 0
+	}
+	return ds.kvInterceptor.PreferredBatchBytes()
+}
+
 // getCostControllerConfig returns the config for the tenant cost model. This
 // returns nil if no KV interceptors are associated with the DistSender, or the
 // KV interceptor is not a multitenant.TenantSideCostController.
@@ -3368,6 +3440,11 @@ func skipStaleReplicas(
 	}
 }
 
+// errRangeCircuitBreakersTripped marks the errors returned by sendToReplicas
+// when the request was rejected by the tripped circuit breakers of all the
+// replicas of the range.
+var errRangeCircuitBreakersTripped = errors.New("circuit breakers of all replicas tripped")
+
 // A sendError indicates that there was a problem communicating with a replica
 // that can evaluate the request. It's possible that the request was, in fact,
 // evaluated by a replica successfully but then the server connection dropped.
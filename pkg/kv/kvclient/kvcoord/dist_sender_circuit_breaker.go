@@ -233,10 +233,12 @@ type cbKey struct {
 // Stale circuit breakers are removed if they haven't seen any traffic for the
 // past GC threshold.
 //
-// TODO(erikgrinaker): we can extend this to also manage range-level circuit
-// breakers, but for now we focus exclusively on replica-level circuit breakers.
-// This avoids the overhead of maintaining and accessing a multi-level
-// structure.
+// There are no separate range-level circuit breakers, which avoids the
+// overhead of maintaining and accessing a multi-level structure. Instead, a
+// range is considered unavailable when the circuit breakers of all its
+// replicas are tripped: the DistSender then fails fast with a
+// ReplicaUnavailableError rather than retrying the range until a probe
+// succeeds.
 //
 // TODO(erikgrinaker): this needs comprehensive testing.
 type DistSenderCircuitBreakers struct {
@@ -303,6 +305,7 @@ func (d *DistSenderCircuitBreakers) probeStallLoop(ctx context.Context) {
 
 		// Don't do anything if circuit breakers have been disabled.
 		if d.Mode() == DistSenderCircuitBreakersNoRanges {
+			d.metrics.CircuitBreaker.RangesTripped.Update(0)
 			continue
 		}
 
@@ -311,18 +314,57 @@ func (d *DistSenderCircuitBreakers) probeStallLoop(ctx context.Context) {
 		nowNanos := timeutil.Now().UnixNano()
 		probeThreshold := CircuitBreakerProbeThreshold.Get(&d.settings.SV)
 
+		// Also collect the ranges with tripped replica circuit breakers, to update
+		// the tripped ranges metric below.
+		var trippedRanges map[roachpb.RangeID]bool
 		d.replicas.Range(func(_, v any) bool {
 			cb := v.(*ReplicaCircuitBreaker)
 
 			// Don't probe if the breaker is already tripped. It will be probed in
 			// response to user traffic, to reduce the number of concurrent probes.
-			if cb.stallDuration(nowNanos) >= probeThreshold && !cb.isTripped() {
-				cb.breaker.Probe()
+			if !cb.isTripped() {
+				if cb.stallDuration(nowNanos) >= probeThreshold {
+					cb.breaker.Probe()
+				}
+			} else {
+				if trippedRanges == nil {
+					trippedRanges = map[roachpb.RangeID]bool{}
+				}
+				trippedRanges[cb.rangeID] = true
 			}
 
 			return true
 		})
+		d.metrics.CircuitBreaker.RangesTripped.Update(d.countTrippedRanges(trippedRanges))
+	}
+}
+
+// countTrippedRanges returns the number of ranges for which the circuit
+// breakers of all tracked replicas are tripped, given the ranges with at least
+// one tripped replica circuit breaker.
+//
+// We don't know which of the tracked replicas are still members of the range,
+// since stale replicas are only removed by GC, so this is an approximation.
+func (d *DistSenderCircuitBreakers) countTrippedRanges(
+	trippedRanges map[roachpb.RangeID]bool,
+) int64 {
+	if len(trippedRanges) == 0 {
+		return 0
+	}
+	d.replicas.Range(func(_, v any) bool {
+		cb := v.(*ReplicaCircuitBreaker)
+		if trippedRanges[cb.rangeID] && !cb.isTripped() {
+			trippedRanges[cb.rangeID] = false
+		}
+		return true
+	})
+	var n int64
+	for _, tripped := range trippedRanges {
+		if tripped {
+			n++
+		}
 	}
+	return n
 }
 
 // gcLoop periodically GCs replica circuit breakers that haven't seen traffic
@@ -123,6 +123,64 @@ func TestDistSenderReplicaStall(t *testing.T) {
 	})
 }
 
+// TestDistSenderRangeUnavailable tests that the DistSender fails fast with a
+// ReplicaUnavailableError when the circuit breakers of all replicas of a range
+// are tripped, instead of retrying the range until a probe succeeds.
+func TestDistSenderRangeUnavailable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	kvcoord.CircuitBreakersMode.Override(
+		ctx, &st.SV, int64(kvcoord.DistSenderCircuitBreakersAllRanges),
+	)
+	kvcoord.CircuitBreakerCancellation.Override(ctx, &st.SV, true)
+	kvcoord.CircuitBreakerProbeThreshold.Override(ctx, &st.SV, time.Second)
+	kvcoord.CircuitBreakerProbeInterval.Override(ctx, &st.SV, time.Second)
+	kvcoord.CircuitBreakerProbeTimeout.Override(ctx, &st.SV, time.Second)
+
+	tc := testcluster.StartTestCluster(t, 1, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+		ServerArgs: base.TestServerArgs{
+			Settings: st,
+		},
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	db := tc.Server(0).ApplicationLayer().DB()
+	metrics := tc.Server(0).DistSenderI().(*kvcoord.DistSender).Metrics().CircuitBreaker
+
+	// Deadlock the only replica of a scratch range.
+	key := tc.ScratchRange(t)
+	desc := tc.LookupRangeOrFatal(t, key)
+	repl, err := tc.GetFirstStoreFromServer(t, 0).GetReplica(desc.RangeID)
+	require.NoError(t, err)
+	mu := repl.GetMutexForTesting()
+	mu.Lock()
+	defer mu.Unlock()
+	t.Logf("deadlocked %s", desc)
+
+	// The read stalls until the replica circuit breaker trips, at which point
+	// the DistSender returns a ReplicaUnavailableError instead of hanging until
+	// the client times out.
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	_, err = db.Get(ctx, key)
+	require.Error(t, err)
+	require.NoError(t, ctx.Err())
+	require.True(t, errors.HasType(err, (*kvpb.ReplicaUnavailableError)(nil)), "%v", err)
+	require.Positive(t, metrics.RangesRequestsRejected.Count())
+
+	// The tripped range is eventually reflected in the metrics.
+	testutils.SucceedsSoon(t, func() error {
+		if n := metrics.RangesTripped.Value(); n != 1 {
+			return errors.Errorf("expected 1 tripped range, got %d", n)
+		}
+		return nil
+	})
+}
+
 // TestDistSenderCircuitBreakerModes validates the behavior of DistSender after
 // a range stall caused by a locked mutex. It tests two different ranges
 // (liveness and regular) and all three different circuit breaker modes.
@@ -6341,6 +6341,18 @@ func (mockTenantSideCostController) OnResponseWait(
 	return nil
 }
 
+func (mockTenantSideCostController) IsThrottled(ctx context.Context) bool {
+	return false
+}
+
+func (mockTenantSideCostController) CheckLiveBytesLimit(ctx context.Context) error {
+	return nil
+}
+
+func (mockTenantSideCostController) PreferredBatchBytes() int64 {
+	return 0
+}
+
 func (mockTenantSideCostController) OnExternalIOWait(
 	ctx context.Context, usage multitenant.ExternalIOUsage,
 ) error {
@@ -6360,10 +6372,30 @@ func (m *mockTenantSideCostController) GetCostConfig() *tenantcostmodel.Config {
 	return m.cfg
 }
 
+func (mockTenantSideCostController) GetHostConsumptionHistory() []multitenant.HostConsumptionSnapshot {
+	return nil
+}
+
+func (mockTenantSideCostController) GetCostHistory() []multitenant.CostSample {
+	return nil
+}
+
+func (mockTenantSideCostController) GetIndexReadUsage(limit int) []multitenant.IndexReadUsage {
+	return nil
+}
+
+func (mockTenantSideCostController) GetRangeCosts() []multitenant.RangeCost {
+	return nil
+}
+
 func (m *mockTenantSideCostController) Metrics() metric.Struct {
 	return nil
 }
 
+func (mockTenantSideCostController) OnBackgroundWorkWait(ctx context.Context) error {
+	return nil
+}
+
 // benchNodeStore mocks out the looking up for node descriptors. On a real
 // system this is done through gossip, but we don't want to include the time to
 // look these up in the test.
@@ -151,6 +151,28 @@ func TestTxnCoordSenderKeyRanges(t *testing.T) {
 }
 
 // Test that the heartbeat loop detects aborted transactions and stops.
+// TestTxnCoordSenderHasAcquiredLocks verifies that locking reads are reported
+// as acquiring locks, even though they don't count as writes.
+func TestTxnCoordSenderHasAcquiredLocks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s := createTestDB(t)
+	defer s.Stop()
+
+	txn := kv.NewTxn(ctx, s.DB, 0 /* gatewayNodeID */)
+	_, err := txn.Get(ctx, roachpb.Key("a"))
+	require.NoError(t, err)
+	require.False(t, txn.Sender().HasAcquiredLocks())
+
+	_, err = txn.GetForUpdate(ctx, roachpb.Key("a"), kvpb.BestEffort)
+	require.NoError(t, err)
+	require.True(t, txn.Sender().HasAcquiredLocks())
+	require.False(t, txn.Sender().HasPerformedWrites())
+	require.NoError(t, txn.Rollback(ctx))
+}
+
 func TestTxnCoordSenderHeartbeat(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
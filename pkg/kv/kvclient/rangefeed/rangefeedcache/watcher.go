@@ -373,6 +373,21 @@ func (s *Watcher[E]) Run(ctx context.Context) error {
 
 var restartErr = errors.New("testing restart requested")
 
+var restartRequestedErr = errors.New("restart requested")
+
+// Restart forces the rangefeed cache to restart, which results in a new
+// initial scan of the watched spans. It returns once the running rangefeed has
+// been asked to stop, or with an error if the context is canceled first (e.g.
+// because the rangefeed is not running).
+func (s *Watcher[E]) Restart(ctx context.Context) error {
+	select {
+	case s.restartErrCh <- restartRequestedErr:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // TestingRestart injects an error into the rangefeed cache, forcing
 // it to restart. This is separate from the testing knob so that we
 // can force a restart from test infrastructure without overriding the
@@ -2366,6 +2366,49 @@ func (c *ContentionEvent) String() string {
 	return redact.StringWithoutMarkers(c)
 }
 
+// TokenBucketProtocolVersion is the version of the TokenBucket protocol
+// between the SQL instances of a tenant and the host cluster.
+type TokenBucketProtocolVersion uint32
+
+const (
+	// TokenBucketProtocolV1 is the original protocol. It is the version of
+	// instances and hosts that leave ProtocolVersion unset.
+	TokenBucketProtocolV1 TokenBucketProtocolVersion = iota
+	// TokenBucketProtocolV2 adds the negotiation of optional features through
+	// TokenBucketCapabilities.
+	TokenBucketProtocolV2
+
+	// TokenBucketProtocolLatest is the latest version of the protocol.
+	TokenBucketProtocolLatest = TokenBucketProtocolV2
+)
+
+// String implements fmt.Stringer.
+func (v TokenBucketProtocolVersion) String() string {
+	return fmt.Sprintf("v%d", uint32(v)+1)
+}
+
+// TokenBucketCapabilities is a set of optional features of the TokenBucket
+// protocol. Instances send the features they support, and hosts respond with
+// the features they enabled among those.
+type TokenBucketCapabilities uint64
+
+const (
+	// TokenBucketCapabilityUnacknowledgedConsumption is set if the instance
+	// reports the consumption of its unacknowledged requests again (see
+	// TokenBucketRequest.UnacknowledgedConsumption), and the host accounts for
+	// it.
+	TokenBucketCapabilityUnacknowledgedConsumption TokenBucketCapabilities = 1 << iota
+	// TokenBucketCapabilityReportInterval is set if the instance follows the
+	// report interval pushed by the host (see
+	// TokenBucketResponse.ReportInterval).
+	TokenBucketCapabilityReportInterval
+)
+
+// Has returns true if all the given capabilities are set.
+func (c TokenBucketCapabilities) Has(other TokenBucketCapabilities) bool {
+	return c&other == other
+}
+
 // Equal returns whether the two structs are identical. Needed for compatibility
 // with proto2.
 func (c *TenantConsumption) Equal(other *TenantConsumption) bool {
@@ -2376,7 +2419,8 @@ func (c *TenantConsumption) Equal(other *TenantConsumption) bool {
 // proto2.
 var _ = (*TenantConsumption).Equal
 
-// Add consumption from the given structure.
+// Add consumption from the given structure. LiveBytes and TotalBytes are not
+// cumulative and are left unchanged.
 func (c *TenantConsumption) Add(other *TenantConsumption) {
 	c.RU += other.RU
 	c.KVRU += other.KVRU
@@ -2391,9 +2435,14 @@ func (c *TenantConsumption) Add(other *TenantConsumption) {
 	c.ExternalIOIngressBytes += other.ExternalIOIngressBytes
 	c.ExternalIOEgressBytes += other.ExternalIOEgressBytes
 	c.CrossRegionNetworkRU += other.CrossRegionNetworkRU
+	c.EstimatedCPUSeconds += other.EstimatedCPUSeconds
+	c.EstimatedKVCPUSeconds += other.EstimatedKVCPUSeconds
+	c.BackupRU += other.BackupRU
+	c.SystemOverheadRU += other.SystemOverheadRU
 }
 
-// Sub subtracts consumption, making sure no fields become negative.
+// Sub subtracts consumption, making sure no fields become negative. LiveBytes
+// and TotalBytes are not cumulative and are left unchanged.
 func (c *TenantConsumption) Sub(other *TenantConsumption) {
 	if c.RU < other.RU {
 		c.RU = 0
@@ -2472,6 +2521,30 @@ func (c *TenantConsumption) Sub(other *TenantConsumption) {
 	} else {
 		c.CrossRegionNetworkRU -= other.CrossRegionNetworkRU
 	}
+
+	if c.EstimatedCPUSeconds < other.EstimatedCPUSeconds {
+		c.EstimatedCPUSeconds = 0
+	} else {
+		c.EstimatedCPUSeconds -= other.EstimatedCPUSeconds
+	}
+
+	if c.EstimatedKVCPUSeconds < other.EstimatedKVCPUSeconds {
+		c.EstimatedKVCPUSeconds = 0
+	} else {
+		c.EstimatedKVCPUSeconds -= other.EstimatedKVCPUSeconds
+	}
+
+	if c.BackupRU < other.BackupRU {
+		c.BackupRU = 0
+	} else {
+		c.BackupRU -= other.BackupRU
+	}
+
+	if c.SystemOverheadRU < other.SystemOverheadRU {
+		c.SystemOverheadRU = 0
+	} else {
+		c.SystemOverheadRU -= other.SystemOverheadRU
+	}
 }
 
 func humanizeCount(n uint64) redact.SafeString {
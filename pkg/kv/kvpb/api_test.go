@@ -317,34 +317,45 @@ func TestContentionEvent_SafeFormat(t *testing.T) {
 
 func TestTenantConsumptionAddSub(t *testing.T) {
 	a := TenantConsumption{
-		RU:                   1,
-		ReadBatches:          2,
-		ReadRequests:         3,
-		ReadBytes:            4,
-		WriteBatches:         5,
-		WriteRequests:        6,
-		WriteBytes:           7,
-		SQLPodsCPUSeconds:    8,
-		PGWireEgressBytes:    9,
-		KVRU:                 10,
-		CrossRegionNetworkRU: 11,
+		RU:                    1,
+		ReadBatches:           2,
+		ReadRequests:          3,
+		ReadBytes:             4,
+		WriteBatches:          5,
+		WriteRequests:         6,
+		WriteBytes:            7,
+		SQLPodsCPUSeconds:     8,
+		PGWireEgressBytes:     9,
+		KVRU:                  10,
+		CrossRegionNetworkRU:  11,
+		EstimatedCPUSeconds:   14,
+		EstimatedKVCPUSeconds: 15,
+		BackupRU:              16,
+		SystemOverheadRU:      17,
+		// LiveBytes and TotalBytes are not cumulative.
+		LiveBytes:  12,
+		TotalBytes: 13,
 	}
 	var b TenantConsumption
 	for i := 0; i < 10; i++ {
 		b.Add(&a)
 	}
 	if exp := (TenantConsumption{
-		RU:                   10,
-		ReadBatches:          20,
-		ReadRequests:         30,
-		ReadBytes:            40,
-		WriteBatches:         50,
-		WriteRequests:        60,
-		WriteBytes:           70,
-		SQLPodsCPUSeconds:    80,
-		PGWireEgressBytes:    90,
-		KVRU:                 100,
-		CrossRegionNetworkRU: 110,
+		RU:                    10,
+		ReadBatches:           20,
+		ReadRequests:          30,
+		ReadBytes:             40,
+		WriteBatches:          50,
+		WriteRequests:         60,
+		WriteBytes:            70,
+		SQLPodsCPUSeconds:     80,
+		PGWireEgressBytes:     90,
+		KVRU:                  100,
+		CrossRegionNetworkRU:  110,
+		EstimatedCPUSeconds:   140,
+		EstimatedKVCPUSeconds: 150,
+		BackupRU:              160,
+		SystemOverheadRU:      170,
 	}); b != exp {
 		t.Errorf("expected\n%#v\ngot\n%#v", exp, b)
 	}
@@ -352,17 +363,21 @@ func TestTenantConsumptionAddSub(t *testing.T) {
 	c := b
 	c.Sub(&a)
 	if exp := (TenantConsumption{
-		RU:                   9,
-		ReadBatches:          18,
-		ReadRequests:         27,
-		ReadBytes:            36,
-		WriteBatches:         45,
-		WriteRequests:        54,
-		WriteBytes:           63,
-		SQLPodsCPUSeconds:    72,
-		PGWireEgressBytes:    81,
-		KVRU:                 90,
-		CrossRegionNetworkRU: 99,
+		RU:                    9,
+		ReadBatches:           18,
+		ReadRequests:          27,
+		ReadBytes:             36,
+		WriteBatches:          45,
+		WriteRequests:         54,
+		WriteBytes:            63,
+		SQLPodsCPUSeconds:     72,
+		PGWireEgressBytes:     81,
+		KVRU:                  90,
+		CrossRegionNetworkRU:  99,
+		EstimatedCPUSeconds:   126,
+		EstimatedKVCPUSeconds: 135,
+		BackupRU:              144,
+		SystemOverheadRU:      153,
 	}); c != exp {
 		t.Errorf("expected\n%#v\ngot\n%#v", exp, c)
 	}
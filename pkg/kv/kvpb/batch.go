@@ -173,6 +173,19 @@ func (ba *BatchRequest) IsWrite() bool {
 	return ba.hasFlag(isWrite)
 }
 
+// AddsData returns true iff the BatchRequest contains requests that can
+// increase the amount of live data, as opposed to only deleting data.
+func (ba *BatchRequest) AddsData() bool {
+	for _, ru := range ba.Requests {
+		switch ru.GetInner().(type) {
+		case *PutRequest, *ConditionalPutRequest, *InitPutRequest,
+			*IncrementRequest, *MergeRequest, *AddSSTableRequest:
+			return true
+		}
+	}
+	return false
+}
+
 // IsReadOnly returns true if all requests within are read-only.
 func (ba *BatchRequest) IsReadOnly() bool {
 	return len(ba.Requests) > 0 && !ba.hasFlag(isWrite|isAdmin)
@@ -117,6 +117,20 @@ var recoveryStoreSelector = settings.RegisterStringSetting(
 	"good",
 )
 
+// MaxNonVoterPromotionCatchUpETA bounds how long a non-voter may be estimated
+// to need to catch up with the raft leader's log for it to be promoted to a
+// voter. Promoting a non-voter that is far behind adds a voter that can't
+// help form a quorum until it has caught up.
+var MaxNonVoterPromotionCatchUpETA = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.allocator.max_non_voter_promotion_catch_up_eta",
+	"non-voters that are estimated to need longer than this to catch up with the "+
+		"raft leader's log are not promoted to voters, unless they replace a dead "+
+		"voter; 0 disables the check",
+	time.Minute,
+	settings.NonNegativeDuration,
+)
+
 // AllocatorAction enumerates the various replication adjustments that may be
 // recommended by the allocator.
 type AllocatorAction int
@@ -595,6 +609,9 @@ type Allocator struct {
 	st            *cluster.Settings
 	deterministic bool
 	nodeLatencyFn func(nodeID roachpb.NodeID) (time.Duration, bool)
+	// networkCosts caches the cross-region network cost table used by the
+	// LeaseLocalityNetworkCost objective.
+	networkCosts *networkCostTableCache
 	// TODO(aayush): Let's replace this with a *rand.Rand that has a rand.Source
 	// wrapped inside a mutex, to avoid misuse.
 	randGen allocatorRand
@@ -646,6 +663,7 @@ func MakeAllocator(
 		st:            st,
 		deterministic: deterministic,
 		nodeLatencyFn: nodeLatencyFn,
+		networkCosts:  &networkCostTableCache{},
 		randGen:       makeAllocatorRand(randSource),
 		Metrics:       makeAllocatorMetrics(),
 		knobs:         knobs,
@@ -2234,6 +2252,14 @@ func (a *Allocator) LeaseholderShouldMoveDueToPreferences(
 	return true
 }
 
+// NonVoterCatchingUpTooSlowly returns whether a non-voter that is estimated to
+// need eta to catch up with the raft leader's log shouldn't be promoted to a
+// voter yet. See MaxNonVoterPromotionCatchUpETA.
+func (a *Allocator) NonVoterCatchingUpTooSlowly(eta time.Duration) bool {
+	maxETA := MaxNonVoterPromotionCatchUpETA.Get(&a.st.SV)
+	return maxETA > 0 && eta > maxETA
+}
+
 // DiskOptions returns the disk options. The disk options are used to determine
 // whether a store has disk capacity for additional replicas; or whether the
 // disk is over capacity and should shed replicas.
@@ -2803,6 +2829,15 @@ func (a Allocator) shouldTransferLeaseForAccessLocality(
 		qpsStats, replicaLocalities, replicaWeights)
 	sourceWeight := math.Max(minReplicaWeight, replicaWeights[source.Node.NodeID])
 
+	// When minimizing the cross-region network cost, the costs replace the
+	// weights and latencies. Fall back to the latter if the costs can't be
+	// computed.
+	var networkCosts map[roachpb.NodeID]float64
+	useNetworkCosts := false
+	if LeaseLocalityObjective(LeaseLocalityObjectiveSetting.Get(&a.st.SV)) == LeaseLocalityNetworkCost {
+		networkCosts, useNetworkCosts = a.replicaNetworkCosts(ctx, qpsStats, replicaLocalities, usageInfo)
+	}
+
 	// TODO(a-robinson): This may not have enough protection against all leases
 	// ending up on a single node in extreme cases. Continue testing against
 	// different situations.
@@ -2816,14 +2851,21 @@ func (a Allocator) shouldTransferLeaseForAccessLocality(
 		if !ok {
 			continue
 		}
-		remoteLatency, ok := a.nodeLatencyFn(repl.NodeID)
-		if !ok {
-			continue
+		var replScore int32
+		var rebalanceAdjustment float64
+		if useNetworkCosts {
+			replScore, rebalanceAdjustment = networkCostLeaseRebalanceScore(
+				ctx, a.st, networkCosts[repl.NodeID], storeDesc, networkCosts[source.Node.NodeID], source,
+				candidateLeasesMean)
+		} else {
+			remoteLatency, ok := a.nodeLatencyFn(repl.NodeID)
+			if !ok {
+				continue
+			}
+			remoteWeight := math.Max(minReplicaWeight, replicaWeights[repl.NodeID])
+			replScore, rebalanceAdjustment = loadBasedLeaseRebalanceScore(
+				ctx, a.st, remoteWeight, remoteLatency, storeDesc, sourceWeight, source, candidateLeasesMean)
 		}
-
-		remoteWeight := math.Max(minReplicaWeight, replicaWeights[repl.NodeID])
-		replScore, rebalanceAdjustment := loadBasedLeaseRebalanceScore(
-			ctx, a.st, remoteWeight, remoteLatency, storeDesc, sourceWeight, source, candidateLeasesMean)
 		if replScore > bestReplScore {
 			bestReplScore = replScore
 			bestRepl = repl
@@ -2902,6 +2944,25 @@ func loadBasedLeaseRebalanceScore(
 	remoteLatencyMillis := float64(remoteLatency) / float64(time.Millisecond)
 	rebalanceAdjustment :=
 		leaseRebalancingAggressiveness.Get(&st.SV) * 0.1 * math.Log10(remoteWeight/sourceWeight) * math.Log1p(remoteLatencyMillis)
+	log.KvDistribution.VEventf(ctx, 5,
+		"node: %d, sourceWeight: %.2f, remoteWeight: %.2f, remoteLatency: %v",
+		remoteStore.Node.NodeID, sourceWeight, remoteWeight, remoteLatency)
+	return leaseCountRebalanceScore(ctx, st, rebalanceAdjustment, remoteStore, source, meanLeases),
+		rebalanceAdjustment
+}
+
+// leaseCountRebalanceScore scores how desirable it would be to transfer a
+// range lease from the source store to a remote store, given the lease counts
+// of the stores and a rebalance adjustment reflecting how much the remote
+// store is preferred for load-based reasons. See loadBasedLeaseRebalanceScore.
+func leaseCountRebalanceScore(
+	ctx context.Context,
+	st *cluster.Settings,
+	rebalanceAdjustment float64,
+	remoteStore roachpb.StoreDescriptor,
+	source roachpb.StoreDescriptor,
+	meanLeases float64,
+) int32 {
 	// Start with twice the base rebalance threshold in order to fight more
 	// strongly against thrashing caused by small variances in the distribution
 	// of request weights.
@@ -2916,14 +2977,12 @@ func loadBasedLeaseRebalanceScore(
 	log.KvDistribution.VEventf(
 		ctx,
 		5,
-		"node: %d, sourceWeight: %.2f, remoteWeight: %.2f, remoteLatency: %v, "+
-			"rebalanceThreshold: %.2f, meanLeases: %.2f, sourceLeaseCount: %d, overfullThreshold: %d, "+
-			"remoteLeaseCount: %d, underfullThreshold: %d, totalScore: %d",
-		remoteStore.Node.NodeID, sourceWeight, remoteWeight, remoteLatency,
-		rebalanceThreshold, meanLeases, source.Capacity.LeaseCount, overfullLeaseThreshold,
-		remoteStore.Capacity.LeaseCount, underfullLeaseThreshold, totalScore,
+		"node: %d, rebalanceThreshold: %.2f, meanLeases: %.2f, sourceLeaseCount: %d, "+
+			"overfullThreshold: %d, remoteLeaseCount: %d, underfullThreshold: %d, totalScore: %d",
+		remoteStore.Node.NodeID, rebalanceThreshold, meanLeases, source.Capacity.LeaseCount,
+		overfullLeaseThreshold, remoteStore.Capacity.LeaseCount, underfullLeaseThreshold, totalScore,
 	)
-	return totalScore, rebalanceAdjustment
+	return totalScore
 }
 
 func (a Allocator) shouldTransferLeaseForLeaseCountConvergence(
@@ -32,9 +32,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/replicastats"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/raft"
 	"github.com/cockroachdb/cockroach/pkg/raft/tracker"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/gossiputil"
@@ -5906,6 +5908,162 @@ func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
 	}
 }
 
+func TestAllocatorTransferLeaseTargetNetworkCost(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	stopper, g, _, storePool, _ := storepool.CreateTestStorePool(ctx, st,
+		liveness.TestTimeUntilNodeDeadOff, true, /* deterministic */
+		func() int { return 10 }, /* nodeCount */
+		livenesspb.NodeLivenessStatus_LIVE)
+	defer stopper.Stop(ctx)
+
+	// 3 stores with the same lease count, each in its own region.
+	regions := map[roachpb.NodeID]string{1: "a", 2: "b", 3: "c"}
+	var stores []*roachpb.StoreDescriptor
+	for i := 1; i <= 3; i++ {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i),
+			Node: roachpb.NodeDescriptor{
+				NodeID:  roachpb.NodeID(i),
+				Address: util.MakeUnresolvedAddr("tcp", strconv.Itoa(i)),
+				Locality: roachpb.Locality{
+					Tiers: []roachpb.Tier{
+						{Key: "region", Value: regions[roachpb.NodeID(i)]},
+					},
+				},
+			},
+			Capacity: roachpb.StoreCapacity{LeaseCount: 10},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+	for _, store := range stores {
+		require.NoError(t, g.SetNodeDescriptor(&store.Node))
+	}
+
+	// All the requests come from region c.
+	now := testingStartTime()
+	stats := replicastats.NewReplicaStats(now, func(nodeID roachpb.NodeID) string {
+		return "region=" + regions[nodeID]
+	})
+	for i := 0; i < 100*int(MinLeaseTransferStatsDuration.Seconds()); i++ {
+		stats.RecordCount(now, 1, 3)
+	}
+	now = now.Add(MinLeaseTransferStatsDuration)
+	localitySummary := stats.SnapshotRatedSummary(now)
+	tenID := roachpb.MustMakeTenantID(10)
+
+	existing := []roachpb.ReplicaDescriptor{
+		{NodeID: 1, StoreID: 1, ReplicaID: 1},
+		{NodeID: 2, StoreID: 2, ReplicaID: 2},
+		{NodeID: 3, StoreID: 3, ReplicaID: 3},
+	}
+	const costTable = `{"regionPairs": [
+		{"fromRegion": "a", "toRegion": "b", "cost": 1},
+		{"fromRegion": "a", "toRegion": "c", "cost": 1},
+		{"fromRegion": "b", "toRegion": "a", "cost": 1},
+		{"fromRegion": "b", "toRegion": "c", "cost": 1},
+		{"fromRegion": "c", "toRegion": "a", "cost": 1},
+		{"fromRegion": "c", "toRegion": "b", "cost": 1}
+	]}`
+
+	testCases := []struct {
+		objective LeaseLocalityObjective
+		costTable string
+		// tenantCostTable, if set, overrides the cost table for the tenant of
+		// the range.
+		tenantCostTable string
+		writeBytes      float64
+		leaseholder     roachpb.StoreID
+		expected        roachpb.StoreID
+	}{
+		// Without latency between the nodes, the latency objective doesn't move
+		// the lease toward the requests.
+		{objective: LeaseLocalityLatency, costTable: costTable, leaseholder: 1, expected: 0},
+		// Without a network cost table, the network cost objective falls back to
+		// the latency objective.
+		{objective: LeaseLocalityNetworkCost, costTable: "", leaseholder: 1, expected: 0},
+		{objective: LeaseLocalityNetworkCost, costTable: costTable, leaseholder: 1, expected: 3},
+		{objective: LeaseLocalityNetworkCost, costTable: costTable, leaseholder: 2, expected: 3},
+		{objective: LeaseLocalityNetworkCost, costTable: costTable, leaseholder: 3, expected: 0},
+		// Writes are sent to every replica wherever the lease is, so moving the
+		// lease barely reduces the cost of a write-heavy range.
+		{objective: LeaseLocalityNetworkCost, costTable: costTable, writeBytes: 100000, leaseholder: 1, expected: 0},
+		// The cost table of the tenant is used rather than the one of the
+		// system tenant.
+		{objective: LeaseLocalityNetworkCost, costTable: "", tenantCostTable: costTable, leaseholder: 1, expected: 3},
+		{objective: LeaseLocalityNetworkCost, costTable: costTable, tenantCostTable: `{"regionPairs": []}`, leaseholder: 1, expected: 0},
+	}
+
+	for i, c := range testCases {
+		t.Run(fmt.Sprintf("%d/%s/s%d", i, c.objective, c.leaseholder), func(t *testing.T) {
+			LeaseLocalityObjectiveSetting.Override(ctx, &st.SV, int64(c.objective))
+			tenantcostmodel.CrossRegionNetworkCostSetting.Override(ctx, &st.SV, c.costTable)
+			a := MakeAllocator(st, true /* deterministic */, func(id roachpb.NodeID) (time.Duration, bool) {
+				return 0, true
+			}, nil)
+			overrides := &testTenantSettingOverrides{}
+			if c.tenantCostTable != "" {
+				overrides.tenants = map[roachpb.TenantID][]kvpb.TenantSetting{
+					tenID: {{
+						InternalKey: tenantcostmodel.CrossRegionNetworkCostSetting.InternalKey(),
+						Value:       settings.EncodedValue{Value: c.tenantCostTable, Type: "s"},
+					}},
+				}
+			}
+			a.SetTenantSettingOverrides(overrides)
+			usage := allocator.RangeUsageInfo{
+				ReadBytesPerSecond:  100,
+				WriteBytesPerSecond: c.writeBytes,
+				RequestLocality: &allocator.RangeRequestLocalityInfo{
+					Counts:   localitySummary.LocalityCounts,
+					Duration: localitySummary.Duration,
+				},
+				TenantID: tenID,
+			}
+			target := a.TransferLeaseTarget(
+				ctx,
+				storePool,
+				&roachpb.RangeDescriptor{},
+				emptySpanConfig(),
+				existing,
+				&mockRepl{
+					replicationFactor: 3,
+					storeID:           c.leaseholder,
+				},
+				usage,
+				false,
+				allocator.TransferLeaseOptions{
+					CheckCandidateFullness: true,
+				},
+			)
+			require.Equal(t, c.expected, target.StoreID)
+		})
+	}
+}
+
+// testTenantSettingOverrides is a TenantSettingOverrides with fixed
+// overrides.
+type testTenantSettingOverrides struct {
+	tenants map[roachpb.TenantID][]kvpb.TenantSetting
+	all     []kvpb.TenantSetting
+}
+
+func (o *testTenantSettingOverrides) GetTenantOverrides(
+	_ context.Context, tenantID roachpb.TenantID,
+) ([]kvpb.TenantSetting, <-chan struct{}) {
+	return o.tenants[tenantID], nil
+}
+
+func (o *testTenantSettingOverrides) GetAllTenantOverrides(
+	context.Context,
+) ([]kvpb.TenantSetting, <-chan struct{}) {
+	return o.all, nil
+}
+
 func TestLoadBasedLeaseRebalanceScore(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -9443,3 +9601,25 @@ func TestAllocatorRebalanceTargetVoterConstraintUnsatisfied(t *testing.T) {
 		})
 	}
 }
+
+// TestAllocatorNonVoterCatchingUpTooSlowly tests that non-voters are only
+// considered too slow to promote when they are estimated to need longer than
+// kv.allocator.max_non_voter_promotion_catch_up_eta to catch up.
+func TestAllocatorNonVoterCatchingUpTooSlowly(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper, _, _, a, _ := CreateTestAllocator(ctx, 1, true /* deterministic */)
+	defer stopper.Stop(ctx)
+
+	MaxNonVoterPromotionCatchUpETA.Override(ctx, &a.st.SV, time.Minute)
+	require.False(t, a.NonVoterCatchingUpTooSlowly(0))
+	require.False(t, a.NonVoterCatchingUpTooSlowly(time.Minute))
+	require.True(t, a.NonVoterCatchingUpTooSlowly(time.Minute+time.Second))
+	require.True(t, a.NonVoterCatchingUpTooSlowly(math.MaxInt64))
+
+	// A zero duration disables the check.
+	MaxNonVoterPromotionCatchUpETA.Override(ctx, &a.st.SV, 0)
+	require.False(t, a.NonVoterCatchingUpTooSlowly(math.MaxInt64))
+}
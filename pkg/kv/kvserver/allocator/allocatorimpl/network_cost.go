@@ -0,0 +1,257 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package allocatorimpl
+
+import (
+	"context"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// LeaseLocalityObjective controls what load-based lease rebalancing
+// ("follow-the-workload") minimizes when moving leases toward the localities
+// that requests come from.
+//
+// The objective only applies to the placement of leases among the existing
+// replicas of a range. It does not influence which stores the replicas are
+// placed on: replica placement is driven by constraints, diversity and load,
+// and the network cost of writes, which are sent to every replica, can only be
+// reduced by placing fewer replicas in remote regions, e.g. with zone
+// configuration constraints.
+type LeaseLocalityObjective int64
+
+const (
+	// LeaseLocalityLatency moves leases toward requests based on the latency
+	// between the nodes holding the replicas.
+	LeaseLocalityLatency LeaseLocalityObjective = iota
+	// LeaseLocalityNetworkCost moves leases toward requests based on the cost
+	// of the cross-region network traffic of the range, as configured in the
+	// tenant_cost_model.cross_region_network_cost setting of the tenant the
+	// range belongs to. Tenants are charged Request Units for this traffic, so
+	// this reduces the cross-region network RUs of multi-region tenants.
+	LeaseLocalityNetworkCost
+)
+
+var leaseLocalityObjectiveMap = map[int64]string{
+	int64(LeaseLocalityLatency):     "latency",
+	int64(LeaseLocalityNetworkCost): "network_cost",
+}
+
+func (o LeaseLocalityObjective) String() string {
+	return leaseLocalityObjectiveMap[int64(o)]
+}
+
+// LeaseLocalityObjectiveSetting is a cluster setting that defines what
+// load-based lease rebalancing minimizes.
+var LeaseLocalityObjectiveSetting = settings.RegisterEnumSetting(
+	settings.SystemOnly,
+	"kv.allocator.load_based_lease_rebalancing.locality_objective",
+	"what load-based lease rebalancing minimizes when moving leases toward the "+
+		"localities requests come from; if set to `latency` it weighs the latency "+
+		"between nodes, if set to `network_cost` it weighs the cross-region network "+
+		"cost configured in tenant_cost_model.cross_region_network_cost; only the "+
+		"placement of leases is affected, not that of replicas",
+	"latency",
+	leaseLocalityObjectiveMap,
+	settings.WithPublic)
+
+// TenantSettingOverrides provides the values of the settings overridden for
+// virtual clusters with ALTER VIRTUAL CLUSTER ... SET CLUSTER SETTING. It is
+// implemented by the tenant settings watcher of the KV server.
+type TenantSettingOverrides interface {
+	// GetTenantOverrides returns the overrides for the given tenant.
+	GetTenantOverrides(
+		ctx context.Context, tenantID roachpb.TenantID,
+	) (overrides []kvpb.TenantSetting, changeCh <-chan struct{})
+	// GetAllTenantOverrides returns the overrides for all tenants.
+	GetAllTenantOverrides(
+		ctx context.Context,
+	) (overrides []kvpb.TenantSetting, changeCh <-chan struct{})
+}
+
+// maxCachedNetworkCostTables bounds the number of distinct network cost
+// tables that are kept parsed.
+const maxCachedNetworkCostTables = 64
+
+// networkCostTableCache caches the parsed network cost tables, so that they
+// aren't parsed on every lease rebalancing decision.
+type networkCostTableCache struct {
+	syncutil.Mutex
+	// overrides, if set, provides the network cost tables of the tenants.
+	overrides TenantSettingOverrides
+	tables    map[string]*tenantcostmodel.NetworkCostTable
+}
+
+// setting returns the value of the network cost setting observed by the given
+// tenant: the override for the tenant, if any, otherwise the override for all
+// tenants, otherwise the value of the system tenant.
+func (c *networkCostTableCache) setting(
+	ctx context.Context, sv *settings.Values, tenantID roachpb.TenantID,
+) string {
+	c.Lock()
+	overrides := c.overrides
+	c.Unlock()
+	if overrides != nil && tenantID.IsSet() && !tenantID.IsSystem() {
+		key := tenantcostmodel.CrossRegionNetworkCostSetting.InternalKey()
+		tenantOverrides, _ := overrides.GetTenantOverrides(ctx, tenantID)
+		if v, ok := findTenantSetting(tenantOverrides, key); ok {
+			return v
+		}
+		allOverrides, _ := overrides.GetAllTenantOverrides(ctx)
+		if v, ok := findTenantSetting(allOverrides, key); ok {
+			return v
+		}
+	}
+	return tenantcostmodel.CrossRegionNetworkCostSetting.Get(sv)
+}
+
+func findTenantSetting(overrides []kvpb.TenantSetting, key settings.InternalKey) (string, bool) {
+	for i := range overrides {
+		if overrides[i].InternalKey == key {
+			return overrides[i].Value.Value, true
+		}
+	}
+	return "", false
+}
+
+// get returns the network cost table configured for the given tenant, or nil
+// if none is configured.
+func (c *networkCostTableCache) get(
+	ctx context.Context, sv *settings.Values, tenantID roachpb.TenantID,
+) *tenantcostmodel.NetworkCostTable {
+	setting := c.setting(ctx, sv, tenantID)
+	c.Lock()
+	defer c.Unlock()
+	table, ok := c.tables[setting]
+	if !ok {
+		if c.tables == nil || len(c.tables) >= maxCachedNetworkCostTables {
+			c.tables = make(map[string]*tenantcostmodel.NetworkCostTable)
+		}
+		// The setting is validated, so it can't fail to parse.
+		table, _ = tenantcostmodel.NewNetworkCostTable(setting)
+		c.tables[setting] = table
+	}
+	return table
+}
+
+// SetTenantSettingOverrides configures where the allocator finds the network
+// cost tables of the tenants for the LeaseLocalityNetworkCost objective. If it
+// isn't set, the table configured for the system tenant is used for all
+// ranges.
+func (a *Allocator) SetTenantSettingOverrides(overrides TenantSettingOverrides) {
+	a.networkCosts.Lock()
+	defer a.networkCosts.Unlock()
+	a.networkCosts.overrides = overrides
+}
+
+// replicaNetworkCosts returns, for each node holding a replica, the relative
+// cost of the cross-region network traffic of the range if that node held the
+// lease, based on the request counts by locality and on the byte rates of the
+// range. Returns false if the costs can't be computed, because no network cost
+// table is configured for the tenant of the range or because some replica has
+// no region.
+//
+// The costs follow the cost model of the tenants (see
+// kvcoord.DistSender.computeNetworkCost): the responses to reads are sent from
+// the leaseholder to the gateway, while writes are sent from the gateway to
+// every replica. Only the cost of the reads depends on where the lease is, but
+// the cost of the writes is included so that the savings of a lease transfer
+// are weighed against the whole cost of the range.
+func (a Allocator) replicaNetworkCosts(
+	ctx context.Context,
+	requestCounts map[string]float64,
+	replicaLocalities map[roachpb.NodeID]roachpb.Locality,
+	usageInfo allocator.RangeUsageInfo,
+) (map[roachpb.NodeID]float64, bool) {
+	if a.networkCosts == nil {
+		return nil, false
+	}
+	table := a.networkCosts.get(ctx, &a.st.SV, usageInfo.TenantID)
+	if table == nil {
+		return nil, false
+	}
+	replicaRegions := make(map[roachpb.NodeID]string, len(replicaLocalities))
+	for nodeID, locality := range replicaLocalities {
+		region, ok := locality.Find("region")
+		if !ok {
+			return nil, false
+		}
+		replicaRegions[nodeID] = region
+	}
+	var totalRequests float64
+	for _, count := range requestCounts {
+		totalRequests += count
+	}
+	if totalRequests == 0 {
+		return nil, false
+	}
+
+	costs := make(map[roachpb.NodeID]float64, len(replicaRegions))
+	for requestLocalityStr, count := range requestCounts {
+		var requestLocality roachpb.Locality
+		if err := requestLocality.Set(requestLocalityStr); err != nil {
+			log.KvDistribution.Errorf(ctx, "unable to parse locality string %q: %+v", requestLocalityStr, err)
+			continue
+		}
+		requestRegion, ok := requestLocality.Find("region")
+		if !ok {
+			continue
+		}
+		// Attribute the traffic of the range to the localities in proportion to
+		// their requests.
+		share := count / totalRequests
+		readBytes := share * usageInfo.ReadBytesPerSecond
+		writeBytes := share * usageInfo.WriteBytesPerSecond
+		var writeCost float64
+		for _, region := range replicaRegions {
+			writeCost += writeBytes * float64(
+				table.Matrix[tenantcostmodel.NetworkPath{FromRegion: requestRegion, ToRegion: region}])
+		}
+		for nodeID, region := range replicaRegions {
+			costs[nodeID] += writeCost + readBytes*float64(
+				table.Matrix[tenantcostmodel.NetworkPath{FromRegion: region, ToRegion: requestRegion}])
+		}
+	}
+	return costs, true
+}
+
+// networkCostLeaseRebalanceScore is the equivalent of
+// loadBasedLeaseRebalanceScore for the LeaseLocalityNetworkCost objective. The
+// rebalance adjustment is the fraction of the cross-region network cost that
+// moving the lease to the remote store would save (or add, when negative),
+// scaled by the lease rebalancing aggressiveness.
+func networkCostLeaseRebalanceScore(
+	ctx context.Context,
+	st *cluster.Settings,
+	remoteCost float64,
+	remoteStore roachpb.StoreDescriptor,
+	sourceCost float64,
+	source roachpb.StoreDescriptor,
+	meanLeases float64,
+) (int32, float64) {
+	var rebalanceAdjustment float64
+	if maxCost := math.Max(remoteCost, sourceCost); maxCost > 0 {
+		rebalanceAdjustment = leaseRebalancingAggressiveness.Get(&st.SV) * (sourceCost - remoteCost) / maxCost
+	}
+	log.KvDistribution.VEventf(ctx, 5,
+		"node: %d, sourceCost: %.2f, remoteCost: %.2f, rebalanceAdjustment: %.2f",
+		remoteStore.Node.NodeID, sourceCost, remoteCost, rebalanceAdjustment)
+	return leaseCountRebalanceScore(ctx, st, rebalanceAdjustment, remoteStore, source, meanLeases),
+		rebalanceAdjustment
+}
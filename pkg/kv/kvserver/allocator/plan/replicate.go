@@ -13,6 +13,7 @@ package plan
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
@@ -104,6 +105,9 @@ type AllocatorReplica interface {
 	LastReplicaAdded() (roachpb.ReplicaID, time.Time)
 	StoreID() roachpb.StoreID
 	GetRangeID() roachpb.RangeID
+	// FollowerCatchUpETA returns how long the given follower is estimated to
+	// need to catch up with the raft log, if there is an estimate.
+	FollowerCatchUpETA(roachpb.ReplicaID) (time.Duration, bool)
 }
 
 // ReplicaPlanner implements the ReplicationPlanner interface.
@@ -408,7 +412,14 @@ func (rp ReplicaPlanner) addOrReplaceVoters(
 				" already has an unexpected replica: %s", newVoter, replDesc)
 		}
 		// If the allocation target has a non-voter already, we will promote it to a
-		// voter.
+		// voter. Unless we're replacing a dead voter, don't do so while the
+		// non-voter is far behind on the raft log, for it wouldn't contribute to
+		// the quorum until it has caught up.
+		if replicaStatus != allocatorimpl.Dead {
+			if err := rp.checkNonVoterPromotion(repl, replDesc); err != nil {
+				return nil, stats, err
+			}
+		}
 		stats.NonVoterPromotionsCount++
 		ops = kvpb.ReplicationChangesForPromotion(newVoter)
 	} else {
@@ -771,6 +782,24 @@ func (rp ReplicaPlanner) removeDead(
 	return op, stats, nil
 }
 
+// checkNonVoterPromotion returns an error if the given non-voter shouldn't be
+// promoted to a voter because it is catching up with the raft log too slowly.
+// See allocatorimpl.MaxNonVoterPromotionCatchUpETA.
+func (rp ReplicaPlanner) checkNonVoterPromotion(
+	repl AllocatorReplica, nonVoter roachpb.ReplicaDescriptor,
+) error {
+	eta, ok := repl.FollowerCatchUpETA(nonVoter.ReplicaID)
+	if !ok || !rp.allocator.NonVoterCatchingUpTooSlowly(eta) {
+		return nil
+	}
+	if eta == math.MaxInt64 {
+		return errors.Errorf("not promoting non-voter %s, which isn't catching up with the raft log",
+			nonVoter)
+	}
+	return errors.Errorf("not promoting non-voter %s, which is estimated to need %s to catch up "+
+		"with the raft log", nonVoter, eta)
+}
+
 func (rp ReplicaPlanner) considerRebalance(
 	ctx context.Context,
 	repl AllocatorReplica,
@@ -834,6 +863,17 @@ func (rp ReplicaPlanner) considerRebalance(
 		return nil, stats, err
 	}
 
+	if performingSwap {
+		// A swap promotes the non-voter on the target store to a voter.
+		if nonVoter, ok := desc.GetReplicaDescriptor(addTarget.StoreID); ok {
+			if err := rp.checkNonVoterPromotion(repl, nonVoter); err != nil {
+				log.KvDistribution.VInfof(ctx, 2, "not rebalancing %s to %+v: %v",
+					rebalanceTargetType, addTarget, err)
+				return nil, stats, nil
+			}
+		}
+	}
+
 	stats = stats.trackRebalanceReplicaCount(rebalanceTargetType)
 	if performingSwap {
 		stats.VoterDemotionsCount++
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/load"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/redact"
 )
@@ -31,6 +32,8 @@ type RangeUsageInfo struct {
 	RequestsPerSecond        float64
 	RaftCPUNanosPerSecond    float64
 	RequestLocality          *RangeRequestLocalityInfo
+	// TenantID is the tenant which the range belongs to, if known.
+	TenantID roachpb.TenantID
 }
 
 // RangeRequestLocalityInfo is the same as PerLocalityCounts and is used for
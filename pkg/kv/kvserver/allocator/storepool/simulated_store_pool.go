@@ -0,0 +1,233 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storepool
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/redact"
+)
+
+// SimulatedStorePool is an implementation of AllocatorStorePool that overlays
+// simulated replica additions and removals on top of an underlying store pool,
+// so that the allocator can plan a sequence of changes across ranges while
+// accounting for the changes planned before, as the allocation simulator does.
+//
+// The underlying store pool is only read: UpdateLocalStoreAfterRebalance
+// updates the simulated capacity of the store instead of the underlying one,
+// and the store descriptors returned by the SimulatedStorePool carry the
+// simulated capacities. The health and liveness of the stores are those of the
+// underlying store pool. Leases are not simulated, so the lease transfer and
+// relocate updates are no-ops.
+type SimulatedStorePool struct {
+	sp AllocatorStorePool
+
+	mu struct {
+		syncutil.Mutex
+		// capacities contains the simulated capacity of the stores updated by
+		// the simulation.
+		capacities map[roachpb.StoreID]roachpb.StoreCapacity
+	}
+}
+
+var _ AllocatorStorePool = &SimulatedStorePool{}
+
+// NewSimulatedStorePool constructs a SimulatedStorePool overlaying simulated
+// changes on top of the given store pool.
+func NewSimulatedStorePool(storePool AllocatorStorePool) *SimulatedStorePool {
+	s := &SimulatedStorePool{sp: storePool}
+	s.mu.capacities = make(map[roachpb.StoreID]roachpb.StoreCapacity)
+	return s
+}
+
+// overlay replaces the capacity of the given descriptors with their simulated
+// capacity, if any.
+func (s *SimulatedStorePool) overlay(descs []roachpb.StoreDescriptor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range descs {
+		if c, ok := s.mu.capacities[descs[i].StoreID]; ok {
+			descs[i].Capacity = c
+		}
+	}
+}
+
+// overlayList returns the given store list with the simulated capacities, and
+// the stats recomputed accordingly.
+func (s *SimulatedStorePool) overlayList(sl StoreList) StoreList {
+	descs := append([]roachpb.StoreDescriptor(nil), sl.Stores...)
+	s.overlay(descs)
+	return MakeStoreList(descs)
+}
+
+func (s *SimulatedStorePool) String() string {
+	return redact.StringWithoutMarkers(s)
+}
+
+// SafeFormat implements the redact.SafeFormatter interface.
+func (s *SimulatedStorePool) SafeFormat(w redact.SafePrinter, _ rune) {
+	w.Printf("simulated %v", s.sp)
+}
+
+// ClusterNodeCount implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) ClusterNodeCount() int {
+	return s.sp.ClusterNodeCount()
+}
+
+// IsDeterministic implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) IsDeterministic() bool {
+	return s.sp.IsDeterministic()
+}
+
+// IsStoreReadyForRoutineReplicaTransfer implements the AllocatorStorePool
+// interface.
+func (s *SimulatedStorePool) IsStoreReadyForRoutineReplicaTransfer(
+	ctx context.Context, targetStoreID roachpb.StoreID,
+) bool {
+	return s.sp.IsStoreReadyForRoutineReplicaTransfer(ctx, targetStoreID)
+}
+
+// Clock implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) Clock() *hlc.Clock {
+	return s.sp.Clock()
+}
+
+// DecommissioningReplicas implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) DecommissioningReplicas(
+	repls []roachpb.ReplicaDescriptor,
+) []roachpb.ReplicaDescriptor {
+	return s.sp.DecommissioningReplicas(repls)
+}
+
+// GetLocalitiesByNode implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) GetLocalitiesByNode(
+	replicas []roachpb.ReplicaDescriptor,
+) map[roachpb.NodeID]roachpb.Locality {
+	return s.sp.GetLocalitiesByNode(replicas)
+}
+
+// GetLocalitiesByStore implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) GetLocalitiesByStore(
+	replicas []roachpb.ReplicaDescriptor,
+) map[roachpb.StoreID]roachpb.Locality {
+	return s.sp.GetLocalitiesByStore(replicas)
+}
+
+// GetStores implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) GetStores() map[roachpb.StoreID]roachpb.StoreDescriptor {
+	stores := s.sp.GetStores()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for storeID, c := range s.mu.capacities {
+		if desc, ok := stores[storeID]; ok {
+			desc.Capacity = c
+			stores[storeID] = desc
+		}
+	}
+	return stores
+}
+
+// GetStoreDescriptor implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) GetStoreDescriptor(
+	storeID roachpb.StoreID,
+) (roachpb.StoreDescriptor, bool) {
+	desc, ok := s.sp.GetStoreDescriptor(storeID)
+	if !ok {
+		return desc, false
+	}
+	descs := []roachpb.StoreDescriptor{desc}
+	s.overlay(descs)
+	return descs[0], true
+}
+
+// GetStoreList implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) GetStoreList(
+	filter StoreFilter,
+) (StoreList, int, ThrottledStoreReasons) {
+	sl, aliveStoreCount, throttled := s.sp.GetStoreList(filter)
+	return s.overlayList(sl), aliveStoreCount, throttled
+}
+
+// GetStoreListFromIDs implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) GetStoreListFromIDs(
+	storeIDs roachpb.StoreIDSlice, filter StoreFilter,
+) (StoreList, int, ThrottledStoreReasons) {
+	sl, aliveStoreCount, throttled := s.sp.GetStoreListFromIDs(storeIDs, filter)
+	return s.overlayList(sl), aliveStoreCount, throttled
+}
+
+// GetStoreListForTargets implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) GetStoreListForTargets(
+	candidates []roachpb.ReplicationTarget, filter StoreFilter,
+) (StoreList, int, ThrottledStoreReasons) {
+	sl, aliveStoreCount, throttled := s.sp.GetStoreListForTargets(candidates, filter)
+	return s.overlayList(sl), aliveStoreCount, throttled
+}
+
+// LiveAndDeadReplicas implements the AllocatorStorePool interface.
+func (s *SimulatedStorePool) LiveAndDeadReplicas(
+	repls []roachpb.ReplicaDescriptor, includeSuspectAndDrainingStores bool,
+) (liveReplicas, deadReplicas []roachpb.ReplicaDescriptor) {
+	return s.sp.LiveAndDeadReplicas(repls, includeSuspectAndDrainingStores)
+}
+
+// UpdateLocalStoreAfterRebalance implements the AllocatorStorePool interface.
+// It updates the simulated capacity of the store, leaving the underlying store
+// pool untouched.
+func (s *SimulatedStorePool) UpdateLocalStoreAfterRebalance(
+	storeID roachpb.StoreID,
+	rangeUsageInfo allocator.RangeUsageInfo,
+	changeType roachpb.ReplicaChangeType,
+) {
+	desc, ok := s.sp.GetStoreDescriptor(storeID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, simulated := s.mu.capacities[storeID]
+	if !simulated {
+		if !ok {
+			// As in StorePool.UpdateLocalStoreAfterRebalance, the store is
+			// unknown and can't be updated.
+			return
+		}
+		c = desc.Capacity
+	}
+	if applyRebalanceToCapacity(&c, rangeUsageInfo, changeType) {
+		s.mu.capacities[storeID] = c
+	}
+}
+
+// UpdateLocalStoresAfterLeaseTransfer implements the AllocatorStorePool
+// interface. It is a no-op, since leases are not simulated.
+func (s *SimulatedStorePool) UpdateLocalStoresAfterLeaseTransfer(
+	_ roachpb.StoreID, _ roachpb.StoreID, _ allocator.RangeUsageInfo,
+) {
+}
+
+// UpdateLocalStoreAfterRelocate implements the AllocatorStorePool interface.
+// It is a no-op, since relocations are simulated as individual additions and
+// removals.
+func (s *SimulatedStorePool) UpdateLocalStoreAfterRelocate(
+	_, _ []roachpb.ReplicationTarget,
+	_, _ []roachpb.ReplicaDescriptor,
+	_ roachpb.StoreID,
+	_ allocator.RangeUsageInfo,
+) {
+}
+
+// SetOnCapacityChange implements the AllocatorStorePool interface. It is a
+// no-op, since the simulated changes aren't capacity changes of the underlying
+// store pool.
+func (s *SimulatedStorePool) SetOnCapacityChange(fn CapacityChangeFn) {
+}
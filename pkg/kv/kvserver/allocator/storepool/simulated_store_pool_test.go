@@ -0,0 +1,86 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storepool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils/gossiputil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSimulatedStorePool verifies that the simulated replica changes are
+// reflected in the store descriptors and store lists of a SimulatedStorePool,
+// without affecting the underlying store pool.
+func TestSimulatedStorePool(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+
+	stopper, g, _, testStorePool, mnl := CreateTestStorePool(ctx, st,
+		liveness.TestTimeUntilNodeDead, true, /* deterministic */
+		func() int { return 2 }, /* nodeCount */
+		livenesspb.NodeLivenessStatus_LIVE)
+	defer stopper.Stop(ctx)
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores([]*roachpb.StoreDescriptor{
+		{
+			StoreID:  1,
+			Node:     roachpb.NodeDescriptor{NodeID: 1},
+			Capacity: roachpb.StoreCapacity{RangeCount: 10, LogicalBytes: 1000},
+		},
+		{
+			StoreID:  2,
+			Node:     roachpb.NodeDescriptor{NodeID: 2},
+			Capacity: roachpb.StoreCapacity{RangeCount: 20, LogicalBytes: 2000},
+		},
+	}, t)
+	for i := 1; i <= 2; i++ {
+		mnl.SetNodeStatus(roachpb.NodeID(i), livenesspb.NodeLivenessStatus_LIVE)
+	}
+
+	sp := NewSimulatedStorePool(testStorePool)
+	usage := allocator.RangeUsageInfo{LogicalBytes: 100}
+	sp.UpdateLocalStoreAfterRebalance(1, usage, roachpb.ADD_VOTER)
+	sp.UpdateLocalStoreAfterRebalance(1, usage, roachpb.ADD_NON_VOTER)
+	sp.UpdateLocalStoreAfterRebalance(2, usage, roachpb.REMOVE_VOTER)
+	// Unknown stores are ignored.
+	sp.UpdateLocalStoreAfterRebalance(3, usage, roachpb.ADD_VOTER)
+
+	desc, ok := sp.GetStoreDescriptor(1)
+	require.True(t, ok)
+	require.Equal(t, int32(12), desc.Capacity.RangeCount)
+	require.Equal(t, int64(1200), desc.Capacity.LogicalBytes)
+	require.Equal(t, int32(19), sp.GetStores()[2].Capacity.RangeCount)
+	_, ok = sp.GetStoreDescriptor(3)
+	require.False(t, ok)
+
+	sl, aliveStoreCount, _ := sp.GetStoreList(StoreFilterNone)
+	require.Equal(t, 2, aliveStoreCount)
+	require.Equal(t, 15.5, sl.CandidateRanges.Mean)
+	sl, _, _ = sp.GetStoreListFromIDs(roachpb.StoreIDSlice{1}, StoreFilterNone)
+	require.Equal(t, 12.0, sl.CandidateRanges.Mean)
+
+	// The underlying store pool is untouched.
+	desc, ok = testStorePool.GetStoreDescriptor(1)
+	require.True(t, ok)
+	require.Equal(t, int32(10), desc.Capacity.RangeCount)
+	require.Equal(t, int64(1000), desc.Capacity.LogicalBytes)
+}
@@ -502,41 +502,55 @@ func (sp *StorePool) UpdateLocalStoreAfterRebalance(
 		// network). We can't update the local store at this time.
 		return
 	}
+	if !applyRebalanceToCapacity(&detail.Desc.Capacity, rangeUsageInfo, changeType) {
+		return
+	}
+	sp.DetailsMu.StoreDetails[storeID] = &detail
+}
+
+// applyRebalanceToCapacity updates the given store capacity as though a replica
+// with the given usage were added to or removed from the store. It returns
+// false if the change type is neither an addition nor a removal.
+func applyRebalanceToCapacity(
+	c *roachpb.StoreCapacity,
+	rangeUsageInfo allocator.RangeUsageInfo,
+	changeType roachpb.ReplicaChangeType,
+) bool {
 	// Only apply the raft cpu delta on rebalance. This estimate assumes that
 	// the raft cpu usage is approximately equal across replicas for a range.
 	switch changeType {
 	case roachpb.ADD_VOTER, roachpb.ADD_NON_VOTER:
-		detail.Desc.Capacity.RangeCount++
-		detail.Desc.Capacity.LogicalBytes += rangeUsageInfo.LogicalBytes
-		detail.Desc.Capacity.WritesPerSecond += rangeUsageInfo.WritesPerSecond
-		if detail.Desc.Capacity.CPUPerSecond >= 0 {
-			detail.Desc.Capacity.CPUPerSecond += rangeUsageInfo.RaftCPUNanosPerSecond
+		c.RangeCount++
+		c.LogicalBytes += rangeUsageInfo.LogicalBytes
+		c.WritesPerSecond += rangeUsageInfo.WritesPerSecond
+		if c.CPUPerSecond >= 0 {
+			c.CPUPerSecond += rangeUsageInfo.RaftCPUNanosPerSecond
 		}
 	case roachpb.REMOVE_VOTER, roachpb.REMOVE_NON_VOTER:
-		detail.Desc.Capacity.RangeCount--
-		if detail.Desc.Capacity.LogicalBytes <= rangeUsageInfo.LogicalBytes {
-			detail.Desc.Capacity.LogicalBytes = 0
+		c.RangeCount--
+		if c.LogicalBytes <= rangeUsageInfo.LogicalBytes {
+			c.LogicalBytes = 0
 		} else {
-			detail.Desc.Capacity.LogicalBytes -= rangeUsageInfo.LogicalBytes
+			c.LogicalBytes -= rangeUsageInfo.LogicalBytes
 		}
-		if detail.Desc.Capacity.WritesPerSecond <= rangeUsageInfo.WritesPerSecond {
-			detail.Desc.Capacity.WritesPerSecond = 0
+		if c.WritesPerSecond <= rangeUsageInfo.WritesPerSecond {
+			c.WritesPerSecond = 0
 		} else {
-			detail.Desc.Capacity.WritesPerSecond -= rangeUsageInfo.WritesPerSecond
+			c.WritesPerSecond -= rangeUsageInfo.WritesPerSecond
 		}
 		// When CPU attribution is unsupported, the store will set the
 		// CPUPerSecond of its store capacity to be -1.
-		if detail.Desc.Capacity.CPUPerSecond >= 0 {
-			if detail.Desc.Capacity.CPUPerSecond <= rangeUsageInfo.RaftCPUNanosPerSecond {
-				detail.Desc.Capacity.CPUPerSecond = 0
+		if c.CPUPerSecond >= 0 {
+			if c.CPUPerSecond <= rangeUsageInfo.RaftCPUNanosPerSecond {
+				c.CPUPerSecond = 0
 			} else {
-				detail.Desc.Capacity.CPUPerSecond -= rangeUsageInfo.RaftCPUNanosPerSecond
+				c.CPUPerSecond -= rangeUsageInfo.RaftCPUNanosPerSecond
 			}
 		}
 	default:
-		return
+		return false
 	}
-	sp.DetailsMu.StoreDetails[storeID] = &detail
+	return true
 }
 
 // UpdateLocalStoreAfterRelocate is used to update the local copy of the
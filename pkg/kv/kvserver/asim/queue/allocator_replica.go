@@ -117,6 +117,13 @@ func (sr *SimulatorReplica) LastReplicaAdded() (roachpb.ReplicaID, time.Time) {
 	return sr.Desc().NextReplicaID - 1, timeutil.Now()
 }
 
+// FollowerCatchUpETA returns how long the given follower is estimated to need
+// to catch up with the raft log. The simulator doesn't model raft log
+// replication, so there is never an estimate.
+func (sr *SimulatorReplica) FollowerCatchUpETA(roachpb.ReplicaID) (time.Duration, bool) {
+	return 0, false
+}
+
 // OwnsValidLease returns whether this replica is the current valid
 // leaseholder.
 func (sr *SimulatorReplica) OwnsValidLease(context.Context, hlc.ClockTimestamp) bool {
@@ -131,5 +131,8 @@ func ReverseScan(
 	}
 
 	res.Local.EncounteredIntents = scanRes.Intents
+	if scanRes.NumSkippedLocked > 0 {
+		res.Local.Metrics = &result.Metrics{SkipLockedSkipped: int(scanRes.NumSkippedLocked)}
+	}
 	return res, nil
 }
@@ -133,6 +133,9 @@ func Scan(
 	}
 
 	res.Local.EncounteredIntents = scanRes.Intents
+	if scanRes.NumSkippedLocked > 0 {
+		res.Local.Metrics = &result.Metrics{SkipLockedSkipped: int(scanRes.NumSkippedLocked)}
+	}
 	return res, nil
 }
 
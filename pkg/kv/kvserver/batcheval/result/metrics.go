@@ -23,6 +23,7 @@ type Metrics struct {
 	AddSSTableAsWrites           int // AddSSTable requests with IngestAsWrites set
 	SplitsWithEstimatedStats     int // Splits that computed stats estimates
 	SplitEstimatedTotalBytesDiff int // Difference between pre- and post-split total bytes.
+	SkipLockedSkipped            int // keys or rows skipped over by SkipLocked reads
 }
 
 // Add absorbs the supplied Metrics into the receiver.
@@ -37,4 +38,5 @@ func (mt *Metrics) Add(o Metrics) {
 	mt.AddSSTableAsWrites += o.AddSSTableAsWrites
 	mt.SplitsWithEstimatedStats += o.SplitsWithEstimatedStats
 	mt.SplitEstimatedTotalBytesDiff += o.SplitEstimatedTotalBytesDiff
+	mt.SkipLockedSkipped += o.SkipLockedSkipped
 }
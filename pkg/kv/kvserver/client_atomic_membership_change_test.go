@@ -126,3 +126,49 @@ func TestAtomicReplicationChange(t *testing.T) {
 	// Only a lone voter on s5 should be left over.
 	checkDesc(desc, 5)
 }
+
+// TestBatchedReplicationChange verifies that, with
+// kv.replication_changes.batched.enabled, AdminChangeReplicas carries out a
+// change touching several voters and non-voters in fewer range descriptor
+// updates, and thus fewer raft round trips, than it does by default.
+func TestBatchedReplicationChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	tc := testcluster.StartTestCluster(t, 5, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	// Create two ranges with voters on n1, n2 and n3 and a non-voter on n4.
+	k := tc.ScratchRange(t)
+	tc.AddVotersOrFatal(t, k, tc.Target(1), tc.Target(2))
+	tc.AddNonVotersOrFatal(t, k, tc.Target(3))
+	kBatched := append(k[:len(k):len(k)], 'b')
+	tc.SplitRangeOrFatal(t, kBatched)
+
+	// Replace the voter on n3 with one on n5, and remove the non-voter, and
+	// return the number of range descriptor updates it took.
+	runChange := func(key roachpb.Key) int64 {
+		t.Helper()
+		desc := tc.LookupRangeOrFatal(t, key)
+		newDesc, err := tc.Servers[0].DB().AdminChangeReplicas(ctx, key, desc, []kvpb.ReplicationChange{
+			{ChangeType: roachpb.ADD_VOTER, Target: tc.Target(4)},
+			{ChangeType: roachpb.REMOVE_VOTER, Target: tc.Target(2)},
+			{ChangeType: roachpb.REMOVE_NON_VOTER, Target: tc.Target(3)},
+		})
+		require.NoError(t, err)
+		require.Len(t, newDesc.Replicas().VoterDescriptors(), 3)
+		require.Empty(t, newDesc.Replicas().NonVoterDescriptors())
+		require.Empty(t, newDesc.Replicas().LearnerDescriptors())
+		_, ok := newDesc.GetReplicaDescriptor(tc.Target(4).StoreID)
+		require.True(t, ok)
+		return int64(newDesc.Generation - desc.Generation)
+	}
+
+	unbatched := runChange(k)
+	kvserver.BatchedReplicationChangesEnabled.Override(ctx, &tc.Server(0).ClusterSettings().SV, true)
+	batched := runChange(kBatched)
+	require.Less(t, batched, unbatched)
+}
@@ -2621,6 +2621,22 @@ func TestQuotaPool(t *testing.T) {
 			_, pErr := leaderRepl.Send(ctx, ba)
 			ch <- pErr
 		}()
+
+		// The second write waits for quota, which is reflected in the range info
+		// and the store metrics.
+		testutils.SucceedsSoon(t, func() error {
+			if waiters := leaderRepl.State(ctx).ProposalQuotaWaiters; waiters != 1 {
+				return errors.Errorf("expected 1 proposal waiting for quota, found: %d", waiters)
+			}
+			return nil
+		})
+		ri := leaderRepl.State(ctx)
+		require.Equal(t, int64(quota), ri.ProposalQuotaCapacity)
+		require.Greater(t, ri.ProposalQuotaLongestWaitNanos, int64(0))
+		leaderStore := leaderRepl.Store()
+		require.NoError(t, leaderStore.ComputeMetrics(ctx))
+		require.Equal(t, int64(1), leaderStore.Metrics().RaftQuotaPoolWaiters.Value())
+		require.Greater(t, leaderStore.Metrics().RaftQuotaPoolLongestWait.Value(), int64(0))
 	}()
 
 	testutils.SucceedsSoon(t, func() error {
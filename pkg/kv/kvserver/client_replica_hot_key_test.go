@@ -0,0 +1,85 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/bootstrap"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplicaHotKeyProtection verifies that ranges of user data with a hot key
+// close timestamps in the future and request extra non-voters, as configured
+// by the hot key protection cluster settings.
+func TestReplicaHotKeyProtection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s := serverutils.StartServerOnly(t, base.TestServerArgs{
+		DisableSQLServer: true,
+		Knobs: base.TestingKnobs{
+			Store: &kvserver.StoreTestingKnobs{
+				DisableMergeQueue: true,
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	store, err := s.GetStores().(*kvserver.Stores).GetStore(s.GetFirstStoreID())
+	require.NoError(t, err)
+	sv := &store.ClusterSettings().SV
+
+	userKey := keys.SystemSQLCodec.TablePrefix(bootstrap.TestingUserDescID(0))
+	_, pErr := kv.SendWrapped(ctx, store.TestSender(), adminSplitArgs(userKey))
+	require.Nil(t, pErr)
+	userRepl := store.LookupReplica(roachpb.RKey(userKey))
+	systemRepl := store.LookupReplica(roachpb.RKeyMin)
+
+	addNonVoters := func(repl *kvserver.Replica) roachpb.SpanConfig {
+		conf := roachpb.SpanConfig{NumReplicas: 3}
+		repl.MaybeAddHotKeyNonVoters(&conf)
+		return conf
+	}
+
+	// Without a hot key, the protection doesn't apply.
+	kvserver.HotKeyFollowerReadsEnabled.Override(ctx, sv, true)
+	kvserver.HotKeyExtraNonVoters.Override(ctx, sv, 2)
+	require.Equal(t, roachpb.LAG_BY_CLUSTER_SETTING, userRepl.ClosedTimestampPolicy())
+	require.Equal(t, roachpb.SpanConfig{NumReplicas: 3}, addNonVoters(userRepl))
+
+	// With a hot key, ranges of user data are protected.
+	userRepl.SetHotKey(true)
+	require.Equal(t, roachpb.LEAD_FOR_GLOBAL_READS, userRepl.ClosedTimestampPolicy())
+	require.Equal(t, roachpb.SpanConfig{NumReplicas: 5, NumVoters: 3}, addNonVoters(userRepl))
+
+	// System ranges are never protected.
+	systemRepl.SetHotKey(true)
+	defer systemRepl.SetHotKey(false)
+	require.Equal(t, roachpb.LAG_BY_CLUSTER_SETTING, systemRepl.ClosedTimestampPolicy())
+	require.Equal(t, roachpb.SpanConfig{NumReplicas: 3}, addNonVoters(systemRepl))
+
+	// The protection is disabled by the cluster settings.
+	kvserver.HotKeyFollowerReadsEnabled.Override(ctx, sv, false)
+	kvserver.HotKeyExtraNonVoters.Override(ctx, sv, 0)
+	require.Equal(t, roachpb.LAG_BY_CLUSTER_SETTING, userRepl.ClosedTimestampPolicy())
+	require.Equal(t, roachpb.SpanConfig{NumReplicas: 3}, addNonVoters(userRepl))
+	userRepl.SetHotKey(false)
+}
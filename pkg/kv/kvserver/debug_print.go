@@ -362,9 +362,29 @@ func tryRaftLogEntry(kv storage.MVCCKeyValue) (string, error) {
 		return "", err
 	}
 	defer e.Release()
+	return sprintRaftLogEntry(e), nil
+}
+
+// SprintRaftLogEntry decodes the given raft log entry and returns a
+// human-readable description of it, including the kind of command it carries
+// and the contents of its write batch.
+func SprintRaftLogEntry(ent raftpb.Entry) (string, error) {
+	e, err := raftlog.NewEntry(ent)
+	if err != nil {
+		return "", err
+	}
+	defer e.Release()
+	return sprintRaftLogEntry(e), nil
+}
 
+func sprintRaftLogEntry(e *raftlog.Entry) string {
 	if len(e.Data) == 0 {
-		return fmt.Sprintf("%s: EMPTY\n", &e.Entry), nil
+		return fmt.Sprintf("%s: EMPTY\n", &e.Entry)
+	}
+	kind := raftLogEntryKind(e)
+	var confChangeStr string
+	if cc := e.ConfChange(); cc != nil {
+		confChangeStr = fmt.Sprintf("conf change: %s\n", raftpb.ConfChangesToString(cc.AsV2().Changes))
 	}
 	e.Data = nil
 	cmd := e.Cmd
@@ -375,8 +395,35 @@ func tryRaftLogEntry(kv storage.MVCCKeyValue) (string, error) {
 	}
 	cmd.WriteBatch = nil
 
-	return fmt.Sprintf("%s (ID %s) by lease #%d\n%s\nwrite batch:\n%s",
-		&e.Entry, e.ID, cmd.ProposerLeaseSequence, &cmd, wbStr), nil
+	return fmt.Sprintf("%s (ID %s) by lease #%d: %s\n%s%s\nwrite batch:\n%s",
+		&e.Entry, e.ID, cmd.ProposerLeaseSequence, kind, confChangeStr, &cmd, wbStr)
+}
+
+// raftLogEntryKind returns a short description of the kind of command carried
+// by the given non-empty raft log entry.
+func raftLogEntryKind(e *raftlog.Entry) string {
+	if e.ConfChange() != nil {
+		return "conf change"
+	}
+	res := &e.Cmd.ReplicatedEvalResult
+	switch {
+	case res.IsLeaseRequest:
+		return "lease request"
+	case res.Split != nil:
+		return "split"
+	case res.Merge != nil:
+		return "merge"
+	case res.State != nil && res.State.TruncatedState != nil:
+		return "log truncation"
+	case res.ComputeChecksum != nil:
+		return "consistency check"
+	case res.AddSSTable != nil:
+		return "AddSSTable"
+	case res.IsProbe:
+		return "probe"
+	default:
+		return "write batch"
+	}
 }
 
 func tryTxn(kv storage.MVCCKeyValue) (string, error) {
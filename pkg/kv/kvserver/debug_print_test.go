@@ -16,12 +16,15 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/raftlog"
+	"github.com/cockroachdb/cockroach/pkg/raft/raftpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/pebble"
 	"github.com/stretchr/testify/require"
 )
@@ -113,3 +116,44 @@ func TestStringifyWriteBatch(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "Delete Range Keys: /db{1-2} (0x2f64623100-0x2f64623200)\n", s)
 }
+
+func TestSprintRaftLogEntry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s, err := SprintRaftLogEntry(raftpb.Entry{Term: 5, Index: 10})
+	require.NoError(t, err)
+	require.Contains(t, s, "EMPTY")
+
+	for _, tc := range []struct {
+		res  kvserverpb.ReplicatedEvalResult
+		kind string
+	}{
+		{kvserverpb.ReplicatedEvalResult{}, "write batch"},
+		{kvserverpb.ReplicatedEvalResult{IsLeaseRequest: true}, "lease request"},
+		{kvserverpb.ReplicatedEvalResult{Split: &kvserverpb.Split{}}, "split"},
+		{kvserverpb.ReplicatedEvalResult{Merge: &kvserverpb.Merge{}}, "merge"},
+		{kvserverpb.ReplicatedEvalResult{IsProbe: true}, "probe"},
+	} {
+		t.Run(tc.kind, func(t *testing.T) {
+			data, err := protoutil.Marshal(&kvserverpb.RaftCommand{ReplicatedEvalResult: tc.res})
+			require.NoError(t, err)
+			ent := raftpb.Entry{
+				Term:  5,
+				Index: 11,
+				Type:  raftpb.EntryNormal,
+				Data: raftlog.EncodeCommandBytes(
+					raftlog.EntryEncodingStandardWithoutAC, raftlog.MakeCmdIDKey(), data),
+			}
+
+			e, err := raftlog.NewEntry(ent)
+			require.NoError(t, err)
+			defer e.Release()
+			require.Equal(t, tc.kind, raftLogEntryKind(e))
+
+			s, err := SprintRaftLogEntry(ent)
+			require.NoError(t, err)
+			require.Contains(t, s, ": "+tc.kind+"\n")
+		})
+	}
+}
@@ -210,6 +210,15 @@ import (
 //   - onDestroyed is when the replica is destroyed. Like onBecameFollower, we
 //     close the underlying kvflowcontrol.Handle and clear other tracking state.
 //
+//   - onSplit is invoked on the RHS of a split, and onMerge on the LHS of a
+//     merge, with the flowControlStreamState of the range they were split off
+//     from or subsumed. Replicas of the new range start off with fresh raft
+//     progress (probing followers), which would otherwise have the leader
+//     disconnect every stream and only reconnect them once raft has caught
+//     up. Instead we carry over the set of streams that were connected, and
+//     keep them connected when assuming raft leadership, avoiding replication
+//     stalls on hot ranges right after a split.
+//
 // TODO(irfansharif): Today, whenever a raft transport stream breaks, we
 // propagate O(replicas) notifications. We could do something simpler --
 // bump a sequence number for stores that have been disconnected and lazily
@@ -223,8 +232,21 @@ type replicaFlowControlIntegration interface {
 	onRaftTransportDisconnected(context.Context, ...roachpb.StoreID)
 	onRaftTicked(context.Context)
 	onDestroyed(context.Context)
+	onSplit(ctx context.Context, lhs flowControlStreamState)
+	onMerge(ctx context.Context, rhs flowControlStreamState)
 
 	handle() (kvflowcontrol.Handle, bool)
+	streamState() flowControlStreamState
+}
+
+// flowControlStreamState is the per-follower flow control state that's
+// transferred across range splits and merges. Since all replicas of the ranges
+// involved are colocated, it's keyed by store.
+type flowControlStreamState struct {
+	// connected is the set of stores the source replica, as raft leader, was
+	// actively replicating to. It's empty if the source replica wasn't the
+	// leader.
+	connected map[roachpb.StoreID]struct{}
 }
 
 // replicaForFlowControl abstracts the interface of an individual Replica, as
@@ -242,6 +264,7 @@ type replicaForFlowControl interface {
 
 	annotateCtx(context.Context) context.Context
 	assertLocked()        // only affects test builds
+	assertRLocked()       // only affects test builds
 	isScratchRange() bool // only used in tests
 }
 
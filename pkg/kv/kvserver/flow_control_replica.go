@@ -30,6 +30,10 @@ func (rf *replicaFlowControl) assertLocked() {
 	rf.mu.AssertHeld()
 }
 
+func (rf *replicaFlowControl) assertRLocked() {
+	rf.mu.AssertRHeld()
+}
+
 func (rf *replicaFlowControl) annotateCtx(ctx context.Context) context.Context {
 	return rf.AnnotateCtx(ctx)
 }
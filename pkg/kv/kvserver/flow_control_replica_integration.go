@@ -51,6 +51,17 @@ type replicaFlowControlIntegrationImpl struct {
 	// This does not include replicas that are no longer part of the range,
 	// since we're not looking to reconnect to them in the future.
 	disconnectedStreams map[roachpb.ReplicaID]kvflowcontrol.Stream
+
+	// inheritedStreams is the set of stores whose replication streams were
+	// connected on the range we were split off from or subsumed (see onSplit
+	// and onMerge). It's recorded while we're not the leader, and only applied
+	// if we assume raft leadership in the term immediately following
+	// inheritedTerm. Once applied, followers on these stores are not considered
+	// behind while raft is still probing them; they're untracked once raft
+	// observes them as caught up, or once their streams are disconnected for
+	// any reason.
+	inheritedStreams map[roachpb.StoreID]struct{}
+	inheritedTerm    uint64
 }
 
 var _ replicaFlowControlIntegration = &replicaFlowControlIntegrationImpl{}
@@ -97,6 +108,12 @@ func (f *replicaFlowControlIntegrationImpl) onBecameLeader(ctx context.Context)
 	)
 	f.lastKnownReplicas = f.replicaForFlowControl.getDescriptor().Replicas()
 	f.disconnectedStreams = make(map[roachpb.ReplicaID]kvflowcontrol.Stream)
+	if f.inheritedStreams != nil &&
+		f.replicaForFlowControl.getAppliedLogPosition().Term != f.inheritedTerm+1 {
+		// Some other replica was the leader in the interim; what we inherited
+		// is stale.
+		f.inheritedStreams = nil
+	}
 
 	// Connect to the local stream.
 	localRepl, found := f.lastKnownReplicas.GetReplicaDescriptorByID(f.replicaForFlowControl.getReplicaID())
@@ -116,13 +133,25 @@ func (f *replicaFlowControlIntegrationImpl) onBecameLeader(ctx context.Context)
 		},
 	)
 
-	// Start off every remote stream as disconnected. Later we'll try to
+	// Start off every remote stream as disconnected, unless it was connected
+	// on the range we were split off from or subsumed. Later we'll try to
 	// reconnect them.
 	var toDisconnect []roachpb.ReplicaDescriptor
 	for _, desc := range f.replicaForFlowControl.getDescriptor().Replicas().Descriptors() {
-		if desc.ReplicaID != localRepl.ReplicaID {
-			toDisconnect = append(toDisconnect, desc)
+		if desc.ReplicaID == localRepl.ReplicaID {
+			continue
 		}
+		if _, ok := f.inheritedStreams[desc.StoreID]; ok {
+			f.innerHandle.ConnectStream(ctx,
+				f.replicaForFlowControl.getAppliedLogPosition(),
+				kvflowcontrol.Stream{
+					TenantID: f.replicaForFlowControl.getTenantID(),
+					StoreID:  desc.StoreID,
+				},
+			)
+			continue
+		}
+		toDisconnect = append(toDisconnect, desc)
 	}
 	f.disconnectStreams(ctx, toDisconnect, "unknown followers on new leader")
 	f.tryReconnect(ctx)
@@ -256,6 +285,7 @@ func (f *replicaFlowControlIntegrationImpl) onRaftTicked(ctx context.Context) {
 	}
 
 	f.refreshStreams(ctx, "refreshing streams")
+	f.pruneInheritedStreams()
 }
 
 // onDestroyed is part of the replicaFlowControlIntegration interface.
@@ -277,12 +307,81 @@ func (f *replicaFlowControlIntegrationImpl) onDestroyed(ctx context.Context) {
 	f.clearState(ctx)
 }
 
+// onSplit is part of the replicaFlowControlIntegration interface.
+func (f *replicaFlowControlIntegrationImpl) onSplit(
+	ctx context.Context, lhs flowControlStreamState,
+) {
+	f.replicaForFlowControl.assertLocked()
+	f.inheritStreams(ctx, lhs, "split")
+}
+
+// onMerge is part of the replicaFlowControlIntegration interface.
+func (f *replicaFlowControlIntegrationImpl) onMerge(
+	ctx context.Context, rhs flowControlStreamState,
+) {
+	f.replicaForFlowControl.assertLocked()
+	f.inheritStreams(ctx, rhs, "merge")
+}
+
 // handle is part of the replicaFlowControlIntegration interface.
 func (f *replicaFlowControlIntegrationImpl) handle() (kvflowcontrol.Handle, bool) {
 	f.replicaForFlowControl.assertLocked()
 	return f.innerHandle, f.innerHandle != nil
 }
 
+// streamState is part of the replicaFlowControlIntegration interface.
+func (f *replicaFlowControlIntegrationImpl) streamState() flowControlStreamState {
+	f.replicaForFlowControl.assertRLocked()
+	if f.innerHandle == nil {
+		return flowControlStreamState{}
+	}
+	connected := make(map[roachpb.StoreID]struct{})
+	ourReplicaID := f.replicaForFlowControl.getReplicaID()
+	for _, repl := range f.lastKnownReplicas.Descriptors() {
+		if repl.ReplicaID == ourReplicaID {
+			continue
+		}
+		if _, found := f.disconnectedStreams[repl.ReplicaID]; found {
+			continue
+		}
+		connected[repl.StoreID] = struct{}{}
+	}
+	return flowControlStreamState{connected: connected}
+}
+
+// inheritStreams records the streams connected on the range we were split off
+// from or subsumed, to be applied if we assume raft leadership in the next
+// term. If we're already the leader our own view of the followers is
+// authoritative, so there's nothing to do.
+func (f *replicaFlowControlIntegrationImpl) inheritStreams(
+	ctx context.Context, state flowControlStreamState, reason string,
+) {
+	if f.innerHandle != nil || len(state.connected) == 0 {
+		return // nothing to do
+	}
+	f.inheritedStreams = state.connected
+	f.inheritedTerm = f.replicaForFlowControl.getAppliedLogPosition().Term
+	log.VInfof(ctx, 1, "inherited %d connected stream(s) for %s (reason: %s)",
+		len(f.inheritedStreams), f.replicaForFlowControl.getDescriptor(), reason)
+}
+
+// pruneInheritedStreams untracks inherited streams for followers raft no
+// longer considers behind; they're subject to the usual checks from here on.
+func (f *replicaFlowControlIntegrationImpl) pruneInheritedStreams() {
+	if len(f.inheritedStreams) == 0 {
+		return
+	}
+	behindFollowers := f.replicaForFlowControl.getBehindFollowers()
+	for _, repl := range f.lastKnownReplicas.Descriptors() {
+		if _, found := behindFollowers[repl.ReplicaID]; !found {
+			delete(f.inheritedStreams, repl.StoreID)
+		}
+	}
+	if len(f.inheritedStreams) == 0 {
+		f.inheritedStreams = nil
+	}
+}
+
 // refreshStreams disconnects any streams we're not actively replicating to, and
 // reconnect previously disconnected streams if we're able.
 func (f *replicaFlowControlIntegrationImpl) refreshStreams(ctx context.Context, reason string) {
@@ -346,7 +445,12 @@ func (f *replicaFlowControlIntegrationImpl) notActivelyReplicatingTo() []roachpb
 
 		if _, found := behindFollowers[repl.ReplicaID]; found &&
 			!maintainStreamsForBehindFollowers {
-			notActivelyReplicatingTo[repl] = struct{}{}
+			// Followers whose streams we inherited across a split or merge are
+			// still being probed by raft, but were caught up on the source
+			// range; see onSplit and onMerge.
+			if _, inherited := f.inheritedStreams[repl.StoreID]; !inherited {
+				notActivelyReplicatingTo[repl] = struct{}{}
+			}
 		}
 
 		if _, found := inactiveFollowers[repl.ReplicaID]; found &&
@@ -389,6 +493,7 @@ func (f *replicaFlowControlIntegrationImpl) disconnectStreams(
 		}
 		f.innerHandle.DisconnectStream(ctx, stream)
 		f.disconnectedStreams[repl.ReplicaID] = stream
+		delete(f.inheritedStreams, repl.StoreID)
 		log.VInfof(ctx, 1, "tracked disconnected stream: %s (reason: %s)", stream, reason)
 	}
 }
@@ -439,4 +544,5 @@ func (f *replicaFlowControlIntegrationImpl) clearState(ctx context.Context) {
 	f.innerHandle = nil
 	f.lastKnownReplicas = roachpb.MakeReplicaSet(nil)
 	f.disconnectedStreams = nil
+	f.inheritedStreams = nil
 }
@@ -60,12 +60,13 @@ import (
 //
 //   - "integration" op=[became-leader | became-follower | desc-changed |
 //     followers-paused |replica-destroyed |
-//     proposal-quota-updated]
+//     proposal-quota-updated | split | merge] [connected=(<int>,...)]
 //     ----
 //     Invoke the specific APIs integration interface, informing it of the
 //     underlying replica acquire raft leadership, losing it, its range
 //     descriptor changing, a change in the set of paused followers, it being
-//     destroyed, and its proposal quota being updated
+//     destroyed, its proposal quota being updated, and it being split off
+//     from or subsuming a range with the given connected streams
 func TestFlowControlReplicaIntegration(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -252,6 +253,26 @@ func TestFlowControlReplicaIntegration(t *testing.T) {
 							integration.onDestroyed(ctx)
 						case "raft-ticked":
 							integration.onRaftTicked(ctx)
+						case "split", "merge":
+							// Parse connected=(<int>,<int>,...).
+							state := flowControlStreamState{
+								connected: make(map[roachpb.StoreID]struct{}),
+							}
+							for _, arg := range d.CmdArgs {
+								if arg.Key != "connected" {
+									continue
+								}
+								for i := range arg.Vals {
+									var id uint64
+									arg.Scan(t, i, &id)
+									state.connected[roachpb.StoreID(id)] = struct{}{}
+								}
+							}
+							if op == "split" {
+								integration.onSplit(ctx, state)
+							} else {
+								integration.onMerge(ctx, state)
+							}
 						default:
 							t.Fatalf("unknown op: %s", op)
 						}
@@ -305,6 +326,8 @@ func newMockReplicaForFlowControl(
 
 func (m *mockReplicaForFlowControl) assertLocked() {}
 
+func (m *mockReplicaForFlowControl) assertRLocked() {}
+
 func (m *mockReplicaForFlowControl) annotateCtx(ctx context.Context) context.Context {
 	return ctx
 }
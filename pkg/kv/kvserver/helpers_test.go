@@ -590,11 +590,26 @@ func (r *Replica) ClosedTimestampPolicy() roachpb.RangeClosedTimestampPolicy {
 	return r.closedTimestampPolicyRLocked()
 }
 
+// SetHotKey marks the range as having a hot key or not, as if detected by the
+// load-based splitter.
+func (r *Replica) SetHotKey(hot bool) {
+	r.hotKey.Store(hot)
+}
+
+// MaybeAddHotKeyNonVoters exposes maybeAddHotKeyNonVoters for tests.
+func (r *Replica) MaybeAddHotKeyNonVoters(conf *roachpb.SpanConfig) {
+	r.maybeAddHotKeyNonVoters(conf)
+}
+
 // TripBreaker synchronously trips the breaker.
 func (r *Replica) TripBreaker() {
 	r.breaker.tripSync(errors.New("injected error"))
 }
 
+// FramedRaftTransportEnabled exports the setting enabling the framed raft
+// transport.
+var FramedRaftTransportEnabled = framedRaftTransportEnabled
+
 // GetCircuitBreaker returns the circuit breaker controlling
 // connection attempts to the specified node.
 func (t *RaftTransport) GetCircuitBreaker(
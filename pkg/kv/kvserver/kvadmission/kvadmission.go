@@ -15,6 +15,7 @@ package kvadmission
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -170,11 +171,19 @@ type Controller interface {
 	// periodically polled for weights. The stopper should be used to terminate
 	// the periodic polling.
 	SetTenantWeightProvider(TenantWeightProvider, *stop.Stopper)
+	// SetRUThrottledTenants is used to set the source of the tenants that are
+	// deprioritized because tenant cost control is throttling them. No tenant
+	// is deprioritized until it is set.
+	SetRUThrottledTenants(RUThrottledTenants)
 	// SnapshotIngestedOrWritten informs admission control about a range
 	// snapshot ingestion or a range snapshot written as a normal write.
 	// writeBytes should roughly correspond to the size of the write when
 	// flushed to a sstable.
 	SnapshotIngestedOrWritten(_ roachpb.StoreID, _ pebble.IngestOperationStats, writeBytes uint64)
+	// SnapshotQueue returns the queue used to pace the writes of incoming
+	// range snapshots to the given store. It returns nil if the store is not
+	// known.
+	SnapshotQueue(roachpb.StoreID) *admission.SnapshotQueue
 	// FollowerStoreWriteBytes informs admission control about writes
 	// replicated to a raft follower, that have not been subject to admission
 	// control.
@@ -190,6 +199,13 @@ type TenantWeightProvider interface {
 	GetTenantWeights() TenantWeights
 }
 
+// RUThrottledTenants reports which tenants are throttled by tenant cost
+// control because they have exhausted their request units.
+type RUThrottledTenants interface {
+	// IsRUThrottled returns true if the token bucket of the tenant is in debt.
+	IsRUThrottled(roachpb.TenantID) bool
+}
+
 // TenantWeights contains the various tenant weights.
 type TenantWeights struct {
 	// Node is the node level tenant ID => weight.
@@ -217,6 +233,10 @@ type controllerImpl struct {
 	kvflowController           kvflowcontrol.Controller
 	kvflowHandles              kvflowcontrol.Handles
 
+	// ruThrottledTenants holds the RUThrottledTenants set by
+	// SetRUThrottledTenants, if any.
+	ruThrottledTenants atomic.Value
+
 	settings *cluster.Settings
 	every    log.EveryN
 }
@@ -233,6 +253,8 @@ type Handle struct {
 	storeAdmissionQ      *admission.StoreWorkQueue
 	storeWorkHandle      admission.StoreWorkHandle
 	elasticCPUWorkHandle *admission.ElasticCPUWorkHandle
+	diskReadQ            *admission.DiskReadQueue
+	diskReadHandle       *admission.DiskReadHandle
 	raftAdmissionMeta    *kvflowcontrolpb.RaftAdmissionMeta
 
 	callAdmittedWorkDoneOnKVAdmissionQ bool
@@ -245,6 +267,9 @@ func (h *Handle) AnnotateCtx(ctx context.Context) context.Context {
 	if h.elasticCPUWorkHandle != nil {
 		ctx = admission.ContextWithElasticCPUWorkHandle(ctx, h.elasticCPUWorkHandle)
 	}
+	if h.diskReadHandle != nil {
+		ctx = admission.ContextWithDiskReadHandle(ctx, h.diskReadHandle)
+	}
 	if h.raftAdmissionMeta != nil {
 		ctx = kvflowcontrol.ContextWithMeta(ctx, h.raftAdmissionMeta)
 	}
@@ -322,6 +347,15 @@ func (n *controllerImpl) AdmitKVWork(
 		CreateTime:      createTime,
 		BypassAdmission: bypassAdmission,
 	}
+	// Virtual clusters that are throttled by tenant cost control are
+	// deprioritized relative to other tenants, so that they cannot use their
+	// debt to overload this node.
+	if !roachpb.IsSystemTenantID(tenantID.ToUint64()) &&
+		admission.KVRUThrottledTenantDeprioritizationEnabled.Get(&n.settings.SV) {
+		if t, ok := n.ruThrottledTenants.Load().(RUThrottledTenants); ok {
+			admissionInfo.RUThrottled = t.IsRUThrottled(tenantID)
+		}
+	}
 
 	admissionEnabled := true
 	// Don't subject HeartbeatTxnRequest to the storeAdmissionQ. Even though
@@ -399,12 +433,30 @@ func (n *controllerImpl) AdmitKVWork(
 		//   general (notably, for KV work done on the behalf of row-level TTL
 		//   reads). Everything admissionpb.UserLowPri and above uses the slots
 		//   mechanism.
+		// - These elastic reads are additionally paced using the disk read
+		//   bandwidth tokens of the store, before being admitted through the
+		//   elastic CPU work queue, so that they don't saturate the disk.
 		isInternalLowPriRead := ba.IsReadOnly() && admissionInfo.Priority < admissionpb.UserLowPri
 		shouldUseElasticCPU :=
 			(exportRequestElasticControlEnabled.Get(&n.settings.SV) && ba.IsSingleExportRequest()) ||
 				(internalLowPriReadElasticControlEnabled.Get(&n.settings.SV) && isInternalLowPriRead)
 
 		if shouldUseElasticCPU {
+			diskReadQ := n.storeGrantCoords.TryGetDiskReadQueueForStore(int32(ba.Replica.StoreID))
+			diskReadHandle, err := diskReadQ.Admit(ctx)
+			if err != nil {
+				return Handle{}, err
+			}
+			if diskReadHandle != nil {
+				ah.diskReadQ, ah.diskReadHandle = diskReadQ, diskReadHandle
+				defer func() {
+					if retErr != nil {
+						// Nothing was read.
+						diskReadQ.AdmittedReadDone(diskReadHandle)
+					}
+				}()
+			}
+
 			var admitDuration time.Duration
 			if ba.IsSingleExportRequest() {
 				admitDuration = elasticCPUDurationPerExportRequest.Get(&n.settings.SV)
@@ -450,6 +502,7 @@ func (n *controllerImpl) AdmitKVWork(
 // AdmittedKVWorkDone implements the Controller interface.
 func (n *controllerImpl) AdmittedKVWorkDone(ah Handle, writeBytes *StoreWriteBytes) {
 	n.elasticCPUGrantCoordinator.ElasticCPUWorkQueue.AdmittedWorkDone(ah.elasticCPUWorkHandle)
+	ah.diskReadQ.AdmittedReadDone(ah.diskReadHandle)
 	if ah.callAdmittedWorkDoneOnKVAdmissionQ {
 		cpuTime := grunning.Time() - ah.cpuStart
 		if cpuTime < 0 {
@@ -542,6 +595,11 @@ func (n *controllerImpl) SetTenantWeightProvider(
 	}()
 }
 
+// SetRUThrottledTenants implements the Controller interface.
+func (n *controllerImpl) SetRUThrottledTenants(t RUThrottledTenants) {
+	n.ruThrottledTenants.Store(t)
+}
+
 // SnapshotIngestedOrWritten implements the Controller interface.
 func (n *controllerImpl) SnapshotIngestedOrWritten(
 	storeID roachpb.StoreID, ingestStats pebble.IngestOperationStats, writeBytes uint64,
@@ -553,6 +611,11 @@ func (n *controllerImpl) SnapshotIngestedOrWritten(
 	storeAdmissionQ.StatsToIgnore(ingestStats, writeBytes)
 }
 
+// SnapshotQueue implements the Controller interface.
+func (n *controllerImpl) SnapshotQueue(storeID roachpb.StoreID) *admission.SnapshotQueue {
+	return n.storeGrantCoords.TryGetSnapshotQueueForStore(int32(storeID))
+}
+
 // FollowerStoreWriteBytes implements the Controller interface.
 func (n *controllerImpl) FollowerStoreWriteBytes(
 	storeID roachpb.StoreID, followerWriteBytes FollowerStoreWriteBytes,
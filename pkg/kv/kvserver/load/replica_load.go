@@ -175,6 +175,25 @@ func (rl *ReplicaLoad) Reset() {
 	}
 }
 
+// Seed replaces all recorded history with the given per-second averages, so
+// that they are immediately reflected in Stats(). It is used to warm start the
+// replica load following a restart, from stats that were persisted before the
+// restart.
+func (rl *ReplicaLoad) Seed(stats ReplicaLoadStats) {
+	now := timeutil.Unix(0, rl.clock.PhysicalNow())
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.mu.stats[Queries].SeedRate(now, stats.QueriesPerSecond)
+	rl.mu.stats[Requests].SeedRate(now, stats.RequestsPerSecond)
+	rl.mu.stats[WriteKeys].SeedRate(now, stats.WriteKeysPerSecond)
+	rl.mu.stats[ReadKeys].SeedRate(now, stats.ReadKeysPerSecond)
+	rl.mu.stats[WriteBytes].SeedRate(now, stats.WriteBytesPerSecond)
+	rl.mu.stats[ReadBytes].SeedRate(now, stats.ReadBytesPerSecond)
+	rl.mu.stats[RaftCPUNanos].SeedRate(now, stats.RaftCPUNanosPerSecond)
+	rl.mu.stats[ReqCPUNanos].SeedRate(now, stats.RequestCPUNanosPerSecond)
+}
+
 // getLocked returns the current value for the LoadStat with ordinal stat. It
 // requires holding a lock.
 func (rl *ReplicaLoad) getLocked(stat LoadStat) float64 {
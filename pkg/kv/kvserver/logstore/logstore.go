@@ -19,6 +19,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/raftentry"
@@ -62,6 +63,18 @@ var enableNonBlockingRaftLogSync = settings.RegisterBoolSetting(
 	envutil.EnvOrDefaultBool("COCKROACH_ENABLE_RAFT_LOG_NON_BLOCKING_SYNCHRONIZATION", true),
 )
 
+// sideloadLargePayloadThreshold is the size above which the payloads of raft
+// log entries are stored in the sideloaded storage rather than in the storage
+// engine. AddSSTable payloads are always sideloaded regardless.
+var sideloadLargePayloadThreshold = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"kv.raft_log.sideloading.large_payload_threshold",
+	"raft log entries with payloads larger than this size are stored outside of the "+
+		"storage engine to reduce write amplification; 0 disables this",
+	4<<20, // 4 MiB
+	settings.NonNegativeInt,
+)
+
 // MsgStorageAppend is a raftpb.Message with type MsgStorageAppend.
 type MsgStorageAppend raftpb.Message
 
@@ -179,7 +192,15 @@ func (s *LogStore) storeEntriesAndCommitBatch(
 		stats.Begin = timeutil.Now()
 		// All of the entries are appended to distinct keys, returning a new
 		// last index.
-		thinEntries, numSideloaded, sideLoadedEntriesSize, otherEntriesSize, err := MaybeSideloadEntries(ctx, m.Entries, s.Sideload)
+		var largePayloadThreshold int64
+		// Nodes running older binaries don't understand sideloaded payloads, so
+		// only write them once the cluster version guarantees that a binary
+		// downgrade is no longer possible.
+		if s.Settings.Version.IsActive(ctx, clusterversion.V24_2) {
+			largePayloadThreshold = sideloadLargePayloadThreshold.Get(&s.Settings.SV)
+		}
+		thinEntries, numSideloaded, sideLoadedEntriesSize, otherEntriesSize, err := MaybeSideloadEntries(
+			ctx, m.Entries, s.Sideload, largePayloadThreshold)
 		if err != nil {
 			const expl = "during sideloading"
 			return RaftState{}, errors.Wrap(err, expl)
@@ -75,10 +75,19 @@ type SideloadStorage interface {
 // requests. It adds these SSTs to the provided sideloaded storage, and in
 // their place returns an entry with a nil payload (but otherwise identical).
 //
+// Additionally, if largePayloadThreshold is positive, any other command whose
+// Data exceeds the threshold (e.g. a large write batch) is sideloaded in its
+// entirety, and replaced by an entry of encoding
+// EntryEncodingSideloadedPayload that only retains the command ID. This
+// reduces the write amplification of the raft log engine for large proposals.
+//
 // The provided slice is not modified, though the returned slice may be backed
 // in parts or entirely by the same memory.
 func MaybeSideloadEntries(
-	ctx context.Context, input []raftpb.Entry, sideloaded SideloadStorage,
+	ctx context.Context,
+	input []raftpb.Entry,
+	sideloaded SideloadStorage,
+	largePayloadThreshold int64,
 ) (
 	_ []raftpb.Entry,
 	numSideloaded int,
@@ -92,6 +101,22 @@ func MaybeSideloadEntries(
 		if err != nil {
 			return nil, 0, 0, 0, err
 		}
+		if isLargePayload(typ, input[i], largePayloadThreshold) {
+			if output == nil {
+				output = append([]raftpb.Entry(nil), input...)
+			}
+			outputEnt := &output[i]
+			id, _ := raftlog.DecomposeRaftEncodingStandardOrSideloaded(input[i].Data)
+			outputEnt.Data = raftlog.EncodeCommandBytes(raftlog.EntryEncodingSideloadedPayload, id, nil)
+
+			log.Eventf(ctx, "writing large payload at index=%d term=%d", outputEnt.Index, outputEnt.Term)
+			if err := sideloaded.Put(ctx, kvpb.RaftIndex(outputEnt.Index), kvpb.RaftTerm(outputEnt.Term), input[i].Data); err != nil {
+				return nil, 0, 0, 0, err
+			}
+			numSideloaded++
+			sideloadedEntriesSize += int64(len(input[i].Data))
+			continue
+		}
 		if !typ.IsSideloaded() {
 			otherEntriesSize += int64(len(input[i].Data))
 			continue
@@ -157,6 +182,16 @@ func MaybeSideloadEntries(
 	return output, numSideloaded, sideloadedEntriesSize, otherEntriesSize, nil
 }
 
+// isLargePayload returns whether the entry is a standard command whose Data
+// exceeds the given threshold, and should be sideloaded as a whole. A
+// non-positive threshold disables sideloading of large payloads.
+func isLargePayload(typ raftlog.EntryEncoding, ent raftpb.Entry, threshold int64) bool {
+	if threshold <= 0 || int64(len(ent.Data)) <= threshold {
+		return false
+	}
+	return typ == raftlog.EntryEncodingStandardWithAC || typ == raftlog.EntryEncodingStandardWithoutAC
+}
+
 // MaybeInlineSideloadedRaftCommand takes an entry and inspects it. If its
 // command encoding version indicates a sideloaded entry, it uses the entryCache
 // or SideloadStorage to inline the payload, returning a new entry (which must
@@ -178,6 +213,9 @@ func MaybeInlineSideloadedRaftCommand(
 	if !typ.IsSideloaded() {
 		return nil, nil
 	}
+	if typ == raftlog.EntryEncodingSideloadedPayload {
+		return inlineSideloadedPayload(ctx, rangeID, ent, sideloaded, entryCache)
+	}
 	log.Event(ctx, "inlining sideloaded SSTable")
 	// We could unmarshal this yet again, but if it's committed we
 	// are very likely to have appended it recently, in which case
@@ -233,6 +271,42 @@ func MaybeInlineSideloadedRaftCommand(
 	return &ent, nil
 }
 
+// inlineSideloadedPayload restores the original entry from a thin entry of
+// encoding EntryEncodingSideloadedPayload.
+func inlineSideloadedPayload(
+	ctx context.Context,
+	rangeID roachpb.RangeID,
+	ent raftpb.Entry,
+	sideloaded SideloadStorage,
+	entryCache *raftentry.Cache,
+) (*raftpb.Entry, error) {
+	log.Event(ctx, "inlining sideloaded payload")
+	cachedSingleton, _, _, _ := entryCache.Scan(
+		nil, rangeID, kvpb.RaftIndex(ent.Index), kvpb.RaftIndex(ent.Index+1), 1<<20,
+	)
+	if len(cachedSingleton) > 0 {
+		log.Event(ctx, "using cache hit")
+		return &cachedSingleton[0], nil
+	}
+	log.Event(ctx, "inlined entry not cached")
+	data, err := sideloaded.Get(ctx, kvpb.RaftIndex(ent.Index), kvpb.RaftTerm(ent.Term))
+	if err != nil {
+		return nil, errors.Wrap(err, "loading sideloaded payload")
+	}
+	// The sideloaded payload is the original Data of the entry, which must
+	// carry the same command ID as the thin entry.
+	thinID, _ := raftlog.DecomposeRaftEncodingStandardOrSideloaded(ent.Data)
+	if len(data) < raftlog.RaftCommandPrefixLen {
+		return nil, errors.AssertionFailedf("sideloaded payload at index %d is too short", ent.Index)
+	}
+	if id, _ := raftlog.DecomposeRaftEncodingStandardOrSideloaded(data); id != thinID {
+		return nil, errors.AssertionFailedf(
+			"sideloaded payload at index %d has command ID %x, expected %x", ent.Index, id, thinID)
+	}
+	ent.Data = data
+	return &ent, nil
+}
+
 // AssertSideloadedRaftCommandInlined asserts that if the provided entry is a
 // sideloaded entry, then its payload has already been inlined. Doing so
 // requires unmarshalling the raft command, so this assertion should be kept out
@@ -245,6 +319,10 @@ func AssertSideloadedRaftCommandInlined(ctx context.Context, ent *raftpb.Entry)
 	if !typ.IsSideloaded() {
 		return
 	}
+	if typ == raftlog.EntryEncodingSideloadedPayload {
+		// Inlined entries have their original encoding, so this is a thin entry.
+		log.Fatalf(ctx, "found thin sideloaded raft payload at index %d", ent.Index)
+	}
 
 	e, err := raftlog.NewEntry(*ent)
 	if err != nil {
@@ -553,7 +553,8 @@ func TestRaftSSTableSideloadingSideload(t *testing.T) {
 			eng := storage.NewDefaultInMemForTesting()
 			defer eng.Close()
 			sideloaded := newTestingSideloadStorage(eng)
-			postEnts, numSideloaded, size, nonSideloadedSize, err := MaybeSideloadEntries(ctx, test.preEnts, sideloaded)
+			postEnts, numSideloaded, size, nonSideloadedSize, err := MaybeSideloadEntries(
+				ctx, test.preEnts, sideloaded, 0 /* largePayloadThreshold */)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -584,6 +585,84 @@ func TestRaftSSTableSideloadingSideload(t *testing.T) {
 	}
 }
 
+func TestRaftSideloadingLargePayload(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	rangeID := roachpb.RangeID(1)
+	const threshold = 100
+
+	mkWriteBatchEnt := func(index uint64, size int) raftpb.Entry {
+		var cmd kvserverpb.RaftCommand
+		cmd.WriteBatch = &kvserverpb.WriteBatch{Data: bytes.Repeat([]byte("x"), size)}
+		b, err := protoutil.Marshal(&cmd)
+		require.NoError(t, err)
+		return raftpb.Entry{
+			Index: index,
+			Term:  99,
+			Data: raftlog.EncodeCommandBytes(raftlog.EntryEncodingStandardWithoutAC,
+				kvserverbase.CmdIDKey(strings.Repeat("x", raftlog.RaftCommandIDLen)), b),
+		}
+	}
+	addSST := kvserverpb.ReplicatedEvalResult_AddSSTable{Data: bytes.Repeat([]byte("s"), 2*threshold)}
+	small := mkWriteBatchEnt(10, threshold/2)
+	large := mkWriteBatchEnt(11, 2*threshold)
+	sst := mkEnt(raftlog.EntryEncodingSideloadedWithoutAC, 12, 99, &addSST)
+	input := []raftpb.Entry{small, large, sst}
+
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+	ss := newTestingSideloadStorage(eng)
+
+	// With sideloading of large payloads disabled, only the SST is sideloaded.
+	output, numSideloaded, size, otherSize, err := MaybeSideloadEntries(ctx, input, ss, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, numSideloaded)
+	require.Equal(t, int64(len(addSST.Data)), size)
+	require.Equal(t, int64(len(small.Data)+len(large.Data)), otherSize)
+	require.Equal(t, large, output[1])
+	require.NoError(t, ss.Clear(ctx))
+
+	output, numSideloaded, size, otherSize, err = MaybeSideloadEntries(ctx, input, ss, threshold)
+	require.NoError(t, err)
+	require.Equal(t, 2, numSideloaded)
+	require.Equal(t, int64(len(large.Data)+len(addSST.Data)), size)
+	require.Equal(t, int64(len(small.Data)), otherSize)
+	require.Equal(t, small, output[0])
+
+	// The large entry is thin, and retains its command ID.
+	typ, err := raftlog.EncodingOf(output[1])
+	require.NoError(t, err)
+	require.Equal(t, raftlog.EntryEncodingSideloadedPayload, typ)
+	require.Len(t, output[1].Data, raftlog.RaftCommandPrefixLen)
+	thinID, _ := raftlog.DecomposeRaftEncodingStandardOrSideloaded(output[1].Data)
+	id, _ := raftlog.DecomposeRaftEncodingStandardOrSideloaded(large.Data)
+	require.Equal(t, id, thinID)
+	// The input was not modified.
+	require.Equal(t, mkWriteBatchEnt(11, 2*threshold), input[1])
+
+	// Inlining restores the original entry.
+	ec := raftentry.NewCache(1024)
+	inlined, err := MaybeInlineSideloadedRaftCommand(ctx, rangeID, output[1], ss, ec)
+	require.NoError(t, err)
+	require.Equal(t, large, *inlined)
+
+	// Truncation accounts for the sideloaded payloads.
+	freed, retained, err := ss.BytesIfTruncatedFromTo(ctx, 0, 12)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(large.Data)), freed)
+	require.Equal(t, int64(len(addSST.Data)), retained)
+	freed, retained, err = ss.TruncateTo(ctx, 12)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(large.Data)), freed)
+	require.Equal(t, int64(len(addSST.Data)), retained)
+
+	// Once the payload is gone, inlining fails.
+	_, err = MaybeInlineSideloadedRaftCommand(ctx, rangeID, output[1], ss, ec)
+	require.True(t, errors.Is(err, errSideloadedFileNotFound), "%+v", err)
+}
+
 func newOnDiskEngine(ctx context.Context, t *testing.T) (func(), storage.Engine) {
 	dir, cleanup := testutils.TempDir(t)
 	eng, err := storage.Open(
@@ -29,12 +29,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
@@ -77,6 +79,7 @@ type Server struct {
 	visitStatusNode    visitNodeStatusFn
 	planStore          PlanStore
 	decommissionFn     func(context.Context, roachpb.NodeID) error
+	postApplyFn        func(context.Context)
 
 	metadataQueryTimeout time.Duration
 	forwardReplicaFilter func(*serverpb.RecoveryCollectLocalReplicaInfoResponse) error
@@ -92,6 +95,7 @@ func NewServer(
 	rpcCtx *rpc.Context,
 	knobs base.ModuleTestingKnobs,
 	decommission func(context.Context, roachpb.NodeID) error,
+	postApply func(context.Context),
 ) *Server {
 	// Server side timeouts are necessary in recovery collector since we do best
 	// effort operations where cluster info collection as an operation succeeds
@@ -113,6 +117,7 @@ func NewServer(
 		visitStatusNode:      makeVisitNode(g, loc, rpcCtx),
 		planStore:            planStore,
 		decommissionFn:       decommission,
+		postApplyFn:          postApply,
 		metadataQueryTimeout: metadataQueryTimeout,
 		forwardReplicaFilter: forwardReplicaFilter,
 	}
@@ -463,6 +468,193 @@ func stagePlanRecoveryStagePlanParallelFn(
 	}
 }
 
+// ApplyPlan applies a previously staged recovery plan to live replicas
+// without restarting nodes. Each replica is only rewritten if its current state
+// still passes the checks the plan was made with, see
+// kvserver.Store.UnsafeRecoverReplica.
+// Application is recorded the same way as when plan is applied on node
+// restart so that it can be verified using RecoveryVerify.
+func (s Server) ApplyPlan(
+	ctx context.Context, req *serverpb.RecoveryApplyPlanRequest,
+) (*serverpb.RecoveryApplyPlanResponse, error) {
+	localNodeID := s.nodeIDContainer.Get()
+	if req.AllNodes {
+		var nodeErrors threadSafeSlice[string]
+		err := s.visitAdminNodes(
+			ctx,
+			fanOutConnectionRetryOptions,
+			req.MaxConcurrency,
+			allNodes,
+			applyPlanRecoveryApplyPlanParallelFn(ctx, req, &nodeErrors))
+		if err != nil {
+			nodeErrors.Append(
+				errors.Wrapf(err, "failed to perform fan-out to cluster nodes from n%d", localNodeID).Error())
+		}
+		return &serverpb.RecoveryApplyPlanResponse{Errors: nodeErrors.Clone()}, nil
+	}
+
+	responseFromError := func(err error) (*serverpb.RecoveryApplyPlanResponse, error) {
+		return &serverpb.RecoveryApplyPlanResponse{
+			Errors: []string{
+				errors.Wrapf(err, "failed to apply plan on node n%d", localNodeID).Error(),
+			},
+		}, nil
+	}
+
+	plan, exists, err := s.planStore.LoadPlan()
+	if err != nil {
+		return responseFromError(err)
+	}
+	if !exists {
+		// Nothing is staged on this node, so it has nothing to apply.
+		return &serverpb.RecoveryApplyPlanResponse{}, nil
+	}
+	if !plan.PlanID.Equal(req.PlanID) {
+		return responseFromError(errors.Newf("conflicting plan %s is staged", plan.PlanID))
+	}
+	version := s.settings.Version.ActiveVersion(ctx)
+	if err := checkPlanVersionMatches(plan.Version, version.Version, false); err != nil {
+		return responseFromError(errors.Wrap(err, "incompatible plan"))
+	}
+
+	log.Infof(ctx, "applying loss of quorum recovery plan %s online", plan.PlanID)
+	applyErrs := s.applyPlanLocally(ctx, localNodeID, plan)
+	r := loqrecoverypb.PlanApplicationResult{
+		AppliedPlanID:  plan.PlanID,
+		ApplyTimestamp: timeutil.Now(),
+	}
+	if len(applyErrs) == 0 {
+		if err := s.planStore.RemovePlan(); err != nil {
+			log.Errorf(ctx, "failed to remove loss of quorum recovery plan: %s", err)
+		}
+	} else {
+		// Keep the plan staged so that applying it again retries the replicas
+		// that failed. The replicas that were updated are skipped on retry.
+		var applyErr error
+		for _, err := range applyErrs {
+			applyErr = errors.CombineErrors(applyErr, err)
+		}
+		r.Error = applyErr.Error()
+		log.Errorf(ctx, "failed to apply loss of quorum recovery plan %s", applyErr)
+	}
+	if err := s.stores.VisitStores(func(store *kvserver.Store) error {
+		if err := writeNodeRecoveryResults(ctx, store.TODOEngine(), r,
+			loqrecoverypb.DeferredRecoveryActions{DecommissionedNodeIDs: plan.DecommissionedNodeIDs}); err != nil {
+			return err
+		}
+		return iterutil.StopIteration()
+	}); iterutil.Map(err) != nil {
+		log.Errorf(ctx, "failed to write loss of quorum recovery results to store: %s", err)
+	}
+	if s.postApplyFn != nil {
+		s.postApplyFn(ctx)
+	}
+	resp := &serverpb.RecoveryApplyPlanResponse{}
+	for _, err := range applyErrs {
+		resp.Errors = append(resp.Errors,
+			errors.Wrapf(err, "failed to apply plan on node n%d", localNodeID).Error())
+	}
+	return resp, nil
+}
+
+// applyPlanLocally rewrites all replicas of local stores that are present in
+// the plan. A failure to update a replica doesn't prevent the other replicas
+// from being updated. It returns one error per store that failed to update
+// some of its replicas.
+func (s Server) applyPlanLocally(
+	ctx context.Context, nodeID roachpb.NodeID, plan loqrecoverypb.ReplicaUpdatePlan,
+) []error {
+	// Nodes that the plan decommissions are the ones it considers lost. Other
+	// nodes may still hold replicas able to form a quorum.
+	dead := make(map[roachpb.NodeID]struct{}, len(plan.DecommissionedNodeIDs))
+	for _, id := range plan.DecommissionedNodeIDs {
+		dead[id] = struct{}{}
+	}
+	isLive := func(rd roachpb.ReplicaDescriptor) bool {
+		_, ok := dead[rd.NodeID]
+		return !ok
+	}
+	var storeIDs []roachpb.StoreID
+	storeErrs := make(map[roachpb.StoreID]error)
+	addErr := func(storeID roachpb.StoreID, err error) {
+		if _, ok := storeErrs[storeID]; !ok {
+			storeIDs = append(storeIDs, storeID)
+		}
+		storeErrs[storeID] = errors.CombineErrors(storeErrs[storeID], err)
+	}
+	for _, update := range plan.Updates {
+		if update.NodeID() != nodeID {
+			continue
+		}
+		store, err := s.stores.GetStore(update.StoreID())
+		if err != nil {
+			addErr(update.StoreID(), err)
+			continue
+		}
+		// Skip replicas that were already updated by a previous attempt to apply
+		// the plan.
+		if r, err := store.GetReplica(update.RangeID); err == nil {
+			if rs := r.Desc().Replicas().Descriptors(); len(rs) == 1 &&
+				rs[0].ReplicaID == update.NewReplica.ReplicaID {
+				continue
+			}
+		}
+		err = store.UnsafeRecoverReplica(ctx, update.RangeID, update.OldReplicaID,
+			update.NewReplica.ReplicaID, isLive,
+			func(rw storage.ReadWriter) (roachpb.RangeDescriptor, roachpb.ReplicaID, error) {
+				report, err := applyReplicaUpdate(ctx, rw, update)
+				if err != nil {
+					return roachpb.RangeDescriptor{}, 0, err
+				}
+				if report.AlreadyUpdated {
+					return roachpb.RangeDescriptor{}, 0, errors.Newf(
+						"replica r%d is already updated", update.RangeID)
+				}
+				id, err := uuid.DefaultGenerator.NewV1()
+				if err != nil {
+					return roachpb.RangeDescriptor{}, 0, errors.Wrap(err,
+						"failed to generate uuid to write replica recovery evidence record")
+				}
+				if err := writeReplicaRecoveryStoreRecord(
+					id, timeutil.Now().UnixNano(), update, report, rw); err != nil {
+					return roachpb.RangeDescriptor{}, 0, errors.Wrap(err,
+						"failed writing replica recovery evidence record")
+				}
+				return report.Descriptor, update.NewReplica.ReplicaID, nil
+			})
+		if err != nil {
+			addErr(update.StoreID(), errors.Wrapf(err,
+				"failed to update replica for range r%d on store s%d", update.RangeID, update.StoreID()))
+		}
+	}
+	errs := make([]error, 0, len(storeIDs))
+	for _, storeID := range storeIDs {
+		errs = append(errs, storeErrs[storeID])
+	}
+	return errs
+}
+
+func applyPlanRecoveryApplyPlanParallelFn(
+	ctx context.Context,
+	req *serverpb.RecoveryApplyPlanRequest,
+	nodeErrors *threadSafeSlice[string],
+) visitNodeAdminFn {
+	return func(nodeID roachpb.NodeID, client serverpb.AdminClient) error {
+		res, err := client.RecoveryApplyPlan(ctx, &serverpb.RecoveryApplyPlanRequest{
+			PlanID:         req.PlanID,
+			AllNodes:       false,
+			MaxConcurrency: req.MaxConcurrency,
+		})
+		if err != nil {
+			nodeErrors.Append(
+				errors.Wrapf(err, "failed applying the plan on node n%d", nodeID).Error())
+			return nil
+		}
+		nodeErrors.Append(res.Errors...)
+		return nil
+	}
+}
+
 func (s Server) NodeStatus(
 	ctx context.Context, _ *serverpb.RecoveryNodeStatusRequest,
 ) (*serverpb.RecoveryNodeStatusResponse, error) {
@@ -383,6 +383,96 @@ func TestStageConflictingPlans(t *testing.T) {
 		"conflicting plans must not be allowed")
 }
 
+func TestApplyConflictingPlan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	tc, _, _ := prepTestCluster(t, 3)
+	defer tc.Stopper().Stop(ctx)
+
+	adm := tc.GetAdminClient(t, 0)
+
+	sk := tc.ScratchRange(t)
+
+	plan := makeTestRecoveryPlan(ctx, t, adm)
+	plan.Updates = []loqrecoverypb.ReplicaUpdate{
+		createRecoveryForRange(t, tc, sk, 3),
+	}
+	res, err := adm.RecoveryStagePlan(ctx, &serverpb.RecoveryStagePlanRequest{
+		Plan:           &plan,
+		AllNodes:       true,
+		MaxConcurrency: -1, // no limit
+	})
+	require.NoError(t, err, "failed to stage plan")
+	require.Empty(t, res.Errors, "unexpected errors in stage response")
+
+	// Applying a plan that is not the one staged must be rejected and must
+	// leave the staged plan in place.
+	resA, err := adm.RecoveryApplyPlan(ctx, &serverpb.RecoveryApplyPlanRequest{
+		PlanID:         uuid.MakeV4(),
+		AllNodes:       true,
+		MaxConcurrency: -1, // no limit
+	})
+	require.NoError(t, err)
+	require.Len(t, resA.Errors, 1, "only node with staged plan should fail")
+	require.Contains(t, resA.Errors[0],
+		fmt.Sprintf("failed to apply plan on node n3: conflicting plan %s is staged", plan.PlanID))
+
+	resV, err := adm.RecoveryVerify(ctx, &serverpb.RecoveryVerifyRequest{MaxConcurrency: -1 /* no limit */})
+	require.NoError(t, err)
+	statuses := aggregateStatusByNode(resV)
+	require.NotNil(t, statuses[3].PendingPlanID, "staged plan must not be removed")
+	require.Equal(t, plan.PlanID, *statuses[3].PendingPlanID)
+	require.Nil(t, statuses[3].AppliedPlanID, "plan must not be applied")
+}
+
+func TestApplyPlanFailureKeepsPlan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	tc, _, _ := prepTestCluster(t, 3)
+	defer tc.Stopper().Stop(ctx)
+
+	adm := tc.GetAdminClient(t, 0)
+
+	sk := tc.ScratchRange(t)
+
+	// The scratch range has not lost quorum and the update doesn't match its
+	// replica ID, so applying the plan must fail.
+	plan := makeTestRecoveryPlan(ctx, t, adm)
+	plan.Updates = []loqrecoverypb.ReplicaUpdate{
+		createRecoveryForRange(t, tc, sk, 3),
+	}
+	res, err := adm.RecoveryStagePlan(ctx, &serverpb.RecoveryStagePlanRequest{
+		Plan:           &plan,
+		AllNodes:       true,
+		MaxConcurrency: -1, // no limit
+	})
+	require.NoError(t, err, "failed to stage plan")
+	require.Empty(t, res.Errors, "unexpected errors in stage response")
+
+	resA, err := adm.RecoveryApplyPlan(ctx, &serverpb.RecoveryApplyPlanRequest{
+		PlanID:         plan.PlanID,
+		AllNodes:       true,
+		MaxConcurrency: -1, // no limit
+	})
+	require.NoError(t, err)
+	require.Len(t, resA.Errors, 1, "only the store with the update should fail")
+	require.Contains(t, resA.Errors[0], "failed to apply plan on node n3")
+	require.Contains(t, resA.Errors[0], "on store s3")
+
+	// The failed plan must stay staged so that it can be retried.
+	resV, err := adm.RecoveryVerify(ctx, &serverpb.RecoveryVerifyRequest{MaxConcurrency: -1 /* no limit */})
+	require.NoError(t, err)
+	statuses := aggregateStatusByNode(resV)
+	require.NotNil(t, statuses[3].PendingPlanID, "failed plan must not be removed")
+	require.Equal(t, plan.PlanID, *statuses[3].PendingPlanID)
+}
+
 func TestForcePlanUpdate(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
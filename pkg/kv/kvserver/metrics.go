@@ -1085,6 +1085,21 @@ var (
 		Measurement: "Snapshots",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaRangeSnapshotCompressedRcvdBytes = metric.Metadata{
+		Name:        "range.snapshots.compression.rcvd-bytes",
+		Help:        "Number of compressed snapshot bytes received",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaRangeSnapshotUncompressedRcvdBytes = metric.Metadata{
+		Name: "range.snapshots.compression.uncompressed-bytes",
+		Help: `Number of bytes the compressed snapshot bytes received decompressed to.
+
+Comparing this to range.snapshots.compression.rcvd-bytes gives the compression
+ratio of snapshots, as configured by kv.snapshot.compression.codec.`,
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
 	metaRangeSnapshotRecvUnusable = metric.Metadata{
 		Name:        "range.snapshots.recv-unusable",
 		Help:        "Number of range snapshot that were fully transmitted but determined to be unnecessary or unusable",
@@ -1181,6 +1196,12 @@ var (
 		Measurement: "Leader Transfers",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaRaftLeaderDivergence = metric.Metadata{
+		Name:        "raft.leader_leaseholder.divergence_duration",
+		Help:        "Duration for which raft leaders on this store were not the leaseholder of their range, recorded once they no longer diverge",
+		Measurement: "Divergence Duration",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 	metaRangeRaftLeaderRemovals = metric.Metadata{
 		Name:        "range.raftleaderremovals",
 		Help:        "Number of times the current Raft leader was removed from a range",
@@ -1226,6 +1247,39 @@ or the delegate being too busy to send.
 		Measurement: "Snapshots",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaDelegateSnapshotFanOutSendBytes = metric.Metadata{
+		Name: "range.snapshots.delegate.fan-out.sent-bytes",
+		Help: `Bytes sent by a replica acting as a fan-out delegate.
+
+The number of bytes sent by a replica which was itself seeded with a snapshot
+earlier in the same replication change, on behalf of the other replicas added
+to its locality. These bytes would otherwise have crossed the locality
+boundary. They are included in range.snapshots.delegate.sent-bytes.
+`,
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaDelegateSnapshotFanOutSuccesses = metric.Metadata{
+		Name: "range.snapshots.delegate.fan-out.successes",
+		Help: `Number of snapshots that were successfully fanned out through a
+replica seeded earlier in the same replication change. These are also counted
+in range.snapshots.delegate.successes.
+`,
+		Measurement: "Snapshots",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaDirectSnapshotSendBytes = metric.Metadata{
+		Name: "range.snapshots.direct.sent-bytes",
+		Help: `Bytes sent directly by the coordinator of a snapshot.
+
+The number of bytes sent for snapshots which were not delegated, i.e. sent by
+the replica that originated the snapshot request. Together with
+range.snapshots.delegate.sent-bytes, this allows comparing the volume of
+delegated and direct snapshot traffic.
+`,
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
 	metaDelegateSnapshotInProgress = metric.Metadata{
 		Name:        "range.snapshots.delegate.in-progress",
 		Help:        `Number of delegated snapshots that are currently in-flight.`,
@@ -1242,6 +1296,29 @@ or the delegate being too busy to send.
 		// (0 to 1.0) so it probably won't produce useful results here.
 		Unit: metric.Unit_COUNT,
 	}
+	metaRaftQuotaPoolWaiters = metric.Metadata{
+		Name: "raft.quota_pool.waiters",
+		Help: `Number of proposals waiting for proposal quota on the leaders on this store.
+
+Proposals wait for quota when the leader's proposal quota pool is exhausted,
+which happens when a follower is slow to acknowledge the raft log entries
+sent to it.
+`,
+		Measurement: "Proposals",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRaftQuotaPoolLongestWait = metric.Metadata{
+		Name:        "raft.quota_pool.longest_wait",
+		Help:        `Longest time any proposal on this store has been waiting for proposal quota`,
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaRaftQuotaPoolWaitLatency = metric.Metadata{
+		Name:        "raft.quota_pool.wait_latency",
+		Help:        `Time spent by proposals waiting for proposal quota, for the proposals that had to wait`,
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 	// Raft entry bytes loaded in memory.
 	metaRaftLoadedEntriesBytes = metric.Metadata{
 		Name:        "raft.loaded_entries.bytes",
@@ -1339,6 +1416,22 @@ state machines on this node.`,
 		Measurement: "Commands",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaRaftApplyLagEntries = metric.Metadata{
+		Name: "raft.apply.lag_entries",
+		Help: `Number of committed Raft entries that the replicas on this store haven't applied yet.
+
+A growing number of unapplied entries indicates that the Raft apply loops can't
+keep up with the rate at which entries are committed, for instance because of
+slow storage or large commands. See also raft.apply.oldest_unapplied_age.`,
+		Measurement: "Log Entries",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRaftApplyOldestUnappliedAge = metric.Metadata{
+		Name:        "raft.apply.oldest_unapplied_age",
+		Help:        `Longest time any committed Raft entry on this store has been waiting to be applied`,
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 	metaRaftLogCommitLatency = metric.Metadata{
 		Name: "raft.process.logcommit.latency",
 		Help: `Latency histogram for committing Raft log entries to stable storage
@@ -1449,6 +1542,26 @@ of processing.
 		Measurement: "Latency",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaRaftSchedulerPriorityLatency = metric.Metadata{
+		Name: "raft.scheduler.priority_latency",
+		Help: `Queueing durations for priority ranges waiting to be processed by the Raft scheduler.
+
+This histogram measures the same delay as raft.scheduler.latency, but only for
+ranges that are processed by the dedicated priority workers, such as the node
+liveness range.
+`,
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaRaftSchedulerWorkers = metric.Metadata{
+		Name: "raft.scheduler.workers",
+		Help: `Number of Raft scheduler workers for this store.
+
+This changes over time if kv.raft.scheduler.dynamic_resizing.enabled is set.
+`,
+		Measurement: "Workers",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaRaftTimeoutCampaign = metric.Metadata{
 		Name:        "raft.timeoutcampaign",
 		Help:        "Number of Raft replicas campaigning after missed heartbeats from leader",
@@ -1479,6 +1592,19 @@ A common reason for elevated measurements on this metric is that a store is
 falling behind on raft log application. The raft entry cache generally tracks
 entries that were recently appended, so if log application falls behind the
 cache will already have moved on to newer entries.
+`,
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaRaftStoragePrefetchBytes = metric.Metadata{
+		Name: "raft.storage.prefetch_bytes",
+		Help: `Counter of raftpb.Entry.Size() read from pebble ahead of time for raft log entries.
+
+These are the bytes of the entries that the leader read into the raft entry cache
+because followers catching up on the log were about to need them, see
+kv.raft_log.prefetch.enabled. Entries that are prefetched are then returned from
+the raft entry cache, so they count towards raft.entrycache.read_bytes rather than
+raft.storage.read_bytes.
 `,
 		Measurement: "Bytes",
 		Unit:        metric.Unit_BYTES,
@@ -1676,6 +1802,16 @@ difficult to meaningfully interpret this metric.`,
 		Measurement: "Log Entries",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaRaftLogTruncationForcedSnapshots = metric.Metadata{
+		Name: "raftlog.truncation_forced_snapshots",
+		Help: `Number of followers cut off from the Raft log by log truncations.
+
+Each such follower needs a Raft snapshot to catch up. Raft leaders only
+truncate the log past followers that have not been recently active and are not
+being replicated to, and only once the log has grown too large.`,
+		Measurement: "Snapshots",
+		Unit:        metric.Unit_COUNT,
+	}
 
 	metaRaftFollowerPaused = metric.Metadata{
 		Name: "admission.raft.paused_replicas",
@@ -2270,6 +2406,20 @@ throttled they do count towards 'delay.total' and 'delay.enginebackpressure'.
 		Measurement: "Lock-Queue Waiters",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaConcurrencyAverageLockWaitQueueWaitersForLock = metric.Metadata{
+		Name:        "kv.concurrency.avg_lock_wait_queue_waiters_for_lock",
+		Help:        "Average number of requests actively waiting in the lock wait-queues of locks with active wait-queues",
+		Measurement: "Lock-Queue Waiters",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConcurrencySkipLockedSkipped = metric.Metadata{
+		Name: "kv.concurrency.skip_locked.skipped",
+		Help: "Number of keys skipped over by reads using the SKIP LOCKED wait policy " +
+			"because they were locked by another transaction. Reads of whole SQL rows " +
+			"count skipped rows instead",
+		Measurement: "Keys",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaLatchConflictWaitDurations = metric.Metadata{
 		Name:        "kv.concurrency.latch_conflict_wait_durations",
 		Help:        "Durations in nanoseconds spent on latch acquisition waiting for conflicts with other latches",
@@ -2337,6 +2487,20 @@ Note that the measurement does not include the duration for replicating the eval
 		Unit:        metric.Unit_COUNT,
 	}
 
+	metaHotKeyRanges = metric.Metadata{
+		Name:        "kv.loadsplitter.hotkey.ranges",
+		Help:        "Number of ranges whose load is concentrated on a single key, which load-based splitting cannot spread.",
+		Measurement: "Ranges",
+		Unit:        metric.Unit_COUNT,
+	}
+
+	metaHotKeyDetected = metric.Metadata{
+		Name:        "kv.loadsplitter.hotkey.detected",
+		Help:        "Number of times the load of a range was found to be concentrated on a single key.",
+		Measurement: "Occurrences",
+		Unit:        metric.Unit_COUNT,
+	}
+
 	metaSplitEstimatedStats = metric.Metadata{
 		Name:        "kv.split.estimated_stats",
 		Help:        "Number of splits that computed estimated MVCC stats.",
@@ -2357,6 +2521,39 @@ Note that the measurement does not include the duration for replicating the eval
 		Measurement: "Flush Utilization",
 		Unit:        metric.Unit_PERCENT,
 	}
+	metaStorageTenantFlushedBytes = metric.Metadata{
+		Name: "storage.tenant.flushed-bytes",
+		Help: "Estimated number of bytes flushed from memtables to L0 on behalf of " +
+			"the tenant, extrapolated from a sample of the flushed sstables",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaStorageTenantIngestedBytes = metric.Metadata{
+		Name: "storage.tenant.ingested-bytes",
+		Help: "Estimated number of bytes ingested into the LSM on behalf of the " +
+			"tenant, extrapolated from a sample of the ingested sstables",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaStorageTenantCompactedBytes = metric.Metadata{
+		Name: "storage.tenant.compacted-bytes",
+		Help: "Estimated number of bytes written by compactions on behalf of the " +
+			"tenant, extrapolated from a sample of the compacted sstables",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaSSTScrubberScrubbedBytes = metric.Metadata{
+		Name:        "storage.sst_scrubber.scrubbed-bytes",
+		Help:        "Number of bytes read by the sstable scrubber to validate the block checksums of cold sstables",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaSSTScrubberCorruptSSTables = metric.Metadata{
+		Name:        "storage.sst_scrubber.corrupt-sstables",
+		Help:        "Number of sstables whose block checksums failed to validate",
+		Measurement: "SSTables",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaWALBytesWritten = metric.Metadata{
 		Name:        "storage.wal.bytes_written",
 		Help:        "The number of bytes the storage engine has written to the WAL",
@@ -2396,6 +2593,20 @@ Note that the measurement does not include the duration for replicating the eval
 		Measurement: "Nanoseconds",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaStorageWALFailoverSecondarySwitchCount = metric.Metadata{
+		Name: "storage.wal.failover.secondary.switch.count",
+		Help: "Count of the number of times WAL writing has failed over from the primary to " +
+			"the secondary WAL directory. Only populated when WAL failover is configured",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaStorageWALFailoverSecondaryBytes = metric.Metadata{
+		Name: "storage.wal.failover.secondary.bytes",
+		Help: "Cumulative bytes written to WAL files in the secondary WAL directory. Only " +
+			"populated when WAL failover is configured",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
 	metaStorageWALFailoverWriteAndSyncLatency = metric.Metadata{
 		Name: "storage.wal.failover.write_and_sync.latency",
 		Help: "The observed latency for writing and syncing to the write ahead log. Only populated " +
@@ -2496,6 +2707,10 @@ type StoreMetrics struct {
 	// LoadSplitterMetrics stores metrics for load-based splitter split key.
 	*split.LoadSplitterMetrics
 
+	// Hot key protection metrics.
+	HotKeyRanges   *metric.Gauge
+	HotKeyDetected *metric.Counter
+
 	// Replica metrics.
 	ReplicaCount                  *metric.Gauge // Does not include uninitialized or reserved replicas.
 	ReservedReplicaCount          *metric.Gauge
@@ -2649,6 +2864,8 @@ type StoreMetrics struct {
 	WALFailoverSwitchCount            *metric.Gauge
 	WALFailoverPrimaryDuration        *metric.Gauge
 	WALFailoverSecondaryDuration      *metric.Gauge
+	WALFailoverSecondarySwitchCount   *metric.Gauge
+	WALFailoverSecondaryBytes         *metric.Gauge
 	WALFailoverWriteAndSyncLatency    *metric.ManualWindowHistogram
 
 	RdbCheckpoints *metric.Gauge
@@ -2688,6 +2905,8 @@ type StoreMetrics struct {
 	RangeSnapshotRebalancingSentBytes            *metric.Counter
 	RangeSnapshotRecvFailed                      *metric.Counter
 	RangeSnapshotRecvUnusable                    *metric.Counter
+	RangeSnapshotCompressedRcvdBytes             *metric.Counter
+	RangeSnapshotUncompressedRcvdBytes           *metric.Counter
 	RangeSnapShotCrossRegionSentBytes            *metric.Counter
 	RangeSnapShotCrossRegionRcvdBytes            *metric.Counter
 	RangeSnapShotCrossZoneSentBytes              *metric.Counter
@@ -2708,29 +2927,44 @@ type StoreMetrics struct {
 	DelegateSnapshotSuccesses  *metric.Counter
 	DelegateSnapshotFailures   *metric.Counter
 	DelegateSnapshotInProgress *metric.Gauge
+	// Fan-out delegate snapshot metrics. These are a subset of the delegate
+	// snapshot metrics above.
+	DelegateSnapshotFanOutSendBytes *metric.Counter
+	DelegateSnapshotFanOutSuccesses *metric.Counter
+	// DirectSnapshotSendBytes counts the bytes sent by self-delegated snapshots.
+	DirectSnapshotSendBytes *metric.Counter
 
 	// Raft processing metrics.
-	RaftTicks                  *metric.Counter
-	RaftProposalsDropped       *metric.Counter
-	RaftProposalsDroppedLeader *metric.Counter
-	RaftQuotaPoolPercentUsed   metric.IHistogram
-	RaftLoadedEntriesBytes     *metric.Gauge
-	RaftWorkingDurationNanos   *metric.Counter
-	RaftTickingDurationNanos   *metric.Counter
-	RaftCommandsProposed       *metric.Counter
-	RaftCommandsReproposed     *metric.Counter
-	RaftCommandsReproposedLAI  *metric.Counter
-	RaftCommandsPending        *metric.Gauge
-	RaftCommandsApplied        *metric.Counter
-	RaftLogCommitLatency       metric.IHistogram
-	RaftCommandCommitLatency   metric.IHistogram
-	RaftHandleReadyLatency     metric.IHistogram
-	RaftApplyCommittedLatency  metric.IHistogram
-	RaftReplicationLatency     metric.IHistogram
-	RaftSchedulerLatency       metric.IHistogram
-	RaftTimeoutCampaign        *metric.Counter
-	RaftStorageReadBytes       *metric.Counter
-	RaftStorageError           *metric.Counter
+	RaftTicks                    *metric.Counter
+	RaftProposalsDropped         *metric.Counter
+	RaftProposalsDroppedLeader   *metric.Counter
+	RaftQuotaPoolPercentUsed     metric.IHistogram
+	RaftQuotaPoolWaiters         *metric.Gauge
+	RaftQuotaPoolLongestWait     *metric.Gauge
+	RaftQuotaPoolWaitLatency     metric.IHistogram
+	RaftLoadedEntriesBytes       *metric.Gauge
+	RaftWorkingDurationNanos     *metric.Counter
+	RaftTickingDurationNanos     *metric.Counter
+	RaftCommandsProposed         *metric.Counter
+	RaftCommandsReproposed       *metric.Counter
+	RaftCommandsReproposedLAI    *metric.Counter
+	RaftCommandsPending          *metric.Gauge
+	RaftCommandsApplied          *metric.Counter
+	RaftApplyLagEntries          *metric.Gauge
+	RaftApplyOldestUnappliedAge  *metric.Gauge
+	RaftLogCommitLatency         metric.IHistogram
+	RaftCommandCommitLatency     metric.IHistogram
+	RaftHandleReadyLatency       metric.IHistogram
+	RaftApplyCommittedLatency    metric.IHistogram
+	RaftReplicationLatency       metric.IHistogram
+	RaftSchedulerLatency         metric.IHistogram
+	RaftSchedulerPriorityLatency metric.IHistogram
+	RaftSchedulerWorkers         *metric.Gauge
+	RaftLeaderDivergence         metric.IHistogram
+	RaftTimeoutCampaign          *metric.Counter
+	RaftStorageReadBytes         *metric.Counter
+	RaftStoragePrefetchBytes     *metric.Counter
+	RaftStorageError             *metric.Counter
 
 	// Raft message metrics.
 	//
@@ -2748,8 +2982,9 @@ type StoreMetrics struct {
 	RaftSentCrossZoneBytes   *metric.Counter
 
 	// Raft log metrics.
-	RaftLogFollowerBehindCount *metric.Gauge
-	RaftLogTruncated           *metric.Counter
+	RaftLogFollowerBehindCount       *metric.Gauge
+	RaftLogTruncated                 *metric.Counter
+	RaftLogTruncationForcedSnapshots *metric.Counter
 
 	RaftPausedFollowerCount       *metric.Gauge
 	RaftPausedFollowerDroppedMsgs *metric.Counter
@@ -2864,6 +3099,8 @@ type StoreMetrics struct {
 	AverageLockWaitDurationNanos   *metric.Gauge
 	MaxLockWaitDurationNanos       *metric.Gauge
 	MaxLockWaitQueueWaitersForLock *metric.Gauge
+	AvgLockWaitQueueWaitersForLock *metric.Gauge
+	SkipLockedSkipped              *metric.Counter
 	LatchWaitDurations             metric.IHistogram
 
 	// Ingestion metrics
@@ -2889,6 +3126,20 @@ type StoreMetrics struct {
 	FlushUtilization *metric.GaugeFloat64
 	FsyncLatency     *metric.ManualWindowHistogram
 
+	// Per-tenant LSM write metrics, from which the write amplification of each
+	// tenant can be estimated. See storage.TenantWriteAmp.
+	TenantFlushedBytes   *aggmetric.AggCounter
+	TenantIngestedBytes  *aggmetric.AggCounter
+	TenantCompactedBytes *aggmetric.AggCounter
+	tenantWriteAmp       struct {
+		syncutil.Mutex
+		children map[roachpb.TenantID]*tenantWriteAmpMetrics
+	}
+
+	// SSTable scrubber metrics.
+	SSTScrubberScrubbedBytes   *metric.Counter
+	SSTScrubberCorruptSSTables *metric.Counter
+
 	// Disk metrics
 	DiskReadBytes              *metric.Gauge
 	DiskReadCount              *metric.Gauge
@@ -2903,6 +3154,14 @@ type StoreMetrics struct {
 	DiskWriteMaxBytesPerSecond *metric.Gauge
 }
 
+// tenantWriteAmpMetrics contains the children of the per-tenant LSM write
+// metrics for a tenant.
+type tenantWriteAmpMetrics struct {
+	FlushedBytes   *aggmetric.Counter
+	IngestedBytes  *aggmetric.Counter
+	CompactedBytes *aggmetric.Counter
+}
+
 type tenantMetricsRef struct {
 	// All fields are internal. Don't access them.
 
@@ -3200,6 +3459,8 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 			PopularKeyCount: metric.NewCounter(metaPopularKeyCount),
 			NoSplitKeyCount: metric.NewCounter(metaNoSplitKeyCount),
 		},
+		HotKeyRanges:   metric.NewGauge(metaHotKeyRanges),
+		HotKeyDetected: metric.NewCounter(metaHotKeyDetected),
 
 		// Replica metrics.
 		ReplicaCount:                  metric.NewGauge(metaReplicaCount),
@@ -3358,11 +3619,13 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		categoryDiskWriteMetrics: pebbleCategoryDiskWriteMetricsContainer{
 			registry: storeRegistry,
 		},
-		WALBytesWritten:              metric.NewGauge(metaWALBytesWritten),
-		WALBytesIn:                   metric.NewGauge(metaWALBytesIn),
-		WALFailoverSwitchCount:       metric.NewGauge(metaStorageWALFailoverSwitchCount),
-		WALFailoverPrimaryDuration:   metric.NewGauge(metaStorageWALFailoverPrimaryDuration),
-		WALFailoverSecondaryDuration: metric.NewGauge(metaStorageWALFailoverSecondaryDuration),
+		WALBytesWritten:                 metric.NewGauge(metaWALBytesWritten),
+		WALBytesIn:                      metric.NewGauge(metaWALBytesIn),
+		WALFailoverSwitchCount:          metric.NewGauge(metaStorageWALFailoverSwitchCount),
+		WALFailoverPrimaryDuration:      metric.NewGauge(metaStorageWALFailoverPrimaryDuration),
+		WALFailoverSecondaryDuration:    metric.NewGauge(metaStorageWALFailoverSecondaryDuration),
+		WALFailoverSecondarySwitchCount: metric.NewGauge(metaStorageWALFailoverSecondarySwitchCount),
+		WALFailoverSecondaryBytes:       metric.NewGauge(metaStorageWALFailoverSecondaryBytes),
 		WALFailoverWriteAndSyncLatency: metric.NewManualWindowHistogram(
 			metaStorageWALFailoverWriteAndSyncLatency,
 			pebble.FsyncLatencyBuckets,
@@ -3399,6 +3662,8 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		RangeSnapshotRebalancingSentBytes:            metric.NewCounter(metaRangeSnapshotRebalancingSentBytes),
 		RangeSnapshotRecvFailed:                      metric.NewCounter(metaRangeSnapshotRecvFailed),
 		RangeSnapshotRecvUnusable:                    metric.NewCounter(metaRangeSnapshotRecvUnusable),
+		RangeSnapshotCompressedRcvdBytes:             metric.NewCounter(metaRangeSnapshotCompressedRcvdBytes),
+		RangeSnapshotUncompressedRcvdBytes:           metric.NewCounter(metaRangeSnapshotUncompressedRcvdBytes),
 		RangeSnapShotCrossRegionSentBytes:            metric.NewCounter(metaRangeSnapShotCrossRegionSentBytes),
 		RangeSnapShotCrossRegionRcvdBytes:            metric.NewCounter(metaRangeSnapShotCrossRegionRcvdBytes),
 		RangeSnapShotCrossZoneSentBytes:              metric.NewCounter(metaRangeSnapShotCrossZoneSentBytes),
@@ -3418,6 +3683,9 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		DelegateSnapshotSuccesses:                    metric.NewCounter(metaDelegateSnapshotSuccesses),
 		DelegateSnapshotFailures:                     metric.NewCounter(metaDelegateSnapshotFailures),
 		DelegateSnapshotInProgress:                   metric.NewGauge(metaDelegateSnapshotInProgress),
+		DelegateSnapshotFanOutSendBytes:              metric.NewCounter(metaDelegateSnapshotFanOutSendBytes),
+		DelegateSnapshotFanOutSuccesses:              metric.NewCounter(metaDelegateSnapshotFanOutSuccesses),
+		DirectSnapshotSendBytes:                      metric.NewCounter(metaDirectSnapshotSendBytes),
 
 		// Raft processing metrics.
 		RaftTicks:                  metric.NewCounter(metaRaftTicks),
@@ -3430,14 +3698,24 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 			SigFigs:      1,
 			BucketConfig: metric.Percent100Buckets,
 		}),
-		RaftLoadedEntriesBytes:    metric.NewGauge(metaRaftLoadedEntriesBytes),
-		RaftWorkingDurationNanos:  metric.NewCounter(metaRaftWorkingDurationNanos),
-		RaftTickingDurationNanos:  metric.NewCounter(metaRaftTickingDurationNanos),
-		RaftCommandsProposed:      metric.NewCounter(metaRaftCommandsProposed),
-		RaftCommandsReproposed:    metric.NewCounter(metaRaftCommandsReproposed),
-		RaftCommandsReproposedLAI: metric.NewCounter(metaRaftCommandsReproposedLAI),
-		RaftCommandsPending:       metric.NewGauge(metaRaftCommandsPending),
-		RaftCommandsApplied:       metric.NewCounter(metaRaftCommandsApplied),
+		RaftQuotaPoolWaiters:     metric.NewGauge(metaRaftQuotaPoolWaiters),
+		RaftQuotaPoolLongestWait: metric.NewGauge(metaRaftQuotaPoolLongestWait),
+		RaftQuotaPoolWaitLatency: metric.NewHistogram(metric.HistogramOptions{
+			Mode:         metric.HistogramModePreferHdrLatency,
+			Metadata:     metaRaftQuotaPoolWaitLatency,
+			Duration:     histogramWindow,
+			BucketConfig: metric.IOLatencyBuckets,
+		}),
+		RaftLoadedEntriesBytes:      metric.NewGauge(metaRaftLoadedEntriesBytes),
+		RaftWorkingDurationNanos:    metric.NewCounter(metaRaftWorkingDurationNanos),
+		RaftTickingDurationNanos:    metric.NewCounter(metaRaftTickingDurationNanos),
+		RaftCommandsProposed:        metric.NewCounter(metaRaftCommandsProposed),
+		RaftCommandsReproposed:      metric.NewCounter(metaRaftCommandsReproposed),
+		RaftCommandsReproposedLAI:   metric.NewCounter(metaRaftCommandsReproposedLAI),
+		RaftCommandsPending:         metric.NewGauge(metaRaftCommandsPending),
+		RaftCommandsApplied:         metric.NewCounter(metaRaftCommandsApplied),
+		RaftApplyLagEntries:         metric.NewGauge(metaRaftApplyLagEntries),
+		RaftApplyOldestUnappliedAge: metric.NewGauge(metaRaftApplyOldestUnappliedAge),
 		RaftLogCommitLatency: metric.NewHistogram(metric.HistogramOptions{
 			Mode:         metric.HistogramModePreferHdrLatency,
 			Metadata:     metaRaftLogCommitLatency,
@@ -3474,9 +3752,24 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 			Duration:     histogramWindow,
 			BucketConfig: metric.IOLatencyBuckets,
 		}),
-		RaftTimeoutCampaign:  metric.NewCounter(metaRaftTimeoutCampaign),
-		RaftStorageReadBytes: metric.NewCounter(metaRaftStorageReadBytes),
-		RaftStorageError:     metric.NewCounter(metaRaftStorageError),
+		RaftSchedulerPriorityLatency: metric.NewHistogram(metric.HistogramOptions{
+			Mode:         metric.HistogramModePreferHdrLatency,
+			Metadata:     metaRaftSchedulerPriorityLatency,
+			Duration:     histogramWindow,
+			BucketConfig: metric.IOLatencyBuckets,
+		}),
+		RaftSchedulerWorkers: metric.NewGauge(metaRaftSchedulerWorkers),
+		RaftLeaderDivergence: metric.NewHistogram(metric.HistogramOptions{
+			Metadata:     metaRaftLeaderDivergence,
+			Duration:     histogramWindow,
+			MaxVal:       time.Hour.Nanoseconds(),
+			SigFigs:      1,
+			BucketConfig: metric.LongRunning60mLatencyBuckets,
+		}),
+		RaftTimeoutCampaign:      metric.NewCounter(metaRaftTimeoutCampaign),
+		RaftStorageReadBytes:     metric.NewCounter(metaRaftStorageReadBytes),
+		RaftStoragePrefetchBytes: metric.NewCounter(metaRaftStoragePrefetchBytes),
+		RaftStorageError:         metric.NewCounter(metaRaftStorageError),
 
 		// Raft message metrics.
 		RaftRcvdMessages: [maxRaftMsgType + 1]*metric.Counter{
@@ -3505,8 +3798,9 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		RaftSentCrossZoneBytes:   metric.NewCounter(metaRaftSentCrossZoneBytes),
 
 		// Raft log metrics.
-		RaftLogFollowerBehindCount: metric.NewGauge(metaRaftLogFollowerBehindCount),
-		RaftLogTruncated:           metric.NewCounter(metaRaftLogTruncated),
+		RaftLogFollowerBehindCount:       metric.NewGauge(metaRaftLogFollowerBehindCount),
+		RaftLogTruncated:                 metric.NewCounter(metaRaftLogTruncated),
+		RaftLogTruncationForcedSnapshots: metric.NewCounter(metaRaftLogTruncationForcedSnapshots),
 
 		RaftPausedFollowerCount:       metric.NewGauge(metaRaftFollowerPaused),
 		RaftPausedFollowerDroppedMsgs: metric.NewCounter(metaRaftPausedFollowerDroppedMsgs),
@@ -3619,6 +3913,8 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		AverageLockWaitDurationNanos:   metric.NewGauge(metaConcurrencyAverageLockWaitDurationNanos),
 		MaxLockWaitDurationNanos:       metric.NewGauge(metaConcurrencyMaxLockWaitDurationNanos),
 		MaxLockWaitQueueWaitersForLock: metric.NewGauge(metaConcurrencyMaxLockWaitQueueWaitersForLock),
+		AvgLockWaitQueueWaitersForLock: metric.NewGauge(metaConcurrencyAverageLockWaitQueueWaitersForLock),
+		SkipLockedSkipped:              metric.NewCounter(metaConcurrencySkipLockedSkipped),
 		LatchWaitDurations: metric.NewHistogram(metric.HistogramOptions{
 			Mode:         metric.HistogramModePreferHdrLatency,
 			Metadata:     metaLatchConflictWaitDurations,
@@ -3647,6 +3943,14 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 			BucketConfig: metric.IOLatencyBuckets,
 		}),
 		FlushUtilization: metric.NewGaugeFloat64(metaStorageFlushUtilization),
+		TenantFlushedBytes: aggmetric.NewCounter(
+			metaStorageTenantFlushedBytes, multitenant.TenantIDLabel),
+		TenantIngestedBytes: aggmetric.NewCounter(
+			metaStorageTenantIngestedBytes, multitenant.TenantIDLabel),
+		TenantCompactedBytes: aggmetric.NewCounter(
+			metaStorageTenantCompactedBytes, multitenant.TenantIDLabel),
+		SSTScrubberScrubbedBytes:   metric.NewCounter(metaSSTScrubberScrubbedBytes),
+		SSTScrubberCorruptSSTables: metric.NewCounter(metaSSTScrubberCorruptSSTables),
 		FsyncLatency: metric.NewManualWindowHistogram(
 			metaStorageFsyncLatency,
 			pebble.FsyncLatencyBuckets,
@@ -3672,6 +3976,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		SplitsWithEstimatedStats:     metric.NewCounter(metaSplitEstimatedStats),
 		SplitEstimatedTotalBytesDiff: metric.NewCounter(metaSplitEstimatedTotalBytesDiff),
 	}
+	sm.tenantWriteAmp.children = make(map[roachpb.TenantID]*tenantWriteAmpMetrics)
 
 	storeRegistry.AddMetricStruct(sm)
 	storeRegistry.AddMetricStruct(sm.LoadSplitterMetrics)
@@ -3784,6 +4089,10 @@ func (sm *StoreMetrics) updateEngineMetrics(m storage.Metrics) {
 	sm.WALFailoverSwitchCount.Update(m.WAL.Failover.DirSwitchCount)
 	sm.WALFailoverPrimaryDuration.Update(m.WAL.Failover.PrimaryWriteDuration.Nanoseconds())
 	sm.WALFailoverSecondaryDuration.Update(m.WAL.Failover.SecondaryWriteDuration.Nanoseconds())
+	// WAL writing starts in the primary directory, so every other switch is a
+	// failover to the secondary.
+	sm.WALFailoverSecondarySwitchCount.Update((m.WAL.Failover.DirSwitchCount + 1) / 2)
+	sm.WALFailoverSecondaryBytes.Update(m.WALFailoverSecondaryBytes)
 	sm.BatchCommitCount.Update(int64(m.BatchCommitStats.Count))
 	sm.BatchCommitDuration.Update(int64(m.BatchCommitStats.TotalDuration))
 	sm.BatchCommitSemWaitDuration.Update(int64(m.BatchCommitStats.SemaphoreWaitDuration))
@@ -3795,6 +4104,7 @@ func (sm *StoreMetrics) updateEngineMetrics(m storage.Metrics) {
 	sm.SSTableZombieBytes.Update(int64(m.Table.ZombieSize))
 	sm.categoryIterMetrics.update(m.CategoryStats)
 	sm.categoryDiskWriteMetrics.update(m.DiskWriteStats)
+	sm.updateTenantWriteAmpMetrics(m.TenantWriteAmp)
 
 	for level, stats := range m.Levels {
 		sm.RdbBytesIngested[level].Update(int64(stats.BytesIngested))
@@ -3803,6 +4113,38 @@ func (sm *StoreMetrics) updateEngineMetrics(m storage.Metrics) {
 	}
 }
 
+// updateTenantWriteAmpMetrics updates the per-tenant LSM write metrics from
+// the estimates of the engine. The estimates are cumulative since the engine
+// was opened, so the counters of each tenant are incremented by the difference
+// with the previous estimates.
+func (sm *StoreMetrics) updateTenantWriteAmpMetrics(writes []storage.TenantWriteAmp) {
+	sm.tenantWriteAmp.Lock()
+	defer sm.tenantWriteAmp.Unlock()
+	for _, w := range writes {
+		m, ok := sm.tenantWriteAmp.children[w.TenantID]
+		if !ok {
+			tenantIDStr := w.TenantID.String()
+			m = &tenantWriteAmpMetrics{
+				FlushedBytes:   sm.TenantFlushedBytes.AddChild(tenantIDStr),
+				IngestedBytes:  sm.TenantIngestedBytes.AddChild(tenantIDStr),
+				CompactedBytes: sm.TenantCompactedBytes.AddChild(tenantIDStr),
+			}
+			sm.tenantWriteAmp.children[w.TenantID] = m
+		}
+		incTo(m.FlushedBytes, w.FlushedBytes)
+		incTo(m.IngestedBytes, w.IngestedBytes)
+		incTo(m.CompactedBytes, w.CompactedBytes)
+	}
+}
+
+// incTo increments the counter up to the given cumulative value. It is a no-op
+// if the counter is already at or above the value.
+func incTo(c *aggmetric.Counter, v int64) {
+	if delta := v - c.Value(); delta > 0 {
+		c.Inc(delta)
+	}
+}
+
 // updateCrossLocalityMetricsOnSnapshotSent updates cross-locality related store
 // metrics when outgoing snapshots are sent to the outgoingSnapshotStream. The
 // metrics being updated include 1. cross-region metrics, which monitor
@@ -3927,6 +4269,9 @@ func (sm *StoreMetrics) handleMetricsResult(ctx context.Context, metric result.M
 	sm.SplitEstimatedTotalBytesDiff.Inc(int64(metric.SplitEstimatedTotalBytesDiff))
 	metric.SplitEstimatedTotalBytesDiff = 0
 
+	sm.SkipLockedSkipped.Inc(int64(metric.SkipLockedSkipped))
+	metric.SkipLockedSkipped = 0
+
 	if metric != (result.Metrics{}) {
 		log.Fatalf(ctx, "unhandled fields in metrics result: %+v", metric)
 	}
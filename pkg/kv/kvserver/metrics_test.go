@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -93,3 +94,26 @@ func TestTenantsStorageMetricsConcurrency(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestUpdateTenantWriteAmpMetrics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sm := newStoreMetrics(time.Minute)
+	tenant := roachpb.MustMakeTenantID(10)
+	sm.updateTenantWriteAmpMetrics([]storage.TenantWriteAmp{{
+		TenantID: tenant, FlushedBytes: 100, IngestedBytes: 50, CompactedBytes: 300,
+	}})
+	require.Equal(t, int64(100), sm.TenantFlushedBytes.Count())
+	require.Equal(t, int64(50), sm.TenantIngestedBytes.Count())
+	require.Equal(t, int64(300), sm.TenantCompactedBytes.Count())
+
+	// The counters follow the cumulative estimates of the engine.
+	sm.updateTenantWriteAmpMetrics([]storage.TenantWriteAmp{{
+		TenantID: tenant, FlushedBytes: 150, IngestedBytes: 50, CompactedBytes: 700,
+	}})
+	require.Equal(t, int64(150), sm.TenantFlushedBytes.Count())
+	require.Equal(t, int64(50), sm.TenantIngestedBytes.Count())
+	require.Equal(t, int64(700), sm.TenantCompactedBytes.Count())
+	require.Equal(t, int64(700), sm.tenantWriteAmp.children[tenant].CompactedBytes.Value())
+}
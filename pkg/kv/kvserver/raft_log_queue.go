@@ -308,6 +308,9 @@ func newTruncateDecision(ctx context.Context, r *Replica) (truncateDecision, err
 		},
 	)
 	log.Eventf(ctx, "raft status after lastUpdateTimes check: %+v", raftStatus.Progress)
+	replicatingFollowers := replicatingFollowersFromFlowControl(
+		r.descRLocked(), r.mu.replicaFlowControlIntegration.streamState(),
+	)
 	r.mu.RUnlock()
 
 	input := truncateDecisionInput{
@@ -318,6 +321,7 @@ func newTruncateDecision(ctx context.Context, r *Replica) (truncateDecision, err
 		FirstIndex:           firstIndex,
 		LastIndex:            lastIndex,
 		PendingSnapshotIndex: pendingSnapshotIndex,
+		ReplicatingFollowers: replicatingFollowers,
 	}
 
 	decision := computeTruncateDecision(input)
@@ -349,13 +353,35 @@ func updateRaftProgressFromActivity(
 	}
 }
 
+// replicatingFollowersFromFlowControl returns the followers which the given
+// replication flow control stream state, of the raft leader, is connected to.
+// These are the followers the leader is actively replicating to, through
+// MsgApps, as opposed to followers that are paused, disconnected, or in need
+// of a snapshot. It returns nil if replication flow control isn't enabled for
+// the range.
+func replicatingFollowersFromFlowControl(
+	desc *roachpb.RangeDescriptor, state flowControlStreamState,
+) map[roachpb.ReplicaID]struct{} {
+	if len(state.connected) == 0 {
+		return nil
+	}
+	followers := make(map[roachpb.ReplicaID]struct{}, len(state.connected))
+	for _, repl := range desc.Replicas().Descriptors() {
+		if _, ok := state.connected[repl.StoreID]; ok {
+			followers[repl.ReplicaID] = struct{}{}
+		}
+	}
+	return followers
+}
+
 const (
-	truncatableIndexChosenViaCommitIndex     = "commit"
-	truncatableIndexChosenViaFollowers       = "followers"
-	truncatableIndexChosenViaProbingFollower = "probing follower"
-	truncatableIndexChosenViaPendingSnap     = "pending snapshot"
-	truncatableIndexChosenViaFirstIndex      = "first index"
-	truncatableIndexChosenViaLastIndex       = "last index"
+	truncatableIndexChosenViaCommitIndex          = "commit"
+	truncatableIndexChosenViaFollowers            = "followers"
+	truncatableIndexChosenViaReplicatingFollowers = "replicating followers"
+	truncatableIndexChosenViaProbingFollower      = "probing follower"
+	truncatableIndexChosenViaPendingSnap          = "pending snapshot"
+	truncatableIndexChosenViaFirstIndex           = "first index"
+	truncatableIndexChosenViaLastIndex            = "last index"
 )
 
 // No assumption should be made about the relationship between
@@ -375,12 +401,29 @@ type truncateDecisionInput struct {
 	LogSizeTrusted        bool // false when LogSize might be off
 	FirstIndex, LastIndex kvpb.RaftIndex
 	PendingSnapshotIndex  kvpb.RaftIndex
+	// ReplicatingFollowers is the set of followers whose replication flow
+	// control stream is connected, i.e. which are being caught up through
+	// MsgApps.
+	ReplicatingFollowers map[roachpb.ReplicaID]struct{}
 }
 
+// replicatingFollowerMaxLogSizeMultiple is the multiple of the target raft log
+// size beyond which followers being replicated to are no longer protected from
+// truncations. Flow tokens only bound the log growth ahead of these followers
+// for elastic work (see kvadmission.flow_control.mode), so this is what keeps
+// the log from growing without bounds behind a slow follower.
+const replicatingFollowerMaxLogSizeMultiple = 4
+
 func (input truncateDecisionInput) LogTooLarge() bool {
 	return input.LogSize > input.MaxLogSize
 }
 
+// LogTooLargeForReplicatingFollowers returns whether the log is too large to
+// keep protecting the followers being replicated to.
+func (input truncateDecisionInput) LogTooLargeForReplicatingFollowers() bool {
+	return input.LogSize > replicatingFollowerMaxLogSizeMultiple*input.MaxLogSize
+}
+
 // truncateDecision describes a truncation decision.
 // Beware: when extending this struct, be sure to adjust .String()
 // so that it is guaranteed to not contain any PII or confidential
@@ -515,7 +558,7 @@ func computeTruncateDecision(input truncateDecisionInput) truncateDecision {
 	// RaftStatus.Commit is updated at propose time.
 	decision.ProtectIndex(decision.CommitIndex, truncatableIndexChosenViaCommitIndex)
 
-	for _, progress := range input.RaftStatus.Progress {
+	for id, progress := range input.RaftStatus.Progress {
 		// Snapshots are expensive, so we try our best to avoid truncating past
 		// where a follower is.
 
@@ -555,6 +598,23 @@ func computeTruncateDecision(input truncateDecisionInput) truncateDecision {
 		// truncate it off as long as the raft log is not too large.
 		if !input.LogTooLarge() {
 			decision.ProtectIndex(kvpb.RaftIndex(progress.Match), truncatableIndexChosenViaFollowers)
+			continue
+		}
+
+		// Third, even if the log is too large, we don't truncate off a follower
+		// that the leader is still sending its log to, as per its replication
+		// flow control stream. Its activity may merely not have been observed in
+		// a while, and truncating the entries it's about to receive would force
+		// a snapshot right before it would have caught up through appends. The
+		// stream is disconnected as soon as the follower is found to be inactive
+		// or stops being replicated to, at which point it is no longer protected.
+		// Since regular writes don't necessarily deduct flow tokens, the log can
+		// keep growing ahead of such a follower, so the protection only holds up
+		// to a hard multiple of the target log size.
+		if _, ok := input.ReplicatingFollowers[roachpb.ReplicaID(id)]; ok &&
+			progress.State == tracker.StateReplicate &&
+			!input.LogTooLargeForReplicatingFollowers() {
+			decision.ProtectIndex(kvpb.RaftIndex(progress.Match), truncatableIndexChosenViaReplicatingFollowers)
 		}
 
 		// Otherwise, we let it truncate to the committed index.
@@ -737,6 +797,7 @@ func (rlq *raftLogQueue) process(
 		return false, err
 	}
 	r.store.metrics.RaftLogTruncated.Inc(int64(decision.NumTruncatableIndexes()))
+	r.store.metrics.RaftLogTruncationForcedSnapshots.Inc(int64(decision.NumNewRaftSnapshots()))
 	return true, nil
 }
 
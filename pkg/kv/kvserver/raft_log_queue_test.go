@@ -294,6 +294,91 @@ func TestComputeTruncateDecisionProgressStatusProbe(t *testing.T) {
 	})
 }
 
+func TestComputeTruncateDecisionReplicatingFollower(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// An inactive follower, at index 200, is truncated off once the log is too
+	// large, unless its replication flow control stream is connected and the
+	// log isn't beyond the hard limit.
+	for _, tc := range []struct {
+		name        string
+		state       tracker.StateType
+		replicating bool
+		logSize     int64
+		exp         string
+	}{{
+		name:  "disconnected",
+		state: tracker.StateReplicate,
+		exp:   "should truncate: true [truncate 390 entries to first index 400 (chosen via: commit); log too large (2.0 KiB > 1.0 KiB); implies 1 Raft snapshot]",
+	}, {
+		name:        "connected",
+		state:       tracker.StateReplicate,
+		replicating: true,
+		exp:         "should truncate: true [truncate 190 entries to first index 200 (chosen via: replicating followers); log too large (2.0 KiB > 1.0 KiB)]",
+	}, {
+		name:        "connected-log-beyond-limit",
+		state:       tracker.StateReplicate,
+		replicating: true,
+		logSize:     5 << 10,
+		exp:         "should truncate: true [truncate 390 entries to first index 400 (chosen via: commit); log too large (5.0 KiB > 1.0 KiB); implies 1 Raft snapshot]",
+	}, {
+		// The match index of a probing follower can't be trusted.
+		name:        "connected-probing",
+		state:       tracker.StateProbe,
+		replicating: true,
+		exp:         "should truncate: true [truncate 390 entries to first index 400 (chosen via: commit); log too large (2.0 KiB > 1.0 KiB)]",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			status := raft.Status{
+				Progress: map[uint64]tracker.Progress{
+					1: {Match: 500, Next: 501, RecentActive: true, State: tracker.StateReplicate},
+					2: {Match: 200, Next: 201, RecentActive: false, State: tc.state},
+					3: {Match: 400, Next: 401, RecentActive: true, State: tracker.StateReplicate},
+				},
+			}
+			status.Commit = 400
+			logSize := tc.logSize
+			if logSize == 0 {
+				logSize = 2048
+			}
+			input := truncateDecisionInput{
+				RaftStatus:     status,
+				LogSize:        logSize,
+				MaxLogSize:     1024,
+				LogSizeTrusted: true,
+				FirstIndex:     10,
+				LastIndex:      500,
+			}
+			if tc.replicating {
+				input.ReplicatingFollowers = map[roachpb.ReplicaID]struct{}{2: {}, 3: {}}
+			}
+			decision := computeTruncateDecision(input)
+			require.Equal(t, tc.exp, decision.String())
+		})
+	}
+}
+
+func TestReplicatingFollowersFromFlowControl(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	desc := &roachpb.RangeDescriptor{
+		InternalReplicas: []roachpb.ReplicaDescriptor{
+			{NodeID: 1, StoreID: 1, ReplicaID: 1},
+			{NodeID: 2, StoreID: 2, ReplicaID: 2},
+			{NodeID: 3, StoreID: 3, ReplicaID: 3},
+		},
+	}
+	require.Nil(t, replicatingFollowersFromFlowControl(desc, flowControlStreamState{}))
+	require.Equal(t,
+		map[roachpb.ReplicaID]struct{}{3: {}},
+		replicatingFollowersFromFlowControl(desc, flowControlStreamState{
+			connected: map[roachpb.StoreID]struct{}{3: {}, 4: {}},
+		}),
+	)
+}
+
 func TestTruncateDecisionZeroValue(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
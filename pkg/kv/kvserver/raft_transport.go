@@ -80,12 +80,32 @@ type RaftMessageResponseStream interface {
 	Send(*kvserverpb.RaftMessageResponse) error
 }
 
+// raftMessageBatchServerStream is the subset of the
+// MultiRaft_RaftMessageBatchServer interface that is needed to serve a
+// RaftMessageBatch stream. It is also implemented by the streams of the framed
+// raft transport.
+type raftMessageBatchServerStream interface {
+	Context() context.Context
+	Send(*kvserverpb.RaftMessageResponse) error
+	Recv() (*kvserverpb.RaftMessageRequestBatch, error)
+}
+
+// raftMessageBatchClientStream is the subset of the
+// MultiRaft_RaftMessageBatchClient interface that is needed to send raft
+// messages. It is also implemented by the streams of the framed raft
+// transport.
+type raftMessageBatchClientStream interface {
+	Context() context.Context
+	Send(*kvserverpb.RaftMessageRequestBatch) error
+	Recv() (*kvserverpb.RaftMessageResponse, error)
+}
+
 // lockedRaftMessageResponseStream is an implementation of
 // RaftMessageResponseStream which provides support for concurrent calls to
 // Send. Note that the default implementation of grpc.Stream for server
 // responses (grpc.serverStream) is not safe for concurrent calls to Send.
 type lockedRaftMessageResponseStream struct {
-	wrapped MultiRaft_RaftMessageBatchServer
+	wrapped raftMessageBatchServerStream
 	sendMu  syncutil.Mutex
 }
 
@@ -185,6 +205,15 @@ type RaftTransport struct {
 	incomingMessageHandlers syncutil.IntMap // map[roachpb.StoreID]*IncomingRaftMessageHandler
 	outgoingMessageHandlers syncutil.IntMap // map[roachpb.StoreID]*OutgoingRaftMessageHandler
 
+	// framed tracks the availability of the framed raft transport. See
+	// raft_transport_framed.go.
+	framed struct {
+		syncutil.Mutex
+		// unavailableUntil tracks the nodes to which the framed transport
+		// couldn't be used, and until when it isn't attempted again.
+		unavailableUntil map[roachpb.NodeID]time.Time
+	}
+
 	kvflowControl struct {
 		// Everything nested under this struct is used to return flow tokens
 		// from the receiver (where work was admitted) up to the sender (where
@@ -318,6 +347,7 @@ func NewRaftTransport(
 	t.kvflowControl.handles = kvflowHandles
 	t.kvflowControl.disconnectListener = disconnectListener
 	t.kvflowControl.mu.connectionTracker = newConnectionTrackerForFlowControl()
+	t.framed.unavailableUntil = make(map[roachpb.NodeID]time.Time)
 
 	t.initMetrics()
 	if grpcServer != nil {
@@ -472,7 +502,13 @@ func newRaftMessageResponse(
 }
 
 // RaftMessageBatch proxies the incoming requests to the listening server interface.
-func (t *RaftTransport) RaftMessageBatch(stream MultiRaft_RaftMessageBatchServer) (lastErr error) {
+func (t *RaftTransport) RaftMessageBatch(stream MultiRaft_RaftMessageBatchServer) error {
+	return t.handleRaftMessageBatch(stream)
+}
+
+// handleRaftMessageBatch serves a RaftMessageBatch stream, received either
+// over gRPC or over the framed raft transport.
+func (t *RaftTransport) handleRaftMessageBatch(stream raftMessageBatchServerStream) error {
 	errCh := make(chan error, 1)
 
 	// Node stopping error is caught below in the select.
@@ -644,7 +680,7 @@ func (t *RaftTransport) StopOutgoingMessage(storeID roachpb.StoreID) {
 // lost and a new instance of processQueue will be started by the next message
 // to be sent.
 func (t *RaftTransport) processQueue(
-	q *raftSendQueue, stream MultiRaft_RaftMessageBatchClient, class rpc.ConnectionClass,
+	q *raftSendQueue, stream raftMessageBatchClientStream, class rpc.ConnectionClass,
 ) error {
 	errCh := make(chan error, 1)
 
@@ -983,20 +1019,35 @@ func (t *RaftTransport) startProcessNewQueue(
 			t.kvflowControl.mu.connectionTracker.markNodeDisconnected(toNodeID, class)
 			t.kvflowControl.mu.Unlock()
 		}()
-		conn, err := t.dialer.Dial(ctx, toNodeID, class)
-		if err != nil {
-			// DialNode already logs sufficiently, so just return.
-			return
-		}
-
-		client := NewMultiRaftClient(conn)
 		batchCtx, cancel := context.WithCancel(ctx)
 		defer cancel()
 
-		stream, err := client.RaftMessageBatch(batchCtx) // closed via cancellation
-		if err != nil {
-			log.Warningf(ctx, "creating batch client for node %d failed: %+v", toNodeID, err)
-			return
+		var stream raftMessageBatchClientStream
+		if t.useFramedTransport(toNodeID) {
+			c, err := t.dialFramedConn(batchCtx, toNodeID)
+			if err != nil {
+				// The remote node might not support the framed transport. Fall
+				// back to gRPC, and don't try again for a while.
+				log.Warningf(ctx, "falling back to gRPC for raft messages to n%d: %v", toNodeID, err)
+				t.markFramedTransportUnavailable(toNodeID)
+			} else {
+				defer c.close(nil /* err */)
+				stream = framedRaftClientStream{c}
+			}
+		}
+		if stream == nil {
+			conn, err := t.dialer.Dial(ctx, toNodeID, class)
+			if err != nil {
+				// DialNode already logs sufficiently, so just return.
+				return
+			}
+
+			client := NewMultiRaftClient(conn)
+			stream, err = client.RaftMessageBatch(batchCtx) // closed via cancellation
+			if err != nil {
+				log.Warningf(ctx, "creating batch client for node %d failed: %+v", toNodeID, err)
+				return
+			}
 		}
 
 		if err := t.processQueue(q, stream, class); err != nil {
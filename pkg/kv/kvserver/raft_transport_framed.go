@@ -0,0 +1,474 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The framed raft transport is an alternative to the gRPC RaftMessageBatch
+// streams. It runs a lean length-prefixed protocol directly on top of a raw
+// connection (see rpc.Context.DialRawConn), which shares the RPC port with
+// gRPC. Each raft stream, i.e. each connection class to a node, gets its own
+// connection, like with gRPC, so that e.g. system traffic isn't held up by
+// default traffic.
+//
+// The read loop of a connection hands the messages it receives off to a queue
+// of up to framedRaftRecvQueueSize messages, from which they are consumed.
+// Once the queue is full, the read loop blocks until messages are consumed,
+// which, through TCP flow control, eventually blocks the sender. Messages are
+// limited to framedRaftMaxMessageSize, and the connection is reset if a larger
+// one is received.
+//
+// Each frame starts with a header made of the length of its payload (4 bytes)
+// and its kind (1 byte). The client sends RaftMessageRequestBatches, the
+// server RaftMessageResponses, and either side closes the stream by sending a
+// close frame before closing the connection.
+//
+// The framed transport is only used by clients if
+// kv.raft.transport.framed.enabled is set. Servers always accept it, and
+// clients fall back to gRPC if the remote node doesn't (e.g. because it runs
+// an older version), so that the transport can be switched independently on
+// each node.
+
+var framedRaftTransportEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.raft.transport.framed.enabled",
+	"if set, raft messages are sent to other nodes over a framed TCP protocol, "+
+		"with a connection per connection class, instead of gRPC streams; gRPC is "+
+		"still used for nodes that don't support it",
+	false,
+)
+
+const (
+	// framedRaftPreamble starts the connections of the framed raft transport.
+	framedRaftPreamble = "CRDBRAFT\x01"
+
+	// framedRaftHeaderSize is the size of a frame header.
+	framedRaftHeaderSize = 5
+
+	// framedRaftMaxMessageSize is the maximum size of a message. It is well
+	// above the maximum size of a raft command (kv.raft.command.max_size).
+	framedRaftMaxMessageSize = 256 << 20
+
+	// framedRaftRecvQueueSize is the number of received messages that a
+	// connection queues before blocking its read loop.
+	framedRaftRecvQueueSize = 16
+
+	// framedRaftRetryInterval is the time after which the framed transport is
+	// attempted again for a node to which it couldn't be used.
+	framedRaftRetryInterval = time.Minute
+)
+
+// framedRaftFrameKind is the kind of a frame of the framed raft transport.
+type framedRaftFrameKind byte
+
+const (
+	// framedRaftData frames carry a message.
+	framedRaftData framedRaftFrameKind = iota + 1
+	// framedRaftClose frames close the stream. The payload, if any, is the
+	// error the stream was closed with.
+	framedRaftClose
+)
+
+// MatchFramedRaftTransport returns whether a connection accepted on the RPC
+// port, starting with the given bytes, belongs to the framed raft transport.
+// It is meant to be used as a connection multiplexer matcher.
+func MatchFramedRaftTransport(r io.Reader) bool {
+	var buf [len(framedRaftPreamble)]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return false
+	}
+	return string(buf[:]) == framedRaftPreamble
+}
+
+// ServeFramed serves the framed raft transport on the given listener, which
+// receives the connections matched by MatchFramedRaftTransport. It returns
+// once the listener is closed.
+func (t *RaftTransport) ServeFramed(
+	ctx context.Context, ln net.Listener, rpcCtx *rpc.Context,
+) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		if err := t.stopper.RunAsyncTask(ctx, "storage.RaftTransport: serving framed connection",
+			func(ctx context.Context) {
+				t.serveFramedConn(ctx, conn, rpcCtx)
+			}); err != nil {
+			_ = conn.Close()
+			return err
+		}
+	}
+}
+
+// serveFramedConn serves a connection of the framed raft transport, until it
+// is closed.
+func (t *RaftTransport) serveFramedConn(ctx context.Context, conn net.Conn, rpcCtx *rpc.Context) {
+	ctx, cancel := t.stopper.WithCancelOnQuiesce(ctx)
+	defer cancel()
+	// The connection multiplexer replays the preamble that it matched.
+	if !MatchFramedRaftTransport(conn) {
+		_ = conn.Close()
+		return
+	}
+	rawConn, err := rpcCtx.AcceptRawConn(ctx, conn)
+	if err != nil {
+		log.Warningf(ctx, "rejecting framed raft transport connection: %v", err)
+		_ = conn.Close()
+		return
+	}
+	c := newFramedRaftConn(ctx, rawConn)
+	if err := t.stopper.RunAsyncTask(ctx, "storage.RaftTransport: processing framed batch",
+		func(ctx context.Context) {
+			c.close(t.handleRaftMessageBatch(framedRaftServerStream{c}))
+		}); err != nil {
+		c.close(err)
+		return
+	}
+	t.runFramedConn(ctx, c)
+}
+
+// runFramedConn reads the frames of the given connection until it breaks or
+// the stopper quiesces.
+func (t *RaftTransport) runFramedConn(ctx context.Context, c *framedRaftConn) {
+	if err := t.stopper.RunAsyncTask(ctx, "storage.RaftTransport: closing framed connection",
+		func(ctx context.Context) {
+			select {
+			case <-t.stopper.ShouldQuiesce():
+				c.teardown(errors.New("node is stopping"))
+			case <-c.closed:
+			}
+		}); err != nil {
+		c.teardown(err)
+		return
+	}
+	c.teardown(c.readLoop())
+}
+
+// useFramedTransport returns whether raft messages to the given node should be
+// sent over the framed transport.
+func (t *RaftTransport) useFramedTransport(nodeID roachpb.NodeID) bool {
+	if !framedRaftTransportEnabled.Get(&t.st.SV) {
+		return false
+	}
+	t.framed.Lock()
+	defer t.framed.Unlock()
+	until, ok := t.framed.unavailableUntil[nodeID]
+	if ok && timeutil.Now().Before(until) {
+		return false
+	}
+	delete(t.framed.unavailableUntil, nodeID)
+	return true
+}
+
+// markFramedTransportUnavailable records that the framed transport couldn't be
+// used for the given node, so that gRPC is used for a while.
+func (t *RaftTransport) markFramedTransportUnavailable(nodeID roachpb.NodeID) {
+	t.metrics.FramedFallbacks.Inc(1)
+	t.framed.Lock()
+	defer t.framed.Unlock()
+	t.framed.unavailableUntil[nodeID] = timeutil.Now().Add(framedRaftRetryInterval)
+}
+
+// dialFramedConn opens a connection of the framed transport to the given
+// node, carrying a new raft stream.
+func (t *RaftTransport) dialFramedConn(
+	ctx context.Context, nodeID roachpb.NodeID,
+) (*framedRaftConn, error) {
+	conn, err := t.dialer.DialRaw(ctx, nodeID, []byte(framedRaftPreamble))
+	if err != nil {
+		return nil, err
+	}
+	c := newFramedRaftConn(ctx, conn)
+	t.metrics.FramedConns.Inc(1)
+	c.onClose = func() {
+		t.metrics.FramedConns.Dec(1)
+	}
+	if err := t.stopper.RunAsyncTask(ctx, "storage.RaftTransport: reading framed connection",
+		func(ctx context.Context) {
+			t.runFramedConn(ctx, c)
+		}); err != nil {
+		c.teardown(err)
+		return nil, err
+	}
+	return c, nil
+}
+
+// framedRaftConn is a connection of the framed raft transport, carrying a
+// raft stream.
+type framedRaftConn struct {
+	ctx  context.Context
+	conn net.Conn
+
+	// recvQueue hands the messages received by the read loop off to recv.
+	recvQueue chan []byte
+	// onClose, if set, is called once the connection is closed.
+	onClose func()
+	// closed is closed once the connection is closed.
+	closed chan struct{}
+
+	writeMu struct {
+		syncutil.Mutex
+		w      *bufio.Writer
+		header [framedRaftHeaderSize]byte
+	}
+
+	mu struct {
+		syncutil.Mutex
+		// err is set once the connection is closed.
+		err error
+		// closing is set once the stream was closed locally.
+		closing bool
+	}
+}
+
+func newFramedRaftConn(ctx context.Context, conn net.Conn) *framedRaftConn {
+	c := &framedRaftConn{
+		ctx:       ctx,
+		conn:      conn,
+		recvQueue: make(chan []byte, framedRaftRecvQueueSize),
+		closed:    make(chan struct{}),
+	}
+	c.writeMu.w = bufio.NewWriter(conn)
+	return c
+}
+
+// teardown closes the connection with the given error, without notifying the
+// remote node. Messages already queued can still be received.
+func (c *framedRaftConn) teardown(err error) {
+	if err == nil {
+		err = io.EOF
+	}
+	c.mu.Lock()
+	if c.mu.err != nil {
+		c.mu.Unlock()
+		return
+	}
+	c.mu.err = err
+	c.mu.Unlock()
+
+	_ = c.conn.Close()
+	close(c.closed)
+	if c.onClose != nil {
+		c.onClose()
+	}
+}
+
+// reset closes the connection, resetting it if possible so that the remote
+// node doesn't keep sending data that won't be read.
+func (c *framedRaftConn) reset(err error) {
+	conn := c.conn
+	if nc, ok := conn.(interface{ NetConn() net.Conn }); ok {
+		conn = nc.NetConn()
+	}
+	if tc, ok := conn.(*net.TCPConn); ok {
+		_ = tc.SetLinger(0)
+	}
+	c.teardown(err)
+}
+
+// readLoop reads the frames of the connection and queues the messages they
+// carry, until the connection breaks or the stream is closed.
+func (c *framedRaftConn) readLoop() error {
+	r := bufio.NewReader(c.conn)
+	var header [framedRaftHeaderSize]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return err
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		kind := framedRaftFrameKind(header[4])
+		if length > framedRaftMaxMessageSize {
+			err := errors.Errorf("message of %d bytes exceeds the maximum of %d bytes",
+				length, framedRaftMaxMessageSize)
+			c.reset(err)
+			return err
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+
+		switch kind {
+		case framedRaftData:
+			select {
+			case c.recvQueue <- payload:
+			case <-c.closed:
+				return nil
+			}
+
+		case framedRaftClose:
+			if length > 0 {
+				return errors.Newf("stream closed by remote node: %s", payload)
+			}
+			return io.EOF
+
+		default:
+			return errors.Errorf("unknown frame kind %d", kind)
+		}
+	}
+}
+
+// writeFrame writes a frame to the connection. The connection is closed if
+// the frame can't be written.
+func (c *framedRaftConn) writeFrame(kind framedRaftFrameKind, payload []byte) error {
+	err := func() error {
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+		header := c.writeMu.header[:]
+		binary.BigEndian.PutUint32(header[0:4], uint32(len(payload)))
+		header[4] = byte(kind)
+		if _, err := c.writeMu.w.Write(header); err != nil {
+			return err
+		}
+		if _, err := c.writeMu.w.Write(payload); err != nil {
+			return err
+		}
+		return c.writeMu.w.Flush()
+	}()
+	if err != nil {
+		c.teardown(err)
+	}
+	return err
+}
+
+// send sends a message on the stream.
+func (c *framedRaftConn) send(msg protoutil.Message) error {
+	data, err := protoutil.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if len(data) > framedRaftMaxMessageSize {
+		return errors.Errorf("message of %d bytes exceeds the maximum of %d bytes",
+			len(data), framedRaftMaxMessageSize)
+	}
+	c.mu.Lock()
+	err = c.mu.err
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(framedRaftData, data)
+}
+
+// recv receives a message from the stream. The messages received before the
+// connection was closed are returned before its error.
+func (c *framedRaftConn) recv(msg protoutil.Message) error {
+	select {
+	case data := <-c.recvQueue:
+		return protoutil.Unmarshal(data, msg)
+	default:
+	}
+	select {
+	case data := <-c.recvQueue:
+		return protoutil.Unmarshal(data, msg)
+	case <-c.closed:
+		select {
+		case data := <-c.recvQueue:
+			return protoutil.Unmarshal(data, msg)
+		default:
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.mu.err
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+}
+
+// close closes the stream and the connection, notifying the remote node of the
+// given error, if any.
+func (c *framedRaftConn) close(err error) {
+	c.mu.Lock()
+	closing := c.mu.closing || c.mu.err != nil
+	c.mu.closing = true
+	c.mu.Unlock()
+	if !closing {
+		var payload []byte
+		if err != nil && !errors.Is(err, io.EOF) {
+			payload = []byte(err.Error())
+		}
+		// If the connection is broken, there is nobody to notify.
+		_ = c.writeFrame(framedRaftClose, payload)
+	}
+	c.teardown(errors.New("stream closed"))
+}
+
+// framedRaftClientStream is the client side of a stream of the framed raft
+// transport.
+type framedRaftClientStream struct {
+	*framedRaftConn
+}
+
+var _ raftMessageBatchClientStream = framedRaftClientStream{}
+
+// Context implements the raftMessageBatchClientStream interface.
+func (s framedRaftClientStream) Context() context.Context {
+	return s.ctx
+}
+
+// Send implements the raftMessageBatchClientStream interface.
+func (s framedRaftClientStream) Send(batch *kvserverpb.RaftMessageRequestBatch) error {
+	return s.send(batch)
+}
+
+// Recv implements the raftMessageBatchClientStream interface.
+func (s framedRaftClientStream) Recv() (*kvserverpb.RaftMessageResponse, error) {
+	resp := &kvserverpb.RaftMessageResponse{}
+	if err := s.recv(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// framedRaftServerStream is the server side of a stream of the framed raft
+// transport.
+type framedRaftServerStream struct {
+	*framedRaftConn
+}
+
+var _ raftMessageBatchServerStream = framedRaftServerStream{}
+
+// Context implements the raftMessageBatchServerStream interface.
+func (s framedRaftServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// Send implements the raftMessageBatchServerStream interface.
+func (s framedRaftServerStream) Send(resp *kvserverpb.RaftMessageResponse) error {
+	return s.send(resp)
+}
+
+// Recv implements the raftMessageBatchServerStream interface.
+func (s framedRaftServerStream) Recv() (*kvserverpb.RaftMessageRequestBatch, error) {
+	batch := &kvserverpb.RaftMessageRequestBatch{}
+	if err := s.recv(batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
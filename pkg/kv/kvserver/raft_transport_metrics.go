@@ -25,6 +25,9 @@ type RaftTransportMetrics struct {
 	ReverseRcvd *metric.Counter
 
 	FlowTokenDispatchesDropped *metric.Counter
+
+	FramedConns     *metric.Gauge
+	FramedFallbacks *metric.Counter
 }
 
 func (t *RaftTransport) initMetrics() {
@@ -100,5 +103,23 @@ responses to Raft messages. Responses are received over another stream.`,
 			Measurement: "Dispatches",
 			Unit:        metric.Unit_COUNT,
 		}),
+
+		FramedConns: metric.NewGauge(metric.Metadata{
+			Name:        "raft.transport.framed-conns",
+			Help:        "Number of open outgoing connections of the framed Raft Transport",
+			Measurement: "Connections",
+			Unit:        metric.Unit_COUNT,
+		}),
+
+		FramedFallbacks: metric.NewCounter(metric.Metadata{
+			Name: "raft.transport.framed-fallbacks",
+			Help: `Number of times the Raft Transport fell back to gRPC after failing to
+use the framed transport for a peer.
+
+This is expected while the peer runs a version that doesn't support the framed
+transport. Otherwise, this could indicate network or certificate issues.`,
+			Measurement: "Fallbacks",
+			Unit:        metric.Unit_COUNT,
+		}),
 	}
 }
@@ -18,6 +18,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cmux"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
@@ -543,6 +544,84 @@ func TestRaftTransportIndependentRanges(t *testing.T) {
 	}
 }
 
+// TestRaftTransportFramed verifies that raft messages are sent over the framed
+// transport to the nodes that serve it, and over gRPC to the nodes that don't.
+func TestRaftTransportFramed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	kvserver.FramedRaftTransportEnabled.Override(ctx, &st.SV, true)
+	rttc := newRaftTransportTestContext(t, st)
+	defer rttc.Stop()
+
+	// Node 1 only serves gRPC.
+	grpcOnly := roachpb.ReplicaDescriptor{NodeID: 1, StoreID: 1, ReplicaID: 1}
+	grpcOnlyTransport := rttc.AddNode(grpcOnly.NodeID)
+
+	// Node 2 serves the framed transport on its RPC port, like servers do.
+	framed := roachpb.ReplicaDescriptor{NodeID: 2, StoreID: 2, ReplicaID: 2}
+	grpcServer, err := rpc.NewServer(ctx, rttc.nodeRPCContext)
+	require.NoError(t, err)
+	framedTransport := kvserver.NewRaftTransport(
+		log.MakeTestingAmbientCtxWithNewTracer(),
+		rttc.st,
+		nodedialer.New(rttc.nodeRPCContext, gossip.AddressResolver(rttc.gossip)),
+		grpcServer,
+		rttc.stopper,
+		kvflowdispatch.NewDummyDispatch(),
+		kvserver.NoopStoresFlowControlIntegration{},
+		kvserver.NoopRaftTransportDisconnectListener{},
+		nil, /* knobs */
+	)
+	rttc.transports[framed.NodeID] = framedTransport
+	ln, err := net.Listen(util.TestAddr.Network(), util.TestAddr.String())
+	require.NoError(t, err)
+	m := cmux.New(ln)
+	raftL := m.Match(kvserver.MatchFramedRaftTransport)
+	anyL := m.Match(cmux.Any())
+	rttc.stopper.AddCloser(stop.CloserFn(grpcServer.Stop))
+	require.NoError(t, rttc.stopper.RunAsyncTask(ctx, "listen-quiesce", func(context.Context) {
+		<-rttc.stopper.ShouldQuiesce()
+		netutil.FatalIfUnexpected(ln.Close())
+	}))
+	require.NoError(t, rttc.stopper.RunAsyncTask(ctx, "serve-grpc", func(context.Context) {
+		netutil.FatalIfUnexpected(grpcServer.Serve(anyL))
+	}))
+	require.NoError(t, rttc.stopper.RunAsyncTask(ctx, "serve-framed-raft", func(ctx context.Context) {
+		_ = framedTransport.ServeFramed(ctx, raftL, rttc.nodeRPCContext)
+	}))
+	require.NoError(t, rttc.stopper.RunAsyncTask(ctx, "serve-mux", func(context.Context) {
+		netutil.FatalIfUnexpected(m.Serve())
+	}))
+	rttc.GossipNode(framed.NodeID, ln.Addr())
+
+	const numMessages = 10
+	grpcOnlyCh := rttc.ListenStore(grpcOnly.NodeID, grpcOnly.StoreID)
+	framedCh := rttc.ListenStore(framed.NodeID, framed.StoreID)
+	receive := func(ch channelServer, i int) {
+		select {
+		case msg := <-ch.ch:
+			require.Equal(t, uint64(i), msg.Message.Commit)
+		case <-time.After(testutils.DefaultSucceedsSoonDuration):
+			t.Fatalf("timeout waiting for message %d", i)
+		}
+	}
+	for i := 0; i < numMessages; i++ {
+		require.True(t, rttc.Send(grpcOnly, framed, 1, raftpb.Message{Commit: uint64(i)}))
+		receive(framedCh, i)
+		require.True(t, rttc.Send(framed, grpcOnly, 1, raftpb.Message{Commit: uint64(i)}))
+		receive(grpcOnlyCh, i)
+	}
+
+	// Node 1 reached node 2 over the framed transport, and node 2 fell back to
+	// gRPC to reach node 1.
+	require.Equal(t, int64(1), grpcOnlyTransport.Metrics().FramedConns.Value())
+	require.Zero(t, grpcOnlyTransport.Metrics().FramedFallbacks.Count())
+	require.Zero(t, framedTransport.Metrics().FramedConns.Value())
+	require.Equal(t, int64(1), framedTransport.Metrics().FramedFallbacks.Count())
+}
+
 // TestReopenConnection verifies that if a raft response indicates that the
 // expected store isn't present on the node, that the connection gets
 // terminated and reopened before retrying, to ensure that the transport
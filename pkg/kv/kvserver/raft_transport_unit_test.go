@@ -12,6 +12,7 @@ package kvserver
 
 import (
 	"context"
+	"encoding/binary"
 	"math/rand"
 	"net"
 	"sync"
@@ -27,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -112,3 +114,62 @@ func TestRaftTransportStartNewQueue(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestFramedRaftConnRecvQueue verifies that the read loop of a connection of
+// the framed raft transport blocks once framedRaftRecvQueueSize messages are
+// queued, until messages are consumed, and that it resets the connection when
+// a message exceeds framedRaftMaxMessageSize.
+func TestFramedRaftConnRecvQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	local, remote := net.Pipe()
+	defer func() { _ = remote.Close() }()
+	c := newFramedRaftConn(ctx, local)
+	readLoopErr := make(chan error, 1)
+	go func() {
+		readLoopErr <- c.readLoop()
+	}()
+
+	// The messages are larger than the buffer of the read loop's reader, so
+	// that it doesn't read ahead.
+	key := roachpb.Key(make([]byte, 8<<10))
+	data, err := protoutil.Marshal(&roachpb.Span{Key: key})
+	require.NoError(t, err)
+	writeFrame := func(length int, payload []byte) error {
+		frame := make([]byte, framedRaftHeaderSize, framedRaftHeaderSize+len(payload))
+		binary.BigEndian.PutUint32(frame[0:4], uint32(length))
+		frame[4] = byte(framedRaftData)
+		_, err := remote.Write(append(frame, payload...))
+		return err
+	}
+
+	// The read loop reads one more message than it can queue, and then
+	// blocks.
+	for i := 0; i < framedRaftRecvQueueSize+1; i++ {
+		require.NoError(t, writeFrame(len(data), data))
+	}
+	written := make(chan error, 1)
+	go func() {
+		written <- writeFrame(len(data), data)
+	}()
+	select {
+	case <-written:
+		t.Fatal("message read beyond the queue limit")
+	case <-time.After(10 * time.Millisecond):
+	}
+	var span roachpb.Span
+	require.NoError(t, c.recv(&span))
+	require.Equal(t, key, span.Key)
+	require.NoError(t, <-written)
+	for i := 0; i < framedRaftRecvQueueSize+1; i++ {
+		require.NoError(t, c.recv(&span))
+	}
+
+	// A message exceeding the maximum size resets the connection.
+	require.NoError(t, writeFrame(framedRaftMaxMessageSize+1, nil))
+	require.ErrorContains(t, <-readLoopErr, "exceeds the maximum")
+	<-c.closed
+	require.Error(t, c.recv(&span))
+}
@@ -30,16 +30,32 @@ import (
 type Cache struct {
 	metrics  Metrics
 	maxBytes int32
+	minBytes int32
 
 	// accessed with atomics
 	bytes   int32
 	entries int32
+	limit   int32 // the current size limit, in [minBytes, maxBytes]
 
 	mu    syncutil.Mutex
 	lru   partitionList
 	parts map[roachpb.RangeID]*partition
+	// pinned is the set of ranges whose partitions are only evicted once no
+	// unpinned partitions remain, see Pin.
+	pinned map[roachpb.RangeID]struct{}
+	// lastAccesses and lastHits are the values of the corresponding metrics as
+	// of the last call to Adapt.
+	lastAccesses, lastHits int64
 }
 
+// Adaptive sizing
+//
+// The size of the cache is bounded by maxBytes, as provided to NewCache, but the
+// limit it enforces can be adjusted between minBytes and maxBytes by calls to
+// Adapt. The limit is lowered under memory pressure, to release memory, and is
+// raised again once the cache is full and its hit rate is low, which indicates
+// that the cache is too small for the working set of the store.
+
 // Design
 //
 // Cache is designed to be a shared store-wide object which incurs low
@@ -110,17 +126,37 @@ type rangeCache interface {
 // ringBuf implements rangeCache.
 var _ rangeCache = (*ringBuf)(nil)
 
+// minBytesFraction is the fraction of maxBytes below which Adapt does not
+// lower the cache's size limit.
+const minBytesFraction = 8
+
+const (
+	// adaptMinAccesses is the minimum number of accesses since the last call to
+	// Adapt for the hit rate to be considered representative.
+	adaptMinAccesses = 100
+	// adaptTargetHitRate is the hit rate below which a full cache grows.
+	adaptTargetHitRate = 0.95
+	// adaptFullFraction is the fraction of the size limit above which the cache
+	// is considered full.
+	adaptFullFraction = 0.9
+)
+
 // NewCache creates a cache with a max size.
 // Size must be less than math.MaxInt32.
 func NewCache(maxBytes uint64) *Cache {
 	if maxBytes > math.MaxInt32 {
 		maxBytes = math.MaxInt32
 	}
-	return &Cache{
+	c := &Cache{
 		maxBytes: int32(maxBytes),
+		minBytes: int32(maxBytes / minBytesFraction),
+		limit:    int32(maxBytes),
 		metrics:  makeMetrics(),
 		parts:    map[roachpb.RangeID]*partition{},
+		pinned:   map[roachpb.RangeID]struct{}{},
 	}
+	c.metrics.Limit.Update(int64(c.limit))
+	return c
 }
 
 // Metrics returns a struct which contains metrics for the raft entry cache.
@@ -128,10 +164,86 @@ func (c *Cache) Metrics() Metrics {
 	return c.metrics
 }
 
-// Drop drops all cached entries associated with the specified range.
+// MaxBytes returns the maximum size of the cache, as provided to NewCache.
+func (c *Cache) MaxBytes() uint64 {
+	return uint64(c.maxBytes)
+}
+
+// Limit returns the size limit currently enforced by the cache.
+func (c *Cache) Limit() uint64 {
+	return uint64(atomic.LoadInt32(&c.limit))
+}
+
+// SetLimit sets the size limit enforced by the cache, evicting partitions as
+// needed to respect it. The limit is clamped to [maxBytes/8, maxBytes].
+func (c *Cache) SetLimit(limit uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLimitLocked(limit)
+}
+
+func (c *Cache) setLimitLocked(limit uint64) {
+	if limit > uint64(c.maxBytes) {
+		limit = uint64(c.maxBytes)
+	} else if limit < uint64(c.minBytes) {
+		limit = uint64(c.minBytes)
+	}
+	atomic.StoreInt32(&c.limit, int32(limit))
+	c.metrics.Limit.Update(int64(limit))
+	c.evictLocked(0)
+}
+
+// Adapt adjusts the size limit of the cache based on its hit rate since the
+// last call, and on whether the process is under memory pressure. Under memory
+// pressure, the limit is halved. Otherwise, if the cache is full and its hit
+// rate is low, the limit is raised by a quarter. The new limit is returned.
+func (c *Cache) Adapt(memoryPressure bool) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	accesses, hits := c.metrics.Accesses.Count(), c.metrics.Hits.Count()
+	accessesDelta, hitsDelta := accesses-c.lastAccesses, hits-c.lastHits
+	c.lastAccesses, c.lastHits = accesses, hits
+
+	limit := uint64(atomic.LoadInt32(&c.limit))
+	if memoryPressure {
+		c.setLimitLocked(limit / 2)
+	} else if accessesDelta >= adaptMinAccesses &&
+		float64(hitsDelta)/float64(accessesDelta) < adaptTargetHitRate &&
+		float64(atomic.LoadInt32(&c.bytes)) >= adaptFullFraction*float64(limit) {
+		c.setLimitLocked(limit + limit/4)
+	}
+	return uint64(atomic.LoadInt32(&c.limit))
+}
+
+// Pin pins the entries of the specified range in the cache, such that they are
+// only evicted once no entries of unpinned ranges remain. It is used for ranges
+// with followers that are catching up on the log, and would otherwise have to
+// be served from disk.
+func (c *Cache) Pin(id roachpb.RangeID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pinned[id] = struct{}{}
+	c.metrics.Pinned.Update(int64(len(c.pinned)))
+}
+
+// Unpin reverts the effect of Pin for the specified range.
+func (c *Cache) Unpin(id roachpb.RangeID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unpinLocked(id)
+}
+
+func (c *Cache) unpinLocked(id roachpb.RangeID) {
+	delete(c.pinned, id)
+	c.metrics.Pinned.Update(int64(len(c.pinned)))
+}
+
+// Drop drops all cached entries associated with the specified range, and
+// unpins it.
 func (c *Cache) Drop(id roachpb.RangeID) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.unpinLocked(id)
 	p := c.getPartLocked(id, false /* create */, false /* recordUse */)
 	if p != nil {
 		c.updateGauges(c.evictPartitionLocked(p))
@@ -147,7 +259,7 @@ func (c *Cache) Add(id roachpb.RangeID, ents []raftpb.Entry, truncate bool) {
 		return
 	}
 	bytesGuessed := analyzeEntries(ents)
-	add := bytesGuessed <= c.maxBytes
+	add := bytesGuessed <= atomic.LoadInt32(&c.limit)
 	if !add {
 		bytesGuessed = 0
 	}
@@ -157,7 +269,9 @@ func (c *Cache) Add(id roachpb.RangeID, ents []raftpb.Entry, truncate bool) {
 	p := c.getPartLocked(id, add /* create */, true /* recordUse */)
 	if bytesGuessed > 0 {
 		c.evictLocked(bytesGuessed)
-		if len(c.parts) == 0 { // Get p again if we evicted everything.
+		// Get p again if we evicted it, which happens if we evicted everything
+		// but pinned partitions.
+		if _, ok := c.parts[id]; !ok {
 			p = c.getPartLocked(id, true /* create */, false /* recordUse */)
 		}
 		// Use the atomic (load|set)Size partition methods to avoid a race condition
@@ -221,6 +335,7 @@ func (c *Cache) Get(id roachpb.RangeID, idx kvpb.RaftIndex) (e raftpb.Entry, ok
 	p := c.getPartLocked(id, false /* create */, true /* recordUse */)
 	c.mu.Unlock()
 	if p == nil {
+		c.metrics.Misses.Inc(1)
 		return e, false
 	}
 	p.mu.RLock()
@@ -229,10 +344,28 @@ func (c *Cache) Get(id roachpb.RangeID, idx kvpb.RaftIndex) (e raftpb.Entry, ok
 	if ok {
 		c.metrics.Hits.Inc(1)
 		c.metrics.ReadBytes.Inc(int64(e.Size()))
+	} else {
+		c.metrics.Misses.Inc(1)
 	}
 	return e, ok
 }
 
+// Contains returns whether the entry for the specified index is present in the
+// cache. Unlike Get, it doesn't count as an access of the cache, nor as a use of
+// the range's partition.
+func (c *Cache) Contains(id roachpb.RangeID, idx kvpb.RaftIndex) bool {
+	c.mu.Lock()
+	p := c.getPartLocked(id, false /* create */, false /* recordUse */)
+	c.mu.Unlock()
+	if p == nil {
+		return false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.get(idx)
+	return ok
+}
+
 // Scan returns entries between [lo, hi) for specified range. If any entries are
 // returned for the specified indices, they will start with index lo and proceed
 // sequentially without gaps until 1) all entries exclusive of hi are fetched,
@@ -247,6 +380,7 @@ func (c *Cache) Scan(
 	p := c.getPartLocked(id, false /* create */, true /* recordUse */)
 	c.mu.Unlock()
 	if p == nil {
+		c.metrics.Misses.Inc(1)
 		return ents, 0, lo, false
 	}
 	p.mu.RLock()
@@ -259,6 +393,8 @@ func (c *Cache) Scan(
 	c.metrics.ReadBytes.Inc(int64(bytes))
 	if nextIdx == hi || exceededMaxBytes {
 		c.metrics.Hits.Inc(1)
+	} else {
+		c.metrics.Misses.Inc(1)
 	}
 	return ents, bytes, nextIdx, exceededMaxBytes
 }
@@ -277,12 +413,22 @@ func (c *Cache) getPartLocked(id roachpb.RangeID, create, recordUse bool) *parti
 }
 
 // evictLocked adds toAdd to the current cache byte size and evicts partitions
-// until the cache is below the maxBytes threshold. toAdd must be smaller than
-// c.maxBytes.
+// until the cache is below the size limit. Partitions of unpinned ranges are
+// evicted first, in LRU order. toAdd must be smaller than the size limit.
 func (c *Cache) evictLocked(toAdd int32) {
 	bytes := c.addBytes(toAdd)
-	for bytes > c.maxBytes && len(c.parts) > 0 {
+	limit := atomic.LoadInt32(&c.limit)
+	for p := c.lru.back(); p != nil && bytes > limit; {
+		prev := c.lru.prev(p)
+		if _, pinned := c.pinned[p.id]; !pinned {
+			bytes, _ = c.evictPartitionLocked(p)
+			c.metrics.Evictions.Inc(1)
+		}
+		p = prev
+	}
+	for bytes > limit && len(c.parts) > 0 {
 		bytes, _ = c.evictPartitionLocked(c.lru.back())
+		c.metrics.Evictions.Inc(1)
 	}
 }
 
@@ -453,6 +599,15 @@ func (l *partitionList) back() *partition {
 	return l.root.prev
 }
 
+// prev returns the partition preceding e in the list, or nil if e is the first
+// partition.
+func (l *partitionList) prev(e *partition) *partition {
+	if e.prev == &l.root {
+		return nil
+	}
+	return e.prev
+}
+
 func (l *partitionList) remove(e *partition) *partition {
 	if e == &l.root {
 		panic("cannot remove root list node")
@@ -564,6 +564,92 @@ func TestEntryCacheEviction(t *testing.T) {
 	}
 }
 
+func TestEntryCachePin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rangeID, rangeID2 := roachpb.RangeID(1), roachpb.RangeID(2)
+	c := NewCache(200 + 2*uint64(partitionSize))
+	c.Add(rangeID, []raftpb.Entry{newEntry(1, 40), newEntry(2, 40)}, true)
+	c.Pin(rangeID)
+	require.Equal(t, int64(1), c.Metrics().Pinned.Value())
+	c.Add(rangeID2, []raftpb.Entry{newEntry(1, 40), newEntry(2, 40)}, true)
+	// Exceed the size limit. The least recently used partition is pinned, so the
+	// partition of rangeID2 is evicted instead.
+	c.Add(rangeID2, []raftpb.Entry{newEntry(3, 60)}, true)
+	_, ok := c.Get(rangeID, 1)
+	require.True(t, ok)
+	_, ok = c.Get(rangeID2, 1)
+	require.False(t, ok)
+	_, ok = c.Get(rangeID2, 3)
+	require.True(t, ok)
+	require.Equal(t, int64(1), c.Metrics().Evictions.Count())
+
+	// Once unpinned, the least recently used partition is evicted.
+	c.Unpin(rangeID)
+	require.Equal(t, int64(0), c.Metrics().Pinned.Value())
+	c.Add(rangeID2, []raftpb.Entry{newEntry(4, 100)}, true)
+	_, ok = c.Get(rangeID, 1)
+	require.False(t, ok)
+	_, ok = c.Get(rangeID2, 4)
+	require.True(t, ok)
+	require.Equal(t, int64(2), c.Metrics().Evictions.Count())
+
+	// Dropping a range unpins it.
+	c.Pin(rangeID)
+	c.Drop(rangeID)
+	require.Equal(t, int64(0), c.Metrics().Pinned.Value())
+}
+
+func TestEntryCacheContains(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rangeID := roachpb.RangeID(1)
+	c := NewCache(100 + uint64(partitionSize))
+	require.False(t, c.Contains(rangeID, 1))
+	c.Add(rangeID, []raftpb.Entry{newEntry(1, 40), newEntry(2, 40)}, true)
+	require.True(t, c.Contains(rangeID, 1))
+	require.True(t, c.Contains(rangeID, 2))
+	require.False(t, c.Contains(rangeID, 3))
+	// Contains doesn't count as an access.
+	require.Equal(t, int64(0), c.Metrics().Accesses.Count())
+}
+
+func TestEntryCacheAdapt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rangeID, rangeID2 := roachpb.RangeID(1), roachpb.RangeID(2)
+	const maxBytes = 16 << 10
+	c := NewCache(maxBytes)
+	require.Equal(t, uint64(maxBytes), c.Limit())
+
+	// Memory pressure halves the limit, down to a minimum.
+	require.Equal(t, uint64(maxBytes/2), c.Adapt(true /* memoryPressure */))
+	require.Equal(t, uint64(maxBytes/4), c.Adapt(true /* memoryPressure */))
+	require.Equal(t, uint64(maxBytes/8), c.Adapt(true /* memoryPressure */))
+	require.Equal(t, uint64(maxBytes/8), c.Adapt(true /* memoryPressure */))
+	require.Equal(t, int64(maxBytes/8), c.Metrics().Limit.Value())
+
+	// Without accesses, the limit is unchanged.
+	require.Equal(t, uint64(maxBytes/8), c.Adapt(false /* memoryPressure */))
+
+	// Fill the cache, and miss on lookups. The limit is raised.
+	c.Add(rangeID, newEntries(1, 19, 100), true)
+	for i := 0; i < adaptMinAccesses; i++ {
+		_, ok := c.Get(rangeID2, 1)
+		require.False(t, ok)
+	}
+	require.Equal(t, int64(adaptMinAccesses), c.Metrics().Misses.Count())
+	require.Equal(t, uint64(maxBytes/8+maxBytes/32), c.Adapt(false /* memoryPressure */))
+
+	// With a high hit rate, the limit is unchanged.
+	for i := 0; i < adaptMinAccesses; i++ {
+		_, ok := c.Get(rangeID, 1)
+		require.True(t, ok)
+	}
+	require.Equal(t, uint64(maxBytes/8+maxBytes/32), c.Adapt(false /* memoryPressure */))
+
+	// The limit is clamped to the maximum size.
+	c.SetLimit(math.MaxUint64)
+	require.Equal(t, uint64(maxBytes), c.Limit())
+}
+
 // TestConcurrentUpdates ensures that concurrent updates to the same do not
 // race with each other.
 func TestConcurrentUpdates(t *testing.T) {
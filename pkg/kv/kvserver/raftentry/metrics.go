@@ -37,6 +37,30 @@ var (
 		Measurement: "Hits",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaEntryCacheMisses = metric.Metadata{
+		Name:        "raft.entrycache.misses",
+		Help:        "Number of unsuccessful cache lookups in the Raft entry cache",
+		Measurement: "Misses",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaEntryCacheEvictions = metric.Metadata{
+		Name:        "raft.entrycache.evictions",
+		Help:        "Number of ranges whose entries were evicted from the Raft entry cache to make room for other entries",
+		Measurement: "Evictions",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaEntryCacheLimit = metric.Metadata{
+		Name:        "raft.entrycache.limit_bytes",
+		Help:        "Current size limit of the Raft entry cache, as adapted to its hit rate and memory pressure",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaEntryCachePinned = metric.Metadata{
+		Name:        "raft.entrycache.pinned",
+		Help:        "Number of ranges pinned in the Raft entry cache while followers catch up on their log",
+		Measurement: "Ranges",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaEntryCacheReadBytes = metric.Metadata{
 		Name:        "raft.entrycache.read_bytes",
 		Help:        "Counter of bytes in entries returned from the Raft entry cache",
@@ -53,7 +77,11 @@ type Metrics struct {
 	Bytes     *metric.Gauge
 	Accesses  *metric.Counter
 	Hits      *metric.Counter
+	Misses    *metric.Counter
+	Evictions *metric.Counter
 	ReadBytes *metric.Counter
+	Limit     *metric.Gauge
+	Pinned    *metric.Gauge
 }
 
 func makeMetrics() Metrics {
@@ -62,6 +90,10 @@ func makeMetrics() Metrics {
 		Bytes:     metric.NewGauge(metaEntryCacheBytes),
 		Accesses:  metric.NewCounter(metaEntryCacheAccesses),
 		Hits:      metric.NewCounter(metaEntryCacheHits),
+		Misses:    metric.NewCounter(metaEntryCacheMisses),
+		Evictions: metric.NewCounter(metaEntryCacheEvictions),
 		ReadBytes: metric.NewCounter(metaEntryCacheReadBytes),
+		Limit:     metric.NewGauge(metaEntryCacheLimit),
+		Pinned:    metric.NewGauge(metaEntryCachePinned),
 	}
 }
@@ -72,12 +72,25 @@ const (
 	// EntryEncodingRaftConfChange, with the replacements
 	// raftpb.EntryConfChange{,V2} and raftpb.ConfChange{,V2} applied.
 	EntryEncodingRaftConfChangeV2
+	// EntryEncodingSideloadedPayload is the encoding of an entry with a large
+	// EntryEncodingStandardWith{,out}AC payload whose Data has been moved out of
+	// the raft log into sideloaded storage.
+	//
+	// This is a raftpb.Entry of type EntryNormal whose data slice has first
+	// byte == entryEncodingSideloadedPayloadPrefixByte, followed by the eight
+	// bytes of the CmdIDKey and nothing else. The original Data slice of the
+	// entry, including its own prefix, is stored as-is in the sideloaded
+	// storage. Unlike the other encodings, this one only exists in the raft log
+	// storage: entries are inlined back to their original encoding before they
+	// are handed to raft, so it is never proposed, sent or applied.
+	EntryEncodingSideloadedPayload
 )
 
 // IsSideloaded returns true if the encoding is
-// EntryEncodingSideloadedWith{,out}AC.
+// EntryEncodingSideloadedWith{,out}AC or EntryEncodingSideloadedPayload.
 func (enc EntryEncoding) IsSideloaded() bool {
-	return enc == EntryEncodingSideloadedWithAC || enc == EntryEncodingSideloadedWithoutAC
+	return enc == EntryEncodingSideloadedWithAC || enc == EntryEncodingSideloadedWithoutAC ||
+		enc == EntryEncodingSideloadedPayload
 }
 
 // UsesAdmissionControl returns true if the encoding is
@@ -98,6 +111,8 @@ func (enc EntryEncoding) prefixByte() byte {
 		return entryEncodingStandardWithoutACPrefixByte
 	case EntryEncodingSideloadedWithoutAC:
 		return entryEncodingSideloadedWithoutACPrefixByte
+	case EntryEncodingSideloadedPayload:
+		return entryEncodingSideloadedPayloadPrefixByte
 	default:
 		panic(fmt.Sprintf("invalid encoding: %v has no prefix byte", enc))
 	}
@@ -120,6 +135,10 @@ const (
 	// raftpb.Entry's Data slice for an Entry of encoding
 	// EntryEncodingSideloadedWithoutAC.
 	entryEncodingSideloadedWithoutACPrefixByte = byte(1) // 0b00000001
+	// entryEncodingSideloadedPayloadPrefixByte is the first byte of a
+	// raftpb.Entry's Data slice for an Entry of encoding
+	// EntryEncodingSideloadedPayload.
+	entryEncodingSideloadedPayloadPrefixByte = byte(4) // 0b00000100
 )
 
 const (
@@ -54,6 +54,8 @@ func EncodingOf(ent raftpb.Entry) (EntryEncoding, error) {
 		return EntryEncodingStandardWithoutAC, nil
 	case entryEncodingSideloadedWithoutACPrefixByte:
 		return EntryEncodingSideloadedWithoutAC, nil
+	case entryEncodingSideloadedPayloadPrefixByte:
+		return EntryEncodingSideloadedPayload, nil
 	default:
 		return 0, errors.AssertionFailedf("unknown command encoding version %d", ent.Data[0])
 	}
@@ -158,6 +160,11 @@ func (e *Entry) load() error {
 		e.ApplyAdmissionControl = true
 	case EntryEncodingStandardWithoutAC, EntryEncodingSideloadedWithoutAC:
 		e.ID, raftCmdBytes = DecomposeRaftEncodingStandardOrSideloaded(e.Entry.Data)
+	case EntryEncodingSideloadedPayload:
+		// The command has been moved to sideloaded storage and must be inlined
+		// to be decoded. A thin entry carries no command, and is treated like
+		// an empty one below.
+		e.ID, raftCmdBytes = DecomposeRaftEncodingStandardOrSideloaded(e.Entry.Data)
 	case EntryEncodingEmpty:
 		// Nothing to load, the empty raftpb.Entry is represented by a trivial
 		// Entry.
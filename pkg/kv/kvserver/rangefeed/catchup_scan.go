@@ -70,6 +70,12 @@ type CatchUpIterator struct {
 	startTime hlc.Timestamp // exclusive
 	pacer     *admission.Pacer
 	OnEmit    func(key, endKey roachpb.Key, ts hlc.Timestamp, vh enginepb.MVCCValueHeader)
+	// EstimateProgress, if set, is used to estimate the progress of the
+	// catch-up scan, which is exposed through metrics and logged for long
+	// scans.
+	EstimateProgress CatchUpProgressEstimator
+	// progress, if set, is updated with the estimated progress of the scan.
+	progress *catchUpScanProgress
 }
 
 // NewCatchUpIterator returns a CatchUpIterator for the given Reader over the
@@ -170,6 +176,7 @@ func (i *CatchUpIterator) CatchUpScan(
 	i.SeekGE(storage.MVCCKey{Key: i.span.Key})
 
 	every := log.Every(100 * time.Millisecond)
+	var tracker catchUpScanTracker
 	for {
 		if ok, err := i.Valid(); err != nil {
 			return err
@@ -177,6 +184,8 @@ func (i *CatchUpIterator) CatchUpScan(
 			break
 		}
 
+		tracker.maybeUpdateProgress(ctx, i)
+
 		if err := i.pacer.Pace(ctx); err != nil {
 			// We're unable to pace things automatically -- shout loudly
 			// semi-infrequently but don't fail the rangefeed itself.
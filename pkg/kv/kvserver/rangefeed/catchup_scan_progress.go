@@ -0,0 +1,204 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rangefeed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// catchUpScanProgressInterval is the minimum interval between two estimates
+	// of the progress of a catch-up scan.
+	catchUpScanProgressInterval = time.Second
+	// catchUpScanProgressKeys is the number of iterations of a catch-up scan
+	// between two checks of whether its progress should be estimated again.
+	catchUpScanProgressKeys = 1024
+	// catchUpScanLogThreshold is the duration after which the progress of a
+	// catch-up scan is logged, every catchUpScanLogThreshold.
+	catchUpScanLogThreshold = 10 * time.Second
+	// tagCatchUpScanProgress is the tracing span tag that the
+	// *catchUpScanProgress of the catch-up scan of a rangefeed lives under.
+	tagCatchUpScanProgress = "catchup_scan_progress"
+)
+
+// CatchUpProgressEstimator estimates the fraction, between 0 and 1, of the
+// catch-up scan of a span which is complete when the scan reaches the given
+// key.
+type CatchUpProgressEstimator func(key roachpb.Key) float64
+
+// catchUpScanProgress tracks the estimated progress of a catch-up scan. It
+// is set as a tag of the tracing span of the rangefeed, so that the progress
+// of the catch-up scan of each rangefeed can be inspected, e.g. through
+// crdb_internal.node_inflight_trace_spans.
+type catchUpScanProgress struct {
+	start time.Time
+	// fraction is the estimated fraction of the scan which is complete, stored
+	// as the bits of a float64.
+	fraction atomic.Uint64
+}
+
+func newCatchUpScanProgress(start time.Time) *catchUpScanProgress {
+	return &catchUpScanProgress{start: start}
+}
+
+func (p *catchUpScanProgress) setFraction(f float64) {
+	p.fraction.Store(math.Float64bits(f))
+}
+
+// getFraction returns the estimated fraction of the scan which is complete.
+func (p *catchUpScanProgress) getFraction() float64 {
+	return math.Float64frombits(p.fraction.Load())
+}
+
+// Render implements the tracing.LazyTag interface.
+func (p *catchUpScanProgress) Render() []attribute.KeyValue {
+	now := timeutil.Now()
+	tags := []attribute.KeyValue{
+		{
+			Key:   "elapsed",
+			Value: attribute.StringValue(string(humanizeutil.Duration(now.Sub(p.start)))),
+		},
+		{
+			Key:   "progress",
+			Value: attribute.StringValue(fmt.Sprintf("%.0f%%", p.getFraction()*100)),
+		},
+	}
+	if eta, ok := p.eta(now); ok {
+		tags = append(tags, attribute.KeyValue{
+			Key:   "eta",
+			Value: attribute.StringValue(string(humanizeutil.Duration(eta))),
+		})
+	}
+	return tags
+}
+
+var _ tracing.LazyTag = (*catchUpScanProgress)(nil)
+
+// eta returns the estimated time until the scan completes, extrapolated from
+// its progress so far. It returns false if the progress is unknown.
+func (p *catchUpScanProgress) eta(now time.Time) (time.Duration, bool) {
+	f := p.getFraction()
+	if f <= 0 {
+		return 0, false
+	}
+	elapsed := now.Sub(p.start)
+	return time.Duration(float64(elapsed) * (1 - f) / f), true
+}
+
+// catchUpScanTracker periodically updates the progress of a catch-up scan
+// while it iterates, and logs the progress of long scans.
+type catchUpScanTracker struct {
+	keys       int
+	lastUpdate time.Time
+	lastLog    time.Time
+}
+
+// maybeUpdateProgress is called on every iteration of the catch-up scan of the
+// given iterator. Every catchUpScanProgressKeys iterations, and at most every
+// catchUpScanProgressInterval, it estimates the progress of the scan from the
+// current key of the iterator.
+func (t *catchUpScanTracker) maybeUpdateProgress(ctx context.Context, i *CatchUpIterator) {
+	if i.progress == nil {
+		return
+	}
+	t.keys++
+	if t.keys%catchUpScanProgressKeys != 0 {
+		return
+	}
+	now := timeutil.Now()
+	if now.Sub(t.lastUpdate) < catchUpScanProgressInterval {
+		return
+	}
+	t.lastUpdate = now
+	if i.EstimateProgress != nil {
+		i.progress.setFraction(i.EstimateProgress(i.UnsafeKey().Key))
+	}
+
+	elapsed := now.Sub(i.progress.start)
+	if elapsed < catchUpScanLogThreshold || now.Sub(t.lastLog) < catchUpScanLogThreshold {
+		return
+	}
+	t.lastLog = now
+	if eta, ok := i.progress.eta(now); ok {
+		log.Infof(ctx, "catch-up scan of %s running for %s: %.0f%% complete, ETA %s",
+			i.span, elapsed.Round(time.Second), i.progress.getFraction()*100, eta.Round(time.Second))
+	} else {
+		log.Infof(ctx, "catch-up scan of %s running for %s", i.span, elapsed.Round(time.Second))
+	}
+}
+
+// catchUpScans tracks the catch-up scans in progress, for the purpose of
+// metrics.
+type catchUpScans struct {
+	syncutil.Mutex
+	scans map[*catchUpScanProgress]struct{}
+}
+
+func (s *catchUpScans) add(p *catchUpScanProgress) {
+	s.Lock()
+	defer s.Unlock()
+	if s.scans == nil {
+		s.scans = make(map[*catchUpScanProgress]struct{})
+	}
+	s.scans[p] = struct{}{}
+}
+
+func (s *catchUpScans) remove(p *catchUpScanProgress) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.scans, p)
+}
+
+// count returns the number of catch-up scans in progress.
+func (s *catchUpScans) count() int64 {
+	s.Lock()
+	defer s.Unlock()
+	return int64(len(s.scans))
+}
+
+// minProgressPercent returns the estimated progress, in percent, of the least
+// advanced catch-up scan in progress, or 100 if there is none.
+func (s *catchUpScans) minProgressPercent() int64 {
+	s.Lock()
+	defer s.Unlock()
+	minFraction := 1.0
+	for p := range s.scans {
+		minFraction = min(minFraction, p.getFraction())
+	}
+	return int64(minFraction * 100)
+}
+
+// maxETA returns the longest estimated time until a catch-up scan in progress
+// completes. Scans whose progress is still unknown are ignored.
+func (s *catchUpScans) maxETA() time.Duration {
+	s.Lock()
+	defer s.Unlock()
+	now := timeutil.Now()
+	var maxETA time.Duration
+	for p := range s.scans {
+		if eta, ok := p.eta(now); ok {
+			maxETA = max(maxETA, eta)
+		}
+	}
+	return maxETA
+}
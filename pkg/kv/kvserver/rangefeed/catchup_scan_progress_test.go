@@ -0,0 +1,64 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rangefeed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatchUpScanProgress(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	start := time.Unix(100, 0)
+	p := newCatchUpScanProgress(start)
+	_, ok := p.eta(start.Add(time.Minute))
+	require.False(t, ok)
+
+	// A scan which is a quarter complete after a minute is expected to complete
+	// in three more minutes.
+	p.setFraction(0.25)
+	eta, ok := p.eta(start.Add(time.Minute))
+	require.True(t, ok)
+	require.Equal(t, 3*time.Minute, eta)
+
+	// The progress is rendered as a tag of the rangefeed's tracing span.
+	tags := make(map[string]string)
+	for _, kv := range p.Render() {
+		tags[string(kv.Key)] = kv.Value.Emit()
+	}
+	require.Equal(t, "25%", tags["progress"])
+	require.Contains(t, tags, "elapsed")
+	require.Contains(t, tags, "eta")
+
+	var m Metrics
+	require.Zero(t, m.catchUpScans.count())
+	require.Equal(t, int64(100), m.catchUpScans.minProgressPercent())
+	require.Zero(t, m.catchUpScans.maxETA())
+
+	// A scan whose progress is unknown counts as having made no progress.
+	unknown := newCatchUpScanProgress(start)
+	m.catchUpScans.add(p)
+	m.catchUpScans.add(unknown)
+	require.Equal(t, int64(2), m.catchUpScans.count())
+	require.Equal(t, int64(0), m.catchUpScans.minProgressPercent())
+	require.Greater(t, m.catchUpScans.maxETA(), 3*time.Minute)
+
+	m.catchUpScans.remove(unknown)
+	require.Equal(t, int64(1), m.catchUpScans.count())
+	require.Equal(t, int64(25), m.catchUpScans.minProgressPercent())
+
+	m.catchUpScans.remove(p)
+	require.Zero(t, m.catchUpScans.count())
+}
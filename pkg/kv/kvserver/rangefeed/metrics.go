@@ -25,6 +25,24 @@ var (
 		Measurement: "Nanoseconds",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaRangeFeedCatchUpScansActive = metric.Metadata{
+		Name:        "kv.rangefeed.catchup_scan.active",
+		Help:        "Number of RangeFeed catchup scans in progress",
+		Measurement: "Scans",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRangeFeedCatchUpScanMinProgress = metric.Metadata{
+		Name:        "kv.rangefeed.catchup_scan.min_progress",
+		Help:        "Estimated progress of the least advanced RangeFeed catchup scan in progress, or 100 if there is none",
+		Measurement: "Percent",
+		Unit:        metric.Unit_PERCENT,
+	}
+	metaRangeFeedCatchUpScanMaxETA = metric.Metadata{
+		Name:        "kv.rangefeed.catchup_scan.max_eta",
+		Help:        "Longest estimated time until a RangeFeed catchup scan in progress completes",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 	metaRangeFeedExhausted = metric.Metadata{
 		Name:        "kv.rangefeed.budget_allocation_failed",
 		Help:        "Number of times RangeFeed failed because memory budget was exceeded",
@@ -86,6 +104,12 @@ type Metrics struct {
 	// is removed.
 	RangeFeedProcessorsGO        *metric.Gauge
 	RangeFeedProcessorsScheduler *metric.Gauge
+	// Metrics exposing the progress of the catch-up scans in progress, which
+	// are tracked by catchUpScans.
+	RangeFeedCatchUpScansActive     *metric.Gauge
+	RangeFeedCatchUpScanMinProgress *metric.Gauge
+	RangeFeedCatchUpScanMaxETA      *metric.Gauge
+	catchUpScans                    catchUpScans
 }
 
 // MetricStruct implements the metric.Struct interface.
@@ -93,7 +117,7 @@ func (*Metrics) MetricStruct() {}
 
 // NewMetrics makes the metrics for RangeFeeds monitoring.
 func NewMetrics() *Metrics {
-	return &Metrics{
+	m := &Metrics{
 		RangeFeedCatchUpScanNanos:            metric.NewCounter(metaRangeFeedCatchUpScanNanos),
 		RangeFeedBudgetExhausted:             metric.NewCounter(metaRangeFeedExhausted),
 		RangeFeedBudgetBlocked:               metric.NewCounter(metaRangeFeedBudgetBlocked),
@@ -103,6 +127,13 @@ func NewMetrics() *Metrics {
 		RangeFeedProcessorsGO:                metric.NewGauge(metaRangeFeedProcessorsGO),
 		RangeFeedProcessorsScheduler:         metric.NewGauge(metaRangeFeedProcessorsScheduler),
 	}
+	m.RangeFeedCatchUpScansActive = metric.NewFunctionalGauge(metaRangeFeedCatchUpScansActive,
+		m.catchUpScans.count)
+	m.RangeFeedCatchUpScanMinProgress = metric.NewFunctionalGauge(metaRangeFeedCatchUpScanMinProgress,
+		m.catchUpScans.minProgressPercent)
+	m.RangeFeedCatchUpScanMaxETA = metric.NewFunctionalGauge(metaRangeFeedCatchUpScanMaxETA,
+		func() int64 { return m.catchUpScans.maxETA().Nanoseconds() })
+	return m
 }
 
 // FeedBudgetPoolMetrics holds metrics for RangeFeed budgets for the purpose
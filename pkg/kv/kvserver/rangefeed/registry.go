@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
 )
 
@@ -396,7 +397,15 @@ func (r *registration) maybeRunCatchUpScan(ctx context.Context) error {
 		return nil
 	}
 	start := timeutil.Now()
+	catchUpIter.progress = newCatchUpScanProgress(start)
+	r.metrics.catchUpScans.add(catchUpIter.progress)
+	if sp := tracing.SpanFromContext(ctx); sp != nil {
+		sp.SetLazyTag(tagCatchUpScanProgress, catchUpIter.progress)
+	}
 	defer func() {
+		// The tag of the rangefeed's span outlives the scan.
+		catchUpIter.progress.setFraction(1)
+		r.metrics.catchUpScans.remove(catchUpIter.progress)
 		catchUpIter.Close()
 		r.metrics.RangeFeedCatchUpScanNanos.Inc(timeutil.Since(start).Nanoseconds())
 	}()
@@ -55,6 +55,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -775,6 +776,15 @@ type Replica struct {
 		// live).
 		lastUpdateTimes lastUpdateTimesMap
 
+		// followerCatchUp is maintained on the raft leader to estimate how long
+		// its followers need to catch up with its log. See
+		// followerCatchUpTracker.
+		followerCatchUp followerCatchUpTracker
+
+		// applyLag tracks for how long the committed entries that this replica
+		// hasn't applied yet have been committed. See applyLagTracker.
+		applyLag applyLagTracker
+
 		// Computed checksum at a snapshot UUID.
 		checksums map[uuid.UUID]*replicaChecksum
 
@@ -862,6 +872,21 @@ type Replica struct {
 		// outside the surrounding mutex.
 		pausedFollowers map[roachpb.ReplicaID]struct{}
 
+		// raftEntryCachePinned is set while the range is pinned in the store's
+		// Raft entry cache, because followers are catching up on the log. Only
+		// the leader pins the range, see updateProposalQuotaRaftMuLocked.
+		raftEntryCachePinned bool
+
+		// raftLogPrefetching is set while the log entries needed by followers
+		// catching up on the log are read into the Raft entry cache, see
+		// maybePrefetchRaftLogLocked.
+		raftLogPrefetching bool
+
+		// leaderLeaseholderDivergedSince is the time since which this replica
+		// has been the raft leader while another replica held the lease, or zero
+		// if that isn't the case. See maybeTransferRaftLeadershipToLeaseholderLocked.
+		leaderLeaseholderDivergedSince time.Time
+
 		slowProposalCount int64 // updated in refreshProposalsLocked
 
 		// replicaFlowControlIntegration is used to interface with replication flow
@@ -918,6 +943,10 @@ type Replica struct {
 	// loadBasedSplitter keeps information about load-based splitting.
 	loadBasedSplitter split.Decider
 
+	// hotKey is set when the load-based splitter found the load on the range to
+	// be concentrated on a single key. See updateHotKeyProtection.
+	hotKey atomic.Bool
+
 	// allocatorToken is acquired when planning and executing replica or lease
 	// changes for a range on the leaseholder.
 	allocatorToken *plan.AllocatorToken
@@ -1153,7 +1182,7 @@ func (r *Replica) descRLocked() *roachpb.RangeDescriptor {
 // NOTE: an exported version of this method which does not require the replica
 // lock exists in helpers_test.go. Move here if needed.
 func (r *Replica) closedTimestampPolicyRLocked() roachpb.RangeClosedTimestampPolicy {
-	if r.mu.conf.GlobalReads {
+	if r.mu.conf.GlobalReads || r.hotKeyFollowerReadsRLocked() {
 		if !r.mu.state.Desc.ContainsKey(roachpb.RKey(keys.NodeLivenessPrefix)) {
 			return roachpb.LEAD_FOR_GLOBAL_READS
 		}
@@ -1643,6 +1672,20 @@ func (r *Replica) State(ctx context.Context) kvserverpb.RangeInfo {
 	// it's best to keep it out of the Replica.mu critical section.
 	ri.RangefeedRegistrations = int64(r.numRangefeedRegistrations())
 
+	if d, ok := r.loadBasedSplitter.LastSplitDecision(); ok {
+		ri.LoadSplitDecision = &kvserverpb.LoadSplitDecision{
+			TimeNanos:           d.Time.UnixNano(),
+			SplitKey:            d.SplitKey,
+			Objective:           d.Objective.String(),
+			Threshold:           d.Threshold,
+			LastLoad:            d.Last,
+			MaxLoad:             d.Max,
+			MaxLoadOk:           d.MaxOk,
+			PopularKeyFrequency: d.PopularKeyFrequency,
+			SplitterState:       d.SplitterState,
+		}
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	ri.ReplicaState = *(protoutil.Clone(&r.mu.state)).(*kvserverpb.ReplicaState)
@@ -1651,8 +1694,14 @@ func (r *Replica) State(ctx context.Context) kvserverpb.RangeInfo {
 	ri.RaftLogSize = r.mu.raftLogSize
 	ri.RaftLogSizeTrusted = r.mu.raftLogSizeTrusted
 	ri.NumDropped = uint64(r.mu.droppedMessages)
+	ri.OldestUnappliedEntryAgeNanos = r.mu.applyLag.oldestUnappliedAge(
+		kvpb.RaftIndex(r.raftBasicStatusRLocked().Applied), timeutil.Now(),
+	).Nanoseconds()
 	if r.mu.proposalQuota != nil {
 		ri.ApproximateProposalQuota = int64(r.mu.proposalQuota.ApproximateQuota())
+		ri.ProposalQuotaCapacity = int64(r.mu.proposalQuota.Capacity())
+		ri.ProposalQuotaWaiters = int64(r.mu.proposalQuota.Len())
+		ri.ProposalQuotaLongestWaitNanos = r.mu.proposalQuota.LongestWait().Nanoseconds()
 		ri.ProposalQuotaBaseIndex = int64(r.mu.proposalQuotaBaseIndex)
 		ri.ProposalQuotaReleaseQueue = make([]int64, len(r.mu.quotaReleaseQueue))
 		for i, a := range r.mu.quotaReleaseQueue {
@@ -2350,17 +2399,14 @@ func (r *Replica) maybeWatchForMergeLocked(ctx context.Context) (bool, error) {
 // We like it when leases and raft leadership are collocated because that
 // facilitates quick command application (requests generally need to make it to
 // both the lease holder and the raft leader before being applied by other
-// replicas).
+// replicas). How eagerly leadership is transferred is controlled by
+// LeaderLeaseholderColocation.
 func (r *Replica) maybeTransferRaftLeadershipToLeaseholderLocked(
 	ctx context.Context, status kvserverpb.LeaseStatus,
 ) {
-	if r.store.TestingKnobs().DisableLeaderFollowsLeaseholder {
-		return
-	}
-	if !r.isRaftLeaderRLocked() { // fast path
-		return
-	}
-	if !status.IsValid() || status.OwnedBy(r.StoreID()) {
+	diverged := r.isRaftLeaderRLocked() && status.IsValid() && !status.OwnedBy(r.StoreID())
+	divergedFor := r.recordLeaderLeaseholderDivergenceLocked(diverged)
+	if !diverged || r.store.TestingKnobs().DisableLeaderFollowsLeaseholder {
 		return
 	}
 	raftStatus := r.raftSparseStatusRLocked()
@@ -2369,13 +2415,51 @@ func (r *Replica) maybeTransferRaftLeadershipToLeaseholderLocked(
 	}
 	lhReplicaID := uint64(status.Lease.Replica.ReplicaID)
 	lhProgress, ok := raftStatus.Progress[lhReplicaID]
-	if (ok && lhProgress.Match >= raftStatus.Commit) || r.store.IsDraining() {
+	mode := LeaderLeaseholderColocationMode(LeaderLeaseholderColocation.Get(&r.store.ClusterSettings().SV))
+	if mode.shouldTransfer(lhProgress, ok, raftStatus.Commit, divergedFor) || r.store.IsDraining() {
 		log.VEventf(ctx, 1, "transferring raft leadership to replica ID %v", lhReplicaID)
 		r.store.metrics.RangeRaftLeaderTransfers.Inc(1)
 		r.mu.internalRaftGroup.TransferLeader(lhReplicaID)
 	}
 }
 
+// maybeShedRaftLeadershipForDrain transfers the raft leadership away from this
+// draining replica, to the most up-to-date voter on another store, if the
+// range has no valid lease. When the range has a valid lease, leadership
+// follows the lease instead (see maybeTransferRaftLeadershipToLeaseholderLocked).
+// Returns whether a transfer was attempted.
+func (r *Replica) maybeShedRaftLeadershipForDrain(
+	ctx context.Context, status kvserverpb.LeaseStatus,
+) bool {
+	if status.IsValid() {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	raftStatus := r.raftSparseStatusRLocked()
+	if raftStatus == nil || raftStatus.RaftState != raft.StateLeader {
+		return false
+	}
+	var target, targetMatch uint64
+	for _, repl := range r.mu.state.Desc.Replicas().VoterDescriptors() {
+		if repl.StoreID == r.StoreID() {
+			continue
+		}
+		pr, ok := raftStatus.Progress[uint64(repl.ReplicaID)]
+		if !ok || (target != 0 && pr.Match <= targetMatch) {
+			continue
+		}
+		target, targetMatch = uint64(repl.ReplicaID), pr.Match
+	}
+	if target == 0 {
+		return false
+	}
+	log.VEventf(ctx, 1, "transferring raft leadership to replica ID %v for drain", target)
+	r.store.metrics.RangeRaftLeaderTransfers.Inc(1)
+	r.mu.internalRaftGroup.TransferLeader(target)
+	return true
+}
+
 func (r *Replica) getReplicaDescriptorByIDRLocked(
 	replicaID roachpb.ReplicaID, fallback roachpb.ReplicaDescriptor,
 ) (roachpb.ReplicaDescriptor, error) {
@@ -2485,6 +2569,7 @@ func (r *Replica) MeasureRaftCPUNanos(start time.Duration) {
 func (r *Replica) RangeUsageInfo() allocator.RangeUsageInfo {
 	loadStats := r.LoadStats()
 	localityInfo := r.loadStats.RequestLocalityInfo()
+	tenantID, _ := r.TenantID()
 	return allocator.RangeUsageInfo{
 		LogicalBytes:             r.GetMVCCStats().Total(),
 		QueriesPerSecond:         loadStats.QueriesPerSecond,
@@ -2499,6 +2584,7 @@ func (r *Replica) RangeUsageInfo() allocator.RangeUsageInfo {
 			Counts:   localityInfo.LocalityCounts,
 			Duration: localityInfo.Duration,
 		},
+		TenantID: tenantID,
 	}
 }
 
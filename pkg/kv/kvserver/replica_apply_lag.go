@@ -0,0 +1,75 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+)
+
+// maxApplyLagSamples is the maximum number of commit index samples kept by an
+// applyLagTracker.
+const maxApplyLagSamples = 16
+
+// applyLagTracker tracks when a replica learned that the entries it hasn't
+// applied yet were committed, to determine for how long the oldest of them has
+// been waiting to be applied. A replica that can't keep up with applying its
+// committed entries, e.g. because of slow storage, delays the proposals it
+// acknowledges and the reads it serves.
+type applyLagTracker struct {
+	// samples are the commit indexes observed by the replica while it was
+	// behind on application, in increasing order, along with the time each
+	// was first observed.
+	samples []applyLagSample
+}
+
+type applyLagSample struct {
+	index       kvpb.RaftIndex
+	committedAt time.Time
+}
+
+// record samples the replica's commit and applied indexes. Once the maximum
+// number of samples is reached, the last one is extended to the new commit
+// index, which overestimates the age of the entries committed in the meantime
+// until the replica has applied the entries of the preceding samples.
+func (t *applyLagTracker) record(commit, applied kvpb.RaftIndex, now time.Time) {
+	i := 0
+	for i < len(t.samples) && t.samples[i].index <= applied {
+		i++
+	}
+	t.samples = append(t.samples[:0], t.samples[i:]...)
+	if commit <= applied {
+		return
+	}
+	n := len(t.samples)
+	if n > 0 && t.samples[n-1].index >= commit {
+		return
+	}
+	if n == maxApplyLagSamples {
+		t.samples[n-1].index = commit
+		return
+	}
+	t.samples = append(t.samples, applyLagSample{index: commit, committedAt: now})
+}
+
+// oldestUnappliedAge returns for how long the oldest committed entry above the
+// given applied index has been waiting to be applied, or zero if there is none.
+func (t *applyLagTracker) oldestUnappliedAge(
+	applied kvpb.RaftIndex, now time.Time,
+) time.Duration {
+	for _, s := range t.samples {
+		if s.index > applied {
+			return now.Sub(s.committedAt)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyLagTracker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(secs int) time.Time { return t0.Add(time.Duration(secs) * time.Second) }
+	var tr applyLagTracker
+
+	// A replica that is caught up has no unapplied entries.
+	tr.record(10, 10, at(0))
+	require.Empty(t, tr.samples)
+	require.Zero(t, tr.oldestUnappliedAge(10, at(1)))
+
+	// Entries committed at t=1 and t=2 are unapplied. The age is that of the
+	// oldest of them, and observing the same commit index again doesn't reset
+	// it.
+	tr.record(20, 10, at(1))
+	tr.record(30, 10, at(2))
+	tr.record(30, 10, at(3))
+	require.Equal(t, 3*time.Second, tr.oldestUnappliedAge(10, at(4)))
+
+	// Once the entries committed at t=1 are applied, the age is that of the
+	// entries committed at t=2.
+	tr.record(30, 20, at(5))
+	require.Equal(t, 3*time.Second, tr.oldestUnappliedAge(20, at(5)))
+
+	// Once all entries are applied, the samples are dropped.
+	tr.record(30, 30, at(6))
+	require.Empty(t, tr.samples)
+	require.Zero(t, tr.oldestUnappliedAge(30, at(6)))
+
+	// Beyond the maximum number of samples, the last sample is extended to the
+	// new commit index.
+	for i := 1; i <= maxApplyLagSamples+1; i++ {
+		tr.record(30+kvpb.RaftIndex(i), 30, at(10+i))
+	}
+	require.Len(t, tr.samples, maxApplyLagSamples)
+	last := tr.samples[maxApplyLagSamples-1]
+	require.Equal(t, kvpb.RaftIndex(30+maxApplyLagSamples+1), last.index)
+	require.Equal(t, at(10+maxApplyLagSamples), last.committedAt)
+	require.Equal(t, 10*time.Second, tr.oldestUnappliedAge(30, at(21)))
+}
@@ -117,10 +117,18 @@ func (r *Replica) BumpSideTransportClosed(
 // this range. Note that we might not be able to ultimately close this timestamp
 // if there are requests in flight.
 func (r *Replica) closedTimestampTargetRLocked() hlc.Timestamp {
+	lagTargetDuration := closedts.TargetDuration.Get(&r.ClusterSettings().SV)
+	// The span config may ask for timestamps to be closed more aggressively for
+	// this range. Overrides that are less aggressive than the cluster setting are
+	// ignored, since the side transport would close timestamps at the cluster
+	// setting's target regardless.
+	if d := r.mu.conf.ClosedTimestampTargetDuration; d > 0 && d < lagTargetDuration {
+		lagTargetDuration = d
+	}
 	return closedts.TargetForPolicy(
 		r.Clock().NowAsClockTimestamp(),
 		r.Clock().MaxOffset(),
-		closedts.TargetDuration.Get(&r.ClusterSettings().SV),
+		lagTargetDuration,
 		closedts.LeadForGlobalReadsOverride.Get(&r.ClusterSettings().SV),
 		closedts.SideTransportCloseInterval.Get(&r.ClusterSettings().SV),
 		r.closedTimestampPolicyRLocked(),
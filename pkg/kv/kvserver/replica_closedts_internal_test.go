@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -577,6 +578,39 @@ func TestReplicaClosedTimestamp(t *testing.T) {
 	}
 }
 
+// TestClosedTimestampTargetSpanConfigOverride verifies that the closed
+// timestamp target of a replica honors the ClosedTimestampTargetDuration of its
+// span config only when it is shorter than the cluster setting.
+func TestClosedTimestampTargetSpanConfigOverride(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	tc := testContext{}
+	tc.Start(ctx, t, stopper)
+	closedts.TargetDuration.Override(ctx, &tc.store.ClusterSettings().SV, 3*time.Second)
+
+	// targetLag returns the lag of the closed timestamp target behind the
+	// present time with the given span config. The manual clock doesn't advance
+	// on its own, so the wall time of the clock is the same for all targets.
+	targetLag := func(conf roachpb.SpanConfig) time.Duration {
+		tc.repl.mu.Lock()
+		defer tc.repl.mu.Unlock()
+		tc.repl.mu.conf = conf
+		target := tc.repl.closedTimestampTargetRLocked()
+		return time.Duration(tc.repl.Clock().PhysicalNow() - target.WallTime)
+	}
+
+	require.Equal(t, 3*time.Second, targetLag(roachpb.SpanConfig{}))
+	require.Equal(t, 500*time.Millisecond,
+		targetLag(roachpb.SpanConfig{ClosedTimestampTargetDuration: 500 * time.Millisecond}))
+	// Overrides which are less aggressive than the cluster setting are ignored.
+	require.Equal(t, 3*time.Second,
+		targetLag(roachpb.SpanConfig{ClosedTimestampTargetDuration: 5 * time.Second}))
+}
+
 // TestQueryResolvedTimestamp verifies that QueryResolvedTimestamp requests
 // behave as expected.
 func TestQueryResolvedTimestamp(t *testing.T) {
@@ -81,6 +81,18 @@ var EnableMVCCStatsRecomputationInSplit = settings.RegisterBoolSetting(
 		"to prevent stats estimates from drifting",
 	metamorphic.ConstantWithTestBool("kv.split.mvcc_stats_recomputation.enabled", true))
 
+// BatchedReplicationChangesEnabled controls whether the replication changes
+// made by the replicate queue and AdminChangeReplicas are carried out in a
+// single joint configuration transition when possible, see
+// ChangeReplicasBatched.
+var BatchedReplicationChangesEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.replication_changes.batched.enabled",
+	"if enabled, the changes to the replicas of a range are carried out in a "+
+		"single joint configuration transition when possible, instead of one "+
+		"transition per kind of change",
+	false)
+
 // mergeApplicationTimeout is the timeout when waiting for a merge command to be
 // applied on all range replicas. There doesn't appear to be any strong reason
 // why this value was chosen in particular, but it seems to work.
@@ -1169,6 +1181,13 @@ func (r *Replica) changeReplicasImpl(
 	}
 	targets := SynthesizeTargetsByChangeType(chgs)
 
+	if BatchedReplicationChangesEnabled.Get(&r.store.ClusterSettings().SV) &&
+		validateBatchedReplicationChanges(desc, targets) == nil {
+		return r.changeReplicasBatchedImpl(
+			ctx, desc, senderName, senderQueuePriority, reason, details, targets,
+		)
+	}
+
 	// NB: As of the time of this writing,`AdminRelocateRange` will only execute
 	// replication changes one by one. Thus, the order in which we execute the
 	// changes we've synthesized doesn't matter that much. However, this
@@ -1301,6 +1320,170 @@ func (r *Replica) changeReplicasImpl(
 	return desc, nil
 }
 
+// ChangeReplicasBatched is like ChangeReplicas, but carries out all of the
+// given changes to the voters and non-voters of the range in a single joint
+// configuration transition, instead of in a sequence of transitions. This cuts
+// down on the number of raft round trips (and range descriptor updates) needed
+// by large-scale rebalancing, which otherwise e.g. swaps voters with non-voters,
+// replaces voters, and removes non-voters in separate steps.
+//
+// New voters are first added as learners and new non-voters are added, and
+// both are sent their initial snapshot, one at a time like ChangeReplicas does.
+// All promotions, demotions and removals are then proposed together, through
+// a joint configuration which is left before returning. Since only changes of
+// the voters require joint consensus, the batch must change at least one voter;
+// see validateBatchedReplicationChanges.
+//
+// ChangeReplicas also batches the changes when
+// kv.replication_changes.batched.enabled is set.
+func (r *Replica) ChangeReplicasBatched(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	reason kvserverpb.RangeLogEventReason,
+	details string,
+	chgs kvpb.ReplicationChanges,
+) (updatedDesc *roachpb.RangeDescriptor, _ error) {
+	if desc == nil {
+		return nil, errors.Errorf("%s: the current RangeDescriptor must not be nil", r)
+	}
+	var err error
+	desc, err = r.maybeLeaveAtomicChangeReplicas(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateReplicationChanges(desc, chgs); err != nil {
+		return nil, errors.Mark(err, errMarkInvalidReplicationChange)
+	}
+	targets := SynthesizeTargetsByChangeType(chgs)
+	if err := validateBatchedReplicationChanges(desc, targets); err != nil {
+		return nil, errors.Mark(err, errMarkInvalidReplicationChange)
+	}
+	return r.changeReplicasBatchedImpl(
+		ctx, desc, kvserverpb.SnapshotRequest_OTHER, 0.0, reason, details, targets,
+	)
+}
+
+// changeReplicasBatchedImpl carries out the given replication changes, which
+// have been validated by validateBatchedReplicationChanges, in a single joint
+// configuration transition.
+func (r *Replica) changeReplicasBatchedImpl(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	senderName kvserverpb.SnapshotRequest_QueueName,
+	senderQueuePriority float64,
+	reason kvserverpb.RangeLogEventReason,
+	details string,
+	targets TargetsForReplicationChanges,
+) (updatedDesc *roachpb.RangeDescriptor, _ error) {
+	var err error
+	if adds := targets.VoterAdditions; len(adds) > 0 {
+		desc, err = r.initializeRaftLearners(
+			ctx, desc, senderName, senderQueuePriority, reason, details, adds, roachpb.LEARNER,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// rollbackLearners removes the learners added for the new voters if they
+	// couldn't be promoted.
+	rollbackLearners := func(err error) {
+		if _, err := r.maybeLeaveAtomicChangeReplicas(ctx, r.Desc()); err != nil {
+			log.Warningf(ctx, "could not leave joint config: %v", err)
+		}
+		if fn := r.store.cfg.TestingKnobs.ReplicaAddSkipLearnerRollback; fn != nil && fn() {
+			return
+		}
+		if adds := targets.VoterAdditions; len(adds) > 0 {
+			log.Infof(ctx, "could not promote %v to voter, rolling back: %v", adds, err)
+			for _, target := range adds {
+				r.tryRollbackRaftLearner(ctx, r.Desc(), target, reason, details)
+			}
+		}
+	}
+
+	if adds := targets.NonVoterAdditions; len(adds) > 0 {
+		desc, err = r.initializeRaftLearners(
+			ctx, desc, senderName, senderQueuePriority, reason, details, adds, roachpb.NON_VOTER,
+		)
+		if err != nil {
+			rollbackLearners(err)
+			return nil, err
+		}
+	}
+
+	iChgs := getInternalChangesForBatchedReplicationChanges(desc, targets)
+	desc, err = execChangeReplicasTxn(ctx, r.store.cfg.Tracer(), desc, reason, details, iChgs, changeReplicasTxnArgs{
+		db:                                   r.store.DB(),
+		liveAndDeadReplicas:                  r.store.cfg.StorePool.LiveAndDeadReplicas,
+		logChange:                            r.store.logChange,
+		testForceJointConfig:                 r.store.TestingKnobs().ReplicationAlwaysUseJointConfig,
+		testAllowDangerousReplicationChanges: r.store.TestingKnobs().AllowDangerousReplicationChanges,
+	})
+	if err != nil {
+		rollbackLearners(err)
+		return nil, err
+	}
+
+	// Leave the joint config, and remove the learners that the removed voters
+	// were demoted to.
+	desc, _, err = r.maybeLeaveAtomicChangeReplicasAndRemoveLearners(ctx, desc)
+	return desc, err
+}
+
+// validateBatchedReplicationChanges validates replication changes, which have
+// already been validated by validateReplicationChanges, for
+// ChangeReplicasBatched. It ensures the following:
+// 1. At least one voter is added, removed, promoted or demoted, since the
+// changes can only be carried out together through a joint configuration, and
+// raft only enters one when the voters change.
+// 2. The range is left with at least one voter.
+func validateBatchedReplicationChanges(
+	desc *roachpb.RangeDescriptor, targets TargetsForReplicationChanges,
+) error {
+	addedVoters := len(targets.VoterAdditions) + len(targets.NonVoterPromotions)
+	removedVoters := len(targets.VoterDemotions)
+	for _, target := range targets.VoterRemovals {
+		// Removing a learner doesn't change the voters.
+		if repl, ok := desc.GetReplicaDescriptor(target.StoreID); ok && repl.Type != roachpb.LEARNER {
+			removedVoters++
+		}
+	}
+	if addedVoters+removedVoters == 0 {
+		return errors.AssertionFailedf("batched replication changes must change at least one voter: %+v", targets)
+	}
+	if len(desc.Replicas().VoterDescriptors())+addedVoters-removedVoters <= 0 {
+		return errors.AssertionFailedf("batched replication changes must leave at least one voter: %+v", targets)
+	}
+	return nil
+}
+
+// getInternalChangesForBatchedReplicationChanges returns the changes that make
+// up the joint configuration transition of ChangeReplicasBatched, given the
+// range descriptor after the new voters have been added as learners and the new
+// non-voters have been added.
+func getInternalChangesForBatchedReplicationChanges(
+	desc *roachpb.RangeDescriptor, targets TargetsForReplicationChanges,
+) []internalReplicationChange {
+	iChgs := getInternalChangesForExplicitPromotionsAndDemotions(
+		targets.VoterDemotions, targets.NonVoterPromotions,
+	)
+	for _, target := range targets.VoterAdditions {
+		iChgs = append(iChgs, internalReplicationChange{target: target, typ: internalChangeTypePromoteLearner})
+	}
+	for _, target := range targets.VoterRemovals {
+		typ := internalChangeTypeRemoveLearner
+		if rDesc, ok := desc.GetReplicaDescriptor(target.StoreID); ok && rDesc.Type == roachpb.VOTER_FULL {
+			typ = internalChangeTypeDemoteVoterToLearner
+		}
+		iChgs = append(iChgs, internalReplicationChange{target: target, typ: typ})
+	}
+	for _, target := range targets.NonVoterRemovals {
+		iChgs = append(iChgs, internalReplicationChange{target: target, typ: internalChangeTypeRemoveNonVoter})
+	}
+	return iChgs
+}
+
 // TargetsForReplicationChanges is a grouped representation of a replication
 // change.
 type TargetsForReplicationChanges struct {
@@ -1948,6 +2131,7 @@ func (r *Replica) initializeRaftLearners(
 		)
 	}
 
+	learners := make([]roachpb.ReplicaDescriptor, 0, len(targets))
 	for _, target := range targets {
 		rDesc, ok := desc.GetReplicaDescriptor(target.StoreID)
 		if !ok {
@@ -1957,6 +2141,21 @@ func (r *Replica) initializeRaftLearners(
 		if rDesc.Type != replicaType {
 			return nil, errors.Errorf("programming error: cannot promote replica of type %s", rDesc.Type)
 		}
+		learners = append(learners, rDesc)
+	}
+
+	// When adding several replicas to a locality which doesn't contain a replica
+	// of the range yet, fan the snapshot out through the first replica seeded in
+	// that locality instead of sending every snapshot across the locality
+	// boundary.
+	var fanOut *snapshotFanOut
+	if len(learners) > 1 && r.store.cfg.StorePool != nil &&
+		snapshotFanOutEnabled.Get(&r.ClusterSettings().SV) &&
+		NumDelegateLimit.Get(&r.ClusterSettings().SV) > 0 {
+		fanOut = newSnapshotFanOut(r.store.cfg.StorePool.GetLocalitiesPerReplica, desc, learners)
+	}
+
+	for _, rDesc := range learners {
 
 		if fn := r.store.cfg.TestingKnobs.ReplicaSkipInitialSnapshot; fn != nil && fn() {
 			continue
@@ -1978,9 +2177,18 @@ func (r *Replica) initializeRaftLearners(
 		// orphaned learner. Second, this tickled some bugs in etcd/raft around
 		// switching between StateSnapshot and StateProbe. Even if we worked through
 		// these, it would be susceptible to future similar issues.
-		if err := r.sendSnapshotUsingDelegate(ctx, rDesc, senderName, senderQueuePriority); err != nil {
+		var fanOutSenders []roachpb.ReplicaDescriptor
+		if fanOut != nil {
+			fanOutSenders = fanOut.sendersFor(rDesc)
+		}
+		if err := r.sendSnapshotUsingDelegate(
+			ctx, rDesc, senderName, senderQueuePriority, fanOutSenders,
+		); err != nil {
 			return nil, err
 		}
+		if fanOut != nil {
+			fanOut.markSeeded(rDesc)
+		}
 	}
 	return desc, nil
 }
@@ -2897,11 +3105,17 @@ func (r *Replica) getSenderReplicas(
 // callers of `shouldAcceptSnapshotData` return an error so that we no longer
 // have to worry about racing with a second snapshot. See the comment on
 // ReplicaPlaceholder for details.
+//
+// fanOutSenders, if non-empty, lists replicas which were seeded with a
+// snapshot earlier in the same replication change and are closer to the
+// recipient than any other replica of the range. They are attempted as
+// delegates before the senders picked by getSenderReplicas.
 func (r *Replica) sendSnapshotUsingDelegate(
 	ctx context.Context,
 	recipient roachpb.ReplicaDescriptor,
 	senderQueueName kvserverpb.SnapshotRequest_QueueName,
 	senderQueuePriority float64,
+	fanOutSenders []roachpb.ReplicaDescriptor,
 ) (retErr error) {
 
 	defer func() {
@@ -2975,8 +3189,16 @@ func (r *Replica) sendSnapshotUsingDelegate(
 	if len(senders) == 0 {
 		return errors.Errorf("no sender found to send a snapshot from for %v", r)
 	}
+	if len(fanOutSenders) > 0 {
+		if limit := int(NumDelegateLimit.Get(&r.ClusterSettings().SV)); len(fanOutSenders) > limit {
+			fanOutSenders = fanOutSenders[:limit]
+		}
+		senders = append(append([]roachpb.ReplicaDescriptor(nil), fanOutSenders...), senders...)
+	}
 
 	for n, sender := range senders {
+		fanOutDelegate := n < len(fanOutSenders)
+		delegateRequest.FanOut = fanOutDelegate
 		delegateRequest.DelegatedSender = sender
 		log.VEventf(
 			ctx, 2, "delegating snapshot transmission attempt %v for %v to %v", n+1, recipient, sender,
@@ -3023,6 +3245,9 @@ func (r *Replica) sendSnapshotUsingDelegate(
 			if !selfDelegate {
 				r.store.Metrics().DelegateSnapshotSuccesses.Inc(1)
 			}
+			if fanOutDelegate {
+				r.store.Metrics().DelegateSnapshotFanOutSuccesses.Inc(1)
+			}
 			return
 		} else {
 			if !selfDelegate {
@@ -3312,6 +3537,7 @@ func (r *Replica) followerSendSnapshot(
 		SharedReplicate:     sharedReplicate,
 		ExternalReplicate:   externalReplicate,
 	}
+	setSnapshotCompression(ctx, r.store.ClusterSettings(), &header)
 	newBatchFn := func() storage.WriteBatch {
 		return r.store.TODOEngine().NewWriteBatch()
 	}
@@ -3325,6 +3551,11 @@ func (r *Replica) followerSendSnapshot(
 		// Only counts for delegated bytes if we are not self-delegating.
 		if r.NodeID() != req.CoordinatorReplica.NodeID {
 			r.store.metrics.DelegateSnapshotSendBytes.Inc(inc)
+			if req.FanOut {
+				r.store.metrics.DelegateSnapshotFanOutSendBytes.Inc(inc)
+			}
+		} else {
+			r.store.metrics.DirectSnapshotSendBytes.Inc(inc)
 		}
 		r.store.metrics.RangeSnapshotSentBytes.Inc(inc)
 		r.store.metrics.updateCrossLocalityMetricsOnSnapshotSent(comparisonResult, inc)
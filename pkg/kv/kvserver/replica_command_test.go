@@ -430,6 +430,84 @@ func TestValidateReplicationChanges(t *testing.T) {
 	}
 }
 
+func TestBatchedReplicationChanges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	desc := &roachpb.RangeDescriptor{
+		InternalReplicas: []roachpb.ReplicaDescriptor{
+			{NodeID: 1, StoreID: 1, ReplicaID: 1},
+			{NodeID: 2, StoreID: 2, ReplicaID: 2},
+			{NodeID: 3, StoreID: 3, ReplicaID: 3},
+			{NodeID: 4, StoreID: 4, ReplicaID: 4, Type: roachpb.NON_VOTER},
+			{NodeID: 5, StoreID: 5, ReplicaID: 5, Type: roachpb.NON_VOTER},
+		},
+		NextReplicaID: 6,
+	}
+	target := func(id int) roachpb.ReplicationTarget {
+		return roachpb.ReplicationTarget{NodeID: roachpb.NodeID(id), StoreID: roachpb.StoreID(id)}
+	}
+
+	// Changes that don't affect the voters can't be batched, and the range
+	// can't be left without voters.
+	for _, tc := range []struct {
+		chgs kvpb.ReplicationChanges
+		err  string
+	}{{
+		chgs: kvpb.ReplicationChanges{
+			{ChangeType: roachpb.ADD_NON_VOTER, Target: target(6)},
+			{ChangeType: roachpb.REMOVE_NON_VOTER, Target: target(5)},
+		},
+		err: "must change at least one voter",
+	}, {
+		chgs: kvpb.ReplicationChanges{
+			{ChangeType: roachpb.REMOVE_VOTER, Target: target(1)},
+			{ChangeType: roachpb.REMOVE_VOTER, Target: target(2)},
+			{ChangeType: roachpb.REMOVE_VOTER, Target: target(3)},
+		},
+		err: "must leave at least one voter",
+	}} {
+		require.NoError(t, validateReplicationChanges(desc, tc.chgs))
+		err := validateBatchedReplicationChanges(desc, SynthesizeTargetsByChangeType(tc.chgs))
+		require.ErrorContains(t, err, tc.err)
+	}
+
+	// Swap s1 and s4, replace s2 with s6, and remove s5, all at once.
+	chgs := kvpb.ReplicationChanges{
+		{ChangeType: roachpb.ADD_VOTER, Target: target(4)},
+		{ChangeType: roachpb.REMOVE_NON_VOTER, Target: target(4)},
+		{ChangeType: roachpb.ADD_NON_VOTER, Target: target(1)},
+		{ChangeType: roachpb.REMOVE_VOTER, Target: target(1)},
+		{ChangeType: roachpb.ADD_VOTER, Target: target(6)},
+		{ChangeType: roachpb.REMOVE_VOTER, Target: target(2)},
+		{ChangeType: roachpb.REMOVE_NON_VOTER, Target: target(5)},
+	}
+	require.NoError(t, validateReplicationChanges(desc, chgs))
+	targets := SynthesizeTargetsByChangeType(chgs)
+	require.NoError(t, validateBatchedReplicationChanges(desc, targets))
+
+	// The new voter is added as a learner first, and everything else happens
+	// in a single transition into a joint configuration.
+	desc.AddReplica(6, 6, roachpb.LEARNER)
+	iChgs := getInternalChangesForBatchedReplicationChanges(desc, targets)
+	require.Len(t, iChgs, 5)
+	crt, err := prepareChangeReplicasTrigger(ctx, desc, iChgs, nil /* testingForceJointConfig */)
+	require.NoError(t, err)
+	typs := map[roachpb.StoreID]roachpb.ReplicaType{}
+	for _, repl := range crt.Desc.Replicas().Descriptors() {
+		typs[repl.StoreID] = repl.Type
+	}
+	require.Equal(t, map[roachpb.StoreID]roachpb.ReplicaType{
+		1: roachpb.VOTER_DEMOTING_NON_VOTER,
+		2: roachpb.VOTER_DEMOTING_LEARNER,
+		3: roachpb.VOTER_FULL,
+		4: roachpb.VOTER_INCOMING,
+		6: roachpb.VOTER_INCOMING,
+	}, typs)
+	require.True(t, crt.Desc.Replicas().InAtomicReplicationChange())
+}
+
 func TestSynthesizeTargetsByChangeType(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	type testCase struct {
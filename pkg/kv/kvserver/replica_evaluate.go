@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -236,12 +237,20 @@ func evaluateBatch(
 	// retries.
 	var deferredWriteTooOldErr *kvpb.WriteTooOldError
 
-	// Only collect the scan stats if the tracing is enabled.
+	// Only collect the scan stats if the tracing is enabled, or if the bytes
+	// read from disk need to be reported to admission control, or the block
+	// cache usage needs to be reported to the tenant rate limiter.
 	var ss *kvpb.ScanStats
-	if sp := tracing.SpanFromContext(ctx); sp.RecordingType() != tracingpb.RecordingOff {
+	sp := tracing.SpanFromContext(ctx)
+	recording := sp.RecordingType() != tracingpb.RecordingOff
+	diskReadHandle := admission.DiskReadHandleFromContext(ctx)
+	blockCacheUsage := blockCacheUsageFromContext(ctx)
+	if recording || diskReadHandle != nil || blockCacheUsage != nil {
 		ss = &kvpb.ScanStats{}
 		defer func() {
-			if ss.NumGets != 0 || ss.NumScans != 0 || ss.NumReverseScans != 0 {
+			diskReadHandle.RecordBytesRead(int64(ss.BlockBytes - ss.BlockBytesInCache))
+			blockCacheUsage.record(ss)
+			if recording && (ss.NumGets != 0 || ss.NumScans != 0 || ss.NumReverseScans != 0) {
 				// Only record non-empty ScanStats.
 				sp.RecordStructured(ss)
 			}
@@ -0,0 +1,176 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/raft/tracker"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// catchUpSampleInterval is the minimum time between two samples of a
+// follower's match index used to estimate the rate at which it appends the
+// entries of the leader's log.
+const catchUpSampleInterval = time.Second
+
+// catchUpRateSmoothing is the weight given to the latest sample in the
+// exponentially weighted moving average of a follower's append rate.
+const catchUpRateSmoothing = 0.5
+
+// CatchUpEstimate estimates how long a follower, typically a learner, needs to
+// catch up with the raft leader's log.
+type CatchUpEstimate struct {
+	// RemainingEntries is the number of entries in the leader's log that the
+	// follower hasn't acknowledged yet.
+	RemainingEntries uint64
+	// RemainingBytes approximates the size of these entries, based on the
+	// average size of the entries in the leader's log.
+	RemainingBytes int64
+	// EntriesPerSecond is the rate at which the follower has recently been
+	// acknowledging entries.
+	EntriesPerSecond float64
+	// ETA is how long the follower needs to acknowledge the remaining entries at
+	// that rate. It is zero if the follower is caught up, and math.MaxInt64 if
+	// it is behind but not making progress.
+	ETA time.Duration
+}
+
+// followerCatchUpTracker is maintained on the raft leader to estimate how long
+// each follower needs to catch up with the leader's log. It samples the
+// followers' match indexes as the leader processes raft ready, and tracks the
+// rate at which they advance.
+type followerCatchUpTracker map[roachpb.ReplicaID]*followerCatchUpSample
+
+type followerCatchUpSample struct {
+	// match is the follower's match index at the time of the last sample.
+	match     kvpb.RaftIndex
+	sampledAt time.Time
+	// rate is the smoothed rate, in entries per second, at which the follower's
+	// match index advanced. It is only valid if rated is set, i.e. once two
+	// samples have been taken.
+	rate  float64
+	rated bool
+}
+
+// record samples the given follower's match index.
+func (t followerCatchUpTracker) record(
+	replicaID roachpb.ReplicaID, match kvpb.RaftIndex, now time.Time,
+) {
+	if t == nil {
+		return
+	}
+	s, ok := t[replicaID]
+	if !ok {
+		t[replicaID] = &followerCatchUpSample{match: match, sampledAt: now}
+		return
+	}
+	elapsed := now.Sub(s.sampledAt)
+	if elapsed < catchUpSampleInterval {
+		return
+	}
+	var rate float64
+	// NB: the match index of a follower can regress, for instance when it
+	// restarts without having synced its log. Don't count that as progress.
+	if match > s.match {
+		rate = float64(match-s.match) / elapsed.Seconds()
+	}
+	if s.rated {
+		rate = catchUpRateSmoothing*rate + (1-catchUpRateSmoothing)*s.rate
+	}
+	*s = followerCatchUpSample{match: match, sampledAt: now, rate: rate, rated: true}
+}
+
+// estimate returns the catch-up estimate for the given follower, whose
+// current match index is match, given the leader's last index and the average
+// size of the entries in its log. It returns false if the follower is behind
+// but hasn't been sampled for long enough to estimate its rate.
+func (t followerCatchUpTracker) estimate(
+	replicaID roachpb.ReplicaID, match, lastIndex kvpb.RaftIndex, avgEntryBytes int64,
+) (CatchUpEstimate, bool) {
+	var e CatchUpEstimate
+	if lastIndex <= match {
+		return e, true
+	}
+	e.RemainingEntries = uint64(lastIndex - match)
+	e.RemainingBytes = int64(e.RemainingEntries) * avgEntryBytes
+	s, ok := t[replicaID]
+	if !ok || !s.rated {
+		return e, false
+	}
+	e.EntriesPerSecond = s.rate
+	e.ETA = math.MaxInt64
+	if s.rate > 0 {
+		if eta := float64(e.RemainingEntries) / s.rate * float64(time.Second); eta < math.MaxInt64 {
+			e.ETA = time.Duration(eta)
+		}
+	}
+	return e, true
+}
+
+// reset drops the samples of all the followers. It is called when the range
+// quiesces: the followers' match indexes don't advance while it is quiesced, so
+// neither the last samples nor the rates computed from them say anything about
+// how fast the followers catch up once it unquiesces.
+func (t followerCatchUpTracker) reset() {
+	for replicaID := range t {
+		delete(t, replicaID)
+	}
+}
+
+// retain drops the samples of the replicas that are no longer part of the
+// range.
+func (t followerCatchUpTracker) retain(desc *roachpb.RangeDescriptor) {
+	for replicaID := range t {
+		if _, ok := desc.GetReplicaDescriptorByID(replicaID); !ok {
+			delete(t, replicaID)
+		}
+	}
+}
+
+// FollowerCatchUpEstimates returns estimates of how long the followers of this
+// replica need to catch up with its raft log, keyed by replica ID. Followers
+// that are waiting for a snapshot or that have no estimate yet are omitted. It
+// returns nil if this replica isn't the raft leader.
+func (r *Replica) FollowerCatchUpEstimates() map[roachpb.ReplicaID]CatchUpEstimate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	status := r.raftSparseStatusRLocked()
+	if r.mu.followerCatchUp == nil || status == nil || len(status.Progress) == 0 {
+		return nil
+	}
+	lastIndex := r.mu.lastIndexNotDurable
+	var avgEntryBytes int64
+	if n := int64(lastIndex) - int64(r.raftFirstIndexRLocked()) + 1; n > 0 {
+		avgEntryBytes = r.mu.raftLogSize / n
+	}
+	estimates := make(map[roachpb.ReplicaID]CatchUpEstimate, len(status.Progress))
+	for id, pr := range status.Progress {
+		if pr.State == tracker.StateSnapshot {
+			continue
+		}
+		replicaID := roachpb.ReplicaID(id)
+		if e, ok := r.mu.followerCatchUp.estimate(
+			replicaID, kvpb.RaftIndex(pr.Match), lastIndex, avgEntryBytes,
+		); ok {
+			estimates[replicaID] = e
+		}
+	}
+	return estimates
+}
+
+// FollowerCatchUpETA implements the plan.AllocatorReplica interface.
+func (r *Replica) FollowerCatchUpETA(replicaID roachpb.ReplicaID) (time.Duration, bool) {
+	e, ok := r.FollowerCatchUpEstimates()[replicaID]
+	return e.ETA, ok
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowerCatchUpTracker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const avgEntryBytes = 100
+	tr := make(followerCatchUpTracker)
+
+	// A follower that is caught up needs no time to catch up, even without a
+	// rate.
+	e, ok := tr.estimate(1, 100, 100, avgEntryBytes)
+	require.True(t, ok)
+	require.Equal(t, CatchUpEstimate{}, e)
+
+	// A follower that is behind needs two samples to be rated.
+	tr.record(2, 0, t0)
+	e, ok = tr.estimate(2, 0, 1000, avgEntryBytes)
+	require.False(t, ok)
+	require.Equal(t, uint64(1000), e.RemainingEntries)
+	require.Equal(t, int64(100000), e.RemainingBytes)
+
+	// Samples taken before the sample interval elapsed are ignored.
+	tr.record(2, 50, t0.Add(catchUpSampleInterval/2))
+	_, ok = tr.estimate(2, 50, 1000, avgEntryBytes)
+	require.False(t, ok)
+
+	// The follower appends 100 entries per second, so it needs 8s to append
+	// the remaining 800 entries.
+	tr.record(2, 200, t0.Add(2*time.Second))
+	e, ok = tr.estimate(2, 200, 1000, avgEntryBytes)
+	require.True(t, ok)
+	require.Equal(t, CatchUpEstimate{
+		RemainingEntries: 800,
+		RemainingBytes:   80000,
+		EntriesPerSecond: 100,
+		ETA:              8 * time.Second,
+	}, e)
+
+	// The rate is smoothed: after a second without progress, the follower is
+	// estimated to append 50 entries per second.
+	tr.record(2, 200, t0.Add(3*time.Second))
+	e, ok = tr.estimate(2, 200, 1000, avgEntryBytes)
+	require.True(t, ok)
+	require.Equal(t, float64(50), e.EntriesPerSecond)
+	require.Equal(t, 16*time.Second, e.ETA)
+
+	// A follower that stopped making progress altogether doesn't catch up.
+	for i := 4; i < 100; i++ {
+		tr.record(2, 200, t0.Add(time.Duration(i)*time.Second))
+	}
+	e, ok = tr.estimate(2, 200, 1000, avgEntryBytes)
+	require.True(t, ok)
+	require.Equal(t, time.Duration(math.MaxInt64), e.ETA)
+
+	// Quiescing the range drops the samples, so that the follower isn't rated
+	// by its progress from before the range quiesced. After it unquiesces, it
+	// needs two samples again.
+	tr.reset()
+	_, ok = tr.estimate(2, 200, 1000, avgEntryBytes)
+	require.False(t, ok)
+	tr.record(2, 200, t0.Add(time.Hour))
+	tr.record(2, 400, t0.Add(time.Hour+2*time.Second))
+	e, ok = tr.estimate(2, 400, 1000, avgEntryBytes)
+	require.True(t, ok)
+	require.Equal(t, float64(100), e.EntriesPerSecond)
+	require.Equal(t, 6*time.Second, e.ETA)
+
+	// Replicas that are removed from the range are no longer tracked.
+	tr.retain(&roachpb.RangeDescriptor{InternalReplicas: []roachpb.ReplicaDescriptor{{ReplicaID: 1}}})
+	require.NotContains(t, tr, roachpb.ReplicaID(2))
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+// A range has a hot key when the load-based splitter finds that its load is
+// concentrated on a single key, so that splitting the range cannot spread the
+// load. Such ranges are protected by serving their reads from more replicas:
+// they close timestamps in the future, which lets every replica serve
+// present-time reads (as with the global_reads zone config), and they can be
+// given additional non-voting replicas. Both responses are opt-in, as they
+// trade write latency and resources for read throughput.
+
+// HotKeyFollowerReadsEnabled controls whether ranges with a hot key close
+// timestamps in the future, to let present-time reads be served by followers.
+var HotKeyFollowerReadsEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.hot_key_protection.follower_reads.enabled",
+	"if enabled, ranges whose load is concentrated on a single key close timestamps "+
+		"in the future, as with global reads, so that present-time reads can be "+
+		"served by any replica at the cost of higher write latency",
+	false,
+)
+
+// HotKeyExtraNonVoters is the number of non-voting replicas added to ranges
+// with a hot key, in addition to those of their span config.
+var HotKeyExtraNonVoters = settings.RegisterIntSetting(
+	settings.SystemOnly,
+	"kv.hot_key_protection.extra_non_voters",
+	"the number of non-voting replicas added to ranges whose load is concentrated "+
+		"on a single key, to serve follower reads",
+	0,
+	settings.NonNegativeIntWithMaximum(5),
+)
+
+// updateHotKeyProtection checks with the load-based splitter whether the range
+// has a hot key, and updates the protection of the range accordingly. It
+// returns whether the range has a hot key. It is called periodically, when the
+// store computes its metrics.
+func (r *Replica) updateHotKeyProtection(ctx context.Context) bool {
+	hot := r.loadBasedSplitter.HotKeyDetected(r.Clock().PhysicalTime())
+	if r.hotKey.Swap(hot) == hot {
+		return hot
+	}
+	desc := r.Desc()
+	if hot {
+		r.store.metrics.HotKeyDetected.Inc(1)
+		log.KvDistribution.Infof(ctx, "load concentrated on a single key of %s, protecting hot range", desc)
+		log.StructuredEvent(ctx, &eventpb.HotKeyDetected{
+			NodeID:   int32(r.store.NodeID()),
+			StoreID:  int32(r.store.StoreID()),
+			RangeID:  int64(desc.RangeID),
+			StartKey: desc.StartKey.String(),
+		})
+	} else {
+		log.KvDistribution.Infof(ctx, "load no longer concentrated on a single key of %s", desc)
+	}
+	// Let the replicate queue add or remove the extra non-voters.
+	if HotKeyExtraNonVoters.Get(&r.store.ClusterSettings().SV) > 0 {
+		r.store.replicateQueue.MaybeAddAsync(ctx, r, r.store.Clock().NowAsClockTimestamp())
+	}
+	return hot
+}
+
+// hotKeyProtectionApplies returns whether the range has a hot key and is
+// eligible for protection. Only ranges of user data are protected: system
+// ranges can't tolerate closing timestamps in the future.
+func (r *Replica) hotKeyProtectionApplies(desc *roachpb.RangeDescriptor) bool {
+	return r.hotKey.Load() && !desc.StartKey.Less(roachpb.RKey(keys.UserTableDataMin))
+}
+
+// hotKeyFollowerReadsRLocked returns whether the range should close timestamps
+// in the future because it has a hot key.
+func (r *Replica) hotKeyFollowerReadsRLocked() bool {
+	return HotKeyFollowerReadsEnabled.Get(&r.store.ClusterSettings().SV) &&
+		r.hotKeyProtectionApplies(r.mu.state.Desc)
+}
+
+// maybeAddHotKeyNonVoters adds the extra non-voters of ranges with a hot key
+// to the given span config of the range.
+func (r *Replica) maybeAddHotKeyNonVoters(conf *roachpb.SpanConfig) {
+	extra := int32(HotKeyExtraNonVoters.Get(&r.store.ClusterSettings().SV))
+	if extra == 0 || !r.hotKeyProtectionApplies(r.Desc()) {
+		return
+	}
+	if conf.NumVoters == 0 {
+		conf.NumVoters = conf.NumReplicas
+	}
+	conf.NumReplicas += extra
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/raft/tracker"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+)
+
+// LeaderLeaseholderColocation controls how aggressively a raft leader which
+// isn't the range's leaseholder transfers raft leadership to the leaseholder.
+// Proposals are evaluated on the leaseholder, and have to be forwarded to the
+// raft leader to be replicated, so proposal latency is lowest when both are
+// colocated.
+var LeaderLeaseholderColocation = settings.RegisterEnumSetting(
+	settings.SystemOnly,
+	"kv.raft.leader_leaseholder_colocation.mode",
+	"how aggressively raft leadership is transferred to the leaseholder when they "+
+		"diverge: off never transfers it, conservative transfers it once the "+
+		"leaseholder is caught up on the log and they have diverged for a while, "+
+		"moderate transfers it once the leaseholder is caught up on the log, and "+
+		"aggressive transfers it as soon as the leaseholder is being replicated to",
+	"moderate",
+	map[int64]string{
+		int64(LeaderLeaseholderColocationOff):          "off",
+		int64(LeaderLeaseholderColocationConservative): "conservative",
+		int64(LeaderLeaseholderColocationModerate):     "moderate",
+		int64(LeaderLeaseholderColocationAggressive):   "aggressive",
+	},
+)
+
+// LeaderLeaseholderColocationMode determines when a raft leader which isn't the
+// range's leaseholder transfers raft leadership to the leaseholder.
+type LeaderLeaseholderColocationMode int64
+
+const (
+	// LeaderLeaseholderColocationOff means that raft leadership is not
+	// transferred to the leaseholder, unless the leader's store is draining.
+	LeaderLeaseholderColocationOff LeaderLeaseholderColocationMode = iota
+	// LeaderLeaseholderColocationConservative means that raft leadership is
+	// transferred to the leaseholder once it is caught up on the log, and the
+	// leader and leaseholder have diverged for at least
+	// conservativeColocationMinDivergence.
+	LeaderLeaseholderColocationConservative
+	// LeaderLeaseholderColocationModerate means that raft leadership is
+	// transferred to the leaseholder once it is caught up on the log.
+	LeaderLeaseholderColocationModerate
+	// LeaderLeaseholderColocationAggressive means that raft leadership is
+	// transferred to the leaseholder as soon as it is being replicated to,
+	// relying on raft to catch it up on the log before handing over
+	// leadership.
+	LeaderLeaseholderColocationAggressive
+)
+
+// conservativeColocationMinDivergence is the minimum duration for which the
+// raft leader and leaseholder have to diverge before raft leadership is
+// transferred to the leaseholder under LeaderLeaseholderColocationConservative.
+// It avoids transferring raft leadership for leases that are short-lived, e.g.
+// while leases are rebalanced.
+const conservativeColocationMinDivergence = 10 * time.Second
+
+// shouldTransfer returns whether the raft leader should transfer leadership to
+// the leaseholder, given the leaseholder's progress (if known), the leader's
+// commit index and the duration for which the leader and leaseholder have
+// diverged.
+func (m LeaderLeaseholderColocationMode) shouldTransfer(
+	lhProgress tracker.Progress, lhProgressOK bool, commit uint64, diverged time.Duration,
+) bool {
+	if !lhProgressOK {
+		return false
+	}
+	caughtUp := lhProgress.Match >= commit
+	switch m {
+	case LeaderLeaseholderColocationConservative:
+		return caughtUp && diverged >= conservativeColocationMinDivergence
+	case LeaderLeaseholderColocationModerate:
+		return caughtUp
+	case LeaderLeaseholderColocationAggressive:
+		return caughtUp || lhProgress.State == tracker.StateReplicate
+	default:
+		return false
+	}
+}
+
+// recordLeaderLeaseholderDivergenceLocked tracks whether this replica is the
+// raft leader while another replica holds the lease, and returns the duration
+// for which they have diverged. Once they no longer diverge, the duration of
+// the divergence is recorded in the store's metrics.
+func (r *Replica) recordLeaderLeaseholderDivergenceLocked(diverged bool) time.Duration {
+	since := r.mu.leaderLeaseholderDivergedSince
+	if !diverged {
+		if !since.IsZero() {
+			r.store.metrics.RaftLeaderDivergence.RecordValue(
+				r.Clock().PhysicalTime().Sub(since).Nanoseconds())
+			r.mu.leaderLeaseholderDivergedSince = time.Time{}
+		}
+		return 0
+	}
+	now := r.Clock().PhysicalTime()
+	if since.IsZero() {
+		r.mu.leaderLeaseholderDivergedSince = now
+		return 0
+	}
+	return now.Sub(since)
+}
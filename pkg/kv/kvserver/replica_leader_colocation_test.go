@@ -0,0 +1,62 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/raft/tracker"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderLeaseholderColocationModeShouldTransfer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const commit = 10
+	caughtUp := tracker.Progress{Match: commit, State: tracker.StateReplicate}
+	behind := tracker.Progress{Match: commit - 5, State: tracker.StateReplicate}
+	probing := tracker.Progress{Match: commit - 5, State: tracker.StateProbe}
+	const brief, long = time.Second, time.Minute
+
+	for _, tc := range []struct {
+		mode       LeaderLeaseholderColocationMode
+		progress   tracker.Progress
+		progressOK bool
+		diverged   time.Duration
+		exp        bool
+	}{
+		{LeaderLeaseholderColocationOff, caughtUp, true, long, false},
+
+		{LeaderLeaseholderColocationConservative, caughtUp, true, brief, false},
+		{LeaderLeaseholderColocationConservative, caughtUp, true, long, true},
+		{LeaderLeaseholderColocationConservative, behind, true, long, false},
+
+		{LeaderLeaseholderColocationModerate, caughtUp, true, brief, true},
+		{LeaderLeaseholderColocationModerate, behind, true, long, false},
+		{LeaderLeaseholderColocationModerate, caughtUp, false, long, false},
+
+		{LeaderLeaseholderColocationAggressive, caughtUp, true, brief, true},
+		{LeaderLeaseholderColocationAggressive, behind, true, brief, true},
+		{LeaderLeaseholderColocationAggressive, probing, true, long, false},
+		{LeaderLeaseholderColocationAggressive, caughtUp, false, long, false},
+	} {
+		name := fmt.Sprintf("mode=%d,match=%d,state=%s,ok=%t,diverged=%s",
+			tc.mode, tc.progress.Match, tc.progress.State, tc.progressOK, tc.diverged)
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.exp, tc.mode.shouldTransfer(tc.progress, tc.progressOK, commit, tc.diverged))
+		})
+	}
+}
@@ -13,9 +13,11 @@ package kvserver
 import (
 	"context"
 	"math"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocatorimpl"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
@@ -24,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/raft"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
 // ReplicaMetrics contains details on the current status of the replica.
@@ -57,6 +60,18 @@ type ReplicaMetrics struct {
 	SlowRaftProposalCount    int64
 
 	QuotaPoolPercentUsed int64 // [0,100]
+	// QuotaPoolWaiters is the number of proposals waiting for proposal quota.
+	QuotaPoolWaiters int64
+	// QuotaPoolLongestWaitNanos is how long the oldest of these proposals has
+	// been waiting.
+	QuotaPoolLongestWaitNanos int64
+
+	// ApplyLagEntries is the number of committed entries that the replica
+	// hasn't applied yet.
+	ApplyLagEntries int64
+	// OldestUnappliedEntryAgeNanos is how long the oldest of these entries has
+	// been waiting to be applied.
+	OldestUnappliedEntryAgeNanos int64
 
 	// Latching and locking metrics.
 	LatchMetrics     concurrency.LatchMetrics
@@ -83,12 +98,18 @@ func (r *Replica) Metrics(
 
 	r.mu.RLock()
 
-	var qpUsed, qpCap int64
+	var qpUsed, qpCap, qpWaiters int64
+	var qpLongestWait time.Duration
 	if q := r.mu.proposalQuota; q != nil {
 		qpAvail := int64(q.ApproximateQuota())
 		qpCap = int64(q.Capacity()) // NB: max capacity is MaxInt64, see NewIntPool
 		qpUsed = qpCap - qpAvail
+		qpWaiters = int64(q.Len())
+		qpLongestWait = q.LongestWait()
 	}
+	oldestUnappliedAge := r.mu.applyLag.oldestUnappliedAge(
+		kvpb.RaftIndex(r.raftBasicStatusRLocked().Applied), timeutil.Now(),
+	)
 
 	input := calcReplicaMetricsInput{
 		raftCfg:                  &r.store.cfg.RaftConfig,
@@ -110,6 +131,9 @@ func (r *Replica) Metrics(
 		raftLogSizeTrusted:       r.mu.raftLogSizeTrusted,
 		qpUsed:                   qpUsed,
 		qpCapacity:               qpCap,
+		qpWaiters:                qpWaiters,
+		qpLongestWait:            qpLongestWait,
+		oldestUnappliedAge:       oldestUnappliedAge,
 		paused:                   r.mu.pausedFollowers,
 		pendingRaftProposalCount: r.numPendingProposalsRLocked(),
 		slowRaftProposalCount:    r.mu.slowProposalCount,
@@ -138,6 +162,9 @@ type calcReplicaMetricsInput struct {
 	raftLogSize              int64
 	raftLogSizeTrusted       bool
 	qpUsed, qpCapacity       int64 // quota pool used and capacity bytes
+	qpWaiters                int64
+	qpLongestWait            time.Duration
+	oldestUnappliedAge       time.Duration
 	paused                   map[roachpb.ReplicaID]struct{}
 	pendingRaftProposalCount int64
 	slowRaftProposalCount    int64
@@ -175,6 +202,13 @@ func calcReplicaMetrics(d calcReplicaMetricsInput) ReplicaMetrics {
 		leaderPausedFollowerCount = int64(len(d.paused))
 	}
 
+	// Every replica computes the number of committed entries it hasn't applied
+	// yet.
+	var applyLagEntries int64
+	if d.raftStatus != nil && d.raftStatus.Commit > d.raftStatus.Applied {
+		applyLagEntries = int64(d.raftStatus.Commit - d.raftStatus.Applied)
+	}
+
 	const raftLogTooLargeMultiple = 4
 	return ReplicaMetrics{
 		Leader:                    leader,
@@ -193,13 +227,17 @@ func calcReplicaMetrics(d calcReplicaMetricsInput) ReplicaMetrics {
 		Overreplicated:            overreplicated,
 		RaftLogTooLarge: d.raftLogSizeTrusted &&
 			d.raftLogSize > raftLogTooLargeMultiple*d.raftCfg.RaftLogTruncationThreshold,
-		BehindCount:              leaderBehindCount,
-		PausedFollowerCount:      leaderPausedFollowerCount,
-		PendingRaftProposalCount: d.pendingRaftProposalCount,
-		SlowRaftProposalCount:    d.slowRaftProposalCount,
-		QuotaPoolPercentUsed:     calcQuotaPoolPercentUsed(d.qpUsed, d.qpCapacity),
-		LatchMetrics:             d.latchMetrics,
-		LockTableMetrics:         d.lockTableMetrics,
+		BehindCount:                  leaderBehindCount,
+		PausedFollowerCount:          leaderPausedFollowerCount,
+		PendingRaftProposalCount:     d.pendingRaftProposalCount,
+		SlowRaftProposalCount:        d.slowRaftProposalCount,
+		QuotaPoolPercentUsed:         calcQuotaPoolPercentUsed(d.qpUsed, d.qpCapacity),
+		QuotaPoolWaiters:             d.qpWaiters,
+		QuotaPoolLongestWaitNanos:    d.qpLongestWait.Nanoseconds(),
+		ApplyLagEntries:              applyLagEntries,
+		OldestUnappliedEntryAgeNanos: d.oldestUnappliedAge.Nanoseconds(),
+		LatchMetrics:                 d.latchMetrics,
+		LockTableMetrics:             d.lockTableMetrics,
 	}
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -85,6 +86,14 @@ var logSlowRaftProposalQuotaAcquisition = quotapool.OnSlowAcquisition(
 	base.SlowRequestThreshold, quotapool.LogSlowAcquisition,
 )
 
+// recordProposalQuotaWait records the time a proposal spent waiting for
+// proposal quota.
+func (r *Replica) recordProposalQuotaWait(
+	_ context.Context, _ string, _ quotapool.Request, start time.Time,
+) {
+	r.store.metrics.RaftQuotaPoolWaitLatency.RecordValue(timeutil.Since(start).Nanoseconds())
+}
+
 func (r *Replica) updateProposalQuotaRaftMuLocked(
 	ctx context.Context, lastLeaderID roachpb.ReplicaID,
 ) {
@@ -117,9 +126,11 @@ func (r *Replica) updateProposalQuotaRaftMuLocked(
 				"raft proposal",
 				uint64(r.store.cfg.RaftProposalQuota),
 				logSlowRaftProposalQuotaAcquisition,
+				quotapool.OnWaitFinish(r.recordProposalQuotaWait),
 			)
 			r.mu.lastUpdateTimes = make(map[roachpb.ReplicaID]time.Time)
 			r.mu.lastUpdateTimes.updateOnBecomeLeader(r.mu.state.Desc.Replicas().Descriptors(), now)
+			r.mu.followerCatchUp = make(followerCatchUpTracker)
 			r.mu.replicaFlowControlIntegration.onBecameLeader(ctx)
 			r.mu.lastProposalAtTicks = r.mu.ticks // delay imminent quiescence
 		} else if r.mu.proposalQuota != nil {
@@ -131,7 +142,9 @@ func (r *Replica) updateProposalQuotaRaftMuLocked(
 			r.mu.quotaReleaseQueue = nil
 			r.mu.proposalQuota = nil
 			r.mu.lastUpdateTimes = nil
+			r.mu.followerCatchUp = nil
 			r.mu.replicaFlowControlIntegration.onBecameFollower(ctx)
+			r.setRaftEntryCachePinnedLocked(false)
 		}
 		return
 	} else if r.mu.proposalQuota == nil {
@@ -153,12 +166,18 @@ func (r *Replica) updateProposalQuotaRaftMuLocked(
 	// cannot correspond to values beyond the applied index there's no reason
 	// to consider progress beyond it as meaningful.
 	minIndex := kvpb.RaftIndex(status.Applied)
+	// catchingUp is set if any active follower is catching up on the log.
+	var catchingUp bool
+	// prefetchFrom is the lowest Next index of the followers catching up on the
+	// log, from which entries are prefetched into the Raft entry cache.
+	var prefetchFrom kvpb.RaftIndex
 
 	r.mu.internalRaftGroup.WithProgress(func(id uint64, _ raft.ProgressType, progress tracker.Progress) {
 		rep, ok := r.mu.state.Desc.GetReplicaDescriptorByID(roachpb.ReplicaID(id))
 		if !ok {
 			return
 		}
+		r.mu.followerCatchUp.record(rep.ReplicaID, kvpb.RaftIndex(progress.Match), now)
 
 		// Only consider followers that are active. Inactive ones don't decrease
 		// minIndex - i.e. they don't hold up releasing quota.
@@ -175,6 +194,17 @@ func (r *Replica) updateProposalQuotaRaftMuLocked(
 		// recently, or we became the leader recently. The latter case is ambiguous
 		// w.r.t. the actual state of that replica, but it is temporary.
 
+		// A follower which lags behind is caught up using entries read from the
+		// log, unless it is sent a snapshot, or we're dropping MsgApps to it.
+		if _, paused := r.mu.pausedFollowers[roachpb.ReplicaID(id)]; !paused &&
+			progress.State != tracker.StateSnapshot &&
+			kvpb.RaftIndex(progress.Match)+raftEntryCacheCatchUpMinLag < commitIndex {
+			catchingUp = true
+			if next := kvpb.RaftIndex(progress.Next); prefetchFrom == 0 || next < prefetchFrom {
+				prefetchFrom = next
+			}
+		}
+
 		// Note that the Match field has different semantics depending on
 		// the State.
 		//
@@ -260,9 +290,31 @@ func (r *Replica) updateProposalQuotaRaftMuLocked(
 			status.Applied)
 	}
 
+	// Pin the range in the Raft entry cache while followers are catching up, so
+	// that the entries they need are served from the cache rather than read
+	// from disk.
+	r.setRaftEntryCachePinnedLocked(catchingUp)
+	// Warm the cache with the entries these followers need next, if they were
+	// evicted or never cached.
+	r.maybePrefetchRaftLogLocked(ctx, prefetchFrom)
+
 	// Tick the replicaFlowControlIntegration interface. This is as convenient a
 	// place to do it as any other. Much like the quota pool code above, the
 	// flow control integration layer considers raft progress state for
 	// individual replicas, and whether they've been recently active.
 	r.mu.replicaFlowControlIntegration.onRaftTicked(ctx)
 }
+
+// setRaftEntryCachePinnedLocked pins or unpins the range in the store's Raft
+// entry cache.
+func (r *Replica) setRaftEntryCachePinnedLocked(pinned bool) {
+	if r.mu.raftEntryCachePinned == pinned {
+		return
+	}
+	r.mu.raftEntryCachePinned = pinned
+	if pinned {
+		r.store.raftEntryCache.Pin(r.RangeID)
+	} else {
+		r.store.raftEntryCache.Unpin(r.RangeID)
+	}
+}
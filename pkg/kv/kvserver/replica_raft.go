@@ -1169,6 +1169,14 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 			}
 		}
 
+		// Track how long committed entries wait to be applied. The local
+		// MsgStorageApplyResp was just delivered, so the applied index of the
+		// raft group reflects the entries applied above.
+		status := raftGroup.BasicStatus()
+		r.mu.applyLag.record(
+			kvpb.RaftIndex(status.Commit), kvpb.RaftIndex(status.Applied), timeutil.Now(),
+		)
+
 		// If the Raft group still has more to process then we immediately
 		// re-enqueue it for another round of processing. This is possible if
 		// the group's committed entries were paginated due to size limitations
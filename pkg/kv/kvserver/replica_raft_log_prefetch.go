@@ -0,0 +1,154 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/raftlog"
+	"github.com/cockroachdb/cockroach/pkg/raft"
+	"github.com/cockroachdb/cockroach/pkg/raft/raftpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// RaftLogPrefetchEnabled controls whether the raft leader reads the log entries
+// that followers catching up on the log need next into the Raft entry cache
+// ahead of time, so that the MsgApps sent to them are built from the cache
+// rather than from disk.
+var RaftLogPrefetchEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.raft_log.prefetch.enabled",
+	"if enabled, the raft leader reads the log entries that followers catching "+
+		"up on the log need next into the raft entry cache ahead of time",
+	true,
+)
+
+// raftLogPrefetchMaxBytes is the maximum number of bytes of log entries read
+// by a single prefetch.
+var raftLogPrefetchMaxBytes = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"kv.raft_log.prefetch.max_bytes",
+	"the maximum number of bytes of raft log entries read ahead of time for "+
+		"the followers of a range catching up on the log",
+	4<<20, // 4 MiB
+	settings.PositiveInt,
+)
+
+// raftLogPrefetchConcurrency is the maximum number of concurrent prefetches
+// on a store. Prefetches are skipped rather than queued beyond it.
+const raftLogPrefetchConcurrency = 8
+
+// maybePrefetchRaftLogLocked starts reading the log entries from index lo into
+// the Raft entry cache, in an async task, unless they are already cached. It
+// is called by the leader with the lowest Next index of the followers catching
+// up on the log.
+//
+// Requires that both Replica.raftMu and Replica.mu are held.
+func (r *Replica) maybePrefetchRaftLogLocked(ctx context.Context, lo kvpb.RaftIndex) {
+	if lo == 0 || r.mu.raftLogPrefetching ||
+		!RaftLogPrefetchEnabled.Get(&r.store.cfg.Settings.SV) {
+		return
+	}
+	if first := r.raftFirstIndexRLocked(); lo < first {
+		// The follower needs a snapshot.
+		return
+	}
+	hi := r.mu.lastIndexNotDurable + 1
+	if lo >= hi || r.store.raftEntryCache.Contains(r.RangeID, lo) {
+		return
+	}
+	status := r.raftBasicStatusRLocked()
+	if status.RaftState != raft.StateLeader {
+		return
+	}
+	term := status.Term
+	maxBytes := uint64(raftLogPrefetchMaxBytes.Get(&r.store.cfg.Settings.SV))
+
+	r.mu.raftLogPrefetching = true
+	if err := r.store.stopper.RunAsyncTaskEx(r.AnnotateCtx(context.Background()), stop.TaskOpts{
+		TaskName:   "raft-log-prefetch",
+		SpanOpt:    stop.SterileRootSpan,
+		Sem:        r.store.raftLogPrefetchSem,
+		WaitForSem: false,
+	}, func(ctx context.Context) {
+		defer func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.mu.raftLogPrefetching = false
+		}()
+		if err := r.prefetchRaftLog(ctx, term, lo, hi, maxBytes); err != nil {
+			log.VEventf(ctx, 2, "failed to prefetch raft log entries [%d, %d): %v", lo, hi, err)
+		}
+	}); err != nil {
+		r.mu.raftLogPrefetching = false
+	}
+}
+
+// prefetchRaftLog reads the log entries in [lo, hi), up to maxBytes, and adds
+// them to the Raft entry cache if this replica is still the leader at the given
+// term. It stops at the first sideloaded entry, since sideloaded entries can't
+// be read without holding raftMu.
+func (r *Replica) prefetchRaftLog(
+	ctx context.Context, term uint64, lo, hi kvpb.RaftIndex, maxBytes uint64,
+) error {
+	var ents []raftpb.Entry
+	var size uint64
+	reader := r.store.TODOEngine().NewReader(storage.StandardDurability)
+	defer reader.Close()
+	if err := raftlog.Visit(ctx, reader, r.RangeID, lo, hi, func(ent raftpb.Entry) error {
+		// Stop at gaps, e.g. if the log was truncated concurrently.
+		if kvpb.RaftIndex(ent.Index) != lo+kvpb.RaftIndex(len(ents)) {
+			return iterutil.StopIteration()
+		}
+		typ, err := raftlog.EncodingOf(ent)
+		if err != nil {
+			return err
+		}
+		if typ.IsSideloaded() {
+			return iterutil.StopIteration()
+		}
+		ents = append(ents, ent)
+		size += uint64(ent.Size())
+		if size >= maxBytes {
+			return iterutil.StopIteration()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(ents) == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// A leader never overwrites its own log, so the entries read are still
+	// valid if this replica remained the leader since. Entries that have been
+	// truncated from the log in the meantime are dropped, to not repopulate the
+	// cache with them.
+	if status := r.raftBasicStatusRLocked(); status.RaftState != raft.StateLeader || status.Term != term {
+		return nil
+	}
+	if first := r.raftFirstIndexRLocked(); first > lo {
+		if first >= lo+kvpb.RaftIndex(len(ents)) {
+			return nil
+		}
+		ents = ents[first-lo:]
+	}
+	r.store.raftEntryCache.Add(r.RangeID, ents, false /* truncate */)
+	r.store.metrics.RaftStoragePrefetchBytes.Inc(int64(size))
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaRaftLogPrefetch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testContext{}
+	cfg := TestStoreConfig(nil)
+	// Disable ticks to avoid quiescence, which can result in empty entries
+	// being proposed.
+	cfg.RaftTickInterval = math.MaxInt32
+	cfg.TestingKnobs.DisableRaftLogQueue = true
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	tc.StartWithStoreConfig(ctx, t, stopper, cfg)
+	st := tc.store.ClusterSettings()
+	repl := tc.repl
+	rangeID := repl.RangeID
+
+	var lo kvpb.RaftIndex
+	for i := 0; i < 10; i++ {
+		if _, pErr := tc.SendWrapped(incrementArgs([]byte("a"), int64(i))); pErr != nil {
+			t.Fatal(pErr)
+		}
+		if i == 0 {
+			lo = repl.GetLastIndex()
+		}
+	}
+	hi := repl.GetLastIndex()
+
+	prefetch := func() {
+		repl.raftMu.Lock()
+		defer repl.raftMu.Unlock()
+		repl.mu.Lock()
+		defer repl.mu.Unlock()
+		repl.maybePrefetchRaftLogLocked(ctx, lo)
+	}
+	waitForPrefetch := func() {
+		testutils.SucceedsSoon(t, func() error {
+			repl.mu.RLock()
+			defer repl.mu.RUnlock()
+			if repl.mu.raftLogPrefetching {
+				return errors.New("prefetch in progress")
+			}
+			return nil
+		})
+	}
+
+	// Nothing is prefetched if prefetching is disabled.
+	repl.store.raftEntryCache.Drop(rangeID)
+	RaftLogPrefetchEnabled.Override(ctx, &st.SV, false)
+	prefetch()
+	waitForPrefetch()
+	require.False(t, repl.store.raftEntryCache.Contains(rangeID, lo))
+
+	// Otherwise, the entries are read into the cache, up to the size limit.
+	RaftLogPrefetchEnabled.Override(ctx, &st.SV, true)
+	raftLogPrefetchMaxBytes.Override(ctx, &st.SV, 1)
+	prefetch()
+	waitForPrefetch()
+	require.True(t, repl.store.raftEntryCache.Contains(rangeID, lo))
+	require.False(t, repl.store.raftEntryCache.Contains(rangeID, lo+1))
+	require.Greater(t, repl.store.metrics.RaftStoragePrefetchBytes.Count(), int64(0))
+
+	repl.store.raftEntryCache.Drop(rangeID)
+	raftLogPrefetchMaxBytes.Override(ctx, &st.SV, 4<<20)
+	prefetch()
+	waitForPrefetch()
+	for i := lo; i <= hi; i++ {
+		require.True(t, repl.store.raftEntryCache.Contains(rangeID, i), "entry %d", i)
+	}
+}
@@ -43,6 +43,7 @@ func (r *Replica) quiesceLocked(ctx context.Context, lagging laggingReplicaSet)
 		}
 		r.mu.quiescent = true
 		r.mu.laggingFollowersOnQuiesce = lagging
+		r.mu.followerCatchUp.reset()
 		r.store.unquiescedReplicas.Lock()
 		delete(r.store.unquiescedReplicas.m, r.RangeID)
 		r.store.unquiescedReplicas.Unlock()
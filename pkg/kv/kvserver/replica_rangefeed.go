@@ -341,6 +341,8 @@ func (r *Replica) RangeFeed(
 		if f := r.store.TestingKnobs().RangefeedValueHeaderFilter; f != nil {
 			catchUpIter.OnEmit = f
 		}
+		catchUpIter.EstimateProgress = catchUpProgressEstimator(
+			r.store.TODOEngine(), rSpan.AsRawSpanWithNoLocals())
 	}
 	var done future.ErrorFuture
 	p := r.registerWithRangefeedRaftMuLocked(
@@ -425,6 +427,34 @@ func logSlowRangefeedRegistration(ctx context.Context) func() {
 	}
 }
 
+// catchUpProgressEstimator returns an estimator of the progress of a catch-up
+// scan over the given span, based on the approximate on-disk size of the part
+// of the span that was scanned. The size of the whole span is computed lazily,
+// by the first estimate, so as not to hold raftMu while doing so.
+func catchUpProgressEstimator(
+	eng storage.Engine, span roachpb.Span,
+) rangefeed.CatchUpProgressEstimator {
+	var total uint64
+	var computed bool
+	return func(key roachpb.Key) float64 {
+		if !computed {
+			computed = true
+			var err error
+			if total, _, _, err = eng.ApproximateDiskBytes(span.Key, span.EndKey); err != nil {
+				total = 0
+			}
+		}
+		if total == 0 {
+			return 0
+		}
+		done, _, _, err := eng.ApproximateDiskBytes(span.Key, key)
+		if err != nil {
+			return 0
+		}
+		return min(float64(done)/float64(total), 1)
+	}
+}
+
 // registerWithRangefeedRaftMuLocked sets up a Rangefeed registration over the
 // provided span. It initializes a rangefeed for the Replica if one is not
 // already running. Requires raftMu be locked.
@@ -16,10 +16,24 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/errors"
 )
 
+// tenantBlockCacheMetricsEnabled controls whether the block cache usage of
+// read-only batches is tracked per tenant. The tracked usage also feeds the
+// max_block_cache_fill_bytes_per_second_per_node capability, which is not
+// enforced while the setting is disabled.
+var tenantBlockCacheMetricsEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.tenant_block_cache_metrics.enabled",
+	"when true, the block cache usage of reads is tracked per tenant, "+
+		"in the kv.tenant_block_cache.* metrics and against the "+
+		"max_block_cache_fill_bytes_per_second_per_node capability",
+	true,
+)
+
 // maybeRateLimitBatch may block the batch waiting to be rate-limited. Note that
 // the replica must be initialized and thus there is no synchronization issue
 // on the tenantRateLimiter.
@@ -61,3 +75,52 @@ func (r *Replica) recordImpactOnRateLimiter(
 	// readMultiplier isn't needed here since it's only used to calculate RUs.
 	r.tenantLimiter.RecordRead(ctx, tenantcostmodel.MakeResponseInfo(br, isReadOnly, 1))
 }
+
+// blockCacheUsage accumulates the block cache usage of the evaluation of a
+// read-only batch, as reported by the iterator stats.
+type blockCacheUsage struct {
+	blockBytes        uint64
+	blockBytesInCache uint64
+}
+
+type blockCacheUsageKey struct{}
+
+// blockCacheUsageFromContext returns the blockCacheUsage contained in the
+// Context, if any.
+func blockCacheUsageFromContext(ctx context.Context) *blockCacheUsage {
+	u, _ := ctx.Value(blockCacheUsageKey{}).(*blockCacheUsage)
+	return u
+}
+
+// record adds the block cache usage in the provided ScanStats. It is a no-op
+// on a nil blockCacheUsage.
+func (u *blockCacheUsage) record(ss *kvpb.ScanStats) {
+	if u == nil {
+		return
+	}
+	u.blockBytes += ss.BlockBytes
+	u.blockBytesInCache += ss.BlockBytesInCache
+}
+
+// maybeTrackBlockCacheUsage returns a Context that collects the block cache
+// usage of the evaluation of a read-only batch, if it needs to be recorded
+// against the tenant rate limiter.
+func (r *Replica) maybeTrackBlockCacheUsage(
+	ctx context.Context,
+) (context.Context, *blockCacheUsage) {
+	if r.tenantLimiter == nil || !tenantBlockCacheMetricsEnabled.Get(&r.store.cfg.Settings.SV) {
+		return ctx, nil
+	}
+	u := &blockCacheUsage{}
+	return context.WithValue(ctx, blockCacheUsageKey{}, u), u
+}
+
+// recordBlockCacheUsage records the block cache usage of a read-only batch
+// against the tenant rate limiter, for the per-tenant block cache metrics and
+// the max_block_cache_fill_bytes_per_second_per_node capability.
+func (r *Replica) recordBlockCacheUsage(ctx context.Context, u *blockCacheUsage) {
+	if u == nil || r.tenantLimiter == nil {
+		return
+	}
+	r.tenantLimiter.RecordBlockCacheUsage(ctx, int64(u.blockBytes), int64(u.blockBytesInCache))
+}
@@ -531,6 +531,11 @@ func (r *Replica) handleReadOnlyLocalEvalResult(
 		lResult.AcquiredLocks = nil
 	}
 
+	if lResult.Metrics != nil {
+		r.store.metrics.handleMetricsResult(ctx, *lResult.Metrics)
+		lResult.Metrics = nil
+	}
+
 	if !lResult.IsZero() {
 		log.Fatalf(ctx, "unhandled field in LocalEvalResult: %s", pretty.Diff(lResult, result.LocalResult{}))
 	}
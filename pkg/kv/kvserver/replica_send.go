@@ -177,8 +177,10 @@ func (r *Replica) SendWithWriteBytes(
 	var writeBytes *kvadmission.StoreWriteBytes
 	if isReadOnly {
 		log.Event(ctx, "read-only path")
+		readCtx, usage := r.maybeTrackBlockCacheUsage(ctx)
 		fn := (*Replica).executeReadOnlyBatch
-		br, _, pErr = r.executeBatchWithConcurrencyRetries(ctx, ba, fn)
+		br, _, pErr = r.executeBatchWithConcurrencyRetries(readCtx, ba, fn)
+		r.recordBlockCacheUsage(ctx, usage)
 	} else if ba.IsWrite() {
 		log.Event(ctx, "read-write path")
 		fn := (*Replica).executeWriteBatch
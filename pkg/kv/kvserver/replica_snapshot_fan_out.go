@@ -0,0 +1,110 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+)
+
+// snapshotFanOutEnabled controls whether the initial snapshots sent to a set
+// of learners added in one replication change can be fanned out through the
+// first learner seeded in a locality that has no existing replica.
+var snapshotFanOutEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.snapshot_delegation.fan_out.enabled",
+	"if enabled, when multiple replicas are added to a locality that doesn't "+
+		"contain a replica of the range, the first of them to receive a snapshot "+
+		"is used as the delegate sender for the remaining ones",
+	false,
+)
+
+// snapshotFanOut tracks the learners seeded by initial snapshots during a
+// single replication change, so that they can act as delegate senders for the
+// remaining learners in the same locality. Without it, each of the learners
+// added to a remote region receives its snapshot across the region boundary
+// from the coordinator since there is no existing replica in that region that
+// could act as a delegate.
+//
+// A snapshotFanOut is not safe for concurrent use.
+type snapshotFanOut struct {
+	// localities holds the localities of the learners being added, keyed by
+	// their replica IDs.
+	localities map[roachpb.ReplicaID]roachpb.Locality
+	// existing holds the localities of the voters and non-voters which were
+	// part of the range before the change. These can already act as delegates,
+	// so no fan-out is needed for learners that share a region with one of
+	// them.
+	existing []roachpb.Locality
+	// seeded holds the learners which have successfully received their
+	// initial snapshot, in the order they were seeded.
+	seeded []roachpb.ReplicaDescriptor
+}
+
+// newSnapshotFanOut constructs a snapshotFanOut for the given learners which
+// are being added to the range described by desc.
+func newSnapshotFanOut(
+	localities func(...roachpb.ReplicaDescriptor) map[roachpb.ReplicaID]roachpb.Locality,
+	desc *roachpb.RangeDescriptor,
+	learners []roachpb.ReplicaDescriptor,
+) *snapshotFanOut {
+	learnerIDs := make(map[roachpb.ReplicaID]struct{}, len(learners))
+	for _, l := range learners {
+		learnerIDs[l.ReplicaID] = struct{}{}
+	}
+	var existing []roachpb.ReplicaDescriptor
+	for _, rDesc := range desc.Replicas().VoterAndNonVoterDescriptors() {
+		if _, ok := learnerIDs[rDesc.ReplicaID]; !ok {
+			existing = append(existing, rDesc)
+		}
+	}
+	f := &snapshotFanOut{localities: localities(learners...)}
+	for _, locality := range localities(existing...) {
+		f.existing = append(f.existing, locality)
+	}
+	return f
+}
+
+// sendersFor returns the seeded learners which should be preferred as the
+// delegate sender of the snapshot to the given recipient, closest first. An
+// empty result means that the regular delegate selection should be used,
+// either because an existing replica shares the recipient's region or because
+// no learner in the recipient's region has been seeded yet.
+func (f *snapshotFanOut) sendersFor(recipient roachpb.ReplicaDescriptor) []roachpb.ReplicaDescriptor {
+	recipientLocality, ok := f.localities[recipient.ReplicaID]
+	if !ok || recipientLocality.Empty() {
+		return nil
+	}
+	for _, locality := range f.existing {
+		if recipientLocality.SharedPrefix(locality) > 0 {
+			return nil
+		}
+	}
+	var senders []roachpb.ReplicaDescriptor
+	for _, s := range f.seeded {
+		if recipientLocality.SharedPrefix(f.localities[s.ReplicaID]) > 0 {
+			senders = append(senders, s)
+		}
+	}
+	sort.SliceStable(senders, func(i, j int) bool {
+		return recipientLocality.SharedPrefix(f.localities[senders[i].ReplicaID]) >
+			recipientLocality.SharedPrefix(f.localities[senders[j].ReplicaID])
+	})
+	return senders
+}
+
+// markSeeded records that the given learner has received its initial
+// snapshot and can act as a delegate sender for other learners.
+func (f *snapshotFanOut) markSeeded(learner roachpb.ReplicaDescriptor) {
+	f.seeded = append(f.seeded, learner)
+}
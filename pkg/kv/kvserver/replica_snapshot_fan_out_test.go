@@ -0,0 +1,95 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotFanOut(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	locality := func(region, zone string) roachpb.Locality {
+		return roachpb.Locality{Tiers: []roachpb.Tier{
+			{Key: "region", Value: region},
+			{Key: "zone", Value: zone},
+		}}
+	}
+	// Replica ID -> locality.
+	localities := map[roachpb.ReplicaID]roachpb.Locality{
+		1: locality("us-east", "a"),
+		2: locality("us-east", "b"),
+		3: locality("eu-west", "a"),
+		4: locality("eu-west", "b"),
+		5: locality("us-east", "c"),
+		6: locality("eu-west", "a"),
+	}
+	lookup := func(replicas ...roachpb.ReplicaDescriptor) map[roachpb.ReplicaID]roachpb.Locality {
+		m := make(map[roachpb.ReplicaID]roachpb.Locality, len(replicas))
+		for _, r := range replicas {
+			m[r.ReplicaID] = localities[r.ReplicaID]
+		}
+		return m
+	}
+	replica := func(id roachpb.ReplicaID, typ roachpb.ReplicaType) roachpb.ReplicaDescriptor {
+		return roachpb.ReplicaDescriptor{
+			NodeID:    roachpb.NodeID(id),
+			StoreID:   roachpb.StoreID(id),
+			ReplicaID: id,
+			Type:      typ,
+		}
+	}
+
+	// Replicas 1 and 2 are existing voters in us-east. Replicas 3, 4 and 6 are
+	// learners being added to eu-west, and 5 is a learner added to us-east.
+	desc := &roachpb.RangeDescriptor{
+		InternalReplicas: []roachpb.ReplicaDescriptor{
+			replica(1, roachpb.VOTER_FULL),
+			replica(2, roachpb.VOTER_FULL),
+			replica(3, roachpb.LEARNER),
+			replica(4, roachpb.LEARNER),
+			replica(5, roachpb.LEARNER),
+			replica(6, roachpb.LEARNER),
+		},
+	}
+	learners := []roachpb.ReplicaDescriptor{
+		replica(3, roachpb.LEARNER),
+		replica(4, roachpb.LEARNER),
+		replica(5, roachpb.LEARNER),
+		replica(6, roachpb.LEARNER),
+	}
+	f := newSnapshotFanOut(lookup, desc, learners)
+
+	// Nothing has been seeded yet, so the regular delegate selection is used.
+	require.Empty(t, f.sendersFor(learners[0]))
+	f.markSeeded(learners[0])
+
+	// The second eu-west learner is fanned out through the first one.
+	require.Equal(t, []roachpb.ReplicaDescriptor{learners[0]}, f.sendersFor(learners[1]))
+	f.markSeeded(learners[1])
+
+	// The us-east learner already has existing voters in its region to use as
+	// delegates, so no fan-out is needed.
+	require.Empty(t, f.sendersFor(learners[2]))
+	f.markSeeded(learners[2])
+
+	// The last eu-west learner prefers the seeded learner in its own zone.
+	require.Equal(t,
+		[]roachpb.ReplicaDescriptor{learners[0], learners[1]},
+		f.sendersFor(learners[3]),
+	)
+}
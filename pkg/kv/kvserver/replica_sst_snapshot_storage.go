@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -56,18 +57,20 @@ func NewSSTSnapshotStorage(engine storage.Engine, limiter *rate.Limiter) SSTSnap
 }
 
 // NewScratchSpace creates a new storage scratch space for SSTs for a specific
-// snapshot.
+// snapshot. If snapshotQ is non-nil, writes to the scratch space are paced by
+// admission control.
 func (s *SSTSnapshotStorage) NewScratchSpace(
-	rangeID roachpb.RangeID, snapUUID uuid.UUID,
+	rangeID roachpb.RangeID, snapUUID uuid.UUID, snapshotQ *admission.SnapshotQueue,
 ) *SSTSnapshotStorageScratch {
 	s.mu.Lock()
 	s.mu.rangeRefCount[rangeID]++
 	s.mu.Unlock()
 	snapDir := filepath.Join(s.dir, strconv.Itoa(int(rangeID)), snapUUID.String())
 	return &SSTSnapshotStorageScratch{
-		storage: s,
-		rangeID: rangeID,
-		snapDir: snapDir,
+		storage:   s,
+		rangeID:   rangeID,
+		snapDir:   snapDir,
+		snapshotQ: snapshotQ,
 	}
 }
 
@@ -108,6 +111,7 @@ type SSTSnapshotStorageScratch struct {
 	snapDir    string
 	dirCreated bool
 	closed     bool
+	snapshotQ  *admission.SnapshotQueue
 }
 
 func (s *SSTSnapshotStorageScratch) filename(id int) string {
@@ -234,6 +238,9 @@ func (f *SSTSnapshotStorageFile) Write(contents []byte) error {
 	if err := kvserverbase.LimitBulkIOWrite(f.ctx, f.scratch.storage.limiter, len(contents)); err != nil {
 		return err
 	}
+	if err := f.scratch.snapshotQ.Admit(f.ctx, int64(len(contents))); err != nil {
+		return err
+	}
 	// Write always returns an error if it can't write all the contents.
 	_, err := f.file.Write(contents)
 	return err
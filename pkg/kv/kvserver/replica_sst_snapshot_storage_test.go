@@ -49,7 +49,7 @@ func TestSSTSnapshotStorage(t *testing.T) {
 	defer eng.Close()
 
 	sstSnapshotStorage := NewSSTSnapshotStorage(eng, testLimiter)
-	scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, testSnapUUID)
+	scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, testSnapUUID, nil /* snapshotQ */)
 
 	// Check that the storage lazily creates the directories on first write.
 	_, err := eng.Env().Stat(scratch.snapDir)
@@ -131,7 +131,7 @@ func TestSSTSnapshotStorageConcurrentRange(t *testing.T) {
 	sstSnapshotStorage := NewSSTSnapshotStorage(eng, testLimiter)
 
 	runForSnap := func(snapUUID uuid.UUID) error {
-		scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, snapUUID)
+		scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, snapUUID, nil /* snapshotQ */)
 
 		// Check that the storage lazily creates the directories on first write.
 		_, err := eng.Env().Stat(scratch.snapDir)
@@ -234,7 +234,7 @@ func TestSSTSnapshotStorageContextCancellation(t *testing.T) {
 	defer eng.Close()
 
 	sstSnapshotStorage := NewSSTSnapshotStorage(eng, testLimiter)
-	scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, testSnapUUID)
+	scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, testSnapUUID, nil /* snapshotQ */)
 
 	var cancel func()
 	ctx, cancel = context.WithCancel(ctx)
@@ -270,7 +270,7 @@ func TestMultiSSTWriterInitSST(t *testing.T) {
 	defer eng.Close()
 
 	sstSnapshotStorage := NewSSTSnapshotStorage(eng, testLimiter)
-	scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, testSnapUUID)
+	scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, testSnapUUID, nil /* snapshotQ */)
 	desc := roachpb.RangeDescriptor{
 		StartKey: roachpb.RKey("d"),
 		EndKey:   roachpb.RKeyMax,
@@ -336,7 +336,7 @@ func TestMultiSSTWriterAddLastSpan(t *testing.T) {
 			defer eng.Close()
 
 			sstSnapshotStorage := NewSSTSnapshotStorage(eng, testLimiter)
-			scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, testSnapUUID)
+			scratch := sstSnapshotStorage.NewScratchSpace(testRangeID, testSnapUUID, nil /* snapshotQ */)
 			desc := roachpb.RangeDescriptor{
 				StartKey: roachpb.RKey("d"),
 				EndKey:   roachpb.RKeyMax,
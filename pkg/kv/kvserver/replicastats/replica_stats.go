@@ -372,6 +372,18 @@ func (rs *ReplicaStats) ResetRequestCounts(now time.Time) {
 	rs.lastRotate = now
 }
 
+// SeedRate discards any recorded counts and replaces them with a single full
+// window whose average is the given rate per second. This is used to warm
+// start the stats from a previously persisted rate, e.g. following a restart,
+// so that the rate is immediately available and decays as new windows are
+// recorded. Per-locality counts are not seeded.
+func (rs *ReplicaStats) SeedRate(now time.Time, rate float64) {
+	rs.ResetRequestCounts(now)
+	prevIdx := (rs.idx + len(rs.records) - 1) % len(rs.records)
+	rs.records[prevIdx].sum = rate * replStatsRotateInterval.Seconds()
+	rs.records[prevIdx].activate()
+}
+
 // SnapshotRatedSummary returns a RatedSummary representing a snapshot of the
 // current replica stats state, summarized by arithmetic mean count,
 // per-locality count and duration recorded over.
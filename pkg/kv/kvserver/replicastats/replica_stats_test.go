@@ -658,3 +658,33 @@ func TestReplicaStatsRecordAggregate(t *testing.T) {
 
 	require.Equal(t, expectedStatsRecord, rs.records[rs.idx])
 }
+
+// TestReplicaStatsSeedRate asserts that a seeded rate is immediately reported
+// as the average and decays as new windows are recorded.
+func TestReplicaStatsSeedRate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	now := testingStartTime()
+	rs := NewReplicaStats(now, nil)
+	rs.RecordCount(now, 1000, 0)
+
+	rs.SeedRate(now, 50)
+	rate, dur := rs.AverageRatePerSecond(now)
+	require.InDelta(t, 50, rate, 0.001)
+	require.Equal(t, replStatsRotateInterval, dur)
+
+	// Nothing is recorded for a further window, which halves the average.
+	now = now.Add(replStatsRotateInterval)
+	rate, dur = rs.AverageRatePerSecond(now)
+	require.InDelta(t, 25, rate, 0.001)
+	require.Equal(t, 2*replStatsRotateInterval, dur)
+
+	// Once the seeded window has rotated out, nothing remains. The counts
+	// recorded prior to seeding were discarded.
+	for i := 1; i < len(rs.records); i++ {
+		now = now.Add(replStatsRotateInterval)
+		rate, _ = rs.AverageRatePerSecond(now)
+	}
+	require.Zero(t, rate)
+}
@@ -609,6 +609,7 @@ func (rq *replicateQueue) shouldQueue(
 	if err != nil {
 		return false, 0
 	}
+	repl.maybeAddHotKeyNonVoters(&conf)
 	desc := repl.Desc()
 	return rq.planner.ShouldPlanChange(
 		ctx,
@@ -642,6 +643,7 @@ func (rq *replicateQueue) process(
 	if err != nil {
 		return false, err
 	}
+	repl.maybeAddHotKeyNonVoters(&conf)
 	desc := repl.Desc()
 	// Use a retry loop in order to backoff in the case of snapshot errors,
 	// usually signaling that a rebalancing reservation could not be made with the
@@ -15,9 +15,13 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -27,6 +31,41 @@ import (
 
 const rangeIDChunkSize = 1000
 
+// raftSchedulerDynamicResizingEnabled controls whether the Raft scheduler of
+// each store resizes its worker pool based on the queueing latency of its
+// ranges. Since each store has its own scheduler, this allows a store with a
+// slow disk, whose workers are blocked on I/O, to add workers without
+// affecting the other stores on the node.
+var raftSchedulerDynamicResizingEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.raft.scheduler.dynamic_resizing.enabled",
+	"if enabled, the Raft scheduler of each store adds workers when ranges "+
+		"queue for longer than kv.raft.scheduler.dynamic_resizing.target_latency, "+
+		"up to twice the configured number of workers",
+	false,
+)
+
+// raftSchedulerTargetLatency is the mean queueing latency above which the Raft
+// scheduler adds workers when dynamic resizing is enabled. Workers are removed
+// again when the latency drops below half of it.
+var raftSchedulerTargetLatency = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.raft.scheduler.dynamic_resizing.target_latency",
+	"the mean Raft scheduler queueing latency above which workers are added "+
+		"when dynamic resizing is enabled",
+	10*time.Millisecond,
+	settings.PositiveDuration,
+)
+
+const (
+	// raftSchedulerResizeInterval is the interval at which the Raft scheduler
+	// considers resizing its shards.
+	raftSchedulerResizeInterval = time.Second
+	// raftSchedulerMaxWorkersMultiplier bounds the number of workers of a shard,
+	// relative to its configured number of workers.
+	raftSchedulerMaxWorkersMultiplier = 2
+)
+
 // priorityIDsValue is a placeholder value for raftScheduler.priorityIDs. IntMap
 // requires an unsafe.Pointer value, but we don't care about the value (only
 // the key), so we can reuse the same allocation.
@@ -106,6 +145,15 @@ func (q *rangeIDQueue) PopFront() (roachpb.RangeID, bool) {
 	return id, true
 }
 
+// Front returns the range ID at the front of the queue without removing it.
+func (q *rangeIDQueue) Front() (roachpb.RangeID, bool) {
+	if q.len == 0 {
+		return 0, false
+	}
+	front := q.chunks.Front().Value.(*rangeIDChunk)
+	return front.buf[front.rd], true
+}
+
 func (q *rangeIDQueue) Len() int {
 	return q.len
 }
@@ -215,6 +263,7 @@ func shardIndex(id roachpb.RangeID, numShards int, priority bool) int {
 
 type raftScheduler struct {
 	ambientContext log.AmbientContext
+	st             *cluster.Settings // nil in tests that don't resize
 	processor      raftProcessor
 	metrics        *StoreMetrics
 	// shards contains scheduler shards. Ranges and workers are allocated to
@@ -227,16 +276,31 @@ type raftScheduler struct {
 
 type raftSchedulerShard struct {
 	syncutil.Mutex
-	cond       *sync.Cond
-	queue      rangeIDQueue
-	state      map[roachpb.RangeID]raftScheduleState
+	cond     *sync.Cond
+	queue    rangeIDQueue
+	state    map[roachpb.RangeID]raftScheduleState
+	priority bool
+	maxTicks int
+	stopped  bool
+	// numWorkers is the configured number of workers. The shard starts with
+	// this many workers, and never has fewer.
 	numWorkers int
-	maxTicks   int
-	stopped    bool
+	// targetWorkers is the number of workers the shard is being resized to. It
+	// is equal to numWorkers unless dynamic resizing is enabled.
+	targetWorkers int
+	// runningWorkers is the number of workers currently running. Workers exit
+	// when it exceeds targetWorkers.
+	runningWorkers int
+
+	// latencySum and latencyCount accumulate the queueing latency of the ranges
+	// processed by the shard since the last resizing decision.
+	latencySum   atomic.Int64
+	latencyCount atomic.Int64
 }
 
 func newRaftScheduler(
 	ambient log.AmbientContext,
+	st *cluster.Settings,
 	metrics *StoreMetrics,
 	processor raftProcessor,
 	numWorkers int,
@@ -246,6 +310,7 @@ func newRaftScheduler(
 ) *raftScheduler {
 	s := &raftScheduler{
 		ambientContext: ambient,
+		st:             st,
 		processor:      processor,
 		metrics:        metrics,
 	}
@@ -255,6 +320,7 @@ func newRaftScheduler(
 		priorityWorkers = 1
 	}
 	s.shards = append(s.shards, newRaftSchedulerShard(priorityWorkers, maxTicks))
+	s.shards[0].priority = true
 
 	// Regular shards, excluding priority shard.
 	numShards := 1
@@ -276,9 +342,10 @@ func newRaftScheduler(
 
 func newRaftSchedulerShard(numWorkers, maxTicks int) *raftSchedulerShard {
 	shard := &raftSchedulerShard{
-		state:      map[roachpb.RangeID]raftScheduleState{},
-		numWorkers: numWorkers,
-		maxTicks:   maxTicks,
+		state:         map[roachpb.RangeID]raftScheduleState{},
+		numWorkers:    numWorkers,
+		targetWorkers: numWorkers,
+		maxTicks:      maxTicks,
 	}
 	shard.cond = sync.NewCond(&shard.Mutex)
 	return shard
@@ -307,27 +374,141 @@ func (s *raftScheduler) Start(stopper *stop.Stopper) {
 	}
 
 	for _, shard := range s.shards {
-		s.done.Add(shard.numWorkers)
-		shard := shard // pin loop variable
-		for i := 0; i < shard.numWorkers; i++ {
-			if err := stopper.RunAsyncTaskEx(ctx,
-				stop.TaskOpts{
-					TaskName: "raft-worker",
-					// This task doesn't reference a parent because it runs for the server's
-					// lifetime.
-					SpanOpt: stop.SterileRootSpan,
-				},
-				func(ctx context.Context) {
-					shard.worker(ctx, s.processor, s.metrics)
-					s.done.Done()
-				},
-			); err != nil {
+		s.startWorkers(ctx, stopper, shard, shard.numWorkers)
+	}
+
+	if s.st != nil {
+		if err := stopper.RunAsyncTaskEx(ctx,
+			stop.TaskOpts{
+				TaskName: "raftsched-resizer",
+				// This task doesn't reference a parent because it runs for the server's
+				// lifetime.
+				SpanOpt: stop.SterileRootSpan,
+			},
+			func(ctx context.Context) {
+				s.resizeLoop(ctx, stopper)
+			}); err != nil {
+			log.Warningf(ctx, "unable to start raft scheduler resizer: %v", err)
+		}
+	}
+}
+
+// startWorkers starts n additional workers for the given shard.
+func (s *raftScheduler) startWorkers(
+	ctx context.Context, stopper *stop.Stopper, shard *raftSchedulerShard, n int,
+) {
+	shard.Lock()
+	if shard.stopped || n <= 0 {
+		shard.Unlock()
+		return
+	}
+	// Account for the workers while holding the lock, so that they are waited
+	// for by Wait even if the shard is stopped concurrently. Since a running
+	// shard always has at least one worker, s.done can't be at zero here.
+	shard.runningWorkers += n
+	s.done.Add(n)
+	shard.Unlock()
+	s.metrics.RaftSchedulerWorkers.Inc(int64(n))
+
+	for i := 0; i < n; i++ {
+		if err := stopper.RunAsyncTaskEx(ctx,
+			stop.TaskOpts{
+				TaskName: "raft-worker",
+				// This task doesn't reference a parent because it runs for the server's
+				// lifetime.
+				SpanOpt: stop.SterileRootSpan,
+			},
+			func(ctx context.Context) {
+				shard.worker(ctx, s.processor, s.metrics)
+				s.metrics.RaftSchedulerWorkers.Dec(1)
 				s.done.Done()
+			},
+		); err != nil {
+			shard.Lock()
+			shard.runningWorkers--
+			shard.Unlock()
+			s.metrics.RaftSchedulerWorkers.Dec(1)
+			s.done.Done()
+		}
+	}
+}
+
+// resizeLoop periodically resizes the scheduler's shards until the stopper
+// quiesces.
+func (s *raftScheduler) resizeLoop(ctx context.Context, stopper *stop.Stopper) {
+	ticker := time.NewTicker(raftSchedulerResizeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			enabled := raftSchedulerDynamicResizingEnabled.Get(&s.st.SV)
+			targetLatency := raftSchedulerTargetLatency.Get(&s.st.SV)
+			for _, shard := range s.shards {
+				target := shard.nextTargetWorkers(enabled, targetLatency)
+				s.resizeShard(ctx, stopper, shard, target)
 			}
+		case <-stopper.ShouldQuiesce():
+			return
 		}
 	}
 }
 
+// nextTargetWorkers returns the number of workers the shard should have, based
+// on the mean queueing latency of the ranges it processed since the last call,
+// or the time the oldest queued range has been waiting if that is longer, which
+// is the case when all workers are stalled. Workers are added when the latency
+// exceeds targetLatency, and removed when it drops below half of it. The shard
+// always has between numWorkers and raftSchedulerMaxWorkersMultiplier*numWorkers
+// workers.
+func (ss *raftSchedulerShard) nextTargetWorkers(enabled bool, targetLatency time.Duration) int {
+	sum, count := ss.latencySum.Swap(0), ss.latencyCount.Swap(0)
+	if !enabled {
+		return ss.numWorkers
+	}
+	now := nowNanos()
+	var latency time.Duration
+	if count > 0 {
+		latency = time.Duration(sum / count)
+	}
+	ss.Lock()
+	target := ss.targetWorkers
+	if id, ok := ss.queue.Front(); ok {
+		latency = max(latency, time.Duration(now-ss.state[id].begin))
+	}
+	ss.Unlock()
+
+	step := max(ss.numWorkers/4, 1)
+	switch {
+	case latency > targetLatency:
+		target = min(target+step, raftSchedulerMaxWorkersMultiplier*ss.numWorkers)
+	case latency < targetLatency/2:
+		target = max(target-step, ss.numWorkers)
+	}
+	return target
+}
+
+// resizeShard resizes the given shard to the target number of workers. Excess
+// workers exit once they finish processing their current range.
+func (s *raftScheduler) resizeShard(
+	ctx context.Context, stopper *stop.Stopper, shard *raftSchedulerShard, target int,
+) {
+	shard.Lock()
+	prev := shard.targetWorkers
+	shard.targetWorkers = target
+	toStart := target - shard.runningWorkers
+	shard.Unlock()
+	if target == prev {
+		return
+	}
+	log.VEventf(ctx, 1, "resizing raft scheduler shard from %d to %d workers", prev, target)
+	if toStart > 0 {
+		s.startWorkers(ctx, stopper, shard, toStart)
+	} else if target < prev {
+		// Wake up idle workers so that excess ones exit.
+		shard.cond.Broadcast()
+	}
+}
+
 func (s *raftScheduler) Wait(context.Context) {
 	s.done.Wait()
 }
@@ -368,6 +549,18 @@ func (ss *raftSchedulerShard) worker(
 		var id roachpb.RangeID
 		for {
 			if ss.stopped {
+				ss.runningWorkers--
+				ss.Unlock()
+				return
+			}
+			if ss.runningWorkers > ss.targetWorkers {
+				// The shard was resized down, so this worker exits. It may have been
+				// woken up by a Signal meant for a worker to process a queued range,
+				// so pass it on to another worker.
+				ss.runningWorkers--
+				if ss.queue.Len() > 0 {
+					ss.cond.Signal()
+				}
 				ss.Unlock()
 				return
 			}
@@ -388,6 +581,11 @@ func (ss *raftSchedulerShard) worker(
 		// Record the scheduling latency for the range.
 		lat := nowNanos() - state.begin
 		metrics.RaftSchedulerLatency.RecordValue(lat)
+		if ss.priority {
+			metrics.RaftSchedulerPriorityLatency.RecordValue(lat)
+		}
+		ss.latencySum.Add(lat)
+		ss.latencyCount.Add(1)
 
 		// Process requests first. This avoids a scenario where a tick and a
 		// "quiesce" message are processed in the same iteration and intervening
@@ -225,7 +225,7 @@ func TestSchedulerLoop(t *testing.T) {
 
 	m := newStoreMetrics(metric.TestSampleInterval)
 	p := newTestProcessor()
-	s := newRaftScheduler(log.MakeTestingAmbientContext(stopper.Tracer()), m, p, 1, 1, 1, 1)
+	s := newRaftScheduler(log.MakeTestingAmbientContext(stopper.Tracer()), nil /* st */, m, p, 1, 1, 1, 1)
 	s.Start(stopper)
 
 	batch := s.NewEnqueueBatch()
@@ -261,7 +261,7 @@ func TestSchedulerBuffering(t *testing.T) {
 
 	m := newStoreMetrics(metric.TestSampleInterval)
 	p := newTestProcessor()
-	s := newRaftScheduler(log.MakeTestingAmbientContext(stopper.Tracer()), m, p, 1, 1, 1, 5)
+	s := newRaftScheduler(log.MakeTestingAmbientContext(stopper.Tracer()), nil /* st */, m, p, 1, 1, 1, 5)
 	s.Start(stopper)
 
 	testCases := []struct {
@@ -384,7 +384,7 @@ func TestNewSchedulerShards(t *testing.T) {
 		t.Run(fmt.Sprintf("workers=%d/shardSize=%d", tc.workers, tc.shardSize), func(t *testing.T) {
 			m := newStoreMetrics(metric.TestSampleInterval)
 			p := newTestProcessor()
-			s := newRaftScheduler(log.MakeTestingAmbientContext(nil), m, p,
+			s := newRaftScheduler(log.MakeTestingAmbientContext(nil), nil /* st */, m, p,
 				tc.workers, tc.shardSize, tc.priorityWorkers, 5)
 
 			var shardWorkers []int
@@ -410,7 +410,7 @@ func TestSchedulerPriority(t *testing.T) {
 
 	m := newStoreMetrics(metric.TestSampleInterval)
 	p := newTestProcessor()
-	s := newRaftScheduler(log.MakeTestingAmbientContext(nil), m, p, 1, 1, 1, 5)
+	s := newRaftScheduler(log.MakeTestingAmbientContext(nil), nil /* st */, m, p, 1, 1, 1, 5)
 	s.Start(stopper)
 	require.Empty(t, s.PriorityIDs())
 
@@ -473,6 +473,96 @@ func TestSchedulerPriority(t *testing.T) {
 	}, 10*time.Second, 100*time.Millisecond)
 }
 
+// TestSchedulerDynamicResizing tests that a scheduler shard adds workers when
+// its ranges are starved by a stalled worker, and removes them again once the
+// stall resolves.
+func TestSchedulerDynamicResizing(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// Set up a test scheduler with 1 regular worker. Resizing is driven
+	// manually below, so no settings are passed.
+	stopper := stop.NewStopper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer stopper.Stop(ctx)
+
+	m := newStoreMetrics(metric.TestSampleInterval)
+	p := newTestProcessor()
+	s := newRaftScheduler(log.MakeTestingAmbientContext(nil), nil /* st */, m, p, 1, 1, 1, 5)
+	s.Start(stopper)
+	require.Equal(t, int64(2), m.RaftSchedulerWorkers.Value())
+
+	shard := s.shards[1]
+	runningWorkers := func() int {
+		shard.Lock()
+		defer shard.Unlock()
+		return shard.runningWorkers
+	}
+	resize := func() {
+		s.resizeShard(ctx, stopper, shard, shard.nextTargetWorkers(true, time.Millisecond))
+	}
+
+	// Disabled resizing keeps the configured number of workers.
+	require.Equal(t, 1, shard.nextTargetWorkers(false, time.Millisecond))
+
+	// We use 2 ranges: r1 blocks, r2 starves due to r1.
+	const (
+		blockedID = 1
+		starvedID = 2
+	)
+	blockedC := make(chan chan struct{}, 1)
+	p.onReady(func(rangeID roachpb.RangeID) {
+		if rangeID == blockedID {
+			unblockC := make(chan struct{})
+			blockedC <- unblockC
+			select {
+			case <-unblockC:
+			case <-ctx.Done():
+			}
+		}
+	})
+	s.EnqueueRaftReady(blockedID)
+
+	var unblockC chan struct{}
+	select {
+	case unblockC = <-blockedC:
+	case <-ctx.Done():
+		return
+	}
+	s.EnqueueRaftReady(starvedID)
+	time.Sleep(10 * time.Millisecond)
+	require.Zero(t, p.readyCount(starvedID))
+
+	// r2 has been queued for longer than the target latency, so the shard adds
+	// a worker, which processes r2.
+	resize()
+	require.Equal(t, 2, runningWorkers())
+	require.Eventually(t, func() bool {
+		return p.readyCount(starvedID) == 1
+	}, 10*time.Second, 100*time.Millisecond)
+	require.Equal(t, int64(3), m.RaftSchedulerWorkers.Value())
+
+	// The shard never grows beyond twice its configured size.
+	resize()
+	require.Equal(t, 2, runningWorkers())
+
+	// Once the stall resolves and ranges no longer queue, the shard shrinks
+	// back to its configured size.
+	close(unblockC)
+	resize()
+	require.Eventually(t, func() bool {
+		return runningWorkers() == 1
+	}, 10*time.Second, 100*time.Millisecond)
+	require.Equal(t, int64(2), m.RaftSchedulerWorkers.Value())
+
+	// The remaining worker still processes ranges.
+	s.EnqueueRaftReady(starvedID)
+	require.Eventually(t, func() bool {
+		return p.readyCount(starvedID) == 2
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
 // TestSchedulerPrioritizesLivenessAndMeta tests that the meta and liveness
 // ranges are prioritized in the Raft scheduler.
 func TestSchedulerPrioritizesLivenessAndMeta(t *testing.T) {
@@ -541,7 +631,7 @@ func runSchedulerEnqueueRaftTicks(
 	m := newStoreMetrics(metric.TestSampleInterval)
 	p := newTestProcessor()
 	s := newRaftScheduler(
-		a, m, p, numWorkers, defaultRaftSchedulerShardSize, defaultRaftSchedulerPriorityShardSize, 5)
+		a, nil /* st */, m, p, numWorkers, defaultRaftSchedulerShardSize, defaultRaftSchedulerPriorityShardSize, 5)
 
 	// If requested, add a prioritized range corresponding to e.g. the liveness
 	// range.
@@ -0,0 +1,355 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// snapshotCompressionCodec is the codec, and for zstd the level, with which
+// the KV batches of outgoing snapshots are compressed.
+type snapshotCompressionCodec int64
+
+const (
+	snapshotCompressionOff snapshotCompressionCodec = iota
+	snapshotCompressionSnappy
+	snapshotCompressionZstdFastest
+	snapshotCompressionZstdDefault
+	snapshotCompressionZstdBetter
+	snapshotCompressionZstdBest
+)
+
+// snapshotCompression selects the codec of the KV batches of snapshots. The
+// batches are compressed by the sender, on top of the compression of the RPC
+// connection, so that rebalancing traffic can be compressed more aggressively
+// than the rest.
+var snapshotCompression = settings.RegisterEnumSetting(
+	settings.SystemOnly,
+	"kv.snapshot.compression.codec",
+	"the codec used to compress the KV batches of snapshots; the zstd levels "+
+		"compress better than snappy but use more CPU on the sender, and can use "+
+		"the dictionaries of kv.snapshot.compression.dictionaries",
+	"off",
+	map[int64]string{
+		int64(snapshotCompressionOff):         "off",
+		int64(snapshotCompressionSnappy):      "snappy",
+		int64(snapshotCompressionZstdFastest): "zstd-fastest",
+		int64(snapshotCompressionZstdDefault): "zstd-default",
+		int64(snapshotCompressionZstdBetter):  "zstd-better",
+		int64(snapshotCompressionZstdBest):    "zstd-best",
+	},
+	settings.WithPublic,
+)
+
+// snapshotCompressionDictionaries lists zstd dictionaries, e.g. trained with
+// `zstd --train` on the data of a table, to compress the KV batches of
+// snapshots starting with given key prefixes with. Small batches of similar
+// KVs compress much better with a dictionary.
+var snapshotCompressionDictionaries = settings.RegisterStringSetting(
+	settings.SystemOnly,
+	"kv.snapshot.compression.dictionaries",
+	"zstd dictionaries used to compress the KV batches of snapshots starting with "+
+		"given key prefixes, as a comma-separated list of <hex-encoded key prefix>="+
+		"<base64-encoded dictionary> pairs; the longest matching prefix is used, and "+
+		"the dictionaries must have distinct IDs",
+	"",
+	settings.WithValidateString(func(_ *settings.Values, s string) error {
+		_, err := parseSnapshotCompressionDictionaries(s)
+		return err
+	}),
+)
+
+// zstdDictionaryMagic is the magic number at the start of zstd dictionaries.
+const zstdDictionaryMagic = 0xEC30A437
+
+// snapshotDictionaryID returns the ID of the given zstd dictionary. The frame
+// header of a batch compressed with a dictionary only identifies it by its ID,
+// so the dictionaries of a snapshot must have distinct, non-zero IDs.
+func snapshotDictionaryID(dict []byte) (uint32, error) {
+	if len(dict) < 8 || binary.LittleEndian.Uint32(dict) != zstdDictionaryMagic {
+		return 0, errors.New("not a zstd dictionary")
+	}
+	id := binary.LittleEndian.Uint32(dict[4:])
+	if id == 0 {
+		return 0, errors.New("zstd dictionary has no ID")
+	}
+	return id, nil
+}
+
+// checkSnapshotDictionaryIDs returns an error if two of the given dictionaries
+// have the same ID, in which case the receiver could not tell which of them a
+// batch was compressed with. This happens when dictionaries are trained
+// separately (e.g. for different tables, or by different versions of zstd)
+// with the same, or the default random, --dictID.
+func checkSnapshotDictionaryIDs(dicts []kvserverpb.SnapshotRequest_CompressionDictionary) error {
+	prefixByID := make(map[uint32]roachpb.Key, len(dicts))
+	for _, d := range dicts {
+		id, err := snapshotDictionaryID(d.Dictionary)
+		if err != nil {
+			return errors.Wrapf(err, "invalid dictionary for key prefix %s", d.KeyPrefix)
+		}
+		if other, ok := prefixByID[id]; ok {
+			return errors.Newf(
+				"the dictionaries for key prefixes %s and %s have the same ID %d",
+				other, d.KeyPrefix, id)
+		}
+		prefixByID[id] = d.KeyPrefix
+	}
+	return nil
+}
+
+// parseSnapshotCompressionDictionaries parses the value of
+// kv.snapshot.compression.dictionaries. The dictionaries are returned in
+// decreasing order of key prefix length, so that the first matching one is the
+// one with the longest prefix.
+func parseSnapshotCompressionDictionaries(
+	s string,
+) ([]kvserverpb.SnapshotRequest_CompressionDictionary, error) {
+	var dicts []kvserverpb.SnapshotRequest_CompressionDictionary
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		// NB: base64 padding uses '=', but the hex-encoded prefix can't.
+		prefixStr, dictStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, errors.Newf("invalid dictionary %q: expected <key prefix>=<dictionary>", entry)
+		}
+		prefix, err := hex.DecodeString(prefixStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key prefix %q", prefixStr)
+		}
+		dict, err := base64.StdEncoding.DecodeString(dictStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid dictionary for key prefix %q", prefixStr)
+		}
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderDict(dict))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid dictionary for key prefix %q", prefixStr)
+		}
+		_ = enc.Close()
+		dicts = append(dicts, kvserverpb.SnapshotRequest_CompressionDictionary{
+			KeyPrefix:  prefix,
+			Dictionary: dict,
+		})
+	}
+	if err := checkSnapshotDictionaryIDs(dicts); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(dicts, func(i, j int) bool {
+		return len(dicts[i].KeyPrefix) > len(dicts[j].KeyPrefix)
+	})
+	return dicts, nil
+}
+
+// setSnapshotCompression sets the codec and the dictionaries of the KV batches
+// of a snapshot in its header, unless the receiver may not support them.
+func setSnapshotCompression(
+	ctx context.Context, st *cluster.Settings, header *kvserverpb.SnapshotRequest_Header,
+) {
+	if !st.Version.IsActive(ctx, clusterversion.V24_2_SnapshotCompression) {
+		return
+	}
+	switch snapshotCompressionCodec(snapshotCompression.Get(&st.SV)) {
+	case snapshotCompressionOff:
+	case snapshotCompressionSnappy:
+		header.Compression = kvserverpb.SnapshotRequest_COMPRESSION_SNAPPY
+	default:
+		header.Compression = kvserverpb.SnapshotRequest_COMPRESSION_ZSTD
+		dicts, err := parseSnapshotCompressionDictionaries(snapshotCompressionDictionaries.Get(&st.SV))
+		if err != nil {
+			log.Warningf(ctx, "compressing snapshot without dictionaries: %v", err)
+			return
+		}
+		header.CompressionDictionaries = dicts
+	}
+}
+
+// snapshotZstdLevel returns the zstd level of the KV batches of snapshots.
+func snapshotZstdLevel(sv *settings.Values) zstd.EncoderLevel {
+	switch snapshotCompressionCodec(snapshotCompression.Get(sv)) {
+	case snapshotCompressionZstdFastest:
+		return zstd.SpeedFastest
+	case snapshotCompressionZstdBetter:
+		return zstd.SpeedBetterCompression
+	case snapshotCompressionZstdBest:
+		return zstd.SpeedBestCompression
+	default:
+		return zstd.SpeedDefault
+	}
+}
+
+// snapshotCompressor compresses the KV batches of an outgoing snapshot with
+// the codec and the dictionaries of its header.
+type snapshotCompressor struct {
+	compression kvserverpb.SnapshotRequest_Compression
+	// prefixes[i] is the key prefix of the batches compressed by encoders[i].
+	// The last encoder uses no dictionary, and compresses the batches matching
+	// none of the prefixes.
+	prefixes []roachpb.Key
+	encoders []*zstd.Encoder
+	buf      []byte
+}
+
+func newSnapshotCompressor(
+	sv *settings.Values, header *kvserverpb.SnapshotRequest_Header,
+) (*snapshotCompressor, error) {
+	c := &snapshotCompressor{compression: header.Compression}
+	switch header.Compression {
+	case kvserverpb.SnapshotRequest_COMPRESSION_NONE, kvserverpb.SnapshotRequest_COMPRESSION_SNAPPY:
+		return c, nil
+	case kvserverpb.SnapshotRequest_COMPRESSION_ZSTD:
+	default:
+		return nil, errors.AssertionFailedf("unknown snapshot compression %s", header.Compression)
+	}
+	level := snapshotZstdLevel(sv)
+	newEncoder := func(opts ...zstd.EOption) error {
+		enc, err := zstd.NewWriter(nil, append([]zstd.EOption{
+			zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1),
+		}, opts...)...)
+		if err != nil {
+			return err
+		}
+		c.encoders = append(c.encoders, enc)
+		return nil
+	}
+	for _, d := range header.CompressionDictionaries {
+		if err := newEncoder(zstd.WithEncoderDict(d.Dictionary)); err != nil {
+			c.close()
+			return nil, errors.Wrapf(err, "creating encoder for the dictionary of %s", d.KeyPrefix)
+		}
+		c.prefixes = append(c.prefixes, d.KeyPrefix)
+	}
+	if err := newEncoder(); err != nil {
+		c.close()
+		return nil, errors.Wrap(err, "creating encoder")
+	}
+	return c, nil
+}
+
+// compress returns the given batch repr, compressed. The result is only valid
+// until the next call.
+func (c *snapshotCompressor) compress(repr []byte) []byte {
+	switch c.compression {
+	case kvserverpb.SnapshotRequest_COMPRESSION_SNAPPY:
+		c.buf = snappy.Encode(c.buf[:cap(c.buf)], repr)
+		return c.buf
+	case kvserverpb.SnapshotRequest_COMPRESSION_ZSTD:
+		enc := c.encoders[snapshotDictionaryForBatch(c.prefixes, repr)]
+		c.buf = enc.EncodeAll(repr, c.buf[:0])
+		return c.buf
+	default:
+		return repr
+	}
+}
+
+func (c *snapshotCompressor) close() {
+	for _, enc := range c.encoders {
+		_ = enc.Close()
+	}
+	c.encoders = nil
+}
+
+// snapshotDictionaryForBatch returns the index of the first of the given key
+// prefixes that the first key of the batch starts with, or len(prefixes) if
+// there is none.
+func snapshotDictionaryForBatch(prefixes []roachpb.Key, repr []byte) int {
+	if len(prefixes) == 0 {
+		return 0
+	}
+	r, err := storage.NewBatchReader(repr)
+	if err != nil || !r.Next() {
+		return len(prefixes)
+	}
+	key, err := r.EngineKey()
+	if err != nil {
+		return len(prefixes)
+	}
+	for i, prefix := range prefixes {
+		if bytes.HasPrefix(key.Key, prefix) {
+			return i
+		}
+	}
+	return len(prefixes)
+}
+
+// snapshotDecompressor decompresses the KV batches of an incoming snapshot
+// with the codec and the dictionaries of its header.
+type snapshotDecompressor struct {
+	compression kvserverpb.SnapshotRequest_Compression
+	decoder     *zstd.Decoder
+	buf         []byte
+}
+
+func newSnapshotDecompressor(
+	header *kvserverpb.SnapshotRequest_Header,
+) (*snapshotDecompressor, error) {
+	d := &snapshotDecompressor{compression: header.Compression}
+	switch header.Compression {
+	case kvserverpb.SnapshotRequest_COMPRESSION_NONE, kvserverpb.SnapshotRequest_COMPRESSION_SNAPPY:
+	case kvserverpb.SnapshotRequest_COMPRESSION_ZSTD:
+		if err := checkSnapshotDictionaryIDs(header.CompressionDictionaries); err != nil {
+			return nil, err
+		}
+		dicts := make([][]byte, len(header.CompressionDictionaries))
+		for i := range header.CompressionDictionaries {
+			dicts[i] = header.CompressionDictionaries[i].Dictionary
+		}
+		dec, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderDicts(dicts...))
+		if err != nil {
+			return nil, errors.Wrap(err, "creating decoder")
+		}
+		d.decoder = dec
+	default:
+		return nil, errors.Errorf("unsupported snapshot compression %s", header.Compression)
+	}
+	return d, nil
+}
+
+// decompress returns the given compressed batch repr, decompressed. The result
+// is only valid until the next call.
+func (d *snapshotDecompressor) decompress(b []byte) ([]byte, error) {
+	var err error
+	switch d.compression {
+	case kvserverpb.SnapshotRequest_COMPRESSION_SNAPPY:
+		d.buf, err = snappy.Decode(d.buf[:cap(d.buf)], b)
+	case kvserverpb.SnapshotRequest_COMPRESSION_ZSTD:
+		d.buf, err = d.decoder.DecodeAll(b, d.buf[:0])
+	default:
+		return b, nil
+	}
+	return d.buf, err
+}
+
+func (d *snapshotDecompressor) close() {
+	if d.decoder != nil {
+		d.decoder.Close()
+		d.decoder = nil
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotCompression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+	b := eng.NewWriteBatch()
+	defer b.Close()
+	for i := 0; i < 1000; i++ {
+		key := storage.EngineKey{Key: roachpb.Key(fmt.Sprintf("key-%04d", i))}
+		require.NoError(t, b.PutEngineKey(key, bytes.Repeat([]byte("value"), 20)))
+	}
+	repr := b.Repr()
+
+	for _, codec := range []snapshotCompressionCodec{
+		snapshotCompressionOff,
+		snapshotCompressionSnappy,
+		snapshotCompressionZstdFastest,
+		snapshotCompressionZstdDefault,
+		snapshotCompressionZstdBetter,
+		snapshotCompressionZstdBest,
+	} {
+		st := cluster.MakeTestingClusterSettings()
+		snapshotCompression.Override(ctx, &st.SV, int64(codec))
+		t.Run(snapshotCompression.String(&st.SV), func(t *testing.T) {
+			var header kvserverpb.SnapshotRequest_Header
+			setSnapshotCompression(ctx, st, &header)
+			require.Equal(t, codec == snapshotCompressionOff,
+				header.Compression == kvserverpb.SnapshotRequest_COMPRESSION_NONE)
+
+			c, err := newSnapshotCompressor(&st.SV, &header)
+			require.NoError(t, err)
+			defer c.close()
+			d, err := newSnapshotDecompressor(&header)
+			require.NoError(t, err)
+			defer d.close()
+
+			// Compress the batch a few times, to exercise the reuse of buffers.
+			for i := 0; i < 3; i++ {
+				compressed := c.compress(repr)
+				if codec != snapshotCompressionOff {
+					require.Less(t, len(compressed), len(repr)/2)
+				}
+				decompressed, err := d.decompress(compressed)
+				require.NoError(t, err)
+				require.Equal(t, repr, decompressed)
+			}
+		})
+	}
+
+	// Snapshots aren't compressed before the receiver is guaranteed to support
+	// it.
+	st := cluster.MakeTestingClusterSettingsWithVersions(
+		clusterversion.V24_2_StmtDiagRedacted.Version(), clusterversion.MinSupported.Version(), true, /* initializeVersion */
+	)
+	snapshotCompression.Override(ctx, &st.SV, int64(snapshotCompressionZstdDefault))
+	var header kvserverpb.SnapshotRequest_Header
+	setSnapshotCompression(ctx, st, &header)
+	require.Equal(t, kvserverpb.SnapshotRequest_COMPRESSION_NONE, header.Compression)
+
+	// The receiver rejects codecs it doesn't know.
+	header.Compression = kvserverpb.SnapshotRequest_Compression(100)
+	_, err := newSnapshotDecompressor(&header)
+	require.ErrorContains(t, err, "unsupported snapshot compression")
+}
+
+func TestSnapshotCompressionDictionaries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	dicts, err := parseSnapshotCompressionDictionaries("")
+	require.NoError(t, err)
+	require.Empty(t, dicts)
+
+	for _, tc := range []struct {
+		value string
+		err   string
+	}{
+		{value: "f0", err: "expected <key prefix>=<dictionary>"},
+		{value: "zz=AAAA", err: "invalid key prefix"},
+		{value: "f0=not base64", err: "invalid dictionary"},
+		// Dictionaries have to be zstd dictionaries.
+		{value: "f0=AAAAAAAAAAA=", err: "invalid dictionary"},
+	} {
+		_, err := parseSnapshotCompressionDictionaries(tc.value)
+		require.ErrorContains(t, err, tc.err, "value %q", tc.value)
+	}
+
+	// The dictionaries must have distinct IDs, which identify them in the frame
+	// headers of the compressed batches. Only the header of the dictionaries is
+	// inspected, so they don't need to be valid beyond it.
+	dictWithID := func(id uint32) []byte {
+		dict := make([]byte, 16)
+		binary.LittleEndian.PutUint32(dict, zstdDictionaryMagic)
+		binary.LittleEndian.PutUint32(dict[4:], id)
+		return dict
+	}
+	header := kvserverpb.SnapshotRequest_Header{
+		Compression: kvserverpb.SnapshotRequest_COMPRESSION_ZSTD,
+		CompressionDictionaries: []kvserverpb.SnapshotRequest_CompressionDictionary{
+			{KeyPrefix: roachpb.Key("a"), Dictionary: dictWithID(1)},
+			{KeyPrefix: roachpb.Key("b"), Dictionary: dictWithID(2)},
+		},
+	}
+	require.NoError(t, checkSnapshotDictionaryIDs(header.CompressionDictionaries))
+	header.CompressionDictionaries[1].Dictionary = dictWithID(0)
+	require.ErrorContains(t, checkSnapshotDictionaryIDs(header.CompressionDictionaries), "no ID")
+	header.CompressionDictionaries[1].Dictionary = dictWithID(1)
+	require.ErrorContains(t, checkSnapshotDictionaryIDs(header.CompressionDictionaries), "same ID 1")
+	// The receiver rejects them too.
+	_, err = newSnapshotDecompressor(&header)
+	require.ErrorContains(t, err, "same ID 1")
+
+	// Batches are compressed with the dictionary of the first prefix matching
+	// their first key. The prefixes are sorted by decreasing length, so the
+	// longest one matches.
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+	batchWithFirstKey := func(key string) []byte {
+		b := eng.NewWriteBatch()
+		defer b.Close()
+		require.NoError(t, b.PutEngineKey(storage.EngineKey{Key: roachpb.Key(key)}, []byte("value")))
+		require.NoError(t, b.PutEngineKey(storage.EngineKey{Key: roachpb.Key("zzz")}, []byte("value")))
+		return append([]byte(nil), b.Repr()...)
+	}
+	prefixes := []roachpb.Key{roachpb.Key("abc"), roachpb.Key("ab"), roachpb.Key("b")}
+	require.Equal(t, 0, snapshotDictionaryForBatch(prefixes, batchWithFirstKey("abcd")))
+	require.Equal(t, 1, snapshotDictionaryForBatch(prefixes, batchWithFirstKey("abd")))
+	require.Equal(t, 2, snapshotDictionaryForBatch(prefixes, batchWithFirstKey("b")))
+	require.Equal(t, 3, snapshotDictionaryForBatch(prefixes, batchWithFirstKey("c")))
+	require.Equal(t, 0, snapshotDictionaryForBatch(nil, batchWithFirstKey("abc")))
+}
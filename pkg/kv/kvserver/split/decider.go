@@ -161,9 +161,44 @@ type Decider struct {
 
 		// Fields tracking logging / metrics around load-based splitter split key.
 		lastNoSplitKeyLoggingMetrics time.Time
+
+		// lastHotKey is the last time that no split key could be found because
+		// the load was concentrated on a single popular key, see HotKeyDetected.
+		lastHotKey time.Time
+
+		// lastDecision records the inputs of the last split key returned by
+		// MaybeSplitKey. It is retained across resets, for post-hoc analysis of
+		// the split.
+		lastDecision SplitDecision
 	}
 }
 
+// SplitDecision records the inputs of a load-based split decision, i.e. of a
+// split key suggested by the Decider.
+type SplitDecision struct {
+	// Time is the time at which the split key was suggested.
+	Time time.Time
+	// SplitKey is the suggested split key. It may not be a safe split key, see
+	// MaybeSplitKey.
+	SplitKey roachpb.Key
+	// Objective is the split objective that the load is measured in.
+	Objective SplitObjective
+	// Threshold is the load threshold above which the range was considered for
+	// splitting.
+	Threshold float64
+	// Last is the last measured load of the range.
+	Last float64
+	// Max is the maximum load of the range over the retention period, if MaxOk
+	// is true.
+	Max   float64
+	MaxOk bool
+	// PopularKeyFrequency is the fraction of the sampled spans which contained
+	// the most popular key.
+	PopularKeyFrequency float64
+	// SplitterState describes the samples that the split key was chosen from.
+	SplitterState string
+}
+
 // Init initializes a Decider (which is assumed to be zero). The signature allows
 // embedding the Decider into a larger struct outside of the scope of this package
 // without incurring a pointer reference. This is relevant since many Deciders
@@ -273,6 +308,7 @@ func (d *Decider) recordLocked(
 						log.KvDistribution.VInfof(ctx, 3, "splitter_state=%v", (*lockedDecider)(d))
 						if popularKeyFrequency >= splitKeyThreshold {
 							d.loadSplitterMetrics.PopularKeyCount.Inc(1)
+							d.mu.lastHotKey = now
 						}
 						d.loadSplitterMetrics.NoSplitKeyCount.Inc(1)
 					}
@@ -351,10 +387,45 @@ func (d *Decider) MaybeSplitKey(ctx context.Context, now time.Time) roachpb.Key
 		// replica.adminSplitWithDescriptor for how split keys are handled when we
 		// aren't certain the provided key is safe.
 		key = d.mu.splitFinder.Key()
+		if key != nil {
+			maxStat, maxOk := d.mu.maxStat.max(now, d.config.StatRetention())
+			d.mu.lastDecision = SplitDecision{
+				Time:                now,
+				SplitKey:            key,
+				Objective:           d.mu.objective,
+				Threshold:           d.config.StatThreshold(d.mu.objective),
+				Last:                d.mu.lastStatVal,
+				Max:                 maxStat,
+				MaxOk:               maxOk,
+				PopularKeyFrequency: d.mu.splitFinder.PopularKeyFrequency(),
+				SplitterState:       d.mu.splitFinder.String(),
+			}
+		}
 	}
 	return key
 }
 
+// LastSplitDecision returns the inputs of the last split key returned by
+// MaybeSplitKey, if any. It is not discarded by Reset, such that the decision
+// which led to the last load-based split of the range can be inspected after
+// the fact.
+func (d *Decider) LastSplitDecision() (SplitDecision, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.mu.lastDecision, !d.mu.lastDecision.Time.IsZero()
+}
+
+// HotKeyDetected returns whether the load on the range was found to be
+// concentrated on a single key, which splitting cannot spread, within the last
+// retention period.
+func (d *Decider) HotKeyDetected(now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return !d.mu.lastHotKey.IsZero() && now.Sub(d.mu.lastHotKey) < d.config.StatRetention()
+}
+
 // Reset deactivates any current attempt at determining a split key. The method
 // also discards any historical stat tracking information.
 func (d *Decider) Reset(now time.Time) {
@@ -373,6 +444,22 @@ func (d *Decider) resetLocked(now time.Time) {
 	d.mu.suggestionsMade = 0
 	d.mu.lastSplitSuggestion = time.Time{}
 	d.mu.lastNoSplitKeyLoggingMetrics = time.Time{}
+	d.mu.lastHotKey = time.Time{}
+}
+
+// SeedMax seeds the Decider's historical stat value tracker with the given
+// maximum, such that it is treated as having been observed over a full
+// retention period. It is used to warm start the Decider following a restart.
+// The seed is ignored if it was measured for a split objective other than the
+// Decider's current one.
+func (d *Decider) SeedMax(now time.Time, obj SplitObjective, maxStat float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if obj != d.mu.objective {
+		return
+	}
+	d.mu.maxStat.seed(now, d.config.StatRetention(), maxStat)
 }
 
 // SetSplitObjective sets the decider split objective to the given value and
@@ -462,6 +549,14 @@ func (t *maxStatTracker) reset(now time.Time, minRetention time.Duration) {
 	t.minRetention = minRetention
 }
 
+// seed resets the tracker such that it considers itself to have been recording
+// for a full minRetention period, with the given qps as the maximum observed.
+func (t *maxStatTracker) seed(now time.Time, minRetention time.Duration, qps float64) {
+	t.reset(now, minRetention)
+	t.lastReset = now.Add(-minRetention)
+	t.windows[t.curIdx] = qps
+}
+
 func (t *maxStatTracker) maybeReset(now time.Time, minRetention time.Duration) {
 	// If the retention period changes, simply reset the entire tracker. Merging
 	// or splitting windows would be a difficult task and could lead to samples
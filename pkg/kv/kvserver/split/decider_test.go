@@ -105,6 +105,9 @@ func TestDecider(t *testing.T) {
 		assert.Equal(t, expOK, ok)
 	}
 
+	_, ok := d.LastSplitDecision()
+	assert.False(t, ok)
+
 	assert.Equal(t, false, d.Record(context.Background(), ms(100), ld(1), nil))
 	assertStat(100, 0)
 	assertMaxStat(100, 0, false)
@@ -167,6 +170,18 @@ func TestDecider(t *testing.T) {
 
 	assert.Equal(t, roachpb.Key("z"), d.MaybeSplitKey(context.Background(), ms(tick)))
 
+	// The inputs of the decision are recorded.
+	decision, ok := d.LastSplitDecision()
+	require.True(t, ok)
+	assert.Equal(t, ms(tick), decision.Time)
+	assert.Equal(t, roachpb.Key("z"), decision.SplitKey)
+	assert.Equal(t, SplitQPS, decision.Objective)
+	assert.Equal(t, float64(10), decision.Threshold)
+	assert.GreaterOrEqual(t, decision.Last, decision.Threshold)
+	assert.True(t, decision.MaxOk)
+	assert.GreaterOrEqual(t, decision.Max, decision.Last)
+	assert.NotEmpty(t, decision.SplitterState)
+
 	// We were told to split, but won't be told to split again for some time
 	// to avoid busy-looping on split attempts.
 	for i := 0; i <= int(minSplitSuggestionInterval/time.Second); i++ {
@@ -233,6 +248,11 @@ func TestDecider(t *testing.T) {
 	d.Reset(ms(tick))
 	assert.Nil(t, d.MaybeSplitKey(context.Background(), ms(tick)))
 	assert.Nil(t, d.mu.splitFinder)
+
+	// The last decision survives the reset.
+	decision, ok = d.LastSplitDecision()
+	require.True(t, ok)
+	assert.Equal(t, roachpb.Key("z"), decision.SplitKey)
 }
 
 func TestDecider_MaxStat(t *testing.T) {
@@ -379,6 +399,37 @@ func TestMaxStatTracker(t *testing.T) {
 	require.Equal(t, 1, mt.curIdx)
 }
 
+func TestMaxStatTrackerSeed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tick := 100
+	minRetention := time.Second
+
+	var mt maxStatTracker
+	mt.seed(ms(tick), minRetention, 42)
+
+	// The seeded maximum is reported immediately.
+	stat, ok := mt.max(ms(tick), minRetention)
+	require.Equal(t, 42.0, stat)
+	require.Equal(t, true, ok)
+
+	// Lower samples don't replace the seeded maximum within the retention
+	// period.
+	for i := 0; i < 10; i++ {
+		tick += 50
+		mt.record(ms(tick), minRetention, float64(10+i))
+	}
+	stat, ok = mt.max(ms(tick), minRetention)
+	require.Equal(t, 42.0, stat)
+	require.Equal(t, true, ok)
+
+	// Once the seeded window has rotated out, the recorded samples remain.
+	tick += 700
+	stat, ok = mt.max(ms(tick), minRetention)
+	require.Equal(t, 19.0, stat)
+	require.Equal(t, true, ok)
+}
+
 func TestDeciderMetrics(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	rand := rand.New(rand.NewSource(11))
@@ -411,6 +462,11 @@ func TestDeciderMetrics(t *testing.T) {
 
 	assert.Equal(t, dPopular.loadSplitterMetrics.PopularKeyCount.Count(), int64(2))
 	assert.Equal(t, dPopular.loadSplitterMetrics.NoSplitKeyCount.Count(), int64(2))
+	// The hot key is detected for a retention period.
+	lastHotKey := dPopular.mu.lastHotKey
+	assert.False(t, lastHotKey.IsZero())
+	assert.True(t, dPopular.HotKeyDetected(lastHotKey.Add(loadSplitConfig.statRetention/2)))
+	assert.False(t, dPopular.HotKeyDetected(lastHotKey.Add(loadSplitConfig.statRetention)))
 
 	// No split key, not popular key
 	var dNotPopular Decider
@@ -432,6 +488,7 @@ func TestDeciderMetrics(t *testing.T) {
 
 	assert.Equal(t, dNotPopular.loadSplitterMetrics.PopularKeyCount.Count(), int64(0))
 	assert.Equal(t, dNotPopular.loadSplitterMetrics.NoSplitKeyCount.Count(), int64(2))
+	assert.True(t, dNotPopular.mu.lastHotKey.IsZero())
 
 	// No split key, all insufficient counters
 	var dAllInsufficientCounters Decider
@@ -287,6 +287,18 @@ var LeaseTransferPerIterationTimeout = settings.RegisterDurationSetting(
 	settings.WithPublic,
 )
 
+// DrainLeaseTransferRate paces the lease and raft leadership transfers of
+// draining stores.
+var DrainLeaseTransferRate = settings.RegisterFloatSetting(
+	settings.SystemOnly,
+	"server.shutdown.lease_transfer_rate",
+	"the maximum number of range lease and raft leadership transfers per second "+
+		"performed by each store of a draining node; 0 disables pacing",
+	200,
+	settings.NonNegativeFloat,
+	settings.WithPublic,
+)
+
 // exportRequestsLimit is the number of Export requests that can run at once.
 // Each extracts data from Pebble to an in-memory SST and returns it to the
 // caller. In order to not exhaust the disk or memory, or saturate the network,
@@ -870,6 +882,7 @@ type Store struct {
 	recoveryMgr         txnrecovery.Manager
 	syncWaiter          *logstore.SyncWaiterLoop
 	raftEntryCache      *raftentry.Cache
+	raftLogPrefetchSem  *quotapool.IntPool
 	limiters            batcheval.Limiters
 	txnWaitMetrics      *txnwait.Metrics
 	sstSnapshotStorage  SSTSnapshotStorage
@@ -909,6 +922,12 @@ type Store struct {
 	// the time of its creation was riddled with deadlock (but that situation
 	// has likely improved).
 	draining atomic.Bool
+	// drainTransfers count the transfers attempted since the store started
+	// draining, for progress reporting.
+	drainTransfers struct {
+		leases          atomic.Int64
+		raftLeaderships atomic.Int64
+	}
 
 	// Locking notes: To avoid deadlocks, the following lock order must be
 	// obeyed: baseQueue.mu < Replica.raftMu < Replica.readOnlyCmdMu < Store.mu
@@ -1076,6 +1095,16 @@ type Store struct {
 	// all range leases it held due to becoming IO overloaded.
 	lastIOOverloadLeaseShed atomic.Value
 
+	// inactiveKeyRewrite tracks the rewrite of the sstables encrypted with
+	// inactive data keys, see StartInactiveKeyRewrite.
+	inactiveKeyRewrite struct {
+		syncutil.Mutex
+		running bool
+		// rewrittenBytes is the number of bytes of sstables rewritten by the
+		// last completed rewrite.
+		rewrittenBytes int64
+	}
+
 	counts struct {
 		// Number of placeholders removed due to error. Not a good fit for meaningful
 		// metrics, as snapshots to initialized ranges don't get a placeholder.
@@ -1139,6 +1168,11 @@ type StoreConfig struct {
 	// maintenance queue to dispatch individual maintenance tasks.
 	TimeSeriesDataStore TimeSeriesDataStore
 
+	// TenantSettingOverrides provides the setting overrides of virtual
+	// clusters, which the allocator uses to weigh the cross-region network cost
+	// of the ranges of each tenant with that tenant's cost model. Can be nil.
+	TenantSettingOverrides allocatorimpl.TenantSettingOverrides
+
 	// CoalescedHeartbeatsInterval is the interval for which heartbeat messages
 	// are queued and then sent as a single coalesced heartbeat; it is a
 	// fraction of the RaftTickInterval so that heartbeats don't get delayed by
@@ -1480,6 +1514,9 @@ func NewStore(
 			}, cfg.TestingKnobs.AllocatorKnobs,
 		)
 	}
+	if cfg.TenantSettingOverrides != nil {
+		s.allocator.SetTenantSettingOverrides(cfg.TenantSettingOverrides)
+	}
 	if s.metrics != nil {
 		s.metrics.registry.AddMetricStruct(s.allocator.Metrics.LoadBasedLeaseTransferMetrics)
 		s.metrics.registry.AddMetricStruct(s.allocator.Metrics.LoadBasedReplicaRebalanceMetrics)
@@ -1505,7 +1542,7 @@ func NewStore(
 
 	// NB: buffer up to RaftElectionTimeoutTicks in Raft scheduler to avoid
 	// unnecessary elections when ticks are temporarily delayed and piled up.
-	s.scheduler = newRaftScheduler(cfg.AmbientCtx, s.metrics, s,
+	s.scheduler = newRaftScheduler(cfg.AmbientCtx, cfg.Settings, s.metrics, s,
 		cfg.RaftSchedulerConcurrency, cfg.RaftSchedulerShardSize, cfg.RaftSchedulerConcurrencyPriority,
 		cfg.RaftElectionTimeoutTicks)
 
@@ -1513,6 +1550,7 @@ func NewStore(
 
 	s.raftEntryCache = raftentry.NewCache(cfg.RaftEntryCacheSize)
 	s.metrics.registry.AddMetricStruct(s.raftEntryCache.Metrics())
+	s.raftLogPrefetchSem = quotapool.NewIntPool("raft-log-prefetch", raftLogPrefetchConcurrency)
 
 	s.coalescedMu.Lock()
 	s.coalescedMu.heartbeats = map[roachpb.StoreIdent][]kvserverpb.RaftHeartbeat{}
@@ -1745,7 +1783,10 @@ func (s *Store) AnnotateCtx(ctx context.Context) context.Context {
 // been done by the time this call returns. See the explanation in
 // pkg/server/drain.go for details.
 func (s *Store) SetDraining(drain bool, reporter func(int, redact.SafeString), verbose bool) {
-	s.draining.Store(drain)
+	if wasDraining := s.draining.Swap(drain); drain && !wasDraining {
+		s.drainTransfers.leases.Store(0)
+		s.drainTransfers.raftLeaderships.Store(0)
+	}
 	if !drain {
 		return
 	}
@@ -1774,6 +1815,14 @@ func (s *Store) SetDraining(drain bool, reporter func(int, redact.SafeString), v
 
 	var wg sync.WaitGroup
 
+	// Pace the transfers, so that draining a store with many leases doesn't
+	// overwhelm the rest of the cluster.
+	transferLimit, transferBurst := quotapool.Inf(), int64(1)
+	if rate := DrainLeaseTransferRate.Get(&s.cfg.Settings.SV); rate > 0 {
+		transferLimit, transferBurst = quotapool.Limit(rate), int64(math.Max(1, rate))
+	}
+	transferLimiter := quotapool.NewRateLimiter("Store.SetDraining", transferLimit, transferBurst)
+
 	transferAllAway := func(transferCtx context.Context) int {
 		// Limit the number of concurrent lease transfers.
 		const leaseTransferConcurrency = 100
@@ -1784,7 +1833,7 @@ func (s *Store) SetDraining(drain bool, reporter func(int, redact.SafeString), v
 		// retry loop until there are no leases left (ignoring single-replica
 		// ranges).
 		var numTransfersAttempted int32
-		newStoreReplicaVisitor(s).Visit(func(r *Replica) bool {
+		drainReplica := func(r *Replica) bool {
 			//
 			// We need to be careful about the case where the ctx has been canceled
 			// prior to the call to (*Stopper).RunAsyncTaskEx(). In that case,
@@ -1861,6 +1910,16 @@ func (s *Store) SetDraining(drain bool, reporter func(int, redact.SafeString), v
 						drainingLeaseStatus.State == kvserverpb.LeaseState_VALID
 
 					if !needsLeaseTransfer && !needsLeaseReacquisition {
+						// Without a valid lease to transfer, the raft leadership
+						// doesn't follow the lease and needs to be moved manually.
+						// The transfer is accounted for after the fact, since most
+						// replicas aren't the leader.
+						if !leaseLocallyOwned && transferTargetAvailable &&
+							r.maybeShedRaftLeadershipForDrain(ctx, drainingLeaseStatus) {
+							s.drainTransfers.raftLeaderships.Add(1)
+							_ = transferLimiter.WaitN(ctx, 1)
+							return
+						}
 						// Skip this replica.
 						atomic.AddInt32(&numTransfersAttempted, -1)
 						return
@@ -1894,11 +1953,9 @@ func (s *Store) SetDraining(drain bool, reporter func(int, redact.SafeString), v
 						// The lease reacquisition succeeded. Proceed to the lease transfer.
 					}
 
-					// Note that this code doesn't deal with transferring the Raft
-					// leadership. Leadership tries to follow the lease, so when leases
-					// are transferred, leadership will be transferred too. For ranges
-					// without leases we probably should try to move the leadership
-					// manually to a non-draining replica.
+					// Note that the Raft leadership tries to follow the lease, so when
+					// leases are transferred, leadership will be transferred too. For
+					// ranges without a valid lease, leadership is moved manually above.
 
 					desc, conf := r.DescAndSpanConfig()
 
@@ -1907,6 +1964,10 @@ func (s *Store) SetDraining(drain bool, reporter func(int, redact.SafeString), v
 						log.Infof(ctx, "attempting to transfer lease %v for range %s", drainingLeaseStatus.Lease, desc)
 					}
 
+					if err := transferLimiter.WaitN(ctx, 1); err != nil {
+						return
+					}
+					s.drainTransfers.leases.Add(1)
 					start := timeutil.Now()
 					transferStatus, err := s.replicateQueue.shedLease(
 						ctx,
@@ -1947,8 +2008,19 @@ func (s *Store) SetDraining(drain bool, reporter func(int, redact.SafeString), v
 				return false
 			}
 			return true
-		})
-		wg.Wait()
+		}
+
+		// Shed the leases of the system ranges first, since their
+		// unavailability affects the whole cluster.
+		systemRepls, otherRepls := s.replicasByDrainPriority()
+		for _, repls := range [][]*Replica{systemRepls, otherRepls} {
+			for _, r := range repls {
+				if !drainReplica(r) {
+					break
+				}
+			}
+			wg.Wait()
+		}
 		return int(numTransfersAttempted)
 	}
 
@@ -2212,6 +2284,12 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	}
 	log.Infof(ctx, "initialized %d/%d replicas", len(repls), len(repls))
 
+	// Warm start the replicas' load statistics from before the restart, so
+	// that the allocator and load-based splitting don't have to re-learn them.
+	if err := s.warmStartReplicaLoad(ctx); err != nil {
+		log.Warningf(ctx, "unable to warm start replica load: %v", err)
+	}
+
 	// Register a callback to unquiesce any ranges with replicas on a
 	// node transitioning from non-live to live.
 	if s.cfg.NodeLiveness != nil {
@@ -2280,6 +2358,12 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 
 	s.startRangefeedTxnPushNotifier(ctx)
 
+	s.startLoadSnapshotPersister(ctx)
+
+	s.startRaftEntryCacheSizer(ctx)
+
+	s.startSSTScrubber(ctx)
+
 	if s.replicateQueue != nil {
 		s.storeRebalancer = NewStoreRebalancer(
 			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings, s.rebalanceObjManager)
@@ -2940,6 +3024,77 @@ func (s *Store) TestingKnobs() *StoreTestingKnobs { return &s.cfg.TestingKnobs }
 // IsDraining accessor.
 func (s *Store) IsDraining() bool { return s.draining.Load() }
 
+// drainSystemRangesEnd is the key that ends the system ranges, whose leases
+// are shed first when draining.
+var drainSystemRangesEnd = roachpb.RKey(keys.SystemSQLCodec.TablePrefix(keys.MaxReservedDescID + 1))
+
+// isSystemRangeForDrain returns whether the range starts within the system
+// ranges, which includes the meta, liveness and system table ranges.
+func isSystemRangeForDrain(desc *roachpb.RangeDescriptor) bool {
+	return desc.StartKey.Less(drainSystemRangesEnd)
+}
+
+// replicasByDrainPriority returns the replicas of the store, split into those
+// of the system ranges and the others.
+func (s *Store) replicasByDrainPriority() (system, other []*Replica) {
+	newStoreReplicaVisitor(s).Visit(func(r *Replica) bool {
+		if isSystemRangeForDrain(r.Desc()) {
+			system = append(system, r)
+		} else {
+			other = append(other, r)
+		}
+		return true
+	})
+	return system, other
+}
+
+// StoreDrainProgress describes the progress of the drain of a store.
+type StoreDrainProgress struct {
+	// SystemLeases is the number of valid leases of system ranges still held
+	// by the store.
+	SystemLeases int
+	// Leases is the number of valid leases still held by the store, including
+	// the SystemLeases.
+	Leases int
+	// RaftLeaderships is the number of raft leaderships still held by the
+	// store.
+	RaftLeaderships int
+	// LeaseTransfers and RaftLeadershipTransfers are the number of transfers
+	// attempted since the store started draining.
+	LeaseTransfers          int64
+	RaftLeadershipTransfers int64
+}
+
+// DrainProgress returns the progress of the drain of the store. Ranges with a
+// single voter are ignored, since their leases and leaderships can't be moved
+// away.
+func (s *Store) DrainProgress(ctx context.Context) StoreDrainProgress {
+	progress := StoreDrainProgress{
+		LeaseTransfers:          s.drainTransfers.leases.Load(),
+		RaftLeadershipTransfers: s.drainTransfers.raftLeaderships.Load(),
+	}
+	now := s.Clock().NowAsClockTimestamp()
+	newStoreReplicaVisitor(s).Visit(func(r *Replica) bool {
+		desc := r.Desc()
+		if len(desc.Replicas().VoterDescriptors()) <= 1 {
+			return true
+		}
+		if r.OwnsValidLease(ctx, now) {
+			progress.Leases++
+			if isSystemRangeForDrain(desc) {
+				progress.SystemLeases++
+			}
+		}
+		r.mu.RLock()
+		if r.isRaftLeaderRLocked() {
+			progress.RaftLeaderships++
+		}
+		r.mu.RUnlock()
+		return true
+	})
+	return progress
+}
+
 // AllocateRangeID allocates a new RangeID from the cluster-wide RangeID allocator.
 func (s *Store) AllocateRangeID(ctx context.Context) (roachpb.RangeID, error) {
 	id, err := s.rangeIDAlloc.Allocate(ctx)
@@ -3177,6 +3332,11 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		ioOverload                float64
 		pendingRaftProposalCount  int64
 		slowRaftProposalCount     int64
+		quotaPoolWaiters          int64
+		quotaPoolLongestWaitNanos int64
+		applyLagEntries           int64
+		oldestUnappliedAgeNanos   int64
+		hotKeyRangeCount          int64
 
 		locks                          int64
 		totalLockHoldDurationNanos     int64
@@ -3256,7 +3416,18 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		pausedFollowerCount += metrics.PausedFollowerCount
 		pendingRaftProposalCount += metrics.PendingRaftProposalCount
 		slowRaftProposalCount += metrics.SlowRaftProposalCount
+		quotaPoolWaiters += metrics.QuotaPoolWaiters
+		if w := metrics.QuotaPoolLongestWaitNanos; w > quotaPoolLongestWaitNanos {
+			quotaPoolLongestWaitNanos = w
+		}
+		applyLagEntries += metrics.ApplyLagEntries
+		if a := metrics.OldestUnappliedEntryAgeNanos; a > oldestUnappliedAgeNanos {
+			oldestUnappliedAgeNanos = a
+		}
 		behindCount += metrics.BehindCount
+		if rep.updateHotKeyProtection(ctx) {
+			hotKeyRangeCount++
+		}
 		loadStats := rep.loadStats.Stats()
 		averageQueriesPerSecond += loadStats.QueriesPerSecond
 		averageRequestsPerSecond += loadStats.RequestsPerSecond
@@ -3319,15 +3490,24 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	s.metrics.IOOverload.Update(ioOverload)
 	s.metrics.RaftCommandsPending.Update(pendingRaftProposalCount)
 	s.metrics.SlowRaftRequests.Update(slowRaftProposalCount)
+	s.metrics.RaftQuotaPoolWaiters.Update(quotaPoolWaiters)
+	s.metrics.RaftQuotaPoolLongestWait.Update(quotaPoolLongestWaitNanos)
+	s.metrics.RaftApplyLagEntries.Update(applyLagEntries)
+	s.metrics.RaftApplyOldestUnappliedAge.Update(oldestUnappliedAgeNanos)
+	s.metrics.HotKeyRanges.Update(hotKeyRangeCount)
 
 	var averageLockHoldDurationNanos int64
 	var averageLockWaitDurationNanos int64
+	var averageLockWaitQueueWaitersForLock int64
 	if locks > 0 {
 		averageLockHoldDurationNanos = totalLockHoldDurationNanos / locks
 	}
 	if lockWaitQueueWaiters > 0 {
 		averageLockWaitDurationNanos = totalLockWaitDurationNanos / lockWaitQueueWaiters
 	}
+	if locksWithWaitQueues > 0 {
+		averageLockWaitQueueWaitersForLock = lockWaitQueueWaiters / locksWithWaitQueues
+	}
 
 	s.metrics.Locks.Update(locks)
 	s.metrics.AverageLockHoldDurationNanos.Update(averageLockHoldDurationNanos)
@@ -3337,6 +3517,7 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	s.metrics.AverageLockWaitDurationNanos.Update(averageLockWaitDurationNanos)
 	s.metrics.MaxLockWaitDurationNanos.Update(maxLockWaitDurationNanos)
 	s.metrics.MaxLockWaitQueueWaitersForLock.Update(maxLockWaitQueueWaitersForLock)
+	s.metrics.AvgLockWaitQueueWaitersForLock.Update(averageLockWaitQueueWaitersForLock)
 
 	if !minMaxClosedTS.IsEmpty() {
 		nanos := timeutil.Since(minMaxClosedTS.GoTime()).Nanoseconds()
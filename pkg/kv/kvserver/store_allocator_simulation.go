@@ -0,0 +1,270 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocatorimpl"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/plan"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/storepool"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/raft"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// SimulatedReplicationChange is a replication change which the replicate
+// queue would make to a range, as found by AllocatorSimulateRange.
+type SimulatedReplicationChange struct {
+	Action  allocatorimpl.AllocatorAction
+	Changes kvpb.ReplicationChanges
+	Details string
+}
+
+// AllocatorSimulateRange runs the replica planner used by the replicate queue
+// against the given range, as though the range had the given span config, and
+// applies the planned replication changes to a copy of its descriptor until no
+// further change is planned or maxSteps changes were planned. It returns the
+// changes, in order, and the descriptor resulting from them. Nothing is
+// changed in the cluster.
+//
+// The changes are planned against the given simulated store pool, to which
+// each planned replica addition and removal is applied, so that a simulation
+// spanning several ranges accounts for the changes planned for the previous
+// ones. If the store pool is nil, a simulated store pool on top of the store's
+// configured store pool is used for this range only. The simulated pool needs
+// the logical bytes of the range to account for them; they are taken from the
+// range's replica on the store, if any, or else from logicalBytesFn, which is
+// called once the first change is planned and may be nil.
+//
+// If the planner comes back to a placement of the replicas seen before, the
+// changes made since are a no-op cycle, e.g. a replica rebalanced back and
+// forth, so they are dropped, their effect on the store pool is reverted, and
+// the simulation stops. In particular, no change is returned for a range whose
+// changes end at its original placement.
+//
+// The simulation doesn't model leases: the replica running the simulation is
+// assumed not to hold the lease, so that replicas may be removed from any
+// store, and lease transfers are never planned. If the planner returns an
+// error, e.g. because no store satisfies the constraints of the config, it is
+// returned along with the changes planned until then.
+func (s *Store) AllocatorSimulateRange(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	conf *roachpb.SpanConfig,
+	storePool *storepool.SimulatedStorePool,
+	maxSteps int,
+	logicalBytesFn func() int64,
+) ([]SimulatedReplicationChange, *roachpb.RangeDescriptor, error) {
+	if storePool == nil {
+		storePool = storepool.NewSimulatedStorePool(s.cfg.StorePool)
+	}
+	planner := plan.NewReplicaPlanner(s.allocator, storePool, plan.ReplicaPlannerTestingKnobs{
+		// The simulated replica is never the raft leader, and all the replicas
+		// are assumed to be up-to-date.
+		AllowVoterRemovalWhenNotLeader: true,
+	})
+
+	simDesc := *desc
+	simDesc.InternalReplicas = append([]roachpb.ReplicaDescriptor(nil), desc.InternalReplicas...)
+	repl := &simulatedReplica{desc: &simDesc}
+	usageKnown := false
+	if r := s.GetReplicaIfExists(desc.RangeID); r != nil {
+		repl.usage = r.RangeUsageInfo()
+		usageKnown = true
+	}
+
+	// placements contains the replicas of the range before each change, and
+	// seen the index in placements of each placement of the replicas.
+	placements := [][]roachpb.ReplicaDescriptor{
+		append([]roachpb.ReplicaDescriptor(nil), simDesc.InternalReplicas...),
+	}
+	seen := map[string]int{simulatedPlacement(&simDesc): 0}
+	var changes []SimulatedReplicationChange
+	for len(changes) < maxSteps {
+		change, err := planner.PlanOneChange(
+			ctx, repl, &simDesc, conf, plan.PlannerOptions{CanTransferLease: false},
+		)
+		if err != nil {
+			return changes, &simDesc, err
+		}
+		op, ok := change.Op.(plan.AllocationChangeReplicasOp)
+		if !ok {
+			// Either no change is needed, or the range is in the middle of a
+			// replication change, which the simulation doesn't finalize.
+			break
+		}
+		if !usageKnown {
+			if logicalBytesFn != nil {
+				repl.usage.LogicalBytes = logicalBytesFn()
+			}
+			usageKnown = true
+		}
+		if err := applySimulatedReplicationChanges(&simDesc, op.Chgs); err != nil {
+			return changes, &simDesc, err
+		}
+		updateSimulatedStorePool(storePool, op.Chgs, repl.usage, false /* revert */)
+		changes = append(changes, SimulatedReplicationChange{
+			Action:  change.Action,
+			Changes: op.Chgs,
+			Details: op.Details,
+		})
+		log.VEventf(ctx, 2, "simulated %s: %v", change.Action, op.Chgs)
+		placement := simulatedPlacement(&simDesc)
+		if i, ok := seen[placement]; ok {
+			for j := len(changes) - 1; j >= i; j-- {
+				updateSimulatedStorePool(storePool, changes[j].Changes, repl.usage, true /* revert */)
+			}
+			log.VEventf(ctx, 2, "dropping %d simulated changes back to a previous placement", len(changes)-i)
+			changes = changes[:i]
+			simDesc.InternalReplicas = placements[i]
+			break
+		}
+		seen[placement] = len(placements)
+		placements = append(placements,
+			append([]roachpb.ReplicaDescriptor(nil), simDesc.InternalReplicas...))
+	}
+	return changes, &simDesc, nil
+}
+
+// updateSimulatedStorePool applies the given replication changes, or reverts
+// them, to the capacity of the stores in the simulated store pool.
+func updateSimulatedStorePool(
+	storePool *storepool.SimulatedStorePool,
+	chgs kvpb.ReplicationChanges,
+	usage allocator.RangeUsageInfo,
+	revert bool,
+) {
+	for _, chg := range chgs {
+		changeType := chg.ChangeType
+		if revert {
+			switch changeType {
+			case roachpb.ADD_VOTER:
+				changeType = roachpb.REMOVE_VOTER
+			case roachpb.ADD_NON_VOTER:
+				changeType = roachpb.REMOVE_NON_VOTER
+			case roachpb.REMOVE_VOTER:
+				changeType = roachpb.ADD_VOTER
+			case roachpb.REMOVE_NON_VOTER:
+				changeType = roachpb.ADD_NON_VOTER
+			}
+		}
+		storePool.UpdateLocalStoreAfterRebalance(chg.Target.StoreID, usage, changeType)
+	}
+}
+
+// applySimulatedReplicationChanges applies the given changes to the
+// descriptor. Removals are applied first, so that a replica whose type changes
+// is replaced by one of the new type.
+func applySimulatedReplicationChanges(
+	desc *roachpb.RangeDescriptor, chgs kvpb.ReplicationChanges,
+) error {
+	for _, chg := range chgs {
+		if chg.ChangeType != roachpb.REMOVE_VOTER && chg.ChangeType != roachpb.REMOVE_NON_VOTER {
+			continue
+		}
+		if _, ok := desc.RemoveReplica(chg.Target.NodeID, chg.Target.StoreID); !ok {
+			return errors.AssertionFailedf("no replica to remove on %s in %s", chg.Target, desc)
+		}
+	}
+	for _, chg := range chgs {
+		switch chg.ChangeType {
+		case roachpb.ADD_VOTER:
+			desc.AddReplica(chg.Target.NodeID, chg.Target.StoreID, roachpb.VOTER_FULL)
+		case roachpb.ADD_NON_VOTER:
+			desc.AddReplica(chg.Target.NodeID, chg.Target.StoreID, roachpb.NON_VOTER)
+		}
+	}
+	return nil
+}
+
+// simulatedPlacement returns a key identifying the stores of the replicas of
+// the given range, and their types.
+func simulatedPlacement(desc *roachpb.RangeDescriptor) string {
+	repls := append([]roachpb.ReplicaDescriptor(nil), desc.InternalReplicas...)
+	sort.Slice(repls, func(i, j int) bool {
+		return repls[i].StoreID < repls[j].StoreID
+	})
+	var buf strings.Builder
+	for _, r := range repls {
+		fmt.Fprintf(&buf, "s%d:%s,", r.StoreID, r.Type)
+	}
+	return buf.String()
+}
+
+// simulatedReplica implements the plan.AllocatorReplica interface for a range
+// simulated by AllocatorSimulateRange.
+type simulatedReplica struct {
+	desc  *roachpb.RangeDescriptor
+	usage allocator.RangeUsageInfo
+}
+
+var _ plan.AllocatorReplica = &simulatedReplica{}
+
+func (sr *simulatedReplica) HasCorrectLeaseType(roachpb.Lease) bool {
+	return true
+}
+
+func (sr *simulatedReplica) LeaseStatusAt(
+	context.Context, hlc.ClockTimestamp,
+) kvserverpb.LeaseStatus {
+	return kvserverpb.LeaseStatus{}
+}
+
+func (sr *simulatedReplica) LeaseViolatesPreferences(
+	context.Context, *roachpb.SpanConfig,
+) bool {
+	return false
+}
+
+func (sr *simulatedReplica) OwnsValidLease(context.Context, hlc.ClockTimestamp) bool {
+	return false
+}
+
+func (sr *simulatedReplica) RangeUsageInfo() allocator.RangeUsageInfo {
+	return sr.usage
+}
+
+func (sr *simulatedReplica) RaftStatus() *raft.Status {
+	return nil
+}
+
+func (sr *simulatedReplica) GetFirstIndex() kvpb.RaftIndex {
+	return 0
+}
+
+// LastReplicaAdded returns no replica, so that the replicas added by the
+// simulation can be removed by a later step.
+func (sr *simulatedReplica) LastReplicaAdded() (roachpb.ReplicaID, time.Time) {
+	return 0, time.Time{}
+}
+
+// StoreID returns zero, since the simulated replica isn't on any store.
+func (sr *simulatedReplica) StoreID() roachpb.StoreID {
+	return 0
+}
+
+func (sr *simulatedReplica) GetRangeID() roachpb.RangeID {
+	return sr.desc.RangeID
+}
+
+func (sr *simulatedReplica) FollowerCatchUpETA(roachpb.ReplicaID) (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+)
+
+// errInactiveKeyRewriteRunning is returned by StartInactiveKeyRewrite when a
+// rewrite is already running on the store.
+var errInactiveKeyRewriteRunning = errors.New("a rewrite of the sstables using inactive keys is already running")
+
+// StartInactiveKeyRewrite starts rewriting, in the background, the sstables of
+// the store encrypted with an encryption-at-rest data key other than the active
+// one, at the rate configured by storage.InactiveKeyRewriteRate. Only one
+// rewrite runs at a time on a store.
+func (s *Store) StartInactiveKeyRewrite(ctx context.Context) error {
+	s.inactiveKeyRewrite.Lock()
+	defer s.inactiveKeyRewrite.Unlock()
+	if s.inactiveKeyRewrite.running {
+		return errInactiveKeyRewriteRunning
+	}
+	if err := s.stopper.RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "inactive-key-rewrite",
+		SpanOpt:  stop.SterileRootSpan,
+	}, func(ctx context.Context) {
+		ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		// TODO(sep-raft-log): rewrite the log engine as well.
+		rewritten, err := s.TODOEngine().RewriteInactiveKeySSTables(ctx, s.stopper)
+		if err != nil && ctx.Err() == nil {
+			log.Warningf(ctx, "unable to rewrite sstables using inactive keys: %v", err)
+		} else {
+			log.Infof(ctx, "rewrote %d bytes of sstables using inactive keys", rewritten)
+		}
+		s.inactiveKeyRewrite.Lock()
+		defer s.inactiveKeyRewrite.Unlock()
+		s.inactiveKeyRewrite.running = false
+		s.inactiveKeyRewrite.rewrittenBytes = rewritten
+	}); err != nil {
+		return err
+	}
+	s.inactiveKeyRewrite.running = true
+	return nil
+}
+
+// InactiveKeyRewriteStatus returns whether a rewrite of the sstables using
+// inactive keys is running on the store, and the number of bytes rewritten by
+// the last one to complete.
+func (s *Store) InactiveKeyRewriteStatus() (running bool, rewrittenBytes int64) {
+	s.inactiveKeyRewrite.Lock()
+	defer s.inactiveKeyRewrite.Unlock()
+	return s.inactiveKeyRewrite.running, s.inactiveKeyRewrite.rewrittenBytes
+}
@@ -0,0 +1,197 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/load"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/split"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// LoadSnapshotInterval controls how often a store persists a snapshot of its
+// replicas' load statistics, which are used to warm start the statistics when
+// the store restarts. Without it, the allocator and load-based splitting have
+// to re-learn the load on each range from scratch following a restart.
+var LoadSnapshotInterval = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.replica_stats.load_snapshot_interval",
+	"the interval at which a store persists the load statistics of its replicas, "+
+		"which are used to warm start them following a restart; 0 disables",
+	time.Minute,
+	settings.NonNegativeDuration,
+)
+
+// loadSnapshotMaxAge is the maximum age of a persisted load snapshot for it to
+// be used to warm start replicas. It corresponds to the period over which the
+// replica load statistics are tracked; load observed prior to it would have
+// aged out had the store not restarted.
+const loadSnapshotMaxAge = 30 * time.Minute
+
+// loadSnapshot returns a snapshot of the replica's load statistics. False is
+// returned if the replica has no load to speak of.
+func (r *Replica) loadSnapshot(ctx context.Context) (kvserverpb.ReplicaLoadSnapshot, bool) {
+	if r.loadStats == nil {
+		return kvserverpb.ReplicaLoadSnapshot{}, false
+	}
+	stats := r.loadStats.Stats()
+	splitSnap := r.loadBasedSplitter.Snapshot(ctx, r.Clock().PhysicalTime())
+	if stats == (load.ReplicaLoadStats{}) && splitSnap.Max == 0 {
+		return kvserverpb.ReplicaLoadSnapshot{}, false
+	}
+	snap := kvserverpb.ReplicaLoadSnapshot{
+		RangeID:                  r.RangeID,
+		QueriesPerSecond:         stats.QueriesPerSecond,
+		RequestsPerSecond:        stats.RequestsPerSecond,
+		WriteKeysPerSecond:       stats.WriteKeysPerSecond,
+		ReadKeysPerSecond:        stats.ReadKeysPerSecond,
+		WriteBytesPerSecond:      stats.WriteBytesPerSecond,
+		ReadBytesPerSecond:       stats.ReadBytesPerSecond,
+		RaftCPUNanosPerSecond:    stats.RaftCPUNanosPerSecond,
+		RequestCPUNanosPerSecond: stats.RequestCPUNanosPerSecond,
+		SplitObjective:           int32(splitSnap.SplitObjective),
+	}
+	if splitSnap.Ok {
+		snap.MaxSplitStat = splitSnap.Max
+	}
+	return snap, true
+}
+
+// seedLoad warm starts the replica's load statistics from the given snapshot.
+func (r *Replica) seedLoad(snap kvserverpb.ReplicaLoadSnapshot) {
+	if r.loadStats == nil {
+		return
+	}
+	r.loadStats.Seed(load.ReplicaLoadStats{
+		QueriesPerSecond:         snap.QueriesPerSecond,
+		RequestsPerSecond:        snap.RequestsPerSecond,
+		WriteKeysPerSecond:       snap.WriteKeysPerSecond,
+		ReadKeysPerSecond:        snap.ReadKeysPerSecond,
+		WriteBytesPerSecond:      snap.WriteBytesPerSecond,
+		ReadBytesPerSecond:       snap.ReadBytesPerSecond,
+		RaftCPUNanosPerSecond:    snap.RaftCPUNanosPerSecond,
+		RequestCPUNanosPerSecond: snap.RequestCPUNanosPerSecond,
+	})
+	if snap.MaxSplitStat > 0 {
+		r.loadBasedSplitter.SeedMax(
+			r.Clock().PhysicalTime(), split.SplitObjective(snap.SplitObjective), snap.MaxSplitStat)
+	}
+}
+
+// WriteLoadSnapshot persists a snapshot of the load statistics of the store's
+// replicas into the store-local load snapshot key.
+func (s *Store) WriteLoadSnapshot(ctx context.Context) error {
+	ctx = s.AnnotateCtx(ctx)
+	snap := kvserverpb.StoreLoadSnapshot{Timestamp: s.Clock().PhysicalTime()}
+	s.VisitReplicas(func(r *Replica) bool {
+		if rs, ok := r.loadSnapshot(ctx); ok {
+			snap.Replicas = append(snap.Replicas, rs)
+		}
+		return true
+	}, WithReplicasInOrder())
+	return storage.MVCCPutProto(
+		ctx,
+		s.TODOEngine(), // TODO(sep-raft-log): probably state engine
+		keys.StoreLoadSnapshotKey(),
+		hlc.Timestamp{},
+		&snap,
+		storage.MVCCWriteOptions{},
+	)
+}
+
+// ReadLoadSnapshot returns the load snapshot persisted in this store. False is
+// returned if the store does not contain one, e.g. on a newly bootstrapped
+// store.
+func (s *Store) ReadLoadSnapshot(
+	ctx context.Context,
+) (kvserverpb.StoreLoadSnapshot, bool, error) {
+	var snap kvserverpb.StoreLoadSnapshot
+	ok, err := storage.MVCCGetProto(ctx, s.TODOEngine(), keys.StoreLoadSnapshotKey(), hlc.Timestamp{},
+		&snap, storage.MVCCGetOptions{})
+	if err != nil || !ok {
+		return kvserverpb.StoreLoadSnapshot{}, false, err
+	}
+	return snap, true, nil
+}
+
+// warmStartReplicaLoad seeds the load statistics of the store's replicas from
+// the persisted load snapshot, unless it is too old to be representative of
+// the current load.
+func (s *Store) warmStartReplicaLoad(ctx context.Context) error {
+	snap, ok, err := s.ReadLoadSnapshot(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	if age := s.Clock().PhysicalTime().Sub(snap.Timestamp); age > loadSnapshotMaxAge {
+		log.Infof(ctx, "not warm starting replica load from load snapshot taken %s ago", age)
+		return nil
+	}
+	var seeded int
+	for _, rs := range snap.Replicas {
+		if r := s.GetReplicaIfExists(rs.RangeID); r != nil {
+			r.seedLoad(rs)
+			seeded++
+		}
+	}
+	log.Infof(ctx, "warm started load of %d/%d replicas from load snapshot", seeded, len(snap.Replicas))
+	return nil
+}
+
+// startLoadSnapshotPersister starts a goroutine which periodically persists a
+// snapshot of the load statistics of the store's replicas, see
+// LoadSnapshotInterval.
+func (s *Store) startLoadSnapshotPersister(ctx context.Context) {
+	confCh := make(chan struct{}, 1)
+	LoadSnapshotInterval.SetOnChange(&s.ClusterSettings().SV, func(context.Context) {
+		select {
+		case confCh <- struct{}{}:
+		default:
+		}
+	})
+
+	_ = s.stopper.RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "load-snapshot-persister",
+		SpanOpt:  stop.SterileRootSpan,
+	}, func(ctx context.Context) {
+		ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			interval := LoadSnapshotInterval.Get(&s.ClusterSettings().SV)
+			if interval > 0 {
+				timer.Reset(interval)
+			} else {
+				timer.Stop()
+			}
+			select {
+			case <-timer.C:
+				timer.Read = true
+				if err := s.WriteLoadSnapshot(ctx); err != nil {
+					log.Warningf(ctx, "unable to persist load snapshot: %v", err)
+				}
+			case <-confCh:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
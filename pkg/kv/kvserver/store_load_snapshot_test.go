@@ -0,0 +1,79 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/load"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStoreLoadSnapshot verifies that the load statistics of a store's
+// replicas are persisted, and used to warm start the replicas' load unless the
+// persisted snapshot is too old.
+func TestStoreLoadSnapshot(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	store, manual := createTestStore(ctx, t, testStoreOpts{}, stopper)
+
+	_, ok, err := store.ReadLoadSnapshot(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	repl := store.LookupReplica(roachpb.RKeyMin)
+	require.NotNil(t, repl)
+	repl.loadStats.TestingSetStat(load.Queries, 100)
+	repl.loadStats.TestingSetStat(load.WriteBytes, 2000)
+	require.NoError(t, store.WriteLoadSnapshot(ctx))
+
+	snap, ok, err := store.ReadLoadSnapshot(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	var found bool
+	for _, rs := range snap.Replicas {
+		if rs.RangeID == repl.RangeID {
+			found = true
+			require.Equal(t, 100.0, rs.QueriesPerSecond)
+			require.Equal(t, 2000.0, rs.WriteBytesPerSecond)
+		}
+	}
+	require.True(t, found)
+
+	// Simulate a restart by discarding the replica's load, then warm start it
+	// from the snapshot.
+	resetLoad := func() {
+		repl.loadStats.TestingSetStat(load.Queries, 0)
+		repl.loadStats.TestingSetStat(load.WriteBytes, 0)
+		repl.loadStats.Reset()
+	}
+	resetLoad()
+	require.Zero(t, repl.LoadStats().QueriesPerSecond)
+	require.NoError(t, store.warmStartReplicaLoad(ctx))
+	stats := repl.LoadStats()
+	require.InDelta(t, 100, stats.QueriesPerSecond, 0.01)
+	require.InDelta(t, 2000, stats.WriteBytesPerSecond, 0.01)
+
+	// A snapshot older than the max age isn't used.
+	resetLoad()
+	manual.Advance(loadSnapshotMaxAge + 1)
+	require.NoError(t, store.warmStartReplicaLoad(ctx))
+	require.Zero(t, repl.LoadStats().QueriesPerSecond)
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvstorage"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// UnsafeRecoverReplicaFn rewrites the on-disk state of a replica as part of
+// loss of quorum recovery. It returns the range descriptor and the replica ID
+// that the replica should be re-created with once the batch is committed.
+type UnsafeRecoverReplicaFn func(
+	storage.ReadWriter,
+) (roachpb.RangeDescriptor, roachpb.ReplicaID, error)
+
+// UnsafeRecoverReplica applies a loss of quorum recovery update to a live
+// initialized replica without restarting the node. This is the online
+// equivalent of applying a staged recovery plan at store startup.
+//
+// Before touching any state, the replica is checked to still be the one the
+// plan was made for, to have applied all entries it knows to be committed, and
+// to belong to a range that has lost quorum: the range descriptor, including
+// any joint configuration it is in, must not be able to make progress with the
+// replicas that isLive considers available. These are the same checks the
+// planner applied to the collected replica info, repeated against the current
+// state since it may have changed since the plan was made.
+//
+// The replica is then unlinked from the store while a placeholder holds on to
+// its keyspan, the rewrite is committed to the engine, and a new replica is
+// instantiated from the rewritten state. The replica ID of a Replica is
+// immutable, which is why the in-memory instance has to be replaced.
+//
+// If the rewrite fails, the replica is re-created from its unmodified on-disk
+// state and the error is returned.
+func (s *Store) UnsafeRecoverReplica(
+	ctx context.Context,
+	rangeID roachpb.RangeID,
+	oldReplicaID, nextReplicaID roachpb.ReplicaID,
+	isLive func(roachpb.ReplicaDescriptor) bool,
+	rewrite UnsafeRecoverReplicaFn,
+) error {
+	rep, err := s.GetReplica(rangeID)
+	if err != nil {
+		return err
+	}
+	rep.raftMu.Lock()
+	defer rep.raftMu.Unlock()
+
+	if !rep.IsInitialized() {
+		return errors.Errorf("cannot recover uninitialized replica %s", rep)
+	}
+
+	desc, err := func() (*roachpb.RangeDescriptor, error) {
+		rep.mu.Lock()
+		defer rep.mu.Unlock()
+		if !rep.mu.destroyStatus.IsAlive() {
+			return nil, rep.mu.destroyStatus.err
+		}
+		if err := rep.validateUnsafeRecoveryLocked(oldReplicaID, isLive); err != nil {
+			return nil, errors.Wrapf(err, "cannot recover replica %s", rep)
+		}
+		// Commit to the recovery. This has the same effect on concurrent requests
+		// as the replica being removed from the range.
+		rep.mu.destroyStatus.Set(kvpb.NewRangeNotFoundError(rep.RangeID, rep.StoreID()),
+			destroyReasonRemoved)
+		return rep.mu.state.Desc, nil
+	}()
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "applying loss of quorum recovery to replica r%d/%d", rep.RangeID, rep.replicaID)
+
+	ph, err := s.removeInitializedReplicaRaftMuLocked(ctx, rep, nextReplicaID, RemoveOptions{
+		InsertPlaceholder: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	newDesc, newReplicaID, rewriteErr := func() (roachpb.RangeDescriptor, roachpb.ReplicaID, error) {
+		batch := s.TODOEngine().NewBatch()
+		defer batch.Close()
+		newDesc, newReplicaID, err := rewrite(batch)
+		if err != nil {
+			return roachpb.RangeDescriptor{}, 0, err
+		}
+		return newDesc, newReplicaID, batch.Commit(true /* sync */)
+	}()
+	if rewriteErr != nil {
+		log.Warningf(ctx, "failed to apply loss of quorum recovery to r%d, restoring replica: %v",
+			rep.RangeID, rewriteErr)
+		newDesc, newReplicaID = *desc, rep.replicaID
+	}
+
+	// From here on, the keyspan is only held by the placeholder and all errors
+	// are fatal since the store would otherwise be left without a replica for
+	// the range.
+	state, err := kvstorage.LoadReplicaState(ctx, s.TODOEngine(), s.StoreID(), &newDesc, newReplicaID)
+	if err != nil {
+		log.Fatalf(ctx, "failed to load state of recovered replica r%d: %v", rangeID, err)
+	}
+	newRep, err := newInitializedReplica(s, state)
+	if err != nil {
+		log.Fatalf(ctx, "failed to instantiate recovered replica r%d: %v", rangeID, err)
+	}
+	func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, err := s.removePlaceholderLocked(ctx, ph, removePlaceholderFilled); err != nil {
+			log.Fatalf(ctx, "failed to remove placeholder of recovered replica r%d: %v", rangeID, err)
+		}
+		err := s.addToReplicasByRangeIDLocked(newRep)
+		if err == nil {
+			err = s.addToReplicasByKeyLocked(newRep, newRep.Desc())
+		}
+		if err != nil {
+			log.Fatalf(ctx, "failed to add recovered replica r%d to store: %v", rangeID, err)
+		}
+	}()
+
+	s.metrics.ReplicaCount.Inc(1)
+	s.metrics.addMVCCStats(ctx, newRep.tenantMetricsRef, newRep.GetMVCCStats())
+	s.storeGossip.MaybeGossipOnCapacityChange(ctx, RangeAddEvent)
+	// Wake up the replica so that it campaigns and acquires a lease right away
+	// instead of waiting for traffic.
+	newRep.maybeUnquiesce(ctx, true /* wakeLeader */, true /* mayCampaign */)
+	return rewriteErr
+}
+
+// validateUnsafeRecoveryLocked checks that the replica can be safely rewritten
+// to be the sole member of its range. Requires that Replica.mu is held.
+func (r *Replica) validateUnsafeRecoveryLocked(
+	oldReplicaID roachpb.ReplicaID, isLive func(roachpb.ReplicaDescriptor) bool,
+) error {
+	if r.replicaID != oldReplicaID {
+		return errors.Errorf("replica ID %d does not match the planned replica ID %d",
+			r.replicaID, oldReplicaID)
+	}
+	rn := r.mu.internalRaftGroup
+	if rn == nil {
+		return errors.New("replica has no raft group")
+	}
+	// The rewritten replica will not retain any knowledge of raft entries that
+	// are committed but not yet applied, so we refuse to proceed if there are
+	// any.
+	if st := rn.BasicStatus(); st.Applied < st.Commit {
+		return errors.Errorf("replica has unapplied committed raft entries (applied %d < committed %d)",
+			st.Applied, st.Commit)
+	}
+	// If the range can still reach quorum without the replicas that are gone,
+	// rewriting this replica would let two copies of the range make progress
+	// independently. CanMakeProgress requires a quorum of both the incoming
+	// and outgoing configurations of a joint descriptor.
+	if desc := r.mu.state.Desc; desc.Replicas().CanMakeProgress(isLive) {
+		return errors.Errorf("range %s has not lost quorum", desc)
+	}
+	return nil
+}
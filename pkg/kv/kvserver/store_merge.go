@@ -125,6 +125,12 @@ func (s *Store) MergeRange(
 	leftRepl.raftMu.AssertHeld()
 	rightRepl.raftMu.AssertHeld()
 
+	// Capture the RHS's flow control stream state before it's destroyed below,
+	// to be transferred to the LHS.
+	rightRepl.mu.Lock()
+	rightFlowControlState := rightRepl.mu.replicaFlowControlIntegration.streamState()
+	rightRepl.mu.Unlock()
+
 	// Note that we were called (indirectly) from raft processing so we must
 	// call removeInitializedReplicaRaftMuLocked directly to avoid deadlocking
 	// on the right-hand replica's raftMu.
@@ -226,5 +232,6 @@ func (s *Store) MergeRange(
 	}
 
 	leftRepl.setDescLockedRaftMuLocked(ctx, &newLeftDesc)
+	leftRepl.mu.replicaFlowControlIntegration.onMerge(ctx, rightFlowControlState)
 	return nil
 }
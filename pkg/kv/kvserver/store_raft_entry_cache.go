@@ -0,0 +1,99 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// RaftEntryCacheAdaptiveSizingEnabled controls whether the size limit of the
+// Raft entry cache adapts to its hit rate and to memory pressure. When
+// disabled, the cache uses its full configured size.
+var RaftEntryCacheAdaptiveSizingEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.raft.entry_cache.adaptive_sizing.enabled",
+	"if enabled, the size of the raft entry cache adapts to its hit rate and "+
+		"to memory pressure, within its configured size",
+	true,
+)
+
+// RaftEntryCacheMemoryPressureThreshold is the fraction of the Go runtime's
+// soft memory limit used by the live heap above which the Raft entry cache
+// shrinks.
+var RaftEntryCacheMemoryPressureThreshold = settings.RegisterFloatSetting(
+	settings.SystemOnly,
+	"kv.raft.entry_cache.memory_pressure_threshold",
+	"the fraction of the memory limit used by the live heap above which the "+
+		"raft entry cache shrinks, when adaptive sizing is enabled",
+	0.85,
+	settings.FractionUpperExclusive,
+)
+
+// raftEntryCacheSizerInterval is the interval at which the size limit of the
+// Raft entry cache is adapted.
+const raftEntryCacheSizerInterval = 10 * time.Second
+
+// raftEntryCacheCatchUpMinLag is the number of entries by which a follower
+// needs to lag behind the commit index for the leader to pin the range in the
+// Raft entry cache while the follower catches up.
+const raftEntryCacheCatchUpMinLag = 64
+
+// startRaftEntryCacheSizer starts a goroutine which periodically adapts the
+// size limit of the store's Raft entry cache, see
+// RaftEntryCacheAdaptiveSizingEnabled.
+func (s *Store) startRaftEntryCacheSizer(ctx context.Context) {
+	_ = s.stopper.RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "raft-entry-cache-sizer",
+		SpanOpt:  stop.SterileRootSpan,
+	}, func(ctx context.Context) {
+		ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(raftEntryCacheSizerInterval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+				s.adaptRaftEntryCache(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// adaptRaftEntryCache adapts the size limit of the store's Raft entry cache
+// to its hit rate and to the memory pressure on the node.
+func (s *Store) adaptRaftEntryCache(ctx context.Context) {
+	sv := &s.ClusterSettings().SV
+	c := s.raftEntryCache
+	if !RaftEntryCacheAdaptiveSizingEnabled.Get(sv) {
+		c.SetLimit(c.MaxBytes())
+		return
+	}
+	memoryPressure := admission.ReadGoMemoryStats().HeapFraction() >=
+		RaftEntryCacheMemoryPressureThreshold.Get(sv)
+	prev := c.Limit()
+	if limit := c.Adapt(memoryPressure); limit != prev {
+		log.VEventf(ctx, 1, "adapted raft entry cache limit from %s to %s (memory pressure: %t)",
+			humanizeutil.IBytes(int64(prev)), humanizeutil.IBytes(int64(limit)), memoryPressure)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
@@ -490,6 +491,12 @@ func (kvSS *kvBatchSnapshotStrategy) Receive(
 
 	timingTag.start("totalTime")
 
+	decompressor, err := newSnapshotDecompressor(&header)
+	if err != nil {
+		return noSnap, sendSnapshotError(ctx, s, stream, err)
+	}
+	defer decompressor.close()
+
 	// At the moment we'll write at most five SSTs.
 	// TODO(jeffreyxiao): Re-evaluate as the default range size grows.
 	keyRanges := rditer.MakeReplicatedKeySpans(header.State.Desc)
@@ -548,7 +555,15 @@ func (kvSS *kvBatchSnapshotStrategy) Receive(
 
 		if req.KVBatch != nil {
 			recordBytesReceived(int64(len(req.KVBatch)))
-			batchReader, err := storage.NewBatchReader(req.KVBatch)
+			kvBatch, err := decompressor.decompress(req.KVBatch)
+			if err != nil {
+				return noSnap, errors.Wrap(err, "failed to decompress batch")
+			}
+			if header.Compression != kvserverpb.SnapshotRequest_COMPRESSION_NONE {
+				s.metrics.RangeSnapshotCompressedRcvdBytes.Inc(int64(len(req.KVBatch)))
+				s.metrics.RangeSnapshotUncompressedRcvdBytes.Inc(int64(len(kvBatch)))
+			}
+			batchReader, err := storage.NewBatchReader(kvBatch)
 			if err != nil {
 				return noSnap, errors.Wrap(err, "failed to decode batch")
 			}
@@ -770,11 +785,17 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 		}
 	}()
 
+	compressor, err := newSnapshotCompressor(&kvSS.st.SV, &header)
+	if err != nil {
+		return 0, err
+	}
+	defer compressor.close()
+
 	flushBatch := func() error {
-		if err := kvSS.sendBatch(ctx, stream, b, sharedSSTs, externalSSTs, transitionFromSharedToRegularReplicate, timingTag); err != nil {
+		bLen, err := kvSS.sendBatch(ctx, stream, compressor, b, sharedSSTs, externalSSTs, transitionFromSharedToRegularReplicate, timingTag)
+		if err != nil {
 			return err
 		}
-		bLen := int64(b.Len())
 		bytesSent += bLen
 		recordBytesSent(bLen)
 		b.Close()
@@ -856,7 +877,7 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 		}
 		return err
 	}
-	err := rditer.IterateReplicaKeySpans(ctx, snap.State.Desc, snap.EngineSnap, true, /* replicatedOnly */
+	err = rditer.IterateReplicaKeySpans(ctx, snap.State.Desc, snap.EngineSnap, true, /* replicatedOnly */
 		replicatedFilter, iterateRKSpansVisitor)
 	if err != nil {
 		return 0, err
@@ -979,30 +1000,34 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 	return bytesSent, nil
 }
 
+// sendBatch sends the given batch, compressed with the given compressor, and
+// returns the number of bytes of the sent batch.
 func (kvSS *kvBatchSnapshotStrategy) sendBatch(
 	ctx context.Context,
 	stream outgoingSnapshotStream,
+	compressor *snapshotCompressor,
 	batch storage.WriteBatch,
 	sharedSSTs []kvserverpb.SnapshotRequest_SharedTable,
 	externalSSTs []kvserverpb.SnapshotRequest_ExternalTable,
 	transitionToRegularReplicate bool,
 	timerTag *snapshotTimingTag,
-) error {
+) (int64, error) {
 	timerTag.start("rateLimit")
 	err := kvSS.limiter.WaitN(ctx, 1)
 	timerTag.stop("rateLimit")
 	if err != nil {
-		return err
+		return 0, err
 	}
+	kvBatch := compressor.compress(batch.Repr())
 	timerTag.start("send")
 	res := stream.Send(&kvserverpb.SnapshotRequest{
-		KVBatch:                                batch.Repr(),
+		KVBatch:                                kvBatch,
 		SharedTables:                           sharedSSTs,
 		ExternalTables:                         externalSSTs,
 		TransitionFromSharedToRegularReplicate: transitionToRegularReplicate,
 	})
 	timerTag.stop("send")
-	return res
+	return int64(len(kvBatch)), res
 }
 
 // Status implements the snapshotStrategy interface.
@@ -1424,8 +1449,12 @@ func (s *Store) receiveSnapshot(
 		return sendSnapshotError(ctx, s, stream, err)
 	}
 
+	var snapshotQ *admission.SnapshotQueue
+	if s.cfg.KVAdmissionController != nil {
+		snapshotQ = s.cfg.KVAdmissionController.SnapshotQueue(s.StoreID())
+	}
 	ss := &kvBatchSnapshotStrategy{
-		scratch:      s.sstSnapshotStorage.NewScratchSpace(header.State.Desc.RangeID, snapUUID),
+		scratch:      s.sstSnapshotStorage.NewScratchSpace(header.State.Desc.RangeID, snapUUID, snapshotQ),
 		sstChunkSize: snapshotSSTWriteSyncRate.Get(&s.cfg.Settings.SV),
 		st:           s.ClusterSettings(),
 		clusterID:    s.ClusterID(),
@@ -1543,6 +1572,12 @@ var rebalanceSnapshotRate = settings.RegisterByteSizeSetting(
 	settings.WithPublic,
 )
 
+// RebalanceSnapshotRate returns the rate limit (bytes/sec) of rebalance and
+// upreplication snapshots.
+func RebalanceSnapshotRate(sv *settings.Values) int64 {
+	return rebalanceSnapshotRate.Get(sv)
+}
+
 // snapshotSenderBatchSize is the size that key-value batches are allowed to
 // grow to during Range snapshots before being sent to the receiver. This limit
 // places an upper-bound on the memory footprint of the sender of a Range
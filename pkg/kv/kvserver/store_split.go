@@ -225,11 +225,14 @@ func prepareRightReplicaForSplit(
 	// so we can assign it to the RHS. minLeaseProposedTS ensures that if the LHS
 	// was not able to use its current lease because of a restart or lease
 	// transfer, the RHS will also not be able to. minValidObservedTS ensures that
-	// the bounds for uncertainty interval are preserved.
-	r.mu.RLock()
+	// the bounds for uncertainty interval are preserved. We also copy out the
+	// LHS's flow control stream state, so the RHS doesn't start off with all
+	// its replication streams disconnected.
+	r.mu.Lock()
 	minLeaseProposedTS := r.mu.minLeaseProposedTS
 	minValidObservedTS := r.mu.minValidObservedTimestamp
-	r.mu.RUnlock()
+	flowControlState := r.mu.replicaFlowControlIntegration.streamState()
+	r.mu.Unlock()
 
 	// If the RHS replica of the split is not removed, then it has been obtained
 	// (and its raftMu acquired) in Replica.acquireSplitLock.
@@ -264,6 +267,9 @@ func prepareRightReplicaForSplit(
 	// Copy the minValidObservedTimestamp field from the LHS.
 	rightRepl.mu.minValidObservedTimestamp = minValidObservedTS
 
+	// Transfer the LHS's flow control stream state.
+	rightRepl.mu.replicaFlowControlIntegration.onSplit(ctx, flowControlState)
+
 	// Invoke the leasePostApplyLocked method to ensure we properly initialize
 	// the replica according to whether it holds the lease. This enables the
 	// txnWaitQueue.
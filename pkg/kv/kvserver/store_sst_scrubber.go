@@ -0,0 +1,126 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// sstScrubberPollInterval is the interval at which a store checks whether the
+// sstable scrubber is enabled and whether cold sstables are due for scrubbing,
+// see storage.SSTScrubberEnabled.
+const sstScrubberPollInterval = time.Minute
+
+// startSSTScrubber starts a goroutine which periodically validates the block
+// checksums of the cold sstables of the store's engine, and marks the replicas
+// overlapping the sstables found to be corrupt as corrupt.
+func (s *Store) startSSTScrubber(ctx context.Context) {
+	_ = s.stopper.RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "sst-scrubber",
+		SpanOpt:  stop.SterileRootSpan,
+	}, func(ctx context.Context) {
+		ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(sstScrubberPollInterval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+				if storage.SSTScrubberEnabled.Get(&s.ClusterSettings().SV) {
+					s.scrubSSTables(ctx)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// scrubSSTables runs a pass of the sstable scrubber of the store's engine.
+func (s *Store) scrubSSTables(ctx context.Context) {
+	// TODO(sep-raft-log): scrub the log engine as well.
+	stats, err := s.TODOEngine().ScrubSSTables(ctx)
+	s.metrics.SSTScrubberScrubbedBytes.Inc(stats.ScrubbedBytes)
+	if err != nil && ctx.Err() == nil {
+		log.Warningf(ctx, "unable to scrub sstables: %v", err)
+	}
+	for _, c := range stats.Corrupt {
+		s.handleCorruptSSTable(ctx, c)
+	}
+}
+
+// handleCorruptSSTable reports the given corrupt sstable, and marks the store's
+// replicas overlapping its span as corrupt, the way a replica is marked
+// corrupt when a request reads corrupt data from the engine. This terminates
+// the node, and the affected ranges are repaired by up-replicating them from
+// the healthy replicas on the other nodes, rather than waiting for the corrupt
+// data to be read, or for the consistency checker to find it, which only
+// happens on the leaseholder's schedule.
+func (s *Store) handleCorruptSSTable(ctx context.Context, c storage.CorruptSSTable) {
+	span := corruptSSTableSpan(c.Span)
+	s.metrics.SSTScrubberCorruptSSTables.Inc(1)
+	log.Errorf(ctx, "sstable %d in L%d is corrupt, marking the replicas overlapping %s as corrupt: %v",
+		c.FileNum, c.Level, span, c.Err)
+	log.StructuredEvent(ctx, &eventpb.SstableCorruptionDetected{
+		NodeID:       int32(s.NodeID()),
+		StoreID:      int32(s.StoreID()),
+		FileNum:      c.FileNum,
+		Level:        int32(c.Level),
+		StartKey:     span.Key.String(),
+		EndKey:       span.EndKey.String(),
+		ErrorMessage: c.Err.Error(),
+	})
+
+	_ = s.visitReplicasByKey(ctx, span.Key, span.EndKey, AscendingKeyOrder,
+		func(ctx context.Context, repl *Replica) error {
+			repl.raftMu.Lock()
+			defer repl.raftMu.Unlock()
+			_ = repl.setCorruptRaftMuLocked(ctx, &kvpb.ReplicaCorruptionError{
+				ErrorMsg: fmt.Sprintf("sstable %d in L%d is corrupt: %v", c.FileNum, c.Level, c.Err),
+			})
+			return nil
+		})
+}
+
+// corruptSSTableSpan returns the span of the addressable keyspace containing the
+// keys of the given span of engine keys. Spans which cannot be mapped to the
+// addressable keyspace, e.g. because they contain store-local keys or straddle
+// the local and global keyspaces, are conservatively mapped to the entire
+// keyspace.
+func corruptSSTableSpan(span roachpb.Span) roachpb.RSpan {
+	all := roachpb.RSpan{Key: roachpb.RKeyMin, EndKey: roachpb.RKeyMax}
+	if keys.IsLocal(span.Key) != keys.IsLocal(span.EndKey) {
+		return all
+	}
+	start, err := keys.Addr(span.Key)
+	if err != nil {
+		return all
+	}
+	end, err := keys.AddrUpperBound(span.EndKey)
+	if err != nil || !start.Less(end) {
+		return all
+	}
+	return roachpb.RSpan{Key: start, EndKey: end}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorruptSSTableSpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	all := roachpb.RSpan{Key: roachpb.RKeyMin, EndKey: roachpb.RKeyMax}
+	tableA := keys.SystemSQLCodec.TablePrefix(100)
+	tableB := keys.SystemSQLCodec.TablePrefix(101)
+	for _, tc := range []struct {
+		name     string
+		span     roachpb.Span
+		expected roachpb.RSpan
+	}{
+		{
+			name:     "global",
+			span:     roachpb.Span{Key: tableA, EndKey: tableB},
+			expected: roachpb.RSpan{Key: roachpb.RKey(tableA), EndKey: roachpb.RKey(tableB)},
+		},
+		{
+			name: "range-local",
+			span: roachpb.Span{
+				Key:    keys.RangeDescriptorKey(roachpb.RKey(tableA)),
+				EndKey: keys.RangeDescriptorKey(roachpb.RKey(tableB)),
+			},
+			expected: roachpb.RSpan{Key: roachpb.RKey(tableA), EndKey: roachpb.RKey(tableB).Next()},
+		},
+		{
+			name:     "store-local",
+			span:     roachpb.Span{Key: keys.StoreIdentKey(), EndKey: keys.StoreIdentKey().Next()},
+			expected: all,
+		},
+		{
+			name:     "local and global",
+			span:     roachpb.Span{Key: keys.RangeDescriptorKey(roachpb.RKey(tableB)), EndKey: tableA},
+			expected: all,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, corruptSSTableSpan(tc.span))
+		})
+	}
+}
+
+func TestStoreHandleCorruptSSTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var exitStatus exit.Code
+	log.SetExitFunc(true /* hideStack */, func(i exit.Code) {
+		exitStatus = i
+	})
+	defer log.ResetExitFunc()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	store, _ := createTestStore(ctx, t, testStoreOpts{createSystemRanges: true}, stopper)
+
+	tableA := keys.SystemSQLCodec.TablePrefix(100)
+	tableB := keys.SystemSQLCodec.TablePrefix(101)
+	corruptRepl := store.LookupReplica(roachpb.RKey(tableA))
+	healthyRepl := store.LookupReplica(roachpb.RKeyMin)
+	require.NotEqual(t, corruptRepl.RangeID, healthyRepl.RangeID)
+
+	store.handleCorruptSSTable(ctx, storage.CorruptSSTable{
+		FileNum: 5,
+		Level:   6,
+		Span:    roachpb.Span{Key: tableA, EndKey: tableB},
+		Err:     errors.New("corrupt block"),
+	})
+	require.Equal(t, int64(1), store.metrics.SSTScrubberCorruptSSTables.Count())
+
+	// The replica overlapping the sstable is marked corrupt, which terminates
+	// the node, and the other replicas are left alone.
+	require.Equal(t, exit.FatalError(), exitStatus)
+	_, err := corruptRepl.IsDestroyed()
+	require.True(t, errors.HasType(err, (*kvpb.ReplicaCorruptionError)(nil)), "%v", err)
+	require.ErrorContains(t, err, "sstable 5 in L6 is corrupt")
+	_, err = healthyRepl.IsDestroyed()
+	require.NoError(t, err)
+}
@@ -18,7 +18,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/tokenbucket"
 )
@@ -50,6 +53,28 @@ import (
 // value. RecordRead can push the limiter into debt, blocking future requests
 // until that debt is paid.
 //
+// In addition, if the tenant has been granted a non-zero
+// max_requests_per_second_per_node capability, Wait also limits the rate at
+// which batches are admitted to that many per second, with a burst of one
+// second's worth of requests. The limit only applies to the requests served by
+// this node.
+//
+// Finally, if the tenant has been granted non-zero
+// max_{read,write}_bytes_per_second_per_node capabilities, the read and write
+// bandwidth of the tenant on this node are limited by separate token buckets,
+// in bytes, with a burst of one second's worth of bytes. Writes acquire their
+// bytes in Wait. Reads are accounted for in RecordRead, which can push the read
+// bandwidth bucket into debt; subsequent reads wait until the debt is paid off.
+//
+// Similarly, if the tenant has been granted a non-zero
+// max_block_cache_fill_bytes_per_second_per_node capability, the bytes of the
+// sstable blocks that the reads of the tenant load into the block cache of this
+// node, i.e. the bytes not already found in the cache, are limited by another
+// token bucket. They are accounted for in RecordBlockCacheUsage, and subsequent
+// reads wait while that bucket is in debt. This weighs each tenant's share of
+// the block cache fill rate, which bounds how quickly a tenant can evict the
+// blocks cached for the other tenants.
+//
 // The Limiter is backed by a FIFO queue which provides fairness.
 type Limiter interface {
 	// Wait acquires the quota necessary to admit a read or write request. This
@@ -66,6 +91,13 @@ type Limiter interface {
 	// forcing subsequent Wait calls to block until the debt is paid.
 	// However, RecordRead itself will never block.
 	RecordRead(ctx context.Context, respInfo tenantcostmodel.ResponseInfo)
+
+	// RecordBlockCacheUsage records the bytes in sstable blocks loaded by a
+	// read request, and the subset of those that were served from the block
+	// cache. The remaining bytes are subtracted from the block cache fill token
+	// bucket, which may push it into debt and force subsequent reads to wait.
+	// However, RecordBlockCacheUsage itself will never block.
+	RecordBlockCacheUsage(ctx context.Context, blockBytes, blockBytesInCache int64)
 }
 
 type limiter struct {
@@ -80,6 +112,27 @@ type limiter struct {
 	// some infrastructure to update the cache when the capability actually
 	// changes.
 	authorizer tenantcapabilities.Authorizer
+
+	// requestRate enforces the tenant's max_requests_per_second_per_node
+	// capability. It is configured with an infinite rate while the capability is
+	// unset.
+	requestRate *quotapool.RateLimiter
+	// maxRequestsPerSecond is the value of the capability that requestRate is
+	// currently configured with.
+	maxRequestsPerSecond struct {
+		syncutil.Mutex
+		val int64
+	}
+	// maxBytesPerSecond are the values of the tenant's
+	// max_{read,write,block_cache_fill}_bytes_per_second_per_node capabilities
+	// that the bandwidth token buckets are currently configured with.
+	maxBytesPerSecond struct {
+		syncutil.Mutex
+		read, write, cacheFill int64
+	}
+
+	// waitEventEvery rate limits the structured events reporting long waits.
+	waitEventEvery log.EveryN
 }
 
 // init initializes a new limiter.
@@ -96,6 +149,8 @@ func (rl *limiter) init(
 		tenantID:   tenantID,
 		metrics:    metrics,
 		authorizer: authorizer,
+
+		waitEventEvery: log.Every(time.Second),
 	}
 	// Note: if multiple token buckets are needed, consult the history of
 	// this file as of 0e70529f84 for a sample implementation.
@@ -116,16 +171,72 @@ func (rl *limiter) init(
 	// directly without separate synchronization for the Config.
 	rl.qp = quotapool.New(tenantID.String(), bucket, options...)
 	bucket.init(config, rl.qp.TimeSource())
+	rate, burst := requestRateLimit(0)
+	rl.requestRate = quotapool.NewRateLimiter(tenantID.String()+"-requests", rate, burst, options...)
+}
+
+// requestRateLimit returns the rate and burst for the request rate limiter
+// given the value of the max_requests_per_second_per_node capability.
+func requestRateLimit(maxRequestsPerSecond int64) (quotapool.Limit, int64) {
+	if maxRequestsPerSecond <= 0 {
+		return quotapool.Inf(), 1
+	}
+	return quotapool.Limit(maxRequestsPerSecond), maxRequestsPerSecond
+}
+
+// waitForRequestRate blocks until the tenant's
+// max_requests_per_second_per_node capability, if set, allows another batch to
+// be admitted.
+func (rl *limiter) waitForRequestRate(ctx context.Context) error {
+	maxRequestsPerSecond := rl.authorizer.GetMaxRequestsPerSecondPerNode(ctx, rl.tenantID)
+	rl.maxRequestsPerSecond.Lock()
+	if rl.maxRequestsPerSecond.val != maxRequestsPerSecond {
+		rl.maxRequestsPerSecond.val = maxRequestsPerSecond
+		rl.requestRate.UpdateLimit(requestRateLimit(maxRequestsPerSecond))
+	}
+	rl.maxRequestsPerSecond.Unlock()
+	return rl.requestRate.WaitN(ctx, 1)
+}
+
+// maybeUpdateBandwidthLimits reconfigures the bandwidth token buckets if the
+// tenant's max_{read,write,block_cache_fill}_bytes_per_second_per_node
+// capabilities changed.
+func (rl *limiter) maybeUpdateBandwidthLimits(ctx context.Context) {
+	read := rl.authorizer.GetMaxReadBytesPerSecondPerNode(ctx, rl.tenantID)
+	write := rl.authorizer.GetMaxWriteBytesPerSecondPerNode(ctx, rl.tenantID)
+	cacheFill := rl.authorizer.GetMaxBlockCacheFillBytesPerSecondPerNode(ctx, rl.tenantID)
+	rl.maxBytesPerSecond.Lock()
+	defer rl.maxBytesPerSecond.Unlock()
+	if rl.maxBytesPerSecond.read == read && rl.maxBytesPerSecond.write == write &&
+		rl.maxBytesPerSecond.cacheFill == cacheFill {
+		return
+	}
+	rl.maxBytesPerSecond.read, rl.maxBytesPerSecond.write = read, write
+	rl.maxBytesPerSecond.cacheFill = cacheFill
+	rl.qp.Update(func(res quotapool.Resource) (shouldNotify bool) {
+		res.(*tokenBucket).updateBandwidthLimits(read, write, cacheFill)
+		return true
+	})
 }
 
 // Wait is part of the Limiter interface.
 func (rl *limiter) Wait(ctx context.Context, reqInfo tenantcostmodel.RequestInfo) error {
 	exempt := rl.authorizer.IsExemptFromRateLimiting(ctx, rl.tenantID)
 	if !exempt {
+		rl.maybeUpdateBandwidthLimits(ctx)
 		r := newWaitRequest(reqInfo)
 		defer putWaitRequest(r)
 
-		if err := rl.qp.Acquire(ctx, r); err != nil {
+		start := rl.qp.TimeSource().Now()
+		err := rl.qp.Acquire(ctx, r)
+		if r.bandwidthLimited {
+			rl.recordBandwidthLimited(reqInfo, err != nil /* rejected */)
+		}
+		if err == nil {
+			err = rl.waitForRequestRate(ctx)
+		}
+		rl.maybeLogWait(ctx, reqInfo, rl.qp.TimeSource().Since(start), err != nil /* rejected */)
+		if err != nil {
 			return err
 		}
 	}
@@ -139,6 +250,44 @@ func (rl *limiter) Wait(ctx context.Context, reqInfo tenantcostmodel.RequestInfo
 	return nil
 }
 
+// longWaitThreshold is the wait duration above which a request waiting in the
+// limiter is reported on the QUOTA logging channel.
+const longWaitThreshold = time.Second
+
+// maybeLogWait reports a long wait of a request on the QUOTA logging channel.
+// The events are rate limited.
+func (rl *limiter) maybeLogWait(
+	ctx context.Context, reqInfo tenantcostmodel.RequestInfo, waitDur time.Duration, rejected bool,
+) {
+	if waitDur < longWaitThreshold || !rl.waitEventEvery.ShouldLog() {
+		return
+	}
+	log.StructuredEvent(ctx, &eventpb.TenantRateLimiterWait{
+		TenantID:   rl.tenantID.ToUint64(),
+		IsWrite:    reqInfo.IsWrite(),
+		WriteBytes: reqInfo.WriteBytes(),
+		WaitNanos:  waitDur.Nanoseconds(),
+		Rejected:   rejected,
+	})
+}
+
+// recordBandwidthLimited updates the metrics for a request that had to wait
+// for the read, write, or block cache fill bandwidth limits. If rejected is
+// set, the request gave up waiting.
+func (rl *limiter) recordBandwidthLimited(reqInfo tenantcostmodel.RequestInfo, rejected bool) {
+	switch {
+	case reqInfo.IsWrite() && rejected:
+		rl.metrics.writeBytesRejected.Inc(reqInfo.WriteBytes())
+	case reqInfo.IsWrite():
+		rl.metrics.writeBytesDelayed.Inc(reqInfo.WriteBytes())
+	case rejected:
+		// The number of bytes that a read would have returned is not known.
+		rl.metrics.readBatchesRejected.Inc(1)
+	default:
+		rl.metrics.readBatchesDelayed.Inc(1)
+	}
+}
+
 // RecordRead is part of the Limiter interface.
 func (rl *limiter) RecordRead(ctx context.Context, respInfo tenantcostmodel.ResponseInfo) {
 	exempt := rl.authorizer.IsExemptFromRateLimiting(ctx, rl.tenantID)
@@ -147,12 +296,16 @@ func (rl *limiter) RecordRead(ctx context.Context, respInfo tenantcostmodel.Resp
 	rl.metrics.readRequestsAdmitted.Inc(respInfo.ReadCount())
 	rl.metrics.readBytesAdmitted.Inc(respInfo.ReadBytes())
 	if !exempt {
+		rl.maybeUpdateBandwidthLimits(ctx)
 		rl.qp.Update(func(res quotapool.Resource) (shouldNotify bool) {
 			tb := res.(*tokenBucket)
 			amount := tb.config.ReadBatchUnits
 			amount += float64(respInfo.ReadCount()) * tb.config.ReadRequestUnits
 			amount += float64(respInfo.ReadBytes()) * tb.config.ReadUnitsPerByte
 			tb.Adjust(tokenbucket.Tokens(-amount))
+			if tb.maxReadBytesPerSecond > 0 {
+				tb.readBytes.Adjust(tokenbucket.Tokens(-respInfo.ReadBytes()))
+			}
 			// Do not notify the head of the queue. In the best case we did not disturb
 			// the time at which it can be fulfilled and in the worst case, we made it
 			// further in the future.
@@ -161,6 +314,26 @@ func (rl *limiter) RecordRead(ctx context.Context, respInfo tenantcostmodel.Resp
 	}
 }
 
+// RecordBlockCacheUsage is part of the Limiter interface.
+func (rl *limiter) RecordBlockCacheUsage(
+	ctx context.Context, blockBytes, blockBytesInCache int64,
+) {
+	rl.metrics.blockBytes.Inc(blockBytes)
+	rl.metrics.blockBytesInCache.Inc(blockBytesInCache)
+	if rl.authorizer.IsExemptFromRateLimiting(ctx, rl.tenantID) {
+		return
+	}
+	rl.maybeUpdateBandwidthLimits(ctx)
+	rl.qp.Update(func(res quotapool.Resource) (shouldNotify bool) {
+		tb := res.(*tokenBucket)
+		if tb.maxCacheFillBytesPerSecond > 0 {
+			tb.cacheFillBytes.Adjust(tokenbucket.Tokens(-(blockBytes - blockBytesInCache)))
+		}
+		// As in RecordRead, there is no need to notify the head of the queue.
+		return false
+	})
+}
+
 // Release cleans up resources reserved for this limiter.
 func (rl *limiter) Release() {
 	rl.metrics.unlink()
@@ -180,11 +353,22 @@ func (rl *limiter) updateConfig(config Config) {
 	})
 }
 
-// tokenBucket represents the token bucket for KV Compute Units and its
+// tokenBucket represents the token bucket for KV Compute Units, the token
+// buckets for the read, write, and block cache fill bandwidth, and their
 // associated configuration. It implements quotapool.Resource.
 type tokenBucket struct {
 	tokenbucket.TokenBucket
 
+	// readBytes, writeBytes, and cacheFillBytes are only used if the
+	// corresponding capability is positive.
+	readBytes      tokenbucket.TokenBucket
+	writeBytes     tokenbucket.TokenBucket
+	cacheFillBytes tokenbucket.TokenBucket
+	// maxReadBytesPerSecond, maxWriteBytesPerSecond, and
+	// maxCacheFillBytesPerSecond are the values of the tenant's
+	// max_{read,write,block_cache_fill}_bytes_per_second_per_node capabilities.
+	maxReadBytesPerSecond, maxWriteBytesPerSecond, maxCacheFillBytesPerSecond int64
+
 	config Config
 }
 
@@ -194,12 +378,40 @@ func (tb *tokenBucket) init(config Config, timeSource timeutil.TimeSource) {
 	tb.TokenBucket.InitWithNowFn(
 		tokenbucket.TokensPerSecond(config.Rate), tokenbucket.Tokens(config.Burst), timeSource.Now,
 	)
+	tb.readBytes.InitWithNowFn(0, 0, timeSource.Now)
+	tb.writeBytes.InitWithNowFn(0, 0, timeSource.Now)
+	tb.cacheFillBytes.InitWithNowFn(0, 0, timeSource.Now)
 	tb.config = config
 }
 
+// updateBandwidthLimits reconfigures the bandwidth token buckets given the
+// values of the max_{read,write,block_cache_fill}_bytes_per_second_per_node
+// capabilities. The burst is one second's worth of bytes.
+func (tb *tokenBucket) updateBandwidthLimits(
+	maxReadBytesPerSecond, maxWriteBytesPerSecond, maxCacheFillBytesPerSecond int64,
+) {
+	tb.maxReadBytesPerSecond = maxReadBytesPerSecond
+	tb.maxWriteBytesPerSecond = maxWriteBytesPerSecond
+	tb.maxCacheFillBytesPerSecond = maxCacheFillBytesPerSecond
+	tb.readBytes.UpdateConfig(
+		tokenbucket.TokensPerSecond(maxReadBytesPerSecond), tokenbucket.Tokens(maxReadBytesPerSecond),
+	)
+	tb.writeBytes.UpdateConfig(
+		tokenbucket.TokensPerSecond(maxWriteBytesPerSecond), tokenbucket.Tokens(maxWriteBytesPerSecond),
+	)
+	tb.cacheFillBytes.UpdateConfig(
+		tokenbucket.TokensPerSecond(maxCacheFillBytesPerSecond),
+		tokenbucket.Tokens(maxCacheFillBytesPerSecond),
+	)
+}
+
 // waitRequest is used to wait for adequate resources in the tokenBuckets.
 type waitRequest struct {
 	info tenantcostmodel.RequestInfo
+
+	// bandwidthLimited is set if the request had to wait for the read, write,
+	// or block cache fill bandwidth limits.
+	bandwidthLimited bool
 }
 
 var _ quotapool.Request = (*waitRequest)(nil)
@@ -226,6 +438,38 @@ func (req *waitRequest) Acquire(
 	ctx context.Context, res quotapool.Resource,
 ) (fulfilled bool, tryAgainAfter time.Duration) {
 	tb := res.(*tokenBucket)
+
+	// Acquire the bandwidth first; it is returned if the KV Compute Units are
+	// not available.
+	var bandwidth *tokenbucket.TokenBucket
+	var neededBytes float64
+	if req.info.IsWrite() {
+		if tb.maxWriteBytesPerSecond > 0 {
+			bandwidth = &tb.writeBytes
+			neededBytes = float64(req.info.WriteBytes())
+		}
+	} else {
+		// As for the KV Compute Units below, reads only wait while the read
+		// bandwidth or block cache fill buckets are in debt.
+		if tb.maxCacheFillBytesPerSecond > 0 {
+			fulfilled, tryAgainAfter = tb.cacheFillBytes.TryToFulfill(0)
+			if !fulfilled {
+				req.bandwidthLimited = true
+				return false, tryAgainAfter
+			}
+		}
+		if tb.maxReadBytesPerSecond > 0 {
+			bandwidth = &tb.readBytes
+		}
+	}
+	if bandwidth != nil {
+		fulfilled, tryAgainAfter = bandwidth.TryToFulfill(tokenbucket.Tokens(neededBytes))
+		if !fulfilled {
+			req.bandwidthLimited = true
+			return false, tryAgainAfter
+		}
+	}
+
 	var needed float64
 	if req.info.IsWrite() {
 		needed = tb.config.WriteBatchUnits
@@ -237,7 +481,11 @@ func (req *waitRequest) Acquire(
 		// value, in case the quota pool is in debt and the read should block.
 		needed = 0
 	}
-	return tb.TryToFulfill(tokenbucket.Tokens(needed))
+	fulfilled, tryAgainAfter = tb.TryToFulfill(tokenbucket.Tokens(needed))
+	if !fulfilled && bandwidth != nil {
+		bandwidth.Adjust(tokenbucket.Tokens(neededBytes))
+	}
+	return fulfilled, tryAgainAfter
 }
 
 // ShouldWait is part of quotapool.Request.
@@ -168,18 +168,19 @@ func (s launchState) String() string {
 }
 
 var testStateCommands = map[string]func(*testState, *testing.T, *datadriven.TestData) string{
-	"init":            (*testState).init,
-	"update_settings": (*testState).updateSettings,
-	"advance":         (*testState).advance,
-	"launch":          (*testState).launch,
-	"await":           (*testState).await,
-	"cancel":          (*testState).cancel,
-	"record_read":     (*testState).recordRead,
-	"timers":          (*testState).timers,
-	"metrics":         (*testState).metrics,
-	"get_tenants":     (*testState).getTenants,
-	"release_tenants": (*testState).releaseTenants,
-	"estimate_iops":   (*testState).estimateIOPS,
+	"init":                     (*testState).init,
+	"update_settings":          (*testState).updateSettings,
+	"advance":                  (*testState).advance,
+	"launch":                   (*testState).launch,
+	"await":                    (*testState).await,
+	"cancel":                   (*testState).cancel,
+	"record_read":              (*testState).recordRead,
+	"record_block_cache_usage": (*testState).recordBlockCacheUsage,
+	"timers":                   (*testState).timers,
+	"metrics":                  (*testState).metrics,
+	"get_tenants":              (*testState).getTenants,
+	"release_tenants":          (*testState).releaseTenants,
+	"estimate_iops":            (*testState).estimateIOPS,
 }
 
 func (ts *testState) run(t *testing.T, d *datadriven.TestData) string {
@@ -403,6 +404,36 @@ func (ts *testState) recordRead(t *testing.T, d *datadriven.TestData) string {
 	return ts.FormatRunning()
 }
 
+// recordBlockCacheUsage records the block cache usage of reads. It takes as
+// input a yaml list with fields tenant, blockbytes, and blockbytesincache. It
+// returns the set of tasks currently running like launch, await, and cancel.
+//
+// For example:
+//
+//	record_block_cache_usage
+//	- { tenant: 2, blockbytes: 100, blockbytesincache: 60 }
+//	----
+//	[]
+func (ts *testState) recordBlockCacheUsage(t *testing.T, d *datadriven.TestData) string {
+	var usages []struct {
+		Tenant            uint64
+		BlockBytes        int64
+		BlockBytesInCache int64
+	}
+	if err := yaml.UnmarshalStrict([]byte(d.Input), &usages); err != nil {
+		d.Fatalf(t, "failed to unmarshal block cache usages: %v", err)
+	}
+	for _, u := range usages {
+		tid := roachpb.MustMakeTenantID(u.Tenant)
+		lims := ts.tenants[tid]
+		if len(lims) == 0 {
+			d.Fatalf(t, "no outstanding limiters for %v", tid)
+		}
+		lims[0].RecordBlockCacheUsage(context.Background(), u.BlockBytes, u.BlockBytesInCache)
+	}
+	return ts.FormatRunning()
+}
+
 // metrics will print out the prometheus metric values. The command takes an
 // argument as a regular expression over the values. The metrics are printed in
 // lexicographical order. The command will retry until the output matches to
@@ -667,6 +698,8 @@ func (ts *testState) HasCapabilityForBatch(
 
 func (ts *testState) BindReader(tenantcapabilities.Reader) {}
 
+func (ts *testState) BindLiveBytesReader(tenantcapabilities.LiveBytesReader) {}
+
 var _ tenantcapabilities.Authorizer = &testState{}
 
 func (ts *testState) HasProcessDebugCapability(ctx context.Context, tenID roachpb.TenantID) error {
@@ -715,6 +748,24 @@ func (ts *testState) IsExemptFromRateLimiting(_ context.Context, tenID roachpb.T
 	return ts.capabilities[tenID].ExemptFromRateLimiting
 }
 
+func (ts *testState) GetMaxRequestsPerSecondPerNode(_ context.Context, tenID roachpb.TenantID) int64 {
+	return ts.capabilities[tenID].MaxRequestsPerSecondPerNode
+}
+
+func (ts *testState) GetMaxReadBytesPerSecondPerNode(_ context.Context, tenID roachpb.TenantID) int64 {
+	return ts.capabilities[tenID].MaxReadBytesPerSecondPerNode
+}
+
+func (ts *testState) GetMaxWriteBytesPerSecondPerNode(_ context.Context, tenID roachpb.TenantID) int64 {
+	return ts.capabilities[tenID].MaxWriteBytesPerSecondPerNode
+}
+
+func (ts *testState) GetMaxBlockCacheFillBytesPerSecondPerNode(
+	_ context.Context, tenID roachpb.TenantID,
+) int64 {
+	return ts.capabilities[tenID].MaxBlockCacheFillBytesPerSecondPerNode
+}
+
 func parseTenantIDs(t *testing.T, d *datadriven.TestData) []uint64 {
 	var tenantIDs []uint64
 	if err := yaml.UnmarshalStrict([]byte(d.Input), &tenantIDs); err != nil {
@@ -804,12 +855,27 @@ func (fakeAuthorizer) HasNodelocalStorageCapability(
 func (fakeAuthorizer) IsExemptFromRateLimiting(_ context.Context, tenID roachpb.TenantID) bool {
 	return false
 }
+func (fakeAuthorizer) GetMaxRequestsPerSecondPerNode(_ context.Context, tenID roachpb.TenantID) int64 {
+	return 0
+}
+func (fakeAuthorizer) GetMaxReadBytesPerSecondPerNode(_ context.Context, tenID roachpb.TenantID) int64 {
+	return 0
+}
+func (fakeAuthorizer) GetMaxWriteBytesPerSecondPerNode(_ context.Context, tenID roachpb.TenantID) int64 {
+	return 0
+}
+func (fakeAuthorizer) GetMaxBlockCacheFillBytesPerSecondPerNode(
+	_ context.Context, tenID roachpb.TenantID,
+) int64 {
+	return 0
+}
 func (fakeAuthorizer) HasCapabilityForBatch(
 	_ context.Context, tenID roachpb.TenantID, _ *kvpb.BatchRequest,
 ) error {
 	return nil
 }
-func (fakeAuthorizer) BindReader(tenantcapabilities.Reader) {}
+func (fakeAuthorizer) BindReader(tenantcapabilities.Reader)                   {}
+func (fakeAuthorizer) BindLiveBytesReader(tenantcapabilities.LiveBytesReader) {}
 
 func (fakeAuthorizer) HasProcessDebugCapability(ctx context.Context, tenID roachpb.TenantID) error {
 	return nil
@@ -27,6 +27,12 @@ type Metrics struct {
 	WriteRequestsAdmitted *aggmetric.AggCounter
 	ReadBytesAdmitted     *aggmetric.AggCounter
 	WriteBytesAdmitted    *aggmetric.AggCounter
+	ReadBatchesDelayed    *aggmetric.AggCounter
+	ReadBatchesRejected   *aggmetric.AggCounter
+	WriteBytesDelayed     *aggmetric.AggCounter
+	WriteBytesRejected    *aggmetric.AggCounter
+	BlockBytes            *aggmetric.AggCounter
+	BlockBytesInCache     *aggmetric.AggCounter
 }
 
 var _ metric.Struct = (*Metrics)(nil)
@@ -80,6 +86,45 @@ var (
 		Measurement: "Bytes",
 		Unit:        metric.Unit_BYTES,
 	}
+	metaReadBatchesDelayed = metric.Metadata{
+		Name:        "kv.tenant_rate_limit.read_batches_delayed",
+		Help:        "Number of read batches delayed by the read bandwidth or block cache fill limits",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaReadBatchesRejected = metric.Metadata{
+		Name:        "kv.tenant_rate_limit.read_batches_rejected",
+		Help:        "Number of read batches that gave up waiting for the read bandwidth or block cache fill limits",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaWriteBytesDelayed = metric.Metadata{
+		Name:        "kv.tenant_rate_limit.write_bytes_delayed",
+		Help:        "Number of write bytes delayed by the write bandwidth limit",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaWriteBytesRejected = metric.Metadata{
+		Name:        "kv.tenant_rate_limit.write_bytes_rejected",
+		Help:        "Number of write bytes in batches that gave up waiting for the write bandwidth limit",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaBlockBytes = metric.Metadata{
+		Name: "kv.tenant_block_cache.block_bytes",
+		Help: "Number of bytes in sstable data blocks loaded by reads, whether or not " +
+			"they were served from the block cache",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaBlockBytesInCache = metric.Metadata{
+		Name: "kv.tenant_block_cache.block_bytes_in_cache",
+		Help: "Number of bytes in sstable data blocks loaded by reads that were served " +
+			"from the block cache; the ratio to kv.tenant_block_cache.block_bytes is the " +
+			"block cache hit rate",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
 )
 
 func makeMetrics() Metrics {
@@ -93,6 +138,12 @@ func makeMetrics() Metrics {
 		WriteRequestsAdmitted: b.Counter(metaWriteRequestsAdmitted),
 		ReadBytesAdmitted:     b.Counter(metaReadBytesAdmitted),
 		WriteBytesAdmitted:    b.Counter(metaWriteBytesAdmitted),
+		ReadBatchesDelayed:    b.Counter(metaReadBatchesDelayed),
+		ReadBatchesRejected:   b.Counter(metaReadBatchesRejected),
+		WriteBytesDelayed:     b.Counter(metaWriteBytesDelayed),
+		WriteBytesRejected:    b.Counter(metaWriteBytesRejected),
+		BlockBytes:            b.Counter(metaBlockBytes),
+		BlockBytesInCache:     b.Counter(metaBlockBytesInCache),
 	}
 }
 
@@ -108,6 +159,12 @@ type tenantMetrics struct {
 	writeRequestsAdmitted *aggmetric.Counter
 	readBytesAdmitted     *aggmetric.Counter
 	writeBytesAdmitted    *aggmetric.Counter
+	readBatchesDelayed    *aggmetric.Counter
+	readBatchesRejected   *aggmetric.Counter
+	writeBytesDelayed     *aggmetric.Counter
+	writeBytesRejected    *aggmetric.Counter
+	blockBytes            *aggmetric.Counter
+	blockBytesInCache     *aggmetric.Counter
 }
 
 func (m *Metrics) tenantMetrics(tenantID roachpb.TenantID) tenantMetrics {
@@ -120,6 +177,12 @@ func (m *Metrics) tenantMetrics(tenantID roachpb.TenantID) tenantMetrics {
 		writeRequestsAdmitted: m.WriteRequestsAdmitted.AddChild(tid),
 		readBytesAdmitted:     m.ReadBytesAdmitted.AddChild(tid),
 		writeBytesAdmitted:    m.WriteBytesAdmitted.AddChild(tid),
+		readBatchesDelayed:    m.ReadBatchesDelayed.AddChild(tid),
+		readBatchesRejected:   m.ReadBatchesRejected.AddChild(tid),
+		writeBytesDelayed:     m.WriteBytesDelayed.AddChild(tid),
+		writeBytesRejected:    m.WriteBytesRejected.AddChild(tid),
+		blockBytes:            m.BlockBytes.AddChild(tid),
+		blockBytesInCache:     m.BlockBytesInCache.AddChild(tid),
 	}
 }
 
@@ -131,4 +194,10 @@ func (tm *tenantMetrics) unlink() {
 	tm.writeRequestsAdmitted.Unlink()
 	tm.readBytesAdmitted.Unlink()
 	tm.writeBytesAdmitted.Unlink()
+	tm.readBatchesDelayed.Unlink()
+	tm.readBatchesRejected.Unlink()
+	tm.writeBytesDelayed.Unlink()
+	tm.writeBytesRejected.Unlink()
+	tm.blockBytes.Unlink()
+	tm.blockBytesInCache.Unlink()
 }
@@ -39,4 +39,11 @@ func (s systemLimiter) RecordRead(ctx context.Context, respInfo tenantcostmodel.
 	}
 }
 
+func (s systemLimiter) RecordBlockCacheUsage(
+	ctx context.Context, blockBytes, blockBytesInCache int64,
+) {
+	s.blockBytes.Inc(blockBytes)
+	s.blockBytesInCache.Inc(blockBytesInCache)
+}
+
 var _ Limiter = (*systemLimiter)(nil)
@@ -275,6 +275,11 @@ func (m *MockTransactionalSender) HasPerformedWrites() bool {
 	panic("unimplemented")
 }
 
+// HasAcquiredLocks is part of TxnSenderFactory.
+func (m *MockTransactionalSender) HasAcquiredLocks() bool {
+	panic("unimplemented")
+}
+
 // TestingShouldRetry is part of TxnSenderFactory.
 func (m *MockTransactionalSender) TestingShouldRetry() bool {
 	return false
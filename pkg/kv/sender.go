@@ -362,6 +362,10 @@ type TxnSender interface {
 	// HasPerformedWrites returns true if a write has been performed.
 	HasPerformedWrites() bool
 
+	// HasAcquiredLocks returns true if the transaction has attempted to acquire
+	// any locks, either by writing or through locking reads.
+	HasAcquiredLocks() bool
+
 	// TestingShouldRetry returns true if transaction retry errors should be
 	// randomly returned to callers. Note that it is the responsibility of
 	// (*kv.DB).Txn() to return the retries. This lives here since the
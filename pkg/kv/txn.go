@@ -490,6 +490,15 @@ func (txn *Txn) SetOmitInRangefeeds() {
 	txn.mu.sender.SetOmitInRangefeeds()
 }
 
+// SetAdmissionPriority overrides the priority with which the work of this
+// transaction is admitted.
+//
+// SetAdmissionPriority must be called before any operations are performed on
+// the transaction.
+func (txn *Txn) SetAdmissionPriority(priority admissionpb.WorkPriority) {
+	txn.admissionHeader.Priority = int32(priority)
+}
+
 // NewBatch creates and returns a new empty batch object for use with the Txn.
 func (txn *Txn) NewBatch() *Batch {
 	return &Batch{txn: txn, AdmissionHeader: txn.AdmissionHeader()}
@@ -735,9 +744,31 @@ func (txn *Txn) ReverseScanForShare(
 // must not be used for side-effects before the txn has committed.
 func (txn *Txn) Iterate(
 	ctx context.Context, begin, end interface{}, pageSize int, f func([]KeyValue) error,
+) error {
+	return txn.iterate(ctx, begin, end, pageSize, false /* isReverse */, f)
+}
+
+// ReverseIterate performs a paginated reverse scan and applies the function f
+// to every page. The semantics of retrieval and ordering are the same as for
+// ReverseScan, and the same caveats as for Iterate apply.
+//
+// Note that SQL scans don't use it: the row fetchers paginate reverse scans
+// themselves, with the same BatchRequest limits as forward scans.
+func (txn *Txn) ReverseIterate(
+	ctx context.Context, begin, end interface{}, pageSize int, f func([]KeyValue) error,
+) error {
+	return txn.iterate(ctx, begin, end, pageSize, true /* isReverse */, f)
+}
+
+func (txn *Txn) iterate(
+	ctx context.Context,
+	begin, end interface{},
+	pageSize int,
+	isReverse bool,
+	f func([]KeyValue) error,
 ) error {
 	for {
-		rows, err := txn.Scan(ctx, begin, end, int64(pageSize))
+		rows, err := txn.scan(ctx, begin, end, int64(pageSize), isReverse, kvpb.NonLocking, kvpb.Invalid)
 		if err != nil {
 			return err
 		}
@@ -750,7 +781,13 @@ func (txn *Txn) Iterate(
 		if len(rows) < pageSize {
 			return nil
 		}
-		begin = rows[len(rows)-1].Key.Next()
+		if isReverse {
+			// The end key is exclusive, so the next page picks up right below the
+			// smallest key returned so far.
+			end = rows[len(rows)-1].Key
+		} else {
+			begin = rows[len(rows)-1].Key.Next()
+		}
 	}
 }
 
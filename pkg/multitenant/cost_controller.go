@@ -12,9 +12,12 @@ package multitenant
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -41,14 +44,136 @@ type TenantSideCostController interface {
 	// is using.
 	GetCostConfig() *tenantcostmodel.Config
 
+	// GetHostConsumptionHistory returns snapshots of the total consumption of
+	// the tenant across all of its SQL instances, as recorded by the host
+	// cluster in its most recent responses to the token bucket requests of this
+	// instance, oldest first. At most HostConsumptionHistorySize snapshots are
+	// retained.
+	GetHostConsumptionHistory() []HostConsumptionSnapshot
+
+	// GetCostHistory returns the samples of the consumption and throttling of
+	// this SQL instance recorded over the last CostHistoryRetention, oldest
+	// first.
+	GetCostHistory() []CostSample
+
+	// GetIndexReadUsage returns the read consumption of this SQL instance per
+	// index, as extrapolated from a sample of its KV reads, for the limit
+	// indexes with the highest read cost, in decreasing order of cost.
+	GetIndexReadUsage(limit int) []IndexReadUsage
+
+	// GetRangeCosts returns the recent consumption of this SQL instance per
+	// range, as extrapolated from a sample of its KV requests, in no particular
+	// order.
+	GetRangeCosts() []RangeCost
+
 	// Metrics returns a metric.Struct which holds metrics for the controller.
 	Metrics() metric.Struct
 
+	// OnBackgroundWorkWait blocks for as long as the tenant is running low on
+	// RUs, so that background work (e.g. row-level TTL deletions) slows down
+	// before foreground traffic gets throttled. It returns an error if the wait
+	// is canceled.
+	//
+	// If the context (or a parent context) was created using
+	// WithTenantCostControlExemption, the method is a no-op.
+	OnBackgroundWorkWait(ctx context.Context) error
+
 	TenantSideKVInterceptor
 
 	TenantSideExternalIORecorder
 }
 
+// CostHistoryRetention is the period over which the TenantSideCostController
+// retains CostSamples.
+const CostHistoryRetention = time.Hour
+
+// HostConsumptionHistorySize is the number of HostConsumptionSnapshots
+// retained by the TenantSideCostController. This covers an hour at the default
+// period of token bucket requests of 10s.
+const HostConsumptionHistorySize = 360
+
+// HostConsumptionSnapshot is the total consumption of a tenant, as recorded by
+// the host cluster in a response to a token bucket request.
+type HostConsumptionSnapshot struct {
+	// Time is the time at which the SQL instance received the response.
+	Time time.Time
+
+	// Consumption is the total consumption of the tenant across all of its SQL
+	// instances.
+	Consumption kvpb.TenantConsumption
+}
+
+// CostSample describes the consumption and throttling of a SQL instance since
+// the previous sample, as periodically recorded by the
+// TenantSideCostController.
+type CostSample struct {
+	// Time is the time at which the sample was recorded.
+	Time time.Time
+
+	// RU is the number of request units consumed since the previous sample.
+	RU float64
+
+	// EstimatedCPUSeconds is the estimated CPU usage of the tenant (in CPU
+	// secs) since the previous sample. It is only set when the tenant is billed
+	// under the estimated CPU model.
+	EstimatedCPUSeconds float64
+
+	// BlockedRequests is the number of requests blocked by the rate limiter at
+	// the time of the sample.
+	BlockedRequests int64
+
+	// WaitDurationP50, WaitDurationP90 and WaitDurationP99 are percentiles of
+	// the time recently spent by blocked requests waiting for RUs.
+	WaitDurationP50 time.Duration
+	WaitDurationP90 time.Duration
+	WaitDurationP99 time.Duration
+}
+
+// IndexReadUsage describes the read consumption of a SQL instance attributed to
+// an index, as recorded by the TenantSideCostController. It is extrapolated
+// from a sample of the KV reads of the instance.
+type IndexReadUsage struct {
+	// TableID and IndexID identify the index.
+	TableID uint32
+	IndexID uint32
+
+	// RU is the number of request units consumed by reads of the index.
+	RU float64
+
+	// ReadBytes and ReadRequests are the number of bytes and requests read
+	// from the index.
+	ReadBytes    int64
+	ReadRequests int64
+
+	// SampledBatches is the number of read batches that were sampled to
+	// estimate the usage.
+	SampledBatches int64
+}
+
+// RangeCost describes the recent consumption of a SQL instance attributed to a
+// range, as recorded by the TenantSideCostController. It is extrapolated from a
+// sample of the KV requests of the instance, recorded since Since.
+type RangeCost struct {
+	RangeID roachpb.RangeID
+	Since   time.Time
+
+	// RU is the number of request units consumed by requests to the range.
+	RU float64
+
+	// KVCPUSeconds is the estimated CPU consumed by KV to serve requests to the
+	// range.
+	KVCPUSeconds float64
+
+	// ReadBytes and WriteBytes are the number of bytes read from and written to
+	// the range.
+	ReadBytes  int64
+	WriteBytes int64
+
+	// SampledBatches is the number of batches that were sampled to estimate the
+	// consumption.
+	SampledBatches int64
+}
+
 // ExternalUsage contains information about usage that is not tracked through
 // TenantSideKVInterceptor or TenantSideExternalIORecorder.
 type ExternalUsage struct {
@@ -82,22 +207,52 @@ type TenantSideKVInterceptor interface {
 	// actual costs are only accounted for by the OnResponseWait method.
 	//
 	// If the context (or a parent context) was created using
-	// WithTenantCostControlExemption, the method is a no-op.
+	// WithTenantCostControlExemption, the method is a no-op. If it was created
+	// using WithBackupRUPacing, the backup rate limiter may be used instead. If
+	// it was created using WithResourceGroupStart, the method may also block
+	// until the resource group's start time while the tenant is throttled.
 	OnRequestWait(ctx context.Context) error
 
 	// OnResponseWait blocks until the rate limiter has enough capacity to allow
 	// the given request and response to be accounted for.
 	//
 	// If the context (or a parent context) was created using
-	// WithTenantCostControlExemption, the method is a no-op.
+	// WithTenantCostControlExemption, the method does not block, and the cost
+	// may be reported as system overhead instead. If it was created
+	// using WithBackupRUPacing, the backup rate limiter may be used instead.
 	OnResponseWait(
 		ctx context.Context, req tenantcostmodel.RequestInfo, resp tenantcostmodel.ResponseInfo,
 	) error
+
+	// IsThrottled returns true if the tenant is currently being throttled
+	// because it has exhausted its request units, according to the local token
+	// bucket. It can be used to slow down background work of the tenant.
+	//
+	// If the context (or a parent context) was created using
+	// WithTenantCostControlExemption, the method returns false.
+	IsThrottled(ctx context.Context) bool
+
+	// CheckLiveBytesLimit returns an error if the live bytes of the tenant
+	// exceeded the limit set by its max_live_bytes capability, as of the last
+	// response from the host cluster. It is called before sending KV batches that
+	// add data.
+	//
+	// If the context (or a parent context) was created using
+	// WithTenantCostControlExemption, the method is a no-op.
+	CheckLiveBytesLimit(ctx context.Context) error
+
+	// PreferredBatchBytes returns the size in bytes that the KV batches sent by
+	// the SQL layer should preferably reach, based on the state of the token
+	// bucket, or 0 if there is no preference. While the tenant is throttled,
+	// larger batches amortize the per-batch cost of KV requests.
+	PreferredBatchBytes() int64
 }
 
 // WithTenantCostControlExemption generates a child context which will cause the
-// TenantSideKVInterceptor to ignore the respective operations. This is used for
-// important internal traffic that we don't want to stall (or be accounted for).
+// TenantSideKVInterceptor to never throttle the respective operations, and to
+// report their cost as system overhead rather than charging it to the tenant
+// (unless tenant_cost_control.system_overhead_exemption.enabled is false). This
+// is used for important internal traffic that we don't want to stall.
 func WithTenantCostControlExemption(ctx context.Context) context.Context {
 	return context.WithValue(ctx, exemptCtxValue, exemptCtxValue)
 }
@@ -108,6 +263,67 @@ func HasTenantCostControlExemption(ctx context.Context) bool {
 	return ctx.Value(exemptCtxValue) != nil
 }
 
+// WithBackupRUPacing generates a child context which marks the respective
+// operations as part of a backup. If the tenant has a dedicated backup token
+// bucket configured, such operations are paced by that bucket instead of the
+// tenant's main token bucket, and their RUs are reported separately, so that
+// backups neither starve nor are starved by foreground traffic.
+func WithBackupRUPacing(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backupCtxValue, backupCtxValue)
+}
+
+// HasBackupRUPacing returns true if this context or one of its parent contexts
+// was created using WithBackupRUPacing.
+func HasBackupRUPacing(ctx context.Context) bool {
+	return ctx.Value(backupCtxValue) != nil
+}
+
+// WithResourceGroupStart generates a child context which marks the respective
+// operations as part of a resource group whose fair share of the tenant's
+// resources allows them to start at the given time. While the tenant is
+// throttled, such operations are delayed until then, so that resource groups
+// exceeding their share yield to the other groups of the tenant.
+func WithResourceGroupStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, resourceGroupCtxValue, start)
+}
+
+// ResourceGroupStartFromContext returns the start time that the context (or
+// one of its parent contexts) was created with using WithResourceGroupStart.
+func ResourceGroupStartFromContext(ctx context.Context) (start time.Time, ok bool) {
+	start, ok = ctx.Value(resourceGroupCtxValue).(time.Time)
+	return start, ok
+}
+
+// RUObserver is notified of the RUs consumed by KV and external I/O operations.
+// Under the estimated CPU cost model, estimatedCPUSeconds is the estimated KV
+// CPU usage of the operation; otherwise, it is zero.
+type RUObserver func(ru tenantcostmodel.RU, estimatedCPUSeconds float64)
+
+// WithRUObserver generates a child context which causes the RUs consumed by
+// the KV and external I/O operations of the context to be reported to the
+// given observer, in addition to being accounted for as usual. This is used to
+// attribute consumption to individual jobs, tables or statements. If the
+// context already has an observer, both are notified, so that e.g. the
+// statements run by a job are attributed to both. The observer may be called
+// concurrently.
+func WithRUObserver(ctx context.Context, observer RUObserver) context.Context {
+	if parent := RUObserverFromContext(ctx); parent != nil {
+		child := observer
+		observer = func(ru tenantcostmodel.RU, estimatedCPUSeconds float64) {
+			child(ru, estimatedCPUSeconds)
+			parent(ru, estimatedCPUSeconds)
+		}
+	}
+	return context.WithValue(ctx, ruObserverCtxValue, observer)
+}
+
+// RUObserverFromContext returns the observer that the context (or one of its
+// parent contexts) was created with using WithRUObserver, or nil.
+func RUObserverFromContext(ctx context.Context) RUObserver {
+	observer, _ := ctx.Value(ruObserverCtxValue).(RUObserver)
+	return observer
+}
+
 // ExternalIOUsage specifies the amount of external I/O that has been consumed.
 type ExternalIOUsage struct {
 	IngressBytes int64
@@ -121,17 +337,44 @@ type TenantSideExternalIORecorder interface {
 	// the external I/O operation. It returns an error if the wait is canceled.
 	//
 	// If the context (or a parent context) was created using
-	// WithTenantCostControlExemption, the method is a no-op.
+	// WithTenantCostControlExemption, the method does not block, and the cost
+	// may be reported as system overhead instead. If it was created
+	// using WithBackupRUPacing, the backup rate limiter may be used instead.
 	OnExternalIOWait(ctx context.Context, usage ExternalIOUsage) error
 
 	// OnExternalIO reports ingress/egress that has occurred, without any
 	// blocking.
 	//
 	// If the context (or a parent context) was created using
-	// WithTenantCostControlExemption, the method is a no-op.
+	// WithTenantCostControlExemption, the cost may be reported as system
+	// overhead instead.
 	OnExternalIO(ctx context.Context, usage ExternalIOUsage)
 }
 
+// MetaTotalSQLPodsCPUSeconds is the metadata of the metric reporting the CPU
+// usage of a virtual cluster. It is reported by the tenant-side cost controller
+// of separate-process virtual clusters, and from the CPU attributed to them for
+// virtual clusters running in shared-process mode.
+var MetaTotalSQLPodsCPUSeconds = metric.Metadata{
+	Name: "tenant.sql_usage.sql_pods_cpu_seconds",
+	Help: "Total amount of CPU used by SQL pods, or attributed to the virtual " +
+		"cluster in shared-process mode",
+	Measurement: "CPU Seconds",
+	Unit:        metric.Unit_SECONDS,
+}
+
 type exemptCtxValueType struct{}
 
 var exemptCtxValue interface{} = exemptCtxValueType{}
+
+type backupCtxValueType struct{}
+
+var backupCtxValue interface{} = backupCtxValueType{}
+
+type ruObserverCtxValueType struct{}
+
+type resourceGroupCtxValueType struct{}
+
+var resourceGroupCtxValue interface{} = resourceGroupCtxValueType{}
+
+var ruObserverCtxValue interface{} = ruObserverCtxValueType{}
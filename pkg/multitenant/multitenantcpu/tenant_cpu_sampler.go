@@ -0,0 +1,254 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package multitenantcpu
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/google/pprof/profile"
+)
+
+var cpuAttributionInterval = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"server.shared_process.cpu_attribution.interval",
+	"how often a short CPU profile is taken to attribute the CPU usage of the process "+
+		"to the virtual clusters running in shared-process mode; 0 disables the attribution",
+	0,
+	settings.NonNegativeDuration,
+)
+
+// cpuAttributionProfileDuration is the duration of each CPU profile taken to
+// measure the share of the process CPU used by each virtual cluster.
+const cpuAttributionProfileDuration = time.Second
+
+// TenantLabel is the pprof label set on all the goroutines of a virtual
+// cluster running in shared-process mode, including those serving its SQL
+// connections. Goroutines without the label belong to the system tenant.
+const TenantLabel = "cluster"
+
+// NewSharedProcessCPUSecondsCounter returns the counter used to report the CPU
+// usage attributed to a virtual cluster running in shared-process mode. It
+// mirrors the counter reported by the tenant-side cost controller of
+// separate-process virtual clusters.
+func NewSharedProcessCPUSecondsCounter() *metric.CounterFloat64 {
+	return metric.NewCounterFloat64(multitenant.MetaTotalSQLPodsCPUSeconds)
+}
+
+// TenantCPUSampler attributes the CPU usage of the process to the virtual
+// clusters running in shared-process mode.
+//
+// Periodically, it takes a short CPU profile and computes the share of the
+// sampled CPU time spent by the goroutines of each virtual cluster, as
+// identified by their pprof labels. The CPU used by the process until the next
+// profile is then attributed to each virtual cluster according to that share.
+// The attributed CPU time is cumulative and never decreases.
+type TenantCPUSampler struct {
+	st *cluster.Settings
+
+	// processCPUFn returns the total CPU usage of the process, in seconds.
+	processCPUFn func(context.Context) float64
+
+	mu struct {
+		syncutil.Mutex
+		// lastProcessCPU is the CPU usage of the process at the time the
+		// shares were last updated.
+		lastProcessCPU float64
+		// shares contains the fraction of the process CPU used by each
+		// virtual cluster, as of the last profile.
+		shares map[roachpb.TenantName]float64
+		// attributed contains the CPU seconds attributed to each virtual
+		// cluster up to lastProcessCPU.
+		attributed map[roachpb.TenantName]float64
+		// counters contains the metrics of the registered virtual clusters.
+		counters map[roachpb.TenantName]*metric.CounterFloat64
+	}
+}
+
+// NewTenantCPUSampler creates a TenantCPUSampler. Start must be called for the
+// CPU usage to be attributed.
+func NewTenantCPUSampler(st *cluster.Settings) *TenantCPUSampler {
+	s := &TenantCPUSampler{
+		st:           st,
+		processCPUFn: GetCPUSeconds,
+	}
+	s.mu.shares = make(map[roachpb.TenantName]float64)
+	s.mu.attributed = make(map[roachpb.TenantName]float64)
+	s.mu.counters = make(map[roachpb.TenantName]*metric.CounterFloat64)
+	return s
+}
+
+// Start starts the periodic attribution of the process CPU.
+func (s *TenantCPUSampler) Start(ctx context.Context, stopper *stop.Stopper) error {
+	s.mu.Lock()
+	s.mu.lastProcessCPU = s.processCPUFn(ctx)
+	s.mu.Unlock()
+
+	return stopper.RunAsyncTask(ctx, "tenant-cpu-sampler", func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			interval := cpuAttributionInterval.Get(&s.st.SV)
+			if interval == 0 {
+				// Check again later whether the attribution was enabled.
+				interval = time.Minute
+			} else if s.hasTenants() {
+				s.sample(ctx, stopper)
+			}
+			timer.Reset(interval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+// Register registers a virtual cluster with the sampler. The given counter, if
+// not nil, is updated with the CPU seconds attributed to the virtual cluster
+// every time a profile is taken. The returned function unregisters the virtual
+// cluster.
+func (s *TenantCPUSampler) Register(
+	name roachpb.TenantName, counter *metric.CounterFloat64,
+) (unregister func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.counters[name] = counter
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.mu.counters, name)
+	}
+}
+
+// CPUSeconds returns the total CPU usage, in seconds, attributed to the given
+// virtual cluster since the sampler was started.
+func (s *TenantCPUSampler) CPUSeconds(ctx context.Context, name roachpb.TenantName) float64 {
+	processCPU := s.processCPUFn(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cpuSecondsLocked(name, processCPU)
+}
+
+func (s *TenantCPUSampler) cpuSecondsLocked(name roachpb.TenantName, processCPU float64) float64 {
+	delta := processCPU - s.mu.lastProcessCPU
+	if delta < 0 {
+		// The shares were updated after processCPU was measured.
+		delta = 0
+	}
+	return s.mu.attributed[name] + delta*s.mu.shares[name]
+}
+
+func (s *TenantCPUSampler) hasTenants() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.mu.counters) > 0
+}
+
+// sample takes a CPU profile and updates the shares of the virtual clusters.
+// If another CPU profile is in progress, the previous shares are kept.
+func (s *TenantCPUSampler) sample(ctx context.Context, stopper *stop.Stopper) {
+	var buf bytes.Buffer
+	if err := s.st.SetCPUProfiling(cluster.CPUProfileDefault); err != nil {
+		log.VEventf(ctx, 2, "skipping CPU attribution: %v", err)
+		return
+	}
+	err := func() error {
+		defer func() { _ = s.st.SetCPUProfiling(cluster.CPUProfileNone) }()
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return err
+		}
+		defer pprof.StopCPUProfile()
+		select {
+		case <-time.After(cpuAttributionProfileDuration):
+		case <-stopper.ShouldQuiesce():
+		}
+		return nil
+	}()
+	if err != nil {
+		log.VEventf(ctx, 2, "skipping CPU attribution: %v", err)
+		return
+	}
+	p, err := profile.Parse(&buf)
+	if err != nil {
+		log.Warningf(ctx, "unable to parse CPU profile: %v", err)
+		return
+	}
+	s.update(s.processCPUFn(ctx), cpuSharesFromProfile(p))
+}
+
+// update attributes the CPU used by the process since the last update
+// according to the previous shares, then installs the new shares. The
+// previous shares are kept if shares is nil.
+func (s *TenantCPUSampler) update(processCPU float64, shares map[roachpb.TenantName]float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if processCPU > s.mu.lastProcessCPU {
+		for name := range s.mu.shares {
+			s.mu.attributed[name] = s.cpuSecondsLocked(name, processCPU)
+		}
+		s.mu.lastProcessCPU = processCPU
+	}
+	if shares != nil {
+		s.mu.shares = shares
+	}
+	for name, counter := range s.mu.counters {
+		if counter != nil {
+			counter.UpdateIfHigher(s.mu.attributed[name])
+		}
+	}
+}
+
+// cpuSharesFromProfile returns the fraction of the sampled CPU time spent by
+// the goroutines of each virtual cluster. It returns nil if the profile
+// contains no samples.
+func cpuSharesFromProfile(p *profile.Profile) map[roachpb.TenantName]float64 {
+	valueIdx := -1
+	for i, st := range p.SampleType {
+		if st.Type == "cpu" {
+			valueIdx = i
+			break
+		}
+	}
+	if valueIdx < 0 {
+		return nil
+	}
+	var total int64
+	perTenant := make(map[roachpb.TenantName]int64)
+	for _, sample := range p.Sample {
+		v := sample.Value[valueIdx]
+		total += v
+		if labels := sample.Label[TenantLabel]; len(labels) > 0 {
+			perTenant[roachpb.TenantName(labels[0])] += v
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	shares := make(map[roachpb.TenantName]float64, len(perTenant))
+	for name, v := range perTenant {
+		shares[name] = float64(v) / float64(total)
+	}
+	return shares
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package multitenantcpu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCPUSharesFromProfile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sample := func(cpu int64, tenant string) *profile.Sample {
+		s := &profile.Sample{Value: []int64{1, cpu}}
+		if tenant != "" {
+			s.Label = map[string][]string{TenantLabel: {tenant}}
+		}
+		return s
+	}
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "samples", Unit: "count"},
+			{Type: "cpu", Unit: "nanoseconds"},
+		},
+		Sample: []*profile.Sample{
+			sample(500, ""),
+			sample(200, "a"),
+			sample(100, "a"),
+			sample(200, "b"),
+		},
+	}
+	require.Equal(t, map[roachpb.TenantName]float64{"a": 0.3, "b": 0.2}, cpuSharesFromProfile(p))
+
+	p.Sample = nil
+	require.Nil(t, cpuSharesFromProfile(p))
+}
+
+func TestTenantCPUSampler(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	var processCPU float64
+	s := NewTenantCPUSampler(cluster.MakeTestingClusterSettings())
+	s.processCPUFn = func(context.Context) float64 { return processCPU }
+
+	counter := NewSharedProcessCPUSecondsCounter()
+	unregister := s.Register("a", counter)
+	defer unregister()
+	require.True(t, s.hasTenants())
+
+	// Nothing is attributed before the first profile.
+	processCPU = 10
+	require.Zero(t, s.CPUSeconds(ctx, "a"))
+	s.update(processCPU, map[roachpb.TenantName]float64{"a": 0.5, "b": 0.25})
+	require.Zero(t, s.CPUSeconds(ctx, "a"))
+
+	// The CPU used since the last profile is attributed according to the
+	// current shares.
+	processCPU = 14
+	require.Equal(t, 2.0, s.CPUSeconds(ctx, "a"))
+	require.Equal(t, 1.0, s.CPUSeconds(ctx, "b"))
+	require.Zero(t, s.CPUSeconds(ctx, "c"))
+
+	// A new profile doesn't change the CPU attributed so far.
+	s.update(processCPU, map[roachpb.TenantName]float64{"a": 0.1})
+	require.Equal(t, 2.0, s.CPUSeconds(ctx, "a"))
+	require.Equal(t, 1.0, s.CPUSeconds(ctx, "b"))
+	require.Equal(t, 2.0, counter.Count())
+
+	processCPU = 24
+	require.Equal(t, 3.0, s.CPUSeconds(ctx, "a"))
+	require.Equal(t, 1.0, s.CPUSeconds(ctx, "b"))
+
+	// An empty profile keeps the previous shares.
+	s.update(processCPU, nil)
+	processCPU = 34
+	require.Equal(t, 4.0, s.CPUSeconds(ctx, "a"))
+	require.Equal(t, 3.0, counter.Count())
+
+	unregister()
+	require.False(t, s.hasTenants())
+}
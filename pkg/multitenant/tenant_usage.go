@@ -65,6 +65,124 @@ type TenantUsageServer interface {
 		asOfConsumedRequestUnits float64,
 	) error
 
+	// GrantBurst temporarily increases the refill rate of a tenant's token
+	// bucket by burstRate (in RU/s), for the given duration. It replaces any
+	// burst previously granted to the tenant; a zero burstRate revokes it.
+	GrantBurst(
+		ctx context.Context,
+		txn isql.Txn,
+		tenantID roachpb.TenantID,
+		burstRate float64,
+		duration time.Duration,
+	) error
+
+	// GetTenantConsumption returns the current consumption and token bucket
+	// state of a tenant, along with its recent consumption rate and throttling
+	// events as observed by this node.
+	GetTenantConsumption(
+		ctx context.Context, txn isql.Txn, tenantID roachpb.TenantID,
+	) (TenantConsumptionInfo, error)
+
+	// GetConsumptionSummaries returns a summary of the consumption of each
+	// tenant over the given window, computed from the rollups recorded by this
+	// node while serving token bucket requests. Tenants that did not send token
+	// bucket requests to this node during the window are not included.
+	GetConsumptionSummaries(window time.Duration) []TenantConsumptionSummary
+
+	// GetThrottledTenants returns the tenants whose token bucket is in debt,
+	// i.e. that are being throttled by tenant cost control. It is computed from
+	// the state of the buckets in the tenant_usage system table, which is the
+	// same on all nodes, and does not depend on what the tenants report.
+	GetThrottledTenants(ctx context.Context) ([]roachpb.TenantID, error)
+
 	// Metrics returns the top-level metrics.
 	Metrics() metric.Struct
 }
+
+// TenantDataSizeFn returns the logical live and total bytes of a tenant's data
+// across the cluster.
+type TenantDataSizeFn func(
+	ctx context.Context, tenantID roachpb.TenantID,
+) (liveBytes, totalBytes int64, _ error)
+
+// TenantDataSizeReader provides access to the storage used by tenants, as
+// periodically measured by the host cluster in the background. It is used by
+// the TenantUsageServer to meter the storage used by tenants without measuring
+// it while serving token bucket requests.
+type TenantDataSizeReader interface {
+	// GetDataSize returns the logical live and total bytes of the tenant's data
+	// across the cluster, as of the last measurement, and whether the data was
+	// measured. Asking for the data size of a tenant also keeps it measured in
+	// the background for a while.
+	GetDataSize(id roachpb.TenantID) (liveBytes, totalBytes int64, found bool)
+}
+
+// TenantConsumptionInfo describes the consumption of a tenant and the state of
+// its token bucket, as returned by TenantUsageServer.GetTenantConsumption.
+type TenantConsumptionInfo struct {
+	// Present is false if the tenant has never requested tokens, in which case
+	// the bucket state and consumption are not set.
+	Present bool
+
+	// LastUpdate is the time of the last token bucket request from any of the
+	// tenant's SQL instances.
+	LastUpdate time.Time
+
+	// RUAvailable is the current amount of RUs in the bucket. It is negative
+	// when the tenant is in debt.
+	RUAvailable float64
+	// RURefillRate is the rate at which the bucket is refilled, in RU/s.
+	RURefillRate float64
+	// RUBurstLimit is the maximum amount of RUs that can be accumulated in the
+	// bucket, or 0 if there is no limit.
+	RUBurstLimit float64
+
+	// Consumption is the cumulative consumption of the tenant.
+	Consumption kvpb.TenantConsumption
+
+	// HasRecentStats is true if this node has served token bucket requests for
+	// the tenant since it started; the fields below are only set if so.
+	HasRecentStats bool
+	// RURate is a moving average of the tenant's RU consumption, in RU/s.
+	RURate float64
+	// RecentThrottleEvents is the number of token bucket requests that could
+	// not be fully granted during the last RecentThrottleWindow.
+	RecentThrottleEvents int
+	// LastThrottled is the time of the most recent token bucket request that
+	// could not be fully granted, or zero if there was none.
+	LastThrottled time.Time
+}
+
+// RecentThrottleWindow is the period over which throttling events are
+// reported in TenantConsumptionInfo.
+const RecentThrottleWindow = 10 * time.Minute
+
+// TenantConsumptionRollup is a snapshot of the cumulative consumption of a
+// tenant, recorded periodically by the TenantUsageServer.
+type TenantConsumptionRollup struct {
+	Time time.Time
+	// RU is the cumulative RU consumption of the tenant.
+	RU float64
+	// SQLPodsCPUSeconds is the cumulative CPU usage of the tenant's SQL pods.
+	SQLPodsCPUSeconds float64
+	// LiveBytes is the live bytes of the tenant, as last measured by the host
+	// cluster.
+	LiveBytes uint64
+}
+
+// TenantConsumptionSummary describes the consumption of a tenant over a period
+// of time, as returned by TenantUsageServer.GetConsumptionSummaries.
+type TenantConsumptionSummary struct {
+	TenantID roachpb.TenantID
+	// Start and End are the oldest and the most recent rollups within the
+	// period. They are the same if there is a single rollup.
+	Start, End TenantConsumptionRollup
+}
+
+// ConsumptionRollupInterval is the minimum interval between two consecutive
+// rollups of the consumption of a tenant.
+const ConsumptionRollupInterval = time.Minute
+
+// ConsumptionRollupRetention is the period for which rollups are retained. It
+// is the maximum window supported by GetConsumptionSummaries.
+const ConsumptionRollupRetention = 24 * time.Hour
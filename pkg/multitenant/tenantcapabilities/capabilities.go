@@ -97,6 +97,59 @@ const (
 	// metrics, but this implementation is simpler).
 	CanViewAllMetrics // can_view_all_metrics
 
+	// MaxRequestsPerSecondPerNode, if positive, limits the rate of KV batch
+	// requests the tenant can issue to each KV node. It complements the request
+	// unit based throttling of tenant cost control with a hard cap enforced by
+	// the KV-side tenant rate limiter. The limit applies to each node
+	// independently, so the rate across the cluster can be up to the number of
+	// KV nodes times this value.
+	MaxRequestsPerSecondPerNode // max_requests_per_second_per_node
+
+	// MaxSQLConnectionsPerInstance, if positive, limits the number of
+	// concurrent SQL connections each of the tenant's SQL instances accepts.
+	// The limit is enforced by the SQL instances themselves, so the number of
+	// connections across the tenant can be up to the number of instances times
+	// this value.
+	MaxSQLConnectionsPerInstance // max_sql_connections_per_instance
+
+	// MaxLiveBytes, if positive, limits the live bytes of the tenant's data
+	// across the cluster. The usage is measured periodically by the KV nodes,
+	// whose tenant authorizer rejects the writes that add data once the tenant
+	// exceeds the limit.
+	MaxLiveBytes // max_live_bytes
+
+	// CostModel selects the cost model under which the tenant is billed (see
+	// tenantcostmodel.ModelVersion): 0 for Request Units, 1 for estimated
+	// vCPU-seconds. The tenant-side cost controller learns the model from the
+	// token bucket responses of the host.
+	CostModel // cost_model
+
+	// MaxSpanConfigs, if positive, limits the number of span configs the
+	// tenant can install. It's enforced by the host when the tenant's span
+	// config reconciliation updates its span configs, regardless of the limit
+	// the tenant enforces on itself (spanconfig.virtual_cluster.max_spans).
+	MaxSpanConfigs // max_span_configs
+
+	// MaxReadBytesPerSecondPerNode, if positive, limits the rate of bytes the
+	// tenant can read from each KV node. Like MaxRequestsPerSecondPerNode, it
+	// is enforced by the KV-side tenant rate limiter of each node
+	// independently.
+	MaxReadBytesPerSecondPerNode // max_read_bytes_per_second_per_node
+
+	// MaxWriteBytesPerSecondPerNode, if positive, limits the rate of bytes the
+	// tenant can write to each KV node. Like MaxRequestsPerSecondPerNode, it
+	// is enforced by the KV-side tenant rate limiter of each node
+	// independently.
+	MaxWriteBytesPerSecondPerNode // max_write_bytes_per_second_per_node
+
+	// MaxBlockCacheFillBytesPerSecondPerNode, if positive, limits the rate of
+	// bytes the tenant's reads can load into the block cache of each KV node,
+	// i.e. the bytes of the sstable blocks they read that were not already
+	// cached. This bounds how quickly the tenant can evict the blocks cached
+	// for other tenants. It is enforced by the KV-side tenant rate limiter of
+	// each node independently.
+	MaxBlockCacheFillBytesPerSecondPerNode // max_block_cache_fill_bytes_per_second_per_node
+
 	MaxCapabilityID ID = iota - 1
 )
 
@@ -117,18 +170,26 @@ func FromID(id ID) (Capability, bool) {
 }
 
 var capabilities = [MaxCapabilityID + 1]Capability{
-	CanAdminRelocateRange:  boolCapability(CanAdminRelocateRange),
-	CanAdminScatter:        boolCapability(CanAdminScatter),
-	CanAdminSplit:          boolCapability(CanAdminSplit),
-	CanAdminUnsplit:        boolCapability(CanAdminUnsplit),
-	CanCheckConsistency:    boolCapability(CanCheckConsistency),
-	CanUseNodelocalStorage: boolCapability(CanUseNodelocalStorage),
-	CanViewNodeInfo:        boolCapability(CanViewNodeInfo),
-	CanViewTSDBMetrics:     boolCapability(CanViewTSDBMetrics),
-	ExemptFromRateLimiting: boolCapability(ExemptFromRateLimiting),
-	TenantSpanConfigBounds: spanConfigBoundsCapability(TenantSpanConfigBounds),
-	CanDebugProcess:        boolCapability(CanDebugProcess),
-	CanViewAllMetrics:      boolCapability(CanViewAllMetrics),
+	CanAdminRelocateRange:                  boolCapability(CanAdminRelocateRange),
+	CanAdminScatter:                        boolCapability(CanAdminScatter),
+	CanAdminSplit:                          boolCapability(CanAdminSplit),
+	CanAdminUnsplit:                        boolCapability(CanAdminUnsplit),
+	CanCheckConsistency:                    boolCapability(CanCheckConsistency),
+	CanUseNodelocalStorage:                 boolCapability(CanUseNodelocalStorage),
+	CanViewNodeInfo:                        boolCapability(CanViewNodeInfo),
+	CanViewTSDBMetrics:                     boolCapability(CanViewTSDBMetrics),
+	ExemptFromRateLimiting:                 boolCapability(ExemptFromRateLimiting),
+	TenantSpanConfigBounds:                 spanConfigBoundsCapability(TenantSpanConfigBounds),
+	CanDebugProcess:                        boolCapability(CanDebugProcess),
+	CanViewAllMetrics:                      boolCapability(CanViewAllMetrics),
+	MaxRequestsPerSecondPerNode:            int64Capability(MaxRequestsPerSecondPerNode),
+	MaxSQLConnectionsPerInstance:           int64Capability(MaxSQLConnectionsPerInstance),
+	MaxLiveBytes:                           int64Capability(MaxLiveBytes),
+	CostModel:                              int64Capability(CostModel),
+	MaxSpanConfigs:                         int64Capability(MaxSpanConfigs),
+	MaxReadBytesPerSecondPerNode:           int64Capability(MaxReadBytesPerSecondPerNode),
+	MaxWriteBytesPerSecondPerNode:          int64Capability(MaxWriteBytesPerSecondPerNode),
+	MaxBlockCacheFillBytesPerSecondPerNode: int64Capability(MaxBlockCacheFillBytesPerSecondPerNode),
 }
 
 // EnableAll enables maximum access to services.
@@ -147,6 +208,10 @@ func EnableAll(t *tenantcapabilitiespb.TenantCapabilities) {
 			// No bound.
 			v.Set(nil)
 
+		case TypedValue[int64]:
+			// No limit.
+			v.Set(0)
+
 		default:
 			panic(errors.AssertionFailedf("unhandled type: %T", val))
 		}
@@ -36,6 +36,7 @@ type TypedCapability[T any] interface {
 
 type (
 	BoolCapability             = TypedCapability[bool]
+	Int64Capability            = TypedCapability[int64]
 	SpanConfigBoundsCapability = TypedCapability[*spanconfigbounds.Bounds]
 )
 
@@ -48,6 +49,15 @@ func (b boolCapability) Value(t *tenantcapabilitiespb.TenantCapabilities) BoolVa
 	return MustGetValueByID(t, b.ID()).(BoolValue)
 }
 
+type int64Capability ID
+
+func (b int64Capability) String() string                                 { return ID(b).String() }
+func (b int64Capability) SafeFormat(s interfaces.SafePrinter, verb rune) { s.Print(ID(b)) }
+func (b int64Capability) ID() ID                                         { return ID(b) }
+func (b int64Capability) Value(t *tenantcapabilitiespb.TenantCapabilities) Int64Value {
+	return MustGetValueByID(t, b.ID()).(Int64Value)
+}
+
 type spanConfigBoundsCapability ID
 
 func (b spanConfigBoundsCapability) String() string                                 { return ID(b).String() }
@@ -60,3 +70,4 @@ func (b spanConfigBoundsCapability) Value(
 }
 
 var _ TypedCapability[bool] = boolCapability(0)
+var _ TypedCapability[int64] = int64Capability(0)
@@ -20,7 +20,15 @@ func _() {
 	_ = x[TenantSpanConfigBounds-10]
 	_ = x[CanDebugProcess-11]
 	_ = x[CanViewAllMetrics-12]
-	_ = x[MaxCapabilityID-12]
+	_ = x[MaxRequestsPerSecondPerNode-13]
+	_ = x[MaxSQLConnectionsPerInstance-14]
+	_ = x[MaxLiveBytes-15]
+	_ = x[CostModel-16]
+	_ = x[MaxSpanConfigs-17]
+	_ = x[MaxReadBytesPerSecondPerNode-18]
+	_ = x[MaxWriteBytesPerSecondPerNode-19]
+	_ = x[MaxBlockCacheFillBytesPerSecondPerNode-20]
+	_ = x[MaxCapabilityID-20]
 }
 
 func (i ID) String() string {
@@ -49,25 +57,49 @@ func (i ID) String() string {
 		return "can_debug_process"
 	case CanViewAllMetrics:
 		return "can_view_all_metrics"
+	case MaxRequestsPerSecondPerNode:
+		return "max_requests_per_second_per_node"
+	case MaxSQLConnectionsPerInstance:
+		return "max_sql_connections_per_instance"
+	case MaxLiveBytes:
+		return "max_live_bytes"
+	case CostModel:
+		return "cost_model"
+	case MaxSpanConfigs:
+		return "max_span_configs"
+	case MaxReadBytesPerSecondPerNode:
+		return "max_read_bytes_per_second_per_node"
+	case MaxWriteBytesPerSecondPerNode:
+		return "max_write_bytes_per_second_per_node"
+	case MaxBlockCacheFillBytesPerSecondPerNode:
+		return "max_block_cache_fill_bytes_per_second_per_node"
 	default:
 		return "ID(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
 }
 
 var stringToCapabilityIDMap = map[string]ID{
-	"can_admin_relocate_range":  1,
-	"can_admin_scatter":         2,
-	"can_admin_split":           3,
-	"can_admin_unsplit":         4,
-	"can_use_nodelocal_storage": 5,
-	"can_view_node_info":        6,
-	"can_check_consistency":     7,
-	"can_view_tsdb_metrics":     8,
-	"exempt_from_rate_limiting": 9,
-	"span_config_bounds":        10,
-	"can_debug_process":         11,
-	"can_view_all_metrics":      12,
-	"MaxCapabilityID":           12,
+	"can_admin_relocate_range":                       1,
+	"can_admin_scatter":                              2,
+	"can_admin_split":                                3,
+	"can_admin_unsplit":                              4,
+	"can_use_nodelocal_storage":                      5,
+	"can_view_node_info":                             6,
+	"can_check_consistency":                          7,
+	"can_view_tsdb_metrics":                          8,
+	"exempt_from_rate_limiting":                      9,
+	"span_config_bounds":                             10,
+	"can_debug_process":                              11,
+	"can_view_all_metrics":                           12,
+	"max_requests_per_second_per_node":               13,
+	"max_sql_connections_per_instance":               14,
+	"max_live_bytes":                                 15,
+	"cost_model":                                     16,
+	"max_span_configs":                               17,
+	"max_read_bytes_per_second_per_node":             18,
+	"max_write_bytes_per_second_per_node":            19,
+	"max_block_cache_fill_bytes_per_second_per_node": 20,
+	"MaxCapabilityID":                                20,
 }
 
 var IDs = []ID{
@@ -81,6 +113,14 @@ var IDs = []ID{
 	CanViewAllMetrics,
 	CanViewNodeInfo,
 	CanViewTSDBMetrics,
+	CostModel,
 	ExemptFromRateLimiting,
+	MaxBlockCacheFillBytesPerSecondPerNode,
+	MaxLiveBytes,
+	MaxReadBytesPerSecondPerNode,
+	MaxRequestsPerSecondPerNode,
+	MaxSQLConnectionsPerInstance,
+	MaxSpanConfigs,
+	MaxWriteBytesPerSecondPerNode,
 	TenantSpanConfigBounds,
 }
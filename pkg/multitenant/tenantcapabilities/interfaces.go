@@ -34,6 +34,15 @@ type Reader interface {
 	GetGlobalCapabilityState() map[roachpb.TenantID]*tenantcapabilitiespb.TenantCapabilities
 }
 
+// LiveBytesReader provides access to the live bytes of tenants, as measured by
+// the host cluster. The measurements may be arbitrarily stale.
+type LiveBytesReader interface {
+	// GetLiveBytes returns the live bytes of the specified tenant's data across
+	// the cluster, as of the last measurement, and whether the data was
+	// measured.
+	GetLiveBytes(id roachpb.TenantID) (liveBytes int64, found bool)
+}
+
 // Authorizer performs various kinds of capability checks for requests issued
 // by tenants. It does so by consulting the global tenant capability state.
 //
@@ -60,6 +69,11 @@ type Authorizer interface {
 	// cycle.
 	BindReader(reader Reader)
 
+	// BindLiveBytesReader binds the LiveBytesReader used to enforce the
+	// max_live_bytes capability of tenants post-creation, for the same reason
+	// as BindReader. Until it is bound, the capability isn't enforced.
+	BindLiveBytesReader(reader LiveBytesReader)
+
 	// HasNodeStatusCapability returns an error if a tenant, referenced by its ID,
 	// is not allowed to access cluster-level node metadata and liveness.
 	HasNodeStatusCapability(ctx context.Context, tenID roachpb.TenantID) error
@@ -77,6 +91,26 @@ type Authorizer interface {
 	// not be subject to rate limiting.
 	IsExemptFromRateLimiting(ctx context.Context, tenID roachpb.TenantID) bool
 
+	// GetMaxRequestsPerSecondPerNode returns the maximum rate of KV batch
+	// requests the tenant may issue to this node, or 0 if the rate is not
+	// limited. Each node enforces the limit independently.
+	GetMaxRequestsPerSecondPerNode(ctx context.Context, tenID roachpb.TenantID) int64
+
+	// GetMaxReadBytesPerSecondPerNode returns the maximum rate of bytes the
+	// tenant may read from this node, or 0 if the rate is not limited. Each
+	// node enforces the limit independently.
+	GetMaxReadBytesPerSecondPerNode(ctx context.Context, tenID roachpb.TenantID) int64
+
+	// GetMaxWriteBytesPerSecondPerNode returns the maximum rate of bytes the
+	// tenant may write to this node, or 0 if the rate is not limited. Each
+	// node enforces the limit independently.
+	GetMaxWriteBytesPerSecondPerNode(ctx context.Context, tenID roachpb.TenantID) int64
+
+	// GetMaxBlockCacheFillBytesPerSecondPerNode returns the maximum rate of
+	// bytes the tenant's reads may load into the block cache of this node, or 0
+	// if the rate is not limited. Each node enforces the limit independently.
+	GetMaxBlockCacheFillBytesPerSecondPerNode(ctx context.Context, tenID roachpb.TenantID) int64
+
 	// HasProcessDebugCapability returns an error if a tenant, referenced by its ID,
 	// is not allowed to debug the running process.
 	HasProcessDebugCapability(ctx context.Context, tenID roachpb.TenantID) error
@@ -39,6 +39,9 @@ func (n *AllowEverythingAuthorizer) HasCapabilityForBatch(
 // BindReader implements the tenantcapabilities.Authorizer interface.
 func (n *AllowEverythingAuthorizer) BindReader(tenantcapabilities.Reader) {}
 
+// BindLiveBytesReader implements the tenantcapabilities.Authorizer interface.
+func (n *AllowEverythingAuthorizer) BindLiveBytesReader(tenantcapabilities.LiveBytesReader) {}
+
 // HasNodeStatusCapability implements the tenantcapabilities.Authorizer interface.
 func (n *AllowEverythingAuthorizer) HasNodeStatusCapability(
 	ctx context.Context, tenID roachpb.TenantID,
@@ -67,6 +70,38 @@ func (n *AllowEverythingAuthorizer) IsExemptFromRateLimiting(
 	return true
 }
 
+// GetMaxRequestsPerSecondPerNode implements the tenantcapabilities.Authorizer
+// interface.
+func (n *AllowEverythingAuthorizer) GetMaxRequestsPerSecondPerNode(
+	context.Context, roachpb.TenantID,
+) int64 {
+	return 0
+}
+
+// GetMaxReadBytesPerSecondPerNode implements the tenantcapabilities.Authorizer
+// interface.
+func (n *AllowEverythingAuthorizer) GetMaxReadBytesPerSecondPerNode(
+	context.Context, roachpb.TenantID,
+) int64 {
+	return 0
+}
+
+// GetMaxWriteBytesPerSecondPerNode implements the tenantcapabilities.Authorizer
+// interface.
+func (n *AllowEverythingAuthorizer) GetMaxWriteBytesPerSecondPerNode(
+	context.Context, roachpb.TenantID,
+) int64 {
+	return 0
+}
+
+// GetMaxBlockCacheFillBytesPerSecondPerNode implements the
+// tenantcapabilities.Authorizer interface.
+func (n *AllowEverythingAuthorizer) GetMaxBlockCacheFillBytesPerSecondPerNode(
+	context.Context, roachpb.TenantID,
+) int64 {
+	return 0
+}
+
 // HasProcessDebugCapability implements the tenantcapabilities.Authorizer interface.
 func (n *AllowEverythingAuthorizer) HasProcessDebugCapability(
 	ctx context.Context, tenID roachpb.TenantID,
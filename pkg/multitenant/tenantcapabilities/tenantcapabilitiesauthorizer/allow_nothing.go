@@ -40,6 +40,9 @@ func (n *AllowNothingAuthorizer) HasCapabilityForBatch(
 // BindReader implements the tenantcapabilities.Authorizer interface.
 func (n *AllowNothingAuthorizer) BindReader(tenantcapabilities.Reader) {}
 
+// BindLiveBytesReader implements the tenantcapabilities.Authorizer interface.
+func (n *AllowNothingAuthorizer) BindLiveBytesReader(tenantcapabilities.LiveBytesReader) {}
+
 // HasNodeStatusCapability implements the tenantcapabilities.Authorizer interface.
 func (n *AllowNothingAuthorizer) HasNodeStatusCapability(
 	ctx context.Context, tenID roachpb.TenantID,
@@ -66,6 +69,38 @@ func (n *AllowNothingAuthorizer) IsExemptFromRateLimiting(context.Context, roach
 	return false
 }
 
+// GetMaxRequestsPerSecondPerNode implements the tenantcapabilities.Authorizer
+// interface.
+func (n *AllowNothingAuthorizer) GetMaxRequestsPerSecondPerNode(
+	context.Context, roachpb.TenantID,
+) int64 {
+	return 0
+}
+
+// GetMaxReadBytesPerSecondPerNode implements the tenantcapabilities.Authorizer
+// interface.
+func (n *AllowNothingAuthorizer) GetMaxReadBytesPerSecondPerNode(
+	context.Context, roachpb.TenantID,
+) int64 {
+	return 0
+}
+
+// GetMaxWriteBytesPerSecondPerNode implements the tenantcapabilities.Authorizer
+// interface.
+func (n *AllowNothingAuthorizer) GetMaxWriteBytesPerSecondPerNode(
+	context.Context, roachpb.TenantID,
+) int64 {
+	return 0
+}
+
+// GetMaxBlockCacheFillBytesPerSecondPerNode implements the
+// tenantcapabilities.Authorizer interface.
+func (n *AllowNothingAuthorizer) GetMaxBlockCacheFillBytesPerSecondPerNode(
+	context.Context, roachpb.TenantID,
+) int64 {
+	return 0
+}
+
 // HasProcessDebugCapability implements the tenantcapabilities.Authorizer interface.
 func (n *AllowNothingAuthorizer) HasProcessDebugCapability(
 	ctx context.Context, tenID roachpb.TenantID,
@@ -66,11 +66,12 @@ type Authorizer struct {
 	settings *cluster.Settings
 	knobs    tenantcapabilities.TestingKnobs
 
-	// We protect capabilitiesReader by a mutex because it is assigned
-	// asynchronously during server startup, after the RPC service may
-	// have started accepting requests.
+	// We protect capabilitiesReader and liveBytesReader by a mutex because
+	// they are assigned asynchronously during server startup, after the RPC
+	// service may have started accepting requests.
 	syncutil.Mutex
 	capabilitiesReader tenantcapabilities.Reader
+	liveBytesReader    tenantcapabilities.LiveBytesReader
 
 	logEvery log.EveryN
 }
@@ -112,7 +113,10 @@ func (a *Authorizer) HasCapabilityForBatch(
 		if entry.ServiceMode == mtinfopb.ServiceModeNone || entry.ServiceMode == mtinfopb.ServiceModeStopping {
 			return errors.Newf("operation not allowed when in service mode %q", entry.ServiceMode)
 		}
-		return a.capCheckForBatch(ctx, tenID, ba, entry)
+		if err := a.capCheckForBatch(ctx, tenID, ba, entry); err != nil {
+			return err
+		}
+		return a.liveBytesCheckForBatch(tenID, ba, entry)
 	case authorizerModeAllowAll:
 		return nil
 	case authorizerModeV222:
@@ -174,6 +178,29 @@ func (a *Authorizer) capCheckForBatch(
 	return nil
 }
 
+// liveBytesCheckForBatch returns an error if the batch adds data and the live
+// bytes of the tenant exceed its max_live_bytes capability. Deletions are
+// allowed so that the tenant can free up space.
+func (a *Authorizer) liveBytesCheckForBatch(
+	tenID roachpb.TenantID, ba *kvpb.BatchRequest, entry tenantcapabilities.Entry,
+) error {
+	limit := tenantcapabilities.MustGetInt64ByID(entry.TenantCapabilities, tenantcapabilities.MaxLiveBytes)
+	if limit <= 0 || !ba.AddsData() {
+		return nil
+	}
+	a.Lock()
+	reader := a.liveBytesReader
+	a.Unlock()
+	if reader == nil {
+		return nil
+	}
+	if liveBytes, found := reader.GetLiveBytes(tenID); found && liveBytes > limit {
+		return errors.Newf("client tenant exceeded its storage limit: %d live bytes, max_live_bytes is %d",
+			liveBytes, limit)
+	}
+	return nil
+}
+
 func newTenantDoesNotHaveCapabilityError(cap tenantcapabilities.ID, req kvpb.Request) error {
 	return errors.Newf("client tenant does not have capability %q (%T)", cap, req)
 }
@@ -257,6 +284,13 @@ func (a *Authorizer) BindReader(reader tenantcapabilities.Reader) {
 	a.capabilitiesReader = reader
 }
 
+// BindLiveBytesReader implements the tenantcapabilities.Authorizer interface.
+func (a *Authorizer) BindLiveBytesReader(reader tenantcapabilities.LiveBytesReader) {
+	a.Lock()
+	defer a.Unlock()
+	a.liveBytesReader = reader
+}
+
 func (a *Authorizer) HasNodeStatusCapability(ctx context.Context, tenID roachpb.TenantID) error {
 	if tenID.IsSystem() {
 		return nil
@@ -360,6 +394,62 @@ func (a *Authorizer) IsExemptFromRateLimiting(ctx context.Context, tenID roachpb
 	return tenantcapabilities.MustGetBoolByID(entry.TenantCapabilities, tenantcapabilities.ExemptFromRateLimiting)
 }
 
+// GetMaxRequestsPerSecondPerNode returns the configured limit on the rate of
+// KV batch requests for the tenant on this node, or 0 if there is none.
+func (a *Authorizer) GetMaxRequestsPerSecondPerNode(
+	ctx context.Context, tenID roachpb.TenantID,
+) int64 {
+	return a.getPerNodeLimit(ctx, tenID, tenantcapabilities.MaxRequestsPerSecondPerNode)
+}
+
+// GetMaxReadBytesPerSecondPerNode returns the configured limit on the rate of
+// bytes read by the tenant on this node, or 0 if there is none.
+func (a *Authorizer) GetMaxReadBytesPerSecondPerNode(
+	ctx context.Context, tenID roachpb.TenantID,
+) int64 {
+	return a.getPerNodeLimit(ctx, tenID, tenantcapabilities.MaxReadBytesPerSecondPerNode)
+}
+
+// GetMaxWriteBytesPerSecondPerNode returns the configured limit on the rate of
+// bytes written by the tenant on this node, or 0 if there is none.
+func (a *Authorizer) GetMaxWriteBytesPerSecondPerNode(
+	ctx context.Context, tenID roachpb.TenantID,
+) int64 {
+	return a.getPerNodeLimit(ctx, tenID, tenantcapabilities.MaxWriteBytesPerSecondPerNode)
+}
+
+// GetMaxBlockCacheFillBytesPerSecondPerNode returns the configured limit on the
+// rate of bytes the tenant's reads load into the block cache of this node, or 0
+// if there is none.
+func (a *Authorizer) GetMaxBlockCacheFillBytesPerSecondPerNode(
+	ctx context.Context, tenID roachpb.TenantID,
+) int64 {
+	return a.getPerNodeLimit(ctx, tenID, tenantcapabilities.MaxBlockCacheFillBytesPerSecondPerNode)
+}
+
+// getPerNodeLimit returns the value of the given int64 capability that limits
+// the tenant on this node, or 0 if the tenant is not limited.
+func (a *Authorizer) getPerNodeLimit(
+	ctx context.Context, tenID roachpb.TenantID, capID tenantcapabilities.ID,
+) int64 {
+	if tenID.IsSystem() {
+		return 0
+	}
+	entry, mode := a.getMode(ctx, tenID)
+	switch mode {
+	case authorizerModeOn:
+		break
+	case authorizerModeAllowAll, authorizerModeV222:
+		return 0
+	default:
+		err := errors.AssertionFailedf("unknown authorizer mode: %d", mode)
+		logcrash.ReportOrPanic(ctx, &a.settings.SV, "%v", err)
+		return 0
+	}
+
+	return tenantcapabilities.MustGetInt64ByID(entry.TenantCapabilities, capID)
+}
+
 func (a *Authorizer) HasProcessDebugCapability(ctx context.Context, tenID roachpb.TenantID) error {
 	if tenID.IsSystem() {
 		return nil
@@ -62,6 +62,13 @@ import (
 // ----
 // ok
 //
+// "set-live-bytes": sets the live bytes of a tenant, as measured by the host
+// cluster, which are checked against its max_live_bytes capability. Example:
+//
+// set-live-bytes ten=10 bytes=2000
+// ----
+// ok
+//
 // "set-bool-cluster-setting": overrides the specified boolean cluster setting
 // to the given value. Currently, only the authorizerEnabled cluster setting is
 // supported.
@@ -78,6 +85,8 @@ func TestDataDriven(t *testing.T) {
 		mockReader := mockReader(make(map[roachpb.TenantID]*tenantcapabilities.Entry))
 		authorizer := New(clusterSettings, nil /* TestingKnobs */)
 		authorizer.BindReader(mockReader)
+		liveBytes := mockLiveBytesReader(make(map[roachpb.TenantID]int64))
+		authorizer.BindLiveBytesReader(liveBytes)
 
 		datadriven.RunTest(t, path, func(t *testing.T, d *datadriven.TestData) string {
 			var tenID roachpb.TenantID
@@ -96,6 +105,10 @@ func TestDataDriven(t *testing.T) {
 			case "delete":
 				update := tenantcapabilitiestestutils.ParseTenantCapabilityDelete(t, d)
 				mockReader.updateState([]*tenantcapabilities.Update{update})
+			case "set-live-bytes":
+				var bytes int64
+				d.ScanArgs(t, "bytes", &bytes)
+				liveBytes[tenID] = bytes
 			case "has-capability-for-batch":
 				ba := tenantcapabilitiestestutils.ParseBatchRequests(t, d)
 				err := authorizer.HasCapabilityForBatch(context.Background(), tenID, &ba)
@@ -181,6 +194,16 @@ func (m mockReader) GetGlobalCapabilityState() map[roachpb.TenantID]*tenantcapab
 	return ret
 }
 
+type mockLiveBytesReader map[roachpb.TenantID]int64
+
+var _ tenantcapabilities.LiveBytesReader = mockLiveBytesReader{}
+
+// GetLiveBytes implements the tenantcapabilities.LiveBytesReader interface.
+func (m mockLiveBytesReader) GetLiveBytes(id roachpb.TenantID) (int64, bool) {
+	liveBytes, found := m[id]
+	return liveBytes, found
+}
+
 func TestAllBatchCapsAreBoolean(t *testing.T) {
 	for _, capID := range reqMethodToCap {
 		if capID >= tenantcapabilities.MaxCapabilityID {
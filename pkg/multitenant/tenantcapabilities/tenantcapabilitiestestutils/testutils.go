@@ -109,6 +109,13 @@ func ParseTenantCapabilityUpsert(
 			}
 			c.Value(&caps).Set(b)
 
+		case tenantcapabilities.Int64Capability:
+			i, err := strconv.ParseInt(arg.Vals[0], 10, 64)
+			if err != nil {
+				return entry, err
+			}
+			c.Value(&caps).Set(i)
+
 		case tenantcapabilities.SpanConfigBoundsCapability:
 			jsonD, err := json.ParseJSON(arg.Vals[0])
 			if err != nil {
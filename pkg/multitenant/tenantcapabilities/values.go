@@ -32,6 +32,13 @@ func MustGetBoolByID(t *tenantcapabilitiespb.TenantCapabilities, id ID) bool {
 	return MustGetValueByID(t, id).(BoolValue).Get()
 }
 
+// MustGetInt64ByID will get the int64 value for the capability corresponding
+// to the requested ID. If the ID is not valid or the capability is not an
+// int64 capability, this function will panic.
+func MustGetInt64ByID(t *tenantcapabilitiespb.TenantCapabilities, id ID) int64 {
+	return MustGetValueByID(t, id).(Int64Value).Get()
+}
+
 // GetValueByID looks up the capability value by ID. It returns an
 // error if the ID is not valid.
 func GetValueByID(t *tenantcapabilitiespb.TenantCapabilities, id ID) (Value, error) {
@@ -60,6 +67,22 @@ func GetValueByID(t *tenantcapabilitiespb.TenantCapabilities, id ID) (Value, err
 		return (*boolValue)(&t.CanDebugProcess), nil
 	case CanViewAllMetrics:
 		return (*boolValue)(&t.CanViewAllMetrics), nil
+	case MaxRequestsPerSecondPerNode:
+		return (*int64Value)(&t.MaxRequestsPerSecondPerNode), nil
+	case MaxSQLConnectionsPerInstance:
+		return (*int64Value)(&t.MaxSQLConnectionsPerInstance), nil
+	case MaxLiveBytes:
+		return (*int64Value)(&t.MaxLiveBytes), nil
+	case CostModel:
+		return (*int64Value)(&t.CostModel), nil
+	case MaxSpanConfigs:
+		return (*int64Value)(&t.MaxSpanConfigs), nil
+	case MaxReadBytesPerSecondPerNode:
+		return (*int64Value)(&t.MaxReadBytesPerSecondPerNode), nil
+	case MaxWriteBytesPerSecondPerNode:
+		return (*int64Value)(&t.MaxWriteBytesPerSecondPerNode), nil
+	case MaxBlockCacheFillBytesPerSecondPerNode:
+		return (*int64Value)(&t.MaxBlockCacheFillBytesPerSecondPerNode), nil
 	default:
 		return nil, errors.AssertionFailedf("unknown capability: %q", id.String())
 	}
@@ -41,6 +41,8 @@ func TestGetSet(t *testing.T) {
 		switch c, _ := FromID(id); c := c.(type) {
 		case BoolCapability:
 			c.Value(&v).Set(c.Value(someCaps()).Get())
+		case Int64Capability:
+			c.Value(&v).Set(c.Value(someCaps()).Get())
 		case SpanConfigBoundsCapability:
 			c.Value(&v).Set(c.Value(someCaps()).Get())
 		default:
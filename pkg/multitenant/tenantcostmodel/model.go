@@ -10,7 +10,12 @@
 
 package tenantcostmodel
 
-import "github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
 
 // RU stands for "Request Unit(s)"; the tenant cost model maps tenant activity
 // into this abstract unit.
@@ -19,6 +24,40 @@ import "github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 // a small point read of < 64 bytes costs 1 RU.
 type RU float64
 
+// ModelVersion identifies the cost model under which a tenant is billed. It is
+// selected by the cost_model tenant capability.
+type ModelVersion int64
+
+const (
+	// RequestUnitModel is the classic cost model, which bills tenants for the
+	// Request Units consumed by their KV operations, SQL CPU usage, and network
+	// and external I/O traffic.
+	RequestUnitModel ModelVersion = iota
+
+	// EstimatedCPUModel bills tenants for the vCPU-seconds they consume: the
+	// CPU usage measured on their SQL pods, plus an estimate of the CPU used
+	// by their KV operations on the host cluster. Network and external I/O
+	// traffic is not billed under this model.
+	EstimatedCPUModel
+)
+
+// IsValid returns true if v is a known cost model version.
+func (v ModelVersion) IsValid() bool {
+	return v == RequestUnitModel || v == EstimatedCPUModel
+}
+
+// String implements the fmt.Stringer interface.
+func (v ModelVersion) String() string {
+	switch v {
+	case RequestUnitModel:
+		return "request-units"
+	case EstimatedCPUModel:
+		return "estimated-cpu"
+	default:
+		return fmt.Sprintf("ModelVersion(%d)", int64(v))
+	}
+}
+
 // NetworkCost records how expensive network traffic is between two regions.
 // bytes_transmitted * NetworkCost is the RU cost of the network traffic.
 type NetworkCost float64
@@ -83,10 +122,56 @@ type Config struct {
 	// service into the SQL pod.
 	ExternalIOIngressByte RU
 
+	// KVCPUSecond is the KV cost that corresponds to one CPU second of usage on
+	// the host cluster. It is used to estimate the KV CPU usage of tenants
+	// billed under the EstimatedCPUModel.
+	KVCPUSecond RU
+
+	// EstimatedWriteAmplification contains, for each type of statement, a
+	// coefficient applied to the estimated cost of the rows it writes. It
+	// accounts for the write amplification that the per-request and per-byte
+	// costs don't capture, e.g. the tombstones written by deletes. It is only
+	// used by EstimatedRowWriteCost, and isn't applied to the cost of the KV
+	// writes actually performed by the statements.
+	EstimatedWriteAmplification [NumWriteStatementTypes]float64
+
 	// NetworkCostTable is a table describing the network cost between regions.
 	NetworkCostTable NetworkCostTable
 }
 
+// WriteStatementType is the type of a SQL statement which writes rows.
+type WriteStatementType int
+
+const (
+	// InsertStatement is an INSERT statement.
+	InsertStatement WriteStatementType = iota
+	// UpsertStatement is an UPSERT statement, or an INSERT statement with an
+	// ON CONFLICT clause.
+	UpsertStatement
+	// UpdateStatement is an UPDATE statement.
+	UpdateStatement
+	// DeleteStatement is a DELETE statement.
+	DeleteStatement
+	// NumWriteStatementTypes is the number of write statement types.
+	NumWriteStatementTypes
+)
+
+// String implements the fmt.Stringer interface.
+func (t WriteStatementType) String() string {
+	switch t {
+	case InsertStatement:
+		return "insert"
+	case UpsertStatement:
+		return "upsert"
+	case UpdateStatement:
+		return "update"
+	case DeleteStatement:
+		return "delete"
+	default:
+		return fmt.Sprintf("WriteStatementType(%d)", int(t))
+	}
+}
+
 // KVReadCost calculates the cost of a KV read operation.
 func (c *Config) KVReadCost(count, bytes int64) RU {
 	return c.KVReadBatch + RU(count)*c.KVReadRequest + RU(bytes)*c.KVReadByte
@@ -97,11 +182,33 @@ func (c *Config) KVWriteCost(count, bytes int64) RU {
 	return c.KVWriteBatch + RU(count)*c.KVWriteRequest + RU(bytes)*c.KVWriteByte
 }
 
+// EstimatedRowWriteCost estimates the cost of a row written by a statement of
+// the given type, which writes the given number of index entries (one per
+// index the row is written to, including the primary index) totaling the given
+// number of bytes. It doesn't account for replication, nor for the per-batch
+// cost. The estimate is only reported by EXPLAIN: the Request Units consumed
+// by the statement are computed from its KV requests, by KVWriteCost.
+func (c *Config) EstimatedRowWriteCost(
+	typ WriteStatementType, indexWrites int64, bytes float64,
+) RU {
+	cost := RU(indexWrites)*c.KVWriteRequest + RU(bytes)*c.KVWriteByte
+	return cost * RU(c.EstimatedWriteAmplification[typ])
+}
+
 // PodCPUCost calculates the cost of CPU seconds consumed in the SQL pod.
 func (c *Config) PodCPUCost(seconds float64) RU {
 	return RU(seconds) * c.PodCPUSecond
 }
 
+// EstimatedKVCPUSeconds estimates the CPU seconds consumed on the host cluster
+// by KV operations of the given cost.
+func (c *Config) EstimatedKVCPUSeconds(kv RU) float64 {
+	if c.KVCPUSecond == 0 {
+		return 0
+	}
+	return float64(kv / c.KVCPUSecond)
+}
+
 // PGWireEgressCost calculates the cost of bytes leaving the SQL pod to external
 // services.
 func (c *Config) PGWireEgressCost(bytes int64) RU {
@@ -183,6 +290,9 @@ type RequestInfo struct {
 	// calculated ahead of time by distsender and accounts for the network cost
 	// of each replica written.
 	networkCost NetworkCost
+	// rangeID is the range to which the batch was sent, if known. It is used to
+	// attribute the cost of reads and writes to ranges.
+	rangeID roachpb.RangeID
 }
 
 // MakeRequestInfo extracts the relevant information from a BatchRequest.
@@ -239,6 +349,18 @@ func (bri RequestInfo) WriteBytes() int64 {
 	return bri.writeBytes
 }
 
+// WithRangeID returns a copy of the RequestInfo that records that the batch was
+// sent to the given range.
+func (bri RequestInfo) WithRangeID(rangeID roachpb.RangeID) RequestInfo {
+	bri.rangeID = rangeID
+	return bri
+}
+
+// RangeID is the range to which the batch was sent, or 0 if it is unknown.
+func (bri RequestInfo) RangeID() roachpb.RangeID {
+	return bri.rangeID
+}
+
 // TestingRequestInfo creates a RequestInfo for testing purposes.
 func TestingRequestInfo(
 	writeReplicas, writeCount, writeBytes int64, networkCost NetworkCost,
@@ -268,6 +390,9 @@ type ResponseInfo struct {
 	readBytes int64
 	// networkCost is RU/byte cost for this read request.
 	networkCost NetworkCost
+	// readKey is the key at which the reads of the batch start, if known. It is
+	// used to attribute the cost of reads to indexes.
+	readKey roachpb.Key
 }
 
 // MakeResponseInfo extracts the relevant information from a BatchResponse.
@@ -300,6 +425,13 @@ func MakeResponseInfo(
 	}
 }
 
+// WithReadKey returns a copy of the ResponseInfo that records that the reads of
+// the batch start at the given key.
+func (bri ResponseInfo) WithReadKey(key roachpb.Key) ResponseInfo {
+	bri.readKey = key
+	return bri
+}
+
 // IsRead is true if this was a read-only batch rather than a write batch.
 func (bri ResponseInfo) IsRead() bool {
 	return bri.isRead
@@ -317,6 +449,12 @@ func (bri ResponseInfo) ReadBytes() int64 {
 	return bri.readBytes
 }
 
+// ReadKey is the key at which the reads of the batch start, or nil if it is
+// unknown or if it is a write batch.
+func (bri ResponseInfo) ReadKey() roachpb.Key {
+	return bri.readKey
+}
+
 // TestingResponseInfo creates a ResponseInfo for testing purposes.
 func TestingResponseInfo(
 	isRead bool, readCount, readBytes int64, networkCost NetworkCost,
@@ -109,6 +109,51 @@ var (
 		settings.NonNegativeFloat,
 	)
 
+	KVCPUSecondCost = settings.RegisterFloatSetting(
+		settings.SystemVisible,
+		"tenant_cost_model.kv_cpu_second_cost",
+		"cost of a CPU-second on the KV nodes in Request Units; used to estimate the KV CPU "+
+			"usage of tenants billed by estimated CPU",
+		1000,
+		settings.PositiveFloat,
+	)
+
+	InsertEstimatedWriteAmplification = settings.RegisterFloatSetting(
+		settings.SystemVisible,
+		"tenant_cost_model.estimated_write_amplification.insert",
+		"coefficient applied to the estimated cost of the rows written by INSERT statements "+
+			"in the write cost estimates of EXPLAIN; it doesn't affect the Request Units consumed",
+		1,
+		settings.NonNegativeFloat,
+	)
+
+	UpsertEstimatedWriteAmplification = settings.RegisterFloatSetting(
+		settings.SystemVisible,
+		"tenant_cost_model.estimated_write_amplification.upsert",
+		"coefficient applied to the estimated cost of the rows written by UPSERT statements "+
+			"in the write cost estimates of EXPLAIN; it doesn't affect the Request Units consumed",
+		1,
+		settings.NonNegativeFloat,
+	)
+
+	UpdateEstimatedWriteAmplification = settings.RegisterFloatSetting(
+		settings.SystemVisible,
+		"tenant_cost_model.estimated_write_amplification.update",
+		"coefficient applied to the estimated cost of the rows written by UPDATE statements "+
+			"in the write cost estimates of EXPLAIN; it doesn't affect the Request Units consumed",
+		1,
+		settings.NonNegativeFloat,
+	)
+
+	DeleteEstimatedWriteAmplification = settings.RegisterFloatSetting(
+		settings.SystemVisible,
+		"tenant_cost_model.estimated_write_amplification.delete",
+		"coefficient applied to the estimated cost of the rows deleted by DELETE statements "+
+			"in the write cost estimates of EXPLAIN; it doesn't affect the Request Units consumed",
+		1,
+		settings.NonNegativeFloat,
+	)
+
 	CrossRegionNetworkCostSetting = settings.RegisterStringSetting(
 		settings.SystemVisible,
 		"tenant_cost_model.cross_region_network_cost",
@@ -130,6 +175,11 @@ var (
 		PgwireEgressCostPerMiB,
 		ExternalIOEgressCostPerMiB,
 		ExternalIOIngressCostPerMiB,
+		KVCPUSecondCost,
+		InsertEstimatedWriteAmplification,
+		UpsertEstimatedWriteAmplification,
+		UpdateEstimatedWriteAmplification,
+		DeleteEstimatedWriteAmplification,
 		CrossRegionNetworkCostSetting,
 	}
 )
@@ -260,7 +310,14 @@ func ConfigFromSettings(sv *settings.Values) Config {
 		PGWireEgressByte:      RU(PgwireEgressCostPerMiB.Get(sv) * perMiBToPerByte),
 		ExternalIOIngressByte: RU(ExternalIOIngressCostPerMiB.Get(sv) * perMiBToPerByte),
 		ExternalIOEgressByte:  RU(ExternalIOEgressCostPerMiB.Get(sv) * perMiBToPerByte),
-		NetworkCostTable:      *networkTable,
+		KVCPUSecond:           RU(KVCPUSecondCost.Get(sv)),
+		EstimatedWriteAmplification: [NumWriteStatementTypes]float64{
+			InsertStatement: InsertEstimatedWriteAmplification.Get(sv),
+			UpsertStatement: UpsertEstimatedWriteAmplification.Get(sv),
+			UpdateStatement: UpdateEstimatedWriteAmplification.Get(sv),
+			DeleteStatement: DeleteEstimatedWriteAmplification.Get(sv),
+		},
+		NetworkCostTable: *networkTable,
 	}
 }
 
@@ -278,7 +335,14 @@ func DefaultConfig() Config {
 		PGWireEgressByte:      RU(PgwireEgressCostPerMiB.Default() * perMiBToPerByte),
 		ExternalIOIngressByte: RU(ExternalIOEgressCostPerMiB.Default() * perMiBToPerByte),
 		ExternalIOEgressByte:  RU(ExternalIOIngressCostPerMiB.Default() * perMiBToPerByte),
-		NetworkCostTable:      *newEmptyCostTable(),
+		KVCPUSecond:           RU(KVCPUSecondCost.Default()),
+		EstimatedWriteAmplification: [NumWriteStatementTypes]float64{
+			InsertStatement: InsertEstimatedWriteAmplification.Default(),
+			UpsertStatement: UpsertEstimatedWriteAmplification.Default(),
+			UpdateStatement: UpdateEstimatedWriteAmplification.Default(),
+			DeleteStatement: DeleteEstimatedWriteAmplification.Default(),
+		},
+		NetworkCostTable: *newEmptyCostTable(),
 	}
 }
 
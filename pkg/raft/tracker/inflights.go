@@ -135,6 +135,9 @@ func (in *Inflights) Full() bool {
 // Count returns the number of inflight messages.
 func (in *Inflights) Count() int { return in.count }
 
+// Bytes returns the total byte size of the inflight messages.
+func (in *Inflights) Bytes() uint64 { return in.bytes }
+
 // reset frees all inflights.
 func (in *Inflights) reset() {
 	in.start = 0
@@ -244,9 +244,11 @@ func TestInflightsReset(t *testing.T) {
 		in.FreeLE(index - 2)
 		require.False(t, in.Full())
 		require.Equal(t, 2, in.Count())
+		require.Equal(t, uint64(32), in.Bytes())
 	}
 	in.FreeLE(index)
 	require.Equal(t, 0, in.Count())
+	require.Equal(t, uint64(0), in.Bytes())
 }
 
 func inflightsBuffer(indices []uint64, sizes []uint64) []inflight {
@@ -197,6 +197,11 @@ func (pr *Progress) SentCommit(commit uint64) {
 	pr.sentCommit = commit
 }
 
+// LastSentCommit returns the highest commit index sent to the follower.
+func (pr *Progress) LastSentCommit() uint64 {
+	return pr.sentCommit
+}
+
 // MaybeUpdate is called when an MsgAppResp arrives from the follower, with the
 // index acked by it. The method returns false if the given n index comes from
 // an outdated message. Otherwise it updates the progress and returns true.
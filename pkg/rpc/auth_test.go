@@ -1109,6 +1109,11 @@ func (m mockAuthorizer) BindReader(tenantcapabilities.Reader) {
 	panic("unimplemented")
 }
 
+// BindLiveBytesReader implements the tenantcapabilities.Authorizer interface.
+func (m mockAuthorizer) BindLiveBytesReader(tenantcapabilities.LiveBytesReader) {
+	panic("unimplemented")
+}
+
 func (m mockAuthorizer) HasNodeStatusCapability(ctx context.Context, tenID roachpb.TenantID) error {
 	if m.hasNodestatusCapability {
 		return nil
@@ -1135,3 +1140,21 @@ func (m mockAuthorizer) HasNodelocalStorageCapability(
 func (m mockAuthorizer) IsExemptFromRateLimiting(context.Context, roachpb.TenantID) bool {
 	return m.hasExemptFromRateLimiterCapability
 }
+
+func (m mockAuthorizer) GetMaxRequestsPerSecondPerNode(context.Context, roachpb.TenantID) int64 {
+	return 0
+}
+
+func (m mockAuthorizer) GetMaxReadBytesPerSecondPerNode(context.Context, roachpb.TenantID) int64 {
+	return 0
+}
+
+func (m mockAuthorizer) GetMaxWriteBytesPerSecondPerNode(context.Context, roachpb.TenantID) int64 {
+	return 0
+}
+
+func (m mockAuthorizer) GetMaxBlockCacheFillBytesPerSecondPerNode(
+	context.Context, roachpb.TenantID,
+) int64 {
+	return 0
+}
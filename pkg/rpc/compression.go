@@ -0,0 +1,289 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rpc
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// compressionAlgorithm is an algorithm used to compress the RPCs of a
+// connection class.
+type compressionAlgorithm int64
+
+const (
+	compressionOff compressionAlgorithm = iota
+	compressionSnappy
+	compressionZstd
+)
+
+var compressionAlgorithms = map[int64]string{
+	int64(compressionOff):    "off",
+	int64(compressionSnappy): "snappy",
+	int64(compressionZstd):   "zstd",
+}
+
+func registerCompressionSetting(key settings.InternalKey, class string) *settings.EnumSetting {
+	return settings.RegisterEnumSetting(
+		settings.SystemVisible,
+		key,
+		"the algorithm used to compress the RPCs of the "+class+" connection class; "+
+			"zstd compresses better but uses more CPU, and snappy is used instead "+
+			"with nodes that don't support zstd",
+		"snappy",
+		compressionAlgorithms,
+		settings.WithPublic,
+	)
+}
+
+// The compression of each connection class can be configured separately, so
+// that, for example, raft traffic crossing WAN links can be compressed more
+// aggressively than the rest. The rangefeed connection class uses the
+// compression of the default class.
+var (
+	defaultClassCompression = registerCompressionSetting("rpc.compression.default_class", "default")
+	systemClassCompression  = registerCompressionSetting("rpc.compression.system_class", "system")
+	raftClassCompression    = registerCompressionSetting("rpc.compression.raft_class", "raft")
+)
+
+func compressionSettingForClass(class ConnectionClass) *settings.EnumSetting {
+	switch class {
+	case SystemClass:
+		return systemClassCompression
+	case RaftClass:
+		return raftClassCompression
+	default:
+		return defaultClassCompression
+	}
+}
+
+// supportedCompressors are the names of the compressors this node can
+// decompress RPCs with. They are advertised in heartbeat responses. Nodes that
+// predate this advertisement only support snappy.
+var supportedCompressors = []string{
+	(snappyCompressor{}).Name(),
+	(zstdCompressor{}).Name(),
+}
+
+var (
+	metaCompressionUncompressedBytes = metric.Metadata{
+		Name:        "rpc.compression.uncompressed_bytes",
+		Help:        "Number of bytes of RPC messages compressed by this node, before compression",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaCompressionBytesSaved = metric.Metadata{
+		Name: "rpc.compression.bytes_saved",
+		Help: `Number of bytes saved by compressing RPC messages sent by this node.
+
+Comparing this to rpc.compression.uncompressed_bytes gives the compression ratio
+of RPC traffic. Messages that compress poorly don't count against it.`,
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+)
+
+// CompressionMetrics are the metrics of the RPC compressors. They are
+// process-wide, since the compressors are registered globally with gRPC, and
+// are thus registered once with the node-level registry rather than with the
+// registry of each Context.
+type CompressionMetrics struct {
+	UncompressedBytes *metric.Counter
+	BytesSaved        *metric.Counter
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (CompressionMetrics) MetricStruct() {}
+
+var compressionMetrics = CompressionMetrics{
+	UncompressedBytes: metric.NewCounter(metaCompressionUncompressedBytes),
+	BytesSaved:        metric.NewCounter(metaCompressionBytesSaved),
+}
+
+// GetCompressionMetrics returns the metrics shared by all the compressors.
+func GetCompressionMetrics() *CompressionMetrics {
+	return &compressionMetrics
+}
+
+// recordCompression records the compression of a message.
+func recordCompression(uncompressed, compressed int) {
+	compressionMetrics.UncompressedBytes.Inc(int64(uncompressed))
+	if saved := uncompressed - compressed; saved > 0 {
+		compressionMetrics.BytesSaved.Inc(int64(saved))
+	}
+}
+
+// countingWriter counts the bytes written to the wrapped writer.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += n
+	return n, err
+}
+
+// classCompression picks the compressor of the RPCs of a connection, based on
+// the setting of its connection class and on the compressors supported by the
+// remote node.
+type classCompression struct {
+	sv    *settings.Values
+	class ConnectionClass
+	// zstdSupported is set once the remote node advertised that it supports
+	// zstd, in a heartbeat response.
+	zstdSupported atomic.Bool
+}
+
+func newClassCompression(sv *settings.Values, class ConnectionClass) *classCompression {
+	return &classCompression{sv: sv, class: class}
+}
+
+// callOptions returns the call options selecting the compressor of an RPC,
+// appended to the given ones.
+func (c *classCompression) callOptions(opts []grpc.CallOption) []grpc.CallOption {
+	switch compressionAlgorithm(compressionSettingForClass(c.class).Get(c.sv)) {
+	case compressionOff:
+		return opts
+	case compressionZstd:
+		if c.zstdSupported.Load() {
+			return append(opts, grpc.UseCompressor((zstdCompressor{}).Name()))
+		}
+	}
+	return append(opts, grpc.UseCompressor((snappyCompressor{}).Name()))
+}
+
+// unaryInterceptor selects the compressor of unary RPCs. It also picks up the
+// compressors advertised by the remote node in heartbeat responses.
+func (c *classCompression) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if err := invoker(ctx, method, req, reply, cc, c.callOptions(opts)...); err != nil {
+		return err
+	}
+	if resp, ok := reply.(*PingResponse); ok {
+		c.zstdSupported.Store(slices.Contains(resp.Compressors, (zstdCompressor{}).Name()))
+	}
+	return nil
+}
+
+// streamInterceptor selects the compressor of streaming RPCs.
+func (c *classCompression) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return streamer(ctx, desc, cc, method, c.callOptions(opts)...)
+}
+
+// NB: like for snappy, the encoders and decoders are pooled since the
+// compressor is shared by all the streams of all the connections.
+var zstdWriterPool = sync.Pool{
+	New: func() interface{} {
+		// The options can't fail.
+		w, _ := zstd.NewWriter(nil,
+			zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+		return &zstdWriter{zstd: w}
+	},
+}
+var zstdReaderPool = sync.Pool{
+	New: func() interface{} {
+		r, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+		return &zstdReader{zstd: r}
+	},
+}
+
+type zstdWriter struct {
+	zstd     *zstd.Encoder
+	inner    countingWriter
+	wroteLen int
+}
+
+func (w *zstdWriter) Write(p []byte) (int, error) {
+	w.wroteLen += len(p)
+	return w.zstd.Write(p)
+}
+
+func (w *zstdWriter) Close() error {
+	defer w.release()
+	if err := w.zstd.Close(); err != nil {
+		return err
+	}
+	recordCompression(w.wroteLen, w.inner.n)
+	return nil
+}
+
+func (w *zstdWriter) release() {
+	*w = zstdWriter{zstd: w.zstd}
+	w.zstd.Reset(nil) // for GC
+	zstdWriterPool.Put(w)
+}
+
+type zstdReader struct {
+	zstd *zstd.Decoder
+}
+
+func (r *zstdReader) Read(p []byte) (int, error) {
+	n, err := r.zstd.Read(p)
+	if err == io.EOF {
+		r.release()
+	}
+	return n, err
+}
+
+func (r *zstdReader) release() {
+	_ = r.zstd.Reset(nil) // for GC
+	zstdReaderPool.Put(r)
+}
+
+type zstdCompressor struct{}
+
+func (zstdCompressor) Name() string {
+	return "zstd"
+}
+
+func (zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	zw := zstdWriterPool.Get().(*zstdWriter)
+	zw.inner = countingWriter{w: w}
+	zw.zstd.Reset(&zw.inner)
+	return zw, nil
+}
+
+func (zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	zr := zstdReaderPool.Get().(*zstdReader)
+	if err := zr.zstd.Reset(r); err != nil {
+		zstdReaderPool.Put(zr)
+		return nil, err
+	}
+	return zr, nil
+}
+
+func init() {
+	encoding.RegisterCompressor(zstdCompressor{})
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestZstdCompressorCompressDecompress(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	var c zstdCompressor
+	in := bytes.Repeat([]byte("compressible "), 1000)
+
+	uncompressedBefore := compressionMetrics.UncompressedBytes.Count()
+	savedBefore := compressionMetrics.BytesSaved.Count()
+
+	buf := &bytes.Buffer{}
+	wc, err := c.Compress(buf)
+	require.NoError(t, err)
+	_, err = wc.Write(in)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+	out := buf.Bytes()
+	require.Less(t, len(out), len(in))
+
+	require.Equal(t, int64(len(in)), compressionMetrics.UncompressedBytes.Count()-uncompressedBefore)
+	require.Equal(t, int64(len(in)-len(out)), compressionMetrics.BytesSaved.Count()-savedBefore)
+
+	r, err := c.Decompress(bytes.NewReader(out))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, in, decompressed)
+}
+
+func TestClassCompression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	raft := newClassCompression(&st.SV, RaftClass)
+	def := newClassCompression(&st.SV, DefaultClass)
+
+	compressor := func(c *classCompression) string {
+		var name string
+		for _, opt := range c.callOptions(nil) {
+			if o, ok := opt.(grpc.CompressorCallOption); ok {
+				name = o.CompressorType
+			}
+		}
+		return name
+	}
+	require.Equal(t, "snappy", compressor(raft))
+	require.Equal(t, "snappy", compressor(def))
+
+	// zstd isn't used until the remote node advertises that it supports it.
+	raftClassCompression.Override(ctx, &st.SV, int64(compressionZstd))
+	require.Equal(t, "snappy", compressor(raft))
+	ping := func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*reply.(*PingResponse) = PingResponse{Compressors: supportedCompressors}
+		return nil
+	}
+	require.NoError(t, raft.unaryInterceptor(ctx, "ping", &PingRequest{}, &PingResponse{}, nil, ping))
+	require.Equal(t, "zstd", compressor(raft))
+	require.Equal(t, "snappy", compressor(def))
+
+	defaultClassCompression.Override(ctx, &st.SV, int64(compressionOff))
+	require.Equal(t, "", compressor(def))
+}
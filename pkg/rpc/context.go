@@ -325,6 +325,10 @@ type ContextOptions struct {
 	// preliminary checks but before recording clock offset information.
 	// It can inject an error or modify the response.
 	OnIncomingPing func(context.Context, *PingRequest, *PingResponse) error
+	// OnIncomingRawConn is called when accepting a raw connection from the
+	// given node, after the checks applied to heartbeats. It can reject the
+	// connection by returning an error. See AcceptRawConn.
+	OnIncomingRawConn func(ctx context.Context, originNodeID roachpb.NodeID) error
 	// OnOutgoingPing intercepts outgoing PingRequests. It may inject an
 	// error.
 	OnOutgoingPing func(context.Context, *PingRequest) error
@@ -430,9 +434,9 @@ func (c ContextOptions) validate() error {
 		return errors.New("RPCHeartbeatInterval must be set")
 	}
 
-	// NB: OnOutgoingPing and OnIncomingPing default to noops.
-	// This is used both for testing and the cli.
-	_, _ = c.OnOutgoingPing, c.OnIncomingPing
+	// NB: OnOutgoingPing, OnIncomingPing and OnIncomingRawConn default to
+	// noops. This is used both for testing and the cli.
+	_, _, _ = c.OnOutgoingPing, c.OnIncomingPing, c.OnIncomingRawConn
 
 	return nil
 }
@@ -1677,12 +1681,17 @@ func (rpcCtx *Context) dialOptsNetwork(
 	//
 	// On a related note, this configuration uses our own snappy codec.
 	// We believe it works better than the gzip codec provided natively
-	// by grpc, although the specific reason is now lost to history. It
-	// would be possible to change/simplify this, and since it's for
-	// each client to decide changing this will not require much
-	// cross-version compatibility dance.
+	// by grpc, although the specific reason is now lost to history.
+	//
+	// The codec is picked for each RPC according to the setting of the
+	// connection class, so that changes to the setting apply to existing
+	// connections. zstd is only used once the server advertised in a
+	// heartbeat response that it supports it, since older servers don't.
 	if rpcCtx.rpcCompression {
-		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor((snappyCompressor{}).Name())))
+		compression := newClassCompression(&rpcCtx.Settings.SV, class)
+		dialOpts = append(dialOpts,
+			grpc.WithChainUnaryInterceptor(compression.unaryInterceptor),
+			grpc.WithChainStreamInterceptor(compression.streamInterceptor))
 	}
 
 	// GRPC uses the HTTPS_PROXY environment variable by default[1]. This is
@@ -167,6 +167,7 @@ func (hs *HeartbeatService) Ping(ctx context.Context, request *PingRequest) (*Pi
 		ServerVersion:                  hs.version.LatestVersion(),
 		ClusterName:                    hs.clusterName,
 		DisableClusterNameVerification: hs.disableClusterNameVerification,
+		Compressors:                    supportedCompressors,
 	}
 
 	if fn := hs.onHandlePing; fn != nil {
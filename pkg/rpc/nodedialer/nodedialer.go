@@ -119,6 +119,25 @@ func (n *Dialer) DialNoBreaker(
 	return n.dial(ctx, nodeID, addr, false, class)
 }
 
+// DialRaw opens a raw (non-gRPC) connection to the given node, starting with
+// the given preamble. See (*rpc.Context).DialRawConn.
+func (n *Dialer) DialRaw(
+	ctx context.Context, nodeID roachpb.NodeID, preamble []byte,
+) (net.Conn, error) {
+	if n == nil || n.resolver == nil || n.rpcContext == nil {
+		return nil, errors.New("no node dialer configured")
+	}
+	addr, err := n.resolver(nodeID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve n%d", nodeID)
+	}
+	conn, err := n.rpcContext.DialRawConn(ctx, addr.String(), nodeID, preamble)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to n%d at %v", nodeID, addr)
+	}
+	return conn, nil
+}
+
 // DialInternalClient is a specialization of DialClass for callers that
 // want a kvpb.InternalClient. This supports an optimization to bypass the
 // network for the local node.
@@ -0,0 +1,216 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/credentials"
+	grpcpeer "google.golang.org/grpc/peer"
+)
+
+// Raw connections are used by the few inter-node protocols that don't run on
+// top of gRPC (for example, the framed raft transport). They share the RPC
+// port with gRPC: the client starts the connection with a protocol-specific
+// preamble, sent in clear text, which the server's connection multiplexer
+// uses to route the connection. The remainder of the connection is secured
+// with the same TLS certificates as gRPC connections, unless the cluster is
+// insecure.
+//
+// Once secured, the client sends its storage cluster ID, the ID of the node it
+// means to reach, its own node ID and its binary version, which the server
+// checks like it does for heartbeats: the cluster and node IDs must match, the
+// client's version must be compatible with the active cluster version, and
+// the connection must pass ContextOptions.OnIncomingRawConn, which servers use
+// to reject connections until they are operational and from decommissioned
+// nodes. The server replies with a single byte to accept the connection, after
+// which the connection belongs to the protocol.
+
+// rawConnHandshakeTimeout bounds the time spent setting up a raw connection.
+const rawConnHandshakeTimeout = 10 * time.Second
+
+// rawConnAccepted is sent by the server once it accepted a raw connection.
+const rawConnAccepted = byte(1)
+
+// rawConnHandshakeSize is the size of the handshake sent by the client: its
+// cluster ID, the target and origin node IDs and the four components of its
+// version.
+const rawConnHandshakeSize = uuid.Size + 4*6
+
+// DialRawConn opens a raw connection to the given node at the given address,
+// starting with the given preamble.
+func (rpcCtx *Context) DialRawConn(
+	ctx context.Context, target string, nodeID roachpb.NodeID, preamble []byte,
+) (_ net.Conn, retErr error) {
+	dialer := net.Dialer{LocalAddr: sourceAddr}
+	conn, err := dialer.DialContext(ctx, "tcp", target)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if retErr != nil {
+			_ = conn.Close()
+		}
+	}()
+	if err := conn.SetDeadline(rawConnHandshakeDeadline(ctx)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(preamble); err != nil {
+		return nil, err
+	}
+	if !rpcCtx.ContextOptions.Insecure {
+		tlsConfig, err := rpcCtx.GetClientTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = tlsConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			// Like gRPC, verify the server certificate against the host name
+			// used to reach it.
+			host, _, err := net.SplitHostPort(target)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.ServerName = host
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	var buf [rawConnHandshakeSize]byte
+	copy(buf[:], rpcCtx.StorageClusterID.Get().GetBytes())
+	rest := buf[uuid.Size:]
+	version := rpcCtx.Settings.Version.LatestVersion()
+	for _, v := range []uint32{
+		uint32(nodeID), uint32(rpcCtx.NodeID.Get()),
+		uint32(version.Major), uint32(version.Minor), uint32(version.Patch), uint32(version.Internal),
+	} {
+		binary.BigEndian.PutUint32(rest, v)
+		rest = rest[4:]
+	}
+	if _, err := conn.Write(buf[:]); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+		return nil, errors.Wrap(err, "raw connection not accepted by server")
+	}
+	if buf[0] != rawConnAccepted {
+		return nil, errors.Errorf("unexpected raw connection handshake response %d", buf[0])
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// AcceptRawConn sets up a raw connection accepted by the RPC server, whose
+// preamble has already been consumed. It checks that the client authenticated
+// with a root or node certificate, which is what inter-node gRPC calls
+// require, and that it passes the checks applied to heartbeats.
+func (rpcCtx *Context) AcceptRawConn(ctx context.Context, conn net.Conn) (net.Conn, error) {
+	if err := conn.SetDeadline(rawConnHandshakeDeadline(ctx)); err != nil {
+		return nil, err
+	}
+	if !rpcCtx.ContextOptions.Insecure {
+		tlsConfig, err := rpcCtx.GetServerTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		tlsConn := tls.Server(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, err
+		}
+		a := kvAuth{
+			sv: &rpcCtx.Settings.SV,
+			tenant: tenantAuthorizer{
+				tenantID:               rpcCtx.tenID,
+				capabilitiesAuthorizer: rpcCtx.capabilitiesAuthorizer,
+			},
+		}
+		peerCtx := grpcpeer.NewContext(ctx, &grpcpeer.Peer{
+			Addr:     conn.RemoteAddr(),
+			AuthInfo: credentials.TLSInfo{State: tlsConn.ConnectionState()},
+		})
+		ar, err := a.authenticateNetworkRequest(peerCtx)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ar.(authnSuccessPeerIsPrivileged); !ok {
+			return nil, authErrorf("raw connections are only accepted from root or node clients")
+		}
+		conn = tlsConn
+	}
+
+	var buf [rawConnHandshakeSize]byte
+	if _, err := io.ReadFull(conn, buf[:]); err != nil {
+		return nil, err
+	}
+	clientClusterID, err := uuid.FromBytes(buf[:uuid.Size])
+	if err != nil {
+		return nil, err
+	}
+	if clusterID := rpcCtx.StorageClusterID.Get(); clientClusterID != uuid.Nil &&
+		clusterID != uuid.Nil && clientClusterID != clusterID {
+		return nil, errors.Errorf(
+			"client cluster ID %q doesn't match server cluster ID %q", clientClusterID, clusterID)
+	}
+	rest := buf[uuid.Size:]
+	next := func() int32 {
+		v := int32(binary.BigEndian.Uint32(rest))
+		rest = rest[4:]
+		return v
+	}
+	targetNodeID, originNodeID := roachpb.NodeID(next()), roachpb.NodeID(next())
+	peerVersion := roachpb.Version{Major: next(), Minor: next(), Patch: next(), Internal: next()}
+	nodeID := rpcCtx.NodeID.Get()
+	if targetNodeID != 0 && (!rpcCtx.TestingAllowNamedRPCToAnonymousServer || nodeID != 0) &&
+		targetNodeID != nodeID {
+		return nil, errors.Errorf(
+			"client requested node ID %d doesn't match server node ID %d", targetNodeID, nodeID)
+	}
+	if err := checkVersion(ctx, rpcCtx.Settings.Version, peerVersion); err != nil {
+		return nil, errors.Wrap(err, "version compatibility check failed on raw connection")
+	}
+	if fn := rpcCtx.OnIncomingRawConn; fn != nil {
+		if err := fn(ctx, originNodeID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := conn.Write([]byte{rawConnAccepted}); err != nil {
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// rawConnHandshakeDeadline returns the deadline for setting up a raw
+// connection.
+func rawConnHandshakeDeadline(ctx context.Context) time.Time {
+	deadline := timeutil.Now().Add(rawConnHandshakeTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		return d
+	}
+	return deadline
+}
@@ -74,8 +74,9 @@ var snappyReaderPool = sync.Pool{
 type snappyWriter struct {
 	snappy *snappy.Writer
 
-	// Fields used to track and write the decompressed length chunk.
-	inner    io.Writer
+	// Fields used to track and write the decompressed length chunk. inner
+	// also counts the compressed bytes, for metrics.
+	inner    countingWriter
 	wroteLen int
 	buf      [chunkTypeDecompressedLengthMaxSize]byte
 }
@@ -102,7 +103,11 @@ func (w *snappyWriter) Close() error {
 		return errors.Wrapf(err, "writing decompressed size chunk")
 	}
 	// Finally, close the snappy Writer.
-	return w.snappy.Close()
+	if err := w.snappy.Close(); err != nil {
+		return err
+	}
+	recordCompression(w.wroteLen, w.inner.n)
+	return nil
 }
 
 func (w *snappyWriter) release() {
@@ -138,8 +143,8 @@ func (snappyCompressor) Name() string {
 
 func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
 	sw := snappyWriterPool.Get().(*snappyWriter)
-	sw.snappy.Reset(w)
-	sw.inner = w
+	sw.inner = countingWriter{w: w}
+	sw.snappy.Reset(&sw.inner)
 	return sw, nil
 }
 
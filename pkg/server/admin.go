@@ -42,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/apiconstants"
 	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/decommissioning"
 	"github.com/cockroachdb/cockroach/pkg/server/privchecker"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
@@ -2743,6 +2744,77 @@ func (s *systemAdminServer) DecommissionPreCheck(
 	return resp, nil
 }
 
+// DecommissionAnalyze simulates the decommissioning of the given nodes and
+// returns the DecommissionAnalyzeResponse.
+func (s *systemAdminServer) DecommissionAnalyze(
+	ctx context.Context, req *serverpb.DecommissionAnalyzeRequest,
+) (*serverpb.DecommissionAnalyzeResponse, error) {
+	vitality, err := s.nodeLiveness.ScanNodeVitalityFromKV(ctx)
+	if err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+
+	// As in DecommissionPreCheck, nodes which are already decommissioned or
+	// have unknown liveness are not analyzed.
+	var nodesToAnalyze []roachpb.NodeID
+	readinessByNodeID := make(map[roachpb.NodeID]serverpb.DecommissionPreCheckResponse_NodeReadiness)
+	for _, nID := range req.NodeIDs {
+		vitality := vitality[nID]
+		if vitality.IsDecommissioned() {
+			readinessByNodeID[nID] = serverpb.DecommissionPreCheckResponse_ALREADY_DECOMMISSIONED
+		} else if vitality.LivenessStatus() == livenesspb.NodeLivenessStatus_UNKNOWN {
+			readinessByNodeID[nID] = serverpb.DecommissionPreCheckResponse_UNKNOWN
+		} else {
+			nodesToAnalyze = append(nodesToAnalyze, nID)
+			readinessByNodeID[nID] = serverpb.DecommissionPreCheckResponse_READY
+		}
+	}
+
+	resp := &serverpb.DecommissionAnalyzeResponse{}
+	var results decommissioning.AnalyzeResult
+	if len(nodesToAnalyze) > 0 {
+		if results, err = s.server.DecommissionAnalyze(ctx, nodesToAnalyze); err != nil {
+			return nil, err
+		}
+	}
+
+	resp.RangesChecked = int64(results.RangesChecked)
+	resp.ActionCounts = make(map[string]int64, len(results.ActionCounts))
+	for action, count := range results.ActionCounts {
+		resp.ActionCounts[action] = int64(count)
+	}
+	resp.NumViolations = int64(len(results.RangesNotReady))
+	for _, rangeWithErr := range results.RangesNotReady {
+		for _, nID := range nodesToAnalyze {
+			if rangeWithErr.Desc.Replicas().HasReplicaOnNode(nID) {
+				readinessByNodeID[nID] = serverpb.DecommissionPreCheckResponse_ALLOCATION_ERRORS
+			}
+		}
+		if len(resp.Violations) < int(req.NumViolationsReport) {
+			resp.Violations = append(resp.Violations, serverpb.DecommissionPreCheckResponse_RangeCheckResult{
+				RangeID: rangeWithErr.Desc.RangeID,
+				Action:  rangeWithErr.Action,
+				Error:   rangeWithErr.Err.Error(),
+			})
+		}
+	}
+
+	// Report the nodes in request order.
+	for _, nID := range req.NodeIDs {
+		resp.Nodes = append(resp.Nodes, serverpb.DecommissionAnalyzeResponse_NodeAnalysis{
+			NodeID:                nID,
+			DecommissionReadiness: readinessByNodeID[nID],
+			ReplicaCount:          int64(len(results.ReplicasByNode[nID])),
+			LogicalBytes:          results.BytesByNode[nID],
+		})
+		resp.TotalBytes += results.BytesByNode[nID]
+	}
+	resp.ReceivingStores = int32(results.ReceivingStores)
+	resp.SnapshotRate = results.SnapshotRate
+	resp.EstimatedDuration = results.EstimatedDuration
+	return resp, nil
+}
+
 // DecommissionStatus returns the DecommissionStatus for all or the given nodes.
 func (s *systemAdminServer) DecommissionStatus(
 	ctx context.Context, req *serverpb.DecommissionStatusRequest,
@@ -3285,6 +3357,46 @@ func (s *systemAdminServer) enqueueRangeLocal(
 	return response, nil
 }
 
+// defaultAllocatorSimulateMaxRanges is the number of ranges simulated by
+// AllocatorSimulate when the request doesn't specify one.
+const defaultAllocatorSimulateMaxRanges = 10000
+
+// AllocatorSimulate runs the allocator in simulation against the ranges in the
+// requested span under the proposed span config, and returns the
+// AllocatorSimulateResponse.
+func (s *systemAdminServer) AllocatorSimulate(
+	ctx context.Context, req *serverpb.AllocatorSimulateRequest,
+) (*serverpb.AllocatorSimulateResponse, error) {
+	ctx = authserver.ForwardSQLIdentityThroughRPCCalls(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	if err := s.privilegeChecker.RequireViewClusterMetadataPermission(ctx); err != nil {
+		// NB: not using srverrors.ServerError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	if !req.Span.Valid() || len(req.Span.EndKey) == 0 {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, "invalid span %s", req.Span)
+	}
+	if _, err := keys.Addr(req.Span.Key); err != nil {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, "invalid span %s: %v", req.Span, err)
+	}
+	diff, err := makeZoneConfigDiff(&req.ZoneConfig, req.ZoneConfigFields)
+	if err != nil {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, "invalid zone config change: %v", err)
+	}
+	if req.MaxRanges < 0 {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, "max_ranges must be non-negative; got %d", req.MaxRanges)
+	}
+	maxRanges := int(req.MaxRanges)
+	if maxRanges == 0 {
+		maxRanges = defaultAllocatorSimulateMaxRanges
+	}
+
+	return s.server.AllocatorSimulate(ctx, req.Span, diff, maxRanges, int(req.NumRangesReport))
+}
+
 // SendKVBatch proxies the given BatchRequest into KV, returning the
 // response. It is for use by the CLI `debug send-kv-batch` command.
 func (s *systemAdminServer) SendKVBatch(
@@ -3393,6 +3505,19 @@ func (s *systemAdminServer) RecoveryStagePlan(
 	return s.server.recoveryServer.StagePlan(ctx, request)
 }
 
+func (s *systemAdminServer) RecoveryApplyPlan(
+	ctx context.Context, request *serverpb.RecoveryApplyPlanRequest,
+) (*serverpb.RecoveryApplyPlanResponse, error) {
+	ctx = s.server.AnnotateCtx(ctx)
+	err := s.privilegeChecker.RequireRepairClusterPermission(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ops.Info(ctx, "applying recovery plan")
+	return s.server.recoveryServer.ApplyPlan(ctx, request)
+}
+
 func (s *systemAdminServer) RecoveryNodeStatus(
 	ctx context.Context, request *serverpb.RecoveryNodeStatusRequest,
 ) (*serverpb.RecoveryNodeStatusResponse, error) {
@@ -0,0 +1,355 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/storepool"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/rangedesc"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// maxAllocatorSimulateSteps is the maximum number of replication changes
+// simulated for a single range.
+const maxAllocatorSimulateSteps = 16
+
+// zoneConfigDiff is a change to the replication fields of the zone configs of
+// the simulated ranges, as made by ALTER ... CONFIGURE ZONE USING.
+type zoneConfigDiff struct {
+	// fields are the names of the changed fields.
+	fields []string
+	// conf contains the changed fields, converted to their span config
+	// representation.
+	conf roachpb.SpanConfig
+}
+
+// makeZoneConfigDiff validates the given zone config change, which sets the
+// given fields of the zone config to their value in zone.
+func makeZoneConfigDiff(zone *zonepb.ZoneConfig, fields []string) (zoneConfigDiff, error) {
+	if len(fields) == 0 {
+		return zoneConfigDiff{}, errors.New("no field changed")
+	}
+	for _, field := range fields {
+		switch field {
+		case "num_replicas":
+			if zone.NumReplicas == nil || *zone.NumReplicas <= 0 {
+				return zoneConfigDiff{}, errors.New("num_replicas must be positive")
+			}
+		case "num_voters":
+			if zone.NumVoters == nil || *zone.NumVoters <= 0 {
+				return zoneConfigDiff{}, errors.New("num_voters must be positive")
+			}
+		case "constraints", "voter_constraints", "lease_preferences":
+		default:
+			return zoneConfigDiff{}, errors.Newf("unsupported field %q", field)
+		}
+	}
+	// The conversion to a span config expects known constraint types.
+	checkConstraints := func(field string, constraints []zonepb.Constraint) error {
+		for _, c := range constraints {
+			if c.Type != zonepb.Constraint_REQUIRED && c.Type != zonepb.Constraint_PROHIBITED {
+				return errors.Newf("%s must be either required or prohibited", field)
+			}
+		}
+		return nil
+	}
+	for _, conj := range zone.Constraints {
+		if err := checkConstraints("constraints", conj.Constraints); err != nil {
+			return zoneConfigDiff{}, err
+		}
+	}
+	for _, conj := range zone.VoterConstraints {
+		if err := checkConstraints("voter_constraints", conj.Constraints); err != nil {
+			return zoneConfigDiff{}, err
+		}
+	}
+	for _, pref := range zone.LeasePreferences {
+		if err := checkConstraints("lease_preferences", pref.Constraints); err != nil {
+			return zoneConfigDiff{}, err
+		}
+	}
+	// Hydrate the unchanged fields required by the conversion; they are
+	// ignored.
+	hydrated := protoutil.Clone(zone).(*zonepb.ZoneConfig)
+	hydrated.InheritFromParent(zonepb.DefaultZoneConfigRef())
+	return zoneConfigDiff{fields: fields, conf: hydrated.AsSpanConfig()}, nil
+}
+
+// apply returns the given span config with the changed fields.
+func (d zoneConfigDiff) apply(conf roachpb.SpanConfig) roachpb.SpanConfig {
+	for _, field := range d.fields {
+		switch field {
+		case "num_replicas":
+			conf.NumReplicas = d.conf.NumReplicas
+		case "num_voters":
+			conf.NumVoters = d.conf.NumVoters
+		case "constraints":
+			conf.Constraints = d.conf.Constraints
+		case "voter_constraints":
+			conf.VoterConstraints = d.conf.VoterConstraints
+		case "lease_preferences":
+			conf.LeasePreferences = d.conf.LeasePreferences
+		}
+	}
+	return conf
+}
+
+// simulatedRange is the result of the simulation of a range with planned
+// changes or an error.
+type simulatedRange struct {
+	desc    roachpb.RangeDescriptor
+	changes []kvserver.SimulatedReplicationChange
+	final   *roachpb.RangeDescriptor
+	err     error
+	// logicalBytes returns the logical bytes of the range.
+	logicalBytes func() int64
+}
+
+// storeSimulationDelta tracks the replicas added to and removed from a store
+// by the simulation.
+type storeSimulationDelta struct {
+	added, removed, bytesAdded int64
+}
+
+// AllocatorSimulate runs the allocator in simulation against the ranges
+// overlapping the given span, up to maxRanges of them, as though their span
+// config were changed by diff. It reports the planned changes of up to
+// numRangesReport ranges. The ranges are simulated in order against a
+// simulated store pool, so that the changes planned for a range account for
+// those planned for the ranges before it. See
+// kvserver.Store.AllocatorSimulateRange for the details of the simulation.
+// The error returned is a gRPC error.
+func (s *topLevelServer) AllocatorSimulate(
+	ctx context.Context,
+	span roachpb.Span,
+	diff zoneConfigDiff,
+	maxRanges int,
+	numRangesReport int,
+) (*serverpb.AllocatorSimulateResponse, error) {
+	// As in DecommissionPreCheck, simulate using the first store on this node.
+	var evalStore *kvserver.Store
+	err := s.node.stores.VisitStores(func(s *kvserver.Store) error {
+		if evalStore == nil {
+			evalStore = s
+		}
+		return nil
+	})
+	if err == nil && evalStore == nil {
+		err = errors.Errorf("n%d has no initialized store", s.NodeID())
+	}
+	if err != nil {
+		return nil, grpcstatus.Error(codes.NotFound, err.Error())
+	}
+	confReader, err := evalStore.GetConfReader(ctx)
+	if err != nil {
+		return nil, grpcstatus.Error(codes.Unavailable, err.Error())
+	}
+
+	var rangesChecked int
+	var truncated bool
+	var simulated []simulatedRange
+	var storePool *storepool.SimulatedStorePool
+	const pageSize = 10000
+
+	// Iterate through the range descriptors using the rangedesc.Scanner, which
+	// will perform the requisite meta1/meta2 lookups, including retries. The
+	// results, including the simulated changes to the stores, need to be reset
+	// on any transaction retries.
+	initResults := func() {
+		rangesChecked = 0
+		truncated = false
+		simulated = simulated[:0]
+		storePool = storepool.NewSimulatedStorePool(s.storePool)
+	}
+	rangeDescScanner := rangedesc.NewScanner(s.db)
+	err = rangeDescScanner.Scan(ctx, pageSize, initResults, span, func(descriptors ...roachpb.RangeDescriptor) error {
+		for i := range descriptors {
+			desc := descriptors[i]
+			if rangesChecked >= maxRanges {
+				truncated = true
+				continue
+			}
+			rangesChecked++
+			r := simulatedRange{desc: desc}
+			var logicalBytes int64
+			var fetched bool
+			r.logicalBytes = func() int64 {
+				if !fetched {
+					fetched = true
+					var err error
+					if logicalBytes, err = s.rangeLogicalBytes(ctx, &desc); err != nil {
+						log.Warningf(ctx, "unable to fetch the stats of r%d: %v", desc.RangeID, err)
+					}
+				}
+				return logicalBytes
+			}
+			conf, _, err := confReader.GetSpanConfigForKey(ctx, desc.StartKey)
+			if err == nil {
+				conf = diff.apply(conf)
+				if conf.NumVoters > conf.NumReplicas {
+					err = errors.Newf("num_voters (%d) exceeds num_replicas (%d)",
+						conf.NumVoters, conf.NumReplicas)
+				}
+			}
+			if err == nil {
+				r.changes, r.final, r.err = evalStore.AllocatorSimulateRange(
+					ctx, &desc, &conf, storePool, maxAllocatorSimulateSteps, r.logicalBytes,
+				)
+			} else {
+				r.final, r.err = &desc, err
+			}
+			if len(r.changes) == 0 && r.err == nil {
+				continue
+			}
+			simulated = append(simulated, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, grpcstatus.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &serverpb.AllocatorSimulateResponse{
+		RangesChecked: int64(rangesChecked),
+		Truncated:     truncated,
+		ActionCounts:  make(map[string]int64),
+		SnapshotRate:  kvserver.RebalanceSnapshotRate(&s.st.SV),
+	}
+	deltas := make(map[roachpb.StoreID]*storeSimulationDelta)
+	getDelta := func(storeID roachpb.StoreID) *storeSimulationDelta {
+		d, ok := deltas[storeID]
+		if !ok {
+			d = &storeSimulationDelta{}
+			deltas[storeID] = d
+		}
+		return d
+	}
+	for _, r := range simulated {
+		if len(r.changes) > 0 {
+			resp.RangesChanged++
+		}
+		if r.err != nil {
+			resp.NumErrors++
+		}
+		for _, change := range r.changes {
+			resp.ActionCounts[change.Action.String()]++
+		}
+		// Account for the net changes to the placement of the replicas, so that
+		// a replica added by a change and moved by a later one only counts once.
+		// A replica whose type changes stays on its store.
+		initialStores := make(map[roachpb.StoreID]struct{})
+		for _, repl := range r.desc.Replicas().Descriptors() {
+			initialStores[repl.StoreID] = struct{}{}
+		}
+		finalStores := make(map[roachpb.StoreID]struct{})
+		var logicalBytes int64
+		for _, repl := range r.final.Replicas().Descriptors() {
+			finalStores[repl.StoreID] = struct{}{}
+			if _, ok := initialStores[repl.StoreID]; ok {
+				continue
+			}
+			logicalBytes = r.logicalBytes()
+			d := getDelta(repl.StoreID)
+			d.added++
+			d.bytesAdded += logicalBytes
+			resp.TotalBytes += logicalBytes
+		}
+		for storeID := range initialStores {
+			if _, ok := finalStores[storeID]; !ok {
+				getDelta(storeID).removed++
+			}
+		}
+		if len(resp.Ranges) >= numRangesReport {
+			continue
+		}
+		result := serverpb.AllocatorSimulateResponse_RangeResult{
+			RangeID:       r.desc.RangeID,
+			FinalReplicas: r.final.Replicas().Descriptors(),
+			LogicalBytes:  logicalBytes,
+		}
+		if r.err != nil {
+			result.Error = r.err.Error()
+		}
+		for _, change := range r.changes {
+			result.Changes = append(result.Changes, serverpb.AllocatorSimulateResponse_Change{
+				Action:  change.Action.String(),
+				Changes: change.Changes,
+				Details: change.Details,
+			})
+		}
+		resp.Ranges = append(resp.Ranges, result)
+	}
+
+	// Report the placement of replicas on every store, in store ID order.
+	var maxBytesAdded int64
+	for storeID, desc := range s.storePool.GetStores() {
+		var d storeSimulationDelta
+		if delta, ok := deltas[storeID]; ok {
+			d = *delta
+		}
+		resp.Stores = append(resp.Stores, serverpb.AllocatorSimulateResponse_StoreResult{
+			StoreID:           storeID,
+			NodeID:            desc.Node.NodeID,
+			ReplicaCount:      int64(desc.Capacity.RangeCount),
+			FinalReplicaCount: int64(desc.Capacity.RangeCount) + d.added - d.removed,
+			ReplicasAdded:     d.added,
+			ReplicasRemoved:   d.removed,
+			BytesAdded:        d.bytesAdded,
+		})
+		if d.bytesAdded > maxBytesAdded {
+			maxBytesAdded = d.bytesAdded
+		}
+	}
+	sort.Slice(resp.Stores, func(i, j int) bool {
+		return resp.Stores[i].StoreID < resp.Stores[j].StoreID
+	})
+	// The stores ingest snapshots concurrently, so the time needed is that of
+	// the store receiving the most data.
+	if resp.SnapshotRate > 0 {
+		resp.EstimatedDuration = time.Duration(
+			float64(maxBytesAdded) / float64(resp.SnapshotRate) * float64(time.Second))
+	}
+	return resp, nil
+}
+
+// rangeLogicalBytes returns the logical bytes of the given range, as reported
+// by its leaseholder.
+func (s *topLevelServer) rangeLogicalBytes(
+	ctx context.Context, desc *roachpb.RangeDescriptor,
+) (int64, error) {
+	key := desc.StartKey.AsRawKey()
+	if desc.StartKey.Equal(roachpb.RKeyMin) {
+		// The first range can't be addressed by its start key.
+		key = keys.LocalMax
+	}
+	ba := &kvpb.BatchRequest{}
+	ba.Add(&kvpb.RangeStatsRequest{
+		RequestHeader: kvpb.RequestHeader{Key: key},
+	})
+	br, pErr := s.db.NonTransactionalSender().Send(ctx, ba)
+	if pErr != nil {
+		return 0, pErr.GoError()
+	}
+	return br.Responses[0].GetInner().(*kvpb.RangeStatsResponse).MVCCStats.Total(), nil
+}
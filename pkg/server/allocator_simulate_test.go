@@ -0,0 +1,80 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneConfigDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	zone := zonepb.ZoneConfig{
+		NumReplicas: proto.Int32(5),
+		NumVoters:   proto.Int32(3),
+		Constraints: []zonepb.ConstraintsConjunction{{
+			Constraints: []zonepb.Constraint{
+				{Type: zonepb.Constraint_REQUIRED, Key: "region", Value: "us-east1"},
+			},
+		}},
+	}
+	conf := roachpb.TestingDefaultSpanConfig()
+	conf.LeasePreferences = []roachpb.LeasePreference{{
+		Constraints: []roachpb.Constraint{
+			{Type: roachpb.Constraint_REQUIRED, Key: "region", Value: "us-west1"},
+		},
+	}}
+
+	// Only the listed fields are changed.
+	diff, err := makeZoneConfigDiff(&zone, []string{"num_replicas", "constraints"})
+	require.NoError(t, err)
+	changed := diff.apply(conf)
+	require.Equal(t, int32(5), changed.NumReplicas)
+	require.Equal(t, conf.NumVoters, changed.NumVoters)
+	require.Equal(t, []roachpb.ConstraintsConjunction{{
+		Constraints: []roachpb.Constraint{
+			{Type: roachpb.Constraint_REQUIRED, Key: "region", Value: "us-east1"},
+		},
+	}}, changed.Constraints)
+	require.Equal(t, conf.LeasePreferences, changed.LeasePreferences)
+	require.Equal(t, conf.RangeMaxBytes, changed.RangeMaxBytes)
+
+	// Listed fields can clear the current value.
+	diff, err = makeZoneConfigDiff(&zone, []string{"lease_preferences"})
+	require.NoError(t, err)
+	require.Empty(t, diff.apply(conf).LeasePreferences)
+
+	for _, tc := range []struct {
+		fields []string
+		zone   zonepb.ZoneConfig
+	}{
+		{fields: nil, zone: zone},
+		{fields: []string{"range_max_bytes"}, zone: zone},
+		{fields: []string{"num_voters"}, zone: zonepb.ZoneConfig{}},
+		{fields: []string{"num_replicas"}, zone: zonepb.ZoneConfig{NumReplicas: proto.Int32(0)}},
+		{fields: []string{"lease_preferences"}, zone: zonepb.ZoneConfig{
+			LeasePreferences: []zonepb.LeasePreference{{
+				Constraints: []zonepb.Constraint{{Type: zonepb.Constraint_DEPRECATED_POSITIVE}},
+			}},
+		}},
+	} {
+		_, err := makeZoneConfigDiff(&tc.zone, tc.fields)
+		require.Error(t, err, "%v %v", tc.fields, tc.zone)
+	}
+}
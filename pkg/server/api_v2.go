@@ -55,6 +55,7 @@ type ApiV2System interface {
 	health(w http.ResponseWriter, r *http.Request)
 	listNodes(w http.ResponseWriter, r *http.Request)
 	listNodeRanges(w http.ResponseWriter, r *http.Request)
+	topology(w http.ResponseWriter, r *http.Request)
 }
 
 type apiV2ServerOpts struct {
@@ -174,6 +175,7 @@ func registerRoutes(
 		// Any endpoint returning range information requires an admin user. This is because range start/end keys
 		// are sensitive info.
 		{"nodes/{node_id}/ranges/", systemRoutes.listNodeRanges, true, authserver.ViewClusterMetadataRole, false},
+		{"topology/", systemRoutes.topology, true, authserver.ViewClusterMetadataRole, false},
 		{"ranges/hot/", a.listHotRanges, true, authserver.ViewClusterMetadataRole, false},
 		{"ranges/{range_id:[0-9]+}/", a.listRange, true, authserver.ViewClusterMetadataRole, false},
 		{"health/", systemRoutes.health, false, authserver.RegularRole, false},
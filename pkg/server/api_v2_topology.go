@@ -0,0 +1,278 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/apiutil"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+)
+
+// Health of a region, derived from the liveness of its nodes.
+const (
+	// regionHealthy indicates that all nodes in the region are live.
+	regionHealthy = "healthy"
+	// regionDegraded indicates that some, but not all, nodes in the region are
+	// live.
+	regionDegraded = "degraded"
+	// regionUnavailable indicates that no node in the region is live.
+	regionUnavailable = "unavailable"
+)
+
+// A node in the cluster topology.
+type topologyNode struct {
+	// NodeID is the integer ID of this node.
+	NodeID int32 `json:"node_id"`
+	// Locality is the locality of this node.
+	Locality roachpb.Locality `json:"locality"`
+	// LivenessStatus is the status of the node from the perspective of the
+	// liveness subsystem. For internal use only.
+	LivenessStatus int32 `json:"liveness_status"`
+	// ReplicaCount is the number of replicas on the stores of this node.
+	ReplicaCount int64 `json:"replica_count"`
+	// LeaseholderCount is the number of leaseholders on the stores of this
+	// node.
+	LeaseholderCount int64 `json:"leaseholder_count"`
+	// CapacityBytes is the total capacity of the stores of this node.
+	CapacityBytes int64 `json:"capacity_bytes"`
+	// AvailableBytes is the available capacity of the stores of this node.
+	AvailableBytes int64 `json:"available_bytes"`
+	// UsedBytes is the capacity used by the stores of this node.
+	UsedBytes int64 `json:"used_bytes"`
+}
+
+// A region in the cluster topology.
+type topologyRegion struct {
+	// Region is the value of the "region" locality tier of the nodes in this
+	// region. It is empty for nodes without a region tier.
+	Region string `json:"region"`
+	// Health is the health of the region: "healthy" if all of its nodes are
+	// live, "degraded" if some are, and "unavailable" if none are.
+	Health string `json:"health"`
+	// Nodes are the nodes in this region.
+	Nodes []topologyNode `json:"nodes"`
+	// LiveNodeCount is the number of live nodes in this region.
+	LiveNodeCount int32 `json:"live_node_count"`
+	// ReplicaCount is the number of replicas in this region.
+	ReplicaCount int64 `json:"replica_count"`
+	// LeaseholderCount is the number of leaseholders in this region.
+	LeaseholderCount int64 `json:"leaseholder_count"`
+	// CapacityBytes is the total capacity of the stores in this region.
+	CapacityBytes int64 `json:"capacity_bytes"`
+	// AvailableBytes is the available capacity of the stores in this region.
+	AvailableBytes int64 `json:"available_bytes"`
+	// UsedBytes is the capacity used by the stores in this region.
+	UsedBytes int64 `json:"used_bytes"`
+	// ReplicaPercent is the percentage of the cluster's replicas in this
+	// region.
+	ReplicaPercent float64 `json:"replica_percent"`
+	// LeaseholderPercent is the percentage of the cluster's leaseholders in
+	// this region.
+	LeaseholderPercent float64 `json:"leaseholder_percent"`
+	// UsedPercent is the percentage of the cluster's used capacity in this
+	// region.
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// The network latency between two regions.
+type topologyLatency struct {
+	// FromRegion is the region of the nodes the latency is measured from.
+	FromRegion string `json:"from_region"`
+	// ToRegion is the region of the nodes the latency is measured to.
+	ToRegion string `json:"to_region"`
+	// AvgLatencyNanos is the average latency between the nodes of both
+	// regions, in nanoseconds.
+	AvgLatencyNanos int64 `json:"avg_latency_nanos"`
+	// MaxLatencyNanos is the maximum latency between the nodes of both
+	// regions, in nanoseconds.
+	MaxLatencyNanos int64 `json:"max_latency_nanos"`
+}
+
+// Response struct for topology.
+type topologyResponse struct {
+	// Regions of the cluster, ordered by name.
+	Regions []topologyRegion `json:"regions"`
+	// Latencies between the regions of the cluster, including between the
+	// nodes of each region, ordered by region names.
+	Latencies []topologyLatency `json:"latencies"`
+	// ReplicaCount is the number of replicas in the cluster.
+	ReplicaCount int64 `json:"replica_count"`
+	// LeaseholderCount is the number of leaseholders in the cluster.
+	LeaseholderCount int64 `json:"leaseholder_count"`
+	// CapacityBytes is the total capacity of the stores in the cluster.
+	CapacityBytes int64 `json:"capacity_bytes"`
+	// AvailableBytes is the available capacity of the stores in the cluster.
+	AvailableBytes int64 `json:"available_bytes"`
+	// UsedBytes is the capacity used by the stores in the cluster.
+	UsedBytes int64 `json:"used_bytes"`
+}
+
+// # Get cluster topology
+//
+// Returns the topology of the cluster: its nodes grouped by region, with the
+// health, replica and leaseholder counts, capacity and share of the cluster's
+// data of each region, as well as the network latencies between regions.
+// Decommissioned nodes are omitted.
+//
+// Client must be logged-in as a user with admin privileges.
+//
+// ---
+// produces:
+// - application/json
+// security:
+// - api_session: []
+// responses:
+//
+//	"200":
+//	  description: Cluster topology response.
+//	  schema:
+//	    "$ref": "#/definitions/topologyResponse"
+func (a *apiV2SystemServer) topology(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = authserver.ForwardHTTPAuthInfoToRPCCalls(ctx, r)
+
+	nodes, _, err := a.systemStatus.nodesHelper(ctx, 0 /* limit */, 0 /* offset */)
+	if err != nil {
+		srverrors.APIV2InternalError(ctx, err, w)
+		return
+	}
+	apiutil.WriteJSONResponse(ctx, w, http.StatusOK, makeTopologyResponse(nodes))
+}
+
+func (a *apiV2Server) topology(w http.ResponseWriter, r *http.Request) {
+	apiutil.WriteJSONResponse(r.Context(), w, http.StatusNotImplemented, nil)
+}
+
+// makeTopologyResponse aggregates the given node statuses into the cluster
+// topology.
+func makeTopologyResponse(nodes *serverpb.NodesResponse) topologyResponse {
+	var resp topologyResponse
+	regions := make(map[string]*topologyRegion)
+	nodeRegions := make(map[roachpb.NodeID]string)
+	for i := range nodes.Nodes {
+		n := &nodes.Nodes[i]
+		liveness := nodes.LivenessByNodeID[n.Desc.NodeID]
+		if liveness == livenesspb.NodeLivenessStatus_DECOMMISSIONED {
+			continue
+		}
+		region, _ := n.Desc.Locality.Find("region")
+		nodeRegions[n.Desc.NodeID] = region
+		node := topologyNode{
+			NodeID:         int32(n.Desc.NodeID),
+			Locality:       n.Desc.Locality,
+			LivenessStatus: int32(liveness),
+		}
+		for _, ss := range n.StoreStatuses {
+			node.ReplicaCount += int64(ss.Desc.Capacity.RangeCount)
+			node.LeaseholderCount += int64(ss.Desc.Capacity.LeaseCount)
+			node.CapacityBytes += ss.Desc.Capacity.Capacity
+			node.AvailableBytes += ss.Desc.Capacity.Available
+			node.UsedBytes += ss.Desc.Capacity.Used
+		}
+
+		r, ok := regions[region]
+		if !ok {
+			r = &topologyRegion{Region: region}
+			regions[region] = r
+		}
+		r.Nodes = append(r.Nodes, node)
+		if liveness == livenesspb.NodeLivenessStatus_LIVE {
+			r.LiveNodeCount++
+		}
+		r.ReplicaCount += node.ReplicaCount
+		r.LeaseholderCount += node.LeaseholderCount
+		r.CapacityBytes += node.CapacityBytes
+		r.AvailableBytes += node.AvailableBytes
+		r.UsedBytes += node.UsedBytes
+
+		resp.ReplicaCount += node.ReplicaCount
+		resp.LeaseholderCount += node.LeaseholderCount
+		resp.CapacityBytes += node.CapacityBytes
+		resp.AvailableBytes += node.AvailableBytes
+		resp.UsedBytes += node.UsedBytes
+	}
+
+	percent := func(v, total int64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(v) / float64(total) * 100
+	}
+	for _, r := range regions {
+		switch {
+		case int(r.LiveNodeCount) == len(r.Nodes):
+			r.Health = regionHealthy
+		case r.LiveNodeCount > 0:
+			r.Health = regionDegraded
+		default:
+			r.Health = regionUnavailable
+		}
+		r.ReplicaPercent = percent(r.ReplicaCount, resp.ReplicaCount)
+		r.LeaseholderPercent = percent(r.LeaseholderCount, resp.LeaseholderCount)
+		r.UsedPercent = percent(r.UsedBytes, resp.UsedBytes)
+		sort.Slice(r.Nodes, func(i, j int) bool {
+			return r.Nodes[i].NodeID < r.Nodes[j].NodeID
+		})
+		resp.Regions = append(resp.Regions, *r)
+	}
+	sort.Slice(resp.Regions, func(i, j int) bool {
+		return resp.Regions[i].Region < resp.Regions[j].Region
+	})
+
+	type regionPair struct{ from, to string }
+	type latencyStats struct{ sum, max, count int64 }
+	latencies := make(map[regionPair]*latencyStats)
+	for i := range nodes.Nodes {
+		n := &nodes.Nodes[i]
+		from, ok := nodeRegions[n.Desc.NodeID]
+		if !ok {
+			continue
+		}
+		for nodeID, activity := range n.Activity {
+			to, ok := nodeRegions[nodeID]
+			if !ok || nodeID == n.Desc.NodeID || activity.Latency <= 0 {
+				continue
+			}
+			pair := regionPair{from: from, to: to}
+			s, ok := latencies[pair]
+			if !ok {
+				s = &latencyStats{}
+				latencies[pair] = s
+			}
+			s.sum += activity.Latency
+			s.count++
+			if activity.Latency > s.max {
+				s.max = activity.Latency
+			}
+		}
+	}
+	for pair, s := range latencies {
+		resp.Latencies = append(resp.Latencies, topologyLatency{
+			FromRegion:      pair.from,
+			ToRegion:        pair.to,
+			AvgLatencyNanos: s.sum / s.count,
+			MaxLatencyNanos: s.max,
+		})
+	}
+	sort.Slice(resp.Latencies, func(i, j int) bool {
+		if resp.Latencies[i].FromRegion != resp.Latencies[j].FromRegion {
+			return resp.Latencies[i].FromRegion < resp.Latencies[j].FromRegion
+		}
+		return resp.Latencies[i].ToRegion < resp.Latencies[j].ToRegion
+	})
+	return resp
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/apiconstants"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeTopologyResponse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	node := func(
+		nodeID roachpb.NodeID, region string, replicas, leases int32, used int64,
+		latencies map[roachpb.NodeID]int64,
+	) statuspb.NodeStatus {
+		ns := statuspb.NodeStatus{
+			Desc: roachpb.NodeDescriptor{
+				NodeID: nodeID,
+				Locality: roachpb.Locality{Tiers: []roachpb.Tier{
+					{Key: "region", Value: region},
+				}},
+			},
+			StoreStatuses: []statuspb.StoreStatus{{
+				Desc: roachpb.StoreDescriptor{Capacity: roachpb.StoreCapacity{
+					Capacity:   1000,
+					Available:  1000 - used,
+					Used:       used,
+					RangeCount: replicas,
+					LeaseCount: leases,
+				}},
+			}},
+			Activity: map[roachpb.NodeID]statuspb.NodeStatus_NetworkActivity{},
+		}
+		for id, latency := range latencies {
+			ns.Activity[id] = statuspb.NodeStatus_NetworkActivity{Latency: latency}
+		}
+		return ns
+	}
+	nodes := &serverpb.NodesResponse{
+		Nodes: []statuspb.NodeStatus{
+			node(1, "us-east1", 30, 20, 300, map[roachpb.NodeID]int64{2: 10, 3: 100, 4: 1000}),
+			node(2, "us-east1", 30, 10, 300, map[roachpb.NodeID]int64{1: 20, 3: 80}),
+			node(3, "us-west1", 40, 10, 400, map[roachpb.NodeID]int64{1: 120, 2: 90}),
+			node(4, "us-west1", 0, 0, 0, nil),
+		},
+		LivenessByNodeID: map[roachpb.NodeID]livenesspb.NodeLivenessStatus{
+			1: livenesspb.NodeLivenessStatus_LIVE,
+			2: livenesspb.NodeLivenessStatus_DEAD,
+			3: livenesspb.NodeLivenessStatus_LIVE,
+			4: livenesspb.NodeLivenessStatus_DECOMMISSIONED,
+		},
+	}
+
+	resp := makeTopologyResponse(nodes)
+	require.Equal(t, int64(100), resp.ReplicaCount)
+	require.Equal(t, int64(40), resp.LeaseholderCount)
+	require.Equal(t, int64(3000), resp.CapacityBytes)
+	require.Equal(t, int64(1000), resp.UsedBytes)
+
+	// The decommissioned node 4 is omitted.
+	require.Len(t, resp.Regions, 2)
+	east, west := resp.Regions[0], resp.Regions[1]
+	require.Equal(t, "us-east1", east.Region)
+	require.Equal(t, regionDegraded, east.Health)
+	require.Len(t, east.Nodes, 2)
+	require.Equal(t, int32(1), east.LiveNodeCount)
+	require.Equal(t, int64(60), east.ReplicaCount)
+	require.Equal(t, 60.0, east.ReplicaPercent)
+	require.Equal(t, 75.0, east.LeaseholderPercent)
+	require.Equal(t, 60.0, east.UsedPercent)
+
+	require.Equal(t, "us-west1", west.Region)
+	require.Equal(t, regionHealthy, west.Health)
+	require.Len(t, west.Nodes, 1)
+	require.Equal(t, 40.0, west.ReplicaPercent)
+
+	require.Equal(t, []topologyLatency{
+		{FromRegion: "us-east1", ToRegion: "us-east1", AvgLatencyNanos: 15, MaxLatencyNanos: 20},
+		{FromRegion: "us-east1", ToRegion: "us-west1", AvgLatencyNanos: 90, MaxLatencyNanos: 100},
+		{FromRegion: "us-west1", ToRegion: "us-east1", AvgLatencyNanos: 105, MaxLatencyNanos: 120},
+	}, resp.Latencies)
+}
+
+func TestTopologyV2(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testCluster := serverutils.StartCluster(t, 3, base.TestClusterArgs{})
+	ctx := context.Background()
+	defer testCluster.Stopper().Stop(ctx)
+
+	ts := testCluster.Server(0)
+	client, err := ts.GetAdminHTTPClient()
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("GET", ts.AdminURL().WithPath(apiconstants.APIV2Path+"topology/").String(), nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var tr topologyResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&tr))
+	require.NoError(t, resp.Body.Close())
+	// The test cluster's nodes have no region locality tier, so they are all
+	// part of the same, unnamed, region.
+	require.Len(t, tr.Regions, 1)
+	require.Len(t, tr.Regions[0].Nodes, 3)
+	require.Equal(t, regionHealthy, tr.Regions[0].Health)
+}
@@ -783,6 +783,7 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 			storage.Attributes(spec.Attributes),
 			storage.If(storeKnobs.SmallEngineBlocks, storage.BlockSize(1)),
 			storage.DiskWriteStatsCollector(cfg.DiskWriteStatsCollector),
+			storage.If(spec.WALFailoverThreshold > 0, storage.WALFailoverThreshold(spec.WALFailoverThreshold)),
 		}
 		if len(storeKnobs.EngineKnobs) > 0 {
 			storageConfigOpts = append(storageConfigOpts, storeKnobs.EngineKnobs...)
@@ -267,6 +267,63 @@ func (s *topLevelServer) DecommissionPreCheck(
 	}, nil
 }
 
+// DecommissionAnalyze simulates the decommissioning of the given nodes. On top
+// of the result of DecommissionPreCheck, evaluated for all the ranges on the
+// nodes without strict readiness, it reports the volume of data to move and an
+// estimate of the time needed to move it.
+// The error returned is a gRPC error.
+func (s *topLevelServer) DecommissionAnalyze(
+	ctx context.Context, nodeIDs []roachpb.NodeID,
+) (decommissioning.AnalyzeResult, error) {
+	preCheck, err := s.DecommissionPreCheck(
+		ctx, nodeIDs, false /* strictReadiness */, false /* collectTraces */, 0, /* maxErrors */
+	)
+	if err != nil {
+		return decommissioning.AnalyzeResult{}, err
+	}
+	res := decommissioning.AnalyzeResult{
+		PreCheckResult: preCheck,
+		BytesByNode:    make(map[roachpb.NodeID]int64, len(nodeIDs)),
+		SnapshotRate:   kvserver.RebalanceSnapshotRate(&s.st.SV),
+	}
+	for _, nodeID := range nodeIDs {
+		res.BytesByNode[nodeID] = 0
+	}
+	for _, desc := range s.storePool.GetStores() {
+		if _, ok := res.BytesByNode[desc.Node.NodeID]; ok {
+			res.BytesByNode[desc.Node.NodeID] += desc.Capacity.LogicalBytes
+		}
+	}
+	liveStores, _, _ := s.storePool.GetStoreList(storepool.StoreFilterNone)
+	for _, desc := range liveStores.Stores {
+		if _, ok := res.BytesByNode[desc.Node.NodeID]; !ok {
+			res.ReceivingStores++
+		}
+	}
+	var totalBytes int64
+	for _, bytes := range res.BytesByNode {
+		totalBytes += bytes
+	}
+	res.EstimatedDuration = estimateDecommissionDuration(totalBytes, res.SnapshotRate, res.ReceivingStores)
+	return res, nil
+}
+
+// estimateDecommissionDuration estimates the time needed to move the given
+// number of bytes to the given number of stores, assuming that each store
+// ingests one snapshot at a time, sent at the given rate. The estimate is a
+// lower bound, since the data isn't spread evenly across the receiving stores
+// and snapshots don't always reach the rate limit.
+func estimateDecommissionDuration(bytes, rate int64, receivingStores int) time.Duration {
+	if bytes <= 0 || rate <= 0 {
+		return 0
+	}
+	if receivingStores < 1 {
+		receivingStores = 1
+	}
+	seconds := float64(bytes) / float64(rate) / float64(receivingStores)
+	return time.Duration(seconds * float64(time.Second))
+}
+
 // evaluateRangeCheckResult returns true or false if the range has passed
 // decommissioning checks (based on if we are testing strict readiness or not),
 // as well as the encapsulated range check result with errors defined as needed.
@@ -11,6 +11,8 @@
 package decommissioning
 
 import (
+	"time"
+
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing/tracingpb"
 )
@@ -33,3 +35,18 @@ type PreCheckResult struct {
 	ActionCounts   map[string]int
 	RangesNotReady []RangeCheckResult
 }
+
+// AnalyzeResult is the result of simulating the decommissioning of a node or
+// set of nodes.
+type AnalyzeResult struct {
+	PreCheckResult
+	// BytesByNode is the logical bytes of the replicas on each node, as last
+	// gossiped by its stores.
+	BytesByNode map[roachpb.NodeID]int64
+	// ReceivingStores is the number of live stores on other nodes.
+	ReceivingStores int
+	// SnapshotRate is the rate limit, in bytes/s, of rebalance snapshots.
+	SnapshotRate int64
+	// EstimatedDuration is the estimated time needed to move the replicas.
+	EstimatedDuration time.Duration
+}
@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/serverctl"
@@ -121,6 +122,33 @@ func (s *adminServer) Drain(req *serverpb.DrainRequest, stream serverpb.Admin_Dr
 	return s.drainServer.handleDrain(ctx, req, stream)
 }
 
+// DrainStatus reports the progress of the shedding of range leases and raft
+// leaderships by a draining node.
+// This method is part of the serverpb.AdminClient interface.
+func (s *adminServer) DrainStatus(
+	ctx context.Context, req *serverpb.DrainStatusRequest,
+) (*serverpb.DrainStatusResponse, error) {
+	ctx = s.AnnotateCtx(ctx)
+
+	nodeID, local, err := s.serverIterator.parseServerID(req.NodeId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if !local {
+		client, err := s.dialNode(ctx, roachpb.NodeID(nodeID))
+		if err != nil {
+			return nil, srverrors.ServerError(ctx, err)
+		}
+		return client.DrainStatus(ctx, req)
+	}
+
+	resp, err := s.drainServer.drainStatus(ctx)
+	if err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+	return resp, nil
+}
+
 type drainServer struct {
 	stopper *stop.Stopper
 	// stopTrigger is used to request that the server is shut down.
@@ -191,6 +219,30 @@ func (s *drainServer) handleDrain(
 	return s.maybeShutdownAfterDrain(ctx, req)
 }
 
+// drainStatus reports the progress of the drain of the local stores.
+func (s *drainServer) drainStatus(ctx context.Context) (*serverpb.DrainStatusResponse, error) {
+	resp := &serverpb.DrainStatusResponse{IsDraining: s.isDraining()}
+	if s.kvServer.node == nil {
+		// No KV subsystem. Nothing to report.
+		return resp, nil
+	}
+	if err := s.kvServer.node.stores.VisitStores(func(store *kvserver.Store) error {
+		progress := store.DrainProgress(ctx)
+		resp.Stores = append(resp.Stores, serverpb.StoreDrainStatus{
+			StoreID:                  store.StoreID(),
+			SystemLeasesRemaining:    int64(progress.SystemLeases),
+			LeasesRemaining:          int64(progress.Leases),
+			RaftLeadershipsRemaining: int64(progress.RaftLeaderships),
+			LeaseTransfers:           progress.LeaseTransfers,
+			RaftLeadershipTransfers:  progress.RaftLeadershipTransfers,
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (s *drainServer) maybeShutdownAfterDrain(
 	ctx context.Context, req *serverpb.DrainRequest,
 ) error {
@@ -456,6 +508,10 @@ func (s *drainServer) drainClients(
 	// shutdown.
 	s.sqlServer.jobRegistry.WaitForRegistryShutdown(ctx)
 
+	// Persist the hot SQL cache entries so that they can be pre-warmed when
+	// the server restarts. This must be done before the leases are drained.
+	s.sqlServer.maybePersistCacheWarmup(ctx)
+
 	// Drain all SQL table leases. This must be done after the pgServer has
 	// given sessions a chance to finish ongoing work and after the background
 	// tasks that may issue SQL statements have shut down.
@@ -367,3 +367,58 @@ func TestNoSQLServer(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, drainResp.IsDraining)
 }
+
+// TestDrainStatus tests that the DrainStatus RPC reports the progress of the
+// shedding of leases by a draining node.
+func TestDrainStatus(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 3,
+		base.TestClusterArgs{
+			ServerArgs: base.TestServerArgs{
+				DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+			},
+		})
+	defer tc.Stopper().Stop(ctx)
+
+	// Move a lease to the node to be drained.
+	scratch := tc.ScratchRange(t)
+	desc := tc.AddVotersOrFatal(t, scratch, tc.Targets(1, 2)...)
+	tc.TransferRangeLeaseOrFatal(t, desc, tc.Target(2))
+
+	c := tc.Server(0).GetAdminClient(t)
+
+	resp, err := c.DrainStatus(ctx, &serverpb.DrainStatusRequest{NodeId: "3"})
+	require.NoError(t, err)
+	require.False(t, resp.IsDraining)
+	require.Len(t, resp.Stores, 1)
+	require.Equal(t, tc.Server(2).GetFirstStoreID(), resp.Stores[0].StoreID)
+	require.Zero(t, resp.Stores[0].LeaseTransfers)
+
+	testutils.SucceedsSoon(t, func() error {
+		stream, err := c.Drain(ctx, &serverpb.DrainRequest{DoDrain: true, NodeId: "3"})
+		if err != nil {
+			return err
+		}
+		drainResp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if drainResp.DrainRemainingIndicator > 0 {
+			return errors.Newf("still %d remaining, desc: %s", drainResp.DrainRemainingIndicator,
+				drainResp.DrainRemainingDescription)
+		}
+		return nil
+	})
+
+	resp, err = c.DrainStatus(ctx, &serverpb.DrainStatusRequest{NodeId: "3"})
+	require.NoError(t, err)
+	require.True(t, resp.IsDraining)
+	require.Len(t, resp.Stores, 1)
+	store := resp.Stores[0]
+	require.Zero(t, store.SystemLeasesRemaining)
+	require.Zero(t, store.LeasesRemaining)
+	require.Positive(t, store.LeaseTransfers)
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RewriteInactiveKeyFiles implements the serverpb.StatusServer interface.
+func (s *systemStatusServer) RewriteInactiveKeyFiles(
+	ctx context.Context, req *serverpb.RewriteInactiveKeyFilesRequest,
+) (*serverpb.RewriteInactiveKeyFilesResponse, error) {
+	ctx = authserver.ForwardSQLIdentityThroughRPCCalls(ctx)
+	ctx = s.AnnotateCtx(ctx)
+	if err := s.privilegeChecker.RequireRepairClusterPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	resp := &serverpb.RewriteInactiveKeyFilesResponse{
+		ErrorsByNodeID: make(map[roachpb.NodeID]string),
+	}
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if !local {
+			client, err := s.dialNode(ctx, requestedNodeID)
+			if err != nil {
+				return nil, srverrors.ServerError(ctx, err)
+			}
+			return client.RewriteInactiveKeyFiles(ctx, req)
+		}
+		var errs error
+		if err := s.stores.VisitStores(func(store *kvserver.Store) error {
+			// The rewrite runs in the background, started with the server's
+			// context so that it outlives the request.
+			if err := store.StartInactiveKeyRewrite(store.AnnotateCtx(context.Background())); err != nil {
+				errs = errors.CombineErrors(errs, errors.Wrapf(err, "s%d", store.StoreID()))
+			}
+			return nil
+		}); err != nil {
+			return nil, srverrors.ServerError(ctx, err)
+		}
+		if errs != nil {
+			return nil, status.Error(codes.FailedPrecondition, errs.Error())
+		}
+		return resp, nil
+	}
+
+	remoteRequest := serverpb.RewriteInactiveKeyFilesRequest{NodeID: "local"}
+	nodeFn := func(
+		ctx context.Context, client serverpb.StatusClient, _ roachpb.NodeID,
+	) (*serverpb.RewriteInactiveKeyFilesResponse, error) {
+		return client.RewriteInactiveKeyFiles(ctx, &remoteRequest)
+	}
+	responseFn := func(roachpb.NodeID, *serverpb.RewriteInactiveKeyFilesResponse) {}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		resp.ErrorsByNodeID[nodeID] = err.Error()
+	}
+	if err := iterateNodes(ctx, s.serverIterator, s.stopper, "rewrite inactive key files",
+		noTimeout,
+		s.dialNode,
+		nodeFn,
+		responseFn,
+		errorFn,
+	); err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+	return resp, nil
+}
+
+// rewriteInactiveKeyFilesFunc returns the eval.RewriteInactiveKeyFilesFunc
+// used by the crdb_internal.rewrite_inactive_key_files builtin, which is only
+// available to the system tenant.
+func rewriteInactiveKeyFilesFunc(
+	nodesStatusServer serverpb.OptionalNodesStatusServer,
+) eval.RewriteInactiveKeyFilesFunc {
+	return func(ctx context.Context, nodeID int32) error {
+		ss, err := nodesStatusServer.OptionalNodesStatusServer()
+		if err != nil {
+			return err
+		}
+		req := &serverpb.RewriteInactiveKeyFilesRequest{}
+		if nodeID != 0 {
+			req.NodeID = roachpb.NodeID(nodeID).String()
+		}
+		resp, err := ss.RewriteInactiveKeyFiles(ctx, req)
+		if err != nil {
+			return err
+		}
+		nodeIDs := make([]roachpb.NodeID, 0, len(resp.ErrorsByNodeID))
+		for id := range resp.ErrorsByNodeID {
+			nodeIDs = append(nodeIDs, id)
+		}
+		sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+		var errs error
+		for _, id := range nodeIDs {
+			errs = errors.CombineErrors(errs, errors.Newf("n%d: %s", id, resp.ErrorsByNodeID[id]))
+		}
+		return errs
+	}
+}
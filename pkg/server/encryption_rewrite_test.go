@@ -0,0 +1,61 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteInactiveKeyFiles(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s := serverutils.StartServerOnly(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+	})
+	defer s.Stopper().Stop(ctx)
+
+	client := s.GetStatusClient(t)
+	resp, err := client.RewriteInactiveKeyFiles(ctx, &serverpb.RewriteInactiveKeyFilesRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.ErrorsByNodeID)
+
+	// The store is not encrypted, so the rewrite completes without rewriting
+	// anything.
+	testutils.SucceedsSoon(t, func() error {
+		stores, err := client.Stores(ctx, &serverpb.StoresRequest{NodeId: "local"})
+		if err != nil {
+			return err
+		}
+		for _, store := range stores.Stores {
+			if store.InactiveKeyRewriteRunning {
+				return errors.Errorf("s%d is still rewriting", store.StoreID)
+			}
+			require.Zero(t, store.InactiveKeyRewriteBytes)
+			require.Empty(t, store.KeyStats)
+		}
+		return nil
+	})
+
+	_, err = client.RewriteInactiveKeyFiles(ctx, &serverpb.RewriteInactiveKeyFilesRequest{NodeID: "local"})
+	require.NoError(t, err)
+}
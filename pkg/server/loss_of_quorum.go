@@ -34,6 +34,18 @@ import (
 // inside a vfs for the first store if in memory configuration is used.
 // This function will respect sticky in-memory configuration of test clusters.
 func newPlanStore(cfg Config) (loqrecovery.PlanStore, error) {
+	path, fs, err := firstStoreFS(cfg)
+	if err != nil {
+		return loqrecovery.PlanStore{}, err
+	}
+	return loqrecovery.NewPlanStore(path, fs), nil
+}
+
+// firstStoreFS returns the directory and the filesystem of the first store,
+// which can be used to persist node-local state across restarts. For in memory
+// configurations, the returned filesystem is a vfs that respects the sticky
+// in-memory configuration of test clusters.
+func firstStoreFS(cfg Config) (string, vfs.FS, error) {
 	spec := cfg.Stores.Specs[0]
 	fs := vfs.Default
 	path := spec.Path
@@ -41,13 +53,13 @@ func newPlanStore(cfg Config) (loqrecovery.PlanStore, error) {
 		path = ""
 		if spec.StickyVFSID != "" {
 			if cfg.TestingKnobs.Server == nil {
-				return loqrecovery.PlanStore{}, errors.AssertionFailedf("Could not create a sticky " +
+				return "", nil, errors.AssertionFailedf("Could not create a sticky " +
 					"engine no server knobs available to get a registry. " +
 					"Please use Knobs.Server.StickyVFSRegistry to provide one.")
 			}
 			knobs := cfg.TestingKnobs.Server.(*TestingKnobs)
 			if knobs.StickyVFSRegistry == nil {
-				return loqrecovery.PlanStore{}, errors.Errorf("Could not create a sticky " +
+				return "", nil, errors.Errorf("Could not create a sticky " +
 					"engine no registry available. Please use " +
 					"Knobs.Server.StickyVFSRegistry to provide one.")
 			}
@@ -56,7 +68,7 @@ func newPlanStore(cfg Config) (loqrecovery.PlanStore, error) {
 			fs = vfs.NewMem()
 		}
 	}
-	return loqrecovery.NewPlanStore(path, fs), nil
+	return path, fs, nil
 }
 
 func logPendingLossOfQuorumRecoveryEvents(ctx context.Context, stores *kvserver.Stores) {
@@ -0,0 +1,47 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+)
+
+// MinServableStaleness implements the serverpb.StatusServer interface.
+func (s *statusServer) MinServableStaleness(
+	ctx context.Context, req *serverpb.MinServableStalenessRequest,
+) (*serverpb.MinServableStalenessResponse, error) {
+	ctx = authserver.ForwardSQLIdentityThroughRPCCalls(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	// The keys are arbitrary, so they can be used to probe the keyspace of
+	// the cluster.
+	if err := s.privilegeChecker.RequireViewClusterMetadataPermission(ctx); err != nil {
+		// NB: not using srverrors.ServerError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	resolvedTS, err := s.db.QueryResolvedTimestampForKeys(ctx, req.Keys, true /* nearest */)
+	if err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+	resp := &serverpb.MinServableStalenessResponse{}
+	if resolvedTS.IsEmpty() {
+		return resp, nil
+	}
+	resp.ResolvedTimestamp = resolvedTS.GoTime()
+	resp.Staleness = max(s.clock.PhysicalTime().Sub(resp.ResolvedTimestamp), 0)
+	return resp, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/apiconstants"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srvtestutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinServableStaleness(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+
+	client := s.GetStatusClient(t)
+	resp, err := client.MinServableStaleness(ctx, &serverpb.MinServableStalenessRequest{})
+	require.NoError(t, err)
+	require.True(t, resp.ResolvedTimestamp.IsZero())
+	require.Zero(t, resp.Staleness)
+
+	// The keys of the descriptor and namespace tables are closed and can be
+	// read with some staleness.
+	req := &serverpb.MinServableStalenessRequest{
+		Keys: []roachpb.Key{
+			s.Codec().TablePrefix(keys.DescriptorTableID),
+			s.Codec().TablePrefix(keys.NamespaceTableID),
+		},
+	}
+	testutils.SucceedsSoon(t, func() error {
+		start := timeutil.Now()
+		resp, err := client.MinServableStaleness(ctx, req)
+		if err != nil {
+			return err
+		}
+		if resp.ResolvedTimestamp.IsZero() {
+			return errors.New("resolved timestamp not yet known")
+		}
+		require.False(t, resp.ResolvedTimestamp.After(timeutil.Now()))
+		require.Less(t, resp.Staleness, time.Hour)
+		require.False(t, resp.ResolvedTimestamp.Add(resp.Staleness).Before(start))
+		return nil
+	})
+
+	// The builtin function returns the same staleness.
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	testutils.SucceedsSoon(t, func() error {
+		var staleness *string
+		sqlDB.QueryRow(t, `SELECT crdb_internal.min_servable_staleness(ARRAY[
+  crdb_internal.table_span('system.descriptor'::REGCLASS::INT)[1],
+  crdb_internal.table_span('system.namespace'::REGCLASS::INT)[1]
+])`).Scan(&staleness)
+		if staleness == nil {
+			return errors.New("resolved timestamp not yet known")
+		}
+		return nil
+	})
+	sqlDB.CheckQueryResults(t,
+		`SELECT crdb_internal.min_servable_staleness(ARRAY[]::BYTES[]) IS NULL`,
+		[][]string{{"true"}})
+}
+
+func TestMinServableStalenessPrivileges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	// The endpoint requires VIEWCLUSTERMETADATA.
+	var resp serverpb.MinServableStalenessResponse
+	req := &serverpb.MinServableStalenessRequest{}
+	err := srvtestutils.PostStatusJSONProtoWithAdminOption(
+		s, "min_servable_staleness", req, &resp, false, /* isAdmin */
+	)
+	require.True(t, testutils.IsError(err, "status: 403"), "expected privilege error, got %v", err)
+	sqlDB.Exec(t, fmt.Sprintf("GRANT SYSTEM VIEWCLUSTERMETADATA TO %s",
+		apiconstants.TestingUserNameNoAdmin().Normalized()))
+	require.NoError(t, srvtestutils.PostStatusJSONProtoWithAdminOption(
+		s, "min_servable_staleness", req, &resp, false, /* isAdmin */
+	))
+
+	// So does the builtin function.
+	sqlDB.Exec(t, "CREATE USER testuser")
+	testUserDB := sqlutils.MakeSQLRunner(s.SQLConn(t, serverutils.User("testuser")))
+	const query = `SELECT crdb_internal.min_servable_staleness(ARRAY[]::BYTES[])`
+	testUserDB.ExpectErr(t, "VIEWCLUSTERMETADATA", query)
+	sqlDB.Exec(t, "GRANT SYSTEM VIEWCLUSTERMETADATA TO testuser")
+	testUserDB.Exec(t, query)
+}
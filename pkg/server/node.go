@@ -215,6 +215,13 @@ This metric is thus not an indicator of KV health.`,
 		Measurement: "Streams",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaSpanConfigLimitRejections = metric.Metadata{
+		Name: "spanconfig.tenant_limit.rejected_updates",
+		Help: "Number of span config updates of virtual clusters rejected because " +
+			"they would exceed the max_span_configs capability",
+		Measurement: "Updates",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // Cluster settings.
@@ -274,6 +281,7 @@ type nodeMetrics struct {
 	ActiveRangeFeed               *metric.Gauge
 	NumMuxRangeFeed               *metric.Counter
 	ActiveMuxRangeFeed            *metric.Gauge
+	SpanConfigLimitRejections     *metric.Counter
 }
 
 func makeNodeMetrics(reg *metric.Registry, histogramWindow time.Duration) nodeMetrics {
@@ -297,6 +305,7 @@ func makeNodeMetrics(reg *metric.Registry, histogramWindow time.Duration) nodeMe
 		NumRangeFeed:                  metric.NewCounter(metaTotalRangeFeed),
 		ActiveMuxRangeFeed:            metric.NewGauge(metaActiveMuxRangeFeed),
 		NumMuxRangeFeed:               metric.NewCounter(metaTotalMuxRangeFeed),
+		SpanConfigLimitRejections:     metric.NewCounter(metaSpanConfigLimitRejections),
 	}
 
 	for i := range nm.MethodCounts {
@@ -396,6 +405,10 @@ type Node struct {
 
 	spanConfigReporter spanconfig.Reporter // powers the span configuration RPCs
 
+	// spanConfigCounts caches the number of span configs of the secondary
+	// tenants whose span config updates are limited by the host.
+	spanConfigCounts spanConfigCounts
+
 	// Turns `Node.writeNodeStatus` into a no-op. This is a hack to enable the
 	// COCKROACH_DEBUG_TS_IMPORT_FILE env var.
 	suppressNodeStatus syncutil.AtomicBool
@@ -1622,6 +1635,10 @@ func (n *Node) Batch(ctx context.Context, args *kvpb.BatchRequest) (*kvpb.BatchR
 	} else {
 		// We had this tag before the ResetAndAnnotateCtx() call above.
 		ctx = logtags.AddTag(ctx, "tenant", tenantID)
+		// Also attribute the request in the logs of the request. The values of
+		// the tags are only computed if something is logged.
+		ctx = logtags.AddTag(ctx, "pri", (*batchPriorityLogTag)(args))
+		ctx = logtags.AddTag(ctx, "cost", (*batchCostClassLogTag)(args))
 	}
 
 	// If the node is collecting a CPU profile with labels, and the sender has set
@@ -1760,7 +1777,13 @@ func setupSpanForIncomingRPC(
 			tracing.WithServerSpanKind)
 	}
 
-	newSpan.SetLazyTag("request", ba.ShallowCopy())
+	baCopy := ba.ShallowCopy()
+	newSpan.SetLazyTag("request", baCopy)
+	if !tenID.IsSystem() && !newSpan.IsNoop() {
+		// Attribute the request to the tenant in its trace. The tags are only
+		// rendered if the trace is collected.
+		newSpan.SetLazyTag("tenant_request", &tenantRequestSpanTags{tenantID: tenID, ba: baCopy})
+	}
 	return ctx, spanForRequest{
 		// For non-local requests, we'll need to attach the recording to the
 		// outgoing BatchResponse if the request is traced. We ignore whether the
@@ -2588,6 +2611,8 @@ var NewTenantUsageServer = func(
 	settings *cluster.Settings,
 	db *kv.DB,
 	ief isql.DB,
+	capabilities tenantcapabilities.Reader,
+	dataSizeReader multitenant.TenantDataSizeReader,
 ) multitenant.TenantUsageServer {
 	return dummyTenantUsageServer{}
 }
@@ -2619,6 +2644,38 @@ func (dummyTenantUsageServer) ReconfigureTokenBucket(
 	return errors.Errorf("tenant resource limits require a CCL binary")
 }
 
+// GrantBurst is defined in the TenantUsageServer interface.
+func (dummyTenantUsageServer) GrantBurst(
+	ctx context.Context,
+	txn isql.Txn,
+	tenantID roachpb.TenantID,
+	burstRate float64,
+	duration time.Duration,
+) error {
+	return errors.Errorf("tenant resource limits require a CCL binary")
+}
+
+// GetTenantConsumption is defined in the TenantUsageServer interface.
+func (dummyTenantUsageServer) GetTenantConsumption(
+	ctx context.Context, txn isql.Txn, tenantID roachpb.TenantID,
+) (multitenant.TenantConsumptionInfo, error) {
+	return multitenant.TenantConsumptionInfo{}, errors.Errorf("tenant consumption requires a CCL binary")
+}
+
+// GetConsumptionSummaries is defined in the TenantUsageServer interface.
+func (dummyTenantUsageServer) GetConsumptionSummaries(
+	window time.Duration,
+) []multitenant.TenantConsumptionSummary {
+	return nil
+}
+
+// GetThrottledTenants is defined in the TenantUsageServer interface.
+func (dummyTenantUsageServer) GetThrottledTenants(
+	ctx context.Context,
+) ([]roachpb.TenantID, error) {
+	return nil, nil
+}
+
 // Metrics is defined in the TenantUsageServer interface.
 func (dummyTenantUsageServer) Metrics() metric.Struct {
 	return emptyMetricStruct{}
@@ -2675,6 +2732,16 @@ func (n *Node) UpdateSpanConfigs(
 	if err != nil {
 		return nil, err
 	}
+	tenID, limited := roachpb.ClientTenantFromContext(ctx)
+	var delta int
+	if limited {
+		if delta, err = n.checkSpanConfigLimit(ctx, tenID, toDelete, toUpsert); err != nil {
+			n.metrics.SpanConfigLimitRejections.Inc(1)
+			return &roachpb.UpdateSpanConfigsResponse{
+				Error: errors.EncodeError(ctx, err),
+			}, nil
+		}
+	}
 	if err := n.spanConfigAccessor.UpdateSpanConfigRecords(
 		ctx, toDelete, toUpsert, req.MinCommitTimestamp, req.MaxCommitTimestamp,
 	); err != nil {
@@ -2682,9 +2749,184 @@ func (n *Node) UpdateSpanConfigs(
 			Error: errors.EncodeError(ctx, err),
 		}, nil
 	}
+	if limited {
+		n.spanConfigCounts.add(tenID, delta)
+	}
 	return &roachpb.UpdateSpanConfigsResponse{}, nil
 }
 
+// spanConfigCountRefreshInterval is the interval after which the number of
+// span configs of a tenant cached by spanConfigCounts is recomputed.
+const spanConfigCountRefreshInterval = time.Minute
+
+// spanConfigCounts caches the number of span configs of secondary tenants, so
+// that checkSpanConfigLimit doesn't scan all the span configs of a tenant on
+// every update. The counts are maintained incrementally with the updates
+// served by this node. Updates of a tenant can also be served by other nodes,
+// so the counts are recomputed once they are older than
+// spanConfigCountRefreshInterval.
+type spanConfigCounts struct {
+	syncutil.Mutex
+	m map[roachpb.TenantID]spanConfigCount
+}
+
+type spanConfigCount struct {
+	count      int
+	computedAt time.Time
+}
+
+// get returns the number of span configs of the given tenant, calling compute
+// to count them if there is no recent cached count.
+func (c *spanConfigCounts) get(
+	tenID roachpb.TenantID, compute func() (int, error),
+) (int, error) {
+	now := timeutil.Now()
+	c.Lock()
+	cached, ok := c.m[tenID]
+	c.Unlock()
+	if ok && now.Sub(cached.computedAt) < spanConfigCountRefreshInterval {
+		return cached.count, nil
+	}
+	count, err := compute()
+	if err != nil {
+		return 0, err
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.m == nil {
+		c.m = make(map[roachpb.TenantID]spanConfigCount)
+	}
+	c.m[tenID] = spanConfigCount{count: count, computedAt: now}
+	return count, nil
+}
+
+// add adjusts the cached number of span configs of the given tenant, if any,
+// after an update.
+func (c *spanConfigCounts) add(tenID roachpb.TenantID, delta int) {
+	c.Lock()
+	defer c.Unlock()
+	if cached, ok := c.m[tenID]; ok {
+		cached.count += delta
+		c.m[tenID] = cached
+	}
+}
+
+// forget drops the cached number of span configs of the given tenant.
+func (c *spanConfigCounts) forget(tenID roachpb.TenantID) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.m, tenID)
+}
+
+// checkSpanConfigLimit returns an error if the given update of the span
+// configs of a secondary tenant would make it exceed the number of span
+// configs allowed by its max_span_configs capability. Updates that don't
+// increase the number of span configs are always allowed, so that tenants
+// over their limit can get back under it. Otherwise, it returns the change in
+// the number of span configs of the tenant that the update makes.
+//
+// The tenant is also expected to limit itself (see spanconfiglimiter), but
+// the host can't rely on that.
+func (n *Node) checkSpanConfigLimit(
+	ctx context.Context,
+	tenID roachpb.TenantID,
+	toDelete []spanconfig.Target,
+	toUpsert []spanconfig.Record,
+) (delta int, _ error) {
+	capabilities, found := n.tenantInfoWatcher.GetCapabilities(tenID)
+	if !found {
+		return 0, nil
+	}
+	limit := tenantcapabilities.MustGetInt64ByID(capabilities, tenantcapabilities.MaxSpanConfigs)
+	if limit <= 0 {
+		n.spanConfigCounts.forget(tenID)
+		return 0, nil
+	}
+
+	// The span targets of a tenant don't overlap, so they're identified by
+	// their start keys. Look up which of the start keys touched by the update
+	// have a span config. system.span_configurations is keyed by start key, so
+	// this doesn't read the other span configs of the tenant.
+	var targets []spanconfig.Target
+	existed := make(map[string]bool)
+	exists := make(map[string]bool)
+	touch := func(target spanconfig.Target, upsert bool) {
+		if !target.IsSpanTarget() {
+			return
+		}
+		key := string(target.GetSpan().Key)
+		if _, ok := existed[key]; !ok {
+			existed[key] = false
+			targets = append(targets, target)
+		}
+		// Deletions are applied before upserts.
+		exists[key] = upsert
+	}
+	for _, target := range toDelete {
+		touch(target, false /* upsert */)
+	}
+	for i := range toUpsert {
+		touch(toUpsert[i].GetTarget(), true /* upsert */)
+	}
+	if len(targets) == 0 {
+		return 0, nil
+	}
+	records, err := n.spanConfigAccessor.GetSpanConfigRecords(ctx, targets)
+	if err != nil {
+		return 0, err
+	}
+	for i := range records {
+		if target := records[i].GetTarget(); target.IsSpanTarget() {
+			key := string(target.GetSpan().Key)
+			if _, ok := existed[key]; ok {
+				existed[key] = true
+			}
+		}
+	}
+	for key, after := range exists {
+		if before := existed[key]; after && !before {
+			delta++
+		} else if before && !after {
+			delta--
+		}
+	}
+	if delta <= 0 {
+		return delta, nil
+	}
+
+	before, err := n.spanConfigCounts.get(tenID, func() (int, error) {
+		return n.countSpanConfigs(ctx, tenID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if after := before + delta; int64(after) > limit {
+		return 0, errors.WithHintf(
+			errors.Newf("virtual cluster %s would have %d span configs, exceeding its limit of %d",
+				tenID, after, limit),
+			"the limit is set by the %s capability", tenantcapabilities.MaxSpanConfigs)
+	}
+	return delta, nil
+}
+
+// countSpanConfigs returns the number of span configs of the given secondary
+// tenant. It reads all of them.
+func (n *Node) countSpanConfigs(ctx context.Context, tenID roachpb.TenantID) (int, error) {
+	records, err := n.spanConfigAccessor.GetSpanConfigRecords(ctx, []spanconfig.Target{
+		spanconfig.MakeTargetFromSpan(keys.MakeTenantSpan(tenID)),
+	})
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	for i := range records {
+		if records[i].GetTarget().IsSpanTarget() {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // SpanConfigConformance implements the kvpb.InternalServer interface.
 func (n *Node) SpanConfigConformance(
 	ctx context.Context, req *roachpb.SpanConfigConformanceRequest,
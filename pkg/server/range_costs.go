@@ -0,0 +1,102 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RangeCosts implements the serverpb.StatusServer interface.
+func (s *statusServer) RangeCosts(
+	ctx context.Context, req *serverpb.RangeCostsRequest,
+) (*serverpb.RangeCostsResponse, error) {
+	ctx = authserver.ForwardSQLIdentityThroughRPCCalls(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	// Check permissions early to avoid fan-out to all nodes.
+	if err := s.privilegeChecker.RequireViewActivityOrViewActivityRedactedPermission(ctx); err != nil {
+		// NB: not using srverrors.ServerError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	localRequest := serverpb.RangeCostsRequest{NodeID: "local"}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if local {
+			return s.localRangeCosts(), nil
+		}
+		statusClient, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, srverrors.ServerError(ctx, err)
+		}
+		return statusClient.RangeCosts(ctx, &localRequest)
+	}
+
+	var response serverpb.RangeCostsResponse
+
+	nodeFn := func(ctx context.Context, statusClient serverpb.StatusClient, _ roachpb.NodeID) (*serverpb.RangeCostsResponse, error) {
+		return statusClient.RangeCosts(ctx, &localRequest)
+	}
+	responseFn := func(_ roachpb.NodeID, resp *serverpb.RangeCostsResponse) {
+		if resp == nil {
+			return
+		}
+		response.Costs = append(response.Costs, resp.Costs...)
+	}
+	errorFn := func(_ roachpb.NodeID, err error) {
+		response.Errors = append(response.Errors, errors.EncodeError(ctx, err))
+	}
+
+	if err := iterateNodes(ctx, s.serverIterator, s.stopper, "range costs", noTimeout,
+		s.dialNode, nodeFn,
+		responseFn, errorFn); err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+	return &response, nil
+}
+
+// localRangeCosts returns the range costs recorded by the tenant cost
+// controller of this SQL instance. There are none in the system tenant, which
+// is not metered.
+func (s *statusServer) localRangeCosts() *serverpb.RangeCostsResponse {
+	response := &serverpb.RangeCostsResponse{}
+	costController := s.sqlServer.execCfg.DistSQLSrv.TenantCostController
+	if costController == nil {
+		return response
+	}
+	instanceID := roachpb.NodeID(s.sqlServer.SQLInstanceID())
+	for _, c := range costController.GetRangeCosts() {
+		response.Costs = append(response.Costs, serverpb.RangeCostsResponse_RangeCost{
+			NodeID:         instanceID,
+			RangeID:        c.RangeID,
+			Since:          c.Since,
+			RU:             c.RU,
+			KVCPUSeconds:   c.KVCPUSeconds,
+			ReadBytes:      c.ReadBytes,
+			WriteBytes:     c.WriteBytes,
+			SampledBatches: c.SampledBatches,
+		})
+	}
+	return response
+}
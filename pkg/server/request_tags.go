@@ -0,0 +1,94 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/redact"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// costClass is the class of cost of a KV request, as charged by the tenant
+// cost model.
+type costClass int8
+
+const (
+	// costClassRead is the class of read-only requests, which are charged for
+	// the data they read.
+	costClassRead costClass = iota
+	// costClassWrite is the class of requests which write, and are charged for
+	// the data they write on each replica.
+	costClassWrite
+)
+
+// String implements the fmt.Stringer interface.
+func (c costClass) String() string {
+	if c == costClassWrite {
+		return "write"
+	}
+	return "read"
+}
+
+// SafeValue implements the redact.SafeValue interface.
+func (costClass) SafeValue() {}
+
+func batchCostClass(ba *kvpb.BatchRequest) costClass {
+	if ba.IsWrite() {
+		return costClassWrite
+	}
+	return costClassRead
+}
+
+// The log tags and span tags attributing a request to the virtual cluster
+// which issued it are derived from the authenticated tenant ID and the batch
+// when the request is served, so that they can't be spoofed by the client.
+// They are only computed when they are rendered, so that requests which are
+// neither logged nor traced don't pay for them.
+
+// batchPriorityLogTag is the value of the log tag of the admission priority of
+// a batch.
+type batchPriorityLogTag kvpb.BatchRequest
+
+// SafeFormat implements the redact.SafeFormatter interface.
+func (t *batchPriorityLogTag) SafeFormat(w redact.SafePrinter, _ rune) {
+	w.Print(admissionpb.WorkPriority(t.AdmissionHeader.Priority))
+}
+
+func (t *batchPriorityLogTag) String() string { return redact.StringWithoutMarkers(t) }
+
+// batchCostClassLogTag is the value of the log tag of the cost class of a
+// batch.
+type batchCostClassLogTag kvpb.BatchRequest
+
+// SafeFormat implements the redact.SafeFormatter interface.
+func (t *batchCostClassLogTag) SafeFormat(w redact.SafePrinter, _ rune) {
+	w.Print(batchCostClass((*kvpb.BatchRequest)(t)))
+}
+
+func (t *batchCostClassLogTag) String() string { return redact.StringWithoutMarkers(t) }
+
+// tenantRequestSpanTags is the lazy span tag attributing a batch to the
+// virtual cluster which issued it.
+type tenantRequestSpanTags struct {
+	tenantID roachpb.TenantID
+	ba       *kvpb.BatchRequest
+}
+
+// Render implements the tracing.LazyTag interface.
+func (t *tenantRequestSpanTags) Render() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int64("tenant", int64(t.tenantID.ToUint64())),
+		attribute.String("priority", admissionpb.WorkPriority(t.ba.AdmissionHeader.Priority).String()),
+		attribute.String("cost_class", batchCostClass(t.ba).String()),
+	}
+}
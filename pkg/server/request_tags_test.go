@@ -0,0 +1,53 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/logtags"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRequestTags(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tenID := roachpb.MustMakeTenantID(10)
+
+	read := &kvpb.BatchRequest{}
+	read.AdmissionHeader.Priority = int32(admissionpb.UserHighPri)
+	read.Add(&kvpb.GetRequest{})
+	require.Equal(t, costClassRead, batchCostClass(read))
+	require.Equal(t, []attribute.KeyValue{
+		attribute.Int64("tenant", 10),
+		attribute.String("priority", admissionpb.UserHighPri.String()),
+		attribute.String("cost_class", "read"),
+	}, (&tenantRequestSpanTags{tenantID: tenID, ba: read}).Render())
+
+	write := &kvpb.BatchRequest{}
+	write.AdmissionHeader.Priority = int32(admissionpb.BulkNormalPri)
+	write.Add(&kvpb.GetRequest{}, &kvpb.PutRequest{})
+	require.Equal(t, costClassWrite, batchCostClass(write))
+
+	// The log tags are rendered like the values they are computed from.
+	ctx := logtags.AddTag(context.Background(), "pri", (*batchPriorityLogTag)(write))
+	ctx = logtags.AddTag(ctx, "cost", (*batchCostClassLogTag)(write))
+	require.Equal(t, "pri="+admissionpb.BulkNormalPri.String()+",cost=write",
+		logtags.FromContext(ctx).String())
+}
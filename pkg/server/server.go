@@ -62,6 +62,7 @@ import (
 	serverrangefeed "github.com/cockroachdb/cockroach/pkg/kv/kvserver/rangefeed"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rangelog"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/reports"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/multitenantcpu"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities/tenantcapabilitiesauthorizer"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities/tenantcapabilitieswatcher"
@@ -92,6 +93,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/spanconfig/spanconfigreporter"
 	"github.com/cockroachdb/cockroach/pkg/spanconfig/spanconfigstore"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/cachewarmup"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	_ "github.com/cockroachdb/cockroach/pkg/sql/catalog/schematelemetry" // register schedules declared outside of pkg/sql
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
@@ -202,6 +204,18 @@ type topLevelServer struct {
 
 	tenantCapabilitiesWatcher *tenantcapabilitieswatcher.Watcher
 
+	// tenantLiveBytes measures the live bytes of the tenants with a
+	// max_live_bytes capability, which are enforced by the tenant authorizer.
+	tenantLiveBytes *tenantLiveBytesMonitor
+
+	// tenantRUThrottling tracks the tenants throttled by tenant cost control,
+	// which are deprioritized by KV admission control.
+	tenantRUThrottling *tenantRUThrottlingMonitor
+
+	// tenantCPUSampler attributes the CPU usage of the process to the
+	// virtual clusters running in shared-process mode.
+	tenantCPUSampler *multitenantcpu.TenantCPUSampler
+
 	// pgL is the SQL listener for pgwire connections coming over the network.
 	pgL net.Listener
 	// loopbackPgL is the SQL listener for internal pgwire connections.
@@ -367,6 +381,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 	}
 
 	appRegistry.AddMetricStruct(rpcContext.Metrics())
+	nodeRegistry.AddMetricStruct(rpc.GetCompressionMetrics())
 
 	// Attempt to load TLS configs right away, failures are permanent.
 	if !cfg.Insecure {
@@ -401,6 +416,15 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		return nil, err
 	}
 	gossip.RegisterGossipServer(grpcServer.Server, g)
+	rpcContext.OnIncomingRawConn = func(ctx context.Context, originNodeID roachpb.NodeID) error {
+		// Like gRPC calls, raw connections (i.e. the framed raft transport) are
+		// only served once the node is operational, and not to decommissioned
+		// nodes.
+		if !grpcServer.operational() {
+			return NewWaitingForInitError("raw connection")
+		}
+		return decommissionCheck(ctx, originNodeID, codes.PermissionDenied)
+	}
 
 	var dialerKnobs nodedialer.DialerTestingKnobs
 	if dk := cfg.TestingKnobs.DialerKnobs; dk != nil {
@@ -829,6 +853,11 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 			uint64(kvserver.EagerLeaseAcquisitionConcurrency.Get(&cfg.Settings.SV)))
 	})
 
+	tenantSettingsWatcher := tenantsettingswatcher.New(
+		clock, rangeFeedFactory, stopper, st,
+	)
+	nodeRegistry.AddMetricStruct(tenantSettingsWatcher.Metrics())
+
 	storeCfg := kvserver.StoreConfig{
 		DefaultSpanConfig:            cfg.DefaultZoneConfig.AsSpanConfig(),
 		Settings:                     st,
@@ -849,6 +878,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		LogRangeAndNodeEvents:        cfg.EventLogEnabled,
 		RangeDescriptorCache:         distSender.RangeDescriptorCache(),
 		TimeSeriesDataStore:          tsDB,
+		TenantSettingOverrides:       tenantSettingsWatcher,
 		ClosedTimestampSender:        ctSender,
 		ClosedTimestampReceiver:      ctReceiver,
 		ProtectedTimestampReader:     protectedTSReader,
@@ -901,12 +931,35 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		updates.TestingKnobs = &cfg.TestingKnobs.Server.(*TestingKnobs).DiagnosticsTestingKnobs
 	}
 
-	tenantUsage := NewTenantUsageServer(st, db, insqlDB)
-	nodeRegistry.AddMetricStruct(tenantUsage.Metrics())
+	lateBoundServer := &topLevelServer{}
 
-	tenantSettingsWatcher := tenantsettingswatcher.New(
-		clock, rangeFeedFactory, stopper, st,
+	// The status server is only created below, so the tenant data size monitor
+	// reaches it through lateBoundServer.
+	tenantDataSizeFn := func(
+		ctx context.Context, tenantID roachpb.TenantID,
+	) (liveBytes, totalBytes int64, _ error) {
+		span := keys.MakeTenantSpan(tenantID)
+		resp, err := lateBoundServer.status.getSpanStatsInternal(ctx, &roachpb.SpanStatsRequest{
+			NodeID: "0", // fan out to all nodes
+			Spans:  []roachpb.Span{span},
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		stats, ok := resp.SpanToStats[span.String()]
+		if !ok {
+			return 0, 0, errors.AssertionFailedf("missing span stats for %s", span)
+		}
+		return stats.TotalStats.LiveBytes, stats.TotalStats.Total(), nil
+	}
+	tenantLiveBytes := newTenantLiveBytesMonitor(
+		tenantCapabilitiesWatcher, tenantDataSizeFn, timeutil.DefaultTimeSource{},
+	)
+	tenantUsage := NewTenantUsageServer(
+		st, db, insqlDB, tenantCapabilitiesWatcher, tenantLiveBytes,
 	)
+	nodeRegistry.AddMetricStruct(tenantUsage.Metrics())
+	tenantRUThrottling := newTenantRUThrottlingMonitor(tenantUsage.GetThrottledTenants)
 
 	node := NewNode(
 		storeCfg,
@@ -942,8 +995,6 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		db, node.stores, storePool, st, nodeLiveness, internalExecutor, systemConfigWatcher,
 	)
 
-	lateBoundServer := &topLevelServer{}
-
 	// The following initialization is mirrored in NewTenantServer().
 	// Please keep them in sync.
 
@@ -1120,6 +1171,16 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 			spanConfigKVAccessor:     spanConfig.kvAccessorForTenantRecords,
 			kvStoresIterator:         kvserver.MakeStoresIterator(node.stores),
 			inspectzServer:           inspectzServer,
+			applyRecoveryPlanFunc: func(ctx context.Context, planID uuid.UUID) ([]string, error) {
+				res, err := lateBoundServer.recoveryServer.ApplyPlan(ctx, &serverpb.RecoveryApplyPlanRequest{
+					PlanID:   planID,
+					AllNodes: true,
+				})
+				if err != nil {
+					return nil, err
+				}
+				return res.Errors, nil
+			},
 
 			notifyChangeToSystemVisibleSettings: tenantSettingsWatcher.SetAlternateDefaults,
 		},
@@ -1162,6 +1223,13 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		return nil, err
 	}
 
+	// The SQL cache warm-up state is persisted alongside the first store.
+	warmupPath, warmupFS, err := firstStoreFS(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create SQL cache warm-up store")
+	}
+	sqlServer.cacheWarmupStore = cachewarmup.NewStore(warmupPath, warmupFS)
+
 	// Tell the authz server how to connect to SQL.
 	adminAuthzCheck.SetAuthzAccessorFactory(func(opName string) (sql.AuthorizationAccessor, func()) {
 		// This is a hack to get around a Go package dependency cycle. See comment
@@ -1257,6 +1325,17 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		func(ctx context.Context, id roachpb.NodeID) error {
 			return nodeTombStorage.SetDecommissioned(ctx, id, timeutil.Now())
 		},
+		func(context.Context) {
+			// Plans applied online don't go through a restart, so we need to
+			// trigger the cleanup that otherwise happens on startup. Cleanup runs
+			// asynchronously and must not be tied to the lifetime of the request.
+			maybeRunLossOfQuorumRecoveryCleanup(
+				lateBoundServer.AnnotateCtx(context.Background()),
+				lateBoundServer.node.execCfg.InternalDB.Executor(),
+				lateBoundServer.node.stores,
+				lateBoundServer,
+				lateBoundServer.stopper)
+		},
 	)
 
 	*lateBoundServer = topLevelServer{
@@ -1305,6 +1384,9 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		spanConfigSubscriber:      spanConfig.subscriber,
 		spanConfigReporter:        spanConfig.reporter,
 		tenantCapabilitiesWatcher: tenantCapabilitiesWatcher,
+		tenantLiveBytes:           tenantLiveBytes,
+		tenantRUThrottling:        tenantRUThrottling,
+		tenantCPUSampler:          multitenantcpu.NewTenantCPUSampler(st),
 		pgPreServer:               pgPreServer,
 		sqlServer:                 sqlServer,
 		serverController:          sc,
@@ -1554,7 +1636,10 @@ func (s *topLevelServer) PreStart(ctx context.Context) error {
 	// below when the server has initialized.
 	pgL, loopbackPgL, rpcLoopbackDialFn, startRPCServer, err := startListenRPCAndSQL(
 		ctx, workersCtx, s.cfg.BaseConfig,
-		s.stopper, s.grpc, ListenAndUpdateAddrs, true /* enableSQLListener */)
+		s.stopper, s.grpc, ListenAndUpdateAddrs, true, /* enableSQLListener */
+		func(ctx context.Context, ln net.Listener) {
+			netutil.FatalIfUnexpected(s.raftTransport.ServeFramed(ctx, ln, s.rpcContext))
+		})
 	if err != nil {
 		return err
 	}
@@ -1897,6 +1982,11 @@ func (s *topLevelServer) PreStart(ctx context.Context) error {
 		return err
 	}
 
+	// Begin attributing the process CPU to shared-process virtual clusters.
+	if err := s.tenantCPUSampler.Start(workersCtx, s.stopper); err != nil {
+		return err
+	}
+
 	// Begin recording time series data collected by the status monitor.
 	// The writes will be async; we'll wait for the first one to go through
 	// later in this method, using the returned channel.
@@ -2178,6 +2268,14 @@ func (s *topLevelServer) PreStart(ctx context.Context) error {
 	// the Reader to the TenantRPCAuthorizer, so that it has a handle into the
 	// global tenant capabilities state.
 	s.rpcContext.TenantRPCAuthorizer.BindReader(s.tenantCapabilitiesWatcher)
+	if err := s.tenantLiveBytes.start(workersCtx, s.stopper); err != nil {
+		return err
+	}
+	s.rpcContext.TenantRPCAuthorizer.BindLiveBytesReader(s.tenantLiveBytes)
+	if err := s.tenantRUThrottling.start(workersCtx, s.stopper); err != nil {
+		return err
+	}
+	s.node.storeCfg.KVAdmissionController.SetRUThrottledTenants(s.tenantRUThrottling)
 
 	if err := s.kvProber.Start(workersCtx, s.stopper); err != nil {
 		return errors.Wrapf(err, "failed to start KV prober")
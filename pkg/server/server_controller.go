@@ -594,8 +594,9 @@ type orchestratedServer interface {
 
 // tenantServerWrapper implements the onDemandServer interface for SQLServerWrapper.
 type tenantServerWrapper struct {
-	stopper *stop.Stopper
-	server  *SQLServerWrapper
+	stopper    *stop.Stopper
+	server     *SQLServerWrapper
+	tenantName *roachpb.TenantNameContainer
 }
 
 var _ onDemandServer = (*tenantServerWrapper)(nil)
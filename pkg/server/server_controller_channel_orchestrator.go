@@ -16,6 +16,7 @@ import (
 	"sync"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/multitenantcpu"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
@@ -334,7 +335,7 @@ func (o *channelOrchestrator) startControlledServer(
 		// goroutines related to this virtual cluster. The
 		// calls here are exactly what pprof.Do does.
 		defer pprof.SetGoroutineLabels(ctx)
-		ctx = pprof.WithLabels(ctx, pprof.Labels("cluster", string(tenantName)))
+		ctx = pprof.WithLabels(ctx, pprof.Labels(multitenantcpu.TenantLabel, string(tenantName)))
 		pprof.SetGoroutineLabels(ctx)
 
 		// We want a context that gets cancelled when the server is
@@ -13,8 +13,10 @@ package server
 import (
 	"context"
 	"net"
+	"runtime/pprof"
 
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/multitenantcpu"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirecancel"
@@ -185,8 +187,14 @@ func (t *tenantServerWrapper) handleCancel(
 
 func (t *tenantServerWrapper) serveConn(
 	ctx context.Context, conn net.Conn, status pgwire.PreServeStatus,
-) error {
+) (err error) {
 	pgCtx := t.server.sqlServer.AnnotateCtx(context.Background())
 	pgCtx = logtags.AddTags(pgCtx, logtags.FromContext(ctx))
-	return t.server.sqlServer.pgServer.ServeConn(pgCtx, conn, status)
+	// The connection is served by a goroutine of the system tenant's listener,
+	// so label it for the CPU it uses to be attributed to the virtual cluster.
+	labels := pprof.Labels(multitenantcpu.TenantLabel, string(t.tenantName.Get()))
+	pprof.Do(pgCtx, labels, func(pgCtx context.Context) {
+		err = t.server.sqlServer.pgServer.ServeConn(pgCtx, conn, status)
+	})
+	return err
 }
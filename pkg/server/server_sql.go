@@ -72,6 +72,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/spanconfig/spanconfigsqlwatcher"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/auditlogging"
+	"github.com/cockroachdb/cockroach/pkg/sql/cachewarmup"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catsessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
@@ -183,6 +184,10 @@ type SQLServer struct {
 	spanconfigSQLTranslatorFactory *spanconfigsqltranslator.Factory
 	spanconfigSQLWatcher           *spanconfigsqlwatcher.SQLWatcher
 	settingsWatcher                *settingswatcher.SettingsWatcher
+	// cacheWarmupStore persists the hot SQL cache entries across restarts. It
+	// is set by the KV server once the SQL server is instantiated, and is nil
+	// if the SQL server does not run on a KV node.
+	cacheWarmupStore *cachewarmup.Store
 
 	systemConfigWatcher *systemconfigwatcher.Cache
 
@@ -253,6 +258,10 @@ type sqlServerOptionalKVArgs struct {
 	// node.
 	kvStoresIterator kvserverbase.StoresIterator
 
+	// applyRecoveryPlanFunc is used by crdb_internal builtins to apply staged
+	// loss of quorum recovery plans online.
+	applyRecoveryPlanFunc eval.ApplyRecoveryPlanFunc
+
 	// inspectzServer is used to power various crdb_internal vtables, exposing
 	// the equivalent of /inspectz but through SQL.
 	inspectzServer inspectzpb.InspectzServer
@@ -687,6 +696,15 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 
 	rootSQLMemoryMonitor := cfg.monitorAndMetrics.rootSQLMemoryMonitor
 
+	// Set up memory-based admission control for SQL statements, which
+	// accounts for the aggregate memory usage on the node, unlike the
+	// per-query memory budgets enforced by the memory monitors.
+	sqlMemoryAdmissionCoord := admission.NewSQLMemoryGrantCoordinator(
+		cfg.AmbientCtx, cfg.Settings, cfg.registry)
+	if err := sqlMemoryAdmissionCoord.Start(ctx, cfg.stopper); err != nil {
+		return nil, err
+	}
+
 	// bulkMemoryMonitor is the parent to all child SQL monitors tracking bulk
 	// operations (IMPORT, index backfill). It is itself a child of the
 	// ParentMemoryMonitor.
@@ -735,6 +753,8 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 
 	distSQLMetrics := execinfra.MakeDistSQLMetrics(cfg.HistogramWindowInterval())
 	cfg.registry.AddMetricStruct(distSQLMetrics)
+	auditLogExternalSink := auditlogging.NewExternalSink(cfg.Settings, cfg.externalStorageFromURI)
+	cfg.registry.AddMetricStruct(auditLogExternalSink.Metrics())
 	rowMetrics := sql.NewRowMetrics(false /* internal */)
 	cfg.registry.AddMetricStruct(rowMetrics)
 	internalRowMetrics := sql.NewRowMetrics(true /* internal */)
@@ -971,15 +991,19 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		AuditConfig: &auditlogging.AuditConfigLock{
 			Config: auditlogging.EmptyAuditConfig(),
 		},
+		AuditLogExternalSink:        auditLogExternalSink,
 		RootMemoryMonitor:           rootSQLMemoryMonitor,
+		SQLMemoryAdmissionQ:         sqlMemoryAdmissionCoord.SQLMemoryWorkQueue,
 		TestingKnobs:                sqlExecutorTestingKnobs,
 		CompactEngineSpanFunc:       storageEngineClient.CompactEngineSpan,
+		RewriteInactiveKeyFilesFunc: rewriteInactiveKeyFilesFunc(cfg.nodesStatusServer),
 		CompactionConcurrencyFunc:   storageEngineClient.SetCompactionConcurrency,
 		GetTableMetricsFunc:         storageEngineClient.GetTableMetrics,
 		ScanStorageInternalKeysFunc: storageEngineClient.ScanStorageInternalKeys,
 		TraceCollector:              traceCollector,
 		TenantUsageServer:           cfg.tenantUsageServer,
 		KVStoresIterator:            cfg.kvStoresIterator,
+		ApplyRecoveryPlanFunc:       cfg.applyRecoveryPlanFunc,
 		InspectzServer:              cfg.inspectzServer,
 		RangeDescIteratorFactory:    cfg.rangeDescIteratorFactory,
 		SyntheticPrivilegeCache: syntheticprivilegecache.New(
@@ -1048,6 +1072,15 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 	} else {
 		execCfg.TypeSchemaChangerTestingKnobs = new(sql.TypeSchemaChangerTestingKnobs)
 	}
+	if cfg.tenantConnect != nil {
+		execCfg.MaxSQLConnectionsPerInstanceFunc = func() int64 {
+			entry, _ := cfg.tenantConnect.TenantInfo()
+			if !entry.Ready() {
+				return 0
+			}
+			return tenantcapabilities.MustGetInt64ByID(entry.TenantCapabilities, tenantcapabilities.MaxSQLConnectionsPerInstance)
+		}
+	}
 	execCfg.SchemaChangerMetrics = sql.NewSchemaChangerMetrics()
 	cfg.registry.AddMetricStruct(execCfg.SchemaChangerMetrics)
 
@@ -1255,6 +1288,7 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		)
 		execCfg.UpgradeJobDeps = upgradeMgr
 		execCfg.VersionUpgradeHook = upgradeMgr.Migrate
+		execCfg.UpgradeDryRunFunc = upgradeMgr.DryRun
 		execCfg.UpgradeTestingKnobs = knobs
 	}
 
@@ -1282,6 +1316,7 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		cfg.Settings,
 		spanConfigKnobs,
 	)
+	cfg.registry.AddMetricStruct(spanConfigReconciler.Metrics())
 	spanConfig.manager = spanconfigmanager.New(
 		cfg.internalDB,
 		jobRegistry,
@@ -1483,6 +1518,10 @@ func (s *SQLServer) preStart(
 
 	s.execCfg.ContentionRegistry.Start(ctx, stopper)
 
+	if err := s.execCfg.AuditLogExternalSink.Start(ctx, stopper); err != nil {
+		return err
+	}
+
 	// Start the sql liveness subsystem. We'll need it to get a session.
 	s.sqlLivenessProvider.Start(ctx, regionPhysicalRep)
 
@@ -1685,6 +1724,7 @@ func (s *SQLServer) preStart(
 	if err := s.execCfg.TableStatsCache.Start(ctx, s.execCfg.Codec, s.execCfg.RangeFeedFactory); err != nil {
 		return err
 	}
+	s.startCacheWarmup(ctx, stopper)
 
 	scheduledlogging.Start(
 		ctx, stopper, s.execCfg.InternalDB, s.execCfg.Settings,
@@ -40,8 +40,10 @@ type SQLStatusServer interface {
 	UserSQLRoles(context.Context, *UserSQLRolesRequest) (*UserSQLRolesResponse, error)
 	TxnIDResolution(context.Context, *TxnIDResolutionRequest) (*TxnIDResolutionResponse, error)
 	TransactionContentionEvents(context.Context, *TransactionContentionEventsRequest) (*TransactionContentionEventsResponse, error)
+	TxnWaitForGraph(context.Context, *TxnWaitForGraphRequest) (*TxnWaitForGraphResponse, error)
 	NodesList(context.Context, *NodesListRequest) (*NodesListResponse, error)
 	ListExecutionInsights(context.Context, *ListExecutionInsightsRequest) (*ListExecutionInsightsResponse, error)
+	RangeCosts(context.Context, *RangeCostsRequest) (*RangeCostsResponse, error)
 	LogFilesList(context.Context, *LogFilesListRequest) (*LogFilesListResponse, error)
 	LogFile(context.Context, *LogFileRequest) (*LogEntriesResponse, error)
 	Logs(context.Context, *LogsRequest) (*LogEntriesResponse, error)
@@ -69,10 +71,13 @@ func MakeOptionalNodesStatusServer(s NodesStatusServer) OptionalNodesStatusServe
 }
 
 // NodesStatusServer is an endpoint that allows the SQL subsystem
-// to observe node descriptors.
+// to observe node descriptors and the ranges and stores they host.
 // It is unavailable to tenants.
 type NodesStatusServer interface {
 	ListNodesInternal(context.Context, *NodesRequest) (*NodesResponse, error)
+	Ranges(context.Context, *RangesRequest) (*RangesResponse, error)
+	Stores(context.Context, *StoresRequest) (*StoresResponse, error)
+	RewriteInactiveKeyFiles(context.Context, *RewriteInactiveKeyFilesRequest) (*RewriteInactiveKeyFilesResponse, error)
 }
 
 // TenantStatusServer is the subset of the serverpb.StatusServer that is
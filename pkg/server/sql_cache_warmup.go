@@ -0,0 +1,60 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/cachewarmup"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// maybePersistCacheWarmup persists the currently hot descriptors and table
+// statistics so that they can be pre-warmed by startCacheWarmup after a
+// restart. Failures are logged and otherwise ignored.
+func (s *SQLServer) maybePersistCacheWarmup(ctx context.Context) {
+	if s.cacheWarmupStore == nil || !cachewarmup.Enabled.Get(&s.execCfg.Settings.SV) {
+		return
+	}
+	limit := int(cachewarmup.MaxEntries.Get(&s.execCfg.Settings.SV))
+	snap := cachewarmup.Collect(s.leaseMgr, s.execCfg.TableStatsCache, limit)
+	if err := s.cacheWarmupStore.Save(snap); err != nil {
+		log.Warningf(ctx, "failed to persist SQL cache warm-up state: %v", err)
+		return
+	}
+	log.Infof(ctx, "persisted SQL cache warm-up state for %d descriptors and %d tables with statistics",
+		len(snap.DescriptorIDs), len(snap.StatsTableIDs))
+}
+
+// startCacheWarmup asynchronously pre-warms the descriptor lease and table
+// statistics caches using the state persisted by the last drain, if any.
+func (s *SQLServer) startCacheWarmup(ctx context.Context, stopper *stop.Stopper) {
+	if s.cacheWarmupStore == nil || !cachewarmup.Enabled.Get(&s.execCfg.Settings.SV) {
+		return
+	}
+	snap, found, err := s.cacheWarmupStore.Load()
+	if err != nil {
+		log.Warningf(ctx, "failed to load SQL cache warm-up state: %v", err)
+		return
+	}
+	if !found || snap.Empty() {
+		return
+	}
+	limit := int(cachewarmup.MaxEntries.Get(&s.execCfg.Settings.SV))
+	ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+	if err := stopper.RunAsyncTask(ctx, "sql-cache-warmup", func(ctx context.Context) {
+		defer cancel()
+		cachewarmup.Warm(ctx, s.execCfg.Clock, s.leaseMgr, s.execCfg.TableStatsCache, snap, limit)
+	}); err != nil {
+		cancel()
+	}
+}
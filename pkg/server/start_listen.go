@@ -17,6 +17,7 @@ import (
 	"sync"
 
 	"github.com/cockroachdb/cmux"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -35,6 +36,9 @@ type RPCListenerFactory func(ctx context.Context, addr, advertiseAddr *string, c
 //   - A function that starts the RPC server, when the cluster is known to have
 //     bootstrapped or when waiting for init().
 //
+// If serveRaft is set, the connections of the framed raft transport are
+// passed to it by the RPC server.
+//
 // This does not start *accepting* connections just yet.
 func startListenRPCAndSQL(
 	ctx, workersCtx context.Context,
@@ -43,6 +47,7 @@ func startListenRPCAndSQL(
 	grpc *grpcServer,
 	rpcListenerFactory RPCListenerFactory,
 	enableSQLListener bool,
+	serveRaft func(ctx context.Context, ln net.Listener),
 ) (
 	sqlListener net.Listener,
 	pgLoopbackListener *netutil.LoopbackListener,
@@ -130,6 +135,13 @@ func startListenRPCAndSQL(
 		}
 	}
 
+	var raftL net.Listener
+	if serveRaft != nil {
+		// The framed raft transport shares the RPC port. Its connections must
+		// be matched before gRPC's, which match anything.
+		raftL = m.Match(kvserver.MatchFramedRaftTransport)
+	}
+
 	anyL := m.Match(cmux.Any())
 	if serverTestKnobs, ok := cfg.TestingKnobs.Server.(*TestingKnobs); ok {
 		if serverTestKnobs.ContextTestingKnobs.InjectedLatencyOracle != nil {
@@ -145,6 +157,9 @@ func startListenRPCAndSQL(
 		<-stopper.ShouldQuiesce()
 		// TODO(bdarnell): Do we need to also close the other listeners?
 		netutil.FatalIfUnexpected(anyL.Close())
+		if raftL != nil {
+			netutil.FatalIfUnexpected(raftL.Close())
+		}
 		netutil.FatalIfUnexpected(rpcLoopbackL.Close())
 		netutil.FatalIfUnexpected(sqlLoopbackL.Close())
 		netutil.FatalIfUnexpected(ln.Close())
@@ -181,6 +196,11 @@ func startListenRPCAndSQL(
 		_ = stopper.RunAsyncTask(workersCtx, "serve-loopback-grpc", func(context.Context) {
 			netutil.FatalIfUnexpected(grpc.Serve(rpcLoopbackL))
 		})
+		if raftL != nil {
+			_ = stopper.RunAsyncTask(workersCtx, "serve-framed-raft", func(ctx context.Context) {
+				serveRaft(ctx, raftL)
+			})
+		}
 
 		_ = stopper.RunAsyncTask(ctx, "serve-mux", func(context.Context) {
 			serveOnMux.Do(func() {
@@ -24,6 +24,7 @@ import (
 	"os/exec"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -46,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/mtinfopb"
 	raft "github.com/cockroachdb/cockroach/pkg/raft"
+	"github.com/cockroachdb/cockroach/pkg/raft/tracker"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/security"
@@ -2343,6 +2345,41 @@ func (h varsHandler) handleVars(w http.ResponseWriter, r *http.Request) {
 	telemetry.Inc(telemetryPrometheusVars)
 }
 
+// Reasons for which replication to a follower may be paused, as reported in
+// serverpb.RaftState_Progress.PauseReasons.
+const (
+	// raftPauseReasonSnapshot indicates that the follower is waiting for a
+	// snapshot.
+	raftPauseReasonSnapshot = "snapshot"
+	// raftPauseReasonProbe indicates that the leader is waiting for the
+	// follower to respond to a probe.
+	raftPauseReasonProbe = "probe"
+	// raftPauseReasonInflightLimit indicates that the limit of unacknowledged
+	// MsgApps sent to the follower has been reached.
+	raftPauseReasonInflightLimit = "inflight_limit"
+	// raftPauseReasonIOOverload indicates that the follower's store is
+	// overloaded, and the leader has stopped sending it MsgApps.
+	raftPauseReasonIOOverload = "io_overload"
+)
+
+// raftProgressPauseReasons returns the reasons replication to a follower with
+// the given progress is paused, or nil if it isn't.
+func raftProgressPauseReasons(pr tracker.Progress, ioOverloaded bool) []string {
+	var reasons []string
+	switch {
+	case pr.State == tracker.StateSnapshot:
+		reasons = append(reasons, raftPauseReasonSnapshot)
+	case pr.State == tracker.StateProbe && pr.MsgAppFlowPaused:
+		reasons = append(reasons, raftPauseReasonProbe)
+	case pr.State == tracker.StateReplicate && pr.MsgAppFlowPaused:
+		reasons = append(reasons, raftPauseReasonInflightLimit)
+	}
+	if ioOverloaded {
+		reasons = append(reasons, raftPauseReasonIOOverload)
+	}
+	return reasons
+}
+
 // Ranges returns range info for the specified node.
 func (s *systemStatusServer) Ranges(
 	ctx context.Context, req *serverpb.RangesRequest,
@@ -2406,7 +2443,11 @@ func (s *systemStatusServer) rangesHelper(
 		Ranges: make([]serverpb.RangeInfo, 0, s.stores.GetStoreCount()),
 	}
 
-	convertRaftStatus := func(raftStatus *raft.Status) serverpb.RaftState {
+	convertRaftStatus := func(
+		raftStatus *raft.Status,
+		pausedReplicas []roachpb.ReplicaID,
+		catchUp map[roachpb.ReplicaID]kvserver.CatchUpEstimate,
+	) serverpb.RaftState {
 		if raftStatus == nil {
 			return serverpb.RaftState{
 				State: RaftStateDormant,
@@ -2424,13 +2465,28 @@ func (s *systemStatusServer) rangesHelper(
 		}
 
 		for id, progress := range raftStatus.Progress {
-			state.Progress[id] = serverpb.RaftState_Progress{
+			ioOverloaded := slices.Contains(pausedReplicas, roachpb.ReplicaID(id))
+			pr := serverpb.RaftState_Progress{
 				Match:           progress.Match,
 				Next:            progress.Next,
 				Paused:          progress.IsPaused(),
 				PendingSnapshot: progress.PendingSnapshot,
 				State:           progress.State.String(),
+				SentCommit:      progress.LastSentCommit(),
+				InflightCount:   uint64(progress.Inflights.Count()),
+				InflightBytes:   progress.Inflights.Bytes(),
+				RecentActive:    progress.RecentActive,
+				IsLearner:       progress.IsLearner,
+				PauseReasons:    raftProgressPauseReasons(progress, ioOverloaded),
 			}
+			if e, ok := catchUp[roachpb.ReplicaID(id)]; ok {
+				pr.CatchUpETAKnown = true
+				pr.CatchUpETA = e.ETA
+				pr.CatchUpRemainingEntries = e.RemainingEntries
+				pr.CatchUpRemainingBytes = e.RemainingBytes
+				pr.CatchUpEntriesPerSecond = e.EntriesPerSecond
+			}
+			state.Progress[id] = pr
 		}
 
 		return state
@@ -2439,14 +2495,14 @@ func (s *systemStatusServer) rangesHelper(
 	constructRangeInfo := func(
 		rep *kvserver.Replica, storeID roachpb.StoreID, metrics kvserver.ReplicaMetrics,
 	) serverpb.RangeInfo {
+		state := rep.State(ctx)
 		raftStatus := rep.RaftStatus()
-		raftState := convertRaftStatus(raftStatus)
+		raftState := convertRaftStatus(raftStatus, state.PausedReplicas, rep.FollowerCatchUpEstimates())
 		leaseHistory := rep.GetLeaseHistory()
 		var span serverpb.PrettySpan
 		desc := rep.Desc()
 		span.StartKey = desc.StartKey.String()
 		span.EndKey = desc.EndKey.String()
-		state := rep.State(ctx)
 		var topKLocksByWaiters []serverpb.RangeInfo_LockInfo
 		for _, lm := range metrics.LockTableMetrics.TopKLocksByWaiters {
 			if lm.Key == nil {
@@ -3896,8 +3952,18 @@ func (s *systemStatusServer) Stores(
 			TotalBytes:       envStats.TotalBytes,
 			ActiveKeyFiles:   envStats.ActiveKeyFiles,
 			ActiveKeyBytes:   envStats.ActiveKeyBytes,
+			ActiveKeyID:      envStats.ActiveKeyID,
 			Dir:              props.Dir,
 		}
+		for _, ks := range envStats.KeyStats {
+			storeDetails.KeyStats = append(storeDetails.KeyStats, serverpb.EncryptionKeyStats{
+				KeyID: ks.KeyID,
+				Files: ks.Files,
+				Bytes: ks.Bytes,
+			})
+		}
+		storeDetails.InactiveKeyRewriteRunning, storeDetails.InactiveKeyRewriteBytes =
+			store.InactiveKeyRewriteStatus()
 		if props.WalFailoverPath != nil {
 			storeDetails.WalFailoverPath = *props.WalFailoverPath
 		}
@@ -90,7 +90,8 @@ type storeMetrics interface {
 // ChildMetricsEnabled enables exporting of additional prometheus time series with extra labels
 var ChildMetricsEnabled = settings.RegisterBoolSetting(
 	settings.ApplicationLevel, "server.child_metrics.enabled",
-	"enables the exporting of child metrics, additional prometheus time series with extra labels",
+	"enables the exporting of child metrics, additional prometheus time series with extra labels, "+
+		"and their recording as labeled series in the internal time series database",
 	false,
 	settings.WithPublic)
 
@@ -395,13 +396,16 @@ func (mr *MetricsRecorder) GetTimeSeriesData() []tspb.TimeSeriesData {
 	lastDataCount := atomic.LoadInt64(&mr.lastDataCount)
 	data := make([]tspb.TimeSeriesData, 0, lastDataCount)
 
-	// Record time series from node-level registries.
+	// Record time series from node-level registries. If child metrics are
+	// enabled, they are recorded as labeled time series.
 	now := mr.clock.Now()
+	includeChildMetrics := ChildMetricsEnabled.Get(&mr.settings.SV)
 	recorder := registryRecorder{
-		registry:       mr.mu.nodeRegistry,
-		format:         nodeTimeSeriesPrefix,
-		source:         mr.mu.desc.NodeID.String(),
-		timestampNanos: now.UnixNano(),
+		registry:            mr.mu.nodeRegistry,
+		format:              nodeTimeSeriesPrefix,
+		source:              mr.mu.desc.NodeID.String(),
+		timestampNanos:      now.UnixNano(),
+		includeChildMetrics: includeChildMetrics,
 	}
 	recorder.record(&data)
 	// Now record the app metrics for the system tenant.
@@ -417,10 +421,11 @@ func (mr *MetricsRecorder) GetTimeSeriesData() []tspb.TimeSeriesData {
 	// Record time series from app-level registries for secondary tenants.
 	for tenantID, r := range mr.mu.tenantRegistries {
 		tenantRecorder := registryRecorder{
-			registry:       r,
-			format:         nodeTimeSeriesPrefix,
-			source:         tsutil.MakeTenantSource(mr.mu.desc.NodeID.String(), tenantID.String()),
-			timestampNanos: now.UnixNano(),
+			registry:            r,
+			format:              nodeTimeSeriesPrefix,
+			source:              tsutil.MakeTenantSource(mr.mu.desc.NodeID.String(), tenantID.String()),
+			timestampNanos:      now.UnixNano(),
+			includeChildMetrics: includeChildMetrics,
 		}
 		tenantRecorder.record(&data)
 	}
@@ -683,6 +688,9 @@ type registryRecorder struct {
 	format         string
 	source         string
 	timestampNanos int64
+	// includeChildMetrics, if set, causes record to also record the child
+	// metrics of the registry's metrics as labeled time series.
+	includeChildMetrics bool
 }
 
 // extractValue extracts the metric value(s) for the given metric and passes it, along with the metric name, to the
@@ -750,6 +758,50 @@ func (rr registryRecorder) record(dest *[]tspb.TimeSeriesData) {
 			},
 		})
 	})
+	if rr.includeChildMetrics {
+		rr.recordChildrenAsLabeledSeries(dest)
+	}
+}
+
+// recordChildrenAsLabeledSeries records the child metrics of the Counter and
+// Gauge metrics in the registry as time series labeled with the labels of
+// each child. The number of label sets stored for each metric is bounded by
+// the time series database; see ts.MaxLabelCardinality.
+func (rr registryRecorder) recordChildrenAsLabeledSeries(dest *[]tspb.TimeSeriesData) {
+	rr.registry.Each(func(name string, v interface{}) {
+		promIter, ok := v.(metric.PrometheusIterable)
+		if !ok {
+			return
+		}
+		promIter.Each(nil /* labels */, func(m *prometheusgo.Metric) {
+			if len(m.Label) == 0 {
+				return
+			}
+			var value float64
+			if m.Gauge != nil {
+				value = *m.Gauge.Value
+			} else if m.Counter != nil {
+				value = *m.Counter.Value
+			} else {
+				return
+			}
+			labels := make([]tspb.Label, len(m.Label))
+			for i, l := range m.Label {
+				labels[i] = tspb.Label{Name: l.GetName(), Value: l.GetValue()}
+			}
+			*dest = append(*dest, tspb.TimeSeriesData{
+				Name:   fmt.Sprintf(rr.format, name),
+				Source: rr.source,
+				Labels: labels,
+				Datapoints: []tspb.TimeSeriesDatapoint{
+					{
+						TimestampNanos: rr.timestampNanos,
+						Value:          value,
+					},
+				},
+			})
+		})
+	})
 }
 
 // recordChild filters the metrics in the registry down to those provided in
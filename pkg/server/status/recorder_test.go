@@ -407,6 +407,54 @@ func TestRegistryRecorder_RecordChild(t *testing.T) {
 	}
 }
 
+// TestRegistryRecorder_RecordChildrenAsLabeledSeries verifies that child
+// metrics are recorded as labeled time series when requested.
+func TestRegistryRecorder_RecordChildrenAsLabeledSeries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	reg := metric.NewRegistry()
+	ac := aggmetric.NewCounter(metric.Metadata{Name: "testAggCounter"}, "database", "application_name")
+	reg.AddMetric(ac)
+	ac.AddChild("db1", "app").Inc(3)
+	ac.AddChild("db2", "app").Inc(4)
+	reg.AddMetric(metric.NewGauge(metric.Metadata{Name: "testGauge"}))
+
+	rr := registryRecorder{
+		registry:       reg,
+		format:         nodeTimeSeriesPrefix,
+		source:         "1",
+		timestampNanos: 100,
+	}
+	series := func(value float64, labels ...tspb.Label) tspb.TimeSeriesData {
+		return tspb.TimeSeriesData{
+			Name:       "cr.node.testAggCounter",
+			Source:     "1",
+			Labels:     labels,
+			Datapoints: []tspb.TimeSeriesDatapoint{{TimestampNanos: 100, Value: value}},
+		}
+	}
+	gauge := tspb.TimeSeriesData{
+		Name:       "cr.node.testGauge",
+		Source:     "1",
+		Datapoints: []tspb.TimeSeriesDatapoint{{TimestampNanos: 100, Value: 0}},
+	}
+
+	var actual []tspb.TimeSeriesData
+	rr.record(&actual)
+	sort.Sort(byTimeAndName(actual))
+	require.Equal(t, []tspb.TimeSeriesData{series(7), gauge}, actual)
+
+	actual = nil
+	rr.includeChildMetrics = true
+	rr.record(&actual)
+	sort.Stable(byTimeAndName(actual))
+	require.Equal(t, []tspb.TimeSeriesData{
+		series(7),
+		series(3, tspb.Label{Name: "database", Value: "db1"}, tspb.Label{Name: "application_name", Value: "app"}),
+		series(4, tspb.Label{Name: "database", Value: "db2"}, tspb.Label{Name: "application_name", Value: "app"}),
+		gauge,
+	}, actual)
+}
+
 // TestMetricsRecorder verifies that the metrics recorder properly formats the
 // statistics from various registries, both for Time Series and for Status
 // Summaries.
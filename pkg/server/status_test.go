@@ -17,6 +17,7 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/raft/tracker"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
@@ -415,3 +416,44 @@ func TestGossipUnredacted(t *testing.T) {
 		require.NotEqual(t, redactedMarker, res.Server.ConnStatus[i].Address)
 	}
 }
+
+func TestRaftProgressPauseReasons(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		name         string
+		pr           tracker.Progress
+		ioOverloaded bool
+		exp          []string
+	}{
+		{
+			name: "replicating",
+			pr:   tracker.Progress{State: tracker.StateReplicate},
+		},
+		{
+			name: "inflight limit",
+			pr:   tracker.Progress{State: tracker.StateReplicate, MsgAppFlowPaused: true},
+			exp:  []string{raftPauseReasonInflightLimit},
+		},
+		{
+			name: "probing",
+			pr:   tracker.Progress{State: tracker.StateProbe},
+		},
+		{
+			name: "awaiting probe response",
+			pr:   tracker.Progress{State: tracker.StateProbe, MsgAppFlowPaused: true},
+			exp:  []string{raftPauseReasonProbe},
+		},
+		{
+			name:         "snapshot and io overload",
+			pr:           tracker.Progress{State: tracker.StateSnapshot},
+			ioOverloaded: true,
+			exp:          []string{raftPauseReasonSnapshot, raftPauseReasonIOOverload},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, raftProgressPauseReasons(tc.pr, tc.ioOverloaded))
+		})
+	}
+}
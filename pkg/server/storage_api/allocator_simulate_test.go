@@ -0,0 +1,118 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage_api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAllocatorSimulate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartCluster(t, 3, base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{
+			DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+		},
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	// The scratch range has a single replica, on n1. Simulate raising its
+	// replication factor to 3.
+	scratchKey := tc.ScratchRange(t)
+	rangeDesc := tc.LookupRangeOrFatal(t, scratchKey)
+	req := &serverpb.AllocatorSimulateRequest{
+		Span:             roachpb.Span{Key: scratchKey, EndKey: scratchKey.Next()},
+		ZoneConfig:       zonepb.ZoneConfig{NumReplicas: proto.Int32(3)},
+		ZoneConfigFields: []string{"num_replicas"},
+		NumRangesReport:  10,
+	}
+
+	adminClient := tc.Server(0).GetAdminClient(t)
+	var resp *serverpb.AllocatorSimulateResponse
+	testutils.SucceedsSoon(t, func() error {
+		var err error
+		resp, err = adminClient.AllocatorSimulate(ctx, req)
+		if err != nil {
+			return err
+		}
+		// The stores of the other nodes may not have been gossiped yet.
+		if len(resp.Stores) != 3 {
+			return errors.Newf("expected 3 stores, found %d", len(resp.Stores))
+		}
+		if resp.NumErrors != 0 {
+			return errors.Newf("expected no errors, found %+v", resp.Ranges)
+		}
+		return nil
+	})
+
+	require.Equal(t, int64(1), resp.RangesChecked)
+	require.False(t, resp.Truncated)
+	require.Equal(t, int64(1), resp.RangesChanged)
+	require.Len(t, resp.Ranges, 1)
+	result := resp.Ranges[0]
+	require.Equal(t, rangeDesc.RangeID, result.RangeID)
+	require.Empty(t, result.Error)
+	require.Len(t, result.FinalReplicas, 3)
+	var additions int
+	for _, change := range result.Changes {
+		for _, chg := range change.Changes {
+			require.Equal(t, roachpb.ADD_VOTER, chg.ChangeType)
+			require.NotEqual(t, tc.Target(0).StoreID, chg.Target.StoreID)
+			additions++
+		}
+	}
+	require.Equal(t, 2, additions)
+	var actions int64
+	for _, count := range resp.ActionCounts {
+		actions += count
+	}
+	require.Equal(t, int64(len(result.Changes)), actions)
+
+	var replicasAdded int64
+	for _, store := range resp.Stores {
+		require.Zero(t, store.ReplicasRemoved)
+		require.Equal(t, store.ReplicaCount+store.ReplicasAdded, store.FinalReplicaCount)
+		replicasAdded += store.ReplicasAdded
+	}
+	require.Equal(t, int64(2), replicasAdded)
+	require.Equal(t, 2*result.LogicalBytes, resp.TotalBytes)
+	require.Positive(t, resp.SnapshotRate)
+
+	// The range itself is unchanged.
+	require.Len(t, tc.LookupRangeOrFatal(t, scratchKey).InternalReplicas, 1)
+
+	// Invalid changes are rejected.
+	req.ZoneConfig.NumReplicas = proto.Int32(0)
+	_, err := adminClient.AllocatorSimulate(ctx, req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	req.ZoneConfig.NumReplicas = proto.Int32(3)
+	req.ZoneConfigFields = []string{"gc.ttlseconds"}
+	_, err = adminClient.AllocatorSimulate(ctx, req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -653,6 +653,78 @@ func TestDecommissionPreCheckInvalidNode(t *testing.T) {
 	}, resp.CheckedNodes[1])
 }
 
+// TestDecommissionAnalyze tests the DecommissionAnalyze endpoint, which
+// simulates the decommission of nodes without changing their membership.
+func TestDecommissionAnalyze(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartCluster(t, 5, base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{
+			DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+		},
+		ReplicationMode: base.ReplicationManual, // saves time
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	// Place the scratch range on n1, n4 and n5, and analyze the decommission of
+	// n4 and n5, which have no other replicas.
+	decommissioningSrvIdxs := []int{3, 4}
+	decommissioningSrvNodeIDs := make([]roachpb.NodeID, len(decommissioningSrvIdxs))
+	for i, srvIdx := range decommissioningSrvIdxs {
+		decommissioningSrvNodeIDs[i] = tc.Server(srvIdx).NodeID()
+	}
+	rangeDesc := tc.LookupRangeOrFatal(t, tc.ScratchRange(t))
+	tc.AddVotersOrFatal(t, rangeDesc.StartKey.AsRawKey(), tc.Target(3), tc.Target(4))
+
+	adminClient := tc.Server(0).GetAdminClient(t)
+	var resp *serverpb.DecommissionAnalyzeResponse
+	testutils.SucceedsSoon(t, func() error {
+		var err error
+		resp, err = adminClient.DecommissionAnalyze(ctx, &serverpb.DecommissionAnalyzeRequest{
+			NodeIDs:             decommissioningSrvNodeIDs,
+			NumViolationsReport: 10,
+		})
+		if err != nil {
+			return err
+		}
+		// The stores of the other nodes may not have been gossiped yet.
+		if resp.ReceivingStores != 3 {
+			return errors.Newf("expected 3 receiving stores, found %d", resp.ReceivingStores)
+		}
+		return nil
+	})
+
+	require.Len(t, resp.Nodes, len(decommissioningSrvNodeIDs))
+	var totalBytes int64
+	for i, nID := range decommissioningSrvNodeIDs {
+		require.Equal(t, nID, resp.Nodes[i].NodeID)
+		require.Equal(t, serverpb.DecommissionPreCheckResponse_READY, resp.Nodes[i].DecommissionReadiness)
+		require.Equal(t, int64(1), resp.Nodes[i].ReplicaCount)
+		totalBytes += resp.Nodes[i].LogicalBytes
+	}
+	require.Equal(t, totalBytes, resp.TotalBytes)
+	require.Equal(t, int64(1), resp.RangesChecked)
+	var actions int64
+	for _, count := range resp.ActionCounts {
+		actions += count
+	}
+	require.Equal(t, int64(1), actions)
+	require.Zero(t, resp.NumViolations)
+	require.Empty(t, resp.Violations)
+	require.Positive(t, resp.SnapshotRate)
+
+	// The analyzed nodes are not decommissioning.
+	statusResp, err := adminClient.DecommissionStatus(ctx, &serverpb.DecommissionStatusRequest{
+		NodeIDs: decommissioningSrvNodeIDs,
+	})
+	require.NoError(t, err)
+	for _, status := range statusResp.Status {
+		require.True(t, status.Membership.Active())
+	}
+}
+
 func TestDecommissionSelf(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -143,6 +143,10 @@ type SQLServerWrapper struct {
 	// Used for multi-tenant cost control (on the tenant side).
 	costController multitenant.TenantSideCostController
 
+	// cpuSecondsFn returns the CPU usage of the tenant, in seconds. It is used
+	// for resource usage accounting.
+	cpuSecondsFn func(context.Context) float64
+
 	// promRuleExporter is used by the tenant to expose the prometheus rules.
 	promRuleExporter *metric.PrometheusRuleExporter
 
@@ -181,6 +185,10 @@ type tenantServerDeps struct {
 	// capabilities.
 	costControllerFactory costControllerFactory
 	spanLimiterFactory    spanLimiterFactory
+
+	// cpuSampler, if set, attributes the CPU usage of the process to the
+	// virtual clusters sharing it. Only used in shared-process mode.
+	cpuSampler *multitenantcpu.TenantCPUSampler
 }
 
 type spanLimiterFactory func(isql.Executor, *cluster.Settings, *spanconfig.TestingKnobs) spanconfig.Limiter
@@ -229,6 +237,7 @@ func newSharedProcessTenantServer(
 	baseCfg BaseConfig,
 	sqlCfg SQLConfig,
 	tenantNameContainer *roachpb.TenantNameContainer,
+	cpuSampler *multitenantcpu.TenantCPUSampler,
 ) (*SQLServerWrapper, error) {
 	if baseCfg.IDContainer.Get() == 0 {
 		return nil, errors.AssertionFailedf("programming error: NewSharedProcessTenantServer called before NodeID was assigned.")
@@ -251,6 +260,7 @@ func newSharedProcessTenantServer(
 		spanLimiterFactory: func(isql.Executor, *cluster.Settings, *spanconfig.TestingKnobs) spanconfig.Limiter {
 			return spanconfiglimiter.NoopLimiter{}
 		},
+		cpuSampler: cpuSampler,
 	}
 	return newTenantServer(ctx, stopper, baseCfg, sqlCfg, tenantNameContainer, deps, mtinfopb.ServiceModeShared)
 }
@@ -484,6 +494,21 @@ func newTenantServer(
 		processCapAuthz,
 	)
 
+	// In shared-process mode, the process CPU is shared with the other
+	// virtual clusters, so only the share attributed to this one is
+	// accounted for. The cost controller is a no-op in that mode, so the
+	// attributed CPU is also reported through its own metric.
+	cpuSecondsFn := multitenantcpu.GetCPUSeconds
+	if cpuSampler := deps.cpuSampler; cpuSampler != nil {
+		tenantName := tenantNameContainer.Get()
+		cpuCounter := multitenantcpu.NewSharedProcessCPUSecondsCounter()
+		args.registry.AddMetric(cpuCounter)
+		stopper.AddCloser(stop.CloserFn(cpuSampler.Register(tenantName, cpuCounter)))
+		cpuSecondsFn = func(ctx context.Context) float64 {
+			return cpuSampler.CPUSeconds(ctx, tenantName)
+		}
+	}
+
 	return &SQLServerWrapper{
 		cfg: args.BaseConfig,
 
@@ -516,6 +541,7 @@ func newTenantServer(
 
 		externalStorageBuilder: args.externalStorageBuilder,
 		costController:         args.costController,
+		cpuSecondsFn:           cpuSecondsFn,
 		promRuleExporter:       args.promRuleExporter,
 		tenantTimeSeries:       args.tenantTimeSeriesServer,
 	}, nil
@@ -578,7 +604,7 @@ func (s *SQLServerWrapper) PreStart(ctx context.Context) error {
 		lf = s.sqlServer.cfg.RPCListenerFactory
 	}
 
-	pgL, loopbackPgL, rpcLoopbackDialFn, startRPCServer, err := startListenRPCAndSQL(ctx, workersCtx, *s.sqlServer.cfg, s.stopper, s.grpc, lf, enableSQLListener)
+	pgL, loopbackPgL, rpcLoopbackDialFn, startRPCServer, err := startListenRPCAndSQL(ctx, workersCtx, *s.sqlServer.cfg, s.stopper, s.grpc, lf, enableSQLListener, nil /* serveRaft */)
 	if err != nil {
 		return err
 	}
@@ -891,7 +917,7 @@ func (s *SQLServerWrapper) PreStart(ctx context.Context) error {
 	// resource usage accounting in costController.Start below.
 	externalUsageFn := func(ctx context.Context) multitenant.ExternalUsage {
 		return multitenant.ExternalUsage{
-			CPUSecs:           multitenantcpu.GetCPUSeconds(ctx),
+			CPUSecs:           s.cpuSecondsFn(ctx),
 			PGWireEgressBytes: s.sqlServer.pgServer.BytesOut(),
 		}
 	}
@@ -1466,6 +1492,18 @@ func (noopTenantSideCostController) OnResponseWait(
 	return nil
 }
 
+func (noopTenantSideCostController) IsThrottled(ctx context.Context) bool {
+	return false
+}
+
+func (noopTenantSideCostController) CheckLiveBytesLimit(ctx context.Context) error {
+	return nil
+}
+
+func (noopTenantSideCostController) PreferredBatchBytes() int64 {
+	return 0
+}
+
 func (noopTenantSideCostController) OnExternalIOWait(
 	ctx context.Context, usage multitenant.ExternalIOUsage,
 ) error {
@@ -1485,6 +1523,26 @@ func (noopTenantSideCostController) GetCostConfig() *tenantcostmodel.Config {
 	return nil
 }
 
+func (noopTenantSideCostController) GetHostConsumptionHistory() []multitenant.HostConsumptionSnapshot {
+	return nil
+}
+
+func (noopTenantSideCostController) GetCostHistory() []multitenant.CostSample {
+	return nil
+}
+
+func (noopTenantSideCostController) GetIndexReadUsage(limit int) []multitenant.IndexReadUsage {
+	return nil
+}
+
+func (noopTenantSideCostController) GetRangeCosts() []multitenant.RangeCost {
+	return nil
+}
+
 func (noopTenantSideCostController) Metrics() metric.Struct {
 	return emptyMetricStruct{}
 }
+
+func (noopTenantSideCostController) OnBackgroundWorkWait(ctx context.Context) error {
+	return nil
+}
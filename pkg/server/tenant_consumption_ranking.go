@@ -0,0 +1,209 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultTenantConsumptionWindow = time.Hour
+	defaultTenantConsumptionLimit  = 10
+)
+
+// TenantConsumptionRanking implements the serverpb.StatusServer interface.
+func (s *systemStatusServer) TenantConsumptionRanking(
+	ctx context.Context, req *serverpb.TenantConsumptionRankingRequest,
+) (*serverpb.TenantConsumptionRankingResponse, error) {
+	ctx = authserver.ForwardSQLIdentityThroughRPCCalls(ctx)
+	ctx = s.AnnotateCtx(ctx)
+	if err := s.privilegeChecker.RequireViewClusterMetadataPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	window := req.Window
+	if window == 0 {
+		window = defaultTenantConsumptionWindow
+	}
+	if window < 0 || window > multitenant.ConsumptionRollupRetention {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"window must be positive and at most %s", multitenant.ConsumptionRollupRetention)
+	}
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultTenantConsumptionLimit
+	}
+	if limit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be positive")
+	}
+
+	resp := &serverpb.TenantConsumptionRankingResponse{
+		ErrorsByNodeID: make(map[roachpb.NodeID]string),
+	}
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if !local {
+			client, err := s.dialNode(ctx, requestedNodeID)
+			if err != nil {
+				return nil, srverrors.ServerError(ctx, err)
+			}
+			return client.TenantConsumptionRanking(ctx, req)
+		}
+		for _, summary := range s.node.tenantUsage.GetConsumptionSummaries(window) {
+			resp.Tenants = append(resp.Tenants, makeTenantConsumptionSummary(summary))
+		}
+		rankTenantConsumption(resp, resp.Tenants, limit)
+		return resp, nil
+	}
+
+	// Combine the rollups recorded by all nodes. Each node records the
+	// consumption of the tenants whose token bucket requests it served.
+	byTenant := make(map[uint64]*serverpb.TenantConsumptionSummary)
+	remoteRequest := serverpb.TenantConsumptionRankingRequest{
+		NodeID: "local",
+		Window: window,
+		Limit:  req.Limit,
+	}
+	nodeFn := func(
+		ctx context.Context, client serverpb.StatusClient, _ roachpb.NodeID,
+	) (*serverpb.TenantConsumptionRankingResponse, error) {
+		return client.TenantConsumptionRanking(ctx, &remoteRequest)
+	}
+	responseFn := func(_ roachpb.NodeID, nodeResp *serverpb.TenantConsumptionRankingResponse) {
+		for i := range nodeResp.Tenants {
+			summary := &nodeResp.Tenants[i]
+			if existing, ok := byTenant[summary.TenantID]; ok {
+				mergeTenantConsumptionSummaries(existing, summary)
+			} else {
+				byTenant[summary.TenantID] = summary
+			}
+		}
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		resp.ErrorsByNodeID[nodeID] = err.Error()
+	}
+	if err := iterateNodes(ctx, s.serverIterator, s.stopper, "tenant consumption",
+		noTimeout,
+		s.dialNode,
+		nodeFn,
+		responseFn,
+		errorFn,
+	); err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+
+	tenants := make([]serverpb.TenantConsumptionSummary, 0, len(byTenant))
+	for _, summary := range byTenant {
+		tenants = append(tenants, *summary)
+	}
+	rankTenantConsumption(resp, tenants, limit)
+	return resp, nil
+}
+
+func makeTenantConsumptionSummary(
+	summary multitenant.TenantConsumptionSummary,
+) serverpb.TenantConsumptionSummary {
+	res := serverpb.TenantConsumptionSummary{
+		TenantID:               summary.TenantID.ToUint64(),
+		Start:                  summary.Start.Time,
+		End:                    summary.End.Time,
+		StartRU:                summary.Start.RU,
+		EndRU:                  summary.End.RU,
+		StartSQLPodsCPUSeconds: summary.Start.SQLPodsCPUSeconds,
+		EndSQLPodsCPUSeconds:   summary.End.SQLPodsCPUSeconds,
+		LiveBytes:              summary.End.LiveBytes,
+	}
+	computeTenantConsumptionRates(&res)
+	return res
+}
+
+// mergeTenantConsumptionSummaries extends a summary with the rollups recorded
+// for the same tenant by another node. The rollups contain the cumulative
+// consumption of the tenant across all its SQL instances, so the oldest and
+// the most recent rollups can be combined regardless of the node that recorded
+// them.
+func mergeTenantConsumptionSummaries(
+	summary *serverpb.TenantConsumptionSummary, other *serverpb.TenantConsumptionSummary,
+) {
+	if other.Start.Before(summary.Start) {
+		summary.Start = other.Start
+		summary.StartRU = other.StartRU
+		summary.StartSQLPodsCPUSeconds = other.StartSQLPodsCPUSeconds
+	}
+	if other.End.After(summary.End) {
+		summary.End = other.End
+		summary.EndRU = other.EndRU
+		summary.EndSQLPodsCPUSeconds = other.EndSQLPodsCPUSeconds
+		summary.LiveBytes = other.LiveBytes
+	}
+	computeTenantConsumptionRates(summary)
+}
+
+func computeTenantConsumptionRates(summary *serverpb.TenantConsumptionSummary) {
+	summary.RURate, summary.CPURate = 0, 0
+	if elapsed := summary.End.Sub(summary.Start).Seconds(); elapsed > 0 {
+		summary.RURate = (summary.EndRU - summary.StartRU) / elapsed
+		summary.CPURate = (summary.EndSQLPodsCPUSeconds - summary.StartSQLPodsCPUSeconds) / elapsed
+	}
+}
+
+// rankTenantConsumption populates the rankings of the response with the top
+// tenants according to each criterion.
+func rankTenantConsumption(
+	resp *serverpb.TenantConsumptionRankingResponse,
+	tenants []serverpb.TenantConsumptionSummary,
+	limit int,
+) {
+	type summary = serverpb.TenantConsumptionSummary
+	resp.TopByRURate = topTenantsBy(tenants, limit, func(t *summary) float64 {
+		return t.RURate
+	})
+	resp.TopByCPURate = topTenantsBy(tenants, limit, func(t *summary) float64 {
+		return t.CPURate
+	})
+	resp.TopByLiveBytes = topTenantsBy(tenants, limit, func(t *summary) float64 {
+		return float64(t.LiveBytes)
+	})
+}
+
+// topTenantsBy returns the (at most) limit tenants with the highest value of
+// the given key, in decreasing order. Ties are broken by tenant ID.
+func topTenantsBy(
+	tenants []serverpb.TenantConsumptionSummary,
+	limit int,
+	key func(*serverpb.TenantConsumptionSummary) float64,
+) []serverpb.TenantConsumptionSummary {
+	sorted := append([]serverpb.TenantConsumptionSummary(nil), tenants...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := key(&sorted[i]), key(&sorted[j])
+		if a != b {
+			return a > b
+		}
+		return sorted[i].TenantID < sorted[j].TenantID
+	})
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
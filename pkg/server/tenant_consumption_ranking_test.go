@@ -0,0 +1,80 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantConsumptionRanking(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	summary := func(
+		tenantID uint64,
+		start, end time.Duration,
+		startRU, endRU, startCPU, endCPU float64,
+		liveBytes uint64,
+	) serverpb.TenantConsumptionSummary {
+		s := serverpb.TenantConsumptionSummary{
+			TenantID:               tenantID,
+			Start:                  t0.Add(start),
+			End:                    t0.Add(end),
+			StartRU:                startRU,
+			EndRU:                  endRU,
+			StartSQLPodsCPUSeconds: startCPU,
+			EndSQLPodsCPUSeconds:   endCPU,
+			LiveBytes:              liveBytes,
+		}
+		computeTenantConsumptionRates(&s)
+		return s
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		// Node 1 served the tenant during the first half of the window, node 2
+		// during the second half.
+		s := summary(5, 0, 10*time.Minute, 0, 600, 0, 60, 100)
+		other := summary(5, 20*time.Minute, 30*time.Minute, 1200, 1800, 120, 180, 200)
+		mergeTenantConsumptionSummaries(&s, &other)
+		require.Equal(t, summary(5, 0, 30*time.Minute, 0, 1800, 0, 180, 200), s)
+		require.Equal(t, 1.0, s.RURate)
+		require.Equal(t, 0.1, s.CPURate)
+	})
+
+	t.Run("rank", func(t *testing.T) {
+		tenants := []serverpb.TenantConsumptionSummary{
+			summary(2, 0, time.Minute, 0, 60, 0, 30, 300),
+			summary(3, 0, time.Minute, 0, 120, 0, 6, 100),
+			summary(4, 0, time.Minute, 0, 60, 0, 60, 200),
+			// A single rollup does not allow computing rates.
+			summary(5, 0, 0, 100, 100, 100, 100, 400),
+		}
+		ids := func(summaries []serverpb.TenantConsumptionSummary) []uint64 {
+			var res []uint64
+			for _, s := range summaries {
+				res = append(res, s.TenantID)
+			}
+			return res
+		}
+		var resp serverpb.TenantConsumptionRankingResponse
+		rankTenantConsumption(&resp, tenants, 3 /* limit */)
+		require.Equal(t, []uint64{3, 2, 4}, ids(resp.TopByRURate))
+		require.Equal(t, []uint64{4, 2, 3}, ids(resp.TopByCPURate))
+		require.Equal(t, []uint64{5, 2, 4}, ids(resp.TopByLiveBytes))
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TenantCostHistory implements the serverpb.StatusServer interface.
+func (s *statusServer) TenantCostHistory(
+	ctx context.Context, req *serverpb.TenantCostHistoryRequest,
+) (*serverpb.TenantCostHistoryResponse, error) {
+	ctx = authserver.ForwardSQLIdentityThroughRPCCalls(ctx)
+	ctx = s.AnnotateCtx(ctx)
+	if err := s.privilegeChecker.RequireViewClusterMetadataPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if !local {
+		client, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, srverrors.ServerError(ctx, err)
+		}
+		return client.TenantCostHistory(ctx, req)
+	}
+
+	costController := s.sqlServer.execCfg.DistSQLSrv.TenantCostController
+	if costController == nil {
+		return nil, status.Errorf(codes.Unimplemented,
+			"cost history is only available for virtual clusters")
+	}
+	return makeTenantCostHistoryResponse(costController.GetCostHistory()), nil
+}
+
+func makeTenantCostHistoryResponse(
+	samples []multitenant.CostSample,
+) *serverpb.TenantCostHistoryResponse {
+	resp := &serverpb.TenantCostHistoryResponse{
+		Samples: make([]serverpb.TenantCostSample, 0, len(samples)),
+	}
+	for _, sample := range samples {
+		resp.Samples = append(resp.Samples, serverpb.TenantCostSample{
+			Time:                sample.Time,
+			RU:                  sample.RU,
+			EstimatedCPUSeconds: sample.EstimatedCPUSeconds,
+			BlockedRequests:     sample.BlockedRequests,
+			WaitDurationP50:     sample.WaitDurationP50,
+			WaitDurationP90:     sample.WaitDurationP90,
+			WaitDurationP99:     sample.WaitDurationP99,
+		})
+	}
+	return resp
+}
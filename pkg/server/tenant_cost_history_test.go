@@ -0,0 +1,56 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenantCostHistory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	t.Run("convert", func(t *testing.T) {
+		t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		resp := makeTenantCostHistoryResponse([]multitenant.CostSample{
+			{Time: t0, RU: 10, BlockedRequests: 1, WaitDurationP99: time.Second},
+			{Time: t0.Add(10 * time.Second), RU: 20, EstimatedCPUSeconds: 0.5},
+		})
+		require.Equal(t, []serverpb.TenantCostSample{
+			{Time: t0, RU: 10, BlockedRequests: 1, WaitDurationP99: time.Second},
+			{Time: t0.Add(10 * time.Second), RU: 20, EstimatedCPUSeconds: 0.5},
+		}, resp.Samples)
+	})
+
+	t.Run("system tenant", func(t *testing.T) {
+		ctx := context.Background()
+		s := serverutils.StartServerOnly(t, base.TestServerArgs{
+			DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+		})
+		defer s.Stopper().Stop(ctx)
+
+		client := s.GetStatusClient(t)
+		_, err := client.TenantCostHistory(ctx, &serverpb.TenantCostHistoryRequest{})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// tenantLiveBytesRefreshInterval is the interval at which each node measures
+// the data size of the tenants with a max_live_bytes capability and of the
+// tenants recently metered by tenant cost control.
+const tenantLiveBytesRefreshInterval = time.Minute
+
+// tenantLiveBytesTimeout bounds the time spent measuring the data size of a
+// tenant.
+const tenantLiveBytesTimeout = 10 * time.Second
+
+// tenantDataSizeInactivity is the time after which a tenant that tenant cost
+// control no longer asks about stops being measured, unless it has a
+// max_live_bytes capability.
+const tenantDataSizeInactivity = 10 * time.Minute
+
+// tenantLiveBytesMonitor periodically measures the live and total bytes of
+// tenants. It is the only component of the node measuring them:
+//   - the tenant authorizer of the node uses the live bytes of the tenants with
+//     a max_live_bytes capability to reject the writes of the tenants exceeding
+//     their limit. The tenants themselves also reject their writes when told so
+//     by tenant cost control, but the host cannot rely on them doing so.
+//   - tenant cost control uses the measurements to meter the storage of the
+//     tenants requesting tokens from this node, without measuring it while
+//     serving their requests.
+type tenantLiveBytesMonitor struct {
+	capabilities tenantcapabilities.Reader
+	dataSizeFn   multitenant.TenantDataSizeFn
+	timeSource   timeutil.TimeSource
+
+	mu struct {
+		syncutil.Mutex
+		dataSize map[roachpb.TenantID]*tenantDataSize
+	}
+}
+
+// tenantDataSize is the last measurement of the data size of a tenant.
+type tenantDataSize struct {
+	// measured is set once a measurement succeeded.
+	measured   bool
+	liveBytes  int64
+	totalBytes int64
+	// lastRequested is the last time tenant cost control asked for the data
+	// size of the tenant, or zero if it never did.
+	lastRequested time.Time
+}
+
+var _ tenantcapabilities.LiveBytesReader = (*tenantLiveBytesMonitor)(nil)
+var _ multitenant.TenantDataSizeReader = (*tenantLiveBytesMonitor)(nil)
+
+func newTenantLiveBytesMonitor(
+	capabilities tenantcapabilities.Reader,
+	dataSizeFn multitenant.TenantDataSizeFn,
+	timeSource timeutil.TimeSource,
+) *tenantLiveBytesMonitor {
+	m := &tenantLiveBytesMonitor{
+		capabilities: capabilities,
+		dataSizeFn:   dataSizeFn,
+		timeSource:   timeSource,
+	}
+	m.mu.dataSize = make(map[roachpb.TenantID]*tenantDataSize)
+	return m
+}
+
+// GetLiveBytes implements the tenantcapabilities.LiveBytesReader interface.
+func (m *tenantLiveBytesMonitor) GetLiveBytes(id roachpb.TenantID) (int64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ds, ok := m.mu.dataSize[id]
+	if !ok || !ds.measured {
+		return 0, false
+	}
+	return ds.liveBytes, true
+}
+
+// GetDataSize implements the multitenant.TenantDataSizeReader interface.
+func (m *tenantLiveBytesMonitor) GetDataSize(
+	id roachpb.TenantID,
+) (liveBytes, totalBytes int64, found bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ds, ok := m.mu.dataSize[id]
+	if !ok {
+		ds = &tenantDataSize{}
+		m.mu.dataSize[id] = ds
+	}
+	ds.lastRequested = m.timeSource.Now()
+	return ds.liveBytes, ds.totalBytes, ds.measured
+}
+
+// start starts the goroutine refreshing the measurements.
+func (m *tenantLiveBytesMonitor) start(ctx context.Context, stopper *stop.Stopper) error {
+	return stopper.RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "tenant-live-bytes-monitor",
+		SpanOpt:  stop.SterileRootSpan,
+	}, func(ctx context.Context) {
+		ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(tenantLiveBytesRefreshInterval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+				m.refresh(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// refresh measures the data size of the tenants with a max_live_bytes
+// capability and of the tenants recently asked about by tenant cost control,
+// and forgets those of the other tenants.
+func (m *tenantLiveBytesMonitor) refresh(ctx context.Context) {
+	tracked := make(map[roachpb.TenantID]struct{})
+	for id, caps := range m.capabilities.GetGlobalCapabilityState() {
+		if tenantcapabilities.MustGetInt64ByID(caps, tenantcapabilities.MaxLiveBytes) > 0 {
+			tracked[id] = struct{}{}
+		}
+	}
+	func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		now := m.timeSource.Now()
+		for id, ds := range m.mu.dataSize {
+			if _, ok := tracked[id]; ok {
+				continue
+			}
+			if !ds.lastRequested.IsZero() && now.Sub(ds.lastRequested) < tenantDataSizeInactivity {
+				tracked[id] = struct{}{}
+				continue
+			}
+			delete(m.mu.dataSize, id)
+		}
+	}()
+
+	for id := range tracked {
+		var liveBytes, totalBytes int64
+		if err := timeutil.RunWithTimeout(ctx, "tenant-live-bytes", tenantLiveBytesTimeout,
+			func(ctx context.Context) (err error) {
+				liveBytes, totalBytes, err = m.dataSizeFn(ctx, id)
+				return err
+			},
+		); err != nil {
+			// Keep the previous measurement; we will retry after the interval.
+			if ctx.Err() == nil {
+				log.Warningf(ctx, "unable to measure the data size of tenant %s: %v", id, err)
+			}
+			continue
+		}
+		m.mu.Lock()
+		ds, ok := m.mu.dataSize[id]
+		if !ok {
+			ds = &tenantDataSize{}
+			m.mu.dataSize[id] = ds
+		}
+		ds.measured = true
+		ds.liveBytes = liveBytes
+		ds.totalBytes = totalBytes
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities/tenantcapabilitiespb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCapabilitiesReader map[roachpb.TenantID]*tenantcapabilitiespb.TenantCapabilities
+
+var _ tenantcapabilities.Reader = fakeCapabilitiesReader{}
+
+func (r fakeCapabilitiesReader) GetInfo(
+	id roachpb.TenantID,
+) (tenantcapabilities.Entry, <-chan struct{}, bool) {
+	caps, found := r[id]
+	return tenantcapabilities.Entry{TenantID: id, TenantCapabilities: caps}, nil, found
+}
+
+func (r fakeCapabilitiesReader) GetCapabilities(
+	id roachpb.TenantID,
+) (*tenantcapabilitiespb.TenantCapabilities, bool) {
+	caps, found := r[id]
+	return caps, found
+}
+
+func (r fakeCapabilitiesReader) GetGlobalCapabilityState() map[roachpb.TenantID]*tenantcapabilitiespb.TenantCapabilities {
+	return r
+}
+
+func TestTenantLiveBytesMonitor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	limited := roachpb.MustMakeTenantID(10)
+	unlimited := roachpb.MustMakeTenantID(11)
+	caps := fakeCapabilitiesReader{
+		limited:   {MaxLiveBytes: 1000},
+		unlimited: {},
+	}
+	dataSize := map[roachpb.TenantID]int64{limited: 500, unlimited: 2000}
+	var measured []roachpb.TenantID
+	var dataSizeErr error
+	clock := timeutil.NewManualTime(timeutil.Unix(0, 0))
+	m := newTenantLiveBytesMonitor(caps, func(
+		ctx context.Context, tenantID roachpb.TenantID,
+	) (int64, int64, error) {
+		measured = append(measured, tenantID)
+		return dataSize[tenantID], 2 * dataSize[tenantID], dataSizeErr
+	}, clock)
+
+	// Nothing is measured before the first refresh.
+	_, found := m.GetLiveBytes(limited)
+	require.False(t, found)
+
+	// Only the tenants with a max_live_bytes capability are measured.
+	m.refresh(ctx)
+	require.Equal(t, []roachpb.TenantID{limited}, measured)
+	liveBytes, found := m.GetLiveBytes(limited)
+	require.True(t, found)
+	require.Equal(t, int64(500), liveBytes)
+	_, found = m.GetLiveBytes(unlimited)
+	require.False(t, found)
+
+	// A failed measurement keeps the previous one.
+	dataSize[limited] = 1500
+	dataSizeErr = errors.New("boom")
+	m.refresh(ctx)
+	liveBytes, _ = m.GetLiveBytes(limited)
+	require.Equal(t, int64(500), liveBytes)
+	dataSizeErr = nil
+	m.refresh(ctx)
+	liveBytes, _ = m.GetLiveBytes(limited)
+	require.Equal(t, int64(1500), liveBytes)
+
+	// The measurements of the tenants whose limit is removed are forgotten.
+	caps[limited] = &tenantcapabilitiespb.TenantCapabilities{}
+	m.refresh(ctx)
+	_, found = m.GetLiveBytes(limited)
+	require.False(t, found)
+
+	// The tenants asked about by tenant cost control are measured too, until
+	// they are no longer asked about.
+	_, _, found = m.GetDataSize(unlimited)
+	require.False(t, found)
+	measured = nil
+	m.refresh(ctx)
+	require.Equal(t, []roachpb.TenantID{unlimited}, measured)
+	liveBytes, totalBytes, found := m.GetDataSize(unlimited)
+	require.True(t, found)
+	require.Equal(t, int64(2000), liveBytes)
+	require.Equal(t, int64(4000), totalBytes)
+
+	clock.Advance(tenantDataSizeInactivity)
+	measured = nil
+	m.refresh(ctx)
+	require.Empty(t, measured)
+	_, found = m.GetLiveBytes(unlimited)
+	require.False(t, found)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvadmission"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// tenantRUThrottlingRefreshInterval is the interval at which each node reads
+// which tenants are throttled by tenant cost control.
+const tenantRUThrottlingRefreshInterval = 10 * time.Second
+
+// tenantRUThrottlingTimeout bounds the time spent reading which tenants are
+// throttled.
+const tenantRUThrottlingTimeout = 5 * time.Second
+
+// tenantRUThrottlingMonitor periodically reads which tenants have their token
+// bucket in debt, so that KV admission control on the node can deprioritize
+// their work. The state of the buckets is kept by the host, so the tenants
+// cannot opt out of being deprioritized.
+type tenantRUThrottlingMonitor struct {
+	throttledTenantsFn func(context.Context) ([]roachpb.TenantID, error)
+
+	mu struct {
+		syncutil.RWMutex
+		throttled map[roachpb.TenantID]struct{}
+	}
+}
+
+var _ kvadmission.RUThrottledTenants = (*tenantRUThrottlingMonitor)(nil)
+
+func newTenantRUThrottlingMonitor(
+	throttledTenantsFn func(context.Context) ([]roachpb.TenantID, error),
+) *tenantRUThrottlingMonitor {
+	return &tenantRUThrottlingMonitor{throttledTenantsFn: throttledTenantsFn}
+}
+
+// IsRUThrottled implements the kvadmission.RUThrottledTenants interface.
+func (m *tenantRUThrottlingMonitor) IsRUThrottled(id roachpb.TenantID) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.mu.throttled[id]
+	return ok
+}
+
+// start starts the goroutine refreshing the throttled tenants.
+func (m *tenantRUThrottlingMonitor) start(ctx context.Context, stopper *stop.Stopper) error {
+	return stopper.RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "tenant-ru-throttling-monitor",
+		SpanOpt:  stop.SterileRootSpan,
+	}, func(ctx context.Context) {
+		ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(tenantRUThrottlingRefreshInterval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+				m.refresh(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// refresh reads the tenants that are currently throttled. If that fails, the
+// previous set is kept.
+func (m *tenantRUThrottlingMonitor) refresh(ctx context.Context) {
+	var ids []roachpb.TenantID
+	if err := timeutil.RunWithTimeout(ctx, "tenant-ru-throttling", tenantRUThrottlingTimeout,
+		func(ctx context.Context) (err error) {
+			ids, err = m.throttledTenantsFn(ctx)
+			return err
+		},
+	); err != nil {
+		if ctx.Err() == nil {
+			log.Warningf(ctx, "unable to read the tenants throttled by tenant cost control: %v", err)
+		}
+		return
+	}
+	throttled := make(map[roachpb.TenantID]struct{}, len(ids))
+	for _, id := range ids {
+		throttled[id] = struct{}{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mu.throttled = throttled
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantRUThrottlingMonitor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ten10 := roachpb.MustMakeTenantID(10)
+	ten11 := roachpb.MustMakeTenantID(11)
+	var throttled []roachpb.TenantID
+	var readErr error
+	m := newTenantRUThrottlingMonitor(func(ctx context.Context) ([]roachpb.TenantID, error) {
+		return throttled, readErr
+	})
+
+	// No tenant is throttled before the first refresh.
+	require.False(t, m.IsRUThrottled(ten10))
+
+	throttled = []roachpb.TenantID{ten10}
+	m.refresh(ctx)
+	require.True(t, m.IsRUThrottled(ten10))
+	require.False(t, m.IsRUThrottled(ten11))
+
+	// A failed read keeps the previous state.
+	throttled = []roachpb.TenantID{ten11}
+	readErr = errors.New("boom")
+	m.refresh(ctx)
+	require.True(t, m.IsRUThrottled(ten10))
+	require.False(t, m.IsRUThrottled(ten11))
+
+	readErr = nil
+	m.refresh(ctx)
+	require.False(t, m.IsRUThrottled(ten10))
+	require.True(t, m.IsRUThrottled(ten11))
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResyncTenantSettings implements the serverpb.StatusServer interface.
+func (s *systemStatusServer) ResyncTenantSettings(
+	ctx context.Context, req *serverpb.ResyncTenantSettingsRequest,
+) (*serverpb.ResyncTenantSettingsResponse, error) {
+	ctx = authserver.ForwardSQLIdentityThroughRPCCalls(ctx)
+	ctx = s.AnnotateCtx(ctx)
+	if err := s.privilegeChecker.RequireRepairClusterPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	resp := &serverpb.ResyncTenantSettingsResponse{
+		ErrorsByNodeID: make(map[roachpb.NodeID]string),
+	}
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if !local {
+			client, err := s.dialNode(ctx, requestedNodeID)
+			if err != nil {
+				return nil, srverrors.ServerError(ctx, err)
+			}
+			return client.ResyncTenantSettings(ctx, req)
+		}
+		if err := s.node.tenantSettingsWatcher.Resync(ctx); err != nil {
+			return nil, srverrors.ServerError(ctx, err)
+		}
+		return resp, nil
+	}
+
+	// Each node watches the tenant_settings table separately, and pushes the
+	// overrides to the tenants connected to it.
+	remoteRequest := serverpb.ResyncTenantSettingsRequest{NodeID: "local"}
+	nodeFn := func(
+		ctx context.Context, client serverpb.StatusClient, _ roachpb.NodeID,
+	) (*serverpb.ResyncTenantSettingsResponse, error) {
+		return client.ResyncTenantSettings(ctx, &remoteRequest)
+	}
+	responseFn := func(roachpb.NodeID, *serverpb.ResyncTenantSettingsResponse) {}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		resp.ErrorsByNodeID[nodeID] = err.Error()
+	}
+	if err := iterateNodes(ctx, s.serverIterator, s.stopper, "tenant settings resync",
+		noTimeout,
+		s.dialNode,
+		nodeFn,
+		responseFn,
+		errorFn,
+	); err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResyncTenantSettings(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s := serverutils.StartServerOnly(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+	})
+	defer s.Stopper().Stop(ctx)
+	watcher := s.SystemLayer().(*testServer).topLevelServer.node.tenantSettingsWatcher
+
+	client := s.GetStatusClient(t)
+	resp, err := client.ResyncTenantSettings(ctx, &serverpb.ResyncTenantSettingsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.ErrorsByNodeID)
+	require.Equal(t, int64(1), watcher.Metrics().Resyncs.Count())
+
+	_, err = client.ResyncTenantSettings(ctx, &serverpb.ResyncTenantSettingsRequest{NodeID: "local"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), watcher.Metrics().Resyncs.Count())
+}
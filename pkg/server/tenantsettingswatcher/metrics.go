@@ -0,0 +1,57 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantsettingswatcher
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+var (
+	metaApplyLatency = metric.Metadata{
+		Name: "tenant.settings_watcher.apply_latency",
+		Help: "Latency between the commit of a change to the setting overrides of " +
+			"virtual clusters and its application by the settings watcher of the node; " +
+			"it doesn't include the push of the change to the virtual clusters and " +
+			"its application by them",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaResyncs = metric.Metadata{
+		Name:        "tenant.settings_watcher.resyncs",
+		Help:        "Number of forced resyncs of the setting overrides of virtual clusters",
+		Measurement: "Resyncs",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+// Metrics contains the metrics of the Watcher.
+type Metrics struct {
+	ApplyLatency metric.IHistogram
+	Resyncs      *metric.Counter
+}
+
+var _ metric.Struct = (*Metrics)(nil)
+
+// MetricStruct implements the metric.Struct interface.
+func (m *Metrics) MetricStruct() {}
+
+func makeMetrics() *Metrics {
+	return &Metrics{
+		ApplyLatency: metric.NewHistogram(metric.HistogramOptions{
+			Mode:         metric.HistogramModePreferHdrLatency,
+			Metadata:     metaApplyLatency,
+			Duration:     base.DefaultHistogramWindowInterval(),
+			BucketConfig: metric.IOLatencyBuckets,
+		}),
+		Resyncs: metric.NewCounter(metaResyncs),
+	}
+}
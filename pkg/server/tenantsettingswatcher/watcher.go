@@ -57,6 +57,7 @@ type Watcher struct {
 	st      *cluster.Settings
 	dec     RowDecoder
 	store   overridesStore
+	metrics *Metrics
 
 	// startCh is closed once the rangefeed starts.
 	startCh  chan struct{}
@@ -82,6 +83,7 @@ func New(
 		stopper: stopper,
 		st:      st,
 		dec:     MakeRowDecoder(),
+		metrics: makeMetrics(),
 	}
 	w.store.Init()
 	w.mu.updateWait = make(chan struct{})
@@ -153,6 +155,8 @@ func (w *Watcher) startRangeFeed(
 		} else {
 			// We are processing incremental changes.
 			w.store.setTenantOverride(ctx, tenantID, setting)
+			w.metrics.ApplyLatency.RecordValue(
+				w.clock.PhysicalNow() - kv.Value.Timestamp.WallTime)
 		}
 		return nil, false
 	}
@@ -233,6 +237,36 @@ func (w *Watcher) WaitForStart(ctx context.Context) error {
 	}
 }
 
+// Resync restarts the rangefeed, which rescans the tenant_settings table, and
+// waits until the overrides have been refreshed. All the overrides are then
+// pushed again to the tenants connected to the node. This can be used to force
+// the propagation of overrides, e.g. if the rangefeed is suspected to be stuck.
+func (w *Watcher) Resync(ctx context.Context) error {
+	if err := w.WaitForStart(ctx); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	waitCh := w.mu.updateWait
+	w.mu.Unlock()
+	if err := w.rfc.Restart(ctx); err != nil {
+		return errors.Wrap(err, "failed to restart tenant settings rangefeed")
+	}
+	w.metrics.Resyncs.Inc(1)
+	select {
+	case <-waitCh:
+		return nil
+	case <-w.stopper.ShouldQuiesce():
+		return errors.Wrap(stop.ErrUnavailable, "failed to resync tenant settings")
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to resync tenant settings")
+	}
+}
+
+// Metrics returns the metrics of the Watcher.
+func (w *Watcher) Metrics() *Metrics {
+	return w.metrics
+}
+
 // TestingRestart restarts the rangefeeds and waits for the initial
 // update after the rangefeed update to be processed.
 func (w *Watcher) TestingRestart() {
@@ -118,6 +118,17 @@ func TestWatcher(t *testing.T) {
 	// Add an override for a tenant that has no overrides.
 	r.Exec(t, "INSERT INTO system.tenant_settings (tenant_id, name, value, value_type) VALUES (3, 'qux', 'qux-t3', 's')")
 	expectClose(t3Ch)
+	t3Overrides, t3Ch = w.GetTenantOverrides(ctx, t3)
+	expect(t3Overrides, "qux=qux-t3")
+
+	// The latency of the application of incremental changes is recorded.
+	count, _ := w.Metrics().ApplyLatency.CumulativeSnapshot().Total()
+	require.GreaterOrEqual(t, count, int64(4))
+
+	// A resync rescans the table and notifies the listeners.
+	require.NoError(t, w.Resync(ctx))
+	expectClose(t3Ch)
 	t3Overrides, _ = w.GetTenantOverrides(ctx, t3)
 	expect(t3Overrides, "qux=qux-t3")
+	require.Equal(t, int64(1), w.Metrics().Resyncs.Count())
 }
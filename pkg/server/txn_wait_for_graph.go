@@ -0,0 +1,200 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// txnWaitForGraphBatchSize is the maximum number of locks fetched by each
+// QueryLocks request issued to build the wait-for graph.
+const txnWaitForGraphBatchSize = 10000
+
+// TxnWaitForGraph implements the serverpb.StatusServer interface.
+func (s *statusServer) TxnWaitForGraph(
+	ctx context.Context, req *serverpb.TxnWaitForGraphRequest,
+) (*serverpb.TxnWaitForGraphResponse, error) {
+	ctx = s.AnnotateCtx(authserver.ForwardSQLIdentityThroughRPCCalls(ctx))
+
+	if err := s.privilegeChecker.RequireViewActivityOrViewActivityRedactedPermission(ctx); err != nil {
+		return nil, err
+	}
+	user, isAdmin, err := s.privilegeChecker.GetUserAndRole(ctx)
+	if err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+	shouldRedactKeys := false
+	if !isAdmin {
+		shouldRedactKeys, err = s.privilegeChecker.HasRoleOption(ctx, user, roleoption.VIEWACTIVITYREDACTED)
+		if err != nil {
+			return nil, srverrors.ServerError(ctx, err)
+		}
+	}
+
+	locks, err := s.contendedLocks(ctx)
+	if err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+	fingerprints, err := s.txnStatementFingerprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &serverpb.TxnWaitForGraphResponse{}
+	for _, l := range locks {
+		if l.LockHolder == nil {
+			continue
+		}
+		for _, w := range l.Waiters {
+			// Non-transactional requests can't be part of a deadlock and have no
+			// transaction to report.
+			if w.WaitingTxn == nil {
+				continue
+			}
+			edge := serverpb.TxnWaitForGraphResponse_Edge{
+				WaiterTxnID:                w.WaitingTxn.ID,
+				HolderTxnID:                l.LockHolder.ID,
+				RangeID:                    l.RangeID,
+				LockStrength:               w.Strength.String(),
+				WaitDuration:               w.WaitDuration,
+				WaiterStatementFingerprint: fingerprints[w.WaitingTxn.ID],
+				HolderStatementFingerprint: fingerprints[l.LockHolder.ID],
+			}
+			if !shouldRedactKeys {
+				edge.Key = l.Key
+				edge.PrettyKey = keys.PrettyPrint(nil /* valDirs */, l.Key)
+			}
+			resp.Edges = append(resp.Edges, edge)
+		}
+	}
+	markDeadlockedEdges(resp.Edges)
+	return resp, nil
+}
+
+// contendedLocks returns the locks of the tenant's keyspace which have
+// waiters.
+func (s *statusServer) contendedLocks(ctx context.Context) ([]roachpb.LockStateInfo, error) {
+	codec := s.sqlServer.execCfg.Codec
+	span := roachpb.Span{Key: codec.TablePrefix(0), EndKey: codec.TenantEndKey()}
+	var locks []roachpb.LockStateInfo
+	for {
+		b := &kv.Batch{}
+		b.AddRawRequest(&kvpb.QueryLocksRequest{
+			RequestHeader: kvpb.RequestHeaderFromSpan(span),
+		})
+		b.Header.MaxSpanRequestKeys = txnWaitForGraphBatchSize
+		if err := s.db.Run(ctx, b); err != nil {
+			return nil, err
+		}
+		if len(b.RawResponse().Responses) != 1 {
+			return nil, errors.AssertionFailedf(
+				"unexpected response length of %d for QueryLocksRequest", len(b.RawResponse().Responses))
+		}
+		resp := b.RawResponse().Responses[0].GetQueryLocks()
+		locks = append(locks, resp.Locks...)
+		if resp.ResumeSpan == nil {
+			return locks, nil
+		}
+		span = *resp.ResumeSpan
+	}
+}
+
+// txnStatementFingerprints returns, for each open transaction of the
+// cluster's sessions, the fingerprint of the statement it is executing, or
+// else of the last statement executed by its session.
+func (s *statusServer) txnStatementFingerprints(
+	ctx context.Context,
+) (map[uuid.UUID]string, error) {
+	sessions, err := s.ListSessions(ctx, &serverpb.ListSessionsRequest{ExcludeClosedSessions: true})
+	if err != nil {
+		return nil, err
+	}
+	fingerprints := make(map[uuid.UUID]string)
+	for _, session := range sessions.Sessions {
+		if session.ActiveTxn == nil {
+			continue
+		}
+		fingerprint := session.LastActiveQueryNoConstants
+		if len(session.ActiveQueries) > 0 {
+			fingerprint = session.ActiveQueries[0].SqlNoConstants
+		}
+		fingerprints[session.ActiveTxn.ID] = fingerprint
+	}
+	return fingerprints, nil
+}
+
+// markDeadlockedEdges marks the edges of the wait-for graph which are part of
+// a cycle, i.e. whose waiter and holder are in the same strongly connected
+// component of the graph, found with Tarjan's algorithm.
+func markDeadlockedEdges(edges []serverpb.TxnWaitForGraphResponse_Edge) {
+	succ := make(map[uuid.UUID][]uuid.UUID)
+	for _, e := range edges {
+		succ[e.WaiterTxnID] = append(succ[e.WaiterTxnID], e.HolderTxnID)
+	}
+
+	type nodeState struct {
+		index, lowLink int
+		onStack        bool
+	}
+	states := make(map[uuid.UUID]*nodeState)
+	component := make(map[uuid.UUID]int)
+	var stack []uuid.UUID
+	var index, components int
+	var visit func(v uuid.UUID)
+	visit = func(v uuid.UUID) {
+		sv := &nodeState{index: index, lowLink: index, onStack: true}
+		states[v] = sv
+		index++
+		stack = append(stack, v)
+		for _, w := range succ[v] {
+			if sw, ok := states[w]; !ok {
+				visit(w)
+				sv.lowLink = min(sv.lowLink, states[w].lowLink)
+			} else if sw.onStack {
+				sv.lowLink = min(sv.lowLink, sw.index)
+			}
+		}
+		if sv.lowLink == sv.index {
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				states[w].onStack = false
+				component[w] = components
+				if w == v {
+					break
+				}
+			}
+			components++
+		}
+	}
+	for _, e := range edges {
+		if _, ok := states[e.WaiterTxnID]; !ok {
+			visit(e.WaiterTxnID)
+		}
+	}
+
+	for i := range edges {
+		e := &edges[i]
+		e.Deadlocked = e.WaiterTxnID != e.HolderTxnID &&
+			component[e.WaiterTxnID] == component[e.HolderTxnID]
+	}
+}
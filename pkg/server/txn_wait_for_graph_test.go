@@ -0,0 +1,103 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxnWaitForGraph(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v INT)`)
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 1)`)
+
+	client := s.GetStatusClient(t)
+	resp, err := client.TxnWaitForGraph(ctx, &serverpb.TxnWaitForGraphRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Edges)
+
+	// Block a transaction on a lock held by another one.
+	holder, err := db.Begin()
+	require.NoError(t, err)
+	_, err = holder.Exec(`UPDATE t SET v = 2 WHERE k = 1`)
+	require.NoError(t, err)
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err := db.Exec(`UPDATE t SET v = 3 WHERE k = 1`)
+		waiterErr <- err
+	}()
+
+	testutils.SucceedsSoon(t, func() error {
+		resp, err := client.TxnWaitForGraph(ctx, &serverpb.TxnWaitForGraphRequest{})
+		if err != nil {
+			return err
+		}
+		if len(resp.Edges) != 1 {
+			return errors.Newf("expected 1 edge, got %v", resp.Edges)
+		}
+		e := resp.Edges[0]
+		if !strings.Contains(e.WaiterStatementFingerprint, "UPDATE t SET v = _") {
+			return errors.Newf("unexpected waiter statement fingerprint %q", e.WaiterStatementFingerprint)
+		}
+		require.NotEqual(t, e.WaiterTxnID, e.HolderTxnID)
+		require.Contains(t, e.HolderStatementFingerprint, "UPDATE t SET v = _")
+		require.Contains(t, e.PrettyKey, "/Table/")
+		require.False(t, e.Deadlocked)
+		return nil
+	})
+
+	require.NoError(t, holder.Rollback())
+	require.NoError(t, <-waiterErr)
+}
+
+func TestMarkDeadlockedEdges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	txns := make([]uuid.UUID, 5)
+	for i := range txns {
+		txns[i] = uuid.MakeV4()
+	}
+	edge := func(waiter, holder int) serverpb.TxnWaitForGraphResponse_Edge {
+		return serverpb.TxnWaitForGraphResponse_Edge{WaiterTxnID: txns[waiter], HolderTxnID: txns[holder]}
+	}
+	// 0 -> 1 -> 2 -> 0 is a deadlock, which 3 waits on. 3 -> 4 is a chain of
+	// contention.
+	edges := []serverpb.TxnWaitForGraphResponse_Edge{
+		edge(3, 0), edge(0, 1), edge(1, 2), edge(2, 0), edge(3, 4),
+	}
+	markDeadlockedEdges(edges)
+	var deadlocked []bool
+	for _, e := range edges {
+		deadlocked = append(deadlocked, e.Deadlocked)
+	}
+	require.Equal(t, []bool{false, true, true, true, false}, deadlocked)
+}
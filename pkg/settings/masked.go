@@ -33,9 +33,15 @@ var redactSensitiveSettingsEnabled = RegisterBoolSetting(
 
 // String hides the underlying value.
 func (s *MaskedSetting) String(sv *Values) string {
+	return s.Mask(sv, s.setting.String(sv))
+}
+
+// Mask hides the given string representation of a value of the setting, such
+// as a past value, in the same way as String hides the current value.
+func (s *MaskedSetting) Mask(sv *Values, repr string) string {
 	// Special case for non-reportable/sensitive strings: we still want
 	// to distinguish empty from non-empty (= customized).
-	if st, ok := s.setting.(*StringSetting); ok && st.String(sv) == "" {
+	if _, ok := s.setting.(*StringSetting); ok && repr == "" {
 		return ""
 	}
 	isSensitive := false
@@ -48,7 +54,7 @@ func (s *MaskedSetting) String(sv *Values) string {
 	if !isSensitive || sensitiveRedactionEnabled {
 		return "<redacted>"
 	}
-	return s.setting.String(sv)
+	return repr
 }
 
 // DefaultString returns the default value for the setting as a string.
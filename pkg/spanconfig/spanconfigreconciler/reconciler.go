@@ -30,8 +30,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -48,14 +50,39 @@ type Reconciler struct {
 	settings *cluster.Settings
 	knobs    *spanconfig.TestingKnobs
 
+	metrics *Metrics
+
 	mu struct {
 		syncutil.RWMutex
 		lastCheckpoint hlc.Timestamp
+		// reconciling is set while Reconcile is running, i.e. while this is
+		// the instance of the Reconciler running for the tenant.
+		reconciling bool
 	}
 }
 
 var _ spanconfig.Reconciler = &Reconciler{}
 
+var metaReconciliationLag = metric.Metadata{
+	Name: "spanconfig.reconciler.lag_nanos",
+	Help: "Difference between the current time and the span config reconciler's " +
+		"last checkpoint (an ever increasing number indicates that zone config " +
+		"changes are no longer being applied, for example because the host " +
+		"rejects them)",
+	Measurement: "Nanoseconds",
+	Unit:        metric.Unit_NANOSECONDS,
+}
+
+// Metrics are the metrics of the Reconciler.
+type Metrics struct {
+	// Lag is the time since the last checkpoint of the Reconciler, if it's
+	// running.
+	Lag *metric.Gauge
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (m *Metrics) MetricStruct() {}
+
 // New constructs a new Reconciler.
 func New(
 	sqlWatcher spanconfig.SQLWatcher,
@@ -70,7 +97,7 @@ func New(
 	if knobs == nil {
 		knobs = &spanconfig.TestingKnobs{}
 	}
-	return &Reconciler{
+	r := &Reconciler{
 		sqlWatcher:           sqlWatcher,
 		sqlTranslatorFactory: sqlTranslatorFactory,
 		kvAccessor:           kvAccessor,
@@ -81,6 +108,26 @@ func New(
 		tenID:    tenID,
 		knobs:    knobs,
 	}
+	r.metrics = &Metrics{
+		Lag: metric.NewFunctionalGauge(metaReconciliationLag, r.lag),
+	}
+	return r
+}
+
+// Metrics returns the metrics of the Reconciler.
+func (r *Reconciler) Metrics() *Metrics {
+	return r.metrics
+}
+
+// lag returns the time elapsed since the last checkpoint, in nanoseconds. It's
+// zero if the Reconciler isn't running or hasn't checkpointed yet.
+func (r *Reconciler) lag() int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.mu.reconciling || r.mu.lastCheckpoint.IsEmpty() {
+		return 0
+	}
+	return timeutil.Since(r.mu.lastCheckpoint.GoTime()).Nanoseconds()
 }
 
 // Reconcile is part of the spanconfig.Reconciler interface; it's responsible
@@ -151,6 +198,15 @@ func (r *Reconciler) Reconcile(
 		fn(startTS)
 	}
 
+	r.mu.Lock()
+	r.mu.reconciling = true
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.mu.reconciling = false
+	}()
+
 	full := fullReconciler{
 		sqlTranslatorFactory: r.sqlTranslatorFactory,
 		kvAccessor:           r.kvAccessor,
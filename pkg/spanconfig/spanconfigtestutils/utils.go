@@ -669,6 +669,9 @@ func PrintSpanConfigDiffedAgainstDefaults(conf roachpb.SpanConfig) string {
 	if conf.ExcludeDataFromBackup != defaultConf.ExcludeDataFromBackup {
 		diffs = append(diffs, fmt.Sprintf("exclude_data_from_backup=%v", conf.ExcludeDataFromBackup))
 	}
+	if conf.ClosedTimestampTargetDuration != defaultConf.ClosedTimestampTargetDuration {
+		diffs = append(diffs, fmt.Sprintf("closed_timestamp_target_duration=%s", conf.ClosedTimestampTargetDuration))
+	}
 
 	return strings.Join(diffs, " ")
 }
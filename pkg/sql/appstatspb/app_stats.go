@@ -177,6 +177,9 @@ func (s *StatementStatistics) Add(other *StatementStatistics) {
 	s.BytesRead.Add(other.BytesRead, s.Count, other.Count)
 	s.RowsRead.Add(other.RowsRead, s.Count, other.Count)
 	s.RowsWritten.Add(other.RowsWritten, s.Count, other.Count)
+	s.RequestUnits.Add(other.RequestUnits, s.Count, other.Count)
+	s.EstimatedCPUSeconds.Add(other.EstimatedCPUSeconds, s.Count, other.Count)
+	s.Retries.Add(other.Retries, s.Count, other.Count)
 	s.Nodes = util.CombineUnique(s.Nodes, other.Nodes)
 	s.Regions = util.CombineUnique(s.Regions, other.Regions)
 	s.PlanGists = util.CombineUnique(s.PlanGists, other.PlanGists)
@@ -225,7 +228,10 @@ func (s *StatementStatistics) AlmostEqual(other *StatementStatistics, eps float6
 		s.SensitiveInfo.Equal(other.SensitiveInfo) &&
 		s.BytesRead.AlmostEqual(other.BytesRead, eps) &&
 		s.RowsRead.AlmostEqual(other.RowsRead, eps) &&
-		s.RowsWritten.AlmostEqual(other.RowsWritten, eps)
+		s.RowsWritten.AlmostEqual(other.RowsWritten, eps) &&
+		s.RequestUnits.AlmostEqual(other.RequestUnits, eps) &&
+		s.EstimatedCPUSeconds.AlmostEqual(other.EstimatedCPUSeconds, eps) &&
+		s.Retries.AlmostEqual(other.Retries, eps)
 	// s.ExecStats are deliberately ignored since they are subject to sampling
 	// probability and are not fully deterministic (e.g. the number of network
 	// messages depends on the range cache state).
@@ -0,0 +1,382 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package auditlogging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/redact"
+)
+
+// ExternalSinkURI is the URI of the external storage that audit events are
+// shipped to.
+var ExternalSinkURI = settings.RegisterStringSetting(
+	settings.ApplicationLevel,
+	"sql.audit_log.external_sink.uri",
+	"if set, the URI of the external storage (e.g. a cloud storage bucket) or of the "+
+		"Kafka topic (kafka://<broker>?topic_name=<topic>) that audit events are shipped "+
+		"to, in addition to being written to the SENSITIVE_ACCESS logging channel",
+	"",
+	settings.Sensitive,
+	settings.WithPublic,
+)
+
+var externalSinkBufferSize = settings.RegisterByteSizeSetting(
+	settings.ApplicationLevel,
+	"sql.audit_log.external_sink.buffer_size",
+	"maximum amount of memory used to buffer audit events before they are shipped to "+
+		"the external sink; events are dropped when the buffer is full",
+	8<<20, /* 8 MiB */
+	settings.PositiveInt,
+	settings.WithPublic,
+)
+
+var externalSinkFlushInterval = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.audit_log.external_sink.flush_interval",
+	"maximum amount of time audit events are buffered before they are shipped to the "+
+		"external sink",
+	10*time.Second,
+	settings.PositiveDuration,
+	settings.WithPublic,
+)
+
+var (
+	metaExternalSinkEmitted = metric.Metadata{
+		Name:        "sql.audit_log.external_sink.emitted",
+		Help:        "Number of audit events shipped to the external sink",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaExternalSinkDropped = metric.Metadata{
+		Name:        "sql.audit_log.external_sink.dropped",
+		Help:        "Number of audit events dropped because the buffer of the external sink was full",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaExternalSinkFlushErrors = metric.Metadata{
+		Name:        "sql.audit_log.external_sink.flush_errors",
+		Help:        "Number of failed attempts to ship audit events to the external sink",
+		Measurement: "Errors",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaExternalSinkBufferedBytes = metric.Metadata{
+		Name:        "sql.audit_log.external_sink.buffered_bytes",
+		Help:        "Number of bytes of audit events buffered before being shipped to the external sink",
+		Measurement: "Memory",
+		Unit:        metric.Unit_BYTES,
+	}
+)
+
+// ExternalSinkMetrics are the metrics of an ExternalSink.
+type ExternalSinkMetrics struct {
+	Emitted       *metric.Counter
+	Dropped       *metric.Counter
+	FlushErrors   *metric.Counter
+	BufferedBytes *metric.Gauge
+}
+
+// MetricStruct is part of the metric.Struct interface.
+func (ExternalSinkMetrics) MetricStruct() {}
+
+var _ metric.Struct = ExternalSinkMetrics{}
+
+// flushRetryOptions are the retry options used when shipping a batch of audit
+// events to the external sink. If all attempts fail, the batch is kept in the
+// buffer (as far as it fits) and retried on the next flush.
+var flushRetryOptions = retry.Options{
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	MaxRetries:     4,
+}
+
+// ExternalSink ships audit events to an external storage, such as a cloud
+// storage bucket, as files of newline-delimited JSON, or to a Kafka topic, as
+// one JSON message per event.
+//
+// Events are buffered in memory and shipped periodically, or as soon as the
+// buffer is half full. The buffer is bounded: when the external storage is
+// slow or unavailable, events that don't fit in the buffer are dropped rather
+// than blocking the execution of the statements they are emitted for.
+type ExternalSink struct {
+	settings       *cluster.Settings
+	storageFromURI cloud.ExternalStorageFromURIFactory
+	metrics        ExternalSinkMetrics
+
+	// filePrefix makes the names of the files written by this sink unique
+	// across SQL instances.
+	filePrefix string
+	// flushCh is signaled when the buffer is half full.
+	flushCh chan struct{}
+
+	mu struct {
+		syncutil.Mutex
+		// events contains the JSON encoding of the buffered events.
+		events        [][]byte
+		bufferedBytes int64
+		// seq is the sequence number of the next file written by this sink.
+		seq int
+	}
+
+	// writer ships events to the sink configured by writerURI. It is only
+	// accessed by the flush loop.
+	writer    externalSinkWriter
+	writerURI string
+}
+
+// externalSinkWriter ships batches of audit events to an external sink.
+type externalSinkWriter interface {
+	// write ships the given JSON encoded events, each followed by a newline.
+	// The name uniquely identifies the batch.
+	write(ctx context.Context, name string, events [][]byte) error
+	Close() error
+}
+
+// storageWriter is an externalSinkWriter which writes each batch of events to
+// a file of an external storage.
+type storageWriter struct {
+	storage cloud.ExternalStorage
+}
+
+func (w storageWriter) write(ctx context.Context, name string, events [][]byte) error {
+	var buf bytes.Buffer
+	for _, event := range events {
+		buf.Write(event)
+	}
+	return cloud.WriteFile(ctx, w.storage, name+".ndjson", &buf)
+}
+
+func (w storageWriter) Close() error {
+	return w.storage.Close()
+}
+
+// NewExternalSink creates a new ExternalSink. Start needs to be called for
+// buffered events to be shipped.
+func NewExternalSink(
+	st *cluster.Settings, storageFromURI cloud.ExternalStorageFromURIFactory,
+) *ExternalSink {
+	return &ExternalSink{
+		settings:       st,
+		storageFromURI: storageFromURI,
+		metrics: ExternalSinkMetrics{
+			Emitted:       metric.NewCounter(metaExternalSinkEmitted),
+			Dropped:       metric.NewCounter(metaExternalSinkDropped),
+			FlushErrors:   metric.NewCounter(metaExternalSinkFlushErrors),
+			BufferedBytes: metric.NewGauge(metaExternalSinkBufferedBytes),
+		},
+		filePrefix: uuid.MakeV4().Short(),
+		flushCh:    make(chan struct{}, 1),
+	}
+}
+
+// Metrics returns the metrics of the sink.
+func (s *ExternalSink) Metrics() *ExternalSinkMetrics {
+	return &s.metrics
+}
+
+// Enabled returns whether audit events should be shipped to the sink.
+func (s *ExternalSink) Enabled() bool {
+	return s != nil && ExternalSinkURI.Get(&s.settings.SV) != ""
+}
+
+// Emit buffers the given events to be shipped to the external sink. It never
+// blocks; events are dropped if the buffer is full.
+func (s *ExternalSink) Emit(events ...logpb.EventPayload) {
+	if !s.Enabled() {
+		return
+	}
+	maxBytes := externalSinkBufferSize.Get(&s.settings.SV)
+	for _, event := range events {
+		encoded := encodeEvent(event)
+		s.mu.Lock()
+		if s.mu.bufferedBytes+int64(len(encoded)) > maxBytes {
+			s.mu.Unlock()
+			s.metrics.Dropped.Inc(1)
+			continue
+		}
+		s.mu.events = append(s.mu.events, encoded)
+		s.mu.bufferedBytes += int64(len(encoded))
+		halfFull := s.mu.bufferedBytes >= maxBytes/2
+		s.metrics.BufferedBytes.Update(s.mu.bufferedBytes)
+		s.mu.Unlock()
+		if halfFull {
+			select {
+			case s.flushCh <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// encodeEvent returns the JSON encoding of the given event, followed by a
+// newline.
+func encodeEvent(event logpb.EventPayload) []byte {
+	// Populate the missing common fields, like log.StructuredEvent does.
+	common := event.CommonDetails()
+	if common.Timestamp == 0 {
+		common.Timestamp = timeutil.Now().UnixNano()
+	}
+	if len(common.EventType) == 0 {
+		common.EventType = logpb.GetEventTypeName(event)
+	}
+	var b redact.RedactableBytes
+	b = append(b, '{')
+	_, b = event.AppendJSONFields(false /* printComma */, b)
+	b = append(b, '}', '\n')
+	return b.StripMarkers()
+}
+
+// Start starts the loop that ships the buffered events to the external sink.
+func (s *ExternalSink) Start(ctx context.Context, stopper *stop.Stopper) error {
+	return stopper.RunAsyncTask(ctx, "audit-log-external-sink", func(ctx context.Context) {
+		ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		defer s.closeWriter(ctx)
+
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(externalSinkFlushInterval.Get(&s.settings.SV))
+			select {
+			case <-timer.C:
+				timer.Read = true
+			case <-s.flushCh:
+			case <-ctx.Done():
+				return
+			}
+			if err := s.flush(ctx); err != nil {
+				log.Warningf(ctx, "failed to ship audit events to external sink: %v", err)
+			}
+		}
+	})
+}
+
+// flush ships the buffered events to the external sink.
+func (s *ExternalSink) flush(ctx context.Context) error {
+	s.mu.Lock()
+	events := s.mu.events
+	seq := s.mu.seq
+	s.mu.events = nil
+	s.mu.bufferedBytes = 0
+	s.mu.seq++
+	s.mu.Unlock()
+	if len(events) == 0 {
+		return nil
+	}
+
+	name := fmt.Sprintf("audit-%s-%s-%06d",
+		timeutil.Now().UTC().Format("20060102T150405"), s.filePrefix, seq)
+
+	var err error
+	for r := retry.StartWithCtx(ctx, flushRetryOptions); r.Next(); {
+		if err = s.write(ctx, name, events); err == nil {
+			s.metrics.Emitted.Inc(int64(len(events)))
+			s.updateBufferedBytes()
+			return nil
+		}
+		s.metrics.FlushErrors.Inc(1)
+		// Reopen the sink on the next attempt, in case it is in a bad state.
+		s.closeWriter(ctx)
+	}
+
+	// Put the events back at the front of the buffer, as far as they fit, so
+	// they are retried on the next flush.
+	s.requeue(events)
+	return err
+}
+
+// write ships the given events to the configured sink.
+func (s *ExternalSink) write(ctx context.Context, name string, events [][]byte) error {
+	uri := ExternalSinkURI.Get(&s.settings.SV)
+	if s.writer != nil && s.writerURI != uri {
+		s.closeWriter(ctx)
+	}
+	if s.writer == nil {
+		writer, err := s.openWriter(ctx, uri)
+		if err != nil {
+			return err
+		}
+		s.writer, s.writerURI = writer, uri
+	}
+	return s.writer.write(ctx, name, events)
+}
+
+// openWriter opens the sink with the given URI.
+func (s *ExternalSink) openWriter(ctx context.Context, uri string) (externalSinkWriter, error) {
+	if strings.HasPrefix(uri, kafkaScheme+"://") {
+		return newKafkaWriter(uri)
+	}
+	// The storage is accessed as the root user of the virtual cluster rather
+	// than as the node user, like e.g. a BACKUP run by an admin of the virtual
+	// cluster; this determines e.g. the owner of userfile storage and the
+	// privileges checked for external connections.
+	storage, err := s.storageFromURI(ctx, uri, username.RootUserName())
+	if err != nil {
+		return nil, err
+	}
+	return storageWriter{storage: storage}, nil
+}
+
+// requeue puts the given events, which failed to be shipped, back at the
+// front of the buffer. Events that don't fit in the buffer anymore are
+// dropped, favoring older events.
+func (s *ExternalSink) requeue(events [][]byte) {
+	maxBytes := externalSinkBufferSize.Get(&s.settings.SV)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requeued := make([][]byte, 0, len(events)+len(s.mu.events))
+	var requeuedBytes int64
+	for _, batch := range [][][]byte{events, s.mu.events} {
+		for _, event := range batch {
+			if requeuedBytes+int64(len(event)) > maxBytes {
+				s.metrics.Dropped.Inc(1)
+				continue
+			}
+			requeued = append(requeued, event)
+			requeuedBytes += int64(len(event))
+		}
+	}
+	s.mu.events = requeued
+	s.mu.bufferedBytes = requeuedBytes
+	s.metrics.BufferedBytes.Update(requeuedBytes)
+}
+
+func (s *ExternalSink) updateBufferedBytes() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics.BufferedBytes.Update(s.mu.bufferedBytes)
+}
+
+func (s *ExternalSink) closeWriter(ctx context.Context) {
+	if s.writer == nil {
+		return
+	}
+	if err := s.writer.Close(); err != nil {
+		log.Warningf(ctx, "failed to close external audit log sink: %v", err)
+	}
+	s.writer, s.writerURI = nil, ""
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package auditlogging
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	kafkaScheme         = "kafka"
+	kafkaParamTopicName = "topic_name"
+	kafkaParamTLS       = "tls_enabled"
+)
+
+// newKafkaProducer creates the producer used by kafkaWriter. It is a variable
+// so that it can be overridden in tests.
+var newKafkaProducer = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+	return sarama.NewSyncProducer(brokers, config)
+}
+
+// kafkaWriter is an externalSinkWriter which produces each audit event as a
+// message of a Kafka topic.
+type kafkaWriter struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// newKafkaWriter returns a kafkaWriter for the given URI, of the form
+// kafka://<broker>[,<broker>...]?topic_name=<topic>[&tls_enabled=true].
+func newKafkaWriter(uri string) (*kafkaWriter, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.Newf("no Kafka broker specified in %s URI", kafkaScheme)
+	}
+	params := u.Query()
+	topic := params.Get(kafkaParamTopicName)
+	if topic == "" {
+		return nil, errors.Newf("%s parameter is required for %s URIs", kafkaParamTopicName, kafkaScheme)
+	}
+	config := sarama.NewConfig()
+	config.ClientID = "CockroachDB audit log"
+	// Wait for all in-sync replicas to acknowledge the events, so that a batch
+	// is only considered shipped once it is durable.
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Retry.Max = 0 // retried by ExternalSink.flush
+	if tls := params.Get(kafkaParamTLS); tls != "" {
+		if config.Net.TLS.Enable, err = strconv.ParseBool(tls); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s parameter", kafkaParamTLS)
+		}
+	}
+	for p := range params {
+		if p != kafkaParamTopicName && p != kafkaParamTLS {
+			return nil, errors.Newf("unknown %s URI parameter: %s", kafkaScheme, p)
+		}
+	}
+	producer, err := newKafkaProducer(strings.Split(u.Host, ","), config)
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to Kafka")
+	}
+	return &kafkaWriter{producer: producer, topic: topic}, nil
+}
+
+func (w *kafkaWriter) write(_ context.Context, _ string, events [][]byte) error {
+	msgs := make([]*sarama.ProducerMessage, len(events))
+	for i, event := range events {
+		msgs[i] = &sarama.ProducerMessage{
+			Topic: w.topic,
+			Value: sarama.ByteEncoder(bytes.TrimSuffix(event, []byte{'\n'})),
+		}
+	}
+	return w.producer.SendMessages(msgs)
+}
+
+func (w *kafkaWriter) Close() error {
+	return w.producer.Close()
+}
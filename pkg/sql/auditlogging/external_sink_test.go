@@ -0,0 +1,191 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package auditlogging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStorage is an in-memory cloud.ExternalStorage which only supports
+// writing files.
+type fakeStorage struct {
+	cloud.ExternalStorage
+	files map[string]string
+	fail  bool
+}
+
+type fakeWriter struct {
+	bytes.Buffer
+	name    string
+	storage *fakeStorage
+}
+
+func (w *fakeWriter) Close() error {
+	w.storage.files[w.name] = w.String()
+	return nil
+}
+
+func (s *fakeStorage) Conf() cloudpb.ExternalStorage { return cloudpb.ExternalStorage{} }
+
+func (s *fakeStorage) Writer(_ context.Context, name string) (io.WriteCloser, error) {
+	if s.fail {
+		return nil, errors.New("injected failure")
+	}
+	return &fakeWriter{name: name, storage: s}, nil
+}
+
+func (s *fakeStorage) Close() error { return nil }
+
+func TestExternalSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	storage := &fakeStorage{files: map[string]string{}}
+	sink := NewExternalSink(st, func(
+		context.Context, string, username.SQLUsername, ...cloud.ExternalStorageOption,
+	) (cloud.ExternalStorage, error) {
+		return storage, nil
+	})
+	event := func(tag string) *eventpb.SensitiveTableAccess {
+		return &eventpb.SensitiveTableAccess{
+			CommonSQLExecDetails: eventpb.CommonSQLExecDetails{ExecMode: tag},
+			TableName:            "t",
+			AccessMode:           "r",
+		}
+	}
+
+	// Events are not buffered while the sink is disabled.
+	sink.Emit(event("disabled"))
+	require.NoError(t, sink.flush(ctx))
+	require.Empty(t, storage.files)
+
+	ExternalSinkURI.Override(ctx, &st.SV, "nodelocal://1/audit")
+	sink.Emit(event("a"), event("b"))
+	require.NoError(t, sink.flush(ctx))
+	require.Len(t, storage.files, 1)
+	for _, contents := range storage.files {
+		lines := strings.Split(strings.TrimSpace(contents), "\n")
+		require.Len(t, lines, 2)
+		require.Contains(t, lines[0], `"EventType":"sensitive_table_access"`)
+		require.Contains(t, lines[0], `"ExecMode":"a"`)
+		require.Contains(t, lines[1], `"ExecMode":"b"`)
+	}
+	require.Equal(t, int64(2), sink.metrics.Emitted.Count())
+
+	// Events which fail to be shipped are kept in the buffer.
+	storage.fail = true
+	sink.Emit(event("c"))
+	require.Error(t, sink.flush(ctx))
+	require.Equal(t, int64(5), sink.metrics.FlushErrors.Count())
+	require.Len(t, sink.mu.events, 1)
+	require.Positive(t, sink.metrics.BufferedBytes.Value())
+
+	// Events are dropped when the buffer is full.
+	externalSinkBufferSize.Override(ctx, &st.SV, int64(len(sink.mu.events[0])))
+	sink.Emit(event("d"))
+	require.Equal(t, int64(1), sink.metrics.Dropped.Count())
+
+	storage.fail = false
+	require.NoError(t, sink.flush(ctx))
+	require.Len(t, storage.files, 2)
+	require.Equal(t, int64(3), sink.metrics.Emitted.Count())
+	require.Zero(t, sink.metrics.BufferedBytes.Value())
+}
+
+// fakeProducer is a sarama.SyncProducer which records the messages it sends.
+type fakeProducer struct {
+	sarama.SyncProducer
+	msgs []*sarama.ProducerMessage
+	fail bool
+}
+
+func (p *fakeProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	if p.fail {
+		return errors.New("injected failure")
+	}
+	p.msgs = append(p.msgs, msgs...)
+	return nil
+}
+
+func (p *fakeProducer) Close() error { return nil }
+
+func TestExternalSinkKafka(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	producer := &fakeProducer{}
+	var brokers []string
+	defer func(prev func([]string, *sarama.Config) (sarama.SyncProducer, error)) {
+		newKafkaProducer = prev
+	}(newKafkaProducer)
+	newKafkaProducer = func(addrs []string, config *sarama.Config) (sarama.SyncProducer, error) {
+		brokers = addrs
+		require.True(t, config.Producer.Return.Successes)
+		return producer, nil
+	}
+	sink := NewExternalSink(st, func(
+		context.Context, string, username.SQLUsername, ...cloud.ExternalStorageOption,
+	) (cloud.ExternalStorage, error) {
+		t.Fatal("unexpected external storage")
+		return nil, nil
+	})
+	event := func(tag string) *eventpb.SensitiveTableAccess {
+		return &eventpb.SensitiveTableAccess{
+			CommonSQLExecDetails: eventpb.CommonSQLExecDetails{ExecMode: tag},
+			TableName:            "t",
+			AccessMode:           "r",
+		}
+	}
+
+	ExternalSinkURI.Override(ctx, &st.SV, "kafka://broker1:9092?tls_enabled=maybe&topic_name=audit")
+	sink.Emit(event("a"))
+	require.ErrorContains(t, sink.flush(ctx), "invalid tls_enabled parameter")
+
+	ExternalSinkURI.Override(ctx, &st.SV, "kafka://broker1:9092,broker2:9092?topic_name=audit")
+	sink.Emit(event("b"))
+	require.NoError(t, sink.flush(ctx))
+	require.Equal(t, []string{"broker1:9092", "broker2:9092"}, brokers)
+	// Each event is produced as a separate message.
+	require.Len(t, producer.msgs, 2)
+	for i, tag := range []string{"a", "b"} {
+		require.Equal(t, "audit", producer.msgs[i].Topic)
+		value, err := producer.msgs[i].Value.Encode()
+		require.NoError(t, err)
+		require.Contains(t, string(value), `"ExecMode":"`+tag+`"`)
+		require.False(t, strings.HasSuffix(string(value), "\n"))
+	}
+	require.Equal(t, int64(2), sink.metrics.Emitted.Count())
+
+	// Events which fail to be produced are kept in the buffer.
+	producer.fail = true
+	sink.Emit(event("c"))
+	require.Error(t, sink.flush(ctx))
+	require.Len(t, sink.mu.events, 1)
+}
@@ -2857,7 +2857,7 @@ func columnBackfillInTxn(
 		scanBatchSize := rowinfra.RowLimit(columnBackfillBatchSize.Get(&evalCtx.Settings.SV))
 		updateChunkSizeThresholdBytes := rowinfra.BytesLimit(columnBackfillUpdateChunkSizeThresholdBytes.Get(&evalCtx.Settings.SV))
 		const alsoCommit = false
-		sp.Key, err = backfiller.RunColumnBackfillChunk(
+		sp.Key, _, err = backfiller.RunColumnBackfillChunk(
 			ctx, txn, tableDesc, sp, scanBatchSize, updateChunkSizeThresholdBytes, alsoCommit, traceKV,
 		)
 		if err != nil {
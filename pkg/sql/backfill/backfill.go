@@ -278,7 +278,8 @@ func (cb *ColumnBackfiller) Close(ctx context.Context) {
 }
 
 // RunColumnBackfillChunk runs column backfill over a chunk of the table using
-// the span sp provided, for all updateCols.
+// the span sp provided, for all updateCols. It returns the key to resume from
+// and the number of rows that were backfilled.
 func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 	ctx context.Context,
 	txn *kv.Txn,
@@ -288,7 +289,7 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 	updateChunkSizeThresholdBytes rowinfra.BytesLimit,
 	alsoCommit bool,
 	traceKV bool,
-) (roachpb.Key, error) {
+) (_ roachpb.Key, rows int64, _ error) {
 	// TODO(dan): Tighten up the bound on the requestedCols parameter to
 	// makeRowUpdater.
 	requestedCols := make([]catalog.Column, 0, len(tableDesc.PublicColumns())+len(cb.added)+len(cb.dropped))
@@ -309,7 +310,7 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 		cb.rowMetrics,
 	)
 	if err != nil {
-		return roachpb.Key{}, err
+		return roachpb.Key{}, 0, err
 	}
 
 	// TODO(dan): This check is an unfortunate bleeding of the internals of
@@ -322,7 +323,7 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 	// Update the fetcher to use the new txn.
 	if err := cb.fetcher.SetTxn(txn); err != nil {
 		log.Errorf(ctx, "scan error during SetTxn: %s", err)
-		return roachpb.Key{}, err
+		return roachpb.Key{}, 0, err
 	}
 
 	// Get the next set of rows.
@@ -339,7 +340,7 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 		chunkSize,
 	); err != nil {
 		log.Errorf(ctx, "scan error: %s", err)
-		return roachpb.Key{}, err
+		return roachpb.Key{}, 0, err
 	}
 
 	updateValues := make(tree.Datums, len(cb.updateExprs))
@@ -363,11 +364,12 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 	for i := int64(0); i < int64(chunkSize); i++ {
 		ok, err := cb.fetcher.NextRowDecodedInto(ctx, fetchedValues, cb.colIdxMap)
 		if err != nil {
-			return roachpb.Key{}, err
+			return roachpb.Key{}, 0, err
 		}
 		if !ok {
 			break
 		}
+		rows++
 
 		iv.CurSourceRow = append(iv.CurSourceRow[:0], fetchedValues...)
 
@@ -379,12 +381,12 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 				if errors.Is(err, eval.ErrNilTxnInClusterContext) {
 					// Cannot use expressions that depend on the transaction of the
 					// evaluation context as the default value for backfill.
-					return roachpb.Key{}, pgerror.WithCandidateCode(err, pgcode.FeatureNotSupported)
+					return roachpb.Key{}, 0, pgerror.WithCandidateCode(err, pgcode.FeatureNotSupported)
 				}
-				return roachpb.Key{}, sqlerrors.NewInvalidSchemaDefinitionError(err)
+				return roachpb.Key{}, 0, sqlerrors.NewInvalidSchemaDefinitionError(err)
 			}
 			if j < len(cb.added) && !cb.added[j].IsNullable() && val == tree.DNull {
-				return roachpb.Key{}, sqlerrors.NewNonNullViolationError(cb.added[j].GetName())
+				return roachpb.Key{}, 0, sqlerrors.NewNonNullViolationError(cb.added[j].GetName())
 			}
 
 			// Added computed column values should be usable for the next
@@ -404,7 +406,7 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 		if _, err := ru.UpdateRow(
 			ctx, b, oldValues, updateValues, pm, traceKV,
 		); err != nil {
-			return roachpb.Key{}, err
+			return roachpb.Key{}, 0, err
 		}
 
 		// Exit early to flush if the batch byte size exceeds a predefined
@@ -427,9 +429,9 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 		writeBatch = txn.CommitInBatch
 	}
 	if err := writeBatch(ctx, b); err != nil {
-		return roachpb.Key{}, ConvertBackfillError(ctx, tableDesc, b)
+		return roachpb.Key{}, 0, ConvertBackfillError(ctx, tableDesc, b)
 	}
-	return cb.fetcher.Key(), nil
+	return cb.fetcher.Key(), rows, nil
 }
 
 // ConvertBackfillError returns a cleaner SQL error for a failed Batch.
@@ -790,7 +792,8 @@ func (ib *IndexBackfiller) init(
 }
 
 // BuildIndexEntriesChunk reads a chunk of rows from a table using the span sp
-// provided, and builds all the added indexes.
+// provided, and builds all the added indexes. It returns the entries, the key
+// to resume from, and the number of rows that were read.
 // The method accounts for the memory used by the index entries for this chunk
 // using the memory monitor associated with ib and returns the amount of memory
 // that needs to be freed once the returned IndexEntry slice is freed.
@@ -803,7 +806,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 	sp roachpb.Span,
 	chunkSize int64,
 	traceKV bool,
-) ([]rowenc.IndexEntry, roachpb.Key, int64, error) {
+) (_ []rowenc.IndexEntry, _ roachpb.Key, rows int64, _ int64, _ error) {
 	// This ought to be chunkSize but in most tests we are actually building smaller
 	// indexes so use a smaller value.
 	const initBufferSize = 1000
@@ -813,7 +816,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 	indexEntriesInChunkInitialBufferSize :=
 		sizeOfIndexEntry * initBufferSize * int64(len(ib.added))
 	if err := ib.GrowBoundAccount(ctx, indexEntriesInChunkInitialBufferSize); err != nil {
-		return nil, nil, 0, errors.Wrap(err,
+		return nil, nil, 0, 0, errors.Wrap(err,
 			"failed to initialize empty buffer to store the index entries of all rows in the chunk")
 	}
 	memUsedPerChunk += indexEntriesInChunkInitialBufferSize
@@ -844,7 +847,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 	if err := rowenc.InitIndexFetchSpec(
 		&spec, ib.evalCtx.Codec, tableDesc, tableDesc.GetPrimaryIndex(), fetcherCols,
 	); err != nil {
-		return nil, nil, 0, err
+		return nil, nil, 0, 0, err
 	}
 	var fetcher row.Fetcher
 	if err := fetcher.Init(
@@ -858,7 +861,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 			ForceProductionKVBatchSize: ib.evalCtx.TestingKnobs.ForceProductionValues,
 		},
 	); err != nil {
-		return nil, nil, 0, err
+		return nil, nil, 0, 0, err
 	}
 	defer fetcher.Close(ctx)
 	if err := fetcher.StartScan(
@@ -867,7 +870,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 		initBufferSize,
 	); err != nil {
 		log.Errorf(ctx, "scan error: %s", err)
-		return nil, nil, 0, err
+		return nil, nil, 0, 0, err
 	}
 
 	iv := &schemaexpr.RowIndexedVarContainer{
@@ -878,7 +881,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 
 	indexEntriesPerRowInitialBufferSize := int64(len(ib.added)) * sizeOfIndexEntry
 	if err := ib.GrowBoundAccount(ctx, indexEntriesPerRowInitialBufferSize); err != nil {
-		return nil, nil, 0, errors.Wrap(err,
+		return nil, nil, 0, 0, errors.Wrap(err,
 			"failed to initialize empty buffer to store the index entries of a single row")
 	}
 	memUsedPerChunk += indexEntriesPerRowInitialBufferSize
@@ -915,10 +918,10 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 		}
 		return nil
 	}
-	for i := int64(0); i < chunkSize; i++ {
+	for ; rows < chunkSize; rows++ {
 		ok, err := fetcher.NextRowDecodedInto(ctx, ib.rowVals, ib.colIdxMap)
 		if err != nil {
-			return nil, nil, 0, err
+			return nil, nil, 0, 0, err
 		}
 		if !ok {
 			break
@@ -929,10 +932,10 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 		// may reference default values.
 		if len(ib.colExprs) > 0 {
 			if err := evaluateExprs(ib.addedCols); err != nil {
-				return nil, nil, 0, err
+				return nil, nil, 0, 0, err
 			}
 			if err := evaluateExprs(ib.computedCols); err != nil {
-				return nil, nil, 0, err
+				return nil, nil, 0, 0, err
 			}
 		}
 
@@ -954,7 +957,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 
 				val, err := eval.Expr(ctx, ib.evalCtx, texpr)
 				if err != nil {
-					return nil, nil, 0, err
+					return nil, nil, 0, 0, err
 				}
 
 				if val == tree.DBoolTrue {
@@ -988,7 +991,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 			)
 		}(buffer)
 		if err != nil {
-			return nil, nil, 0, err
+			return nil, nil, 0, 0, err
 		}
 		memUsedPerChunk += memUsedDuringEncoding
 
@@ -999,7 +1002,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 		if cap(entries)-len(entries) < len(buffer) {
 			resliceSize := sizeOfIndexEntry * int64(cap(entries))
 			if err := ib.GrowBoundAccount(ctx, resliceSize); err != nil {
-				return nil, nil, 0, err
+				return nil, nil, 0, 0, err
 			}
 			memUsedPerChunk += resliceSize
 		}
@@ -1018,7 +1021,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 		resumeKey = make(roachpb.Key, len(fetcher.Key()))
 		copy(resumeKey, fetcher.Key())
 	}
-	return entries, resumeKey, memUsedPerChunk, nil
+	return entries, resumeKey, rows, memUsedPerChunk, nil
 }
 
 // RunIndexBackfillChunk runs an index backfill over a chunk of the table
@@ -1033,7 +1036,7 @@ func (ib *IndexBackfiller) RunIndexBackfillChunk(
 	alsoCommit bool,
 	traceKV bool,
 ) (roachpb.Key, error) {
-	entries, key, memUsedBuildingChunk, err := ib.BuildIndexEntriesChunk(
+	entries, key, _, memUsedBuildingChunk, err := ib.BuildIndexEntriesChunk(
 		ctx, txn, tableDesc, sp, chunkSize, traceKV,
 	)
 	if err != nil {
@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
 )
@@ -206,21 +207,33 @@ func (ibm *IndexBackfillMerger) Run(ctx context.Context, output execinfra.RowRec
 
 	g.GoCtx(func(ctx context.Context) error {
 		defer close(mergeCh)
+		pacer := NewAdaptivePacer(
+			&ibm.evalCtx.Settings.SV, ibm.flowCtx.Cfg.TenantCostController,
+			indexBackfillMergeBatchSize.Get(&ibm.evalCtx.Settings.SV),
+		)
 		for i := range ibm.spec.Spans {
 			sp := ibm.spec.Spans[i]
 			idx := ibm.spec.SpanIdx[i]
 
 			key := sp.Key
 			for key != nil {
-				chunk, nextKey, err := ibm.scan(ctx, idx, key, sp.EndKey, mergeTimestamp)
+				if err := pacer.Pace(ctx); err != nil {
+					return err
+				}
+				start := timeutil.Now()
+				chunk, nextKey, err := ibm.scan(ctx, idx, key, sp.EndKey, mergeTimestamp, pacer.ChunkSize())
 				if err != nil {
 					return err
 				}
+				rows := int64(len(chunk.keys))
 				select {
 				case <-ctx.Done():
 					return ctx.Err()
 				case mergeCh <- chunk:
 				}
+				// The time to hand the chunk to a merge worker is included, so that
+				// the pacer also backs off when the merges are being queued.
+				pacer.RecordChunk(ctx, rows, timeutil.Since(start))
 				key = nextKey
 
 				if knobs, ok := ibm.flowCtx.Cfg.TestingKnobs.IndexBackfillMergerTestingKnobs.(*IndexBackfillMergerTestingKnobs); ok {
@@ -267,6 +280,7 @@ func (ibm *IndexBackfillMerger) scan(
 	startKey roachpb.Key,
 	endKey roachpb.Key,
 	readAsOf hlc.Timestamp,
+	chunkSize int64,
 ) (mergeChunk, roachpb.Key, error) {
 	if knobs, ok := ibm.flowCtx.Cfg.TestingKnobs.IndexBackfillMergerTestingKnobs.(*IndexBackfillMergerTestingKnobs); ok {
 		if knobs != nil && knobs.RunBeforeScanChunk != nil {
@@ -275,7 +289,6 @@ func (ibm *IndexBackfillMerger) scan(
 			}
 		}
 	}
-	chunkSize := indexBackfillMergeBatchSize.Get(&ibm.evalCtx.Settings.SV)
 	chunkBytes := indexBackfillMergeBatchBytes.Get(&ibm.evalCtx.Settings.SV)
 
 	var br *kvpb.BatchResponse
@@ -0,0 +1,155 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package backfill
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// adaptivePacingEnabled controls whether index backfills, index merges and
+// column backfills in virtual clusters use an AdaptivePacer.
+var adaptivePacingEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"bulkio.index_backfill.adaptive_pacing.enabled",
+	"if true, index backfills, index merges and column backfills in virtual "+
+		"clusters adapt the number of rows they process per batch to the admission "+
+		"control queueing they observe and to the request units available to the "+
+		"virtual cluster",
+	true,
+	settings.WithPublic,
+)
+
+const (
+	// adaptivePacerMinChunkSizeDivisor bounds how small the AdaptivePacer makes
+	// chunks, relative to the configured chunk size.
+	adaptivePacerMinChunkSizeDivisor = 32
+	// adaptivePacerIncreaseDivisor determines by how much the AdaptivePacer grows
+	// chunks after each chunk that didn't queue, relative to the configured
+	// chunk size.
+	adaptivePacerIncreaseDivisor = 16
+	// adaptivePacerQueueingFactor is the factor by which the latency per row of a
+	// chunk has to exceed the baseline latency per row for the AdaptivePacer to
+	// consider that the chunk was queued by admission control.
+	adaptivePacerQueueingFactor = 2
+	// adaptivePacerBaselineAlpha is the weight of the latest chunk in the
+	// exponentially weighted moving average of the latency per row that serves as
+	// the baseline. It is small enough that about ten consecutive chunks have to
+	// be queued before their latency is accepted as the new baseline.
+	adaptivePacerBaselineAlpha = 0.05
+)
+
+// AdaptivePacer paces the chunks of a backfill run by a virtual cluster, so
+// that the backfill backs off when the cluster is overloaded or the virtual
+// cluster is running out of request units, instead of being paced only by
+// the static bulk I/O settings of the host cluster.
+//
+// It adapts the number of rows per chunk with an additive-increase,
+// multiplicative-decrease scheme. The chunk size is halved whenever a chunk
+// takes much longer per row than the moving average of the previous chunks,
+// which is a sign that its requests were queued by admission control, or when
+// the virtual cluster is throttled because it exhausted its request units.
+// Otherwise, the chunk size grows back towards the configured one. The moving
+// average follows lasting changes of the cost of the rows, e.g. because they
+// get wider in a part of the table, so that they aren't mistaken for queueing
+// forever. Additionally, chunks aren't started while the virtual cluster is
+// low on request units.
+//
+// It is used by the index backfiller, the index backfill merger and the column
+// backfiller, which serve both the legacy and the declarative schema changers.
+//
+// An AdaptivePacer is not safe for concurrent use.
+type AdaptivePacer struct {
+	costController multitenant.TenantSideCostController
+
+	maxChunkSize int64
+	minChunkSize int64
+	chunkSize    int64
+	// baselineRowLatency is the exponentially weighted moving average of the
+	// latency per row of the chunks so far, or zero before the first chunk.
+	baselineRowLatency time.Duration
+}
+
+// NewAdaptivePacer returns an AdaptivePacer for chunks of at most
+// maxChunkSize rows. If costController is nil, i.e. outside of virtual
+// clusters, or adaptive pacing is disabled, the AdaptivePacer always uses
+// maxChunkSize and never waits.
+func NewAdaptivePacer(
+	sv *settings.Values, costController multitenant.TenantSideCostController, maxChunkSize int64,
+) *AdaptivePacer {
+	p := &AdaptivePacer{
+		maxChunkSize: maxChunkSize,
+		chunkSize:    maxChunkSize,
+	}
+	if costController == nil || !adaptivePacingEnabled.Get(sv) || maxChunkSize <= 0 {
+		return p
+	}
+	p.costController = costController
+	p.minChunkSize = maxChunkSize / adaptivePacerMinChunkSizeDivisor
+	if p.minChunkSize < 1 {
+		p.minChunkSize = 1
+	}
+	return p
+}
+
+// ChunkSize returns the number of rows to process in the next chunk.
+func (p *AdaptivePacer) ChunkSize() int64 {
+	return p.chunkSize
+}
+
+// Pace blocks for as long as the virtual cluster is low on request units. It
+// should be called before processing each chunk.
+func (p *AdaptivePacer) Pace(ctx context.Context) error {
+	if p.costController == nil {
+		return nil
+	}
+	return p.costController.OnBackgroundWorkWait(ctx)
+}
+
+// RecordChunk adjusts the chunk size after a chunk of the given number of table
+// rows was processed in the given time.
+func (p *AdaptivePacer) RecordChunk(ctx context.Context, rows int64, latency time.Duration) {
+	if p.costController == nil || rows <= 0 {
+		return
+	}
+	rowLatency := latency / time.Duration(rows)
+	baseline := p.baselineRowLatency
+	if baseline == 0 {
+		baseline = rowLatency
+	}
+	p.baselineRowLatency = time.Duration(
+		adaptivePacerBaselineAlpha*float64(rowLatency) + (1-adaptivePacerBaselineAlpha)*float64(baseline))
+
+	prev := p.chunkSize
+	if p.costController.IsThrottled(ctx) || rowLatency > adaptivePacerQueueingFactor*baseline {
+		p.chunkSize /= 2
+		if p.chunkSize < p.minChunkSize {
+			p.chunkSize = p.minChunkSize
+		}
+	} else {
+		increase := p.maxChunkSize / adaptivePacerIncreaseDivisor
+		if increase < 1 {
+			increase = 1
+		}
+		p.chunkSize += increase
+		if p.chunkSize > p.maxChunkSize {
+			p.chunkSize = p.maxChunkSize
+		}
+	}
+	if p.chunkSize != prev {
+		log.VEventf(ctx, 2, "backfill chunk size changed from %d to %d (latency per row %s, baseline %s)",
+			prev, p.chunkSize, rowLatency, baseline)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package backfill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCostController struct {
+	multitenant.TenantSideCostController
+	throttled bool
+}
+
+func (c *fakeCostController) IsThrottled(context.Context) bool {
+	return c.throttled
+}
+
+func TestAdaptivePacer(t *testing.T) {
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+
+	t.Run("system tenant", func(t *testing.T) {
+		p := NewAdaptivePacer(&st.SV, nil /* costController */, 1600)
+		require.NoError(t, p.Pace(ctx))
+		p.RecordChunk(ctx, 1600, time.Hour)
+		require.Equal(t, int64(1600), p.ChunkSize())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		st := cluster.MakeTestingClusterSettings()
+		adaptivePacingEnabled.Override(ctx, &st.SV, false)
+		p := NewAdaptivePacer(&st.SV, &fakeCostController{throttled: true}, 1600)
+		p.RecordChunk(ctx, 1600, time.Second)
+		require.Equal(t, int64(1600), p.ChunkSize())
+	})
+
+	t.Run("queueing", func(t *testing.T) {
+		p := NewAdaptivePacer(&st.SV, &fakeCostController{}, 1600)
+		// Establish a baseline of 1ms per row.
+		p.RecordChunk(ctx, 1600, 1600*time.Millisecond)
+		require.Equal(t, int64(1600), p.ChunkSize())
+
+		// Chunks that take much longer per row shrink the chunk size, down to
+		// the minimum.
+		p.RecordChunk(ctx, 1600, 5*1600*time.Millisecond)
+		require.Equal(t, int64(800), p.ChunkSize())
+		for i := 0; i < 5; i++ {
+			p.RecordChunk(ctx, p.ChunkSize(), 5*time.Duration(p.ChunkSize())*time.Millisecond)
+		}
+		require.Equal(t, int64(50), p.ChunkSize())
+
+		// Once the latency is back to normal, the chunk size grows back
+		// additively, up to the configured one.
+		p.RecordChunk(ctx, 50, 50*time.Millisecond)
+		require.Equal(t, int64(150), p.ChunkSize())
+		for i := 0; i < 20; i++ {
+			p.RecordChunk(ctx, p.ChunkSize(), time.Duration(p.ChunkSize())*time.Millisecond)
+		}
+		require.Equal(t, int64(1600), p.ChunkSize())
+	})
+
+	t.Run("baseline", func(t *testing.T) {
+		p := NewAdaptivePacer(&st.SV, &fakeCostController{}, 1600)
+		p.RecordChunk(ctx, 1600, 1600*time.Millisecond)
+
+		// A single unusually fast chunk doesn't make the following chunks look
+		// queued.
+		p.RecordChunk(ctx, 1600, 160*time.Millisecond)
+		for i := 0; i < 5; i++ {
+			p.RecordChunk(ctx, 1600, 1600*time.Millisecond)
+			require.Equal(t, int64(1600), p.ChunkSize())
+		}
+
+		// A lasting increase of the latency per row is eventually accepted as
+		// the new baseline, and the chunk size grows back.
+		p.RecordChunk(ctx, 1600, 3*1600*time.Millisecond)
+		require.Equal(t, int64(800), p.ChunkSize())
+		for i := 0; i < 30; i++ {
+			p.RecordChunk(ctx, p.ChunkSize(), 3*time.Duration(p.ChunkSize())*time.Millisecond)
+		}
+		require.Equal(t, int64(1600), p.ChunkSize())
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		c := &fakeCostController{}
+		p := NewAdaptivePacer(&st.SV, c, 1600)
+		c.throttled = true
+		p.RecordChunk(ctx, 1600, time.Second)
+		require.Equal(t, int64(800), p.ChunkSize())
+		c.throttled = false
+		p.RecordChunk(ctx, 800, time.Second/2)
+		require.Equal(t, int64(900), p.ChunkSize())
+	})
+}
@@ -0,0 +1,229 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package cachewarmup persists the set of hot SQL catalog and statistics
+// cache entries when a node drains, and pre-populates those caches from the
+// persisted set when the node starts up again. This reduces the latency
+// degradation observed in the first minutes after a (rolling) restart, during
+// which every query would otherwise pay for descriptor lease acquisitions and
+// statistics lookups.
+package cachewarmup
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+// Enabled controls whether the cache warm-up state is persisted on drain and
+// used to pre-warm the caches on startup.
+var Enabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.cache_warmup.enabled",
+	"if set, hot descriptor and table statistics cache entries are persisted "+
+		"when a node drains and pre-warmed when it starts up again",
+	true,
+)
+
+// MaxEntries bounds the number of descriptors and the number of tables with
+// statistics that are persisted and pre-warmed.
+var MaxEntries = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.cache_warmup.max_entries",
+	"maximum number of descriptors and of table statistics that are "+
+		"persisted on drain and pre-warmed on startup",
+	1000,
+	settings.NonNegativeInt,
+)
+
+const warmupDir = "sql-cache-warmup"
+const warmupFileName = "warmup.json"
+
+// Snapshot is the persisted set of cache entries to pre-warm.
+type Snapshot struct {
+	// DescriptorIDs are the IDs of the descriptors that were leased.
+	DescriptorIDs []descpb.ID `json:"descriptor_ids,omitempty"`
+	// StatsTableIDs are the IDs of the tables whose statistics were cached,
+	// most recently used first.
+	StatsTableIDs []descpb.ID `json:"stats_table_ids,omitempty"`
+}
+
+// Empty returns true if the snapshot has nothing to warm up.
+func (s Snapshot) Empty() bool {
+	return len(s.DescriptorIDs) == 0 && len(s.StatsTableIDs) == 0
+}
+
+// Store persists a Snapshot in a single file on the node's first store.
+type Store struct {
+	path string
+	fs   vfs.FS
+}
+
+// NewStore creates a warm-up store rooted at the given path.
+func NewStore(path string, fs vfs.FS) *Store {
+	return &Store{
+		path: fs.PathJoin(path, warmupDir),
+		fs:   fs,
+	}
+}
+
+// Save persists the snapshot, replacing any previously saved one.
+func (s *Store) Save(snap Snapshot) error {
+	if err := s.fs.MkdirAll(s.path, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create cache warm-up directory %s", s.path)
+	}
+	fileName := s.fs.PathJoin(s.path, warmupFileName)
+	tmpFileName := fileName + ".tmp"
+	defer func() { _ = s.fs.Remove(tmpFileName) }()
+
+	out, err := json.Marshal(snap)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cache warm-up state")
+	}
+	if err := func() error {
+		f, err := s.fs.Create(tmpFileName, fs.UnspecifiedWriteCategory)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create file %q", tmpFileName)
+		}
+		defer func() { _ = f.Close() }()
+		if _, err := f.Write(out); err != nil {
+			return errors.Wrap(err, "failed to write cache warm-up state")
+		}
+		return errors.Wrap(f.Sync(), "failed to sync cache warm-up state")
+	}(); err != nil {
+		return err
+	}
+	return errors.Wrap(s.fs.Rename(tmpFileName, fileName), "failed to rename cache warm-up file")
+}
+
+// Load reads the persisted snapshot and removes it, so that it is only used
+// for the first startup following the drain that saved it; a later restart
+// that was not preceded by a drain, e.g. after a crash, would otherwise
+// pre-warm stale entries. The returned bool is false if no snapshot has been
+// saved since the last Load.
+func (s *Store) Load() (Snapshot, bool, error) {
+	fileName := s.fs.PathJoin(s.path, warmupFileName)
+	data, err := func() ([]byte, error) {
+		f, err := s.fs.Open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		return io.ReadAll(f)
+	}()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Snapshot{}, false, nil
+		}
+		return Snapshot{}, false, errors.Wrapf(err, "failed to read cache warm-up file %q", fileName)
+	}
+	if err := s.fs.Remove(fileName); err != nil {
+		return Snapshot{}, false, errors.Wrapf(err, "failed to remove cache warm-up file %q", fileName)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, false, errors.Wrapf(err, "failed to unmarshal cache warm-up file %q", fileName)
+	}
+	return snap, true, nil
+}
+
+// Collect captures the currently hot descriptors and table statistics, each
+// bounded by limit.
+func Collect(lm *lease.Manager, sc *stats.TableStatisticsCache, limit int) Snapshot {
+	var snap Snapshot
+	seen := make(map[descpb.ID]struct{})
+	lm.VisitLeases(func(
+		desc catalog.Descriptor, takenOffline bool, _ int, _ tree.DTimestamp,
+	) (wantMore bool) {
+		if len(snap.DescriptorIDs) >= limit {
+			return false
+		}
+		if _, ok := seen[desc.GetID()]; ok || takenOffline || desc.Dropped() {
+			return true
+		}
+		seen[desc.GetID()] = struct{}{}
+		snap.DescriptorIDs = append(snap.DescriptorIDs, desc.GetID())
+		return true
+	})
+	snap.StatsTableIDs = sc.CachedTableIDs(limit)
+	return snap
+}
+
+// Warm acquires leases on the descriptors in the snapshot and loads the
+// statistics of the tables in the snapshot into the statistics cache. Errors
+// are not fatal: descriptors may have been dropped in the meantime, in which
+// case they are simply skipped.
+func Warm(
+	ctx context.Context,
+	clock *hlc.Clock,
+	lm *lease.Manager,
+	sc *stats.TableStatisticsCache,
+	snap Snapshot,
+	limit int,
+) {
+	descIDs, statsIDs := snap.DescriptorIDs, snap.StatsTableIDs
+	if len(descIDs) > limit {
+		descIDs = descIDs[:limit]
+	}
+	if len(statsIDs) > limit {
+		statsIDs = statsIDs[:limit]
+	}
+	warmStats := make(map[descpb.ID]struct{}, len(statsIDs))
+	for _, id := range statsIDs {
+		warmStats[id] = struct{}{}
+	}
+	// Tables with cached statistics are most likely also leased, but make sure
+	// to visit them even if they are not.
+	ids := make([]descpb.ID, 0, len(descIDs)+len(statsIDs))
+	ids = append(ids, descIDs...)
+	ids = append(ids, statsIDs...)
+
+	var numDescs, numStats int
+	visited := make(map[descpb.ID]struct{}, len(ids))
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		visited[id] = struct{}{}
+		desc, err := lm.Acquire(ctx, clock.Now(), id)
+		if err != nil {
+			log.VEventf(ctx, 2, "skipping warm-up of descriptor %d: %v", id, err)
+			continue
+		}
+		numDescs++
+		if _, ok := warmStats[id]; ok {
+			if tbl, ok := desc.Underlying().(catalog.TableDescriptor); ok {
+				if _, err := sc.GetTableStats(ctx, tbl); err != nil {
+					log.VEventf(ctx, 2, "skipping warm-up of statistics for table %d: %v", id, err)
+				} else {
+					numStats++
+				}
+			}
+		}
+		desc.Release(ctx)
+	}
+	log.Infof(ctx, "pre-warmed %d descriptors and statistics of %d tables", numDescs, numStats)
+}
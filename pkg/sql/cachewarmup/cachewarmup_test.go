@@ -0,0 +1,57 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cachewarmup
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSaveLoad(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s := NewStore("data", vfs.NewMem())
+
+	// Nothing is found before the first save.
+	_, found, err := s.Load()
+	require.NoError(t, err)
+	require.False(t, found)
+
+	snap := Snapshot{
+		DescriptorIDs: []descpb.ID{104, 105, 106},
+		StatsTableIDs: []descpb.ID{106, 104},
+	}
+	require.NoError(t, s.Save(snap))
+	loaded, found, err := s.Load()
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, snap, loaded)
+
+	// The state is removed once loaded, so that it is not used again by a
+	// restart that was not preceded by a drain.
+	_, found, err = s.Load()
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// A subsequent save replaces the previous state.
+	require.NoError(t, s.Save(snap))
+	require.NoError(t, s.Save(Snapshot{}))
+	loaded, found, err = s.Load()
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, loaded.Empty())
+}
@@ -327,6 +327,32 @@ var TenantColumnsWithPriorReplication = ResultColumns{
 	{Name: "activation_time", Typ: types.Decimal},
 }
 
+// TenantColumnsWithConsumption is appended to TenantColumns and
+// TenantColumnsNoReplication for SHOW VIRTUAL CLUSTER ... WITH CONSUMPTION
+// queries.
+var TenantColumnsWithConsumption = ResultColumns{
+	// The state of the token bucket.
+	{Name: "ru_available", Typ: types.Float},
+	{Name: "ru_refill_rate", Typ: types.Float},
+	{Name: "ru_burst_limit", Typ: types.Float},
+	// The cumulative consumption.
+	{Name: "ru_consumed", Typ: types.Float},
+	{Name: "kv_ru_consumed", Typ: types.Float},
+	{Name: "read_bytes", Typ: types.Int},
+	{Name: "write_bytes", Typ: types.Int},
+	{Name: "sql_pods_cpu_seconds", Typ: types.Float},
+	{Name: "pgwire_egress_bytes", Typ: types.Int},
+	// The storage used by the tenant, as last measured by the host cluster.
+	{Name: "live_bytes", Typ: types.Int},
+	{Name: "total_bytes", Typ: types.Int},
+	{Name: "last_update", Typ: types.TimestampTZ},
+	// The recent consumption rate and throttling events, as observed by the
+	// node serving the query.
+	{Name: "ru_per_second", Typ: types.Float},
+	{Name: "recent_throttle_events", Typ: types.Int},
+	{Name: "last_throttled", Typ: types.TimestampTZ},
+}
+
 // TenantColumnsWithCapabilities is appended to TenantColumns and
 // TenantColumnsNoReplication for SHOW VIRTUAL CLUSTER ... WITH CAPABILITIES
 // queries.
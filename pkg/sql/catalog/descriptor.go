@@ -12,6 +12,7 @@ package catalog
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -352,6 +353,10 @@ type TableDescriptor interface {
 	// created at, for materialized views and CREATE TABLE AS. Only valid if
 	// IsAs or MaterializedView returns true.
 	GetCreateAsOfTime() hlc.Timestamp
+	// GetMaterializedViewDataAsOf returns the timestamp as of which the data
+	// stored by a materialized view reflects its query, or an empty timestamp
+	// if unknown. Only valid if MaterializedView returns true.
+	GetMaterializedViewDataAsOf() hlc.Timestamp
 
 	// GetViewQuery returns this view's CREATE VIEW declaration. Only valid if
 	// IsView is true.
@@ -721,6 +726,9 @@ type TableDescriptor interface {
 	// GetExcludeDataFromBackup returns true if the table's row data is configured
 	// to be excluded during backup.
 	GetExcludeDataFromBackup() bool
+	// GetClosedTimestampTargetDuration returns the closed timestamp target
+	// duration configured for the table's ranges, or zero if unset.
+	GetClosedTimestampTargetDuration() time.Duration
 	// GetStorageParams returns a list of storage parameters for the table.
 	GetStorageParams(spaceBetweenEqual bool) []string
 	// NoAutoStatsSettingsOverrides is true if no auto stats related settings are
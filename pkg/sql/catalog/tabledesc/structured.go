@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
@@ -2375,6 +2376,11 @@ func (desc *wrapper) GetExcludeDataFromBackup() bool {
 	return desc.ExcludeDataFromBackup
 }
 
+// GetClosedTimestampTargetDuration implements the TableDescriptor interface.
+func (desc *wrapper) GetClosedTimestampTargetDuration() time.Duration {
+	return desc.ClosedTimestampTargetDuration
+}
+
 // GetStorageParams implements the TableDescriptor interface.
 func (desc *wrapper) GetStorageParams(spaceBetweenEqual bool) []string {
 	var storageParams []string
@@ -2426,6 +2432,9 @@ func (desc *wrapper) GetStorageParams(spaceBetweenEqual bool) []string {
 	if exclude := desc.GetExcludeDataFromBackup(); exclude {
 		appendStorageParam(`exclude_data_from_backup`, `true`)
 	}
+	if d := desc.GetClosedTimestampTargetDuration(); d != 0 {
+		appendStorageParam(`closed_timestamp_target_duration`, fmt.Sprintf(`'%s'`, d.String()))
+	}
 	if settings := desc.AutoStatsSettings; settings != nil {
 		if settings.Enabled != nil {
 			value := *settings.Enabled
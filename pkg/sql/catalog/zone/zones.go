@@ -98,6 +98,13 @@ func init() {
 				c.GC = &zonepb.GCPolicy{TTLSeconds: int32(tree.MustBeDInt(d))}
 			},
 		},
+		{
+			Field:        config.ProtectedTimestampMaxAge,
+			RequiredType: types.Int,
+			Setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.ProtectedTimestampMaxAgeSeconds = proto.Int32(int32(tree.MustBeDInt(d)))
+			},
+		},
 		{
 			Field:        config.Constraints,
 			RequiredType: types.String,
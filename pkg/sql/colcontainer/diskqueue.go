@@ -15,14 +15,17 @@ import (
 	"context"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/colserde"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
@@ -183,7 +186,7 @@ type diskQueue struct {
 	// written before a compress and flush.
 	writeBufferLimit  int
 	writeFileIdx      int
-	writeFile         vfs.File
+	writeFile         io.WriteCloser
 	deserializerState struct {
 		*colserde.FileDeserializer
 		curBatch int
@@ -191,15 +194,132 @@ type diskQueue struct {
 	// readFileIdx is an index into the current file in files the deserializer is
 	// reading from.
 	readFileIdx                  int
-	readFile                     vfs.File
+	readFile                     readAtCloser
 	scratchDecompressedReadBytes []byte
 
+	// externalStorage, if set, stores the files instead of cfg.FS.
+	externalStorage cloud.ExternalStorage
+
+	// diskAcc is the account that the size of the files is accounted against.
+	// When the files are stored in external storage, it is an account of
+	// cfg.ExternalStorageMonitor owned by the queue, if the monitor is set.
 	diskAcc         *mon.BoundAccount
+	ownsDiskAcc     bool
 	converterMemAcc *mon.BoundAccount
+	// externalBufferAcc is the account that the buffer of the file being
+	// written to is accounted against when the files are stored in external
+	// storage. It is an account of cfg.ExternalWriteBufferMonitor owned by the
+	// queue if the monitor is set, and converterMemAcc otherwise.
+	externalBufferAcc     *mon.BoundAccount
+	ownsExternalBufferAcc bool
 }
 
 var _ RewindableQueue = &diskQueue{}
 
+// readAtCloser is the interface of the files that the diskQueue reads from.
+type readAtCloser interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// externalWriteFile is the file of a diskQueue that is being written to when
+// the files are stored in external storage. Since external storage generally
+// doesn't allow reading a file before it has been fully written, the file is
+// buffered in memory and only uploaded when it is closed, i.e. when the
+// diskQueue rotates it after it reached the maximum file size or when no more
+// data will be enqueued. The regions that were flushed in the meantime can be
+// read from the buffer, which allows the diskQueue to interleave Enqueue and
+// Dequeue calls without uploading a file for each of them.
+type externalWriteFile struct {
+	ctx     context.Context
+	storage cloud.ExternalStorage
+	name    string
+	memAcc  *mon.BoundAccount
+	buf     []byte
+	closed  bool
+}
+
+var _ io.WriteCloser = &externalWriteFile{}
+
+// Write implements the io.Writer interface.
+func (f *externalWriteFile) Write(p []byte) (int, error) {
+	if err := f.memAcc.Grow(f.ctx, int64(len(p))); err != nil {
+		return 0, err
+	}
+	f.buf = append(f.buf, p...)
+	return len(p), nil
+}
+
+// readAt reads from the data written so far. It must not be called once the
+// file is closed.
+func (f *externalWriteFile) readAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(f.buf)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.buf[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close implements the io.Closer interface. It uploads the file and releases
+// the buffer.
+func (f *externalWriteFile) Close() error {
+	if f.closed {
+		return nil
+	}
+	err := cloud.WriteFile(f.ctx, f.storage, f.name, bytes.NewReader(f.buf))
+	f.discard()
+	return err
+}
+
+// discard releases the buffer without uploading the file.
+func (f *externalWriteFile) discard() {
+	f.closed = true
+	f.memAcc.Shrink(f.ctx, int64(len(f.buf)))
+	f.buf = nil
+}
+
+// externalReadFile is a readAtCloser for a file of a diskQueue that is stored
+// in external storage. Each ReadAt results in a ranged read of the file, which
+// is fine since the diskQueue reads whole regions of compressed batches at
+// once. If the file was still being written to when it was opened, reads are
+// served by the externalWriteFile until it is uploaded.
+type externalReadFile struct {
+	ctx       context.Context
+	storage   cloud.ExternalStorage
+	name      string
+	writeFile *externalWriteFile
+}
+
+var _ readAtCloser = &externalReadFile{}
+
+// ReadAt implements the io.ReaderAt interface.
+func (f *externalReadFile) ReadAt(p []byte, off int64) (int, error) {
+	if f.writeFile != nil && !f.writeFile.closed {
+		return f.writeFile.readAt(p, off)
+	}
+	r, _, err := f.storage.ReadFile(f.ctx, f.name, cloud.ReadOptions{
+		Offset:     off,
+		LengthHint: int64(len(p)),
+		NoFileSize: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(ioctx.ReaderCtxAdapter(f.ctx, r), p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, errors.CombineErrors(err, r.Close(f.ctx))
+}
+
+// Close implements the io.Closer interface.
+func (f *externalReadFile) Close() error {
+	return nil
+}
+
 // Queue describes a simple queue interface to which coldata.Batches can be
 // Enqueued and Dequeued.
 type Queue interface {
@@ -243,6 +363,11 @@ const (
 	// DiskQueue rolls over to a new file. This value was chosen by running
 	// BenchmarkQueue.
 	defaultMaxFileSizeBytes = 32 << 20 /* 32 MiB */
+	// externalMaxFileSizeBytes is the maximum size of the files stored in
+	// external storage. It is lower than defaultMaxFileSizeBytes since the file
+	// being written to is buffered in memory, and is in the range of the chunk
+	// sizes that cloud storage writers buffer anyway.
+	externalMaxFileSizeBytes = 8 << 20 /* 8 MiB */
 )
 
 // DiskQueueCacheMode specifies a pattern that a DiskQueue should use regarding
@@ -301,6 +426,23 @@ func GetPatherFunc(f func(ctx context.Context) string) GetPather {
 type DiskQueueCfg struct {
 	// FS is the filesystem interface to use.
 	FS vfs.FS
+	// GetExternalStorage, if set, returns the external storage that is used
+	// instead of FS to store the DiskQueue's files. This allows spilling when
+	// the local temporary storage is too small. It is called when the DiskQueue
+	// is created, which allows the external storage to be opened lazily. See
+	// externalWriteFile for more details.
+	GetExternalStorage func(context.Context) (cloud.ExternalStorage, error)
+	// ExternalStorageMonitor, if set, is the monitor that the size of the files
+	// stored in external storage is accounted against, instead of the disk
+	// account passed to the DiskQueue, which usually accounts for the local
+	// temporary storage.
+	ExternalStorageMonitor *mon.BytesMonitor
+	// ExternalWriteBufferMonitor, if set, is the memory monitor that the
+	// in-memory buffer of the file being written to external storage (up to
+	// externalMaxFileSizeBytes per DiskQueue) is accounted against. Otherwise,
+	// it is accounted against the converter memory account passed to the
+	// DiskQueue.
+	ExternalWriteBufferMonitor *mon.BytesMonitor
 	// GetPather returns where the temporary directory that will contain this
 	// DiskQueue's files has been created. The directory name will be a UUID.
 	// Note that the directory is created lazily on the first call to GetPath.
@@ -413,7 +555,29 @@ func newDiskQueue(
 	if d.cfg.CacheMode != DiskQueueCacheModeIntertwinedCalls {
 		d.writeBufferLimit = d.cfg.BufferSizeBytes / 2
 	}
-	if err := cfg.FS.MkdirAll(filepath.Join(cfg.GetPather.GetPath(ctx), d.dirName), os.ModePerm); err != nil {
+	if cfg.GetExternalStorage != nil {
+		var err error
+		if d.externalStorage, err = cfg.GetExternalStorage(ctx); err != nil {
+			return nil, err
+		}
+		// The file being written to is buffered in memory, so cap its size.
+		if d.cfg.MaxFileSizeBytes > externalMaxFileSizeBytes {
+			d.cfg.MaxFileSizeBytes = externalMaxFileSizeBytes
+		}
+		if cfg.ExternalStorageMonitor != nil {
+			acc := cfg.ExternalStorageMonitor.MakeBoundAccount()
+			d.diskAcc = &acc
+			d.ownsDiskAcc = true
+		}
+		d.externalBufferAcc = converterMemAcc
+		if cfg.ExternalWriteBufferMonitor != nil {
+			acc := cfg.ExternalWriteBufferMonitor.MakeBoundAccount()
+			d.externalBufferAcc = &acc
+			d.ownsExternalBufferAcc = true
+		}
+	} else if err := cfg.FS.MkdirAll(
+		filepath.Join(cfg.GetPather.GetPath(ctx), d.dirName), os.ModePerm,
+	); err != nil {
 		return nil, err
 	}
 	// rotateFile will create a new file to write to.
@@ -449,6 +613,12 @@ func (d *diskQueue) Close(ctx context.Context) (retErr error) {
 				retErr = errors.CombineErrors(retErr, err)
 			}
 		}
+		if d.ownsDiskAcc {
+			d.diskAcc.Close(ctx)
+		}
+		if d.ownsExternalBufferAcc {
+			d.externalBufferAcc.Close(ctx)
+		}
 		// Zero out the structure completely upon return. If users of this diskQueue
 		// retain a pointer to it, and we don't remove all references to large
 		// backing slices (various scratch spaces in this struct and children),
@@ -475,14 +645,22 @@ func (d *diskQueue) Close(ctx context.Context) (retErr error) {
 	if err := d.CloseRead(); err != nil {
 		return err
 	}
-	if err := d.cfg.FS.RemoveAll(filepath.Join(d.cfg.GetPather.GetPath(ctx), d.dirName)); err != nil {
-		return err
-	}
-	totalSize := int64(0)
 	leftOverFileIdx := 0
 	if !d.rewindable {
 		leftOverFileIdx = d.readFileIdx
 	}
+	if d.externalStorage != nil {
+		for _, file := range d.files[leftOverFileIdx:] {
+			if err := d.externalStorage.Delete(ctx, file.name); err != nil {
+				return err
+			}
+		}
+	} else if err := d.cfg.FS.RemoveAll(
+		filepath.Join(d.cfg.GetPather.GetPath(ctx), d.dirName),
+	); err != nil {
+		return err
+	}
+	totalSize := int64(0)
 	for _, file := range d.files[leftOverFileIdx : d.writeFileIdx+1] {
 		totalSize += int64(file.totalSize)
 	}
@@ -493,6 +671,14 @@ func (d *diskQueue) Close(ctx context.Context) (retErr error) {
 	return nil
 }
 
+// removeFile removes the file with the given name, which must have been closed.
+func (d *diskQueue) removeFile(ctx context.Context, name string) error {
+	if d.externalStorage != nil {
+		return d.externalStorage.Delete(ctx, name)
+	}
+	return d.cfg.FS.Remove(name)
+}
+
 // rotateFile performs file rotation for the diskQueue. i.e. it creates a new
 // file to write to and sets the diskQueue state up to write to that file when
 // Enqueue is called.
@@ -500,8 +686,20 @@ func (d *diskQueue) Close(ctx context.Context) (retErr error) {
 // any file (i.e. during initialization). This will simply create the first file
 // to write to.
 func (d *diskQueue) rotateFile(ctx context.Context) (retErr error) {
-	fName := filepath.Join(d.cfg.GetPather.GetPath(ctx), d.dirName, strconv.Itoa(d.seqNo))
-	f, err := fs.CreateWithSync(d.cfg.FS, fName, bytesPerSync, fs.SQLColumnSpillWriteCategory)
+	var fName string
+	var f io.WriteCloser
+	var err error
+	if d.externalStorage != nil {
+		// Files in external storage are only named after the queue's directory,
+		// which is unique, since GetPath creates the local temporary directory.
+		fName = path.Join(d.dirName, strconv.Itoa(d.seqNo))
+		f = &externalWriteFile{
+			ctx: ctx, storage: d.externalStorage, name: fName, memAcc: d.externalBufferAcc,
+		}
+	} else {
+		fName = filepath.Join(d.cfg.GetPather.GetPath(ctx), d.dirName, strconv.Itoa(d.seqNo))
+		f, err = fs.CreateWithSync(d.cfg.FS, fName, bytesPerSync, fs.SQLColumnSpillWriteCategory)
+	}
 	if err != nil {
 		return err
 	}
@@ -509,7 +707,10 @@ func (d *diskQueue) rotateFile(ctx context.Context) (retErr error) {
 		if retErr != nil {
 			// If we hit an error, then we lose the reference to newly created
 			// file - ensure that it is closed if so.
-			if err = f.Close(); err != nil {
+			if ef, ok := f.(*externalWriteFile); ok {
+				// Nothing refers to the file, so don't upload it.
+				ef.discard()
+			} else if err = f.Close(); err != nil {
 				retErr = errors.CombineErrors(retErr, err)
 			}
 		}
@@ -545,7 +746,7 @@ func (d *diskQueue) rotateFile(ctx context.Context) (retErr error) {
 	return nil
 }
 
-func (d *diskQueue) resetWriters(f vfs.File) error {
+func (d *diskQueue) resetWriters(f io.Writer) error {
 	d.writer.reset(f)
 	return d.serializer.Reset(d.writer)
 }
@@ -675,7 +876,7 @@ func (d *diskQueue) maybeInitDeserializer(ctx context.Context) (bool, error) {
 			}
 			if !d.rewindable {
 				// Remove current file.
-				if err := d.cfg.FS.Remove(d.files[d.readFileIdx].name); err != nil {
+				if err := d.removeFile(ctx, d.files[d.readFileIdx].name); err != nil {
 					return false, err
 				}
 				fileSize := int64(d.files[d.readFileIdx].totalSize)
@@ -694,11 +895,21 @@ func (d *diskQueue) maybeInitDeserializer(ctx context.Context) (bool, error) {
 	}
 	if d.readFile == nil {
 		// File is not open.
-		f, err := d.cfg.FS.Open(fileToRead.name)
-		if err != nil {
-			return false, err
+		if d.externalStorage != nil {
+			rf := &externalReadFile{
+				ctx: ctx, storage: d.externalStorage, name: fileToRead.name,
+			}
+			if d.readFileIdx == d.writeFileIdx && !fileToRead.finishedWriting {
+				rf.writeFile, _ = d.writeFile.(*externalWriteFile)
+			}
+			d.readFile = rf
+		} else {
+			f, err := d.cfg.FS.Open(fileToRead.name)
+			if err != nil {
+				return false, err
+			}
+			d.readFile = f
 		}
-		d.readFile = f
 	}
 	readRegionStart := fileToRead.offsets[fileToRead.curOffsetIdx]
 	readRegionLength := fileToRead.offsets[fileToRead.curOffsetIdx+1] - readRegionStart
@@ -15,8 +15,12 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coldatatestutils"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
@@ -172,6 +176,152 @@ func TestDiskQueue(t *testing.T) {
 	}
 }
 
+func TestDiskQueueExternalStorage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+	storage := nodelocal.TestingMakeNodelocalStorage(
+		t.TempDir(), cluster.MakeTestingClusterSettings(), cloudpb.ExternalStorage{},
+	)
+	defer storage.Close()
+	queueCfg.GetExternalStorage = func(context.Context) (cloud.ExternalStorage, error) {
+		return storage, nil
+	}
+	externalMonitor := execinfra.NewTestDiskMonitor(ctx, cluster.MakeTestingClusterSettings())
+	defer externalMonitor.Stop(ctx)
+	queueCfg.ExternalStorageMonitor = externalMonitor
+	bufferMonitor := execinfra.NewTestMemMonitor(ctx, cluster.MakeTestingClusterSettings())
+	defer bufferMonitor.Stop(ctx)
+	queueCfg.ExternalWriteBufferMonitor = bufferMonitor
+	// The local temporary directory is not created.
+	localPather := queueCfg.GetPather
+	queueCfg.GetPather = colcontainer.GetPatherFunc(func(context.Context) string {
+		t.Fatal("local temporary storage used")
+		return ""
+	})
+	listFiles := func() []string {
+		var files []string
+		require.NoError(t, storage.List(ctx, "", "", func(f string) error {
+			files = append(files, f)
+			return nil
+		}))
+		return files
+	}
+
+	rng, _ := randutil.NewTestRand()
+	for _, rewindable := range []bool{false, true} {
+		t.Run(fmt.Sprintf("rewindable=%t", rewindable), func(t *testing.T) {
+			var batches []coldata.Batch
+			op, typs := coldatatestutils.NewRandomDataOp(testAllocator, rng, coldatatestutils.RandomDataOpArgs{
+				NumBatches: 1 + rng.Intn(16),
+				BatchSize:  1 + rng.Intn(coldata.BatchSize()),
+				Nulls:      true,
+				BatchAccumulator: func(_ context.Context, b coldata.Batch, typs []*types.T) {
+					batches = append(batches, coldatatestutils.CopyBatch(b, typs, testColumnFactory))
+				},
+			})
+			op.Init(ctx)
+
+			// Use small files so that the queue rotates through several files.
+			queueCfg.SetCacheMode(colcontainer.DiskQueueCacheModeIntertwinedCalls)
+			queueCfg.MaxFileSizeBytes = 10 << 10 /* 10 KiB */
+			var (
+				q   colcontainer.Queue
+				err error
+			)
+			if rewindable {
+				q, err = colcontainer.NewRewindableDiskQueue(ctx, typs, queueCfg, testDiskAcc, testMemAcc)
+			} else {
+				q, err = colcontainer.NewDiskQueue(ctx, typs, queueCfg, testDiskAcc, testMemAcc)
+			}
+			require.NoError(t, err)
+
+			// No directories are created in the local temporary storage.
+			directories, err := queueCfg.FS.List(localPather.GetPath(ctx))
+			require.NoError(t, err)
+			require.Equal(t, 0, len(directories))
+
+			dest := coldata.NewMemBatch(typs, testColumnFactory)
+			for {
+				src := op.Next()
+				require.NoError(t, q.Enqueue(ctx, src))
+				if src.Length() == 0 {
+					break
+				}
+				// Dequeue while the queue is still being written to, which reads
+				// the file that's being written to from memory.
+				if !rewindable && rng.Float64() < 0.5 {
+					ok, err := q.Dequeue(ctx, dest)
+					require.NoError(t, err)
+					require.True(t, ok)
+					coldata.AssertEquivalentBatches(t, batches[0], dest)
+					batches = batches[1:]
+				}
+			}
+			require.NotEmpty(t, listFiles())
+			// The spilled bytes are accounted for separately from the local
+			// temporary storage.
+			require.Zero(t, testDiskAcc.Used())
+			require.NotZero(t, externalMonitor.AllocBytes())
+			// The file being written to is buffered in memory, which is
+			// accounted for until the file is uploaded.
+			require.NotZero(t, bufferMonitor.MaximumBytes())
+			numReadIterations := 1
+			if rewindable {
+				numReadIterations = 2
+			}
+			for i := 0; i < numReadIterations; i++ {
+				for _, b := range batches {
+					ok, err := q.Dequeue(ctx, dest)
+					require.NoError(t, err)
+					require.True(t, ok)
+					coldata.AssertEquivalentBatches(t, b, dest)
+				}
+				ok, err := q.Dequeue(ctx, dest)
+				require.NoError(t, err)
+				require.True(t, ok)
+				require.Equal(t, 0, dest.Length())
+				if rewindable {
+					require.NoError(t, q.(colcontainer.RewindableQueue).Rewind(ctx))
+				}
+			}
+
+			// Closing the queue removes all of its files.
+			require.NoError(t, q.Close(ctx))
+			require.Empty(t, listFiles())
+			require.Zero(t, externalMonitor.AllocBytes())
+			require.Zero(t, bufferMonitor.AllocBytes())
+		})
+	}
+
+	// Interleaving Enqueue and Dequeue calls doesn't upload any files until the
+	// file that's being written to is complete.
+	t.Run("intertwined", func(t *testing.T) {
+		queueCfg.SetCacheMode(colcontainer.DiskQueueCacheModeIntertwinedCalls)
+		queueCfg.MaxFileSizeBytes = 0
+		require.NoError(t, queueCfg.EnsureDefaults())
+		args := coldatatestutils.RandomVecArgs{Rand: rng}
+		batch := coldatatestutils.RandomBatch(testAllocator, args, types.OneIntCol, 1, 1)
+		q, err := colcontainer.NewDiskQueue(ctx, types.OneIntCol, queueCfg, testDiskAcc, testMemAcc)
+		require.NoError(t, err)
+		dest := coldata.NewMemBatch(types.OneIntCol, testColumnFactory)
+		for i := 0; i < 10; i++ {
+			require.NoError(t, q.Enqueue(ctx, batch))
+			ok, err := q.Dequeue(ctx, dest)
+			require.NoError(t, err)
+			require.True(t, ok)
+			coldata.AssertEquivalentBatches(t, batch, dest)
+		}
+		require.Empty(t, listFiles())
+		require.NoError(t, q.Enqueue(ctx, coldata.ZeroBatch))
+		require.Len(t, listFiles(), 1)
+		require.NoError(t, q.Close(ctx))
+		require.Empty(t, listFiles())
+	})
+}
+
 func TestDiskQueueCloseOnErr(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
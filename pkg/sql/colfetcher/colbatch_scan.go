@@ -337,6 +337,7 @@ func NewColBatchScan(
 		spec.LockingWaitPolicy,
 		spec.LockingDurability,
 		flowCtx.EvalCtx.SessionData().LockTimeout,
+		spec.FetchSpec.MaxKeysPerRow,
 		kvFetcherMemAcc,
 		flowCtx.EvalCtx.TestingKnobs.ForceProductionValues,
 	)
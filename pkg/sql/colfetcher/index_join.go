@@ -575,6 +575,7 @@ func NewColIndexJoin(
 			spec.LockingWaitPolicy,
 			spec.LockingDurability,
 			flowCtx.EvalCtx.SessionData().LockTimeout,
+			spec.FetchSpec.MaxKeysPerRow,
 			kvFetcherMemAcc,
 			flowCtx.EvalCtx.TestingKnobs.ForceProductionValues,
 		)
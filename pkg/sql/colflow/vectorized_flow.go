@@ -19,9 +19,11 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coldataext"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
@@ -104,6 +106,20 @@ var fdCountingSemaphoreMaxRetries = settings.RegisterIntSetting(
 	settings.NonNegativeInt,
 )
 
+// externalTempStorageURI is the URI of the external storage that the
+// vectorized engine spills to instead of the local temporary storage. This is
+// useful when the local ephemeral disk is too small to spill to, e.g. for SQL
+// pods of serverless clusters.
+var externalTempStorageURI = settings.RegisterStringSetting(
+	settings.ApplicationLevel,
+	"sql.distsql.temp_storage.external_uri",
+	"if set, the URI of the external storage (e.g. a cloud storage bucket) that "+
+		"the vectorized execution engine spills to instead of the local temporary storage",
+	"",
+	settings.Sensitive,
+	settings.WithPublic,
+)
+
 func (s *fdCountingSemaphore) Acquire(ctx context.Context, n int) error {
 	if s.TryAcquire(n) {
 		return nil
@@ -188,12 +204,21 @@ type vectorizedFlow struct {
 	// Cleanup.
 	countingSemaphore *fdCountingSemaphore
 
+	// externalStorageMonitor accounts for the bytes spilled to external storage
+	// if sql.distsql.temp_storage.external_uri is set, separately from the
+	// local temporary storage.
+	externalStorageMonitor *mon.BytesMonitor
+
 	tempStorage struct {
 		syncutil.Mutex
 		// path is the path to this flow's temporary storage directory. If
 		// it is an empty string, then it hasn't been computed yet nor the
 		// directory has been created.
 		path string
+		// externalStorage is the external storage that this flow spills to if
+		// sql.distsql.temp_storage.external_uri is set. It is opened lazily on
+		// the first spill, and is nil until then.
+		externalStorage cloud.ExternalStorage
 	}
 
 	testingKnobs struct {
@@ -240,6 +265,20 @@ func (f *vectorizedFlow) Setup(
 		SpilledBytesWritten: f.Cfg.Metrics.SpilledBytesWritten,
 		SpilledBytesRead:    f.Cfg.Metrics.SpilledBytesRead,
 	}
+	if uri := externalTempStorageURI.Get(&f.Cfg.Settings.SV); uri != "" {
+		diskQueueCfg.GetExternalStorage = func(ctx context.Context) (cloud.ExternalStorage, error) {
+			return f.getExternalStorage(ctx, uri)
+		}
+		f.externalStorageMonitor = mon.NewUnlimitedMonitor(ctx, mon.Options{
+			Name:     "flow-external-storage-monitor",
+			Res:      mon.DiskResource,
+			Settings: f.Cfg.Settings,
+		})
+		diskQueueCfg.ExternalStorageMonitor = f.externalStorageMonitor
+		// The files being written to external storage are buffered in memory,
+		// which counts against the query's memory budget.
+		diskQueueCfg.ExternalWriteBufferMonitor = f.GetFlowCtx().Mon
+	}
 	if err := diskQueueCfg.EnsureDefaults(); err != nil {
 		return ctx, nil, err
 	}
@@ -264,6 +303,9 @@ func (f *vectorizedFlow) Setup(
 		// clean that up.
 		f.creator.cleanup(ctx)
 		f.creator.Release()
+		if f.externalStorageMonitor != nil {
+			f.externalStorageMonitor.Stop(ctx)
+		}
 		log.VEventf(ctx, 1, "failed to vectorize: %v", err)
 		return ctx, nil, err
 	}
@@ -348,6 +390,31 @@ func (f *vectorizedFlow) GetPath(ctx context.Context) string {
 	return f.tempStorage.path
 }
 
+// getExternalStorage returns the external storage with the given URI that this
+// flow spills to, opening it on the first call.
+func (f *vectorizedFlow) getExternalStorage(
+	ctx context.Context, uri string,
+) (cloud.ExternalStorage, error) {
+	f.tempStorage.Lock()
+	defer f.tempStorage.Unlock()
+	if f.tempStorage.externalStorage != nil {
+		return f.tempStorage.externalStorage, nil
+	}
+	// The storage is accessed as the root user of the virtual cluster rather
+	// than as the node user, like e.g. a BACKUP run by an admin of the virtual
+	// cluster would.
+	es, err := f.Cfg.ExternalStorageFromURI(ctx, uri, username.RootUserName())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open external temporary storage")
+	}
+	log.VEventf(ctx, 1, "flow %s spilling to external storage", f.ID)
+	f.tempStorage.externalStorage = es
+	// GetPath, which counts the spilled queries when spilling to the local
+	// temporary storage, is not called in this case.
+	f.Cfg.Metrics.QueriesSpilled.Inc(1)
+	return es, nil
+}
+
 // ConcurrentTxnUse is part of the flowinfra.Flow interface. It is conservative
 // in that it returns that there is concurrent txn use as soon as any operator
 // concurrency is detected. This should be inconsequential for local flows that
@@ -393,7 +460,19 @@ func (f *vectorizedFlow) Cleanup(ctx context.Context) {
 
 	f.tempStorage.Lock()
 	created := f.tempStorage.path != ""
+	externalStorage := f.tempStorage.externalStorage
 	f.tempStorage.Unlock()
+	if externalStorage != nil {
+		// All files have been removed by the disk queues when they were closed
+		// above.
+		if err := externalStorage.Close(); err != nil {
+			log.Warningf(ctx, "unable to close flow %s's external temporary storage: %v",
+				f.GetID().Short(), err)
+		}
+	}
+	if f.externalStorageMonitor != nil {
+		f.externalStorageMonitor.Stop(ctx)
+	}
 	if created {
 		if err := f.Cfg.TempFS.RemoveAll(f.GetPath(ctx)); err != nil {
 			// Log error as a Warning but keep on going to close the memory
@@ -70,6 +70,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxlog"
@@ -370,6 +371,10 @@ type Server struct {
 
 	idxRecommendationsCache *idxrecommendations.IndexRecCache
 
+	// resourceGroups assigns the transactions of sessions that belong to a
+	// resource group their fair share of the tenant's resources.
+	resourceGroups *resourceGroupScheduler
+
 	mu struct {
 		syncutil.Mutex
 		connectionCount     int64
@@ -468,6 +473,7 @@ func NewServer(cfg *ExecutorConfig, pool *mon.BytesMonitor) *Server {
 			cfg.Settings,
 			&serverMetrics.ContentionSubsystemMetrics),
 		idxRecommendationsCache: idxrecommendations.NewIndexRecommendationsCache(cfg.Settings),
+		resourceGroups:          newResourceGroupScheduler(cfg.Settings),
 	}
 
 	telemetryLoggingMetrics := newTelemetryLoggingMetrics(cfg.TelemetryLoggingTestingKnobs, cfg.Settings)
@@ -515,6 +521,12 @@ func makeMetrics(internal bool) Metrics {
 			SQLOptFallbackCount:   metric.NewCounter(getMetricMeta(MetaSQLOptFallback, internal)),
 			SQLOptPlanCacheHits:   metric.NewCounter(getMetricMeta(MetaSQLOptPlanCacheHits, internal)),
 			SQLOptPlanCacheMisses: metric.NewCounter(getMetricMeta(MetaSQLOptPlanCacheMisses, internal)),
+			SQLOptPlanCacheInvalidations: metric.NewCounter(
+				getMetricMeta(MetaSQLOptPlanCacheInvalidations, internal)),
+			SQLOptGenericPlanCacheHits: metric.NewCounter(
+				getMetricMeta(MetaSQLOptGenericPlanCacheHits, internal)),
+			SQLOptGenericPlanCacheMisses: metric.NewCounter(
+				getMetricMeta(MetaSQLOptGenericPlanCacheMisses, internal)),
 			// TODO(mrtracy): See HistogramWindowInterval in server/config.go for the 6x factor.
 			DistSQLExecLatency: metric.NewHistogram(metric.HistogramOptions{
 				Mode:         metric.HistogramModePreferHdrLatency,
@@ -550,10 +562,12 @@ func makeMetrics(internal bool) Metrics {
 			SQLActiveStatements: metric.NewGauge(getMetricMeta(MetaSQLActiveQueries, internal)),
 			SQLContendedTxns:    metric.NewCounter(getMetricMeta(MetaSQLTxnContended, internal)),
 
-			TxnAbortCount:                     metric.NewCounter(getMetricMeta(MetaTxnAbort, internal)),
-			FailureCount:                      metric.NewCounter(getMetricMeta(MetaFailure, internal)),
-			FullTableOrIndexScanCount:         metric.NewCounter(getMetricMeta(MetaFullTableOrIndexScan, internal)),
-			FullTableOrIndexScanRejectedCount: metric.NewCounter(getMetricMeta(MetaFullTableOrIndexScanRejected, internal)),
+			TxnAbortCount:                      metric.NewCounter(getMetricMeta(MetaTxnAbort, internal)),
+			FailureCount:                       metric.NewCounter(getMetricMeta(MetaFailure, internal)),
+			FullTableOrIndexScanCount:          metric.NewCounter(getMetricMeta(MetaFullTableOrIndexScan, internal)),
+			FullTableOrIndexScanRejectedCount:  metric.NewCounter(getMetricMeta(MetaFullTableOrIndexScanRejected, internal)),
+			QueryMemoryBudgetExceededCount:     metric.NewCounter(getMetricMeta(MetaQueryMemoryBudgetExceeded, internal)),
+			StatementRetryBudgetExhaustedCount: metric.NewCounter(getMetricMeta(MetaStatementRetryBudgetExhausted, internal)),
 		},
 		StartedStatementCounters:  makeStartedStatementCounters(internal),
 		ExecutedStatementCounters: makeExecutedStatementCounters(internal),
@@ -852,13 +866,26 @@ func (s *Server) IncrementConnectionCount(
 	maxNumNonRootConnectionsValue := maxNumNonRootConnections.Get(sv)
 	maxNumConnectionsValue := maxNumNonAdminConnections.Get(sv)
 	maxNumNonRootConnectionsReasonValue := maxNumNonRootConnectionsReason.Get(sv)
-	var maxNumNonRootConnectionsExceeded, maxNumConnectionsExceeded bool
+	var maxSQLConnectionsValue int64
+	if s.cfg.MaxSQLConnectionsPerInstanceFunc != nil {
+		maxSQLConnectionsValue = s.cfg.MaxSQLConnectionsPerInstanceFunc()
+	}
+	var maxNumNonRootConnectionsExceeded, maxNumConnectionsExceeded, maxSQLConnectionsExceeded bool
 	// This lock blocks other connections from being made so minimize the amount
 	// of work done inside lock.
 	func() {
 		s.mu.Lock()
 		defer s.mu.Unlock()
-		// Root user is not affected by connection limits.
+		connectionCount := s.mu.connectionCount
+		// The limit set by the max_sql_connections_per_instance tenant capability
+		// applies to every user, root included, since it is configured by the
+		// operator of the host cluster rather than from within the tenant. It
+		// only counts the connections to this SQL instance.
+		maxSQLConnectionsExceeded = maxSQLConnectionsValue > 0 && connectionCount >= maxSQLConnectionsValue
+		if maxSQLConnectionsExceeded {
+			return
+		}
+		// Root user is not affected by the other connection limits.
 		if sessionArgs.User.IsRootUser() {
 			s.mu.connectionCount++
 			s.mu.rootConnectionCount++
@@ -870,7 +897,6 @@ func (s *Server) IncrementConnectionCount(
 			}
 			return
 		}
-		connectionCount := s.mu.connectionCount
 		nonRootConnectionCount := connectionCount - s.mu.rootConnectionCount
 		maxNumNonRootConnectionsExceeded = maxNumNonRootConnectionsValue >= 0 && nonRootConnectionCount >= maxNumNonRootConnectionsValue
 		if maxNumNonRootConnectionsExceeded {
@@ -902,6 +928,14 @@ func (s *Server) IncrementConnectionCount(
 			maxNumNonAdminConnections.Name(),
 		)
 	}
+	if maxSQLConnectionsExceeded {
+		return nil, errors.WithHintf(
+			pgerror.New(pgcode.TooManyConnections, "sorry, too many clients already"),
+			"the maximum number of allowed connections to this SQL instance is %d and is set by "+
+				"the max_sql_connections_per_instance capability of the virtual cluster",
+			maxSQLConnectionsValue,
+		)
+	}
 	return decrementConnectionCount, nil
 }
 
@@ -1622,6 +1656,10 @@ type connExecutor struct {
 		// The map key is the sequence descpb.ID.
 		createdSequences map[descpb.ID]struct{}
 
+		// writtenTables keeps track of tables to which writes were planned in
+		// the current transaction. The map key is the table descpb.ID.
+		writtenTables map[descpb.ID]struct{}
+
 		// shouldLogToTelemetry indicates if the current transaction should be
 		// logged to telemetry. It is used in telemetry transaction sampling
 		// mode to emit all statement events for a particular transaction.
@@ -2051,6 +2089,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 	ex.extraTxnState.upgradedToSerializable = false
 	ex.extraTxnState.hasAdminRoleCache = HasAdminRoleCache{}
 	ex.extraTxnState.createdSequences = nil
+	ex.extraTxnState.writtenTables = nil
 
 	if ex.extraTxnState.fromOuterTxn {
 		if ex.extraTxnState.shouldResetSyntheticDescriptors {
@@ -3347,6 +3386,16 @@ func (ex *connExecutor) getRewindTxnCapability() (rewindCapability, bool) {
 		cl.Close()
 		return rewindCapability{}, false
 	}
+	// Rewinding retries the transaction, which counts against the retry budget
+	// of the statement that encountered the retriable error.
+	if ex.stmtRetryBudgetErr(
+		ex.state.mu.autoRetryCounter,
+		ex.phaseTimes.GetSessionPhaseTime(sessionphase.SessionFirstStartExecTransaction),
+	) != nil {
+		cl.Close()
+		ex.metrics.EngineMetrics.StatementRetryBudgetExhaustedCount.Inc(1)
+		return rewindCapability{}, false
+	}
 	return rewindCapability{
 		cl:        cl,
 		buf:       ex.stmtBuf,
@@ -3354,6 +3403,28 @@ func (ex *connExecutor) getRewindTxnCapability() (rewindCapability, bool) {
 	}, true
 }
 
+// stmtRetryBudgetErr returns an error if a statement which consumed the given
+// number of automatic retries, and whose first attempt started at the given
+// time, exhausted the retry budget set by the statement_retry_budget_count and
+// statement_retry_budget_latency session variables. It returns nil if the
+// statement can be retried once more.
+func (ex *connExecutor) stmtRetryBudgetErr(retries int32, firstAttempt time.Time) error {
+	sd := ex.sessionData()
+	if budget := sd.StatementRetryBudgetCount; budget > 0 && retries >= budget {
+		return errors.Newf(
+			"statement retry budget exhausted after %d retries; set by statement_retry_budget_count=%d",
+			retries, budget,
+		)
+	}
+	if budget := sd.StatementRetryBudgetLatency; budget > 0 && timeutil.Since(firstAttempt) >= budget {
+		return errors.Newf(
+			"statement retry budget exhausted after %d retries; set by statement_retry_budget_latency=%s",
+			retries, budget,
+		)
+	}
+	return nil
+}
+
 // isCommit returns true if stmt is a "COMMIT" statement.
 func isCommit(stmt tree.Statement) bool {
 	_, ok := stmt.(*tree.CommitTransaction)
@@ -3438,6 +3509,16 @@ func (ex *connExecutor) makeErrEvent(err error, stmt tree.Statement) (fsm.Event,
 		var canAutoRetry bool
 		if ex.implicitTxn() || !ex.sessionData().InjectRetryErrorsEnabled {
 			rc, canAutoRetry = ex.getRewindTxnCapability()
+			if !canAutoRetry {
+				// Tell the client why the error wasn't retried if the statement
+				// exhausted its retry budget.
+				if budgetErr := ex.stmtRetryBudgetErr(
+					ex.state.mu.autoRetryCounter,
+					ex.phaseTimes.GetSessionPhaseTime(sessionphase.SessionFirstStartExecTransaction),
+				); budgetErr != nil {
+					err = errors.Wrapf(err, "%v", budgetErr)
+				}
+			}
 		}
 
 		ev := eventRetriableErr{
@@ -3809,6 +3890,7 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.sqlCursors = ex.getCursorAccessor()
 	p.storedProcTxnState = ex.getStoredProcTxnStateAccessor()
 	p.createdSequences = ex.getCreatedSequencesAccessor()
+	p.writtenTables = ex.getWrittenTablesAccessor()
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -3930,6 +4012,7 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 		if err := ex.maybeSetSQLLivenessSession(); err != nil {
 			return advanceInfo{}, err
 		}
+		ex.maybeApplyResourceGroup()
 	case txnCommit:
 		if res.Err() != nil {
 			// See https://github.com/cockroachdb/errors/issues/86.
@@ -4045,6 +4128,30 @@ func (ex *connExecutor) maybeSetSQLLivenessSession() error {
 	return nil
 }
 
+// maybeApplyResourceGroup applies the fair share of the session's resource
+// group, if any, to the transaction that was just started. If the group is
+// ahead of its share by more than resourceGroupDemotionLead, the work of the
+// transaction is admitted at low priority. While the tenant is throttled, its
+// KV requests are also delayed by the group's lead.
+func (ex *connExecutor) maybeApplyResourceGroup() {
+	group := ex.sessionData().ResourceGroup
+	if group == "" {
+		return
+	}
+	now := ex.server.cfg.Clock.PhysicalTime()
+	lead, ok := ex.server.resourceGroups.startTxn(group, now)
+	if !ok || lead == 0 {
+		return
+	}
+	if lead > resourceGroupDemotionLead {
+		txn := ex.state.mu.txn
+		if admissionpb.WorkPriority(txn.AdmissionHeader().Priority) > admissionpb.UserLowPri {
+			txn.SetAdmissionPriority(admissionpb.UserLowPri)
+		}
+	}
+	ex.state.Ctx = multitenant.WithResourceGroupStart(ex.state.Ctx, now.Add(lead))
+}
+
 // initStatementResult initializes res according to a query.
 //
 // cols represents the columns of the result rows. Should be nil if
@@ -4303,6 +4410,12 @@ func (ex *connExecutor) getCreatedSequencesAccessor() createdSequences {
 	}
 }
 
+func (ex *connExecutor) getWrittenTablesAccessor() writtenTables {
+	return connExWrittenTablesAccessor{
+		ex: ex,
+	}
+}
+
 // sessionEventf logs a message to the session event log (if any).
 func (ex *connExecutor) sessionEventf(ctx context.Context, format string, args ...interface{}) {
 	if log.ExpensiveLogEnabled(ctx, 2) {
@@ -23,7 +23,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/multitenantcpu"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -55,6 +57,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxlog"
@@ -65,6 +69,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing/tracingpb"
@@ -147,7 +153,7 @@ func (ex *connExecutor) execStmt(
 		})
 		switch ev.(type) {
 		case eventNonRetriableErr:
-			ex.recordFailure()
+			ex.recordFailure(payload)
 		}
 
 	case stateAborted:
@@ -196,8 +202,11 @@ func (ex *connExecutor) execStmt(
 	return ev, payload, err
 }
 
-func (ex *connExecutor) recordFailure() {
+func (ex *connExecutor) recordFailure(p fsm.EventPayload) {
 	ex.metrics.EngineMetrics.FailureCount.Inc(1)
+	if pe, ok := p.(payloadWithError); ok && mon.IsQueryMemoryBudgetExceededError(pe.errorCause()) {
+		ex.metrics.EngineMetrics.QueryMemoryBudgetExceededCount.Inc(1)
+	}
 }
 
 // execPortal executes a prepared statement. It is a "wrapper" around execStmt
@@ -580,6 +589,24 @@ func (ex *connExecutor) execStmtInOpenState(
 		}
 	}
 
+	// Admit the execution of statements from client sessions based on the
+	// memory pressure on the node. Statements may be queued, or rejected
+	// outright if the node is close to its memory limit. Statements of
+	// transactions which have already written or performed locking reads
+	// bypass admission: they hold locks which queued statements, including
+	// those of the transactions blocking admission, may be waiting on.
+	if ex.executorType != executorTypeInternal && !ex.state.mu.txn.Sender().HasAcquiredLocks() {
+		h, err := ex.server.cfg.SQLMemoryAdmissionQ.Admit(ctx, admission.WorkInfo{
+			TenantID:   ex.server.cfg.Codec.TenantID,
+			Priority:   admissionpb.WorkPriority(ex.QualityOfService()),
+			CreateTime: timeutil.Now().UnixNano(),
+		})
+		if err != nil {
+			return makeErrEvent(pgerror.WithCandidateCode(err, pgcode.OutOfMemory))
+		}
+		defer ex.server.cfg.SQLMemoryAdmissionQ.AdmittedWorkDone(h)
+	}
+
 	// Special top-level handling for EXPLAIN ANALYZE.
 	if e, ok := ast.(*tree.ExplainAnalyze); ok {
 		switch e.Mode {
@@ -1133,6 +1160,9 @@ func (ex *connExecutor) execStmtInOpenState(
 			return nil, nil, err
 		}
 	} else {
+		// Outside of the READ COMMITTED retry loop, statements are retried by
+		// retrying their transaction.
+		p.instrumentation.stmtRetries = ex.state.mu.autoRetryCounter
 		if err := ex.dispatchToExecutionEngine(stmtCtx, p, res); err != nil {
 			stmtThresholdSpan.Finish()
 			return nil, nil, err
@@ -1672,6 +1702,7 @@ func (ex *connExecutor) dispatchReadCommittedStmtToExecutionEngine(
 	}
 
 	maxRetries := int(ex.sessionData().MaxRetriesForReadCommitted)
+	firstAttempt := timeutil.Now()
 	for attemptNum := 0; ; attemptNum++ {
 		bufferPos := res.BufferedResultsLen()
 		if err = ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
@@ -1701,6 +1732,12 @@ func (ex *connExecutor) dispatchReadCommittedStmtToExecutionEngine(
 			))
 			break
 		}
+		// If the statement exhausted its retry budget, then we must stop too.
+		if budgetErr := ex.stmtRetryBudgetErr(int32(attemptNum), firstAttempt); budgetErr != nil {
+			ex.metrics.EngineMetrics.StatementRetryBudgetExhaustedCount.Inc(1)
+			res.SetError(errors.Wrapf(maybeRetriableErr, "%v", budgetErr))
+			break
+		}
 
 		// In order to retry the statement, we need to clear any results and
 		// errors that were buffered, rollback to the savepoint, then prepare the
@@ -1730,6 +1767,7 @@ func (ex *connExecutor) dispatchReadCommittedStmtToExecutionEngine(
 		}
 		ex.state.mu.autoRetryCounter++
 		ex.state.mu.autoRetryReason = txnRetryErr
+		p.instrumentation.stmtRetries++
 	}
 	return nil
 }
@@ -1984,9 +2022,18 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		distribute = FullDistribution
 	}
 	ex.sessionTracing.TraceExecStart(ctx, "distributed")
+	// In virtual clusters, attribute the RUs consumed by the statement's KV and
+	// external I/O operations to its statement statistics.
+	var ru ruAttribution
+	execCtx := ctx
+	if !ex.server.cfg.Codec.ForSystemTenant() {
+		execCtx = multitenant.WithRUObserver(ctx, ru.observe)
+	}
 	stats, err = ex.execWithDistSQLEngine(
-		ctx, planner, stmt.AST.StatementReturnType(), res, distribute, progAtomic, distSQLProhibitedErr,
+		execCtx, planner, stmt.AST.StatementReturnType(), res, distribute, progAtomic, distSQLProhibitedErr,
 	)
+	stats.requestUnits = syncutil.LoadFloat64(&ru.requestUnits)
+	stats.estimatedCPUSeconds = syncutil.LoadFloat64(&ru.estimatedCPUSeconds)
 	if ppInfo := getPausablePortalInfo(); ppInfo != nil {
 		// For pausable portals, we log the stats when closing the portal, so we need
 		// to aggregate the stats for all executions.
@@ -2367,6 +2414,14 @@ type topLevelQueryStats struct {
 	// client receiving the PGWire protocol messages (as well as construcing
 	// those messages).
 	clientTime time.Duration
+	// requestUnits is the number of RUs consumed by the KV and external I/O
+	// operations of the query on the gateway. It is only set in virtual
+	// clusters.
+	requestUnits float64
+	// estimatedCPUSeconds is the estimated KV CPU usage of the query on the
+	// gateway. It is only set in virtual clusters that are billed under the
+	// estimated CPU cost model.
+	estimatedCPUSeconds float64
 }
 
 func (s *topLevelQueryStats) add(other *topLevelQueryStats) {
@@ -2375,6 +2430,20 @@ func (s *topLevelQueryStats) add(other *topLevelQueryStats) {
 	s.rowsWritten += other.rowsWritten
 	s.networkEgressEstimate += other.networkEgressEstimate
 	s.clientTime += other.clientTime
+	s.requestUnits += other.requestUnits
+	s.estimatedCPUSeconds += other.estimatedCPUSeconds
+}
+
+// ruAttribution accumulates the consumption reported to a
+// multitenant.RUObserver, which may be called concurrently.
+type ruAttribution struct {
+	requestUnits        syncutil.AtomicFloat64
+	estimatedCPUSeconds syncutil.AtomicFloat64
+}
+
+func (a *ruAttribution) observe(ru tenantcostmodel.RU, estimatedCPUSeconds float64) {
+	syncutil.AddFloat64(&a.requestUnits, float64(ru))
+	syncutil.AddFloat64(&a.estimatedCPUSeconds, estimatedCPUSeconds)
 }
 
 // execWithDistSQLEngine converts a plan to a distributed SQL physical plan and
@@ -330,7 +330,9 @@ func TestCopyFromBinary(t *testing.T) {
 			i8 INT8,
 			f FLOAT,
 			s STRING,
-			b BYTES
+			b BYTES,
+			d DECIMAL, -- datum-backed in the vectorized batches
+			j JSONB
 		);
 	`); err != nil {
 		t.Fatal(err)
@@ -346,26 +348,34 @@ func TestCopyFromBinary(t *testing.T) {
 		float64(1),
 		"s",
 		"b",
+		"1.5",
+		`{"a": 1}`,
+	}, {
+		// Only NULLs, which are set differently in the vectorized batches.
+		2, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
 	}}
 	if _, err = conn.CopyFrom(
 		ctx,
 		pgx.Identifier{"t"},
-		[]string{"id", "u", "o", "i2", "i4", "i8", "f", "s", "b"},
+		[]string{"id", "u", "o", "i2", "i4", "i8", "f", "s", "b", "d", "j"},
 		pgx.CopyFromRows(input),
 	); err != nil {
 		t.Fatal(err)
 	}
 
 	expect := func() [][]string {
-		row := make([]string, len(input[0]))
-		for i, v := range input[0] {
-			if v == nil {
-				row[i] = "NULL"
-			} else {
-				row[i] = fmt.Sprintf("%v", v)
+		rows := make([][]string, len(input))
+		for i, in := range input {
+			rows[i] = make([]string, len(in))
+			for j, v := range in {
+				if v == nil {
+					rows[i][j] = "NULL"
+				} else {
+					rows[i][j] = fmt.Sprintf("%v", v)
+				}
 			}
 		}
-		return [][]string{row}
+		return rows
 	}()
 	sqlDB.CheckQueryResults(t, "SELECT * FROM t ORDER BY id", expect)
 }
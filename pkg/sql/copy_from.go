@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -271,6 +272,9 @@ type copyMachine struct {
 	typs          []*types.T
 	valueHandlers []tree.ValueHandler
 	ph            pgdate.ParseHelper
+	// datumToPhysicalFns convert the datums decoded from binary data to the
+	// physical representation of the columns of batch.
+	datumToPhysicalFns []func(tree.Datum) interface{}
 
 	// For testing we want to be able to override this on the instance level.
 	copyBatchRowSize int
@@ -397,6 +401,18 @@ func newCopyMachine(
 	return c, nil
 }
 
+// copyRUPacingEnabled controls whether COPY FROM statements run by virtual
+// clusters are paced as background work by the tenant cost controller.
+var copyRUPacingEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.copy.ru_pacing.enabled",
+	"if true, COPY FROM statements in virtual clusters wait before inserting each "+
+		"batch of rows while the virtual cluster is low on request units, so that "+
+		"bulk loads slow down before foreground traffic gets throttled",
+	true,
+	settings.WithPublic,
+)
+
 var copyMaxCommandSizeFraction = settings.RegisterFloatSetting(
 	settings.ApplicationLevel,
 	"sql.copy.max_command_size_fraction",
@@ -408,10 +424,6 @@ var copyMaxCommandSizeFraction = settings.RegisterFloatSetting(
 )
 
 func (c *copyMachine) canSupportVectorized(table catalog.TableDescriptor) bool {
-	// TODO(cucaroach): support vectorized binary.
-	if c.format == tree.CopyFormatBinary {
-		return false
-	}
 	// Vectorized requires avoiding materializing the rows for the optimizer.
 	if !c.copyFastPath {
 		return false
@@ -505,6 +517,14 @@ func (c *copyMachine) initVectorizedCopy(ctx context.Context, typs []*types.T) e
 	for i := range typs {
 		c.valueHandlers[i] = coldataext.MakeVecHandler(c.batch.ColVec(i))
 	}
+	if c.format == tree.CopyFormatBinary {
+		// Binary data is decoded into datums, which are then set directly in
+		// the batch rather than parsed by the value handlers.
+		c.datumToPhysicalFns = make([]func(tree.Datum) interface{}, len(typs))
+		for i, typ := range typs {
+			c.datumToPhysicalFns[i] = colconv.GetDatumToPhysicalFn(typ)
+		}
+	}
 	return nil
 }
 
@@ -928,7 +948,12 @@ func (c *copyMachine) readBinaryData(ctx context.Context, final bool) (brk bool,
 			return false, err
 		}
 		c.buf = c.buf[n:]
-		return false, nil
+		// The signature isn't a row, so keep reading, like readCSVData does
+		// after a header.
+		if len(c.buf) == 0 {
+			return true, nil
+		}
+		return c.readBinaryData(ctx, final)
 	case binaryStateRead:
 		n, err := c.readBinaryTuple(ctx)
 		if err != nil {
@@ -941,6 +966,13 @@ func (c *copyMachine) readBinaryData(ctx context.Context, final bool) (brk bool,
 			return false, errors.Wrapf(err, "read binary tuple")
 		}
 		c.buf = c.buf[n:]
+		if c.binaryState == binaryStateFoundTrailer {
+			// Like the signature, the trailer isn't a row.
+			if len(c.buf) == 0 {
+				return true, nil
+			}
+			return c.readBinaryData(ctx, final)
+		}
 		return false, nil
 	case binaryStateFoundTrailer:
 		if !final {
@@ -1003,6 +1035,29 @@ func (c *copyMachine) readBinaryTuple(ctx context.Context) (bytesRead int, err e
 		}
 		datums[i] = d
 	}
+	if c.vectorized {
+		// The tuple is only added to the batch once it was entirely read, so
+		// that incomplete tuples can be read again with more data.
+		if len(datums) != len(c.typs) {
+			return bytesRead, pgerror.Newf(pgcode.BadCopyFileFormat,
+				"expected %d values, got %d", len(c.typs), len(datums))
+		}
+		row := c.batch.Length()
+		if err := colexecerror.CatchVectorizedRuntimeError(func() {
+			for i, d := range datums {
+				vec := c.batch.ColVec(i)
+				if d == tree.DNull {
+					vec.Nulls().SetNull(row)
+					continue
+				}
+				coldata.SetValueAt(vec, c.datumToPhysicalFns[i](d), row)
+			}
+		}); err != nil {
+			return bytesRead, err
+		}
+		c.batch.SetLength(row + 1)
+		return bytesRead, nil
+	}
 	_, err = c.rows.AddRow(ctx, datums)
 	if err != nil {
 		return bytesRead, err
@@ -1126,6 +1181,9 @@ func (c *copyMachine) doneWithRows(ctx context.Context) error {
 
 // insertRows inserts rows, retrying if necessary.
 func (c *copyMachine) insertRows(ctx context.Context, finalBatch bool) error {
+	if err := c.paceBatch(ctx); err != nil {
+		return err
+	}
 	var err error
 
 	rOpts := base.DefaultRetryOptions()
@@ -1163,6 +1221,19 @@ func (c *copyMachine) insertRows(ctx context.Context, finalBatch bool) error {
 	return err
 }
 
+// paceBatch blocks for as long as the virtual cluster running the COPY is low
+// on request units, if RU pacing is enabled. COPY is paced like other
+// background work, such as row-level TTL deletions, so that bulk loads slow
+// down before the foreground traffic of the virtual cluster gets throttled.
+func (c *copyMachine) paceBatch(ctx context.Context) error {
+	costController := c.p.ExecCfg().DistSQLSrv.TenantCostController
+	if costController == nil || c.currentBatchSize() == 0 ||
+		!copyRUPacingEnabled.Get(c.p.ExecCfg().SV()) {
+		return nil
+	}
+	return costController.OnBackgroundWorkWait(ctx)
+}
+
 // insertRowsInternal transforms the buffered rows into an insertNode and executes it.
 func (c *copyMachine) insertRowsInternal(ctx context.Context, finalBatch bool) (retErr error) {
 	numRows := c.currentBatchSize()
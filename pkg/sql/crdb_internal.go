@@ -211,7 +211,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalRegionsTable:                       crdbInternalRegionsTable,
 		catconstants.CrdbInternalDefaultPrivilegesTable:             crdbInternalDefaultPrivilegesTable,
 		catconstants.CrdbInternalActiveRangeFeedsTable:              crdbInternalActiveRangeFeedsTable,
-		catconstants.CrdbInternalTenantUsageDetailsViewID:           crdbInternalTenantUsageDetailsView,
+		catconstants.CrdbInternalTenantUsageDetailsTableID:          crdbInternalTenantUsageDetailsTable,
 		catconstants.CrdbInternalPgCatalogTableIsImplementedTableID: crdbInternalPgCatalogTableIsImplementedTable,
 		catconstants.CrdbInternalShowTenantCapabilitiesCacheTableID: crdbInternalShowTenantCapabilitiesCache,
 		catconstants.CrdbInternalInheritedRoleMembersTableID:        crdbInternalInheritedRoleMembers,
@@ -226,6 +226,18 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalPCRStreamsTableID:                  crdbInternalPCRStreamsTable,
 		catconstants.CrdbInternalPCRStreamSpansTableID:              crdbInternalPCRStreamSpansTable,
 		catconstants.CrdbInternalPCRStreamCheckpointsTableID:        crdbInternalPCRStreamCheckpointsTable,
+		catconstants.CrdbInternalNodeStmtIteratorStatsTableID:       crdbInternalNodeStmtIteratorStatsTable,
+		catconstants.CrdbInternalRaftStatusTableID:                  crdbInternalRaftStatusTable,
+		catconstants.CrdbInternalNodeStmtDiagAutoCaptureTableID:     crdbInternalNodeStmtDiagAutoCaptureTable,
+		catconstants.CrdbInternalRaftProposalQuotaTableID:           crdbInternalRaftProposalQuotaTable,
+		catconstants.CrdbInternalNodeIndexReadUsageTableID:          crdbInternalNodeIndexReadUsageTable,
+		catconstants.CrdbInternalKVStoreEncryptionKeysTableID:       crdbInternalKVStoreEncryptionKeysTable,
+		catconstants.CrdbInternalTableMVCCGarbageTableID:            crdbInternalTableMVCCGarbageTable,
+		catconstants.CrdbInternalLoadBasedSplitDecisionsTableID:     crdbInternalLoadBasedSplitDecisionsTable,
+		catconstants.CrdbInternalClusterTxnWaitForGraphTableID:      crdbInternalClusterTxnWaitForGraphTable,
+		catconstants.CrdbInternalNodeRangeCostsTableID:              crdbInternalNodeRangeCostsTable,
+		catconstants.CrdbInternalClusterSettingHistoryTableID:       crdbInternalClusterSettingHistoryTable,
+		catconstants.CrdbInternalClusterRangeCostsTableID:           crdbInternalClusterRangeCostsTable,
 	},
 	validWithNoDatabaseContext: true,
 }
@@ -1180,6 +1192,8 @@ CREATE TABLE crdb_internal.jobs (
   num_runs              INT,
   execution_errors      STRING[],
   execution_events      JSONB,
+  request_units         FLOAT,
+  estimated_cpu_seconds FLOAT,
   INDEX(job_id),
   INDEX(status),
   INDEX(job_type)
@@ -1293,9 +1307,10 @@ func makeJobsTableRows(
 
 		var jobType, description, statement, user, descriptorIDs, started, runningStatus,
 			finished, modified, fractionCompleted, highWaterTimestamp, errorStr, coordinatorID,
-			traceID, executionErrors, executionEvents = tree.DNull, tree.DNull, tree.DNull,
+			traceID, executionErrors, executionEvents, requestUnits, estimatedCPUSeconds = tree.DNull,
 			tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull,
-			tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull
+			tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull,
+			tree.DNull, tree.DNull, tree.DNull
 
 		// Extract data from the payload.
 		payload, err := jobs.UnmarshalPayload(payloadBytes)
@@ -1373,6 +1388,8 @@ func makeJobsTableRows(
 					}
 				}
 				traceID = tree.NewDInt(tree.DInt(progress.TraceID))
+				requestUnits = tree.NewDFloat(tree.DFloat(progress.ResourceUsage.RequestUnits))
+				estimatedCPUSeconds = tree.NewDFloat(tree.DFloat(progress.ResourceUsage.EstimatedCPUSeconds))
 			}
 		}
 		if len(payload.RetriableExecutionFailureLog) > 0 {
@@ -1416,6 +1433,8 @@ func makeJobsTableRows(
 			numRuns,
 			executionErrors,
 			executionEvents,
+			requestUnits,
+			estimatedCPUSeconds,
 		); err != nil {
 			return matched, err
 		}
@@ -1992,6 +2011,211 @@ CREATE TABLE crdb_internal.node_statement_statistics (
 	},
 }
 
+// crdbInternalNodeStmtIteratorStatsTable rolls up the storage engine
+// iterator statistics collected by the execution stats pipeline per statement
+// fingerprint, ranking fingerprints by the amount of LSM read work they are
+// responsible for. The per-fingerprint averages are only recorded for sampled
+// executions, so the totals are extrapolated to the full execution count.
+var crdbInternalNodeStmtIteratorStatsTable = virtualSchemaTable{
+	comment: `storage engine iterator statistics rolled up per statement fingerprint, ` +
+		`ordered by the estimated number of SSTable block bytes read (RAM; local node only)`,
+	schema: `
+CREATE TABLE crdb_internal.node_statement_iterator_stats (
+  node_id                            INT NOT NULL,
+  rank                               INT NOT NULL,
+  application_name                   STRING NOT NULL,
+  statement_id                       STRING NOT NULL,
+  key                                STRING NOT NULL,
+  count                              INT NOT NULL,
+  sampled_count                      INT NOT NULL,
+  seeks                              FLOAT NOT NULL,
+  seeks_internal                     FLOAT NOT NULL,
+  steps                              FLOAT NOT NULL,
+  steps_internal                     FLOAT NOT NULL,
+  block_bytes                        FLOAT NOT NULL,
+  block_bytes_in_cache               FLOAT NOT NULL,
+  points_covered_by_range_tombstones FLOAT NOT NULL,
+  range_key_skipped_points           FLOAT NOT NULL,
+  tombstones_skipped_ratio           FLOAT
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		hasPriv, _, err := p.HasViewActivityOrViewActivityRedactedRole(ctx)
+		if err != nil {
+			return err
+		} else if !hasPriv {
+			return noViewActivityOrViewActivityRedactedRoleError(p.User())
+		}
+
+		sqlStats := p.extendedEvalCtx.statsProvider
+		nodeID, _ := p.execCfg.NodeInfo.NodeID.OptionalNodeID() // zero if not available
+
+		var rollups []stmtIteratorStatsRollup
+		if err := sqlStats.GetLocalMemProvider().IterateStatementStats(ctx, sqlstats.IteratorOptions{
+			SortedAppNames: true,
+			SortedKey:      true,
+		}, func(_ context.Context, stats *appstatspb.CollectedStatementStatistics) error {
+			if stats.Stats.ExecStats.Count == 0 {
+				// Iterator stats are only available for sampled executions.
+				return nil
+			}
+			rollups = append(rollups, makeStmtIteratorStatsRollup(stats))
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		// Rank the fingerprints by the LSM read work they caused. The sort is
+		// stable so that ties retain the (app name, key) order of the iterator.
+		sort.SliceStable(rollups, func(i, j int) bool {
+			return rollups[i].blockBytes > rollups[j].blockBytes
+		})
+
+		var alloc tree.DatumAlloc
+		for i := range rollups {
+			r := &rollups[i]
+			tombstonesSkippedRatio := tree.DNull
+			if r.pointCount > 0 {
+				tombstonesSkippedRatio = alloc.NewDFloat(tree.DFloat(
+					(r.pointsCoveredByRangeTombstones + r.rangeKeySkippedPoints) / r.pointCount))
+			}
+			if err := addRow(
+				alloc.NewDInt(tree.DInt(nodeID)),                                     // node_id
+				alloc.NewDInt(tree.DInt(i+1)),                                        // rank
+				alloc.NewDString(tree.DString(r.app)),                                // application_name
+				alloc.NewDString(tree.DString(strconv.FormatUint(uint64(r.id), 10))), // statement_id
+				alloc.NewDString(tree.DString(r.query)),                              // key
+				alloc.NewDInt(tree.DInt(r.count)),                                    // count
+				alloc.NewDInt(tree.DInt(r.sampledCount)),                             // sampled_count
+				alloc.NewDFloat(tree.DFloat(r.seeks)),                                // seeks
+				alloc.NewDFloat(tree.DFloat(r.seeksInternal)),                        // seeks_internal
+				alloc.NewDFloat(tree.DFloat(r.steps)),                                // steps
+				alloc.NewDFloat(tree.DFloat(r.stepsInternal)),                        // steps_internal
+				alloc.NewDFloat(tree.DFloat(r.blockBytes)),                           // block_bytes
+				alloc.NewDFloat(tree.DFloat(r.blockBytesInCache)),                    // block_bytes_in_cache
+				alloc.NewDFloat(tree.DFloat(r.pointsCoveredByRangeTombstones)),       // points_covered_by_range_tombstones
+				alloc.NewDFloat(tree.DFloat(r.rangeKeySkippedPoints)),                // range_key_skipped_points
+				tombstonesSkippedRatio,                                               // tombstones_skipped_ratio
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// stmtIteratorStatsRollup holds the estimated total iterator work performed
+// by all executions of a statement fingerprint on the local node.
+type stmtIteratorStatsRollup struct {
+	id                             appstatspb.StmtFingerprintID
+	app                            string
+	query                          string
+	count                          int64
+	sampledCount                   int64
+	seeks                          float64
+	seeksInternal                  float64
+	steps                          float64
+	stepsInternal                  float64
+	blockBytes                     float64
+	blockBytesInCache              float64
+	pointCount                     float64
+	pointsCoveredByRangeTombstones float64
+	rangeKeySkippedPoints          float64
+}
+
+// makeStmtIteratorStatsRollup extrapolates the sampled per-execution iterator
+// stats averages of a statement fingerprint to all of its executions.
+func makeStmtIteratorStatsRollup(
+	stats *appstatspb.CollectedStatementStatistics,
+) stmtIteratorStatsRollup {
+	iterStats := &stats.Stats.ExecStats.MVCCIteratorStats
+	count := stats.Stats.Count
+	if count < stats.Stats.ExecStats.Count {
+		count = stats.Stats.ExecStats.Count
+	}
+	total := func(n appstatspb.NumericStat) float64 {
+		return n.Mean * float64(count)
+	}
+	return stmtIteratorStatsRollup{
+		id:                             stats.ID,
+		app:                            stats.Key.App,
+		query:                          stats.Key.Query,
+		count:                          stats.Stats.Count,
+		sampledCount:                   stats.Stats.ExecStats.Count,
+		seeks:                          total(iterStats.SeekCount),
+		seeksInternal:                  total(iterStats.SeekCountInternal),
+		steps:                          total(iterStats.StepCount),
+		stepsInternal:                  total(iterStats.StepCountInternal),
+		blockBytes:                     total(iterStats.BlockBytes),
+		blockBytesInCache:              total(iterStats.BlockBytesInCache),
+		pointCount:                     total(iterStats.PointCount),
+		pointsCoveredByRangeTombstones: total(iterStats.PointsCoveredByRangeTombstones),
+		rangeKeySkippedPoints:          total(iterStats.RangeKeySkippedPoints),
+	}
+}
+
+// crdbInternalNodeStmtDiagAutoCaptureTable exposes the statement fingerprints
+// tracked by the local node for the automatic capture of diagnostics bundles.
+// See the sql.stmt_diagnostics.auto_capture.* cluster settings.
+var crdbInternalNodeStmtDiagAutoCaptureTable = virtualSchemaTable{
+	comment: `statement fingerprints tracked for the automatic capture of ` +
+		`diagnostics bundles (RAM; local node only)`,
+	schema: `
+CREATE TABLE crdb_internal.node_statement_diagnostics_auto_capture (
+  node_id           INT NOT NULL,
+  fingerprint       STRING NOT NULL,
+  window_start      TIMESTAMPTZ NOT NULL,
+  violations        INT NOT NULL,
+  last_violation_at TIMESTAMPTZ NOT NULL,
+  last_requested_at TIMESTAMPTZ,
+  last_request_id   INT,
+  throttled         INT NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		hasPriv, _, err := p.HasViewActivityOrViewActivityRedactedRole(ctx)
+		if err != nil {
+			return err
+		} else if !hasPriv {
+			return noViewActivityOrViewActivityRedactedRoleError(p.User())
+		}
+
+		nodeID, _ := p.execCfg.NodeInfo.NodeID.OptionalNodeID() // zero if not available
+		var alloc tree.DatumAlloc
+		for _, f := range p.execCfg.StmtDiagnosticsRecorder.AutoCaptureFingerprints() {
+			windowStart, err := tree.MakeDTimestampTZ(f.WindowStart, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			lastViolationAt, err := tree.MakeDTimestampTZ(f.LastViolationAt, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			lastRequestedAt := tree.DNull
+			if !f.LastRequestedAt.IsZero() {
+				if lastRequestedAt, err = tree.MakeDTimestampTZ(f.LastRequestedAt, time.Microsecond); err != nil {
+					return err
+				}
+			}
+			lastRequestID := tree.DNull
+			if f.LastRequestID != 0 {
+				lastRequestID = alloc.NewDInt(tree.DInt(f.LastRequestID))
+			}
+			if err := addRow(
+				alloc.NewDInt(tree.DInt(nodeID)),              // node_id
+				alloc.NewDString(tree.DString(f.Fingerprint)), // fingerprint
+				windowStart,                            // window_start
+				alloc.NewDInt(tree.DInt(f.Violations)), // violations
+				lastViolationAt,                        // last_violation_at
+				lastRequestedAt,                        // last_requested_at
+				lastRequestID,                          // last_request_id
+				alloc.NewDInt(tree.DInt(f.Throttled)),  // throttled
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // TODO(arul): Explore updating the schema below to have key be an INT and
 // statement_ids be INT[] now that we've moved to having uint64 as the type of
 // StmtFingerprintID and TxnKey. Issue #55284
@@ -2435,6 +2659,141 @@ CREATE TABLE crdb_internal.cluster_settings (
 	},
 }
 
+// clusterSettingHistoryLookback bounds the scan of system.eventlog done to
+// build crdb_internal.cluster_setting_history. system.eventlog is only indexed
+// by timestamp, so without it every event would be scanned.
+var clusterSettingHistoryLookback = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.crdb_internal.cluster_setting_history.lookback",
+	"how far back system.eventlog is scanned to build the crdb_internal.cluster_setting_history table",
+	30*24*time.Hour,
+	settings.PositiveDuration,
+)
+
+// clusterSettingHistoryLimit is the maximum number of changes listed by
+// crdb_internal.cluster_setting_history. The most recent changes are listed.
+const clusterSettingHistoryLimit = 1000
+
+// crdbInternalClusterSettingHistoryTable exposes the changes of cluster
+// settings and of cluster setting overrides for virtual clusters, as recorded
+// in system.eventlog. Changes are retained as long as other events, as
+// configured by server.eventlog.ttl, but only the most recent ones within
+// sql.crdb_internal.cluster_setting_history.lookback are listed.
+var crdbInternalClusterSettingHistoryTable = virtualSchemaTable{
+	comment: `history of recent cluster setting changes (KV scan of system.eventlog)`,
+	schema: `
+CREATE TABLE crdb_internal.cluster_setting_history (
+  timestamp        TIMESTAMP NOT NULL,
+  variable         STRING NOT NULL,
+  previous_value   STRING, -- NULL if empty or not recorded
+  value            STRING,
+  origin           STRING NOT NULL, -- 'local' or 'virtual-cluster-override'
+  tenant_id        INT,    -- the virtual cluster of an override; NULL if it applies to all
+  user_name        STRING,
+  application_name STRING,
+  statement        STRING
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) (retErr error) {
+		hasModify, err := p.HasGlobalPrivilegeOrRoleOption(ctx, privilege.MODIFYCLUSTERSETTING)
+		if err != nil {
+			return err
+		}
+		canViewAll := hasModify
+		if !canViewAll {
+			canViewAll, err = p.HasGlobalPrivilegeOrRoleOption(ctx, privilege.VIEWCLUSTERSETTING)
+			if err != nil {
+				return err
+			}
+		}
+		canViewSqlOnly := false
+		if !canViewAll {
+			canViewSqlOnly, err = p.HasGlobalPrivilegeOrRoleOption(ctx, privilege.MODIFYSQLCLUSTERSETTING)
+			if err != nil {
+				return err
+			}
+		}
+		if !canViewAll && !canViewSqlOnly {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"only users with %s, %s or %s system privileges are allowed to read "+
+					"crdb_internal.cluster_setting_history", privilege.MODIFYCLUSTERSETTING, privilege.MODIFYSQLCLUSTERSETTING, privilege.VIEWCLUSTERSETTING)
+		}
+
+		// Only scan the recent events, which are contiguous in the primary index
+		// of system.eventlog, and only keep the most recent changes among them.
+		lookback := clusterSettingHistoryLookback.Get(&p.ExecCfg().Settings.SV)
+		it, err := p.InternalSQLTxn().QueryIteratorEx(
+			ctx, "crdb-internal-cluster-setting-history", p.txn,
+			sessiondata.NodeUserSessionDataOverride, `
+SELECT timestamp,
+       "eventType",
+       info::JSONB->>'SettingName',
+       info::JSONB->>'PreviousValue',
+       info::JSONB->>'Value',
+       (info::JSONB->>'TenantId')::INT8,
+       info::JSONB->>'User',
+       info::JSONB->>'ApplicationName',
+       info::JSONB->>'Statement'
+  FROM (
+        SELECT timestamp, "uniqueID", "eventType", info
+          FROM system.eventlog
+         WHERE timestamp >= now()::TIMESTAMP - $1::INTERVAL
+           AND "eventType" IN ('set_cluster_setting', 'set_tenant_cluster_setting')
+         ORDER BY timestamp DESC, "uniqueID" DESC
+         LIMIT $2
+       )
+ ORDER BY timestamp, "uniqueID"`,
+			tree.NewDInterval(duration.MakeDuration(lookback.Nanoseconds(), 0 /* days */, 0 /* months */), types.DefaultIntervalTypeMetadata),
+			clusterSettingHistoryLimit,
+		)
+		if err != nil {
+			return err
+		}
+		defer func() { retErr = errors.CombineErrors(retErr, it.Close()) }()
+
+		forSystemTenant := p.ExecCfg().Codec.ForSystemTenant()
+		for {
+			ok, err := it.Next(ctx)
+			if err != nil || !ok {
+				return err
+			}
+			row := it.Cur()
+			name := tree.MustBeDString(row[2])
+			// If the user can only view sql.defaults settings, hide all other settings.
+			if canViewSqlOnly && !strings.HasPrefix(string(name), "sql.defaults") {
+				continue
+			}
+			previousValue, value := row[3], row[4]
+			setting, found, _ := settings.LookupForDisplay(settings.SettingName(name), forSystemTenant, hasModify)
+			if masked, ok := setting.(*settings.MaskedSetting); found && ok {
+				sv := &p.ExecCfg().Settings.SV
+				if previousValue != tree.DNull {
+					previousValue = tree.NewDString(masked.Mask(sv, string(tree.MustBeDString(previousValue))))
+				}
+				if value != tree.DNull {
+					value = tree.NewDString(masked.Mask(sv, string(tree.MustBeDString(value))))
+				}
+			}
+			origin := "local"
+			if tree.MustBeDString(row[1]) == "set_tenant_cluster_setting" {
+				origin = "virtual-cluster-override"
+			}
+			if err := addRow(
+				row[0],
+				tree.NewDString(string(name)),
+				previousValue,
+				value,
+				tree.NewDString(origin),
+				row[5],
+				row[6],
+				row[7],
+				row[8],
+			); err != nil {
+				return err
+			}
+		}
+	},
+}
+
 // crdbInternalSessionVariablesTable exposes the session variables.
 var crdbInternalSessionVariablesTable = virtualSchemaTable{
 	comment: `session variables (RAM)`,
@@ -5774,6 +6133,350 @@ CREATE TABLE crdb_internal.kv_store_status (
 	},
 }
 
+// crdbInternalKVStoreEncryptionKeysTable exposes the usage of the
+// encryption-at-rest data keys by the cluster stores. The nodes which can't
+// be reached are reported by a row with the error in place of the key ID.
+var crdbInternalKVStoreEncryptionKeysTable = virtualSchemaTable{
+	comment: "files/bytes using each encryption-at-rest data key of each store (cluster RPC; expensive!)",
+	schema: `
+CREATE TABLE crdb_internal.kv_store_encryption_keys (
+  node_id         INT NOT NULL,
+  store_id        INT,
+  key_id          STRING NOT NULL,
+  active          BOOL,
+  files           INT,
+  bytes           INT,
+  fraction        FLOAT,
+  rewrite_running BOOL
+)
+	`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.CheckPrivilege(ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.VIEWCLUSTERMETADATA); err != nil {
+			return err
+		}
+		ss, err := p.ExecCfg().NodesStatusServer.OptionalNodesStatusServer()
+		if err != nil {
+			return err
+		}
+		nodes, err := ss.ListNodesInternal(ctx, &serverpb.NodesRequest{})
+		if err != nil {
+			return err
+		}
+		for _, n := range nodes.Nodes {
+			nodeID := n.Desc.NodeID
+			switch nodes.LivenessByNodeID[nodeID] {
+			case livenesspb.NodeLivenessStatus_DEAD, livenesspb.NodeLivenessStatus_DECOMMISSIONED:
+				// Don't wait on nodes that won't respond.
+				continue
+			}
+			resp, err := ss.Stores(ctx, &serverpb.StoresRequest{NodeId: nodeID.String()})
+			if err != nil {
+				log.Warningf(ctx, "%v", err)
+				// Add a row with this node ID, the error for key ID, and nulls for
+				// all other columns, so that the stores of the other nodes are
+				// still reported.
+				if err := addRow(
+					tree.NewDInt(tree.DInt(nodeID)),    // node ID
+					tree.DNull,                         // store ID
+					tree.NewDString("-- "+err.Error()), // key ID
+					tree.DNull,                         // active
+					tree.DNull,                         // files
+					tree.DNull,                         // bytes
+					tree.DNull,                         // fraction
+					tree.DNull,                         // rewrite_running
+				); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, s := range resp.Stores {
+				for _, ks := range s.KeyStats {
+					// The fraction of the store's data encrypted with the key.
+					var fraction float64
+					if s.TotalBytes > 0 {
+						fraction = float64(ks.Bytes) / float64(s.TotalBytes)
+					}
+					if err := addRow(
+						tree.NewDInt(tree.DInt(s.NodeID)),
+						tree.NewDInt(tree.DInt(s.StoreID)),
+						tree.NewDString(ks.KeyID),
+						tree.MakeDBool(tree.DBool(ks.KeyID == s.ActiveKeyID)),
+						tree.NewDInt(tree.DInt(ks.Files)),
+						tree.NewDInt(tree.DInt(ks.Bytes)),
+						tree.NewDFloat(tree.DFloat(fraction)),
+						tree.MakeDBool(tree.DBool(s.InactiveKeyRewriteRunning)),
+					); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// crdbInternalRaftStatusTable exposes the raft status of every replica in the
+// cluster, along with the leader's view of the replication progress of each
+// of its followers.
+var crdbInternalRaftStatusTable = virtualSchemaTable{
+	comment: "raft status and follower progress of each replica (cluster RPC; expensive!)",
+	schema: `
+CREATE TABLE crdb_internal.raft_status (
+  node_id              INT NOT NULL,
+  store_id             INT NOT NULL,
+  range_id             INT NOT NULL,
+  replica_id           INT NOT NULL,
+  raft_state           STRING NOT NULL,
+  term                 INT NOT NULL,
+  commit_index         INT NOT NULL,
+  applied_index        INT NOT NULL,
+  lead                 INT NOT NULL,
+  lead_transferee      INT NOT NULL,
+  follower_replica_id  INT,
+  follower_state       STRING,
+  match_index          INT,
+  next_index           INT,
+  sent_commit_index    INT,
+  pending_snapshot     INT,
+  inflight_count       INT,
+  inflight_bytes       INT,
+  recent_active        BOOL,
+  is_learner           BOOL,
+  paused               BOOL,
+  pause_reasons        STRING[],
+  apply_lag            INT NOT NULL,
+  oldest_unapplied_age INTERVAL NOT NULL
+)
+	`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.CheckPrivilege(ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.VIEWCLUSTERMETADATA); err != nil {
+			return err
+		}
+		// numProgressCols is the number of columns describing the progress of a
+		// follower, which are NULL for replicas that aren't the leader.
+		const numProgressCols = 12
+		return forEachRangeInfoOnLiveNodes(ctx, p, func(resp *serverpb.RangesResponse) error {
+			for _, r := range resp.Ranges {
+				rs := &r.RaftState
+				var applyLag uint64
+				if rs.HardState.Commit > rs.Applied {
+					applyLag = rs.HardState.Commit - rs.Applied
+				}
+				replicaCols := tree.Datums{
+					tree.NewDInt(tree.DInt(r.SourceNodeID)),
+					tree.NewDInt(tree.DInt(r.SourceStoreID)),
+					tree.NewDInt(tree.DInt(r.State.Desc.RangeID)),
+					tree.NewDInt(tree.DInt(rs.ReplicaID)),
+					tree.NewDString(rs.State),
+					tree.NewDInt(tree.DInt(rs.HardState.Term)),
+					tree.NewDInt(tree.DInt(rs.HardState.Commit)),
+					tree.NewDInt(tree.DInt(rs.Applied)),
+					tree.NewDInt(tree.DInt(rs.Lead)),
+					tree.NewDInt(tree.DInt(rs.LeadTransferee)),
+				}
+				// The columns describing the application of the log by the replica
+				// come last, after the progress columns.
+				applyCols := tree.Datums{
+					tree.NewDInt(tree.DInt(applyLag)),
+					tree.NewDInterval(
+						duration.MakeDuration(r.State.OldestUnappliedEntryAgeNanos, 0 /* days */, 0 /* months */),
+						types.DefaultIntervalTypeMetadata,
+					),
+				}
+				if len(rs.Progress) == 0 {
+					// Only the leader tracks the progress of followers. Emit a single row
+					// for the replica, with the progress columns left NULL.
+					row := append(tree.Datums(nil), replicaCols...)
+					for i := 0; i < numProgressCols; i++ {
+						row = append(row, tree.DNull)
+					}
+					row = append(row, applyCols...)
+					if err := addRow(row...); err != nil {
+						return err
+					}
+					continue
+				}
+
+				followerIDs := make([]uint64, 0, len(rs.Progress))
+				for id := range rs.Progress {
+					followerIDs = append(followerIDs, id)
+				}
+				sort.Slice(followerIDs, func(i, j int) bool { return followerIDs[i] < followerIDs[j] })
+				for _, id := range followerIDs {
+					pr := rs.Progress[id]
+					pauseReasons := tree.NewDArray(types.String)
+					for _, reason := range pr.PauseReasons {
+						if err := pauseReasons.Append(tree.NewDString(reason)); err != nil {
+							return err
+						}
+					}
+					row := append(append(tree.Datums(nil), replicaCols...),
+						tree.NewDInt(tree.DInt(id)),
+						tree.NewDString(pr.State),
+						tree.NewDInt(tree.DInt(pr.Match)),
+						tree.NewDInt(tree.DInt(pr.Next)),
+						tree.NewDInt(tree.DInt(pr.SentCommit)),
+						tree.NewDInt(tree.DInt(pr.PendingSnapshot)),
+						tree.NewDInt(tree.DInt(pr.InflightCount)),
+						tree.NewDInt(tree.DInt(pr.InflightBytes)),
+						tree.MakeDBool(tree.DBool(pr.RecentActive)),
+						tree.MakeDBool(tree.DBool(pr.IsLearner)),
+						tree.MakeDBool(tree.DBool(pr.Paused)),
+						pauseReasons,
+					)
+					row = append(row, applyCols...)
+					if err := addRow(row...); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+	},
+}
+
+// crdbInternalRaftProposalQuotaTable exposes the state of the proposal quota
+// pool of every raft leader in the cluster. Leaders hand out proposal quota
+// for the raft log entries they propose and only get it back once all of their
+// followers have caught up, so a slow follower makes proposals wait for quota,
+// which shows up here as waiters.
+var crdbInternalRaftProposalQuotaTable = virtualSchemaTable{
+	comment: "proposal quota pool state of each raft leader (cluster RPC; expensive!)",
+	schema: `
+CREATE TABLE crdb_internal.raft_proposal_quota (
+  node_id          INT NOT NULL,
+  store_id         INT NOT NULL,
+  range_id         INT NOT NULL,
+  capacity_bytes   INT NOT NULL,
+  available_bytes  INT NOT NULL,
+  waiters          INT NOT NULL,
+  longest_wait     INTERVAL NOT NULL
+)
+	`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.CheckPrivilege(ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.VIEWCLUSTERMETADATA); err != nil {
+			return err
+		}
+		return forEachRangeInfoOnLiveNodes(ctx, p, func(resp *serverpb.RangesResponse) error {
+			for _, r := range resp.Ranges {
+				// Only leaders have a proposal quota pool.
+				if r.State.ProposalQuotaCapacity == 0 {
+					continue
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(r.SourceNodeID)),
+					tree.NewDInt(tree.DInt(r.SourceStoreID)),
+					tree.NewDInt(tree.DInt(r.State.Desc.RangeID)),
+					tree.NewDInt(tree.DInt(r.State.ProposalQuotaCapacity)),
+					tree.NewDInt(tree.DInt(r.State.ApproximateProposalQuota)),
+					tree.NewDInt(tree.DInt(r.State.ProposalQuotaWaiters)),
+					tree.NewDInterval(
+						duration.MakeDuration(r.State.ProposalQuotaLongestWaitNanos, 0 /* days */, 0 /* months */),
+						types.DefaultIntervalTypeMetadata,
+					),
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	},
+}
+
+// crdbInternalLoadBasedSplitDecisionsTable exposes, for every replica in the
+// cluster, the inputs of the last decision of its load-based splitter to split
+// the range, so that operators can understand why (or at which key) a range
+// was split based on load.
+var crdbInternalLoadBasedSplitDecisionsTable = virtualSchemaTable{
+	comment: "last load-based split decision of each replica (cluster RPC; expensive!)",
+	schema: `
+CREATE TABLE crdb_internal.load_based_split_decisions (
+  node_id                INT NOT NULL,
+  store_id               INT NOT NULL,
+  range_id               INT NOT NULL,
+  decided_at             TIMESTAMPTZ NOT NULL,
+  objective              STRING NOT NULL,
+  threshold              FLOAT NOT NULL,
+  last_load              FLOAT NOT NULL,
+  max_load               FLOAT,
+  split_key              BYTES NOT NULL,
+  pretty_split_key       STRING NOT NULL,
+  popular_key_frequency  FLOAT NOT NULL,
+  splitter_state         STRING NOT NULL
+)
+	`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.CheckPrivilege(ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.VIEWCLUSTERMETADATA); err != nil {
+			return err
+		}
+		return forEachRangeInfoOnLiveNodes(ctx, p, func(resp *serverpb.RangesResponse) error {
+			for _, r := range resp.Ranges {
+				d := r.State.LoadSplitDecision
+				if d == nil {
+					continue
+				}
+				decidedAt, err := tree.MakeDTimestampTZ(timeutil.Unix(0, d.TimeNanos), time.Microsecond)
+				if err != nil {
+					return err
+				}
+				maxLoad := tree.DNull
+				if d.MaxLoadOk {
+					maxLoad = tree.NewDFloat(tree.DFloat(d.MaxLoad))
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(r.SourceNodeID)),
+					tree.NewDInt(tree.DInt(r.SourceStoreID)),
+					tree.NewDInt(tree.DInt(r.State.Desc.RangeID)),
+					decidedAt,
+					tree.NewDString(d.Objective),
+					tree.NewDFloat(tree.DFloat(d.Threshold)),
+					tree.NewDFloat(tree.DFloat(d.LastLoad)),
+					maxLoad,
+					tree.NewDBytes(tree.DBytes(d.SplitKey)),
+					tree.NewDString(keys.PrettyPrint(nil /* valDirs */, d.SplitKey)),
+					tree.NewDFloat(tree.DFloat(d.PopularKeyFrequency)),
+					tree.NewDString(d.SplitterState),
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	},
+}
+
+// forEachRangeInfoOnLiveNodes calls fn with the response of the status
+// server's Ranges RPC for each node in the cluster that isn't dead or
+// decommissioned.
+func forEachRangeInfoOnLiveNodes(
+	ctx context.Context, p *planner, fn func(resp *serverpb.RangesResponse) error,
+) error {
+	ss, err := p.ExecCfg().NodesStatusServer.OptionalNodesStatusServer()
+	if err != nil {
+		return err
+	}
+	nodes, err := ss.ListNodesInternal(ctx, &serverpb.NodesRequest{})
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes.Nodes {
+		nodeID := n.Desc.NodeID
+		switch nodes.LivenessByNodeID[nodeID] {
+		case livenesspb.NodeLivenessStatus_DEAD, livenesspb.NodeLivenessStatus_DECOMMISSIONED:
+			// Don't wait on nodes that won't respond.
+			continue
+		}
+		resp, err := ss.Ranges(ctx, &serverpb.RangesRequest{NodeId: nodeID.String()})
+		if err != nil {
+			return err
+		}
+		if err := fn(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var crdbInternalCatalogDescriptorTable = virtualSchemaTable{
 	comment: `like system.descriptor but overlaid with in-txn in-memory changes and including virtual objects`,
 	schema: `
@@ -7556,50 +8259,247 @@ ORDER BY
 	comment: "kv_dropped_relations contains all dropped relations waiting for garbage collection",
 }
 
-// crdbInternalTenantUsageDetailsView, exposes system ranges.
-var crdbInternalTenantUsageDetailsView = virtualSchemaView{
+// crdbInternalTenantUsageDetailsTable exposes the total consumption of
+// tenants. In the system tenant, it contains a row for each tenant, as recorded
+// in system.tenant_usage. In other tenants, it contains a row for each of the
+// recent snapshots of the consumption of the tenant itself, as reported by the
+// host cluster to this SQL instance, so that tenants can analyze their own
+// costs over time.
+var crdbInternalTenantUsageDetailsTable = virtualSchemaTable{
+	comment: `total resource consumption of tenants, as recorded by the host cluster`,
 	schema: `
-CREATE VIEW crdb_internal.tenant_usage_details AS
-  SELECT
-    tenant_id,
-    (j->>'rU')::FLOAT8 AS total_ru,
-    (j->>'readBytes')::INT8 AS total_read_bytes,
-    (j->>'readRequests')::INT8 AS total_read_requests,
-    (j->>'writeBytes')::INT8 AS total_write_bytes,
-    (j->>'writeRequests')::INT8 AS total_write_requests,
-    (j->>'sqlPodsCpuSeconds')::FLOAT8 AS total_sql_pod_seconds,
-    (j->>'pgwireEgressBytes')::INT8 AS total_pgwire_egress_bytes,
-    (j->>'externalIOIngressBytes')::INT8 AS total_external_io_ingress_bytes,
-    (j->>'externalIOIngressBytes')::INT8 AS total_external_io_ingress_bytes,
-    (j->>'kvRU')::FLOAT8 AS total_kv_ru,
-    (j->>'crossRegionNetworkRU')::FLOAT8 AS total_cross_region_network_ru
-  FROM
-    (
-      SELECT
-        tenant_id,
-        crdb_internal.pb_to_json('cockroach.roachpb.TenantConsumption', total_consumption) AS j
-      FROM
-        system.tenant_usage
-      WHERE
-        instance_id = 0
-    )
-`,
-	resultColumns: colinfo.ResultColumns{
-		{Name: "tenant_id", Typ: types.Int},
-		{Name: "total_ru", Typ: types.Float},
-		{Name: "total_read_bytes", Typ: types.Int},
-		{Name: "total_read_requests", Typ: types.Int},
-		{Name: "total_write_bytes", Typ: types.Int},
-		{Name: "total_write_requests", Typ: types.Int},
-		{Name: "total_sql_pod_seconds", Typ: types.Float},
-		{Name: "total_pgwire_egress_bytes", Typ: types.Int},
-		{Name: "total_external_io_ingress_bytes", Typ: types.Int},
-		{Name: "total_external_io_egress_bytes", Typ: types.Int},
-		{Name: "total_kv_ru", Typ: types.Float},
-		{Name: "total_cross_region_network_ru", Typ: types.Float},
+CREATE TABLE crdb_internal.tenant_usage_details (
+  tenant_id                       INT8,
+  total_ru                        FLOAT8,
+  total_read_bytes                INT8,
+  total_read_requests             INT8,
+  total_write_bytes               INT8,
+  total_write_requests            INT8,
+  total_sql_pod_seconds           FLOAT8,
+  total_pgwire_egress_bytes       INT8,
+  total_external_io_ingress_bytes INT8,
+  total_external_io_egress_bytes  INT8,
+  total_kv_ru                     FLOAT8,
+  total_cross_region_network_ru   FLOAT8,
+  as_of                           TIMESTAMPTZ
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if isAdmin, err := p.HasAdminRole(ctx); err != nil {
+			return err
+		} else if !isAdmin {
+			return pgerror.New(pgcode.InsufficientPrivilege,
+				"only users with the admin role can read crdb_internal.tenant_usage_details")
+		}
+		addConsumptionRow := func(tenantID uint64, c *kvpb.TenantConsumption, asOf time.Time) error {
+			ts, err := tree.MakeDTimestampTZ(asOf, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			return addRow(
+				tree.NewDInt(tree.DInt(tenantID)),
+				tree.NewDFloat(tree.DFloat(c.RU)),
+				tree.NewDInt(tree.DInt(c.ReadBytes)),
+				tree.NewDInt(tree.DInt(c.ReadRequests)),
+				tree.NewDInt(tree.DInt(c.WriteBytes)),
+				tree.NewDInt(tree.DInt(c.WriteRequests)),
+				tree.NewDFloat(tree.DFloat(c.SQLPodsCPUSeconds)),
+				tree.NewDInt(tree.DInt(c.PGWireEgressBytes)),
+				tree.NewDInt(tree.DInt(c.ExternalIOIngressBytes)),
+				tree.NewDInt(tree.DInt(c.ExternalIOEgressBytes)),
+				tree.NewDFloat(tree.DFloat(c.KVRU)),
+				tree.NewDFloat(tree.DFloat(c.CrossRegionNetworkRU)),
+				ts,
+			)
+		}
+
+		if !p.ExecCfg().Codec.ForSystemTenant() {
+			costController := p.ExecCfg().DistSQLSrv.TenantCostController
+			if costController == nil {
+				return nil
+			}
+			_, tenantID, err := keys.DecodeTenantPrefix(p.ExecCfg().Codec.TenantPrefix())
+			if err != nil {
+				return err
+			}
+			for _, snapshot := range costController.GetHostConsumptionHistory() {
+				if err := addConsumptionRow(tenantID.ToUint64(), &snapshot.Consumption, snapshot.Time); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		rows, err := p.InternalSQLTxn().QueryBufferedEx(
+			ctx, "crdb-internal-tenant-usage-details", p.txn, sessiondata.NodeUserSessionDataOverride,
+			`SELECT tenant_id, total_consumption, last_update FROM system.tenant_usage WHERE instance_id = 0`,
+		)
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			var consumption kvpb.TenantConsumption
+			if r[1] != tree.DNull {
+				if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(r[1])), &consumption); err != nil {
+					return err
+				}
+			}
+			lastUpdate := tree.MustBeDTimestamp(r[2])
+			if err := addConsumptionRow(uint64(tree.MustBeDInt(r[0])), &consumption, lastUpdate.Time); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// nodeIndexReadUsageLimit is the maximum number of indexes listed by
+// crdb_internal.node_index_read_usage.
+const nodeIndexReadUsageLimit = 100
+
+// crdbInternalNodeIndexReadUsageTable exposes the indexes with the highest read
+// cost on this SQL instance, as estimated by the tenant cost controller from a
+// sample of KV reads, so that tenants can find indexes that are scanned
+// wastefully. It is empty in the system tenant, which is not metered.
+var crdbInternalNodeIndexReadUsageTable = virtualSchemaTable{
+	comment: `request units consumed by reads of the indexes with the highest read cost, ` +
+		`estimated from a sample of KV reads (RAM; local SQL instance only)`,
+	schema: `
+CREATE TABLE crdb_internal.node_index_read_usage (
+  node_id         INT NOT NULL,
+  rank            INT NOT NULL,
+  table_id        INT NOT NULL,
+  index_id        INT NOT NULL,
+  table_name      STRING,
+  index_name      STRING,
+  ru              FLOAT NOT NULL,
+  read_bytes      INT NOT NULL,
+  read_requests   INT NOT NULL,
+  sampled_batches INT NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		hasPriv, _, err := p.HasViewActivityOrViewActivityRedactedRole(ctx)
+		if err != nil {
+			return err
+		} else if !hasPriv {
+			return noViewActivityOrViewActivityRedactedRoleError(p.User())
+		}
+
+		costController := p.ExecCfg().DistSQLSrv.TenantCostController
+		if costController == nil {
+			return nil
+		}
+		instanceID := tree.NewDInt(tree.DInt(p.execCfg.NodeInfo.NodeID.SQLInstanceID()))
+		for i, u := range costController.GetIndexReadUsage(nodeIndexReadUsageLimit) {
+			// The names are NULL if the table or index was dropped.
+			tableName, indexName := tree.DNull, tree.DNull
+			tableDesc, err := p.Descriptors().ByIDWithLeased(p.txn).WithoutNonPublic().Get().Table(
+				ctx, descpb.ID(u.TableID),
+			)
+			if err == nil {
+				tableName = tree.NewDString(tableDesc.GetName())
+				if idx, err := catalog.MustFindIndexByID(tableDesc, descpb.IndexID(u.IndexID)); err == nil {
+					indexName = tree.NewDString(idx.GetName())
+				}
+			}
+			if err := addRow(
+				instanceID,
+				tree.NewDInt(tree.DInt(i+1)),
+				tree.NewDInt(tree.DInt(u.TableID)),
+				tree.NewDInt(tree.DInt(u.IndexID)),
+				tableName,
+				indexName,
+				tree.NewDFloat(tree.DFloat(u.RU)),
+				tree.NewDInt(tree.DInt(u.ReadBytes)),
+				tree.NewDInt(tree.DInt(u.ReadRequests)),
+				tree.NewDInt(tree.DInt(u.SampledBatches)),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+const rangeCostsSchemaPattern = `
+CREATE TABLE crdb_internal.%s (
+  node_id         INT NOT NULL,
+  range_id        INT NOT NULL,
+  since           TIMESTAMPTZ NOT NULL,
+  ru              FLOAT NOT NULL,
+  kv_cpu_seconds  FLOAT NOT NULL,
+  read_bytes      INT NOT NULL,
+  write_bytes     INT NOT NULL,
+  sampled_batches INT NOT NULL
+)`
+
+// crdbInternalClusterRangeCostsTable exposes the recent cost of the requests
+// of every SQL instance of the tenant to each range, as estimated by their
+// tenant cost controllers from a sample of KV requests. It is used by SHOW
+// RANGES WITH COSTS. There is one row per range and SQL instance that sent
+// requests to it. It is empty in the system tenant, which is not metered.
+var crdbInternalClusterRangeCostsTable = virtualSchemaTable{
+	comment: `recent request units consumed by requests to each range, ` +
+		`estimated from a sample of KV requests (cluster RPC; expensive!)`,
+	schema: fmt.Sprintf(rangeCostsSchemaPattern, "cluster_range_costs"),
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return populateRangeCosts(ctx, p, addRow, &serverpb.RangeCostsRequest{})
 	},
 }
 
+// crdbInternalNodeRangeCostsTable is like crdbInternalClusterRangeCostsTable,
+// but only exposes the costs of the requests of this SQL instance.
+var crdbInternalNodeRangeCostsTable = virtualSchemaTable{
+	comment: `recent request units consumed by requests to each range, ` +
+		`estimated from a sample of KV requests (RAM; local SQL instance only)`,
+	schema: fmt.Sprintf(rangeCostsSchemaPattern, "node_range_costs"),
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return populateRangeCosts(ctx, p, addRow, &serverpb.RangeCostsRequest{NodeID: "local"})
+	},
+}
+
+func populateRangeCosts(
+	ctx context.Context,
+	p *planner,
+	addRow func(...tree.Datum) error,
+	request *serverpb.RangeCostsRequest,
+) error {
+	hasPriv, _, err := p.HasViewActivityOrViewActivityRedactedRole(ctx)
+	if err != nil {
+		return err
+	} else if !hasPriv {
+		return noViewActivityOrViewActivityRedactedRoleError(p.User())
+	}
+
+	response, err := p.extendedEvalCtx.SQLStatusServer.RangeCosts(ctx, request)
+	if err != nil {
+		return err
+	}
+	for _, encodedErr := range response.Errors {
+		// The costs of the SQL instances which could not be reached are
+		// missing, which only makes the result incomplete.
+		log.Warningf(ctx, "%v", errors.DecodeError(ctx, encodedErr))
+	}
+	for _, c := range response.Costs {
+		since, err := tree.MakeDTimestampTZ(c.Since, time.Microsecond)
+		if err != nil {
+			return err
+		}
+		if err := addRow(
+			tree.NewDInt(tree.DInt(c.NodeID)),
+			tree.NewDInt(tree.DInt(c.RangeID)),
+			since,
+			tree.NewDFloat(tree.DFloat(c.RU)),
+			tree.NewDFloat(tree.DFloat(c.KVCPUSeconds)),
+			tree.NewDInt(tree.DInt(c.ReadBytes)),
+			tree.NewDInt(tree.DInt(c.WriteBytes)),
+			tree.NewDInt(tree.DInt(c.SampledBatches)),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var crdbInternalTransactionContentionEventsTable = virtualSchemaTable{
 	comment: `cluster-wide transaction contention events. Querying this table is an
 		expensive operation since it creates a cluster-wide RPC-fanout.`,
@@ -7833,6 +8733,170 @@ func generateTableSpan(
 	)
 }
 
+// crdbInternalTableMVCCGarbageTable exposes, per table, the bytes of MVCC
+// garbage (i.e. non-live bytes) awaiting GC. The statistics are aggregated from
+// the MVCC stats of the table's ranges, which are maintained incrementally.
+var crdbInternalTableMVCCGarbageTable = virtualSchemaTable{
+	comment: `MVCC garbage awaiting GC per table (cluster RPC; expensive!)`,
+	schema: `
+CREATE TABLE crdb_internal.table_mvcc_garbage (
+  table_id               INT NOT NULL,
+  database_name          STRING NOT NULL,
+  schema_name            STRING NOT NULL,
+  table_name             STRING NOT NULL,
+  live_bytes             INT NOT NULL,
+  total_bytes            INT NOT NULL,
+  garbage_bytes          INT NOT NULL,
+  garbage_fraction       FLOAT NOT NULL,
+  avg_garbage_age        INTERVAL NOT NULL,
+  est_oldest_garbage_age INTERVAL NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, db catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		hasPriv, _, err := p.HasViewActivityOrViewActivityRedactedRole(ctx)
+		if err != nil {
+			return err
+		} else if !hasPriv {
+			return noViewActivityOrViewActivityRedactedRoleError(p.User())
+		}
+
+		type tableInfo struct {
+			id                        descpb.ID
+			dbName, scName, tableName string
+			span                      roachpb.Span
+		}
+		var tables []tableInfo
+		if err := forEachTableDesc(ctx, p, db, hideVirtual,
+			func(ctx context.Context, db catalog.DatabaseDescriptor, sc catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+				if !table.IsPhysicalTable() {
+					return nil
+				}
+				start := p.ExecCfg().Codec.TablePrefix(uint32(table.GetID()))
+				tables = append(tables, tableInfo{
+					id:        table.GetID(),
+					dbName:    db.GetName(),
+					scName:    sc.GetName(),
+					tableName: table.GetName(),
+					span:      roachpb.Span{Key: start, EndKey: start.PrefixEnd()},
+				})
+				return nil
+			}); err != nil {
+			return err
+		}
+
+		now := timeutil.Now().UnixNano()
+		makeInterval := func(seconds float64) tree.Datum {
+			return tree.NewDInterval(
+				duration.MakeDuration(int64(seconds*float64(time.Second)), 0 /* days */, 0 /* months */),
+				types.DefaultIntervalTypeMetadata,
+			)
+		}
+		batchLimit := int(builtins.SpanStatsBatchLimit.Get(&p.ExecCfg().Settings.SV))
+		for len(tables) > 0 {
+			batch := tables[:min(batchLimit, len(tables))]
+			tables = tables[len(batch):]
+			spans := make(roachpb.Spans, len(batch))
+			for i := range batch {
+				spans[i] = batch[i].span
+			}
+			resp, err := p.SpanStats(ctx, spans)
+			if err != nil {
+				return err
+			}
+			for _, t := range batch {
+				stats, ok := resp.SpanToStats[t.span.String()]
+				if !ok {
+					return errors.AssertionFailedf("could not find span stats for table span: %s", t.span)
+				}
+				ms := stats.TotalStats
+				var garbageFraction float64
+				if total := ms.Total(); total > 0 {
+					garbageFraction = float64(ms.GCBytes()) / float64(total)
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(t.id)),
+					tree.NewDString(t.dbName),
+					tree.NewDString(t.scName),
+					tree.NewDString(t.tableName),
+					tree.NewDInt(tree.DInt(ms.LiveBytes)),
+					tree.NewDInt(tree.DInt(ms.Total())),
+					tree.NewDInt(tree.DInt(ms.GCBytes())),
+					tree.NewDFloat(tree.DFloat(garbageFraction)),
+					makeInterval(ms.AvgGCBytesAge(now)),
+					makeInterval(ms.EstimatedOldestGCBytesAge(now)),
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// crdbInternalClusterTxnWaitForGraphTable exposes the current wait-for graph of
+// the transactions of the cluster, i.e. which transactions are waiting for
+// locks held by which other transactions, along with their statements.
+var crdbInternalClusterTxnWaitForGraphTable = virtualSchemaTable{
+	comment: `cluster-wide wait-for graph of transactions waiting for locks. Querying
+		this table is an expensive operation since it creates a cluster-wide RPC-fanout.`,
+	schema: `
+CREATE TABLE crdb_internal.cluster_txn_wait_for_graph (
+    waiter_txn_id                 UUID NOT NULL,
+    holder_txn_id                 UUID NOT NULL,
+    range_id                      INT NOT NULL,
+    lock_key                      BYTES,
+    lock_key_pretty               STRING,
+    lock_strength                 STRING NOT NULL,
+    wait_duration                 INTERVAL NOT NULL,
+    waiter_statement_fingerprint  STRING,
+    holder_statement_fingerprint  STRING,
+    deadlocked                    BOOL NOT NULL
+);`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		// Check permission first before making RPC fanout.
+		hasPermission, _, err := p.HasViewActivityOrViewActivityRedactedRole(ctx)
+		if err != nil {
+			return err
+		}
+		if !hasPermission {
+			return noViewActivityOrViewActivityRedactedRoleError(p.User())
+		}
+		resp, err := p.extendedEvalCtx.SQLStatusServer.TxnWaitForGraph(ctx, &serverpb.TxnWaitForGraphRequest{})
+		if err != nil {
+			return err
+		}
+		stringOrNull := func(s string) tree.Datum {
+			if s == "" {
+				return tree.DNull
+			}
+			return tree.NewDString(s)
+		}
+		for _, e := range resp.Edges {
+			lockKey := tree.DNull
+			if e.Key != nil {
+				lockKey = tree.NewDBytes(tree.DBytes(e.Key))
+			}
+			if err := addRow(
+				tree.NewDUuid(tree.DUuid{UUID: e.WaiterTxnID}),
+				tree.NewDUuid(tree.DUuid{UUID: e.HolderTxnID}),
+				tree.NewDInt(tree.DInt(e.RangeID)),
+				lockKey,
+				stringOrNull(e.PrettyKey),
+				tree.NewDString(e.LockStrength),
+				tree.NewDInterval(
+					duration.MakeDuration(e.WaitDuration.Nanoseconds(), 0 /* days */, 0 /* months */),
+					types.DefaultIntervalTypeMetadata,
+				),
+				stringOrNull(e.WaiterStatementFingerprint),
+				stringOrNull(e.HolderStatementFingerprint),
+				tree.MakeDBool(tree.DBool(e.Deadlocked)),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // crdbInternalClusterLocksTable exposes the state of locks, as well as lock waiters,
 // in range lock tables across the cluster.
 var crdbInternalClusterLocksTable = virtualSchemaTable{
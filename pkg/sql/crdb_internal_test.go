@@ -1873,3 +1873,53 @@ func TestVirtualPTSTable(t *testing.T) {
 		require.Equal(t, ts, virtualRow.lastUpdated)
 	})
 }
+
+// TestNodeStatementIteratorStats verifies that storage iterator stats collected
+// by sampled executions are attributed to the statement fingerprint and that
+// fingerprints are ranked by the block bytes they read.
+func TestNodeStatementIteratorStats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING sql.txn_stats.sample_rate = 1`)
+	sqlDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+	sqlDB.Exec(t, `INSERT INTO t SELECT i, repeat('a', 100) FROM generate_series(1, 1000) AS g(i)`)
+	sqlDB.Exec(t, `SET application_name = 'iter_stats'`)
+	for i := 0; i < 3; i++ {
+		sqlDB.Exec(t, `SELECT count(*) FROM t`)
+	}
+
+	testutils.SucceedsSoon(t, func() error {
+		var rank, count, sampledCount int
+		var seeks, steps float64
+		row := db.QueryRow(`
+SELECT rank, count, sampled_count, seeks, steps
+  FROM crdb_internal.node_statement_iterator_stats
+ WHERE application_name = 'iter_stats' AND key LIKE 'SELECT count(*) FROM t%'`)
+		if err := row.Scan(&rank, &count, &sampledCount, &seeks, &steps); err != nil {
+			return err
+		}
+		if count < 3 || sampledCount < 1 {
+			return errors.Newf("expected at least 3 executions with samples, got %d (%d sampled)",
+				count, sampledCount)
+		}
+		if rank < 1 || seeks <= 0 || steps <= 0 {
+			return errors.Newf("unexpected iterator stats rank=%d seeks=%f steps=%f", rank, seeks, steps)
+		}
+		return nil
+	})
+
+	// The rank column must be consistent with the block_bytes ordering.
+	rows := sqlDB.QueryStr(t, `
+SELECT count(*) FROM (
+  SELECT rank, block_bytes,
+         lag(block_bytes) OVER (ORDER BY rank) AS prev
+    FROM crdb_internal.node_statement_iterator_stats
+) WHERE prev < block_bytes`)
+	require.Equal(t, [][]string{{"0"}}, rows)
+}
@@ -94,7 +94,6 @@ func TestCreateAsVTable(t *testing.T) {
 					`"".crdb_internal.kv_node_liveness`:               {},
 					`"".crdb_internal.kv_store_status`:                {},
 					`"".crdb_internal.node_tenant_capabilities_cache`: {},
-					`"".crdb_internal.tenant_usage_details`:           {},
 				}
 				if _, ok := onlySystemTenant[fqName]; ok {
 					continue
@@ -38,6 +38,7 @@ func constructSelectQuery(n *tree.ShowJobs) string {
 	if n.Jobs != nil {
 		baseQuery.WriteString(`, trace_id, execution_errors`)
 	}
+	baseQuery.WriteString(`, request_units, estimated_cpu_seconds`)
 
 	// Check if there are any SHOW JOBS options that we need to add columns for.
 	if n.Options != nil {
@@ -51,6 +51,15 @@ import (
 // - otherwise, only data from crdb_internal.ranges_no_leases is included.
 //
 // Then:
+// - if WITH COSTS is specified, the recent cost of the requests of all
+//   the SQL instances to each range is included, as of
+//   crdb_internal.cluster_range_costs. The costs are those of the whole
+//   range: when a range contains multiple tables or indexes, they include
+//   the requests to all of them, and they are repeated on the row of
+//   each table or index listed for the range with WITH TABLES or WITH
+//   INDEXES. They must not be summed across the rows of such a range.
+//
+// Then:
 // - if WITH EXPLAIN is specified, the statement simply returns the
 //   text of the SQL query it would use if WITH EXPLAIN was not
 //   specified. This can be used for learning or troubleshooting.
@@ -425,7 +434,32 @@ all_span_stats AS (
 			endKey,
 		)
 	}
-	buf.WriteString("\nFROM named_ranges r)\n")
+	// If costs were requested, also include the recent costs of the
+	// ranges, summed over the SQL instances that sent requests to them.
+	// Ranges without recorded costs have none.
+	if n.Options.Costs {
+		buf.WriteString(`,
+  c.since AS costs_since,
+  COALESCE(c.ru, 0) AS ru,
+  COALESCE(c.kv_cpu_seconds, 0) AS kv_cpu_seconds,
+  COALESCE(c.read_bytes, 0) AS read_bytes,
+  COALESCE(c.write_bytes, 0) AS write_bytes`)
+	}
+	buf.WriteString("\nFROM named_ranges r")
+	if n.Options.Costs {
+		buf.WriteString(`
+LEFT OUTER JOIN (
+  SELECT range_id,
+         min(since) AS since,
+         sum(ru) AS ru,
+         sum(kv_cpu_seconds) AS kv_cpu_seconds,
+         sum(read_bytes)::INT AS read_bytes,
+         sum(write_bytes)::INT AS write_bytes
+    FROM crdb_internal.cluster_range_costs
+GROUP BY range_id
+) c ON c.range_id = r.range_id`)
+	}
+	buf.WriteString(")\n")
 
 	// Time to assemble the final projection.
 
@@ -711,6 +745,11 @@ all_span_stats AS (
 		buf.WriteString(",\n  span_stats")
 	}
 
+	// If WITH COSTS was specified, include the cost columns.
+	if n.Options.Costs {
+		buf.WriteString(",\n  ru, kv_cpu_seconds, read_bytes, write_bytes, costs_since")
+	}
+
 	// Complete this CTE. and add an order if needed.
 	buf.WriteString("\nFROM intermediate r ORDER BY r.start_key")
 	switch n.Options.Mode {
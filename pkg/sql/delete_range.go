@@ -14,17 +14,30 @@ import (
 	"bytes"
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
 )
 
+// rangefeedEnabledSettingName is the name of the cluster setting which enables
+// rangefeeds on the ranges of the system tenant.
+const rangefeedEnabledSettingName = "kv.rangefeed.enabled"
+
 // deleteRangeNode implements DELETE on a primary index satisfying certain
 // conditions that permit the direct use of the DeleteRange kv operation,
 // instead of many point deletes.
@@ -49,6 +62,16 @@ type deleteRangeNode struct {
 	// batches and will just send one big delete with a commit statement attached.
 	autoCommitEnabled bool
 
+	// useRangeTombstones is set if the spans are deleted by writing MVCC range
+	// tombstones outside of the transaction, rather than a point tombstone per
+	// key, as requested by DELETE ... USING RANGE TOMBSTONE. See
+	// checkDeleteUsingRangeTombstones for the conditions under which this is
+	// safe.
+	useRangeTombstones bool
+
+	// metrics is used to track deletes that use MVCC range tombstones.
+	metrics *rowinfra.Metrics
+
 	// rowCount will be set to the count of rows deleted.
 	rowCount int
 }
@@ -112,7 +135,23 @@ func (d *deleteRangeNode) startExec(params runParams) error {
 	log.VEvent(ctx, 2, "fast delete: skipping scan")
 	spans := make([]roachpb.Span, len(d.spans))
 	copy(spans, d.spans)
-	if !d.autoCommitEnabled {
+	if d.useRangeTombstones {
+		if err := checkDeleteUsingRangeTombstones(d.desc, spans); err != nil {
+			return err
+		}
+		// If range tombstones can't be written safely, fall back to deleting the
+		// rows transactionally.
+		if reason := rangeTombstonesUnsafeReason(params.p); reason != "" {
+			params.p.BufferClientNotice(ctx, pgnotice.Newf(
+				"deleting rows without range tombstones: %s", reason))
+			d.useRangeTombstones = false
+		}
+	}
+	if d.useRangeTombstones {
+		if err := d.deleteSpansUsingRangeTombstones(params, spans); err != nil {
+			return err
+		}
+	} else if !d.autoCommitEnabled {
 		// Without autocommit, we're going to run each batch one by one, respecting
 		// a max span request keys size. We use spans as a queue of spans to delete.
 		// It'll be edited if there are any resume spans encountered (if any request
@@ -160,6 +199,89 @@ func (d *deleteRangeNode) startExec(params runParams) error {
 	return nil
 }
 
+// deleteSpansUsingRangeTombstones deletes the given spans by writing MVCC range
+// tombstones using non-transactional DeleteRange requests. Since these
+// requests don't return the deleted keys, the rows in the spans are first
+// counted using the transaction.
+//
+// The transaction's commit timestamp is fixed beforehand, and the range
+// tombstones are written just above it, since the transaction's own reads of
+// the spans are recorded in the timestamp cache. The tombstones then delete
+// exactly the rows that were counted. If a request is pushed to a higher
+// timestamp, e.g. because of a row written to the span after the transaction's
+// timestamp or a read by another transaction, its tombstone may have deleted
+// rows which were not counted. The statement is then retried at a later
+// timestamp, which counts and deletes the remaining rows.
+func (d *deleteRangeNode) deleteSpansUsingRangeTombstones(
+	params runParams, spans roachpb.Spans,
+) error {
+	ctx := params.ctx
+	log.Event(ctx, "deleting using MVCC range tombstones")
+	ts, err := params.p.txn.CommitTimestamp()
+	if err != nil {
+		return err
+	}
+	writeTS := ts.Next()
+	toCount := make([]roachpb.Span, len(spans))
+	copy(toCount, spans)
+	for len(toCount) != 0 {
+		b := params.p.txn.NewBatch()
+		b.Header.MaxSpanRequestKeys = row.TableTruncateChunkSize
+		for _, span := range toCount {
+			b.Scan(span.Key, span.EndKey)
+		}
+		if err := params.p.txn.Run(ctx, b); err != nil {
+			return row.ConvertBatchError(ctx, d.desc, b)
+		}
+		// Move the scanned keys over to Keys so that they can be counted in the
+		// same way as the keys returned by DelRange.
+		for i := range b.Results {
+			r := &b.Results[i]
+			for _, scanned := range r.Rows {
+				r.Keys = append(r.Keys, scanned.Key)
+			}
+		}
+		if toCount, err = d.processResults(b.Results, toCount[:0]); err != nil {
+			return err
+		}
+	}
+
+	traceKV := params.p.ExtendedEvalContext().Tracing.KVTracingEnabled()
+	db := params.p.txn.DB()
+	for _, span := range spans {
+		if traceKV {
+			log.VEventf(ctx, 2, "DelRangeUsingTombstone %s - %s", span.Key, span.EndKey)
+		}
+		ba := &kvpb.BatchRequest{}
+		ba.Timestamp = writeTS
+		ba.AdmissionHeader = params.p.txn.AdmissionHeader()
+		// IdempotentTombstone avoids writing a new range tombstone if the span is
+		// already deleted, e.g. if the statement is retried.
+		ba.Add(&kvpb.DeleteRangeRequest{
+			RequestHeader:       kvpb.RequestHeader{Key: span.Key, EndKey: span.EndKey},
+			UseRangeTombstone:   true,
+			IdempotentTombstone: true,
+		})
+		br, pErr := db.NonTransactionalSender().Send(ctx, ba)
+		if pErr != nil {
+			if err := pErr.GoError(); errors.HasType(err, (*kvpb.WriteTooOldError)(nil)) {
+				return params.p.txn.GenerateForcedRetryableErr(
+					ctx, "range tombstone delete encountered a newer write")
+			}
+			return errors.Wrapf(pErr.GoError(), "deleting %s using range tombstone", span)
+		}
+		// Non-transactional writes are pushed by the server instead of failing
+		// when they encounter a newer write or read.
+		if br.Timestamp != writeTS {
+			return params.p.txn.GenerateForcedRetryableErr(ctx, redact.Sprintf(
+				"range tombstone delete of %s was pushed from %s to %s", span, writeTS, br.Timestamp))
+		}
+	}
+	d.metrics.RangeTombstoneDeleteCount.Inc(1)
+	d.metrics.RangeTombstoneDeleteRows.Inc(int64(d.rowCount))
+	return nil
+}
+
 // deleteSpans adds each input span to a Del or a DelRange command in the given
 // batch.
 func (d *deleteRangeNode) deleteSpans(params runParams, b *kv.Batch, spans roachpb.Spans) {
@@ -212,6 +334,69 @@ func (d *deleteRangeNode) processResults(
 	return resumeSpans, nil
 }
 
+// checkDeleteUsingRangeTombstones returns an error if the given spans of the
+// table can't be deleted by writing MVCC range tombstones. The table must not
+// require any other writes or checks when its rows are deleted, and all spans
+// must be ranges of rows.
+func checkDeleteUsingRangeTombstones(desc catalog.TableDescriptor, spans roachpb.Spans) error {
+	if desc.GetParentID() == keys.SystemDatabaseID {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"USING RANGE TOMBSTONE is not supported on system tables")
+	}
+	if len(desc.InboundForeignKeys()) > 0 {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"USING RANGE TOMBSTONE is not supported on table %q, which is referenced by "+
+				"foreign keys", desc.GetName())
+	}
+	for _, span := range spans {
+		if span.EndKey == nil {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"USING RANGE TOMBSTONE requires the DELETE to delete ranges of rows rather "+
+					"than individual rows")
+		}
+	}
+	return nil
+}
+
+// rangeTombstonesUnsafeReason returns why writing MVCC range tombstones
+// outside of the transaction is unsafe for the current statement, or an empty
+// string if it is safe. This is only safe if the DELETE is the only statement
+// in an implicit serializable transaction, since the deletion is not atomic
+// with the transaction and can't be rolled back. Range tombstones are also not
+// emitted by rangefeeds as row deletions, so they must not be used if
+// rangefeeds may be enabled on the table.
+func rangeTombstonesUnsafeReason(p *planner) redact.RedactableString {
+	evalCtx := p.ExtendedEvalContext()
+	if !p.autoCommit || !evalCtx.TxnImplicit || !evalCtx.TxnIsSingleStmt {
+		return "the DELETE is not the only statement of an implicit transaction"
+	}
+	// The range tombstones are written at the transaction's commit timestamp,
+	// which can't be fixed early under weaker isolation levels.
+	if p.txn.IsoLevel() != isolation.Serializable {
+		return "the transaction is not SERIALIZABLE"
+	}
+	if rangefeedsMayBeEnabled(p) {
+		return "rangefeeds may be enabled, and do not emit range tombstones as row deletions"
+	}
+	return ""
+}
+
+// rangefeedsMayBeEnabled returns whether rangefeeds may be enabled on the
+// ranges of the tenant. The span configs of secondary tenants always enable
+// rangefeeds, while the system tenant only enables them through the
+// kv.rangefeed.enabled cluster setting, which is defined in kvserver.
+func rangefeedsMayBeEnabled(p *planner) bool {
+	if !p.ExecCfg().Codec.ForSystemTenant() {
+		return true
+	}
+	s, ok, _ := settings.LookupForLocalAccess(rangefeedEnabledSettingName, true /* forSystemTenant */)
+	if !ok {
+		return true
+	}
+	enabled, ok := s.(*settings.BoolSetting)
+	return !ok || enabled.Get(&p.ExecCfg().Settings.SV)
+}
+
 // Next implements the planNode interface.
 func (*deleteRangeNode) Next(params runParams) (bool, error) {
 	// TODO(radu): this shouldn't be used, but it gets called when a cascade uses
@@ -261,12 +261,22 @@ func (ds *ServerImpl) setupFlow(
 		)
 	}
 
+	// The flow monitor enforces the per-query memory budget (if any) on this
+	// node. The cluster setting is a ceiling that the session variable can't
+	// exceed.
+	maxQueryMemory := execinfra.GetMaxQueryMemory(
+		&ds.Settings.SV, req.EvalContext.SessionData.MaxQueryMemory, req.EvalContext.SessionData.Internal,
+	)
 	monitor = mon.NewMonitor(mon.Options{
 		Name:     "flow " + redact.RedactableString(req.Flow.FlowID.Short()),
+		Limit:    maxQueryMemory,
 		CurCount: ds.Metrics.CurBytesCount,
 		MaxHist:  ds.Metrics.MaxBytesHist,
 		Settings: ds.Settings,
 	})
+	if maxQueryMemory > 0 {
+		monitor.MarkAsQueryMemoryBudgetMonitor()
+	}
 	monitor.Start(ctx, parentMonitor, reserved)
 	diskMonitor = execinfra.NewMonitor(ctx, ds.ParentDiskMonitor, "flow-disk-monitor")
 
@@ -3048,7 +3048,8 @@ func (dsp *DistSQLPlanner) createPlanForIndexJoin(
 		return nil, err
 	}
 
-	splitter := span.MakeSplitter(n.table.desc, index, fetchOrdinals)
+	splitter := span.MakeSplitter(n.table.desc, index, fetchOrdinals).
+		WithSkipLocked(n.table.lockingWaitPolicy == descpb.ScanLockingWaitPolicy_SKIP_LOCKED)
 	joinReaderSpec.SplitFamilyIDs = splitter.FamilyIDs()
 
 	plan.PlanToStreamColMap = identityMap(plan.PlanToStreamColMap, len(fetchColIDs))
@@ -3140,6 +3141,7 @@ func (dsp *DistSQLPlanner) createPlanForLookupJoin(
 	} else {
 		splitter = span.MakeSplitter(n.table.desc, n.table.index, fetchOrdinals)
 	}
+	splitter = splitter.WithSkipLocked(n.table.lockingWaitPolicy == descpb.ScanLockingWaitPolicy_SKIP_LOCKED)
 	joinReaderSpec.SplitFamilyIDs = splitter.FamilyIDs()
 
 	joinReaderSpec.LookupColumns = make([]uint32, len(n.eqCols))
@@ -4278,6 +4280,17 @@ func (dsp *DistSQLPlanner) isOnlyOnGateway(plan *PhysicalPlan) bool {
 	return false
 }
 
+// parallelUnionAllAndWindowEnabled controls whether distributed plans run
+// UNION ALL branches and PARTITION BY window functions in parallel across
+// nodes, instead of serializing them on a single node.
+var parallelUnionAllAndWindowEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.distsql.parallel_union_all_and_window.enabled",
+	"when true, UNION ALL branches and PARTITION BY window functions in distributed plans "+
+		"are executed in parallel on the nodes that produce their input",
+	false,
+)
+
 // TODO(abhimadan): Refactor this function to reduce the UNION vs
 // EXCEPT/INTERSECT and DISTINCT vs ALL branching.
 //
@@ -4466,13 +4479,19 @@ func (dsp *DistSQLPlanner) createPlanForSetOp(
 			// on a single node (which is always the case when there are mutations),
 			// we can fuse everything so there are no concurrent KV operations (see
 			// #40487, #41307).
+			//
+			// Distributed plans always use LeafTxns, so concurrent KV operations are
+			// safe there, and we can let the branches on the same node run in
+			// parallel if enabled.
+			parallelize := !planCtx.isLocal && !n.enforceHomeRegion &&
+				parallelUnionAllAndWindowEnabled.Get(&dsp.st.SV)
 
 			if n.hardLimit == 0 {
 				// In order to disable auto-parallelism that could occur when merging
 				// multiple streams on the same node, we force the serialization of the
 				// merge operation (otherwise, it would be possible that we have a
 				// source of unbounded parallelism, see #51548).
-				p.EnsureSingleStreamPerNode(ctx, true /* forceSerialization */, execinfrapb.PostProcessSpec{}, serialStreamErrorSpec)
+				p.EnsureSingleStreamPerNode(ctx, !parallelize /* forceSerialization */, execinfrapb.PostProcessSpec{}, serialStreamErrorSpec)
 			} else {
 				if p.GetLastStageDistribution() != physicalplan.LocalPlan {
 					return nil, errors.AssertionFailedf("we expect that limited UNION ALL queries are only planned locally")
@@ -4499,7 +4518,16 @@ func (dsp *DistSQLPlanner) createPlanForSetOp(
 			// which would violate an assumption later down the line. Check for this
 			// condition and add a no-op stage if it exists.
 			if err := p.CheckLastStagePost(); err != nil {
-				p.EnsureSingleStreamOnGateway(ctx)
+				if parallelize {
+					// Keep the plan distributed by adding the no-op stage on each
+					// node rather than bringing all streams to the gateway.
+					p.AddNoGroupingStage(
+						execinfrapb.ProcessorCoreUnion{Noop: &execinfrapb.NoopCoreSpec{}},
+						execinfrapb.PostProcessSpec{}, p.GetResultTypes(), p.MergeOrdering,
+					)
+				} else {
+					p.EnsureSingleStreamOnGateway(ctx)
+				}
 			}
 		}
 	} else {
@@ -4630,6 +4658,15 @@ func (dsp *DistSQLPlanner) createPlanForWindow(
 
 	// Get all sqlInstanceIDs from the previous stage.
 	sqlInstanceIDs := getSQLInstanceIDsOfRouters(plan.ResultRouters, plan.Processors)
+	if len(partitionIdxs) > 0 && len(sqlInstanceIDs) == 1 && !planCtx.isLocal &&
+		parallelUnionAllAndWindowEnabled.Get(&dsp.st.SV) {
+		// The previous stage ended up on a single node even though the plan is
+		// distributed (e.g. because of an earlier single group stage). Rather
+		// than computing all partitions on that node, spread the windowers
+		// across all nodes that already participate in the plan, which are the
+		// nodes close to the data read by the query.
+		sqlInstanceIDs = getSQLInstanceIDsOfProcessors(plan.Processors)
+	}
 	if len(partitionIdxs) == 0 || len(sqlInstanceIDs) == 1 {
 		// No PARTITION BY or we have a single node. Use a single windower. If
 		// the previous stage was all on a single node, put the windower there.
@@ -483,6 +483,58 @@ func TestDistSQLDeadHosts(t *testing.T) {
 	testutils.SucceedsSoon(t, runQuery)
 }
 
+// TestDistSQLParallelUnionAllAndWindow verifies that UNION ALL branches and
+// PARTITION BY window functions are planned in parallel across nodes when
+// sql.distsql.parallel_union_all_and_window.enabled is set.
+func TestDistSQLParallelUnionAllAndWindow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numNodes = 3
+	tc := serverutils.StartCluster(t, numNodes, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+		ServerArgs:      base.TestServerArgs{UseDatabase: "test"},
+	})
+	defer tc.Stopper().Stop(context.Background())
+
+	r := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	r.Exec(t, "CREATE DATABASE test")
+	r.Exec(t, "CREATE TABLE t (k INT PRIMARY KEY, v INT)")
+	r.Exec(t, "INSERT INTO t SELECT i, i % 3 FROM generate_series(1, 30) AS g(i)")
+	r.Exec(t, "ALTER TABLE t SPLIT AT VALUES (10), (20)")
+	r.Exec(t, "ALTER TABLE t EXPERIMENTAL_RELOCATE VALUES (ARRAY[1], 0), (ARRAY[2], 10), (ARRAY[3], 20)")
+	// Ensure that the range cache is populated.
+	r.Exec(t, "SHOW RANGES FROM TABLE t")
+	r.Exec(t, "SET distsql = always")
+
+	explain := func(query string) string {
+		var json string
+		r.QueryRow(t, fmt.Sprintf("EXPLAIN (DISTSQL, JSON) %s", query)).Scan(&json)
+		return json
+	}
+	// Each node has two TableReaders, one for each side of the UNION ALL.
+	const unionAll = `SELECT k FROM t UNION ALL SELECT k FROM t`
+	// The LIMIT forces the input of the windowers onto a single node.
+	const window = `SELECT k, row_number() OVER (PARTITION BY v) FROM (SELECT * FROM t ORDER BY k LIMIT 20)`
+
+	json := explain(unionAll)
+	require.Contains(t, json, `"title":"serial unordered"`)
+	require.Equal(t, 1, strings.Count(explain(window), `"title":"Windower"`))
+
+	r.Exec(t, "SET CLUSTER SETTING sql.distsql.parallel_union_all_and_window.enabled = true")
+
+	json = explain(unionAll)
+	require.NotContains(t, json, `"title":"serial unordered"`)
+	require.Contains(t, json, `"nodeNames":["1","2","3"]`)
+	require.Equal(t, numNodes, strings.Count(explain(window), `"title":"Windower"`))
+
+	// The results are unaffected.
+	r.CheckQueryResults(t, fmt.Sprintf("SELECT count(*) FROM (%s)", unionAll), [][]string{{"60"}})
+	r.CheckQueryResults(t,
+		fmt.Sprintf("SELECT max(row_number), count(*) FROM (%s)", window), [][]string{{"7", "20"}},
+	)
+}
+
 func TestDistSQLDrainingHosts(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
@@ -58,13 +59,20 @@ type CDCExpressionPlan struct {
 	PlanCtx      *PlanningCtx          // ... and plan context
 	Spans        roachpb.Spans         // Set of spans for rangefeed.
 	Presentation colinfo.ResultColumns // List of result columns.
+
+	// ReadsReferenceTables is true if the plan looks up rows in reference
+	// tables joined against the target table.
+	ReadsReferenceTables bool
 }
 
 // PlanCDCExpression plans the execution of CDCExpression.
 //
-// CDC expressions may contain only a single table. Because of the limited
-// nature of the CDCExpression, this code assumes (and verifies) that the
-// produced plan has only one instance of *scanNode.
+// CDC expressions may contain only a single target table. Because of the
+// limited nature of the CDCExpression, this code assumes (and verifies) that the
+// produced plan has only one instance of *scanNode. The target table may be
+// joined against reference tables, in which case the plan must look up at most
+// one row from each reference table for each row of the target table (i.e.
+// reference tables must be LEFT JOINed using a lookup join on their key).
 //
 // localPlanner is assumed to be an instance of planner created specifically for
 // planning and execution of CDC expressions. This planner ought to be
@@ -101,9 +109,15 @@ func PlanCDCExpression(
 		return cdcPlan, err
 	}
 
+	targetID, err := resolveJoinTargetID(ctx, p, cdcExpr)
+	if err != nil {
+		return cdcPlan, err
+	}
+
 	cdcCat := &cdcOptCatalog{
 		optCatalog:     opc.catalog.(*optCatalog),
 		cdcConfig:      cfg,
+		targetID:       targetID,
 		targetFamilyID: familyID,
 		semaCtx:        &p.semaCtx,
 	}
@@ -130,11 +144,27 @@ func PlanCDCExpression(
 	// Walk the plan, perform sanity checks and extract information we need.
 	var spans roachpb.Spans
 	var presentation colinfo.ResultColumns
+	var readsReferenceTables bool
 
 	if err := walkPlan(ctx, p.curPlan.main.planNode, planObserver{
 		enterNode: func(ctx context.Context, nodeName string, plan planNode) (bool, error) {
 			switch n := plan.(type) {
+			case *lookupJoinNode:
+				// Each row of the target table must produce exactly one row, so the
+				// reference table must be LEFT JOINed on a key.
+				if n.table.desc.GetID() == targetID ||
+					n.joinType != descpb.LeftOuterJoin || !n.eqColsAreKey {
+					return false, pgerror.Newf(pgcode.FeatureNotSupported,
+						"reference table %s must be joined using LEFT JOIN on its primary key",
+						n.table.desc.GetName())
+				}
+				readsReferenceTables = true
 			case *scanNode:
+				if targetID != 0 && n.desc.GetID() != targetID {
+					return false, pgerror.Newf(pgcode.FeatureNotSupported,
+						"reference table %s must be joined using LEFT JOIN on its primary key",
+						n.desc.GetName())
+				}
 				// Collect spans we wanted to scan.  The select statement used for this
 				// plan should result in a single table scan of primary index span.
 				if len(spans) > 0 {
@@ -177,10 +207,11 @@ func PlanCDCExpression(
 	planCtx := p.DistSQLPlanner().NewPlanningCtx(ctx, &p.extendedEvalCtx, p, p.txn, LocalDistribution)
 
 	return CDCExpressionPlan{
-		Plan:         p.curPlan.main,
-		PlanCtx:      planCtx,
-		Spans:        spans,
-		Presentation: presentation,
+		Plan:                 p.curPlan.main,
+		PlanCtx:              planCtx,
+		Spans:                spans,
+		Presentation:         presentation,
+		ReadsReferenceTables: readsReferenceTables,
 	}, nil
 }
 
@@ -210,8 +241,21 @@ func RunCDCEvaluation(
 		p.Descriptors().ReleaseAll(ctx)
 	}
 
+	txn := p.txn
+	if cdcPlan.ReadsReferenceTables {
+		// The transaction used to plan the expression is no longer usable by the
+		// time the plan runs. Reference tables are read using a new transaction
+		// as of the time the evaluation started.
+		nodeID, _ := p.execCfg.NodeInfo.NodeID.OptionalNodeID() // zero if not available
+		txn = kv.NewTxn(ctx, p.execCfg.DB, nodeID)
+		if err := txn.SetFixedTimestamp(ctx, p.execCfg.Clock.Now()); err != nil {
+			return err
+		}
+		defer func() { _ = txn.Rollback(ctx) }()
+	}
+
 	p.DistSQLPlanner().PlanAndRun(
-		ctx, &p.extendedEvalCtx, cdcPlan.PlanCtx, p.txn, cdcPlan.Plan, receiver, finishedSetupFn,
+		ctx, &p.extendedEvalCtx, cdcPlan.PlanCtx, txn, cdcPlan.Plan, receiver, finishedSetupFn,
 	)
 	return nil
 }
@@ -345,6 +389,9 @@ func (n *cdcValuesNode) Close(ctx context.Context) {
 type cdcOptCatalog struct {
 	*optCatalog
 	cdcConfig
+	// targetID is the ID of the target table when the target table is joined
+	// against reference tables; 0 otherwise.
+	targetID       descpb.ID
 	targetFamilyID catid.FamilyID
 	semaCtx        *tree.SemaContext
 }
@@ -357,7 +404,7 @@ func extractFamilyID(stmt CDCExpression) (catid.FamilyID, error) {
 	if !ok {
 		return 0, errors.AssertionFailedf("unexpected expression type %T", stmt.Select)
 	}
-	if t, ok := sc.From.Tables[0].(*tree.AliasedTableExpr); ok {
+	if t, ok := tree.ChangefeedTargetTableExpr(sc.From.Tables[0]).(*tree.AliasedTableExpr); ok {
 		if t.IndexFlags != nil && t.IndexFlags.FamilyID != nil {
 			return *t.IndexFlags.FamilyID, nil
 		}
@@ -365,21 +412,65 @@ func extractFamilyID(stmt CDCExpression) (catid.FamilyID, error) {
 	return 0, nil
 }
 
+// resolveJoinTargetID returns the ID of the target table of CDCExpression if
+// the target table is joined against reference tables. Returns 0 if the
+// expression does not contain any joins.
+func resolveJoinTargetID(ctx context.Context, p *planner, stmt CDCExpression) (descpb.ID, error) {
+	sc, ok := stmt.Select.(*tree.SelectClause)
+	if !ok {
+		return 0, errors.AssertionFailedf("unexpected expression type %T", stmt.Select)
+	}
+	if _, isJoin := sc.From.Tables[0].(*tree.JoinTableExpr); !isJoin {
+		return 0, nil
+	}
+
+	expr := tree.ChangefeedTargetTableExpr(sc.From.Tables[0])
+	for {
+		t, ok := expr.(*tree.AliasedTableExpr)
+		if !ok {
+			break
+		}
+		expr = t.Expr
+	}
+
+	switch t := expr.(type) {
+	case *tree.TableName:
+		// Resolve a copy since resolution qualifies the name in place.
+		tn := *t
+		_, desc, err := resolver.ResolveExistingTableObject(ctx, p, &tn, cdcTableLookupFlags)
+		if err != nil {
+			return 0, err
+		}
+		return desc.GetID(), nil
+	case *tree.TableRef:
+		return descpb.ID(t.TableID), nil
+	default:
+		return 0, errors.AssertionFailedf("unexpected target table expression %T", expr)
+	}
+}
+
+// cdcTableLookupFlags are the flags used to resolve tables referenced by
+// CDCExpression.
+var cdcTableLookupFlags = tree.ObjectLookupFlags{
+	Required:             true,
+	DesiredObjectKind:    tree.TableObject,
+	DesiredTableDescKind: tree.ResolveRequireTableDesc,
+}
+
 // ResolveDataSource implements cat.Catalog interface.
 // We provide custom implementation to ensure that we return data source for
 // primary index.
 func (c *cdcOptCatalog) ResolveDataSource(
 	ctx context.Context, flags cat.Flags, name *cat.DataSourceName,
 ) (cat.DataSource, cat.DataSourceName, error) {
-	lflags := tree.ObjectLookupFlags{
-		Required:             true,
-		DesiredObjectKind:    tree.TableObject,
-		DesiredTableDescKind: tree.ResolveRequireTableDesc,
-	}
-	_, desc, err := resolver.ResolveExistingTableObject(ctx, c.planner, name, lflags)
+	_, desc, err := resolver.ResolveExistingTableObject(ctx, c.planner, name, cdcTableLookupFlags)
 	if err != nil {
 		return nil, cat.DataSourceName{}, err
 	}
+	if c.targetID != 0 && desc.GetID() != c.targetID {
+		// Reference tables are regular data sources.
+		return c.optCatalog.ResolveDataSource(ctx, flags, name)
+	}
 
 	ds, err := c.newCDCDataSource(desc, c.targetFamilyID)
 	if err != nil {
@@ -394,6 +485,10 @@ func (c *cdcOptCatalog) ResolveDataSource(
 func (c *cdcOptCatalog) ResolveDataSourceByID(
 	ctx context.Context, flags cat.Flags, id cat.StableID,
 ) (cat.DataSource, bool, error) {
+	if c.targetID != 0 && descpb.ID(id) != c.targetID {
+		// Reference tables are regular data sources.
+		return c.optCatalog.ResolveDataSourceByID(ctx, flags, id)
+	}
 	desc, err := c.planner.LookupTableByID(ctx, descpb.ID(id))
 	if err != nil {
 		return nil, false, err
@@ -219,6 +219,22 @@ func getSQLInstanceIDsOfRouters(
 	return sqlInstanceIDs
 }
 
+// getSQLInstanceIDsOfProcessors returns the distinct SQL instances on which
+// the given processors are planned, in the order they first appear.
+func getSQLInstanceIDsOfProcessors(
+	processors []physicalplan.Processor,
+) (sqlInstanceIDs []base.SQLInstanceID) {
+	seen := make(map[base.SQLInstanceID]struct{})
+	for i := range processors {
+		n := processors[i].SQLInstanceID
+		if _, ok := seen[n]; !ok {
+			seen[n] = struct{}{}
+			sqlInstanceIDs = append(sqlInstanceIDs, n)
+		}
+	}
+	return sqlInstanceIDs
+}
+
 func findJoinProcessorNodes(
 	leftRouters, rightRouters []physicalplan.ProcessorIdx, processors []physicalplan.Processor,
 ) (instances []base.SQLInstanceID) {
@@ -226,7 +226,8 @@ func (e *distSQLSpecExecFactory) ConstructScan(
 	if params.InvertedConstraint != nil {
 		spans, err = sb.SpansFromInvertedSpans(params.InvertedConstraint, params.IndexConstraint, nil /* scratch */)
 	} else {
-		splitter := span.MakeSplitter(tabDesc, idx, params.NeededCols)
+		splitter := span.MakeSplitter(tabDesc, idx, params.NeededCols).
+			WithSkipLocked(params.Locking.WaitPolicy == tree.LockWaitSkipLocked)
 		spans, err = sb.SpansFromConstraint(params.IndexConstraint, splitter)
 	}
 	if err != nil {
@@ -1037,6 +1038,7 @@ func (e *distSQLSpecExecFactory) ConstructDeleteRange(
 	needed exec.TableColumnOrdinalSet,
 	indexConstraint *constraint.Constraint,
 	autoCommit bool,
+	useRangeTombstone bool,
 ) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: delete range")
 }
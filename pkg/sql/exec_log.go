@@ -251,6 +251,7 @@ func (p *planner) maybeLogStatementInternal(
 			entries[idx] = auditEvent
 		}
 		p.logEventsOnlyExternally(ctx, entries...)
+		p.execCfg.AuditLogExternalSink.Emit(entries...)
 	}
 
 	if slowQueryLogEnabled && (
@@ -109,6 +109,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/upgrade"
 	"github.com/cockroachdb/cockroach/pkg/upgrade/upgradebase"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
@@ -777,6 +778,24 @@ var (
 		Measurement: "SQL Statements",
 		Unit:        metric.Unit_COUNT,
 	}
+	MetaSQLOptPlanCacheInvalidations = metric.Metadata{
+		Name:        "sql.optimizer.plan_cache.invalidations",
+		Help:        "Number of statements for which a cached plan was invalidated by schema, statistics or setting changes and had to be rebuilt",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaSQLOptGenericPlanCacheHits = metric.Metadata{
+		Name:        "sql.optimizer.plan_cache.generic.hits",
+		Help:        "Number of executions of prepared statements which used a cached generic plan",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaSQLOptGenericPlanCacheMisses = metric.Metadata{
+		Name:        "sql.optimizer.plan_cache.generic.misses",
+		Help:        "Number of executions of prepared statements which built a generic plan because none was cached",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
 	MetaDistSQLSelect = metric.Metadata{
 		Name:        "sql.distsql.select.count",
 		Help:        "Number of DistSQL SELECT statements",
@@ -1167,6 +1186,18 @@ var (
 		Measurement: "SQL Statements",
 		Unit:        metric.Unit_COUNT,
 	}
+	MetaQueryMemoryBudgetExceeded = metric.Metadata{
+		Name:        "sql.guardrails.max_query_memory_exceeded.count",
+		Help:        "Number of statements that failed because they exceeded the `max_query_memory` budget",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaStatementRetryBudgetExhausted = metric.Metadata{
+		Name:        "sql.guardrails.statement_retry_budget_exhausted.count",
+		Help:        "Number of statements that were not automatically retried because they exhausted their retry budget",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 func getMetricMeta(meta metric.Metadata, internal bool) metric.Metadata {
@@ -1326,6 +1357,10 @@ type ExecutorConfig struct {
 	// 'sql.log.user_audit' cluster setting to see how this is configured.
 	AuditConfig *auditlogging.AuditConfigLock
 
+	// AuditLogExternalSink ships audit events to an external storage, if
+	// configured via the 'sql.audit_log.external_sink.uri' cluster setting.
+	AuditLogExternalSink *auditlogging.ExternalSink
+
 	// ProtectedTimestampProvider encapsulates the protected timestamp subsystem.
 	ProtectedTimestampProvider protectedts.Provider
 
@@ -1372,10 +1407,18 @@ type ExecutorConfig struct {
 	// root-level memory accounts that are not related to a user session.
 	RootMemoryMonitor *mon.BytesMonitor
 
+	// SQLMemoryAdmissionQ is the admission queue used to admit the execution of
+	// statements from client sessions based on the node's memory pressure.
+	SQLMemoryAdmissionQ *admission.SQLMemoryWorkQueue
+
 	// CompactEngineSpanFunc is used to inform a storage engine of the need to
 	// perform compaction over a key span.
 	CompactEngineSpanFunc eval.CompactEngineSpanFunc
 
+	// RewriteInactiveKeyFilesFunc is used to rewrite the files encrypted with
+	// inactive encryption-at-rest data keys.
+	RewriteInactiveKeyFilesFunc eval.RewriteInactiveKeyFilesFunc
+
 	// CompactionConcurrencyFunc is used to inform a storage engine to change its
 	// compaction concurrency.
 	CompactionConcurrencyFunc eval.SetCompactionConcurrencyFunc
@@ -1388,6 +1431,14 @@ type ExecutorConfig struct {
 	// keys (including snapshot pinned keys) at each level of a node store.
 	ScanStorageInternalKeysFunc eval.ScanStorageInternalKeysFunc
 
+	// ApplyRecoveryPlanFunc is used to apply a staged loss of quorum recovery
+	// plan on all nodes of the cluster. It is nil for secondary tenants.
+	ApplyRecoveryPlanFunc eval.ApplyRecoveryPlanFunc
+
+	// UpgradeDryRunFunc is used to estimate the impact of upgrading the cluster
+	// to a given version without running the upgrades.
+	UpgradeDryRunFunc eval.UpgradeDryRunFunc
+
 	// TraceCollector is used to contact all live nodes in the cluster, and
 	// collect trace spans from their inflight node registries.
 	TraceCollector *collector.TraceCollector
@@ -1463,6 +1514,12 @@ type ExecutorConfig struct {
 	// VirtualClusterName contains the name of the virtual cluster
 	// (tenant).
 	VirtualClusterName roachpb.TenantName
+
+	// MaxSQLConnectionsPerInstanceFunc returns the limit on the number of SQL
+	// connections to this server set by the tenant's
+	// max_sql_connections_per_instance capability, or 0 if there is no limit.
+	// It is nil for the system tenant.
+	MaxSQLConnectionsPerInstanceFunc func() int64
 }
 
 // UpdateVersionSystemSettingHook provides a callback that allows us
@@ -3247,6 +3304,14 @@ func (m *sessionDataMutator) SetDistSQLWorkMem(val int64) {
 	m.data.WorkMemLimit = val
 }
 
+func (m *sessionDataMutator) SetMaxQueryMemory(val int64) {
+	m.data.MaxQueryMemory = val
+}
+
+func (m *sessionDataMutator) SetResourceGroup(val string) {
+	m.data.ResourceGroup = val
+}
+
 func (m *sessionDataMutator) SetForceSavepointRestart(val bool) {
 	m.data.ForceSavepointRestart = val
 }
@@ -3567,6 +3632,14 @@ func (m *sessionDataMutator) SetMaxRetriesForReadCommitted(val int32) {
 	m.data.MaxRetriesForReadCommitted = val
 }
 
+func (m *sessionDataMutator) SetStatementRetryBudgetCount(val int32) {
+	m.data.StatementRetryBudgetCount = val
+}
+
+func (m *sessionDataMutator) SetStatementRetryBudgetLatency(val time.Duration) {
+	m.data.StatementRetryBudgetLatency = val
+}
+
 func (m *sessionDataMutator) SetJoinReaderOrderingStrategyBatchSize(val int64) {
 	m.data.JoinReaderOrderingStrategyBatchSize = val
 }
@@ -3825,6 +3898,18 @@ func (m *sessionDataMutator) SetOptimizerPushOffsetIntoIndexJoin(val bool) {
 	m.data.OptimizerPushOffsetIntoIndexJoin = val
 }
 
+func (m *sessionDataMutator) SetOptimizerUseMaterializedViewRewrite(val bool) {
+	m.data.OptimizerUseMaterializedViewRewrite = val
+}
+
+func (m *sessionDataMutator) SetOptimizerMaterializedViewRewriteMaxStaleness(val time.Duration) {
+	m.data.OptimizerMaterializedViewRewriteMaxStaleness = val
+}
+
+func (m *sessionDataMutator) SetPlanCacheMode(val sessiondatapb.PlanCacheMode) {
+	m.data.PlanCacheMode = val
+}
+
 // Utility functions related to scrubbing sensitive information on SQL Stats.
 
 // quantizeCounts ensures that the Count field in the
@@ -3966,6 +4051,10 @@ func NewRowMetrics(internal bool) rowinfra.Metrics {
 	return rowinfra.Metrics{
 		MaxRowSizeLogCount: metric.NewCounter(getMetricMeta(rowinfra.MetaMaxRowSizeLog, internal)),
 		MaxRowSizeErrCount: metric.NewCounter(getMetricMeta(rowinfra.MetaMaxRowSizeErr, internal)),
+		RangeTombstoneDeleteCount: metric.NewCounter(
+			getMetricMeta(rowinfra.MetaRangeTombstoneDelete, internal)),
+		RangeTombstoneDeleteRows: metric.NewCounter(
+			getMetricMeta(rowinfra.MetaRangeTombstoneDeleteRows, internal)),
 	}
 }
 
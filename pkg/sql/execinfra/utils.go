@@ -65,3 +65,34 @@ var IncludeRUEstimateInExplainAnalyze = settings.RegisterBoolSetting(
 	true,
 	settings.WithName("sql.explain_analyze.include_ru_estimation.enabled"),
 )
+
+// MaxQueryMemory is the maximum amount of memory that a single query can use
+// on each node. It is both the default and the ceiling of the
+// max_query_memory session variable, which can only lower it.
+//
+// This setting is defined here instead of in package 'sql' to avoid
+// a dependency cycle.
+var MaxQueryMemory = settings.RegisterByteSizeSetting(
+	settings.ApplicationLevel,
+	"sql.distsql.max_query_memory",
+	"maximum amount of memory in bytes a single query can use on each node, which the "+
+		"max_query_memory session variable defaults to and can only lower; "+
+		"0 means no limit other than the limit of the SQL memory pool",
+	0,
+	settings.NonNegativeInt,
+	settings.WithPublic,
+)
+
+// GetMaxQueryMemory returns the memory budget of a query on each node given
+// the max_query_memory session variable, clamped to the MaxQueryMemory
+// setting. Zero means no limit. Internal queries are only limited by the
+// session variable.
+func GetMaxQueryMemory(sv *settings.Values, sessionLimit int64, internal bool) int64 {
+	if internal {
+		return sessionLimit
+	}
+	if ceiling := MaxQueryMemory.Get(sv); ceiling > 0 && (sessionLimit == 0 || sessionLimit > ceiling) {
+		return ceiling
+	}
+	return sessionLimit
+}
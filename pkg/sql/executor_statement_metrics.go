@@ -33,6 +33,14 @@ type EngineMetrics struct {
 	SQLOptFallbackCount   *metric.Counter
 	SQLOptPlanCacheHits   *metric.Counter
 	SQLOptPlanCacheMisses *metric.Counter
+	// SQLOptPlanCacheInvalidations counts the statements for which a cached
+	// memo was stale and had to be rebuilt.
+	SQLOptPlanCacheInvalidations *metric.Counter
+	// SQLOptGenericPlanCacheHits and SQLOptGenericPlanCacheMisses count the
+	// executions of prepared statements which used a generic plan, depending
+	// on whether it was found in the query cache.
+	SQLOptGenericPlanCacheHits   *metric.Counter
+	SQLOptGenericPlanCacheMisses *metric.Counter
 
 	DistSQLExecLatency    metric.IHistogram
 	SQLExecLatency        metric.IHistogram
@@ -57,6 +65,16 @@ type EngineMetrics struct {
 	// FullTableOrIndexScanRejectedCount counts the number of queries that were
 	// rejected because of the `disallow_full_table_scans` guardrail.
 	FullTableOrIndexScanRejectedCount *metric.Counter
+
+	// QueryMemoryBudgetExceededCount counts the number of statements that
+	// failed because they exceeded the `max_query_memory` budget.
+	QueryMemoryBudgetExceededCount *metric.Counter
+
+	// StatementRetryBudgetExhaustedCount counts the number of statements that
+	// were not automatically retried because they exhausted the retry budget
+	// set by the `statement_retry_budget_count` and
+	// `statement_retry_budget_latency` session variables.
+	StatementRetryBudgetExhaustedCount *metric.Counter
 }
 
 // EngineMetrics implements the metric.Struct interface.
@@ -192,6 +210,7 @@ func (ex *connExecutor) recordStatementSummary(
 		SessionID:            ex.planner.extendedEvalCtx.SessionID,
 		StatementID:          stmt.QueryID,
 		AutoRetryCount:       automaticRetryCount,
+		StmtRetryCount:       int(planner.instrumentation.stmtRetries),
 		Failed:               stmtErr != nil,
 		AutoRetryReason:      ex.state.mu.autoRetryReason,
 		RowsAffected:         rowsAffected,
@@ -204,6 +223,8 @@ func (ex *connExecutor) recordStatementSummary(
 		BytesRead:            stats.bytesRead,
 		RowsRead:             stats.rowsRead,
 		RowsWritten:          stats.rowsWritten,
+		RequestUnits:         stats.requestUnits,
+		EstimatedCPUSeconds:  stats.estimatedCPUSeconds,
 		Nodes:                sqlInstanceIds,
 		StatementType:        stmt.AST.StatementType(),
 		Plan:                 planner.instrumentation.PlanForStats(ctx),
@@ -265,6 +286,14 @@ func (ex *connExecutor) recordStatementSummary(
 		ex.statsCollector.ObserveStatement(stmtFingerprintID, recordedStmtStats)
 	}
 
+	// Statements issued by the internal executor don't count towards the
+	// automatic capture of diagnostics bundles.
+	if ex.executorType != executorTypeInternal {
+		ex.server.cfg.StmtDiagnosticsRecorder.ObserveExecution(
+			ctx, stmt.StmtNoConstants, svcLatRaw, stats.requestUnits,
+		)
+	}
+
 	// Do some transaction level accounting for the transaction this statement is
 	// a part of.
 
@@ -316,6 +345,14 @@ func (ex *connExecutor) updateOptCounters(planFlags planFlags) {
 	} else if planFlags.IsSet(planFlagOptCacheMiss) {
 		m.SQLOptPlanCacheMisses.Inc(1)
 	}
+	if planFlags.IsSet(planFlagOptCacheInvalidated) {
+		m.SQLOptPlanCacheInvalidations.Inc(1)
+	}
+	if planFlags.IsSet(planFlagGenericPlanCacheHit) {
+		m.SQLOptGenericPlanCacheHits.Inc(1)
+	} else if planFlags.IsSet(planFlagGenericPlanCacheMiss) {
+		m.SQLOptGenericPlanCacheMisses.Inc(1)
+	}
 }
 
 // We only want to keep track of DML (Data Manipulation Language) statements in our latency metrics.
@@ -13,9 +13,12 @@ package sql
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/url"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/colflow"
@@ -29,9 +32,33 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/errors"
 )
 
+// indexRecCostEstimatesEnabled controls whether EXPLAIN includes the estimated
+// impact of index recommendations on the tenant's resource consumption.
+var indexRecCostEstimatesEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.index_recommendation.cost_estimates.enabled",
+	"if enabled, index recommendations in EXPLAIN output include the estimated "+
+		"request unit impact of reads and index maintenance writes",
+	false,
+	settings.WithPublic,
+)
+
+// writeCostEstimatesEnabled controls whether EXPLAIN includes the estimated
+// cost of the rows written by mutations, according to the tenant cost model.
+var writeCostEstimatesEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.explain.write_cost_estimates.enabled",
+	"if enabled, EXPLAIN output includes the estimated request units of the rows "+
+		"written by each mutation, accounting for secondary index fan-out and the "+
+		"write amplification of the statement type",
+	false,
+	settings.WithPublic,
+)
+
 // explainPlanNode implements EXPLAIN (PLAN) and EXPLAIN (DISTSQL); it produces
 // the output of EXPLAIN given an explain.Plan.
 type explainPlanNode struct {
@@ -144,6 +171,11 @@ func (e *explainPlanNode) startExec(params runParams) error {
 		// First add empty row.
 		rows = append(rows, "")
 		rows = append(rows, fmt.Sprintf("index recommendations: %d", len(recs)))
+		var costCfg *tenantcostmodel.Config
+		if sv := &params.ExecCfg().Settings.SV; indexRecCostEstimatesEnabled.Get(sv) {
+			cfg := tenantcostmodel.ConfigFromSettings(sv)
+			costCfg = &cfg
+		}
 		for i := range recs {
 			plural := ""
 			recType := ""
@@ -160,6 +192,46 @@ func (e *explainPlanNode) startExec(params runParams) error {
 			}
 			rows = append(rows, fmt.Sprintf("%d. type: %s", i+1, recType))
 			rows = append(rows, fmt.Sprintf("   SQL command%s: %s", plural, recs[i].SQL))
+			if costCfg != nil {
+				est := recs[i].EstimateCost(costCfg)
+				rows = append(rows, fmt.Sprintf(
+					"   estimated read savings: %.2f RU (%.4f KV CPU seconds) per execution",
+					est.ReadSavings, est.ReadSavingsKVCPUSeconds,
+				))
+				rows = append(rows, fmt.Sprintf(
+					"   estimated write cost: %.2f RU (%.4f KV CPU seconds) per row written",
+					est.WriteCost, est.WriteCostKVCPUSeconds,
+				))
+			}
+		}
+	}
+	// Add write cost estimates to output, if enabled.
+	if sv := &params.ExecCfg().Settings.SV; writeCostEstimatesEnabled.Get(sv) &&
+		e.options.Mode != tree.ExplainGist && !e.options.Flags[tree.ExplainFlagJSON] {
+		if writes := e.plan.MutationWrites(); len(writes) > 0 {
+			costCfg := tenantcostmodel.ConfigFromSettings(sv)
+			rows = append(rows, "")
+			rows = append(rows, fmt.Sprintf("write cost estimates: %d", len(writes)))
+			for i := range writes {
+				w := &writes[i]
+				cost := w.EstimatedCost(&costCfg)
+				rows = append(rows, fmt.Sprintf("%d. table: %s (%s)", i+1, w.Table.Name(), w.Type))
+				rows = append(rows, fmt.Sprintf(
+					"   index writes per row: %d (write amplification: %.2f)",
+					w.IndexWrites, costCfg.EstimatedWriteAmplification[w.Type],
+				))
+				rows = append(rows, fmt.Sprintf(
+					"   estimated write cost: %.2f RU (%.4f KV CPU seconds) per row written",
+					cost, costCfg.EstimatedKVCPUSeconds(cost),
+				))
+				if w.HasEstimatedRowCount {
+					rowCount := math.Ceil(w.EstimatedRowCount)
+					rows = append(rows, fmt.Sprintf(
+						"   estimated total write cost: %.2f RU for %s rows",
+						cost*tenantcostmodel.RU(rowCount), humanizeutil.Count(uint64(rowCount)),
+					))
+				}
+			}
 		}
 	}
 	v := params.p.newContainerValuesNode(colinfo.ExplainPlanColumns, len(rows))
@@ -87,6 +87,11 @@ type instrumentationHelper struct {
 	implicitTxn bool
 	txnPriority roachpb.UserPriority
 
+	// stmtRetries is the number of automatic retries consumed by the statement,
+	// either by retrying its transaction or, in READ COMMITTED transactions, by
+	// retrying the statement alone.
+	stmtRetries int32
+
 	codec keys.SQLCodec
 
 	// -- The following fields are initialized by Setup() --
@@ -422,6 +427,7 @@ func (ih *instrumentationHelper) Setup(
 	ih.fingerprint = fingerprint
 	ih.implicitTxn = implicitTxn
 	ih.txnPriority = txnPriority
+	ih.stmtRetries = 0
 	ih.codec = cfg.Codec
 	ih.origCtx = ctx
 	ih.evalCtx = p.EvalContext()
@@ -812,6 +818,7 @@ func (ih *instrumentationHelper) emitExplainAnalyzePlanToOutputBuilder(
 	ob.AddExecutionTime(phaseTimes.GetRunLatency())
 	ob.AddDistribution(ih.distribution.String())
 	ob.AddVectorized(ih.vectorized)
+	ob.AddRetries(int64(ih.stmtRetries))
 
 	if queryStats != nil {
 		if queryStats.KVRowsRead != 0 {
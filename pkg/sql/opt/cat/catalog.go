@@ -202,6 +202,10 @@ type Catalog interface {
 	// CheckRoleExists returns an error if the role does not exist.
 	CheckRoleExists(ctx context.Context, role username.SQLUsername) error
 
+	// IsTableWrittenInTxn returns true if the current transaction has planned
+	// a write to the table with the given ID.
+	IsTableWrittenInTxn(id StableID) bool
+
 	// Optimizer returns the query Optimizer used to optimize SQL statements
 	// referencing objects in this catalog, if any.
 	Optimizer() interface{}
@@ -151,6 +151,20 @@ type Table interface {
 	// such a view prior to running refresh returns an error.
 	IsRefreshViewRequired() bool
 
+	// MaterializedViewQuery returns the query of a materialized view, with
+	// fully qualified names, and the time as of which the data stored by the
+	// view reflects the query. ok is false if the table is not a materialized
+	// view, or if its data is not known to reflect its query as of any time.
+	MaterializedViewQuery() (query string, asOf time.Time, ok bool)
+
+	// DependentCount returns the number of objects, such as views, which
+	// depend on the table.
+	DependentCount() int
+
+	// DependentID returns the ID of the ith object which depends on the table,
+	// with 0 <= i < DependentCount.
+	DependentID(i int) StableID
+
 	// HomeRegion returns the home region of the table, if any, for example if
 	// a table is defined with LOCALITY REGIONAL BY TABLE.
 	HomeRegion() (region string, ok bool)
@@ -203,6 +217,10 @@ type CheckConstraint interface {
 	ColumnOrdinal(i int) int
 }
 
+// DefaultColumnSize is the estimated average size in bytes of a column that
+// has no statistics, or whose statistics have an average size of 0.
+const DefaultColumnSize = 4.0
+
 // TableStatistic is an interface to a table statistic. Each statistic is
 // associated with a set of columns.
 type TableStatistic interface {
@@ -551,6 +551,12 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (_ execPlan, outputCols colO
 	if ep, ok, err := b.tryBuildDeleteRange(del); err != nil || ok {
 		return ep, colOrdMap{}, err
 	}
+	if del.UseRangeTombstone {
+		return execPlan{}, colOrdMap{}, pgerror.Newf(pgcode.FeatureNotSupported,
+			"USING RANGE TOMBSTONE requires the DELETE to scan contiguous rows of a "+
+				"table without secondary indexes, foreign key checks or cascades",
+		)
+	}
 
 	// Ensure that order of input columns matches order of target table columns.
 	//
@@ -667,7 +673,9 @@ func (b *Builder) buildDeleteRange(del *memo.DeleteExpr) (execPlan, error) {
 	needed, _ := b.getColumns(scan.Cols, scan.Table)
 
 	autoCommit := false
-	if b.allowAutoCommit {
+	// Range tombstones are written outside of the transaction, so there is no
+	// point in committing it in the same batch.
+	if b.allowAutoCommit && !del.UseRangeTombstone {
 		// Permitting autocommit in DeleteRange is very important, because DeleteRange
 		// is used for simple deletes from primary indexes like
 		// DELETE FROM t WHERE key = 1000
@@ -696,6 +704,7 @@ func (b *Builder) buildDeleteRange(del *memo.DeleteExpr) (execPlan, error) {
 		needed,
 		scan.Constraint,
 		autoCommit,
+		del.UseRangeTombstone,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -973,6 +973,9 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		if a.AutoCommit {
 			ob.Attr("auto commit", "")
 		}
+		if a.UseRangeTombstone {
+			ob.Attr("range tombstone", "")
+		}
 		// TODO(radu): this is hacky.
 		params := exec.ScanParams{
 			NeededCols:      a.Needed,
@@ -307,6 +307,15 @@ func (ob *OutputBuilder) AddVectorized(value bool) {
 	ob.AddFlakyTopLevelField(DeflakeVectorized, "vectorized", fmt.Sprintf("%t", value))
 }
 
+// AddRetries adds a top-level field for the number of automatic retries
+// consumed by the statement, if any. If we're redacting, we leave this out to
+// keep test outputs independent of transaction retries.
+func (ob *OutputBuilder) AddRetries(retries int64) {
+	if retries > 0 && !ob.flags.Deflake.Has(DeflakeVolatile) {
+		ob.AddTopLevelField("automatic retries", string(humanizeutil.Count(uint64(retries))))
+	}
+}
+
 // AddPlanningTime adds a top-level planning time field. Cannot be called
 // while inside a node.
 func (ob *OutputBuilder) AddPlanningTime(delta time.Duration) {
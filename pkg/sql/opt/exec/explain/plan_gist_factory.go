@@ -15,6 +15,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/binary"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -593,6 +594,21 @@ func (u *unknownTable) IsRefreshViewRequired() bool {
 	return false
 }
 
+// MaterializedViewQuery is part of the cat.Table interface.
+func (u *unknownTable) MaterializedViewQuery() (query string, asOf time.Time, ok bool) {
+	return "", time.Time{}, false
+}
+
+// DependentCount is part of the cat.Table interface.
+func (u *unknownTable) DependentCount() int {
+	return 0
+}
+
+// DependentID is part of the cat.Table interface.
+func (u *unknownTable) DependentID(i int) cat.StableID {
+	panic(errors.AssertionFailedf("not implemented"))
+}
+
 // HomeRegion is part of the cat.Table interface.
 func (u *unknownTable) HomeRegion() (region string, ok bool) {
 	return "", false
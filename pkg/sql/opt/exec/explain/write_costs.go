@@ -0,0 +1,157 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package explain
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+)
+
+// MutationWrites describes the rows written by a mutation operator of a plan,
+// for the purpose of estimating their cost with the tenant cost model.
+type MutationWrites struct {
+	// Table is the table written by the mutation.
+	Table cat.Table
+	// Type is the type of statement performing the mutation.
+	Type tenantcostmodel.WriteStatementType
+	// IndexWrites is the number of index entries written for each row, i.e.
+	// the number of indexes of the table (including the primary index) that
+	// each row is written to. It accounts for the fan-out of secondary indexes.
+	IndexWrites int64
+	// RowBytes is the estimated size of the index entries written for each
+	// row. Deletes only write the keys of the entries.
+	RowBytes float64
+	// EstimatedRowCount is the estimated number of rows written by the
+	// mutation, if HasEstimatedRowCount is set.
+	EstimatedRowCount    float64
+	HasEstimatedRowCount bool
+}
+
+// EstimatedCost returns the estimated cost of each row written by the
+// mutation, according to the given cost model configuration.
+func (m *MutationWrites) EstimatedCost(cfg *tenantcostmodel.Config) tenantcostmodel.RU {
+	return cfg.EstimatedRowWriteCost(m.Type, m.IndexWrites, m.RowBytes)
+}
+
+// MutationWrites returns the rows written by the mutation operators of the
+// plan, in the order in which they appear in the plan. Cascades are not
+// included, since they are only planned during execution.
+func (p *Plan) MutationWrites() []MutationWrites {
+	var res []MutationWrites
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if m, ok := n.mutationWrites(); ok {
+			res = append(res, m)
+		}
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	for i := range p.Subqueries {
+		walk(p.Subqueries[i].Root.(*Node))
+	}
+	walk(p.Root)
+	for _, c := range p.Checks {
+		walk(c)
+	}
+	return res
+}
+
+// mutationWrites returns the rows written by the node, if it is a mutation.
+func (n *Node) mutationWrites() (MutationWrites, bool) {
+	var m MutationWrites
+	// updateCols is set for updates, which only write the indexes containing an
+	// updated column (in addition to the primary index).
+	var updateCols exec.TableColumnOrdinalSet
+	switch n.op {
+	case insertOp:
+		a := n.args.(*insertArgs)
+		m.Table, m.Type = a.Table, tenantcostmodel.InsertStatement
+	case insertFastPathOp:
+		a := n.args.(*insertFastPathArgs)
+		m.Table, m.Type = a.Table, tenantcostmodel.InsertStatement
+		m.EstimatedRowCount, m.HasEstimatedRowCount = float64(len(a.Rows)), true
+	case upsertOp:
+		a := n.args.(*upsertArgs)
+		m.Table, m.Type = a.Table, tenantcostmodel.UpsertStatement
+	case updateOp:
+		a := n.args.(*updateArgs)
+		m.Table, m.Type = a.Table, tenantcostmodel.UpdateStatement
+		updateCols = a.UpdateCols
+	case deleteOp:
+		a := n.args.(*deleteArgs)
+		m.Table, m.Type = a.Table, tenantcostmodel.DeleteStatement
+	default:
+		return MutationWrites{}, false
+	}
+
+	if !m.HasEstimatedRowCount && len(n.children) > 0 {
+		if stats, ok := n.children[0].annotations[exec.EstimatedStatsID]; ok {
+			m.EstimatedRowCount, m.HasEstimatedRowCount = stats.(*exec.EstimatedStats).RowCount, true
+		}
+	}
+
+	colSizes := tableColSizes(m.Table)
+	indexCount := m.Table.WritableIndexCount()
+	if m.Type == tenantcostmodel.DeleteStatement {
+		indexCount = m.Table.DeletableIndexCount()
+	}
+	for i := 0; i < indexCount; i++ {
+		idx := m.Table.Index(i)
+		colCount := idx.ColumnCount()
+		if m.Type == tenantcostmodel.DeleteStatement {
+			colCount = idx.KeyColumnCount()
+		}
+		var bytes float64
+		written := i == cat.PrimaryIndex || m.Type != tenantcostmodel.UpdateStatement
+		for j := 0; j < colCount; j++ {
+			col := idx.Column(j)
+			if col.Kind() == cat.System {
+				continue
+			}
+			ord := col.Ordinal()
+			if idx.IsInverted() && j == idx.KeyColumnCount()-1 {
+				ord = col.InvertedSourceColumnOrdinal()
+			}
+			if updateCols.Contains(ord) {
+				written = true
+			}
+			if size, ok := colSizes[ord]; ok {
+				bytes += size
+			} else {
+				bytes += cat.DefaultColumnSize
+			}
+		}
+		if written {
+			m.IndexWrites++
+			m.RowBytes += bytes
+		}
+	}
+	return m, true
+}
+
+// tableColSizes returns the average column sizes of the given table, based on
+// its most recent full statistics.
+func tableColSizes(tab cat.Table) map[int]float64 {
+	colSizes := make(map[int]float64)
+	// Statistics are ordered from new to old.
+	for i, n := 0, tab.StatisticCount(); i < n; i++ {
+		stat := tab.Statistic(i)
+		if stat.IsPartial() || stat.ColumnCount() != 1 || stat.AvgSize() == 0 {
+			continue
+		}
+		if ord := stat.ColumnOrdinal(0); colSizes[ord] == 0 {
+			colSizes[ord] = float64(stat.AvgSize())
+		}
+	}
+	return colSizes
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package explain
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutationWrites(t *testing.T) {
+	catalog := testcat.New()
+	_, err := catalog.ExecuteDDL(
+		"CREATE TABLE t (k INT PRIMARY KEY, a INT, b INT, c STRING, " +
+			"INDEX a_idx (a), INDEX b_idx (b) STORING (c))",
+	)
+	require.NoError(t, err)
+	tab := catalog.Table(tree.NewUnqualifiedTableName("t"))
+
+	var allCols, colC, colA exec.TableColumnOrdinalSet
+	allCols.AddRange(0, 3)
+	colA.Add(1)
+	colC.Add(3)
+
+	// plan returns the plan of a mutation of the table, whose input produces 10
+	// rows.
+	plan := func(
+		mutation func(f *Factory, input exec.Node) (exec.Node, error),
+	) *Plan {
+		f := NewFactory(exec.StubFactory{}, &tree.SemaContext{}, &eval.Context{})
+		input, err := f.ConstructValues(
+			[][]tree.TypedExpr{{tree.NewDInt(1)}},
+			colinfo.ResultColumns{{Name: "k", Typ: types.Int}},
+		)
+		require.NoError(t, err)
+		f.AnnotateNode(input, exec.EstimatedStatsID, &exec.EstimatedStats{RowCount: 10})
+		n, err := mutation(f, input)
+		require.NoError(t, err)
+		p, err := f.ConstructPlan(
+			n, nil /* subqueries */, nil /* cascades */, nil /* checks */, -1, /* rootRowCount */
+			0, /* planFlags */
+		)
+		require.NoError(t, err)
+		return p.(*Plan)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		mutation func(f *Factory, input exec.Node) (exec.Node, error)
+		exp      MutationWrites
+	}{
+		{
+			// Inserts write all the columns of every index.
+			name: "insert",
+			mutation: func(f *Factory, input exec.Node) (exec.Node, error) {
+				return f.ConstructInsert(
+					input, tab, nil /* arbiterIndexes */, nil /* arbiterConstraints */, allCols,
+					exec.TableColumnOrdinalSet{}, exec.CheckOrdinalSet{}, false, /* autoCommit */
+				)
+			},
+			exp: MutationWrites{
+				Type:        tenantcostmodel.InsertStatement,
+				IndexWrites: 3,
+				RowBytes:    (4 + 2 + 3) * cat.DefaultColumnSize,
+			},
+		},
+		{
+			// Updates write the primary index, and the indexes containing an
+			// updated column.
+			name: "update stored column",
+			mutation: func(f *Factory, input exec.Node) (exec.Node, error) {
+				return f.ConstructUpdate(
+					input, tab, allCols, colC, exec.TableColumnOrdinalSet{}, exec.CheckOrdinalSet{},
+					nil /* passthrough */, false, /* autoCommit */
+				)
+			},
+			exp: MutationWrites{
+				Type:        tenantcostmodel.UpdateStatement,
+				IndexWrites: 2,
+				RowBytes:    (4 + 3) * cat.DefaultColumnSize,
+			},
+		},
+		{
+			name: "update key column",
+			mutation: func(f *Factory, input exec.Node) (exec.Node, error) {
+				return f.ConstructUpdate(
+					input, tab, allCols, colA, exec.TableColumnOrdinalSet{}, exec.CheckOrdinalSet{},
+					nil /* passthrough */, false, /* autoCommit */
+				)
+			},
+			exp: MutationWrites{
+				Type:        tenantcostmodel.UpdateStatement,
+				IndexWrites: 2,
+				RowBytes:    (4 + 2) * cat.DefaultColumnSize,
+			},
+		},
+		{
+			// Deletes only write the keys of the entries of every index.
+			name: "delete",
+			mutation: func(f *Factory, input exec.Node) (exec.Node, error) {
+				return f.ConstructDelete(
+					input, tab, allCols, exec.TableColumnOrdinalSet{}, nil /* passthrough */, false, /* autoCommit */
+				)
+			},
+			exp: MutationWrites{
+				Type:        tenantcostmodel.DeleteStatement,
+				IndexWrites: 3,
+				RowBytes:    (1 + 2 + 2) * cat.DefaultColumnSize,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			writes := plan(tc.mutation).MutationWrites()
+			require.Len(t, writes, 1)
+			tc.exp.Table = tab
+			tc.exp.EstimatedRowCount, tc.exp.HasEstimatedRowCount = 10, true
+			require.Equal(t, tc.exp, writes[0])
+		})
+	}
+
+	// The cost of each row accounts for the index fan-out, and is scaled by the
+	// write amplification of the statement type.
+	cfg := tenantcostmodel.DefaultConfig()
+	w := MutationWrites{Type: tenantcostmodel.DeleteStatement, IndexWrites: 3, RowBytes: 1024}
+	require.Equal(t, 3*cfg.KVWriteRequest+1024*cfg.KVWriteByte, w.EstimatedCost(&cfg))
+	cfg.EstimatedWriteAmplification[tenantcostmodel.DeleteStatement] = 2
+	require.Equal(t, 2*(3*cfg.KVWriteRequest+1024*cfg.KVWriteByte), w.EstimatedCost(&cfg))
+	w.Type = tenantcostmodel.InsertStatement
+	require.Equal(t, 3*cfg.KVWriteRequest+1024*cfg.KVWriteByte, w.EstimatedCost(&cfg))
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package indexrec
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+)
+
+// defaultRowCount is the estimated number of rows of a table without
+// statistics. It matches the default used by the statistics builder.
+const defaultRowCount = 1000
+
+// CostEstimate is the estimated impact of applying an index recommendation on
+// the resource consumption of a tenant, according to the tenant cost model.
+type CostEstimate struct {
+	// ReadSavings is the estimated cost saved by each execution of the
+	// statement. It is an upper bound, since it assumes the statement reads the
+	// whole table without the recommended index.
+	ReadSavings tenantcostmodel.RU
+	// WriteCost is the estimated cost added to each row written to the table in
+	// order to maintain the recommended index.
+	WriteCost tenantcostmodel.RU
+	// ReadSavingsKVCPUSeconds and WriteCostKVCPUSeconds are the estimated KV CPU
+	// seconds corresponding to ReadSavings and WriteCost, for tenants billed
+	// under the estimated CPU model.
+	ReadSavingsKVCPUSeconds float64
+	WriteCostKVCPUSeconds   float64
+}
+
+// EstimateCost estimates the cost impact of applying the recommendation using
+// the given cost model configuration. Reads are costed by the number of bytes
+// they scan, while writes are costed by the additional KV requests and bytes
+// needed to maintain the recommended index.
+func (r *Rec) EstimateCost(cfg *tenantcostmodel.Config) CostEstimate {
+	var est CostEstimate
+	savedBytes := r.TableRows*r.TableRowSize - r.ScannedRows*r.ScannedRowSize
+	if savedBytes > 0 {
+		est.ReadSavings = tenantcostmodel.RU(savedBytes) * cfg.KVReadByte
+	}
+	est.WriteCost = tenantcostmodel.RU(r.WriteRequests)*cfg.KVWriteRequest +
+		tenantcostmodel.RU(r.WriteBytes)*cfg.KVWriteByte
+	est.ReadSavingsKVCPUSeconds = cfg.EstimatedKVCPUSeconds(est.ReadSavings)
+	est.WriteCostKVCPUSeconds = cfg.EstimatedKVCPUSeconds(est.WriteCost)
+	return est
+}
+
+// setCostInputs populates the fields of rec used by EstimateCost. The
+// existingIndex argument is the index that is replaced or altered by the
+// recommendation, if any.
+func (ir *indexRecommendation) setCostInputs(rec *Rec, existingIndex cat.Index) {
+	tab := ir.index.tab.Table
+	colSizes, tableRows := tableColSizesAndRowCount(tab)
+	colSize := func(ord int) float64 {
+		if size, ok := colSizes[ord]; ok {
+			return size
+		}
+		return cat.DefaultColumnSize
+	}
+	sumColSizes := func(ords intsets.Fast) (size float64) {
+		ords.ForEach(func(ord int) {
+			size += colSize(ord)
+		})
+		return size
+	}
+
+	rec.ScannedRows = ir.scannedRows
+	rec.TableRows = tableRows
+	rec.TableRowSize = sumColSizes(getAllCols(tab.Index(cat.PrimaryIndex)))
+	rec.ScannedRowSize = sumColSizes(ir.entryColOrds())
+
+	switch rec.RecType {
+	case TypeCreateIndex:
+		// Each write to the table must also write an entry of the new index.
+		rec.WriteRequests = 1
+		rec.WriteBytes = rec.ScannedRowSize
+	case TypeReplaceIndex:
+		// The new index replaces the existing one, so only the additional stored
+		// columns are written.
+		existingSize := sumColSizes(getAllCols(existingIndex))
+		if rec.ScannedRowSize > existingSize {
+			rec.WriteBytes = rec.ScannedRowSize - existingSize
+		}
+	case TypeAlterIndex:
+		// The existing index is already maintained on writes.
+	}
+}
+
+// entryColOrds returns the ordinals of the table columns encoded in an entry of
+// the recommended index, i.e., its explicit, implicit and stored columns.
+func (ir *indexRecommendation) entryColOrds() intsets.Fast {
+	var ords intsets.Fast
+	for i := range ir.index.cols {
+		if ir.index.IsInverted() && i == len(ir.index.cols)-1 {
+			ords.Add(ir.index.cols[i].InvertedSourceColumnOrdinal())
+			continue
+		}
+		ords.Add(ir.index.cols[i].Ordinal())
+	}
+	for i := range ir.index.suffixKeyCols {
+		ords.Add(ir.index.suffixKeyCols[i].Ordinal())
+	}
+	ords.UnionWith(ir.newStoredColOrds)
+	return ords
+}
+
+// tableColSizesAndRowCount returns the average column sizes and the row count
+// of the given table, based on its most recent full statistics.
+func tableColSizesAndRowCount(tab cat.Table) (colSizes map[int]float64, rowCount float64) {
+	colSizes = make(map[int]float64)
+	rowCount = defaultRowCount
+	foundRowCount := false
+	// Statistics are ordered from new to old.
+	for i, n := 0, tab.StatisticCount(); i < n; i++ {
+		stat := tab.Statistic(i)
+		if stat.IsPartial() {
+			continue
+		}
+		if !foundRowCount {
+			rowCount = float64(stat.RowCount())
+			foundRowCount = true
+		}
+		if stat.ColumnCount() != 1 || stat.AvgSize() == 0 {
+			continue
+		}
+		if ord := stat.ColumnOrdinal(0); colSizes[ord] == 0 {
+			colSizes[ord] = float64(stat.AvgSize())
+		}
+	}
+	return colSizes, rowCount
+}
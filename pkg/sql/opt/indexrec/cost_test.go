@@ -0,0 +1,111 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package indexrec
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+)
+
+func TestEstimateCost(t *testing.T) {
+	cfg := tenantcostmodel.Config{
+		KVReadByte:     0.01,
+		KVWriteRequest: 1,
+		KVWriteByte:    0.1,
+		KVCPUSecond:    1000,
+	}
+
+	testCases := []struct {
+		name                string
+		rec                 Rec
+		expectedReadSavings tenantcostmodel.RU
+		expectedWriteCost   tenantcostmodel.RU
+	}{
+		{
+			name: "create",
+			rec: Rec{
+				RecType:        TypeCreateIndex,
+				ScannedRows:    10,
+				ScannedRowSize: 8,
+				TableRows:      1000,
+				TableRowSize:   20,
+				WriteRequests:  1,
+				WriteBytes:     8,
+			},
+			expectedReadSavings: 199.2,
+			expectedWriteCost:   1.8,
+		},
+		{
+			name: "replace",
+			rec: Rec{
+				RecType:        TypeReplaceIndex,
+				ScannedRows:    10,
+				ScannedRowSize: 8,
+				TableRows:      1000,
+				TableRowSize:   20,
+				WriteBytes:     4,
+			},
+			expectedReadSavings: 199.2,
+			expectedWriteCost:   0.4,
+		},
+		{
+			name: "alter",
+			rec: Rec{
+				RecType:        TypeAlterIndex,
+				ScannedRows:    10,
+				ScannedRowSize: 8,
+				TableRows:      1000,
+				TableRowSize:   20,
+			},
+			expectedReadSavings: 199.2,
+			expectedWriteCost:   0,
+		},
+		{
+			name: "no savings",
+			rec: Rec{
+				RecType:        TypeCreateIndex,
+				ScannedRows:    1000,
+				ScannedRowSize: 40,
+				TableRows:      1000,
+				TableRowSize:   20,
+				WriteRequests:  1,
+				WriteBytes:     40,
+			},
+			expectedReadSavings: 0,
+			expectedWriteCost:   5,
+		},
+	}
+
+	const epsilon = 1e-9
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			est := tc.rec.EstimateCost(&cfg)
+			if math.Abs(float64(est.ReadSavings-tc.expectedReadSavings)) > epsilon {
+				t.Errorf("expected read savings %f, got %f", tc.expectedReadSavings, est.ReadSavings)
+			}
+			if math.Abs(float64(est.WriteCost-tc.expectedWriteCost)) > epsilon {
+				t.Errorf("expected write cost %f, got %f", tc.expectedWriteCost, est.WriteCost)
+			}
+			expectedReadCPU := float64(tc.expectedReadSavings / cfg.KVCPUSecond)
+			if math.Abs(est.ReadSavingsKVCPUSeconds-expectedReadCPU) > epsilon {
+				t.Errorf("expected read savings of %f KV CPU seconds, got %f",
+					expectedReadCPU, est.ReadSavingsKVCPUSeconds)
+			}
+			expectedWriteCPU := float64(tc.expectedWriteCost / cfg.KVCPUSecond)
+			if math.Abs(est.WriteCostKVCPUSeconds-expectedWriteCPU) > epsilon {
+				t.Errorf("expected write cost of %f KV CPU seconds, got %f",
+					expectedWriteCPU, est.WriteCostKVCPUSeconds)
+			}
+		})
+	}
+}
@@ -52,6 +52,29 @@ type Rec struct {
 	// Replacement is true if SQL replaces an existing index, i.e., it contains
 	// both a CREATE INDEX and DROP INDEX statement.
 	RecType Type
+
+	// The following fields are the inputs used by EstimateCost to estimate the
+	// cost impact of applying the recommendation.
+
+	// ScannedRows is the estimated number of rows read from the recommended
+	// index by the statement.
+	ScannedRows float64
+	// ScannedRowSize is the estimated size in bytes of the entries read from the
+	// recommended index.
+	ScannedRowSize float64
+	// TableRows is the estimated number of rows in the table, which is an upper
+	// bound of the number of rows read by the statement without the recommended
+	// index.
+	TableRows float64
+	// TableRowSize is the estimated size in bytes of a row of the table's
+	// primary index.
+	TableRowSize float64
+	// WriteRequests is the number of additional KV writes needed for each row
+	// written to the table in order to maintain the recommended index.
+	WriteRequests int
+	// WriteBytes is the estimated number of additional bytes written for each
+	// row written to the table in order to maintain the recommended index.
+	WriteBytes float64
 }
 
 // FindRecs finds index candidates that are scanned in an expression to
@@ -80,14 +103,15 @@ type recCollector map[cat.Table][]indexRecommendation
 func (rc recCollector) addIndexRec(md *opt.Metadata, expr opt.Expr) {
 	switch expr := expr.(type) {
 	case *memo.ScanExpr:
-		rc.addIndex(md, expr.Index, expr.Cols, expr.Table)
+		rc.addIndex(md, expr.Index, expr.Cols, expr.Table, expr.Relational().Statistics().RowCount)
 	case *memo.LookupJoinExpr:
-		rc.addIndex(md, expr.Index, expr.Cols, expr.Table)
+		rc.addIndex(md, expr.Index, expr.Cols, expr.Table, expr.Relational().Statistics().RowCount)
 	case *memo.InvertedJoinExpr:
-		rc.addIndex(md, expr.Index, expr.Cols, expr.Table)
+		rc.addIndex(md, expr.Index, expr.Cols, expr.Table, expr.Relational().Statistics().RowCount)
 	case *memo.ZigzagJoinExpr:
-		rc.addIndex(md, expr.LeftIndex, expr.Cols, expr.LeftTable)
-		rc.addIndex(md, expr.RightIndex, expr.Cols, expr.RightTable)
+		rowCount := expr.Relational().Statistics().RowCount
+		rc.addIndex(md, expr.LeftIndex, expr.Cols, expr.LeftTable, rowCount)
+		rc.addIndex(md, expr.RightIndex, expr.Cols, expr.RightTable, rowCount)
 	}
 	for i, n := 0, expr.ChildCount(); i < n; i++ {
 		rc.addIndexRec(md, expr.Child(i))
@@ -262,6 +286,7 @@ func (ir *indexRecommendation) constructIndexRec(ctx context.Context) (Rec, erro
 	}
 
 	// Formats index recommendation to its final output struct Rec.
+	rec := Rec{RecType: recType}
 	switch recType {
 	case TypeCreateIndex:
 		createCmd := tree.CreateIndex{
@@ -273,7 +298,6 @@ func (ir *indexRecommendation) constructIndexRec(ctx context.Context) (Rec, erro
 		}
 		sb.WriteString(createCmd.String())
 		sb.WriteByte(';')
-		return Rec{sb.String(), TypeCreateIndex}, nil
 	case TypeReplaceIndex:
 		dropCmd := tree.DropIndex{
 			IndexList: []*tree.TableIndexName{{
@@ -294,7 +318,6 @@ func (ir *indexRecommendation) constructIndexRec(ctx context.Context) (Rec, erro
 		sb.WriteByte(' ')
 		sb.WriteString(dropCmd.String())
 		sb.WriteByte(';')
-		return Rec{sb.String(), TypeReplaceIndex}, nil
 	case TypeAlterIndex:
 		alterCmd := tree.AlterIndexVisible{
 			Index: tree.TableIndexName{
@@ -304,9 +327,12 @@ func (ir *indexRecommendation) constructIndexRec(ctx context.Context) (Rec, erro
 		}
 		sb.WriteString(alterCmd.String())
 		sb.WriteByte(';')
-		return Rec{sb.String(), TypeAlterIndex}, nil
+	default:
+		return Rec{}, nil
 	}
-	return Rec{}, nil
+	rec.SQL = sb.String()
+	ir.setCostInputs(&rec, existingIndex)
+	return rec, nil
 }
 
 // outputIndexRec formats index recommendations to its final outputs and returns
@@ -348,9 +374,14 @@ func getColOrdSet(md *opt.Metadata, cols opt.ColSet, tabID opt.TableID) intsets.
 // addIndex adds an index to the indexes map if it does not exist already in the
 // map and in the table. The scannedCols argument contains the columns of the
 // index that are actually scanned, used to determine which columns should be
-// stored columns in the index recommendation.
+// stored columns in the index recommendation. The scannedRows argument is the
+// estimated number of rows read from the index.
 func (rc recCollector) addIndex(
-	md *opt.Metadata, indexOrd cat.IndexOrdinal, scannedCols opt.ColSet, tabID opt.TableID,
+	md *opt.Metadata,
+	indexOrd cat.IndexOrdinal,
+	scannedCols opt.ColSet,
+	tabID opt.TableID,
+	scannedRows float64,
 ) {
 	// Do not add real table indexes (non-hypothetical table indexes).
 	switch hypTable := md.TableMeta(tabID).Table.(type) {
@@ -361,12 +392,13 @@ func (rc recCollector) addIndex(
 		}
 		scannedColOrds := getColOrdSet(md, scannedCols, tabID)
 		// Try to find an identical existing index recommendation.
-		for _, indexRec := range rc[hypTable] {
+		for i, indexRec := range rc[hypTable] {
 			index := indexRec.index
 			if index.indexOrdinal == indexOrd {
 				// Update indexRec.newStoredColOrds to include all stored column
 				// ordinals that are in scannedColOrds.
 				indexRec.addStoredColOrds(scannedColOrds)
+				rc[hypTable][i].scannedRows += scannedRows
 				return
 			}
 		}
@@ -374,6 +406,7 @@ func (rc recCollector) addIndex(
 		// columns that are in scannedColOrds.
 		var newIndexRec indexRecommendation
 		newIndexRec.init(indexOrd, hypTable, scannedColOrds)
+		newIndexRec.scannedRows = scannedRows
 		rc[hypTable] = append(rc[hypTable], newIndexRec)
 	}
 }
@@ -413,6 +446,10 @@ type indexRecommendation struct {
 	// newStoredColOrds stores the stored column ordinals that are scanned by the
 	// optimizer in the expression tree passed to FindRecs.
 	newStoredColOrds intsets.Fast
+
+	// scannedRows is the estimated number of rows read from the index in the
+	// expression tree passed to FindRecs.
+	scannedRows float64
 }
 
 // init initializes an index recommendation. If there is an existingIndex with
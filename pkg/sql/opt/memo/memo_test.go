@@ -495,6 +495,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData().OptimizerPushOffsetIntoIndexJoin = false
 	notStale()
 
+	// Stale optimizer_use_materialized_view_rewrite.
+	evalCtx.SessionData().OptimizerUseMaterializedViewRewrite = true
+	stale()
+	evalCtx.SessionData().OptimizerUseMaterializedViewRewrite = false
+	notStale()
+
 	// User no longer has access to view.
 	catalog.View(tree.NewTableNameWithSchema("t", catconstants.PublicSchemaName, "abcview")).Revoked = true
 	_, err = o.Memo().IsStale(ctx, &evalCtx, catalog)
@@ -92,7 +92,7 @@ const (
 
 	// defaultColSize is the default size of a column in bytes. This is used
 	// when the table statistics have an avgSize of 0 for a given column.
-	defaultColSize = 4.0
+	defaultColSize = cat.DefaultColumnSize
 
 	// maxValuesForFullHistogramFromCheckConstraint is the maximum number of
 	// values from the spans a check constraint is allowed to have in order to build
@@ -177,6 +177,12 @@ type Builder struct {
 	// using AST annotations.
 	qualifyDataSourceNamesInAST bool
 
+	// If set, the AST is rewritten as it is when a view is created: stars are
+	// expanded and anonymous subqueries in FROM clauses are named. Used along
+	// with qualifyDataSourceNamesInAST to match statements against the queries
+	// of materialized views.
+	normalizeViewQueryInAST bool
+
 	// isCorrelated is set to true if we already reported to telemetry that the
 	// query contains a correlated subquery.
 	isCorrelated bool
@@ -353,6 +359,9 @@ func (b *Builder) buildStmt(
 
 	switch stmt := stmt.(type) {
 	case *tree.Select:
+		if inScope.atRoot {
+			return b.buildRootSelect(stmt, desiredTypes, inScope)
+		}
 		return b.buildSelect(stmt, noLocking, desiredTypes, inScope)
 
 	case *tree.ParenSelect:
@@ -92,6 +92,16 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	// All columns from the delete table will be projected.
 	mb.buildInputForDelete(inScope, del.Table, del.Where, del.Using, del.Limit, del.OrderBy)
 
+	// Rows deleted using range tombstones are not fetched, so they can't be
+	// returned.
+	if del.UseRangeTombstone {
+		if resultsNeeded(del.Returning) {
+			panic(pgerror.Newf(pgcode.FeatureNotSupported,
+				"RETURNING is not supported with USING RANGE TOMBSTONE"))
+		}
+		mb.useRangeTombstone = true
+	}
+
 	// Build the final delete statement, including any returned expressions.
 	if resultsNeeded(del.Returning) {
 		mb.buildDelete(del.Returning.(*tree.ReturningExprs))
@@ -111,6 +121,7 @@ func (mb *mutationBuilder) buildDelete(returning *tree.ReturningExprs) {
 	mb.projectPartialIndexDelCols()
 
 	private := mb.makeMutationPrivate(returning != nil)
+	private.UseRangeTombstone = mb.useRangeTombstone
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
 			private.PassthroughCols = append(private.PassthroughCols, col.id)
@@ -392,7 +392,6 @@ func (b *Builder) buildLocking(item *lockingItem, inScope *scope) {
 // locking strength.
 func (b *Builder) buildLock(lb *lockBuilder, locking opt.Locking, inScope *scope) {
 	md := b.factory.Metadata()
-	tab := md.Table(lb.table)
 	// We need to use a fresh table reference to have control over the exact
 	// column families locked.
 	newTabID := md.DuplicateTable(lb.table, b.factory.RemapCols)
@@ -426,13 +425,6 @@ func (b *Builder) buildLock(lb *lockBuilder, locking opt.Locking, inScope *scope
 			panic(errors.AssertionFailedf("cols missing key column %d", keyCol))
 		}
 	}
-	if private.Locking.WaitPolicy == tree.LockWaitSkipLocked && tab.FamilyCount() > 1 {
-		// TODO(rytaft): We may be able to support this if enough columns are
-		// pruned that only a single family is scanned.
-		panic(pgerror.Newf(pgcode.FeatureNotSupported,
-			"SKIP LOCKED cannot be used for tables with multiple column families",
-		))
-	}
 	inScope.expr = b.factory.ConstructLock(inScope.expr, private)
 }
 
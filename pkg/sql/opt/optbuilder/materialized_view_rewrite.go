@@ -0,0 +1,191 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// materializedViewCandidate is a materialized view which depends on a table
+// read by the statement being built.
+type materializedViewCandidate struct {
+	view  cat.Table
+	query string
+	asOf  time.Time
+}
+
+// buildRootSelect builds the given root SELECT statement. If the statement
+// may be rewritten to read a materialized view, it builds a copy of the
+// statement instead, whose data source names are qualified and stars expanded
+// in the AST during the build, as is done when a view is created. The text of
+// the copy is then in the form in which the queries of views are stored, and
+// is matched against the queries of the materialized views which depend on the
+// tables read by the statement; see maybeRewriteWithMaterializedView.
+func (b *Builder) buildRootSelect(
+	stmt *tree.Select, desiredTypes []*types.T, inScope *scope,
+) (outScope *scope) {
+	sd := b.evalCtx.SessionData()
+	if !sd.OptimizerUseMaterializedViewRewrite ||
+		sd.OptimizerMaterializedViewRewriteMaxStaleness == 0 ||
+		b.insideViewDef || b.insideFuncDef || b.insideSQLRoutine || b.qualifyDataSourceNamesInAST ||
+		stmt.OrderBy != nil || stmt.Locking != nil || len(b.semaCtx.Placeholders.Types) > 0 {
+		return b.buildSelect(stmt, noLocking, desiredTypes, inScope)
+	}
+
+	// The AST of the statement is not modified, since it may be reused, for
+	// example by a prepared statement.
+	parsed, err := parser.ParseOne(tree.AsStringWithFlags(stmt, tree.FmtParsable))
+	if err != nil || parsed.NumAnnotations > tree.AnnotationIdx(len(b.semaCtx.Annotations)) {
+		return b.buildSelect(stmt, noLocking, desiredTypes, inScope)
+	}
+	sel, ok := parsed.AST.(*tree.Select)
+	if !ok {
+		return b.buildSelect(stmt, noLocking, desiredTypes, inScope)
+	}
+	func() {
+		b.qualifyDataSourceNamesInAST = true
+		b.normalizeViewQueryInAST = true
+		defer func() {
+			b.qualifyDataSourceNamesInAST = false
+			b.normalizeViewQueryInAST = false
+		}()
+		outScope = b.buildSelect(sel, noLocking, desiredTypes, inScope)
+	}()
+	return b.maybeRewriteWithMaterializedView(
+		tree.AsStringWithFlags(sel, tree.FmtParsable), inScope, outScope,
+	)
+}
+
+// maybeRewriteWithMaterializedView replaces a root SELECT statement, already
+// built into origScope, with a scan of a materialized view which stores its
+// result, if such a view exists. query is the text of the statement in the
+// form in which the queries of views are stored. It returns origScope if the
+// statement can't be rewritten.
+//
+// The view is only used if its data was refreshed no earlier than
+// optimizer_materialized_view_rewrite_max_staleness before the read timestamp
+// of the statement, and if the transaction hasn't written to any of the tables
+// read by the statement, since the view doesn't reflect these writes.
+func (b *Builder) maybeRewriteWithMaterializedView(
+	query string, inScope, origScope *scope,
+) *scope {
+	candidates := b.materializedViewCandidates()
+	if len(candidates) == 0 {
+		return origScope
+	}
+	// Whether a view is fresh enough depends on the time at which the statement
+	// runs and on the writes of its transaction, so the memo can't be reused.
+	b.DisableMemoReuse = true
+
+	for _, tabMeta := range b.factory.Metadata().AllTables() {
+		if b.catalog.IsTableWrittenInTxn(tabMeta.Table.ID()) {
+			return origScope
+		}
+	}
+	maxStaleness := b.evalCtx.SessionData().OptimizerMaterializedViewRewriteMaxStaleness
+	readTime := b.evalCtx.GetStmtTimestamp()
+	if b.evalCtx.AsOfSystemTime != nil {
+		readTime = b.evalCtx.AsOfSystemTime.Timestamp.GoTime()
+	}
+	for _, c := range candidates {
+		if c.query != query {
+			continue
+		}
+		// A view refreshed after the read timestamp doesn't reflect the data
+		// visible to the statement.
+		staleness := readTime.Sub(c.asOf)
+		if staleness < 0 || staleness > maxStaleness {
+			continue
+		}
+		if err := b.catalog.CheckPrivilege(b.ctx, c.view, privilege.SELECT); err != nil {
+			continue
+		}
+		if outScope := b.buildMaterializedViewScan(c.view, inScope, origScope); outScope != nil {
+			return outScope
+		}
+	}
+	return origScope
+}
+
+// materializedViewCandidates returns the materialized views which depend on
+// the tables read by the statement being built, and whose data is known to
+// reflect their query.
+func (b *Builder) materializedViewCandidates() []materializedViewCandidate {
+	var candidates []materializedViewCandidate
+	seen := make(map[cat.StableID]struct{})
+	for _, tabMeta := range b.factory.Metadata().AllTables() {
+		tab := tabMeta.Table
+		for i, n := 0, tab.DependentCount(); i < n; i++ {
+			id := tab.DependentID(i)
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			// Dependents which can't be resolved, such as functions or views
+			// being dropped, are ignored.
+			ds, _, err := b.catalog.ResolveDataSourceByID(b.ctx, cat.Flags{}, id)
+			if err != nil {
+				continue
+			}
+			view, ok := ds.(cat.Table)
+			if !ok {
+				continue
+			}
+			if query, asOf, ok := view.MaterializedViewQuery(); ok {
+				candidates = append(candidates, materializedViewCandidate{
+					view: view, query: query, asOf: asOf,
+				})
+			}
+		}
+	}
+	return candidates
+}
+
+// buildMaterializedViewScan builds a scan of the given materialized view which
+// produces the columns of origScope. It returns nil if the columns of the view
+// don't match those of origScope.
+func (b *Builder) buildMaterializedViewScan(view cat.Table, inScope, origScope *scope) *scope {
+	viewScope := b.buildScanFromTableRef(
+		view, &tree.TableRef{TableID: int64(view.ID())}, nil /* indexFlags */, nil /* locking */, inScope,
+	)
+	outScope := inScope.push()
+	for i := range viewScope.cols {
+		if viewScope.cols[i].visibility == visible {
+			outScope.cols = append(outScope.cols, viewScope.cols[i])
+		}
+	}
+	var origCols []scopeColumn
+	for i := range origScope.cols {
+		if origScope.cols[i].visibility == visible {
+			origCols = append(origCols, origScope.cols[i])
+		}
+	}
+	if len(outScope.cols) != len(origCols) {
+		return nil
+	}
+	for i := range outScope.cols {
+		if !outScope.cols[i].typ.Identical(origCols[i].typ) {
+			return nil
+		}
+		outScope.cols[i].name = origCols[i].name
+		outScope.cols[i].table = tree.TableName{}
+	}
+	b.factory.Metadata().AddDependency(opt.DepByID(view.ID()), view, privilege.SELECT)
+	b.constructProjectForScope(viewScope, outScope)
+	return outScope
+}
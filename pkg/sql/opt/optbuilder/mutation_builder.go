@@ -194,6 +194,9 @@ type mutationBuilder struct {
 	// checks.
 	withID opt.WithID
 
+	// useRangeTombstone is set for DELETE ... USING RANGE TOMBSTONE statements.
+	useRangeTombstone bool
+
 	// extraAccessibleCols stores all the columns that are available to the
 	// mutation that are not part of the target table. This is useful for
 	// UPDATE ... FROM queries and DELETE ... USING queries, as the columns
@@ -266,11 +266,16 @@ func (h *uniqueCheckHelper) init(mb *mutationBuilder, uniqueOrdinal int) bool {
 		}
 
 		// If one of the columns is a UUID (or UUID casted to STRING or BYTES) set
-		// to gen_random_uuid() and we don't require uniqueness checks for
-		// gen_random_uuid(), unique check not needed.
+		// to gen_regional_unique_id(), unique check not needed: the generated IDs
+		// embed a unique_rowid(), which makes them unique by construction. The
+		// same holds for gen_random_uuid() if we don't require uniqueness checks
+		// for it.
 		switch mb.md.ColumnMeta(colID).Type.Family() {
 		case types.UuidFamily, types.StringFamily, types.BytesFamily:
-			if columnIsGenRandomUUID(mb.outScope.expr, colID) {
+			if columnIsGeneratedUUID(mb.outScope.expr, colID, "gen_regional_unique_id") {
+				return false
+			}
+			if columnIsGeneratedUUID(mb.outScope.expr, colID, "gen_random_uuid") {
 				requireCheck := UniquenessChecksForGenRandomUUIDClusterMode.Get(&mb.b.evalCtx.Settings.SV)
 				if !requireCheck {
 					return false
@@ -626,10 +631,10 @@ func (h *uniqueCheckHelper) buildTableScan() (outScope *scope, ordinals []int) {
 	), ordinals
 }
 
-// columnIsGenRandomUUID returns true if the expression returns the function
-// gen_random_uuid() for the given column.
-func columnIsGenRandomUUID(e memo.RelExpr, col opt.ColumnID) bool {
-	isGenRandomUUIDFunction := func(scalar opt.ScalarExpr) bool {
+// columnIsGeneratedUUID returns true if the expression returns the given UUID
+// generating function (e.g. gen_random_uuid()) for the given column.
+func columnIsGeneratedUUID(e memo.RelExpr, col opt.ColumnID, fnName string) bool {
+	isGeneratingFunction := func(scalar opt.ScalarExpr) bool {
 		if cast, ok := scalar.(*memo.CastExpr); ok &&
 			(cast.Typ.Family() == types.StringFamily || cast.Typ.Family() == types.BytesFamily) &&
 			cast.Typ.Width() == 0 {
@@ -640,7 +645,7 @@ func columnIsGenRandomUUID(e memo.RelExpr, col opt.ColumnID) bool {
 			scalar = cast.Input
 		}
 		if function, ok := scalar.(*memo.FunctionExpr); ok {
-			if function.Name == "gen_random_uuid" {
+			if function.Name == fnName {
 				return true
 			}
 		}
@@ -651,11 +656,11 @@ func columnIsGenRandomUUID(e memo.RelExpr, col opt.ColumnID) bool {
 	case opt.ProjectOp:
 		p := e.(*memo.ProjectExpr)
 		if p.Passthrough.Contains(col) {
-			return columnIsGenRandomUUID(p.Input, col)
+			return columnIsGeneratedUUID(p.Input, col, fnName)
 		}
 		for i := range p.Projections {
 			if p.Projections[i].Col == col {
-				return isGenRandomUUIDFunction(p.Projections[i].Element)
+				return isGeneratingFunction(p.Projections[i].Element)
 			}
 		}
 
@@ -666,7 +671,7 @@ func columnIsGenRandomUUID(e memo.RelExpr, col opt.ColumnID) bool {
 			return false
 		}
 		for i := range v.Rows {
-			if !isGenRandomUUIDFunction(v.Rows[i].(*memo.TupleExpr).Elems[colOrdinal]) {
+			if !isGeneratingFunction(v.Rows[i].(*memo.TupleExpr).Elems[colOrdinal]) {
 				return false
 			}
 		}
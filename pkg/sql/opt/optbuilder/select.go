@@ -77,7 +77,8 @@ func (b *Builder) buildDataSource(
 			// We do not perform this name assignment in the common case
 			// (everything else besides CREATE VIEW/FUNCTION) so as to save
 			// the cost of the string alloc / name propagation.
-			if _, ok := source.Expr.(*tree.Subquery); ok && (b.insideFuncDef || b.insideViewDef) {
+			if _, ok := source.Expr.(*tree.Subquery); ok &&
+				(b.insideFuncDef || b.insideViewDef || b.normalizeViewQueryInAST) {
 				b.subqueryNameIdx++
 				// The structure of this name is analogous to the auto-generated
 				// names for anonymous scalar expressions.
@@ -764,13 +765,6 @@ func (b *Builder) buildScan(
 			// best-effort locks for better performance.)
 			private.Locking.Durability = tree.LockDurabilityGuaranteed
 		}
-		if private.Locking.WaitPolicy == tree.LockWaitSkipLocked && tab.FamilyCount() > 1 {
-			// TODO(rytaft): We may be able to support this if enough columns are
-			// pruned that only a single family is scanned.
-			panic(pgerror.Newf(pgcode.FeatureNotSupported,
-				"SKIP LOCKED cannot be used for tables with multiple column families",
-			))
-		}
 	}
 	if b.evalCtx.AsOfSystemTime != nil && b.evalCtx.AsOfSystemTime.BoundedStaleness {
 		private.Flags.NoIndexJoin = true
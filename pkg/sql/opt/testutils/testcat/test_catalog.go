@@ -327,6 +327,11 @@ func (tc *Catalog) CheckRoleExists(ctx context.Context, role username.SQLUsernam
 	return nil
 }
 
+// IsTableWrittenInTxn is part of the cat.Catalog interface.
+func (tc *Catalog) IsTableWrittenInTxn(id cat.StableID) bool {
+	return false
+}
+
 // Optimizer is part of the cat.Catalog interface.
 func (tc *Catalog) Optimizer() interface{} {
 	return nil
@@ -1024,6 +1029,21 @@ func (tt *Table) IsRefreshViewRequired() bool {
 	return false
 }
 
+// MaterializedViewQuery is a part of the cat.Table interface.
+func (tt *Table) MaterializedViewQuery() (query string, asOf time.Time, ok bool) {
+	return "", time.Time{}, false
+}
+
+// DependentCount is a part of the cat.Table interface.
+func (tt *Table) DependentCount() int {
+	return 0
+}
+
+// DependentID is a part of the cat.Table interface.
+func (tt *Table) DependentID(i int) cat.StableID {
+	panic(errors.AssertionFailedf("no dependents"))
+}
+
 // Index implements the cat.Index interface for testing purposes.
 type Index struct {
 	IdxName string
@@ -494,6 +494,14 @@ func (oc *optCatalog) CheckRoleExists(ctx context.Context, role username.SQLUser
 	return oc.planner.CheckRoleExists(ctx, role)
 }
 
+// IsTableWrittenInTxn is part of the cat.Catalog interface.
+func (oc *optCatalog) IsTableWrittenInTxn(id cat.StableID) bool {
+	if oc.planner == nil {
+		return false
+	}
+	return oc.planner.writtenTables.isWrittenTable(descpb.ID(id))
+}
+
 // Optimizer is part of the cat.Catalog interface.
 func (oc *optCatalog) Optimizer() interface{} {
 	if oc.planner == nil {
@@ -1422,6 +1430,28 @@ func (ot *optTable) IsRefreshViewRequired() bool {
 	return ot.desc.IsRefreshViewRequired()
 }
 
+// MaterializedViewQuery is part of the cat.Table interface.
+func (ot *optTable) MaterializedViewQuery() (query string, asOf time.Time, ok bool) {
+	if !ot.desc.MaterializedView() || ot.desc.IsRefreshViewRequired() {
+		return "", time.Time{}, false
+	}
+	ts := ot.desc.GetMaterializedViewDataAsOf()
+	if ts.IsEmpty() {
+		return "", time.Time{}, false
+	}
+	return ot.desc.GetViewQuery(), ts.GoTime(), true
+}
+
+// DependentCount is part of the cat.Table interface.
+func (ot *optTable) DependentCount() int {
+	return len(ot.desc.GetDependedOnBy())
+}
+
+// DependentID is part of the cat.Table interface.
+func (ot *optTable) DependentID(i int) cat.StableID {
+	return cat.StableID(ot.desc.GetDependedOnBy()[i].ID)
+}
+
 // optIndex is a wrapper around catalog.Index that caches some
 // commonly accessed information and keeps a reference to the table wrapper.
 type optIndex struct {
@@ -2434,6 +2464,21 @@ func (ot *optVirtualTable) IsRefreshViewRequired() bool {
 	return false
 }
 
+// MaterializedViewQuery is part of the cat.Table interface.
+func (ot *optVirtualTable) MaterializedViewQuery() (query string, asOf time.Time, ok bool) {
+	return "", time.Time{}, false
+}
+
+// DependentCount is part of the cat.Table interface.
+func (ot *optVirtualTable) DependentCount() int {
+	return 0
+}
+
+// DependentID is part of the cat.Table interface.
+func (ot *optVirtualTable) DependentID(i int) cat.StableID {
+	panic(errors.AssertionFailedf("no dependents"))
+}
+
 // optVirtualIndex is a dummy implementation of cat.Index for the indexes
 // reported by a virtual table. The index assumes that table column 0 is a dummy
 // PK column.
@@ -197,7 +197,8 @@ func generateScanSpans(
 	if params.InvertedConstraint != nil {
 		return sb.SpansFromInvertedSpans(params.InvertedConstraint, params.IndexConstraint, nil /* scratch */)
 	}
-	splitter := span.MakeSplitter(tabDesc, index, params.NeededCols)
+	splitter := span.MakeSplitter(tabDesc, index, params.NeededCols).
+		WithSkipLocked(params.Locking.WaitPolicy == tree.LockWaitSkipLocked)
 	return sb.SpansFromConstraint(params.IndexConstraint, splitter)
 }
 
@@ -1346,6 +1347,7 @@ func (ef *execFactory) ConstructInsert(
 	// Derive insert table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	ef.planner.writtenTables.addWrittenTable(tabDesc.GetID())
 	cols := makeColList(table, insertColOrdSet)
 
 	// Create the table inserter, which does the bulk of the work.
@@ -1418,6 +1420,7 @@ func (ef *execFactory) ConstructInsertFastPath(
 	// Derive insert table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	ef.planner.writtenTables.addWrittenTable(tabDesc.GetID())
 	cols := makeColList(table, insertColOrdSet)
 
 	// Create the table inserter, which does the bulk of the work.
@@ -1518,6 +1521,7 @@ func (ef *execFactory) ConstructUpdate(
 	// Derive table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	ef.planner.writtenTables.addWrittenTable(tabDesc.GetID())
 	fetchCols := makeColList(table, fetchColOrdSet)
 
 	// Add each column to update as a sourceSlot. The CBO only uses scalarSlot,
@@ -1628,6 +1632,7 @@ func (ef *execFactory) ConstructUpsert(
 	// Derive table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	ef.planner.writtenTables.addWrittenTable(tabDesc.GetID())
 	insertCols := makeColList(table, insertColOrdSet)
 	fetchCols := makeColList(table, fetchColOrdSet)
 	updateCols := makeColList(table, updateColOrdSet)
@@ -1724,6 +1729,7 @@ func (ef *execFactory) ConstructDelete(
 	// Derive table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	ef.planner.writtenTables.addWrittenTable(tabDesc.GetID())
 	fetchCols := makeColList(table, fetchColOrdSet)
 
 	// Create the table deleter, which does the bulk of the work. In the HP,
@@ -1785,8 +1791,10 @@ func (ef *execFactory) ConstructDeleteRange(
 	needed exec.TableColumnOrdinalSet,
 	indexConstraint *constraint.Constraint,
 	autoCommit bool,
+	useRangeTombstone bool,
 ) (exec.Node, error) {
 	tabDesc := table.(*optTable).desc
+	ef.planner.writtenTables.addWrittenTable(tabDesc.GetID())
 	var sb span.Builder
 	sb.Init(ef.planner.EvalContext(), ef.planner.ExecCfg().Codec, tabDesc, tabDesc.GetPrimaryIndex())
 
@@ -1796,10 +1804,13 @@ func (ef *execFactory) ConstructDeleteRange(
 		return nil, err
 	}
 
+	internal := ef.planner.SessionData().Internal
 	dr := &deleteRangeNode{
-		spans:             spans,
-		desc:              tabDesc,
-		autoCommitEnabled: autoCommit,
+		spans:              spans,
+		desc:               tabDesc,
+		autoCommitEnabled:  autoCommit,
+		useRangeTombstones: useRangeTombstone,
+		metrics:            ef.planner.ExecCfg().GetRowMetrics(internal),
 	}
 
 	return dr, nil
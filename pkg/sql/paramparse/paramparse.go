@@ -13,6 +13,7 @@ package paramparse
 
 import (
 	"context"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -65,28 +66,10 @@ func DatumAsFloat(
 func DatumAsDuration(
 	ctx context.Context, evalCtx *eval.Context, name string, value tree.TypedExpr,
 ) (time.Duration, error) {
-	val, err := eval.Expr(ctx, evalCtx, value)
+	d, err := datumAsInterval(ctx, evalCtx, name, value)
 	if err != nil {
 		return 0, err
 	}
-	var d duration.Duration
-	switch v := eval.UnwrapDatum(ctx, evalCtx, val).(type) {
-	case *tree.DString:
-		datum, err := tree.ParseDInterval(evalCtx.SessionData().GetIntervalStyle(), string(*v))
-		if err != nil {
-			return 0, err
-		}
-		d = datum.Duration
-	case *tree.DInterval:
-		d = v.Duration
-	default:
-		err = pgerror.Newf(pgcode.InvalidParameterValue,
-			"parameter %q requires a duration value", name)
-		err = errors.WithDetailf(err,
-			"%s is a %s", value, errors.Safe(val.ResolvedType()))
-		return 0, err
-	}
-
 	secs, ok := d.AsInt64()
 	if !ok {
 		return 0, pgerror.Newf(
@@ -97,6 +80,51 @@ func DatumAsDuration(
 	return time.Duration(secs) * time.Second, nil
 }
 
+// DatumAsSubsecondDuration is like DatumAsDuration, but retains the sub-second
+// component of the duration instead of truncating it.
+func DatumAsSubsecondDuration(
+	ctx context.Context, evalCtx *eval.Context, name string, value tree.TypedExpr,
+) (time.Duration, error) {
+	d, err := datumAsInterval(ctx, evalCtx, name, value)
+	if err != nil {
+		return 0, err
+	}
+	secs, ok := d.AsInt64()
+	if !ok || secs > math.MaxInt64/int64(time.Second) || secs < math.MinInt64/int64(time.Second) {
+		return 0, pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			"invalid duration",
+		)
+	}
+	return time.Duration(secs)*time.Second + time.Duration(d.Nanos()%int64(time.Second)), nil
+}
+
+// datumAsInterval transforms a tree.TypedExpr containing a string or interval
+// Datum into a duration.Duration.
+func datumAsInterval(
+	ctx context.Context, evalCtx *eval.Context, name string, value tree.TypedExpr,
+) (duration.Duration, error) {
+	val, err := eval.Expr(ctx, evalCtx, value)
+	if err != nil {
+		return duration.Duration{}, err
+	}
+	switch v := eval.UnwrapDatum(ctx, evalCtx, val).(type) {
+	case *tree.DString:
+		datum, err := tree.ParseDInterval(evalCtx.SessionData().GetIntervalStyle(), string(*v))
+		if err != nil {
+			return duration.Duration{}, err
+		}
+		return datum.Duration, nil
+	case *tree.DInterval:
+		return v.Duration, nil
+	}
+	err = pgerror.Newf(pgcode.InvalidParameterValue,
+		"parameter %q requires a duration value", name)
+	err = errors.WithDetailf(err,
+		"%s is a %s", value, errors.Safe(val.ResolvedType()))
+	return duration.Duration{}, err
+}
+
 // DatumAsInt transforms a tree.TypedExpr containing a Datum into an int.
 func DatumAsInt(
 	ctx context.Context, evalCtx *eval.Context, name string, value tree.TypedExpr,
@@ -205,7 +205,7 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 			}
 		}
 
-	case NOT, WITH, AS, GENERATED, NULLS, RESET, ROLE, USER, ON, TENANT, CLUSTER, SET:
+	case NOT, WITH, AS, GENERATED, NULLS, RESET, ROLE, USER, ON, TENANT, CLUSTER, SET, USING:
 		nextToken := sqlSymType{}
 		if l.lastPos+1 < len(l.tokens) {
 			nextToken = l.tokens[l.lastPos+1]
@@ -303,6 +303,14 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 					}
 				}
 			}
+		case USING:
+			switch nextToken.id {
+			case RANGE:
+				switch secondToken.id {
+				case TOMBSTONE:
+					lval.id = USING_LA
+				}
+			}
 		}
 	}
 
@@ -28,6 +28,8 @@ func TestLexer(t *testing.T) {
 		{`NOT SIMILAR`, []int{NOT_LA, SIMILAR}},
 		{`AS OF SYSTEM TIME`, []int{AS_LA, OF, SYSTEM, TIME}},
 		{`AS OF`, []int{AS, OF}},
+		{`USING RANGE TOMBSTONE`, []int{USING_LA, RANGE, TOMBSTONE}},
+		{`USING RANGE`, []int{USING, RANGE}},
 	}
 	for i, d := range testData {
 		s := makeSQLScanner(d.sql)
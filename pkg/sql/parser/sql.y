@@ -914,14 +914,14 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 
 %token <str> BACKUP BACKUPS BACKWARD BATCH BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
-%token <str> BOOLEAN BOTH BOX2D BUNDLE BY
+%token <str> BOOLEAN BOTH BOX2D BUNDLE BURST BY
 
 %token <str> CACHE CALL CALLED CANCEL CANCELQUERY CAPABILITIES CAPABILITY CASCADE CASE CAST CBRT CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK CHECK_FILES CLOSE
 %token <str> CLUSTER CLUSTERS COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMENTS COMMIT
 %token <str> COMMITTED COMPACT COMPLETE COMPLETIONS CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
-%token <str> CONFLICT CONNECTION CONNECTIONS CONSTRAINT CONSTRAINTS CONTAINS CONTROLCHANGEFEED CONTROLJOB
-%token <str> CONVERSION CONVERT COPY COST COVERING CREATE CREATEDB CREATELOGIN CREATEROLE
+%token <str> CONFLICT CONNECTION CONNECTIONS CONSTRAINT CONSTRAINTS CONSUMPTION CONTAINS CONTROLCHANGEFEED CONTROLJOB
+%token <str> CONVERSION CONVERT COPY COST COSTS COVERING CREATE CREATEDB CREATELOGIN CREATEROLE
 %token <str> CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
 %token <str> CURRENT_USER CURSOR CYCLE
@@ -966,7 +966,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
 %token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MODIFYSQLCLUSTERSETTING MONTH MOVE
+%token <str> MATCH MATERIALIZED MERGE METADATA MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MODIFYSQLCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
@@ -1004,7 +1004,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %token <str> SUPPORT SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TENANT_NAME TENANTS TESTING_RELOCATE TEXT THEN
-%token <str> TIES TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO THROTTLING TRAILING TRACE
+%token <str> TIES TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO THROTTLING TOMBSTONE TRAILING TRACE
 %token <str> TRANSACTION TRANSACTIONS TRANSFER TRANSFORM TREAT TRIGGER TRIM TRUE
 %token <str> TRUNCATE TRUSTED TYPE TYPES
 %token <str> TRACING
@@ -1041,8 +1041,10 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 // references.
 // - TENANT_ALL is used to differentiate `ALTER TENANT <id>` from
 // `ALTER TENANT ALL`. Ditto `CLUSTER_ALL` and `CLUSTER ALL`.
+// - USING_LA is needed to differentiate `DELETE ... USING RANGE TOMBSTONE`
+// from `DELETE ... USING <table> <alias>`.
 %token NOT_LA NULLS_LA WITH_LA AS_LA GENERATED_ALWAYS GENERATED_BY_DEFAULT RESET_ALL ROLE_ALL
-%token USER_ALL ON_LA TENANT_ALL CLUSTER_ALL SET_TRACING
+%token USER_ALL ON_LA TENANT_ALL CLUSTER_ALL SET_TRACING USING_LA
 
 %union {
   id    int32
@@ -1101,6 +1103,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 
 // ALTER VIRTUAL CLUSTER CAPABILITY
 %type <tree.Statement> alter_virtual_cluster_capability_stmt
+%type <tree.Statement> alter_virtual_cluster_burst_stmt
 
 // Other ALTER VIRTUAL CLUSTER statements.
 %type <tree.Statement> alter_virtual_cluster_replication_stmt
@@ -1492,6 +1495,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
 %type <tree.TableExprs> opt_using_clause
+%type <bool> opt_using_range_tombstone
 %type <tree.RefreshDataOption> opt_clear_data
 
 %type <tree.BatchParam> batch_param
@@ -1659,7 +1663,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <*tree.BackupTargetList> opt_backup_targets
 
 %type <tree.GrantTargetList> grant_targets targets_roles target_types
-%type <tree.TableExpr> changefeed_target_expr
+%type <tree.TableExpr> changefeed_target_expr changefeed_from_expr
 %type <*tree.GrantTargetList> opt_on_targets_roles
 %type <tree.RoleSpecList> for_grantee_clause
 %type <privilege.List> privileges
@@ -3296,6 +3300,8 @@ opt_clear_data:
 //    Empty targets list: backup full cluster.
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
+//    SYSTEM METADATA: backup roles, system privileges, cluster settings, zone
+//                     configurations of the cluster's ranges and schedules only
 //
 // Destination:
 //    "[scheme]://[host]/[path to backup]?[parameters]"
@@ -3756,6 +3762,7 @@ drop_external_connection_stmt:
 // Targets:
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
+//    SYSTEM METADATA: restore the cluster metadata of a BACKUP SYSTEM METADATA
 //
 // Locations:
 //    "[scheme]://[host]/[path to backup]?[parameters]"
@@ -3797,8 +3804,14 @@ restore_stmt:
   }
 | RESTORE backup_targets FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
   {
+    targets := $2.backupTargetList()
+    coverage := tree.RequestedDescriptors
+    if targets.SystemMetadata {
+      coverage = tree.SystemMetadata
+    }
     $$.val = &tree.Restore{
-    Targets: $2.backupTargetList(),
+    Targets: targets,
+    DescriptorCoverage: coverage,
     From: $4.listOfStringOrPlaceholderOptList(),
     AsOf: $5.asOfClause(),
     Options: *($6.restoreOptions()),
@@ -3806,8 +3819,14 @@ restore_stmt:
   }
 | RESTORE backup_targets FROM string_or_placeholder IN list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
   {
+    targets := $2.backupTargetList()
+    coverage := tree.RequestedDescriptors
+    if targets.SystemMetadata {
+      coverage = tree.SystemMetadata
+    }
     $$.val = &tree.Restore{
-      Targets: $2.backupTargetList(),
+      Targets: targets,
+      DescriptorCoverage: coverage,
       Subdir: $4.expr(),
       From: $6.listOfStringOrPlaceholderOptList(),
       AsOf: $7.asOfClause(),
@@ -5409,7 +5428,7 @@ create_changefeed_stmt:
     }
   }
 | CREATE CHANGEFEED /*$3=*/ opt_changefeed_sink /*$4=*/ opt_with_options
-  AS SELECT /*$7=*/target_list FROM /*$9=*/changefeed_target_expr /*$10=*/opt_where_clause
+  AS SELECT /*$7=*/target_list FROM /*$9=*/changefeed_from_expr /*$10=*/opt_where_clause
   {
     target, err := tree.ChangefeedTargetFromTableExpr($9.tblExpr())
     if err != nil {
@@ -5478,7 +5497,7 @@ create_schedule_for_changefeed_stmt:
      }
   }
 | CREATE SCHEDULE /*$3=*/schedule_label_spec FOR CHANGEFEED /*$6=*/changefeed_sink
-  /*$7=*/opt_with_options AS SELECT /*$10=*/target_list FROM /*$12=*/changefeed_target_expr /*$13=*/opt_where_clause
+  /*$7=*/opt_with_options AS SELECT /*$10=*/target_list FROM /*$12=*/changefeed_from_expr /*$13=*/opt_where_clause
   /*$14=*/cron_expr /*$15=*/opt_with_schedule_options
   {
     target, err := tree.ChangefeedTargetFromTableExpr($12.tblExpr())
@@ -5527,6 +5546,21 @@ changefeed_target:
 
 changefeed_target_expr: insert_target
 
+// changefeed_from_expr is the FROM clause of a changefeed expression: the
+// target table, optionally joined against reference tables.
+changefeed_from_expr:
+  changefeed_target_expr
+| changefeed_from_expr LEFT join_outer opt_join_hint JOIN insert_target ON a_expr
+  {
+    $$.val = &tree.JoinTableExpr{
+      JoinType: tree.AstLeft,
+      Left:     $1.tblExpr(),
+      Right:    $6.tblExpr(),
+      Cond:     &tree.OnJoinCond{Expr: $8.expr()},
+      Hint:     $4,
+    }
+  }
+
 opt_table_prefix:
   TABLE
   {}
@@ -5569,10 +5603,11 @@ changefeed_sink:
 //    [ORDER BY <exprs...>]
 //    [USING <exprs...>]
 //    [LIMIT <expr>]
+//    [USING RANGE TOMBSTONE]
 //    [RETURNING <exprs...>]
 // %SeeAlso: WEBDOCS/delete.html
 delete_stmt:
-  opt_with_clause DELETE opt_batch_clause FROM table_expr_opt_alias_idx opt_using_clause opt_where_clause opt_sort_clause opt_limit_clause returning_clause
+  opt_with_clause DELETE opt_batch_clause FROM table_expr_opt_alias_idx opt_using_clause opt_where_clause opt_sort_clause opt_limit_clause opt_using_range_tombstone returning_clause
   {
     $$.val = &tree.Delete{
       With: $1.with(),
@@ -5582,7 +5617,8 @@ delete_stmt:
       Where: tree.NewWhere(tree.AstWhere, $7.expr()),
       OrderBy: $8.orderBy(),
       Limit: $9.limit(),
-      Returning: $10.retClause(),
+      UseRangeTombstone: $10.bool(),
+      Returning: $11.retClause(),
     }
   }
 | opt_with_clause DELETE error // SHOW HELP: DELETE
@@ -5627,6 +5663,16 @@ opt_using_clause:
     $$.val = tree.TableExprs{}
   }
 
+opt_using_range_tombstone:
+  USING_LA RANGE TOMBSTONE
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
 
 // %Help: DISCARD - reset the session to its initial state
 // %Category: Cfg
@@ -6198,6 +6244,7 @@ backup_kms:
 // Options:
 //     REPLICATION STATUS
 //     CAPABILITIES
+//     CONSUMPTION
 show_virtual_cluster_stmt:
   SHOW virtual_cluster_spec_opt_all opt_show_virtual_cluster_options
   {
@@ -6266,6 +6313,11 @@ show_virtual_cluster_options:
     /* SKIP DOC */
     $$.val = tree.ShowTenantOptions{WithPriorReplication: true}
   }
+| CONSUMPTION
+  {
+    /* SKIP DOC */
+    $$.val = tree.ShowTenantOptions{WithConsumption: true}
+  }
 | show_virtual_cluster_options ',' REPLICATION STATUS
   {
     /* SKIP DOC */
@@ -6287,6 +6339,13 @@ show_virtual_cluster_options:
     o.WithPriorReplication = true
     $$.val = o
   }
+| show_virtual_cluster_options ',' CONSUMPTION
+  {
+    /* SKIP DOC */
+    o := $1.showTenantOpts()
+    o.WithConsumption = true
+    $$.val = o
+  }
 
 // %Help: PREPARE - prepare a statement for later execution
 // %Category: Misc
@@ -6884,12 +6943,14 @@ set_csetting_stmt:
 // %Category: Group
 // %Text:
 // ALTER VIRTUAL CLUSTER REPLICATION, ALTER VIRTUAL CLUSTER SETTING,
-// ALTER VIRTUAL CLUSTER CAPABILITY, ALTER VIRTUAL CLUSTER RENAME,
-// ALTER VIRTUAL CLUSTER RESET, ALTER VIRTUAL CLUSTER SERVICE
+// ALTER VIRTUAL CLUSTER CAPABILITY, ALTER VIRTUAL CLUSTER BURST,
+// ALTER VIRTUAL CLUSTER RENAME, ALTER VIRTUAL CLUSTER RESET,
+// ALTER VIRTUAL CLUSTER SERVICE
 alter_virtual_cluster_stmt:
   alter_virtual_cluster_replication_stmt // EXTEND WITH HELP: ALTER VIRTUAL CLUSTER REPLICATION
 | alter_virtual_cluster_csetting_stmt    // EXTEND WITH HELP: ALTER VIRTUAL CLUSTER SETTING
 | alter_virtual_cluster_capability_stmt  // EXTEND WITH HELP: ALTER VIRTUAL CLUSTER CAPABILITY
+| alter_virtual_cluster_burst_stmt       // EXTEND WITH HELP: ALTER VIRTUAL CLUSTER BURST
 | alter_virtual_cluster_rename_stmt      // EXTEND WITH HELP: ALTER VIRTUAL CLUSTER RENAME
 | alter_virtual_cluster_reset_stmt       // EXTEND WITH HELP: ALTER VIRTUAL CLUSTER RESET
 | alter_virtual_cluster_service_stmt     // EXTEND WITH HELP: ALTER VIRTUAL CLUSTER SERVICE
@@ -7118,6 +7179,31 @@ alter_virtual_cluster_capability_stmt:
 | ALTER virtual_cluster virtual_cluster_spec GRANT error // SHOW HELP: ALTER VIRTUAL CLUSTER CAPABILITY
 | ALTER virtual_cluster virtual_cluster_spec REVOKE error // SHOW HELP: ALTER VIRTUAL CLUSTER CAPABILITY
 
+// %Help: ALTER VIRTUAL CLUSTER BURST - temporarily increase the request unit refill rate of a virtual cluster
+// %Category: Group
+// %Text:
+// ALTER VIRTUAL CLUSTER <virtual_cluster_spec> GRANT BURST <rate> FOR <duration>
+// ALTER VIRTUAL CLUSTER <virtual_cluster_spec> REVOKE BURST
+alter_virtual_cluster_burst_stmt:
+  ALTER virtual_cluster virtual_cluster_spec GRANT BURST a_expr FOR a_expr
+  {
+    /* SKIP DOC */
+    $$.val = &tree.AlterTenantBurst{
+      TenantSpec: $3.tenantSpec(),
+      Rate: $6.expr(),
+      Duration: $8.expr(),
+    }
+  }
+| ALTER virtual_cluster virtual_cluster_spec REVOKE BURST
+  {
+    /* SKIP DOC */
+    $$.val = &tree.AlterTenantBurst{
+      TenantSpec: $3.tenantSpec(),
+      IsRevoke: true,
+    }
+  }
+| ALTER virtual_cluster virtual_cluster_spec GRANT BURST error // SHOW HELP: ALTER VIRTUAL CLUSTER BURST
+
 virtual_cluster_capability:
   var_name
   {
@@ -9061,6 +9147,9 @@ show_range_for_row_stmt:
 //   DETAILS: add range size, leaseholder and other details
 //   KEYS:    include binary start/end keys
 //   EXPLAIN: show the SQL queries that produces the result
+//   COSTS:   add the recent cost of requests to each range; with
+//            TABLES or INDEXES, the cost of the whole range is
+//            repeated for each table or index it contains
 //
 // Note: the availability of some of the options listed above is subject
 // to cluster configuration. See the documentation for details.
@@ -9107,6 +9196,7 @@ show_ranges_options:
 | DETAILS {  $$.val = &tree.ShowRangesOptions{Details: true} }
 | KEYS    {  $$.val = &tree.ShowRangesOptions{Keys: true} }
 | EXPLAIN {  $$.val = &tree.ShowRangesOptions{Explain: true} }
+| COSTS   {  $$.val = &tree.ShowRangesOptions{Costs: true} }
 | show_ranges_options ',' TABLES
   {
     o := $1.showRangesOpts()
@@ -9139,6 +9229,12 @@ show_ranges_options:
     o.Keys = true
     $$.val = o
   }
+| show_ranges_options ',' COSTS
+  {
+    o := $1.showRangesOpts()
+    o.Costs = true
+    $$.val = o
+  }
 
 
 // %Help: SHOW SURVIVAL GOAL - list survival goals
@@ -9532,6 +9628,10 @@ backup_targets:
   {
     $$.val = tree.BackupTargetList{Databases: $2.nameList()}
   }
+| SYSTEM METADATA
+  {
+    $$.val = tree.BackupTargetList{SystemMetadata: true}
+  }
 
 // target_roles is the variant of targets which recognizes ON ROLES
 // with a name list. This cannot be included in targets directly
@@ -17065,6 +17165,7 @@ unreserved_keyword:
 | BINARY
 | BUCKET_COUNT
 | BUNDLE
+| BURST
 | BY
 | CACHE
 | CALL
@@ -17094,12 +17195,14 @@ unreserved_keyword:
 | CONNECTION
 | CONNECTIONS
 | CONSTRAINTS
+| CONSUMPTION
 | CONTROLCHANGEFEED
 | CONTROLJOB
 | CONVERSION
 | CONVERT
 | COPY
 | COST
+| COSTS
 | COVERING
 | CREATEDB
 | CREATELOGIN
@@ -17244,6 +17347,7 @@ unreserved_keyword:
 | MATERIALIZED
 | MAXVALUE
 | MERGE
+| METADATA
 | METHOD
 | MINUTE
 | MINVALUE
@@ -17457,6 +17561,7 @@ unreserved_keyword:
 | TESTING_RELOCATE
 | TEXT
 | TIES
+| TOMBSTONE
 | TRACE
 | TRACING
 | TRANSACTION
@@ -17553,6 +17658,7 @@ bare_label_keywords:
 | BOX2D
 | BUCKET_COUNT
 | BUNDLE
+| BURST
 | BY
 | CACHE
 | CALL
@@ -17591,12 +17697,14 @@ bare_label_keywords:
 | CONNECTIONS
 | CONSTRAINT
 | CONSTRAINTS
+| CONSUMPTION
 | CONTROLCHANGEFEED
 | CONTROLJOB
 | CONVERSION
 | CONVERT
 | COPY
 | COST
+| COSTS
 | COVERING
 | CREATEDB
 | CREATELOGIN
@@ -17793,6 +17901,7 @@ bare_label_keywords:
 | MATERIALIZED
 | MAXVALUE
 | MERGE
+| METADATA
 | METHOD
 | MINVALUE
 | MODIFYCLUSTERSETTING
@@ -18037,6 +18146,7 @@ bare_label_keywords:
 | TIMESTAMP
 | TIMESTAMPTZ
 | TIMETZ
+| TOMBSTONE
 | TRACE
 | TRACING
 | TRAILING
@@ -631,6 +631,18 @@ const (
 	// planFlagSessionMigration is set if the plan is being created during
 	// a session migration.
 	planFlagSessionMigration
+
+	// planFlagOptCacheInvalidated is set if a cached memo was stale and had to
+	// be rebuilt.
+	planFlagOptCacheInvalidated
+
+	// planFlagGenericPlanCacheHit is set if a prepared statement was executed
+	// with a generic plan found in the query plan cache.
+	planFlagGenericPlanCacheHit
+
+	// planFlagGenericPlanCacheMiss is set if a prepared statement was executed
+	// with a generic plan which had to be built.
+	planFlagGenericPlanCacheMiss
 )
 
 func (pf planFlags) IsSet(flag planFlags) bool {
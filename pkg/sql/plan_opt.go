@@ -158,6 +158,7 @@ func (p *planner) prepareUsingOptimizer(
 					return opc.flags, nil
 				}
 				opc.log(ctx, "query cache hit but memo is stale (prepare)")
+				opc.flags.Set(planFlagOptCacheInvalidated)
 			}
 		} else if ok {
 			opc.log(ctx, "query cache hit but there is no prepare metadata")
@@ -527,6 +528,124 @@ func (opc *optPlanningCtx) reuseMemo(
 	return f.Memo(), nil
 }
 
+const (
+	// genericPlanMinCustomPlans is the number of executions of a prepared
+	// statement which use custom plans before a generic plan is considered,
+	// when plan_cache_mode is auto.
+	genericPlanMinCustomPlans = 5
+	// genericPlanMaxCostRatio is the maximum ratio between the estimated cost
+	// of the generic plan of a prepared statement and the average cost of its
+	// custom plans for the generic plan to be used, when plan_cache_mode is
+	// auto. It accounts for the optimization time saved by the generic plan.
+	genericPlanMaxCostRatio = 1.1
+)
+
+// maybeUseGenericMemo returns a generic memo for the execution of the given
+// prepared statement, depending on the plan_cache_mode session setting. It
+// returns false if the execution should use a custom memo instead, optimized
+// for its placeholder values.
+func (opc *optPlanningCtx) maybeUseGenericMemo(
+	ctx context.Context, prepared *PreparedStatement,
+) (_ *memo.Memo, ok bool, _ error) {
+	mode := opc.p.SessionData().PlanCacheMode
+	switch {
+	case mode == sessiondatapb.PlanCacheModeForceCustom:
+		return nil, false, nil
+	case !prepared.Memo.HasPlaceholders() || prepared.Memo.IsOptimized():
+		// The prepared memo can be reused as is for any placeholder values,
+		// e.g. because it was optimized by the placeholder fast path.
+		return nil, false, nil
+	case mode == sessiondatapb.PlanCacheModeAuto &&
+		prepared.customPlans.count < genericPlanMinCustomPlans:
+		return nil, false, nil
+	}
+	genericMemo, cacheHit, err := opc.buildGenericMemo(ctx, prepared)
+	if err != nil {
+		return nil, false, err
+	}
+	if mode == sessiondatapb.PlanCacheModeAuto {
+		avgCustomCost := prepared.customPlans.totalCost / memo.Cost(prepared.customPlans.count)
+		genericCost := genericMemo.RootExpr().(memo.RelExpr).Cost()
+		if genericCost > avgCustomCost*genericPlanMaxCostRatio {
+			return nil, false, nil
+		}
+	}
+	if cacheHit {
+		opc.flags.Set(planFlagGenericPlanCacheHit)
+	} else {
+		opc.flags.Set(planFlagGenericPlanCacheMiss)
+	}
+	return genericMemo, true, nil
+}
+
+// buildGenericMemo returns the generic memo of the given prepared statement,
+// which is its prepared memo fully optimized without assigning placeholders.
+// Its plan evaluates the placeholders during execution, so it can be reused
+// for any placeholder values.
+//
+// Generic memos are kept on the prepared statement, and are also stored in the
+// query cache with the prepared memo they are built from, so that they are
+// shared by all sessions which prepare the same statement. A generic memo is
+// reused as long as it is not stale, and is rebuilt otherwise. cacheHit is
+// true if the memo was found on the prepared statement or in the cache.
+func (opc *optPlanningCtx) buildGenericMemo(
+	ctx context.Context, prepared *PreparedStatement,
+) (_ *memo.Memo, cacheHit bool, _ error) {
+	p := opc.p
+	if prepared.genericMemo != nil {
+		isStale, err := prepared.genericMemo.IsStale(ctx, p.EvalContext(), opc.catalog)
+		if err != nil {
+			return nil, false, err
+		}
+		if !isStale {
+			opc.log(ctx, "reusing generic memo")
+			return prepared.genericMemo, true, nil
+		}
+		opc.log(ctx, "generic memo is stale")
+		opc.flags.Set(planFlagOptCacheInvalidated)
+		prepared.genericMemo = nil
+	}
+	if opc.useCache {
+		cachedData, ok := p.execCfg.QueryCache.Find(&p.queryCacheSession, p.stmt.SQL)
+		if ok && cachedData.Memo == prepared.Memo && cachedData.GenericMemo != nil {
+			isStale, err := cachedData.GenericMemo.IsStale(ctx, p.EvalContext(), opc.catalog)
+			if err != nil {
+				return nil, false, err
+			}
+			if !isStale {
+				opc.log(ctx, "query cache hit (generic)")
+				prepared.genericMemo = cachedData.GenericMemo
+				return cachedData.GenericMemo, true, nil
+			}
+			opc.log(ctx, "query cache hit but generic memo is stale")
+			opc.flags.Set(planFlagOptCacheInvalidated)
+		}
+	}
+
+	f := opc.optimizer.Factory()
+	f.CopyAndReplace(
+		prepared.Memo.RootExpr().(memo.RelExpr),
+		prepared.Memo.RootProps(),
+		f.CopyWithoutAssigningPlaceholders,
+	)
+	if _, err := opc.optimizer.Optimize(); err != nil {
+		return nil, false, err
+	}
+	genericMemo := opc.optimizer.DetachMemo(ctx)
+	prepared.genericMemo = genericMemo
+
+	if opc.useCache {
+		// Only attach the generic memo to a cache entry holding the same prepared
+		// memo. Another session may have prepared the same SQL into a different
+		// memo, e.g. under different session settings, and its entry must not be
+		// overwritten.
+		if !p.execCfg.QueryCache.SetGenericMemo(p.stmt.SQL, prepared.Memo, genericMemo) {
+			opc.log(ctx, "generic memo not added to the query cache")
+		}
+	}
+	return genericMemo, false, nil
+}
+
 // buildExecMemo creates a fully optimized memo, possibly reusing a previously
 // cached memo as a starting point.
 //
@@ -553,13 +672,28 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 			return nil, err
 		} else if isStale {
 			opc.log(ctx, "rebuilding cached memo")
+			opc.flags.Set(planFlagOptCacheInvalidated)
 			prepared.Memo, err = opc.buildReusableMemo(ctx)
 			if err != nil {
 				return nil, err
 			}
+			prepared.genericMemo = nil
+		}
+		if genericMemo, ok, err := opc.maybeUseGenericMemo(ctx, prepared); err != nil {
+			return nil, err
+		} else if ok {
+			opc.log(ctx, "using generic memo")
+			return genericMemo, nil
 		}
 		opc.log(ctx, "reusing cached memo")
-		return opc.reuseMemo(ctx, prepared.Memo)
+		customMemo, err := opc.reuseMemo(ctx, prepared.Memo)
+		if err != nil {
+			return nil, err
+		}
+		if prepared.Memo.HasPlaceholders() {
+			prepared.recordCustomPlan(customMemo)
+		}
+		return customMemo, nil
 	}
 
 	if opc.useCache {
@@ -570,6 +704,7 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 				return nil, err
 			} else if isStale {
 				opc.log(ctx, "query cache hit but needed update")
+				opc.flags.Set(planFlagOptCacheInvalidated)
 				cachedData.Memo, err = opc.buildReusableMemo(ctx)
 				if err != nil {
 					return nil, err
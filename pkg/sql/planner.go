@@ -128,9 +128,12 @@ func (evalCtx *extendedEvalContext) copyFromExecCfg(execCfg *ExecutorConfig) {
 		evalCtx.SQLLivenessReader = execCfg.SQLLiveness.CachedReader()
 	}
 	evalCtx.CompactEngineSpan = execCfg.CompactEngineSpanFunc
+	evalCtx.RewriteInactiveKeyFiles = execCfg.RewriteInactiveKeyFilesFunc
 	evalCtx.SetCompactionConcurrency = execCfg.CompactionConcurrencyFunc
 	evalCtx.GetTableMetrics = execCfg.GetTableMetricsFunc
 	evalCtx.ScanStorageInternalKeys = execCfg.ScanStorageInternalKeysFunc
+	evalCtx.ApplyRecoveryPlan = execCfg.ApplyRecoveryPlanFunc
+	evalCtx.UpgradeDryRun = execCfg.UpgradeDryRunFunc
 	evalCtx.TestingKnobs = execCfg.EvalContextTestingKnobs
 	evalCtx.ClusterID = execCfg.NodeInfo.LogicalClusterID()
 	evalCtx.ClusterName = execCfg.RPCContext.ClusterName()
@@ -243,6 +246,8 @@ type planner struct {
 
 	createdSequences createdSequences
 
+	writtenTables writtenTables
+
 	// autoCommit indicates whether the plan is allowed (but not required) to
 	// commit the transaction along with other KV operations. Committing the txn
 	// might be beneficial because it may enable the 1PC optimization. Note that
@@ -469,6 +474,7 @@ func newInternalPlanner(
 	p.sqlCursors = emptySqlCursors{}
 	p.preparedStatements = emptyPreparedStatements{}
 	p.createdSequences = emptyCreatedSequences{}
+	p.writtenTables = emptyWrittenTables{}
 
 	p.schemaResolver.descCollection = p.Descriptors()
 	p.schemaResolver.sessionDataStack = sds
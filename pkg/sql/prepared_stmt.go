@@ -60,6 +60,19 @@ type PreparedStatement struct {
 	// if it is used by the optimizer as a starting point.
 	Memo *memo.Memo
 
+	// genericMemo is the generic memo built from Memo, optimized without
+	// assigning placeholders. It is reused by executions of the statement while
+	// it is not stale, and is reset when Memo is rebuilt.
+	genericMemo *memo.Memo
+
+	// customPlans tracks the estimated costs of the custom plans, optimized for
+	// the placeholder values of each execution, used by the statement. They are
+	// compared to the cost of the generic plan when plan_cache_mode is auto.
+	customPlans struct {
+		count     int
+		totalCost memo.Cost
+	}
+
 	// refCount keeps track of the number of references to this PreparedStatement.
 	// New references are registered through incRef().
 	// Once refCount hits 0 (through calls to decRef()), the following memAcc is
@@ -90,6 +103,15 @@ func (p *PreparedStatement) MemoryEstimate() int64 {
 	return size
 }
 
+// recordCustomPlan records the estimated cost of a custom plan used by an
+// execution of the statement.
+func (p *PreparedStatement) recordCustomPlan(m *memo.Memo) {
+	if rel, ok := m.RootExpr().(memo.RelExpr); ok {
+		p.customPlans.count++
+		p.customPlans.totalCost += rel.Cost()
+	}
+}
+
 func (p *PreparedStatement) decRef(ctx context.Context) {
 	if p.refCount <= 0 {
 		log.Fatal(ctx, "corrupt PreparedStatement refcount")
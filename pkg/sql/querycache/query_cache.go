@@ -56,6 +56,10 @@ type CachedData struct {
 	// PrepareMetadata is set for prepare queries. In this case the memo contains
 	// unassigned placeholders. For non-prepared queries, it is nil.
 	PrepareMetadata *PrepareMetadata
+	// GenericMemo is optionally set for prepare queries. It is the memo
+	// optimized from Memo without assigning its placeholders, which can be
+	// reused for any placeholder values.
+	GenericMemo *memo.Memo
 }
 
 func (cd *CachedData) memoryEstimate() int64 {
@@ -63,6 +67,9 @@ func (cd *CachedData) memoryEstimate() int64 {
 	if cd.PrepareMetadata != nil {
 		res += cd.PrepareMetadata.MemoryEstimate()
 	}
+	if cd.GenericMemo != nil {
+		res += cd.GenericMemo.MemoryEstimate()
+	}
 	return res
 }
 
@@ -193,6 +200,38 @@ func (c *C) Add(session *Session, d *CachedData) {
 	e.insertAfter(&c.mu.used)
 }
 
+// SetGenericMemo sets the generic memo of the cache entry for the given query.
+// The entry is only updated if it holds the prepared memo the generic memo was
+// built from, so that a session doesn't overwrite an entry added by another
+// session which prepared the same SQL into a different memo. Returns false if
+// the entry wasn't updated.
+func (c *C) SetGenericMemo(sql string, preparedMemo, genericMemo *memo.Memo) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.mu.m[sql]
+	if e == nil || e.Memo != preparedMemo {
+		return false
+	}
+	d := e.CachedData
+	d.GenericMemo = genericMemo
+	mem := d.memoryEstimate()
+	if mem > maxCachedSize || mem > c.totalMem {
+		return false
+	}
+	e.remove()
+	c.mu.availableMem += e.memoryEstimate()
+	e.CachedData = d
+
+	// Evict more entries if necessary.
+	c.makeSpace(mem)
+	c.mu.availableMem -= mem
+
+	// Insert the entry at the front of the used list.
+	e.insertAfter(&c.mu.used)
+	return true
+}
+
 // makeSpace evicts entries from the used list until we have enough free space.
 func (c *C) makeSpace(needed int64) {
 	for c.mu.availableMem < needed {
@@ -123,6 +123,42 @@ func TestCache(t *testing.T) {
 	}
 }
 
+// TestSetGenericMemo tests that generic memos are only attached to entries
+// holding the prepared memo they were built from.
+func TestSetGenericMemo(t *testing.T) {
+	sa := &memo.Memo{}
+	sb := &memo.Memo{}
+	ga := &memo.Memo{}
+
+	c := New(3 * avgCachedSize)
+
+	var s Session
+	s.Init()
+
+	if c.SetGenericMemo("a", sa, ga) {
+		t.Errorf("generic memo shouldn't be set without an entry")
+	}
+	c.Add(&s, data("a", sa, avgCachedSize))
+	c.Add(&s, data("b", sb, avgCachedSize))
+	expect(t, c, "b,a")
+
+	// An entry holding a different prepared memo must not be overwritten.
+	if c.SetGenericMemo("b", sa, ga) {
+		t.Errorf("generic memo shouldn't be set for a different prepared memo")
+	}
+	if res, ok := c.Find(&s, "b"); !ok || res.Memo != sb || res.GenericMemo != nil {
+		t.Errorf("invalid entry for b")
+	}
+
+	if !c.SetGenericMemo("a", sa, ga) {
+		t.Errorf("generic memo should be set")
+	}
+	expect(t, c, "a,b")
+	if res, ok := c.Find(&s, "a"); !ok || res.Memo != sa || res.GenericMemo != ga {
+		t.Errorf("invalid entry for a")
+	}
+}
+
 func TestCacheMemory(t *testing.T) {
 	m := &memo.Memo{}
 
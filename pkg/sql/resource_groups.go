@@ -0,0 +1,237 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// resourceGroupWeights is a cluster setting that defines the resource groups
+// that sessions can be assigned to via the resource_group session variable,
+// along with their relative weights.
+var resourceGroupWeights = settings.RegisterStringSetting(
+	settings.ApplicationLevel,
+	"sql.resource_groups.weights",
+	"comma-separated list of name=weight pairs defining the resource groups that sessions "+
+		"can be assigned to via the resource_group session variable; the transactions of "+
+		"resource groups are admitted in proportion to their weights",
+	"",
+	settings.WithValidateString(func(_ *settings.Values, s string) error {
+		_, err := parseResourceGroupWeights(s)
+		return err
+	}),
+	settings.WithPublic,
+)
+
+const (
+	// resourceGroupQuantum is the virtual cost of a transaction of a resource
+	// group with weight 1. A transaction of a resource group with weight w costs
+	// resourceGroupQuantum/w.
+	resourceGroupQuantum = 10 * time.Millisecond
+
+	// resourceGroupMaxLead bounds how far the start time of a transaction can be
+	// pushed into the future, relative to its actual start time.
+	resourceGroupMaxLead = 10 * time.Second
+
+	// resourceGroupDemotionLead is the lead over the other active groups beyond
+	// which the transactions of a resource group are admitted at low priority.
+	resourceGroupDemotionLead = time.Second
+
+	// resourceGroupActivityWindow is the period after which a resource group
+	// that hasn't started any transactions is considered idle. Idle groups do
+	// not hold back the virtual time, and they do not carry over their lead
+	// once they become active again.
+	resourceGroupActivityWindow = time.Second
+)
+
+// parseResourceGroupWeights parses the value of the sql.resource_groups.weights
+// cluster setting.
+func parseResourceGroupWeights(s string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	if strings.TrimSpace(s) == "" {
+		return weights, nil
+	}
+	for _, def := range strings.Split(s, ",") {
+		name, weightStr, ok := strings.Cut(def, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, errors.Newf("invalid resource group definition %q: expected name=weight", def)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil || weight <= 0 {
+			return nil, errors.Newf("invalid weight for resource group %q: must be a positive number", name)
+		}
+		if _, ok := weights[name]; ok {
+			return nil, errors.Newf("resource group %q is defined more than once", name)
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
+
+// setResourceGroup assigns the session to the given resource group, which must
+// be defined in the sql.resource_groups.weights cluster setting. The empty
+// string removes the session from its resource group.
+func setResourceGroup(m sessionDataMutator, group string) error {
+	if group != "" {
+		weights, err := parseResourceGroupWeights(resourceGroupWeights.Get(&m.settings.SV))
+		if err != nil {
+			return err
+		}
+		if _, ok := weights[group]; !ok {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"resource group %q is not defined in the %s cluster setting",
+				group, resourceGroupWeights.Name())
+		}
+	}
+	m.SetResourceGroup(group)
+	return nil
+}
+
+// checkCanSetResourceGroup returns an error if the current user is not allowed
+// to assign the session to the given resource group. Sessions can always go
+// back to their default resource group, which is assigned to roles with ALTER
+// ROLE ... SET resource_group. Assigning them to any other group gives them a
+// different share of the tenant's resources, so it requires the same
+// privileges as defining the groups.
+func (p *planner) checkCanSetResourceGroup(ctx context.Context, group string) error {
+	if _, defVal := getSessionVarDefaultString(
+		`resource_group`, varGen[`resource_group`], p.sessionDataMutatorIterator.sessionDataMutatorBase,
+	); group == defVal {
+		return nil
+	}
+	for _, priv := range []privilege.Kind{privilege.MODIFYCLUSTERSETTING, privilege.MODIFYSQLCLUSTERSETTING} {
+		if ok, err := p.HasGlobalPrivilegeOrRoleOption(ctx, priv); err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+	}
+	return pgerror.Newf(pgcode.InsufficientPrivilege,
+		"only users with the %s or %s privilege are allowed to set resource_group to a group "+
+			"other than the default of their role",
+		privilege.MODIFYCLUSTERSETTING, privilege.MODIFYSQLCLUSTERSETTING)
+}
+
+// resourceGroupScheduler tracks how far the resource groups are ahead of their
+// fair share. The transactions of groups that are too far ahead are admitted at
+// low priority and, while the tenant is throttled, their KV requests are paced
+// in the tenant cost client.
+//
+// It uses a variant of start-time fair queueing: each resource group keeps a
+// virtual finish time, which is advanced by resourceGroupQuantum/weight for
+// every transaction it starts. The lead of a transaction is the lead of its
+// group's virtual finish time over the smallest virtual finish time of all
+// active groups. Groups that start more transactions than their weight
+// entitles them to thus accumulate a lead over the other groups.
+type resourceGroupScheduler struct {
+	settings *cluster.Settings
+
+	mu struct {
+		syncutil.Mutex
+		// weightsStr and weights cache the parsed value of the
+		// sql.resource_groups.weights cluster setting.
+		weightsStr string
+		weights    map[string]float64
+		groups     map[string]*resourceGroupState
+	}
+}
+
+// resourceGroupState is the scheduling state of a resource group.
+type resourceGroupState struct {
+	// finish is the virtual finish time of the last transaction of the group.
+	finish time.Duration
+	// lastActive is the time at which the group last started a transaction.
+	lastActive time.Time
+}
+
+func newResourceGroupScheduler(st *cluster.Settings) *resourceGroupScheduler {
+	s := &resourceGroupScheduler{settings: st}
+	s.mu.weights = make(map[string]float64)
+	s.mu.groups = make(map[string]*resourceGroupState)
+	return s
+}
+
+// weightLocked returns the weight of the given resource group, or false if the
+// group is not defined.
+func (s *resourceGroupScheduler) weightLocked(group string) (float64, bool) {
+	if weightsStr := resourceGroupWeights.Get(&s.settings.SV); weightsStr != s.mu.weightsStr {
+		weights, err := parseResourceGroupWeights(weightsStr)
+		if err != nil {
+			// This should not happen since the setting is validated.
+			weights = make(map[string]float64)
+		}
+		s.mu.weightsStr, s.mu.weights = weightsStr, weights
+		// Forget the state of the groups that are no longer defined.
+		for name := range s.mu.groups {
+			if _, ok := weights[name]; !ok {
+				delete(s.mu.groups, name)
+			}
+		}
+	}
+	weight, ok := s.mu.weights[group]
+	return weight, ok
+}
+
+// startTxn returns the lead of the given resource group over the other active
+// groups for a transaction that starts at now, bounded by resourceGroupMaxLead.
+// It returns false if the group is not defined, in which case the transaction
+// should not be treated specially.
+func (s *resourceGroupScheduler) startTxn(group string, now time.Time) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	weight, ok := s.weightLocked(group)
+	if !ok {
+		return 0, false
+	}
+	g, ok := s.mu.groups[group]
+	if !ok {
+		g = &resourceGroupState{}
+		s.mu.groups[group] = g
+	}
+	// The virtual time is the smallest virtual finish time of all active
+	// groups.
+	var virtualTime time.Duration
+	foundActive := false
+	for _, other := range s.mu.groups {
+		if now.Sub(other.lastActive) > resourceGroupActivityWindow {
+			continue
+		}
+		if !foundActive || other.finish < virtualTime {
+			virtualTime = other.finish
+			foundActive = true
+		}
+	}
+	start := g.finish
+	if !foundActive {
+		virtualTime = start
+	} else if start < virtualTime || now.Sub(g.lastActive) > resourceGroupActivityWindow {
+		start = virtualTime
+	}
+	g.finish = start + time.Duration(float64(resourceGroupQuantum)/weight)
+	g.lastActive = now
+	lead := start - virtualTime
+	if lead > resourceGroupMaxLead {
+		lead = resourceGroupMaxLead
+	}
+	return lead, true
+}
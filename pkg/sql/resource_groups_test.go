@@ -0,0 +1,101 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResourceGroupWeights(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	weights, err := parseResourceGroupWeights("")
+	require.NoError(t, err)
+	require.Empty(t, weights)
+
+	weights, err = parseResourceGroupWeights("oltp=4, analytics = 0.5")
+	require.NoError(t, err)
+	require.Equal(t, map[string]float64{"oltp": 4, "analytics": 0.5}, weights)
+
+	for _, s := range []string{"oltp", "=1", "oltp=", "oltp=-1", "oltp=0", "oltp=1,oltp=2"} {
+		_, err := parseResourceGroupWeights(s)
+		require.Error(t, err, "%q", s)
+	}
+}
+
+func TestResourceGroupScheduler(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	resourceGroupWeights.Override(ctx, &st.SV, "a=2,b=1")
+	s := newResourceGroupScheduler(st)
+	now := time.Unix(1000, 0)
+
+	// Undefined groups are not scheduled.
+	_, ok := s.startTxn("c", now)
+	require.False(t, ok)
+
+	// A group that is alone has no lead.
+	for i := 0; i < 10; i++ {
+		lead, ok := s.startTxn("a", now)
+		require.True(t, ok)
+		require.Zero(t, lead)
+	}
+
+	// Once another group becomes active, it starts at the current virtual
+	// time; the first group isn't penalized for having used idle capacity.
+	lead, ok := s.startTxn("b", now)
+	require.True(t, ok)
+	require.Zero(t, lead)
+	lead, _ = s.startTxn("a", now)
+	require.Zero(t, lead)
+
+	// From then on, the groups accumulate leads in inverse proportion to their
+	// weights.
+	for i := 0; i < 3; i++ {
+		lead, _ = s.startTxn("b", now)
+		require.Equal(t, resourceGroupQuantum/2, lead)
+		lead, _ = s.startTxn("a", now)
+		require.Zero(t, lead)
+		lead, _ = s.startTxn("a", now)
+		require.Zero(t, lead)
+	}
+
+	// A group that keeps starting transactions while the others don't sees its
+	// lead grow, up to resourceGroupMaxLead.
+	for i := 0; i < 2*int(resourceGroupMaxLead/resourceGroupQuantum); i++ {
+		lead, _ = s.startTxn("b", now)
+	}
+	require.Equal(t, resourceGroupMaxLead, lead)
+
+	// Groups that have been idle don't carry over their lead.
+	later := now.Add(2 * resourceGroupActivityWindow)
+	lead, _ = s.startTxn("b", later)
+	require.Zero(t, lead)
+
+	// Groups that are no longer defined are forgotten.
+	resourceGroupWeights.Override(ctx, &st.SV, "b=1")
+	_, ok = s.startTxn("a", later)
+	require.False(t, ok)
+	lead, ok = s.startTxn("b", later)
+	require.True(t, ok)
+	require.Zero(t, lead)
+}
@@ -463,6 +463,7 @@ func (rf *Fetcher) Init(ctx context.Context, args FetcherInitArgs) error {
 			lockWaitPolicy:             args.LockWaitPolicy,
 			lockDurability:             args.LockDurability,
 			lockTimeout:                args.LockTimeout,
+			maxKeysPerRow:              args.Spec.MaxKeysPerRow,
 			acc:                        rf.kvFetcherMemAcc,
 			forceProductionKVBatchSize: args.ForceProductionKVBatchSize,
 			kvPairsRead:                &kvPairsRead,
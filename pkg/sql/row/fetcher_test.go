@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
@@ -250,9 +251,11 @@ func TestNextRowBatchLimiting(t *testing.T) {
 
 	alloc := &tree.DatumAlloc{}
 
-	// We try to read rows from each table.
+	// We try to read rows from each table, in both directions. Reverse scans
+	// are paginated using the same limits as forward scans, including when the
+	// key limit splits the column families of a row across batches.
 	for tableName, table := range tables {
-		t.Run(tableName, func(t *testing.T) {
+		testutils.RunTrueAndFalse(t, tableName+"/reverse", func(t *testing.T, reverse bool) {
 			tableDesc := desctestutils.TestingGetPublicTableDescriptor(kvDB, codec, sqlutils.TestDB, tableName)
 
 			args := initFetcherArgs{
@@ -261,7 +264,7 @@ func TestNextRowBatchLimiting(t *testing.T) {
 			}
 
 			txn := kv.NewTxn(ctx, kvDB, 0)
-			rf := initFetcher(t, codec, txn, args, false /*reverseScan*/, alloc, nil /*memMon*/)
+			rf := initFetcher(t, codec, txn, args, reverse, alloc, nil /*memMon*/)
 
 			if err := rf.StartScan(
 				ctx,
@@ -275,7 +278,11 @@ func TestNextRowBatchLimiting(t *testing.T) {
 
 			count := 0
 
-			expectedVals := [2]int64{1, 1}
+			// The rows are numbered from 1 to nRows.
+			nextKey, step := int64(1), int64(1)
+			if reverse {
+				nextKey, step = int64(table.nRows), -1
+			}
 			for {
 				datums, err := rf.NextRowDecoded(ctx)
 				if err != nil {
@@ -291,6 +298,8 @@ func TestNextRowBatchLimiting(t *testing.T) {
 					t.Fatalf("expected %d columns, got %d columns", table.nCols, len(datums))
 				}
 
+				// Value column is in terms of a modulo.
+				expectedVals := [2]int64{nextKey, nextKey % int64(table.modFactor)}
 				for i, expected := range expectedVals {
 					actual := int64(*datums[i].(*tree.DInt))
 					if expected != actual {
@@ -298,10 +307,7 @@ func TestNextRowBatchLimiting(t *testing.T) {
 					}
 				}
 
-				expectedVals[0]++
-				expectedVals[1]++
-				// Value column is in terms of a modulo.
-				expectedVals[1] %= int64(table.modFactor)
+				nextKey += step
 			}
 
 			if table.nRows != count {
@@ -188,6 +188,11 @@ type txnKVFetcher struct {
 	// wait while attempting to acquire a lock on a key or while blocking on an
 	// existing lock in order to perform a non-locking read on a key.
 	lockTimeout time.Duration
+	// skipLockedRowSize, if set, is the maximum number of KVs per SQL row of
+	// the index being fetched with the SKIP LOCKED wait policy. It is passed to
+	// the KV layer as WholeRowsOfSize, so that rows with any locked column
+	// family are skipped entirely rather than returned partially.
+	skipLockedRowSize int32
 
 	// alreadyFetched indicates whether fetch() has already been executed at
 	// least once.
@@ -293,6 +298,7 @@ type newTxnKVFetcherArgs struct {
 	lockWaitPolicy             descpb.ScanLockingWaitPolicy
 	lockDurability             descpb.ScanLockingDurability
 	lockTimeout                time.Duration
+	maxKeysPerRow              uint32
 	acc                        *mon.BoundAccount
 	forceProductionKVBatchSize bool
 	kvPairsRead                *int64
@@ -326,6 +332,9 @@ func newTxnKVFetcherInternal(args newTxnKVFetcherArgs) *txnKVFetcher {
 		requestAdmissionHeader:     args.admission.requestHeader,
 		responseAdmissionQ:         args.admission.responseQ,
 	}
+	if f.lockWaitPolicy == lock.WaitPolicy_SkipLocked && args.maxKeysPerRow > 1 {
+		f.skipLockedRowSize = int32(args.maxKeysPerRow)
+	}
 
 	f.maybeInitAdmissionPacer(
 		args.admission.requestHeader,
@@ -557,6 +566,8 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		// to tell the KV layer to never split SQL rows across the
 		// BatchResponses.
 		ba.Header.WholeRowsOfSize = int32(f.indexFetchSpec.MaxKeysPerRow)
+	} else if f.skipLockedRowSize > 0 {
+		ba.Header.WholeRowsOfSize = f.skipLockedRowSize
 	}
 	ba.AdmissionHeader = f.requestAdmissionHeader
 	ba.Requests = spansToRequests(
@@ -59,6 +59,7 @@ func newTxnKVFetcher(
 	lockWaitPolicy descpb.ScanLockingWaitPolicy,
 	lockDurability descpb.ScanLockingDurability,
 	lockTimeout time.Duration,
+	maxKeysPerRow uint32,
 	acc *mon.BoundAccount,
 	forceProductionKVBatchSize bool,
 ) *txnKVFetcher {
@@ -97,6 +98,7 @@ func newTxnKVFetcher(
 		lockWaitPolicy:             lockWaitPolicy,
 		lockDurability:             lockDurability,
 		lockTimeout:                lockTimeout,
+		maxKeysPerRow:              maxKeysPerRow,
 		acc:                        acc,
 		forceProductionKVBatchSize: forceProductionKVBatchSize,
 		kvPairsRead:                new(int64),
@@ -132,14 +134,16 @@ func NewDirectKVBatchFetcher(
 ) KVBatchFetcher {
 	f := newTxnKVFetcher(
 		txn, bsHeader, reverse, lockStrength, lockWaitPolicy, lockDurability,
-		lockTimeout, acc, forceProductionKVBatchSize,
+		lockTimeout, spec.MaxKeysPerRow, acc, forceProductionKVBatchSize,
 	)
 	f.scanFormat = kvpb.COL_BATCH_RESPONSE
 	f.indexFetchSpec = spec
 	return f
 }
 
-// NewKVFetcher creates a new KVFetcher.
+// NewKVFetcher creates a new KVFetcher. maxKeysPerRow is the maximum number of
+// KVs per SQL row of the index being fetched (see
+// fetchpb.IndexFetchSpec.MaxKeysPerRow).
 //
 // If acc is non-nil, this fetcher will track its fetches and must be Closed.
 // The fetcher only grows and shrinks the account according to its own use, so
@@ -153,12 +157,13 @@ func NewKVFetcher(
 	lockWaitPolicy descpb.ScanLockingWaitPolicy,
 	lockDurability descpb.ScanLockingDurability,
 	lockTimeout time.Duration,
+	maxKeysPerRow uint32,
 	acc *mon.BoundAccount,
 	forceProductionKVBatchSize bool,
 ) *KVFetcher {
 	return newKVFetcher(newTxnKVFetcher(
 		txn, bsHeader, reverse, lockStrength, lockWaitPolicy, lockDurability,
-		lockTimeout, acc, forceProductionKVBatchSize,
+		lockTimeout, maxKeysPerRow, acc, forceProductionKVBatchSize,
 	))
 }
 
@@ -141,7 +141,9 @@ func (cb *columnBackfiller) mainLoop(ctx context.Context) (roachpb.Spans, error)
 	// and we're near the end of the alloted time, go ahead and stop there, flush
 	// and return.
 	opportunisticCheckpointAfter := (cb.spec.Duration * 4) / 5
-	chunkSize := rowinfra.RowLimit(cb.spec.ChunkSize)
+	pacer := backfill.NewAdaptivePacer(
+		&cb.flowCtx.Cfg.Settings.SV, cb.flowCtx.Cfg.TenantCostController, cb.spec.ChunkSize,
+	)
 	updateChunkSizeThresholdBytes := rowinfra.BytesLimit(cb.spec.UpdateChunkSizeThresholdBytes)
 	start := timeutil.Now()
 	totalChunks := 0
@@ -155,11 +157,19 @@ func (cb *columnBackfiller) mainLoop(ctx context.Context) (roachpb.Spans, error)
 		todo := cb.spec.Spans[i]
 		for todo.Key != nil {
 			log.VEventf(ctx, 3, "column backfiller starting chunk %d: %s", chunks, todo)
+			if err := pacer.Pace(ctx); err != nil {
+				return nil, err
+			}
+			chunkStart := timeutil.Now()
+			var rows int64
 			var err error
-			todo.Key, err = cb.runChunk(ctx, todo, chunkSize, updateChunkSizeThresholdBytes, cb.spec.ReadAsOf)
+			todo.Key, rows, err = cb.runChunk(
+				ctx, todo, rowinfra.RowLimit(pacer.ChunkSize()), updateChunkSizeThresholdBytes, cb.spec.ReadAsOf,
+			)
 			if err != nil {
 				return nil, err
 			}
+			pacer.RecordChunk(ctx, rows, timeutil.Since(chunkStart))
 			chunks++
 			running := timeutil.Since(start)
 			if running > opportunisticCheckpointAfter || running > cb.spec.Duration {
@@ -298,7 +308,7 @@ func (cb *columnBackfiller) runChunk(
 	chunkSize rowinfra.RowLimit,
 	updateChunkSizeThresholdBytes rowinfra.BytesLimit,
 	_ hlc.Timestamp,
-) (roachpb.Key, error) {
+) (_ roachpb.Key, rows int64, _ error) {
 	var key roachpb.Key
 	var commitWaitFn func(context.Context) error
 	err := cb.flowCtx.Cfg.DB.Txn(
@@ -318,7 +328,7 @@ func (cb *columnBackfiller) runChunk(
 			commitWaitFn = txn.KV().DeferCommitWait(ctx)
 
 			var err error
-			key, err = cb.RunColumnBackfillChunk(
+			key, rows, err = cb.RunColumnBackfillChunk(
 				ctx,
 				txn.KV(),
 				cb.desc,
@@ -335,11 +345,11 @@ func (cb *columnBackfiller) runChunk(
 		maxCommitWaitFns := int(backfillerMaxCommitWaitFns.Get(&cb.flowCtx.Cfg.Settings.SV))
 		if len(cb.commitWaitFns) >= maxCommitWaitFns {
 			if err := cb.runCommitWait(ctx); err != nil {
-				return nil, err
+				return nil, 0, err
 			}
 		}
 	}
-	return key, err
+	return key, rows, err
 }
 
 // runCommitWait consumes the commit-wait functions that the columnBackfiller
@@ -116,9 +116,12 @@ type indexEntryBatch struct {
 func (ib *indexBackfiller) constructIndexEntries(
 	ctx context.Context, indexEntriesCh chan indexEntryBatch,
 ) error {
-	var memUsedBuildingBatch int64
+	var memUsedBuildingBatch, rows int64
 	var err error
 	var entries []rowenc.IndexEntry
+	pacer := backfill.NewAdaptivePacer(
+		&ib.flowCtx.Cfg.Settings.SV, ib.flowCtx.Cfg.TenantCostController, ib.spec.ChunkSize,
+	)
 	for i := range ib.spec.Spans {
 		log.VEventf(ctx, 2, "index backfiller starting span %d of %d: %s",
 			i+1, len(ib.spec.Spans), ib.spec.Spans[i])
@@ -129,8 +132,12 @@ func (ib *indexBackfiller) constructIndexEntries(
 			if readAsOf.IsEmpty() { // old gateway
 				readAsOf = ib.spec.WriteAsOf
 			}
-			todo.Key, entries, memUsedBuildingBatch, err = ib.buildIndexEntryBatch(ctx, todo,
-				readAsOf)
+			if err := pacer.Pace(ctx); err != nil {
+				return err
+			}
+			start := timeutil.Now()
+			todo.Key, entries, rows, memUsedBuildingBatch, err = ib.buildIndexEntryBatch(ctx, todo,
+				readAsOf, pacer.ChunkSize())
 			if err != nil {
 				return err
 			}
@@ -152,6 +159,10 @@ func (ib *indexBackfiller) constructIndexEntries(
 			case <-ctx.Done():
 				return ctx.Err()
 			}
+			// The time to hand the entries off for ingestion is included, so that
+			// the pacer also backs off when the ingestion of previous batches is
+			// being queued.
+			pacer.RecordChunk(ctx, rows, timeutil.Since(start))
 
 			knobs := ib.flowCtx.Cfg.TestingKnobs
 			// Block until the current index entry batch has been ingested. Ingested
@@ -406,15 +417,17 @@ func (ib *indexBackfiller) getProgressReportInterval() time.Duration {
 	return indexBackfillProgressReportInterval
 }
 
-// buildIndexEntryBatch constructs the index entries for a single indexBatch.
+// buildIndexEntryBatch constructs the index entries for a single indexBatch of
+// at most chunkSize rows. It returns the key to resume from, the entries, the
+// number of rows that were read and the memory used by the entries.
 func (ib *indexBackfiller) buildIndexEntryBatch(
-	tctx context.Context, sp roachpb.Span, readAsOf hlc.Timestamp,
-) (roachpb.Key, []rowenc.IndexEntry, int64, error) {
+	tctx context.Context, sp roachpb.Span, readAsOf hlc.Timestamp, chunkSize int64,
+) (roachpb.Key, []rowenc.IndexEntry, int64, int64, error) {
 	knobs := &ib.flowCtx.Cfg.TestingKnobs
-	var memUsedBuildingBatch int64
+	var memUsedBuildingBatch, rows int64
 	if knobs.RunBeforeBackfillChunk != nil {
 		if err := knobs.RunBeforeBackfillChunk(sp); err != nil {
-			return nil, nil, 0, err
+			return nil, nil, 0, 0, err
 		}
 	}
 	var key roachpb.Key
@@ -430,18 +443,18 @@ func (ib *indexBackfiller) buildIndexEntryBatch(
 
 		// TODO(knz): do KV tracing in DistSQL processors.
 		var err error
-		entries, key, memUsedBuildingBatch, err = ib.BuildIndexEntriesChunk(
-			ctx, txn.KV(), ib.desc, sp, ib.spec.ChunkSize, false, /* traceKV */
+		entries, key, rows, memUsedBuildingBatch, err = ib.BuildIndexEntriesChunk(
+			ctx, txn.KV(), ib.desc, sp, chunkSize, false, /* traceKV */
 		)
 		return err
 	}); err != nil {
-		return nil, nil, 0, err
+		return nil, nil, 0, 0, err
 	}
 	prepTime := timeutil.Since(start)
-	log.VEventf(ctx, 3, "index backfill stats: entries %d, prepare %+v",
-		len(entries), prepTime)
+	log.VEventf(ctx, 3, "index backfill stats: rows %d, entries %d, prepare %+v",
+		rows, len(entries), prepTime)
 
-	return key, entries, memUsedBuildingBatch, nil
+	return key, entries, rows, memUsedBuildingBatch, nil
 }
 
 // Resume is part of the execinfra.Processor interface.
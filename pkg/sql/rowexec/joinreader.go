@@ -963,6 +963,12 @@ func (jr *joinReader) readInput() (
 		if l := jr.limitHintHelper.LimitHint(); l != 0 && l == int64(len(jr.scratchInputRows)) {
 			break
 		}
+		if jr.curBatchSizeBytes >= jr.batchSizeBytes {
+			// The batch is full. Don't read the next row from the input only to
+			// keep it as pending: the input might block until the rows of the
+			// current batch are emitted (e.g. when evaluating CDC expressions).
+			break
+		}
 	}
 
 	if err := jr.performMemoryAccounting(); err != nil {
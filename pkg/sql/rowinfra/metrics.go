@@ -29,6 +29,22 @@ var (
 		Measurement: "Rows",
 		Unit:        metric.Unit_COUNT,
 	}
+	// MetaRangeTombstoneDelete is metadata for the
+	// sql.delete.range_tombstone.count{.internal} metrics.
+	MetaRangeTombstoneDelete = metric.Metadata{
+		Name:        "sql.delete.range_tombstone.count",
+		Help:        "Number of DELETE statements executed by writing MVCC range tombstones",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
+	// MetaRangeTombstoneDeleteRows is metadata for the
+	// sql.delete.range_tombstone.rows{.internal} metrics.
+	MetaRangeTombstoneDeleteRows = metric.Metadata{
+		Name:        "sql.delete.range_tombstone.rows",
+		Help:        "Number of rows deleted by DELETE statements that wrote MVCC range tombstones",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // Metrics holds metrics measuring calls into the KV layer by various parts of
@@ -36,6 +52,9 @@ var (
 type Metrics struct {
 	MaxRowSizeLogCount *metric.Counter
 	MaxRowSizeErrCount *metric.Counter
+
+	RangeTombstoneDeleteCount *metric.Counter
+	RangeTombstoneDeleteRows  *metric.Counter
 }
 
 var _ metric.Struct = Metrics{}
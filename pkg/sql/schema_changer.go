@@ -563,6 +563,10 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 			return nil
 		}
 		mut.State = descpb.DescriptorState_PUBLIC
+		if mut.MaterializedView() && !mut.IsRefreshViewRequired() {
+			// The view was backfilled as of its creation time.
+			mut.MaterializedViewDataAsOf = mut.GetCreateAsOfTime()
+		}
 		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, mut, txn.KV())
 	})
 }
@@ -1607,6 +1611,12 @@ func (sc *SchemaChanger) done(ctx context.Context) error {
 				// If we are mutation is in the ADD state, then start GC jobs for the
 				// existing indexes on the table.
 				if m.Adding() {
+					// Record the timestamp as of which the new indexes reflect the view
+					// query, or that they don't if they were left empty.
+					scTable.MaterializedViewDataAsOf = hlc.Timestamp{}
+					if refresh.ShouldBackfill() {
+						scTable.MaterializedViewDataAsOf = refresh.AsOf()
+					}
 					desc := fmt.Sprintf("REFRESH MATERIALIZED VIEW %q cleanup", scTable.Name)
 					for _, idx := range scTable.ActiveIndexes() {
 						if err := sc.createIndexGCJob(ctx, idx.GetID(), txn, desc); err != nil {
@@ -68,6 +68,9 @@ const (
 	// takes in a region and returns it if it is a valid region on the database.
 	// Otherwise, it returns the primary region.
 	DefaultToDatabasePrimaryRegionBuiltinName = "default_to_database_primary_region"
+	// GenRegionalUniqueIDBuiltinName is the name for the builtin that generates
+	// unique IDs prefixed by a region.
+	GenRegionalUniqueIDBuiltinName = "gen_regional_unique_id"
 	// RehomeRowBuiltinName is the name for the builtin that rehomes a row to the
 	// user's gateway region, defaulting to the database primary region.
 	RehomeRowBuiltinName = "rehome_row"
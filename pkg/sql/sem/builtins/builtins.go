@@ -17,11 +17,13 @@ import (
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding/binary"
 	gojson "encoding/json"
 	"fmt"
 	"hash"
 	"hash/crc32"
 	"hash/fnv"
+	"io"
 	"math"
 	"math/bits"
 	"net"
@@ -2159,6 +2161,61 @@ var regularBuiltins = map[string]builtinDefinition{
 		},
 	),
 
+	builtinconstants.GenRegionalUniqueIDBuiltinName: makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategoryIDGeneration,
+			// The region defaults to that of the gateway, so we should always
+			// evaluate this built-in at the gateway.
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types:      tree.ParamTypes{},
+			ReturnType: tree.FixedReturnType(types.Uuid),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				region, found := evalCtx.Locality.Find("region")
+				if !found {
+					return nil, pgerror.Newf(
+						pgcode.ConfigFile,
+						"no region set on the locality flag on this node",
+					)
+				}
+				id, err := GenerateRegionalUniqueID(
+					region, evalCtx.NodeID.SQLInstanceID(), evalCtx.ULIDEntropy,
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDUuid(tree.DUuid{UUID: id}), nil
+			},
+			Info: "Returns a globally unique, k-sortable ID prefixed by the region of the " +
+				"connection's current node. IDs generated in the same region share a prefix " +
+				"and are ordered by generation time, aligning them with REGIONAL BY ROW " +
+				"partitioning. The ID embeds the insert timestamp and the ID of the node " +
+				"executing the statement, so uniqueness checks are not required for columns " +
+				"set to this function. Returns an error if no region is set.",
+			Volatility: volatility.Volatile,
+		},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "region", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.Uuid),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				id, err := GenerateRegionalUniqueID(
+					string(tree.MustBeDString(args[0])), evalCtx.NodeID.SQLInstanceID(), evalCtx.ULIDEntropy,
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDUuid(tree.DUuid{UUID: id}), nil
+			},
+			Info: "Returns a globally unique, k-sortable ID prefixed by the given region. " +
+				"IDs generated for the same region share a prefix and are ordered by " +
+				"generation time, aligning them with REGIONAL BY ROW partitioning. The ID " +
+				"embeds the insert timestamp and the ID of the node executing the statement, " +
+				"so uniqueness checks are not required for columns set to this function.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
 	// Sequence functions.
 
 	"nextval": makeBuiltin(
@@ -6128,6 +6185,101 @@ SELECT
 		},
 	),
 
+	"crdb_internal.estimate_mvcc_garbage_reclaimed": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "table_id", Typ: types.Int},
+				{Name: "gc_ttl", Typ: types.Interval},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				hasViewActivity, _, err := evalCtx.SessionAccessor.HasViewActivityOrViewActivityRedactedRole(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if !hasViewActivity {
+					return nil, pgerror.Newf(pgcode.InsufficientPrivilege, "user needs ADMIN role or the VIEWACTIVITY/VIEWACTIVITYREDACTED permission to view span statistics")
+				}
+				tableID := int64(tree.MustBeDInt(args[0]))
+				if tableID <= 0 {
+					return nil, pgerror.New(pgcode.InvalidParameterValue, "provided table id must be greater than or equal to 1")
+				}
+				ttl := tree.MustBeDInterval(args[1]).Duration
+				if ttl.Compare(duration.Duration{}) < 0 {
+					return nil, pgerror.New(pgcode.InvalidParameterValue, "gc_ttl must not be negative")
+				}
+				start := evalCtx.Codec.TablePrefix(uint32(tableID))
+				span := roachpb.Span{Key: start, EndKey: start.PrefixEnd()}
+				resp, err := evalCtx.Planner.SpanStats(ctx, roachpb.Spans{span})
+				if err != nil {
+					return nil, err
+				}
+				stats, ok := resp.SpanToStats[span.String()]
+				if !ok {
+					return nil, errors.AssertionFailedf("could not find span stats for table span: %s", span)
+				}
+				reclaimed := stats.TotalStats.EstimatedGCBytesOlderThan(
+					evalCtx.GetStmtTimestamp().UnixNano(), ttl.AsFloat64())
+				return tree.NewDInt(tree.DInt(reclaimed)), nil
+			},
+			Info: "Estimates the bytes of MVCC garbage of the table with the given ID which a GC " +
+				"would reclaim if the table's GC TTL were set to the given duration. The estimate " +
+				"assumes that the garbage was generated at a steady rate.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
+	"crdb_internal.min_servable_staleness": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "keys", Typ: types.BytesArray},
+			},
+			ReturnType: tree.FixedReturnType(types.Interval),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				// The keys are arbitrary, so the user must have VIEWCLUSTERMETADATA
+				// to use this builtin.
+				if err := evalCtx.SessionAccessor.CheckPrivilege(
+					ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.VIEWCLUSTERMETADATA,
+				); err != nil {
+					return nil, err
+				}
+				arr := tree.MustBeDArray(args[0])
+				queryKeys := make([]roachpb.Key, 0, len(arr.Array))
+				for _, datum := range arr.Array {
+					if datum == tree.DNull {
+						continue
+					}
+					queryKeys = append(queryKeys, roachpb.Key(tree.MustBeDBytes(datum)))
+				}
+				db := evalCtx.Txn.DB()
+				resolvedTS, err := db.QueryResolvedTimestampForKeys(ctx, queryKeys, true /* nearest */)
+				if err != nil {
+					return nil, err
+				}
+				if resolvedTS.IsEmpty() {
+					return tree.DNull, nil
+				}
+				staleness := max(db.Clock().PhysicalTime().Sub(resolvedTS.GoTime()), 0)
+				return tree.NewDInterval(
+					duration.MakeDuration(staleness.Nanoseconds(), 0 /* days */, 0 /* months */),
+					types.DefaultIntervalTypeMetadata,
+				), nil
+			},
+			Info: "Returns the minimum staleness at which all the given keys can currently be read " +
+				"from the nearest replicas without blocking, or NULL if it is unknown. Keys can be " +
+				"built with crdb_internal.encode_key. The result can be used to pick the staleness " +
+				"bound of bounded staleness reads with with_max_staleness. Requires the " +
+				"VIEWCLUSTERMETADATA system privilege.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
 	// Returns a namespace_id based on parentID and a given name.
 	// Allows a non-admin to query the system.namespace table, but performs
 	// the relevant permission checks to ensure secure access.
@@ -6785,6 +6937,46 @@ SELECT
 			Volatility: volatility.Volatile,
 		},
 	),
+	"crdb_internal.unsafe_apply_recovery_plan": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         builtinconstants.CategorySystemRepair,
+			DistsqlBlocklist: true,
+			Undocumented:     true,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "plan_id", Typ: types.Uuid},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if err := evalCtx.SessionAccessor.CheckPrivilege(
+					ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.REPAIRCLUSTER,
+				); err != nil {
+					return nil, err
+				}
+				if evalCtx.ApplyRecoveryPlan == nil {
+					return nil, pgerror.New(pgcode.FeatureNotSupported,
+						"loss of quorum recovery is only supported on the system tenant")
+				}
+				planID := tree.MustBeDUuid(args[0]).UUID
+
+				log.Warningf(ctx, "crdb_internal.unsafe_apply_recovery_plan applying plan %s", planID)
+
+				nodeErrors, err := evalCtx.ApplyRecoveryPlan(ctx, planID)
+				if err != nil {
+					return nil, err
+				}
+				if len(nodeErrors) > 0 {
+					return nil, errors.Newf("failed to apply loss of quorum recovery plan %s:\n%s",
+						planID, strings.Join(nodeErrors, "\n"))
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "Applies the staged loss of quorum recovery plan with the given ID on all " +
+				"nodes of the cluster without restarting them.",
+			Volatility: volatility.Volatile,
+		},
+	),
 	"crdb_internal.upsert_dropped_relation_gc_ttl": makeBuiltin(
 		tree.FunctionProperties{
 			Category:         builtinconstants.CategorySystemRepair,
@@ -7021,6 +7213,38 @@ Parameters:` + randgencfg.ConfigDoc,
 		},
 	),
 
+	"crdb_internal.rewrite_inactive_key_files": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         builtinconstants.CategorySystemRepair,
+			DistsqlBlocklist: true,
+			Undocumented:     true,
+		},
+		tree.Overload{
+			Types:      tree.ParamTypes{},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return rewriteInactiveKeyFiles(ctx, evalCtx, 0 /* nodeID */)
+			},
+			Info: "This function is used to rewrite the files of the stores of all the nodes " +
+				"which are encrypted with inactive encryption-at-rest data keys. The rewrite runs " +
+				"in the background; its progress is reported by crdb_internal.kv_store_encryption_keys.",
+			Volatility: volatility.Volatile,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "node_id", Typ: types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return rewriteInactiveKeyFiles(ctx, evalCtx, int32(tree.MustBeDInt(args[0])))
+			},
+			Info: "This function is used to rewrite the files of the stores of the given node " +
+				"which are encrypted with inactive encryption-at-rest data keys. The rewrite runs " +
+				"in the background; its progress is reported by crdb_internal.kv_store_encryption_keys.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
 	"crdb_internal.increment_feature_counter": makeBuiltin(
 		tree.FunctionProperties{
 			Category:     builtinconstants.CategorySystemInfo,
@@ -10453,6 +10677,37 @@ func GenerateUniqueUnorderedID(instanceID base.SQLInstanceID) tree.DInt {
 	return tree.DInt(uniqueUnorderedID)
 }
 
+// GenerateRegionalUniqueID creates a unique, k-sortable UUID prefixed by a
+// tag derived from the given region. The first 2 bytes hold the region tag,
+// the following 8 bytes hold a unique int as generated by GenerateUniqueInt
+// (in big-endian order, so IDs generated for a region sort by time), and the
+// remaining 6 bytes are read from the given entropy source. The random suffix
+// guards against collisions should an instance ID be reused while another
+// process still holds it (see GenerateUniqueInt).
+//
+// Since the region tag is a hash of the region name, IDs of distinct regions
+// may share a prefix; this affects their layout in the keyspace, but not
+// their uniqueness, which is provided by the unique int.
+func GenerateRegionalUniqueID(
+	region string, instanceID base.SQLInstanceID, entropy io.Reader,
+) (uuid.UUID, error) {
+	var id uuid.UUID
+	binary.BigEndian.PutUint16(id[0:2], regionalUniqueIDTag(region))
+	binary.BigEndian.PutUint64(id[2:10], uint64(GenerateUniqueInt(ProcessUniqueID(instanceID))))
+	if _, err := io.ReadFull(entropy, id[10:]); err != nil {
+		return uuid.UUID{}, err
+	}
+	return id, nil
+}
+
+// regionalUniqueIDTag returns the 16-bit tag identifying the given region in
+// IDs generated by GenerateRegionalUniqueID.
+func regionalUniqueIDTag(region string) uint16 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(region))
+	return uint16(h.Sum32() >> 16)
+}
+
 // mapToUnorderedUniqueInt is used by GenerateUniqueUnorderedID to convert a
 // serial unique uint64 to an unordered unique int64. It accomplishes this by
 // reversing the timestamp portion of the unique ID. This bit manipulation
@@ -11487,6 +11742,24 @@ func withMaxStaleness(
 	return tree.MakeDTimestampTZ(t, time.Microsecond)
 }
 
+// rewriteInactiveKeyFiles starts rewriting the files encrypted with inactive
+// encryption-at-rest data keys on the given node, or on all nodes if nodeID is
+// zero.
+func rewriteInactiveKeyFiles(
+	ctx context.Context, evalCtx *eval.Context, nodeID int32,
+) (tree.Datum, error) {
+	if err := evalCtx.SessionAccessor.CheckPrivilege(
+		ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.REPAIRCLUSTER,
+	); err != nil {
+		return nil, err
+	}
+	log.Infof(ctx, "crdb_internal.rewrite_inactive_key_files called for nodeID=%d", nodeID)
+	if err := evalCtx.RewriteInactiveKeyFiles(ctx, nodeID); err != nil {
+		return nil, err
+	}
+	return tree.DBoolTrue, nil
+}
+
 func jsonNumInvertedIndexEntries(_ *eval.Context, val tree.Datum) (tree.Datum, error) {
 	if val == tree.DNull {
 		return tree.DZero, nil
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"cmp"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/bits"
@@ -37,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -82,6 +84,40 @@ func TestGenerateUniqueIDOrder(t *testing.T) {
 	}
 }
 
+func TestGenerateRegionalUniqueID(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	entropy := rand.New(rand.NewSource(timeutil.Now().UnixNano()))
+	gen := func(region string) uuid.UUID {
+		id, err := GenerateRegionalUniqueID(region, 1 /* instanceID */, entropy)
+		require.NoError(t, err)
+		return id
+	}
+
+	// IDs generated for a region share a prefix and are ordered by generation
+	// time.
+	const numIDs = 100
+	ids := make([]uuid.UUID, numIDs)
+	for i := range ids {
+		ids[i] = gen("us-east1")
+	}
+	for i := 1; i < numIDs; i++ {
+		require.Equal(t, ids[0][:2], ids[i][:2])
+		require.Negative(t, bytes.Compare(ids[i-1][:], ids[i][:]),
+			"%s should sort before %s", ids[i-1], ids[i])
+	}
+
+	// IDs generated for different regions have different prefixes, and
+	// remain unique.
+	west := gen("us-west1")
+	require.NotEqual(t, ids[0][:2], west[:2])
+	require.Equal(t, regionalUniqueIDTag("us-west1"), binary.BigEndian.Uint16(west[:2]))
+	for _, id := range ids {
+		require.NotEqual(t, id, west)
+	}
+}
+
 // sorterWithSwapCount implements sort.Interface and wraps a slice of data
 // with a counter that tracks the number of swaps performed during sorting.
 type sorterWithSwapCount[T cmp.Ordered] struct {
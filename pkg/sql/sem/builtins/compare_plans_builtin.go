@@ -0,0 +1,246 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// comparePlansQuery retrieves the distinct plan gists persisted for a
+// statement fingerprint, along with execution stats aggregated per plan, in
+// order of first appearance.
+const comparePlansQuery = `
+SELECT
+	gist,
+	min(aggregated_ts),
+	max(aggregated_ts),
+	sum(execution_count)::INT8,
+	(sum(service_latency * execution_count) / NULLIF(sum(execution_count), 0))::FLOAT8
+FROM (
+	SELECT
+		statistics->'statistics'->'planGists'->>0 AS gist,
+		aggregated_ts,
+		execution_count,
+		service_latency
+	FROM system.statement_statistics
+	WHERE fingerprint_id = $1
+)
+WHERE gist IS NOT NULL AND gist != ''
+GROUP BY gist
+ORDER BY min(aggregated_ts), gist
+`
+
+var comparePlansGeneratorType = types.String
+
+// comparePlansGenerator implements crdb_internal.compare_plans. It emits a
+// summary of every plan recorded for a statement fingerprint, followed by a
+// side-by-side diff of each plan against the one preceding it.
+type comparePlansGenerator struct {
+	fingerprintID []byte
+	evalCtx       *eval.Context
+	rows          []string
+	index         int
+}
+
+var _ eval.ValueGenerator = &comparePlansGenerator{}
+
+func makeComparePlansGenerator(
+	ctx context.Context, evalCtx *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	hasViewActivity, _, err := evalCtx.SessionAccessor.HasViewActivityOrViewActivityRedactedRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !hasViewActivity {
+		return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+			"user needs ADMIN role or the VIEWACTIVITY/VIEWACTIVITYREDACTED permission to compare plans")
+	}
+	return &comparePlansGenerator{
+		fingerprintID: []byte(tree.MustBeDBytes(args[0])),
+		evalCtx:       evalCtx,
+	}, nil
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *comparePlansGenerator) ResolvedType() *types.T {
+	return comparePlansGeneratorType
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *comparePlansGenerator) Start(ctx context.Context, _ *kv.Txn) (retErr error) {
+	it, err := g.evalCtx.Planner.QueryIteratorEx(
+		ctx,
+		"crdb_internal.compare_plans",
+		sessiondata.NodeUserSessionDataOverride,
+		comparePlansQuery,
+		tree.NewDBytes(tree.DBytes(g.fingerprintID)),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.CombineErrors(retErr, it.Close())
+	}()
+
+	var plans []comparedPlan
+	var ok bool
+	for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+		row := it.Cur()
+		p := comparedPlan{
+			gist:      string(tree.MustBeDString(row[0])),
+			firstSeen: tree.MustBeDTimestampTZ(row[1]).Time,
+			lastSeen:  tree.MustBeDTimestampTZ(row[2]).Time,
+			execCount: int64(tree.MustBeDInt(row[3])),
+		}
+		if row[4] != tree.DNull {
+			p.meanLatency = time.Duration(float64(tree.MustBeDFloat(row[4])) * float64(time.Second))
+		}
+		if p.lines, err = g.evalCtx.Planner.DecodeGist(ctx, p.gist, false /* external */); err != nil {
+			return err
+		}
+		plans = append(plans, p)
+	}
+	if err != nil {
+		return err
+	}
+
+	g.rows = renderPlanComparison(plans)
+	g.index = -1
+	return nil
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *comparePlansGenerator) Next(context.Context) (bool, error) {
+	g.index++
+	return g.index < len(g.rows), nil
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *comparePlansGenerator) Values() (tree.Datums, error) {
+	return tree.Datums{tree.NewDString(g.rows[g.index])}, nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *comparePlansGenerator) Close(context.Context) {}
+
+// comparedPlan is a plan recorded for a statement fingerprint, decoded from
+// its gist.
+type comparedPlan struct {
+	gist        string
+	firstSeen   time.Time
+	lastSeen    time.Time
+	execCount   int64
+	meanLatency time.Duration
+	lines       []string
+}
+
+// renderPlanComparison renders the given plans, in order, as a summary table of
+// their execution stats followed by a side-by-side diff of each plan against
+// the one preceding it.
+func renderPlanComparison(plans []comparedPlan) []string {
+	if len(plans) == 0 {
+		return []string{"no plans found for statement fingerprint"}
+	}
+
+	const timeFmt = "2006-01-02 15:04:05 MST"
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 4, 0, 2, ' ', 0)
+	fmt.Fprint(w, "plan\tgist\tfirst seen\tlast seen\texecutions\tmean latency\n")
+	for i, p := range plans {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n", i+1, p.gist,
+			p.firstSeen.UTC().Format(timeFmt), p.lastSeen.UTC().Format(timeFmt),
+			p.execCount, p.meanLatency.Round(time.Microsecond))
+	}
+	_ = w.Flush()
+	out := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i := range out {
+		out[i] = strings.TrimRight(out[i], " ")
+	}
+
+	if len(plans) == 1 {
+		out = append(out, "", "plan 1:")
+		return append(out, plans[0].lines...)
+	}
+	for i := 1; i < len(plans); i++ {
+		out = append(out, "", fmt.Sprintf("plan %d vs. plan %d:", i, i+1))
+		out = append(out, diffPlansSideBySide(plans[i-1].lines, plans[i].lines)...)
+	}
+	return out
+}
+
+// diffPlansSideBySide renders two plans next to each other, in the style of
+// sdiff: lines that differ are marked with '|', lines only present in the
+// left plan with '<', and lines only present in the right plan with '>'.
+func diffPlansSideBySide(left, right []string) []string {
+	var width int
+	for _, l := range left {
+		if n := utf8.RuneCountInString(l); n > width {
+			width = n
+		}
+	}
+	line := func(l string, marker byte, r string) string {
+		s := l + strings.Repeat(" ", width-utf8.RuneCountInString(l)) +
+			" " + string(marker) + " " + r
+		return strings.TrimRight(s, " ")
+	}
+
+	var out []string
+	m := difflib.NewMatcher(left, right)
+	for _, op := range m.GetOpCodes() {
+		switch op.Tag {
+		case 'e':
+			for i := op.I1; i < op.I2; i++ {
+				out = append(out, line(left[i], ' ', right[op.J1+i-op.I1]))
+			}
+		case 'r':
+			for k := 0; k < max(op.I2-op.I1, op.J2-op.J1); k++ {
+				var l, r string
+				marker := byte('|')
+				if op.I1+k < op.I2 {
+					l = left[op.I1+k]
+				} else {
+					marker = '>'
+				}
+				if op.J1+k < op.J2 {
+					r = right[op.J1+k]
+				} else {
+					marker = '<'
+				}
+				out = append(out, line(l, marker, r))
+			}
+		case 'd':
+			for i := op.I1; i < op.I2; i++ {
+				out = append(out, line(left[i], '<', ""))
+			}
+		case 'i':
+			for j := op.J1; j < op.J2; j++ {
+				out = append(out, line("", '>', right[j]))
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPlanComparison(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fullScan := comparedPlan{
+		gist:        "AgHQAQIAAAAAAg==",
+		firstSeen:   ts,
+		lastSeen:    ts.Add(time.Hour),
+		execCount:   10,
+		meanLatency: 2 * time.Millisecond,
+		lines:       []string{"• scan", "  table: t@t_pkey", "  spans: FULL SCAN"},
+	}
+	indexScan := comparedPlan{
+		gist:        "AgHQAQQAAgAAAAYC",
+		firstSeen:   ts.Add(2 * time.Hour),
+		lastSeen:    ts.Add(3 * time.Hour),
+		execCount:   5,
+		meanLatency: 150 * time.Microsecond,
+		lines:       []string{"• scan", "  table: t@t_b_idx", "  spans: [/1 - /1]"},
+	}
+
+	t.Run("none", func(t *testing.T) {
+		require.Equal(t,
+			[]string{"no plans found for statement fingerprint"},
+			renderPlanComparison(nil))
+	})
+
+	t.Run("single", func(t *testing.T) {
+		out := renderPlanComparison([]comparedPlan{fullScan})
+		require.Len(t, out, 7)
+		require.Equal(t,
+			[]string{"plan", "gist", "first", "seen", "last", "seen", "executions", "mean", "latency"},
+			strings.Fields(out[0]))
+		require.Equal(t, []string{"1", "AgHQAQIAAAAAAg==", "2024-05-01", "12:00:00", "UTC",
+			"2024-05-01", "13:00:00", "UTC", "10", "2ms"}, strings.Fields(out[1]))
+		require.Equal(t, append([]string{"", "plan 1:"}, fullScan.lines...), out[2:])
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		out := renderPlanComparison([]comparedPlan{fullScan, indexScan})
+		require.Len(t, out, 8)
+		require.Equal(t, []string{"2", "AgHQAQQAAgAAAAYC", "2024-05-01", "14:00:00", "UTC",
+			"2024-05-01", "15:00:00", "UTC", "5", "150µs"}, strings.Fields(out[2]))
+		require.Equal(t, []string{
+			"",
+			"plan 1 vs. plan 2:",
+			"• scan               • scan",
+			"  table: t@t_pkey  |   table: t@t_b_idx",
+			"  spans: FULL SCAN |   spans: [/1 - /1]",
+		}, out[3:])
+	})
+
+	t.Run("added and removed lines", func(t *testing.T) {
+		require.Equal(t, []string{
+			"a   a",
+			"b   b",
+			"  > c",
+		}, diffPlansSideBySide([]string{"a", "b"}, []string{"a", "b", "c"}))
+		require.Equal(t, []string{
+			"a   a",
+			"b <",
+			"c   c",
+		}, diffPlansSideBySide([]string{"a", "b", "c"}, []string{"a", "c"}))
+	})
+}
@@ -2581,6 +2581,16 @@ var builtinOidsArray = []string{
 	2613: `crdb_internal.split_at(key: bytes, ttl: interval) -> void`,
 	2614: `crdb_internal.scatter(key: bytes) -> void`,
 	2615: `crdb_internal.scatter(key: bytes, end_key: bytes) -> void`,
+	2616: `crdb_internal.compare_plans(fingerprint_id: bytes) -> string`,
+	2617: `gen_regional_unique_id() -> uuid`,
+	2618: `gen_regional_unique_id(region: string) -> uuid`,
+	2619: `crdb_internal.unsafe_apply_recovery_plan(plan_id: uuid) -> bool`,
+	2620: `crdb_internal.upgrade_dry_run() -> tuple{string AS version, string AS upgrade, bool AS dry_run_supported, int[] AS descriptor_ids, string[] AS spans, int AS estimated_ranges, int AS estimated_rows, string[] AS details}`,
+	2621: `crdb_internal.upgrade_dry_run(version: string) -> tuple{string AS version, string AS upgrade, bool AS dry_run_supported, int[] AS descriptor_ids, string[] AS spans, int AS estimated_ranges, int AS estimated_rows, string[] AS details}`,
+	2622: `crdb_internal.estimate_mvcc_garbage_reclaimed(table_id: int, gc_ttl: interval) -> int`,
+	2623: `crdb_internal.min_servable_staleness(keys: bytes[]) -> interval`,
+	2624: `crdb_internal.rewrite_inactive_key_files() -> bool`,
+	2625: `crdb_internal.rewrite_inactive_key_files(node_id: int) -> bool`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
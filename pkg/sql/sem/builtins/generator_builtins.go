@@ -574,6 +574,19 @@ The output can be used to recreate a database.'
 			volatility.Volatile,
 		),
 	),
+	"crdb_internal.compare_plans": makeBuiltin(
+		tree.FunctionProperties{},
+		makeGeneratorOverload(
+			tree.ParamTypes{
+				{Name: "fingerprint_id", Typ: types.Bytes},
+			},
+			comparePlansGeneratorType,
+			makeComparePlansGenerator,
+			`Returns rows of output comparing the plans recorded in the statement_statistics table for a statement fingerprint, along with execution stats for each plan, as a side-by-side diff of each plan against the one preceding it.
+			`,
+			volatility.Volatile,
+		),
+	),
 	"crdb_internal.gen_rand_ident": makeBuiltin(
 		tree.FunctionProperties{},
 		makeGeneratorOverload(
@@ -715,6 +728,27 @@ The last argument is a JSONB object containing the following optional fields:
 			volatility.Volatile,
 		),
 	),
+	"crdb_internal.upgrade_dry_run": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		makeGeneratorOverload(
+			tree.ParamTypes{},
+			upgradeDryRunGeneratorType,
+			makeUpgradeDryRunGenerator,
+			"Estimates the impact of the upgrades that would run when finalizing an upgrade of the cluster to the binary version, without running them. Upgrades that can't be dry run are reported with NULL estimates.",
+			volatility.Volatile,
+		),
+		makeGeneratorOverload(
+			tree.ParamTypes{
+				{Name: "version", Typ: types.String},
+			},
+			upgradeDryRunGeneratorType,
+			makeUpgradeDryRunGenerator,
+			"Estimates the impact of the upgrades that would run when upgrading the cluster to the given version, without running them. Upgrades that can't be dry run are reported with NULL estimates.",
+			volatility.Volatile,
+		),
+	),
 	"crdb_internal.execute_internally": makeBuiltin(
 		tree.FunctionProperties{
 			Undocumented: true,
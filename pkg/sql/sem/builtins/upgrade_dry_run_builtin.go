@@ -0,0 +1,144 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/upgrade/upgradebase"
+)
+
+var upgradeDryRunGeneratorType = types.MakeLabeledTuple(
+	[]*types.T{
+		types.String, types.String, types.Bool, types.IntArray, types.StringArray, types.Int,
+		types.Int, types.StringArray,
+	},
+	[]string{
+		"version",
+		"upgrade",
+		"dry_run_supported",
+		"descriptor_ids",
+		"spans",
+		"estimated_ranges",
+		"estimated_rows",
+		"details",
+	},
+)
+
+// upgradeDryRunGenerator implements crdb_internal.upgrade_dry_run. It emits a
+// row for each upgrade that would run when moving the cluster from its active
+// version to the target version, describing the impact of the upgrade.
+type upgradeDryRunGenerator struct {
+	evalCtx *eval.Context
+	target  roachpb.Version
+	results []upgradebase.DryRunResult
+	index   int
+}
+
+var _ eval.ValueGenerator = &upgradeDryRunGenerator{}
+
+func makeUpgradeDryRunGenerator(
+	ctx context.Context, evalCtx *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	if err := evalCtx.SessionAccessor.CheckPrivilege(
+		ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.MODIFYCLUSTERSETTING,
+	); err != nil {
+		return nil, err
+	}
+	if evalCtx.UpgradeDryRun == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported, "upgrade dry runs are not supported")
+	}
+	target := evalCtx.Settings.Version.LatestVersion()
+	if len(args) > 0 {
+		var err error
+		target, err = roachpb.ParseVersion(string(tree.MustBeDString(args[0])))
+		if err != nil {
+			return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+		}
+	}
+	return &upgradeDryRunGenerator{
+		evalCtx: evalCtx,
+		target:  target,
+	}, nil
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *upgradeDryRunGenerator) ResolvedType() *types.T {
+	return upgradeDryRunGeneratorType
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *upgradeDryRunGenerator) Start(ctx context.Context, _ *kv.Txn) error {
+	var err error
+	g.results, err = g.evalCtx.UpgradeDryRun(ctx, g.target)
+	return err
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *upgradeDryRunGenerator) Next(_ context.Context) (bool, error) {
+	g.index++
+	return g.index <= len(g.results), nil
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *upgradeDryRunGenerator) Values() (tree.Datums, error) {
+	res := g.results[g.index-1]
+	row := tree.Datums{
+		tree.NewDString(res.Version.PrettyPrint()),
+		tree.NewDString(res.Name),
+		tree.MakeDBool(tree.DBool(res.Supported)),
+		tree.DNull,
+		tree.DNull,
+		tree.DNull,
+		tree.DNull,
+		tree.DNull,
+	}
+	if !res.Supported {
+		return row, nil
+	}
+
+	descriptors := tree.NewDArray(types.Int)
+	for _, id := range res.Report.Descriptors {
+		if err := descriptors.Append(tree.NewDInt(tree.DInt(id))); err != nil {
+			return nil, err
+		}
+	}
+	spans := tree.NewDArray(types.String)
+	for _, sp := range res.Report.Spans {
+		if err := spans.Append(tree.NewDString(sp.String())); err != nil {
+			return nil, err
+		}
+	}
+	details := tree.NewDArray(types.String)
+	for _, d := range res.Report.Details {
+		if err := details.Append(tree.NewDString(d)); err != nil {
+			return nil, err
+		}
+	}
+	row[3] = descriptors
+	row[4] = spans
+	row[5] = tree.NewDInt(tree.DInt(res.Report.EstimatedRanges))
+	row[6] = tree.NewDInt(tree.DInt(res.Report.EstimatedRows))
+	row[7] = details
+	return row, nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *upgradeDryRunGenerator) Close(_ context.Context) {}
@@ -192,7 +192,7 @@ const (
 	CrdbInternalRegionsTable
 	CrdbInternalDefaultPrivilegesTable
 	CrdbInternalActiveRangeFeedsTable
-	CrdbInternalTenantUsageDetailsViewID
+	CrdbInternalTenantUsageDetailsTableID
 	CrdbInternalPgCatalogTableIsImplementedTableID
 	CrdbInternalSuperRegions
 	CrdbInternalDroppedRelationsViewID
@@ -209,6 +209,18 @@ const (
 	CrdbInternalPCRStreamsTableID
 	CrdbInternalPCRStreamSpansTableID
 	CrdbInternalPCRStreamCheckpointsTableID
+	CrdbInternalNodeStmtIteratorStatsTableID
+	CrdbInternalRaftStatusTableID
+	CrdbInternalNodeStmtDiagAutoCaptureTableID
+	CrdbInternalRaftProposalQuotaTableID
+	CrdbInternalNodeIndexReadUsageTableID
+	CrdbInternalKVStoreEncryptionKeysTableID
+	CrdbInternalTableMVCCGarbageTableID
+	CrdbInternalLoadBasedSplitDecisionsTableID
+	CrdbInternalClusterTxnWaitForGraphTableID
+	CrdbInternalNodeRangeCostsTableID
+	CrdbInternalClusterSettingHistoryTableID
+	CrdbInternalClusterRangeCostsTableID
 	InformationSchemaID
 	InformationSchemaAdministrableRoleAuthorizationsID
 	InformationSchemaApplicableRolesID
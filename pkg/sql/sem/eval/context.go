@@ -225,6 +225,10 @@ type Context struct {
 	// CompactEngineSpan is used to force compaction of a span in a store.
 	CompactEngineSpan CompactEngineSpanFunc
 
+	// RewriteInactiveKeyFiles is used to rewrite the files encrypted with
+	// inactive encryption-at-rest data keys.
+	RewriteInactiveKeyFiles RewriteInactiveKeyFilesFunc
+
 	// GetTableMetrics is used in crdb_internal.sstable_metrics.
 	GetTableMetrics GetTableMetricsFunc
 
@@ -235,6 +239,13 @@ type Context struct {
 	// a store.
 	SetCompactionConcurrency SetCompactionConcurrencyFunc
 
+	// ApplyRecoveryPlan is used in crdb_internal.unsafe_apply_recovery_plan. It
+	// is nil for secondary tenants.
+	ApplyRecoveryPlan ApplyRecoveryPlanFunc
+
+	// UpgradeDryRun is used in crdb_internal.upgrade_dry_run.
+	UpgradeDryRun UpgradeDryRunFunc
+
 	// KVStoresIterator is used by various crdb_internal builtins to directly
 	// access stores on this node.
 	KVStoresIterator kvserverbase.StoresIterator
@@ -27,10 +27,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/upgrade/upgradebase"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/rangedesc"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/lib/pq/oid"
 )
 
@@ -489,6 +491,11 @@ type CompactEngineSpanFunc func(
 	ctx context.Context, nodeID, storeID int32, startKey, endKey []byte,
 ) error
 
+// RewriteInactiveKeyFilesFunc is used to start rewriting the files of the
+// stores of the given node, or of all nodes if nodeID is zero, which are
+// encrypted with inactive encryption-at-rest data keys.
+type RewriteInactiveKeyFilesFunc func(ctx context.Context, nodeID int32) error
+
 // GetTableMetrics is used to retrieve sstable metrics on a key span
 // (end-exclusive) at the given (nodeID, storeID).
 type GetTableMetricsFunc func(
@@ -508,6 +515,16 @@ type SetCompactionConcurrencyFunc func(
 	ctx context.Context, nodeID, storeID int32, compactionConcurrency uint64,
 ) error
 
+// ApplyRecoveryPlanFunc is used to apply the staged loss of quorum recovery
+// plan with the given ID on all nodes of the cluster without restarting them.
+// It returns the errors encountered on individual nodes.
+type ApplyRecoveryPlanFunc func(ctx context.Context, planID uuid.UUID) ([]string, error)
+
+// UpgradeDryRunFunc is used to estimate the impact of the upgrades that would
+// run when moving the cluster from its active version to the given version,
+// without running them.
+type UpgradeDryRunFunc func(ctx context.Context, to roachpb.Version) ([]upgradebase.DryRunResult, error)
+
 // SessionAccessor is a limited interface to access session variables.
 type SessionAccessor interface {
 	// SetSessionVar sets a session variable to a new value. If isLocal is true,
@@ -114,6 +114,33 @@ func (n *AlterTenantCapability) Format(ctx *FmtCtx) {
 	}
 }
 
+// AlterTenantBurst represents an ALTER VIRTUAL CLUSTER GRANT/REVOKE BURST
+// statement.
+type AlterTenantBurst struct {
+	TenantSpec *TenantSpec
+	// Rate is the additional refill rate of the burst, in RU/s, and Duration is
+	// the time for which it is granted. They are not set for REVOKE.
+	Rate     Expr
+	Duration Expr
+	IsRevoke bool
+}
+
+var _ Statement = &AlterTenantBurst{}
+
+// Format implements the NodeFormatter interface.
+func (n *AlterTenantBurst) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER VIRTUAL CLUSTER ")
+	ctx.FormatNode(n.TenantSpec)
+	if n.IsRevoke {
+		ctx.WriteString(" REVOKE BURST")
+		return
+	}
+	ctx.WriteString(" GRANT BURST ")
+	ctx.FormatNode(n.Rate)
+	ctx.WriteString(" FOR ")
+	ctx.FormatNode(n.Duration)
+}
+
 // TenantSpec designates a tenant for the ALTER VIRTUAL CLUSTER statements.
 type TenantSpec struct {
 	Expr   Expr
@@ -36,6 +36,13 @@ const (
 	// SystemUsers coverage indicates that only the system.users
 	// table will be restored from the backup.
 	SystemUsers
+
+	// SystemMetadata coverage indicates that only the cluster's metadata, i.e.
+	// its roles, system privileges, cluster settings, zone configurations of
+	// the cluster's ranges and schedules are backed up or restored. These are
+	// created by `BACKUP SYSTEM METADATA` and restored with `RESTORE SYSTEM
+	// METADATA`.
+	SystemMetadata
 )
 
 // BackupOptions describes options for the BACKUP execution.
@@ -123,6 +130,9 @@ func (node Backup) Coverage() DescriptorCoverage {
 	if node.Targets == nil {
 		return AllDescriptors
 	}
+	if node.Targets.SystemMetadata {
+		return SystemMetadata
+	}
 	return RequestedDescriptors
 }
 
@@ -180,7 +190,7 @@ var _ Statement = &Restore{}
 // Format implements the NodeFormatter interface.
 func (node *Restore) Format(ctx *FmtCtx) {
 	ctx.WriteString("RESTORE ")
-	if node.DescriptorCoverage == RequestedDescriptors {
+	if node.DescriptorCoverage == RequestedDescriptors || node.DescriptorCoverage == SystemMetadata {
 		ctx.FormatNode(&node.Targets)
 		ctx.WriteString(" ")
 	}
@@ -712,6 +722,9 @@ type BackupTargetList struct {
 	Schemas   ObjectNamePrefixList
 	Tables    TableAttrs
 	TenantID  TenantID
+	// SystemMetadata is set for `BACKUP SYSTEM METADATA` and `RESTORE SYSTEM
+	// METADATA`.
+	SystemMetadata bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -725,6 +738,8 @@ func (tl *BackupTargetList) Format(ctx *FmtCtx) {
 	} else if tl.TenantID.Specified {
 		ctx.WriteString("VIRTUAL CLUSTER ")
 		ctx.FormatNode(&tl.TenantID)
+	} else if tl.SystemMetadata {
+		ctx.WriteString("SYSTEM METADATA")
 	} else {
 		if tl.Tables.SequenceOnly {
 			ctx.WriteString("SEQUENCE ")
@@ -102,7 +102,7 @@ func (cts *ChangefeedTargets) Format(ctx *FmtCtx) {
 // ChangefeedTargetFromTableExpr returns ChangefeedTarget for the
 // specified table expression.
 func ChangefeedTargetFromTableExpr(e TableExpr) (ChangefeedTarget, error) {
-	switch t := e.(type) {
+	switch t := ChangefeedTargetTableExpr(e).(type) {
 	case TablePattern:
 		return ChangefeedTarget{TableName: t}, nil
 	case *AliasedTableExpr:
@@ -113,3 +113,30 @@ func ChangefeedTargetFromTableExpr(e TableExpr) (ChangefeedTarget, error) {
 	return ChangefeedTarget{}, pgerror.Newf(
 		pgcode.InvalidName, "unsupported changefeed target type")
 }
+
+// ChangefeedTargetTableExpr returns the table expression referencing the target
+// table in the FROM clause of a changefeed expression. When the target table is
+// joined against reference tables, this is the leftmost table of the join.
+func ChangefeedTargetTableExpr(e TableExpr) TableExpr {
+	for {
+		j, ok := e.(*JoinTableExpr)
+		if !ok {
+			return e
+		}
+		e = j.Left
+	}
+}
+
+// ReplaceChangefeedTargetTableExpr returns a copy of the FROM clause of a
+// changefeed expression, with the table expression referencing the target table
+// replaced by the specified expression. The original expression is not
+// modified.
+func ReplaceChangefeedTargetTableExpr(e TableExpr, target TableExpr) TableExpr {
+	j, ok := e.(*JoinTableExpr)
+	if !ok {
+		return target
+	}
+	join := *j
+	join.Left = ReplaceChangefeedTargetTableExpr(j.Left, target)
+	return &join
+}
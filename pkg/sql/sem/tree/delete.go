@@ -29,6 +29,10 @@ type Delete struct {
 	Using     TableExprs
 	Limit     *Limit
 	Returning ReturningClause
+
+	// UseRangeTombstone is set for DELETE ... USING RANGE TOMBSTONE, which
+	// deletes the rows by writing MVCC range tombstones.
+	UseRangeTombstone bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -54,6 +58,9 @@ func (node *Delete) Format(ctx *FmtCtx) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Limit)
 	}
+	if node.UseRangeTombstone {
+		ctx.WriteString(" USING RANGE TOMBSTONE")
+	}
 	if HasReturningClause(node.Returning) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Returning)
@@ -1196,6 +1196,9 @@ func (node *Delete) doc(p *PrettyCfg) pretty.Doc {
 		node.Where.docRow(p),
 		node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
+	if node.UseRangeTombstone {
+		items = append(items, p.row("USING", pretty.Keyword("RANGE TOMBSTONE")))
+	}
 	items = append(items, p.docReturning(node.Returning))
 	return p.rlTable(items...)
 }
@@ -2189,7 +2192,7 @@ func (node *Restore) doc(p *PrettyCfg) pretty.Doc {
 	items := make([]pretty.TableRow, 0, 6)
 
 	items = append(items, p.row("RESTORE", pretty.Nil))
-	if node.DescriptorCoverage == RequestedDescriptors {
+	if node.DescriptorCoverage == RequestedDescriptors || node.DescriptorCoverage == SystemMetadata {
 		items = append(items, node.Targets.docRow(p))
 	}
 	from := make([]pretty.Doc, len(node.From))
@@ -2223,6 +2226,9 @@ func (node *BackupTargetList) docRow(p *PrettyCfg) pretty.TableRow {
 	if node.TenantID.Specified {
 		return p.row("TENANT", p.Doc(&node.TenantID))
 	}
+	if node.SystemMetadata {
+		return p.row("SYSTEM", pretty.Keyword("METADATA"))
+	}
 	if node.Tables.SequenceOnly {
 		return p.row("SEQUENCE", p.Doc(&node.Tables.TablePatterns))
 	}
@@ -95,6 +95,9 @@ func (node ScheduledBackup) Coverage() DescriptorCoverage {
 	if node.Targets == nil {
 		return AllDescriptors
 	}
+	if node.Targets.SystemMetadata {
+		return SystemMetadata
+	}
 	return RequestedDescriptors
 }
 
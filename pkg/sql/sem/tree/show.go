@@ -1029,6 +1029,7 @@ type ShowRangesOptions struct {
 	Details bool
 	Explain bool
 	Keys    bool
+	Costs   bool
 	Mode    ShowRangesMode
 }
 
@@ -1095,6 +1096,11 @@ func (node *ShowRangesOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("EXPLAIN")
 		comma = ", "
 	}
+	if node.Costs {
+		ctx.WriteString(comma)
+		ctx.WriteString("COSTS")
+		comma = ", "
+	}
 	if node.Mode != UniqueRanges {
 		ctx.WriteString(comma)
 		switch node.Mode {
@@ -1220,6 +1226,7 @@ type ShowTenantOptions struct {
 	WithReplication      bool
 	WithPriorReplication bool
 	WithCapabilities     bool
+	WithConsumption      bool
 }
 
 // ShowTenant represents a SHOW VIRTUAL CLUSTER statement.
@@ -1243,6 +1250,9 @@ func (node *ShowTenant) Format(ctx *FmtCtx) {
 	if node.WithCapabilities {
 		withs = append(withs, "CAPABILITIES")
 	}
+	if node.WithConsumption {
+		withs = append(withs, "CONSUMPTION")
+	}
 	if len(withs) > 0 {
 		ctx.WriteString(" WITH ")
 		ctx.WriteString(strings.Join(withs, ", "))
@@ -1201,6 +1201,43 @@ func (n *ShowFingerprints) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (n *AlterTenantBurst) copyNode() *AlterTenantBurst {
+	stmtCopy := *n
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (n *AlterTenantBurst) walkStmt(v Visitor) Statement {
+	ret := n
+	ts, changed := walkTenantSpec(v, n.TenantSpec)
+	if changed {
+		if ret == n {
+			ret = n.copyNode()
+		}
+		ret.TenantSpec = ts
+	}
+	if n.Rate != nil {
+		e, changed := WalkExpr(v, n.Rate)
+		if changed {
+			if ret == n {
+				ret = n.copyNode()
+			}
+			ret.Rate = e
+		}
+	}
+	if n.Duration != nil {
+		e, changed := WalkExpr(v, n.Duration)
+		if changed {
+			if ret == n {
+				ret = n.copyNode()
+			}
+			ret.Duration = e
+		}
+	}
+	return ret
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (n *AlterTenantRename) copyNode() *AlterTenantRename {
 	stmtCopy := *n
@@ -2050,6 +2087,7 @@ func (stmt *BeginTransaction) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+var _ walkableStmt = &AlterTenantBurst{}
 var _ walkableStmt = &AlterTenantCapability{}
 var _ walkableStmt = &AlterTenantRename{}
 var _ walkableStmt = &AlterTenantReplication{}
@@ -274,6 +274,51 @@ func DescriptorValidationModeFromString(val string) (_ DescriptorValidationMode,
 	}
 }
 
+// PlanCacheMode controls whether executions of prepared statements use custom
+// or generic query plans.
+type PlanCacheMode int64
+
+const (
+	// PlanCacheModeForceCustom means that prepared statements are always
+	// optimized for the placeholder values of each execution.
+	PlanCacheModeForceCustom PlanCacheMode = iota
+	// PlanCacheModeForceGeneric means that prepared statements are optimized
+	// without their placeholder values, and the resulting generic plan is
+	// reused by all their executions.
+	PlanCacheModeForceGeneric
+	// PlanCacheModeAuto means that prepared statements use a generic plan if it
+	// is not estimated to be more expensive than the custom plans of their
+	// first executions.
+	PlanCacheModeAuto
+)
+
+func (m PlanCacheMode) String() string {
+	switch m {
+	case PlanCacheModeForceCustom:
+		return "force_custom_plan"
+	case PlanCacheModeForceGeneric:
+		return "force_generic_plan"
+	case PlanCacheModeAuto:
+		return "auto"
+	default:
+		return fmt.Sprintf("invalid (%d)", m)
+	}
+}
+
+// PlanCacheModeFromString converts a string into a PlanCacheMode.
+func PlanCacheModeFromString(val string) (_ PlanCacheMode, ok bool) {
+	switch strings.ToUpper(val) {
+	case "FORCE_CUSTOM_PLAN":
+		return PlanCacheModeForceCustom, true
+	case "FORCE_GENERIC_PLAN":
+		return PlanCacheModeForceGeneric, true
+	case "AUTO":
+		return PlanCacheModeAuto, true
+	default:
+		return 0, false
+	}
+}
+
 // QoSLevel controls the level of admission control to use for new SQL requests.
 type QoSLevel admissionpb.WorkPriority
 
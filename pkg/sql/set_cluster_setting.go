@@ -421,11 +421,11 @@ func writeSettingInternal(
 	interlockInfo unsafeSettingInterlockInfo,
 ) (expectedEncodedValue string, err error) {
 	if err := func() error {
-		var reportedValue string
+		var reportedValue, previousValue string
 		if value == nil {
 			// This code is doing work for RESET CLUSTER SETTING.
 			var err error
-			reportedValue, expectedEncodedValue, err = writeDefaultSettingValue(ctx, db, setting)
+			reportedValue, expectedEncodedValue, previousValue, err = writeDefaultSettingValue(ctx, db, setting)
 			if err != nil {
 				return err
 			}
@@ -435,7 +435,7 @@ func writeSettingInternal(
 			if err != nil {
 				return err
 			}
-			reportedValue, expectedEncodedValue, err = writeNonDefaultSettingValue(
+			reportedValue, expectedEncodedValue, previousValue, err = writeNonDefaultSettingValue(
 				ctx, hook, db, setting, user, st, value, releaseLeases, interlockInfo,
 			)
 			if err != nil {
@@ -451,8 +451,9 @@ func writeSettingInternal(
 		return logFn(ctx,
 			0, /* no target */
 			&eventpb.SetClusterSetting{
-				SettingName: string(name),
-				Value:       reportedValue,
+				SettingName:   string(name),
+				Value:         reportedValue,
+				PreviousValue: previousValue,
 			})
 	}(); err != nil {
 		return "", err
@@ -466,15 +467,44 @@ func writeSettingInternal(
 // to DEFAULT.
 func writeDefaultSettingValue(
 	ctx context.Context, db isql.DB, setting settings.NonMaskedSetting,
-) (reportedValue string, expectedEncodedValue string, err error) {
+) (reportedValue string, expectedEncodedValue string, previousValue string, err error) {
 	reportedValue = "DEFAULT"
 	expectedEncodedValue = setting.EncodedDefault()
-	_, err = db.Executor().ExecEx(
-		ctx, "reset-setting", nil,
+	err = db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		var err error
+		if previousValue, err = previousSettingValue(ctx, txn, setting); err != nil {
+			return err
+		}
+		_, err = txn.ExecEx(
+			ctx, "reset-setting", txn.KV(),
+			sessiondata.NodeUserSessionDataOverride,
+			"DELETE FROM system.settings WHERE name = $1", setting.InternalKey(),
+		)
+		return err
+	})
+	return reportedValue, expectedEncodedValue, previousValue, err
+}
+
+// previousSettingValue returns the value of the setting stored in
+// system.settings before it is changed by txn, as reported in events, or its
+// default value if none is stored. It is read in the same transaction as the
+// change, so that it is the value actually replaced by the change.
+func previousSettingValue(
+	ctx context.Context, txn isql.Txn, setting settings.NonMaskedSetting,
+) (string, error) {
+	row, err := txn.QueryRowEx(
+		ctx, "retrieve-prev-setting", txn.KV(),
 		sessiondata.NodeUserSessionDataOverride,
-		"DELETE FROM system.settings WHERE name = $1", setting.InternalKey(),
+		"SELECT value FROM system.settings WHERE name = $1", setting.InternalKey(),
 	)
-	return reportedValue, expectedEncodedValue, err
+	if err != nil {
+		return "", err
+	}
+	encoded := setting.EncodedDefault()
+	if row != nil {
+		encoded = string(tree.MustBeDString(row[0]))
+	}
+	return setting.DecodeToString(encoded)
 }
 
 // writeDefaultSettingValue performs the data write to change a
@@ -489,7 +519,7 @@ func writeNonDefaultSettingValue(
 	value tree.Datum,
 	releaseLeases func(context.Context),
 	interlockInfo unsafeSettingInterlockInfo,
-) (reportedValue string, expectedEncodedValue string, err error) {
+) (reportedValue string, expectedEncodedValue string, previousValue string, err error) {
 	// Stringify the value set by the statement for reporting in errors, logs etc.
 	reportedValue = tree.AsStringWithFlags(value, tree.FmtBareStrings)
 
@@ -497,39 +527,47 @@ func writeNonDefaultSettingValue(
 	encoded, err := toSettingString(ctx, st, setting, value)
 	expectedEncodedValue = encoded
 	if err != nil {
-		return reportedValue, expectedEncodedValue, err
+		return reportedValue, expectedEncodedValue, "", err
 	}
 
 	verSetting, isSetVersion := setting.(*settings.VersionSetting)
 	if isSetVersion {
-		if err := setVersionSetting(
+		previousValue, err = setVersionSetting(
 			ctx, hook, verSetting, db, user, st, value, encoded, releaseLeases,
-		); err != nil {
-			return reportedValue, expectedEncodedValue, err
+		)
+		if err != nil {
+			return reportedValue, expectedEncodedValue, previousValue, err
 		}
 	} else {
 		// Modifying another setting than the version.
 		if setting.IsUnsafe() {
 			if err := unsafeSettingInterlock(ctx, st, setting, encoded, interlockInfo); err != nil {
-				return reportedValue, expectedEncodedValue, err
+				return reportedValue, expectedEncodedValue, "", err
 			}
 		}
 
-		if _, err = db.Executor().ExecEx(
-			ctx, "update-setting", nil,
-			sessiondata.NodeUserSessionDataOverride,
-			`UPSERT INTO system.settings (name, value, "lastUpdated", "valueType") VALUES ($1, $2, now(), $3)`,
-			setting.InternalKey(), encoded, setting.Typ(),
-		); err != nil {
-			return reportedValue, expectedEncodedValue, err
+		if err := db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+			var err error
+			if previousValue, err = previousSettingValue(ctx, txn, setting); err != nil {
+				return err
+			}
+			_, err = txn.ExecEx(
+				ctx, "update-setting", txn.KV(),
+				sessiondata.NodeUserSessionDataOverride,
+				`UPSERT INTO system.settings (name, value, "lastUpdated", "valueType") VALUES ($1, $2, now(), $3)`,
+				setting.InternalKey(), encoded, setting.Typ(),
+			)
+			return err
+		}); err != nil {
+			return reportedValue, expectedEncodedValue, previousValue, err
 		}
 	}
 
-	return reportedValue, expectedEncodedValue, nil
+	return reportedValue, expectedEncodedValue, previousValue, nil
 }
 
 // setVersionSetting encapsulates the logic for changing the 'version'
-// cluster setting.
+// cluster setting. It returns the previous version, as reported in events.
 func setVersionSetting(
 	ctx context.Context,
 	hook VersionUpgradeHook,
@@ -540,7 +578,7 @@ func setVersionSetting(
 	value tree.Datum,
 	encoded string,
 	releaseLeases func(context.Context),
-) error {
+) (previousValue string, _ error) {
 	// In the special case of the 'version' cluster setting,
 	// we must first read the previous value to validate that the
 	// value change is valid.
@@ -550,7 +588,7 @@ func setVersionSetting(
 		"SELECT value FROM system.settings WHERE name = $1", setting.InternalKey(),
 	)
 	if err != nil {
-		return err
+		return "", err
 	}
 	var prev tree.Datum
 	if len(datums) == 0 {
@@ -558,7 +596,7 @@ func setVersionSetting(
 		// hasn't run yet, we can't update the version as we don't
 		// have good enough information about the current cluster
 		// version.
-		return errors.New("no persisted cluster version found, please retry later")
+		return "", errors.New("no persisted cluster version found, please retry later")
 	} else {
 		prev = datums[0]
 	}
@@ -566,10 +604,13 @@ func setVersionSetting(
 	// Validate that the upgrade to the new version is valid.
 	dStr, ok := prev.(*tree.DString)
 	if !ok {
-		return errors.Errorf("the existing value is not a string, got %T", prev)
+		return "", errors.Errorf("the existing value is not a string, got %T", prev)
 	}
 	if err := setting.Validate(ctx, &st.SV, []byte(string(*dStr)), []byte(encoded)); err != nil {
-		return err
+		return "", err
+	}
+	if previousValue, err = setting.DecodeToString(string(*dStr)); err != nil {
+		return "", err
 	}
 
 	// Updates the version inside the system.settings table.
@@ -664,7 +705,7 @@ func setVersionSetting(
 	// we currently have a lease for. We don't need to hold on to any leases
 	// because the code isn't relying on them.
 	releaseLeases(ctx)
-	return runMigrationsAndUpgradeVersion(
+	return previousValue, runMigrationsAndUpgradeVersion(
 		ctx, hook, user, prev, value, updateVersionSystemSetting,
 	)
 }
@@ -458,6 +458,36 @@ func transactionTimeoutVarSet(ctx context.Context, m sessionDataMutator, s strin
 	return nil
 }
 
+func statementRetryBudgetLatencyVarSet(ctx context.Context, m sessionDataMutator, s string) error {
+	latency, err := validateTimeoutVar(
+		m.data.GetIntervalStyle(),
+		s,
+		"statement_retry_budget_latency",
+	)
+	if err != nil {
+		return err
+	}
+
+	m.SetStatementRetryBudgetLatency(latency)
+	return nil
+}
+
+func materializedViewRewriteMaxStalenessVarSet(
+	ctx context.Context, m sessionDataMutator, s string,
+) error {
+	staleness, err := validateTimeoutVar(
+		m.data.GetIntervalStyle(),
+		s,
+		"optimizer_materialized_view_rewrite_max_staleness",
+	)
+	if err != nil {
+		return err
+	}
+
+	m.SetOptimizerMaterializedViewRewriteMaxStaleness(staleness)
+	return nil
+}
+
 func idleInTransactionSessionTimeoutVarSet(
 	ctx context.Context, m sessionDataMutator, s string,
 ) error {
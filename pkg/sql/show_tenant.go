@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/mtinfopb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
 	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
@@ -37,6 +38,7 @@ type tenantValues struct {
 	replicationInfo    *streampb.StreamIngestionStats
 	protectedTimestamp hlc.Timestamp
 	capabilities       []showTenantNodeCapability
+	consumption        *multitenant.TenantConsumptionInfo
 }
 
 type showTenantNodeCapability struct {
@@ -49,6 +51,7 @@ type showTenantNode struct {
 	withReplication      bool
 	withPriorReplication bool
 	withCapabilities     bool
+	withConsumption      bool
 	columns              colinfo.ResultColumns
 	tenantIDIndex        int
 	tenantIds            []roachpb.TenantID
@@ -78,6 +81,7 @@ func (p *planner) ShowTenant(ctx context.Context, n *tree.ShowTenant) (planNode,
 		withReplication:      n.WithReplication,
 		withPriorReplication: n.WithPriorReplication,
 		withCapabilities:     n.WithCapabilities,
+		withConsumption:      n.WithConsumption,
 		initTenantValues:     true,
 	}
 
@@ -90,6 +94,9 @@ func (p *planner) ShowTenant(ctx context.Context, n *tree.ShowTenant) (planNode,
 	if n.WithPriorReplication {
 		node.columns = append(node.columns, colinfo.TenantColumnsWithPriorReplication...)
 	}
+	if n.WithConsumption {
+		node.columns = append(node.columns, colinfo.TenantColumnsWithConsumption...)
+	}
 	if n.WithCapabilities {
 		node.columns = append(node.columns, colinfo.TenantColumnsWithCapabilities...)
 	}
@@ -139,6 +146,17 @@ func (n *showTenantNode) getTenantValues(
 		values.capabilities = showTenantNodeCapabilities
 	}
 
+	// Add consumption if requested.
+	if n.withConsumption {
+		consumption, err := params.p.ExecCfg().TenantUsageServer.GetTenantConsumption(
+			params.ctx, params.p.InternalSQLTxn(), roachpb.MustMakeTenantID(tenantInfo.ID),
+		)
+		if err != nil {
+			return nil, err
+		}
+		values.consumption = &consumption
+	}
+
 	// Tenant status + replication status fields.
 	jobId := tenantInfo.PhysicalReplicationConsumerJobID
 	if jobId == 0 {
@@ -288,6 +306,10 @@ func (n *showTenantNode) Values() tree.Datums {
 		result = append(result, sourceID, activationTimestamp)
 	}
 
+	if n.withConsumption {
+		result = append(result, consumptionDatums(v.consumption)...)
+	}
+
 	if n.withCapabilities {
 		capability := n.capability
 		result = append(result,
@@ -299,4 +321,52 @@ func (n *showTenantNode) Values() tree.Datums {
 	return result
 }
 
+// consumptionDatums returns the values of the columns in
+// colinfo.TenantColumnsWithConsumption. The token bucket and consumption
+// columns are NULL if the tenant has never requested tokens, and the recent
+// statistics are NULL if this node has not served any token bucket requests
+// for the tenant.
+func consumptionDatums(info *multitenant.TenantConsumptionInfo) tree.Datums {
+	floatOrNull := func(ok bool, f float64) tree.Datum {
+		if !ok {
+			return tree.DNull
+		}
+		return tree.NewDFloat(tree.DFloat(f))
+	}
+	intOrNull := func(ok bool, i int64) tree.Datum {
+		if !ok {
+			return tree.DNull
+		}
+		return tree.NewDInt(tree.DInt(i))
+	}
+	timestampOrNull := func(ok bool, t time.Time) tree.Datum {
+		if !ok || t.IsZero() {
+			return tree.DNull
+		}
+		d, err := tree.MakeDTimestampTZ(t, time.Microsecond)
+		if err != nil {
+			return tree.DNull
+		}
+		return d
+	}
+	c := &info.Consumption
+	return tree.Datums{
+		floatOrNull(info.Present, info.RUAvailable),
+		floatOrNull(info.Present, info.RURefillRate),
+		floatOrNull(info.Present, info.RUBurstLimit),
+		floatOrNull(info.Present, c.RU),
+		floatOrNull(info.Present, c.KVRU),
+		intOrNull(info.Present, int64(c.ReadBytes)),
+		intOrNull(info.Present, int64(c.WriteBytes)),
+		floatOrNull(info.Present, c.SQLPodsCPUSeconds),
+		intOrNull(info.Present, int64(c.PGWireEgressBytes)),
+		intOrNull(info.Present, int64(c.LiveBytes)),
+		intOrNull(info.Present, int64(c.TotalBytes)),
+		timestampOrNull(info.Present, info.LastUpdate),
+		floatOrNull(info.HasRecentStats, info.RURate),
+		intOrNull(info.HasRecentStats, int64(info.RecentThrottleEvents)),
+		timestampOrNull(info.HasRecentStats, info.LastThrottled),
+	}
+}
+
 func (n *showTenantNode) Close(_ context.Context) {}
@@ -209,6 +209,10 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (string, e
 		maybeWriteComma(f)
 		f.Printf("\tgc.ttlseconds = %d", zone.GC.TTLSeconds)
 	}
+	if zone.ProtectedTimestampMaxAgeSeconds != nil {
+		maybeWriteComma(f)
+		f.Printf("\tprotected_timestamp_max_age_seconds = %d", *zone.ProtectedTimestampMaxAgeSeconds)
+	}
 	if zone.GlobalReads != nil {
 		maybeWriteComma(f)
 		f.Printf("\tglobal_reads = %t", *zone.GlobalReads)
@@ -157,6 +157,18 @@ func MakeSplitterWithFamilyIDs(numKeyColumns int, familyIDs []descpb.FamilyID) S
 	}
 }
 
+// WithSkipLocked returns the splitter to use for lookups with the SKIP LOCKED
+// wait policy if skipLocked is true, or else the receiver. KV skips locked keys
+// individually, so a row looked up with one request per column family could be
+// returned with some of its families missing; such lookups are not split, so
+// that KV can skip the row as a whole.
+func (s Splitter) WithSkipLocked(skipLocked bool) Splitter {
+	if skipLocked && len(s.neededFamilies) > 1 {
+		return NoopSplitter()
+	}
+	return s
+}
+
 // FamilyIDs returns the family IDs into which spans will be split, or nil if
 // splitting is not possible.
 func (s *Splitter) FamilyIDs() []descpb.FamilyID {
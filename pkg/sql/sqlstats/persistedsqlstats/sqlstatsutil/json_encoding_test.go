@@ -100,6 +100,18 @@ func TestSQLStatsJsonEncoding(t *testing.T) {
            "mean": {{.Float}},
            "sqDiff": {{.Float}}
          },
+         "requestUnits": {
+           "mean": {{.Float}},
+           "sqDiff": {{.Float}}
+         },
+         "estimatedCPUSeconds": {
+           "mean": {{.Float}},
+           "sqDiff": {{.Float}}
+         },
+         "retries": {
+           "mean": {{.Float}},
+           "sqDiff": {{.Float}}
+         },
          "nodes": [{{joinInts .IntArray}}],
          "regions": [{{joinStrings .StringArray}}],
          "planGists": [{{joinStrings .StringArray}}],
@@ -313,6 +313,9 @@ func (s *innerStmtStats) jsonFields() jsonFields {
 		{"bytesRead", (*numericStats)(&s.BytesRead)},
 		{"rowsRead", (*numericStats)(&s.RowsRead)},
 		{"rowsWritten", (*numericStats)(&s.RowsWritten)},
+		{"requestUnits", (*numericStats)(&s.RequestUnits)},
+		{"estimatedCPUSeconds", (*numericStats)(&s.EstimatedCPUSeconds)},
+		{"retries", (*numericStats)(&s.Retries)},
 		{"nodes", (*int64Array)(&s.Nodes)},
 		{"regions", (*stringArray)(&s.Regions)},
 		{"planGists", (*stringArray)(&s.PlanGists)},
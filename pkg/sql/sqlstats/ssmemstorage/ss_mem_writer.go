@@ -127,6 +127,9 @@ func (s *Container) RecordStatement(
 	stats.mu.data.BytesRead.Record(stats.mu.data.Count, float64(value.BytesRead))
 	stats.mu.data.RowsRead.Record(stats.mu.data.Count, float64(value.RowsRead))
 	stats.mu.data.RowsWritten.Record(stats.mu.data.Count, float64(value.RowsWritten))
+	stats.mu.data.RequestUnits.Record(stats.mu.data.Count, value.RequestUnits)
+	stats.mu.data.EstimatedCPUSeconds.Record(stats.mu.data.Count, value.EstimatedCPUSeconds)
+	stats.mu.data.Retries.Record(stats.mu.data.Count, float64(value.StmtRetryCount))
 	stats.mu.data.LastExecTimestamp = s.getTimeNow()
 	stats.mu.data.Nodes = util.CombineUnique(stats.mu.data.Nodes, value.Nodes)
 	if value.ExecStats != nil {
@@ -166,6 +166,7 @@ type RecordedStmtStats struct {
 	StatementID          clusterunique.ID
 	TransactionID        uuid.UUID
 	AutoRetryCount       int
+	StmtRetryCount       int
 	Failed               bool
 	AutoRetryReason      error
 	RowsAffected         int
@@ -178,6 +179,8 @@ type RecordedStmtStats struct {
 	BytesRead            int64
 	RowsRead             int64
 	RowsWritten          int64
+	RequestUnits         float64
+	EstimatedCPUSeconds  float64
 	Nodes                []int64
 	StatementType        tree.StatementType
 	Plan                 *appstatspb.ExplainTreePlanNode
@@ -504,6 +504,20 @@ func (sc *TableStatisticsCache) InvalidateTableStats(ctx context.Context, tableI
 	sc.mu.cache.Del(tableID)
 }
 
+// CachedTableIDs returns the IDs of up to limit tables whose statistics are
+// currently cached, most recently used first.
+func (sc *TableStatisticsCache) CachedTableIDs(limit int) []descpb.ID {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	var ids []descpb.ID
+	sc.mu.cache.Do(func(e *cache.Entry) {
+		if len(ids) < limit {
+			ids = append(ids, e.Key.(descpb.ID))
+		}
+	})
+	return ids
+}
+
 const (
 	tableIDIndex = iota
 	statisticsIDIndex
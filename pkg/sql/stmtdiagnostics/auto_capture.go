@@ -0,0 +1,280 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stmtdiagnostics
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/logtags"
+)
+
+var autoCaptureLatencyThreshold = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.stmt_diagnostics.auto_capture.latency_threshold",
+	"if set, a diagnostics bundle is automatically requested for statement "+
+		"fingerprints whose service latency exceeds this threshold too often "+
+		"within sql.stmt_diagnostics.auto_capture.window; set to zero to disable",
+	0,
+	settings.NonNegativeDuration,
+	settings.WithPublic,
+)
+
+var autoCaptureRUThreshold = settings.RegisterFloatSetting(
+	settings.ApplicationLevel,
+	"sql.stmt_diagnostics.auto_capture.ru_threshold",
+	"if set, a diagnostics bundle is automatically requested for statement "+
+		"fingerprints whose executions consume more request units than this "+
+		"threshold too often within sql.stmt_diagnostics.auto_capture.window; "+
+		"set to zero to disable",
+	0,
+	settings.NonNegativeFloat,
+	settings.WithPublic,
+)
+
+var autoCaptureViolationCount = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.stmt_diagnostics.auto_capture.violation_count",
+	"number of executions of a statement fingerprint exceeding the latency or "+
+		"request units threshold within sql.stmt_diagnostics.auto_capture.window "+
+		"after which a diagnostics bundle is automatically requested",
+	3,
+	settings.PositiveInt,
+	settings.WithPublic,
+)
+
+var autoCaptureWindow = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.stmt_diagnostics.auto_capture.window",
+	"window over which threshold violations of a statement fingerprint are "+
+		"counted; also the expiration of the automatically created diagnostics "+
+		"requests",
+	5*time.Minute,
+	settings.PositiveDuration,
+	settings.WithPublic,
+)
+
+var autoCaptureMaxRequestsPerHour = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.stmt_diagnostics.auto_capture.max_requests_per_hour",
+	"maximum number of diagnostics requests automatically created by each "+
+		"node per hour",
+	10,
+	settings.NonNegativeInt,
+	settings.WithPublic,
+)
+
+// maxAutoCaptureFingerprints bounds the number of statement fingerprints for
+// which threshold violations are tracked at any given time.
+const maxAutoCaptureFingerprints = 1000
+
+// autoCaptureState tracks the threshold violations of a statement fingerprint.
+type autoCaptureState struct {
+	// windowStart is the start of the current window over which violations
+	// are counted.
+	windowStart time.Time
+	// violations is the number of violations within the current window.
+	violations int
+	// lastViolationAt is the time of the most recent violation.
+	lastViolationAt time.Time
+	// lastRequestedAt is the time at which a diagnostics request was last
+	// automatically created for the fingerprint, and lastRequestID is its ID.
+	// lastRequestID is zero while the request is being inserted.
+	lastRequestedAt time.Time
+	lastRequestID   RequestID
+	// throttled is the number of times a request wasn't created for the
+	// fingerprint due to the rate limit.
+	throttled int
+}
+
+// AutoCaptureFingerprint describes the automatic diagnostics capture state of
+// a statement fingerprint on the local node.
+type AutoCaptureFingerprint struct {
+	Fingerprint     string
+	WindowStart     time.Time
+	Violations      int
+	LastViolationAt time.Time
+	LastRequestedAt time.Time
+	LastRequestID   RequestID
+	Throttled       int
+}
+
+// ObserveExecution records the service latency and the request units consumed
+// by an execution of the given statement fingerprint. Once the executions of a
+// fingerprint exceeded the auto-capture latency or request units threshold
+// sql.stmt_diagnostics.auto_capture.violation_count times within the window, a
+// diagnostics request is created for it, so that a bundle is collected for one
+// of its next executions.
+//
+// If the request is created because of the latency threshold, it is
+// conditional on the execution exceeding the threshold again.
+func (r *Registry) ObserveExecution(
+	ctx context.Context, fingerprint string, latency time.Duration, requestUnits float64,
+) {
+	latencyThreshold := autoCaptureLatencyThreshold.Get(&r.st.SV)
+	ruThreshold := autoCaptureRUThreshold.Get(&r.st.SV)
+	latencyExceeded := latencyThreshold != 0 && latency >= latencyThreshold
+	ruExceeded := ruThreshold != 0 && requestUnits >= ruThreshold
+	if !latencyExceeded && !ruExceeded {
+		return
+	}
+	window := autoCaptureWindow.Get(&r.st.SV)
+	if !r.recordAutoCaptureViolation(fingerprint, timeutil.Now(), window) {
+		return
+	}
+	var minExecutionLatency time.Duration
+	if latencyExceeded {
+		minExecutionLatency = latencyThreshold
+	}
+	r.insertAutoCaptureRequest(ctx, fingerprint, minExecutionLatency, window)
+}
+
+// recordAutoCaptureViolation records a threshold violation of the given
+// fingerprint and returns whether a diagnostics request should be created for
+// it.
+func (r *Registry) recordAutoCaptureViolation(
+	fingerprint string, now time.Time, window time.Duration,
+) bool {
+	r.autoCapture.Lock()
+	defer r.autoCapture.Unlock()
+
+	s, ok := r.autoCapture.fingerprints[fingerprint]
+	if !ok {
+		if r.autoCapture.fingerprints == nil {
+			r.autoCapture.fingerprints = make(map[string]*autoCaptureState)
+		}
+		if len(r.autoCapture.fingerprints) >= maxAutoCaptureFingerprints {
+			r.evictAutoCaptureFingerprintsLocked(now, window)
+			if len(r.autoCapture.fingerprints) >= maxAutoCaptureFingerprints {
+				return false
+			}
+		}
+		s = &autoCaptureState{windowStart: now}
+		r.autoCapture.fingerprints[fingerprint] = s
+	}
+	if now.Sub(s.windowStart) > window {
+		s.windowStart = now
+		s.violations = 0
+	}
+	s.violations++
+	s.lastViolationAt = now
+
+	if int64(s.violations) < autoCaptureViolationCount.Get(&r.st.SV) {
+		return false
+	}
+	if !s.lastRequestedAt.IsZero() && now.Sub(s.lastRequestedAt) < window {
+		// The request created previously hasn't expired yet.
+		return false
+	}
+	// Start counting anew, regardless of whether the request is throttled.
+	s.windowStart = now
+	s.violations = 0
+	if !r.allowAutoCaptureRequestLocked(now) {
+		s.throttled++
+		return false
+	}
+	s.lastRequestedAt = now
+	s.lastRequestID = 0
+	return true
+}
+
+// allowAutoCaptureRequestLocked returns whether creating another request is
+// within the sql.stmt_diagnostics.auto_capture.max_requests_per_hour limit,
+// and if so, accounts for it.
+func (r *Registry) allowAutoCaptureRequestLocked(now time.Time) bool {
+	var i int
+	for i < len(r.autoCapture.requestTimes) && now.Sub(r.autoCapture.requestTimes[i]) >= time.Hour {
+		i++
+	}
+	r.autoCapture.requestTimes = r.autoCapture.requestTimes[i:]
+	if int64(len(r.autoCapture.requestTimes)) >= autoCaptureMaxRequestsPerHour.Get(&r.st.SV) {
+		return false
+	}
+	r.autoCapture.requestTimes = append(r.autoCapture.requestTimes, now)
+	return true
+}
+
+// evictAutoCaptureFingerprintsLocked removes the fingerprints that haven't
+// violated a threshold within the window and don't have a pending request.
+func (r *Registry) evictAutoCaptureFingerprintsLocked(now time.Time, window time.Duration) {
+	for fingerprint, s := range r.autoCapture.fingerprints {
+		if now.Sub(s.lastViolationAt) > window && now.Sub(s.lastRequestedAt) > window {
+			delete(r.autoCapture.fingerprints, fingerprint)
+		}
+	}
+}
+
+// insertAutoCaptureRequest asynchronously creates a diagnostics request for the
+// given fingerprint, expiring after the given duration.
+func (r *Registry) insertAutoCaptureRequest(
+	ctx context.Context, fingerprint string, minExecutionLatency, expiresAfter time.Duration,
+) {
+	r.autoCapture.Lock()
+	stopper := r.autoCapture.stopper
+	r.autoCapture.Unlock()
+	if stopper == nil {
+		// The registry hasn't been started.
+		return
+	}
+	// The request must outlive the statement that triggered it. Like the rest
+	// of the background work of the registry, it is exempt from cost control.
+	ctx = logtags.AddTags(context.Background(), logtags.FromContext(ctx)) // nolint:context
+	ctx = multitenant.WithTenantCostControlExemption(ctx)
+	if err := stopper.RunAsyncTask(ctx, "stmt-diag-auto-capture", func(ctx context.Context) {
+		ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		reqID, err := r.insertRequestInternal(
+			ctx, fingerprint, "" /* planGist */, false /* antiPlanGist */, 0, /* samplingProbability */
+			minExecutionLatency, expiresAfter, false, /* redacted */
+		)
+		if err != nil {
+			log.Warningf(ctx, "failed to automatically request statement diagnostics: %v", err)
+			return
+		}
+		log.Infof(ctx, "automatically requested statement diagnostics (request %d) for %s", reqID, fingerprint)
+		r.autoCapture.Lock()
+		defer r.autoCapture.Unlock()
+		if s, ok := r.autoCapture.fingerprints[fingerprint]; ok {
+			s.lastRequestID = reqID
+		}
+	}); err != nil {
+		log.VEventf(ctx, 1, "not requesting statement diagnostics: %v", err)
+	}
+}
+
+// AutoCaptureFingerprints returns the automatic diagnostics capture state of
+// the statement fingerprints tracked by the local node, ordered by
+// fingerprint.
+func (r *Registry) AutoCaptureFingerprints() []AutoCaptureFingerprint {
+	r.autoCapture.Lock()
+	defer r.autoCapture.Unlock()
+	res := make([]AutoCaptureFingerprint, 0, len(r.autoCapture.fingerprints))
+	for fingerprint, s := range r.autoCapture.fingerprints {
+		res = append(res, AutoCaptureFingerprint{
+			Fingerprint:     fingerprint,
+			WindowStart:     s.windowStart,
+			Violations:      s.violations,
+			LastViolationAt: s.lastViolationAt,
+			LastRequestedAt: s.lastRequestedAt,
+			LastRequestID:   s.lastRequestID,
+			Throttled:       s.throttled,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Fingerprint < res[j].Fingerprint
+	})
+	return res
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stmtdiagnostics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAutoCapture verifies that diagnostics requests are automatically created
+// for statement fingerprints that repeatedly exceed the latency threshold, and
+// that the creation of requests is rate limited.
+func TestAutoCapture(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	ctx := context.Background()
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	sv := &s.ClusterSettings().SV
+	registry := s.ExecutorConfig().(sql.ExecutorConfig).StmtDiagnosticsRecorder
+	runner := sqlutils.MakeSQLRunner(db)
+	runner.Exec(t, "CREATE TABLE test (x int PRIMARY KEY)")
+
+	// Every execution violates the latency threshold, and at most one request
+	// is created.
+	stmtdiagnostics.AutoCaptureViolationCount.Override(ctx, sv, 2)
+	stmtdiagnostics.AutoCaptureMaxRequestsPerHour.Override(ctx, sv, 1)
+	stmtdiagnostics.AutoCaptureLatencyThreshold.Override(ctx, sv, time.Microsecond)
+	runner.Exec(t, "SELECT * FROM test WHERE x = 1")
+	runner.Exec(t, "SELECT * FROM test WHERE x = 2")
+	runner.Exec(t, "SELECT x FROM test")
+	runner.Exec(t, "SELECT x FROM test")
+	stmtdiagnostics.AutoCaptureLatencyThreshold.Override(ctx, sv, 0)
+
+	testutils.SucceedsSoon(t, func() error {
+		var count int
+		runner.QueryRow(t, `
+SELECT count(*) FROM system.statement_diagnostics_requests
+ WHERE statement_fingerprint = 'SELECT * FROM test WHERE x = _'
+   AND min_execution_latency IS NOT NULL
+   AND expires_at IS NOT NULL`).Scan(&count)
+		if count != 1 {
+			return errors.Newf("expected 1 request, found %d", count)
+		}
+		return nil
+	})
+
+	fingerprints := registry.AutoCaptureFingerprints()
+	require.Len(t, fingerprints, 2)
+	require.Equal(t, "SELECT * FROM test WHERE x = _", fingerprints[0].Fingerprint)
+	require.False(t, fingerprints[0].LastRequestedAt.IsZero())
+	require.Zero(t, fingerprints[0].Throttled)
+	// The second fingerprint exceeded the rate limit.
+	require.Equal(t, "SELECT x FROM test", fingerprints[1].Fingerprint)
+	require.True(t, fingerprints[1].LastRequestedAt.IsZero())
+	require.Equal(t, 1, fingerprints[1].Throttled)
+
+	runner.CheckQueryResults(t, `
+SELECT fingerprint, violations, last_requested_at IS NOT NULL, throttled
+  FROM crdb_internal.node_statement_diagnostics_auto_capture`,
+		[][]string{
+			{"SELECT * FROM test WHERE x = _", "0", "true", "0"},
+			{"SELECT x FROM test", "0", "false", "1"},
+		},
+	)
+}
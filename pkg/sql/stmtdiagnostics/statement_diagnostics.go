@@ -101,6 +101,18 @@ type Registry struct {
 
 		rand *rand.Rand
 	}
+	// autoCapture tracks the statement fingerprints exceeding the auto-capture
+	// thresholds. See ObserveExecution.
+	autoCapture struct {
+		syncutil.Mutex
+		fingerprints map[string]*autoCaptureState
+		// requestTimes are the times at which requests were automatically
+		// created within the last hour, used for rate limiting.
+		requestTimes []time.Time
+		// stopper is used to run the tasks which create the requests. It is set
+		// by Start, and is nil until then.
+		stopper *stop.Stopper
+	}
 	st *cluster.Settings
 	db isql.DB
 }
@@ -150,6 +162,9 @@ func NewRegistry(db isql.DB, st *cluster.Settings) *Registry {
 
 // Start will start the polling loop for the Registry.
 func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) {
+	r.autoCapture.Lock()
+	r.autoCapture.stopper = stopper
+	r.autoCapture.Unlock()
 	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
 
 	// Since background statement diagnostics collection is not under user
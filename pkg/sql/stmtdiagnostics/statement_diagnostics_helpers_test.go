@@ -43,3 +43,10 @@ func (r *Registry) InsertRequestInternal(
 
 // PollingInterval is exposed to override in tests.
 var PollingInterval = pollingInterval
+
+// The auto-capture settings are exposed to override in tests.
+var (
+	AutoCaptureLatencyThreshold   = autoCaptureLatencyThreshold
+	AutoCaptureViolationCount     = autoCaptureViolationCount
+	AutoCaptureMaxRequestsPerHour = autoCaptureMaxRequestsPerHour
+)
@@ -17,6 +17,7 @@ import (
 	"math"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
@@ -490,6 +491,30 @@ var tableParams = map[string]tableParam{
 			return nil
 		},
 	},
+	`closed_timestamp_target_duration`: {
+		onSet: func(ctx context.Context, po *Setter, semaCtx *tree.SemaContext,
+			evalCtx *eval.Context, key string, datum tree.Datum) error {
+			// Nodes running older versions ignore the span config field.
+			if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V24_2) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					`"%s" is not supported until the cluster version is finalized`, key)
+			}
+			d, err := paramparse.DatumAsSubsecondDuration(ctx, evalCtx, key, datum)
+			if err != nil {
+				return err
+			}
+			if d <= 0 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					`"%s" must be positive`, key)
+			}
+			po.TableDesc.ClosedTimestampTargetDuration = d
+			return nil
+		},
+		onReset: func(_ context.Context, po *Setter, evalCtx *eval.Context, key string) error {
+			po.TableDesc.ClosedTimestampTargetDuration = 0
+			return nil
+		},
+	},
 	catpb.AutoStatsEnabledTableSettingName: {
 		onSet:   autoStatsEnabledSettingFunc,
 		onReset: autoStatsTableSettingResetFunc,
@@ -172,6 +172,15 @@ func (tb *tableWriterBase) init(
 	batchMaxBytes := int(maxBatchBytes.Default())
 	if evalCtx != nil {
 		batchMaxBytes = int(maxBatchBytes.Get(&evalCtx.Settings.SV))
+		// While the tenant is throttled, the cost client may suggest larger
+		// batches, which amortize the per-batch cost of KV requests.
+		if evalCtx.Planner != nil {
+			if execCfg, ok := evalCtx.Planner.ExecutorConfig().(*ExecutorConfig); ok && execCfg.DistSender != nil {
+				if hint := int(execCfg.DistSender.PreferredBatchBytes()); hint > batchMaxBytes {
+					batchMaxBytes = hint
+				}
+			}
+		}
 	}
 	tb.maxBatchByteSize = mutations.MaxBatchByteSize(batchMaxBytes, tb.forceProductionBatchSizes)
 	tb.initNewBatch()
@@ -0,0 +1,149 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+const alterTenantBurstOp = "ALTER VIRTUAL CLUSTER BURST"
+
+type alterTenantBurstNode struct {
+	n          *tree.AlterTenantBurst
+	tenantSpec tenantSpec
+
+	// rate and duration are the planned expressions for the burst. They are
+	// not set for REVOKE.
+	rate     tree.TypedExpr
+	duration tree.TypedExpr
+}
+
+// AlterTenantBurst grants a temporary increase of the refill rate of the
+// token bucket of a tenant, or revokes it.
+// Privileges: MANAGEVIRTUALCLUSTER.
+func (p *planner) AlterTenantBurst(
+	ctx context.Context, n *tree.AlterTenantBurst,
+) (planNode, error) {
+	if err := rejectIfCantCoordinateMultiTenancy(p.execCfg.Codec, "grant/revoke bursts to", p.execCfg.Settings); err != nil {
+		return nil, err
+	}
+
+	tSpec, err := p.planTenantSpec(ctx, n.TenantSpec, alterTenantBurstOp)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &alterTenantBurstNode{n: n, tenantSpec: tSpec}
+	if n.IsRevoke {
+		return node, nil
+	}
+	var dummyHelper tree.IndexedVarHelper
+	node.rate, err = p.analyzeExpr(
+		ctx, n.Rate, dummyHelper, types.Float, true /* requireType */, alterTenantBurstOp,
+	)
+	if err != nil {
+		return nil, err
+	}
+	node.duration, err = p.analyzeExpr(
+		ctx, n.Duration, dummyHelper, types.Interval, true /* requireType */, alterTenantBurstOp,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (n *alterTenantBurstNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+
+	// Privilege check.
+	if err := CanManageTenant(ctx, p); err != nil {
+		return err
+	}
+
+	// Refuse to work in read-only transactions.
+	if p.EvalContext().TxnReadOnly {
+		return readOnlyError(alterTenantBurstOp)
+	}
+
+	// Nodes running older versions do not preserve the burst when they update
+	// the token bucket of the tenant.
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_2_TenantBurst) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"virtual cluster bursts not supported before V24.2")
+	}
+
+	// Look up the tenant.
+	tenantInfo, err := n.tenantSpec.getTenantInfo(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to modify the system tenant.
+	if err := rejectIfSystemTenant(tenantInfo.ID, alterTenantBurstOp); err != nil {
+		return err
+	}
+
+	var rate float64
+	var dur time.Duration
+	if !n.n.IsRevoke {
+		rate, err = paramparse.DatumAsFloat(ctx, p.EvalContext(), "burst rate", n.rate)
+		if err != nil {
+			return err
+		}
+		if rate <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"burst rate must be positive, got %g", rate)
+		}
+		dur, err = paramparse.DatumAsSubsecondDuration(ctx, p.EvalContext(), "burst duration", n.duration)
+		if err != nil {
+			return err
+		}
+		if dur <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"burst duration must be positive, got %s", dur)
+		}
+	}
+
+	// Revoking the burst is the same as granting an empty one.
+	if err := p.ExecCfg().TenantUsageServer.GrantBurst(
+		ctx, p.InternalSQLTxn(), roachpb.MustMakeTenantID(tenantInfo.ID), rate, dur,
+	); err != nil {
+		return err
+	}
+
+	if n.n.IsRevoke {
+		return p.logEvent(ctx, 0, /* no target */
+			&eventpb.RevokeTenantBurst{TenantID: tenantInfo.ID})
+	}
+	return p.logEvent(ctx, 0, /* no target */
+		&eventpb.GrantTenantBurst{
+			TenantID:   tenantInfo.ID,
+			BurstRate:  rate,
+			Expiration: timeutil.Now().Add(dur).UnixNano(),
+		})
+}
+
+func (n *alterTenantBurstNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterTenantBurstNode) Values() tree.Datums          { return nil }
+func (n *alterTenantBurstNode) Close(context.Context)        {}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities/tenantcapabilitiespb"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/spanconfig/spanconfigbounds"
 	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -67,6 +68,10 @@ func (p *planner) AlterTenantCapability(
 			// translates to true.
 			missingValueDefault = tree.DBoolTrue
 			revokeValue = tree.DBoolFalse
+		case tenantcapabilities.Int64Capability:
+			desiredType = types.Int
+			// Revoking a limit capability translates to removing the limit.
+			revokeValue = tree.NewDInt(0)
 		case tenantcapabilities.SpanConfigBoundsCapability:
 			desiredType = types.Bytes
 		default:
@@ -153,6 +158,14 @@ func (n *alterTenantCapabilityNode) startExec(params runParams) error {
 				}
 				c.Value(dst).Set(val)
 
+			case tenantcapabilities.Int64Capability:
+				// Granting all capabilities removes all limits; revoking them
+				// leaves the limits unchanged. The cost model is not a limit and
+				// is left unchanged either way.
+				if !n.n.IsRevoke && c.ID() != tenantcapabilities.CostModel {
+					c.Value(dst).Set(0)
+				}
+
 			case tenantcapabilities.SpanConfigBoundsCapability:
 				// "REVOKE" on span config bounds has no meaning currently.
 				if !n.n.IsRevoke {
@@ -182,6 +195,23 @@ func (n *alterTenantCapabilityNode) startExec(params runParams) error {
 					return err
 				}
 				c.Value(dst).Set(boolValue)
+			case tenantcapabilities.Int64Capability:
+				intValue, err := paramparse.DatumAsInt(ctx, p.EvalContext(), update.Name, typedExpr)
+				if err != nil {
+					return err
+				}
+				if intValue < 0 {
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						"value for capability %q must be non-negative", capability)
+				}
+				if c.ID() == tenantcapabilities.CostModel &&
+					!tenantcostmodel.ModelVersion(intValue).IsValid() {
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						"invalid value for capability %q: expected %d (%s) or %d (%s)", capability,
+						tenantcostmodel.RequestUnitModel, tenantcostmodel.RequestUnitModel,
+						tenantcostmodel.EstimatedCPUModel, tenantcostmodel.EstimatedCPUModel)
+				}
+				c.Value(dst).Set(intValue)
 			case tenantcapabilities.SpanConfigBoundsCapability:
 				if n.n.IsRevoke {
 					return pgerror.Newf(pgcode.InvalidParameterValue, "cannot REVOKE CAPABILITY %q", capability)
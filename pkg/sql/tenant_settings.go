@@ -113,6 +113,12 @@ func (n *alterTenantSetClusterSettingNode) startExec(params runParams) error {
 		}
 	}
 
+	// Capture the current override of the setting, to report the change.
+	previousValue, err := n.currentOverride(params, tenantID)
+	if err != nil {
+		return err
+	}
+
 	// Write the setting.
 	var reportedValue string
 	if n.value == nil {
@@ -150,13 +156,32 @@ func (n *alterTenantSetClusterSettingNode) startExec(params runParams) error {
 		params.ctx,
 		0, /* no target */
 		&eventpb.SetTenantClusterSetting{
-			SettingName: string(n.name),
-			Value:       reportedValue,
-			TenantId:    tenantID,
-			AllTenants:  tenantID == 0,
+			SettingName:   string(n.name),
+			Value:         reportedValue,
+			TenantId:      tenantID,
+			AllTenants:    tenantID == 0,
+			PreviousValue: previousValue,
 		})
 }
 
+// currentOverride returns the current override of the setting for the given
+// tenant ID, or for all tenants if it is 0, as reported in events. It returns
+// DEFAULT if there is no override.
+func (n *alterTenantSetClusterSettingNode) currentOverride(
+	params runParams, tenantID uint64,
+) (string, error) {
+	row, err := params.p.InternalSQLTxn().QueryRowEx(
+		params.ctx, "get-tenant-setting", params.p.Txn(),
+		sessiondata.NodeUserSessionDataOverride,
+		"SELECT value FROM system.tenant_settings WHERE tenant_id = $1 AND name = $2",
+		tenantID, n.setting.InternalKey(),
+	)
+	if err != nil || row == nil {
+		return "DEFAULT", err
+	}
+	return n.setting.DecodeToString(string(tree.MustBeDString(row[0])))
+}
+
 func (n *alterTenantSetClusterSettingNode) Next(_ runParams) (bool, error) { return false, nil }
 func (n *alterTenantSetClusterSettingNode) Values() tree.Datums            { return nil }
 func (n *alterTenantSetClusterSettingNode) Close(_ context.Context)        {}
@@ -39,6 +39,7 @@ type RowLevelTTLAggMetrics struct {
 	NumActiveSpans    *aggmetric.AggGauge
 	TotalRows         *aggmetric.AggGauge
 	TotalExpiredRows  *aggmetric.AggGauge
+	RequestUnits      *aggmetric.AggCounterFloat64
 
 	defaultRowLevelMetrics rowLevelTTLMetrics
 	mu                     struct {
@@ -58,6 +59,7 @@ type rowLevelTTLMetrics struct {
 	NumActiveSpans    *aggmetric.Gauge
 	TotalRows         *aggmetric.Gauge
 	TotalExpiredRows  *aggmetric.Gauge
+	RequestUnits      *aggmetric.CounterFloat64
 }
 
 // MetricStruct implements the metric.Struct interface.
@@ -73,6 +75,7 @@ func (m *RowLevelTTLAggMetrics) metricsWithChildren(children ...string) rowLevel
 		NumActiveSpans:    m.NumActiveSpans.AddChild(children...),
 		TotalRows:         m.TotalRows.AddChild(children...),
 		TotalExpiredRows:  m.TotalExpiredRows.AddChild(children...),
+		RequestUnits:      m.RequestUnits.AddChild(children...),
 	}
 }
 
@@ -178,6 +181,15 @@ func makeRowLevelTTLAggMetrics(histogramWindowInterval time.Duration) metric.Str
 				Unit:        metric.Unit_COUNT,
 			},
 		),
+		RequestUnits: b.CounterFloat64(
+			metric.Metadata{
+				Name:        "jobs.row_level_ttl.request_units",
+				Help:        "Number of request units consumed by the row level TTL job (only tracked for virtual clusters).",
+				Measurement: "request_units",
+				Unit:        metric.Unit_COUNT,
+				MetricType:  io_prometheus_client.MetricType_COUNTER,
+			},
+		),
 	}
 	ret.defaultRowLevelMetrics = ret.metricsWithChildren("default")
 	ret.mu.m = make(map[string]rowLevelTTLMetrics)
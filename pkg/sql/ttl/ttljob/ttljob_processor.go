@@ -22,6 +22,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
@@ -163,6 +165,10 @@ func (t *ttlProcessor) work(ctx context.Context) error {
 		labelMetrics,
 		relationName,
 	)
+	// Attribute the RUs consumed by this processor to the table.
+	ctx = multitenant.WithRUObserver(ctx, func(ru tenantcostmodel.RU, _ float64) {
+		metrics.RequestUnits.Inc(float64(ru))
+	})
 
 	group := ctxgroup.WithContext(ctx)
 	processorSpanCount := int64(len(ttlSpec.Spans))
@@ -327,6 +333,14 @@ func (t *ttlProcessor) runTTLOnQueryBounds(
 			return spanRowCount, err
 		}
 
+		// Back off while the tenant is running low on RUs, so that TTL deletions
+		// do not cause foreground traffic to be throttled.
+		if costController := serverCfg.TenantCostController; costController != nil {
+			if err := costController.OnBackgroundWorkWait(ctx); err != nil {
+				return spanRowCount, err
+			}
+		}
+
 		// Step 1. Fetch some rows we want to delete using a historical
 		// SELECT query.
 		expiredRowsPKs, hasNext, err := selectBuilder.Run(ctx, ie)
@@ -713,6 +713,66 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension. Configures the maximum number of automatic retries
+	// that a statement can consume, whether performed by retrying the statement
+	// in a READ COMMITTED transaction or by retrying its transaction.
+	`statement_retry_budget_count`: {
+		GetStringVal: makeIntGetStringValFn(`statement_retry_budget_count`),
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			b, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return err
+			}
+			if b < 0 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"cannot set statement_retry_budget_count to a negative value: %d", b)
+			}
+			if b > math.MaxInt32 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"cannot set statement_retry_budget_count to a value greater than %d: %d", math.MaxInt32, b)
+			}
+			m.SetStatementRetryBudgetCount(int32(b))
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return strconv.FormatInt(int64(evalCtx.SessionData().StatementRetryBudgetCount), 10), nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return "0"
+		},
+	},
+
+	// CockroachDB extension. Configures the time since the first attempt of a
+	// statement after which it is no longer automatically retried.
+	`statement_retry_budget_latency`: {
+		GetStringVal: makeTimeoutVarGetter(`statement_retry_budget_latency`),
+		Set:          statementRetryBudgetLatencyVarSet,
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			ms := evalCtx.SessionData().StatementRetryBudgetLatency.Nanoseconds() / int64(time.Millisecond)
+			return strconv.FormatInt(ms, 10), nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return "0s"
+		},
+	},
+
+	// CockroachDB extension.
+	`resource_group`: {
+		// Set is only used during session initialization, to apply the
+		// resource group assigned with ALTER ROLE ... SET. SetWithPlanner is
+		// defined in init(), as otherwise there is a circular initialization
+		// loop with the planner.
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			return setResourceGroup(m, s)
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return evalCtx.SessionData().ResourceGroup, nil
+		},
+		GlobalDefault: func(_ *settings.Values) string {
+			return ""
+		},
+	},
+
 	// CockroachDB extension.
 	`vectorize`: {
 		Set: func(_ context.Context, m sessionDataMutator, s string) error {
@@ -1165,6 +1225,27 @@ var varGen = map[string]sessionVar{
 	// See https://www.postgresql.org/docs/10/static/runtime-config-preset.html#GUC-MAX-INDEX-KEYS
 	`max_index_keys`: makeReadOnlyVar("32"),
 
+	// CockroachDB extension.
+	`max_query_memory`: {
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			limit, err := humanizeutil.ParseBytes(s)
+			if err != nil {
+				return err
+			}
+			if limit < 0 {
+				return errors.New("max_query_memory cannot be set to a negative value")
+			}
+			m.SetMaxQueryMemory(limit)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return string(humanizeutil.IBytes(evalCtx.SessionData().MaxQueryMemory)), nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return string(humanizeutil.IBytes(execinfra.MaxQueryMemory.Get(sv)))
+		},
+	},
+
 	// CockroachDB extension.
 	`node_id`: {
 		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
@@ -3377,6 +3458,55 @@ var varGen = map[string]sessionVar{
 		},
 		GlobalDefault: globalTrue,
 	},
+
+	// CockroachDB extension.
+	`optimizer_use_materialized_view_rewrite`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`optimizer_use_materialized_view_rewrite`),
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar("optimizer_use_materialized_view_rewrite", s)
+			if err != nil {
+				return err
+			}
+			m.SetOptimizerUseMaterializedViewRewrite(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData().OptimizerUseMaterializedViewRewrite), nil
+		},
+		GlobalDefault: globalFalse,
+	},
+
+	// CockroachDB extension.
+	`optimizer_materialized_view_rewrite_max_staleness`: {
+		GetStringVal: makeTimeoutVarGetter(`optimizer_materialized_view_rewrite_max_staleness`),
+		Set:          materializedViewRewriteMaxStalenessVarSet,
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			ms := evalCtx.SessionData().OptimizerMaterializedViewRewriteMaxStaleness.Nanoseconds() / int64(time.Millisecond)
+			return strconv.FormatInt(ms, 10), nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return "5m"
+		},
+	},
+
+	// See https://www.postgresql.org/docs/current/runtime-config-query.html#GUC-PLAN-CACHE-MODE
+	`plan_cache_mode`: {
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			mode, ok := sessiondatapb.PlanCacheModeFromString(s)
+			if !ok {
+				return newVarValueError(`plan_cache_mode`, s,
+					"force_custom_plan", "force_generic_plan", "auto")
+			}
+			m.SetPlanCacheMode(mode)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return evalCtx.SessionData().PlanCacheMode.String(), nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return sessiondatapb.PlanCacheModeForceCustom.String()
+		},
+	},
 }
 
 func ReplicationModeFromString(s string) (sessiondatapb.ReplicationMode, error) {
@@ -3418,6 +3548,17 @@ func init() {
 				return p.setRole(ctx, local, u)
 			},
 		},
+		{
+			name: `resource_group`,
+			fn: func(ctx context.Context, p *planner, local bool, s string) error {
+				if err := p.checkCanSetResourceGroup(ctx, s); err != nil {
+					return err
+				}
+				return p.applyOnSessionDataMutators(ctx, local, func(m sessionDataMutator) error {
+					return setResourceGroup(m, s)
+				})
+			},
+		},
 	} {
 		v := varGen[p.name]
 		v.SetWithPlanner = p.fn
@@ -3648,10 +3789,11 @@ func makeFloatGetStringValFn(name string) getStringValFn {
 
 // IsSessionVariableConfigurable returns true iff there is a session
 // variable with the given name and it is settable by a client
-// (e.g. in pgwire).
+// (e.g. in pgwire). Variables that check privileges in SetWithPlanner
+// cannot be set by the client when connecting.
 func IsSessionVariableConfigurable(varName string) (exists, configurable bool) {
 	v, exists := varGen[varName]
-	return exists, v.Set != nil
+	return exists, v.Set != nil && v.SetWithPlanner == nil
 }
 
 // IsCustomOptionSessionVariable returns whether the given varName is a custom
@@ -215,6 +215,7 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 			n.sourcePlan = v.visit(n.sourcePlan)
 		}
 
+	case *alterTenantBurstNode:
 	case *alterTenantCapabilityNode:
 	case *alterTenantSetClusterSettingNode:
 	case *alterTenantServiceNode:
@@ -362,6 +363,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterTableOwnerNode{}):                     "alter table owner",
 	reflect.TypeOf(&alterTableSetLocalityNode{}):               "alter table set locality",
 	reflect.TypeOf(&alterTableSetSchemaNode{}):                 "alter table set schema",
+	reflect.TypeOf(&alterTenantBurstNode{}):                    "alter tenant burst",
 	reflect.TypeOf(&alterTenantCapabilityNode{}):               "alter tenant capability",
 	reflect.TypeOf(&alterTenantSetClusterSettingNode{}):        "alter tenant set cluster setting",
 	reflect.TypeOf(&alterTenantServiceNode{}):                  "alter tenant service",
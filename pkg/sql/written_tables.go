@@ -0,0 +1,50 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import "github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+
+type writtenTables interface {
+	// addWrittenTable adds a table to the set of tables to which writes were
+	// planned in the current transaction.
+	addWrittenTable(id descpb.ID)
+	// isWrittenTable checks if writes to a table were planned in the current
+	// transaction.
+	isWrittenTable(id descpb.ID) bool
+}
+
+type connExWrittenTablesAccessor struct {
+	ex *connExecutor
+}
+
+func (c connExWrittenTablesAccessor) addWrittenTable(id descpb.ID) {
+	if c.ex.extraTxnState.writtenTables == nil {
+		// Lazily allocate.
+		c.ex.extraTxnState.writtenTables = make(map[descpb.ID]struct{})
+	}
+	c.ex.extraTxnState.writtenTables[id] = struct{}{}
+}
+
+func (c connExWrittenTablesAccessor) isWrittenTable(id descpb.ID) bool {
+	_, ok := c.ex.extraTxnState.writtenTables[id]
+	return ok
+}
+
+// emptyWrittenTables is the default impl used by the planner when the
+// connExecutor is not available. Writes are not tracked, so it must not be
+// relied upon to know that a table wasn't written.
+type emptyWrittenTables struct{}
+
+func (emptyWrittenTables) addWrittenTable(id descpb.ID) {}
+
+func (emptyWrittenTables) isWrittenTable(id descpb.ID) bool {
+	return false
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+)
+
+// InactiveKeyRewriteRate is the rate at which sstables using inactive
+// encryption-at-rest data keys are rewritten by RewriteInactiveKeySSTables.
+var InactiveKeyRewriteRate = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"storage.encryption.inactive_key_rewrite_rate",
+	"the rate, in bytes per second, at which each store rewrites the sstables "+
+		"encrypted with inactive data keys when a rewrite is requested",
+	16<<20, // 16 MiB
+	settings.PositiveInt,
+)
+
+// inactiveKeySSTable is an sstable encrypted with an inactive data key.
+type inactiveKeySSTable struct {
+	fileNum uint64
+	size    uint64
+	span    roachpb.Span
+}
+
+// RewriteInactiveKeySSTables implements the Engine interface.
+//
+// The sstables are rewritten by compacting their spans, which also rewrites
+// the other sstables overlapping them, so the rewrite is paced by the bytes
+// written by compactions rather than by the sizes of the sstables. Pebble's
+// manual compactions don't rewrite the sstables in the bottommost level which
+// don't overlap sstables in the level above, and Pebble doesn't provide a way
+// to rewrite a single sstable or to mark it for compaction, so these are left
+// in place and reported in the returned error.
+// TODO(storage): rewrite the remaining sstables once Pebble allows marking
+// sstables for compaction. Pebble's Download, which rewrites sstables in
+// place, only applies to external sstables.
+func (p *Pebble) RewriteInactiveKeySSTables(
+	ctx context.Context, stopper *stop.Stopper,
+) (int64, error) {
+	if p.cfg.env.Encryption == nil {
+		return 0, nil
+	}
+	var rewritten int64
+	var timer timeutil.Timer
+	defer timer.Stop()
+	// Compacting the span of an sstable also rewrites the other sstables
+	// overlapping it, so the sstables to rewrite are listed again after each
+	// compaction. Each sstable is attempted at most once.
+	attempted := make(map[uint64]struct{})
+	for {
+		tables, err := p.inactiveKeySSTables()
+		if err != nil {
+			return rewritten, err
+		}
+		var t *inactiveKeySSTable
+		for i := range tables {
+			if _, ok := attempted[tables[i].fileNum]; !ok {
+				t = &tables[i]
+				break
+			}
+		}
+		if t == nil {
+			if len(tables) > 0 {
+				var size uint64
+				for i := range tables {
+					size += tables[i].size
+				}
+				return rewritten, errors.Newf(
+					"%d sstables (%s) in the bottommost level still use inactive keys",
+					len(tables), humanizeutil.IBytes(int64(size)))
+			}
+			return rewritten, nil
+		}
+		attempted[t.fileNum] = struct{}{}
+
+		// A manual compaction can't be interrupted, so stop waiting for it when
+		// the context is canceled, and let it complete in the background.
+		before := p.compactionBytesWritten()
+		done := make(chan error, 1)
+		span := t.span
+		if err := stopper.RunAsyncTask(ctx, "storage.inactive-key-rewrite-compaction",
+			func(context.Context) {
+				done <- p.CompactRange(span.Key, span.EndKey)
+			}); err != nil {
+			return rewritten, err
+		}
+		select {
+		case err := <-done:
+			if err != nil {
+				return rewritten, err
+			}
+		case <-ctx.Done():
+			return rewritten, ctx.Err()
+		}
+		// The bytes written include those of the automatic compactions which ran
+		// concurrently, which only makes the pacing more conservative.
+		written := p.compactionBytesWritten() - before
+		rewritten += int64(t.size)
+		log.VEventf(ctx, 2, "rewrote the span %s of sstable %d using an inactive data key, "+
+			"writing %s", t.span, t.fileNum, humanizeutil.IBytes(int64(written)))
+
+		// Pace the rewrites to the configured rate.
+		rate := InactiveKeyRewriteRate.Get(&p.cfg.settings.SV)
+		timer.Reset(time.Duration(float64(written) / float64(rate) * float64(time.Second)))
+		select {
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return rewritten, ctx.Err()
+		}
+	}
+}
+
+// compactionBytesWritten returns the number of bytes written by compactions
+// since the engine was opened.
+func (p *Pebble) compactionBytesWritten() uint64 {
+	var written uint64
+	for _, l := range p.db.Metrics().Levels {
+		written += l.BytesCompacted
+	}
+	return written
+}
+
+// inactiveKeySSTables returns the local sstables of the LSM encrypted with a
+// data key other than the active one. Virtual sstables are returned as a single
+// sstable spanning the keys of all the virtual sstables sharing their backing
+// file.
+func (p *Pebble) inactiveKeySSTables() ([]inactiveKeySSTable, error) {
+	handler := p.cfg.env.Encryption.StatsHandler
+	activeKeyID, err := handler.GetActiveDataKeyID()
+	if err != nil {
+		return nil, err
+	}
+	inactive := make(map[uint64]struct{})
+	for filePath, entry := range p.cfg.env.Registry.GetRegistrySnapshot().Files {
+		keyID, err := handler.GetKeyIDFromSettings(entry.EncryptionSettings)
+		if err != nil {
+			return nil, err
+		}
+		if len(keyID) == 0 {
+			keyID = "plain"
+		}
+		if keyID == activeKeyID {
+			continue
+		}
+		num, ok, err := p.sstFileNumFromPath(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			inactive[num] = struct{}{}
+		}
+	}
+	if len(inactive) == 0 {
+		return nil, nil
+	}
+
+	levels, err := p.db.SSTables()
+	if err != nil {
+		return nil, err
+	}
+	var tables []inactiveKeySSTable
+	backings := make(map[uint64]int)
+	for _, level := range levels {
+		for _, info := range level {
+			if info.BackingType != pebble.BackingTypeLocal {
+				continue
+			}
+			num := uint64(info.BackingSSTNum)
+			if _, ok := inactive[num]; !ok {
+				continue
+			}
+			span, ok := sstSpan(info.Smallest.UserKey, info.Largest.UserKey)
+			if !ok {
+				continue
+			}
+			if i, ok := backings[num]; ok {
+				tables[i].span = tables[i].span.Combine(span)
+				continue
+			}
+			backings[num] = len(tables)
+			tables = append(tables, inactiveKeySSTable{
+				fileNum: num,
+				size:    info.Size,
+				span:    span,
+			})
+		}
+	}
+	return tables, nil
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
@@ -948,6 +949,13 @@ type Engine interface {
 	// GetEnvStats retrieves stats about the engine's environment
 	// For RocksDB, this includes details of at-rest encryption.
 	GetEnvStats() (*fs.EnvStats, error)
+	// RewriteInactiveKeySSTables rewrites the sstables encrypted with an
+	// encryption-at-rest data key other than the active one, at the rate
+	// configured by InactiveKeyRewriteRate, so that they are encrypted with the
+	// active data key. It returns the number of bytes of sstables rewritten,
+	// and an error if some of the sstables could not be rewritten. The
+	// compactions are run as tasks of the given stopper.
+	RewriteInactiveKeySSTables(ctx context.Context, stopper *stop.Stopper) (int64, error)
 	// GetAuxiliaryDir returns a path under which files can be stored
 	// persistently, and from which data can be ingested by the engine.
 	//
@@ -1091,6 +1099,12 @@ type Engine interface {
 	// of the callback since it could cause a deadlock (since the callback may
 	// be invoked while holding mutexes).
 	RegisterFlushCompletedCallback(cb func())
+	// ScrubSSTables validates the block checksums of the cold sstables of the
+	// engine which were not validated within storage.sst_scrubber.interval,
+	// reading them at the rate set by storage.sst_scrubber.rate. Each corrupt
+	// sstable is returned once. The pass stops early if the scrubber is
+	// disabled.
+	ScrubSSTables(ctx context.Context) (SSTScrubStats, error)
 	// CreateCheckpoint creates a checkpoint of the engine in the given directory,
 	// which must not exist. The directory should be on the same file system so
 	// that hard links can be used. If spans is not empty, the checkpoint excludes
@@ -1245,6 +1259,12 @@ type Metrics struct {
 	WriteStallCount    int64
 	WriteStallDuration time.Duration
 	DiskWriteStats     []vfs.DiskWriteStatsAggregate
+	// WALFailoverSecondaryBytes counts the bytes written to WAL files in the
+	// secondary WAL directory, when WAL failover is configured.
+	WALFailoverSecondaryBytes int64
+	// TenantWriteAmp contains the estimated bytes written to the LSM on behalf
+	// of each tenant, ordered by tenant ID. See TenantWriteAmpSampleRate.
+	TenantWriteAmp []TenantWriteAmp
 }
 
 // AggregatedIteratorStats holds cumulative stats, collected and summed over all
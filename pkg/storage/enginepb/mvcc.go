@@ -113,6 +113,41 @@ func (ms MVCCStats) GCByteAge(nowNanos int64) int64 {
 	return ms.GCBytesAge
 }
 
+// AvgGCBytesAge returns the average age, in seconds, of the outstanding gc'able
+// bytes, based on current wall time specified via nowNanos.
+func (ms MVCCStats) AvgGCBytesAge(nowNanos int64) float64 {
+	gcBytes := ms.GCBytes()
+	if gcBytes <= 0 {
+		return 0
+	}
+	return float64(ms.GCByteAge(nowNanos)) / float64(gcBytes)
+}
+
+// EstimatedOldestGCBytesAge estimates the age, in seconds, of the oldest
+// outstanding gc'able bytes, based on current wall time specified via nowNanos.
+// The stats only track the total age of the gc'able bytes, so the estimate
+// assumes that the bytes became gc'able at a steady rate, i.e. that their ages
+// are uniformly distributed, which makes the oldest ones twice as old as the
+// average.
+func (ms MVCCStats) EstimatedOldestGCBytesAge(nowNanos int64) float64 {
+	return 2 * ms.AvgGCBytesAge(nowNanos)
+}
+
+// EstimatedGCBytesOlderThan estimates the number of outstanding gc'able bytes
+// older than ageSeconds, based on current wall time specified via nowNanos. In
+// other words, it estimates the number of bytes a GC with a TTL of ageSeconds
+// would remove. See EstimatedOldestGCBytesAge for the underlying assumption.
+func (ms MVCCStats) EstimatedGCBytesOlderThan(nowNanos int64, ageSeconds float64) int64 {
+	if ageSeconds <= 0 {
+		return ms.GCBytes()
+	}
+	oldest := ms.EstimatedOldestGCBytesAge(nowNanos)
+	if oldest <= ageSeconds {
+		return 0
+	}
+	return int64(float64(ms.GCBytes()) * (1 - ageSeconds/oldest))
+}
+
 // Forward is like AgeTo, but if nowNanos is not ahead of ms.LastUpdateNanos,
 // this method is a noop.
 func (ms *MVCCStats) Forward(nowNanos int64) {
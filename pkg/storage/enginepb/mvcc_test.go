@@ -12,6 +12,7 @@ package enginepb_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -119,3 +120,26 @@ func TestFormatBytesAsKeyAndValue(t *testing.T) {
 	require.Equal(t, string(enginepb.FormatBytesAsValue(encodedIntVal)), "‹/INT/-8›")
 	require.Equal(t, string(enginepb.FormatBytesAsValue(encodedIntVal).Redact()), "‹×›")
 }
+
+func TestMVCCStatsGCBytesEstimates(t *testing.T) {
+	// 200 gc'able bytes, with an average age of 50s.
+	ms := enginepb.MVCCStats{
+		LiveBytes:  200,
+		KeyBytes:   100,
+		ValBytes:   300,
+		GCBytesAge: 200 * 50,
+	}
+	require.Equal(t, 50.0, ms.AvgGCBytesAge(0))
+	require.Equal(t, 100.0, ms.EstimatedOldestGCBytesAge(0))
+	require.Equal(t, int64(200), ms.EstimatedGCBytesOlderThan(0, 0))
+	require.Equal(t, int64(120), ms.EstimatedGCBytesOlderThan(0, 40))
+	require.Equal(t, int64(0), ms.EstimatedGCBytesOlderThan(0, 100))
+
+	// The gc'able bytes age with time.
+	now := int64(50 * time.Second)
+	require.Equal(t, 100.0, ms.AvgGCBytesAge(now))
+	require.Equal(t, int64(100), ms.EstimatedGCBytesOlderThan(now, 100))
+
+	// Without gc'able bytes, there is nothing to estimate.
+	require.Zero(t, enginepb.MVCCStats{LiveBytes: 10, KeyBytes: 5, ValBytes: 5}.AvgGCBytesAge(now))
+}
@@ -113,9 +113,26 @@ type EnvStats struct {
 	ActiveKeyFiles uint64
 	// ActiveKeyBytes is the size of files using the active data key.
 	ActiveKeyBytes uint64
+	// ActiveKeyID is the ID of the active data key, or "plain" if none.
+	ActiveKeyID string
+	// KeyStats contains the number and size of the files using each data key,
+	// including the active one, sorted by key ID. Files which are not encrypted
+	// are reported under the "plain" key ID.
+	KeyStats []EncryptionKeyStats
 	// EncryptionType is an enum describing the active encryption algorithm.
 	// See: ccl/storageccl/engineccl/enginepbccl/key_registry.proto
 	EncryptionType int32
 	// EncryptionStatus is a serialized enginepbccl/stats.proto::EncryptionStatus protobuf.
 	EncryptionStatus []byte
 }
+
+// EncryptionKeyStats contains stats about the files using a data key.
+type EncryptionKeyStats struct {
+	// KeyID is the ID of the data key.
+	KeyID string
+	// Files is the number of files using the data key.
+	Files uint64
+	// Bytes is the size of the sstables using the data key. As for
+	// EnvStats.ActiveKeyBytes, the size of other files is not included.
+	Bytes uint64
+}
@@ -4524,6 +4524,7 @@ func finalizeScanResult(
 	mvccScanner *pebbleMVCCScanner, res *MVCCScanResult, opts MVCCScanOptions,
 ) error {
 	res.NumKeys, res.NumBytes, _ = mvccScanner.results.sizeInfo(0 /* lenKey */, 0 /* lenValue */)
+	res.NumSkippedLocked = mvccScanner.numSkippedLocked
 
 	// If we're tracking the ScanStats, include the stats from this Scan /
 	// ReverseScan.
@@ -4690,7 +4691,8 @@ type MVCCScanOptions struct {
 	// If the last KV pair(s) belong to a partial row, they will be removed from
 	// the result -- except if the result only consists of a single partial row
 	// and AllowEmpty is false, in which case the remaining KV pairs of the row
-	// will be fetched and returned too.
+	// will be fetched and returned too. With SkipLocked, it also makes the scan
+	// skip entire rows of which any KV pair is locked.
 	WholeRowsOfSize int32
 	// MaxLockConflicts is a maximum number of locks (intents) collected by
 	// scanner in consistent mode before returning LockConflictError.
@@ -4761,6 +4763,10 @@ type MVCCScanResult struct {
 	ResumeReason    kvpb.ResumeReason
 	ResumeNextBytes int64 // populated if TargetBytes != 0, size of next resume kv
 	Intents         []roachpb.Intent
+	// NumSkippedLocked is the number of keys skipped over because they were
+	// locked, when scanning with the SkipLocked option. With WholeRowsOfSize,
+	// it is the number of SQL rows skipped instead.
+	NumSkippedLocked int64
 }
 
 // MVCCScan scans the key range [key, endKey) in the provided reader up to some
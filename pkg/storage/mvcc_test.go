@@ -1188,6 +1188,100 @@ func TestMVCCScanMaxNum(t *testing.T) {
 	}
 }
 
+// skipLockedTestLockTable is a LockTableView in which the given keys are
+// locked by a conflicting transaction.
+type skipLockedTestLockTable struct {
+	locked map[string]bool
+	holder enginepb.TxnMeta
+}
+
+func (lt *skipLockedTestLockTable) IsKeyLockedByConflictingTxn(
+	_ context.Context, key roachpb.Key,
+) (bool, *enginepb.TxnMeta, error) {
+	if lt.locked[string(key)] {
+		return true, &lt.holder, nil
+	}
+	return false, nil, nil
+}
+
+func (lt *skipLockedTestLockTable) Close() {}
+
+// TestMVCCScanSkipLockedWholeRows verifies that scans with the SkipLocked and
+// WholeRowsOfSize options skip entire SQL rows of which any column family is
+// locked.
+func TestMVCCScanSkipLockedWholeRows(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := NewDefaultInMemForTesting()
+	defer engine.Close()
+
+	userID := TestingUserDescID(0)
+	famKey := func(row string, fam uint32) roachpb.Key {
+		return testAddColFam(testTablePrefix(userID, row), fam)
+	}
+	for _, k := range []roachpb.Key{
+		famKey("a", 0), famKey("a", 1), famKey("a", 2),
+		famKey("b", 0), famKey("b", 2),
+		famKey("c", 0),
+	} {
+		_, err := MVCCPut(ctx, engine, k, hlc.Timestamp{WallTime: 1}, value1, MVCCWriteOptions{})
+		require.NoError(t, err)
+	}
+	lockTable := &skipLockedTestLockTable{
+		locked: map[string]bool{
+			string(famKey("a", 1)): true,
+			string(famKey("b", 2)): true,
+		},
+		holder: enginepb.TxnMeta{ID: uuid.MakeV4(), WriteTimestamp: hlc.Timestamp{WallTime: 1}},
+	}
+
+	start, end := testTablePrefix(userID), testTablePrefix(userID).PrefixEnd()
+	for _, tc := range []struct {
+		wholeRows       bool
+		reverse         bool
+		expKeys         []roachpb.Key
+		expSkippedCount int64
+	}{
+		{
+			wholeRows:       false,
+			expKeys:         []roachpb.Key{famKey("a", 0), famKey("a", 2), famKey("b", 0), famKey("c", 0)},
+			expSkippedCount: 2,
+		},
+		{
+			wholeRows:       true,
+			expKeys:         []roachpb.Key{famKey("c", 0)},
+			expSkippedCount: 2,
+		},
+		{
+			wholeRows:       true,
+			reverse:         true,
+			expKeys:         []roachpb.Key{famKey("c", 0)},
+			expSkippedCount: 2,
+		},
+	} {
+		t.Run(fmt.Sprintf("wholeRows=%t/reverse=%t", tc.wholeRows, tc.reverse), func(t *testing.T) {
+			opts := MVCCScanOptions{
+				SkipLocked: true,
+				LockTable:  lockTable,
+				Reverse:    tc.reverse,
+			}
+			if tc.wholeRows {
+				opts.WholeRowsOfSize = 3
+			}
+			res, err := MVCCScan(ctx, engine, start, end, hlc.Timestamp{WallTime: 2}, opts)
+			require.NoError(t, err)
+			var scanned []roachpb.Key
+			for _, kv := range res.KVs {
+				scanned = append(scanned, kv.Key)
+			}
+			require.Equal(t, tc.expKeys, scanned)
+			require.Equal(t, tc.expSkippedCount, res.NumSkippedLocked)
+		})
+	}
+}
+
 func TestMVCCScanWithKeyPrefix(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
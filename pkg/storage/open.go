@@ -14,6 +14,7 @@ import (
 	"cmp"
 	"context"
 	"slices"
+	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -306,7 +307,7 @@ func WALFailover(
 				if err != nil {
 					return err
 				}
-				cfg.opts.WALFailover = makePebbleWALFailoverOptsForDir(cfg.settings, walDir)
+				cfg.opts.WALFailover = makePebbleWALFailoverOptsForDir(cfg, walDir)
 				if walCfg.PrevPath.IsSet() {
 					walDir, err := makeExternalWALDir(cfg, walCfg.PrevPath, defaultFS, statsCollector)
 					if err != nil {
@@ -411,7 +412,7 @@ func WALFailover(
 			Dirname: secondaryEnv.PathJoin(secondaryEnv.Dir, base.AuxiliaryDir, "wals-among-stores"),
 		}
 		if walCfg.Mode == base.WALFailoverAmongStores {
-			cfg.opts.WALFailover = makePebbleWALFailoverOptsForDir(cfg.settings, secondary)
+			cfg.opts.WALFailover = makePebbleWALFailoverOptsForDir(cfg, secondary)
 			return nil
 		}
 		// mode == WALFailoverDisabled
@@ -420,10 +421,11 @@ func WALFailover(
 	}
 }
 
-func makePebbleWALFailoverOptsForDir(
-	settings *cluster.Settings, dir wal.Dir,
-) *pebble.WALFailoverOptions {
+func makePebbleWALFailoverOptsForDir(cfg *engineConfig, dir wal.Dir) *pebble.WALFailoverOptions {
+	settings := cfg.settings
 	cclWALFailoverLogEvery := log.Every(10 * time.Minute)
+	cfg.walFailoverSecondaryBytes = new(atomic.Int64)
+	dir.FS = &walSecondaryFS{FS: dir.FS, bytesWritten: cfg.walFailoverSecondaryBytes}
 	return &pebble.WALFailoverOptions{
 		Secondary: dir,
 		FailoverOptions: wal.FailoverOptions{
@@ -444,7 +446,14 @@ func makePebbleWALFailoverOptsForDir(
 				if !licenseOK && cclWALFailoverLogEvery.ShouldLog() {
 					log.Warningf(context.Background(), "Ignoring WAL failover configuration because it requires an enterprise license.")
 				}
-				return walFailoverUnhealthyOpThreshold.Get(&settings.SV), versionOK && licenseOK
+				// The per-store threshold is read here rather than when the options
+				// are built, since it may be configured by an option applied after
+				// the WAL failover one.
+				threshold := walFailoverUnhealthyOpThreshold.Get(&settings.SV)
+				if cfg.walFailoverUnhealthyOpThreshold > 0 {
+					threshold = cfg.walFailoverUnhealthyOpThreshold
+				}
+				return threshold, versionOK && licenseOK
 			},
 		},
 	}
@@ -889,6 +889,16 @@ type engineConfig struct {
 	// diskMonitor is used to output a disk trace when a stall is detected.
 	diskMonitor *disk.Monitor
 
+	// walFailoverUnhealthyOpThreshold, if non-zero, overrides the
+	// storage.wal_failover.unhealthy_op_threshold cluster setting for the
+	// engine. See WALFailoverThreshold.
+	walFailoverUnhealthyOpThreshold time.Duration
+	// walFailoverSecondaryBytes counts the bytes written to WAL files in the
+	// secondary WAL directory. It is nil if WAL failover is not configured.
+	walFailoverSecondaryBytes *atomic.Int64
+	// walFailoverKnobs contains testing hooks for WAL failover.
+	walFailoverKnobs WALFailoverTestingKnobs
+
 	// DiskWriteStatsCollector is used to categorically track disk write metrics
 	// across all Pebble stores on this node.
 	DiskWriteStatsCollector *vfs.DiskWriteStatsCollector
@@ -934,6 +944,11 @@ type Pebble struct {
 		AggregatedBatchCommitStats
 	}
 	diskWriteStatsCollector *vfs.DiskWriteStatsCollector
+	// tenantWriteAmp attributes a sample of the sstables written by flushes,
+	// ingestions and compactions to tenants, and is returned in GetMetrics.
+	tenantWriteAmp tenantWriteAmpTracker
+	// scrubber is the state of the sstable scrubber, see ScrubSSTables.
+	scrubber sstScrubber
 	// Relevant options copied over from pebble.Options.
 	logCtx        context.Context
 	logger        pebble.LoggerAndTracer
@@ -1078,6 +1093,9 @@ func newPebble(ctx context.Context, cfg engineConfig) (p *Pebble, err error) {
 		)
 	}
 	cfg.opts.FS = cfg.env
+	if cfg.walFailoverKnobs.BeforePrimaryWALWrite != nil {
+		cfg.opts.FS = &walPrimaryFS{FS: cfg.env, knobs: cfg.walFailoverKnobs}
+	}
 	cfg.opts.Lock = cfg.env.DirectoryLock
 	cfg.opts.ErrorIfNotExists = cfg.mustExist
 	for i := range cfg.opts.Levels {
@@ -1176,6 +1194,7 @@ func newPebble(ctx context.Context, cfg engineConfig) (p *Pebble, err error) {
 		singleDelLogEvery:       log.Every(5 * time.Minute),
 		diskWriteStatsCollector: cfg.DiskWriteStatsCollector,
 	}
+	p.tenantWriteAmp.init(&cfg.settings.SV)
 
 	cfg.opts.Experimental.SingleDeleteInvariantViolationCallback = func(userKey []byte) {
 		logFunc := func(ctx context.Context, format string, args ...interface{}) {}
@@ -1467,6 +1486,11 @@ func (p *Pebble) makeMetricEtcEventListener(ctx context.Context) pebble.EventLis
 			if info.Err != nil {
 				return
 			}
+			// The sstables of flushable ingestions are accounted for by the
+			// TableIngested event.
+			if !info.Ingest {
+				p.tenantWriteAmp.maybeRecord(tenantWriteFlush, info.Output)
+			}
 			p.mu.Lock()
 			cb := p.mu.flushCompletedCallback
 			p.mu.Unlock()
@@ -1474,6 +1498,22 @@ func (p *Pebble) makeMetricEtcEventListener(ctx context.Context) pebble.EventLis
 				cb()
 			}
 		},
+		CompactionEnd: func(info pebble.CompactionInfo) {
+			if info.Err != nil {
+				return
+			}
+			p.tenantWriteAmp.maybeRecord(tenantWriteCompaction, info.Output.Tables)
+		},
+		TableIngested: func(info pebble.TableIngestInfo) {
+			if info.Err != nil {
+				return
+			}
+			tables := make([]pebble.TableInfo, len(info.Tables))
+			for i := range info.Tables {
+				tables[i] = info.Tables[i].TableInfo
+			}
+			p.tenantWriteAmp.maybeRecord(tenantWriteIngest, tables)
+		},
 	}
 }
 
@@ -2035,6 +2075,10 @@ func (p *Pebble) GetMetrics() Metrics {
 	m.BatchCommitStats = p.batchCommitStats.AggregatedBatchCommitStats
 	p.batchCommitStats.Unlock()
 	m.DiskWriteStats = p.diskWriteStatsCollector.GetStats()
+	m.TenantWriteAmp = p.tenantWriteAmp.snapshot()
+	if p.cfg.walFailoverSecondaryBytes != nil {
+		m.WALFailoverSecondaryBytes = p.cfg.walFailoverSecondaryBytes.Load()
+	}
 	return m
 }
 
@@ -2097,6 +2141,8 @@ func (p *Pebble) GetEnvStats() (*fs.EnvStats, error) {
 		}
 	}
 
+	stats.ActiveKeyID = activeKeyID
+	keyStats := make(map[string]*fs.EncryptionKeyStats)
 	for filePath, entry := range fr.Files {
 		keyID, err := p.cfg.env.Encryption.StatsHandler.GetKeyIDFromSettings(entry.EncryptionSettings)
 		if err != nil {
@@ -2105,21 +2151,30 @@ func (p *Pebble) GetEnvStats() (*fs.EnvStats, error) {
 		if len(keyID) == 0 {
 			keyID = "plain"
 		}
-		if keyID != activeKeyID {
-			continue
+		ks, ok := keyStats[keyID]
+		if !ok {
+			ks = &fs.EncryptionKeyStats{KeyID: keyID}
+			keyStats[keyID] = ks
 		}
-		stats.ActiveKeyFiles++
+		ks.Files++
 
-		filename := p.cfg.env.PathBase(filePath)
-		numStr := strings.TrimSuffix(filename, ".sst")
-		if len(numStr) == len(filename) {
-			continue // not a sstable
-		}
-		u, err := strconv.ParseUint(numStr, 10, 64)
+		num, ok, err := p.sstFileNumFromPath(filePath)
 		if err != nil {
-			return nil, errors.Wrapf(err, "parsing filename %q", errors.Safe(filename))
+			return nil, err
 		}
-		stats.ActiveKeyBytes += sstSizes[pebble.FileNum(u)]
+		if ok {
+			ks.Bytes += sstSizes[pebble.FileNum(num)]
+		}
+	}
+	for _, ks := range keyStats {
+		stats.KeyStats = append(stats.KeyStats, *ks)
+	}
+	sort.Slice(stats.KeyStats, func(i, j int) bool {
+		return stats.KeyStats[i].KeyID < stats.KeyStats[j].KeyID
+	})
+	if ks, ok := keyStats[activeKeyID]; ok {
+		stats.ActiveKeyFiles = ks.Files
+		stats.ActiveKeyBytes = ks.Bytes
 	}
 
 	// Ensure that encryption percentage does not exceed 100%.
@@ -2135,6 +2190,21 @@ func (p *Pebble) GetEnvStats() (*fs.EnvStats, error) {
 	return stats, nil
 }
 
+// sstFileNumFromPath returns the file number of the sstable at the given path
+// of the file registry, or false if the file is not an sstable.
+func (p *Pebble) sstFileNumFromPath(filePath string) (uint64, bool, error) {
+	filename := p.cfg.env.PathBase(filePath)
+	numStr := strings.TrimSuffix(filename, ".sst")
+	if len(numStr) == len(filename) {
+		return 0, false, nil // not a sstable
+	}
+	u, err := strconv.ParseUint(numStr, 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "parsing filename %q", errors.Safe(filename))
+	}
+	return u, true, nil
+}
+
 // GetAuxiliaryDir implements the Engine interface.
 func (p *Pebble) GetAuxiliaryDir() string {
 	return p.auxDir
@@ -458,6 +458,15 @@ type pebbleMVCCScanner struct {
 	// mostRecentTS) that was more recent than the scan.
 	mostRecentTS  hlc.Timestamp
 	mostRecentKey roachpb.Key
+	// skippedRowPrefix is the prefix of the last SQL row skipped because one of
+	// its keys was locked. Only used with the skipLocked and wholeRows options,
+	// in which case the other keys of the row are skipped as well, so that rows
+	// are either returned whole or not at all.
+	skippedRowPrefix []byte
+	// numSkippedLocked is the number of keys (or of SQL rows, with the wholeRows
+	// option) skipped because they were locked. Only used with the skipLocked
+	// option.
+	numSkippedLocked int64
 	// Stores any error returned. If non-nil, iteration short circuits.
 	err error
 	// Number of iterations to try before we do a Seek/SeekReverse. Stays within
@@ -836,13 +845,16 @@ func (p *pebbleMVCCScanner) getOne(ctx context.Context) (ok, added bool) {
 				// throw a write too old error on equal or more recent versions.
 
 				if p.skipLocked {
+					if p.inSkippedLockedRow(p.curUnsafeKey.Key) {
+						return true /* ok */, false
+					}
 					if locked, ok := p.isKeyLockedByConflictingTxn(ctx, p.curRawKey); !ok {
 						return false, false
 					} else if locked {
 						// 2a. the scanner was configured to skip locked keys, and
 						// this key was locked, so we can advance past it without
 						// raising the write too old error.
-						return true /* ok */, false
+						return p.skipLockedKey(p.curUnsafeKey.Key), false
 					}
 				}
 
@@ -869,13 +881,16 @@ func (p *pebbleMVCCScanner) getOne(ctx context.Context) (ok, added bool) {
 			// throw a write too old error on equal or more recent versions.
 
 			if p.skipLocked {
+				if p.inSkippedLockedRow(p.curUnsafeKey.Key) {
+					return true /* ok */, false
+				}
 				if locked, ok := p.isKeyLockedByConflictingTxn(ctx, p.curRawKey); !ok {
 					return false, false
 				} else if locked {
 					// 4a. the scanner was configured to skip locked keys, and
 					// this key was locked, so we can advance past it without
 					// raising the write too old error.
-					return true /* ok */, false
+					return p.skipLockedKey(p.curUnsafeKey.Key), false
 				}
 			}
 
@@ -987,7 +1002,10 @@ func (p *pebbleMVCCScanner) getOne(ctx context.Context) (ok, added bool) {
 					return false, false
 				}
 			}
-			return true /* ok */, false
+			if p.inSkippedLockedRow(p.curUnsafeKey.Key) {
+				return true /* ok */, false
+			}
+			return p.skipLockedKey(p.curUnsafeKey.Key), false
 		}
 
 		// 11. The key contains an intent which was not written by our
@@ -1234,10 +1252,13 @@ func (p *pebbleMVCCScanner) add(
 	// locks will be represented as intents, which will be skipped over in
 	// getAndAdvance.
 	if p.skipLocked {
+		if p.inSkippedLockedRow(key) {
+			return true /* ok */, false
+		}
 		if locked, ok := p.isKeyLockedByConflictingTxn(ctx, rawKey); !ok {
 			return false, false
 		} else if locked {
-			return true /* ok */, false
+			return p.skipLockedKey(key), false
 		}
 	}
 
@@ -1824,6 +1845,39 @@ func (p *pebbleMVCCScanner) isKeyLockedByConflictingTxn(
 	return false, true
 }
 
+// skipLockedKey is called when the scanner skips over the given key because it
+// is locked by a conflicting txn. With the wholeRows option, the entire SQL row
+// of the key is skipped: the keys of the row which were already added to the
+// result are removed, and the remaining ones are skipped over (see
+// inSkippedLockedRow). Otherwise, a row spanning multiple column families could
+// be returned with some of them missing. Returns false if an error was
+// encountered, in which case p.err is set.
+//
+// NB: this relies on the pebbleResults implementation of maybeTrimPartialLastRow,
+// which is the only one used with the skipLocked option, as direct columnar
+// scans don't support SKIP LOCKED.
+func (p *pebbleMVCCScanner) skipLockedKey(key roachpb.Key) bool {
+	var rowPrefix []byte
+	if p.wholeRows {
+		rowPrefix = getRowPrefix(key)
+	}
+	if rowPrefix != nil {
+		if _, err := p.results.maybeTrimPartialLastRow(key); err != nil {
+			p.err = err
+			return false
+		}
+		p.skippedRowPrefix = append(p.skippedRowPrefix[:0], rowPrefix...)
+	}
+	p.numSkippedLocked++
+	return true
+}
+
+// inSkippedLockedRow returns whether the given key belongs to the last SQL row
+// skipped by skipLockedKey.
+func (p *pebbleMVCCScanner) inSkippedLockedRow(key roachpb.Key) bool {
+	return len(p.skippedRowPrefix) > 0 && bytes.Equal(getRowPrefix(key), p.skippedRowPrefix)
+}
+
 // addCurIntent adds the key-value pair that the scanner is currently
 // pointing to as an intent to the intents set.
 func (p *pebbleMVCCScanner) addCurIntent(ctx context.Context) bool {
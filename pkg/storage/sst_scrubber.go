@@ -0,0 +1,268 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/sstable"
+)
+
+// SSTScrubberEnabled controls whether the block checksums of cold sstables are
+// periodically validated in the background.
+var SSTScrubberEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"storage.sst_scrubber.enabled",
+	"if enabled, the block checksums of the cold sstables of each store are "+
+		"periodically validated in the background, and the replicas overlapping "+
+		"the sstables found to be corrupt are marked as corrupt, terminating the node",
+	false,
+)
+
+// SSTScrubberRate is the rate at which the sstable scrubber reads sstables.
+var SSTScrubberRate = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"storage.sst_scrubber.rate",
+	"the rate, in bytes per second, at which each store reads sstables to "+
+		"validate their block checksums",
+	8<<20, // 8 MiB
+	settings.PositiveInt,
+)
+
+// SSTScrubberMinAge is the minimum time for which an sstable must have been
+// part of the LSM for it to be considered cold. Hot sstables are skipped since
+// they are likely to be rewritten by compactions, which validate the checksums
+// of the blocks they read, shortly.
+var SSTScrubberMinAge = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"storage.sst_scrubber.min_age",
+	"the minimum time for which an sstable must have been part of the LSM for "+
+		"its block checksums to be validated by the sstable scrubber",
+	time.Hour,
+	settings.NonNegativeDuration,
+)
+
+// SSTScrubberInterval is the minimum interval between two validations of the
+// block checksums of an sstable.
+var SSTScrubberInterval = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"storage.sst_scrubber.interval",
+	"the minimum interval between two validations of the block checksums of an "+
+		"sstable by the sstable scrubber",
+	24*time.Hour,
+	settings.PositiveDuration,
+)
+
+// CorruptSSTable describes an sstable whose block checksums failed to
+// validate.
+type CorruptSSTable struct {
+	// FileNum is the number of the file backing the sstable.
+	FileNum uint64
+	// Level is the level of the LSM containing the sstable.
+	Level int
+	// Size is the size of the file backing the sstable.
+	Size int64
+	// Span is the span of the keys contained in the sstable. If the file backs
+	// multiple virtual sstables, it covers the keys of all of them.
+	Span roachpb.Span
+	// Err is the error returned by the validation of the block checksums.
+	Err error
+}
+
+// SSTScrubStats contains the outcome of a pass of the sstable scrubber.
+type SSTScrubStats struct {
+	// ScrubbedTables is the number of sstables whose block checksums were
+	// validated.
+	ScrubbedTables int
+	// ScrubbedBytes is the number of bytes read to validate the block checksums.
+	ScrubbedBytes int64
+	// Corrupt contains the sstables found to be corrupt.
+	Corrupt []CorruptSSTable
+}
+
+// sstScrubber tracks the state of the sstable scrubber of an engine. The mutex
+// is held for the entirety of a pass, which serializes passes.
+type sstScrubber struct {
+	syncutil.Mutex
+	// firstSeen is the time at which the scrubber first observed each file
+	// backing sstables, keyed by file number.
+	firstSeen map[uint64]time.Time
+	// scrubbed is the time at which the block checksums of each file were last
+	// validated.
+	scrubbed map[uint64]time.Time
+	// corrupt contains the files which were found to be corrupt. They are
+	// reported only once.
+	corrupt map[uint64]struct{}
+}
+
+// ScrubSSTables implements the Engine interface.
+func (p *Pebble) ScrubSSTables(ctx context.Context) (SSTScrubStats, error) {
+	s := &p.scrubber
+	s.Lock()
+	defer s.Unlock()
+	if s.firstSeen == nil {
+		s.firstSeen = make(map[uint64]time.Time)
+		s.scrubbed = make(map[uint64]time.Time)
+		s.corrupt = make(map[uint64]struct{})
+	}
+
+	levels, err := p.db.SSTables()
+	if err != nil {
+		return SSTScrubStats{}, err
+	}
+	objects := make(map[uint64]objstorage.ObjectMetadata)
+	for _, meta := range p.db.ObjProvider().List() {
+		objects[uint64(meta.DiskFileNum)] = meta
+	}
+
+	// Group the sstables by backing file, since virtual sstables share the
+	// file of the sstable they were carved out of. Files on remote storage are
+	// not scrubbed.
+	var files []uint64
+	backings := make(map[uint64]*CorruptSSTable)
+	for level, tables := range levels {
+		for i := range tables {
+			t := &tables[i]
+			if t.BackingType != pebble.BackingTypeLocal {
+				continue
+			}
+			span, ok := sstSpan(t.Smallest.UserKey, t.Largest.UserKey)
+			if !ok {
+				continue
+			}
+			num := uint64(t.BackingSSTNum)
+			if b, ok := backings[num]; ok {
+				b.Span = b.Span.Combine(span)
+				continue
+			}
+			backings[num] = &CorruptSSTable{FileNum: num, Level: level, Span: span}
+			files = append(files, num)
+		}
+	}
+	for num := range s.firstSeen {
+		if _, ok := backings[num]; !ok {
+			delete(s.firstSeen, num)
+			delete(s.scrubbed, num)
+			delete(s.corrupt, num)
+		}
+	}
+
+	sv := &p.cfg.settings.SV
+	now := timeutil.Now()
+	var stats SSTScrubStats
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for _, num := range files {
+		firstSeen, ok := s.firstSeen[num]
+		if !ok {
+			firstSeen = now
+			s.firstSeen[num] = now
+		}
+		if now.Sub(firstSeen) < SSTScrubberMinAge.Get(sv) {
+			continue
+		}
+		if _, ok := s.corrupt[num]; ok {
+			continue
+		}
+		if last, ok := s.scrubbed[num]; ok && now.Sub(last) < SSTScrubberInterval.Get(sv) {
+			continue
+		}
+		meta, ok := objects[num]
+		if !ok {
+			continue
+		}
+		if !SSTScrubberEnabled.Get(sv) {
+			break
+		}
+
+		size, err := p.validateSSTableChecksums(ctx, meta)
+		stats.ScrubbedBytes += size
+		if err != nil {
+			if ctx.Err() != nil {
+				return stats, ctx.Err()
+			}
+			if !errors.Is(err, pebble.ErrCorruption) {
+				// The file may have been deleted by a compaction since the sstables
+				// were listed; it will be ignored by the next pass if so.
+				log.Warningf(ctx, "unable to scrub sstable %d: %v", num, err)
+				continue
+			}
+			c := *backings[num]
+			c.Size = size
+			c.Err = err
+			stats.Corrupt = append(stats.Corrupt, c)
+			s.corrupt[num] = struct{}{}
+		} else {
+			stats.ScrubbedTables++
+			s.scrubbed[num] = timeutil.Now()
+		}
+
+		// Pace the reads to the configured rate.
+		rate := SSTScrubberRate.Get(sv)
+		timer.Reset(time.Duration(float64(size) / float64(rate) * float64(time.Second)))
+		select {
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return stats, ctx.Err()
+		}
+	}
+	return stats, nil
+}
+
+// validateSSTableChecksums validates the checksums of all the blocks of the
+// given sstable file, and returns the size of the file.
+func (p *Pebble) validateSSTableChecksums(
+	ctx context.Context, meta objstorage.ObjectMetadata,
+) (int64, error) {
+	readable, err := p.db.ObjProvider().OpenForReading(
+		ctx, meta.FileType, meta.DiskFileNum, objstorage.OpenOptions{})
+	if err != nil {
+		return 0, err
+	}
+	size := readable.Size()
+	opts := p.cfg.opts.MakeReaderOptions()
+	// Bypass the block cache: the scrubbed sstables are cold, and caching their
+	// blocks would evict the blocks of hot sstables.
+	opts.Cache = nil
+	r, err := sstable.NewReader(readable, opts)
+	if err != nil {
+		return size, err
+	}
+	err = r.ValidateBlockChecksums()
+	return size, errors.CombineErrors(err, r.Close())
+}
+
+// sstSpan returns the span of the keys between the given smallest and largest
+// engine keys of an sstable.
+func sstSpan(smallest, largest []byte) (roachpb.Span, bool) {
+	start, ok := DecodeEngineKey(smallest)
+	if !ok {
+		return roachpb.Span{}, false
+	}
+	end, ok := DecodeEngineKey(largest)
+	if !ok {
+		return roachpb.Span{}, false
+	}
+	return roachpb.Span{
+		Key:    start.Key.Clone(),
+		EndKey: end.Key.Clone().Next(),
+	}, true
+}
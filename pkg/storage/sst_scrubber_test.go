@@ -0,0 +1,118 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrubSSTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	st := cluster.MakeTestingClusterSettings()
+	SSTScrubberEnabled.Override(ctx, &st.SV, true)
+	SSTScrubberMinAge.Override(ctx, &st.SV, 0)
+	SSTScrubberRate.Override(ctx, &st.SV, 1<<40)
+	memFS := vfs.NewMem()
+	// Compactions would fail on the corrupt sstable.
+	p, err := Open(ctx, mustInitTestEnv(t, memFS, ""), st, DisableAutomaticCompactions)
+	require.NoError(t, err)
+	defer p.Close()
+
+	key := func(tableID uint32, i int) roachpb.Key {
+		return encoding.EncodeUvarintAscending(keys.SystemSQLCodec.TablePrefix(tableID), uint64(i))
+	}
+	write := func(tableID uint32) {
+		b := p.NewWriteBatch()
+		defer b.Close()
+		for i := 0; i < 100; i++ {
+			require.NoError(t, b.PutMVCC(
+				MVCCKey{Key: key(tableID, i), Timestamp: hlc.Timestamp{WallTime: 1}},
+				MVCCValue{Value: roachpb.MakeValueFromString("value")},
+			))
+		}
+		require.NoError(t, b.Commit(true /* sync */))
+		require.NoError(t, p.Flush())
+	}
+	tableFiles := func() map[uint64]struct{} {
+		files := make(map[uint64]struct{})
+		for _, meta := range p.db.ObjProvider().List() {
+			files[uint64(meta.DiskFileNum)] = struct{}{}
+		}
+		return files
+	}
+
+	// Healthy sstables are scrubbed once per interval.
+	write(100)
+	stats, err := p.ScrubSSTables(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.ScrubbedTables)
+	require.Greater(t, stats.ScrubbedBytes, int64(0))
+	require.Empty(t, stats.Corrupt)
+	stats, err = p.ScrubSSTables(ctx)
+	require.NoError(t, err)
+	require.Zero(t, stats.ScrubbedTables)
+
+	// Corrupt the first data block of a new sstable.
+	prev := tableFiles()
+	write(101)
+	var corruptNum uint64
+	for _, meta := range p.db.ObjProvider().List() {
+		if _, ok := prev[uint64(meta.DiskFileNum)]; ok {
+			continue
+		}
+		corruptNum = uint64(meta.DiskFileNum)
+		f, err := memFS.OpenReadWrite(p.db.ObjProvider().Path(meta), vfs.WriteCategoryUnspecified)
+		require.NoError(t, err)
+		_, err = f.WriteAt([]byte("corruption"), 8)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+	require.NotZero(t, corruptNum)
+
+	// The corrupt sstable is reported once, along with its span.
+	stats, err = p.ScrubSSTables(ctx)
+	require.NoError(t, err)
+	require.Zero(t, stats.ScrubbedTables)
+	require.Len(t, stats.Corrupt, 1)
+	c := stats.Corrupt[0]
+	require.Equal(t, corruptNum, c.FileNum)
+	require.True(t, errors.Is(c.Err, pebble.ErrCorruption))
+	require.True(t, c.Span.ContainsKey(key(101, 0)))
+	require.True(t, c.Span.ContainsKey(key(101, 99)))
+	require.False(t, c.Span.ContainsKey(key(100, 99)))
+	stats, err = p.ScrubSSTables(ctx)
+	require.NoError(t, err)
+	require.Empty(t, stats.Corrupt)
+
+	// Nothing is scrubbed while sstables are hot.
+	SSTScrubberMinAge.Override(ctx, &st.SV, time.Hour)
+	write(102)
+	stats, err = p.ScrubSSTables(ctx)
+	require.NoError(t, err)
+	require.Zero(t, stats.ScrubbedTables)
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/pebble"
+)
+
+// TenantWriteAmpSampleRate is the fraction of the sstables written by flushes,
+// ingestions and compactions that are attributed to the tenant owning their
+// keys.
+var TenantWriteAmpSampleRate = settings.RegisterFloatSetting(
+	settings.SystemOnly,
+	"storage.tenant_write_amp.sample_rate",
+	"fraction of the sstables written by flushes, ingestions and compactions "+
+		"whose bytes are attributed to the tenant owning their keys, for the "+
+		"estimation of the write amplification of each tenant; 0 disables the "+
+		"attribution",
+	0.1,
+	settings.FloatInRange(0, 1),
+)
+
+// maxTrackedTenants is the maximum number of tenants whose writes are tracked.
+// Writes of other tenants are ignored once the limit is reached.
+const maxTrackedTenants = 10000
+
+// TenantWriteAmp contains the estimated number of bytes written to the LSM on
+// behalf of a tenant. The estimates are extrapolated from a sample of the
+// sstables written by the engine; each sampled sstable is attributed in its
+// entirety to the tenant owning its smallest key.
+//
+// The sstables are not split at tenant boundaries, so the bytes of a tenant
+// whose keys share an sstable with a tenant preceding it in the keyspace are
+// attributed to that tenant instead. This skews the estimates towards the
+// tenants with lower IDs, in particular for flushes, whose sstables contain the
+// recent writes of every tenant. The estimates are only meaningful for tenants
+// whose data spans many sstables.
+type TenantWriteAmp struct {
+	TenantID roachpb.TenantID
+	// FlushedBytes is the number of bytes written to L0 by memtable flushes.
+	FlushedBytes int64
+	// IngestedBytes is the number of bytes of ingested sstables.
+	IngestedBytes int64
+	// CompactedBytes is the number of bytes written by compactions.
+	CompactedBytes int64
+	// SampledTables is the number of sstables that were attributed to the
+	// tenant.
+	SampledTables int64
+}
+
+// WriteAmp returns the estimated write amplification of the tenant, i.e. the
+// ratio between the bytes written to the LSM and the bytes which entered the
+// LSM through flushes and ingestions. It returns 0 if no bytes entered the LSM.
+func (w TenantWriteAmp) WriteAmp() float64 {
+	in := w.FlushedBytes + w.IngestedBytes
+	if in == 0 {
+		return 0
+	}
+	return float64(in+w.CompactedBytes) / float64(in)
+}
+
+type tenantWriteKind int
+
+const (
+	tenantWriteFlush tenantWriteKind = iota
+	tenantWriteIngest
+	tenantWriteCompaction
+)
+
+// tenantWriteAmpTracker attributes a sample of the sstables written by the
+// engine to the tenants owning their keys. The bytes written on behalf of each
+// tenant are extrapolated from the sample by weighting each sampled sstable by
+// the inverse of the sample rate.
+type tenantWriteAmpTracker struct {
+	sv *settings.Values
+
+	mu struct {
+		syncutil.Mutex
+		tenants map[roachpb.TenantID]*TenantWriteAmp
+	}
+}
+
+func (t *tenantWriteAmpTracker) init(sv *settings.Values) {
+	t.sv = sv
+	t.mu.tenants = make(map[roachpb.TenantID]*TenantWriteAmp)
+}
+
+// maybeRecord attributes the given sstables, written by a flush, an ingestion
+// or a compaction, to their tenants if they are sampled. An sstable spanning
+// several tenants is attributed to the tenant owning its smallest key; see
+// TenantWriteAmp for the resulting skew.
+func (t *tenantWriteAmpTracker) maybeRecord(kind tenantWriteKind, tables []pebble.TableInfo) {
+	rate := TenantWriteAmpSampleRate.Get(t.sv)
+	if rate == 0 {
+		return
+	}
+	for i := range tables {
+		if rate < 1 && rand.Float64() >= rate {
+			continue
+		}
+		tenantID, ok := tenantIDFromEngineKey(tables[i].Smallest.UserKey)
+		if !ok {
+			continue
+		}
+		t.record(kind, tenantID, int64(float64(tables[i].Size)/rate))
+	}
+}
+
+func (t *tenantWriteAmpTracker) record(
+	kind tenantWriteKind, tenantID roachpb.TenantID, bytes int64,
+) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w, ok := t.mu.tenants[tenantID]
+	if !ok {
+		if len(t.mu.tenants) >= maxTrackedTenants {
+			return
+		}
+		w = &TenantWriteAmp{TenantID: tenantID}
+		t.mu.tenants[tenantID] = w
+	}
+	switch kind {
+	case tenantWriteFlush:
+		w.FlushedBytes += bytes
+	case tenantWriteIngest:
+		w.IngestedBytes += bytes
+	case tenantWriteCompaction:
+		w.CompactedBytes += bytes
+	}
+	w.SampledTables++
+}
+
+// snapshot returns the bytes written on behalf of each tenant, ordered by
+// tenant ID.
+func (t *tenantWriteAmpTracker) snapshot() []TenantWriteAmp {
+	t.mu.Lock()
+	res := make([]TenantWriteAmp, 0, len(t.mu.tenants))
+	for _, w := range t.mu.tenants {
+		res = append(res, *w)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].TenantID.ToUint64() < res[j].TenantID.ToUint64()
+	})
+	return res
+}
+
+// tenantIDFromEngineKey returns the tenant owning the given engine key. Keys
+// outside of the keyspace of secondary tenants, including range-local keys,
+// are attributed to the system tenant.
+func tenantIDFromEngineKey(key []byte) (roachpb.TenantID, bool) {
+	ek, ok := DecodeEngineKey(key)
+	if !ok {
+		return roachpb.TenantID{}, false
+	}
+	_, tenantID, err := keys.DecodeTenantPrefix(ek.Key)
+	if err != nil {
+		return roachpb.TenantID{}, false
+	}
+	return tenantID, true
+}
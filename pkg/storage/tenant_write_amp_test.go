@@ -0,0 +1,99 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantWriteAmp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	st := cluster.MakeTestingClusterSettings()
+	TenantWriteAmpSampleRate.Override(ctx, &st.SV, 1)
+	p, err := Open(ctx, InMemory(), st)
+	require.NoError(t, err)
+	defer p.Close()
+
+	tenantID := roachpb.MustMakeTenantID(5)
+	codec := keys.MakeSQLCodec(tenantID)
+	write := func() {
+		b := p.NewWriteBatch()
+		defer b.Close()
+		for i := 0; i < 100; i++ {
+			key := encoding.EncodeUvarintAscending(codec.TablePrefix(100), uint64(i))
+			require.NoError(t, b.PutMVCC(
+				MVCCKey{Key: key, Timestamp: hlc.Timestamp{WallTime: int64(i + 1)}},
+				MVCCValue{Value: roachpb.MakeValueFromString("value")},
+			))
+		}
+		require.NoError(t, b.Commit(true /* sync */))
+		require.NoError(t, p.Flush())
+	}
+
+	// Flushes are attributed to the tenant owning the flushed keys.
+	write()
+	write()
+	writes := p.GetMetrics().TenantWriteAmp
+	require.Len(t, writes, 1)
+	require.Equal(t, tenantID, writes[0].TenantID)
+	require.Greater(t, writes[0].FlushedBytes, int64(0))
+	require.Zero(t, writes[0].CompactedBytes)
+	require.Equal(t, 1.0, writes[0].WriteAmp())
+
+	// Compactions increase the write amplification of the tenant.
+	require.NoError(t, p.Compact())
+	writes = p.GetMetrics().TenantWriteAmp
+	require.Len(t, writes, 1)
+	require.Greater(t, writes[0].CompactedBytes, int64(0))
+	require.Greater(t, writes[0].WriteAmp(), 1.0)
+
+	// Nothing is attributed when the sample rate is 0.
+	TenantWriteAmpSampleRate.Override(ctx, &st.SV, 0)
+	write()
+	require.Equal(t, writes, p.GetMetrics().TenantWriteAmp)
+}
+
+func TestTenantIDFromEngineKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tenantID := roachpb.MustMakeTenantID(10)
+	for _, tc := range []struct {
+		key      roachpb.Key
+		expected roachpb.TenantID
+	}{
+		{key: keys.MakeSQLCodec(tenantID).TablePrefix(100), expected: tenantID},
+		{key: keys.SystemSQLCodec.TablePrefix(100), expected: roachpb.SystemTenantID},
+		{key: keys.RangeDescriptorKey(roachpb.RKey(keys.MakeTenantPrefix(tenantID))),
+			expected: roachpb.SystemTenantID},
+	} {
+		engineKey := EncodeMVCCKey(MVCCKey{Key: tc.key, Timestamp: hlc.Timestamp{WallTime: 1}})
+		actual, ok := tenantIDFromEngineKey(engineKey)
+		require.True(t, ok)
+		require.Equal(t, tc.expected, actual)
+	}
+
+	_, ok := tenantIDFromEngineKey(nil)
+	require.False(t, ok)
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+// WALFailoverThreshold configures the latency of a WAL write considered
+// unhealthy, which triggers a failover to the secondary WAL directory, for the
+// engine. It overrides the storage.wal_failover.unhealthy_op_threshold cluster
+// setting. It has no effect if WAL failover is not configured.
+func WALFailoverThreshold(threshold time.Duration) ConfigOption {
+	return func(cfg *engineConfig) error {
+		if threshold <= 0 {
+			return errors.Newf("WAL failover threshold must be positive: %s", threshold)
+		}
+		cfg.walFailoverUnhealthyOpThreshold = threshold
+		return nil
+	}
+}
+
+// WALFailoverTestingKnobs contains testing hooks to inject failures in the
+// WAL of an engine, in order to exercise WAL failover.
+type WALFailoverTestingKnobs struct {
+	// BeforePrimaryWALWrite, if set, is called before each write and sync of a
+	// WAL file in the primary WAL directory. It may block to simulate a stalled
+	// disk, or return an error to fail the operation.
+	BeforePrimaryWALWrite func() error
+}
+
+// WALFailoverKnobs configures the WAL failover testing knobs of the engine.
+func WALFailoverKnobs(knobs WALFailoverTestingKnobs) ConfigOption {
+	return func(cfg *engineConfig) error {
+		cfg.walFailoverKnobs = knobs
+		return nil
+	}
+}
+
+// isWALFile returns whether the given path is the path of a WAL file.
+func isWALFile(fs vfs.FS, path string) bool {
+	return strings.HasSuffix(fs.PathBase(path), ".log")
+}
+
+// walSecondaryFS wraps the filesystem of the secondary WAL directory to count
+// the bytes written to WAL files.
+type walSecondaryFS struct {
+	vfs.FS
+	bytesWritten *atomic.Int64
+}
+
+// Create implements vfs.FS.
+func (fs *walSecondaryFS) Create(name string, category vfs.DiskWriteCategory) (vfs.File, error) {
+	f, err := fs.FS.Create(name, category)
+	return fs.wrap(name, f, err)
+}
+
+// ReuseForWrite implements vfs.FS.
+func (fs *walSecondaryFS) ReuseForWrite(
+	oldname, newname string, category vfs.DiskWriteCategory,
+) (vfs.File, error) {
+	f, err := fs.FS.ReuseForWrite(oldname, newname, category)
+	return fs.wrap(newname, f, err)
+}
+
+func (fs *walSecondaryFS) wrap(name string, f vfs.File, err error) (vfs.File, error) {
+	if err != nil || !isWALFile(fs.FS, name) {
+		return f, err
+	}
+	return &walSecondaryFile{File: f, bytesWritten: fs.bytesWritten}, nil
+}
+
+type walSecondaryFile struct {
+	vfs.File
+	bytesWritten *atomic.Int64
+}
+
+// Write implements vfs.File.
+func (f *walSecondaryFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	f.bytesWritten.Add(int64(n))
+	return n, err
+}
+
+// walPrimaryFS wraps the filesystem of the primary WAL directory to inject
+// failures in writes to WAL files, see WALFailoverTestingKnobs.
+type walPrimaryFS struct {
+	vfs.FS
+	knobs WALFailoverTestingKnobs
+}
+
+// Create implements vfs.FS.
+func (fs *walPrimaryFS) Create(name string, category vfs.DiskWriteCategory) (vfs.File, error) {
+	f, err := fs.FS.Create(name, category)
+	return fs.wrap(name, f, err)
+}
+
+// ReuseForWrite implements vfs.FS.
+func (fs *walPrimaryFS) ReuseForWrite(
+	oldname, newname string, category vfs.DiskWriteCategory,
+) (vfs.File, error) {
+	f, err := fs.FS.ReuseForWrite(oldname, newname, category)
+	return fs.wrap(newname, f, err)
+}
+
+func (fs *walPrimaryFS) wrap(name string, f vfs.File, err error) (vfs.File, error) {
+	if err != nil || !isWALFile(fs.FS, name) {
+		return f, err
+	}
+	return &walPrimaryFile{File: f, before: fs.knobs.BeforePrimaryWALWrite}, nil
+}
+
+type walPrimaryFile struct {
+	vfs.File
+	before func() error
+}
+
+// Write implements vfs.File.
+func (f *walPrimaryFile) Write(p []byte) (int, error) {
+	if err := f.before(); err != nil {
+		return 0, err
+	}
+	return f.File.Write(p)
+}
+
+// Sync implements vfs.File.
+func (f *walPrimaryFile) Sync() error {
+	if err := f.before(); err != nil {
+		return err
+	}
+	return f.File.Sync()
+}
+
+// SyncData implements vfs.File.
+func (f *walPrimaryFile) SyncData() error {
+	if err := f.before(); err != nil {
+		return err
+	}
+	return f.File.SyncData()
+}
+
+// SyncTo implements vfs.File.
+func (f *walPrimaryFile) SyncTo(length int64) (fullSync bool, err error) {
+	if err := f.before(); err != nil {
+		return false, err
+	}
+	return f.File.SyncTo(length)
+}
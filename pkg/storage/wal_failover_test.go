@@ -0,0 +1,88 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWALFailoverStalledPrimary stalls the writes to the primary WAL directory
+// with the WAL failover testing knobs and verifies that the engine fails over
+// to the secondary directory, as reflected by its metrics.
+func TestWALFailoverStalledPrimary(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// Mock an enterprise license, required by WAL failover.
+	enterpriseEnabledFunc := base.CCLDistributionAndEnterpriseEnabled
+	base.CCLDistributionAndEnterpriseEnabled = func(st *cluster.Settings) bool { return true }
+	defer func() { base.CCLDistributionAndEnterpriseEnabled = enterpriseEnabledFunc }()
+
+	var walCfg base.WALFailoverConfig
+	require.NoError(t, walCfg.Set("path=/failover"))
+	env := mustInitTestEnv(t, vfs.NewMem(), "/store")
+
+	var stalled atomic.Bool
+	unstall := make(chan struct{})
+	const threshold = 20 * time.Millisecond
+	eng, err := Open(context.Background(), env, cluster.MakeTestingClusterSettings(),
+		WALFailover(walCfg, fs.Envs{env}, vfs.NewMem(), nil /* statsCollector */),
+		WALFailoverThreshold(threshold),
+		WALFailoverKnobs(WALFailoverTestingKnobs{
+			BeforePrimaryWALWrite: func() error {
+				if stalled.Load() {
+					<-unstall
+				}
+				return nil
+			},
+		}))
+	require.NoError(t, err)
+	defer eng.Close()
+
+	// The per-store threshold overrides the cluster setting.
+	d, ok := eng.cfg.opts.WALFailover.UnhealthyOperationLatencyThreshold()
+	require.True(t, ok)
+	require.Equal(t, threshold, d)
+
+	put := func(key string) {
+		b := eng.NewBatch()
+		defer b.Close()
+		require.NoError(t, b.PutUnversioned(roachpb.Key(key), []byte("value")))
+		require.NoError(t, b.Commit(true /* sync */))
+	}
+	put("a")
+	m := eng.GetMetrics()
+	require.Zero(t, m.WAL.Failover.DirSwitchCount)
+	require.Zero(t, m.WALFailoverSecondaryBytes)
+
+	// With the primary stalled, the synced write completes by failing over to
+	// the secondary.
+	stalled.Store(true)
+	put("b")
+	m = eng.GetMetrics()
+	require.Positive(t, m.WAL.Failover.DirSwitchCount)
+	require.Positive(t, m.WALFailoverSecondaryBytes)
+
+	stalled.Store(false)
+	close(unstall)
+}
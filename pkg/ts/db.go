@@ -81,6 +81,10 @@ type DB struct {
 	// eligible for deletion. Thresholds are specified in nanoseconds.
 	pruneThresholdByResolution map[Resolution]func() int64
 
+	// labelLimiter enforces MaxLabelCardinality on the labeled series stored
+	// by this node.
+	labelLimiter labelCardinalityLimiter
+
 	// forceRowFormat is set to true if the database should write in the old row
 	// format, regardless of the current cluster setting. Currently only set to
 	// true in tests to verify backwards compatibility.
@@ -227,6 +231,20 @@ func (db *DB) tryStoreData(ctx context.Context, r Resolution, data []tspb.TimeSe
 	// Process data collection: data is converted to internal format, and a key
 	// is generated for each internal message.
 	for _, d := range data {
+		name := d.Name
+		if len(d.Labels) > 0 {
+			name = MakeLabeledSeriesName(d.Name, d.Labels)
+			if err := validateLabels(d.Labels); err != nil {
+				log.VEventf(ctx, 2, "not storing time series %s: %v", name, err)
+				db.metrics.WriteDroppedLabeledSeries.Inc(1)
+				continue
+			}
+			if !db.labelLimiter.allow(d.Name, name, MaxLabelCardinality.Get(&db.st.SV), timeutil.Now()) {
+				log.VEventf(ctx, 2, "not storing time series %s: label cardinality limit reached", name)
+				db.metrics.WriteDroppedLabeledSeries.Inc(1)
+				continue
+			}
+		}
 		idatas, err := d.ToInternal(r.SlabDuration(), r.SampleDuration(), db.WriteColumnar())
 		if err != nil {
 			return err
@@ -236,7 +254,7 @@ func (db *DB) tryStoreData(ctx context.Context, r Resolution, data []tspb.TimeSe
 			if err := value.SetProto(&idata); err != nil {
 				return err
 			}
-			key := MakeDataKey(d.Name, d.Source, r, idata.StartTimestampNanos)
+			key := MakeDataKey(name, d.Source, r, idata.StartTimestampNanos)
 			kvs = append(kvs, roachpb.KeyValue{
 				Key:   key,
 				Value: value,
@@ -0,0 +1,167 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ts
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Labeled time series are stored as separate series, whose name is the name of
+// the series followed by its labels, sorted by label name:
+//
+//   [series name]{[label name]="[label value]",...}
+//
+// The labels are therefore part of the series name in the data keys, and
+// labeled series are pruned and rolled up like any other series. To bound the
+// amount of data stored, each node limits the number of distinct label sets it
+// stores for a given series name (see timeseries.storage.max_label_cardinality).
+// Label sets which are no longer written expire, making room for new ones.
+
+// MaxLabelCardinality is the maximum number of distinct label sets stored by a
+// node for a given time series name.
+var MaxLabelCardinality = settings.RegisterIntSetting(
+	settings.SystemOnly,
+	"timeseries.storage.max_label_cardinality",
+	"the maximum number of distinct label sets stored by each node for a labeled time series; "+
+		"data for additional label sets is dropped",
+	100,
+	settings.NonNegativeInt,
+	settings.WithPublic)
+
+// maxLabelsPerSeries is the maximum number of labels of a time series.
+const maxLabelsPerSeries = 4
+
+// validateLabels returns an error if the labels cannot identify a time series.
+func validateLabels(labels []tspb.Label) error {
+	if len(labels) > maxLabelsPerSeries {
+		return errors.Errorf("time series cannot have more than %d labels", maxLabelsPerSeries)
+	}
+	for i, l := range labels {
+		if l.Name == "" {
+			return errors.New("time series label name cannot be empty")
+		}
+		if strings.ContainsAny(l.Name, `{}=,"`) {
+			return errors.Errorf("invalid time series label name %q", l.Name)
+		}
+		for _, other := range labels[:i] {
+			if other.Name == l.Name {
+				return errors.Errorf("duplicate time series label %q", l.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// MakeLabeledSeriesName returns the name under which the time series with the
+// given name and labels is stored. The order of the labels is irrelevant.
+func MakeLabeledSeriesName(name string, labels []tspb.Label) string {
+	if len(labels) == 0 {
+		return name
+	}
+	sorted := append([]tspb.Label(nil), labels...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, l := range sorted {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(l.Name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(l.Value))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// labelSetExpiration is the duration after which a label set which has not
+// been written is no longer accounted for by labelCardinalityLimiter, making
+// room for new label sets. Series whose label sets come and go, e.g. per-table
+// series of dropped tables, would otherwise permanently use up the limit.
+const labelSetExpiration = time.Hour
+
+// labelCardinalityLimiter tracks the label sets stored for each labeled time
+// series name, to enforce MaxLabelCardinality.
+type labelCardinalityLimiter struct {
+	syncutil.Mutex
+	// labelSets maps series names to the labeled series names stored for them,
+	// and the last time each was written.
+	labelSets map[string]map[string]time.Time
+	// lastSweep is the last time expired label sets were removed for all
+	// series names.
+	lastSweep time.Time
+}
+
+// allow returns whether the labeled series, with the given name and labeled
+// name, can be stored at the given time without exceeding the limit on the
+// number of label sets for the name. If so, the label set is accounted for.
+func (l *labelCardinalityLimiter) allow(
+	name, labeledName string, limit int64, now time.Time,
+) bool {
+	l.Lock()
+	defer l.Unlock()
+	if now.Sub(l.lastSweep) >= labelSetExpiration {
+		l.sweepLocked(now)
+	}
+	sets, ok := l.labelSets[name]
+	if !ok {
+		if l.labelSets == nil {
+			l.labelSets = make(map[string]map[string]time.Time)
+		}
+		sets = make(map[string]time.Time)
+		l.labelSets[name] = sets
+	}
+	if _, ok := sets[labeledName]; ok {
+		sets[labeledName] = now
+		return true
+	}
+	if int64(len(sets)) >= limit {
+		expireLabelSets(sets, now)
+		if int64(len(sets)) >= limit {
+			return false
+		}
+	}
+	sets[labeledName] = now
+	return true
+}
+
+// sweepLocked removes the expired label sets of all series names, and the
+// names left without label sets.
+func (l *labelCardinalityLimiter) sweepLocked(now time.Time) {
+	for name, sets := range l.labelSets {
+		expireLabelSets(sets, now)
+		if len(sets) == 0 {
+			delete(l.labelSets, name)
+		}
+	}
+	l.lastSweep = now
+}
+
+// expireLabelSets removes the label sets which have not been written for
+// labelSetExpiration.
+func expireLabelSets(sets map[string]time.Time, now time.Time) {
+	for labeledName, lastWritten := range sets {
+		if now.Sub(lastWritten) >= labelSetExpiration {
+			delete(sets, labeledName)
+		}
+	}
+}
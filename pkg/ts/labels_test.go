@@ -0,0 +1,120 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeLabeledSeriesName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	require.Equal(t, "test.metric", MakeLabeledSeriesName("test.metric", nil))
+	labels := []tspb.Label{{Name: "tenant", Value: "5"}, {Name: "table", Value: `t"1`}}
+	require.Equal(t, `test.metric{table="t\"1",tenant="5"}`, MakeLabeledSeriesName("test.metric", labels))
+	// The labels are not reordered in place.
+	require.Equal(t, "tenant", labels[0].Name)
+
+	require.NoError(t, validateLabels(labels))
+	require.EqualError(t,
+		validateLabels([]tspb.Label{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}),
+		"time series cannot have more than 4 labels")
+	require.EqualError(t, validateLabels([]tspb.Label{{Value: "a"}}),
+		"time series label name cannot be empty")
+	require.EqualError(t, validateLabels([]tspb.Label{{Name: "a=b"}}),
+		`invalid time series label name "a=b"`)
+	require.EqualError(t, validateLabels([]tspb.Label{{Name: "a"}, {Name: "a"}}),
+		`duplicate time series label "a"`)
+}
+
+// TestStoreLabeledTimeSeries verifies that labeled time series are stored
+// separately from each other, and that the number of label sets stored for a
+// series is bounded.
+func TestStoreLabeledTimeSeries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	runTestCaseMultipleFormats(t, func(t *testing.T, tm testModelRunner) {
+		MaxLabelCardinality.Override(context.Background(), &tm.Cfg.Settings.SV, 2)
+
+		labeled := func(value float64, labels ...tspb.Label) tspb.TimeSeriesData {
+			d := tsd("test.metric", "1", tsdp(1, value))
+			d.Labels = labels
+			return d
+		}
+		table := func(name string) tspb.Label {
+			return tspb.Label{Name: "table", Value: name}
+		}
+		tenant := tspb.Label{Name: "tenant", Value: "5"}
+		require.NoError(t, tm.DB.StoreData(context.Background(), resolution1ns, []tspb.TimeSeriesData{
+			labeled(100, table("a"), tenant),
+			labeled(200, tenant, table("b")),
+			// Exceeds the label cardinality limit.
+			labeled(300, table("c"), tenant),
+			// Invalid.
+			labeled(400, table("a"), table("b")),
+		}))
+		require.Equal(t, int64(2), tm.DB.Metrics().WriteDroppedLabeledSeries.Count())
+
+		query := func(labels ...tspb.Label) []tspb.TimeSeriesDatapoint {
+			q := tm.makeQuery("test.metric", resolution1ns, 0, 10)
+			q.Labels = labels
+			datapoints, _, err := q.queryDB()
+			require.NoError(t, err)
+			return datapoints
+		}
+		require.Equal(t, []tspb.TimeSeriesDatapoint{tsdp(1, 100)}, query(tenant, table("a")))
+		require.Equal(t, []tspb.TimeSeriesDatapoint{tsdp(1, 200)}, query(table("b"), tenant))
+		require.Empty(t, query(table("c"), tenant))
+		require.Empty(t, query())
+
+		// Label sets already stored can still be written once the limit is
+		// reached.
+		require.NoError(t, tm.DB.StoreData(context.Background(), resolution1ns, []tspb.TimeSeriesData{
+			labeled(500, table("b"), tenant),
+		}))
+		require.Equal(t, int64(2), tm.DB.Metrics().WriteDroppedLabeledSeries.Count())
+	})
+}
+
+// TestLabelCardinalityLimiterExpiration verifies that label sets which are no
+// longer written stop counting towards the label cardinality limit.
+func TestLabelCardinalityLimiterExpiration(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var l labelCardinalityLimiter
+	start := timeutil.Unix(1000, 0)
+	require.True(t, l.allow("m", `m{t="a"}`, 2, start))
+	require.True(t, l.allow("m", `m{t="b"}`, 2, start))
+	require.False(t, l.allow("m", `m{t="c"}`, 2, start))
+
+	// Writing a label set keeps it from expiring.
+	require.True(t, l.allow("m", `m{t="a"}`, 2, start.Add(labelSetExpiration/2)))
+	require.False(t, l.allow("m", `m{t="c"}`, 2, start.Add(labelSetExpiration-1)))
+	// Only the label set which has not been written since has expired.
+	require.True(t, l.allow("m", `m{t="c"}`, 2, start.Add(labelSetExpiration)))
+	require.False(t, l.allow("m", `m{t="b"}`, 2, start.Add(labelSetExpiration)))
+
+	// Names whose label sets all expired are removed.
+	require.True(t, l.allow("n", `n{t="a"}`, 2, start.Add(labelSetExpiration)))
+	l.Lock()
+	require.Len(t, l.labelSets, 2)
+	l.Unlock()
+	require.True(t, l.allow("m", `m{t="c"}`, 2, start.Add(3*labelSetExpiration)))
+	l.Lock()
+	defer l.Unlock()
+	require.Len(t, l.labelSets, 1)
+	require.Len(t, l.labelSets["m"], 1)
+}
@@ -26,6 +26,12 @@ var (
 		Measurement: "Storage",
 		Unit:        metric.Unit_BYTES,
 	}
+	metaWriteDroppedLabeledSeries = metric.Metadata{
+		Name:        "timeseries.write.dropped_labeled_series",
+		Help:        "Total labeled time series not written to disk because their labels were invalid or their label cardinality limit was reached",
+		Measurement: "Time Series",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaWriteErrors = metric.Metadata{
 		Name:        "timeseries.write.errors",
 		Help:        "Total errors encountered while attempting to write metrics to disk",
@@ -36,16 +42,18 @@ var (
 
 // TimeSeriesMetrics contains metrics relevant to the time series system.
 type TimeSeriesMetrics struct {
-	WriteSamples *metric.Counter
-	WriteBytes   *metric.Counter
-	WriteErrors  *metric.Counter
+	WriteSamples              *metric.Counter
+	WriteBytes                *metric.Counter
+	WriteErrors               *metric.Counter
+	WriteDroppedLabeledSeries *metric.Counter
 }
 
 // NewTimeSeriesMetrics creates a new instance of TimeSeriesMetrics.
 func NewTimeSeriesMetrics() *TimeSeriesMetrics {
 	return &TimeSeriesMetrics{
-		WriteSamples: metric.NewCounter(metaWriteSamples),
-		WriteBytes:   metric.NewCounter(metaWriteBytes),
-		WriteErrors:  metric.NewCounter(metaWriteErrors),
+		WriteSamples:              metric.NewCounter(metaWriteSamples),
+		WriteBytes:                metric.NewCounter(metaWriteBytes),
+		WriteErrors:               metric.NewCounter(metaWriteErrors),
+		WriteDroppedLabeledSeries: metric.NewCounter(metaWriteDroppedLabeledSeries),
 	}
 }
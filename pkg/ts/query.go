@@ -460,6 +460,9 @@ func (db *DB) Query(
 	if err := verifyDownsampler(query.GetDownsampler()); err != nil {
 		return nil, nil, err
 	}
+	if err := validateLabels(query.Labels); err != nil {
+		return nil, nil, err
+	}
 
 	// Adjust timespan based on the current time.
 	if err := timespan.adjustForCurrentTime(diskResolution); err != nil {
@@ -553,12 +556,13 @@ func (db *DB) queryChunk(
 	diskTimespan := timespan
 	diskTimespan.expand(mem.InterpolationLimitNanos)
 
+	seriesName := MakeLabeledSeriesName(query.Name, query.Labels)
 	var data []kv.KeyValue
 	var err error
 	if len(query.Sources) == 0 {
-		data, err = db.readAllSourcesFromDatabase(ctx, query.Name, diskResolution, diskTimespan, query.TenantID)
+		data, err = db.readAllSourcesFromDatabase(ctx, seriesName, diskResolution, diskTimespan, query.TenantID)
 	} else {
-		data, err = db.readFromDatabase(ctx, query.Name, diskResolution, diskTimespan, query.Sources, query.TenantID)
+		data, err = db.readFromDatabase(ctx, seriesName, diskResolution, diskTimespan, query.Sources, query.TenantID)
 	}
 
 	if err != nil {
@@ -158,6 +158,7 @@ type LatencyInfo = {
 type Statistics = {
   bytesRead: NumericStat;
   cnt: Long;
+  estimatedCPUSeconds?: NumericStat;
   firstAttemptCnt: Long;
   idleLat: NumericStat;
   indexes: string[];
@@ -171,6 +172,7 @@ type Statistics = {
   parseLat: NumericStat;
   planGists: string[];
   planLat: NumericStat;
+  requestUnits?: NumericStat;
   rowsRead: NumericStat;
   rowsWritten: NumericStat;
   runLat: NumericStat;
@@ -232,6 +234,7 @@ export function convertStatementRawFormatToAggregatedStatistics(
       },
       bytes_read: s.statistics.statistics.bytesRead,
       count: s.statistics.statistics.cnt,
+      estimated_cpu_seconds: s.statistics.statistics.estimatedCPUSeconds,
       first_attempt_count: s.statistics.statistics.firstAttemptCnt,
       idle_lat: s.statistics.statistics.idleLat,
       index_recommendations: s.statistics.index_recommendations,
@@ -254,6 +257,7 @@ export function convertStatementRawFormatToAggregatedStatistics(
       parse_lat: s.statistics.statistics.parseLat,
       plan_gists: s.statistics.statistics.planGists,
       plan_lat: s.statistics.statistics.planLat,
+      request_units: s.statistics.statistics.requestUnits,
       rows_read: s.statistics.statistics.rowsRead,
       rows_written: s.statistics.statistics.rowsWritten,
       run_lat: s.statistics.statistics.runLat,
@@ -842,6 +842,24 @@ export class StatementDetails extends React.Component<
                     Duration,
                   )}
                 />
+                {stats?.request_units?.mean > 0 && (
+                  <SummaryCardItem
+                    label="Request Units"
+                    value={`${Count(stats.request_units.mean)} Mean / ${Count(
+                      stats.request_units.mean * longToInt(stats.count),
+                    )} Total`}
+                  />
+                )}
+                {stats?.estimated_cpu_seconds?.mean > 0 && (
+                  <SummaryCardItem
+                    label="Estimated KV CPU Time"
+                    value={`${duration(
+                      stats.estimated_cpu_seconds.mean,
+                    )} Mean / ${duration(
+                      stats.estimated_cpu_seconds.mean * longToInt(stats.count),
+                    )} Total`}
+                  />
+                )}
                 <SummaryCardItem
                   label="Client Wait Time"
                   value={formatNumberForDisplay(stats?.idle_lat.mean, duration)}
@@ -241,6 +241,18 @@ export function addStatementStats(
       countA,
       countB,
     ),
+    request_units: aggregateNumericStats(
+      a.request_units,
+      b.request_units,
+      countA,
+      countB,
+    ),
+    estimated_cpu_seconds: aggregateNumericStats(
+      a.estimated_cpu_seconds,
+      b.estimated_cpu_seconds,
+      countA,
+      countB,
+    ),
     sensitive_info: coalesceSensitiveInfo(a.sensitive_info, b.sensitive_info),
     legacy_last_err: "",
     legacy_last_err_redacted: "",
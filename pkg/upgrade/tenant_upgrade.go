@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/upgrade/upgradebase"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
 )
 
@@ -68,6 +69,13 @@ type TenantUpgradeFunc func(context.Context, clusterversion.ClusterVersion, Tena
 // being able to upgrade.
 type PreconditionFunc func(context.Context, clusterversion.ClusterVersion, TenantDeps) error
 
+// DryRunFunc is used to estimate the impact of a tenant upgrade without
+// performing it, so that operators can review the work an upgrade will do
+// before finalizing it. It must not modify any state.
+type DryRunFunc func(
+	context.Context, clusterversion.ClusterVersion, TenantDeps,
+) (upgradebase.ImpactReport, error)
+
 // TenantUpgrade is an implementation of Upgrade for tenant-level
 // upgrades. This is used for all upgrade which might affect the state of
 // sql. It includes the system tenant.
@@ -77,6 +85,8 @@ type TenantUpgrade struct {
 	// precondition is executed before fn. Note that permanent upgrades (see
 	// upgrade.permanent) cannot have preconditions.
 	precondition PreconditionFunc
+	// dryRun, if set, estimates the impact of fn without running it.
+	dryRun DryRunFunc
 }
 
 var _ upgradebase.Upgrade = (*TenantUpgrade)(nil)
@@ -122,3 +132,29 @@ func (m *TenantUpgrade) Precondition(
 	}
 	return nil
 }
+
+// WithDryRun sets the function used to estimate the impact of the upgrade
+// without running it. It returns the receiver to allow chaining it with
+// NewTenantUpgrade.
+func (m *TenantUpgrade) WithDryRun(fn DryRunFunc) *TenantUpgrade {
+	m.dryRun = fn
+	return m
+}
+
+// SupportsDryRun returns whether the upgrade can be dry run.
+func (m *TenantUpgrade) SupportsDryRun() bool {
+	return m.dryRun != nil
+}
+
+// DryRun estimates the impact of the upgrade without running it. It must only
+// be called if SupportsDryRun returns true.
+func (m *TenantUpgrade) DryRun(
+	ctx context.Context, cv clusterversion.ClusterVersion, d TenantDeps,
+) (upgradebase.ImpactReport, error) {
+	ctx = logtags.AddTag(ctx, fmt.Sprintf("upgrade=%s,dry-run", cv), nil)
+	if m.dryRun == nil {
+		return upgradebase.ImpactReport{}, errors.AssertionFailedf(
+			"upgrade for version %s does not support dry runs", cv)
+	}
+	return m.dryRun(ctx, cv, d)
+}
@@ -9,7 +9,10 @@
 // licenses/APL.txt.
 package upgradebase
 
-import "github.com/cockroachdb/cockroach/pkg/roachpb"
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+)
 
 // Upgrade defines a program to be executed once every node in the cluster is
 // (a) running a specific binary version, and (b) has completed all prior
@@ -55,3 +58,29 @@ type Upgrade interface {
 
 	RestoreBehavior() string
 }
+
+// ImpactReport describes the work an upgrade would perform if it were run, as
+// estimated by a dry run of the upgrade.
+type ImpactReport struct {
+	// Descriptors are the IDs of the descriptors the upgrade would modify.
+	Descriptors []descpb.ID
+	// Spans are the key spans the upgrade would write to or rewrite.
+	Spans []roachpb.Span
+	// EstimatedRanges is the number of ranges overlapping Spans.
+	EstimatedRanges int64
+	// EstimatedRows is the number of rows the upgrade would read or rewrite.
+	EstimatedRows int64
+	// Details describes the individual steps the upgrade would perform.
+	Details []string
+}
+
+// DryRunResult is the result of a dry run of the upgrade associated with a
+// cluster version.
+type DryRunResult struct {
+	Version roachpb.Version
+	Name    string
+	// Supported is false if the upgrade can't be dry run, in which case Report
+	// is empty.
+	Supported bool
+	Report    ImpactReport
+}
@@ -827,15 +827,7 @@ func (m *Manager) checkPreconditions(ctx context.Context, versions []roachpb.Ver
 		if !ok {
 			continue
 		}
-		if err := tm.Precondition(ctx, clusterversion.ClusterVersion{Version: v}, upgrade.TenantDeps{
-			DB:               m.deps.DB,
-			Codec:            m.codec,
-			Settings:         m.settings,
-			LeaseManager:     m.lm,
-			InternalExecutor: m.ie,
-			JobRegistry:      m.jr,
-			ClusterID:        m.clusterID.Get(),
-		}); err != nil {
+		if err := tm.Precondition(ctx, clusterversion.ClusterVersion{Version: v}, m.tenantDeps()); err != nil {
 			return errors.Wrapf(
 				err,
 				"verifying precondition for version %s",
@@ -845,3 +837,56 @@ func (m *Manager) checkPreconditions(ctx context.Context, versions []roachpb.Ver
 	}
 	return nil
 }
+
+// tenantDeps returns the dependencies passed to tenant upgrade preconditions
+// and dry runs.
+func (m *Manager) tenantDeps() upgrade.TenantDeps {
+	return upgrade.TenantDeps{
+		DB:               m.deps.DB,
+		Codec:            m.codec,
+		Settings:         m.settings,
+		LeaseManager:     m.lm,
+		InternalExecutor: m.ie,
+		JobRegistry:      m.jr,
+		ClusterID:        m.clusterID.Get(),
+	}
+}
+
+// DryRun estimates the impact of the upgrades that would run when moving the
+// cluster from its active version to the given version, without running them.
+// A result is returned for every version in between that has an upgrade
+// associated with it; upgrades that can't be dry run, which include all system
+// upgrades, are reported as unsupported.
+func (m *Manager) DryRun(
+	ctx context.Context, to roachpb.Version,
+) ([]upgradebase.DryRunResult, error) {
+	ctx = logtags.AddTag(ctx, "migration-mgr", nil)
+	from := m.settings.Version.ActiveVersion(ctx).Version
+	if latest := m.settings.Version.LatestVersion(); latest.Less(to) {
+		return nil, errors.Newf("cannot dry run upgrade to version %s: binary version is %s",
+			to, latest)
+	}
+
+	var results []upgradebase.DryRunResult
+	for _, v := range m.listBetween(from, to) {
+		mig, ok := m.GetUpgrade(v)
+		if !ok {
+			continue
+		}
+		res := upgradebase.DryRunResult{Version: v, Name: mig.Name()}
+		if tm, ok := mig.(*upgrade.TenantUpgrade); ok && tm.SupportsDryRun() {
+			report, err := tm.DryRun(ctx, clusterversion.ClusterVersion{Version: v}, m.tenantDeps())
+			if err != nil {
+				return nil, errors.Wrapf(
+					err,
+					"dry run of upgrade for version %s",
+					redact.SafeString(v.PrettyPrint()),
+				)
+			}
+			res.Supported = true
+			res.Report = report
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
@@ -15,9 +15,9 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
-	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
 	"github.com/cockroachdb/cockroach/pkg/upgrade"
+	"github.com/cockroachdb/cockroach/pkg/upgrade/upgradebase"
 )
 
 // Target schema changes in the system.statement_diagnostics_requests table,
@@ -31,39 +31,39 @@ ALTER TABLE system.statement_diagnostics_requests
 CREATE INDEX completed_idx ON system.statement_diagnostics_requests (completed, ID)
   STORING (statement_fingerprint, min_execution_latency, expires_at, sampling_probability, plan_gist, anti_plan_gist, redacted)`
 
-	// Note that only the DROP INDEX stmt must be idempotent since we don't have
-	// the corresponding `schemaExistsFn`.
 	dropCompletedIdxV2 = `DROP INDEX IF EXISTS system.statement_diagnostics_requests@completed_idx_v2`
 )
 
+// stmtDiagRedactedOps are the operations performed by
+// stmtDiagRedactedMigration.
+var stmtDiagRedactedOps = []operation{
+	{
+		name:           "add-stmt-diag-reqs-redacted-column",
+		schemaList:     []string{"redacted"},
+		query:          addRedactedColToStmtDiagReqs,
+		schemaExistsFn: hasColumn,
+	},
+	{
+		name:           "create-stmt-diag-reqs-index",
+		schemaList:     []string{"completed_idx"},
+		query:          createCompletedIdx,
+		schemaExistsFn: hasIndex,
+	},
+	{
+		name:           "drop-stmt-diag-reqs-old-index",
+		schemaList:     []string{"completed_idx_v2"},
+		query:          dropCompletedIdxV2,
+		schemaExistsFn: doesNotHaveIndex,
+	},
+}
+
 // stmtDiagRedactedMigration changes the schema of the
 // system.statement_diagnostics_requests table to support requesting redacted
 // bundles.
 func stmtDiagRedactedMigration(
 	ctx context.Context, cs clusterversion.ClusterVersion, d upgrade.TenantDeps,
 ) error {
-	for _, op := range []operation{
-		{
-			name:           "add-stmt-diag-reqs-redacted-column",
-			schemaList:     []string{"redacted"},
-			query:          addRedactedColToStmtDiagReqs,
-			schemaExistsFn: hasColumn,
-		},
-		{
-			name:           "create-stmt-diag-reqs-index",
-			schemaList:     []string{"completed_idx"},
-			query:          createCompletedIdx,
-			schemaExistsFn: hasIndex,
-		},
-		{
-			name:       "drop-stmt-diag-reqs-old-index",
-			schemaList: []string{"completed_idx_v2"},
-			query:      dropCompletedIdxV2,
-			schemaExistsFn: func(catalog.TableDescriptor, catalog.TableDescriptor, string) (bool, error) {
-				return false, nil
-			},
-		},
-	} {
+	for _, op := range stmtDiagRedactedOps {
 		if err := migrateTable(ctx, cs, d, op, keys.StatementDiagnosticsRequestsTableID,
 			systemschema.StatementDiagnosticsRequestsTable); err != nil {
 			return err
@@ -71,3 +71,11 @@ func stmtDiagRedactedMigration(
 	}
 	return nil
 }
+
+// stmtDiagRedactedDryRun estimates the impact of stmtDiagRedactedMigration.
+func stmtDiagRedactedDryRun(
+	ctx context.Context, _ clusterversion.ClusterVersion, d upgrade.TenantDeps,
+) (upgradebase.ImpactReport, error) {
+	return dryRunMigrateTable(ctx, d, stmtDiagRedactedOps, keys.StatementDiagnosticsRequestsTableID,
+		systemschema.StatementDiagnosticsRequestsTable)
+}
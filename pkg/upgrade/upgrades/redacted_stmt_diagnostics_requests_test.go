@@ -12,6 +12,7 @@ package upgrades_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -26,10 +27,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/upgrade/upgrades"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStmtDiagRedactedMigration(t *testing.T) {
@@ -87,6 +90,23 @@ func TestStmtDiagRedactedMigration(t *testing.T) {
 	// Validate that the statement_diagnostics_requests table has the old
 	// schema.
 	validateSchemaExists(false)
+	// A dry run of the upgrade reports the pending schema changes without
+	// performing them.
+	var (
+		supported     bool
+		descriptorIDs string
+		steps         int
+	)
+	sqlutils.MakeSQLRunner(sqlDB).QueryRow(t, `
+SELECT dry_run_supported, descriptor_ids::STRING, array_length(details, 1)
+  FROM crdb_internal.upgrade_dry_run($1)
+ WHERE version = $1`,
+		clusterversion.V24_2_StmtDiagRedacted.Version().String(),
+	).Scan(&supported, &descriptorIDs, &steps)
+	require.True(t, supported)
+	require.Equal(t, fmt.Sprintf("{%d}", keys.StatementDiagnosticsRequestsTableID), descriptorIDs)
+	require.Equal(t, 3, steps)
+	validateSchemaExists(false)
 	// Run the upgrade.
 	upgrades.Upgrade(
 		t,
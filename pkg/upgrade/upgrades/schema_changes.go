@@ -17,12 +17,16 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/upgrade"
+	"github.com/cockroachdb/cockroach/pkg/upgrade/upgradebase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
@@ -111,19 +115,9 @@ func migrateTable(
 			continue
 		}
 		// Ignore the schema change if the table already has the required schema.
-		// Expect all or none.
-		var exists bool
-		for i, schemaName := range op.schemaList {
-			hasSchema, err := op.schemaExistsFn(storedTable, expectedTable, schemaName)
-			if err != nil {
-				return errors.Wrapf(err, "error while validating descriptors during"+
-					" operation %s", op.name)
-			}
-			if i > 0 && exists != hasSchema {
-				return errors.Errorf("error while validating descriptors. observed"+
-					" partial schema exists while performing %v", op.name)
-			}
-			exists = hasSchema
+		exists, err := schemaChangeExists(storedTable, expectedTable, op)
+		if err != nil {
+			return err
 		}
 		if exists {
 			log.Infof(ctx, "skipping %s operation as the schema change already exists.", op.name)
@@ -144,6 +138,78 @@ func migrateTable(
 	}
 }
 
+// schemaChangeExists returns whether storedTable already has the schema
+// changes of op. It expects all or none of the changes to exist.
+func schemaChangeExists(storedTable, expectedTable catalog.TableDescriptor, op operation) (bool, error) {
+	var exists bool
+	for i, schemaName := range op.schemaList {
+		hasSchema, err := op.schemaExistsFn(storedTable, expectedTable, schemaName)
+		if err != nil {
+			return false, errors.Wrapf(err, "error while validating descriptors during"+
+				" operation %s", op.name)
+		}
+		if i > 0 && exists != hasSchema {
+			return false, errors.Errorf("error while validating descriptors. observed"+
+				" partial schema exists while performing %v", op.name)
+		}
+		exists = hasSchema
+	}
+	return exists, nil
+}
+
+// dryRunMigrateTable estimates the impact of running migrateTable with each of
+// the given operations, without performing them. Operations whose schema
+// changes already exist are skipped, as migrateTable would. If any operation
+// remains, the table is reported along with its span and size, since adding
+// columns or indexes backfills the table.
+func dryRunMigrateTable(
+	ctx context.Context,
+	d upgrade.TenantDeps,
+	ops []operation,
+	storedTableID descpb.ID,
+	expectedTable catalog.TableDescriptor,
+) (upgradebase.ImpactReport, error) {
+	var report upgradebase.ImpactReport
+	storedTable, err := readTableDescriptor(ctx, d, storedTableID)
+	if err != nil {
+		return report, err
+	}
+	for _, op := range ops {
+		exists, err := schemaChangeExists(storedTable, expectedTable, op)
+		if err != nil {
+			return report, err
+		}
+		if !exists {
+			report.Details = append(report.Details, strings.Join(strings.Fields(op.query), " "))
+		}
+	}
+	if len(report.Details) == 0 {
+		return report, nil
+	}
+
+	report.Descriptors = []descpb.ID{storedTableID}
+	report.Spans = []roachpb.Span{storedTable.TableSpan(d.Codec)}
+	tableName := tree.NewTableNameWithSchema(
+		catconstants.SystemDatabaseName, catconstants.PublicSchemaName, tree.Name(storedTable.GetName()),
+	)
+	for _, c := range []struct {
+		opName string
+		query  string
+		count  *int64
+	}{
+		{"upgrade-dry-run-count-rows", "SELECT count(*) FROM %s", &report.EstimatedRows},
+		{"upgrade-dry-run-count-ranges", "SELECT count(*) FROM [SHOW RANGES FROM TABLE %s]", &report.EstimatedRanges},
+	} {
+		row, err := d.InternalExecutor.QueryRowEx(ctx, c.opName, nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride, fmt.Sprintf(c.query, tableName.FQString()))
+		if err != nil {
+			return report, err
+		}
+		*c.count = int64(tree.MustBeDInt(row[0]))
+	}
+	return report, nil
+}
+
 func readTableDescriptor(
 	ctx context.Context, d upgrade.TenantDeps, tableID descpb.ID,
 ) (catalog.TableDescriptor, error) {
@@ -130,7 +130,7 @@ var upgrades = []upgradebase.Upgrade{
 		upgrade.NoPrecondition,
 		stmtDiagRedactedMigration,
 		upgrade.RestoreActionNotRequired("cluster restore does not restore this table"),
-	),
+	).WithDryRun(stmtDiagRedactedDryRun),
 
 	// Note: when starting a new release version, the first upgrade (for
 	// Vxy_zStart) must be a newFirstUpgrade. Keep this comment at the bottom.
@@ -281,6 +281,13 @@ type granterWithIOTokens interface {
 	// getDiskTokensUsedAndReset returns the disk bandwidth tokens used
 	// since the last such call.
 	getDiskTokensUsedAndReset() [admissionpb.NumWorkClasses]int64
+	// setAvailableDiskReadTokens is analogous to setAvailableTokens, for the
+	// disk read tokens used by elastic reads. It needs to be called
+	// periodically.
+	setAvailableDiskReadTokens(tokens int64, tokensCapacity int64)
+	// getDiskReadTokensUsedAndReset returns the disk read tokens used since the
+	// last such call.
+	getDiskReadTokensUsedAndReset() int64
 	// setLinearModels supplies the models to use when storeWriteDone or
 	// storeReplicatedWorkAdmittedLocked is called, to adjust token consumption.
 	// Note that these models are not used for token adjustment at admission
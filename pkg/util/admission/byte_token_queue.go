@@ -0,0 +1,136 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// byteTokenQueue is a FIFO queue of requests for byte tokens, such as disk
+// bandwidth tokens, from a granter. Unlike the WorkQueue, it has no notion of
+// tenants or priorities: requests are admitted immediately if the queue is
+// empty and the granter has tokens, and are otherwise granted in order. It is
+// the requester shared by the SnapshotQueue and the DiskReadQueue, which embed
+// it and decide how many tokens each request needs.
+type byteTokenQueue struct {
+	granter granter
+	metrics byteTokenQueueMetrics
+	mu      struct {
+		syncutil.Mutex
+		// q is the FIFO queue of waiting requests.
+		q []*byteTokenWorkItem
+	}
+}
+
+var _ requester = &byteTokenQueue{}
+
+// byteTokenQueueMetrics are the metrics maintained by a byteTokenQueue. They
+// point into the exported metric struct of the queue embedding it.
+type byteTokenQueueMetrics struct {
+	requested       *metric.Counter
+	admitted        *metric.Counter
+	errored         *metric.Counter
+	waitDurations   metric.IHistogram
+	waitQueueLength *metric.Gauge
+}
+
+// byteTokenWorkItem is a request waiting in the byteTokenQueue.
+type byteTokenWorkItem struct {
+	count int64
+	// grantCh is closed once the request has been granted.
+	grantCh chan struct{}
+	// granted is protected by byteTokenQueue.mu.
+	granted bool
+}
+
+func makeByteTokenQueue(g granter, metrics byteTokenQueueMetrics) byteTokenQueue {
+	return byteTokenQueue{
+		granter: g,
+		metrics: metrics,
+	}
+}
+
+// admit blocks until count tokens are granted, or the context is canceled.
+func (q *byteTokenQueue) admit(ctx context.Context, count int64) error {
+	q.metrics.requested.Inc(1)
+	q.mu.Lock()
+	empty := len(q.mu.q) == 0
+	q.mu.Unlock()
+	// NB: tryGet must not be called while holding q.mu, since the granter calls
+	// into the queue while holding the GrantCoordinator's mutex.
+	if empty && q.granter.tryGet(count) {
+		q.metrics.admitted.Inc(1)
+		return nil
+	}
+
+	startTime := timeutil.Now()
+	item := &byteTokenWorkItem{count: count, grantCh: make(chan struct{})}
+	q.mu.Lock()
+	q.mu.q = append(q.mu.q, item)
+	q.metrics.waitQueueLength.Inc(1)
+	q.mu.Unlock()
+
+	select {
+	case <-item.grantCh:
+		q.metrics.admitted.Inc(1)
+		q.metrics.waitDurations.RecordValue(timeutil.Since(startTime).Nanoseconds())
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		granted := item.granted
+		if !granted {
+			for i := range q.mu.q {
+				if q.mu.q[i] == item {
+					q.mu.q = append(q.mu.q[:i], q.mu.q[i+1:]...)
+					q.metrics.waitQueueLength.Dec(1)
+					break
+				}
+			}
+		}
+		q.mu.Unlock()
+		if granted {
+			// The grant raced with the cancellation, so hand the tokens back to
+			// let them be used by other waiting work.
+			q.granter.returnGrant(count)
+		}
+		q.metrics.errored.Inc(1)
+		return ctx.Err()
+	}
+}
+
+// hasWaitingRequests implements requester.
+func (q *byteTokenQueue) hasWaitingRequests() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.mu.q) > 0
+}
+
+// granted implements requester.
+func (q *byteTokenQueue) granted(grantChainID) int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.mu.q) == 0 {
+		return 0
+	}
+	item := q.mu.q[0]
+	q.mu.q = q.mu.q[1:]
+	q.metrics.waitQueueLength.Dec(1)
+	item.granted = true
+	close(item.grantCh)
+	return item.count
+}
+
+// close implements requester.
+func (q *byteTokenQueue) close() {}
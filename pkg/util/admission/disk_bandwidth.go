@@ -26,8 +26,10 @@ import (
 // - There is a provisioned limit on the sum of read and write bandwidth. This
 //   limit is allowed to change. This is true for block devices of major cloud
 //   providers.
-// - Admission control can only shape the rate of admission of writes. Writes
-//   also cause reads, since compactions do reads and writes.
+// - Admission control mostly shapes the rate of admission of writes. Writes
+//   also cause reads, since compactions do reads and writes. Elastic reads
+//   (e.g. the export requests issued by backups) are also shaped, see
+//   diskReadBandwidthLimiter.
 //
 // There are multiple challenges:
 // - We are unable to precisely track the causes of disk read bandwidth, since
@@ -35,7 +37,9 @@ import (
 //   That is we don't know how much of the reads were due to incoming reads
 //   (that we don't shape) and how much due to compaction read bandwidth.
 //
-// - We don't shape incoming reads.
+// - We don't shape incoming reads, other than elastic ones. The bytes read
+//   by elastic reads are not known at admission time, and are estimated (see
+//   DiskReadQueue).
 //
 // - There can be a large lag (1+min) between the shaping of incoming writes,
 //   and when it affects actual writes in the system, since compaction backlog
@@ -365,3 +369,88 @@ func (d *diskBandwidthLimiter) SafeFormat(p redact.SafePrinter, _ rune) {
 		ib(int64(d.state.smoothedIncomingBytes)), ib(d.state.prevElasticTokensUsed),
 		ib(d.state.elasticTokens))
 }
+
+// diskReadBandwidthLimiter produces disk read bandwidth tokens for elastic
+// reads, such as the export requests issued by backups, or low priority
+// internal scans. These reads can consume a large fraction of the provisioned
+// bandwidth, which results in high tail latency for foreground work. Unlike
+// the tokens computed by diskBandwidthLimiter, each token represents 1 byte
+// read from disk, i.e., not served from the block cache.
+//
+// The tokens are computed using the load level of the diskLoadWatcher of the
+// diskBandwidthLimiter, and the same small multiplicative increase and large
+// multiplicative decrease approach.
+type diskReadBandwidthLimiter struct {
+	level               diskLoadLevel
+	elasticReadTokens   int64
+	prevElasticReadUsed int64
+}
+
+func makeDiskReadBandwidthLimiter() diskReadBandwidthLimiter {
+	return diskReadBandwidthLimiter{
+		elasticReadTokens: math.MaxInt64,
+	}
+}
+
+// computeElasticReadTokens is called every adjustmentInterval, after
+// diskBandwidthLimiter.computeElasticTokens, with the load level computed by
+// the latter. elasticReadTokensUsed is the number of bytes read by elastic
+// reads in the interval.
+func (d *diskReadBandwidthLimiter) computeElasticReadTokens(
+	ctx context.Context, ll diskLoadLevel, id intervalDiskLoadInfo, elasticReadTokensUsed int64,
+) (elasticReadTokens int64) {
+	prevTokens := d.elasticReadTokens
+	doLog := true
+	switch ll {
+	case diskLoadLow:
+		elasticReadTokens = math.MaxInt64
+		if elasticReadTokens == prevTokens {
+			doLog = false
+		}
+	case diskLoadModerate:
+		tokensFullyUtilized := prevTokens == math.MaxInt64 ||
+			(prevTokens > 0 && float64(elasticReadTokensUsed)/float64(prevTokens) >= 0.8)
+		if tokensFullyUtilized {
+			// Give elastic reads the headroom up to 70% utilization of the
+			// provisioned bandwidth, after excluding the bandwidth used by
+			// everything else, and at least a 10% increase over what they used.
+			// The 70% corresponds to the moderate utilization threshold of the
+			// diskLoadWatcher.
+			otherBandwidth := id.readBandwidth + id.writeBandwidth -
+				elasticReadTokensUsed/adjustmentInterval
+			headroomBandwidth := 0.7*float64(id.provisionedBandwidth) - float64(max(0, otherBandwidth))
+			headroomBytes := headroomBandwidth * adjustmentInterval
+			elasticReadTokens = int64(math.Max(headroomBytes, 1.1*float64(elasticReadTokensUsed)))
+		} else {
+			elasticReadTokens = prevTokens
+		}
+	case diskLoadHigh:
+		// No change, unless the tokens were unlimited, in which case we hold
+		// steady at what was used.
+		elasticReadTokens = prevTokens
+		if elasticReadTokens == math.MaxInt64 {
+			elasticReadTokens = elasticReadTokensUsed
+		}
+	case diskLoadOverload:
+		elasticReadTokens = int64(0.5 * math.Min(float64(elasticReadTokensUsed), float64(prevTokens)))
+	}
+	// Give out at least 1 token, so that we don't stop admitting elastic reads
+	// altogether, which would prevent the per-read estimates from being
+	// corrected.
+	elasticReadTokens = max(1, elasticReadTokens)
+	*d = diskReadBandwidthLimiter{
+		level:               ll,
+		elasticReadTokens:   elasticReadTokens,
+		prevElasticReadUsed: elasticReadTokensUsed,
+	}
+	if doLog {
+		log.Infof(ctx, "%v", d)
+	}
+	return elasticReadTokens
+}
+
+func (d *diskReadBandwidthLimiter) SafeFormat(p redact.SafePrinter, _ rune) {
+	ib := humanizeutil.IBytes
+	p.Printf("diskReadBandwidthLimiter %s: elastic-read-tokens (used %s): %s",
+		diskLoadLevelString(d.level), ib(d.prevElasticReadUsed), ib(d.elasticReadTokens))
+}
@@ -101,3 +101,52 @@ func TestDiskBandwidthLimiter(t *testing.T) {
 			}
 		})
 }
+
+func TestDiskReadBandwidthLimiter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var drl diskReadBandwidthLimiter
+	drlToString := func() string {
+		return string(redact.Sprint(&drl))
+	}
+	levels := map[string]diskLoadLevel{
+		"low":      diskLoadLow,
+		"moderate": diskLoadModerate,
+		"high":     diskLoadHigh,
+		"overload": diskLoadOverload,
+	}
+
+	datadriven.RunTest(t, datapathutils.TestDataPath(t, "disk_read_bandwidth_limiter"),
+		func(t *testing.T, d *datadriven.TestData) string {
+			switch d.Cmd {
+			case "init":
+				drl = makeDiskReadBandwidthLimiter()
+				return drlToString()
+
+			case "compute":
+				var levelStr string
+				d.ScanArgs(t, "level", &levelStr)
+				level, ok := levels[levelStr]
+				if !ok {
+					return fmt.Sprintf("unknown level: %s", levelStr)
+				}
+				var readBandwidth, writeBandwidth, provisionedBandwidth, readTokensUsed int
+				d.ScanArgs(t, "read-bw", &readBandwidth)
+				d.ScanArgs(t, "write-bw", &writeBandwidth)
+				d.ScanArgs(t, "provisioned-bw", &provisionedBandwidth)
+				d.ScanArgs(t, "read-tokens-used", &readTokensUsed)
+				diskLoad := intervalDiskLoadInfo{
+					readBandwidth:        int64(readBandwidth),
+					writeBandwidth:       int64(writeBandwidth),
+					provisionedBandwidth: int64(provisionedBandwidth),
+				}
+				drl.computeElasticReadTokens(
+					context.Background(), level, diskLoad, int64(readTokensUsed))
+				return drlToString()
+
+			default:
+				return fmt.Sprintf("unknown command: %s", d.Cmd)
+			}
+		})
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// Elastic reads, such as the export requests issued by backups, or low
+// priority internal scans, can consume a large fraction of the disk bandwidth
+// of a store, which results in high tail latency for foreground work.
+//
+// The DiskReadQueue paces these reads using the elastic disk read tokens of
+// the store's kvStoreTokenGranter, which are computed by the
+// diskReadBandwidthLimiter. Each token represents a byte read from disk, i.e.,
+// not served from the block cache. Since the number of bytes a read will
+// fetch from disk is not known at admission time, we deduct an estimate,
+// which is an exponentially smoothed value of the bytes read by prior reads.
+// When the read is done, the actual bytes read, as reported by Pebble's
+// iterator stats, are used to adjust the tokens and the estimate. Like disk
+// bandwidth tokens for writes, disk read tokens are only limited once the
+// provisioned bandwidth of the store is configured, so reads are otherwise
+// admitted without waiting. Waiting requests are granted in FIFO order, after
+// waiting regular and elastic writes, and snapshot writes.
+
+// diskReadAdmissionEnabled controls whether elastic reads are subject to disk
+// read bandwidth admission control. The default is true since pacing only
+// kicks in once disk bandwidth based admission control is configured.
+var diskReadAdmissionEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"admission.disk_read.enabled",
+	"when true, and provisioned bandwidth for the disk corresponding to a store is configured, "+
+		"elastic reads, such as those issued by backups, are paced using disk read bandwidth tokens",
+	true,
+)
+
+const (
+	// initialDiskReadEstimate is the estimate of the bytes read from disk per
+	// read, before any reads have completed.
+	initialDiskReadEstimate = 64 << 10 // 64 KiB
+	// diskReadEstimateAlpha is the weight given to the latest read when
+	// smoothing the estimate.
+	diskReadEstimateAlpha = 0.2
+)
+
+// DiskReadQueue is the requester for elastic reads from a store. It is created
+// by StoreGrantCoordinators for each store.
+type DiskReadQueue struct {
+	byteTokenQueue
+	settings *cluster.Settings
+	metrics  *DiskReadQueueMetrics
+	estimate struct {
+		syncutil.Mutex
+		// bytesPerRead is the smoothed number of bytes read from disk per read,
+		// used as the number of tokens to deduct at admission.
+		bytesPerRead float64
+	}
+}
+
+var _ requester = &DiskReadQueue{}
+
+// DiskReadHandle is returned by DiskReadQueue.Admit, and is used to record the
+// bytes read from disk by the admitted read. It is safe for concurrent use.
+type DiskReadHandle struct {
+	// tokens is the number of tokens deducted at admission.
+	tokens int64
+	// bytesRead is the number of bytes read from disk so far.
+	bytesRead atomic.Int64
+}
+
+// RecordBytesRead records that n bytes were read from disk. It is a no-op on
+// a nil handle.
+func (h *DiskReadHandle) RecordBytesRead(n int64) {
+	if h == nil || n <= 0 {
+		return
+	}
+	h.bytesRead.Add(n)
+}
+
+type diskReadHandleKey struct{}
+
+// ContextWithDiskReadHandle returns a Context wrapping the supplied disk read
+// handle, if any.
+func ContextWithDiskReadHandle(ctx context.Context, h *DiskReadHandle) context.Context {
+	if h == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, diskReadHandleKey{}, h)
+}
+
+// DiskReadHandleFromContext returns the disk read handle contained in the
+// Context, if any.
+func DiskReadHandleFromContext(ctx context.Context) *DiskReadHandle {
+	h, _ := ctx.Value(diskReadHandleKey{}).(*DiskReadHandle)
+	return h
+}
+
+func makeDiskReadQueue(
+	st *cluster.Settings, g granter, metrics *DiskReadQueueMetrics,
+) *DiskReadQueue {
+	q := &DiskReadQueue{
+		byteTokenQueue: makeByteTokenQueue(g, byteTokenQueueMetrics{
+			requested:       metrics.Requested,
+			admitted:        metrics.Admitted,
+			errored:         metrics.Errored,
+			waitDurations:   metrics.WaitDurations,
+			waitQueueLength: metrics.WaitQueueLength,
+		}),
+		settings: st,
+		metrics:  metrics,
+	}
+	q.estimate.bytesPerRead = initialDiskReadEstimate
+	return q
+}
+
+// Admit is called before performing an elastic read. It blocks until the read
+// is admitted, or the context is canceled. If the returned handle is non-nil,
+// AdmittedReadDone must be called once the read is done. It is a no-op on a
+// nil DiskReadQueue.
+func (q *DiskReadQueue) Admit(ctx context.Context) (*DiskReadHandle, error) {
+	if q == nil || !diskReadAdmissionEnabled.Get(&q.settings.SV) {
+		return nil, nil
+	}
+	q.estimate.Lock()
+	count := max(1, int64(q.estimate.bytesPerRead))
+	q.estimate.Unlock()
+	if err := q.admit(ctx, count); err != nil {
+		return nil, err
+	}
+	return &DiskReadHandle{tokens: count}, nil
+}
+
+// AdmittedReadDone is called when a read admitted by Admit is done. The tokens
+// deducted at admission are adjusted to match the bytes that were actually
+// read from disk, and the estimate for future reads is updated.
+func (q *DiskReadQueue) AdmittedReadDone(h *DiskReadHandle) {
+	if q == nil || h == nil {
+		return
+	}
+	bytesRead := h.bytesRead.Load()
+	q.metrics.ReadBytes.Inc(bytesRead)
+	if diff := bytesRead - h.tokens; diff > 0 {
+		q.granter.tookWithoutPermission(diff)
+	} else if diff < 0 {
+		q.granter.returnGrant(-diff)
+	}
+	q.estimate.Lock()
+	defer q.estimate.Unlock()
+	q.estimate.bytesPerRead = diskReadEstimateAlpha*float64(bytesRead) +
+		(1-diskReadEstimateAlpha)*q.estimate.bytesPerRead
+}
+
+var (
+	diskReadRequestedMeta = metric.Metadata{
+		Name:        "admission.disk_read.requested",
+		Help:        "Number of elastic reads that requested disk read bandwidth admission",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	diskReadAdmittedMeta = metric.Metadata{
+		Name:        "admission.disk_read.admitted",
+		Help:        "Number of elastic reads that were admitted using disk read bandwidth tokens",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	diskReadErroredMeta = metric.Metadata{
+		Name:        "admission.disk_read.errored",
+		Help:        "Number of elastic reads that were canceled while waiting for disk read bandwidth admission",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	diskReadBytesMeta = metric.Metadata{
+		Name:        "admission.disk_read.read_bytes",
+		Help:        "Number of bytes read from disk (excluding block cache hits) by admitted elastic reads",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	diskReadWaitDurationsMeta = metric.Metadata{
+		Name:        "admission.disk_read.wait_durations",
+		Help:        "Wait time durations for elastic reads that waited for disk read bandwidth admission",
+		Measurement: "Wait time Duration",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	diskReadWaitQueueLengthMeta = metric.Metadata{
+		Name:        "admission.disk_read.wait_queue_length",
+		Help:        "Number of elastic reads waiting for disk read bandwidth admission",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+// DiskReadQueueMetrics are the metrics associated with the DiskReadQueues of
+// all stores.
+type DiskReadQueueMetrics struct {
+	Requested       *metric.Counter
+	Admitted        *metric.Counter
+	Errored         *metric.Counter
+	ReadBytes       *metric.Counter
+	WaitDurations   metric.IHistogram
+	WaitQueueLength *metric.Gauge
+}
+
+func makeDiskReadQueueMetrics(registry *metric.Registry) *DiskReadQueueMetrics {
+	m := &DiskReadQueueMetrics{
+		Requested: metric.NewCounter(diskReadRequestedMeta),
+		Admitted:  metric.NewCounter(diskReadAdmittedMeta),
+		Errored:   metric.NewCounter(diskReadErroredMeta),
+		ReadBytes: metric.NewCounter(diskReadBytesMeta),
+		WaitDurations: metric.NewHistogram(metric.HistogramOptions{
+			Mode:         metric.HistogramModePreferHdrLatency,
+			Metadata:     diskReadWaitDurationsMeta,
+			Duration:     base.DefaultHistogramWindowInterval(),
+			BucketConfig: metric.IOLatencyBuckets,
+		}),
+		WaitQueueLength: metric.NewGauge(diskReadWaitQueueLengthMeta),
+	}
+	registry.AddMetricStruct(m)
+	return m
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*DiskReadQueueMetrics) MetricStruct() {}
+
+var _ metric.Struct = &DiskReadQueueMetrics{}
@@ -0,0 +1,140 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskReadQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	var buf strings.Builder
+	coord := &GrantCoordinator{settings: st}
+	coord.mu.numProcs = 1
+	kvg := &kvStoreTokenGranter{coord: coord}
+	kvg.regularRequester = &testRequester{workKind: KVWork, buf: &buf}
+	kvg.elasticRequester = &testRequester{workKind: KVWork, buf: &buf}
+	coord.granters[KVWork] = kvg
+	metrics := makeDiskReadQueueMetrics(metric.NewRegistry())
+	q := makeDiskReadQueue(st, &kvStoreDiskReadGranter{parent: kvg}, metrics)
+	kvg.diskReadRequester = q
+
+	setDiskReadTokens := func(tokens int64) {
+		coord.mu.Lock()
+		defer coord.mu.Unlock()
+		kvg.coordMu.elasticDiskReadTokensAvailable = tokens
+	}
+	diskReadTokensUsed := func() int64 {
+		coord.mu.Lock()
+		defer coord.mu.Unlock()
+		return kvg.coordMu.diskReadTokensUsed
+	}
+
+	// Reads are admitted without waiting while disk read tokens are available,
+	// and initially deduct the initial estimate.
+	setDiskReadTokens(10)
+	h, err := q.Admit(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(initialDiskReadEstimate), diskReadTokensUsed())
+
+	// When done, the tokens are adjusted to the bytes read from disk, and the
+	// estimate moves towards it.
+	h.RecordBytesRead(4 << 10)
+	h.RecordBytesRead(4 << 10)
+	q.AdmittedReadDone(h)
+	require.Equal(t, int64(8<<10), diskReadTokensUsed())
+	require.Equal(t, int64(8<<10), metrics.ReadBytes.Count())
+	bytesRead, initialEstimate := float64(8<<10), float64(initialDiskReadEstimate)
+	expectedEstimate := int64(0.2*bytesRead + 0.8*initialEstimate)
+	q.estimate.Lock()
+	require.Equal(t, expectedEstimate, int64(q.estimate.bytesPerRead))
+	q.estimate.Unlock()
+
+	// Once the tokens are exhausted, reads wait in FIFO order.
+	setDiskReadTokens(0)
+	admitted := make(chan *DiskReadHandle, 2)
+	admit := func() {
+		go func() {
+			h, err := q.Admit(ctx)
+			if err != nil {
+				panic(err)
+			}
+			admitted <- h
+		}()
+	}
+	admit()
+	testutils.SucceedsSoon(t, func() error {
+		if metrics.WaitQueueLength.Value() != 1 {
+			return errors.New("waiting for first request to queue")
+		}
+		return nil
+	})
+	admit()
+	testutils.SucceedsSoon(t, func() error {
+		if metrics.WaitQueueLength.Value() != 2 {
+			return errors.New("waiting for second request to queue")
+		}
+		return nil
+	})
+	select {
+	case <-admitted:
+		t.Fatal("request admitted without tokens")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Making a single token available admits one request at a time.
+	setDiskReadTokens(1)
+	coord.testingTryGrant()
+	h1 := <-admitted
+	require.Equal(t, int64(1), metrics.WaitQueueLength.Value())
+	setDiskReadTokens(1)
+	coord.testingTryGrant()
+	h2 := <-admitted
+	require.Equal(t, int64(8<<10+2*expectedEstimate), diskReadTokensUsed())
+	// Reads served from the block cache return the tokens.
+	q.AdmittedReadDone(h1)
+	q.AdmittedReadDone(h2)
+	require.Equal(t, int64(8<<10), diskReadTokensUsed())
+
+	// A canceled request leaves the queue without consuming tokens.
+	setDiskReadTokens(0)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = q.Admit(cancelCtx)
+	require.Error(t, err)
+	require.Equal(t, int64(0), metrics.WaitQueueLength.Value())
+	require.Equal(t, int64(8<<10), diskReadTokensUsed())
+	require.Equal(t, int64(1), metrics.Errored.Count())
+	require.Equal(t, int64(3), metrics.Admitted.Count())
+
+	// When disabled, reads are admitted without a handle.
+	diskReadAdmissionEnabled.Override(ctx, &st.SV, false)
+	h, err = q.Admit(ctx)
+	require.NoError(t, err)
+	require.Nil(t, h)
+	q.AdmittedReadDone(h)
+	require.Equal(t, int64(8<<10), diskReadTokensUsed())
+}
@@ -62,6 +62,10 @@ type StoreGrantCoordinators struct {
 
 	// These metrics are shared by WorkQueues across stores.
 	workQueueMetrics [admissionpb.NumWorkClasses]*WorkQueueMetrics
+	// These metrics are shared by SnapshotQueues across stores.
+	snapshotQueueMetrics *SnapshotQueueMetrics
+	// These metrics are shared by DiskReadQueues across stores.
+	diskReadQueueMetrics *DiskReadQueueMetrics
 
 	gcMap syncutil.IntMap // map[int64(StoreID)]*GrantCoordinator
 	// numStores is used to track the number of stores which have been added
@@ -178,6 +182,7 @@ func (sgc *StoreGrantCoordinators) initGrantCoordinator(storeID roachpb.StoreID)
 	kvg.coordMu.availableIOTokens[admissionpb.RegularWorkClass] = unlimitedTokens / unloadedDuration.ticksInAdjustmentInterval()
 	kvg.coordMu.availableIOTokens[admissionpb.ElasticWorkClass] = kvg.coordMu.availableIOTokens[admissionpb.RegularWorkClass]
 	kvg.coordMu.elasticDiskBWTokensAvailable = unlimitedTokens / unloadedDuration.ticksInAdjustmentInterval()
+	kvg.coordMu.elasticDiskReadTokensAvailable = unlimitedTokens / unloadedDuration.ticksInAdjustmentInterval()
 
 	opts := makeWorkQueueOptions(KVWork)
 	// This is IO work, so override the usesTokens value.
@@ -211,12 +216,19 @@ func (sgc *StoreGrantCoordinators) initGrantCoordinator(storeID roachpb.StoreID)
 	kvg.regularRequester = requesters[admissionpb.RegularWorkClass]
 	kvg.elasticRequester = requesters[admissionpb.ElasticWorkClass]
 	coord.granters[KVWork] = kvg
+	coord.snapshotQueue = makeSnapshotQueue(
+		sgc.settings, &kvStoreSnapshotGranter{parent: kvg}, sgc.snapshotQueueMetrics)
+	kvg.snapshotRequester = coord.snapshotQueue
+	coord.diskReadQueue = makeDiskReadQueue(
+		sgc.settings, &kvStoreDiskReadGranter{parent: kvg}, sgc.diskReadQueueMetrics)
+	kvg.diskReadRequester = coord.diskReadQueue
 	coord.ioLoadListener = &ioLoadListener{
 		storeID:               storeID,
 		settings:              sgc.settings,
 		kvRequester:           storeReq,
 		perWorkTokenEstimator: makeStorePerWorkTokenEstimator(),
 		diskBandwidthLimiter:  makeDiskBandwidthLimiter(),
+		diskReadLimiter:       makeDiskReadBandwidthLimiter(),
 		kvGranter:             kvg,
 		l0CompactedBytes:      sgc.l0CompactedBytes,
 		l0TokensProduced:      sgc.l0TokensProduced,
@@ -234,6 +246,26 @@ func (sgc *StoreGrantCoordinators) TryGetQueueForStore(storeID int32) *StoreWork
 	return nil
 }
 
+// TryGetSnapshotQueueForStore returns the SnapshotQueue for the given storeID,
+// or nil if the storeID is not known.
+func (sgc *StoreGrantCoordinators) TryGetSnapshotQueueForStore(storeID int32) *SnapshotQueue {
+	if unsafeGranter, ok := sgc.gcMap.Load(int64(storeID)); ok {
+		granter := (*GrantCoordinator)(unsafeGranter)
+		return granter.snapshotQueue
+	}
+	return nil
+}
+
+// TryGetDiskReadQueueForStore returns the DiskReadQueue for the given storeID,
+// or nil if the storeID is not known.
+func (sgc *StoreGrantCoordinators) TryGetDiskReadQueueForStore(storeID int32) *DiskReadQueue {
+	if unsafeGranter, ok := sgc.gcMap.Load(int64(storeID)); ok {
+		granter := (*GrantCoordinator)(unsafeGranter)
+		return granter.diskReadQueue
+	}
+	return nil
+}
+
 func (sgc *StoreGrantCoordinators) close() {
 	// closeCh can be nil in tests that never called SetPebbleMetricsProvider.
 	if sgc.closeCh != nil {
@@ -297,6 +329,12 @@ type GrantCoordinator struct {
 	// This is kept separately only to service GetWorkQueue calls and to call
 	// close().
 	queues [numWorkKinds]requesterClose
+	// snapshotQueue is the requester for incoming snapshot writes. It is only
+	// set for the per-store GrantCoordinators.
+	snapshotQueue *SnapshotQueue
+	// diskReadQueue is the requester for elastic reads. It is only set for the
+	// per-store GrantCoordinators.
+	diskReadQueue *DiskReadQueue
 
 	ioLoadListener *ioLoadListener
 
@@ -483,6 +521,8 @@ func makeStoresGrantCoordinators(
 		l0CompactedBytes:            metrics.L0CompactedBytes,
 		l0TokensProduced:            metrics.L0TokensProduced,
 		workQueueMetrics:            storeWorkQueueMetrics,
+		snapshotQueueMetrics:        makeSnapshotQueueMetrics(registry),
+		diskReadQueueMetrics:        makeDiskReadQueueMetrics(registry),
 		onLogEntryAdmitted:          onLogEntryAdmitted,
 		knobs:                       knobs,
 	}
@@ -292,6 +292,12 @@ type kvStoreTokenGranter struct {
 	coord            *GrantCoordinator
 	regularRequester requester
 	elasticRequester requester
+	// snapshotRequester is the SnapshotQueue for the store, if any. Snapshot
+	// writes only consume elastic disk bandwidth tokens.
+	snapshotRequester requester
+	// diskReadRequester is the DiskReadQueue for the store, if any. Elastic
+	// reads only consume elastic disk read tokens.
+	diskReadRequester requester
 
 	coordMu struct { // holds fields protected by coord.mu.Lock
 		// There is no rate limiting in granting these tokens. That is, they are
@@ -307,6 +313,10 @@ type kvStoreTokenGranter struct {
 		elasticDiskBWTokensAvailable int64
 
 		diskBWTokensUsed [admissionpb.NumWorkClasses]int64
+
+		// Disk read tokens, for elastic reads.
+		elasticDiskReadTokensAvailable int64
+		diskReadTokensUsed             int64
 	}
 
 	ioTokensExhaustedDurationMetric [admissionpb.NumWorkClasses]*metric.Counter
@@ -383,12 +393,104 @@ func (cg *kvStoreTokenChildGranter) storeReplicatedWorkAdmittedLocked(
 	return cg.parent.storeReplicatedWorkAdmittedLocked(cg.workClass, originalTokens, admittedInfo, false /* canGrantAnother */)
 }
 
+// snapshotIngestDemuxHandle is the demuxHandle used by the
+// kvStoreSnapshotGranter. It doesn't overlap with the handles used for work
+// classes.
+const snapshotIngestDemuxHandle = int8(admissionpb.NumWorkClasses)
+
+// kvStoreSnapshotGranter is the granter for the SnapshotQueue of a store. Its
+// methods pass-through to the parent with snapshotIngestDemuxHandle.
+type kvStoreSnapshotGranter struct {
+	parent *kvStoreTokenGranter
+}
+
+var _ granter = &kvStoreSnapshotGranter{}
+
+// grantKind implements granter.
+func (sg *kvStoreSnapshotGranter) grantKind() grantKind {
+	return token
+}
+
+// tryGet implements granter.
+func (sg *kvStoreSnapshotGranter) tryGet(count int64) bool {
+	return sg.parent.coord.tryGet(KVWork, count, snapshotIngestDemuxHandle)
+}
+
+// returnGrant implements granter.
+func (sg *kvStoreSnapshotGranter) returnGrant(count int64) {
+	sg.parent.coord.returnGrant(KVWork, count, snapshotIngestDemuxHandle)
+}
+
+// tookWithoutPermission implements granter.
+func (sg *kvStoreSnapshotGranter) tookWithoutPermission(count int64) {
+	sg.parent.coord.tookWithoutPermission(KVWork, count, snapshotIngestDemuxHandle)
+}
+
+// continueGrantChain implements granter.
+func (sg *kvStoreSnapshotGranter) continueGrantChain(grantChainID grantChainID) {
+	// Ignore since grant chains are not used for store tokens.
+}
+
+// diskReadDemuxHandle is the demuxHandle used by the kvStoreDiskReadGranter.
+const diskReadDemuxHandle = snapshotIngestDemuxHandle + 1
+
+// kvStoreDiskReadGranter is the granter for the DiskReadQueue of a store. Its
+// methods pass-through to the parent with diskReadDemuxHandle.
+type kvStoreDiskReadGranter struct {
+	parent *kvStoreTokenGranter
+}
+
+var _ granter = &kvStoreDiskReadGranter{}
+
+// grantKind implements granter.
+func (rg *kvStoreDiskReadGranter) grantKind() grantKind {
+	return token
+}
+
+// tryGet implements granter.
+func (rg *kvStoreDiskReadGranter) tryGet(count int64) bool {
+	return rg.parent.coord.tryGet(KVWork, count, diskReadDemuxHandle)
+}
+
+// returnGrant implements granter.
+func (rg *kvStoreDiskReadGranter) returnGrant(count int64) {
+	rg.parent.coord.returnGrant(KVWork, count, diskReadDemuxHandle)
+}
+
+// tookWithoutPermission implements granter.
+func (rg *kvStoreDiskReadGranter) tookWithoutPermission(count int64) {
+	rg.parent.coord.tookWithoutPermission(KVWork, count, diskReadDemuxHandle)
+}
+
+// continueGrantChain implements granter.
+func (rg *kvStoreDiskReadGranter) continueGrantChain(grantChainID grantChainID) {
+	// Ignore since grant chains are not used for store tokens.
+}
+
 func (sg *kvStoreTokenGranter) tryGet(workClass admissionpb.WorkClass, count int64) bool {
 	return sg.coord.tryGet(KVWork, count, int8(workClass))
 }
 
 // tryGetLocked implements granterWithLockedCalls.
 func (sg *kvStoreTokenGranter) tryGetLocked(count int64, demuxHandle int8) grantResult {
+	if demuxHandle == snapshotIngestDemuxHandle {
+		// Snapshot writes don't add to L0, so they only consume disk bandwidth
+		// tokens. They are accounted for as elastic disk bandwidth usage.
+		if sg.coordMu.elasticDiskBWTokensAvailable > 0 {
+			sg.coordMu.elasticDiskBWTokensAvailable -= count
+			sg.coordMu.diskBWTokensUsed[admissionpb.ElasticWorkClass] += count
+			return grantSuccess
+		}
+		return grantFailLocal
+	}
+	if demuxHandle == diskReadDemuxHandle {
+		if sg.coordMu.elasticDiskReadTokensAvailable > 0 {
+			sg.coordMu.elasticDiskReadTokensAvailable -= count
+			sg.coordMu.diskReadTokensUsed += count
+			return grantSuccess
+		}
+		return grantFailLocal
+	}
 	wc := admissionpb.WorkClass(demuxHandle)
 	// NB: ideally if regularRequester.hasWaitingRequests() returns true and
 	// wc==elasticWorkClass we should reject this request, since it means that
@@ -424,6 +526,16 @@ func (sg *kvStoreTokenGranter) returnGrant(workClass admissionpb.WorkClass, coun
 
 // returnGrantLocked implements granterWithLockedCalls.
 func (sg *kvStoreTokenGranter) returnGrantLocked(count int64, demuxHandle int8) {
+	if demuxHandle == snapshotIngestDemuxHandle {
+		sg.coordMu.elasticDiskBWTokensAvailable += count
+		sg.coordMu.diskBWTokensUsed[admissionpb.ElasticWorkClass] -= count
+		return
+	}
+	if demuxHandle == diskReadDemuxHandle {
+		sg.coordMu.elasticDiskReadTokensAvailable += count
+		sg.coordMu.diskReadTokensUsed -= count
+		return
+	}
 	wc := admissionpb.WorkClass(demuxHandle)
 	// Return count tokens to the "IO tokens".
 	sg.subtractTokensLocked(-count, -count, false)
@@ -441,6 +553,16 @@ func (sg *kvStoreTokenGranter) tookWithoutPermission(workClass admissionpb.WorkC
 
 // tookWithoutPermissionLocked implements granterWithLockedCalls.
 func (sg *kvStoreTokenGranter) tookWithoutPermissionLocked(count int64, demuxHandle int8) {
+	if demuxHandle == snapshotIngestDemuxHandle {
+		sg.coordMu.elasticDiskBWTokensAvailable -= count
+		sg.coordMu.diskBWTokensUsed[admissionpb.ElasticWorkClass] += count
+		return
+	}
+	if demuxHandle == diskReadDemuxHandle {
+		sg.coordMu.elasticDiskReadTokensAvailable -= count
+		sg.coordMu.diskReadTokensUsed += count
+		return
+	}
 	wc := admissionpb.WorkClass(demuxHandle)
 	sg.subtractTokensLocked(count, count, false)
 	if wc == admissionpb.ElasticWorkClass {
@@ -491,7 +613,9 @@ func (sg *kvStoreTokenGranter) subtractTokensLockedForWorkClass(
 
 // requesterHasWaitingRequests implements granterWithLockedCalls.
 func (sg *kvStoreTokenGranter) requesterHasWaitingRequests() bool {
-	return sg.regularRequester.hasWaitingRequests() || sg.elasticRequester.hasWaitingRequests()
+	return sg.regularRequester.hasWaitingRequests() || sg.elasticRequester.hasWaitingRequests() ||
+		(sg.snapshotRequester != nil && sg.snapshotRequester.hasWaitingRequests()) ||
+		(sg.diskReadRequester != nil && sg.diskReadRequester.hasWaitingRequests())
 }
 
 // tryGrantLocked implements granterWithLockedCalls.
@@ -503,33 +627,61 @@ func (sg *kvStoreTokenGranter) tryGrantLocked(grantChainID grantChainID) grantRe
 			req = sg.elasticRequester
 		}
 		if req.hasWaitingRequests() {
-			res := sg.tryGetLocked(1, int8(wc))
-			if res == grantSuccess {
-				tookTokenCount := req.granted(grantChainID)
-				if tookTokenCount == 0 {
-					// Did not accept grant.
-					sg.returnGrantLocked(1, int8(wc))
-					// Continue with the loop since this requester does not have waiting
-					// requests. If the loop terminates we will correctly return
-					// grantFailLocal.
-				} else {
-					// May have taken more.
-					if tookTokenCount > 1 {
-						sg.tookWithoutPermissionLocked(tookTokenCount-1, int8(wc))
-					}
-					return grantSuccess
-				}
-			} else {
+			res, accepted := sg.tryGrantToRequesterLocked(req, int8(wc), grantChainID)
+			if accepted {
+				return grantSuccess
+			}
+			if res != grantSuccess {
 				// Was not able to get token. Do not continue with looping to grant to
 				// less important work (though it would be harmless since won't be
 				// able to get a token for that either).
-				return res
+				break
 			}
+			// Continue with the loop since this requester does not have waiting
+			// requests. If the loop terminates we will correctly return
+			// grantFailLocal.
+		}
+	}
+	// Snapshot writes only need disk bandwidth tokens, so they may be granted
+	// even if the work classes above were unable to get IO tokens.
+	if sg.snapshotRequester != nil && sg.snapshotRequester.hasWaitingRequests() {
+		if _, accepted := sg.tryGrantToRequesterLocked(
+			sg.snapshotRequester, snapshotIngestDemuxHandle, grantChainID); accepted {
+			return grantSuccess
+		}
+	}
+	// Elastic reads only need disk read tokens.
+	if sg.diskReadRequester != nil && sg.diskReadRequester.hasWaitingRequests() {
+		if _, accepted := sg.tryGrantToRequesterLocked(
+			sg.diskReadRequester, diskReadDemuxHandle, grantChainID); accepted {
+			return grantSuccess
 		}
 	}
 	return grantFailLocal
 }
 
+// tryGrantToRequesterLocked tries to get a token using demuxHandle, and grant
+// it to req. accepted is true iff req accepted the grant.
+func (sg *kvStoreTokenGranter) tryGrantToRequesterLocked(
+	req requester, demuxHandle int8, grantChainID grantChainID,
+) (res grantResult, accepted bool) {
+	res = sg.tryGetLocked(1, demuxHandle)
+	if res != grantSuccess {
+		return res, false
+	}
+	tookTokenCount := req.granted(grantChainID)
+	if tookTokenCount == 0 {
+		// Did not accept grant.
+		sg.returnGrantLocked(1, demuxHandle)
+		return res, false
+	}
+	// May have taken more.
+	if tookTokenCount > 1 {
+		sg.tookWithoutPermissionLocked(tookTokenCount-1, demuxHandle)
+	}
+	return res, true
+}
+
 // setAvailableTokens implements granterWithIOTokens.
 func (sg *kvStoreTokenGranter) setAvailableTokens(
 	ioTokens int64,
@@ -596,6 +748,25 @@ func (sg *kvStoreTokenGranter) getDiskTokensUsedAndReset() [admissionpb.NumWorkC
 	return result
 }
 
+// setAvailableDiskReadTokens implements granterWithIOTokens.
+func (sg *kvStoreTokenGranter) setAvailableDiskReadTokens(tokens int64, tokensCapacity int64) {
+	sg.coord.mu.Lock()
+	defer sg.coord.mu.Unlock()
+	sg.coordMu.elasticDiskReadTokensAvailable += tokens
+	if sg.coordMu.elasticDiskReadTokensAvailable > tokensCapacity {
+		sg.coordMu.elasticDiskReadTokensAvailable = tokensCapacity
+	}
+}
+
+// getDiskReadTokensUsedAndReset implements granterWithIOTokens.
+func (sg *kvStoreTokenGranter) getDiskReadTokensUsedAndReset() int64 {
+	sg.coord.mu.Lock()
+	defer sg.coord.mu.Unlock()
+	result := sg.coordMu.diskReadTokensUsed
+	sg.coordMu.diskReadTokensUsed = 0
+	return result
+}
+
 // setAdmittedModelsLocked implements granterWithIOTokens.
 func (sg *kvStoreTokenGranter) setLinearModels(
 	l0WriteLM tokensLinearModel, l0IngestLM tokensLinearModel, ingestLM tokensLinearModel,
@@ -195,6 +195,12 @@ type ioLoadListener struct {
 	adjustTokensResult
 	perWorkTokenEstimator storePerWorkTokenEstimator
 	diskBandwidthLimiter  diskBandwidthLimiter
+	diskReadLimiter       diskReadBandwidthLimiter
+	// diskReadTokens represents the disk read tokens for elastic reads to give
+	// out until the next call to adjustTokens. They are parceled out in small
+	// intervals. diskReadTokensAllocated represents what has been given out.
+	diskReadTokens          int64
+	diskReadTokensAllocated int64
 
 	l0CompactedBytes *metric.Counter
 	l0TokensProduced *metric.Counter
@@ -495,6 +501,7 @@ func (io *ioLoadListener) pebbleMetricsTick(ctx context.Context, metrics StoreMe
 		io.diskBW.bytesRead = metrics.DiskStats.BytesRead
 		io.diskBW.bytesWritten = metrics.DiskStats.BytesWritten
 		io.diskBW.incomingLSMBytes = cumLSMIncomingBytes
+		io.diskReadTokens = unlimitedTokens
 		io.copyAuxEtcFromPerWorkEstimator()
 
 		// Assume system starts off unloaded.
@@ -572,6 +579,13 @@ func (io *ioLoadListener) allocateTokensTick(remainingTicks int64) {
 			"tokens allocated is negative %d", io.elasticByteTokensAllocated))
 	}
 	io.elasticDiskBWTokensAllocated += toAllocateElasticDiskBWTokens
+	toAllocateDiskReadTokens :=
+		allocateFunc(io.diskReadTokens, io.diskReadTokensAllocated, remainingTicks)
+	if toAllocateDiskReadTokens < 0 {
+		panic(errors.AssertionFailedf("toAllocateDiskReadTokens is negative %d",
+			toAllocateDiskReadTokens))
+	}
+	io.diskReadTokensAllocated += toAllocateDiskReadTokens
 
 	tokensMaxCapacity := allocateFunc(
 		io.totalNumByteTokens, 0, unloadedDuration.ticksInAdjustmentInterval(),
@@ -592,6 +606,9 @@ func (io *ioLoadListener) allocateTokensTick(remainingTicks int64) {
 	)
 	io.byteTokensUsed += tokensUsed
 	io.byteTokensUsedByElasticWork += tokensUsedByElasticWork
+	diskReadTokenMaxCapacity := allocateFunc(
+		io.diskReadTokens, 0, unloadedDuration.ticksInAdjustmentInterval())
+	io.kvGranter.setAvailableDiskReadTokens(toAllocateDiskReadTokens, diskReadTokenMaxCapacity)
 }
 
 func computeIntervalDiskLoadInfo(
@@ -640,6 +657,7 @@ func (io *ioLoadListener) adjustTokens(ctx context.Context, metrics StoreMetrics
 		io.aux.diskBW.intervalDiskLoadInfo = computeIntervalDiskLoadInfo(
 			cumDiskBW.bytesRead, cumDiskBW.bytesWritten, metrics.DiskStats)
 		diskTokensUsed := io.kvGranter.getDiskTokensUsedAndReset()
+		diskReadTokensUsed := io.kvGranter.getDiskReadTokensUsedAndReset()
 		io.aux.diskBW.intervalLSMInfo = intervalLSMInfo{
 			incomingBytes:     int64(cumLSMIncomingBytes) - int64(cumDiskBW.incomingLSMBytes),
 			regularTokensUsed: diskTokensUsed[admissionpb.RegularWorkClass],
@@ -649,10 +667,16 @@ func (io *ioLoadListener) adjustTokens(ctx context.Context, metrics StoreMetrics
 			io.elasticDiskBWTokens = io.diskBandwidthLimiter.computeElasticTokens(ctx,
 				io.aux.diskBW.intervalDiskLoadInfo, io.aux.diskBW.intervalLSMInfo)
 			io.elasticDiskBWTokensAllocated = 0
+			// The disk read tokens use the load level computed above.
+			io.diskReadTokens = io.diskReadLimiter.computeElasticReadTokens(ctx,
+				io.diskBandwidthLimiter.diskLoadWatcher.getLoadLevel(),
+				io.aux.diskBW.intervalDiskLoadInfo, diskReadTokensUsed)
+			io.diskReadTokensAllocated = 0
 		}
 		if metrics.DiskStats.ProvisionedBandwidth == 0 ||
 			!DiskBandwidthTokensForElasticEnabled.Get(&io.settings.SV) {
 			io.elasticDiskBWTokens = unlimitedTokens
+			io.diskReadTokens = unlimitedTokens
 		}
 		io.diskBW.bytesRead = metrics.DiskStats.BytesRead
 		io.diskBW.bytesWritten = metrics.DiskStats.BytesWritten
@@ -60,6 +60,7 @@ func TestIOLoadListener(t *testing.T) {
 					kvRequester:           req,
 					perWorkTokenEstimator: makeStorePerWorkTokenEstimator(),
 					diskBandwidthLimiter:  makeDiskBandwidthLimiter(),
+					diskReadLimiter:       makeDiskReadBandwidthLimiter(),
 					l0CompactedBytes:      metric.NewCounter(l0CompactedBytes),
 					l0TokensProduced:      metric.NewCounter(l0TokensProduced),
 				}
@@ -369,6 +370,7 @@ func TestBadIOLoadListenerStats(t *testing.T) {
 		kvRequester:           req,
 		perWorkTokenEstimator: makeStorePerWorkTokenEstimator(),
 		diskBandwidthLimiter:  makeDiskBandwidthLimiter(),
+		diskReadLimiter:       makeDiskReadBandwidthLimiter(),
 		l0CompactedBytes:      metric.NewCounter(l0CompactedBytes),
 		l0TokensProduced:      metric.NewCounter(l0TokensProduced),
 	}
@@ -451,6 +453,12 @@ func (g *testGranterWithIOTokens) getDiskTokensUsedAndReset() [admissionpb.NumWo
 	return g.diskBandwidthTokensUsed
 }
 
+func (g *testGranterWithIOTokens) setAvailableDiskReadTokens(int64, int64) {}
+
+func (g *testGranterWithIOTokens) getDiskReadTokensUsedAndReset() int64 {
+	return 0
+}
+
 func (g *testGranterWithIOTokens) setLinearModels(
 	l0WriteLM tokensLinearModel, l0IngestLM tokensLinearModel, ingestLM tokensLinearModel,
 ) {
@@ -497,6 +505,17 @@ func (g *testGranterNonNegativeTokens) getDiskTokensUsedAndReset() [admissionpb.
 	return [admissionpb.NumWorkClasses]int64{}
 }
 
+func (g *testGranterNonNegativeTokens) setAvailableDiskReadTokens(
+	tokens int64, tokensCapacity int64,
+) {
+	require.LessOrEqual(g.t, int64(0), tokens)
+	require.LessOrEqual(g.t, int64(0), tokensCapacity)
+}
+
+func (g *testGranterNonNegativeTokens) getDiskReadTokensUsedAndReset() int64 {
+	return 0
+}
+
 func (g *testGranterNonNegativeTokens) setLinearModels(
 	l0WriteLM tokensLinearModel, l0IngestLM tokensLinearModel, ingestLM tokensLinearModel,
 ) {
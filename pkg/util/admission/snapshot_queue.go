@@ -0,0 +1,158 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+// Range snapshots received by a follower are written to SSTs on disk and then
+// ingested into the LSM. These ingests mostly land in the lower levels of the
+// LSM, and are excluded from the L0 token model (see statsToIgnore in
+// storeAdmissionStats). Without any pacing, a burst of incoming snapshots, as
+// is common during rebalancing, can saturate the disk and starve compactions,
+// which in turn inverts the health of the LSM.
+//
+// The SnapshotQueue paces the writes of incoming snapshots using the elastic
+// disk bandwidth tokens of the store's kvStoreTokenGranter. Snapshot writes
+// don't consume IO tokens, since they don't add to L0. Disk bandwidth tokens
+// are only limited once the provisioned bandwidth of the store is configured,
+// so snapshot writes are otherwise admitted without waiting. Waiting requests
+// are granted in FIFO order, after waiting regular and elastic work.
+
+// snapshotIngestAdmissionEnabled controls whether snapshot writes are subject
+// to admission control. The default is true since pacing only kicks in once
+// disk bandwidth based admission control is configured.
+var snapshotIngestAdmissionEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"admission.snapshot_ingest.enabled",
+	"when true, and provisioned bandwidth for the disk corresponding to a store is configured, "+
+		"writes of incoming range snapshots are paced using disk bandwidth tokens",
+	true,
+)
+
+// SnapshotQueue is the requester for writes of incoming range snapshots to a
+// store. It is created by StoreGrantCoordinators for each store.
+type SnapshotQueue struct {
+	byteTokenQueue
+	settings *cluster.Settings
+	metrics  *SnapshotQueueMetrics
+}
+
+var _ requester = &SnapshotQueue{}
+
+func makeSnapshotQueue(
+	st *cluster.Settings, g granter, metrics *SnapshotQueueMetrics,
+) *SnapshotQueue {
+	return &SnapshotQueue{
+		byteTokenQueue: makeByteTokenQueue(g, byteTokenQueueMetrics{
+			requested:       metrics.Requested,
+			admitted:        metrics.Admitted,
+			errored:         metrics.Errored,
+			waitDurations:   metrics.WaitDurations,
+			waitQueueLength: metrics.WaitQueueLength,
+		}),
+		settings: st,
+		metrics:  metrics,
+	}
+}
+
+// Admit is called before writing count bytes of an incoming snapshot to disk.
+// It blocks until the write is admitted, or the context is canceled. It is
+// a no-op on a nil SnapshotQueue.
+func (q *SnapshotQueue) Admit(ctx context.Context, count int64) error {
+	if q == nil || count <= 0 || !snapshotIngestAdmissionEnabled.Get(&q.settings.SV) {
+		return nil
+	}
+	if err := q.admit(ctx, count); err != nil {
+		return err
+	}
+	q.metrics.AdmittedBytes.Inc(count)
+	return nil
+}
+
+var (
+	snapshotRequestedMeta = metric.Metadata{
+		Name:        "admission.snapshot_ingest.requested",
+		Help:        "Number of snapshot writes that requested admission",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	snapshotAdmittedMeta = metric.Metadata{
+		Name:        "admission.snapshot_ingest.admitted",
+		Help:        "Number of snapshot writes that were admitted",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	snapshotErroredMeta = metric.Metadata{
+		Name:        "admission.snapshot_ingest.errored",
+		Help:        "Number of snapshot writes that were canceled while waiting for admission",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	snapshotAdmittedBytesMeta = metric.Metadata{
+		Name:        "admission.snapshot_ingest.admitted_bytes",
+		Help:        "Number of bytes of snapshot writes that were admitted",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	snapshotWaitDurationsMeta = metric.Metadata{
+		Name:        "admission.snapshot_ingest.wait_durations",
+		Help:        "Wait time durations for snapshot writes that waited for admission",
+		Measurement: "Wait time Duration",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	snapshotWaitQueueLengthMeta = metric.Metadata{
+		Name:        "admission.snapshot_ingest.wait_queue_length",
+		Help:        "Number of snapshot writes waiting for admission",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+// SnapshotQueueMetrics are the metrics associated with the SnapshotQueues of
+// all stores.
+type SnapshotQueueMetrics struct {
+	Requested       *metric.Counter
+	Admitted        *metric.Counter
+	Errored         *metric.Counter
+	AdmittedBytes   *metric.Counter
+	WaitDurations   metric.IHistogram
+	WaitQueueLength *metric.Gauge
+}
+
+func makeSnapshotQueueMetrics(registry *metric.Registry) *SnapshotQueueMetrics {
+	m := &SnapshotQueueMetrics{
+		Requested:     metric.NewCounter(snapshotRequestedMeta),
+		Admitted:      metric.NewCounter(snapshotAdmittedMeta),
+		Errored:       metric.NewCounter(snapshotErroredMeta),
+		AdmittedBytes: metric.NewCounter(snapshotAdmittedBytesMeta),
+		WaitDurations: metric.NewHistogram(metric.HistogramOptions{
+			Mode:         metric.HistogramModePreferHdrLatency,
+			Metadata:     snapshotWaitDurationsMeta,
+			Duration:     base.DefaultHistogramWindowInterval(),
+			BucketConfig: metric.IOLatencyBuckets,
+		}),
+		WaitQueueLength: metric.NewGauge(snapshotWaitQueueLengthMeta),
+	}
+	registry.AddMetricStruct(m)
+	return m
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*SnapshotQueueMetrics) MetricStruct() {}
+
+var _ metric.Struct = &SnapshotQueueMetrics{}
@@ -0,0 +1,119 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	var buf strings.Builder
+	coord := &GrantCoordinator{settings: st}
+	coord.mu.numProcs = 1
+	kvg := &kvStoreTokenGranter{coord: coord}
+	kvg.regularRequester = &testRequester{workKind: KVWork, buf: &buf}
+	kvg.elasticRequester = &testRequester{workKind: KVWork, buf: &buf}
+	coord.granters[KVWork] = kvg
+	metrics := makeSnapshotQueueMetrics(metric.NewRegistry())
+	q := makeSnapshotQueue(st, &kvStoreSnapshotGranter{parent: kvg}, metrics)
+	kvg.snapshotRequester = q
+
+	setDiskBWTokens := func(tokens int64) {
+		coord.mu.Lock()
+		defer coord.mu.Unlock()
+		kvg.coordMu.elasticDiskBWTokensAvailable = tokens
+	}
+	diskBWTokensUsed := func() int64 {
+		coord.mu.Lock()
+		defer coord.mu.Unlock()
+		return kvg.coordMu.diskBWTokensUsed[admissionpb.ElasticWorkClass]
+	}
+
+	// Writes are admitted without waiting while disk bandwidth tokens are
+	// available, even if that drives them negative.
+	setDiskBWTokens(10)
+	require.NoError(t, q.Admit(ctx, 100))
+	require.Equal(t, int64(100), diskBWTokensUsed())
+	require.Equal(t, int64(0), metrics.WaitQueueLength.Value())
+
+	// Once the tokens are exhausted, writes wait in FIFO order.
+	admitted := make(chan int64, 2)
+	admit := func(count int64) {
+		go func() {
+			if err := q.Admit(ctx, count); err != nil {
+				panic(err)
+			}
+			admitted <- count
+		}()
+	}
+	admit(5)
+	testutils.SucceedsSoon(t, func() error {
+		if metrics.WaitQueueLength.Value() != 1 {
+			return errors.New("waiting for first request to queue")
+		}
+		return nil
+	})
+	admit(7)
+	testutils.SucceedsSoon(t, func() error {
+		if metrics.WaitQueueLength.Value() != 2 {
+			return errors.New("waiting for second request to queue")
+		}
+		return nil
+	})
+	select {
+	case <-admitted:
+		t.Fatal("request admitted without tokens")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Making a single token available admits the first request only.
+	setDiskBWTokens(1)
+	coord.testingTryGrant()
+	require.Equal(t, int64(5), <-admitted)
+	require.Equal(t, int64(1), metrics.WaitQueueLength.Value())
+	setDiskBWTokens(1)
+	coord.testingTryGrant()
+	require.Equal(t, int64(7), <-admitted)
+	require.Equal(t, int64(112), diskBWTokensUsed())
+
+	// A canceled request leaves the queue without consuming tokens.
+	setDiskBWTokens(0)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Error(t, q.Admit(cancelCtx, 3))
+	require.Equal(t, int64(0), metrics.WaitQueueLength.Value())
+	require.Equal(t, int64(112), diskBWTokensUsed())
+	require.Equal(t, int64(1), metrics.Errored.Count())
+	require.Equal(t, int64(3), metrics.Admitted.Count())
+	require.Equal(t, int64(112), metrics.AdmittedBytes.Count())
+
+	// When disabled, writes are admitted without consuming tokens.
+	snapshotIngestAdmissionEnabled.Override(ctx, &st.SV, false)
+	require.NoError(t, q.Admit(ctx, 50))
+	require.Equal(t, int64(112), diskBWTokensUsed())
+}
@@ -0,0 +1,525 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"math"
+	"runtime/metrics"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+	"github.com/cockroachdb/redact"
+)
+
+// The per-query memory budgets enforced by SQL memory monitors only account
+// for the memory that each query is known to use, and only bound each query
+// in isolation. Neither accounts for the aggregate memory pressure on the
+// node, which includes untracked allocations and memory that has yet to be
+// garbage collected. As the Go heap approaches the runtime's soft memory limit
+// (GOMEMLIMIT, --max-go-memory), the garbage collector runs increasingly often
+// to stay under it, degrading the performance of all work on the node, until
+// it eventually gives up and the process is OOM-killed.
+//
+// The pieces here provide memory-based admission control for new SQL
+// statements, using the Go runtime's own view of the heap as feedback:
+//
+// - The live heap, i.e. the heap memory retained after the most recent
+//   garbage collection, is sampled periodically, and compared against the
+//   soft memory limit. This is the same comparison the runtime uses to pace
+//   garbage collection, and unlike the total heap size, it does not include
+//   garbage waiting to be collected.
+// - Above admission.sql_memory.throttle_threshold, new statements are only
+//   admitted while fewer than admission.sql_memory.throttled_concurrency
+//   statements admitted this way are executing. The remainder queue, in
+//   priority order, until either the memory pressure subsides or executing
+//   statements complete.
+// - Above admission.sql_memory.shed_threshold, statements are additionally
+//   rejected outright, according to admission.sql_memory.shed_policy.
+//
+// Only the start of statement execution is gated. Statements that are already
+// executing are left alone, since they need to run to completion to release
+// the memory they hold.
+
+var (
+	sqlMemoryAdmissionEnabled = settings.RegisterBoolSetting(
+		settings.ApplicationLevel,
+		"admission.sql_memory.enabled",
+		"when true, the admission of new SQL statements is subject to the Go heap's usage of its soft memory limit",
+		false,
+	)
+
+	sqlMemoryThrottleThreshold = settings.RegisterFloatSetting(
+		settings.ApplicationLevel,
+		"admission.sql_memory.throttle_threshold",
+		"the fraction of the Go runtime's soft memory limit used by the live heap above which "+
+			"new SQL statements are admitted with limited concurrency",
+		0.85,
+		settings.FractionUpperExclusive,
+	)
+
+	sqlMemoryShedThreshold = settings.RegisterFloatSetting(
+		settings.ApplicationLevel,
+		"admission.sql_memory.shed_threshold",
+		"the fraction of the Go runtime's soft memory limit used by the live heap above which "+
+			"new SQL statements are rejected, according to admission.sql_memory.shed_policy",
+		0.95,
+		settings.FractionUpperExclusive,
+	)
+
+	sqlMemoryThrottledConcurrency = settings.RegisterIntSetting(
+		settings.ApplicationLevel,
+		"admission.sql_memory.throttled_concurrency",
+		"the maximum number of SQL statements admitted to execute concurrently while under memory pressure",
+		16,
+		settings.PositiveInt,
+	)
+
+	sqlMemoryShedPolicy = settings.RegisterEnumSetting(
+		settings.ApplicationLevel,
+		"admission.sql_memory.shed_policy",
+		"determines which SQL statements are rejected above admission.sql_memory.shed_threshold; "+
+			"statements that aren't rejected are subject to limited concurrency",
+		sqlMemoryShedPolicyDict[SQLMemoryShedLowPriority],
+		map[int64]string{
+			int64(SQLMemoryShedNone):        sqlMemoryShedPolicyDict[SQLMemoryShedNone],
+			int64(SQLMemoryShedLowPriority): sqlMemoryShedPolicyDict[SQLMemoryShedLowPriority],
+			int64(SQLMemoryShedAll):         sqlMemoryShedPolicyDict[SQLMemoryShedAll],
+		},
+	)
+)
+
+// sqlMemorySampleInterval is the interval at which the Go runtime's memory
+// stats are sampled. The live heap is only updated at the end of each garbage
+// collection, so there's little value in sampling it more frequently.
+const sqlMemorySampleInterval = 250 * time.Millisecond
+
+// SQLMemoryShedPolicy determines which SQL statements are rejected when the
+// node is under severe memory pressure.
+type SQLMemoryShedPolicy int64
+
+const (
+	// SQLMemoryShedNone never rejects statements; they are subject to limited
+	// concurrency instead.
+	SQLMemoryShedNone SQLMemoryShedPolicy = iota
+	// SQLMemoryShedLowPriority rejects statements with a priority lower than
+	// admissionpb.NormalPri, i.e. those from sessions with
+	// default_transaction_quality_of_service = background.
+	SQLMemoryShedLowPriority
+	// SQLMemoryShedAll rejects all statements.
+	SQLMemoryShedAll
+)
+
+var sqlMemoryShedPolicyDict = map[SQLMemoryShedPolicy]string{
+	SQLMemoryShedNone:        "none",
+	SQLMemoryShedLowPriority: "low_priority",
+	SQLMemoryShedAll:         "all",
+}
+
+// sheds returns whether the policy rejects work with the given priority.
+func (p SQLMemoryShedPolicy) sheds(pri admissionpb.WorkPriority) bool {
+	switch p {
+	case SQLMemoryShedLowPriority:
+		return pri < admissionpb.NormalPri
+	case SQLMemoryShedAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrSQLMemoryOverload is returned when a SQL statement is rejected due to
+// memory pressure.
+var ErrSQLMemoryOverload = errors.New("statement rejected due to memory pressure")
+
+// memoryPressure is the level of memory pressure on the node.
+type memoryPressure int8
+
+const (
+	memoryPressureNone memoryPressure = iota
+	memoryPressureThrottle
+	memoryPressureShed
+)
+
+// SafeFormat implements the redact.SafeFormatter interface.
+func (p memoryPressure) SafeFormat(s redact.SafePrinter, _ rune) {
+	switch p {
+	case memoryPressureNone:
+		s.Print("none")
+	case memoryPressureThrottle:
+		s.Print("throttle")
+	case memoryPressureShed:
+		s.Print("shed")
+	default:
+		s.Printf("memoryPressure(%d)", int8(p))
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (p memoryPressure) String() string {
+	return redact.StringWithoutMarkers(p)
+}
+
+// MemoryStats is a sample of the Go runtime's memory usage.
+type MemoryStats struct {
+	// LiveHeapBytes is the heap memory retained after the most recent garbage
+	// collection.
+	LiveHeapBytes int64
+	// LimitBytes is the Go runtime's soft memory limit. It is math.MaxInt64 if
+	// no limit is set.
+	LimitBytes int64
+}
+
+// HeapFraction returns the fraction of the soft memory limit used by the live
+// heap. It is zero if no limit is set.
+func (s MemoryStats) HeapFraction() float64 {
+	if s.LimitBytes <= 0 || s.LimitBytes == math.MaxInt64 {
+		return 0
+	}
+	return float64(s.LiveHeapBytes) / float64(s.LimitBytes)
+}
+
+const (
+	runtimeMetricLiveHeap   = "/gc/heap/live:bytes"
+	runtimeMetricGoMemLimit = "/gc/gomemlimit:bytes"
+)
+
+// ReadGoMemoryStats samples the Go runtime's memory usage.
+func ReadGoMemoryStats() MemoryStats {
+	samples := [...]metrics.Sample{
+		{Name: runtimeMetricLiveHeap},
+		{Name: runtimeMetricGoMemLimit},
+	}
+	metrics.Read(samples[:])
+	var stats MemoryStats
+	if v := samples[0].Value; v.Kind() == metrics.KindUint64 {
+		stats.LiveHeapBytes = int64(v.Uint64())
+	}
+	stats.LimitBytes = math.MaxInt64
+	if v := samples[1].Value; v.Kind() == metrics.KindUint64 && v.Uint64() < math.MaxInt64 {
+		stats.LimitBytes = int64(v.Uint64())
+	}
+	return stats
+}
+
+// sqlMemoryGranter is a slot granter for the start of SQL statement
+// execution. Slots are unlimited in the absence of memory pressure, and
+// limited to the throttled concurrency otherwise.
+type sqlMemoryGranter struct {
+	mu struct {
+		// NB: like elasticCPUGranter, this granter has its own mutex which isn't
+		// held while calling into the WorkQueue.
+		syncutil.Mutex
+		usedSlots      int
+		throttledSlots int
+		pressure       memoryPressure
+	}
+	requester requester
+	metrics   *sqlMemoryGranterMetrics
+}
+
+var _ granter = &sqlMemoryGranter{}
+
+func (g *sqlMemoryGranter) setRequester(requester requester) {
+	g.requester = requester
+}
+
+// grantKind implements granter.
+func (g *sqlMemoryGranter) grantKind() grantKind {
+	return slot
+}
+
+// tryGet implements granter.
+func (g *sqlMemoryGranter) tryGet(count int64) bool {
+	if count != 1 {
+		panic(errors.AssertionFailedf("unexpected count: %d", count))
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.mu.pressure != memoryPressureNone && g.mu.usedSlots >= g.mu.throttledSlots {
+		return false
+	}
+	g.mu.usedSlots++
+	g.metrics.UsedSlots.Update(int64(g.mu.usedSlots))
+	return true
+}
+
+// returnGrant implements granter.
+func (g *sqlMemoryGranter) returnGrant(count int64) {
+	g.returnGrantWithoutGrantingElsewhere(count)
+	g.tryGrant()
+}
+
+func (g *sqlMemoryGranter) returnGrantWithoutGrantingElsewhere(count int64) {
+	if count != 1 {
+		panic(errors.AssertionFailedf("unexpected count: %d", count))
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.mu.usedSlots--
+	if g.mu.usedSlots < 0 {
+		panic(errors.AssertionFailedf("used slots is negative %d", g.mu.usedSlots))
+	}
+	g.metrics.UsedSlots.Update(int64(g.mu.usedSlots))
+}
+
+// tookWithoutPermission implements granter.
+func (g *sqlMemoryGranter) tookWithoutPermission(count int64) {
+	if count != 1 {
+		panic(errors.AssertionFailedf("unexpected count: %d", count))
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.mu.usedSlots++
+	g.metrics.UsedSlots.Update(int64(g.mu.usedSlots))
+}
+
+// continueGrantChain implements granter.
+func (g *sqlMemoryGranter) continueGrantChain(grantChainID) {
+	// Ignore since grant chains are not used for SQL memory admission.
+}
+
+// tryGrant is used to attempt to grant to waiting requests.
+func (g *sqlMemoryGranter) tryGrant() {
+	for g.requester.hasWaitingRequests() && g.tryGet(1) {
+		if slots := g.requester.granted(noGrantChain); slots == 0 {
+			g.returnGrantWithoutGrantingElsewhere(1)
+			return // requester didn't accept, nothing left to do; bow out
+		}
+	}
+}
+
+// setPressure updates the memory pressure and the number of slots available
+// under memory pressure, granting to waiting requests if possible.
+func (g *sqlMemoryGranter) setPressure(pressure memoryPressure, throttledSlots int) {
+	func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.mu.pressure = pressure
+		g.mu.throttledSlots = throttledSlots
+	}()
+	g.tryGrant()
+}
+
+func (g *sqlMemoryGranter) getPressure() memoryPressure {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.mu.pressure
+}
+
+// SQLMemoryWorkQueue maintains a queue of SQL statements waiting to be
+// admitted based on memory pressure.
+type SQLMemoryWorkQueue struct {
+	settings  *cluster.Settings
+	workQueue *WorkQueue
+	granter   *sqlMemoryGranter
+	metrics   *sqlMemoryGranterMetrics
+}
+
+// SQLMemoryWorkHandle is returned by SQLMemoryWorkQueue.Admit, and must be
+// passed to SQLMemoryWorkQueue.AdmittedWorkDone once the admitted statement
+// completes.
+type SQLMemoryWorkHandle struct {
+	tenantID roachpb.TenantID
+	admitted bool
+}
+
+// Admit is called when requesting admission for the execution of a SQL
+// statement. It returns ErrSQLMemoryOverload if the statement is rejected due
+// to memory pressure.
+func (q *SQLMemoryWorkQueue) Admit(ctx context.Context, info WorkInfo) (SQLMemoryWorkHandle, error) {
+	if q == nil || !sqlMemoryAdmissionEnabled.Get(&q.settings.SV) {
+		return SQLMemoryWorkHandle{}, nil
+	}
+	if q.granter.getPressure() == memoryPressureShed {
+		policy := SQLMemoryShedPolicy(sqlMemoryShedPolicy.Get(&q.settings.SV))
+		if policy.sheds(info.Priority) {
+			q.metrics.Shed.Inc(1)
+			return SQLMemoryWorkHandle{}, errors.WithHintf(ErrSQLMemoryOverload,
+				"the node is close to its memory limit; retry the statement later")
+		}
+	}
+	enabled, err := q.workQueue.Admit(ctx, info)
+	if err != nil {
+		return SQLMemoryWorkHandle{}, err
+	}
+	return SQLMemoryWorkHandle{tenantID: info.TenantID, admitted: enabled}, nil
+}
+
+// AdmittedWorkDone indicates to the queue that the admitted statement has
+// completed.
+func (q *SQLMemoryWorkQueue) AdmittedWorkDone(h SQLMemoryWorkHandle) {
+	if !h.admitted {
+		return
+	}
+	q.workQueue.AdmittedWorkDone(h.tenantID, 0 /* cpuTime */)
+}
+
+func (q *SQLMemoryWorkQueue) close() {
+	q.workQueue.close()
+}
+
+// SQLMemoryGrantCoordinator coordinates memory-based admission for the start
+// of SQL statement execution. Like the ElasticCPUGrantCoordinator, it has a
+// single granter-requester pair, and is separate from the regular
+// GrantCoordinator: memory pressure should gate new statements, but not the
+// lower-level work of statements that are already executing, which releases
+// memory once it completes.
+type SQLMemoryGrantCoordinator struct {
+	SQLMemoryWorkQueue *SQLMemoryWorkQueue
+
+	ctx      context.Context
+	settings *cluster.Settings
+	granter  *sqlMemoryGranter
+	metrics  *sqlMemoryGranterMetrics
+	// readMemoryStats is overridden in tests.
+	readMemoryStats func() MemoryStats
+}
+
+// NewSQLMemoryGrantCoordinator constructs a SQLMemoryGrantCoordinator. The
+// caller is responsible for calling Start to begin sampling memory usage.
+func NewSQLMemoryGrantCoordinator(
+	ambientCtx log.AmbientContext, st *cluster.Settings, registry *metric.Registry,
+) *SQLMemoryGrantCoordinator {
+	metrics := makeSQLMemoryGranterMetrics()
+	registry.AddMetricStruct(metrics)
+	wqMetrics := makeWorkQueueMetrics("sql-memory", registry,
+		admissionpb.UserLowPri, admissionpb.NormalPri)
+
+	g := &sqlMemoryGranter{metrics: metrics}
+	wq := &WorkQueue{}
+	initWorkQueue(wq, ambientCtx, SQLStatementRootStartWork, "sql-memory-queue", g, st,
+		wqMetrics, workQueueOptions{usesTokens: false}, nil /* knobs */)
+	g.setRequester(wq)
+
+	ctx := ambientCtx.AnnotateCtx(context.Background())
+	ctx = logtags.AddTag(ctx, "sql-memory-admission", "")
+	return &SQLMemoryGrantCoordinator{
+		SQLMemoryWorkQueue: &SQLMemoryWorkQueue{
+			settings:  st,
+			workQueue: wq,
+			granter:   g,
+			metrics:   metrics,
+		},
+		ctx:             ctx,
+		settings:        st,
+		granter:         g,
+		metrics:         metrics,
+		readMemoryStats: ReadGoMemoryStats,
+	}
+}
+
+// Start starts periodically sampling the Go runtime's memory usage, to adjust
+// SQL statement admission. The coordinator is closed when the stopper stops.
+func (c *SQLMemoryGrantCoordinator) Start(ctx context.Context, stopper *stop.Stopper) error {
+	stopper.AddCloser(stop.CloserFn(c.close))
+	return stopper.RunAsyncTask(ctx, "sql-memory-admission", func(ctx context.Context) {
+		ticker := time.NewTicker(sqlMemorySampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.memoryLoad(c.readMemoryStats())
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+// memoryLoad adjusts the memory pressure based on the given memory stats.
+func (c *SQLMemoryGrantCoordinator) memoryLoad(stats MemoryStats) {
+	sv := &c.settings.SV
+	fraction := stats.HeapFraction()
+	c.metrics.HeapFraction.Update(fraction)
+
+	pressure := memoryPressureNone
+	if sqlMemoryAdmissionEnabled.Get(sv) {
+		if fraction >= sqlMemoryShedThreshold.Get(sv) {
+			pressure = memoryPressureShed
+		} else if fraction >= sqlMemoryThrottleThreshold.Get(sv) {
+			pressure = memoryPressureThrottle
+		}
+	}
+	if prev := c.granter.getPressure(); prev != pressure {
+		log.Infof(c.ctx, "memory pressure changed from %s to %s (live heap %s is %.0f%% of memory limit)",
+			prev, pressure, humanizeutil.IBytes(stats.LiveHeapBytes), fraction*100)
+	}
+	c.metrics.Pressure.Update(int64(pressure))
+	c.granter.setPressure(pressure, int(sqlMemoryThrottledConcurrency.Get(sv)))
+}
+
+func (c *SQLMemoryGrantCoordinator) close() {
+	c.SQLMemoryWorkQueue.close()
+}
+
+var (
+	sqlMemoryHeapFraction = metric.Metadata{
+		Name:        "admission.sql_memory.heap_fraction",
+		Help:        "Live heap as a fraction of the Go runtime's soft memory limit, as used for SQL memory admission",
+		Measurement: "Memory",
+		Unit:        metric.Unit_PERCENT,
+	}
+	sqlMemoryPressure = metric.Metadata{
+		Name:        "admission.sql_memory.pressure",
+		Help:        "Memory pressure level used for SQL memory admission (0: none, 1: throttle, 2: shed)",
+		Measurement: "Level",
+		Unit:        metric.Unit_COUNT,
+	}
+	sqlMemoryUsedSlots = metric.Metadata{
+		Name:        "admission.sql_memory.used_slots",
+		Help:        "Number of SQL statements admitted by SQL memory admission that are executing",
+		Measurement: "Statements",
+		Unit:        metric.Unit_COUNT,
+	}
+	sqlMemoryShed = metric.Metadata{
+		Name:        "admission.sql_memory.shed",
+		Help:        "Number of SQL statements rejected due to memory pressure",
+		Measurement: "Statements",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+// sqlMemoryGranterMetrics are the metrics associated with the
+// SQLMemoryGrantCoordinator.
+type sqlMemoryGranterMetrics struct {
+	HeapFraction *metric.GaugeFloat64
+	Pressure     *metric.Gauge
+	UsedSlots    *metric.Gauge
+	Shed         *metric.Counter
+}
+
+func makeSQLMemoryGranterMetrics() *sqlMemoryGranterMetrics {
+	return &sqlMemoryGranterMetrics{
+		HeapFraction: metric.NewGaugeFloat64(sqlMemoryHeapFraction),
+		Pressure:     metric.NewGauge(sqlMemoryPressure),
+		UsedSlots:    metric.NewGauge(sqlMemoryUsedSlots),
+		Shed:         metric.NewCounter(sqlMemoryShed),
+	}
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (m *sqlMemoryGranterMetrics) MetricStruct() {}
+
+var _ metric.Struct = &sqlMemoryGranterMetrics{}
@@ -0,0 +1,152 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLMemoryGrantCoordinator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	sqlMemoryAdmissionEnabled.Override(ctx, &st.SV, true)
+	sqlMemoryThrottledConcurrency.Override(ctx, &st.SV, 1)
+	coord := NewSQLMemoryGrantCoordinator(log.MakeTestingAmbientCtxWithNewTracer(), st, metric.NewRegistry())
+	defer coord.close()
+	q := coord.SQLMemoryWorkQueue
+
+	const limit = 1000
+	setLiveHeap := func(liveHeap int64) {
+		coord.memoryLoad(MemoryStats{LiveHeapBytes: liveHeap, LimitBytes: limit})
+	}
+	admit := func(pri admissionpb.WorkPriority) (SQLMemoryWorkHandle, error) {
+		return q.Admit(ctx, WorkInfo{TenantID: roachpb.SystemTenantID, Priority: pri})
+	}
+
+	// Without memory pressure, admission is unlimited.
+	setLiveHeap(500)
+	require.Equal(t, memoryPressureNone, coord.granter.getPressure())
+	var handles []SQLMemoryWorkHandle
+	for i := 0; i < 3; i++ {
+		h, err := admit(admissionpb.NormalPri)
+		require.NoError(t, err)
+		require.True(t, h.admitted)
+		handles = append(handles, h)
+	}
+
+	// Under memory pressure, new statements wait until the number of executing
+	// statements drops below the throttled concurrency.
+	setLiveHeap(900)
+	require.Equal(t, memoryPressureThrottle, coord.granter.getPressure())
+	// The results of the statements admitted asynchronously are checked by the
+	// test goroutine.
+	type admitResult struct {
+		h   SQLMemoryWorkHandle
+		err error
+	}
+	admittedCh := make(chan admitResult)
+	admitted := func() SQLMemoryWorkHandle {
+		res := <-admittedCh
+		require.NoError(t, res.err)
+		return res.h
+	}
+	go func() {
+		h, err := admit(admissionpb.NormalPri)
+		admittedCh <- admitResult{h, err}
+	}()
+	for _, h := range handles {
+		select {
+		case <-admittedCh:
+			t.Fatal("statement admitted despite memory pressure")
+		case <-time.After(10 * time.Millisecond):
+		}
+		q.AdmittedWorkDone(h)
+	}
+	h := admitted()
+	require.True(t, h.admitted)
+
+	// Above the shed threshold, low priority statements are rejected.
+	setLiveHeap(990)
+	require.Equal(t, memoryPressureShed, coord.granter.getPressure())
+	_, err := admit(admissionpb.UserLowPri)
+	require.True(t, errors.Is(err, ErrSQLMemoryOverload))
+	require.Equal(t, int64(1), coord.metrics.Shed.Count())
+
+	// Normal priority statements continue to queue behind the executing
+	// statement, and are admitted once the memory pressure subsides.
+	go func() {
+		h, err := admit(admissionpb.NormalPri)
+		admittedCh <- admitResult{h, err}
+	}()
+	select {
+	case <-admittedCh:
+		t.Fatal("statement admitted despite memory pressure")
+	case <-time.After(10 * time.Millisecond):
+	}
+	setLiveHeap(100)
+	q.AdmittedWorkDone(admitted())
+	q.AdmittedWorkDone(h)
+
+	// When disabled, statements bypass admission, and aren't shed.
+	setLiveHeap(990)
+	sqlMemoryAdmissionEnabled.Override(ctx, &st.SV, false)
+	h, err = admit(admissionpb.UserLowPri)
+	require.NoError(t, err)
+	require.False(t, h.admitted)
+	q.AdmittedWorkDone(h)
+}
+
+func TestSQLMemoryShedPolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		policy SQLMemoryShedPolicy
+		pri    admissionpb.WorkPriority
+		exp    bool
+	}{
+		{SQLMemoryShedNone, admissionpb.UserLowPri, false},
+		{SQLMemoryShedNone, admissionpb.NormalPri, false},
+		{SQLMemoryShedLowPriority, admissionpb.UserLowPri, true},
+		{SQLMemoryShedLowPriority, admissionpb.NormalPri, false},
+		{SQLMemoryShedLowPriority, admissionpb.UserHighPri, false},
+		{SQLMemoryShedAll, admissionpb.NormalPri, true},
+		{SQLMemoryShedAll, admissionpb.UserHighPri, true},
+	} {
+		require.Equal(t, tc.exp, tc.policy.sheds(tc.pri), "%s %s", sqlMemoryShedPolicyDict[tc.policy], tc.pri)
+	}
+}
+
+func TestMemoryStatsHeapFraction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	require.Equal(t, 0.5, MemoryStats{LiveHeapBytes: 50, LimitBytes: 100}.HeapFraction())
+	// No limit is set.
+	require.Zero(t, MemoryStats{LiveHeapBytes: 50, LimitBytes: 1<<63 - 1}.HeapFraction())
+	require.Zero(t, MemoryStats{LiveHeapBytes: 50}.HeapFraction())
+	// The stats for the running process are readable.
+	stats := ReadGoMemoryStats()
+	require.Greater(t, stats.LiveHeapBytes, int64(0))
+	require.Greater(t, stats.LimitBytes, int64(0))
+}
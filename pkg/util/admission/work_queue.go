@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -114,6 +115,17 @@ var KVStoresTenantWeightsEnabled = settings.RegisterBoolSetting(
 	false,
 )
 
+// KVRUThrottledTenantDeprioritizationEnabled controls whether KV work from
+// tenants that are being throttled by tenant cost control is deprioritized
+// relative to the work of other tenants.
+var KVRUThrottledTenantDeprioritizationEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"admission.kv.ru_throttled_tenant_deprioritization.enabled",
+	"when true, KV work from tenants that are throttled by tenant cost control "+
+		"is admitted only after the work of tenants that are not throttled",
+	true,
+)
+
 // EpochLIFOEnabled controls whether the adaptive epoch-LIFO scheme is enabled
 // for admission control. Is only relevant when the above admission control
 // settings are also set to true. Unlike those settings, which are granular
@@ -191,6 +203,12 @@ type WorkInfo struct {
 	// it to be accounted for. It should be used for high-priority intra-KV work,
 	// and when KV work generates other KV work (to avoid deadlock).
 	BypassAdmission bool
+	// RUThrottled is set for work from tenants that are currently being
+	// throttled by tenant cost control, i.e. that have exhausted their request
+	// units. Such tenants are ordered after all tenants that are not
+	// throttled, so that a tenant running on debt cannot overload the node at
+	// the expense of others.
+	RUThrottled bool
 	// RequestedCount is the requested number of tokens or slots. If unset:
 	// - For slot-based queues we treat it as an implicit request of 1;
 	// - For the store work queue, we use per-request estimates to deduct some
@@ -306,6 +324,11 @@ type WorkQueue struct {
 			// The maps are lazily allocated.
 			active, inactive map[uint64]uint32
 		}
+		// numRUThrottledTenants is the number of tenants last reported in
+		// WorkQueueMetrics as being throttled by tenant cost control. Since
+		// the metrics can be shared across WorkQueues, it is used to update
+		// the gauge using deltas.
+		numRUThrottledTenants int64
 		// The highest epoch that is closed.
 		closedEpochThreshold int64
 		// Following values are copied from the cluster settings.
@@ -314,8 +337,10 @@ type WorkQueue struct {
 		maxQueueDelayToSwitchToLifo time.Duration
 	}
 	logThreshold log.EveryN
-	metrics      *WorkQueueMetrics
-	stopCh       chan struct{}
+	// waitEventEvery rate limits the structured events reporting long waits.
+	waitEventEvery log.EveryN
+	metrics        *WorkQueueMetrics
+	stopCh         chan struct{}
 
 	timeSource timeutil.TimeSource
 	knobs      *TestingKnobs
@@ -405,6 +430,7 @@ func initWorkQueue(
 	q.usesAsyncAdmit = opts.usesAsyncAdmit
 	q.settings = settings
 	q.logThreshold = log.Every(5 * time.Minute)
+	q.waitEventEvery = log.Every(time.Second)
 	q.metrics = metrics
 	q.stopCh = stopCh
 	q.timeSource = timeSource
@@ -607,6 +633,15 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		tenant = newTenantInfo(tenantID, q.getTenantWeightLocked(tenantID))
 		q.mu.tenants[tenantID] = tenant
 	}
+	if info.RUThrottled {
+		q.metrics.ruThrottledRequested.Inc(1)
+		if !tenant.ruThrottled {
+			tenant.ruThrottled = true
+			if isInTenantHeap(tenant) {
+				q.mu.tenantHeap.fix(tenant)
+			}
+		}
+	}
 	if info.ReplicatedWorkInfo.Enabled {
 		if info.BypassAdmission {
 			// TODO(irfansharif): "Admin" work (like splits, scatters, lease
@@ -813,6 +848,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		q.metrics.recordFinishWait(info.Priority, waitDur)
 		deadline, _ := ctx.Deadline()
 		recordAdmissionWorkQueueStats(span, waitDur, q.queueKind, info.Priority, true)
+		q.maybeLogWait(ctx, info, waitDur, true /* deadlineExceeded */)
 		log.Eventf(ctx, "deadline expired, waited in %s queue with pri %s for %v", q.queueKind, admissionpb.WorkPriorityDict[info.Priority], waitDur)
 		return true,
 			errors.Newf("deadline expired while waiting in queue: %s, pri: %s, deadline: %v, start: %v, dur: %v",
@@ -828,6 +864,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 			panic(errors.AssertionFailedf("grantee should be removed from heap"))
 		}
 		recordAdmissionWorkQueueStats(span, waitDur, q.queueKind, info.Priority, false)
+		q.maybeLogWait(ctx, info, waitDur, false /* deadlineExceeded */)
 		q.granter.continueGrantChain(chainID)
 		return true, nil
 	}
@@ -851,6 +888,27 @@ func recordAdmissionWorkQueueStats(
 	})
 }
 
+// longWaitThreshold is the wait duration above which work waiting in a
+// WorkQueue is reported on the QUOTA logging channel.
+const longWaitThreshold = time.Second
+
+// maybeLogWait reports a long wait of work in the queue on the QUOTA logging
+// channel. The events are rate limited.
+func (q *WorkQueue) maybeLogWait(
+	ctx context.Context, info WorkInfo, waitDur time.Duration, deadlineExceeded bool,
+) {
+	if waitDur < longWaitThreshold || !q.waitEventEvery.ShouldLog() {
+		return
+	}
+	log.StructuredEvent(ctx, &eventpb.AdmissionQueueWait{
+		QueueKind:        string(q.queueKind),
+		TenantID:         info.TenantID.ToUint64(),
+		Priority:         admissionpb.WorkPriorityDict[info.Priority],
+		WaitNanos:        waitDur.Nanoseconds(),
+		DeadlineExceeded: deadlineExceeded,
+	})
+}
+
 // AdmittedWorkDone is used to inform the WorkQueue that some admitted work is
 // finished. It must be called iff the WorkKind of this WorkQueue uses slots
 // (not tokens), i.e., KVWork, SQLStatementLeafStartWork,
@@ -950,7 +1008,16 @@ func (q *WorkQueue) gcTenantsAndResetUsed() {
 	// With large numbers of active tenants, this iteration could hold the lock
 	// longer than desired. We could break this iteration into smaller parts if
 	// needed.
+	var numRUThrottled int64
+	reorderHeap := false
 	for id, info := range q.mu.tenants {
+		// Tenants are considered throttled for as long as they keep submitting
+		// work marked as such, so the flag is reset at the same cadence as used.
+		if info.ruThrottled {
+			numRUThrottled++
+			info.ruThrottled = false
+			reorderHeap = reorderHeap || isInTenantHeap(info)
+		}
 		if info.used == 0 && !isInTenantHeap(info) {
 			delete(q.mu.tenants, id)
 			releaseTenantInfo(info)
@@ -960,6 +1027,11 @@ func (q *WorkQueue) gcTenantsAndResetUsed() {
 			// ordering.
 		}
 	}
+	if reorderHeap {
+		heap.Init(&q.mu.tenantHeap)
+	}
+	q.metrics.ruThrottledTenants.Inc(numRUThrottled - q.mu.numRUThrottledTenants)
+	q.mu.numRUThrottledTenants = numRUThrottled
 }
 
 // adjustTenantUsed is used internally by StoreWorkQueue, and by the KV queue
@@ -1011,6 +1083,9 @@ func (q *WorkQueue) SafeFormat(s redact.SafePrinter, _ rune) {
 		tenant := q.mu.tenants[id]
 		s.Printf("\n tenant-id: %d used: %d, w: %d, fifo: %d", tenant.id, tenant.used,
 			tenant.weight, tenant.fifoPriorityThreshold)
+		if tenant.ruThrottled {
+			s.Printf(", ru-throttled")
+		}
 		if len(tenant.waitingWorkHeap) > 0 {
 			s.Printf(" waiting work heap:")
 			for i := range tenant.waitingWorkHeap {
@@ -1332,6 +1407,10 @@ type tenantInfo struct {
 	waitingWorkHeap waitingWorkHeap
 	openEpochsHeap  openEpochsHeap
 
+	// ruThrottled is set when work arrives that is marked as
+	// WorkInfo.RUThrottled, and reset periodically along with used.
+	ruThrottled bool
+
 	priorityStates priorityStates
 	// priority >= fifoPriorityThreshold is FIFO. This uses a larger sized type
 	// than WorkPriority since the threshold can be > MaxPri.
@@ -1345,6 +1424,8 @@ type tenantInfo struct {
 // tenantHeap is a heap of tenants with waiting work, ordered in increasing
 // order of tenantInfo.used/tenantInfo.weight (weights are an optional
 // feature, and default to 1). That is, we prefer tenants that are using less.
+// Tenants that are throttled by tenant cost control are ordered after all
+// tenants that are not.
 type tenantHeap []*tenantInfo
 
 var _ heap.Interface = (*tenantHeap)(nil)
@@ -1403,6 +1484,9 @@ func (th *tenantHeap) Len() int {
 }
 
 func (th *tenantHeap) Less(i, j int) bool {
+	if (*th)[i].ruThrottled != (*th)[j].ruThrottled {
+		return !(*th)[i].ruThrottled
+	}
 	// used_i/weight_i < used_j/weight_j
 	return (*th)[i].used*uint64((*th)[j].weight) < (*th)[j].used*uint64((*th)[i].weight)
 }
@@ -1722,6 +1806,18 @@ var (
 		Measurement: "Requests",
 		Unit:        metric.Unit_COUNT,
 	}
+	ruThrottledRequestedMeta = metric.Metadata{
+		Name:        "admission.requested_ru_throttled.",
+		Help:        "Number of requests from tenants that are throttled by tenant cost control",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	ruThrottledTenantsMeta = metric.Metadata{
+		Name:        "admission.ru_throttled_tenants.",
+		Help:        "Number of tenants that are throttled by tenant cost control and deprioritized by admission control",
+		Measurement: "Tenants",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 func addName(name string, meta metric.Metadata) metric.Metadata {
@@ -1738,6 +1834,9 @@ type WorkQueueMetrics struct {
 	total      workQueueMetricsSingle
 	byPriority sync.Map
 	registry   *metric.Registry
+
+	ruThrottledRequested *metric.Counter
+	ruThrottledTenants   *metric.Gauge
 }
 
 // getOrCreate will return the metric if it exists or create it and then return
@@ -1829,10 +1928,14 @@ func makeWorkQueueMetrics(
 	totalMetric := makeWorkQueueMetricsSingle(name)
 	registry.AddMetricStruct(totalMetric)
 	wqm := &WorkQueueMetrics{
-		name:     name,
-		total:    totalMetric,
-		registry: registry,
-	}
+		name:                 name,
+		total:                totalMetric,
+		registry:             registry,
+		ruThrottledRequested: metric.NewCounter(addName(name, ruThrottledRequestedMeta)),
+		ruThrottledTenants:   metric.NewGauge(addName(name, ruThrottledTenantsMeta)),
+	}
+	registry.AddMetric(wqm.ruThrottledRequested)
+	registry.AddMetric(wqm.ruThrottledTenants)
 	// TODO(abaptist): This is done to pre-register stats. Need to check that we
 	// getOrCreate "enough" of the priorities to be useful. See
 	// https://github.com/cockroachdb/cockroach/issues/88846.
@@ -228,6 +228,10 @@ func TestWorkQueueBasic(t *testing.T) {
 				d.ScanArgs(t, "create-time-millis", &createTime)
 				var bypass bool
 				d.ScanArgs(t, "bypass", &bypass)
+				var ruThrottled bool
+				if d.HasArg("ru-throttled") {
+					d.ScanArgs(t, "ru-throttled", &ruThrottled)
+				}
 				ctx, cancel := context.WithCancel(context.Background())
 				wrkMap.set(id, &testWork{tenantID: tenant, cancel: cancel})
 				workInfo := WorkInfo{
@@ -235,6 +239,7 @@ func TestWorkQueueBasic(t *testing.T) {
 					Priority:        admissionpb.WorkPriority(priority),
 					CreateTime:      int64(createTime) * int64(time.Millisecond),
 					BypassAdmission: bypass,
+					RUThrottled:     ruThrottled,
 				}
 				go func(ctx context.Context, info WorkInfo, id int) {
 					enabled, err := q.Admit(ctx, info)
@@ -36,6 +36,8 @@ func TestEventJSON(t *testing.T) {
 		{&SetClusterSetting{SettingName: "my.setting"}, `"SettingName":"my.setting"`},
 		{&AlterRole{Options: []string{"NOLOGIN", "PASSWORD"}}, `"Options":["NOLOGIN","PASSWORD"]`},
 		{&AlterRole{SetInfo: []string{"DEFAULTSETTINGS"}}, `"SetInfo":["DEFAULTSETTINGS"]`},
+		{&AdmissionQueueWait{QueueKind: "kv-regular-cpu-queue", TenantID: 5, Priority: "normal-pri", WaitNanos: 1500000000},
+			`"QueueKind":"kv-regular-cpu-queue","TenantID":5,"Priority":"normal-pri","WaitNanos":1500000000`},
 		{&GrantRole{GranteeRoles: []string{"role1", "role2"}, Members: []string{"role3", " role4"}}, `"GranteeRoles":["‹role1›","‹role2›"],"Members":["‹role3›","‹ role4›"]`},
 		{&ChangeDatabasePrivilege{CommonSQLPrivilegeEventDetails: CommonSQLPrivilegeEventDetails{
 			GrantedPrivileges: []string{"INSERT", "CREATE"},
@@ -228,6 +228,14 @@ type BytesMonitor struct {
 		// NB: this field doesn't need mutex protection but is inside of mu
 		// struct in order to reduce the struct size.
 		rootSQLMonitor bool
+
+		// queryMemoryBudget indicates whether this monitor enforces the
+		// per-query memory budget (in which case, memory budget exceeded
+		// errors should have a hint to increase max_query_memory session
+		// variable).
+		// NB: this field doesn't need mutex protection but is inside of mu
+		// struct in order to reduce the struct size.
+		queryMemoryBudget bool
 	}
 
 	// parentMu encompasses the fields that must be accessed while holding the
@@ -481,6 +489,16 @@ func (mm *BytesMonitor) MarkAsRootSQLMonitor() {
 	mm.mu.rootSQLMonitor = true
 }
 
+// MarkAsQueryMemoryBudgetMonitor marks this monitor as the one enforcing the
+// per-query memory budget. Errors returned when the configured limit of this
+// monitor is reached can be detected via IsQueryMemoryBudgetExceededError.
+func (mm *BytesMonitor) MarkAsQueryMemoryBudgetMonitor() {
+	if mm.mu.tracksDisk {
+		panic(errors.AssertionFailedf("query memory budget monitor cannot track disk resources"))
+	}
+	mm.mu.queryMemoryBudget = true
+}
+
 // noReserved is safe to be used by multiple monitors as the "reserved" account
 // since only its 'used' and 'reserved' fields will ever be read.
 var noReserved = BoundAccount{}
@@ -1109,6 +1127,9 @@ func (mm *BytesMonitor) makeBudgetExceededError(minExtra int64) error {
 	if mm.mu.rootSQLMonitor {
 		errConstructor = newRootSQLMemoryMonitorBudgetExceededError
 	}
+	if mm.mu.queryMemoryBudget && mm.limit == mm.configLimit {
+		errConstructor = newQueryMemoryBudgetExceededError
+	}
 	return errors.Wrapf(errConstructor(
 		minExtra, mm.mu.curAllocated, mm.reserved.used), "%s", mm.name,
 	)
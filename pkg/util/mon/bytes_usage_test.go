@@ -699,3 +699,46 @@ func TestLimit(t *testing.T) {
 	require.Equal(t, int64(1123), m2.Limit())
 	m2.Stop(ctx)
 }
+
+func TestQueryMemoryBudgetExceededError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+
+	parent := NewMonitor(Options{
+		Name:      "root",
+		Increment: 1,
+		Settings:  st,
+	})
+	parent.Start(ctx, nil, NewStandaloneBudget(10000))
+	defer parent.Stop(ctx)
+
+	for _, tc := range []struct {
+		limit         int64
+		expectedQuery bool
+	}{
+		// The configured limit of the monitor is reached.
+		{limit: 1000, expectedQuery: true},
+		// The limit of the parent is reached.
+		{limit: 100000, expectedQuery: false},
+	} {
+		t.Run(fmt.Sprintf("limit=%d", tc.limit), func(t *testing.T) {
+			m := NewMonitor(Options{
+				Name:      "query",
+				Limit:     tc.limit,
+				Increment: 1,
+				Settings:  st,
+			})
+			m.MarkAsQueryMemoryBudgetMonitor()
+			m.StartNoReserved(ctx, parent)
+			defer m.Stop(ctx)
+
+			acc := m.MakeBoundAccount()
+			defer acc.Close(ctx)
+			err := acc.Grow(ctx, 20000)
+			require.Error(t, err)
+			require.Equal(t, tc.expectedQuery, IsQueryMemoryBudgetExceededError(err))
+		})
+	}
+}
@@ -60,3 +60,26 @@ func newRootSQLMemoryMonitorBudgetExceededError(
 		"Consider increasing --max-sql-memory startup parameter.", /* hint */
 	)
 }
+
+// errQueryMemoryBudgetExceeded is used to mark errors returned when the
+// per-query memory budget is exceeded.
+var errQueryMemoryBudgetExceeded = errors.New("query memory budget exceeded")
+
+func newQueryMemoryBudgetExceededError(
+	requestedBytes int64, reservedBytes int64, budgetBytes int64,
+) error {
+	return errors.Mark(
+		errors.WithHint(
+			NewMemoryBudgetExceededError(requestedBytes, reservedBytes, budgetBytes),
+			"Consider increasing max_query_memory session variable, up to the "+
+				"sql.distsql.max_query_memory cluster setting.", /* hint */
+		),
+		errQueryMemoryBudgetExceeded,
+	)
+}
+
+// IsQueryMemoryBudgetExceededError returns true if err was caused by the
+// per-query memory budget being exceeded.
+func IsQueryMemoryBudgetExceededError(err error) bool {
+	return errors.Is(err, errQueryMemoryBudgetExceeded)
+}
@@ -287,6 +287,12 @@ func (p *IntPool) Len() int {
 	return p.qp.Len()
 }
 
+// LongestWait returns how long the oldest ongoing acquisition on this IntPool
+// has been waiting, or zero if there are none.
+func (p *IntPool) LongestWait() time.Duration {
+	return p.qp.LongestWait()
+}
+
 // ApproximateQuota will report approximately the amount of quota available in
 // the pool. It's "approximate" because, if there's an acquisition in progress,
 // this might return an "intermediate" value - one that does not fully reflect
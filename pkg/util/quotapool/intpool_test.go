@@ -650,6 +650,55 @@ func TestLen(t *testing.T) {
 	assert.Equal(t, 0, qp.Len())
 }
 
+// TestLongestWait ensures that LongestWait reports how long the oldest ongoing
+// acquisition has been waiting.
+func TestLongestWait(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mt := timeutil.NewManualTime(t0)
+	qp := quotapool.NewIntPool("test", 1, quotapool.WithTimeSource(mt))
+	ctx := context.Background()
+	allocCh := make(chan *quotapool.IntAlloc)
+	doAcquire := func(ctx context.Context) {
+		alloc, err := qp.Acquire(ctx, 1)
+		if ctx.Err() == nil && assert.Nil(t, err) {
+			allocCh <- alloc
+		}
+	}
+	assertLenSoon := func(exp int) {
+		testutils.SucceedsSoon(t, func() error {
+			if got := qp.Len(); got != exp {
+				return errors.Errorf("expected queue len to be %d, got %d", exp, got)
+			}
+			return nil
+		})
+	}
+
+	// Nobody is waiting while the quota is available.
+	alloc, err := qp.Acquire(ctx, 1)
+	require.NoError(t, err)
+	require.Zero(t, qp.LongestWait())
+
+	// Queue up two acquisitions, a second apart.
+	go doAcquire(ctx)
+	assertLenSoon(1)
+	mt.Advance(time.Second)
+	go doAcquire(ctx)
+	assertLenSoon(2)
+	mt.Advance(time.Second)
+	require.Equal(t, 2*time.Second, qp.LongestWait())
+
+	// Once the first one is fulfilled, the second one is the oldest.
+	alloc.Release()
+	alloc = <-allocCh
+	require.Equal(t, time.Second, qp.LongestWait())
+	alloc.Release()
+	alloc = <-allocCh
+	require.Zero(t, qp.LongestWait())
+	alloc.Release()
+}
+
 // TestIntpoolIllegalCapacity ensures that constructing an IntPool with capacity
 // in excess of math.MaxInt64 will panic.
 func TestIntpoolWithExcessCapacity(t *testing.T) {
@@ -19,5 +19,5 @@ import (
 
 // TestNodeSize ensures that the byte size of a node matches the expectation.
 func TestNodeSize(t *testing.T) {
-	assert.Equal(t, 32+8*bufferSize, int(unsafe.Sizeof(node{})))
+	assert.Equal(t, 32+16*bufferSize, int(unsafe.Sizeof(node{})))
 }
@@ -14,9 +14,9 @@ import "sync"
 
 // bufferSize is the size of the ringBuf buf served from a notifyQueueNodePool.
 //
-// Each node is 32+8*bufferSize bytes so at 28 a node is 256 bytes which
+// Each node is 32+16*bufferSize bytes so at 14 a node is 256 bytes which
 // feels like a nice number.
-const bufferSize = 28
+const bufferSize = 14
 
 // notifyQueue provides an allocation efficient FIFO queue for chan struct{}.
 //
@@ -42,21 +42,21 @@ func initializeNotifyQueue(q *notifyQueue) {
 var defaultNotifyQueueNodePool = newNotifyQueueNodePool()
 
 // enqueue adds c to the end of the queue and returns the address of the added
-// notifyee.
-func (q *notifyQueue) enqueue(c chan struct{}) (n *notifyee) {
+// notifyee. enqueued is the time, in nanoseconds, at which c started waiting.
+func (q *notifyQueue) enqueue(c chan struct{}, enqueued int64) (n *notifyee) {
 	if q.head == nil {
 		q.head = q.pool.pool.Get().(*node)
 		q.head.prev = q.head
 		q.head.next = q.head
 	}
 	tail := q.head.prev
-	if n = tail.enqueue(c); n == nil {
+	if n = tail.enqueue(c, enqueued); n == nil {
 		newTail := q.pool.pool.Get().(*node)
 		tail.next = newTail
 		q.head.prev = newTail
 		newTail.prev = tail
 		newTail.next = q.head
-		if n = newTail.enqueue(c); n == nil {
+		if n = newTail.enqueue(c, enqueued); n == nil {
 			panic("failed to enqueue into a fresh buffer")
 		}
 	}
@@ -125,6 +125,9 @@ type node struct {
 
 type notifyee struct {
 	c chan struct{}
+	// enqueued is the time, in nanoseconds since the epoch, at which the
+	// notifyee was added to the queue.
+	enqueued int64
 }
 
 type ringBuf struct {
@@ -133,12 +136,12 @@ type ringBuf struct {
 	len  int64
 }
 
-func (rb *ringBuf) enqueue(c chan struct{}) *notifyee {
+func (rb *ringBuf) enqueue(c chan struct{}, enqueued int64) *notifyee {
 	if rb.len == bufferSize {
 		return nil
 	}
 	i := (rb.head + rb.len) % bufferSize
-	rb.buf[i] = notifyee{c: c}
+	rb.buf[i] = notifyee{c: c, enqueued: enqueued}
 	rb.len++
 	return &rb.buf[i]
 }
@@ -60,7 +60,7 @@ func testNotifyQueue(t testing.TB, N int) {
 	for _, op := range ops {
 		switch op {
 		case enqueue:
-			q.enqueue(in[0])
+			q.enqueue(in[0], 0 /* enqueued */)
 			in = in[1:]
 			if b == nil {
 				l++
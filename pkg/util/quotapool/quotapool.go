@@ -140,6 +140,21 @@ func (qp *AbstractPool) Len() int {
 	return int(qp.mu.q.len) - qp.mu.numCanceled
 }
 
+// LongestWait returns how long the acquisition at the front of the queue has
+// been waiting, which is the longest any of the ongoing acquisitions has been
+// waiting. It returns zero if there are no ongoing acquisitions.
+func (qp *AbstractPool) LongestWait() time.Duration {
+	qp.mu.Lock()
+	defer qp.mu.Unlock()
+	// NB: the head of the queue is never a canceled notifyee, see
+	// cleanupOnCancel and notifyNextLocked.
+	n := qp.mu.q.peek()
+	if n == nil || n.c == nil {
+		return 0
+	}
+	return time.Duration(qp.timeSource.Now().UnixNano() - n.enqueued)
+}
+
 // Close signals to all ongoing and subsequent acquisitions that they are
 // free to return to their callers. They will receive an *ErrClosed which
 // contains this reason.
@@ -330,7 +345,7 @@ func (qp *AbstractPool) acquireFastPath(
 		return false, nil, 0, ErrNotEnoughQuota
 	}
 	c := chanSyncPool.Get().(chan struct{})
-	return false, qp.mu.q.enqueue(c), tryAgainAfter, nil
+	return false, qp.mu.q.enqueue(c, qp.timeSource.Now().UnixNano()), tryAgainAfter, nil
 }
 
 func (qp *AbstractPool) tryAcquireOnNotify(
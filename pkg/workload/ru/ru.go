@@ -0,0 +1,373 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ru
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/workload"
+	"github.com/cockroachdb/cockroach/pkg/workload/histogram"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/pflag"
+	"golang.org/x/exp/rand"
+	"golang.org/x/time/rate"
+)
+
+const (
+	ruSchema = `(
+		k INT PRIMARY KEY,
+		v BYTES NOT NULL,
+		FAMILY (k, v)
+	)`
+
+	defaultRows       = 10000
+	defaultBatchSize  = 1000
+	defaultValueBytes = 1024
+)
+
+// RandomSeed is the seed of the random number generators of the workload.
+var RandomSeed = workload.NewUint64RandomSeed()
+
+type ru struct {
+	flags     workload.Flags
+	connFlags *workload.ConnFlags
+
+	targetRU                           float64
+	readShare, writeShare, egressShare float64
+	rows, batchSize                    int
+	valueBytes, egressBytes, replicas  int
+}
+
+func init() {
+	workload.Register(ruMeta)
+}
+
+var ruMeta = workload.Meta{
+	Name: `ru`,
+	Description: `RU issues a mix of reads, writes and egress which consumes a target ` +
+		`rate of request units, to validate the tenant cost model and throttling.`,
+	Details: `The cost of each kind of operation is estimated with the tenant cost ` +
+		`model configured in the cluster, and the operations are rate limited so that the share ` +
+		`of the target RU/s given to each kind is spent on it. The estimates only ` +
+		`account for KV and egress costs: the difference between the target and ` +
+		`the RUs actually consumed by the virtual cluster is the cost of SQL CPU ` +
+		`and the error of the model.`,
+	Version:    `1.0.0`,
+	RandomSeed: RandomSeed,
+	New: func() workload.Generator {
+		g := &ru{}
+		g.flags.FlagSet = pflag.NewFlagSet(`ru`, pflag.ContinueOnError)
+		g.flags.Meta = map[string]workload.FlagMeta{
+			`target-ru`:    {RuntimeOnly: true},
+			`read-share`:   {RuntimeOnly: true},
+			`write-share`:  {RuntimeOnly: true},
+			`egress-share`: {RuntimeOnly: true},
+			`egress-bytes`: {RuntimeOnly: true},
+			`replicas`:     {RuntimeOnly: true},
+			`batch-size`:   {RuntimeOnly: true},
+		}
+		g.flags.Float64Var(&g.targetRU, `target-ru`, 100,
+			`Target consumption, in request units per second.`)
+		g.flags.Float64Var(&g.readShare, `read-share`, 0.5,
+			`Share of the target RU/s spent on point reads.`)
+		g.flags.Float64Var(&g.writeShare, `write-share`, 0.3,
+			`Share of the target RU/s spent on point writes.`)
+		g.flags.Float64Var(&g.egressShare, `egress-share`, 0.2,
+			`Share of the target RU/s spent on egress, by queries which don't read from KV.`)
+		g.flags.IntVar(&g.rows, `rows`, defaultRows, `Initial number of rows in the ru table.`)
+		g.flags.IntVar(&g.batchSize, `batch-size`, defaultBatchSize,
+			`Number of rows in each batch of initial data.`)
+		g.flags.IntVar(&g.valueBytes, `value-bytes`, defaultValueBytes,
+			`Size of the value of each row, read and written by point reads and writes.`)
+		g.flags.IntVar(&g.egressBytes, `egress-bytes`, 64<<10,
+			`Number of bytes returned by each egress query.`)
+		g.flags.IntVar(&g.replicas, `replicas`, 3,
+			`Number of replicas of the ru table, whose writes are all charged.`)
+		RandomSeed.AddFlag(&g.flags)
+		g.connFlags = workload.NewConnFlags(&g.flags)
+		return g
+	},
+}
+
+// Meta implements the Generator interface.
+func (*ru) Meta() workload.Meta { return ruMeta }
+
+// Flags implements the Flagser interface.
+func (w *ru) Flags() workload.Flags { return w.flags }
+
+// ConnFlags implements the ConnFlagser interface.
+func (w *ru) ConnFlags() *workload.ConnFlags { return w.connFlags }
+
+// Hooks implements the Hookser interface.
+func (w *ru) Hooks() workload.Hooks {
+	return workload.Hooks{
+		Validate: func() error {
+			if w.targetRU <= 0 {
+				return errors.Errorf(`Value of 'target-ru' must be positive; was %f`, w.targetRU)
+			}
+			if w.readShare < 0 || w.writeShare < 0 || w.egressShare < 0 ||
+				w.readShare+w.writeShare+w.egressShare == 0 {
+				return errors.New(`Values of 'read-share', 'write-share' and 'egress-share' ` +
+					`must not be negative, and at least one of them must be positive`)
+			}
+			if w.rows <= 0 {
+				return errors.Errorf(`Value of 'rows' must be positive; was %d`, w.rows)
+			}
+			if w.batchSize <= 0 {
+				return errors.Errorf(`Value of 'batch-size' must be positive; was %d`, w.batchSize)
+			}
+			if w.replicas <= 0 {
+				return errors.Errorf(`Value of 'replicas' must be positive; was %d`, w.replicas)
+			}
+			return nil
+		},
+	}
+}
+
+var ruTypes = []*types.T{
+	types.Int,
+	types.Bytes,
+}
+
+// Tables implements the Generator interface.
+func (w *ru) Tables() []workload.Table {
+	table := workload.Table{
+		Name:   `ru`,
+		Schema: ruSchema,
+		InitialRows: workload.BatchedTuples{
+			NumBatches: (w.rows + w.batchSize - 1) / w.batchSize,
+			FillBatch: func(batchIdx int, cb coldata.Batch, a *bufalloc.ByteAllocator) {
+				rng := rand.NewSource(RandomSeed.Seed() + uint64(batchIdx))
+
+				rowBegin, rowEnd := batchIdx*w.batchSize, (batchIdx+1)*w.batchSize
+				if rowEnd > w.rows {
+					rowEnd = w.rows
+				}
+				cb.Reset(ruTypes, rowEnd-rowBegin, coldata.StandardColumnFactory)
+				kCol := cb.ColVec(0).Int64()
+				vCol := cb.ColVec(1).Bytes()
+				// coldata.Bytes only allows appends so we have to reset it.
+				vCol.Reset()
+				for rowIdx := rowBegin; rowIdx < rowEnd; rowIdx++ {
+					var v []byte
+					*a, v = a.Alloc(w.valueBytes, 0 /* extraCap */)
+					randBytes(rng, v)
+
+					rowOffset := rowIdx - rowBegin
+					kCol[rowOffset] = int64(rowIdx)
+					vCol.Set(rowOffset, v)
+				}
+			},
+		},
+	}
+	return []workload.Table{table}
+}
+
+// opKind is a kind of operation issued by the workload.
+type opKind int
+
+const (
+	readOp opKind = iota
+	writeOp
+	egressOp
+	numOpKinds
+)
+
+var opNames = [numOpKinds]string{readOp: `read`, writeOp: `write`, egressOp: `egress`}
+
+// opMix is the mix of operations which consumes the target RU/s.
+type opMix struct {
+	// cost is the estimated cost of each kind of operation.
+	cost [numOpKinds]tenantcostmodel.RU
+	// rate is the number of operations of each kind to issue per second.
+	rate [numOpKinds]float64
+}
+
+// totalRate returns the number of operations to issue per second.
+func (m *opMix) totalRate() float64 {
+	var total float64
+	for _, r := range m.rate {
+		total += r
+	}
+	return total
+}
+
+// pick returns the kind of the next operation to issue, given a random number
+// in [0, 1).
+func (m *opMix) pick(r float64) opKind {
+	r *= m.totalRate()
+	last := readOp
+	for k := readOp; k < numOpKinds; k++ {
+		if m.rate[k] == 0 {
+			continue
+		}
+		if r < m.rate[k] {
+			return k
+		}
+		r -= m.rate[k]
+		last = k
+	}
+	// Only reached because of rounding errors.
+	return last
+}
+
+// makeOpMix computes the mix of operations which spends the configured share
+// of the target RU/s on each kind of operation, according to the given cost
+// model.
+func (w *ru) makeOpMix(cfg *tenantcostmodel.Config) opMix {
+	var m opMix
+	// Reads of a single row, whose value is not returned to the client.
+	m.cost[readOp] = cfg.KVReadCost(1 /* count */, int64(w.valueBytes))
+	// Blind writes of a single row, which are charged for each replica.
+	m.cost[writeOp] = cfg.KVWriteCost(1 /* count */, int64(w.valueBytes)) *
+		tenantcostmodel.RU(w.replicas)
+	m.cost[egressOp] = cfg.PGWireEgressCost(int64(w.egressBytes))
+
+	shares := [numOpKinds]float64{
+		readOp: w.readShare, writeOp: w.writeShare, egressOp: w.egressShare,
+	}
+	var totalShare float64
+	for _, s := range shares {
+		totalShare += s
+	}
+	for k := range shares {
+		if shares[k] == 0 || m.cost[k] <= 0 {
+			continue
+		}
+		m.rate[k] = w.targetRU * shares[k] / totalShare / float64(m.cost[k])
+	}
+	return m
+}
+
+// costModelFromCluster returns the tenant cost model configured in the
+// cluster, as far as the costs of the operations of the workload are
+// concerned. The other costs are left to their defaults.
+func costModelFromCluster(ctx context.Context, db *gosql.DB) (tenantcostmodel.Config, error) {
+	const perMiBToPerByte = float64(1) / (1024 * 1024)
+	cfg := tenantcostmodel.DefaultConfig()
+	for _, c := range []struct {
+		setting *settings.FloatSetting
+		cost    *tenantcostmodel.RU
+		scale   float64
+	}{
+		{tenantcostmodel.ReadBatchCost, &cfg.KVReadBatch, 1},
+		{tenantcostmodel.ReadRequestCost, &cfg.KVReadRequest, 1},
+		{tenantcostmodel.ReadPayloadCostPerMiB, &cfg.KVReadByte, perMiBToPerByte},
+		{tenantcostmodel.WriteBatchCost, &cfg.KVWriteBatch, 1},
+		{tenantcostmodel.WriteRequestCost, &cfg.KVWriteRequest, 1},
+		{tenantcostmodel.WritePayloadCostPerMiB, &cfg.KVWriteByte, perMiBToPerByte},
+		{tenantcostmodel.PgwireEgressCostPerMiB, &cfg.PGWireEgressByte, perMiBToPerByte},
+	} {
+		var v float64
+		if err := db.QueryRowContext(
+			ctx, fmt.Sprintf(`SHOW CLUSTER SETTING %s`, c.setting.Name()),
+		).Scan(&v); err != nil {
+			return tenantcostmodel.Config{}, errors.Wrapf(err, "reading %s", c.setting.Name())
+		}
+		*c.cost = tenantcostmodel.RU(v * c.scale)
+	}
+	return cfg, nil
+}
+
+// Ops implements the Opser interface.
+func (w *ru) Ops(
+	ctx context.Context, urls []string, reg *histogram.Registry,
+) (workload.QueryLoad, error) {
+	db, err := gosql.Open(`cockroach`, strings.Join(urls, ` `))
+	if err != nil {
+		return workload.QueryLoad{}, err
+	}
+	// Allow a maximum of concurrency+1 connections to the database.
+	db.SetMaxOpenConns(w.connFlags.Concurrency + 1)
+	db.SetMaxIdleConns(w.connFlags.Concurrency + 1)
+
+	cfg, err := costModelFromCluster(ctx, db)
+	if err != nil {
+		return workload.QueryLoad{}, errors.CombineErrors(err, db.Close())
+	}
+	mix := w.makeOpMix(&cfg)
+	if mix.totalRate() == 0 {
+		return workload.QueryLoad{}, errors.CombineErrors(
+			errors.New(`the configured operations have no estimated cost`), db.Close())
+	}
+	for k := readOp; k < numOpKinds; k++ {
+		fmt.Printf("%s: %.2f ops/s at an estimated %.4f RU each\n",
+			opNames[k], mix.rate[k], float64(mix.cost[k]))
+	}
+
+	var stmts [numOpKinds]*gosql.Stmt
+	for k, q := range [numOpKinds]string{
+		readOp:   `SELECT length(v) FROM ru WHERE k = $1`,
+		writeOp:  `UPSERT INTO ru (k, v) VALUES ($1, $2)`,
+		egressOp: `SELECT repeat('x', $1)`,
+	} {
+		if stmts[k], err = db.Prepare(q); err != nil {
+			return workload.QueryLoad{}, errors.CombineErrors(err, db.Close())
+		}
+	}
+
+	// The operations of all the workers are paced together, to consume the
+	// target RU/s regardless of the concurrency.
+	limiter := rate.NewLimiter(rate.Limit(mix.totalRate()), 1)
+	ql := workload.QueryLoad{
+		Close: func(_ context.Context) error {
+			return db.Close()
+		},
+	}
+	for i := 0; i < w.connFlags.Concurrency; i++ {
+		rng := rand.New(rand.NewSource(RandomSeed.Seed() + uint64(i)))
+		hists := reg.GetHandle()
+		value := make([]byte, w.valueBytes)
+		workerFn := func(ctx context.Context) error {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+			k := mix.pick(rng.Float64())
+			start := timeutil.Now()
+			var err error
+			switch k {
+			case readOp:
+				var n int
+				err = stmts[k].QueryRowContext(ctx, rng.Intn(w.rows)).Scan(&n)
+				if errors.Is(err, gosql.ErrNoRows) {
+					err = nil
+				}
+			case writeOp:
+				randBytes(rng, value)
+				_, err = stmts[k].ExecContext(ctx, rng.Intn(w.rows), value)
+			case egressOp:
+				var res []byte
+				err = stmts[k].QueryRowContext(ctx, w.egressBytes).Scan(&res)
+			}
+			hists.Get(opNames[k]).Record(timeutil.Since(start))
+			return err
+		}
+		ql.WorkerFns = append(ql.WorkerFns, workerFn)
+	}
+	return ql, nil
+}
+
+// randBytes fills the given buffer with random letters.
+func randBytes(rng rand.Source, buf []byte) {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	for i := range buf {
+		buf[i] = letters[rng.Uint64()%uint64(len(letters))]
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ru
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpMix(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	cfg := tenantcostmodel.DefaultConfig()
+	w := ruMeta.New().(*ru)
+	require.NoError(t, w.Flags().Parse([]string{
+		`--target-ru=200`, `--read-share=2`, `--write-share=1`, `--egress-share=1`,
+	}))
+	mix := w.makeOpMix(&cfg)
+
+	// The RU/s spent on each kind of operation follow the configured shares.
+	spent := func(k opKind) float64 { return mix.rate[k] * float64(mix.cost[k]) }
+	require.InDelta(t, 100, spent(readOp), 1e-6)
+	require.InDelta(t, 50, spent(writeOp), 1e-6)
+	require.InDelta(t, 50, spent(egressOp), 1e-6)
+
+	// Writes are charged for each replica.
+	require.Equal(t, cfg.KVWriteCost(1, defaultValueBytes)*3, mix.cost[writeOp])
+
+	// Operations are picked in proportion to their rates.
+	total := mix.totalRate()
+	require.Equal(t, readOp, mix.pick(0))
+	require.Equal(t, writeOp, mix.pick(mix.rate[readOp]/total))
+	require.Equal(t, egressOp, mix.pick(0.999999999))
+
+	// Kinds of operations without a share are never picked.
+	require.NoError(t, w.Flags().Parse([]string{`--egress-share=0`}))
+	mix = w.makeOpMix(&cfg)
+	require.Zero(t, mix.rate[egressOp])
+	require.Equal(t, writeOp, mix.pick(0.999999999))
+}